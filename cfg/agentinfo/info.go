@@ -31,6 +31,8 @@ var (
 	BuildStr      string = "No Build Date"
 	InputPlugins  []string
 	OutputPlugins []string
+	// ConfigSha256 is the hex encoded sha256 of the configuration applied
+	ConfigSha256 string
 
 	userAgentMap        = make(map[string]string)
 	ciCompiledRegexp, _ = regexp.Compile(containerInsightRegexp)
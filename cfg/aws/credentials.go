@@ -8,6 +8,7 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/aws/amazon-cloudwatch-agent/internal/audit"
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/aws/client"
@@ -38,10 +39,14 @@ type CredentialConfig struct {
 	Profile   string
 	Filename  string
 	Token     string
+	// the timeouts of the API calls of the sessions, and of the clients created from them
+	APICallTimeout       time.Duration
+	DNSResolutionTimeout time.Duration
 }
 
 type stsCredentialProvider struct {
 	regional, partitional, fallbackProvider *stscreds.AssumeRoleProvider
+	roleARN, region                         string
 }
 
 func (s *stsCredentialProvider) IsExpired() bool {
@@ -95,7 +100,8 @@ func (c *CredentialConfig) rootCredentials() client.ConfigProvider {
 	config := &aws.Config{
 		Region:                        aws.String(c.Region),
 		CredentialsChainVerboseErrors: aws.Bool(true),
-		HTTPClient:                    &http.Client{Timeout: 1 * time.Minute},
+		EndpointResolver:              EndpointResolver(),
+		HTTPClient:                    c.httpClient(),
 		LogLevel:                      SDKLogLevel(),
 		Logger:                        SDKLogger{},
 	}
@@ -106,15 +112,20 @@ func (c *CredentialConfig) rootCredentials() client.ConfigProvider {
 func (c *CredentialConfig) assumeCredentials() client.ConfigProvider {
 	rootCredentials := c.rootCredentials()
 	config := &aws.Config{
-		Region:     aws.String(c.Region),
-		HTTPClient: &http.Client{Timeout: 1 * time.Minute},
-		LogLevel:   SDKLogLevel(),
-		Logger:     SDKLogger{},
+		Region:           aws.String(c.Region),
+		EndpointResolver: EndpointResolver(),
+		HTTPClient:       c.httpClient(),
+		LogLevel:         SDKLogLevel(),
+		Logger:           SDKLogger{},
 	}
-	config.Credentials = newStsCredentials(rootCredentials, c.RoleARN, c.Region)
+	config.Credentials = newStsCredentials(rootCredentials, c.RoleARN, c.Region, c.httpClient())
 	return getSession(config)
 }
 
+func (c *CredentialConfig) httpClient() *http.Client {
+	return NewHTTPClient(c.APICallTimeout, c.DNSResolutionTimeout)
+}
+
 func (c *CredentialConfig) Credentials() client.ConfigProvider {
 	if c.RoleARN != "" {
 		return c.assumeCredentials()
@@ -124,6 +135,14 @@ func (c *CredentialConfig) Credentials() client.ConfigProvider {
 }
 
 func (s *stsCredentialProvider) Retrieve() (credentials.Value, error) {
+	v, err := s.retrieve()
+	if err == nil {
+		audit.CredentialsAssumed(s.roleARN, s.region)
+	}
+	return v, err
+}
+
+func (s *stsCredentialProvider) retrieve() (credentials.Value, error) {
 	if s.fallbackProvider != nil {
 		return s.fallbackProvider.Retrieve()
 	}
@@ -141,12 +160,12 @@ func (s *stsCredentialProvider) Retrieve() (credentials.Value, error) {
 	return v, err
 }
 
-func newStsCredentials(c client.ConfigProvider, roleARN string, region string) *credentials.Credentials {
+func newStsCredentials(c client.ConfigProvider, roleARN string, region string, httpClient *http.Client) *credentials.Credentials {
 	regional := &stscreds.AssumeRoleProvider{
 		Client: sts.New(c, &aws.Config{
 			Region:              aws.String(region),
 			STSRegionalEndpoint: endpoints.RegionalSTSEndpoint,
-			HTTPClient:          &http.Client{Timeout: 1 * time.Minute},
+			HTTPClient:          httpClient,
 			LogLevel:            SDKLogLevel(),
 			Logger:              SDKLogger{},
 		}),
@@ -161,7 +180,7 @@ func newStsCredentials(c client.ConfigProvider, roleARN string, region string) *
 			Region:              aws.String(fallbackRegion),
 			Endpoint:            aws.String(getFallbackEndpoint(fallbackRegion)),
 			STSRegionalEndpoint: endpoints.RegionalSTSEndpoint,
-			HTTPClient:          &http.Client{Timeout: 1 * time.Minute},
+			HTTPClient:          httpClient,
 			LogLevel:            SDKLogLevel(),
 			Logger:              SDKLogger{},
 		}),
@@ -169,15 +188,14 @@ func newStsCredentials(c client.ConfigProvider, roleARN string, region string) *
 		Duration: stscreds.DefaultDuration,
 	}
 
-	return credentials.NewCredentials(&stsCredentialProvider{regional: regional, partitional: partitional})
+	return credentials.NewCredentials(&stsCredentialProvider{regional: regional, partitional: partitional, roleARN: roleARN, region: region})
 }
 
 // The partitional STS endpoint used to fallback when regional STS endpoint is not activated.
 func getFallbackEndpoint(region string) string {
-	partition := getPartition(region)
-	endpoint, _ := partition.EndpointFor("sts", region)
-	log.Printf("D! STS partitional endpoint retrieved: %s", endpoint.URL)
-	return endpoint.URL
+	endpoint := ResolveEndpoint("sts", region)
+	log.Printf("D! STS partitional endpoint retrieved: %s", endpoint)
+	return endpoint
 }
 
 // Get the region in the partition where STS endpoint cannot be deactivated by customers which is used to fallback.
@@ -185,7 +203,7 @@ func getFallbackEndpoint(region string) string {
 // manually enable the Region, the regional STS endpoints will always be activated and cannot be deactivated.
 // Refer to: https://docs.aws.amazon.com/IAM/latest/UserGuide/id_credentials_temp_enable-regions.html
 func getFallbackRegion(region string) string {
-	partition := getPartition(region)
+	partition := GetPartition(region)
 	switch partition.ID() {
 	case bjsPartition:
 		return bjsFallbackRegion
@@ -200,12 +218,6 @@ func getFallbackRegion(region string) string {
 	}
 }
 
-// Get the partition information based on the region name
-func getPartition(region string) endpoints.Partition {
-	partition, _ := endpoints.PartitionForRegion(endpoints.DefaultPartitions(), region)
-	return partition
-}
-
 func init() {
 	//Initialize the default root credentials chain
 	staticCredentialsProvider := RootCredentialsProvider{
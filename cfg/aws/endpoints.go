@@ -0,0 +1,144 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package aws
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/aws/amazon-cloudwatch-agent/cfg/envconfig"
+	"github.com/aws/aws-sdk-go/aws/endpoints"
+)
+
+const partitionProbeService = "sts"
+
+const (
+	// the endpoints of the clients, selected by the endpoint_mode of the agent section
+	EndpointModeStandard  = "standard"
+	EndpointModeFIPS      = "fips"
+	EndpointModeDualStack = "dualstack"
+)
+
+// endpointModeServices are the services whose endpoints follow the endpoint mode, the other services, e.g. s3 or
+// ssm, keep their standard endpoints.
+var endpointModeServices = map[string]bool{
+	"monitoring": true,
+	"logs":       true,
+	"ec2":        true,
+	"sts":        true,
+	"ecs":        true,
+}
+
+// fipsPartitions are the partitions with FIPS endpoints, e.g. aws-cn has none.
+var fipsPartitions = map[string]bool{
+	"aws":        true,
+	"aws-us-gov": true,
+}
+
+// dualStackDNSSuffixes are the DNS suffixes of the dual-stack endpoints by partition, the other partitions have no
+// dual-stack endpoints.
+var dualStackDNSSuffixes = map[string]string{
+	"aws":        "api.aws",
+	"aws-us-gov": "api.aws",
+	"aws-cn":     "api.amazonwebservices.com.cn",
+}
+
+// GetPartition returns the partition of the region, e.g. aws-cn for cn-north-1. The regions not known by the SDK yet
+// are matched against the region name pattern of each partition, and the aws partition is used if none matches.
+func GetPartition(region string) endpoints.Partition {
+	if partition, ok := endpoints.PartitionForRegion(endpoints.DefaultPartitions(), region); ok {
+		return partition
+	}
+	if resolved, err := endpoints.DefaultResolver().EndpointFor(partitionProbeService, region); err == nil {
+		for _, partition := range endpoints.DefaultPartitions() {
+			if partition.ID() == resolved.PartitionID {
+				return partition
+			}
+		}
+	}
+	log.Printf("D! No partition matches region %s, using the %s partition", region, endpoints.AwsPartitionID)
+	return endpoints.AwsPartition()
+}
+
+// ResolveEndpoint returns the endpoint url of the service in the region with the DNS suffix of the region's partition,
+// e.g. https://sts.cn-north-1.amazonaws.com.cn, or the endpoint of the endpoint mode. The services not modeled by the SDK, e.g. control.sdkmetrics, get the
+// default hostname of the partition instead of failing the resolution.
+func ResolveEndpoint(service, region string) string {
+	if url, ok := modeEndpoint(EndpointMode(), service, region); ok {
+		return url
+	}
+	resolved, err := endpoints.DefaultResolver().EndpointFor(service, region, endpoints.ResolveUnknownServiceOption)
+	if err == nil && resolved.URL != "" {
+		return resolved.URL
+	}
+	endpoint := fmt.Sprintf("https://%s.%s.%s", service, region, GetPartition(region).DNSSuffix())
+	log.Printf("D! Failed to resolve the %s endpoint for region %s, using %s, error was '%v'", service, region, endpoint, err)
+	return endpoint
+}
+
+// EndpointMode returns the endpoint mode of the agent, from the CWAGENT_ENDPOINT_MODE environment variable of the
+// env-config.json translated from the agent section. It is standard when unset or invalid.
+func EndpointMode() string {
+	mode := strings.ToLower(strings.TrimSpace(os.Getenv(envconfig.CWAGENT_ENDPOINT_MODE)))
+	switch mode {
+	case EndpointModeFIPS, EndpointModeDualStack:
+		return mode
+	case "", EndpointModeStandard:
+	default:
+		log.Printf("W! Invalid endpoint mode %s, using the %s endpoints", mode, EndpointModeStandard)
+	}
+	return EndpointModeStandard
+}
+
+// EndpointResolver returns the resolver of the sessions, it resolves the endpoints of the endpoint mode, e.g.
+// https://monitoring-fips.us-east-1.amazonaws.com in fips mode. The endpoint_override of a plugin still wins.
+func EndpointResolver() endpoints.Resolver {
+	mode := EndpointMode()
+	if mode == EndpointModeStandard {
+		return endpoints.DefaultResolver()
+	}
+	return endpointModeResolver{mode: mode}
+}
+
+type endpointModeResolver struct {
+	mode string
+}
+
+func (r endpointModeResolver) EndpointFor(service, region string, opts ...func(*endpoints.Options)) (endpoints.ResolvedEndpoint, error) {
+	resolved, err := endpoints.DefaultResolver().EndpointFor(service, region, opts...)
+	if err != nil {
+		return resolved, err
+	}
+	if url, ok := modeEndpoint(r.mode, service, region); ok {
+		resolved.URL = url
+	}
+	return resolved, nil
+}
+
+// modeEndpoint returns the endpoint url of the service in the region for the mode, it returns false when the service
+// keeps its standard endpoint.
+func modeEndpoint(mode, service, region string) (string, bool) {
+	if !endpointModeServices[service] || region == "" {
+		return "", false
+	}
+	partition := GetPartition(region)
+	switch mode {
+	case EndpointModeFIPS:
+		if !fipsPartitions[partition.ID()] {
+			log.Printf("W! The %s partition has no FIPS endpoints, using the standard %s endpoint", partition.ID(), service)
+			return "", false
+		}
+		return fmt.Sprintf("https://%s-fips.%s.%s", service, region, partition.DNSSuffix()), true
+	case EndpointModeDualStack:
+		suffix, ok := dualStackDNSSuffixes[partition.ID()]
+		if !ok {
+			log.Printf("D! The %s partition has no dual-stack endpoints, using the standard %s endpoint", partition.ID(), service)
+			return "", false
+		}
+		return fmt.Sprintf("https://%s.%s.%s", service, region, suffix), true
+	}
+	return "", false
+}
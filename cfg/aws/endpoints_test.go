@@ -0,0 +1,100 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package aws
+
+import (
+	"os"
+	"testing"
+
+	"github.com/aws/amazon-cloudwatch-agent/cfg/envconfig"
+	"github.com/aws/aws-sdk-go/aws/endpoints"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetPartition(t *testing.T) {
+	for region, expected := range map[string]string{
+		"us-east-1":      "aws",
+		"eu-west-3":      "aws",
+		"cn-north-1":     "aws-cn",
+		"cn-northwest-1": "aws-cn",
+		"us-gov-west-1":  "aws-us-gov",
+		"us-iso-east-1":  "aws-iso",
+		"us-isob-east-1": "aws-iso-b",
+		// regions not known by the SDK yet are matched by the region pattern
+		"cn-south-9":    "aws-cn",
+		"us-gov-west-9": "aws-us-gov",
+		"ap-east-9":     "aws",
+		"unknown":       "aws",
+	} {
+		assert.Equal(t, expected, GetPartition(region).ID(), region)
+	}
+}
+
+func TestResolveEndpoint(t *testing.T) {
+	assert.Equal(t, "https://monitoring.us-east-1.amazonaws.com", ResolveEndpoint("monitoring", "us-east-1"))
+	assert.Equal(t, "https://monitoring.cn-north-1.amazonaws.com.cn", ResolveEndpoint("monitoring", "cn-north-1"))
+	assert.Equal(t, "https://logs.us-gov-west-1.amazonaws.com", ResolveEndpoint("logs", "us-gov-west-1"))
+	assert.Equal(t, "https://sts.amazonaws.com", ResolveEndpoint("sts", "us-east-1"))
+	assert.Equal(t, "https://sts.cn-north-1.amazonaws.com.cn", ResolveEndpoint("sts", "cn-north-1"))
+
+	// services not modeled by the SDK
+	assert.Equal(t, "https://control.sdkmetrics.us-west-2.amazonaws.com", ResolveEndpoint("control.sdkmetrics", "us-west-2"))
+	assert.Equal(t, "https://control.sdkmetrics.cn-northwest-1.amazonaws.com.cn", ResolveEndpoint("control.sdkmetrics", "cn-northwest-1"))
+	assert.Equal(t, "https://control.sdkmetrics.us-iso-east-1.c2s.ic.gov", ResolveEndpoint("control.sdkmetrics", "us-iso-east-1"))
+}
+
+func TestEndpointMode(t *testing.T) {
+	defer os.Unsetenv(envconfig.CWAGENT_ENDPOINT_MODE)
+	for value, expected := range map[string]string{
+		"":          EndpointModeStandard,
+		"standard":  EndpointModeStandard,
+		"FIPS":      EndpointModeFIPS,
+		"dualstack": EndpointModeDualStack,
+		"ipv6":      EndpointModeStandard,
+	} {
+		os.Setenv(envconfig.CWAGENT_ENDPOINT_MODE, value)
+		assert.Equal(t, expected, EndpointMode(), value)
+	}
+}
+
+func TestResolveEndpoint_EndpointMode(t *testing.T) {
+	defer os.Unsetenv(envconfig.CWAGENT_ENDPOINT_MODE)
+	os.Setenv(envconfig.CWAGENT_ENDPOINT_MODE, EndpointModeFIPS)
+	assert.Equal(t, "https://monitoring-fips.us-east-1.amazonaws.com", ResolveEndpoint("monitoring", "us-east-1"))
+	assert.Equal(t, "https://sts-fips.us-gov-west-1.amazonaws.com", ResolveEndpoint("sts", "us-gov-west-1"))
+	// the partitions without FIPS endpoints keep the standard ones
+	assert.Equal(t, "https://monitoring.cn-north-1.amazonaws.com.cn", ResolveEndpoint("monitoring", "cn-north-1"))
+	assert.Equal(t, "https://logs.us-iso-east-1.c2s.ic.gov", ResolveEndpoint("logs", "us-iso-east-1"))
+	// the other services keep their standard endpoints
+	assert.Equal(t, "https://control.sdkmetrics.us-west-2.amazonaws.com", ResolveEndpoint("control.sdkmetrics", "us-west-2"))
+
+	os.Setenv(envconfig.CWAGENT_ENDPOINT_MODE, EndpointModeDualStack)
+	assert.Equal(t, "https://logs.eu-west-1.api.aws", ResolveEndpoint("logs", "eu-west-1"))
+	assert.Equal(t, "https://ecs.cn-north-1.api.amazonwebservices.com.cn", ResolveEndpoint("ecs", "cn-north-1"))
+	// the partitions without dual-stack endpoints keep the standard ones
+	assert.Equal(t, "https://ec2.us-iso-east-1.c2s.ic.gov", ResolveEndpoint("ec2", "us-iso-east-1"))
+}
+
+func TestEndpointResolver(t *testing.T) {
+	defer os.Unsetenv(envconfig.CWAGENT_ENDPOINT_MODE)
+	assert.Equal(t, endpoints.DefaultResolver(), EndpointResolver())
+
+	os.Setenv(envconfig.CWAGENT_ENDPOINT_MODE, EndpointModeFIPS)
+	resolved, err := EndpointResolver().EndpointFor("ec2", "us-west-2")
+	require.NoError(t, err)
+	assert.Equal(t, "https://ec2-fips.us-west-2.amazonaws.com", resolved.URL)
+	assert.Equal(t, "us-west-2", resolved.SigningRegion)
+	resolved, err = EndpointResolver().EndpointFor("s3", "us-west-2")
+	require.NoError(t, err)
+	assert.Equal(t, "https://s3.us-west-2.amazonaws.com", resolved.URL)
+}
+
+func TestGetFallbackRegion(t *testing.T) {
+	assert.Equal(t, "us-east-1", getFallbackRegion("eu-west-1"))
+	assert.Equal(t, "cn-north-1", getFallbackRegion("cn-northwest-1"))
+	assert.Equal(t, "us-gov-west-1", getFallbackRegion("us-gov-east-1"))
+	assert.Equal(t, "us-iso-east-1", getFallbackRegion("us-iso-west-1"))
+	assert.Equal(t, "us-isob-east-1", getFallbackRegion("us-isob-east-1"))
+}
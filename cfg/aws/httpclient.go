@@ -0,0 +1,78 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package aws
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"time"
+)
+
+const (
+	// DefaultAPICallTimeout is the timeout of the API calls, from the connection to the end of the response.
+	DefaultAPICallTimeout = time.Minute
+	// DefaultDNSResolutionTimeout is the timeout of the resolution of the endpoints and of the proxy.
+	DefaultDNSResolutionTimeout = 10 * time.Second
+
+	dialTimeout      = 30 * time.Second
+	dialKeepAlive    = 30 * time.Second
+	idleConnTimeout  = 90 * time.Second
+	handshakeTimeout = 10 * time.Second
+)
+
+// lookupIPAddr is the resolver of the dialer, replaced in tests.
+var lookupIPAddr = net.DefaultResolver.LookupIPAddr
+
+// NewHTTPClient returns the http client of the AWS clients, the API calls time out after apiCallTimeout and the
+// resolutions of the host names after dnsResolutionTimeout, so a slow resolver doesn't use the whole timeout of the
+// call, the defaults when 0. The proxy of the environment is used like with the default client.
+func NewHTTPClient(apiCallTimeout, dnsResolutionTimeout time.Duration) *http.Client {
+	if apiCallTimeout <= 0 {
+		apiCallTimeout = DefaultAPICallTimeout
+	}
+	if dnsResolutionTimeout <= 0 {
+		dnsResolutionTimeout = DefaultDNSResolutionTimeout
+	}
+	transport := &http.Transport{
+		Proxy:                 http.ProxyFromEnvironment,
+		DialContext:           dialContext(dnsResolutionTimeout),
+		MaxIdleConns:          100,
+		IdleConnTimeout:       idleConnTimeout,
+		TLSHandshakeTimeout:   handshakeTimeout,
+		ExpectContinueTimeout: time.Second,
+	}
+	return &http.Client{Transport: transport, Timeout: apiCallTimeout}
+}
+
+// dialContext resolves the host with its own timeout before dialing its addresses in turn.
+func dialContext(dnsResolutionTimeout time.Duration) func(ctx context.Context, network, addr string) (net.Conn, error) {
+	dialer := &net.Dialer{Timeout: dialTimeout, KeepAlive: dialKeepAlive}
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		host, port, err := net.SplitHostPort(addr)
+		if err != nil || net.ParseIP(host) != nil {
+			return dialer.DialContext(ctx, network, addr)
+		}
+		lookupCtx, cancel := context.WithTimeout(ctx, dnsResolutionTimeout)
+		ips, err := lookupIPAddr(lookupCtx, host)
+		cancel()
+		if err != nil {
+			return nil, err
+		}
+		if len(ips) == 0 {
+			return nil, &net.DNSError{Err: "no such host", Name: host}
+		}
+		var firstErr error
+		for _, ip := range ips {
+			c, err := dialer.DialContext(ctx, network, net.JoinHostPort(ip.String(), port))
+			if err == nil {
+				return c, nil
+			}
+			if firstErr == nil {
+				firstErr = err
+			}
+		}
+		return nil, firstErr
+	}
+}
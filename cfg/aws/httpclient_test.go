@@ -0,0 +1,49 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package aws
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewHTTPClient(t *testing.T) {
+	assert.Equal(t, DefaultAPICallTimeout, NewHTTPClient(0, 0).Timeout)
+	assert.Equal(t, 2*time.Minute, NewHTTPClient(2*time.Minute, 0).Timeout)
+}
+
+func TestNewHTTPClient_DNSResolutionTimeout(t *testing.T) {
+	defer func(lookup func(context.Context, string) ([]net.IPAddr, error)) { lookupIPAddr = lookup }(lookupIPAddr)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+	_, port, _ := net.SplitHostPort(server.Listener.Addr().String())
+
+	// a slow resolver fails the call once the resolution times out, before the timeout of the call
+	lookupIPAddr = func(ctx context.Context, host string) ([]net.IPAddr, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	client := NewHTTPClient(time.Minute, 50*time.Millisecond)
+	// the proxy of the environment isn't resolved by the dialer
+	client.Transport.(*http.Transport).Proxy = nil
+	start := time.Now()
+	_, err := client.Get("http://slow.example.com:" + port)
+	assert.Error(t, err)
+	assert.True(t, time.Since(start) < 5*time.Second)
+
+	lookupIPAddr = func(ctx context.Context, host string) ([]net.IPAddr, error) {
+		assert.Equal(t, "fast.example.com", host)
+		return []net.IPAddr{{IP: net.ParseIP("127.0.0.1")}}, nil
+	}
+	resp, err := client.Get("http://fast.example.com:" + port)
+	assert.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
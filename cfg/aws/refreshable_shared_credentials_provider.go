@@ -6,6 +6,7 @@ package aws
 import (
 	"time"
 
+	"github.com/aws/amazon-cloudwatch-agent/internal/audit"
 	"github.com/aws/aws-sdk-go/aws/credentials"
 )
 
@@ -15,6 +16,9 @@ type Refreshable_shared_credentials_provider struct {
 
 	// Retrival frequency, if the value is 15 minutes, the credentials will be retrieved every 15 minutes.
 	ExpiryWindow time.Duration
+
+	// the access key id last retrieved, to audit the rotation of the shared credentials
+	accessKeyID string
 }
 
 // Retrieve reads and extracts the shared credentials from the current
@@ -23,6 +27,12 @@ func (p *Refreshable_shared_credentials_provider) Retrieve() (credentials.Value,
 
 	p.SetExpiration(time.Now().Add(p.ExpiryWindow), 0)
 	creds, err := p.sharedCredentialsProvider.Retrieve()
+	if err == nil {
+		if p.accessKeyID != "" && p.accessKeyID != creds.AccessKeyID {
+			audit.CredentialsRotated(creds.ProviderName, p.accessKeyID, creds.AccessKeyID)
+		}
+		p.accessKeyID = creds.AccessKeyID
+	}
 
 	return creds, err
 }
@@ -23,9 +23,10 @@ const (
 )
 
 type CommonConfig struct {
-	Credentials *Credentials
-	Proxy       *Proxy
-	SSL         *SSL
+	Credentials        *Credentials
+	Proxy              *Proxy
+	SSL                *SSL
+	ConfigVerification *ConfigVerification `toml:"config_verification"`
 }
 
 type Credentials struct {
@@ -43,6 +44,14 @@ type SSL struct {
 	CABundlePath *string `toml:"ca_bundle_path"`
 }
 
+// ConfigVerification is the key the detached signatures of the configs fetched from SSM or S3 are verified with,
+// either a KMS asymmetric key or a PEM public key.
+type ConfigVerification struct {
+	KMSKeyId         *string `toml:"kms_key_id"`
+	SigningAlgorithm *string `toml:"signing_algorithm"`
+	PublicKeyPath    *string `toml:"public_key_path"`
+}
+
 func New() *CommonConfig {
 	return &CommonConfig{}
 }
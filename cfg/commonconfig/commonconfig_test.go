@@ -63,3 +63,17 @@ func TestSSLOnly(t *testing.T) {
 	config.Parse(strings.NewReader(contents))
 	assert.Equal(t, "{ca_bundle_file_path}", *config.SSL.CABundlePath)
 }
+
+func TestConfigVerification(t *testing.T) {
+	contents := `
+				[config_verification]
+					kms_key_id = "{kms_key_id}"
+					signing_algorithm = "RSASSA_PSS_SHA_256"
+				`
+	config := New()
+	config.Parse(strings.NewReader(contents))
+	assert.Equal(t, "{kms_key_id}", *config.ConfigVerification.KMSKeyId)
+	assert.Equal(t, "RSASSA_PSS_SHA_256", *config.ConfigVerification.SigningAlgorithm)
+	assert.Nil(t, config.ConfigVerification.PublicKeyPath)
+	assert.Nil(t, config.SSL)
+}
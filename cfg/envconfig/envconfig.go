@@ -13,4 +13,8 @@ const (
 	AWS_SDK_LOG_LEVEL  = "AWS_SDK_LOG_LEVEL"
 	CWAGENT_USER_AGENT = "CWAGENT_USER_AGENT"
 	CWAGENT_LOG_LEVEL  = "CWAGENT_LOG_LEVEL"
+	CWAGENT_SIDECAR    = "CWAGENT_SIDECAR"
+
+	CWAGENT_SIDECAR_DRAIN_TIMEOUT = "CWAGENT_SIDECAR_DRAIN_TIMEOUT"
+	CWAGENT_ENDPOINT_MODE         = "CWAGENT_ENDPOINT_MODE"
 )
@@ -0,0 +1,99 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"flag"
+	"io/ioutil"
+	"log"
+	"os"
+	"time"
+
+	"github.com/aws/amazon-cloudwatch-agent/cfg/agentinfo"
+	configaws "github.com/aws/amazon-cloudwatch-agent/cfg/aws"
+	"github.com/aws/amazon-cloudwatch-agent/cfg/commonconfig"
+	"github.com/aws/amazon-cloudwatch-agent/internal/selfupdate"
+	"github.com/aws/amazon-cloudwatch-agent/translator/context"
+	"github.com/aws/amazon-cloudwatch-agent/translator/util"
+	"github.com/aws/amazon-cloudwatch-agent/translator/util/ec2util"
+)
+
+// The updater checks the manifest once per run, schedule it with cron, a systemd timer or the windows task scheduler
+// to keep the fleet on the approved version.
+func main() {
+	var manifestLocation, manifestSignatureLocation, publicKeyPath, stateDir, hostID, mode, inputConfig string
+	var healthCheckInterval time.Duration
+
+	flag.StringVar(&manifestLocation, "manifest", "",
+		"Location of the update manifest, i.e. ssm:<parameter-store-name>, s3://<bucket>/<key>, https://<url> or file:<path>")
+	flag.StringVar(&manifestSignatureLocation, "manifest-signature", "",
+		"Location of the detached signature of the update manifest, the manifest location with a .sig suffix by default")
+	flag.StringVar(&publicKeyPath, "public-key", "", "Path of the PEM encoded public key used to verify the manifest and package signatures")
+	flag.StringVar(&stateDir, "state-dir", "", "Directory to keep the downloaded packages and the update state")
+	flag.StringVar(&hostID, "host-id", "", "Identifier used to decide the canary group, the EC2 instance id by default")
+	flag.StringVar(&mode, "mode", "ec2", "Please provide the mode, i.e. ec2, onPremise, auto")
+	flag.StringVar(&inputConfig, "config", "", "Please provide the common-config file")
+	flag.DurationVar(&healthCheckInterval, "health-check-interval", 10*time.Second, "Interval between the health checks after the update")
+	flag.Parse()
+
+	if manifestLocation == "" || publicKeyPath == "" || stateDir == "" {
+		log.Fatalf("E! usage: --manifest <location> --public-key <path> --state-dir <path>")
+	}
+
+	cc := commonconfig.New()
+	if inputConfig != "" {
+		f, err := os.Open(inputConfig)
+		if err != nil {
+			log.Fatalf("E! Failed to open Common Config: %v", err)
+		}
+		if err := cc.Parse(f); err != nil {
+			log.Fatalf("E! Failed to parse Common Config: %v", err)
+		}
+	}
+	util.SetProxyEnv(cc.ProxyMap())
+	util.SetSSLEnv(cc.SSLMap())
+
+	mode = util.DetectAgentMode(mode)
+	context.CurrentContext().SetMode(mode)
+	region := util.DetectRegion(mode, cc.CredentialsMap())
+	if hostID == "" {
+		hostID = ec2util.GetEC2UtilSingleton().InstanceID
+	}
+	if hostID == "" {
+		if hostID, _ = os.Hostname(); hostID == "" {
+			log.Fatalf("E! Failed to detect the host id, please provide --host-id")
+		}
+	}
+
+	publicKey, err := ioutil.ReadFile(publicKeyPath)
+	if err != nil {
+		log.Fatalf("E! Failed to read public key: %v", err)
+	}
+	if err := os.MkdirAll(stateDir, 0700); err != nil {
+		log.Fatalf("E! Failed to create state dir: %v", err)
+	}
+
+	credsMap := util.GetCredentials(mode, cc.CredentialsMap())
+	fetcher := &selfupdate.AwsFetcher{
+		Credentials: &configaws.CredentialConfig{
+			Region:   region,
+			Profile:  credsMap[commonconfig.CredentialProfile],
+			Filename: credsMap[commonconfig.CredentialFile],
+		},
+	}
+	updater := &selfupdate.Updater{
+		ManifestLocation:          manifestLocation,
+		ManifestSignatureLocation: manifestSignatureLocation,
+		PublicKey:                 publicKey,
+		StateDir:                  stateDir,
+		HostID:                    hostID,
+		CurrentVersion:            agentinfo.Version(),
+		Fetcher:                   fetcher,
+		ServiceManager:            selfupdate.NewServiceManager(),
+		HealthCheckInterval:       healthCheckInterval,
+	}
+	if err := updater.Run(); err != nil {
+		log.Fatalf("E! Self update failed: %v", err)
+	}
+}
@@ -5,6 +5,8 @@ package main
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"flag"
@@ -28,6 +30,10 @@ import (
 
 	"github.com/aws/amazon-cloudwatch-agent/cfg/agentinfo"
 	"github.com/aws/amazon-cloudwatch-agent/cfg/migrate"
+	"github.com/aws/amazon-cloudwatch-agent/internal/audit"
+	"github.com/aws/amazon-cloudwatch-agent/internal/gatherstats"
+	"github.com/aws/amazon-cloudwatch-agent/internal/handoff"
+	"github.com/aws/amazon-cloudwatch-agent/internal/sidecar"
 	"github.com/aws/amazon-cloudwatch-agent/logs"
 	"github.com/aws/amazon-cloudwatch-agent/profiler"
 
@@ -88,6 +94,8 @@ var fServiceName = flag.String("service-name", "telegraf", "service name (window
 var fServiceDisplayName = flag.String("service-display-name", "Telegraf Data Collector Service", "service display name (windows only)")
 var fRunAsConsole = flag.Bool("console", false, "run as console application (windows only)")
 var fSetEnv = flag.String("setenv", "", "set an env in the configuration file in the format of KEY=VALUE")
+var fHandoffSocket = flag.String("handoff-socket", "",
+	"unix socket receiving the requests to hand the agent off to its new binary during an upgrade, disabled if empty (linux only)")
 
 var (
 	version string
@@ -106,12 +114,15 @@ func reloadLoop(
 ) {
 	reload := make(chan bool, 1)
 	reload <- true
+	handoffRequests := serveHandoff()
+	serveDiagnosticSignals()
+	handingOff := make(chan struct{}, 1)
 	for <-reload {
 		reload <- false
 
 		ctx, cancel := context.WithCancel(context.Background())
 
-		signals := make(chan os.Signal)
+		signals := make(chan os.Signal, 1)
 		signal.Notify(signals, os.Interrupt, syscall.SIGHUP,
 			syscall.SIGTERM, syscall.SIGINT)
 		go func() {
@@ -121,8 +132,17 @@ func reloadLoop(
 					log.Printf("I! Reloading Telegraf config")
 					<-reload
 					reload <- true
+				} else if sidecar.Enabled() {
+					drainLogs(ctx, signals)
 				}
 				cancel()
+			case <-handoffRequests:
+				log.Printf("I! Stopping the agent to hand it off to its new binary")
+				handoff.Begin()
+				<-reload
+				reload <- true
+				handingOff <- struct{}{}
+				cancel()
 			case <-stop:
 				cancel()
 			}
@@ -176,13 +196,77 @@ func reloadLoop(
 			}(ctx, envConfigPath)
 		}
 
+		// the listeners of the previous agent not claimed by the inputs started are closed
+		unclaimed := time.AfterFunc(time.Minute, handoff.CloseUnclaimed)
 		err := runAgent(ctx, inputFilters, outputFilters)
+		unclaimed.Stop()
 		if err != nil && err != context.Canceled {
 			log.Fatalf("E! [telegraf] Error running agent: %v", err)
 		}
+		select {
+		case <-handingOff:
+			handOff()
+		default:
+		}
 	}
 }
 
+// drainLogs publishes the logs written by the application of the sidecar after the stop signal, until they stop or
+// the drain times out. A second stop signal stops the agent at once.
+func drainLogs(ctx context.Context, signals <-chan os.Signal) {
+	timeout := sidecar.DrainTimeout()
+	log.Printf("I! Draining the logs of the sidecar for at most %v before stopping", timeout)
+	sidecar.BeginDrain()
+	drainCtx, stopDrain := context.WithCancel(ctx)
+	defer stopDrain()
+	go func() {
+		select {
+		case <-signals:
+			log.Printf("I! Stop signal received again, stopping the drain")
+			stopDrain()
+		case <-drainCtx.Done():
+		}
+	}()
+	start := time.Now()
+	sidecar.WaitDrained(drainCtx, timeout)
+	log.Printf("I! Drained the logs of the sidecar in %v", time.Since(start).Round(time.Millisecond))
+}
+
+// serveHandoff accepts the handoff requests of the updater on the handoff socket, a request at a time.
+func serveHandoff() <-chan struct{} {
+	requests := make(chan struct{}, 1)
+	if *fHandoffSocket == "" || !handoff.Supported {
+		return requests
+	}
+	go func() {
+		err := handoff.Serve(context.Background(), *fHandoffSocket, func() error {
+			select {
+			case requests <- struct{}{}:
+				return nil
+			default:
+				return errors.New("a handoff is already in progress")
+			}
+		})
+		if err != nil {
+			log.Printf("E! Unable to serve the handoff requests on %s: %v", *fHandoffSocket, err)
+		}
+	}()
+	return requests
+}
+
+// handOff replaces the stopped agent by its new binary, with the same arguments and the configs translated by the
+// previous version, the listeners and the tail offsets are handed to it. It only returns when the exec failed, the
+// agent is then restarted in place with its listeners.
+func handOff() {
+	path, err := os.Executable()
+	if err == nil {
+		log.Printf("I! Handing off the agent to %s", path)
+		err = handoff.Exec(path, os.Args)
+	}
+	log.Printf("E! Unable to hand off the agent, restarting it: %v", err)
+	handoff.Abort()
+}
+
 // loadEnvironmentVariables updates OS ENV vars with key/val from the given JSON file.
 // The "config-translator" program populates that file.
 func loadEnvironmentVariables(path string) error {
@@ -207,6 +291,17 @@ func loadEnvironmentVariables(path string) error {
 	return nil
 }
 
+// fileSha256 returns the hex encoded sha256 of the file content, or an empty string if the file cannot be read.
+func fileSha256(path string) string {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		log.Printf("W! Unable to read %v to compute its sha256: %v", path, err)
+		return ""
+	}
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
+
 func getEnvConfigPath(configPath, envConfigPath string) (string, error) {
 	if configPath == "" {
 		return "", fmt.Errorf("No config file specified")
@@ -290,6 +385,7 @@ func runAgent(ctx context.Context,
 		os.Exit(0)
 	}
 
+	gatherReporter := gatherstats.Wrap(c)
 	ag, err := agent.NewAgent(c)
 	if err != nil {
 		return err
@@ -333,6 +429,14 @@ func runAgent(ctx context.Context,
 
 	agentinfo.InputPlugins = c.InputNames()
 	agentinfo.OutputPlugins = c.OutputNames()
+	agentinfo.ConfigSha256 = fileSha256(*fConfig)
+
+	audit.SetConfig(audit.ConfigInfo{
+		File:    *fConfig,
+		Sha256:  agentinfo.ConfigSha256,
+		Inputs:  c.InputNames(),
+		Outputs: c.OutputNames(),
+	})
 
 	if *fPidfile != "" {
 		f, err := os.OpenFile(*fPidfile, os.O_CREATE|os.O_WRONLY, 0644)
@@ -353,6 +457,7 @@ func runAgent(ctx context.Context,
 	}
 	logAgent := logs.NewLogAgent(c)
 	go logAgent.Run(ctx)
+	go gatherReporter.Run(ctx)
 	return ag.Run(ctx)
 }
 
@@ -438,6 +543,15 @@ func main() {
 				processorFilters,
 			)
 			return
+		case "test-logs":
+			runTestLogs(args[1:])
+			return
+		case "test-metrics":
+			runTestMetrics(args[1:])
+			return
+		case "replay-state":
+			runReplayState(args[1:])
+			return
 		}
 	}
 
@@ -554,7 +668,7 @@ func main() {
 				logger.RegisterEventLogger(winlogger)
 				logger.SetupLogging(logger.LogConfig{LogTarget: lumberjack.LogTargetLumberjack})
 			}
-			err = s.Run()
+			err = runService(s, *fServiceName, prg)
 
 			if err != nil {
 				log.Println("E! " + err.Error())
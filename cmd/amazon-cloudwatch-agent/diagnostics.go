@@ -0,0 +1,60 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/aws/amazon-cloudwatch-agent/cfg/agentinfo"
+	"github.com/aws/amazon-cloudwatch-agent/internal/flush"
+	"github.com/aws/amazon-cloudwatch-agent/profiler"
+)
+
+// diagnosticRequest is a request of the operators to the running agent, SIGUSR1 and SIGUSR2 on Linux and macOS, or the
+// service control codes 128 and 129 on Windows, e.g. sc control AmazonCloudWatchAgent 128.
+type diagnosticRequest int
+
+const (
+	// log a snapshot of the internal state of the agent
+	requestStateSnapshot diagnosticRequest = iota
+	// publish the batches of every pipeline now
+	requestFlush
+)
+
+var startTime = time.Now()
+
+func handleDiagnosticRequest(r diagnosticRequest) {
+	switch r {
+	case requestStateSnapshot:
+		logStateSnapshot()
+	case requestFlush:
+		log.Printf("I! Flushing the pipelines on request")
+		flush.Request()
+	}
+}
+
+// logStateSnapshot logs the state of the agent at the info level, so it is in the agent log whatever the log level.
+// The profiler stats are the ones accumulated since their last report, they aren't cleared.
+func logStateSnapshot() {
+	var m runtime.MemStats
+	runtime.ReadMemStats(&m)
+	lines := []string{
+		"I! State snapshot:",
+		"  version: " + agentinfo.FullVersion(),
+		fmt.Sprintf("  pid: %d", os.Getpid()),
+		fmt.Sprintf("  uptime: %v", time.Since(startTime).Round(time.Second)),
+		fmt.Sprintf("  config: %s sha256 %s", *fConfig, agentinfo.ConfigSha256),
+		"  inputs: " + strings.Join(agentinfo.InputPlugins, " "),
+		"  outputs: " + strings.Join(agentinfo.OutputPlugins, " "),
+		fmt.Sprintf("  goroutines: %d", runtime.NumGoroutine()),
+		fmt.Sprintf("  memory: alloc %d heap_in_use %d sys %d gc %d", m.Alloc, m.HeapInuse, m.Sys, m.NumGC),
+		"  profiler: " + strings.Join(profiler.Profiler.Snapshot(), " "),
+	}
+	log.Print(strings.Join(lines, "\n"))
+}
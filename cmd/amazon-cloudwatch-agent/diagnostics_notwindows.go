@@ -0,0 +1,36 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+//go:build !windows
+// +build !windows
+
+package main
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/kardianos/service"
+)
+
+// serveDiagnosticSignals logs a state snapshot on SIGUSR1 and flushes the pipelines on SIGUSR2, for the life of the
+// process since the reloads don't change them.
+func serveDiagnosticSignals() {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGUSR1, syscall.SIGUSR2)
+	go func() {
+		for sig := range signals {
+			if sig == syscall.SIGUSR1 {
+				handleDiagnosticRequest(requestStateSnapshot)
+			} else {
+				handleDiagnosticRequest(requestFlush)
+			}
+		}
+	}()
+}
+
+// runService runs the agent as a service, only on Windows.
+func runService(s service.Service, _ string, _ *program) error {
+	return s.Run()
+}
@@ -0,0 +1,66 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+//go:build windows
+// +build windows
+
+package main
+
+import (
+	"log"
+
+	"github.com/kardianos/service"
+	"golang.org/x/sys/windows/svc"
+)
+
+const (
+	// the user defined service control codes, from 128 to 255
+	controlStateSnapshot svc.Cmd = 128
+	controlFlush         svc.Cmd = 129
+)
+
+// serveDiagnosticSignals does nothing, Windows has no user signals, the service control codes are handled by the
+// service instead.
+func serveDiagnosticSignals() {}
+
+// runService runs the agent as a Windows service with its own handler of the service control requests, the handler
+// of the service library ignores the user defined control codes.
+func runService(s service.Service, name string, prg *program) error {
+	return svc.Run(name, &serviceHandler{service: s, prg: prg})
+}
+
+type serviceHandler struct {
+	service service.Service
+	prg     *program
+}
+
+func (h *serviceHandler) Execute(_ []string, requests <-chan svc.ChangeRequest, changes chan<- svc.Status) (bool, uint32) {
+	const accepted = svc.AcceptStop | svc.AcceptShutdown
+	changes <- svc.Status{State: svc.StartPending}
+	if err := h.prg.Start(h.service); err != nil {
+		log.Printf("E! Failed to start the service: %v", err)
+		return true, 1
+	}
+	changes <- svc.Status{State: svc.Running, Accepts: accepted}
+
+	for r := range requests {
+		switch r.Cmd {
+		case svc.Interrogate:
+			changes <- r.CurrentStatus
+		case svc.Stop, svc.Shutdown:
+			changes <- svc.Status{State: svc.StopPending}
+			if err := h.prg.Stop(h.service); err != nil {
+				log.Printf("E! Failed to stop the service: %v", err)
+				return true, 2
+			}
+			return false, 0
+		case controlStateSnapshot:
+			handleDiagnosticRequest(requestStateSnapshot)
+		case controlFlush:
+			handleDiagnosticRequest(requestFlush)
+		default:
+			log.Printf("W! Unexpected service control request %d", r.Cmd)
+		}
+	}
+	return false, 0
+}
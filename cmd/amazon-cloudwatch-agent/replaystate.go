@@ -0,0 +1,69 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/aws/amazon-cloudwatch-agent/plugins/inputs/logfile"
+)
+
+const replayStateUsage = `usage: amazon-cloudwatch-agent replay-state [-root <dir>] [-json] <state-dir>
+
+Reads the checkpoints of a state directory, e.g. copied from a failed host, and reports for each tailed
+file whether it was uploaded up to its end, had pending bytes, or was truncated after the checkpoint and
+lost the events in between. The files are looked up under root, the mounted file system of the host.
+Nothing is written to the state directory, and nothing is sent to CloudWatch Logs.`
+
+func replayState(args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("replay-state", flag.ContinueOnError)
+	root := fs.String("root", "", "the root of the file system of the host, the tailed files are looked up under it")
+	asJSON := fs.Bool("json", false, "print the states as json")
+	fs.Usage = func() { fmt.Fprintln(fs.Output(), replayStateUsage) }
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return errors.New("the state directory is required")
+	}
+
+	states, err := logfile.ReplayState(fs.Arg(0), *root)
+	if err != nil {
+		return err
+	}
+	if *asJSON {
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		return enc.Encode(states)
+	}
+	w := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "SOURCE\tSTATUS\tOFFSET\tSIZE\tBYTES\tSAVED")
+	for _, s := range states {
+		size := "-"
+		if s.Status != logfile.StateUnknown {
+			size = fmt.Sprint(s.Size)
+		}
+		source := s.Source
+		if s.WindowsEvent {
+			source = "windows event log " + source
+		}
+		fmt.Fprintf(w, "%s\t%s\t%d\t%s\t%d\t%s\n", source, s.Status, s.Offset, size, s.Bytes, s.Saved.Format(time.RFC3339))
+	}
+	return w.Flush()
+}
+
+func runReplayState(args []string) {
+	if err := replayState(args, os.Stdout); err != nil {
+		fmt.Fprintf(os.Stderr, "E! %v\n", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,83 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/aws/amazon-cloudwatch-agent/plugins/inputs/logfile"
+	"github.com/influxdata/telegraf/config"
+)
+
+const testLogsUsage = `usage: amazon-cloudwatch-agent -config <toml> test-logs [-file-path <file_path>] <sample-file>...
+
+Prints the log events the logfile inputs of the config would publish for the sample files after the
+multiline grouping, truncation, timestamp parsing and filters. Nothing is sent to CloudWatch Logs.`
+
+// testLogs runs the sample files through every file_config of the logfile inputs, or only the one
+// whose file_path is given.
+func testLogs(args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("test-logs", flag.ContinueOnError)
+	filePath := fs.String("file-path", "", "only test the file_config with this file_path")
+	fs.Usage = func() { fmt.Fprintln(fs.Output(), testLogsUsage) }
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *fConfig == "" || fs.NArg() == 0 {
+		fs.Usage()
+		return errors.New("config and sample file are required")
+	}
+
+	c := config.NewConfig()
+	if err := c.LoadConfig(*fConfig); err != nil {
+		return err
+	}
+
+	tested := 0
+	for _, input := range c.Inputs {
+		lf, ok := input.Input.(*logfile.LogFile)
+		if !ok {
+			continue
+		}
+		for i := range lf.FileConfig {
+			fileconfig := &lf.FileConfig[i]
+			if *filePath != "" && fileconfig.FilePath != *filePath {
+				continue
+			}
+			tested++
+			for _, sample := range fs.Args() {
+				fmt.Fprintf(out, "==> file_path: %s, sample: %s <==\n", fileconfig.FilePath, sample)
+				events, err := logfile.TestFileConfig(fileconfig, sample)
+				if err != nil {
+					return err
+				}
+				for _, e := range events {
+					timestamp := "<none, the time of ingestion is used>"
+					if !e.Time().IsZero() {
+						timestamp = e.Time().Format(time.RFC3339Nano)
+					}
+					fmt.Fprintf(out, "log_group_name: %s, log_stream_name: %s, timestamp: %s\n%s\n\n",
+						fileconfig.LogGroupName, fileconfig.LogStreamName, timestamp, e.Message())
+				}
+				fmt.Fprintf(out, "%d events\n", len(events))
+			}
+		}
+	}
+	if tested == 0 {
+		return fmt.Errorf("no logfile file_config found in %s", *fConfig)
+	}
+	return nil
+}
+
+func runTestLogs(args []string) {
+	if err := testLogs(args, os.Stdout); err != nil {
+		fmt.Fprintf(os.Stderr, "E! %v\n", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,217 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aws/amazon-cloudwatch-agent/plugins/outputs/cloudwatch"
+	"github.com/aws/aws-sdk-go/aws"
+	cloudwatchsdk "github.com/aws/aws-sdk-go/service/cloudwatch"
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/agent"
+	"github.com/influxdata/telegraf/config"
+)
+
+const testMetricsUsage = `usage: amazon-cloudwatch-agent -config <toml> test-metrics [-wait <duration>]
+
+Runs one collection cycle of the inputs of the config and prints the metric datums the cloudwatch
+outputs would publish, with their dimensions, units and rollups. Nothing is sent to CloudWatch.`
+
+// testMetrics gathers every input twice, so the inputs and processors computing deltas (cpu, diskio, net...) have
+// a previous sample, and only the metrics of the second gathering are passed to the outputs.
+func testMetrics(args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("test-metrics", flag.ContinueOnError)
+	wait := fs.Duration("wait", time.Second, "time between the two gatherings, and time to wait for the service inputs")
+	fs.Usage = func() { fmt.Fprintln(fs.Output(), testMetricsUsage) }
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *fConfig == "" {
+		fs.Usage()
+		return errors.New("config is required")
+	}
+
+	envConfigPath, err := getEnvConfigPath(*fConfig, *fEnvConfig)
+	if err != nil {
+		return err
+	}
+	if err := loadEnvironmentVariables(envConfigPath); err != nil {
+		log.Printf("W! Failed to load environment variables due to %s", err.Error())
+	}
+	c := config.NewConfig()
+	if err := c.LoadConfig(*fConfig); err != nil {
+		return err
+	}
+	if err := initPlugins(c); err != nil {
+		return err
+	}
+
+	gathered := newCollector()
+	discarded := newCollector()
+
+	var serviceInputs []telegraf.ServiceInput
+	for _, input := range c.Inputs {
+		if si, ok := input.Input.(telegraf.ServiceInput); ok {
+			acc := agent.NewAccumulator(input, gathered.metricC)
+			if err := si.Start(acc); err != nil {
+				return fmt.Errorf("could not start input %s: %v", input.LogName(), err)
+			}
+			serviceInputs = append(serviceInputs, si)
+		}
+	}
+
+	gather(c, discarded.metricC)
+	applyProcessors(c, discarded.Metrics())
+	time.Sleep(*wait)
+	gather(c, gathered.metricC)
+	for _, si := range serviceInputs {
+		si.Stop()
+	}
+	metrics := applyProcessors(c, gathered.Metrics())
+
+	found := false
+	for _, output := range c.Outputs {
+		cw, ok := output.Output.(*cloudwatch.CloudWatch)
+		if !ok {
+			continue
+		}
+		found = true
+		var selected []telegraf.Metric
+		for _, m := range metrics {
+			m = m.Copy()
+			if !output.Config.Filter.Select(m) {
+				continue
+			}
+			output.Config.Filter.Modify(m)
+			if len(m.FieldList()) > 0 {
+				selected = append(selected, m)
+			}
+		}
+		datums, err := cw.DryRun(selected)
+		if err != nil {
+			return err
+		}
+		printDatums(out, cw.Namespace, datums)
+	}
+	if !found {
+		return fmt.Errorf("no cloudwatch output found in %s", *fConfig)
+	}
+	return nil
+}
+
+func initPlugins(c *config.Config) error {
+	for _, input := range c.Inputs {
+		if err := input.Init(); err != nil {
+			return fmt.Errorf("could not initialize input %s: %v", input.LogName(), err)
+		}
+	}
+	for _, processor := range c.Processors {
+		if err := processor.Init(); err != nil {
+			return fmt.Errorf("could not initialize processor %s: %v", processor.Config.Name, err)
+		}
+	}
+	for _, output := range c.Outputs {
+		if err := output.Init(); err != nil {
+			return fmt.Errorf("could not initialize output %s: %v", output.Config.Name, err)
+		}
+	}
+	return nil
+}
+
+func gather(c *config.Config, metricC chan telegraf.Metric) {
+	for _, input := range c.Inputs {
+		if _, ok := input.Input.(telegraf.ServiceInput); ok {
+			continue
+		}
+		acc := agent.NewAccumulator(input, metricC)
+		if err := input.Input.Gather(acc); err != nil {
+			acc.AddError(err)
+		}
+	}
+}
+
+// collector reads the metrics of its channel while they are gathered, so the accumulators never block on a full
+// channel however many metrics the inputs produce.
+type collector struct {
+	metricC chan telegraf.Metric
+	done    chan struct{}
+	metrics []telegraf.Metric
+}
+
+func newCollector() *collector {
+	c := &collector{
+		metricC: make(chan telegraf.Metric, 100),
+		done:    make(chan struct{}),
+	}
+	go func() {
+		defer close(c.done)
+		for m := range c.metricC {
+			c.metrics = append(c.metrics, m)
+		}
+	}()
+	return c
+}
+
+// Metrics closes the channel and returns the metrics read from it. Nothing may write to the channel afterwards.
+func (c *collector) Metrics() []telegraf.Metric {
+	close(c.metricC)
+	<-c.done
+	return c.metrics
+}
+
+func applyProcessors(c *config.Config, metrics []telegraf.Metric) []telegraf.Metric {
+	for _, processor := range c.Processors {
+		metrics = processor.Apply(metrics...)
+	}
+	return metrics
+}
+
+func printDatums(out io.Writer, namespace string, datums []*cloudwatchsdk.MetricDatum) {
+	lines := make([]string, 0, len(datums))
+	for _, datum := range datums {
+		dims := make([]string, 0, len(datum.Dimensions))
+		for _, d := range datum.Dimensions {
+			dims = append(dims, aws.StringValue(d.Name)+"="+aws.StringValue(d.Value))
+		}
+		var value string
+		if datum.StatisticValues != nil {
+			s := datum.StatisticValues
+			value = fmt.Sprintf("min=%v max=%v sum=%v count=%v values=%d",
+				aws.Float64Value(s.Minimum), aws.Float64Value(s.Maximum), aws.Float64Value(s.Sum), aws.Float64Value(s.SampleCount), len(datum.Values))
+		} else {
+			value = fmt.Sprintf("value=%v", aws.Float64Value(datum.Value))
+		}
+		unit := aws.StringValue(datum.Unit)
+		if unit == "" {
+			unit = cloudwatchsdk.StandardUnitNone
+		}
+		resolution := "60s"
+		if aws.Int64Value(datum.StorageResolution) == 1 {
+			resolution = "1s"
+		}
+		lines = append(lines, fmt.Sprintf("%s %s [%s] unit=%s resolution=%s %s",
+			namespace, aws.StringValue(datum.MetricName), strings.Join(dims, ","), unit, resolution, value))
+	}
+	sort.Strings(lines)
+	for _, line := range lines {
+		fmt.Fprintln(out, line)
+	}
+	fmt.Fprintf(out, "%d metric datums\n", len(datums))
+}
+
+func runTestMetrics(args []string) {
+	if err := testMetrics(args, os.Stdout); err != nil {
+		fmt.Fprintf(os.Stderr, "E! %v\n", err)
+		os.Exit(1)
+	}
+}
@@ -15,6 +15,7 @@ import (
 	"github.com/aws/amazon-cloudwatch-agent/translator/context"
 	"github.com/aws/amazon-cloudwatch-agent/translator/util"
 	sdkutil "github.com/aws/amazon-cloudwatch-agent/translator/util"
+	"github.com/aws/amazon-cloudwatch-agent/translator/util/ec2util"
 
 	"fmt"
 
@@ -25,6 +26,8 @@ import (
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/credentials"
 	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/s3"
 	"github.com/aws/aws-sdk-go/service/ssm"
 )
 
@@ -32,6 +35,8 @@ const (
 	locationDefault = "default"
 	locationSSM     = "ssm"
 	locationFile    = "file"
+	locationS3      = "s3"
+	locationEC2Tag  = "ec2tag"
 
 	locationSeparator = ":"
 
@@ -42,10 +47,7 @@ func defaultJsonConfig(mode string) (string, error) {
 	return config.DefaultJsonConfig(config.ToValidOs(""), mode), nil
 }
 
-func downloadFromSSM(region, parameterStoreName, mode string, credsConfig map[string]string) (string, error) {
-	fmt.Printf("Region: %v\n", region)
-	fmt.Printf("credsConfig: %v\n", credsConfig)
-	var ses *session.Session
+func newSession(region, mode string, credsConfig map[string]string) (*session.Session, error) {
 	credsMap := util.GetCredentials(mode, credsConfig)
 	profile, profileOk := credsMap[commonconfig.CredentialProfile]
 	sharedConfigFile, sharedConfigFileOk := credsMap[commonconfig.CredentialFile]
@@ -64,6 +66,15 @@ func downloadFromSSM(region, parameterStoreName, mode string, credsConfig map[st
 	ses, err := session.NewSession(rootconfig)
 	if err != nil {
 		fmt.Printf("Error in creating session: %v\n", err)
+	}
+	return ses, err
+}
+
+func downloadFromSSM(region, parameterStoreName, mode string, credsConfig map[string]string) (string, error) {
+	fmt.Printf("Region: %v\n", region)
+	fmt.Printf("credsConfig: %v\n", credsConfig)
+	ses, err := newSession(region, mode, credsConfig)
+	if err != nil {
 		return "", err
 	}
 
@@ -81,6 +92,123 @@ func downloadFromSSM(region, parameterStoreName, mode string, credsConfig map[st
 	return *output.Parameter.Value, nil
 }
 
+func downloadFromS3(region, bucketAndKey, mode string, credsConfig map[string]string) (string, error) {
+	bucket, key, err := splitS3Location(bucketAndKey)
+	if err != nil {
+		return "", err
+	}
+	ses, err := newSession(region, mode, credsConfig)
+	if err != nil {
+		return "", err
+	}
+	output, err := s3.New(ses).GetObject(&s3.GetObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		fmt.Printf("Error in retrieving s3 object content: %v\n", err)
+		return "", err
+	}
+	defer output.Body.Close()
+	bytes, err := ioutil.ReadAll(output.Body)
+	return string(bytes), err
+}
+
+// splitS3Location splits "<bucket>/<key>" into the bucket and the key of the object.
+func splitS3Location(bucketAndKey string) (bucket, key string, err error) {
+	parts := strings.SplitN(bucketAndKey, "/", 2)
+	if len(parts) < 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", fmt.Errorf("s3 location %s is not in the format <bucket>/<key>", bucketAndKey)
+	}
+	return parts[0], parts[1], nil
+}
+
+// readEC2Tag returns the value of the tag of the instance the downloader runs on.
+func readEC2Tag(region, tagKey, mode string, credsConfig map[string]string) (string, error) {
+	instanceId := ec2util.GetEC2UtilSingleton().InstanceID
+	if instanceId == "" {
+		return "", fmt.Errorf("unable to determine the instance id, the %s location is only supported on EC2", locationEC2Tag)
+	}
+	ses, err := newSession(region, mode, credsConfig)
+	if err != nil {
+		return "", err
+	}
+	output, err := ec2.New(ses).DescribeTags(&ec2.DescribeTagsInput{
+		Filters: []*ec2.Filter{
+			{
+				Name:   aws.String("resource-id"),
+				Values: aws.StringSlice([]string{instanceId}),
+			},
+			{
+				Name:   aws.String("key"),
+				Values: aws.StringSlice([]string{tagKey}),
+			},
+		},
+	})
+	if err != nil {
+		fmt.Printf("Error in describing the tags of instance %s: %v\n", instanceId, err)
+		return "", err
+	}
+	for _, tag := range output.Tags {
+		if aws.StringValue(tag.Key) == tagKey && aws.StringValue(tag.Value) != "" {
+			return aws.StringValue(tag.Value), nil
+		}
+	}
+	return "", fmt.Errorf("instance %s has no %s tag", instanceId, tagKey)
+}
+
+// parseTagLocation returns the location type and name of the config an instance tag points to. The tag value is
+// "ssm:<parameter-store-name>", "s3://<bucket>/<key>", "s3:<bucket>/<key>", "default" or a bare SSM parameter store name.
+func parseTagLocation(tagValue string) (locationType, locationName string, err error) {
+	tagValue = strings.TrimSpace(tagValue)
+	if tagValue == locationDefault {
+		return locationDefault, "", nil
+	}
+	locationArray := strings.SplitN(tagValue, locationSeparator, 2)
+	if len(locationArray) == 2 {
+		switch locationArray[0] {
+		case locationSSM:
+			return locationSSM, locationArray[1], nil
+		case locationS3:
+			locationName = strings.TrimPrefix(locationArray[1], "//")
+			if _, _, err = splitS3Location(locationName); err != nil {
+				return "", "", err
+			}
+			return locationS3, locationName, nil
+		case locationFile, locationEC2Tag:
+			return "", "", fmt.Errorf("location type %s is not supported in an instance tag", locationArray[0])
+		}
+	}
+	if tagValue == "" {
+		return "", "", fmt.Errorf("the instance tag has an empty value")
+	}
+	// e.g. "AmazonCloudWatch-linux" or "/cwagent/web/config"
+	return locationSSM, tagValue, nil
+}
+
+// verifyConfig verifies the detached signature of a config fetched from SSM or S3, the config isn't applied when its
+// signature is missing or invalid, so a compromised parameter or object can't redirect the telemetry of the fleet.
+func verifyConfig(verifier signatureVerifier, config, locationType, locationName, signatureSource, region, mode string, credsConfig map[string]string) error {
+	sigType, sigName, err := signatureLocation(locationType, locationName, signatureSource)
+	if err != nil {
+		return err
+	}
+	var signature string
+	if sigType == locationSSM {
+		signature, err = downloadFromSSM(region, sigName, mode, credsConfig)
+	} else {
+		signature, err = downloadFromS3(region, sigName, mode, credsConfig)
+	}
+	if err != nil {
+		return fmt.Errorf("unable to fetch the signature %s%s%s: %v", sigType, locationSeparator, sigName, err)
+	}
+	if err = verifier.verify([]byte(config), decodeSignature(signature)); err != nil {
+		return fmt.Errorf("the signature %s%s%s of the config is not valid: %v", sigType, locationSeparator, sigName, err)
+	}
+	fmt.Printf("Verified the signature %s%s%s of the config\n", sigType, locationSeparator, sigName)
+	return nil
+}
+
 func readFromFile(filePath string) (string, error) {
 	bytes, err := ioutil.ReadFile(filePath)
 	return string(bytes), err
@@ -111,14 +239,18 @@ func main() {
 		}
 	}()
 
-	var region, mode, downloadLocation, outputDir, inputConfig, multiConfig string
+	var region, mode, downloadLocation, outputDir, inputConfig, multiConfig, signatureSource string
 
 	flag.StringVar(&mode, "mode", "ec2", "Please provide the mode, i.e. ec2, onPremise, auto")
 	flag.StringVar(&downloadLocation, "download-source", "",
-		"Download source. Example: \"ssm:my-parameter-store-name\" for an EC2 SSM Parameter Store Name holding your CloudWatch Agent configuration.")
+		"Download source. Example: \"ssm:my-parameter-store-name\" for an EC2 SSM Parameter Store Name holding your CloudWatch Agent configuration, "+
+			"\"s3:my-bucket/my-key\" for an S3 object, \"ec2tag:my-tag-key\" for the location held by a tag of the instance.")
 	flag.StringVar(&outputDir, "output-dir", "", "Path of output json config directory.")
 	flag.StringVar(&inputConfig, "config", "", "Please provide the common-config file")
 	flag.StringVar(&multiConfig, "multi-config", "default", "valid values: default, append, remove")
+	flag.StringVar(&signatureSource, "signature-source", "",
+		"Location of the detached signature of the config when config_verification is set in the common-config, "+
+			"\"ssm:my-parameter-store-name\" or \"s3:my-bucket/my-key\". The download source with the .sig suffix by default.")
 	flag.Parse()
 
 	cc := commonconfig.New()
@@ -188,8 +320,28 @@ func main() {
 		log.Panicf("E! downloadLocation %s is malformated.", downloadLocation)
 	}
 
-	var config, outputFilePath string
+	var verifier signatureVerifier
 	var err error
+	if multiConfig != "remove" {
+		if verifier, err = newSignatureVerifier(cc.ConfigVerification, region, mode, cc.CredentialsMap()); err != nil {
+			log.Panicf("E! Invalid config_verification in the common-config: %v", err)
+		}
+	}
+
+	var config, outputFilePath string
+	if locationArray[0] == locationEC2Tag {
+		// the tag points to the config, it is fetched like the location of the tag value was the download source
+		var tagValue string
+		if tagValue, err = readEC2Tag(region, locationArray[1], mode, cc.CredentialsMap()); err != nil {
+			log.Panicf("E! Fail to read the location of the json config: %v", err)
+		}
+		fmt.Printf("Instance tag %s points to the config at %s\n", locationArray[1], tagValue)
+		var locationType, locationName string
+		if locationType, locationName, err = parseTagLocation(tagValue); err != nil {
+			log.Panicf("E! Fail to read the location of the json config: %v", err)
+		}
+		locationArray = []string{locationType, locationName}
+	}
 	switch locationArray[0] {
 	case locationDefault:
 		outputFilePath = locationDefault
@@ -201,6 +353,13 @@ func main() {
 		if multiConfig != "remove" {
 			config, err = downloadFromSSM(region, locationArray[1], mode, cc.CredentialsMap())
 		}
+	case locationS3:
+		// both s3:<bucket>/<key> and s3://<bucket>/<key> are accepted
+		locationArray[1] = strings.TrimPrefix(locationArray[1], "//")
+		outputFilePath = locationS3 + "_" + EscapeFilePath(locationArray[1])
+		if multiConfig != "remove" {
+			config, err = downloadFromS3(region, locationArray[1], mode, cc.CredentialsMap())
+		}
 	case locationFile:
 		outputFilePath = locationFile + "_" + EscapeFilePath(filepath.Base(locationArray[1]))
 		if multiConfig != "remove" {
@@ -214,6 +373,17 @@ func main() {
 		log.Panicf("E! Fail to fetch/remove json config: %v", err)
 	}
 
+	if verifier != nil {
+		switch locationArray[0] {
+		case locationSSM, locationS3:
+			if err = verifyConfig(verifier, config, locationArray[0], locationArray[1], signatureSource, region, mode, cc.CredentialsMap()); err != nil {
+				log.Panicf("E! Refusing to apply the json config: %v", err)
+			}
+		default:
+			fmt.Printf("The signature of the %s config is not verified, only the configs of SSM and S3 are\n", locationArray[0])
+		}
+	}
+
 	if multiConfig != "remove" {
 		outputFilePath = filepath.Join(outputDir, outputFilePath+context.TmpFileSuffix)
 		err = ioutil.WriteFile(outputFilePath, []byte(config), 0644)
@@ -0,0 +1,36 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseTagLocation(t *testing.T) {
+	tests := []struct {
+		tagValue     string
+		locationType string
+		locationName string
+	}{
+		{"ssm:AmazonCloudWatch-linux", locationSSM, "AmazonCloudWatch-linux"},
+		{"AmazonCloudWatch-linux", locationSSM, "AmazonCloudWatch-linux"},
+		{" /cwagent/web/config ", locationSSM, "/cwagent/web/config"},
+		{"s3://my-bucket/cwagent/config.json", locationS3, "my-bucket/cwagent/config.json"},
+		{"s3:my-bucket/config.json", locationS3, "my-bucket/config.json"},
+		{"default", locationDefault, ""},
+	}
+	for _, test := range tests {
+		locationType, locationName, err := parseTagLocation(test.tagValue)
+		assert.NoError(t, err, test.tagValue)
+		assert.Equal(t, test.locationType, locationType, test.tagValue)
+		assert.Equal(t, test.locationName, locationName, test.tagValue)
+	}
+
+	for _, tagValue := range []string{"", "file:/etc/config.json", "ec2tag:other", "s3://my-bucket", "s3:/config.json"} {
+		_, _, err := parseTagLocation(tagValue)
+		assert.Error(t, err, tagValue)
+	}
+}
@@ -0,0 +1,193 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"strings"
+
+	commonconfig "github.com/aws/amazon-cloudwatch-agent/cfg/commonconfig"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/kms"
+	"github.com/aws/aws-sdk-go/service/kms/kmsiface"
+)
+
+const (
+	signatureSuffix         = ".sig"
+	defaultSigningAlgorithm = kms.SigningAlgorithmSpecEcdsaSha256
+)
+
+// signatureVerifier verifies the detached signature of a config.
+type signatureVerifier interface {
+	verify(config, signature []byte) error
+}
+
+// newSignatureVerifier returns the verifier of the key of the common config, nil when the configs aren't verified.
+func newSignatureVerifier(cv *commonconfig.ConfigVerification, region, mode string, credsConfig map[string]string) (signatureVerifier, error) {
+	if cv == nil {
+		return nil, nil
+	}
+	kmsKeyId, publicKeyPath := aws.StringValue(cv.KMSKeyId), aws.StringValue(cv.PublicKeyPath)
+	switch {
+	case kmsKeyId != "" && publicKeyPath != "":
+		return nil, errors.New("only one of kms_key_id and public_key_path can be set")
+	case kmsKeyId != "":
+		algorithm := aws.StringValue(cv.SigningAlgorithm)
+		if algorithm == "" {
+			algorithm = defaultSigningAlgorithm
+		}
+		if _, err := signingHash(algorithm); err != nil {
+			return nil, err
+		}
+		ses, err := newSession(region, mode, credsConfig)
+		if err != nil {
+			return nil, err
+		}
+		return &kmsVerifier{client: kms.New(ses), keyId: kmsKeyId, algorithm: algorithm}, nil
+	case publicKeyPath != "":
+		b, err := ioutil.ReadFile(publicKeyPath)
+		if err != nil {
+			return nil, err
+		}
+		key, err := parsePublicKey(b)
+		if err != nil {
+			return nil, fmt.Errorf("invalid public key %s: %v", publicKeyPath, err)
+		}
+		return &publicKeyVerifier{key: key}, nil
+	}
+	return nil, nil
+}
+
+// kmsVerifier verifies the signatures with the Verify API of a KMS asymmetric key. The digest of the config is sent
+// since KMS limits the raw messages to 4096 bytes, the signature is the same as the one of the raw config.
+type kmsVerifier struct {
+	client    kmsiface.KMSAPI
+	keyId     string
+	algorithm string
+}
+
+func (v *kmsVerifier) verify(config, signature []byte) error {
+	hash, err := signingHash(v.algorithm)
+	if err != nil {
+		return err
+	}
+	h := hash.New()
+	h.Write(config)
+	output, err := v.client.Verify(&kms.VerifyInput{
+		KeyId:            aws.String(v.keyId),
+		Message:          h.Sum(nil),
+		MessageType:      aws.String(kms.MessageTypeDigest),
+		Signature:        signature,
+		SigningAlgorithm: aws.String(v.algorithm),
+	})
+	if err != nil {
+		return err
+	}
+	if !aws.BoolValue(output.SignatureValid) {
+		return errors.New("invalid signature")
+	}
+	return nil
+}
+
+// signingHashes are the hashes of the KMS signing algorithms
+var signingHashes = map[string]crypto.Hash{
+	kms.SigningAlgorithmSpecEcdsaSha256:          crypto.SHA256,
+	kms.SigningAlgorithmSpecEcdsaSha384:          crypto.SHA384,
+	kms.SigningAlgorithmSpecEcdsaSha512:          crypto.SHA512,
+	kms.SigningAlgorithmSpecRsassaPkcs1V15Sha256: crypto.SHA256,
+	kms.SigningAlgorithmSpecRsassaPkcs1V15Sha384: crypto.SHA384,
+	kms.SigningAlgorithmSpecRsassaPkcs1V15Sha512: crypto.SHA512,
+	kms.SigningAlgorithmSpecRsassaPssSha256:      crypto.SHA256,
+	kms.SigningAlgorithmSpecRsassaPssSha384:      crypto.SHA384,
+	kms.SigningAlgorithmSpecRsassaPssSha512:      crypto.SHA512,
+}
+
+func signingHash(algorithm string) (crypto.Hash, error) {
+	if hash, ok := signingHashes[algorithm]; ok {
+		return hash, nil
+	}
+	return 0, fmt.Errorf("unsupported signing_algorithm %s", algorithm)
+}
+
+// publicKeyVerifier verifies the signatures of the SHA-256 digest of the config with an ECDSA or RSA key, PKCS #1
+// v1.5 or PSS, or the signatures of the config with an Ed25519 key.
+type publicKeyVerifier struct {
+	key crypto.PublicKey
+}
+
+func (v *publicKeyVerifier) verify(config, signature []byte) error {
+	digest := crypto.SHA256.New()
+	digest.Write(config)
+	hashed := digest.Sum(nil)
+	switch key := v.key.(type) {
+	case *ecdsa.PublicKey:
+		var sig struct{ R, S *big.Int }
+		if rest, err := asn1.Unmarshal(signature, &sig); err != nil || len(rest) > 0 {
+			return errors.New("invalid signature")
+		}
+		if !ecdsa.Verify(key, hashed, sig.R, sig.S) {
+			return errors.New("invalid signature")
+		}
+	case *rsa.PublicKey:
+		if rsa.VerifyPKCS1v15(key, crypto.SHA256, hashed, signature) != nil &&
+			rsa.VerifyPSS(key, crypto.SHA256, hashed, signature, nil) != nil {
+			return errors.New("invalid signature")
+		}
+	case ed25519.PublicKey:
+		if !ed25519.Verify(key, config, signature) {
+			return errors.New("invalid signature")
+		}
+	default:
+		return fmt.Errorf("unsupported public key %T", v.key)
+	}
+	return nil
+}
+
+// parsePublicKey parses a PEM "PUBLIC KEY", the format of "openssl pkey -pubout" and of the public keys downloaded
+// from KMS once PEM encoded.
+func parsePublicKey(b []byte) (crypto.PublicKey, error) {
+	block, _ := pem.Decode(b)
+	if block == nil {
+		// the DER public key of the KMS GetPublicKey API
+		return x509.ParsePKIXPublicKey(b)
+	}
+	return x509.ParsePKIXPublicKey(block.Bytes)
+}
+
+// decodeSignature returns the signature of its base64 encoding, as stored in the parameter store, or as is.
+func decodeSignature(s string) []byte {
+	if decoded, err := base64.StdEncoding.DecodeString(strings.TrimSpace(s)); err == nil {
+		return decoded
+	}
+	return []byte(s)
+}
+
+// signatureLocation returns the location of the detached signature of the config, the signature source when set,
+// else the location of the config with the .sig suffix, e.g. the "AmazonCloudWatch-linux.sig" parameter or the
+// "my-bucket/config.json.sig" object.
+func signatureLocation(locationType, locationName, signatureSource string) (string, string, error) {
+	if signatureSource == "" {
+		return locationType, locationName + signatureSuffix, nil
+	}
+	sigType, sigName, err := parseTagLocation(signatureSource)
+	if err != nil {
+		return "", "", err
+	}
+	if sigType != locationSSM && sigType != locationS3 {
+		return "", "", fmt.Errorf("signature source %s is not a ssm or s3 location", signatureSource)
+	}
+	return sigType, sigName, nil
+}
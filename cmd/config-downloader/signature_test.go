@@ -0,0 +1,155 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	commonconfig "github.com/aws/amazon-cloudwatch-agent/cfg/commonconfig"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/kms"
+	"github.com/aws/aws-sdk-go/service/kms/kmsiface"
+	"github.com/stretchr/testify/assert"
+)
+
+const signedConfig = `{"metrics":{"metrics_collected":{"mem":{"measurement":["used_percent"]}}}}`
+
+type mockKMS struct {
+	kmsiface.KMSAPI
+	input *kms.VerifyInput
+	valid bool
+}
+
+func (m *mockKMS) Verify(input *kms.VerifyInput) (*kms.VerifyOutput, error) {
+	m.input = input
+	if !m.valid {
+		return nil, awserr.New(kms.ErrCodeKMSInvalidSignatureException, "invalid signature", nil)
+	}
+	return &kms.VerifyOutput{SignatureValid: aws.Bool(true)}, nil
+}
+
+func TestPublicKeyVerifier(t *testing.T) {
+	digest := sha256.Sum256([]byte(signedConfig))
+
+	ecdsaKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	ecdsaSig, err := ecdsaKey.Sign(rand.Reader, digest[:], crypto.SHA256)
+	assert.NoError(t, err)
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	pkcs1Sig, err := rsa.SignPKCS1v15(rand.Reader, rsaKey, crypto.SHA256, digest[:])
+	assert.NoError(t, err)
+	pssSig, err := rsa.SignPSS(rand.Reader, rsaKey, crypto.SHA256, digest[:], nil)
+	assert.NoError(t, err)
+
+	edPublic, edPrivate, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+	edSig := ed25519.Sign(edPrivate, []byte(signedConfig))
+
+	tests := []struct {
+		key       crypto.PublicKey
+		signature []byte
+	}{
+		{&ecdsaKey.PublicKey, ecdsaSig},
+		{&rsaKey.PublicKey, pkcs1Sig},
+		{&rsaKey.PublicKey, pssSig},
+		{edPublic, edSig},
+	}
+	for _, test := range tests {
+		v := &publicKeyVerifier{key: test.key}
+		assert.NoError(t, v.verify([]byte(signedConfig), test.signature))
+		// a config changed after signing
+		assert.Error(t, v.verify([]byte(signedConfig+" "), test.signature))
+		assert.Error(t, v.verify([]byte(signedConfig), []byte("invalid")))
+	}
+}
+
+func TestKMSVerifier(t *testing.T) {
+	client := &mockKMS{valid: true}
+	v := &kmsVerifier{client: client, keyId: "alias/cwagent-config", algorithm: kms.SigningAlgorithmSpecRsassaPssSha384}
+	assert.NoError(t, v.verify([]byte(signedConfig), []byte("signature")))
+	// the digest is verified, not the config
+	assert.Equal(t, kms.MessageTypeDigest, *client.input.MessageType)
+	assert.Equal(t, 48, len(client.input.Message))
+	assert.Equal(t, "alias/cwagent-config", *client.input.KeyId)
+	assert.Equal(t, []byte("signature"), client.input.Signature)
+
+	client.valid = false
+	assert.Error(t, v.verify([]byte(signedConfig), []byte("signature")))
+}
+
+func TestNewSignatureVerifier(t *testing.T) {
+	v, err := newSignatureVerifier(nil, "us-east-1", "ec2", map[string]string{})
+	assert.NoError(t, err)
+	assert.Nil(t, v)
+
+	v, err = newSignatureVerifier(&commonconfig.ConfigVerification{KMSKeyId: aws.String("alias/cwagent-config")}, "us-east-1", "ec2", map[string]string{})
+	assert.NoError(t, err)
+	assert.Equal(t, defaultSigningAlgorithm, v.(*kmsVerifier).algorithm)
+
+	_, err = newSignatureVerifier(&commonconfig.ConfigVerification{KMSKeyId: aws.String("alias/cwagent-config"), SigningAlgorithm: aws.String("SHA_256")}, "us-east-1", "ec2", map[string]string{})
+	assert.Error(t, err)
+	_, err = newSignatureVerifier(&commonconfig.ConfigVerification{KMSKeyId: aws.String("alias/cwagent-config"), PublicKeyPath: aws.String("/etc/key.pem")}, "us-east-1", "ec2", map[string]string{})
+	assert.Error(t, err)
+
+	dir, err := ioutil.TempDir("", "signature")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	assert.NoError(t, err)
+	path := filepath.Join(dir, "key.pem")
+	assert.NoError(t, ioutil.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), 0644))
+	v, err = newSignatureVerifier(&commonconfig.ConfigVerification{PublicKeyPath: aws.String(path)}, "us-east-1", "ec2", map[string]string{})
+	assert.NoError(t, err)
+	assert.Equal(t, &key.PublicKey, v.(*publicKeyVerifier).key)
+
+	assert.NoError(t, ioutil.WriteFile(path, []byte("invalid"), 0644))
+	_, err = newSignatureVerifier(&commonconfig.ConfigVerification{PublicKeyPath: aws.String(path)}, "us-east-1", "ec2", map[string]string{})
+	assert.Error(t, err)
+}
+
+func TestDecodeSignature(t *testing.T) {
+	assert.Equal(t, []byte{1, 2, 255}, decodeSignature(base64.StdEncoding.EncodeToString([]byte{1, 2, 255})+"\n"))
+	assert.Equal(t, []byte("raw signature!"), decodeSignature("raw signature!"))
+}
+
+func TestSignatureLocation(t *testing.T) {
+	tests := []struct {
+		locationType, locationName, signatureSource string
+		sigType, sigName                            string
+	}{
+		{locationSSM, "AmazonCloudWatch-linux", "", locationSSM, "AmazonCloudWatch-linux.sig"},
+		{locationS3, "my-bucket/config.json", "", locationS3, "my-bucket/config.json.sig"},
+		{locationSSM, "AmazonCloudWatch-linux", "s3://my-bucket/signatures/linux.sig", locationS3, "my-bucket/signatures/linux.sig"},
+		{locationS3, "my-bucket/config.json", "ssm:/cwagent/signature", locationSSM, "/cwagent/signature"},
+	}
+	for _, test := range tests {
+		sigType, sigName, err := signatureLocation(test.locationType, test.locationName, test.signatureSource)
+		assert.NoError(t, err)
+		assert.Equal(t, test.sigType, sigType)
+		assert.Equal(t, test.sigName, sigName)
+	}
+	_, _, err := signatureLocation(locationSSM, "AmazonCloudWatch-linux", "default")
+	assert.Error(t, err)
+	_, _, err = signatureLocation(locationSSM, "AmazonCloudWatch-linux", "file:/etc/linux.sig")
+	assert.Error(t, err)
+}
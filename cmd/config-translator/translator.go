@@ -7,13 +7,16 @@ import (
 	"flag"
 	"log"
 	"os"
+	"os/signal"
 	"os/user"
 	"path/filepath"
+	"syscall"
 
 	"github.com/aws/amazon-cloudwatch-agent/cfg/commonconfig"
 	"github.com/aws/amazon-cloudwatch-agent/translator"
 	"github.com/aws/amazon-cloudwatch-agent/translator/cmdutil"
 	"github.com/aws/amazon-cloudwatch-agent/translator/context"
+	"github.com/aws/amazon-cloudwatch-agent/translator/schemaserver"
 	translatorUtil "github.com/aws/amazon-cloudwatch-agent/translator/util"
 )
 
@@ -31,6 +34,8 @@ func initFlags() {
 	var inputMode = flag.String("mode", "ec2", "Please provide the mode, i.e. ec2, onPremise, auto")
 	var inputConfig = flag.String("config", "", "Please provide the common-config file")
 	var multiConfig = flag.String("multi-config", "remove", "valid values: default, append, remove")
+	var catalogFile = flag.String("catalog", "", "Please provide the path of the output metric catalog json file, or - for stdout. Only the catalog is generated when set")
+	var schemaServer = flag.String("schema-server", "", "Please provide the address to serve the json schema of the agent config on, e.g. localhost:8888, for the autocompletion and the validation of the editors. The config is not translated when set")
 	flag.Parse()
 
 	ctx := context.CurrentContext()
@@ -39,6 +44,8 @@ func initFlags() {
 	ctx.SetInputJsonDirPath(*inputJsonDir)
 	ctx.SetMultiConfig(*multiConfig)
 	ctx.SetOutputTomlFilePath(*inputTomlFile)
+	ctx.SetCatalogFilePath(*catalogFile)
+	ctx.SetSchemaServerAddress(*schemaServer)
 
 	if *inputConfig != "" {
 		f, err := os.Open(*inputConfig)
@@ -61,15 +68,27 @@ func initFlags() {
 
 /**
  *	config-translator --input ${JSON} --input-dir ${JSON_DIR} --output ${TOML} --mode ${param_mode} --config ${COMMON_CONFIG}
- *  --multi-config [default|append|remove]
+ *  --multi-config [default|append|remove] --catalog ${CATALOG_JSON} --schema-server ${ADDRESS}
  *
  *		multi-config:
  *			default:	only process .tmp files
  *			append:		process both existing files and .tmp files
  *			remove:		only process existing files
+ *
+ *		catalog:
+ *			only write the catalog of the metric names, units, dimension sets and namespaces the config publishes,
+ *			so dashboards and alarms can be generated from the agent config. The toml config is not written.
+ *
+ *		schema-server:
+ *			only serve the json schema of the agent config at /schema.json and validate the configs posted to
+ *			/validate until interrupted, for the autocompletion and the validation of the editors.
  */
 func main() {
 	initFlags()
+	if addr := context.CurrentContext().SchemaServerAddress(); addr != "" {
+		serveSchema(addr)
+		return
+	}
 	defer func() {
 		if r := recover(); r != nil {
 			// Only emit error message if panic content is string(pre-checked)
@@ -92,6 +111,11 @@ func main() {
 		log.Panicf("E! Failed to generate merged json config: %v", err)
 	}
 
+	if ctx.CatalogFilePath() != "" {
+		cmdutil.TranslateJsonMapToCatalogFile(mergedJsonConfigMap, ctx.CatalogFilePath())
+		return
+	}
+
 	if !ctx.RunInContainer() {
 		// run as user only applies to non container situation.
 		current, err := user.Current()
@@ -110,3 +134,15 @@ func main() {
 	envConfigPath := filepath.Join(filepath.Dir(tomlConfigPath), envConfigFileName)
 	cmdutil.TranslateJsonMapToEnvConfigFile(mergedJsonConfigMap, envConfigPath)
 }
+
+// serveSchema serves the json schema of the agent config until the translator is interrupted.
+func serveSchema(addr string) {
+	server := schemaserver.NewServer(addr, context.CurrentContext().Os())
+	if err := server.Start(); err != nil {
+		log.Fatalf("E! Failed to start the schema server on %s: %v", addr, err)
+	}
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	<-signals
+	server.Stop()
+}
@@ -57,9 +57,27 @@ func TestAgentConfig(t *testing.T) {
 	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/validAgent.json", true, map[string]int{})
 	expectedErrorMap := map[string]int{}
 	expectedErrorMap["invalid_type"] = 5
+	expectedErrorMap["enum"] = 1
 	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/invalidAgent.json", false, expectedErrorMap)
 }
 
+func TestAgentHealthHookConfig(t *testing.T) {
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/validAgentHealthHook.json", true, map[string]int{})
+	expectedErrorMap := map[string]int{}
+	expectedErrorMap["number_any_of"] = 1
+	expectedErrorMap["number_gte"] = 1
+	expectedErrorMap["required"] = 1
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/invalidAgentHealthHook.json", false, expectedErrorMap)
+}
+
+func TestAgentCanaryConfig(t *testing.T) {
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/validAgentCanary.json", true, map[string]int{})
+	expectedErrorMap := map[string]int{}
+	expectedErrorMap["number_gte"] = 1
+	expectedErrorMap["additional_property_not_allowed"] = 1
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/invalidAgentCanary.json", false, expectedErrorMap)
+}
+
 func TestLogFilesConfig(t *testing.T) {
 	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/validLogFiles.json", true, map[string]int{})
 	expectedErrorMap := map[string]int{}
@@ -73,6 +91,22 @@ func TestLogFilesConfig(t *testing.T) {
 	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/invalidLogFilesWithDuplicateEntry.json", false, expectedErrorMap2)
 }
 
+func TestLogFilesW3CFormatConfig(t *testing.T) {
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/validLogFilesWithW3CFormat.json", true, map[string]int{})
+
+	expectedErrorMap := map[string]int{}
+	expectedErrorMap["enum"] = 1
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/invalidLogFilesWithW3CFormat.json", false, expectedErrorMap)
+}
+
+func TestLogFilesStreamBucketsConfig(t *testing.T) {
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/validLogFilesWithStreamBuckets.json", true, map[string]int{})
+
+	expectedErrorMap := map[string]int{}
+	expectedErrorMap["number_gte"] = 1
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/invalidLogFilesWithStreamBuckets.json", false, expectedErrorMap)
+}
+
 func TestLogWindowsEventConfig(t *testing.T) {
 	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/validLogWindowsEvents.json", true, map[string]int{})
 	expectedErrorMap := map[string]int{}
@@ -89,6 +123,13 @@ func TestLogWindowsEventConfig(t *testing.T) {
 	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/invalidLogWindowsEventsWithInvalidEventFormatType.json", false, expectedErrorMap3)
 }
 
+func TestLogAgentAuditConfig(t *testing.T) {
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/validLogAgentAudit.json", true, map[string]int{})
+	expectedErrorMap := map[string]int{}
+	expectedErrorMap["required"] = 1
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/invalidLogAgentAuditWithMissingLogGroupName.json", false, expectedErrorMap)
+}
+
 func TestMetricsConfig(t *testing.T) {
 	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/validLinuxMetrics.json", true, map[string]int{})
 	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/validWindowsMetrics.json", true, map[string]int{})
@@ -124,6 +165,34 @@ func TestMetricsConfig(t *testing.T) {
 	expectedErrorMap6["required"] = 1
 	expectedErrorMap6["invalid_type"] = 1
 	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/invalidMetricsWithInvalidMetrics_Collected.json", false, expectedErrorMap6)
+	expectedErrorMap7 := map[string]int{}
+	expectedErrorMap7["invalid_type"] = 1
+	expectedErrorMap7["number_all_of"] = 1
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/invalidMetricsWithInvalidMeasurementExclude.json", false, expectedErrorMap7)
+}
+
+func TestDimensionNormalizationConfig(t *testing.T) {
+	expectedErrorMap := map[string]int{}
+	expectedErrorMap["enum"] = 1
+	expectedErrorMap["number_gte"] = 1
+	expectedErrorMap["additional_property_not_allowed"] = 1
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/invalidDimensionNormalization.json", false, expectedErrorMap)
+}
+
+func TestDimensionLookupsConfig(t *testing.T) {
+	expectedErrorMap := map[string]int{}
+	expectedErrorMap["required"] = 1
+	expectedErrorMap["number_gte"] = 1
+	expectedErrorMap["additional_property_not_allowed"] = 1
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/invalidDimensionLookups.json", false, expectedErrorMap)
+}
+
+func TestNamespaceRoutingConfig(t *testing.T) {
+	expectedErrorMap := map[string]int{}
+	expectedErrorMap["number_any_of"] = 1
+	expectedErrorMap["required"] = 1
+	expectedErrorMap["additional_property_not_allowed"] = 1
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/invalidNamespaceRouting.json", false, expectedErrorMap)
 }
 
 func TestCsmConfig_Valid(t *testing.T) {
@@ -159,6 +228,176 @@ func TestProcstatConfig(t *testing.T) {
 	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/validProcstatConfig.json", true, map[string]int{})
 }
 
+func TestProcstatRecoveryConfig(t *testing.T) {
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/validProcstatRecoveryConfig.json", true, map[string]int{})
+
+	expectedErrorMap := map[string]int{}
+	expectedErrorMap["number_all_of"] = 1
+	expectedErrorMap["number_any_of"] = 1
+	expectedErrorMap["required"] = 1
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/invalidProcstatRecoveryWithoutAction.json", false, expectedErrorMap)
+}
+
+func TestJmxConfig(t *testing.T) {
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/validJmxConfig.json", true, map[string]int{})
+
+	expectedErrorMap := map[string]int{}
+	expectedErrorMap["enum"] = 1
+	expectedErrorMap["required"] = 1
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/invalidJmxConfig.json", false, expectedErrorMap)
+}
+
+func TestParquetExportConfig(t *testing.T) {
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/validParquetExportConfig.json", true, map[string]int{})
+
+	expectedErrorMap := map[string]int{}
+	expectedErrorMap["enum"] = 1
+	expectedErrorMap["required"] = 1
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/invalidParquetExportConfig.json", false, expectedErrorMap)
+}
+
+func TestTimeSyncConfig(t *testing.T) {
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/validTimeSyncConfig.json", true, map[string]int{})
+
+	expectedErrorMap := map[string]int{}
+	expectedErrorMap["enum"] = 1
+	expectedErrorMap["number_all_of"] = 1
+	expectedErrorMap["required"] = 1
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/invalidTimeSyncConfig.json", false, expectedErrorMap)
+}
+
+func TestSystemdConfig(t *testing.T) {
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/validSystemdConfig.json", true, map[string]int{})
+
+	expectedErrorMap := map[string]int{}
+	expectedErrorMap["array_min_items"] = 1
+	expectedErrorMap["number_all_of"] = 1
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/invalidSystemdConfig.json", false, expectedErrorMap)
+}
+
+func TestCgroupConfig(t *testing.T) {
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/validCgroupConfig.json", true, map[string]int{})
+
+	expectedErrorMap := map[string]int{}
+	expectedErrorMap["array_min_items"] = 1
+	expectedErrorMap["pattern"] = 1
+	expectedErrorMap["number_all_of"] = 1
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/invalidCgroupConfig.json", false, expectedErrorMap)
+}
+
+func TestFdConfig(t *testing.T) {
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/validFdConfig.json", true, map[string]int{})
+
+	expectedErrorMap := map[string]int{}
+	expectedErrorMap["number_lte"] = 1
+	expectedErrorMap["number_all_of"] = 1
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/invalidFdConfig.json", false, expectedErrorMap)
+}
+
+func TestTopProcessesConfig(t *testing.T) {
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/validTopProcessesConfig.json", true, map[string]int{})
+
+	expectedErrorMap := map[string]int{}
+	expectedErrorMap["number_gte"] = 1
+	expectedErrorMap["enum"] = 1
+	expectedErrorMap["number_all_of"] = 1
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/invalidTopProcessesConfig.json", false, expectedErrorMap)
+}
+
+func TestCloudWatchQueryConfig(t *testing.T) {
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/validCloudWatchQueryConfig.json", true, map[string]int{})
+
+	expectedErrorMap := map[string]int{}
+	expectedErrorMap["pattern"] = 1
+	expectedErrorMap["number_one_of"] = 1
+	expectedErrorMap["required"] = 1
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/invalidCloudWatchQueryConfig.json", false, expectedErrorMap)
+}
+
+func TestMemNumaConfig(t *testing.T) {
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/validMemNumaConfig.json", true, map[string]int{})
+
+	expectedErrorMap := map[string]int{}
+	expectedErrorMap["invalid_type"] = 1
+	expectedErrorMap["number_all_of"] = 1
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/invalidMemNumaConfig.json", false, expectedErrorMap)
+}
+
+func TestNodeExporterConfig(t *testing.T) {
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/validNodeExporterConfig.json", true, map[string]int{})
+
+	expectedErrorMap := map[string]int{}
+	expectedErrorMap["pattern"] = 1
+	expectedErrorMap["number_all_of"] = 1
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/invalidNodeExporterConfig.json", false, expectedErrorMap)
+}
+
+func TestContainerdConfig(t *testing.T) {
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/validContainerdConfig.json", true, map[string]int{})
+
+	expectedErrorMap := map[string]int{}
+	expectedErrorMap["pattern"] = 1
+	expectedErrorMap["number_all_of"] = 1
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/invalidContainerdConfig.json", false, expectedErrorMap)
+}
+
+func TestDockerConfig(t *testing.T) {
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/validDockerConfig.json", true, map[string]int{})
+
+	expectedErrorMap := map[string]int{}
+	expectedErrorMap["pattern"] = 1
+	expectedErrorMap["number_all_of"] = 1
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/invalidDockerConfig.json", false, expectedErrorMap)
+}
+
+func TestKafkaConfig(t *testing.T) {
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/validKafkaConfig.json", true, map[string]int{})
+
+	expectedErrorMap := map[string]int{}
+	expectedErrorMap["pattern"] = 1
+	expectedErrorMap["invalid_type"] = 1
+	expectedErrorMap["number_all_of"] = 1
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/invalidKafkaConfig.json", false, expectedErrorMap)
+}
+
+func TestHAProxyConfig(t *testing.T) {
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/validHAProxyConfig.json", true, map[string]int{})
+
+	expectedErrorMap := map[string]int{}
+	expectedErrorMap["pattern"] = 1
+	expectedErrorMap["invalid_type"] = 1
+	expectedErrorMap["number_all_of"] = 1
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/invalidHAProxyConfig.json", false, expectedErrorMap)
+}
+
+func TestRabbitMQConfig(t *testing.T) {
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/validRabbitMQConfig.json", true, map[string]int{})
+
+	expectedErrorMap := map[string]int{}
+	expectedErrorMap["pattern"] = 1
+	expectedErrorMap["invalid_type"] = 1
+	expectedErrorMap["number_all_of"] = 1
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/invalidRabbitMQConfig.json", false, expectedErrorMap)
+}
+
+func TestKernelConfig(t *testing.T) {
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/validKernelConfig.json", true, map[string]int{})
+
+	expectedErrorMap := map[string]int{}
+	expectedErrorMap["invalid_type"] = 1
+	expectedErrorMap["number_all_of"] = 1
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/invalidKernelConfig.json", false, expectedErrorMap)
+}
+
+func TestEmfListenerConfig(t *testing.T) {
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/validEmfListenerConfig.json", true, map[string]int{})
+
+	expectedErrorMap := map[string]int{}
+	expectedErrorMap["invalid_type"] = 1
+	expectedErrorMap["number_lte"] = 1
+	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/invalidEmfListenerConfig.json", false, expectedErrorMap)
+}
+
 func TestEthtoolConfig(t *testing.T) {
 	checkIfSchemaValidateAsExpected(t, "../../translator/config/sampleSchema/validEthtoolConfig.json", true, map[string]int{})
 }
@@ -0,0 +1,82 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"io/ioutil"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/aws/amazon-cloudwatch-agent/translator/context"
+)
+
+// BOOTSTRAP_TAG_KEY is the instance tag holding the location of the config of an agent started without one, e.g.
+// "ssm:AmazonCloudWatch-linux" or "s3://my-bucket/amazon-cloudwatch-agent.json".
+const BOOTSTRAP_TAG_KEY = "cwagent:config-location"
+
+// bootstrapped is true once the config has been looked up, it is looked up at most once per start.
+var bootstrapped bool
+
+// bootstrapConfig installs the config located by the BOOTSTRAP_TAG_KEY tag of the instance in the json config dir, so
+// a fleet is onboarded by tagging its instances instead of scripting the fetch of the config in their user data.
+// It returns the installed config files, or false when the instance has no such tag or the config cannot be fetched.
+func bootstrapConfig() ([]string, bool) {
+	bootstrapped = true
+	if err := os.MkdirAll(jsonDirPath, 0755); err != nil {
+		log.Printf("E! Cannot create the json config dir %s, ERROR is %v \n", jsonDirPath, err)
+		return nil, false
+	}
+	args := []string{"--output-dir", jsonDirPath, "--download-source", "ec2tag:" + BOOTSTRAP_TAG_KEY, "--mode", "auto"}
+	if _, err := os.Stat(commonConfigPath); err == nil {
+		args = append(args, "--config", commonConfigPath)
+	}
+	cmd := exec.Command(downloaderBinaryPath, args...)
+	stdoutStderr, err := cmd.CombinedOutput()
+	log.Printf("I! %s \n", stdoutStderr)
+	if err != nil {
+		log.Printf("I! no json configuration was bootstrapped from the %s tag of the instance: %v\n", BOOTSTRAP_TAG_KEY, err)
+		return nil, false
+	}
+	installed, err := installTmpConfigs(jsonDirPath)
+	if err != nil {
+		log.Printf("E! Cannot install the bootstrapped json configuration, ERROR is %v \n", err)
+		removeConfigs(installed)
+		return nil, false
+	}
+	log.Printf("I! json configuration has been bootstrapped from the %s tag of the instance\n", BOOTSTRAP_TAG_KEY)
+	return installed, len(installed) > 0
+}
+
+// installTmpConfigs renames the .tmp configs written by the downloader to the name the translator reads, like
+// "amazon-cloudwatch-agent-ctl -a fetch-config" does once the configs are validated.
+func installTmpConfigs(dir string) ([]string, error) {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var installed []string
+	for _, file := range files {
+		if file.IsDir() || filepath.Ext(file.Name()) != context.TmpFileSuffix {
+			continue
+		}
+		tmpPath := filepath.Join(dir, file.Name())
+		path := strings.TrimSuffix(tmpPath, context.TmpFileSuffix)
+		if err := os.Rename(tmpPath, path); err != nil {
+			return installed, err
+		}
+		installed = append(installed, path)
+	}
+	return installed, nil
+}
+
+func removeConfigs(paths []string) {
+	for _, path := range paths {
+		if err := os.Remove(path); err != nil {
+			log.Printf("E! Cannot remove the json configuration %s, ERROR is %v \n", path, err)
+		}
+	}
+}
@@ -0,0 +1,34 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInstallTmpConfigs(t *testing.T) {
+	dir, err := ioutil.TempDir("", "bootstrap")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "ssm_AmazonCloudWatch-linux.tmp"), []byte("{}"), 0644))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "file_other"), []byte("{}"), 0644))
+
+	installed, err := installTmpConfigs(dir)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{filepath.Join(dir, "ssm_AmazonCloudWatch-linux")}, installed)
+	files, err := ioutil.ReadDir(dir)
+	assert.NoError(t, err)
+	assert.Len(t, files, 2)
+	assert.Equal(t, "file_other", files[0].Name())
+	assert.Equal(t, "ssm_AmazonCloudWatch-linux", files[1].Name())
+
+	removeConfigs(installed)
+	_, err = os.Stat(installed[0])
+	assert.True(t, os.IsNotExist(err))
+}
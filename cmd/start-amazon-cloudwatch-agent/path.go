@@ -25,6 +25,7 @@ const (
 	JSON_DIR_LINUX = "amazon-cloudwatch-agent.d"
 
 	TRANSLATOR_BINARY_LINUX = "config-translator"
+	DOWNLOADER_BINARY_LINUX = "config-downloader"
 	AGENT_BINARY_LINUX      = "amazon-cloudwatch-agent"
 )
 
@@ -66,9 +67,11 @@ func startAgent(writer io.WriteCloser) error {
 		return err
 	}
 
-	// linux command has pid passed while windows does not
+	// linux command has pid passed while windows does not, the updater hands the agent off to the new binary through
+	// the handoff socket
 	agentCmd := []string{agentBinaryPath, "-config", tomlConfigPath, "-envconfig", envConfigPath,
-		"-pidfile", AGENT_DIR_LINUX + "/var/amazon-cloudwatch-agent.pid"}
+		"-pidfile", AGENT_DIR_LINUX + "/var/amazon-cloudwatch-agent.pid",
+		"-handoff-socket", AGENT_DIR_LINUX + "/var/amazon-cloudwatch-agent.sock"}
 	if err = syscall.Exec(name, agentCmd, os.Environ()); err != nil {
 		// log file is closed, so use fmt here
 		fmt.Printf("E! Exec failed: %v \n", err)
@@ -97,5 +100,6 @@ func init() {
 	agentLogFilePath = AGENT_DIR_LINUX + "/logs/" + AGENT_LOG_FILE
 
 	translatorBinaryPath = AGENT_DIR_LINUX + "/bin/" + TRANSLATOR_BINARY_LINUX
+	downloaderBinaryPath = AGENT_DIR_LINUX + "/bin/" + DOWNLOADER_BINARY_LINUX
 	agentBinaryPath = AGENT_DIR_LINUX + "/bin/" + AGENT_BINARY_LINUX
 }
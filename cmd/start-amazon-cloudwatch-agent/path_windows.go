@@ -20,6 +20,7 @@ const (
 	JSON_DIR_WINDOWS = "\\Configs"
 
 	TRANSLATOR_BINARY_WINDOWS = "config-translator.exe"
+	DOWNLOADER_BINARY_WINDOWS = "config-downloader.exe"
 	AGENT_BINARY_WINDOWS      = "amazon-cloudwatch-agent.exe"
 )
 
@@ -59,5 +60,6 @@ func init() {
 	agentLogFilePath = agentConfigDir + "\\Logs\\" + AGENT_LOG_FILE
 
 	translatorBinaryPath = agentRootDir + "\\" + TRANSLATOR_BINARY_WINDOWS
+	downloaderBinaryPath = agentRootDir + "\\" + DOWNLOADER_BINARY_WINDOWS
 	agentBinaryPath = agentRootDir + "\\" + AGENT_BINARY_WINDOWS
 }
@@ -36,6 +36,7 @@ var (
 	agentLogFilePath string
 
 	translatorBinaryPath string
+	downloaderBinaryPath string
 	agentBinaryPath      string
 )
 
@@ -62,6 +63,16 @@ func translateConfig() error {
 
 				if status.ExitStatus() == config.ERR_CODE_NOJSONFILE {
 					log.Printf("I! there is no json configuration when running translator\n")
+					if runInContainer != config.RUN_IN_CONTAINER_TRUE && !bootstrapped {
+						if installed, ok := bootstrapConfig(); ok {
+							if err := translateConfig(); err != nil {
+								// the next start fetches the config again instead of failing on the installed one
+								removeConfigs(installed)
+								return err
+							}
+							return nil
+						}
+					}
 					os.Exit(0)
 				}
 			}
@@ -39,8 +39,10 @@ replace k8s.io/klog => github.com/simonpasquier/klog-gokit v0.1.0
 replace golang.zx2c4.com/wireguard v0.0.20200121 => golang.zx2c4.com/wireguard v0.0.0-20200121152719-05b03c675090
 
 require (
+	collectd.org v0.3.0
 	github.com/BurntSushi/toml v0.3.1
 	github.com/Jeffail/gabs v1.4.0
+	github.com/apache/thrift v0.13.0
 	github.com/aws/aws-sdk-go v1.30.15
 	github.com/aws/aws-sdk-go-v2 v1.16.3
 	github.com/aws/aws-sdk-go-v2/config v1.15.3
@@ -53,6 +55,7 @@ require (
 	github.com/docker/docker v1.13.1
 	github.com/go-kit/kit v0.10.0
 	github.com/gobwas/glob v0.2.3
+	github.com/golang/protobuf v1.3.5
 	github.com/google/cadvisor v0.36.0
 	github.com/google/go-cmp v0.5.7
 	github.com/hashicorp/golang-lru v0.5.4
@@ -70,10 +73,12 @@ require (
 	github.com/shirou/gopsutil v2.20.5+incompatible
 	github.com/stretchr/testify v1.7.0
 	github.com/xeipuuv/gojsonschema v1.2.0
+	github.com/xitongsys/parquet-go v1.5.2
 	golang.org/x/net v0.0.0-20200301022130-244492dfa37a
 	golang.org/x/sync v0.0.0-20200317015054-43a5402ce75a
 	golang.org/x/sys v0.0.0-20200316230553-a7d97aace0b0
 	golang.org/x/text v0.3.3
+	google.golang.org/grpc v1.28.0
 	gopkg.in/fsnotify.v1 v1.4.7
 	gopkg.in/natefinch/lumberjack.v2 v2.0.0
 	gopkg.in/tomb.v1 v1.0.0-20141024135613-dd632973f1e7
@@ -0,0 +1,293 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+// Package audit records the changes of the agent configuration, credentials and endpoints as structured events.
+// The events are only recorded when the audit is enabled by the agent_audit input, which publishes them to a
+// dedicated log group.
+package audit
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	EventConfigApplied      = "ConfigApplied"
+	EventConfigReloaded     = "ConfigReloaded"
+	EventCredentialsAssumed = "CredentialsAssumed"
+	EventCredentialsRotated = "CredentialsRotated"
+	EventEndpointChanged    = "EndpointChanged"
+	EventRetentionDrifted   = "RetentionDrifted"
+
+	// the events are kept in memory until the log pipeline publishes them, the oldest are dropped beyond this limit
+	maxPendingEvents = 1000
+	defaultEndpoint  = "default"
+)
+
+type Event struct {
+	Time      time.Time         `json:"timestamp"`
+	EventType string            `json:"event_type"`
+	Details   map[string]string `json:"details,omitempty"`
+}
+
+// ConfigInfo describes the configuration applied by the agent.
+type ConfigInfo struct {
+	File    string
+	Sha256  string
+	Inputs  []string
+	Outputs []string
+}
+
+type endpointState struct {
+	Region   string `json:"region"`
+	Endpoint string `json:"endpoint"`
+}
+
+// state is persisted so the changes are detected across the agent restarts.
+type state struct {
+	ConfigSha256 string                   `json:"config_sha256"`
+	Endpoints    map[string]endpointState `json:"endpoints"`
+}
+
+type auditor struct {
+	sync.Mutex
+	enabled   bool
+	stateFile string
+	state     state
+	config    *ConfigInfo
+	reload    bool
+	pending   []Event
+	out       chan Event
+	now       func() time.Time
+}
+
+var defaultAuditor = newAuditor()
+
+func newAuditor() *auditor {
+	return &auditor{now: time.Now}
+}
+
+// SetConfig records the configuration being applied. The ConfigApplied event is emitted when the audit is enabled,
+// and the configurations applied after the first one in the same process are reported as ConfigReloaded.
+func SetConfig(info ConfigInfo) {
+	defaultAuditor.setConfig(info)
+}
+
+// Enable loads the audit state from the state file and emits the event for the applied configuration.
+func Enable(stateFile string) error {
+	return defaultAuditor.enable(stateFile)
+}
+
+// Disable stops recording the events, e.g. when the agent_audit input is stopped on reload.
+func Disable() {
+	defaultAuditor.disable()
+}
+
+// EndpointConfigured emits an EndpointChanged event if the endpoint used for the service differs from the last
+// endpoint recorded. An empty endpoint means the default endpoint of the region.
+func EndpointConfigured(service, region, endpoint string) {
+	defaultAuditor.endpointConfigured(service, region, endpoint)
+}
+
+// CredentialsAssumed emits a CredentialsAssumed event for the role assumed through STS.
+func CredentialsAssumed(roleARN, region string) {
+	defaultAuditor.record(EventCredentialsAssumed, map[string]string{
+		"role_arn": roleARN,
+		"region":   region,
+	})
+}
+
+// CredentialsRotated emits a CredentialsRotated event when the credentials read from the source changed.
+// Only the last characters of the access key id are recorded.
+func CredentialsRotated(source, previousAccessKeyID, accessKeyID string) {
+	defaultAuditor.record(EventCredentialsRotated, map[string]string{
+		"source":                 source,
+		"previous_access_key_id": maskAccessKeyID(previousAccessKeyID),
+		"access_key_id":          maskAccessKeyID(accessKeyID),
+	})
+}
+
+// RetentionDrifted emits a RetentionDrifted event when the retention of a log group was changed out of the agent, an
+// actual retention of 0 means the events never expire.
+func RetentionDrifted(group string, configured int, actual int64, restored bool) {
+	defaultAuditor.record(EventRetentionDrifted, map[string]string{
+		"log_group_name":    group,
+		"retention_in_days": strconv.Itoa(configured),
+		"actual_retention":  strconv.FormatInt(actual, 10),
+		"restored":          strconv.FormatBool(restored),
+	})
+}
+
+// Subscribe returns the channel receiving the events, starting with the events recorded so far.
+func Subscribe() <-chan Event {
+	return defaultAuditor.subscribe()
+}
+
+// Unsubscribe stops sending the events to the channel, the following events are kept in memory.
+func Unsubscribe(ch <-chan Event) {
+	defaultAuditor.unsubscribe(ch)
+}
+
+func (a *auditor) setConfig(info ConfigInfo) {
+	a.Lock()
+	defer a.Unlock()
+	a.reload = a.config != nil
+	a.config = &info
+}
+
+func (a *auditor) enable(stateFile string) error {
+	a.Lock()
+	defer a.Unlock()
+	a.enabled = true
+	a.stateFile = stateFile
+	a.state = state{Endpoints: make(map[string]endpointState)}
+
+	var err error
+	if content, readErr := ioutil.ReadFile(stateFile); readErr == nil {
+		if err = json.Unmarshal(content, &a.state); err != nil {
+			log.Printf("W! [audit] ignoring the invalid audit state file %v: %v", stateFile, err)
+			a.state = state{Endpoints: make(map[string]endpointState)}
+			err = nil
+		}
+		if a.state.Endpoints == nil {
+			a.state.Endpoints = make(map[string]endpointState)
+		}
+	} else if !os.IsNotExist(readErr) {
+		err = readErr
+	}
+
+	if a.config != nil {
+		eventType := EventConfigApplied
+		if a.reload {
+			eventType = EventConfigReloaded
+		}
+		a.recordLocked(eventType, map[string]string{
+			"config_file":            a.config.File,
+			"config_sha256":          a.config.Sha256,
+			"previous_config_sha256": a.state.ConfigSha256,
+			"changed":                strconv.FormatBool(a.config.Sha256 != a.state.ConfigSha256),
+			"inputs":                 strings.Join(a.config.Inputs, ","),
+			"outputs":                strings.Join(a.config.Outputs, ","),
+		})
+		a.state.ConfigSha256 = a.config.Sha256
+		a.saveStateLocked()
+	}
+	return err
+}
+
+func (a *auditor) disable() {
+	a.Lock()
+	defer a.Unlock()
+	a.enabled = false
+}
+
+func (a *auditor) endpointConfigured(service, region, endpoint string) {
+	a.Lock()
+	defer a.Unlock()
+	if !a.enabled {
+		return
+	}
+	if endpoint == "" {
+		endpoint = defaultEndpoint
+	}
+	current := endpointState{Region: region, Endpoint: endpoint}
+	previous, ok := a.state.Endpoints[service]
+	if ok && previous == current {
+		return
+	}
+	a.recordLocked(EventEndpointChanged, map[string]string{
+		"service":           service,
+		"region":            region,
+		"endpoint":          endpoint,
+		"previous_region":   previous.Region,
+		"previous_endpoint": previous.Endpoint,
+	})
+	a.state.Endpoints[service] = current
+	a.saveStateLocked()
+}
+
+func (a *auditor) record(eventType string, details map[string]string) {
+	a.Lock()
+	defer a.Unlock()
+	if !a.enabled {
+		return
+	}
+	a.recordLocked(eventType, details)
+}
+
+func (a *auditor) recordLocked(eventType string, details map[string]string) {
+	e := Event{
+		Time:      a.now(),
+		EventType: eventType,
+		Details:   details,
+	}
+	log.Printf("I! [audit] %v %v", eventType, details)
+	if a.out != nil {
+		select {
+		case a.out <- e:
+		default:
+			log.Printf("W! [audit] dropping the %v event because the audit log is not published fast enough", eventType)
+		}
+		return
+	}
+	if len(a.pending) >= maxPendingEvents {
+		a.pending = a.pending[1:]
+	}
+	a.pending = append(a.pending, e)
+}
+
+func (a *auditor) saveStateLocked() {
+	content, err := json.Marshal(a.state)
+	if err == nil {
+		if err = os.MkdirAll(filepath.Dir(a.stateFile), 0755); err == nil {
+			err = ioutil.WriteFile(a.stateFile, content, 0644)
+		}
+	}
+	if err != nil {
+		log.Printf("E! [audit] failed to save the audit state to %v: %v", a.stateFile, err)
+	}
+}
+
+func (a *auditor) subscribe() <-chan Event {
+	a.Lock()
+	defer a.Unlock()
+	out := make(chan Event, maxPendingEvents)
+	for _, e := range a.pending {
+		out <- e
+	}
+	a.pending = nil
+	a.out = out
+	return out
+}
+
+func (a *auditor) unsubscribe(ch <-chan Event) {
+	a.Lock()
+	defer a.Unlock()
+	if a.out == nil || (<-chan Event)(a.out) != ch {
+		return
+	}
+	a.out = nil
+	// keep the events not published yet for the next subscriber
+	for {
+		select {
+		case e := <-ch:
+			a.pending = append(a.pending, e)
+		default:
+			return
+		}
+	}
+}
+
+func maskAccessKeyID(accessKeyID string) string {
+	if len(accessKeyID) <= 4 {
+		return accessKeyID
+	}
+	return strings.Repeat("*", len(accessKeyID)-4) + accessKeyID[len(accessKeyID)-4:]
+}
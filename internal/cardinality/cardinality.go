@@ -0,0 +1,127 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+// Package cardinality limits the series, the metric names with their dimensions, an input publishes by aggregation
+// interval, so a client tagging its metrics with e.g. a request id doesn't publish a CloudWatch metric by request.
+// The series over the limit are dropped, or aggregated into the overflow series of their metric, and counted in the
+// "cardinality" stats reported by the internal input.
+package cardinality
+
+import (
+	"fmt"
+	"hash/fnv"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/influxdata/telegraf/selfstat"
+)
+
+const (
+	// the series over the limit are dropped, or published as the overflow series of their metric
+	OverflowDrop      = "drop"
+	OverflowAggregate = "aggregate"
+
+	// the dimension of the overflow series, replacing the dimensions of the series over the limit
+	OverflowTag   = "overflow"
+	overflowValue = "true"
+
+	defaultInterval = time.Minute
+)
+
+// Guard admits up to a maximum of series by interval, the series admitted during an interval are admitted until its
+// end. A nil Guard admits every series.
+type Guard struct {
+	input     string
+	maxSeries int
+	overflow  string
+	interval  time.Duration
+	now       func() time.Time
+
+	mu     sync.Mutex
+	series map[uint64]bool
+	start  time.Time
+	warned bool
+
+	limited selfstat.Stat
+}
+
+// NewGuard returns the guard of the series of the input, nil when maxSeries isn't positive. The interval is the
+// aggregation interval of the input, a minute when 0.
+func NewGuard(input string, maxSeries int, overflow string, interval time.Duration) (*Guard, error) {
+	switch overflow {
+	case "":
+		overflow = OverflowDrop
+	case OverflowDrop, OverflowAggregate:
+	default:
+		return nil, fmt.Errorf("invalid series overflow %q, it must be %s or %s", overflow, OverflowDrop, OverflowAggregate)
+	}
+	if maxSeries <= 0 {
+		return nil, nil
+	}
+	if interval <= 0 {
+		interval = defaultInterval
+	}
+	return &Guard{
+		input:     input,
+		maxSeries: maxSeries,
+		overflow:  overflow,
+		interval:  interval,
+		now:       time.Now,
+		series:    map[uint64]bool{},
+		limited:   selfstat.Register("cardinality", "series_limited", map[string]string{"input": input}),
+	}, nil
+}
+
+// Admit returns the tags the series of the metric is published with, and false when it's dropped. The series over
+// the limit are aggregated into the series of the metric with the only overflow dimension, which isn't counted.
+func (g *Guard) Admit(name string, tags map[string]string) (map[string]string, bool) {
+	if g == nil {
+		return tags, true
+	}
+	key := seriesKey(name, tags)
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if now := g.now(); now.Sub(g.start) >= g.interval {
+		g.series = map[uint64]bool{}
+		g.start = now
+		g.warned = false
+	}
+	if g.series[key] {
+		return tags, true
+	}
+	if len(g.series) < g.maxSeries {
+		g.series[key] = true
+		return tags, true
+	}
+
+	g.limited.Incr(1)
+	if !g.warned {
+		g.warned = true
+		log.Printf("W! [%s] Reached the limit of %d series in %v, the new series are %s until the next interval",
+			g.input, g.maxSeries, g.interval, map[string]string{OverflowDrop: "dropped", OverflowAggregate: "aggregated into the overflow series"}[g.overflow])
+	}
+	if g.overflow == OverflowDrop {
+		return nil, false
+	}
+	return map[string]string{OverflowTag: overflowValue}, true
+}
+
+func seriesKey(name string, tags map[string]string) uint64 {
+	keys := make([]string, 0, len(tags))
+	for k := range tags {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	h := fnv.New64a()
+	h.Write([]byte(name))
+	for _, k := range keys {
+		h.Write([]byte{0})
+		h.Write([]byte(k))
+		h.Write([]byte{0})
+		h.Write([]byte(tags[k]))
+	}
+	return h.Sum64()
+}
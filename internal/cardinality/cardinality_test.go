@@ -0,0 +1,58 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package cardinality
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGuard_Drop(t *testing.T) {
+	g, err := NewGuard("statsd", 2, "", time.Minute)
+	assert.NoError(t, err)
+	now := time.Now()
+	g.now = func() time.Time { return now }
+
+	for _, id := range []string{"1", "2", "1"} {
+		tags, ok := g.Admit("latency", map[string]string{"request_id": id})
+		assert.True(t, ok)
+		assert.Equal(t, map[string]string{"request_id": id}, tags)
+	}
+	_, ok := g.Admit("latency", map[string]string{"request_id": "3"})
+	assert.False(t, ok)
+	_, ok = g.Admit("errors", nil)
+	assert.False(t, ok)
+	assert.Equal(t, int64(2), g.limited.Get())
+
+	// the series are counted again at the next interval
+	now = now.Add(time.Minute)
+	_, ok = g.Admit("latency", map[string]string{"request_id": "3"})
+	assert.True(t, ok)
+}
+
+func TestGuard_Aggregate(t *testing.T) {
+	g, err := NewGuard("collectd_listener", 1, OverflowAggregate, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, time.Minute, g.interval)
+
+	_, ok := g.Admit("cpu_value", map[string]string{"host": "web-01"})
+	assert.True(t, ok)
+	tags, ok := g.Admit("cpu_value", map[string]string{"host": "web-02"})
+	assert.True(t, ok)
+	assert.Equal(t, map[string]string{"overflow": "true"}, tags)
+}
+
+func TestNewGuard(t *testing.T) {
+	g, err := NewGuard("statsd", 0, OverflowAggregate, time.Minute)
+	assert.NoError(t, err)
+	assert.Nil(t, g)
+	tags, ok := g.Admit("latency", map[string]string{"request_id": "1"})
+	assert.True(t, ok)
+	assert.Equal(t, map[string]string{"request_id": "1"}, tags)
+
+	_, err = NewGuard("statsd", 10, "sample", time.Minute)
+	assert.EqualError(t, err, `invalid series overflow "sample", it must be drop or aggregate`)
+}
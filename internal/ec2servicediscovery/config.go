@@ -0,0 +1,56 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package ec2servicediscovery
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	AwsSdkLevelRetryCount = 3
+
+	portSeparator = ";"
+)
+
+// InstanceConfig selects the instances with all of its tags, the values accept the * and ? wildcards of the EC2
+// filters, and the ports and path their exporters are scraped on.
+type InstanceConfig struct {
+	JobName      string            `toml:"sd_job_name"`
+	MetricsPath  string            `toml:"sd_metrics_path"`
+	MetricsPorts string            `toml:"sd_metrics_ports"`
+	InstanceTags map[string]string `toml:"sd_instance_tags"`
+
+	metricsPortList []int
+}
+
+func (i *InstanceConfig) String() string {
+	return fmt.Sprintf("InstanceTags: %v\nJobName: %v\nMetricsPath: %v\nMetricsPorts: %v\n",
+		i.InstanceTags,
+		i.JobName,
+		i.MetricsPath,
+		i.MetricsPorts,
+	)
+}
+
+func (i *InstanceConfig) init() {
+	ports := strings.Split(i.MetricsPorts, portSeparator)
+	for _, v := range ports {
+		if port, err := strconv.Atoi(strings.TrimSpace(v)); err != nil || port <= 0 {
+			continue
+		} else {
+			i.metricsPortList = append(i.metricsPortList, port)
+		}
+	}
+}
+
+type ServiceDiscoveryConfig struct {
+	Frequency           string            `toml:"sd_frequency"`
+	ResultFile          string            `toml:"sd_result_file"`
+	HttpSdListenAddress string            `toml:"sd_http_sd_listen_address"`
+	Region              string            `toml:"sd_region"`
+	VpcId               string            `toml:"sd_vpc_id"`
+	Instances           []*InstanceConfig `toml:"instance_list"`
+}
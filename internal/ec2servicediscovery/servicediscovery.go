@@ -0,0 +1,283 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package ec2servicediscovery
+
+import (
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"regexp"
+	"strconv"
+	"sync"
+	"time"
+
+	configaws "github.com/aws/amazon-cloudwatch-agent/cfg/aws"
+	"github.com/aws/amazon-cloudwatch-agent/internal/ecsservicediscovery"
+	"github.com/aws/amazon-cloudwatch-agent/internal/sidecar"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/ec2metadata"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/ec2/ec2iface"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	jobNameLabel          = "job"
+	metricsPathLabel      = "__metrics_path__"
+	instanceIdLabel       = "InstanceId"
+	instanceTypeLabel     = "InstanceType"
+	vpcIdLabel            = "VpcId"
+	subnetIdLabel         = "SubnetId"
+	availabilityZoneLabel = "AvailabilityZone"
+
+	//https://prometheus.io/docs/prometheus/latest/configuration/configuration/#scrape_config
+	defaultPrometheusMetricsPath = "/metrics"
+	prometheusLabelNamePattern   = "^[a-zA-Z_][a-zA-Z0-9_]*$"
+)
+
+// vpcIdResolver returns the VPC of the instance of the agent.
+type vpcIdResolver func() (string, error)
+
+// ServiceDiscovery discovers the Prometheus exporters of the EC2 instances selected by their tags, in the VPC of the
+// agent by default, for the clusters managed without ECS or EKS. The targets are the private IPs of the running
+// instances with the configured ports and path, written to the result file for the file_sd_configs of the
+// Prometheus config.
+type ServiceDiscovery struct {
+	Config *ServiceDiscoveryConfig
+
+	svcEc2   ec2iface.EC2API
+	vpcIdFn  vpcIdResolver
+	tagRegex *regexp.Regexp
+
+	// the targets of the last successful discovery, served by the http_sd endpoint
+	targetsLock   sync.RWMutex
+	latestTargets []*ecsservicediscovery.PrometheusTarget
+}
+
+func (sd *ServiceDiscovery) init() {
+	credentialConfig := &configaws.CredentialConfig{
+		Region: sd.Config.Region,
+	}
+	configProvider := credentialConfig.Credentials()
+	sd.svcEc2 = ec2.New(configProvider, aws.NewConfig().WithRegion(sd.Config.Region).WithMaxRetries(AwsSdkLevelRetryCount))
+	metadata := ec2metadata.New(configProvider)
+	sd.vpcIdFn = func() (string, error) {
+		mac, err := metadata.GetMetadata("mac")
+		if err != nil {
+			return "", err
+		}
+		return metadata.GetMetadata("network/interfaces/macs/" + mac + "/vpc-id")
+	}
+	sd.initConfig()
+}
+
+func (sd *ServiceDiscovery) initConfig() {
+	sd.tagRegex = regexp.MustCompile(prometheusLabelNamePattern)
+	for _, i := range sd.Config.Instances {
+		i.init()
+	}
+}
+
+func StartEC2ServiceDiscovery(sd *ServiceDiscovery, shutDownChan chan interface{}, wg *sync.WaitGroup) {
+	defer wg.Done()
+
+	if !sd.validateConfig() {
+		return
+	}
+
+	frequency, _ := time.ParseDuration(sd.Config.Frequency)
+	sd.init()
+	sd.resolveVpcId()
+	if sd.Config.HttpSdListenAddress != "" {
+		httpSdServer := ecsservicediscovery.NewHttpSdServer(sd.Config.HttpSdListenAddress, sd.Targets).WithName("EC2 SD")
+		if err := httpSdServer.Start(); err != nil {
+			log.Printf("E! EC2 SD fails to start the http_sd endpoint on %v: %v\n", sd.Config.HttpSdListenAddress, err)
+		} else {
+			defer httpSdServer.Stop()
+		}
+	}
+	// the instances are discovered at once, the scrapes don't wait for the first tick
+	sd.work()
+	t := time.NewTicker(frequency)
+	defer t.Stop()
+	for {
+		select {
+		case <-shutDownChan:
+			return
+		case <-t.C:
+			if sidecar.Draining() {
+				// the targets are not refreshed while the sidecar drains its logs before stopping
+				continue
+			}
+			sd.work()
+		}
+	}
+}
+
+// resolveVpcId restricts the discovery to the VPC of the agent when sd_vpc_id isn't set, the instances of every VPC
+// of the region are discovered when the instance metadata isn't available.
+func (sd *ServiceDiscovery) resolveVpcId() {
+	if sd.Config.VpcId != "" {
+		return
+	}
+	vpcId, err := sd.vpcIdFn()
+	if err != nil || vpcId == "" {
+		log.Printf("W! EC2 SD fails to get the VPC of the agent from the instance metadata, the instances of every VPC are discovered: %v\n", err)
+		return
+	}
+	sd.Config.VpcId = vpcId
+}
+
+func (sd *ServiceDiscovery) work() {
+	targets, err := sd.discover()
+	// Ignore partial result to avoid overwriting existing targets
+	if err != nil {
+		log.Printf("E! EC2 SD fails to discover the instances: %v\n", err)
+		return
+	}
+	if err := sd.export(targets); err != nil {
+		log.Printf("E! EC2 SD fails to export the targets: %v\n", err)
+		return
+	}
+	log.Printf("D! EC2 SD discovered %d targets\n", len(targets))
+}
+
+// discover returns the targets of the running instances matching the tags of each instance config, deduplicated by
+// target and metrics path.
+func (sd *ServiceDiscovery) discover() ([]*ecsservicediscovery.PrometheusTarget, error) {
+	targets := map[string]*ecsservicediscovery.PrometheusTarget{}
+	for _, config := range sd.Config.Instances {
+		input := &ec2.DescribeInstancesInput{Filters: sd.filters(config)}
+		err := sd.svcEc2.DescribeInstancesPages(input, func(output *ec2.DescribeInstancesOutput, lastPage bool) bool {
+			for _, reservation := range output.Reservations {
+				for _, instance := range reservation.Instances {
+					sd.addTargets(config, instance, targets)
+				}
+			}
+			return true
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	result := make([]*ecsservicediscovery.PrometheusTarget, 0, len(targets))
+	for _, target := range targets {
+		result = append(result, target)
+	}
+	return result, nil
+}
+
+func (sd *ServiceDiscovery) filters(config *InstanceConfig) []*ec2.Filter {
+	filters := []*ec2.Filter{{Name: aws.String("instance-state-name"), Values: aws.StringSlice([]string{ec2.InstanceStateNameRunning})}}
+	if sd.Config.VpcId != "" {
+		filters = append(filters, &ec2.Filter{Name: aws.String("vpc-id"), Values: aws.StringSlice([]string{sd.Config.VpcId})})
+	}
+	for key, value := range config.InstanceTags {
+		filters = append(filters, &ec2.Filter{Name: aws.String("tag:" + key), Values: aws.StringSlice([]string{value})})
+	}
+	return filters
+}
+
+// addTargets adds a target for each port of the instance config, labeled with the instance and its tags which are
+// valid label names, the job of the config overrides a job tag.
+func (sd *ServiceDiscovery) addTargets(config *InstanceConfig, instance *ec2.Instance, targets map[string]*ecsservicediscovery.PrometheusTarget) {
+	ip := aws.StringValue(instance.PrivateIpAddress)
+	if ip == "" {
+		return
+	}
+	metricsPath := config.MetricsPath
+	if metricsPath == "" {
+		metricsPath = defaultPrometheusMetricsPath
+	}
+	for _, port := range config.metricsPortList {
+		target := ip + ":" + strconv.Itoa(port)
+		key := target + metricsPath
+		if _, ok := targets[key]; ok {
+			continue
+		}
+		labels := map[string]string{}
+		for _, tag := range instance.Tags {
+			if sd.tagRegex.MatchString(aws.StringValue(tag.Key)) {
+				addLabel(labels, aws.StringValue(tag.Key), aws.StringValue(tag.Value))
+			}
+		}
+		addLabel(labels, instanceIdLabel, aws.StringValue(instance.InstanceId))
+		addLabel(labels, instanceTypeLabel, aws.StringValue(instance.InstanceType))
+		addLabel(labels, vpcIdLabel, aws.StringValue(instance.VpcId))
+		addLabel(labels, subnetIdLabel, aws.StringValue(instance.SubnetId))
+		if instance.Placement != nil {
+			addLabel(labels, availabilityZoneLabel, aws.StringValue(instance.Placement.AvailabilityZone))
+		}
+		addLabel(labels, metricsPathLabel, metricsPath)
+		addLabel(labels, jobNameLabel, config.JobName)
+		targets[key] = &ecsservicediscovery.PrometheusTarget{Targets: []string{target}, Labels: labels}
+	}
+}
+
+func addLabel(labels map[string]string, key, value string) {
+	if value != "" {
+		labels[key] = value
+	}
+}
+
+// export writes the targets to the result file through a temporary file, so the file_sd of Prometheus never reads a
+// partial file.
+func (sd *ServiceDiscovery) export(targets []*ecsservicediscovery.PrometheusTarget) error {
+	m, err := yaml.Marshal(targets)
+	if err != nil {
+		return fmt.Errorf("fail to marshal Prometheus Targets: %v", err)
+	}
+	sd.targetsLock.Lock()
+	sd.latestTargets = targets
+	sd.targetsLock.Unlock()
+
+	tmpResultFile := sd.Config.ResultFile + "_temp"
+	if err := ioutil.WriteFile(tmpResultFile, m, 0644); err != nil {
+		return fmt.Errorf("fail to write Prometheus targets into file %v: %v", tmpResultFile, err)
+	}
+	if err := os.Rename(tmpResultFile, sd.Config.ResultFile); err != nil {
+		os.Remove(tmpResultFile)
+		return fmt.Errorf("fail to rename tmp result file %v to %v: %v", tmpResultFile, sd.Config.ResultFile, err)
+	}
+	return nil
+}
+
+// Targets returns the targets of the last successful discovery.
+func (sd *ServiceDiscovery) Targets() []*ecsservicediscovery.PrometheusTarget {
+	sd.targetsLock.RLock()
+	defer sd.targetsLock.RUnlock()
+	return sd.latestTargets
+}
+
+func (sd *ServiceDiscovery) validateConfig() bool {
+	if sd.Config == nil {
+		return false
+	}
+
+	if len(sd.Config.Instances) == 0 {
+		log.Printf("E! EC2 service discovery is enabled without instance_list.\n")
+		return false
+	}
+
+	for _, i := range sd.Config.Instances {
+		if len(i.InstanceTags) == 0 {
+			log.Printf("E! EC2 service discovery instance config without sd_instance_tags: %v", i)
+			return false
+		}
+	}
+
+	if sd.Config.Region == "" || sd.Config.ResultFile == "" {
+		log.Printf("E! EC2 service discovery region or result file is not defined.\n")
+		return false
+	}
+
+	_, err := time.ParseDuration(sd.Config.Frequency)
+	if err != nil {
+		log.Printf("E! Invalid EC2 service discovery frequency: %v.\n", sd.Config.Frequency)
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,184 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package ec2servicediscovery
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/aws/amazon-cloudwatch-agent/internal/ecsservicediscovery"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/ec2/ec2iface"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v2"
+)
+
+type mockEC2 struct {
+	ec2iface.EC2API
+	inputs    []*ec2.DescribeInstancesInput
+	instances [][]*ec2.Instance
+	err       error
+}
+
+func (m *mockEC2) DescribeInstancesPages(input *ec2.DescribeInstancesInput, fn func(*ec2.DescribeInstancesOutput, bool) bool) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.inputs = append(m.inputs, input)
+	for i, page := range m.instances {
+		if !fn(&ec2.DescribeInstancesOutput{Reservations: []*ec2.Reservation{{Instances: page}}}, i == len(m.instances)-1) {
+			break
+		}
+	}
+	return nil
+}
+
+func instance(id, ip string, tags map[string]string) *ec2.Instance {
+	i := &ec2.Instance{
+		InstanceId:       aws.String(id),
+		InstanceType:     aws.String("m5.large"),
+		PrivateIpAddress: aws.String(ip),
+		VpcId:            aws.String("vpc-1"),
+		SubnetId:         aws.String("subnet-1"),
+		Placement:        &ec2.Placement{AvailabilityZone: aws.String("us-east-1a")},
+	}
+	for k, v := range tags {
+		i.Tags = append(i.Tags, &ec2.Tag{Key: aws.String(k), Value: aws.String(v)})
+	}
+	return i
+}
+
+func newServiceDiscovery(t *testing.T, svc *mockEC2) (*ServiceDiscovery, string) {
+	dir, err := ioutil.TempDir("", "ec2sd")
+	assert.NoError(t, err)
+	sd := &ServiceDiscovery{
+		Config: &ServiceDiscoveryConfig{
+			Frequency:  "1m",
+			ResultFile: filepath.Join(dir, "ec2_sd.yaml"),
+			Region:     "us-east-1",
+			Instances: []*InstanceConfig{
+				{JobName: "node", MetricsPorts: "9100;9101", InstanceTags: map[string]string{"Role": "api"}},
+				{MetricsPath: "/stats", MetricsPorts: "8080", InstanceTags: map[string]string{"Env": "prod*"}},
+			},
+		},
+		svcEc2:  svc,
+		vpcIdFn: func() (string, error) { return "vpc-1", nil },
+	}
+	sd.initConfig()
+	return sd, dir
+}
+
+func TestDiscover(t *testing.T) {
+	svc := &mockEC2{instances: [][]*ec2.Instance{
+		{instance("i-1", "10.0.0.1", map[string]string{"Role": "api", "Name": "api-1", "aws:autoscaling:groupName": "api"})},
+		{instance("i-2", "10.0.0.2", map[string]string{"Role": "api", "job": "other"}), instance("i-3", "", nil)},
+	}}
+	sd, dir := newServiceDiscovery(t, svc)
+	defer os.RemoveAll(dir)
+	sd.resolveVpcId()
+
+	targets, err := sd.discover()
+	assert.NoError(t, err)
+	sort.Slice(targets, func(i, j int) bool {
+		if targets[i].Targets[0] != targets[j].Targets[0] {
+			return targets[i].Targets[0] < targets[j].Targets[0]
+		}
+		return targets[i].Labels["__metrics_path__"] < targets[j].Labels["__metrics_path__"]
+	})
+	// the mock returns the same instances for both configs, the instance without private ip has no target
+	assert.Len(t, targets, 6)
+	assert.Equal(t, &ecsservicediscovery.PrometheusTarget{
+		Targets: []string{"10.0.0.1:8080"},
+		Labels: map[string]string{
+			"__metrics_path__": "/stats",
+			"AvailabilityZone": "us-east-1a",
+			"InstanceId":       "i-1",
+			"InstanceType":     "m5.large",
+			"Name":             "api-1",
+			"Role":             "api",
+			"SubnetId":         "subnet-1",
+			"VpcId":            "vpc-1",
+		},
+	}, targets[0])
+	assert.Equal(t, []string{"10.0.0.1:9100"}, targets[1].Targets)
+	assert.Equal(t, "node", targets[1].Labels["job"])
+	assert.Equal(t, "/metrics", targets[1].Labels["__metrics_path__"])
+	assert.Equal(t, []string{"10.0.0.2:9101"}, targets[5].Targets)
+	// the job of the config overrides the tag
+	assert.Equal(t, "node", targets[5].Labels["job"])
+
+	assert.Len(t, svc.inputs, 2)
+	assert.Equal(t, []*ec2.Filter{
+		{Name: aws.String("instance-state-name"), Values: aws.StringSlice([]string{"running"})},
+		{Name: aws.String("vpc-id"), Values: aws.StringSlice([]string{"vpc-1"})},
+		{Name: aws.String("tag:Role"), Values: aws.StringSlice([]string{"api"})},
+	}, svc.inputs[0].Filters)
+}
+
+func TestWork(t *testing.T) {
+	svc := &mockEC2{instances: [][]*ec2.Instance{{instance("i-1", "10.0.0.1", map[string]string{"Role": "api"})}}}
+	sd, dir := newServiceDiscovery(t, svc)
+	defer os.RemoveAll(dir)
+	sd.Config.Instances = sd.Config.Instances[:1]
+
+	sd.work()
+	b, err := ioutil.ReadFile(sd.Config.ResultFile)
+	assert.NoError(t, err)
+	var written []*ecsservicediscovery.PrometheusTarget
+	assert.NoError(t, yaml.Unmarshal(b, &written))
+	assert.Len(t, written, 2)
+	assert.Len(t, sd.Targets(), 2)
+
+	// the targets of the last discovery are kept on errors
+	svc.err = errors.New("throttled")
+	sd.work()
+	assert.Len(t, sd.Targets(), 2)
+	b2, err := ioutil.ReadFile(sd.Config.ResultFile)
+	assert.NoError(t, err)
+	assert.Equal(t, b, b2)
+}
+
+func TestResolveVpcId(t *testing.T) {
+	sd, dir := newServiceDiscovery(t, &mockEC2{})
+	defer os.RemoveAll(dir)
+	sd.Config.VpcId = "vpc-2"
+	sd.resolveVpcId()
+	assert.Equal(t, "vpc-2", sd.Config.VpcId)
+
+	sd.Config.VpcId = ""
+	sd.vpcIdFn = func() (string, error) { return "", errors.New("no metadata") }
+	sd.resolveVpcId()
+	assert.Equal(t, "", sd.Config.VpcId)
+	// without VPC, the instances of every VPC are discovered
+	assert.Len(t, sd.filters(sd.Config.Instances[0]), 2)
+}
+
+func TestValidateConfig(t *testing.T) {
+	sd, dir := newServiceDiscovery(t, &mockEC2{})
+	defer os.RemoveAll(dir)
+	assert.True(t, sd.validateConfig())
+
+	sd.Config.Frequency = "1"
+	assert.False(t, sd.validateConfig())
+	sd.Config.Frequency = "1m"
+
+	sd.Config.Instances[1].InstanceTags = nil
+	assert.False(t, sd.validateConfig())
+
+	sd.Config.Instances = nil
+	assert.False(t, sd.validateConfig())
+
+	assert.False(t, (&ServiceDiscovery{}).validateConfig())
+}
+
+func TestInstanceConfigInit(t *testing.T) {
+	i := &InstanceConfig{MetricsPorts: "9100; 9101;abc;0"}
+	i.init()
+	assert.Equal(t, []int{9100, 9101}, i.metricsPortList)
+}
@@ -104,6 +104,7 @@ func (s *ServiceNameForTasksConfig) init() {
 type ServiceDiscoveryConfig struct {
 	Frequency            string                       `toml:"sd_frequency"`
 	ResultFile           string                       `toml:"sd_result_file"`
+	HttpSdListenAddress  string                       `toml:"sd_http_sd_listen_address"`
 	TargetCluster        string                       `toml:"sd_target_cluster"`
 	TargetClusterRegion  string                       `toml:"sd_cluster_region"`
 	ServiceNamesForTasks []*ServiceNameForTasksConfig `toml:"service_name_list_for_tasks"`
@@ -0,0 +1,85 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package ecsservicediscovery
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net"
+	"net/http"
+	"time"
+)
+
+const (
+	httpSdPath            = "/targets"
+	httpSdShutdownTimeout = 5 * time.Second
+)
+
+// HttpSdServer serves the discovered targets in the Prometheus http_sd format, so external Prometheus servers
+// can reuse the discovery of the agent with:
+//
+//	http_sd_configs:
+//	  - url: http://<sd_http_sd_listen_address>/targets
+type HttpSdServer struct {
+	// the discovery named in the logs
+	name      string
+	targetsFn func() []*PrometheusTarget
+	server    *http.Server
+}
+
+func NewHttpSdServer(address string, targetsFn func() []*PrometheusTarget) *HttpSdServer {
+	s := &HttpSdServer{name: "ECS SD", targetsFn: targetsFn}
+	mux := http.NewServeMux()
+	mux.HandleFunc(httpSdPath, s.handleTargets)
+	s.server = &http.Server{Addr: address, Handler: mux}
+	return s
+}
+
+func (s *HttpSdServer) handleTargets(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	targets := s.targetsFn()
+	if targets == nil {
+		// http_sd requires a list, even before the first discovery
+		targets = []*PrometheusTarget{}
+	}
+	body, err := json.Marshal(targets)
+	if err != nil {
+		log.Printf("E! %s http_sd endpoint fails to marshal the targets: %v\n", s.name, err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(body)
+}
+
+// WithName names the discovery serving its targets in the logs, "ECS SD" by default.
+func (s *HttpSdServer) WithName(name string) *HttpSdServer {
+	s.name = name
+	return s
+}
+
+// Start listens on the address and serves the requests in the background.
+func (s *HttpSdServer) Start() error {
+	listener, err := net.Listen("tcp", s.server.Addr)
+	if err != nil {
+		return err
+	}
+	log.Printf("I! %s http_sd endpoint is listening on http://%v%v\n", s.name, listener.Addr(), httpSdPath)
+	go func() {
+		if err := s.server.Serve(listener); err != nil && err != http.ErrServerClosed {
+			log.Printf("E! %s http_sd endpoint stopped: %v\n", s.name, err)
+		}
+	}()
+	return nil
+}
+
+func (s *HttpSdServer) Stop() {
+	ctx, cancel := context.WithTimeout(context.Background(), httpSdShutdownTimeout)
+	defer cancel()
+	s.server.Shutdown(ctx)
+}
@@ -0,0 +1,78 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package ecsservicediscovery
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_HttpSdServer_NoTargets(t *testing.T) {
+	s := NewHttpSdServer("127.0.0.1:0", func() []*PrometheusTarget { return nil })
+
+	w := httptest.NewRecorder()
+	s.handleTargets(w, httptest.NewRequest(http.MethodGet, httpSdPath, nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+	assert.Equal(t, "[]", w.Body.String())
+}
+
+func Test_HttpSdServer_MethodNotAllowed(t *testing.T) {
+	s := NewHttpSdServer("127.0.0.1:0", func() []*PrometheusTarget { return nil })
+
+	w := httptest.NewRecorder()
+	s.handleTargets(w, httptest.NewRequest(http.MethodPost, httpSdPath, nil))
+
+	assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+}
+
+func Test_HttpSdServer_ExportedTargets(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ecssd")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	config := &ServiceDiscoveryConfig{ResultFile: filepath.Join(dir, "result.yaml")}
+	p := NewTargetsExportProcessor(config, &ProcessorStats{})
+	s := NewHttpSdServer("127.0.0.1:0", p.Targets)
+
+	_, err = p.Process("test", nil)
+	assert.Nil(t, err)
+	w := httptest.NewRecorder()
+	s.handleTargets(w, httptest.NewRequest(http.MethodGet, httpSdPath, nil))
+	assert.Equal(t, "[]", w.Body.String())
+
+	p.targetsLock.Lock()
+	p.latestTargets = []*PrometheusTarget{
+		{
+			Targets: []string{"10.0.0.129:9404"},
+			Labels:  map[string]string{"job": "java-tomcat", "__metrics_path__": "/metrics"},
+		},
+	}
+	p.targetsLock.Unlock()
+
+	w = httptest.NewRecorder()
+	s.handleTargets(w, httptest.NewRequest(http.MethodGet, httpSdPath, nil))
+	var targets []PrometheusTarget
+	assert.Nil(t, json.Unmarshal(w.Body.Bytes(), &targets))
+	assert.Equal(t, 1, len(targets))
+	assert.Equal(t, []string{"10.0.0.129:9404"}, targets[0].Targets)
+	assert.Equal(t, "java-tomcat", targets[0].Labels["job"])
+}
+
+func Test_HttpSdServer_StartStop(t *testing.T) {
+	s := NewHttpSdServer("127.0.0.1:0", func() []*PrometheusTarget { return nil })
+	assert.Nil(t, s.Start())
+	s.Stop()
+
+	s = NewHttpSdServer("invalid-address", func() []*PrometheusTarget { return nil })
+	assert.NotNil(t, s.Start())
+}
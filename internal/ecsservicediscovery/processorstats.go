@@ -16,6 +16,8 @@ const (
 	AWSCLIListServices               = "AWSCLI_ListServices"
 	AWSCLIListTasks                  = "AWSCLI_ListTasks"
 	AWSCLIDescribeTasks              = "AWSCLI_DescribeTasks"
+	CacheGetTask                     = "Cache_Get_Task"
+	CacheSizeTask                    = "Cache_Size_Task"
 	LRUCacheGetEC2MetaData           = "LRUCache_Get_EC2MetaData"
 	LRUCacheGetTaskDefinition        = "LRUCache_Get_TaskDefinition"
 	LRUCacheSizeContainerInstance    = "LRUCache_Size_ContainerInstance"
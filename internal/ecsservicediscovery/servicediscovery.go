@@ -5,15 +5,23 @@ package ecsservicediscovery
 
 import (
 	"log"
+	"math/rand"
 	"sync"
 	"time"
 
 	configaws "github.com/aws/amazon-cloudwatch-agent/cfg/aws"
+	"github.com/aws/amazon-cloudwatch-agent/internal/sidecar"
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/ec2"
 	"github.com/aws/aws-sdk-go/service/ecs"
 )
 
+const (
+	// the part of sd_frequency the refreshes are spread by, so the agents of a cluster started together don't call
+	// the ECS APIs at the same time
+	frequencyJitter = 0.1
+)
+
 type ServiceDiscovery struct {
 	Config *ServiceDiscoveryConfig
 
@@ -22,6 +30,7 @@ type ServiceDiscovery struct {
 
 	stats             ProcessorStats
 	clusterProcessors []Processor
+	targetsExporter   *TargetsExportProcessor
 }
 
 func (sd *ServiceDiscovery) init() {
@@ -43,7 +52,8 @@ func (sd *ServiceDiscovery) initClusterProcessorPipeline() {
 	sd.clusterProcessors = append(sd.clusterProcessors, NewTaskDefinitionDiscoveryProcessor(sd.Config.TaskDefinitions))
 	sd.clusterProcessors = append(sd.clusterProcessors, NewTaskFilterProcessor())
 	sd.clusterProcessors = append(sd.clusterProcessors, NewContainerInstanceProcessor(sd.svcEcs, sd.svcEc2, &sd.stats))
-	sd.clusterProcessors = append(sd.clusterProcessors, NewTargetsExportProcessor(sd.Config, &sd.stats))
+	sd.targetsExporter = NewTargetsExportProcessor(sd.Config, &sd.stats)
+	sd.clusterProcessors = append(sd.clusterProcessors, sd.targetsExporter)
 }
 
 func StartECSServiceDiscovery(sd *ServiceDiscovery, shutDownChan chan interface{}, wg *sync.WaitGroup) {
@@ -55,18 +65,40 @@ func StartECSServiceDiscovery(sd *ServiceDiscovery, shutDownChan chan interface{
 
 	frequency, _ := time.ParseDuration(sd.Config.Frequency)
 	sd.init()
-	t := time.NewTicker(frequency)
+	if sd.Config.HttpSdListenAddress != "" {
+		httpSdServer := NewHttpSdServer(sd.Config.HttpSdListenAddress, sd.targetsExporter.Targets)
+		if err := httpSdServer.Start(); err != nil {
+			log.Printf("E! ECS SD fails to start the http_sd endpoint on %v: %v\n", sd.Config.HttpSdListenAddress, err)
+		} else {
+			defer httpSdServer.Stop()
+		}
+	}
+	t := time.NewTimer(jitter(frequency))
 	defer t.Stop()
 	for {
 		select {
 		case <-shutDownChan:
 			return
 		case <-t.C:
+			t.Reset(jitter(frequency))
+			if sidecar.Draining() {
+				// the targets are not refreshed while the sidecar drains its logs before stopping
+				continue
+			}
 			sd.work()
 		}
 	}
 }
 
+// jitter returns the frequency moved by a random duration up to frequencyJitter of it, earlier or later.
+func jitter(frequency time.Duration) time.Duration {
+	spread := int64(float64(frequency) * frequencyJitter)
+	if spread <= 0 {
+		return frequency
+	}
+	return frequency + time.Duration(rand.Int63n(2*spread+1)-spread)
+}
+
 func (sd *ServiceDiscovery) work() {
 	sd.stats.ResetStats()
 	var err error
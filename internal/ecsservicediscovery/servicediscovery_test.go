@@ -6,6 +6,7 @@ package ecsservicediscovery
 import (
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -70,3 +71,11 @@ func Test_StartECSServiceDiscovery_BadClusterConfig(t *testing.T) {
 	StartECSServiceDiscovery(p, nil, &wg)
 	assert.Equal(t, 0, len(p.clusterProcessors))
 }
+
+func Test_jitter(t *testing.T) {
+	for i := 0; i < 100; i++ {
+		d := jitter(time.Minute)
+		assert.True(t, d >= 54*time.Second && d <= 66*time.Second)
+	}
+	assert.Equal(t, time.Duration(5), jitter(5))
+}
@@ -8,6 +8,7 @@ import (
 	"io/ioutil"
 	"os"
 	"regexp"
+	"sync"
 
 	"gopkg.in/yaml.v2"
 )
@@ -19,8 +20,8 @@ const (
 )
 
 type PrometheusTarget struct {
-	Targets []string          `yaml:"targets"`
-	Labels  map[string]string `yaml:"labels"`
+	Targets []string          `yaml:"targets" json:"targets"`
+	Labels  map[string]string `yaml:"labels" json:"labels"`
 }
 
 type TargetsExportProcessor struct {
@@ -29,6 +30,10 @@ type TargetsExportProcessor struct {
 
 	dockerLabelRegex  *regexp.Regexp
 	tmpResultFilePath string
+
+	// the targets of the last successful discovery, served by the http_sd endpoint
+	targetsLock   sync.RWMutex
+	latestTargets []*PrometheusTarget
 }
 
 func NewTargetsExportProcessor(sdConfig *ServiceDiscoveryConfig, s *ProcessorStats) *TargetsExportProcessor {
@@ -60,6 +65,10 @@ func (p *TargetsExportProcessor) Process(cluster string, taskList []*DecoratedTa
 	}
 	p.stats.AddStatsCount(ExporterDiscoveredTargetCount, len(targetsArr))
 
+	p.targetsLock.Lock()
+	p.latestTargets = targetsArr
+	p.targetsLock.Unlock()
+
 	err = ioutil.WriteFile(p.tmpResultFilePath, m, 0644)
 	if err != nil {
 		return nil, newServiceDiscoveryError(fmt.Sprintf("Fail to write Prometheus targets into file: %v", p.tmpResultFilePath), &err)
@@ -73,6 +82,13 @@ func (p *TargetsExportProcessor) Process(cluster string, taskList []*DecoratedTa
 	return nil, nil
 }
 
+// Targets returns the Prometheus targets exported by the last successful discovery.
+func (p *TargetsExportProcessor) Targets() []*PrometheusTarget {
+	p.targetsLock.RLock()
+	defer p.targetsLock.RUnlock()
+	return p.latestTargets
+}
+
 func (p *TargetsExportProcessor) ProcessorName() string {
 	return "TargetsExportProcessor"
 }
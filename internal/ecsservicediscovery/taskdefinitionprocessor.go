@@ -16,7 +16,9 @@ const (
 	taskDefCacheSize = 2000
 )
 
-// Decorate the tasks with the ECS task definition
+// Decorate the tasks with the ECS task definition. The definitions are cached by their arn, which ends with their
+// revision, since a revision of a task definition is immutable, so DescribeTaskDefinition is only called for the
+// revisions not seen yet.
 type TaskDefinitionProcessor struct {
 	svcEcs *ecs.ECS
 	stats  *ProcessorStats
@@ -6,23 +6,40 @@ package ecsservicediscovery
 import (
 	"log"
 
+	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/ecs"
 )
 
-// Get all running tasks for the target cluster
+const (
+	// the maximum tasks of a DescribeTasks call
+	describeTasksBatchSize = 100
+)
+
+// Get all running tasks for the target cluster. The running tasks described by a previous discovery are kept while
+// they are listed, so only the new tasks of the cluster are described and a large cluster isn't described again at
+// each sd_frequency.
 type TaskProcessor struct {
 	svcEcs *ecs.ECS
 	stats  *ProcessorStats
+
+	// the running tasks of the last discovery by task arn
+	taskCache map[string]*ecs.Task
 }
 
-func NewTaskProcessor(ecs *ecs.ECS, s *ProcessorStats) *TaskProcessor {
+func NewTaskProcessor(svcEcs *ecs.ECS, s *ProcessorStats) *TaskProcessor {
 	return &TaskProcessor{
-		svcEcs: ecs,
-		stats:  s,
+		svcEcs:    svcEcs,
+		stats:     s,
+		taskCache: make(map[string]*ecs.Task),
 	}
 }
 
 func (p *TaskProcessor) Process(cluster string, taskList []*DecoratedTask) ([]*DecoratedTask, error) {
+	defer func() {
+		p.stats.AddStatsCount(CacheSizeTask, len(p.taskCache))
+	}()
+
+	var arns []string
 	req := &ecs.ListTasksInput{Cluster: &cluster}
 	for {
 		listTaskResp, listTaskErr := p.svcEcs.ListTasks(req)
@@ -30,27 +47,74 @@ func (p *TaskProcessor) Process(cluster string, taskList []*DecoratedTask) ([]*D
 		if listTaskErr != nil {
 			return taskList, newServiceDiscoveryError("Failed to list task ARNs for "+cluster, &listTaskErr)
 		}
+		arns = append(arns, aws.StringValueSlice(listTaskResp.TaskArns)...)
 
-		descTaskResp, descTaskErr := p.svcEcs.DescribeTasks(&ecs.DescribeTasksInput{Cluster: &cluster, Tasks: listTaskResp.TaskArns})
+		if listTaskResp.NextToken == nil {
+			break
+		}
+		req.NextToken = listTaskResp.NextToken
+	}
+
+	uncached := p.uncachedTasks(arns)
+	var described []*ecs.Task
+	for i := 0; i < len(uncached); i += describeTasksBatchSize {
+		end := i + describeTasksBatchSize
+		if end > len(uncached) {
+			end = len(uncached)
+		}
+		descTaskResp, descTaskErr := p.svcEcs.DescribeTasks(&ecs.DescribeTasksInput{Cluster: &cluster, Tasks: aws.StringSlice(uncached[i:end])})
 		p.stats.AddStats(AWSCLIDescribeTasks)
 		if descTaskErr != nil {
 			return taskList, newServiceDiscoveryError("Failed to describe ECS Tasks for "+cluster, &descTaskErr)
 		}
 
 		for _, f := range descTaskResp.Failures {
-			log.Printf("E! DescribeTask Failure for %v, Reason: %v, Detail: %v \n", *f.Arn, *f.Reason, *f.Detail)
+			log.Printf("E! DescribeTask Failure for %v, Reason: %v, Detail: %v \n", aws.StringValue(f.Arn), aws.StringValue(f.Reason), aws.StringValue(f.Detail))
 		}
+		described = append(described, descTaskResp.Tasks...)
+	}
+
+	return append(taskList, p.refreshTasks(arns, described)...), nil
+}
 
-		for i := 0; i < len(descTaskResp.Tasks); i++ {
-			taskList = append(taskList, &DecoratedTask{Task: descTaskResp.Tasks[i], TaskDefinition: nil, EC2Info: nil})
+// uncachedTasks returns the listed tasks which are not cached, they are described.
+func (p *TaskProcessor) uncachedTasks(arns []string) []string {
+	var uncached []string
+	for _, arn := range arns {
+		if _, ok := p.taskCache[arn]; ok {
+			p.stats.AddStats(CacheGetTask)
+			continue
 		}
+		uncached = append(uncached, arn)
+	}
+	return uncached
+}
 
-		if listTaskResp.NextToken == nil {
-			break
+// refreshTasks returns the listed tasks in the order of the list, from the cache or the described ones. The cache is
+// replaced by the listed tasks, so the stopped tasks are dropped, and only the running tasks are cached since the
+// network of a pending task is not set yet.
+func (p *TaskProcessor) refreshTasks(arns []string, described []*ecs.Task) []*DecoratedTask {
+	byArn := make(map[string]*ecs.Task, len(described))
+	for _, t := range described {
+		byArn[aws.StringValue(t.TaskArn)] = t
+	}
+
+	cache := make(map[string]*ecs.Task, len(arns))
+	var taskList []*DecoratedTask
+	for _, arn := range arns {
+		t, ok := p.taskCache[arn]
+		if !ok {
+			if t, ok = byArn[arn]; !ok {
+				continue
+			}
 		}
-		req.NextToken = listTaskResp.NextToken
+		if aws.StringValue(t.LastStatus) == ecs.DesiredStatusRunning {
+			cache[arn] = t
+		}
+		taskList = append(taskList, &DecoratedTask{Task: t, TaskDefinition: nil, EC2Info: nil})
 	}
-	return taskList, nil
+	p.taskCache = cache
+	return taskList
 }
 
 func (p *TaskProcessor) ProcessorName() string {
@@ -0,0 +1,54 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package ecsservicediscovery
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ecs"
+	"github.com/stretchr/testify/assert"
+)
+
+func buildTestingECSTask(arn string, status string) *ecs.Task {
+	return &ecs.Task{TaskArn: aws.String(arn), LastStatus: aws.String(status)}
+}
+
+func Test_TaskProcessor_RefreshTasks(t *testing.T) {
+	p := NewTaskProcessor(nil, &ProcessorStats{})
+
+	// the first discovery describes every task
+	arns := []string{"arn:task/1", "arn:task/2", "arn:task/3"}
+	assert.Equal(t, arns, p.uncachedTasks(arns))
+	tasks := p.refreshTasks(arns, []*ecs.Task{
+		buildTestingECSTask("arn:task/1", "RUNNING"),
+		buildTestingECSTask("arn:task/2", "PENDING"),
+		buildTestingECSTask("arn:task/3", "RUNNING"),
+	})
+	assert.Equal(t, 3, len(tasks))
+	assert.Equal(t, 2, len(p.taskCache))
+
+	// the pending task and the new one are described, the stopped one is dropped
+	arns = []string{"arn:task/2", "arn:task/3", "arn:task/4"}
+	assert.Equal(t, []string{"arn:task/2", "arn:task/4"}, p.uncachedTasks(arns))
+	assert.Equal(t, 1, p.stats.GetStats(CacheGetTask))
+	tasks = p.refreshTasks(arns, []*ecs.Task{
+		buildTestingECSTask("arn:task/4", "RUNNING"),
+		buildTestingECSTask("arn:task/2", "RUNNING"),
+	})
+	var actual []string
+	for _, task := range tasks {
+		actual = append(actual, aws.StringValue(task.Task.TaskArn))
+	}
+	assert.Equal(t, arns, actual)
+	assert.Equal(t, 3, len(p.taskCache))
+	_, ok := p.taskCache["arn:task/1"]
+	assert.False(t, ok)
+
+	// a task which failed to be described is skipped
+	arns = []string{"arn:task/2", "arn:task/5"}
+	tasks = p.refreshTasks(arns, nil)
+	assert.Equal(t, 1, len(tasks))
+	assert.Equal(t, "arn:task/2", aws.StringValue(tasks[0].Task.TaskArn))
+}
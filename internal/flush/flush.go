@@ -0,0 +1,42 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+// Package flush broadcasts the requests of the operators to flush the pipelines of the agent now, e.g. on SIGUSR2,
+// so the batches of the outputs are published without waiting for their flush interval or a restart.
+package flush
+
+import (
+	"sync"
+)
+
+type notifier struct {
+	mu sync.Mutex
+	// closed by the next request, then replaced
+	requested chan struct{}
+}
+
+var defaultNotifier = &notifier{requested: make(chan struct{})}
+
+// Request asks every pipeline to publish its batches now.
+func Request() {
+	defaultNotifier.request()
+}
+
+// Requested returns the channel closed by the next request. A pipeline calls it again once the channel is closed
+// to wait for the request after.
+func Requested() <-chan struct{} {
+	return defaultNotifier.next()
+}
+
+func (n *notifier) request() {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	close(n.requested)
+	n.requested = make(chan struct{})
+}
+
+func (n *notifier) next() <-chan struct{} {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return n.requested
+}
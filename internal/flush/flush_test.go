@@ -0,0 +1,34 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package flush
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func isClosed(ch <-chan struct{}) bool {
+	select {
+	case <-ch:
+		return true
+	default:
+		return false
+	}
+}
+
+func TestRequest(t *testing.T) {
+	first := Requested()
+	assert.False(t, isClosed(first))
+
+	Request()
+	assert.True(t, isClosed(first))
+
+	// the pipelines wait for the next request once flushed
+	second := Requested()
+	assert.False(t, isClosed(second))
+	Request()
+	assert.True(t, isClosed(second))
+	assert.False(t, isClosed(Requested()))
+}
@@ -0,0 +1,183 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+// Package gatherstats measures the collections of the inputs of the agent, the collectors, and periodically logs the
+// slowest ones, so the plugin which takes most of its collection interval is found without a profile of the agent.
+// The gathers longer than the interval of their input, the timeouts, and the intervals skipped meanwhile are counted
+// in the "gather" stats reported by the internal input, next to the gather_time_ns of each input.
+package gatherstats
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/config"
+	"github.com/influxdata/telegraf/selfstat"
+)
+
+const (
+	defaultReportInterval = 30 * time.Minute
+	// the collectors listed in a report
+	reportSize = 5
+)
+
+// collectorStats are the stats of an input, the window ones are reset by each report.
+type collectorStats struct {
+	name     string
+	interval time.Duration
+
+	timeouts         selfstat.Stat
+	skippedIntervals selfstat.Stat
+
+	gathers        int64
+	total          time.Duration
+	max            time.Duration
+	windowTimeouts int64
+	windowSkipped  int64
+}
+
+// Reporter records the gathers of the collectors and logs the slowest ones.
+type Reporter struct {
+	sync.Mutex
+	collectors []*collectorStats
+	interval   time.Duration
+	logf       func(format string, v ...interface{})
+}
+
+// collector times the gathers of an input, the other methods of the input are the embedded ones.
+type collector struct {
+	telegraf.Input
+	stats    *collectorStats
+	reporter *Reporter
+}
+
+func (c *collector) Init() error {
+	if i, ok := c.Input.(telegraf.Initializer); ok {
+		return i.Init()
+	}
+	return nil
+}
+
+func (c *collector) Gather(acc telegraf.Accumulator) error {
+	start := time.Now()
+	err := c.Input.Gather(acc)
+	c.reporter.record(c.stats, time.Since(start))
+	return err
+}
+
+// Wrap times the gathers of the inputs of the config, it must be called before the agent is created. The service
+// inputs are left as is since they collect in the background and their gathers only flush what they received.
+func Wrap(c *config.Config) *Reporter {
+	r := &Reporter{interval: defaultReportInterval, logf: log.Printf}
+	for _, input := range c.Inputs {
+		if _, ok := input.Input.(telegraf.ServiceInput); ok {
+			continue
+		}
+		interval := input.Config.Interval
+		if interval == 0 {
+			interval = c.Agent.Interval.Duration
+		}
+		tags := map[string]string{"input": input.Config.Name}
+		if input.Config.Alias != "" {
+			tags["alias"] = input.Config.Alias
+		}
+		stats := &collectorStats{
+			name:             input.LogName(),
+			interval:         interval,
+			timeouts:         selfstat.Register("gather", "timeouts", tags),
+			skippedIntervals: selfstat.Register("gather", "skipped_intervals", tags),
+		}
+		r.collectors = append(r.collectors, stats)
+		input.Input = &collector{Input: input.Input, stats: stats, reporter: r}
+	}
+	return r
+}
+
+// record records a gather of the collector, since the ticks of the agent elapsed during the gather are dropped, a
+// gather above the interval skips the collection of each interval elapsed.
+func (r *Reporter) record(s *collectorStats, d time.Duration) {
+	r.Lock()
+	defer r.Unlock()
+	s.gathers++
+	s.total += d
+	if d > s.max {
+		s.max = d
+	}
+	if s.interval > 0 && d > s.interval {
+		skipped := int64(d / s.interval)
+		s.windowTimeouts++
+		s.windowSkipped += skipped
+		s.timeouts.Incr(1)
+		s.skippedIntervals.Incr(skipped)
+	}
+}
+
+// Run logs the report of the slowest collectors at each interval until the context is done.
+func (r *Reporter) Run(ctx context.Context) {
+	if len(r.collectors) == 0 {
+		return
+	}
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			r.report()
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// report logs the collectors which used the largest part of their interval since the last report, by their slowest
+// gather, then resets the window.
+func (r *Reporter) report() {
+	r.Lock()
+	defer r.Unlock()
+	var ranked []*collectorStats
+	for _, s := range r.collectors {
+		if s.gathers > 0 {
+			ranked = append(ranked, s)
+		}
+	}
+	if len(ranked) == 0 {
+		return
+	}
+	sort.SliceStable(ranked, func(i, j int) bool {
+		return budget(ranked[i]) > budget(ranked[j])
+	})
+	if len(ranked) > reportSize {
+		ranked = ranked[:reportSize]
+	}
+	lines := make([]string, len(ranked))
+	for i, s := range ranked {
+		lines[i] = fmt.Sprintf("%d. [%s] max %v (%.0f%% of its %v interval), mean %v, %d gathers, %d timeouts, %d skipped intervals",
+			i+1, s.name, s.max.Round(time.Millisecond), 100*budget(s), s.interval, (s.total / time.Duration(s.gathers)).Round(time.Millisecond),
+			s.gathers, s.windowTimeouts, s.windowSkipped)
+	}
+	level := "I!"
+	for _, s := range ranked {
+		if s.windowTimeouts > 0 {
+			level = "W!"
+		}
+	}
+	r.logf("%s [gatherstats] The slowest collectors in the last %v: %s", level, r.interval, strings.Join(lines, "; "))
+	for _, s := range r.collectors {
+		s.gathers, s.total, s.max, s.windowTimeouts, s.windowSkipped = 0, 0, 0, 0, 0
+	}
+}
+
+// budget is the part of the interval used by the slowest gather, the collectors without interval are ranked by their
+// slowest gather in seconds.
+func budget(s *collectorStats) float64 {
+	if s.interval <= 0 {
+		return s.max.Seconds()
+	}
+	return float64(s.max) / float64(s.interval)
+}
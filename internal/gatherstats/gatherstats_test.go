@@ -0,0 +1,128 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package gatherstats
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/config"
+	"github.com/influxdata/telegraf/models"
+	"github.com/influxdata/telegraf/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testInput struct {
+	initialized bool
+	gathers     int
+}
+
+func (i *testInput) SampleConfig() string { return "" }
+func (i *testInput) Description() string  { return "" }
+func (i *testInput) Init() error {
+	i.initialized = true
+	return nil
+}
+func (i *testInput) Gather(acc telegraf.Accumulator) error {
+	i.gathers++
+	acc.AddFields("test", map[string]interface{}{"value": i.gathers}, nil)
+	return nil
+}
+
+type testServiceInput struct {
+	testInput
+}
+
+func (i *testServiceInput) Start(telegraf.Accumulator) error { return nil }
+func (i *testServiceInput) Stop()                            {}
+
+func newTestConfig(inputs ...*models.RunningInput) *config.Config {
+	c := config.NewConfig()
+	c.Agent.Interval.Duration = time.Minute
+	c.Inputs = inputs
+	return c
+}
+
+func TestWrap(t *testing.T) {
+	input, service := &testInput{}, &testServiceInput{}
+	c := newTestConfig(
+		models.NewRunningInput(input, &models.InputConfig{Name: "test", Interval: 10 * time.Second}),
+		models.NewRunningInput(service, &models.InputConfig{Name: "service"}),
+	)
+	r := Wrap(c)
+
+	require.Len(t, r.collectors, 1)
+	assert.Equal(t, 10*time.Second, r.collectors[0].interval)
+	assert.Equal(t, service, c.Inputs[1].Input, "the service inputs are not wrapped")
+
+	require.NoError(t, c.Inputs[0].Init())
+	assert.True(t, input.initialized)
+	acc := &testutil.Accumulator{}
+	require.NoError(t, c.Inputs[0].Input.Gather(acc))
+	assert.Equal(t, 1, input.gathers)
+	assert.Len(t, acc.Metrics, 1)
+	assert.Equal(t, int64(1), r.collectors[0].gathers)
+}
+
+func TestWrapAgentInterval(t *testing.T) {
+	r := Wrap(newTestConfig(models.NewRunningInput(&testInput{}, &models.InputConfig{Name: "test"})))
+	require.Len(t, r.collectors, 1)
+	assert.Equal(t, time.Minute, r.collectors[0].interval)
+}
+
+func TestRecordTimeouts(t *testing.T) {
+	r := Wrap(newTestConfig(models.NewRunningInput(&testInput{}, &models.InputConfig{Name: "timeouts", Interval: 10 * time.Second})))
+	s := r.collectors[0]
+	r.record(s, 2*time.Second)
+	r.record(s, 25*time.Second)
+	r.record(s, 11*time.Second)
+
+	assert.Equal(t, int64(3), s.gathers)
+	assert.Equal(t, 25*time.Second, s.max)
+	assert.Equal(t, int64(2), s.windowTimeouts)
+	assert.Equal(t, int64(3), s.windowSkipped)
+	assert.Equal(t, int64(2), s.timeouts.Get())
+	assert.Equal(t, int64(3), s.skippedIntervals.Get())
+}
+
+func TestReport(t *testing.T) {
+	var inputs []*models.RunningInput
+	for i := 0; i < 7; i++ {
+		inputs = append(inputs, models.NewRunningInput(&testInput{}, &models.InputConfig{Name: fmt.Sprintf("report%d", i), Interval: 10 * time.Second}))
+	}
+	// collected every minute, 20s is a third of its interval
+	inputs = append(inputs, models.NewRunningInput(&testInput{}, &models.InputConfig{Name: "minute", Interval: time.Minute}))
+	r := Wrap(newTestConfig(inputs...))
+	var logs []string
+	r.logf = func(format string, v ...interface{}) {
+		logs = append(logs, fmt.Sprintf(format, v...))
+	}
+
+	r.report()
+	assert.Empty(t, logs, "nothing is reported without gather")
+
+	for i, s := range r.collectors[:7] {
+		r.record(s, time.Duration(i+1)*100*time.Millisecond)
+	}
+	r.record(r.collectors[7], 20*time.Second)
+	r.record(r.collectors[6], 2*time.Second)
+	r.report()
+	require.Len(t, logs, 1)
+	assert.Equal(t, "I! [gatherstats] The slowest collectors in the last 30m0s: "+
+		"1. [inputs.minute] max 20s (33% of its 1m0s interval), mean 20s, 1 gathers, 0 timeouts, 0 skipped intervals; "+
+		"2. [inputs.report6] max 2s (20% of its 10s interval), mean 1.35s, 2 gathers, 0 timeouts, 0 skipped intervals; "+
+		"3. [inputs.report5] max 600ms (6% of its 10s interval), mean 600ms, 1 gathers, 0 timeouts, 0 skipped intervals; "+
+		"4. [inputs.report4] max 500ms (5% of its 10s interval), mean 500ms, 1 gathers, 0 timeouts, 0 skipped intervals; "+
+		"5. [inputs.report3] max 400ms (4% of its 10s interval), mean 400ms, 1 gathers, 0 timeouts, 0 skipped intervals", logs[0])
+
+	// the window is reset by the report
+	r.record(r.collectors[0], 35*time.Second)
+	r.report()
+	require.Len(t, logs, 2)
+	assert.Equal(t, "W! [gatherstats] The slowest collectors in the last 30m0s: "+
+		"1. [inputs.report0] max 35s (350% of its 10s interval), mean 35s, 1 gathers, 1 timeouts, 3 skipped intervals", logs[1])
+}
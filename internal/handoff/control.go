@@ -0,0 +1,91 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package handoff
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"time"
+)
+
+const (
+	requestHandoff = "handoff"
+	responseOK     = "ok"
+)
+
+// Serve accepts the handoff requests, of the updater, on the unix socket until the context is done. When start
+// returns no error the request is acknowledged and the caller hands the agent off.
+func Serve(ctx context.Context, path string, start func() error) error {
+	// the socket of the previous process is left behind
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	ln, err := net.Listen("unix", path)
+	if err != nil {
+		return err
+	}
+	if err := os.Chmod(path, 0600); err != nil {
+		ln.Close()
+		return err
+	}
+	go func() {
+		<-ctx.Done()
+		ln.Close()
+	}()
+	for {
+		c, err := ln.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+		serveRequest(c, start)
+	}
+}
+
+func serveRequest(c net.Conn, start func() error) {
+	defer c.Close()
+	c.SetDeadline(time.Now().Add(10 * time.Second))
+	line, err := bufio.NewReader(c).ReadString('\n')
+	if err != nil {
+		return
+	}
+	if strings.TrimSpace(line) != requestHandoff {
+		fmt.Fprintf(c, "unknown request %q\n", strings.TrimSpace(line))
+		return
+	}
+	if err := start(); err != nil {
+		fmt.Fprintf(c, "%v\n", err)
+		return
+	}
+	fmt.Fprintln(c, responseOK)
+}
+
+// Request asks the agent serving the unix socket to hand off to its new binary, it returns once the agent
+// acknowledged it.
+func Request(path string, timeout time.Duration) error {
+	c, err := net.DialTimeout("unix", path, timeout)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+	c.SetDeadline(time.Now().Add(timeout))
+	if _, err := fmt.Fprintln(c, requestHandoff); err != nil {
+		return err
+	}
+	response, err := bufio.NewReader(c).ReadString('\n')
+	if err != nil {
+		return err
+	}
+	if response = strings.TrimSpace(response); response != responseOK {
+		return errors.New(response)
+	}
+	return nil
+}
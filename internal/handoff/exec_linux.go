@@ -0,0 +1,106 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+//go:build linux
+// +build linux
+
+package handoff
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+)
+
+// Supported is whether the platform can hand off the agent.
+const Supported = true
+
+// Exec replaces the process by the binary with the listeners, the connections kept and the state of the inputs. It
+// only returns when the exec failed, the handoff should then be aborted.
+func Exec(path string, args []string) error {
+	doc, files, err := reg.document()
+	if err != nil {
+		return err
+	}
+	b, err := json.Marshal(doc)
+	if err != nil {
+		return err
+	}
+	// the document is passed in memory, the memfd is inherited by the new process
+	fd, err := unix.MemfdCreate("cwagent-handoff", 0)
+	if err != nil {
+		return fmt.Errorf("unable to create the handoff document: %v", err)
+	}
+	memfd := os.NewFile(uintptr(fd), "handoff")
+	defer memfd.Close()
+	if _, err := memfd.Write(b); err != nil {
+		return fmt.Errorf("unable to write the handoff document: %v", err)
+	}
+	if _, err := memfd.Seek(0, 0); err != nil {
+		return err
+	}
+
+	for _, f := range files {
+		if _, err := unix.FcntlInt(f.Fd(), unix.F_SETFD, 0); err != nil {
+			return fmt.Errorf("unable to keep %s open across the exec: %v", f.Name(), err)
+		}
+	}
+	env := []string{EnvHandoffFD + "=" + strconv.Itoa(fd)}
+	for _, e := range os.Environ() {
+		if !strings.HasPrefix(e, EnvHandoffFD+"=") {
+			env = append(env, e)
+		}
+	}
+	err = syscall.Exec(path, args, env)
+	for _, f := range files {
+		syscall.CloseOnExec(int(f.Fd()))
+	}
+	return fmt.Errorf("unable to exec %s: %v", path, err)
+}
+
+// inherit loads the handoff document of the previous process, the descriptors are closed on exec again so the
+// commands run by the agent don't inherit them.
+func inherit() error {
+	s := os.Getenv(EnvHandoffFD)
+	if s == "" {
+		return nil
+	}
+	os.Unsetenv(EnvHandoffFD)
+	fd, err := strconv.Atoi(s)
+	if err != nil {
+		return fmt.Errorf("invalid %s %q", EnvHandoffFD, s)
+	}
+	f := os.NewFile(uintptr(fd), "handoff")
+	defer f.Close()
+	b, err := ioutil.ReadAll(f)
+	if err != nil {
+		return fmt.Errorf("unable to read the handoff document: %v", err)
+	}
+	doc := document{}
+	if err := json.Unmarshal(b, &doc); err != nil {
+		return fmt.Errorf("invalid handoff document: %v", err)
+	}
+	for _, fd := range doc.Listeners {
+		syscall.CloseOnExec(int(fd))
+	}
+	for _, conns := range doc.Conns {
+		for _, c := range conns {
+			syscall.CloseOnExec(int(c.FD))
+		}
+	}
+	reg.load(doc)
+	return nil
+}
+
+func init() {
+	if err := inherit(); err != nil {
+		log.Printf("E! [handoff] %v", err)
+	}
+}
@@ -0,0 +1,15 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+//go:build !linux
+// +build !linux
+
+package handoff
+
+// Supported is whether the platform can hand off the agent.
+const Supported = false
+
+// Exec isn't supported, the agent is restarted by the service manager.
+func Exec(path string, args []string) error {
+	return errUnsupported
+}
@@ -0,0 +1,369 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+// Package handoff hands the listening sockets, the accepted connections and the state of the inputs, like the tail
+// offsets, from the agent to the new binary replacing it during an upgrade. The process execs the new binary in
+// place, the sockets stay open across the exec so the senders see no connection reset and the kernel queues their
+// data until the new process reads it.
+package handoff
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"sync"
+)
+
+// EnvHandoffFD is the environment variable of the file descriptor of the handoff document in the new process.
+const EnvHandoffFD = "CWAGENT_HANDOFF_FD"
+
+var errUnsupported = errors.New("handoff is not supported on this platform")
+
+// document is what the process hands to the new one, the file descriptors stay open across the exec.
+type document struct {
+	Listeners map[string]uintptr         `json:"listeners,omitempty"`
+	Conns     map[string][]handedConn    `json:"conns,omitempty"`
+	State     map[string]json.RawMessage `json:"state,omitempty"`
+}
+
+type handedConn struct {
+	FD uintptr `json:"fd"`
+	// the bytes read from the connection but not processed yet
+	Unread []byte `json:"unread,omitempty"`
+}
+
+type conn struct {
+	file   *os.File
+	unread []byte
+}
+
+// HandedConn is a connection accepted by the previous process.
+type HandedConn struct {
+	net.Conn
+	// the bytes read by the previous process but not processed, they come before the bytes of the connection
+	Unread []byte
+}
+
+type registry struct {
+	mu sync.Mutex
+	// a handoff is in progress, the listeners closed by the inputs are kept for the new process
+	handing bool
+
+	// the listeners and the connections of the previous process not claimed yet, by network://address
+	inherited      map[string]*os.File
+	inheritedConns map[string][]conn
+	inheritedState map[string]json.RawMessage
+
+	// the duplicates of the listeners of this process and the connections kept for the new one
+	held  map[string]*os.File
+	kept  map[string][]conn
+	state map[string]func() interface{}
+}
+
+func newRegistry() *registry {
+	return &registry{
+		inherited:      map[string]*os.File{},
+		inheritedConns: map[string][]conn{},
+		inheritedState: map[string]json.RawMessage{},
+		held:           map[string]*os.File{},
+		kept:           map[string][]conn{},
+		state:          map[string]func() interface{}{},
+	}
+}
+
+var reg = newRegistry()
+
+// filer is implemented by the tcp, udp and unix listeners and connections.
+type filer interface {
+	File() (*os.File, error)
+}
+
+type listener struct {
+	net.Listener
+	key string
+}
+
+func (l *listener) Close() error {
+	if ul, ok := l.Listener.(*net.UnixListener); ok {
+		// the socket file stays for the new process during a handoff
+		ul.SetUnlinkOnClose(!Handing())
+	}
+	reg.release(l.key)
+	return l.Listener.Close()
+}
+
+type packetConn struct {
+	net.PacketConn
+	key string
+}
+
+func (c *packetConn) Close() error {
+	reg.release(c.key)
+	return c.PacketConn.Close()
+}
+
+// SetReadBuffer sets the size of the receive buffer of the socket, like the one of net.UDPConn.
+func (c *packetConn) SetReadBuffer(bytes int) error {
+	rb, ok := c.PacketConn.(interface{ SetReadBuffer(int) error })
+	if !ok {
+		return fmt.Errorf("the socket %s has no read buffer", c.key)
+	}
+	return rb.SetReadBuffer(bytes)
+}
+
+// Listen returns the listener of the previous process on the address, else a new one like net.Listen.
+func Listen(network, address string) (net.Listener, error) {
+	key := network + "://" + address
+	var ln net.Listener
+	if f := reg.claim(key); f != nil {
+		var err error
+		ln, err = net.FileListener(f)
+		f.Close()
+		if err != nil {
+			return nil, fmt.Errorf("unable to use the listener %s of the previous process: %v", key, err)
+		}
+	} else {
+		var err error
+		if ln, err = net.Listen(network, address); err != nil {
+			return nil, err
+		}
+	}
+	if err := reg.hold(key, ln); err != nil {
+		ln.Close()
+		return nil, err
+	}
+	return &listener{Listener: ln, key: key}, nil
+}
+
+// ListenPacket returns the socket of the previous process on the address, else a new one like net.ListenPacket.
+func ListenPacket(network, address string) (net.PacketConn, error) {
+	key := network + "://" + address
+	var pc net.PacketConn
+	if f := reg.claim(key); f != nil {
+		var err error
+		pc, err = net.FilePacketConn(f)
+		f.Close()
+		if err != nil {
+			return nil, fmt.Errorf("unable to use the socket %s of the previous process: %v", key, err)
+		}
+	} else {
+		var err error
+		if pc, err = net.ListenPacket(network, address); err != nil {
+			return nil, err
+		}
+	}
+	if err := reg.hold(key, pc); err != nil {
+		pc.Close()
+		return nil, err
+	}
+	return &packetConn{PacketConn: pc, key: key}, nil
+}
+
+// Handing returns whether a handoff is in progress, the inputs stopping keep their connections for the new process
+// with KeepConn instead of closing them.
+func Handing() bool {
+	reg.mu.Lock()
+	defer reg.mu.Unlock()
+	return reg.handing
+}
+
+// KeepConn hands the connection accepted by the listener of Listen to the new process, with the bytes read but not
+// processed yet. The connection is closed in this process, the socket stays open for the new one.
+func KeepConn(ln net.Listener, c net.Conn, unread []byte) error {
+	l, ok := ln.(*listener)
+	if !ok {
+		return fmt.Errorf("the listener %s isn't handed off", ln.Addr())
+	}
+	fc, ok := c.(filer)
+	if !ok {
+		return fmt.Errorf("the connection %s can't be handed off", c.RemoteAddr())
+	}
+	f, err := fc.File()
+	if err != nil {
+		return err
+	}
+	c.Close()
+	reg.mu.Lock()
+	defer reg.mu.Unlock()
+	if !reg.handing {
+		f.Close()
+		return errors.New("no handoff in progress")
+	}
+	reg.kept[l.key] = append(reg.kept[l.key], conn{file: f, unread: append([]byte(nil), unread...)})
+	return nil
+}
+
+// Conns returns the connections of the listener of Listen accepted by the previous process, once.
+func Conns(ln net.Listener) []HandedConn {
+	l, ok := ln.(*listener)
+	if !ok {
+		return nil
+	}
+	reg.mu.Lock()
+	conns := reg.inheritedConns[l.key]
+	delete(reg.inheritedConns, l.key)
+	reg.mu.Unlock()
+
+	var handed []HandedConn
+	for _, c := range conns {
+		nc, err := net.FileConn(c.file)
+		c.file.Close()
+		if err != nil {
+			continue
+		}
+		handed = append(handed, HandedConn{Conn: nc, Unread: c.unread})
+	}
+	return handed
+}
+
+// RegisterState registers the provider of a state handed to the new process under the key, it is called once the
+// agent is stopped. The returned function unregisters it.
+func RegisterState(key string, provider func() interface{}) func() {
+	reg.mu.Lock()
+	defer reg.mu.Unlock()
+	reg.state[key] = provider
+	return func() {
+		reg.mu.Lock()
+		defer reg.mu.Unlock()
+		delete(reg.state, key)
+	}
+}
+
+// State decodes the state of the key handed by the previous process in value, once, it returns whether there was
+// one.
+func State(key string, value interface{}) bool {
+	reg.mu.Lock()
+	b, ok := reg.inheritedState[key]
+	delete(reg.inheritedState, key)
+	reg.mu.Unlock()
+	return ok && json.Unmarshal(b, value) == nil
+}
+
+// Begin starts a handoff, from now the listeners closed are kept for the new process.
+func Begin() {
+	reg.mu.Lock()
+	defer reg.mu.Unlock()
+	reg.handing = true
+}
+
+// Abort cancels the handoff when the new process can't be executed, the listeners and the connections kept are
+// claimed again by the inputs restarted in this process.
+func Abort() {
+	reg.mu.Lock()
+	defer reg.mu.Unlock()
+	reg.handing = false
+	for key, f := range reg.held {
+		reg.inherited[key] = f
+	}
+	for key, conns := range reg.kept {
+		reg.inheritedConns[key] = append(reg.inheritedConns[key], conns...)
+	}
+	reg.held = map[string]*os.File{}
+	reg.kept = map[string][]conn{}
+}
+
+// CloseUnclaimed closes the listeners and the connections of the previous process which weren't claimed, their
+// inputs were removed from the config.
+func CloseUnclaimed() {
+	reg.mu.Lock()
+	defer reg.mu.Unlock()
+	for key, f := range reg.inherited {
+		f.Close()
+		delete(reg.inherited, key)
+	}
+	for key, conns := range reg.inheritedConns {
+		for _, c := range conns {
+			c.file.Close()
+		}
+		delete(reg.inheritedConns, key)
+	}
+	reg.inheritedState = map[string]json.RawMessage{}
+}
+
+func (r *registry) claim(key string) *os.File {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	f := r.inherited[key]
+	delete(r.inherited, key)
+	return f
+}
+
+// hold keeps a duplicate of the socket, it keeps the socket open when the input closes it during a handoff.
+func (r *registry) hold(key string, socket interface{}) error {
+	fs, ok := socket.(filer)
+	if !ok {
+		return nil
+	}
+	f, err := fs.File()
+	if err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if previous, ok := r.held[key]; ok {
+		previous.Close()
+	}
+	r.held[key] = f
+	return nil
+}
+
+func (r *registry) release(key string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.handing {
+		return
+	}
+	if f, ok := r.held[key]; ok {
+		f.Close()
+		delete(r.held, key)
+	}
+}
+
+// document returns the handoff document and the files whose descriptors it references.
+func (r *registry) document() (document, []*os.File, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	doc := document{
+		Listeners: map[string]uintptr{},
+		Conns:     map[string][]handedConn{},
+		State:     map[string]json.RawMessage{},
+	}
+	var files []*os.File
+	for key, f := range r.held {
+		doc.Listeners[key] = f.Fd()
+		files = append(files, f)
+	}
+	for key, conns := range r.kept {
+		for _, c := range conns {
+			doc.Conns[key] = append(doc.Conns[key], handedConn{FD: c.file.Fd(), Unread: c.unread})
+			files = append(files, c.file)
+		}
+	}
+	for key, provider := range r.state {
+		b, err := json.Marshal(provider())
+		if err != nil {
+			return doc, nil, fmt.Errorf("unable to encode the state %s: %v", key, err)
+		}
+		doc.State[key] = b
+	}
+	return doc, files, nil
+}
+
+// load takes the listeners, the connections and the state of the document of the previous process.
+func (r *registry) load(doc document) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for key, fd := range doc.Listeners {
+		r.inherited[key] = os.NewFile(fd, key)
+	}
+	for key, conns := range doc.Conns {
+		for _, c := range conns {
+			r.inheritedConns[key] = append(r.inheritedConns[key], conn{file: os.NewFile(c.FD, key), unread: c.Unread})
+		}
+	}
+	for key, b := range doc.State {
+		r.inheritedState[key] = b
+	}
+}
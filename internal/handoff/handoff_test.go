@@ -0,0 +1,188 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+//go:build !windows
+// +build !windows
+
+package handoff
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCloseReleasesListener(t *testing.T) {
+	reg = newRegistry()
+	ln, err := Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	assert.Len(t, reg.held, 1)
+	assert.NoError(t, ln.Close())
+	assert.Empty(t, reg.held)
+
+	// the port is free again
+	ln, err = net.Listen("tcp", ln.Addr().String())
+	require.NoError(t, err)
+	ln.Close()
+}
+
+func TestAbortReclaimsListenerAndConns(t *testing.T) {
+	reg = newRegistry()
+	ln, err := Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	key := ln.Addr().String()
+
+	client, err := net.Dial("tcp", key)
+	require.NoError(t, err)
+	defer client.Close()
+	c, err := ln.Accept()
+	require.NoError(t, err)
+
+	Begin()
+	assert.True(t, Handing())
+	require.NoError(t, KeepConn(ln, c, []byte("partial")))
+	require.NoError(t, ln.Close())
+
+	// the socket stays open, the kernel queues the connections until the next listener accepts them
+	queued, err := net.Dial("tcp", key)
+	require.NoError(t, err)
+	defer queued.Close()
+	_, err = client.Write([]byte(" line\n"))
+	require.NoError(t, err)
+
+	Abort()
+	assert.False(t, Handing())
+	// the listener of the address of the config is reclaimed, with the port assigned
+	ln, err = Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer ln.Close()
+	assert.Equal(t, key, ln.Addr().String())
+
+	conns := Conns(ln)
+	require.Len(t, conns, 1)
+	defer conns[0].Close()
+	assert.Equal(t, "partial", string(conns[0].Unread))
+	conns[0].SetReadDeadline(time.Now().Add(5 * time.Second))
+	line, err := bufio.NewReader(conns[0]).ReadString('\n')
+	require.NoError(t, err)
+	assert.Equal(t, " line\n", line)
+	assert.Empty(t, Conns(ln))
+
+	accepted, err := ln.Accept()
+	require.NoError(t, err)
+	accepted.Close()
+}
+
+func TestAbortReclaimsPacketConn(t *testing.T) {
+	reg = newRegistry()
+	pc, err := ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := pc.LocalAddr().String()
+
+	Begin()
+	require.NoError(t, pc.Close())
+	sender, err := net.Dial("udp", addr)
+	require.NoError(t, err)
+	defer sender.Close()
+	_, err = sender.Write([]byte("counter:1|c"))
+	require.NoError(t, err)
+	Abort()
+
+	pc, err = ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer pc.Close()
+	assert.Equal(t, addr, pc.LocalAddr().String())
+	buf := make([]byte, 64)
+	pc.SetReadDeadline(time.Now().Add(5 * time.Second))
+	n, _, err := pc.ReadFrom(buf)
+	require.NoError(t, err)
+	assert.Equal(t, "counter:1|c", string(buf[:n]))
+}
+
+func TestKeepConnWithoutHandoff(t *testing.T) {
+	reg = newRegistry()
+	ln, err := Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer ln.Close()
+	client, err := net.Dial("tcp", ln.Addr().String())
+	require.NoError(t, err)
+	defer client.Close()
+	c, err := ln.Accept()
+	require.NoError(t, err)
+	assert.Error(t, KeepConn(ln, c, nil))
+}
+
+func TestState(t *testing.T) {
+	reg = newRegistry()
+	unregister := RegisterState("logfile:/var/log/messages", func() interface{} { return int64(42) })
+	RegisterState("removed", func() interface{} { return "value" })()
+	doc, _, err := reg.document()
+	require.NoError(t, err)
+	unregister()
+	assert.Len(t, doc.State, 1)
+
+	reg = newRegistry()
+	reg.load(doc)
+	var offset int64
+	assert.True(t, State("logfile:/var/log/messages", &offset))
+	assert.Equal(t, int64(42), offset)
+	// the state is handed once
+	assert.False(t, State("logfile:/var/log/messages", &offset))
+	assert.False(t, State("removed", &offset))
+}
+
+func TestCloseUnclaimed(t *testing.T) {
+	reg = newRegistry()
+	_, err := Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	Begin()
+	Abort()
+	assert.Len(t, reg.inherited, 1)
+	CloseUnclaimed()
+	assert.Empty(t, reg.inherited)
+}
+
+func TestServe(t *testing.T) {
+	dir, err := ioutil.TempDir("", "handoff")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "agent.sock")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var mtx sync.Mutex
+	started := 0
+	var startErr error
+	done := make(chan error)
+	go func() {
+		done <- Serve(ctx, path, func() error {
+			mtx.Lock()
+			defer mtx.Unlock()
+			started++
+			return startErr
+		})
+	}()
+	require.Eventually(t, func() bool {
+		_, err := os.Stat(path)
+		return err == nil
+	}, 5*time.Second, 10*time.Millisecond)
+
+	assert.NoError(t, Request(path, 5*time.Second))
+	mtx.Lock()
+	assert.Equal(t, 1, started)
+	startErr = errors.New("a handoff is already in progress")
+	mtx.Unlock()
+	assert.EqualError(t, Request(path, 5*time.Second), "a handoff is already in progress")
+
+	cancel()
+	assert.NoError(t, <-done)
+}
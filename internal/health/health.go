@@ -0,0 +1,204 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+// Package health tracks the publishing of the agent pipelines, the cloudwatch and cloudwatchlogs outputs, and notifies
+// when a pipeline keeps failing for longer than a threshold. The failures are only tracked when the tracking is
+// enabled by the agent_health input, which runs the configured hook on the notifications.
+package health
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	StatusUnhealthy = "unhealthy"
+	StatusRecovered = "recovered"
+
+	defaultThreshold     = 5 * time.Minute
+	defaultCheckInterval = 10 * time.Second
+)
+
+// Event is the notification of a pipeline that became unhealthy or recovered.
+type Event struct {
+	Status              string    `json:"status"`
+	Pipeline            string    `json:"pipeline"`
+	Time                time.Time `json:"timestamp"`
+	FailingSince        time.Time `json:"failing_since"`
+	FailingSeconds      int64     `json:"failing_duration_seconds"`
+	ConsecutiveFailures int       `json:"consecutive_failures"`
+	LastError           string    `json:"last_error,omitempty"`
+}
+
+type Config struct {
+	// Threshold is how long a pipeline fails without any success before it is unhealthy.
+	Threshold time.Duration
+	// RepeatInterval repeats the notification while the pipeline stays unhealthy, it is sent once when zero.
+	RepeatInterval time.Duration
+	// Notify is called from the goroutine of the tracker, one event at a time.
+	Notify func(Event)
+}
+
+type pipelineState struct {
+	failingSince time.Time
+	failures     int
+	lastError    string
+	// the time of the last unhealthy notification of the current failure, zero when it has not been notified
+	notified time.Time
+}
+
+type tracker struct {
+	sync.Mutex
+	enabled   bool
+	config    Config
+	pipelines map[string]*pipelineState
+	// the recovered events, sent by the goroutine of the tracker so the outputs are not delayed by the hook
+	pending []Event
+	done    chan struct{}
+	now     func() time.Time
+}
+
+var defaultTracker = newTracker()
+
+func newTracker() *tracker {
+	return &tracker{now: time.Now}
+}
+
+// Enable starts the tracking of the pipelines.
+func Enable(config Config) {
+	defaultTracker.enable(config, defaultCheckInterval)
+}
+
+// Disable stops the tracking and forgets the state of the pipelines.
+func Disable() {
+	defaultTracker.disable()
+}
+
+// RecordSuccess records a successful publishing of the pipeline, which is healthy again.
+func RecordSuccess(pipeline string) {
+	defaultTracker.recordSuccess(pipeline)
+}
+
+// RecordFailure records a failed publishing of the pipeline, retries included.
+func RecordFailure(pipeline string, err error) {
+	defaultTracker.recordFailure(pipeline, err)
+}
+
+func (t *tracker) enable(config Config, checkInterval time.Duration) {
+	t.Lock()
+	defer t.Unlock()
+	if t.enabled {
+		close(t.done)
+	}
+	if config.Threshold <= 0 {
+		config.Threshold = defaultThreshold
+	}
+	t.enabled = true
+	t.config = config
+	t.pipelines = make(map[string]*pipelineState)
+	t.pending = nil
+	t.done = make(chan struct{})
+	go t.run(checkInterval, t.done)
+}
+
+func (t *tracker) disable() {
+	t.Lock()
+	defer t.Unlock()
+	if !t.enabled {
+		return
+	}
+	close(t.done)
+	t.enabled = false
+	t.pipelines = nil
+	t.pending = nil
+}
+
+func (t *tracker) run(checkInterval time.Duration, done chan struct{}) {
+	ticker := time.NewTicker(checkInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			t.check()
+		case <-done:
+			return
+		}
+	}
+}
+
+func (t *tracker) recordSuccess(pipeline string) {
+	t.Lock()
+	defer t.Unlock()
+	if !t.enabled {
+		return
+	}
+	s, ok := t.pipelines[pipeline]
+	if !ok {
+		return
+	}
+	if !s.notified.IsZero() {
+		t.pending = append(t.pending, t.event(StatusRecovered, pipeline, s))
+	}
+	delete(t.pipelines, pipeline)
+}
+
+func (t *tracker) recordFailure(pipeline string, err error) {
+	t.Lock()
+	defer t.Unlock()
+	if !t.enabled {
+		return
+	}
+	s, ok := t.pipelines[pipeline]
+	if !ok {
+		s = &pipelineState{failingSince: t.now()}
+		t.pipelines[pipeline] = s
+	}
+	s.failures++
+	if err != nil {
+		s.lastError = err.Error()
+	}
+}
+
+// check notifies the recovered pipelines and the pipelines failing for longer than the threshold.
+func (t *tracker) check() {
+	t.Lock()
+	if !t.enabled {
+		t.Unlock()
+		return
+	}
+	events := t.pending
+	t.pending = nil
+	now := t.now()
+	for pipeline, s := range t.pipelines {
+		if now.Sub(s.failingSince) < t.config.Threshold {
+			continue
+		}
+		if !s.notified.IsZero() && (t.config.RepeatInterval <= 0 || now.Sub(s.notified) < t.config.RepeatInterval) {
+			continue
+		}
+		s.notified = now
+		events = append(events, t.event(StatusUnhealthy, pipeline, s))
+	}
+	notify := t.config.Notify
+	t.Unlock()
+
+	if notify == nil {
+		return
+	}
+	for _, e := range events {
+		notify(e)
+	}
+}
+
+func (t *tracker) event(status, pipeline string, s *pipelineState) Event {
+	now := t.now()
+	return Event{
+		Status:              status,
+		Pipeline:            pipeline,
+		Time:                now,
+		FailingSince:        s.failingSince,
+		FailingSeconds:      int64(now.Sub(s.failingSince) / time.Second),
+		ConsecutiveFailures: s.failures,
+		LastError:           s.lastError,
+	}
+}
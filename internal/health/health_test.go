@@ -0,0 +1,118 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package health
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeClock struct {
+	t time.Time
+}
+
+func (c *fakeClock) now() time.Time {
+	return c.t
+}
+
+func newTestTracker(repeat time.Duration) (*tracker, *fakeClock, *[]Event) {
+	clock := &fakeClock{t: time.Date(2021, 3, 2, 15, 4, 5, 0, time.UTC)}
+	var events []Event
+	t := newTracker()
+	t.now = clock.now
+	// the checks are run by the tests
+	t.enable(Config{Threshold: 5 * time.Minute, RepeatInterval: repeat, Notify: func(e Event) { events = append(events, e) }}, time.Hour)
+	return t, clock, &events
+}
+
+func TestTracker_Disabled(t *testing.T) {
+	tr := newTracker()
+	tr.recordFailure("cloudwatch", errors.New("throttled"))
+	tr.recordSuccess("cloudwatch")
+	tr.check()
+	assert.Empty(t, tr.pipelines)
+}
+
+func TestTracker_Unhealthy(t *testing.T) {
+	tr, clock, events := newTestTracker(0)
+	defer tr.disable()
+
+	tr.recordFailure("cloudwatch", errors.New("RequestError: send request failed"))
+	clock.t = clock.t.Add(4 * time.Minute)
+	tr.recordFailure("cloudwatch", errors.New("RequestError: dial tcp: i/o timeout"))
+	tr.check()
+	assert.Empty(t, *events)
+
+	clock.t = clock.t.Add(time.Minute)
+	tr.check()
+	assert.Equal(t, []Event{{
+		Status:              StatusUnhealthy,
+		Pipeline:            "cloudwatch",
+		Time:                clock.t,
+		FailingSince:        clock.t.Add(-5 * time.Minute),
+		FailingSeconds:      300,
+		ConsecutiveFailures: 2,
+		LastError:           "RequestError: dial tcp: i/o timeout",
+	}}, *events)
+
+	// notified once
+	clock.t = clock.t.Add(time.Hour)
+	tr.check()
+	assert.Len(t, *events, 1)
+
+	tr.recordSuccess("cloudwatch")
+	assert.Len(t, *events, 1, "the recovered event is sent by the next check")
+	tr.check()
+	assert.Len(t, *events, 2)
+	assert.Equal(t, StatusRecovered, (*events)[1].Status)
+	assert.Equal(t, int64(3900), (*events)[1].FailingSeconds)
+	assert.Empty(t, tr.pipelines)
+}
+
+func TestTracker_RecoveredBeforeThreshold(t *testing.T) {
+	tr, clock, events := newTestTracker(0)
+	defer tr.disable()
+
+	tr.recordFailure("cloudwatchlogs", errors.New("ServiceUnavailableException"))
+	clock.t = clock.t.Add(time.Minute)
+	tr.recordSuccess("cloudwatchlogs")
+	clock.t = clock.t.Add(10 * time.Minute)
+	tr.check()
+	assert.Empty(t, *events)
+
+	// a new failure starts a new period
+	tr.recordFailure("cloudwatchlogs", nil)
+	clock.t = clock.t.Add(time.Minute)
+	tr.check()
+	assert.Empty(t, *events)
+}
+
+func TestTracker_Repeat(t *testing.T) {
+	tr, clock, events := newTestTracker(30 * time.Minute)
+	defer tr.disable()
+
+	tr.recordFailure("cloudwatch", errors.New("throttled"))
+	tr.recordFailure("cloudwatchlogs", errors.New("throttled"))
+	tr.recordSuccess("cloudwatchlogs")
+	for i := 0; i < 8; i++ {
+		clock.t = clock.t.Add(10 * time.Minute)
+		tr.check()
+	}
+	// at 10, 40 and 70 minutes
+	assert.Len(t, *events, 3)
+	for _, e := range *events {
+		assert.Equal(t, "cloudwatch", e.Pipeline)
+		assert.Equal(t, StatusUnhealthy, e.Status)
+	}
+	assert.Equal(t, int64(4200), (*events)[2].FailingSeconds)
+
+	// the state is forgotten when the tracking is disabled
+	tr.disable()
+	tr.check()
+	assert.Len(t, *events, 3)
+	assert.Nil(t, tr.pipelines)
+}
@@ -0,0 +1,98 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package logscommon
+
+import (
+	"fmt"
+	"hash/fnv"
+	"regexp"
+)
+
+const (
+	// the limits of CloudWatch Logs on the log group and log stream names
+	MaxLogGroupNameLength  = 512
+	MaxLogStreamNameLength = 512
+
+	// the names longer than the limit are truncated and suffixed with a hash of the whole name, or only truncated
+	OverflowHash     = "hash"
+	OverflowTruncate = "truncate"
+
+	defaultNameReplacement = "_"
+)
+
+var (
+	invalidLogGroupNameChars  = regexp.MustCompile(`[^._\-/#A-Za-z0-9]`)
+	invalidLogStreamNameChars = regexp.MustCompile(`[:*]`)
+	// the replacement is valid in the log group and the log stream names
+	validNameReplacement = regexp.MustCompile(`^[._\-/#A-Za-z0-9]{1,8}$`)
+)
+
+// NameSanitizer makes the log group and log stream names valid for CloudWatch Logs once their placeholders are
+// resolved, the invalid characters are replaced and the names over the limits are shortened, so the events are
+// published instead of being rejected with every request.
+type NameSanitizer struct {
+	replacement string
+	overflow    string
+}
+
+// NewNameSanitizer returns the sanitizer of the policy, an empty replacement or overflow is the default one, the
+// characters replaced with "_" and the overflow hashed.
+func NewNameSanitizer(replacement, overflow string) (*NameSanitizer, error) {
+	s := &NameSanitizer{replacement: replacement, overflow: overflow}
+	if s.replacement == "" {
+		s.replacement = defaultNameReplacement
+	} else if !validNameReplacement.MatchString(s.replacement) {
+		return nil, fmt.Errorf("invalid replacement %q, it must be 1 to 8 of the characters allowed in a log group name: a-z, A-Z, 0-9, '_', '-', '/', '.' and '#'", replacement)
+	}
+	switch s.overflow {
+	case "":
+		s.overflow = OverflowHash
+	case OverflowHash, OverflowTruncate:
+	default:
+		return nil, fmt.Errorf("invalid overflow %q, it must be %s or %s", overflow, OverflowHash, OverflowTruncate)
+	}
+	return s, nil
+}
+
+// LogGroupName returns the valid log group name of the name.
+func (s *NameSanitizer) LogGroupName(name string) string {
+	return s.sanitize(name, invalidLogGroupNameChars, MaxLogGroupNameLength)
+}
+
+// LogStreamName returns the valid log stream name of the name.
+func (s *NameSanitizer) LogStreamName(name string) string {
+	return s.sanitize(name, invalidLogStreamNameChars, MaxLogStreamNameLength)
+}
+
+// ValidLogGroupName tells whether CloudWatch Logs accepts the log group name.
+func ValidLogGroupName(name string) bool {
+	return name != "" && len(name) <= MaxLogGroupNameLength && !invalidLogGroupNameChars.MatchString(name)
+}
+
+// ValidLogStreamName tells whether CloudWatch Logs accepts the log stream name.
+func ValidLogStreamName(name string) bool {
+	return name != "" && len(name) <= MaxLogStreamNameLength && !invalidLogStreamNameChars.MatchString(name)
+}
+
+func (s *NameSanitizer) sanitize(name string, invalidChars *regexp.Regexp, maxLength int) string {
+	name = invalidChars.ReplaceAllString(name, s.replacement)
+	if len(name) <= maxLength {
+		return name
+	}
+	if s.overflow == OverflowTruncate {
+		return truncate(name, maxLength)
+	}
+	h := fnv.New32a()
+	h.Write([]byte(name))
+	suffix := fmt.Sprintf("-%08x", h.Sum32())
+	return truncate(name, maxLength-len(suffix)) + suffix
+}
+
+// truncate cuts the string at a rune boundary.
+func truncate(s string, length int) string {
+	for length > 0 && length < len(s) && s[length]&0xC0 == 0x80 {
+		length--
+	}
+	return s[:length]
+}
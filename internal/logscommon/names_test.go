@@ -0,0 +1,55 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package logscommon
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNameSanitizer(t *testing.T) {
+	s, err := NewNameSanitizer("", "")
+	assert.NoError(t, err)
+	// the valid names are unchanged
+	assert.Equal(t, "/aws/app#1/web-01_a.log", s.LogGroupName("/aws/app#1/web-01_a.log"))
+	assert.Equal(t, "web 01 {bucket} é", s.LogStreamName("web 01 {bucket} é"))
+
+	assert.Equal(t, "app_logs_web_01", s.LogGroupName("app logs:web*01"))
+	assert.Equal(t, "arn_aws_ecs_task_1", s.LogStreamName("arn:aws:ecs*task:1"))
+
+	long := strings.Repeat("a", 600)
+	group := s.LogGroupName(long)
+	assert.Len(t, group, MaxLogGroupNameLength)
+	assert.True(t, strings.HasPrefix(group, strings.Repeat("a", 503)+"-"))
+	// the names only differing past the limit stay distinct
+	assert.NotEqual(t, group, s.LogGroupName(long+"b"))
+
+	// the truncation keeps whole runes
+	stream := s.LogStreamName(strings.Repeat("é", 300))
+	assert.Len(t, stream, 511)
+	assert.True(t, strings.HasSuffix(stream, "-"+stream[len(stream)-8:]))
+}
+
+func TestNameSanitizer_Policy(t *testing.T) {
+	s, err := NewNameSanitizer("-", OverflowTruncate)
+	assert.NoError(t, err)
+	assert.Equal(t, "app-logs", s.LogGroupName("app logs"))
+	assert.Equal(t, strings.Repeat("a", MaxLogStreamNameLength), s.LogStreamName(strings.Repeat("a", 600)))
+
+	_, err = NewNameSanitizer(":", "")
+	assert.Error(t, err)
+	_, err = NewNameSanitizer("", "reject")
+	assert.EqualError(t, err, `invalid overflow "reject", it must be hash or truncate`)
+}
+
+func TestValidLogNames(t *testing.T) {
+	assert.True(t, ValidLogGroupName("/aws/app#1/web-01_a.log"))
+	assert.False(t, ValidLogGroupName("app logs"))
+	assert.False(t, ValidLogGroupName(""))
+	assert.False(t, ValidLogGroupName(strings.Repeat("a", 513)))
+	assert.True(t, ValidLogStreamName("web 01 {bucket}"))
+	assert.False(t, ValidLogStreamName("arn:aws:ecs"))
+}
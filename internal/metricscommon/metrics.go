@@ -0,0 +1,70 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package metricscommon
+
+import (
+	"runtime"
+	"time"
+)
+
+// the field whose CloudWatch metric is named after its measurement alone
+const valueField = "value"
+
+// MetricName returns the name of the CloudWatch metric of the field on this platform, before the metric_decoration
+// renames, e.g. cpu_usage_idle, or "LogicalDisk % Free Space" on Windows.
+func MetricName(measurement, field string) string {
+	return MetricNameForOS(measurement, field, runtime.GOOS)
+}
+
+// MetricNameForOS returns the name of the CloudWatch metric of the field on the os, the Windows names are separated
+// by a space.
+func MetricNameForOS(measurement, field, goos string) string {
+	if field == valueField {
+		return measurement
+	}
+	separator := "_"
+	if goos == "windows" {
+		separator = " "
+	}
+	return measurement + separator + field
+}
+
+// ToFloat returns the value the field is published with, for the numeric, boolean and time fields. The other fields,
+// e.g. the strings, are not published.
+func ToFloat(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case uint:
+		return float64(v), true
+	case uint8:
+		return float64(v), true
+	case uint16:
+		return float64(v), true
+	case uint32:
+		return float64(v), true
+	case uint64:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case int8:
+		return float64(v), true
+	case int16:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case float32:
+		return float64(v), true
+	case float64:
+		return v, true
+	case bool:
+		if v {
+			return 1, true
+		}
+		return 0, true
+	case time.Time:
+		return float64(v.Unix()), true
+	}
+	return 0, false
+}
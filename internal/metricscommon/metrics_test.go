@@ -0,0 +1,37 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package metricscommon
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetricNameForOS(t *testing.T) {
+	assert.Equal(t, "cpu_usage_idle", MetricNameForOS("cpu", "usage_idle", "linux"))
+	assert.Equal(t, "LogicalDisk % Free Space", MetricNameForOS("LogicalDisk", "% Free Space", "windows"))
+	assert.Equal(t, "statsd_timer", MetricNameForOS("statsd_timer", "value", "linux"))
+	assert.Equal(t, "statsd_timer", MetricNameForOS("statsd_timer", "value", "windows"))
+}
+
+func TestToFloat(t *testing.T) {
+	for _, value := range []interface{}{uint(2), uint8(2), uint16(2), uint32(2), uint64(2), 2, int8(2), int16(2), int32(2), int64(2), float32(2), 2.0} {
+		f, ok := ToFloat(value)
+		assert.True(t, ok, "%T", value)
+		assert.Equal(t, 2.0, f, "%T", value)
+	}
+	f, ok := ToFloat(true)
+	assert.True(t, ok)
+	assert.Equal(t, 1.0, f)
+	f, ok = ToFloat(false)
+	assert.True(t, ok)
+	assert.Equal(t, 0.0, f)
+	f, ok = ToFloat(time.Unix(1600000000, 0))
+	assert.True(t, ok)
+	assert.Equal(t, 1600000000.0, f)
+	_, ok = ToFloat("2")
+	assert.False(t, ok)
+}
@@ -0,0 +1,188 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package retryer
+
+import (
+	"log"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/influxdata/telegraf/selfstat"
+)
+
+const (
+	// the rate of the throttled requests is at least this part of max_requests_per_second
+	rateLimitMinDivisor = 100
+	// the rate is halved once by second at most, and grows back by a twentieth of max_requests_per_second every ten
+	// seconds at most
+	rateLimitShrinkInterval = time.Second
+	rateLimitGrowInterval   = 10 * time.Second
+	rateLimitGrowthDivisor  = 20
+)
+
+// RateLimiter spaces the attempts of the limited operations to at most max_requests_per_second, and adapts the rate to
+// the throttling: the rate is halved when a request is throttled, and grows back by a twentieth of
+// max_requests_per_second by successful request every ten seconds at most. An output shares its limiter between all
+// its clients, so the hosts of a fleet sharing the API limits of an account back off together.
+type RateLimiter struct {
+	max        float64
+	operations map[string]bool
+
+	mu         sync.Mutex
+	current    float64
+	next       time.Time
+	lastChange time.Time
+}
+
+// NewRateLimiter returns nil, which doesn't limit the requests, when maxRequestsPerSecond isn't positive.
+func NewRateLimiter(maxRequestsPerSecond float64, operations ...string) *RateLimiter {
+	if maxRequestsPerSecond <= 0 {
+		return nil
+	}
+	return &RateLimiter{max: maxRequestsPerSecond, operations: toSet(operations), current: maxRequestsPerSecond}
+}
+
+// Wait blocks until the next attempt can be sent.
+func (l *RateLimiter) Wait() {
+	l.mu.Lock()
+	now := time.Now()
+	if l.next.Before(now) {
+		l.next = now
+	}
+	wait := l.next.Sub(now)
+	l.next = l.next.Add(time.Duration(float64(time.Second) / l.current))
+	l.mu.Unlock()
+	time.Sleep(wait)
+}
+
+// Rate returns the requests per second currently allowed.
+func (l *RateLimiter) Rate() float64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.current
+}
+
+// Succeeded grows the rate back after a successful request.
+func (l *RateLimiter) Succeeded() {
+	if l == nil {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.current == l.max || time.Since(l.lastChange) < rateLimitGrowInterval {
+		return
+	}
+	l.current += l.max / rateLimitGrowthDivisor
+	if l.current > l.max {
+		l.current = l.max
+	}
+	l.lastChange = time.Now()
+}
+
+// AddHandlers waits for the rate before each attempt of the limited operations, including the ones the sdk retries,
+// and halves the rate on their throttled attempts.
+func (l *RateLimiter) AddHandlers(h *request.Handlers) {
+	if l == nil {
+		return
+	}
+	h.Send.PushFrontNamed(request.NamedHandler{
+		Name: "retryer.RateLimiterWaitHandler",
+		Fn: func(r *request.Request) {
+			if l.operations[operationName(r)] {
+				l.Wait()
+			}
+		},
+	})
+	h.Retry.PushBackNamed(request.NamedHandler{
+		Name: "retryer.RateLimiterThrottleHandler",
+		Fn: func(r *request.Request) {
+			if l.operations[operationName(r)] && r.IsErrorThrottle() {
+				l.throttled()
+			}
+		},
+	})
+}
+
+func (l *RateLimiter) throttled() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	min := l.max / rateLimitMinDivisor
+	if l.current <= min || time.Since(l.lastChange) < rateLimitShrinkInterval {
+		return
+	}
+	l.current /= 2
+	if l.current < min {
+		l.current = min
+	}
+	l.lastChange = time.Now()
+	log.Printf("D! The requests are throttled, they are limited to %.2f per second", l.current)
+}
+
+// RequestStats counts the throttled and the retried attempts of the operations of an output, by operation in the
+// requests_throttled and requests_retried stats of the measurement of the output in the internal input. The retries of
+// the sdk and of the output are both counted.
+type RequestStats struct {
+	throttled map[string]selfstat.Stat
+	retried   map[string]selfstat.Stat
+}
+
+func NewRequestStats(measurement string, operations ...string) *RequestStats {
+	s := &RequestStats{throttled: map[string]selfstat.Stat{}, retried: map[string]selfstat.Stat{}}
+	for _, operation := range operations {
+		tags := map[string]string{"operation": operation}
+		s.throttled[operation] = selfstat.Register(measurement, "requests_throttled", tags)
+		s.retried[operation] = selfstat.Register(measurement, "requests_retried", tags)
+	}
+	return s
+}
+
+// Retried counts a retry of the operation by the output.
+func (s *RequestStats) Retried(operation string) {
+	if s == nil {
+		return
+	}
+	if stat, ok := s.retried[operation]; ok {
+		stat.Incr(1)
+	}
+}
+
+// AddHandlers counts the throttled attempts on the error of each attempt, and the attempts the sdk retries before it
+// clears their error.
+func (s *RequestStats) AddHandlers(h *request.Handlers) {
+	if s == nil {
+		return
+	}
+	h.Retry.PushBackNamed(request.NamedHandler{
+		Name: "retryer.RequestStatsThrottleHandler",
+		Fn: func(r *request.Request) {
+			if stat, ok := s.throttled[operationName(r)]; ok && r.IsErrorThrottle() {
+				stat.Incr(1)
+			}
+		},
+	})
+	h.AfterRetry.PushFrontNamed(request.NamedHandler{
+		Name: "retryer.RequestStatsRetryHandler",
+		Fn: func(r *request.Request) {
+			if stat, ok := s.retried[operationName(r)]; ok && r.WillRetry() {
+				stat.Incr(1)
+			}
+		},
+	})
+}
+
+func operationName(r *request.Request) string {
+	if r.Operation == nil {
+		return ""
+	}
+	return r.Operation.Name
+}
+
+func toSet(items []string) map[string]bool {
+	set := map[string]bool{}
+	for _, item := range items {
+		set[item] = true
+	}
+	return set
+}
@@ -0,0 +1,106 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package retryer
+
+import (
+	"bytes"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/client"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateLimiter(t *testing.T) {
+	assert.Nil(t, NewRateLimiter(0, "PutLogEvents"))
+	// the limiter is disabled without a rate
+	var disabled *RateLimiter
+	disabled.Succeeded()
+	disabled.AddHandlers(&request.Handlers{})
+
+	l := NewRateLimiter(100, "PutLogEvents")
+	start := time.Now()
+	for i := 0; i < 11; i++ {
+		l.Wait()
+	}
+	// the first attempt is sent at once, the next ones 10ms apart
+	assert.True(t, time.Since(start) >= 100*time.Millisecond)
+
+	l.throttled()
+	assert.Equal(t, 50.0, l.Rate())
+	// the requests throttled at once halve the rate once
+	l.throttled()
+	assert.Equal(t, 50.0, l.Rate())
+	l.current = 1.5
+	l.lastChange = time.Now().Add(-rateLimitShrinkInterval)
+	l.throttled()
+	assert.Equal(t, 1.0, l.Rate())
+
+	// the rate grows back once the throttling stops
+	l.lastChange = time.Now().Add(-rateLimitGrowInterval)
+	l.Succeeded()
+	assert.Equal(t, 6.0, l.Rate())
+	l.current = 98
+	l.lastChange = time.Now().Add(-rateLimitGrowInterval)
+	l.Succeeded()
+	assert.Equal(t, 100.0, l.Rate())
+}
+
+func TestRateLimiter_AddHandlers(t *testing.T) {
+	l := NewRateLimiter(10, "PutLogEvents", "PutMetricData")
+	h := request.Handlers{}
+	l.AddHandlers(&h)
+	r := &request.Request{Operation: &request.Operation{Name: "CreateLogStream"}}
+	r.Error = awserr.New("ThrottlingException", "Rate exceeded", nil)
+	// the other operations are neither limited nor slowed down
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		h.Send.Run(r)
+	}
+	assert.True(t, time.Since(start) < 100*time.Millisecond)
+	h.Retry.Run(r)
+	assert.Equal(t, 10.0, l.Rate())
+
+	r.Operation.Name = "PutLogEvents"
+	h.Retry.Run(r)
+	assert.Equal(t, 5.0, l.Rate())
+	start = time.Now()
+	h.Send.Run(r)
+	h.Send.Run(r)
+	assert.True(t, time.Since(start) >= 200*time.Millisecond)
+}
+
+func TestRequestStats(t *testing.T) {
+	s := NewRequestStats("retryer_test", "PutLogEvents")
+	h := request.Handlers{}
+	s.AddHandlers(&h)
+	throttled := s.throttled["PutLogEvents"].Get()
+	retried := s.retried["PutLogEvents"].Get()
+
+	r := &request.Request{
+		Operation:   &request.Operation{Name: "PutLogEvents"},
+		Retryer:     client.DefaultRetryer{NumMaxRetries: 1},
+		Retryable:   aws.Bool(true),
+		Body:        bytes.NewReader(nil),
+		HTTPRequest: &http.Request{Body: http.NoBody},
+	}
+	r.Error = awserr.New("ThrottlingException", "Rate exceeded", nil)
+	h.Retry.Run(r)
+	h.AfterRetry.Run(r)
+	// the sdk doesn't retry the last attempt
+	r.RetryCount = 1
+	r.Error = awserr.New("ThrottlingException", "Rate exceeded", nil)
+	h.Retry.Run(r)
+	h.AfterRetry.Run(r)
+	s.Retried("PutLogEvents")
+	// the other operations aren't counted
+	s.Retried("CreateLogStream")
+
+	assert.Equal(t, throttled+2, s.throttled["PutLogEvents"].Get())
+	assert.Equal(t, retried+2, s.retried["PutLogEvents"].Get())
+}
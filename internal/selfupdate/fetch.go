@@ -0,0 +1,114 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package selfupdate
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	configaws "github.com/aws/amazon-cloudwatch-agent/cfg/aws"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/client"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/ssm"
+)
+
+const (
+	locationSSM   = "ssm:"
+	locationFile  = "file:"
+	locationS3    = "s3://"
+	locationHTTPS = "https://"
+
+	httpTimeout = 5 * time.Minute
+)
+
+// Fetcher reads the manifest, the packages and the signatures from their locations.
+type Fetcher interface {
+	Fetch(location string) ([]byte, error)
+}
+
+// AwsFetcher supports "ssm:<parameter-name>", "s3://<bucket>/<key>", "https://<url>" and "file:<path>" locations.
+// The ssm and s3 locations are read with the credentials of the common-config, like the config downloader does.
+type AwsFetcher struct {
+	Credentials *configaws.CredentialConfig
+
+	session client.ConfigProvider
+}
+
+func (f *AwsFetcher) Fetch(location string) ([]byte, error) {
+	switch {
+	case strings.HasPrefix(location, locationSSM):
+		return f.fetchFromSSM(strings.TrimPrefix(location, locationSSM))
+	case strings.HasPrefix(location, locationS3):
+		return f.fetchFromS3(strings.TrimPrefix(location, locationS3))
+	case strings.HasPrefix(location, locationHTTPS):
+		return fetchFromHTTPS(location)
+	case strings.HasPrefix(location, locationFile):
+		return ioutil.ReadFile(strings.TrimPrefix(location, locationFile))
+	}
+	return nil, fmt.Errorf("unsupported location %s", location)
+}
+
+func (f *AwsFetcher) awsSession() (client.ConfigProvider, error) {
+	if f.session != nil {
+		return f.session, nil
+	}
+	if f.Credentials == nil {
+		return nil, fmt.Errorf("no credentials to read the aws locations")
+	}
+	f.session = f.Credentials.Credentials()
+	return f.session, nil
+}
+
+func (f *AwsFetcher) fetchFromSSM(name string) ([]byte, error) {
+	ses, err := f.awsSession()
+	if err != nil {
+		return nil, err
+	}
+	output, err := ssm.New(ses).GetParameter(&ssm.GetParameterInput{
+		Name:           aws.String(name),
+		WithDecryption: aws.Bool(true),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get parameter %s: %v", name, err)
+	}
+	return []byte(aws.StringValue(output.Parameter.Value)), nil
+}
+
+func (f *AwsFetcher) fetchFromS3(path string) ([]byte, error) {
+	parts := strings.SplitN(path, "/", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return nil, fmt.Errorf("invalid s3 location s3://%s", path)
+	}
+	ses, err := f.awsSession()
+	if err != nil {
+		return nil, err
+	}
+	output, err := s3.New(ses).GetObject(&s3.GetObjectInput{
+		Bucket: aws.String(parts[0]),
+		Key:    aws.String(parts[1]),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get object s3://%s: %v", path, err)
+	}
+	defer output.Body.Close()
+	return ioutil.ReadAll(output.Body)
+}
+
+func fetchFromHTTPS(url string) ([]byte, error) {
+	client := &http.Client{Timeout: httpTimeout}
+	resp, err := client.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to get %s, status code %d", url, resp.StatusCode)
+	}
+	return ioutil.ReadAll(resp.Body)
+}
@@ -0,0 +1,143 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package selfupdate
+
+import (
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+const (
+	maxCanaryPercentage = 100
+)
+
+// Manifest describes the agent version approved for the fleet and where to get the package for each platform. It is
+// signed with the key of the packages, the detached signature is next to it.
+//
+//	{
+//	  "version": "1.247347.0",
+//	  "canary_percentage": 10,
+//	  "health_check_window": 300,
+//	  "packages": {
+//	    "linux_amd64": {
+//	      "url": "s3://bucket/amazon-cloudwatch-agent.rpm",
+//	      "sha256": "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08",
+//	      "signature_url": "s3://bucket/amazon-cloudwatch-agent.rpm.sig"
+//	    }
+//	  }
+//	}
+type Manifest struct {
+	Version           string             `json:"version"`
+	CanaryPercentage  *int               `json:"canary_percentage,omitempty"`
+	HealthCheckWindow int                `json:"health_check_window,omitempty"` // unit is second
+	Packages          map[string]Package `json:"packages"`
+}
+
+type Package struct {
+	Url          string `json:"url"`
+	Sha256       string `json:"sha256"`
+	SignatureUrl string `json:"signature_url"`
+}
+
+func ParseManifest(data []byte) (*Manifest, error) {
+	m := &Manifest{}
+	if err := json.Unmarshal(data, m); err != nil {
+		return nil, fmt.Errorf("invalid manifest: %v", err)
+	}
+	if m.Version == "" {
+		return nil, fmt.Errorf("invalid manifest: version is missing")
+	}
+	if m.CanaryPercentage != nil && (*m.CanaryPercentage < 0 || *m.CanaryPercentage > maxCanaryPercentage) {
+		return nil, fmt.Errorf("invalid manifest: canary_percentage %d is out of range [0, %d]", *m.CanaryPercentage, maxCanaryPercentage)
+	}
+	if m.HealthCheckWindow < 0 {
+		return nil, fmt.Errorf("invalid manifest: health_check_window %d is negative", m.HealthCheckWindow)
+	}
+	for platform, p := range m.Packages {
+		if p.Url == "" || p.Sha256 == "" {
+			return nil, fmt.Errorf("invalid manifest: url and sha256 are required for package %s", platform)
+		}
+	}
+	return m, nil
+}
+
+// PackageFor returns the package of the given platform, e.g. linux_amd64.
+func (m *Manifest) PackageFor(platform string) (Package, error) {
+	if p, ok := m.Packages[platform]; ok {
+		return p, nil
+	}
+	return Package{}, fmt.Errorf("no package for platform %s in manifest version %s", platform, m.Version)
+}
+
+// Canary returns the percentage of the fleet which should pick up the version, all hosts by default.
+func (m *Manifest) Canary() int {
+	if m.CanaryPercentage == nil {
+		return maxCanaryPercentage
+	}
+	return *m.CanaryPercentage
+}
+
+// InCanary decides if the host belongs to the canary group of the given version. The decision is stable for a
+// host and a version, so raising the percentage in the manifest only adds hosts to the group.
+func InCanary(hostID string, version string, percentage int) bool {
+	if percentage >= maxCanaryPercentage {
+		return true
+	}
+	if percentage <= 0 {
+		return false
+	}
+	h := fnv.New32a()
+	h.Write([]byte(hostID + "/" + version))
+	return int(h.Sum32()%maxCanaryPercentage) < percentage
+}
+
+func CurrentPlatform() string {
+	return runtime.GOOS + "_" + runtime.GOARCH
+}
+
+// CompareVersions compares the dot separated versions of the agent, e.g. 1.247347.0, by numeric part. The parts which
+// aren't numbers, like a commit hash, are compared as strings.
+func CompareVersions(a, b string) int {
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(as) || i < len(bs); i++ {
+		var x, y string
+		if i < len(as) {
+			x = as[i]
+		}
+		if i < len(bs) {
+			y = bs[i]
+		}
+		if c := comparePart(x, y); c != 0 {
+			return c
+		}
+	}
+	return 0
+}
+
+func comparePart(x, y string) int {
+	xn, xerr := strconv.ParseUint(x, 10, 64)
+	yn, yerr := strconv.ParseUint(y, 10, 64)
+	switch {
+	case xerr == nil && yerr == nil:
+		if xn < yn {
+			return -1
+		} else if xn > yn {
+			return 1
+		}
+		return 0
+	case x == "" || y == "":
+		// a missing part is lower, 1.2 < 1.2.0
+		if x == y {
+			return 0
+		} else if x == "" {
+			return -1
+		}
+		return 1
+	}
+	return strings.Compare(x, y)
+}
@@ -0,0 +1,67 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package selfupdate
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseManifest(t *testing.T) {
+	m, err := ParseManifest([]byte(`{"version":"1.2.3","canary_percentage":20,"packages":{"linux_amd64":{"url":"s3://b/k.rpm","sha256":"abc","signature_url":"s3://b/k.rpm.sig"}}}`))
+	assert.NoError(t, err)
+	assert.Equal(t, "1.2.3", m.Version)
+	assert.Equal(t, 20, m.Canary())
+	p, err := m.PackageFor("linux_amd64")
+	assert.NoError(t, err)
+	assert.Equal(t, "s3://b/k.rpm", p.Url)
+	_, err = m.PackageFor("windows_amd64")
+	assert.Error(t, err)
+}
+
+func TestParseManifestDefaultCanary(t *testing.T) {
+	m, err := ParseManifest([]byte(`{"version":"1.2.3"}`))
+	assert.NoError(t, err)
+	assert.Equal(t, 100, m.Canary())
+}
+
+func TestParseManifestInvalid(t *testing.T) {
+	for _, input := range []string{
+		`{`,
+		`{"canary_percentage":10}`,
+		`{"version":"1.2.3","canary_percentage":101}`,
+		`{"version":"1.2.3","health_check_window":-1}`,
+		`{"version":"1.2.3","packages":{"linux_amd64":{"url":"s3://b/k.rpm"}}}`,
+	} {
+		_, err := ParseManifest([]byte(input))
+		assert.Error(t, err, input)
+	}
+}
+
+func TestInCanary(t *testing.T) {
+	assert.True(t, InCanary("i-1", "1.2.3", 100))
+	assert.False(t, InCanary("i-1", "1.2.3", 0))
+
+	in := 0
+	for i := 0; i < 1000; i++ {
+		host := fmt.Sprintf("i-%d", i)
+		if InCanary(host, "1.2.3", 10) {
+			in++
+			// raising the percentage keeps the hosts already in the canary group
+			assert.True(t, InCanary(host, "1.2.3", 50))
+		}
+	}
+	assert.InDelta(t, 100, in, 40)
+}
+
+func TestCompareVersions(t *testing.T) {
+	assert.Equal(t, 0, CompareVersions("1.247347.0", "1.247347.0"))
+	assert.Equal(t, -1, CompareVersions("1.247347.0", "1.247348.0"))
+	assert.Equal(t, 1, CompareVersions("1.247347.10", "1.247347.9"))
+	assert.Equal(t, -1, CompareVersions("1", "1.247347.0"))
+	assert.Equal(t, 1, CompareVersions("1.247347.0", "1.247346.6b250880"))
+	assert.Equal(t, -1, CompareVersions("1.247347.6a", "1.247347.6b"))
+}
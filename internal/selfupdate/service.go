@@ -0,0 +1,144 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package selfupdate
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"time"
+
+	"github.com/aws/amazon-cloudwatch-agent/internal/handoff"
+)
+
+const (
+	statusRunning  = "running"
+	handoffTimeout = 30 * time.Second
+)
+
+type agentStatus struct {
+	Status  string `json:"status"`
+	Version string `json:"version"`
+}
+
+// ctlServiceManager restarts the agent and queries its status with amazon-cloudwatch-agent-ctl so the platform
+// service manager (systemd, upstart, launchd or the windows service control manager) stays in charge of the agent.
+type ctlServiceManager struct {
+	ctl        []string
+	installCmd func(packagePath string) *exec.Cmd
+	// the directory of the agent binaries which are backed up before the first update
+	binDir string
+	// the socket of the running agent, it replaces itself by the new binary instead of being restarted
+	handoffSocket string
+}
+
+func (s *ctlServiceManager) Install(packagePath string) error {
+	return run(s.installCmd(packagePath))
+}
+
+// Restart hands the agent off to the new binary when it serves the handoff socket, its listeners stay open, else
+// restarts it.
+func (s *ctlServiceManager) Restart() error {
+	if s.handoffSocket != "" {
+		err := handoff.Request(s.handoffSocket, handoffTimeout)
+		if err == nil {
+			return nil
+		}
+		log.Printf("W! Unable to hand off the agent, restarting it: %v", err)
+	}
+	if err := run(s.ctlCmd("-a", "stop")); err != nil {
+		return err
+	}
+	return run(s.ctlCmd("-a", "start"))
+}
+
+func (s *ctlServiceManager) Backup(dir string) error {
+	return copyDir(s.binDir, dir)
+}
+
+// Restore stops the agent before putting the binaries back, the running ones can't be replaced on windows.
+func (s *ctlServiceManager) Restore(dir string) error {
+	if err := run(s.ctlCmd("-a", "stop")); err != nil {
+		return err
+	}
+	if err := copyDir(dir, s.binDir); err != nil {
+		return err
+	}
+	return run(s.ctlCmd("-a", "start"))
+}
+
+func (s *ctlServiceManager) Healthy(version string) (bool, error) {
+	out, err := s.ctlCmd("-a", "status").Output()
+	if err != nil {
+		return false, fmt.Errorf("failed to query agent status: %v", err)
+	}
+	return parseStatus(out, version)
+}
+
+func (s *ctlServiceManager) ctlCmd(args ...string) *exec.Cmd {
+	return exec.Command(s.ctl[0], append(s.ctl[1:], args...)...)
+}
+
+func parseStatus(out []byte, version string) (bool, error) {
+	status := agentStatus{}
+	if err := json.Unmarshal(out, &status); err != nil {
+		return false, fmt.Errorf("invalid agent status %q: %v", string(out), err)
+	}
+	return status.Status == statusRunning && status.Version == version, nil
+}
+
+// copyDir copies the files of src to dst with their modes, each file is renamed into place.
+func copyDir(src, dst string) error {
+	return filepath.Walk(src, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+		if info.IsDir() {
+			return os.MkdirAll(target, 0755)
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+		return copyFile(path, target, info.Mode())
+	})
+}
+
+func copyFile(src, dst string, mode os.FileMode) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	tmp := dst + ".tmp"
+	out, err := os.OpenFile(tmp, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, mode)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		os.Remove(tmp)
+		return err
+	}
+	if err := out.Close(); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return os.Rename(tmp, dst)
+}
+
+func run(cmd *exec.Cmd) error {
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("%v failed: %v, output: %s", cmd.Args, err, string(out))
+	}
+	return nil
+}
@@ -0,0 +1,26 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+//go:build darwin
+// +build darwin
+
+package selfupdate
+
+import (
+	"os/exec"
+)
+
+const (
+	binDirDarwin  = "/opt/aws/amazon-cloudwatch-agent/bin"
+	ctlPathDarwin = binDirDarwin + "/amazon-cloudwatch-agent-ctl"
+)
+
+func NewServiceManager() ServiceManager {
+	return &ctlServiceManager{
+		ctl:    []string{ctlPathDarwin},
+		binDir: binDirDarwin,
+		installCmd: func(packagePath string) *exec.Cmd {
+			return exec.Command("installer", "-pkg", packagePath, "-target", "/")
+		},
+	}
+}
@@ -0,0 +1,33 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+//go:build linux
+// +build linux
+
+package selfupdate
+
+import (
+	"os/exec"
+	"strings"
+)
+
+const (
+	binDirLinux        = "/opt/aws/amazon-cloudwatch-agent/bin"
+	ctlPathLinux       = binDirLinux + "/amazon-cloudwatch-agent-ctl"
+	handoffSocketLinux = "/opt/aws/amazon-cloudwatch-agent/var/amazon-cloudwatch-agent.sock"
+)
+
+func NewServiceManager() ServiceManager {
+	return &ctlServiceManager{
+		ctl:           []string{ctlPathLinux},
+		binDir:        binDirLinux,
+		handoffSocket: handoffSocketLinux,
+		installCmd: func(packagePath string) *exec.Cmd {
+			if strings.HasSuffix(packagePath, ".deb") {
+				return exec.Command("dpkg", "-i", "-E", packagePath)
+			}
+			// --oldpackage is needed when rolling back to a lower version
+			return exec.Command("rpm", "-U", "--force", "--oldpackage", packagePath)
+		},
+	}
+}
@@ -0,0 +1,71 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+//go:build !windows
+// +build !windows
+
+package selfupdate
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/aws/amazon-cloudwatch-agent/internal/handoff"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRestartHandsOff(t *testing.T) {
+	dir, err := ioutil.TempDir("", "selfupdate")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	socket := filepath.Join(dir, "agent.sock")
+
+	// the ctl is only run when no agent serves the socket
+	s := &ctlServiceManager{ctl: []string{"false"}, handoffSocket: socket}
+	assert.Error(t, s.Restart())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	requests := make(chan struct{}, 1)
+	go handoff.Serve(ctx, socket, func() error {
+		requests <- struct{}{}
+		return nil
+	})
+	require.Eventually(t, func() bool {
+		_, err := os.Stat(socket)
+		return err == nil
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.NoError(t, s.Restart())
+	assert.Len(t, requests, 1)
+
+	// the agent is restarted when the socket is missing
+	s = &ctlServiceManager{ctl: []string{"true"}, handoffSocket: filepath.Join(dir, "missing.sock")}
+	assert.NoError(t, s.Restart())
+}
+
+func TestBackupRestore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "selfupdate")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	binDir := filepath.Join(dir, "bin")
+	require.NoError(t, os.MkdirAll(binDir, 0755))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(binDir, "amazon-cloudwatch-agent"), []byte("1.0.0"), 0755))
+
+	s := &ctlServiceManager{ctl: []string{"true"}, binDir: binDir}
+	backup := filepath.Join(dir, "backup")
+	require.NoError(t, s.Backup(backup))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(binDir, "amazon-cloudwatch-agent"), []byte("2.0.0"), 0755))
+
+	require.NoError(t, s.Restore(backup))
+	data, err := ioutil.ReadFile(filepath.Join(binDir, "amazon-cloudwatch-agent"))
+	require.NoError(t, err)
+	assert.Equal(t, "1.0.0", string(data))
+	info, err := os.Stat(filepath.Join(binDir, "amazon-cloudwatch-agent"))
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0755), info.Mode().Perm())
+}
@@ -0,0 +1,29 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+//go:build windows
+// +build windows
+
+package selfupdate
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+)
+
+const (
+	binDirWindows  = "Amazon\\AmazonCloudWatchAgent"
+	ctlPathWindows = binDirWindows + "\\amazon-cloudwatch-agent-ctl.ps1"
+)
+
+func NewServiceManager() ServiceManager {
+	ctl := filepath.Join(os.Getenv("ProgramFiles"), ctlPathWindows)
+	return &ctlServiceManager{
+		ctl:    []string{"powershell", "-NoProfile", "-ExecutionPolicy", "Bypass", "-File", ctl},
+		binDir: filepath.Join(os.Getenv("ProgramFiles"), binDirWindows),
+		installCmd: func(packagePath string) *exec.Cmd {
+			return exec.Command("msiexec", "/i", packagePath, "/qn", "/norestart")
+		},
+	}
+}
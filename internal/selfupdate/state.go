@@ -0,0 +1,62 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package selfupdate
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+const stateFileName = "selfupdate-state.json"
+
+// State is persisted between the runs so the previous package is available for rollback and a version which failed
+// the health check is not installed again. CurrentBackup holds the binaries of the installed version until the
+// updater has downloaded a package to roll back to.
+type State struct {
+	CurrentVersion  string   `json:"current_version"`
+	CurrentPackage  string   `json:"current_package"`
+	CurrentBackup   string   `json:"current_backup,omitempty"`
+	PreviousVersion string   `json:"previous_version"`
+	PreviousPackage string   `json:"previous_package"`
+	FailedVersions  []string `json:"failed_versions"`
+}
+
+func (s *State) hasFailed(version string) bool {
+	for _, v := range s.FailedVersions {
+		if v == version {
+			return true
+		}
+	}
+	return false
+}
+
+func loadState(dir string) (*State, error) {
+	data, err := ioutil.ReadFile(filepath.Join(dir, stateFileName))
+	if os.IsNotExist(err) {
+		return &State{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	s := &State{}
+	if err := json.Unmarshal(data, s); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func saveState(dir string, s *State) error {
+	data, err := json.MarshalIndent(s, "", "\t")
+	if err != nil {
+		return err
+	}
+	path := filepath.Join(dir, stateFileName)
+	tmp := path + ".tmp"
+	if err := ioutil.WriteFile(tmp, data, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
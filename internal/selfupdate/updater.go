@@ -0,0 +1,255 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package selfupdate
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"path"
+	"path/filepath"
+	"time"
+)
+
+const (
+	defaultHealthCheckWindow   = 5 * time.Minute
+	defaultHealthCheckInterval = 10 * time.Second
+	manifestSignatureSuffix    = ".sig"
+	backupDirName              = "backup"
+)
+
+// ServiceManager hands the package over to the service manager of the platform.
+type ServiceManager interface {
+	Install(packagePath string) error
+	Restart() error
+	// Backup copies the binaries of the installed agent to dir, Restore puts them back and restarts the agent. They
+	// are the rollback target when the package of the installed version was not downloaded by the updater.
+	Backup(dir string) error
+	Restore(dir string) error
+	// Healthy reports if the agent is running with the expected version.
+	Healthy(version string) (bool, error)
+}
+
+type Updater struct {
+	ManifestLocation string
+	// ManifestSignatureLocation is the detached signature of the manifest, ManifestLocation with a .sig suffix by
+	// default.
+	ManifestSignatureLocation string
+	PublicKey                 []byte
+	StateDir                  string
+	HostID                    string
+	CurrentVersion            string
+	Platform                  string
+
+	Fetcher             Fetcher
+	ServiceManager      ServiceManager
+	HealthCheckInterval time.Duration
+
+	sleep func(time.Duration)
+}
+
+// Run checks the signed manifest once, installs the approved version if it is newer and the host belongs to its canary
+// group, and rolls back to the previous package or the backed up binaries if the agent does not stay healthy within the
+// health check window.
+func (u *Updater) Run() error {
+	if len(u.PublicKey) == 0 {
+		return errors.New("public key is required to verify the packages")
+	}
+	data, err := u.Fetcher.Fetch(u.ManifestLocation)
+	if err != nil {
+		return fmt.Errorf("failed to fetch manifest: %v", err)
+	}
+	signature, err := u.Fetcher.Fetch(u.manifestSignatureLocation())
+	if err != nil {
+		return fmt.Errorf("failed to fetch manifest signature: %v", err)
+	}
+	if err := VerifySignature(data, signature, u.PublicKey); err != nil {
+		return fmt.Errorf("failed to verify manifest: %v", err)
+	}
+	m, err := ParseManifest(data)
+	if err != nil {
+		return err
+	}
+	state, err := loadState(u.StateDir)
+	if err != nil {
+		return fmt.Errorf("failed to load state: %v", err)
+	}
+	if state.CurrentVersion == "" {
+		state.CurrentVersion = u.CurrentVersion
+	}
+
+	if m.Version == state.CurrentVersion {
+		log.Printf("I! Agent is already on version %s", m.Version)
+		return nil
+	}
+	// a replayed manifest of an older version is signed too, it must not downgrade the agent
+	if CompareVersions(m.Version, state.CurrentVersion) < 0 {
+		return fmt.Errorf("manifest version %s is older than the installed version %s", m.Version, state.CurrentVersion)
+	}
+	if state.hasFailed(m.Version) {
+		log.Printf("I! Skip version %s as it failed the health check before", m.Version)
+		return nil
+	}
+	if !InCanary(u.HostID, m.Version, m.Canary()) {
+		log.Printf("I! Host is not in the %d%% canary group of version %s", m.Canary(), m.Version)
+		return nil
+	}
+
+	platform := u.Platform
+	if platform == "" {
+		platform = CurrentPlatform()
+	}
+	pkg, err := m.PackageFor(platform)
+	if err != nil {
+		return err
+	}
+	packagePath, err := u.download(m.Version, pkg)
+	if err != nil {
+		return err
+	}
+
+	if err := u.backup(state); err != nil {
+		return err
+	}
+	log.Printf("I! Updating agent from version %s to %s", state.CurrentVersion, m.Version)
+	if err := u.install(packagePath); err != nil {
+		return u.rollback(state, m.Version, err)
+	}
+	if err := u.waitHealthy(m.Version, healthCheckWindow(m)); err != nil {
+		return u.rollback(state, m.Version, err)
+	}
+
+	backupDir := state.CurrentBackup
+	state.PreviousVersion, state.PreviousPackage = state.CurrentVersion, state.CurrentPackage
+	state.CurrentVersion, state.CurrentPackage, state.CurrentBackup = m.Version, packagePath, ""
+	if err := saveState(u.StateDir, state); err != nil {
+		return fmt.Errorf("failed to save state: %v", err)
+	}
+	// the downloaded package is the rollback target from now on
+	if backupDir != "" {
+		os.RemoveAll(backupDir)
+	}
+	log.Printf("I! Agent is updated to version %s", m.Version)
+	return nil
+}
+
+func (u *Updater) manifestSignatureLocation() string {
+	if u.ManifestSignatureLocation != "" {
+		return u.ManifestSignatureLocation
+	}
+	return u.ManifestLocation + manifestSignatureSuffix
+}
+
+// backup copies the binaries of the installed agent before the first update, the updater has no package of the
+// installed version to roll back to then.
+func (u *Updater) backup(state *State) error {
+	if state.CurrentPackage != "" || state.CurrentBackup != "" {
+		return nil
+	}
+	dir := filepath.Join(u.StateDir, state.CurrentVersion, backupDirName)
+	if err := os.RemoveAll(dir); err != nil {
+		return err
+	}
+	if err := u.ServiceManager.Backup(dir); err != nil {
+		return fmt.Errorf("failed to back up the installed agent: %v", err)
+	}
+	state.CurrentBackup = dir
+	if err := saveState(u.StateDir, state); err != nil {
+		return fmt.Errorf("failed to save state: %v", err)
+	}
+	return nil
+}
+
+func (u *Updater) download(version string, pkg Package) (string, error) {
+	if pkg.SignatureUrl == "" {
+		return "", fmt.Errorf("signature_url is missing for version %s", version)
+	}
+	data, err := u.Fetcher.Fetch(pkg.Url)
+	if err != nil {
+		return "", fmt.Errorf("failed to download package: %v", err)
+	}
+	if err := VerifySha256(data, pkg.Sha256); err != nil {
+		return "", err
+	}
+	signature, err := u.Fetcher.Fetch(pkg.SignatureUrl)
+	if err != nil {
+		return "", fmt.Errorf("failed to download signature: %v", err)
+	}
+	if err := VerifySignature(data, signature, u.PublicKey); err != nil {
+		return "", err
+	}
+
+	dir := filepath.Join(u.StateDir, version)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return "", err
+	}
+	packagePath := filepath.Join(dir, path.Base(pkg.Url))
+	if err := ioutil.WriteFile(packagePath, data, 0600); err != nil {
+		return "", err
+	}
+	return packagePath, nil
+}
+
+func (u *Updater) install(packagePath string) error {
+	if err := u.ServiceManager.Install(packagePath); err != nil {
+		return fmt.Errorf("failed to install %s: %v", packagePath, err)
+	}
+	if err := u.ServiceManager.Restart(); err != nil {
+		return fmt.Errorf("failed to restart agent: %v", err)
+	}
+	return nil
+}
+
+func (u *Updater) waitHealthy(version string, window time.Duration) error {
+	interval := u.HealthCheckInterval
+	if interval <= 0 {
+		interval = defaultHealthCheckInterval
+	}
+	sleep := u.sleep
+	if sleep == nil {
+		sleep = time.Sleep
+	}
+	// The agent has to stay healthy for the whole window, a single failed check is a regression.
+	for elapsed := time.Duration(0); elapsed < window; elapsed += interval {
+		sleep(interval)
+		healthy, err := u.ServiceManager.Healthy(version)
+		if err != nil {
+			return fmt.Errorf("health check failed: %v", err)
+		}
+		if !healthy {
+			return fmt.Errorf("agent is not healthy on version %s", version)
+		}
+	}
+	return nil
+}
+
+func (u *Updater) rollback(state *State, version string, cause error) error {
+	log.Printf("E! Update to version %s failed, rolling back: %v", version, cause)
+	state.FailedVersions = append(state.FailedVersions, version)
+	if err := saveState(u.StateDir, state); err != nil {
+		log.Printf("E! Failed to save state: %v", err)
+	}
+	switch {
+	case state.CurrentPackage != "":
+		if err := u.install(state.CurrentPackage); err != nil {
+			return fmt.Errorf("%v, rollback to version %s failed: %v", cause, state.CurrentVersion, err)
+		}
+	case state.CurrentBackup != "":
+		if err := u.ServiceManager.Restore(state.CurrentBackup); err != nil {
+			return fmt.Errorf("%v, rollback to version %s failed: %v", cause, state.CurrentVersion, err)
+		}
+	default:
+		return fmt.Errorf("%v, no previous package to roll back to", cause)
+	}
+	return fmt.Errorf("%v, rolled back to version %s", cause, state.CurrentVersion)
+}
+
+func healthCheckWindow(m *Manifest) time.Duration {
+	if m.HealthCheckWindow > 0 {
+		return time.Duration(m.HealthCheckWindow) * time.Second
+	}
+	return defaultHealthCheckWindow
+}
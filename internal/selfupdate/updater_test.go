@@ -0,0 +1,192 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package selfupdate
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type mapFetcher map[string][]byte
+
+func (f mapFetcher) Fetch(location string) ([]byte, error) {
+	if data, ok := f[location]; ok {
+		return data, nil
+	}
+	return nil, fmt.Errorf("not found %s", location)
+}
+
+type fakeServiceManager struct {
+	installed []string
+	restarts  int
+	healthy   bool
+	backups   []string
+	restored  []string
+}
+
+func (s *fakeServiceManager) Install(packagePath string) error {
+	s.installed = append(s.installed, packagePath)
+	return nil
+}
+
+func (s *fakeServiceManager) Restart() error {
+	s.restarts++
+	return nil
+}
+
+func (s *fakeServiceManager) Backup(dir string) error {
+	s.backups = append(s.backups, dir)
+	return nil
+}
+
+func (s *fakeServiceManager) Restore(dir string) error {
+	s.restored = append(s.restored, dir)
+	return nil
+}
+
+func (s *fakeServiceManager) Healthy(version string) (bool, error) {
+	return s.healthy, nil
+}
+
+func newTestUpdater(t *testing.T, manifest string, sm ServiceManager) *Updater {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	data := []byte("new package")
+	digest := sha256.Sum256(data)
+	signature, err := ecdsa.SignASN1(rand.Reader, key, digest[:])
+	assert.NoError(t, err)
+	manifestData := []byte(fmt.Sprintf(manifest, hex.EncodeToString(digest[:])))
+	manifestDigest := sha256.Sum256(manifestData)
+	manifestSignature, err := ecdsa.SignASN1(rand.Reader, key, manifestDigest[:])
+	assert.NoError(t, err)
+
+	return &Updater{
+		ManifestLocation: "ssm:manifest",
+		PublicKey:        publicKeyPEM(t, &key.PublicKey),
+		StateDir:         t.TempDir(),
+		HostID:           "i-1234567890",
+		CurrentVersion:   "1.0.0",
+		Platform:         "linux_amd64",
+		Fetcher: mapFetcher{
+			"ssm:manifest":       manifestData,
+			"ssm:manifest.sig":   manifestSignature,
+			"s3://b/new.rpm":     data,
+			"s3://b/new.rpm.sig": signature,
+		},
+		ServiceManager:      sm,
+		HealthCheckInterval: time.Second,
+		sleep:               func(time.Duration) {},
+	}
+}
+
+const testManifest = `{"version":"2.0.0","health_check_window":3,"packages":{"linux_amd64":{"url":"s3://b/new.rpm","sha256":"%s","signature_url":"s3://b/new.rpm.sig"}}}`
+
+func TestUpdaterRun(t *testing.T) {
+	sm := &fakeServiceManager{healthy: true}
+	u := newTestUpdater(t, testManifest, sm)
+	assert.NoError(t, u.Run())
+	assert.Equal(t, []string{filepath.Join(u.StateDir, "2.0.0", "new.rpm")}, sm.installed)
+	assert.Equal(t, 1, sm.restarts)
+	// the installed binaries are backed up before the first update
+	assert.Equal(t, []string{filepath.Join(u.StateDir, "1.0.0", "backup")}, sm.backups)
+
+	state, err := loadState(u.StateDir)
+	assert.NoError(t, err)
+	assert.Equal(t, "2.0.0", state.CurrentVersion)
+	assert.Equal(t, "1.0.0", state.PreviousVersion)
+	assert.Empty(t, state.CurrentBackup)
+
+	// already on the version
+	assert.NoError(t, u.Run())
+	assert.Equal(t, 1, sm.restarts)
+}
+
+func TestUpdaterRollback(t *testing.T) {
+	sm := &fakeServiceManager{healthy: false}
+	u := newTestUpdater(t, testManifest, sm)
+	assert.NoError(t, saveState(u.StateDir, &State{CurrentVersion: "1.0.0", CurrentPackage: "/tmp/old.rpm"}))
+
+	assert.Error(t, u.Run())
+	assert.Equal(t, []string{filepath.Join(u.StateDir, "2.0.0", "new.rpm"), "/tmp/old.rpm"}, sm.installed)
+
+	state, err := loadState(u.StateDir)
+	assert.NoError(t, err)
+	assert.Equal(t, "1.0.0", state.CurrentVersion)
+	assert.Equal(t, []string{"2.0.0"}, state.FailedVersions)
+
+	// the failed version is not installed again
+	assert.NoError(t, u.Run())
+	assert.Len(t, sm.installed, 2)
+	assert.Empty(t, sm.backups)
+}
+
+func TestUpdaterRollbackFirstUpdate(t *testing.T) {
+	sm := &fakeServiceManager{healthy: false}
+	u := newTestUpdater(t, testManifest, sm)
+
+	assert.Error(t, u.Run())
+	backup := filepath.Join(u.StateDir, "1.0.0", "backup")
+	assert.Equal(t, []string{filepath.Join(u.StateDir, "2.0.0", "new.rpm")}, sm.installed)
+	assert.Equal(t, []string{backup}, sm.restored)
+
+	state, err := loadState(u.StateDir)
+	assert.NoError(t, err)
+	assert.Equal(t, "1.0.0", state.CurrentVersion)
+	assert.Equal(t, backup, state.CurrentBackup)
+}
+
+func TestUpdaterManifestSignature(t *testing.T) {
+	sm := &fakeServiceManager{healthy: true}
+	u := newTestUpdater(t, testManifest, sm)
+	u.Fetcher.(mapFetcher)["ssm:manifest"] = []byte(`{"version":"3.0.0"}`)
+	assert.Error(t, u.Run())
+	delete(u.Fetcher.(mapFetcher), "ssm:manifest.sig")
+	assert.Error(t, u.Run())
+	assert.Empty(t, sm.installed)
+}
+
+func TestUpdaterDowngrade(t *testing.T) {
+	sm := &fakeServiceManager{healthy: true}
+	u := newTestUpdater(t, testManifest, sm)
+	u.CurrentVersion = "2.1.0"
+	assert.Error(t, u.Run())
+	assert.Empty(t, sm.installed)
+}
+
+func TestUpdaterNotInCanary(t *testing.T) {
+	sm := &fakeServiceManager{healthy: true}
+	u := newTestUpdater(t, `{"version":"2.0.0","canary_percentage":0,"packages":{"linux_amd64":{"url":"s3://b/new.rpm","sha256":"%s","signature_url":"s3://b/new.rpm.sig"}}}`, sm)
+	assert.NoError(t, u.Run())
+	assert.Empty(t, sm.installed)
+}
+
+func TestUpdaterBadChecksum(t *testing.T) {
+	sm := &fakeServiceManager{healthy: true}
+	u := newTestUpdater(t, testManifest, sm)
+	u.Fetcher.(mapFetcher)["s3://b/new.rpm"] = []byte("tampered package")
+	assert.Error(t, u.Run())
+	assert.Empty(t, sm.installed)
+}
+
+func TestParseStatus(t *testing.T) {
+	healthy, err := parseStatus([]byte(`{"status": "running", "starttime": "", "version": "2.0.0"}`), "2.0.0")
+	assert.NoError(t, err)
+	assert.True(t, healthy)
+	healthy, err = parseStatus([]byte(`{"status": "running", "version": "1.0.0"}`), "2.0.0")
+	assert.NoError(t, err)
+	assert.False(t, healthy)
+	healthy, _ = parseStatus([]byte(`{"status": "stopped", "version": "2.0.0"}`), "2.0.0")
+	assert.False(t, healthy)
+	_, err = parseStatus([]byte(`garbage`), "2.0.0")
+	assert.Error(t, err)
+}
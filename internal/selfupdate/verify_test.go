@@ -0,0 +1,56 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package selfupdate
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/pem"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func publicKeyPEM(t *testing.T, key interface{}) []byte {
+	der, err := x509.MarshalPKIXPublicKey(key)
+	assert.NoError(t, err)
+	return pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+}
+
+func TestVerifySha256(t *testing.T) {
+	assert.NoError(t, VerifySha256([]byte("test"), "9F86D081884C7D659A2FEAA0C55AD015A3BF4F1B2B0B822CD15D6C15B0F00A08"))
+	assert.Error(t, VerifySha256([]byte("test2"), "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"))
+}
+
+func TestVerifySignatureRSA(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	data := []byte("package")
+	digest := sha256.Sum256(data)
+	signature, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	assert.NoError(t, err)
+
+	pub := publicKeyPEM(t, &key.PublicKey)
+	assert.NoError(t, VerifySignature(data, signature, pub))
+	assert.Error(t, VerifySignature([]byte("tampered"), signature, pub))
+}
+
+func TestVerifySignatureECDSA(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	data := []byte("package")
+	digest := sha256.Sum256(data)
+	signature, err := ecdsa.SignASN1(rand.Reader, key, digest[:])
+	assert.NoError(t, err)
+
+	pub := publicKeyPEM(t, &key.PublicKey)
+	assert.NoError(t, VerifySignature(data, signature, pub))
+	assert.Error(t, VerifySignature([]byte("tampered"), signature, pub))
+	assert.Error(t, VerifySignature(data, signature, []byte("not a key")))
+}
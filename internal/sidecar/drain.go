@@ -0,0 +1,128 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+// Package sidecar is the fast drain of the agent deployed as a sidecar. When the container is stopped, e.g. by the
+// SIGTERM of an ECS task stopping, the agent keeps publishing the logs the application writes until it stopped,
+// with sub-second batches and without looking for new sources, before it stops within the stop timeout of the
+// container.
+package sidecar
+
+import (
+	"context"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/amazon-cloudwatch-agent/cfg/envconfig"
+)
+
+const (
+	// DrainFlushInterval is the flush interval of the log batches during the drain.
+	DrainFlushInterval = 200 * time.Millisecond
+	// the drain ends when no event was published for this long
+	quietPeriod = time.Second
+	// below the 30s stop timeout of the ECS containers, so the outputs have the time to close
+	defaultDrainTimeout = 20 * time.Second
+)
+
+type drainer struct {
+	mu           sync.Mutex
+	started      chan struct{}
+	lastActivity time.Time
+	now          func() time.Time
+}
+
+func newDrainer() *drainer {
+	return &drainer{started: make(chan struct{}), now: time.Now}
+}
+
+var defaultDrainer = newDrainer()
+
+// Enabled returns whether the agent runs as a sidecar, one of its stop signals then starts a drain.
+func Enabled() bool {
+	return strings.EqualFold(os.Getenv(envconfig.CWAGENT_SIDECAR), "TRUE")
+}
+
+// DrainTimeout is the longest drain, CWAGENT_SIDECAR_DRAIN_TIMEOUT when it is a valid duration.
+func DrainTimeout() time.Duration {
+	if d, err := time.ParseDuration(os.Getenv(envconfig.CWAGENT_SIDECAR_DRAIN_TIMEOUT)); err == nil && d > 0 {
+		return d
+	}
+	return defaultDrainTimeout
+}
+
+// BeginDrain starts the drain, the channel of DrainStarted is closed.
+func BeginDrain() {
+	defaultDrainer.begin()
+}
+
+// DrainStarted returns the channel closed when the drain begins.
+func DrainStarted() <-chan struct{} {
+	return defaultDrainer.started
+}
+
+// Draining returns whether the drain began.
+func Draining() bool {
+	select {
+	case <-defaultDrainer.started:
+		return true
+	default:
+		return false
+	}
+}
+
+// Activity records that events were published, the drain goes on while they are.
+func Activity() {
+	if Draining() {
+		defaultDrainer.activity()
+	}
+}
+
+// WaitDrained returns when no event was published for a second, at the timeout, or when the context is done.
+func WaitDrained(ctx context.Context, timeout time.Duration) {
+	defaultDrainer.wait(ctx, timeout)
+}
+
+func (d *drainer) begin() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	select {
+	case <-d.started:
+	default:
+		d.lastActivity = d.now()
+		close(d.started)
+	}
+}
+
+func (d *drainer) activity() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.lastActivity = d.now()
+}
+
+// quietFor returns how long no event was published.
+func (d *drainer) quietFor() time.Duration {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.now().Sub(d.lastActivity)
+}
+
+func (d *drainer) wait(ctx context.Context, timeout time.Duration) {
+	deadline := time.NewTimer(timeout)
+	defer deadline.Stop()
+	ticker := time.NewTicker(quietPeriod / 10)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if d.quietFor() >= quietPeriod {
+				return
+			}
+		case <-deadline.C:
+			return
+		case <-ctx.Done():
+			return
+		}
+	}
+}
@@ -0,0 +1,80 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package sidecar
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/aws/amazon-cloudwatch-agent/cfg/envconfig"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEnabled(t *testing.T) {
+	defer os.Unsetenv(envconfig.CWAGENT_SIDECAR)
+	assert.False(t, Enabled())
+	os.Setenv(envconfig.CWAGENT_SIDECAR, "TRUE")
+	assert.True(t, Enabled())
+}
+
+func TestDrainTimeout(t *testing.T) {
+	defer os.Unsetenv(envconfig.CWAGENT_SIDECAR_DRAIN_TIMEOUT)
+	assert.Equal(t, defaultDrainTimeout, DrainTimeout())
+	os.Setenv(envconfig.CWAGENT_SIDECAR_DRAIN_TIMEOUT, "5s")
+	assert.Equal(t, 5*time.Second, DrainTimeout())
+	os.Setenv(envconfig.CWAGENT_SIDECAR_DRAIN_TIMEOUT, "-1s")
+	assert.Equal(t, defaultDrainTimeout, DrainTimeout())
+}
+
+func TestDrainer_Begin(t *testing.T) {
+	d := newDrainer()
+	d.begin()
+	// a second stop signal doesn't close the channel again
+	d.begin()
+	select {
+	case <-d.started:
+	default:
+		assert.Fail(t, "the drain did not start")
+	}
+}
+
+func TestDrainer_WaitQuiet(t *testing.T) {
+	now := time.Now()
+	d := newDrainer()
+	d.now = func() time.Time { return now }
+	d.begin()
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		// no event since the drain began
+		d.mu.Lock()
+		now = now.Add(quietPeriod)
+		d.mu.Unlock()
+	}()
+	start := time.Now()
+	d.wait(context.Background(), time.Minute)
+	assert.True(t, time.Since(start) < 10*time.Second)
+}
+
+func TestDrainer_WaitTimeout(t *testing.T) {
+	now := time.Now()
+	d := newDrainer()
+	d.now = func() time.Time { return now }
+	d.begin()
+	// the events keep being published
+	start := time.Now()
+	d.wait(context.Background(), 300*time.Millisecond)
+	assert.True(t, time.Since(start) >= 300*time.Millisecond)
+}
+
+func TestDrainer_WaitCanceled(t *testing.T) {
+	d := newDrainer()
+	d.now = func() time.Time { return time.Now().Add(-quietPeriod / 2) }
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	start := time.Now()
+	d.wait(ctx, time.Minute)
+	assert.True(t, time.Since(start) < 10*time.Second)
+}
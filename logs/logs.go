@@ -9,6 +9,7 @@ import (
 	"log"
 	"time"
 
+	"github.com/aws/amazon-cloudwatch-agent/internal/sidecar"
 	"github.com/influxdata/telegraf/config"
 )
 
@@ -54,14 +55,16 @@ type LogAgent struct {
 	Config      *config.Config
 	backends    map[string]LogBackend
 	destNames   map[LogDest]string
+	schedulers  map[LogDest]*scheduler
 	collections []LogCollection
 }
 
 func NewLogAgent(c *config.Config) *LogAgent {
 	return &LogAgent{
-		Config:    c,
-		backends:  make(map[string]LogBackend),
-		destNames: make(map[LogDest]string),
+		Config:     c,
+		backends:   make(map[string]LogBackend),
+		destNames:  make(map[LogDest]string),
+		schedulers: make(map[LogDest]*scheduler),
 	}
 }
 
@@ -95,6 +98,10 @@ func (l *LogAgent) Run(ctx context.Context) {
 	for {
 		select {
 		case <-t.C:
+			if sidecar.Draining() {
+				// the sidecar only publishes the sources it has during its drain
+				continue
+			}
 			for _, c := range l.collections {
 				srcs := c.FindLogSrc()
 				for _, src := range srcs {
@@ -106,8 +113,13 @@ func (l *LogAgent) Run(ctx context.Context) {
 					}
 					dest := backend.CreateDest(src.Group(), src.Stream(), src.Retention())
 					l.destNames[dest] = dname
+					sched, ok := l.schedulers[dest]
+					if !ok {
+						sched = &scheduler{}
+						l.schedulers[dest] = sched
+					}
 					log.Printf("I! [logagent] piping log from %v/%v(%v) to %v with retention %v", src.Group(), src.Stream(), src.Description(), dname, src.Retention())
-					go l.runSrcToDest(src, dest)
+					go l.runSrcToDest(src, dest, sched)
 				}
 			}
 		case <-ctx.Done():
@@ -116,9 +128,13 @@ func (l *LogAgent) Run(ctx context.Context) {
 	}
 }
 
-func (l *LogAgent) runSrcToDest(src LogSrc, dest LogDest) {
+// runSrcToDest publishes the events of the source to the destination, in turn with the other sources of the
+// destination. The source stays a candidate of the next turn while it has its next event, so the priorities of the
+// sources are only enforced when the destination is slower than them.
+func (l *LogAgent) runSrcToDest(src LogSrc, dest LogDest, sched *scheduler) {
 	eventsCh := make(chan LogEvent)
 	defer src.Stop()
+	turn := newScheduledSrc(src)
 
 	src.SetOutput(func(e LogEvent) {
 		if e == nil {
@@ -129,15 +145,34 @@ func (l *LogAgent) runSrcToDest(src LogSrc, dest LogDest) {
 		eventsCh <- e
 	})
 
-	for e := range eventsCh {
-		err := dest.Publish([]LogEvent{e})
-		if err == ErrOutputStopped {
-			log.Printf("I! [logagent] Log destination %v has stopped, finalizing %v/%v", l.destNames[dest], src.Group(), src.Stream())
+	for {
+		e, ok := <-eventsCh
+		if !ok {
 			return
 		}
-		if err != nil {
-			log.Printf("E! [logagent] Failed to publish log to %v, error: %v", l.destNames[dest], err)
-			return
+		sched.acquire(turn)
+		for ok {
+			err := dest.Publish([]LogEvent{e})
+			if err == ErrOutputStopped {
+				sched.release()
+				log.Printf("I! [logagent] Log destination %v has stopped, finalizing %v/%v", l.destNames[dest], src.Group(), src.Stream())
+				return
+			}
+			if err != nil {
+				sched.release()
+				log.Printf("E! [logagent] Failed to publish log to %v, error: %v", l.destNames[dest], err)
+				return
+			}
+			// the source keeps its turn in the round robin while its next event is ready
+			select {
+			case e, ok = <-eventsCh:
+				if ok {
+					sched.requeue(turn)
+				}
+			default:
+				ok = false
+			}
 		}
+		sched.release()
 	}
 }
@@ -0,0 +1,91 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package logs
+
+import (
+	"sync"
+)
+
+const defaultPriority = 1
+
+// A PrioritizedSrc is a LogSrc with a priority, the events of the sources sharing a destination are published in
+// proportion to their priority when the destination is slower than the sources, e.g. the audit logs before the bulk
+// logs while the destination retries.
+type PrioritizedSrc interface {
+	Priority() int
+}
+
+// scheduler orders the publishing of the sources of a destination, one event at a time, with a smooth weighted round
+// robin of the priorities of the sources waiting, instead of the arrival order of their events.
+type scheduler struct {
+	mu      sync.Mutex
+	busy    bool
+	waiting []*scheduledSrc
+}
+
+// scheduledSrc is the state of a source in the scheduler of its destination.
+type scheduledSrc struct {
+	weight int
+	// the credit of the source in the round robin, kept while it waits
+	current int
+	ready   chan struct{}
+}
+
+func newScheduledSrc(src LogSrc) *scheduledSrc {
+	weight := defaultPriority
+	if p, ok := src.(PrioritizedSrc); ok && p.Priority() > 0 {
+		weight = p.Priority()
+	}
+	return &scheduledSrc{weight: weight, ready: make(chan struct{}, 1)}
+}
+
+// acquire blocks until the source can publish, at once when no other source publishes.
+func (s *scheduler) acquire(src *scheduledSrc) {
+	s.mu.Lock()
+	if !s.busy {
+		s.busy = true
+		s.mu.Unlock()
+		return
+	}
+	s.waiting = append(s.waiting, src)
+	s.mu.Unlock()
+	<-src.ready
+}
+
+// release hands the destination to the next source waiting, if any.
+func (s *scheduler) release() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.waiting) == 0 {
+		s.busy = false
+		return
+	}
+	s.next()
+}
+
+// requeue hands the destination to the next source waiting, among which the source publishing has its next event,
+// and blocks until the source can publish it.
+func (s *scheduler) requeue(src *scheduledSrc) {
+	s.mu.Lock()
+	s.waiting = append(s.waiting, src)
+	s.next()
+	s.mu.Unlock()
+	<-src.ready
+}
+
+// next wakes up the source waiting with the highest credit once every source waiting is credited with its weight.
+func (s *scheduler) next() {
+	total, next := 0, 0
+	for i, w := range s.waiting {
+		w.current += w.weight
+		total += w.weight
+		if w.current > s.waiting[next].current {
+			next = i
+		}
+	}
+	src := s.waiting[next]
+	src.current -= total
+	s.waiting = append(s.waiting[:next], s.waiting[next+1:]...)
+	src.ready <- struct{}{}
+}
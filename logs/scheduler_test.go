@@ -0,0 +1,133 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package logs
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testEvent struct {
+	src string
+}
+
+func (e testEvent) Message() string { return e.src }
+func (e testEvent) Time() time.Time { return time.Time{} }
+func (e testEvent) Done()           {}
+
+// testSrc outputs events as fast as they are published, until it is stopped.
+type testSrc struct {
+	name     string
+	priority int
+	stop     chan struct{}
+}
+
+func (s *testSrc) SetOutput(fn func(LogEvent)) {
+	go func() {
+		for {
+			select {
+			case <-s.stop:
+				fn(nil)
+				return
+			default:
+				fn(testEvent{src: s.name})
+			}
+		}
+	}()
+}
+func (s *testSrc) Group() string       { return "G" }
+func (s *testSrc) Stream() string      { return "S" }
+func (s *testSrc) Destination() string { return "cloudwatchlogs" }
+func (s *testSrc) Description() string { return s.name }
+func (s *testSrc) Retention() int      { return -1 }
+func (s *testSrc) Priority() int       { return s.priority }
+func (s *testSrc) Stop()               {}
+
+// slowDest records the sources of the events it publishes, a millisecond each.
+type slowDest struct {
+	mu        sync.Mutex
+	published []string
+}
+
+func (d *slowDest) Publish(events []LogEvent) error {
+	time.Sleep(time.Millisecond)
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for _, e := range events {
+		d.published = append(d.published, e.Message())
+	}
+	return nil
+}
+
+func (d *slowDest) count(src string, from, to int) int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	n := 0
+	for _, p := range d.published[from:to] {
+		if p == src {
+			n++
+		}
+	}
+	return n
+}
+
+func (d *slowDest) len() int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return len(d.published)
+}
+
+func TestRunSrcToDest_Priority(t *testing.T) {
+	l := NewLogAgent(nil)
+	dest := &slowDest{}
+	sched := &scheduler{}
+	stop := make(chan struct{})
+	var wg sync.WaitGroup
+	for _, src := range []*testSrc{{name: "audit", priority: 3, stop: stop}, {name: "bulk", stop: stop}} {
+		wg.Add(1)
+		go func(src *testSrc) {
+			defer wg.Done()
+			l.runSrcToDest(src, dest, sched)
+		}(src)
+	}
+	for dest.len() < 210 {
+		time.Sleep(10 * time.Millisecond)
+	}
+	close(stop)
+	wg.Wait()
+
+	// both sources are ready all along after the first events, the audit events are published 3 times as often
+	assert.InDelta(t, 150, dest.count("audit", 10, 210), 10)
+}
+
+func TestScheduler_Weights(t *testing.T) {
+	s := &scheduler{}
+	a := &scheduledSrc{weight: 2, ready: make(chan struct{}, 1)}
+	b := &scheduledSrc{weight: 1, ready: make(chan struct{}, 1)}
+	current := &scheduledSrc{weight: 1, ready: make(chan struct{}, 1)}
+	s.acquire(current)
+	s.waiting = []*scheduledSrc{a, b}
+
+	var order []*scheduledSrc
+	for i := 0; i < 6; i++ {
+		s.release()
+		select {
+		case <-a.ready:
+			order = append(order, a)
+			s.waiting = append(s.waiting, a)
+		case <-b.ready:
+			order = append(order, b)
+			s.waiting = append(s.waiting, b)
+		}
+	}
+	assert.Equal(t, []*scheduledSrc{a, b, a, a, b, a}, order)
+
+	// the last source releasing frees the destination
+	s.waiting = nil
+	s.release()
+	assert.False(t, s.busy)
+}
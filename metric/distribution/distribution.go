@@ -3,6 +3,13 @@
 
 package distribution
 
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/influxdata/telegraf/selfstat"
+)
+
 type Distribution interface {
 	Maximum() float64
 
@@ -30,3 +37,90 @@ type Distribution interface {
 }
 
 var NewDistribution func() Distribution
+
+const (
+	// the distributions can't bucket the negative values, they are dropped by default, added as 0 with clamp, or only
+	// added to the statistics, the sample count, the sum, the minimum and the maximum, with track
+	NegativeValuesDrop  = "drop"
+	NegativeValuesClamp = "clamp"
+	NegativeValuesTrack = "track"
+)
+
+var negativeValuePolicy = NegativeValuesDrop
+
+// SetNegativeValuePolicy sets the policy the distributions apply to the negative values of their entries.
+func SetNegativeValuePolicy(policy string) error {
+	switch policy {
+	case "":
+		policy = NegativeValuesDrop
+	case NegativeValuesDrop, NegativeValuesClamp, NegativeValuesTrack:
+	default:
+		return fmt.Errorf("invalid negative value policy %q, it must be %s, %s or %s", policy, NegativeValuesDrop, NegativeValuesClamp, NegativeValuesTrack)
+	}
+	negativeValuePolicy = policy
+	return nil
+}
+
+// NegativeValue counts a negative value of an entry by policy in the negative_values stat of the distribution
+// measurement, and returns the policy the distribution applies to it.
+func NegativeValue() string {
+	policy := negativeValuePolicy
+	selfstat.Register("distribution", "negative_values", map[string]string{"policy": policy}).Incr(1)
+	return policy
+}
+
+// Serializable is a Distribution marshaled with Marshal, the distributions of its type are created by the
+// constructor registered for its TypeName.
+type Serializable interface {
+	Distribution
+	json.Marshaler
+	json.Unmarshaler
+
+	TypeName() string
+}
+
+var types = map[string]func() Distribution{}
+
+// RegisterType registers the constructor of the distributions of the type, it's called by the init of the packages
+// of the distributions.
+func RegisterType(name string, newDistribution func() Distribution) {
+	types[name] = newDistribution
+}
+
+type marshaled struct {
+	Type         string          `json:"type"`
+	Distribution json.RawMessage `json:"distribution"`
+}
+
+// Marshal returns the json of the distribution with its type, e.g. to keep it across restarts.
+func Marshal(d Distribution) ([]byte, error) {
+	s, ok := d.(Serializable)
+	if !ok {
+		return nil, fmt.Errorf("the distribution type %T can't be marshaled", d)
+	}
+	data, err := s.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(marshaled{Type: s.TypeName(), Distribution: data})
+}
+
+// Unmarshal returns the distribution marshaled by Marshal, its package must be imported.
+func Unmarshal(data []byte) (Distribution, error) {
+	var m marshaled
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, err
+	}
+	newDistribution, ok := types[m.Type]
+	if !ok {
+		return nil, fmt.Errorf("unknown distribution type %q", m.Type)
+	}
+	d, ok := newDistribution().(Serializable)
+	if !ok {
+		return nil, fmt.Errorf("the distribution type %q can't be unmarshaled", m.Type)
+	}
+	if err := d.UnmarshalJSON(m.Distribution); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
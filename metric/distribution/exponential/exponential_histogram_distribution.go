@@ -0,0 +1,370 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package exponential
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+
+	"github.com/aws/amazon-cloudwatch-agent/metric/distribution"
+)
+
+const (
+	// the scales of the OpenTelemetry exponential histograms, the base of the buckets is 2^(2^-scale)
+	MaxScale = 20
+	MinScale = -10
+
+	// the values of a datum of PutMetricData
+	DefaultMaxSize = 150
+)
+
+// ExponentialHistogramDistribution buckets the values like the base-2 exponential histograms of OpenTelemetry: the
+// bucket i of the scale covers (2^(i*2^-scale), 2^((i+1)*2^-scale)], and the zero values have their own bucket. The
+// scale starts at MaxScale and is lowered, merging the buckets by pairs, whenever there are more buckets than the
+// max size, so the histograms received over OTLP are added without re-bucketing them.
+type ExponentialHistogramDistribution struct {
+	maximum     float64
+	minimum     float64
+	sampleCount float64
+	sum         float64
+	scale       int32
+	zeroCount   float64
+	buckets     map[int32]float64 // from bucket index to the counter (i.e. weight)
+	unit        string
+	maxSize     int
+}
+
+func NewExponentialHistogramDistribution() distribution.Distribution {
+	return NewExponentialHistogramDistributionWithMaxSize(DefaultMaxSize)
+}
+
+// NewExponentialHistogramDistributionWithMaxSize returns a distribution of at most maxSize values, with the zero
+// bucket, e.g. the max_values_per_datum of the cloudwatch output.
+func NewExponentialHistogramDistributionWithMaxSize(maxSize int) distribution.Distribution {
+	if maxSize < 2 {
+		maxSize = 2
+	}
+	return &ExponentialHistogramDistribution{
+		maximum:     0, // negative number is not supported for now, so zero is the min value
+		minimum:     math.MaxFloat64,
+		sampleCount: 0,
+		sum:         0,
+		scale:       MaxScale,
+		buckets:     map[int32]float64{},
+		unit:        "",
+		maxSize:     maxSize,
+	}
+}
+
+// FromBuckets returns the distribution of the positive buckets and the zero count of an OpenTelemetry exponential
+// histogram point, counts[k] being the count of the bucket offset+k of the scale.
+func FromBuckets(scale int32, offset int32, counts []float64, zeroCount, sum, minimum, maximum float64) (*ExponentialHistogramDistribution, error) {
+	if scale < MinScale || scale > MaxScale {
+		return nil, fmt.Errorf("invalid scale %d, it must be between %d and %d", scale, MinScale, MaxScale)
+	}
+	if minimum < 0 {
+		return nil, errors.New("negative value")
+	}
+	d := NewExponentialHistogramDistribution().(*ExponentialHistogramDistribution)
+	d.scale = scale
+	for k, count := range counts {
+		if count > 0 {
+			d.buckets[offset+int32(k)] += count
+			d.sampleCount += count
+		}
+	}
+	if zeroCount > 0 {
+		d.zeroCount = zeroCount
+		d.sampleCount += zeroCount
+	}
+	if d.sampleCount == 0 {
+		return d, nil
+	}
+	d.sum = sum
+	d.minimum = minimum
+	d.maximum = maximum
+	d.fit()
+	return d, nil
+}
+
+func (d *ExponentialHistogramDistribution) Maximum() float64 {
+	return d.maximum
+}
+
+func (d *ExponentialHistogramDistribution) Minimum() float64 {
+	return d.minimum
+}
+
+func (d *ExponentialHistogramDistribution) SampleCount() float64 {
+	return d.sampleCount
+}
+
+func (d *ExponentialHistogramDistribution) Sum() float64 {
+	return d.sum
+}
+
+// Scale is the scale of the buckets, MaxScale until the buckets were merged to fit the max size.
+func (d *ExponentialHistogramDistribution) Scale() int32 {
+	return d.scale
+}
+
+// ValuesAndCounts returns the middle of each bucket, in the logarithmic scale, with its count.
+func (d *ExponentialHistogramDistribution) ValuesAndCounts() (values []float64, counts []float64) {
+	values = []float64{}
+	counts = []float64{}
+	if d.zeroCount > 0 {
+		values = append(values, 0)
+		counts = append(counts, d.zeroCount)
+	}
+	for index, counter := range d.buckets {
+		values = append(values, math.Exp2(math.Ldexp(float64(index)+0.5, -int(d.scale))))
+		counts = append(counts, counter)
+	}
+	return
+}
+
+func (d *ExponentialHistogramDistribution) Unit() string {
+	return d.unit
+}
+
+func (d *ExponentialHistogramDistribution) Size() int {
+	if d.zeroCount > 0 {
+		return len(d.buckets) + 1
+	}
+	return len(d.buckets)
+}
+
+// weight is 1/samplingRate
+func (d *ExponentialHistogramDistribution) AddEntryWithUnit(value float64, weight float64, unit string) error {
+	if weight > 0 {
+		factor, ok, err := distribution.ReconcileUnit(d.unit, unit)
+		if !ok {
+			return err
+		}
+		value *= factor
+
+		tracked := false
+		if value < 0 {
+			switch distribution.NegativeValue() {
+			case distribution.NegativeValuesClamp:
+				value = 0
+			case distribution.NegativeValuesTrack:
+				// the statistics include the value, the buckets can't
+				if d.sampleCount == 0 {
+					d.maximum = value
+				}
+				tracked = true
+			default:
+				return nil
+			}
+		}
+		if math.IsNaN(value) || math.IsInf(value, 0) {
+			return fmt.Errorf("invalid value %v", value)
+		}
+		//sample count
+		d.sampleCount += weight
+		//sum
+		d.sum += value * weight
+		//min
+		if value < d.minimum {
+			d.minimum = value
+		}
+		//max
+		if value > d.maximum {
+			d.maximum = value
+		}
+
+		if !tracked {
+			//buckets
+			if value == 0 {
+				d.zeroCount += weight
+			} else {
+				d.buckets[bucketIndex(value, d.scale)] += weight
+				d.fit()
+			}
+		}
+
+		//unit
+		if d.unit == "" {
+			d.unit = unit
+		}
+	} else {
+		log.Printf("D! Weight should be larger than 0: %v", weight)
+	}
+	return nil
+}
+
+// weight is 1/samplingRate
+func (d *ExponentialHistogramDistribution) AddEntry(value float64, weight float64) error {
+	return d.AddEntryWithUnit(value, weight, "")
+}
+
+func (d *ExponentialHistogramDistribution) AddDistribution(distribution distribution.Distribution) {
+	d.AddDistributionWithWeight(distribution, 1)
+}
+
+// AddDistributionWithWeight adds the buckets of the distribution at the lowest of both scales.
+func (d *ExponentialHistogramDistribution) AddDistributionWithWeight(from distribution.Distribution, weight float64) {
+	if from.SampleCount()*weight > 0 {
+		factor, ok, err := distribution.ReconcileUnit(d.unit, from.Unit())
+		if !ok {
+			if err != nil {
+				log.Printf("E! Unable to add the distribution: %v", err)
+			}
+			return
+		}
+		if factor != 1 {
+			from = distribution.Convert(from, factor, d.unit)
+		}
+
+		//buckets
+		if from, ok := from.(*ExponentialHistogramDistribution); ok {
+			if from.scale < d.scale {
+				d.downscale(d.scale - from.scale)
+			}
+			shift := from.scale - d.scale
+			for index, counter := range from.buckets {
+				d.buckets[index>>shift] += counter * weight
+			}
+			d.zeroCount += from.zeroCount * weight
+			d.fit()
+		} else {
+			// the other distributions, e.g. the ones converted to the unit, are bucketed by their values
+			values, counts := from.ValuesAndCounts()
+			for i := range values {
+				if values[i] == 0 {
+					d.zeroCount += counts[i] * weight
+				} else {
+					d.buckets[bucketIndex(values[i], d.scale)] += counts[i] * weight
+				}
+			}
+			d.fit()
+		}
+
+		//sample count
+		d.sampleCount += from.SampleCount() * weight
+		//sum
+		d.sum += from.Sum() * weight
+		//min
+		if from.Minimum() < d.minimum {
+			d.minimum = from.Minimum()
+		}
+		//max
+		if from.Maximum() > d.maximum {
+			d.maximum = from.Maximum()
+		}
+
+		//unit
+		if d.unit == "" {
+			d.unit = from.Unit()
+		}
+	} else {
+		log.Printf("D! SampleCount * Weight should be larger than 0: %v, %v", from.SampleCount(), weight)
+	}
+}
+
+// fit lowers the scale until the buckets fit the max size.
+func (d *ExponentialHistogramDistribution) fit() {
+	for d.Size() > d.maxSize && d.scale > MinScale {
+		d.downscale(1)
+	}
+}
+
+// downscale lowers the scale by change, each bucket is merged with the 2^change-1 following ones.
+func (d *ExponentialHistogramDistribution) downscale(change int32) {
+	if change > d.scale-MinScale {
+		change = d.scale - MinScale
+	}
+	if change <= 0 {
+		return
+	}
+	buckets := make(map[int32]float64, len(d.buckets))
+	for index, counter := range d.buckets {
+		buckets[index>>change] += counter
+	}
+	d.buckets = buckets
+	d.scale -= change
+}
+
+// bucketIndex returns the index of the bucket of the positive value at the scale, the exact powers of 2 are the
+// upper boundaries of their buckets like in OpenTelemetry.
+func bucketIndex(value float64, scale int32) int32 {
+	frac, exp := math.Frexp(value)
+	// value is frac*2^exp with frac in [0.5, 1), it's an exact power of 2 when frac is 0.5
+	if scale <= 0 {
+		index := int32(exp - 1)
+		if frac == 0.5 {
+			index--
+		}
+		return index >> -scale
+	}
+	if frac == 0.5 {
+		return (int32(exp-1) << scale) - 1
+	}
+	return int32(math.Ceil(math.Log(value)*math.Ldexp(math.Log2E, int(scale)))) - 1
+}
+
+const typeName = "exponential_histogram"
+
+func init() {
+	distribution.RegisterType(typeName, NewExponentialHistogramDistribution)
+}
+
+// exponentialState is the state of the distribution marshaled in json.
+type exponentialState struct {
+	Maximum     float64           `json:"maximum"`
+	Minimum     float64           `json:"minimum"`
+	SampleCount float64           `json:"sample_count"`
+	Sum         float64           `json:"sum"`
+	Scale       int32             `json:"scale"`
+	ZeroCount   float64           `json:"zero_count"`
+	Buckets     map[int32]float64 `json:"buckets"`
+	Unit        string            `json:"unit,omitempty"`
+	MaxSize     int               `json:"max_size"`
+}
+
+func (d *ExponentialHistogramDistribution) TypeName() string {
+	return typeName
+}
+
+func (d *ExponentialHistogramDistribution) MarshalJSON() ([]byte, error) {
+	return json.Marshal(exponentialState{
+		Maximum:     d.maximum,
+		Minimum:     d.minimum,
+		SampleCount: d.sampleCount,
+		Sum:         d.sum,
+		Scale:       d.scale,
+		ZeroCount:   d.zeroCount,
+		Buckets:     d.buckets,
+		Unit:        d.unit,
+		MaxSize:     d.maxSize,
+	})
+}
+
+func (d *ExponentialHistogramDistribution) UnmarshalJSON(data []byte) error {
+	var state exponentialState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return err
+	}
+	if state.Scale < MinScale || state.Scale > MaxScale {
+		return fmt.Errorf("invalid scale %d, it must be between %d and %d", state.Scale, MinScale, MaxScale)
+	}
+	if state.Buckets == nil {
+		state.Buckets = map[int32]float64{}
+	}
+	*d = *NewExponentialHistogramDistributionWithMaxSize(state.MaxSize).(*ExponentialHistogramDistribution)
+	d.maximum = state.Maximum
+	d.minimum = state.Minimum
+	d.sampleCount = state.SampleCount
+	d.sum = state.Sum
+	d.scale = state.Scale
+	d.zeroCount = state.ZeroCount
+	d.buckets = state.Buckets
+	d.unit = state.Unit
+	d.fit()
+	return nil
+}
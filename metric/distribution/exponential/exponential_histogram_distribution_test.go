@@ -0,0 +1,121 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package exponential
+
+import (
+	"math"
+	"testing"
+
+	"github.com/aws/amazon-cloudwatch-agent/metric/distribution"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExponentialHistogramDistribution(t *testing.T) {
+	dist := NewExponentialHistogramDistribution()
+
+	assert.NoError(t, dist.AddEntry(0, 1))
+	assert.NoError(t, dist.AddEntry(20, 1))
+	assert.NoError(t, dist.AddEntryWithUnit(50, 2, "Count"))
+	// the negative values are dropped by default
+	assert.NoError(t, dist.AddEntry(-1, 1))
+
+	assert.Equal(t, 120.0, dist.Sum())
+	assert.Equal(t, 4.0, dist.SampleCount())
+	assert.Equal(t, 0.0, dist.Minimum())
+	assert.Equal(t, 50.0, dist.Maximum())
+	assert.Equal(t, "Count", dist.Unit())
+	assert.Equal(t, 3, dist.Size())
+	assert.Equal(t, int32(MaxScale), dist.(*ExponentialHistogramDistribution).Scale())
+
+	values, counts := dist.ValuesAndCounts()
+	valuesCountsMap := map[float64]float64{}
+	for i := range values {
+		valuesCountsMap[values[i]] = counts[i]
+	}
+	assert.Equal(t, 1.0, valuesCountsMap[0])
+	for _, v := range values[1:] {
+		// the middle of the buckets of the highest scale are within 2^(2^-20) of the values
+		assert.True(t, math.Abs(v-20)/20 < 1e-6 || math.Abs(v-50)/50 < 1e-6, "value %v", v)
+	}
+
+	// the buckets of another scale are merged at the lowest one
+	other := NewExponentialHistogramDistributionWithMaxSize(2)
+	for _, v := range []float64{1, 3, 1000} {
+		assert.NoError(t, other.AddEntry(v, 1))
+	}
+	assert.Equal(t, 2, other.Size())
+	dist.AddDistributionWithWeight(other, 2)
+	assert.Equal(t, other.(*ExponentialHistogramDistribution).Scale(), dist.(*ExponentialHistogramDistribution).Scale())
+	assert.Equal(t, 10.0, dist.SampleCount())
+	assert.Equal(t, 2128.0, dist.Sum())
+	assert.Equal(t, 1000.0, dist.Maximum())
+}
+
+func TestBucketIndex(t *testing.T) {
+	tests := []struct {
+		value float64
+		scale int32
+		index int32
+	}{
+		// the exact powers of 2 are the upper boundaries of their buckets
+		{1, 0, -1},
+		{2, 0, 0},
+		{3, 0, 1},
+		{0.75, 0, -1},
+		{3, 1, 3},
+		{4, 1, 3},
+		{4.1, 1, 4},
+		{3, -1, 0},
+		{4, -1, 0},
+		{5, -1, 1},
+		{0.3, -1, -1},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.index, bucketIndex(test.value, test.scale), "value %v scale %d", test.value, test.scale)
+	}
+}
+
+func TestFromBuckets(t *testing.T) {
+	dist, err := FromBuckets(1, 2, []float64{3, 0, 1}, 1, 12, 0, 5)
+	assert.NoError(t, err)
+	assert.Equal(t, 5.0, dist.SampleCount())
+	assert.Equal(t, 3, dist.Size())
+	assert.Equal(t, map[int32]float64{2: 3, 4: 1}, dist.buckets)
+
+	// the values are the middle of their buckets, (2, 2.83] and (4, 5.66]
+	values, counts := dist.ValuesAndCounts()
+	valuesCountsMap := map[float64]float64{}
+	for i := range values {
+		valuesCountsMap[values[i]] = counts[i]
+	}
+	assert.Equal(t, map[float64]float64{0: 1, math.Exp2(1.25): 3, math.Exp2(2.25): 1}, valuesCountsMap)
+
+	_, err = FromBuckets(21, 0, nil, 0, 0, 0, 0)
+	assert.EqualError(t, err, "invalid scale 21, it must be between -10 and 20")
+}
+
+func TestExponentialHistogramDistribution_Marshal(t *testing.T) {
+	dist := NewExponentialHistogramDistribution()
+	assert.NoError(t, dist.AddEntryWithUnit(0.5, 2, "Seconds"))
+	assert.NoError(t, dist.AddEntry(20, 1))
+	assert.NoError(t, dist.AddEntry(300, 3))
+
+	data, err := distribution.Marshal(dist)
+	assert.NoError(t, err)
+	restored, err := distribution.Unmarshal(data)
+	assert.NoError(t, err)
+	assert.Equal(t, dist.Maximum(), restored.Maximum())
+	assert.Equal(t, dist.Minimum(), restored.Minimum())
+	assert.Equal(t, dist.SampleCount(), restored.SampleCount())
+	assert.Equal(t, dist.Sum(), restored.Sum())
+	assert.Equal(t, dist.Unit(), restored.Unit())
+	values, counts := dist.ValuesAndCounts()
+	restoredValues, restoredCounts := restored.ValuesAndCounts()
+	assert.ElementsMatch(t, values, restoredValues)
+	assert.ElementsMatch(t, counts, restoredCounts)
+
+	// the restored distribution keeps aggregating
+	assert.NoError(t, restored.AddEntry(20, 1))
+	assert.Equal(t, dist.SampleCount()+1, restored.SampleCount())
+}
@@ -4,6 +4,7 @@
 package regular
 
 import (
+	"encoding/json"
 	"errors"
 	"log"
 	"math"
@@ -68,8 +69,26 @@ func (regularDist *RegularDistribution) Size() int {
 // weight is 1/samplingRate
 func (regularDist *RegularDistribution) AddEntryWithUnit(value float64, weight float64, unit string) error {
 	if weight > 0 {
+		factor, ok, err := distribution.ReconcileUnit(regularDist.unit, unit)
+		if !ok {
+			return err
+		}
+		value *= factor
+
+		tracked := false
 		if value < 0 {
-			return errors.New("negative value")
+			switch distribution.NegativeValue() {
+			case distribution.NegativeValuesClamp:
+				value = 0
+			case distribution.NegativeValuesTrack:
+				// the statistics include the value, the values and counts can't
+				if regularDist.sampleCount == 0 {
+					regularDist.maximum = value
+				}
+				tracked = true
+			default:
+				return nil
+			}
 		}
 		//sample count
 		regularDist.sampleCount += weight
@@ -84,14 +103,14 @@ func (regularDist *RegularDistribution) AddEntryWithUnit(value float64, weight f
 			regularDist.maximum = value
 		}
 
-		//values and counts
-		regularDist.buckets[value] += weight
+		if !tracked {
+			//values and counts
+			regularDist.buckets[value] += weight
+		}
 
 		//unit
 		if regularDist.unit == "" {
 			regularDist.unit = unit
-		} else if regularDist.unit != unit && unit != "" {
-			log.Printf("D! Multiple units are detected: %s, %s", regularDist.unit, unit)
 		}
 	} else {
 		log.Printf("D! Weight should be larger than 0: %v", weight)
@@ -108,43 +127,112 @@ func (regularDist *RegularDistribution) AddDistribution(distribution distributio
 	regularDist.AddDistributionWithWeight(distribution, 1)
 }
 
-func (regularDist *RegularDistribution) AddDistributionWithWeight(distribution distribution.Distribution, weight float64) {
-	if distribution.SampleCount()*weight > 0 {
+func (regularDist *RegularDistribution) AddDistributionWithWeight(from distribution.Distribution, weight float64) {
+	if from.SampleCount()*weight > 0 {
+		factor, ok, err := distribution.ReconcileUnit(regularDist.unit, from.Unit())
+		if !ok {
+			if err != nil {
+				log.Printf("E! Unable to add the distribution: %v", err)
+			}
+			return
+		}
+		if factor != 1 {
+			from = distribution.Convert(from, factor, regularDist.unit)
+		}
 
 		//values and counts
-		if fromDistribution, ok := distribution.(*RegularDistribution); ok {
+		if fromDistribution, ok := from.(*RegularDistribution); ok {
 			for bucketNumber, bucketCounts := range fromDistribution.buckets {
 				regularDist.buckets[bucketNumber] += bucketCounts * weight
 			}
 		} else {
-			log.Printf("E! The from distribution type is not compatible with the to distribution type: from distribution type %T, to distribution type %T", regularDist, distribution)
-			return
+			// the other distributions, e.g. the ones converted to the unit, are added by their values
+			values, counts := from.ValuesAndCounts()
+			for i := range values {
+				regularDist.buckets[values[i]] += counts[i] * weight
+			}
 		}
 
 		//sample count
-		regularDist.sampleCount += distribution.SampleCount() * weight
+		regularDist.sampleCount += from.SampleCount() * weight
 		//sum
-		regularDist.sum += distribution.Sum() * weight
+		regularDist.sum += from.Sum() * weight
 		//min
-		if distribution.Minimum() < regularDist.minimum {
-			regularDist.minimum = distribution.Minimum()
+		if from.Minimum() < regularDist.minimum {
+			regularDist.minimum = from.Minimum()
 		}
 		//max
-		if distribution.Maximum() > regularDist.maximum {
-			regularDist.maximum = distribution.Maximum()
+		if from.Maximum() > regularDist.maximum {
+			regularDist.maximum = from.Maximum()
 		}
 
 		//unit
 		if regularDist.unit == "" {
-			regularDist.unit = distribution.Unit()
-		} else if regularDist.unit != distribution.Unit() && distribution.Unit() != "" {
-			log.Printf("D! Multiple units are dected: %s, %s", regularDist.unit, distribution.Unit())
+			regularDist.unit = from.Unit()
 		}
 	} else {
-		log.Printf("D! SampleCount * Weight should be larger than 0: %v, %v", distribution.SampleCount(), weight)
+		log.Printf("D! SampleCount * Weight should be larger than 0: %v, %v", from.SampleCount(), weight)
 	}
 }
 
 func (regularDist *RegularDistribution) GetCount(value float64) float64 {
 	return regularDist.buckets[value]
 }
+
+const typeName = "regular"
+
+func init() {
+	distribution.RegisterType(typeName, NewRegularDistribution)
+}
+
+// regularState is the state of the distribution marshaled in json, the values and their counts are lists since the
+// keys of json are strings.
+type regularState struct {
+	Maximum     float64   `json:"maximum"`
+	Minimum     float64   `json:"minimum"`
+	SampleCount float64   `json:"sample_count"`
+	Sum         float64   `json:"sum"`
+	Values      []float64 `json:"values"`
+	Counts      []float64 `json:"counts"`
+	Unit        string    `json:"unit,omitempty"`
+}
+
+func (regularDist *RegularDistribution) TypeName() string {
+	return typeName
+}
+
+func (regularDist *RegularDistribution) MarshalJSON() ([]byte, error) {
+	values, counts := regularDist.ValuesAndCounts()
+	return json.Marshal(regularState{
+		Maximum:     regularDist.maximum,
+		Minimum:     regularDist.minimum,
+		SampleCount: regularDist.sampleCount,
+		Sum:         regularDist.sum,
+		Values:      values,
+		Counts:      counts,
+		Unit:        regularDist.unit,
+	})
+}
+
+func (regularDist *RegularDistribution) UnmarshalJSON(data []byte) error {
+	var state regularState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return err
+	}
+	if len(state.Values) != len(state.Counts) {
+		return errors.New("the values and counts have different lengths")
+	}
+	buckets := make(map[float64]float64, len(state.Values))
+	for i, value := range state.Values {
+		buckets[value] += state.Counts[i]
+	}
+	*regularDist = RegularDistribution{
+		maximum:     state.Maximum,
+		minimum:     state.Minimum,
+		sampleCount: state.SampleCount,
+		sum:         state.Sum,
+		buckets:     buckets,
+		unit:        state.Unit,
+	}
+	return nil
+}
@@ -6,6 +6,7 @@ package regular
 import (
 	"testing"
 
+	"github.com/aws/amazon-cloudwatch-agent/metric/distribution"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -91,3 +92,28 @@ func cloneRegularDistribution(dist *RegularDistribution) *RegularDistribution {
 	}
 	return clonedDist
 }
+
+func TestRegularDistribution_Marshal(t *testing.T) {
+	dist := NewRegularDistribution()
+	assert.NoError(t, dist.AddEntryWithUnit(0.5, 2, "Seconds"))
+	assert.NoError(t, dist.AddEntry(20, 1))
+	assert.NoError(t, dist.AddEntry(300, 3))
+
+	data, err := distribution.Marshal(dist)
+	assert.NoError(t, err)
+	restored, err := distribution.Unmarshal(data)
+	assert.NoError(t, err)
+	assert.Equal(t, dist.Maximum(), restored.Maximum())
+	assert.Equal(t, dist.Minimum(), restored.Minimum())
+	assert.Equal(t, dist.SampleCount(), restored.SampleCount())
+	assert.Equal(t, dist.Sum(), restored.Sum())
+	assert.Equal(t, dist.Unit(), restored.Unit())
+	values, counts := dist.ValuesAndCounts()
+	restoredValues, restoredCounts := restored.ValuesAndCounts()
+	assert.ElementsMatch(t, values, restoredValues)
+	assert.ElementsMatch(t, counts, restoredCounts)
+
+	// the restored distribution keeps aggregating
+	assert.NoError(t, restored.AddEntry(20, 1))
+	assert.Equal(t, dist.SampleCount()+1, restored.SampleCount())
+}
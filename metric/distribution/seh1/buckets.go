@@ -0,0 +1,95 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package seh1
+
+import (
+	"sort"
+	"sync"
+)
+
+// buckets are the counters of the buckets sorted by bucket number. The sorted slices of the few tens of buckets of
+// a distribution take several times less memory than a map, and fewer objects for the garbage collector, which
+// matters on the hosts aggregating tens of thousands of distributions.
+type buckets struct {
+	numbers []int16
+	counts  []float64
+}
+
+// the buffers of the merges of the buckets, so they only grow the buckets they are merged to
+var mergeBuffers = sync.Pool{
+	New: func() interface{} {
+		return &buckets{}
+	},
+}
+
+func (b *buckets) len() int {
+	return len(b.numbers)
+}
+
+func (b *buckets) search(number int16) int {
+	return sort.Search(len(b.numbers), func(i int) bool { return b.numbers[i] >= number })
+}
+
+func (b *buckets) has(number int16) bool {
+	i := b.search(number)
+	return i < len(b.numbers) && b.numbers[i] == number
+}
+
+// add adds the count to the bucket, the bucket is inserted at its position when it's new.
+func (b *buckets) add(number int16, count float64) {
+	i := b.search(number)
+	if i < len(b.numbers) && b.numbers[i] == number {
+		b.counts[i] += count
+		return
+	}
+	b.numbers = append(b.numbers, 0)
+	b.counts = append(b.counts, 0)
+	copy(b.numbers[i+1:], b.numbers[i:])
+	copy(b.counts[i+1:], b.counts[i:])
+	b.numbers[i] = number
+	b.counts[i] = count
+}
+
+// merge adds the counts of the other buckets multiplied by the weight, in a single pass over both.
+func (b *buckets) merge(other *buckets, weight float64) {
+	merged := mergeBuffers.Get().(*buckets)
+	merged.numbers, merged.counts = merged.numbers[:0], merged.counts[:0]
+	i, j := 0, 0
+	for i < len(b.numbers) || j < len(other.numbers) {
+		switch {
+		case j == len(other.numbers) || (i < len(b.numbers) && b.numbers[i] < other.numbers[j]):
+			merged.numbers = append(merged.numbers, b.numbers[i])
+			merged.counts = append(merged.counts, b.counts[i])
+			i++
+		case i == len(b.numbers) || other.numbers[j] < b.numbers[i]:
+			merged.numbers = append(merged.numbers, other.numbers[j])
+			merged.counts = append(merged.counts, other.counts[j]*weight)
+			j++
+		default:
+			merged.numbers = append(merged.numbers, b.numbers[i])
+			merged.counts = append(merged.counts, b.counts[i]+other.counts[j]*weight)
+			i++
+			j++
+		}
+	}
+	b.numbers = append(b.numbers[:0], merged.numbers...)
+	b.counts = append(b.counts[:0], merged.counts...)
+	mergeBuffers.Put(merged)
+}
+
+func (b *buckets) toMap() map[int16]float64 {
+	m := make(map[int16]float64, len(b.numbers))
+	for i, number := range b.numbers {
+		m[number] = b.counts[i]
+	}
+	return m
+}
+
+func bucketsFromMap(m map[int16]float64) buckets {
+	var b buckets
+	for number, count := range m {
+		b.add(number, count)
+	}
+	return b
+}
@@ -0,0 +1,46 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package seh1
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuckets(t *testing.T) {
+	var b buckets
+	for _, number := range []int16{5, -3, 5, bucketForZero, 12, -3, 0} {
+		b.add(number, 1)
+	}
+	assert.Equal(t, []int16{bucketForZero, -3, 0, 5, 12}, b.numbers)
+	assert.Equal(t, []float64{1, 2, 1, 2, 1}, b.counts)
+	assert.True(t, b.has(12))
+	assert.False(t, b.has(6))
+
+	var other buckets
+	for _, number := range []int16{-4, 0, 12, 20} {
+		other.add(number, 1)
+	}
+	b.merge(&other, 2)
+	assert.Equal(t, []int16{bucketForZero, -4, -3, 0, 5, 12, 20}, b.numbers)
+	assert.Equal(t, []float64{1, 2, 2, 3, 2, 3, 2}, b.counts)
+	// the merged buckets are untouched
+	assert.Equal(t, []float64{1, 1, 1, 1}, other.counts)
+
+	b.merge(&b, 1)
+	assert.Equal(t, []float64{2, 4, 4, 6, 4, 6, 4}, b.counts)
+
+	assert.Equal(t, b, bucketsFromMap(b.toMap()))
+}
+
+func BenchmarkSEH1Distribution_AddEntry(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		dist := NewSEH1Distribution()
+		for j := 1; j <= 1000; j++ {
+			dist.AddEntry(float64(j), 1)
+		}
+	}
+}
@@ -4,6 +4,7 @@
 package seh1
 
 import (
+	"encoding/json"
 	"errors"
 	"log"
 	"math"
@@ -12,28 +13,68 @@ import (
 )
 
 var bucketForZero int16 = math.MinInt16
-var bucketFactor = math.Log(1 + 0.1)
+
+const (
+	// DefaultEpsilon is the growth of the buckets, each bucket is 10% wider than the previous one
+	DefaultEpsilon = 0.1
+	// the buckets of the values CloudWatch accepts, in [2^-360, 2^360], fit an int16 down to this growth
+	MinEpsilon = 0.01
+	MaxEpsilon = 1.0
+)
+
+var defaultBucketFactor = math.Log(1 + DefaultEpsilon)
 
 type SEH1Distribution struct {
 	maximum     float64
 	minimum     float64
 	sampleCount float64
 	sum         float64
-	buckets     map[int16]float64 // from bucket number (i.e. value) to the counter (i.e. weight)
+	buckets     buckets // the counters (i.e. weight) by bucket number (i.e. value)
 	unit        string
+	// the logarithm of 1+epsilon, the buckets are [(1+epsilon)^n, (1+epsilon)^(n+1))
+	bucketFactor float64
 }
 
 func NewSEH1Distribution() distribution.Distribution {
+	return newSEH1Distribution(defaultBucketFactor)
+}
+
+// NewSEH1DistributionWithEpsilon returns a distribution whose buckets grow by epsilon, between MinEpsilon and
+// MaxEpsilon. A lower epsilon has more buckets, whose values are closer to the values of the entries, e.g. 20.09
+// instead of 20.13 for 20 with 0.01.
+func NewSEH1DistributionWithEpsilon(epsilon float64) distribution.Distribution {
+	return newSEH1Distribution(math.Log(1 + ClampEpsilon(epsilon)))
+}
+
+func newSEH1Distribution(bucketFactor float64) *SEH1Distribution {
 	return &SEH1Distribution{
-		maximum:     0, // negative number is not supported for now, so zero is the min value
-		minimum:     math.MaxFloat64,
-		sampleCount: 0,
-		sum:         0,
-		buckets:     map[int16]float64{},
-		unit:        "",
+		maximum:      0, // negative number is not supported for now, so zero is the min value
+		minimum:      math.MaxFloat64,
+		sampleCount:  0,
+		sum:          0,
+		unit:         "",
+		bucketFactor: bucketFactor,
 	}
 }
 
+// ClampEpsilon returns the epsilon within MinEpsilon and MaxEpsilon, DefaultEpsilon when it's 0.
+func ClampEpsilon(epsilon float64) float64 {
+	switch {
+	case epsilon == 0:
+		return DefaultEpsilon
+	case epsilon < MinEpsilon:
+		return MinEpsilon
+	case epsilon > MaxEpsilon:
+		return MaxEpsilon
+	}
+	return epsilon
+}
+
+// Epsilon is the growth of the buckets.
+func (seh1Distribution *SEH1Distribution) Epsilon() float64 {
+	return math.Exp(seh1Distribution.bucketFactor) - 1
+}
+
 func (seh1Distribution *SEH1Distribution) Maximum() float64 {
 	return seh1Distribution.maximum
 }
@@ -51,18 +92,18 @@ func (seh1Distribution *SEH1Distribution) Sum() float64 {
 }
 
 func (seh1Distribution *SEH1Distribution) ValuesAndCounts() (values []float64, counts []float64) {
-	values = []float64{}
-	counts = []float64{}
-	for bucketNumber, counter := range seh1Distribution.buckets {
+	values = make([]float64, 0, seh1Distribution.buckets.len())
+	counts = make([]float64, 0, seh1Distribution.buckets.len())
+	for i, bucketNumber := range seh1Distribution.buckets.numbers {
 		var value float64
 		if bucketNumber == bucketForZero {
 			value = 0
 		} else {
 			// Add 0.5 to calculate exponent for the middle of the bin
-			value = math.Exp((float64(bucketNumber) + 0.5) * bucketFactor)
+			value = math.Exp((float64(bucketNumber) + 0.5) * seh1Distribution.bucketFactor)
 		}
 		values = append(values, value)
-		counts = append(counts, counter)
+		counts = append(counts, seh1Distribution.buckets.counts[i])
 	}
 	return
 }
@@ -72,14 +113,32 @@ func (seh1Distribution *SEH1Distribution) Unit() string {
 }
 
 func (seh1Distribution *SEH1Distribution) Size() int {
-	return len(seh1Distribution.buckets)
+	return seh1Distribution.buckets.len()
 }
 
 // weight is 1/samplingRate
 func (seh1Distribution *SEH1Distribution) AddEntryWithUnit(value float64, weight float64, unit string) error {
 	if weight > 0 {
+		factor, ok, err := distribution.ReconcileUnit(seh1Distribution.unit, unit)
+		if !ok {
+			return err
+		}
+		value *= factor
+
+		tracked := false
 		if value < 0 {
-			return errors.New("negative value")
+			switch distribution.NegativeValue() {
+			case distribution.NegativeValuesClamp:
+				value = 0
+			case distribution.NegativeValuesTrack:
+				// the statistics include the value, the buckets can't
+				if seh1Distribution.sampleCount == 0 {
+					seh1Distribution.maximum = value
+				}
+				tracked = true
+			default:
+				return nil
+			}
 		}
 		//sample count
 		seh1Distribution.sampleCount += weight
@@ -94,15 +153,15 @@ func (seh1Distribution *SEH1Distribution) AddEntryWithUnit(value float64, weight
 			seh1Distribution.maximum = value
 		}
 
-		//seh
-		bucketNumber := bucketNumber(value)
-		seh1Distribution.buckets[bucketNumber] += weight
+		if !tracked {
+			//seh
+			bucketNumber := bucketNumber(value, seh1Distribution.bucketFactor)
+			seh1Distribution.buckets.add(bucketNumber, weight)
+		}
 
 		//unit
 		if seh1Distribution.unit == "" {
 			seh1Distribution.unit = unit
-		} else if seh1Distribution.unit != unit && unit != "" {
-			log.Printf("D! Multiple units are detected: %s, %s", seh1Distribution.unit, unit)
 		}
 	} else {
 		log.Printf("D! Weight should be larger than 0: %v", weight)
@@ -119,40 +178,50 @@ func (seh1Distribution *SEH1Distribution) AddDistribution(distribution distribut
 	seh1Distribution.AddDistributionWithWeight(distribution, 1)
 }
 
-func (seh1Distribution *SEH1Distribution) AddDistributionWithWeight(distribution distribution.Distribution, weight float64) {
-	if distribution.SampleCount()*weight > 0 {
+func (seh1Distribution *SEH1Distribution) AddDistributionWithWeight(from distribution.Distribution, weight float64) {
+	if from.SampleCount()*weight > 0 {
+		factor, ok, err := distribution.ReconcileUnit(seh1Distribution.unit, from.Unit())
+		if !ok {
+			if err != nil {
+				log.Printf("E! Unable to add the distribution: %v", err)
+			}
+			return
+		}
+		if factor != 1 {
+			from = distribution.Convert(from, factor, seh1Distribution.unit)
+		}
 
 		//seh
-		if fromSEH1Distribution, ok := distribution.(*SEH1Distribution); ok {
-			for bucketNumber, bucketCounts := range fromSEH1Distribution.buckets {
-				seh1Distribution.buckets[bucketNumber] += bucketCounts * weight
-			}
+		if fromSEH1Distribution, ok := from.(*SEH1Distribution); ok && fromSEH1Distribution.bucketFactor == seh1Distribution.bucketFactor {
+			seh1Distribution.buckets.merge(&fromSEH1Distribution.buckets, weight)
 		} else {
-			log.Printf("E! The from distribution type is not compatible with the to distribution type: from distribution type %T, to distribution type %T", seh1Distribution, distribution)
-			return
+			// the buckets of another epsilon and the other distributions, e.g. the ones converted to the unit, are
+			// bucketed again by their values
+			values, counts := from.ValuesAndCounts()
+			for i := range values {
+				seh1Distribution.buckets.add(bucketNumber(values[i], seh1Distribution.bucketFactor), counts[i]*weight)
+			}
 		}
 
 		//sample count
-		seh1Distribution.sampleCount += distribution.SampleCount() * weight
+		seh1Distribution.sampleCount += from.SampleCount() * weight
 		//sum
-		seh1Distribution.sum += distribution.Sum() * weight
+		seh1Distribution.sum += from.Sum() * weight
 		//min
-		if distribution.Minimum() < seh1Distribution.minimum {
-			seh1Distribution.minimum = distribution.Minimum()
+		if from.Minimum() < seh1Distribution.minimum {
+			seh1Distribution.minimum = from.Minimum()
 		}
 		//max
-		if distribution.Maximum() > seh1Distribution.maximum {
-			seh1Distribution.maximum = distribution.Maximum()
+		if from.Maximum() > seh1Distribution.maximum {
+			seh1Distribution.maximum = from.Maximum()
 		}
 
 		//unit
 		if seh1Distribution.unit == "" {
-			seh1Distribution.unit = distribution.Unit()
-		} else if seh1Distribution.unit != distribution.Unit() && distribution.Unit() != "" {
-			log.Printf("D! Multiple units are dected: %s, %s", seh1Distribution.unit, distribution.Unit())
+			seh1Distribution.unit = from.Unit()
 		}
 	} else {
-		log.Printf("D! SampleCount * Weight should be larger than 0: %v, %v", distribution.SampleCount(), weight)
+		log.Printf("D! SampleCount * Weight should be larger than 0: %v, %v", from.SampleCount(), weight)
 	}
 }
 
@@ -160,14 +229,11 @@ func (seh1Distribution *SEH1Distribution) CanAdd(value float64, sizeLimit int) b
 	if seh1Distribution.Size() < sizeLimit {
 		return true
 	}
-	bucketNumber := bucketNumber(value)
-	if _, ok := seh1Distribution.buckets[bucketNumber]; ok {
-		return true
-	}
-	return false
+	bucketNumber := bucketNumber(value, seh1Distribution.bucketFactor)
+	return seh1Distribution.buckets.has(bucketNumber)
 }
 
-func bucketNumber(value float64) int16 {
+func bucketNumber(value float64, bucketFactor float64) int16 {
 	bucketNumber := bucketForZero
 	if value > 0 {
 		bucketNumber = int16(floor(math.Log(value) / bucketFactor))
@@ -175,7 +241,7 @@ func bucketNumber(value float64) int16 {
 	return bucketNumber
 }
 
-//This method is faster than math.Floor
+// This method is faster than math.Floor
 func floor(fvalue float64) int64 {
 	ivalue := int64(fvalue)
 	if fvalue < 0 && float64(ivalue) != fvalue {
@@ -183,3 +249,56 @@ func floor(fvalue float64) int64 {
 	}
 	return ivalue
 }
+
+const typeName = "seh1"
+
+func init() {
+	distribution.RegisterType(typeName, NewSEH1Distribution)
+}
+
+// seh1State is the state of the distribution marshaled in json.
+type seh1State struct {
+	Maximum      float64           `json:"maximum"`
+	Minimum      float64           `json:"minimum"`
+	SampleCount  float64           `json:"sample_count"`
+	Sum          float64           `json:"sum"`
+	Buckets      map[int16]float64 `json:"buckets"`
+	Unit         string            `json:"unit,omitempty"`
+	BucketFactor float64           `json:"bucket_factor"`
+}
+
+func (seh1Distribution *SEH1Distribution) TypeName() string {
+	return typeName
+}
+
+func (seh1Distribution *SEH1Distribution) MarshalJSON() ([]byte, error) {
+	return json.Marshal(seh1State{
+		Maximum:      seh1Distribution.maximum,
+		Minimum:      seh1Distribution.minimum,
+		SampleCount:  seh1Distribution.sampleCount,
+		Sum:          seh1Distribution.sum,
+		Buckets:      seh1Distribution.buckets.toMap(),
+		Unit:         seh1Distribution.unit,
+		BucketFactor: seh1Distribution.bucketFactor,
+	})
+}
+
+func (seh1Distribution *SEH1Distribution) UnmarshalJSON(data []byte) error {
+	var state seh1State
+	if err := json.Unmarshal(data, &state); err != nil {
+		return err
+	}
+	if state.BucketFactor <= 0 {
+		return errors.New("invalid bucket factor")
+	}
+	*seh1Distribution = SEH1Distribution{
+		maximum:      state.Maximum,
+		minimum:      state.Minimum,
+		sampleCount:  state.SampleCount,
+		sum:          state.Sum,
+		buckets:      bucketsFromMap(state.Buckets),
+		unit:         state.Unit,
+		bucketFactor: state.BucketFactor,
+	}
+	return nil
+}
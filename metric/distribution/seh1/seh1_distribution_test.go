@@ -6,6 +6,7 @@ package seh1
 import (
 	"testing"
 
+	"github.com/aws/amazon-cloudwatch-agent/metric/distribution"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -83,11 +84,92 @@ func cloneSEH1Distribution(dist *SEH1Distribution) *SEH1Distribution {
 		minimum:     dist.minimum,
 		sampleCount: dist.sampleCount,
 		sum:         dist.sum,
-		buckets:     map[int16]float64{},
-		unit:        dist.unit,
-	}
-	for k, v := range dist.buckets {
-		clonedDist.buckets[k] = v
+		buckets: buckets{
+			numbers: append([]int16(nil), dist.buckets.numbers...),
+			counts:  append([]float64(nil), dist.buckets.counts...),
+		},
+		unit:         dist.unit,
+		bucketFactor: dist.bucketFactor,
 	}
 	return clonedDist
 }
+
+func TestSEH1DistributionWithEpsilon(t *testing.T) {
+	dist := NewSEH1DistributionWithEpsilon(0.01)
+	assert.InDelta(t, 0.01, dist.(*SEH1Distribution).Epsilon(), 1e-12)
+	assert.NoError(t, dist.AddEntry(20, 1))
+	values, _ := dist.ValuesAndCounts()
+	assert.InEpsilon(t, 20, values[0], 0.005)
+
+	// the buckets of the default epsilon are bucketed again
+	other := NewSEH1Distribution()
+	assert.NoError(t, other.AddEntry(50, 2))
+	dist.AddDistribution(other)
+	assert.Equal(t, 3.0, dist.SampleCount())
+	values, counts := dist.ValuesAndCounts()
+	assert.Len(t, values, 2)
+	assert.ElementsMatch(t, []float64{1, 2}, counts)
+
+	assert.Equal(t, DefaultEpsilon, ClampEpsilon(0))
+	assert.Equal(t, MinEpsilon, ClampEpsilon(0.0001))
+	assert.Equal(t, MaxEpsilon, ClampEpsilon(5))
+}
+
+func TestSEH1Distribution_Marshal(t *testing.T) {
+	dist := NewSEH1DistributionWithEpsilon(0.05)
+	assert.NoError(t, dist.AddEntryWithUnit(0.5, 2, "Seconds"))
+	assert.NoError(t, dist.AddEntry(20, 1))
+	assert.NoError(t, dist.AddEntry(300, 3))
+
+	data, err := distribution.Marshal(dist)
+	assert.NoError(t, err)
+	restored, err := distribution.Unmarshal(data)
+	assert.NoError(t, err)
+	assert.Equal(t, dist.Maximum(), restored.Maximum())
+	assert.Equal(t, dist.Minimum(), restored.Minimum())
+	assert.Equal(t, dist.SampleCount(), restored.SampleCount())
+	assert.Equal(t, dist.Sum(), restored.Sum())
+	assert.Equal(t, dist.Unit(), restored.Unit())
+	values, counts := dist.ValuesAndCounts()
+	restoredValues, restoredCounts := restored.ValuesAndCounts()
+	assert.ElementsMatch(t, values, restoredValues)
+	assert.ElementsMatch(t, counts, restoredCounts)
+
+	// the restored distribution keeps aggregating
+	assert.NoError(t, restored.AddEntry(20, 1))
+	assert.Equal(t, dist.SampleCount()+1, restored.SampleCount())
+}
+
+func TestSEH1Distribution_NegativeValues(t *testing.T) {
+	defer distribution.SetNegativeValuePolicy(distribution.NegativeValuesDrop)
+	assert.EqualError(t, distribution.SetNegativeValuePolicy("abs"), `invalid negative value policy "abs", it must be drop, clamp or track`)
+
+	dist := NewSEH1Distribution()
+	assert.NoError(t, dist.AddEntry(-1, 1))
+	assert.Equal(t, 0.0, dist.SampleCount())
+	assert.Equal(t, 0, dist.Size())
+
+	assert.NoError(t, distribution.SetNegativeValuePolicy(distribution.NegativeValuesClamp))
+	dist = NewSEH1Distribution()
+	assert.NoError(t, dist.AddEntry(-1, 2))
+	assert.NoError(t, dist.AddEntry(10, 1))
+	assert.Equal(t, 3.0, dist.SampleCount())
+	assert.Equal(t, 10.0, dist.Sum())
+	assert.Equal(t, 0.0, dist.Minimum())
+	assert.Equal(t, 2, dist.Size())
+
+	assert.NoError(t, distribution.SetNegativeValuePolicy(distribution.NegativeValuesTrack))
+	dist = NewSEH1Distribution()
+	assert.NoError(t, dist.AddEntry(-5, 1))
+	assert.NoError(t, dist.AddEntry(-1, 1))
+	assert.Equal(t, 2.0, dist.SampleCount())
+	assert.Equal(t, -6.0, dist.Sum())
+	assert.Equal(t, -5.0, dist.Minimum())
+	assert.Equal(t, -1.0, dist.Maximum())
+	assert.Equal(t, 0, dist.Size())
+	assert.NoError(t, dist.AddEntry(10, 1))
+	assert.Equal(t, 10.0, dist.Maximum())
+	values, counts := dist.ValuesAndCounts()
+	assert.Equal(t, []float64{10.330486782497703}, values)
+	assert.Equal(t, []float64{1}, counts)
+}
@@ -0,0 +1,153 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package distribution
+
+import (
+	"fmt"
+	"sync"
+	"unsafe"
+)
+
+// SynchronizedDistribution guards a distribution with a lock, so the goroutines of an input, e.g. the statsd workers,
+// can add entries to the distribution of the same series without locking it themselves.
+type SynchronizedDistribution struct {
+	mu           sync.RWMutex
+	distribution Distribution
+}
+
+// NewSynchronizedDistribution guards the distribution, it must not be used other than through the returned one.
+func NewSynchronizedDistribution(d Distribution) *SynchronizedDistribution {
+	return &SynchronizedDistribution{distribution: d}
+}
+
+// NewSynchronized returns a constructor of the distributions of newDistribution guarded by a lock.
+func NewSynchronized(newDistribution func() Distribution) func() Distribution {
+	return func() Distribution {
+		return NewSynchronizedDistribution(newDistribution())
+	}
+}
+
+// Unwrap returns the guarded distribution, for the readers once the writers are done with it, e.g. the outputs.
+func (s *SynchronizedDistribution) Unwrap() Distribution {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.distribution
+}
+
+func (s *SynchronizedDistribution) Maximum() float64 {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.distribution.Maximum()
+}
+
+func (s *SynchronizedDistribution) Minimum() float64 {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.distribution.Minimum()
+}
+
+func (s *SynchronizedDistribution) SampleCount() float64 {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.distribution.SampleCount()
+}
+
+func (s *SynchronizedDistribution) Sum() float64 {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.distribution.Sum()
+}
+
+// ValuesAndCounts holds the write lock since some distributions, e.g. the t-digests, merge their entries first.
+func (s *SynchronizedDistribution) ValuesAndCounts() ([]float64, []float64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.distribution.ValuesAndCounts()
+}
+
+func (s *SynchronizedDistribution) Unit() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.distribution.Unit()
+}
+
+func (s *SynchronizedDistribution) Size() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.distribution.Size()
+}
+
+func (s *SynchronizedDistribution) AddEntryWithUnit(value float64, weight float64, unit string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.distribution.AddEntryWithUnit(value, weight, unit)
+}
+
+func (s *SynchronizedDistribution) AddEntry(value float64, weight float64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.distribution.AddEntry(value, weight)
+}
+
+func (s *SynchronizedDistribution) AddDistribution(distribution Distribution) {
+	s.AddDistributionWithWeight(distribution, 1)
+}
+
+// AddDistributionWithWeight locks both distributions when the added one is synchronized as well, always in the same
+// order so two distributions added to each other concurrently don't deadlock.
+func (s *SynchronizedDistribution) AddDistributionWithWeight(distribution Distribution, weight float64) {
+	other, ok := distribution.(*SynchronizedDistribution)
+	if !ok {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.distribution.AddDistributionWithWeight(distribution, weight)
+		return
+	}
+	if other == s {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.distribution.AddDistributionWithWeight(s.distribution, weight)
+		return
+	}
+	// the write lock of the other distribution is held since reading it may merge its entries
+	first, second := s, other
+	if uintptr(unsafe.Pointer(other)) < uintptr(unsafe.Pointer(s)) {
+		first, second = other, s
+	}
+	first.mu.Lock()
+	defer first.mu.Unlock()
+	second.mu.Lock()
+	defer second.mu.Unlock()
+	s.distribution.AddDistributionWithWeight(other.distribution, weight)
+}
+
+// TypeName is the type of the guarded distribution, it is restored by Unmarshal without the lock.
+func (s *SynchronizedDistribution) TypeName() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if serializable, ok := s.distribution.(Serializable); ok {
+		return serializable.TypeName()
+	}
+	return ""
+}
+
+func (s *SynchronizedDistribution) MarshalJSON() ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	serializable, ok := s.distribution.(Serializable)
+	if !ok {
+		return nil, fmt.Errorf("the distribution type %T can't be marshaled", s.distribution)
+	}
+	return serializable.MarshalJSON()
+}
+
+func (s *SynchronizedDistribution) UnmarshalJSON(data []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	serializable, ok := s.distribution.(Serializable)
+	if !ok {
+		return fmt.Errorf("the distribution type %T can't be unmarshaled", s.distribution)
+	}
+	return serializable.UnmarshalJSON(data)
+}
@@ -0,0 +1,60 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package distribution_test
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/aws/amazon-cloudwatch-agent/metric/distribution"
+	"github.com/aws/amazon-cloudwatch-agent/metric/distribution/regular"
+	"github.com/aws/amazon-cloudwatch-agent/metric/distribution/seh1"
+	"github.com/aws/amazon-cloudwatch-agent/metric/distribution/tdigest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSynchronizedDistribution_ConcurrentWriters(t *testing.T) {
+	for _, newDistribution := range []func() distribution.Distribution{regular.NewRegularDistribution, seh1.NewSEH1Distribution, tdigest.NewTDigestDistribution} {
+		dist := distribution.NewSynchronized(newDistribution)()
+		other := distribution.NewSynchronizedDistribution(newDistribution())
+		var wg sync.WaitGroup
+		for i := 0; i < 8; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				for j := 1; j <= 1000; j++ {
+					assert.NoError(t, dist.AddEntryWithUnit(float64(j), 1, "Milliseconds"))
+					dist.ValuesAndCounts()
+					if j%100 == 0 {
+						assert.NoError(t, other.AddEntry(float64(j), 1))
+						// the distributions are added to each other concurrently
+						if i%2 == 0 {
+							dist.AddDistribution(other)
+						} else {
+							other.AddDistribution(dist)
+						}
+					}
+				}
+			}(i)
+		}
+		wg.Wait()
+		assert.GreaterOrEqual(t, dist.SampleCount(), 8000.0)
+		assert.Equal(t, 1.0, dist.Minimum())
+		assert.Equal(t, 1000.0, dist.Maximum())
+		assert.Equal(t, "Milliseconds", dist.Unit())
+	}
+}
+
+func TestSynchronizedDistribution_Marshal(t *testing.T) {
+	dist := distribution.NewSynchronizedDistribution(seh1.NewSEH1Distribution())
+	assert.NoError(t, dist.AddEntry(20, 2))
+	data, err := distribution.Marshal(dist)
+	assert.NoError(t, err)
+	restored, err := distribution.Unmarshal(data)
+	assert.NoError(t, err)
+	assert.IsType(t, &seh1.SEH1Distribution{}, restored)
+	assert.Equal(t, 2.0, restored.SampleCount())
+	assert.Equal(t, 40.0, restored.Sum())
+	assert.Equal(t, dist.Unwrap().Size(), restored.Size())
+}
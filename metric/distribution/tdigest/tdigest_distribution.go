@@ -0,0 +1,353 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package tdigest
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"math"
+	"sort"
+
+	"github.com/aws/amazon-cloudwatch-agent/metric/distribution"
+)
+
+const (
+	// DefaultCompression bounds the centroids to about 100, the error of the quantiles is about 1/compression in
+	// the middle and much lower near the tails, e.g. for the p99.9
+	DefaultCompression = 100
+	// the values added before they are merged into the centroids, by compression
+	bufferFactor = 5
+)
+
+type centroid struct {
+	mean   float64
+	weight float64
+}
+
+// TDigestDistribution is a merging t-digest of the values: the values are merged into centroids whose weights are
+// bounded by the k3 scale function, so the centroids near the minimum and the maximum keep few values and the high
+// quantiles are accurate. There are at most compression centroids.
+type TDigestDistribution struct {
+	maximum     float64
+	minimum     float64
+	sampleCount float64
+	sum         float64
+	compression float64
+	centroids   []centroid // merged, sorted by mean
+	unmerged    []centroid
+	unit        string
+}
+
+func NewTDigestDistribution() distribution.Distribution {
+	return NewTDigestDistributionWithCompression(DefaultCompression)
+}
+
+// NewTDigestDistributionWithCompression returns a t-digest of at most compression centroids, the default
+// compression when it's lower than 1.
+func NewTDigestDistributionWithCompression(compression float64) distribution.Distribution {
+	if compression < 1 {
+		compression = DefaultCompression
+	}
+	return &TDigestDistribution{
+		maximum:     0, // negative number is not supported for now, so zero is the min value
+		minimum:     math.MaxFloat64,
+		sampleCount: 0,
+		sum:         0,
+		compression: compression,
+		unit:        "",
+	}
+}
+
+func (d *TDigestDistribution) Maximum() float64 {
+	return d.maximum
+}
+
+func (d *TDigestDistribution) Minimum() float64 {
+	return d.minimum
+}
+
+func (d *TDigestDistribution) SampleCount() float64 {
+	return d.sampleCount
+}
+
+func (d *TDigestDistribution) Sum() float64 {
+	return d.sum
+}
+
+// ValuesAndCounts returns the means of the centroids with their weights, the values added since the last merge are
+// merged first.
+func (d *TDigestDistribution) ValuesAndCounts() (values []float64, counts []float64) {
+	d.merge()
+	values = make([]float64, 0, len(d.centroids))
+	counts = make([]float64, 0, len(d.centroids))
+	for _, c := range d.centroids {
+		values = append(values, c.mean)
+		counts = append(counts, c.weight)
+	}
+	return
+}
+
+func (d *TDigestDistribution) Unit() string {
+	return d.unit
+}
+
+// Size is the number of centroids once the values added since the last merge are merged.
+func (d *TDigestDistribution) Size() int {
+	d.merge()
+	return len(d.centroids)
+}
+
+// weight is 1/samplingRate
+func (d *TDigestDistribution) AddEntryWithUnit(value float64, weight float64, unit string) error {
+	if weight > 0 {
+		factor, ok, err := distribution.ReconcileUnit(d.unit, unit)
+		if !ok {
+			return err
+		}
+		value *= factor
+
+		tracked := false
+		if value < 0 {
+			switch distribution.NegativeValue() {
+			case distribution.NegativeValuesClamp:
+				value = 0
+			case distribution.NegativeValuesTrack:
+				// the statistics include the value, the centroids can't
+				if d.sampleCount == 0 {
+					d.maximum = value
+				}
+				tracked = true
+			default:
+				return nil
+			}
+		}
+		if math.IsNaN(value) || math.IsInf(value, 0) {
+			return fmt.Errorf("invalid value %v", value)
+		}
+		//sample count
+		d.sampleCount += weight
+		//sum
+		d.sum += value * weight
+		//min
+		if value < d.minimum {
+			d.minimum = value
+		}
+		//max
+		if value > d.maximum {
+			d.maximum = value
+		}
+
+		if !tracked {
+			//centroids
+			d.add(centroid{mean: value, weight: weight})
+		}
+
+		//unit
+		if d.unit == "" {
+			d.unit = unit
+		}
+	} else {
+		log.Printf("D! Weight should be larger than 0: %v", weight)
+	}
+	return nil
+}
+
+// weight is 1/samplingRate
+func (d *TDigestDistribution) AddEntry(value float64, weight float64) error {
+	return d.AddEntryWithUnit(value, weight, "")
+}
+
+func (d *TDigestDistribution) AddDistribution(distribution distribution.Distribution) {
+	d.AddDistributionWithWeight(distribution, 1)
+}
+
+// AddDistributionWithWeight merges the centroids of a t-digest, or the values and counts of another distribution as
+// centroids, so the distributions of any type are merged.
+func (d *TDigestDistribution) AddDistributionWithWeight(from distribution.Distribution, weight float64) {
+	if from.SampleCount()*weight > 0 {
+		factor, ok, err := distribution.ReconcileUnit(d.unit, from.Unit())
+		if !ok {
+			if err != nil {
+				log.Printf("E! Unable to add the distribution: %v", err)
+			}
+			return
+		}
+		if factor != 1 {
+			from = distribution.Convert(from, factor, d.unit)
+		}
+
+		//centroids
+		values, counts := from.ValuesAndCounts()
+		for i := range values {
+			d.add(centroid{mean: values[i], weight: counts[i] * weight})
+		}
+
+		//sample count
+		d.sampleCount += from.SampleCount() * weight
+		//sum
+		d.sum += from.Sum() * weight
+		//min
+		if from.Minimum() < d.minimum {
+			d.minimum = from.Minimum()
+		}
+		//max
+		if from.Maximum() > d.maximum {
+			d.maximum = from.Maximum()
+		}
+
+		//unit
+		if d.unit == "" {
+			d.unit = from.Unit()
+		}
+	} else {
+		log.Printf("D! SampleCount * Weight should be larger than 0: %v, %v", from.SampleCount(), weight)
+	}
+}
+
+// Quantile estimates the value of the quantile q, between 0 and 1, interpolating between the centroids and the
+// minimum and maximum.
+func (d *TDigestDistribution) Quantile(q float64) float64 {
+	d.merge()
+	if len(d.centroids) == 0 {
+		return 0
+	}
+	if q <= 0 {
+		return d.minimum
+	}
+	if q >= 1 {
+		return d.maximum
+	}
+	total := d.totalWeight()
+	target := q * total
+	cumulative := 0.0
+	for i, c := range d.centroids {
+		middle := cumulative + c.weight/2
+		if target < middle {
+			if i == 0 {
+				return d.minimum + (c.mean-d.minimum)*target/middle
+			}
+			previous := d.centroids[i-1]
+			previousMiddle := cumulative - previous.weight/2
+			return previous.mean + (c.mean-previous.mean)*(target-previousMiddle)/(middle-previousMiddle)
+		}
+		cumulative += c.weight
+	}
+	last := d.centroids[len(d.centroids)-1]
+	lastMiddle := total - last.weight/2
+	return last.mean + (d.maximum-last.mean)*(target-lastMiddle)/(total-lastMiddle)
+}
+
+func (d *TDigestDistribution) add(c centroid) {
+	d.unmerged = append(d.unmerged, c)
+	if float64(len(d.unmerged)) >= bufferFactor*d.compression {
+		d.merge()
+	}
+}
+
+// merge merges the values added since the last merge into the centroids, a centroid grows while it spans at most
+// 1 of the k3 scale k(q) = compression/z * log(2q), or -compression/z * log(2(1-q)) above the median, with
+// z = 4*log(n/compression)+21 for n values.
+func (d *TDigestDistribution) merge() {
+	if len(d.unmerged) == 0 {
+		return
+	}
+	all := append(d.centroids, d.unmerged...)
+	d.unmerged = nil
+	sort.Slice(all, func(i, j int) bool { return all[i].mean < all[j].mean })
+	total := 0.0
+	for _, c := range all {
+		total += c.weight
+	}
+
+	normalizer := d.compression / (4*math.Log(math.Max(total/d.compression, 1)) + 21)
+	merged := []centroid{all[0]}
+	before := 0.0
+	for _, c := range all[1:] {
+		current := &merged[len(merged)-1]
+		if k(math.Min(1, (before+current.weight+c.weight)/total), normalizer)-k(before/total, normalizer) <= 1 {
+			current.weight += c.weight
+			current.mean += (c.mean - current.mean) * c.weight / current.weight
+		} else {
+			before += current.weight
+			merged = append(merged, c)
+		}
+	}
+	d.centroids = merged
+}
+
+func k(q, normalizer float64) float64 {
+	// the quantiles 0 and 1 are kept out of the logarithm
+	q = math.Min(math.Max(q, 1e-15), 1-1e-15)
+	if q <= 0.5 {
+		return normalizer * math.Log(2*q)
+	}
+	return -normalizer * math.Log(2*(1-q))
+}
+
+func (d *TDigestDistribution) totalWeight() float64 {
+	total := 0.0
+	for _, c := range d.centroids {
+		total += c.weight
+	}
+	return total
+}
+
+const typeName = "tdigest"
+
+func init() {
+	distribution.RegisterType(typeName, NewTDigestDistribution)
+}
+
+// tdigestState is the state of the distribution marshaled in json, the centroids are [mean, weight] pairs.
+type tdigestState struct {
+	Maximum     float64      `json:"maximum"`
+	Minimum     float64      `json:"minimum"`
+	SampleCount float64      `json:"sample_count"`
+	Sum         float64      `json:"sum"`
+	Compression float64      `json:"compression"`
+	Centroids   [][2]float64 `json:"centroids"`
+	Unit        string       `json:"unit,omitempty"`
+}
+
+func (d *TDigestDistribution) TypeName() string {
+	return typeName
+}
+
+func (d *TDigestDistribution) MarshalJSON() ([]byte, error) {
+	d.merge()
+	centroids := make([][2]float64, len(d.centroids))
+	for i, c := range d.centroids {
+		centroids[i] = [2]float64{c.mean, c.weight}
+	}
+	return json.Marshal(tdigestState{
+		Maximum:     d.maximum,
+		Minimum:     d.minimum,
+		SampleCount: d.sampleCount,
+		Sum:         d.sum,
+		Compression: d.compression,
+		Centroids:   centroids,
+		Unit:        d.unit,
+	})
+}
+
+func (d *TDigestDistribution) UnmarshalJSON(data []byte) error {
+	var state tdigestState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return err
+	}
+	*d = *NewTDigestDistributionWithCompression(state.Compression).(*TDigestDistribution)
+	d.maximum = state.Maximum
+	d.minimum = state.Minimum
+	d.sampleCount = state.SampleCount
+	d.sum = state.Sum
+	d.unit = state.Unit
+	for _, c := range state.Centroids {
+		if c[1] > 0 {
+			d.unmerged = append(d.unmerged, centroid{mean: c[0], weight: c[1]})
+		}
+	}
+	d.merge()
+	return nil
+}
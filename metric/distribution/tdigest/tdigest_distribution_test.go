@@ -0,0 +1,108 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package tdigest
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/aws/amazon-cloudwatch-agent/metric/distribution"
+	"github.com/aws/amazon-cloudwatch-agent/metric/distribution/seh1"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTDigestDistribution(t *testing.T) {
+	dist := NewTDigestDistribution()
+
+	assert.NoError(t, dist.AddEntry(20, 1))
+	assert.NoError(t, dist.AddEntry(30, 1))
+	assert.NoError(t, dist.AddEntryWithUnit(50, 1, "Count"))
+	// the negative values are dropped by default
+	assert.NoError(t, dist.AddEntry(-1, 1))
+
+	assert.Equal(t, 100.0, dist.Sum())
+	assert.Equal(t, 3.0, dist.SampleCount())
+	assert.Equal(t, 20.0, dist.Minimum())
+	assert.Equal(t, 50.0, dist.Maximum())
+	assert.Equal(t, "Count", dist.Unit())
+	// the few values are centroids of their own
+	values, counts := dist.ValuesAndCounts()
+	assert.Equal(t, []float64{20, 30, 50}, values)
+	assert.Equal(t, []float64{1, 1, 1}, counts)
+}
+
+func TestTDigestDistribution_Quantiles(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	dist := NewTDigestDistributionWithCompression(100).(*TDigestDistribution)
+	values := make([]float64, 100000)
+	for i := range values {
+		// latencies with a long tail
+		values[i] = r.ExpFloat64() * 100
+		assert.NoError(t, dist.AddEntry(values[i], 1))
+	}
+	sort.Float64s(values)
+
+	assert.True(t, dist.Size() <= 100, "size %d", dist.Size())
+	// far below the 10% of the buckets of seh1, the tails are the most accurate
+	for q, epsilon := range map[float64]float64{0.5: 0.02, 0.9: 0.01, 0.99: 0.01, 0.999: 0.01} {
+		expected := values[int(q*float64(len(values)))]
+		assert.InEpsilon(t, expected, dist.Quantile(q), epsilon, "quantile %v", q)
+	}
+	assert.Equal(t, values[0], dist.Quantile(0))
+	assert.Equal(t, values[len(values)-1], dist.Quantile(1))
+}
+
+func TestTDigestDistribution_AddDistribution(t *testing.T) {
+	dist := NewTDigestDistribution().(*TDigestDistribution)
+	other := NewTDigestDistribution()
+	for i := 1; i <= 1000; i++ {
+		assert.NoError(t, dist.AddEntry(float64(i), 1))
+		assert.NoError(t, other.AddEntry(float64(1000+i), 1))
+	}
+	dist.AddDistributionWithWeight(other, 2)
+	assert.Equal(t, 3000.0, dist.SampleCount())
+	assert.Equal(t, 2000.0, dist.Maximum())
+	_, counts := dist.ValuesAndCounts()
+	total := 0.0
+	for _, c := range counts {
+		total += c
+	}
+	assert.Equal(t, 3000.0, total)
+	assert.InEpsilon(t, 1250, dist.Quantile(0.5), 0.02)
+
+	// the values and counts of the other distributions are merged as centroids
+	s := seh1.NewSEH1Distribution()
+	assert.NoError(t, s.AddEntry(5000, 3))
+	dist.AddDistribution(s)
+	assert.Equal(t, 3003.0, dist.SampleCount())
+	assert.Equal(t, 5000.0, dist.Maximum())
+	assert.True(t, math.Abs(dist.Quantile(0.9999)-5000) < 5000*0.1)
+}
+
+func TestTDigestDistribution_Marshal(t *testing.T) {
+	dist := NewTDigestDistributionWithCompression(50)
+	assert.NoError(t, dist.AddEntryWithUnit(0.5, 2, "Seconds"))
+	assert.NoError(t, dist.AddEntry(20, 1))
+	assert.NoError(t, dist.AddEntry(300, 3))
+
+	data, err := distribution.Marshal(dist)
+	assert.NoError(t, err)
+	restored, err := distribution.Unmarshal(data)
+	assert.NoError(t, err)
+	assert.Equal(t, dist.Maximum(), restored.Maximum())
+	assert.Equal(t, dist.Minimum(), restored.Minimum())
+	assert.Equal(t, dist.SampleCount(), restored.SampleCount())
+	assert.Equal(t, dist.Sum(), restored.Sum())
+	assert.Equal(t, dist.Unit(), restored.Unit())
+	values, counts := dist.ValuesAndCounts()
+	restoredValues, restoredCounts := restored.ValuesAndCounts()
+	assert.ElementsMatch(t, values, restoredValues)
+	assert.ElementsMatch(t, counts, restoredCounts)
+
+	// the restored distribution keeps aggregating
+	assert.NoError(t, restored.AddEntry(20, 1))
+	assert.Equal(t, dist.SampleCount()+1, restored.SampleCount())
+}
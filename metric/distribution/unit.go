@@ -0,0 +1,147 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package distribution
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+
+	"github.com/influxdata/telegraf/selfstat"
+)
+
+const (
+	// the units which can't be converted to each other are merged keeping the unit of the distribution with
+	// prefer_left by default, not merged with an error with fail, or dropped with drop
+	UnitConflictsPreferLeft = "prefer_left"
+	UnitConflictsFail       = "fail"
+	UnitConflictsDrop       = "drop"
+)
+
+// the units of a dimension, e.g. the time, by their size in the smallest unit of the dimension, the bytes are
+// converted by 1024 and the bits by 1000
+var unitDimensions = []map[string]float64{
+	{"Microseconds": 1, "Milliseconds": 1e3, "Seconds": 1e6},
+	{"Bytes": 1, "Kilobytes": 1 << 10, "Megabytes": 1 << 20, "Gigabytes": 1 << 30, "Terabytes": 1 << 40},
+	{"Bits": 1, "Kilobits": 1e3, "Megabits": 1e6, "Gigabits": 1e9, "Terabits": 1e12},
+}
+
+var (
+	unitConflictPolicy = UnitConflictsPreferLeft
+
+	unitConflictsMu     sync.Mutex
+	unitConflictsLogged = map[string]bool{}
+)
+
+// SetUnitConflictPolicy sets the policy the distributions apply to the entries and the distributions added with a
+// unit which can't be converted to their unit.
+func SetUnitConflictPolicy(policy string) error {
+	switch policy {
+	case "":
+		policy = UnitConflictsPreferLeft
+	case UnitConflictsPreferLeft, UnitConflictsFail, UnitConflictsDrop:
+	default:
+		return fmt.Errorf("invalid unit conflict policy %q, it must be %s, %s or %s", policy, UnitConflictsPreferLeft, UnitConflictsFail, UnitConflictsDrop)
+	}
+	unitConflictPolicy = policy
+	return nil
+}
+
+// UnitFactor returns the factor converting a value of the unit from to the unit to, e.g. 1000 from Seconds to
+// Milliseconds, the rates like Kilobytes/Second are converted by the unit of their numerator.
+func UnitFactor(from, to string) (float64, bool) {
+	if from == to {
+		return 1, true
+	}
+	fromUnit, fromRate := splitRate(from)
+	toUnit, toRate := splitRate(to)
+	if fromRate != toRate {
+		return 0, false
+	}
+	for _, dimension := range unitDimensions {
+		fromSize, fromOk := dimension[fromUnit]
+		toSize, toOk := dimension[toUnit]
+		if fromOk && toOk {
+			return fromSize / toSize, true
+		}
+	}
+	return 0, false
+}
+
+func splitRate(unit string) (string, string) {
+	if i := strings.Index(unit, "/"); i >= 0 {
+		return unit[:i], unit[i:]
+	}
+	return unit, ""
+}
+
+// ReconcileUnit returns the factor of the values of the unit from added to a distribution of the unit to, 1 when
+// either unit is empty. It returns false when the values aren't added, with an error with the fail policy. The
+// conflicts are counted by policy in the unit_conflicts stat of the distribution measurement.
+func ReconcileUnit(to, from string) (float64, bool, error) {
+	if to == "" || from == "" {
+		return 1, true, nil
+	}
+	if factor, ok := UnitFactor(from, to); ok {
+		return factor, true, nil
+	}
+	policy := unitConflictPolicy
+	selfstat.Register("distribution", "unit_conflicts", map[string]string{"policy": policy}).Incr(1)
+	switch policy {
+	case UnitConflictsFail:
+		return 0, false, fmt.Errorf("the unit %s can't be converted to the unit %s of the distribution", from, to)
+	case UnitConflictsDrop:
+		log.Printf("D! Dropped the values of unit %s added to a distribution of unit %s", from, to)
+		return 0, false, nil
+	}
+	unitConflictsMu.Lock()
+	defer unitConflictsMu.Unlock()
+	if key := from + ":" + to; !unitConflictsLogged[key] {
+		unitConflictsLogged[key] = true
+		log.Printf("W! The values of unit %s are added to distributions of unit %s, which it can't be converted to", from, to)
+	}
+	return 1, true, nil
+}
+
+// Convert returns a view of the distribution whose values are multiplied by the factor, in the unit, to add them to
+// a distribution of the unit.
+func Convert(d Distribution, factor float64, unit string) Distribution {
+	if factor == 1 && d.Unit() == unit {
+		return d
+	}
+	return &converted{Distribution: d, factor: factor, unit: unit}
+}
+
+// converted is only read, the entries and distributions are added to the distribution it converts.
+type converted struct {
+	Distribution
+	factor float64
+	unit   string
+}
+
+func (c *converted) Maximum() float64 {
+	return c.Distribution.Maximum() * c.factor
+}
+
+func (c *converted) Minimum() float64 {
+	return c.Distribution.Minimum() * c.factor
+}
+
+func (c *converted) Sum() float64 {
+	return c.Distribution.Sum() * c.factor
+}
+
+func (c *converted) ValuesAndCounts() ([]float64, []float64) {
+	values, counts := c.Distribution.ValuesAndCounts()
+	convertedValues := make([]float64, len(values))
+	for i, value := range values {
+		convertedValues[i] = value * c.factor
+	}
+	return convertedValues, counts
+}
+
+func (c *converted) Unit() string {
+	return c.unit
+}
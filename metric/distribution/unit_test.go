@@ -0,0 +1,90 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package distribution_test
+
+import (
+	"testing"
+
+	"github.com/aws/amazon-cloudwatch-agent/metric/distribution"
+	"github.com/aws/amazon-cloudwatch-agent/metric/distribution/regular"
+	"github.com/aws/amazon-cloudwatch-agent/metric/distribution/seh1"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnitFactor(t *testing.T) {
+	testCases := []struct {
+		from, to string
+		factor   float64
+		ok       bool
+	}{
+		{"Seconds", "Seconds", 1, true},
+		{"Seconds", "Milliseconds", 1000, true},
+		{"Microseconds", "Milliseconds", 0.001, true},
+		{"Megabytes", "Kilobytes", 1024, true},
+		{"Kilobits/Second", "Bits/Second", 1000, true},
+		{"Kilobytes/Second", "Kilobytes", 0, false},
+		{"Bytes", "Bits", 0, false},
+		{"Count", "Seconds", 0, false},
+	}
+	for _, testCase := range testCases {
+		factor, ok := distribution.UnitFactor(testCase.from, testCase.to)
+		assert.Equal(t, testCase.ok, ok, "%s to %s", testCase.from, testCase.to)
+		assert.Equal(t, testCase.factor, factor, "%s to %s", testCase.from, testCase.to)
+	}
+}
+
+func TestAddDistribution_ConvertedUnit(t *testing.T) {
+	for _, newDistribution := range []func() distribution.Distribution{regular.NewRegularDistribution, seh1.NewSEH1Distribution} {
+		dist := newDistribution()
+		assert.NoError(t, dist.AddEntryWithUnit(2, 1, "Seconds"))
+		// the entries of compatible units are converted
+		assert.NoError(t, dist.AddEntryWithUnit(500, 1, "Milliseconds"))
+		other := newDistribution()
+		assert.NoError(t, other.AddEntryWithUnit(1500, 2, "Milliseconds"))
+		dist.AddDistribution(other)
+
+		assert.Equal(t, "Seconds", dist.Unit())
+		assert.Equal(t, 4.0, dist.SampleCount())
+		assert.Equal(t, 5.5, dist.Sum())
+		assert.Equal(t, 0.5, dist.Minimum())
+		assert.Equal(t, 2.0, dist.Maximum())
+		values, _ := dist.ValuesAndCounts()
+		for _, value := range values {
+			assert.True(t, value > 0.4 && value < 2.2, "value %v", value)
+		}
+	}
+}
+
+func TestAddDistribution_UnitConflicts(t *testing.T) {
+	defer distribution.SetUnitConflictPolicy(distribution.UnitConflictsPreferLeft)
+	assert.EqualError(t, distribution.SetUnitConflictPolicy("prefer_right"), `invalid unit conflict policy "prefer_right", it must be prefer_left, fail or drop`)
+
+	newDistributions := func() (distribution.Distribution, distribution.Distribution) {
+		dist := seh1.NewSEH1Distribution()
+		assert.NoError(t, dist.AddEntryWithUnit(2, 1, "Seconds"))
+		other := seh1.NewSEH1Distribution()
+		assert.NoError(t, other.AddEntryWithUnit(3, 1, "Count"))
+		return dist, other
+	}
+
+	// the values are merged in the unit of the distribution by default
+	dist, other := newDistributions()
+	dist.AddDistribution(other)
+	assert.NoError(t, dist.AddEntryWithUnit(4, 1, "Count"))
+	assert.Equal(t, "Seconds", dist.Unit())
+	assert.Equal(t, 3.0, dist.SampleCount())
+
+	assert.NoError(t, distribution.SetUnitConflictPolicy(distribution.UnitConflictsFail))
+	dist, other = newDistributions()
+	dist.AddDistribution(other)
+	assert.EqualError(t, dist.AddEntryWithUnit(4, 1, "Count"), "the unit Count can't be converted to the unit Seconds of the distribution")
+	assert.Equal(t, 1.0, dist.SampleCount())
+
+	assert.NoError(t, distribution.SetUnitConflictPolicy(distribution.UnitConflictsDrop))
+	dist, other = newDistributions()
+	dist.AddDistribution(other)
+	assert.NoError(t, dist.AddEntryWithUnit(4, 1, "Count"))
+	assert.Equal(t, 1.0, dist.SampleCount())
+	assert.Equal(t, 2.0, dist.Sum())
+}
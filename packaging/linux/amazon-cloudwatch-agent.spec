@@ -57,6 +57,7 @@ ln -f -s /opt/aws/amazon-cloudwatch-agent/cwagent-otel-collector/var ${RPM_BUILD
 /opt/aws/amazon-cloudwatch-agent/bin/CWAGENT_VERSION
 /opt/aws/amazon-cloudwatch-agent/bin/config-translator
 /opt/aws/amazon-cloudwatch-agent/bin/config-downloader
+/opt/aws/amazon-cloudwatch-agent/bin/amazon-cloudwatch-agent-updater
 /opt/aws/amazon-cloudwatch-agent/bin/amazon-cloudwatch-agent-config-wizard
 /opt/aws/amazon-cloudwatch-agent/bin/start-amazon-cloudwatch-agent
 /opt/aws/amazon-cloudwatch-agent/bin/cwagent-otel-collector
@@ -29,7 +29,7 @@ $UsageString = @"
         usage:  amazon-cloudwatch-agent-ctl.ps1 -a
                 stop|start|status|fetch-config|append-config|remove-config|set-log-level
                 [-m ec2|onPremise|auto]
-                [-c default|all|ssm:<parameter-store-name>|file:<file-path>]
+                [-c default|all|ssm:<parameter-store-name>|s3:<bucket>/<key>|ec2tag:<tag-key>|file:<file-path>]
                 [-o default|all|ssm:<parameter-store-name>|file:<file-path>]
                 [-s]
                 [-l INFO|DEBUG|WARN|ERROR|OFF]
@@ -59,6 +59,8 @@ $UsageString = @"
         -c: amazon-cloudwatch-agent configuration
             default:                                default configuration for quick trial.
             ssm:<parameter-store-name>:             ssm parameter store name.
+            s3:<bucket>/<key>:                      s3 object.
+            ec2tag:<tag-key>:                       the location held by the tag of the ec2 instance, e.g. ssm:<parameter-store-name> or s3://<bucket>/<key>.
             file:<file-path>:                       file path on the host.
             all:                                    all existing configs. Only apply to remove-config action.
 
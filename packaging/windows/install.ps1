@@ -35,6 +35,7 @@ New-Item -ItemType Directory -Force -Path "${CWOCProgramData}\Configs" | Out-Nul
 "start-amazon-cloudwatch-agent.exe",
 "amazon-cloudwatch-agent-ctl.ps1",
 "config-downloader.exe",
+"amazon-cloudwatch-agent-updater.exe",
 "config-translator.exe",
 "amazon-cloudwatch-agent-config-wizard.exe",
 "amazon-cloudwatch-agent-schema.json"
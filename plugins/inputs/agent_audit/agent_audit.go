@@ -0,0 +1,160 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package agent_audit
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/aws/amazon-cloudwatch-agent/internal/audit"
+	"github.com/aws/amazon-cloudwatch-agent/logs"
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/plugins/inputs"
+)
+
+const (
+	stateFileName = "agent_audit_state.json"
+)
+
+// AgentAudit publishes the audit events of the agent configuration, credential and endpoint changes to a
+// dedicated log group.
+type AgentAudit struct {
+	FileStateFolder string          `toml:"file_state_folder"`
+	LogGroupName    string          `toml:"log_group_name"`
+	LogStreamName   string          `toml:"log_stream_name"`
+	Destination     string          `toml:"destination"`
+	RetentionInDays int             `toml:"retention_in_days"`
+	Log             telegraf.Logger `toml:"-"`
+
+	src     *auditLogSrc
+	newSrcs []logs.LogSrc
+}
+
+func (a *AgentAudit) Description() string {
+	return "A plugin to publish the audit events of the agent configuration, credentials and endpoints"
+}
+
+func (a *AgentAudit) SampleConfig() string {
+	return `
+	file_state_folder = "/opt/aws/amazon-cloudwatch-agent/logs/state"
+	log_group_name = "amazon-cloudwatch-agent-audit"
+	log_stream_name = "STREAM_NAME"
+	destination = "cloudwatchlogs"
+	`
+}
+
+// Init enables the audit before the outputs connect, so the endpoints and credentials they use are audited.
+func (a *AgentAudit) Init() error {
+	if err := audit.Enable(filepath.Join(a.FileStateFolder, stateFileName)); err != nil {
+		a.Log.Errorf("Failed to load the audit state, the changes are reported against an empty state: %v", err)
+	}
+	a.src = &auditLogSrc{
+		group:       a.LogGroupName,
+		stream:      a.LogStreamName,
+		destination: a.Destination,
+		retention:   a.RetentionInDays,
+		done:        make(chan struct{}),
+	}
+	a.newSrcs = []logs.LogSrc{a.src}
+	return nil
+}
+
+func (a *AgentAudit) Gather(acc telegraf.Accumulator) error {
+	return nil
+}
+
+func (a *AgentAudit) FindLogSrc() []logs.LogSrc {
+	srcs := a.newSrcs
+	a.newSrcs = nil
+	return srcs
+}
+
+func (a *AgentAudit) Start(acc telegraf.Accumulator) error {
+	return nil
+}
+
+func (a *AgentAudit) Stop() {
+	audit.Disable()
+	if a.src != nil {
+		a.src.Stop()
+	}
+}
+
+type auditLogSrc struct {
+	group       string
+	stream      string
+	destination string
+	retention   int
+
+	stopOnce sync.Once
+	done     chan struct{}
+}
+
+func (s *auditLogSrc) SetOutput(fn func(logs.LogEvent)) {
+	if fn == nil {
+		return
+	}
+	events := audit.Subscribe()
+	go func() {
+		defer audit.Unsubscribe(events)
+		for {
+			select {
+			case e := <-events:
+				message, err := json.Marshal(e)
+				if err != nil {
+					continue
+				}
+				fn(&auditLogEvent{message: string(message), t: e.Time})
+			case <-s.done:
+				fn(nil)
+				return
+			}
+		}
+	}()
+}
+
+func (s *auditLogSrc) Group() string {
+	return s.group
+}
+
+func (s *auditLogSrc) Stream() string {
+	return s.stream
+}
+
+func (s *auditLogSrc) Destination() string {
+	return s.destination
+}
+
+func (s *auditLogSrc) Description() string {
+	return "agent audit"
+}
+
+func (s *auditLogSrc) Retention() int {
+	return s.retention
+}
+
+func (s *auditLogSrc) Stop() {
+	s.stopOnce.Do(func() { close(s.done) })
+}
+
+type auditLogEvent struct {
+	message string
+	t       time.Time
+}
+
+func (e *auditLogEvent) Message() string {
+	return e.message
+}
+
+func (e *auditLogEvent) Time() time.Time {
+	return e.t
+}
+
+func (e *auditLogEvent) Done() {}
+
+func init() {
+	inputs.Add("agent_audit", func() telegraf.Input { return &AgentAudit{} })
+}
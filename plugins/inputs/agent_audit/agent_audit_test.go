@@ -0,0 +1,75 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package agent_audit
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/aws/amazon-cloudwatch-agent/internal/audit"
+	"github.com/aws/amazon-cloudwatch-agent/logs"
+	"github.com/influxdata/telegraf/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAgentAudit(t *testing.T) {
+	dir, err := ioutil.TempDir("", "agent_audit")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	audit.SetConfig(audit.ConfigInfo{File: "agent.toml", Sha256: "abc", Inputs: []string{"cpu"}, Outputs: []string{"cloudwatch"}})
+	a := &AgentAudit{
+		FileStateFolder: dir,
+		LogGroupName:    "audit",
+		LogStreamName:   "i-1234567890abcdef0",
+		Destination:     "cloudwatchlogs",
+		Log:             testutil.Logger{},
+	}
+	assert.NoError(t, a.Init())
+	audit.EndpointConfigured("cloudwatch", "us-east-1", "")
+
+	srcs := a.FindLogSrc()
+	assert.Len(t, srcs, 1)
+	assert.Empty(t, a.FindLogSrc())
+	src := srcs[0]
+	assert.Equal(t, "audit", src.Group())
+	assert.Equal(t, "i-1234567890abcdef0", src.Stream())
+	assert.Equal(t, "cloudwatchlogs", src.Destination())
+
+	eventsCh := make(chan logs.LogEvent, 10)
+	src.SetOutput(func(e logs.LogEvent) {
+		if e == nil {
+			close(eventsCh)
+			return
+		}
+		eventsCh <- e
+	})
+
+	var eventTypes []string
+	timeout := time.After(5 * time.Second)
+	for len(eventTypes) < 2 {
+		select {
+		case e := <-eventsCh:
+			var event audit.Event
+			assert.NoError(t, json.Unmarshal([]byte(e.Message()), &event))
+			assert.Equal(t, event.Time.Unix(), e.Time().Unix())
+			eventTypes = append(eventTypes, event.EventType)
+		case <-timeout:
+			assert.FailNow(t, "no audit event published")
+		}
+	}
+	assert.Equal(t, []string{audit.EventConfigApplied, audit.EventEndpointChanged}, eventTypes)
+
+	a.Stop()
+	_, open := <-eventsCh
+	assert.False(t, open)
+
+	// the state is persisted to detect the changes on the next start
+	_, err = os.Stat(filepath.Join(dir, stateFileName))
+	assert.NoError(t, err)
+}
@@ -0,0 +1,176 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package agent_health
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/aws/amazon-cloudwatch-agent/cfg/agentinfo"
+	"github.com/aws/amazon-cloudwatch-agent/internal"
+	"github.com/aws/amazon-cloudwatch-agent/internal/health"
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/plugins/inputs"
+)
+
+const (
+	defaultUnhealthyThreshold = 5 * time.Minute
+	defaultTimeout            = 30 * time.Second
+
+	statusEnv   = "CWAGENT_HEALTH_STATUS"
+	pipelineEnv = "CWAGENT_HEALTH_PIPELINE"
+)
+
+var sampleConfig = `
+  ## The command run and the url posted to when a pipeline is unhealthy or recovered, the json of the event is
+  ## written to the stdin of the command and is the body of the request.
+  # command = "/usr/local/bin/page-oncall"
+  # webhook_url = "https://events.example.com/cwagent"
+
+  ## How long the publishing of a pipeline fails without any success before the pipeline is unhealthy.
+  # unhealthy_threshold = "5m"
+
+  ## Repeat the notification while the pipeline stays unhealthy, it is sent once when zero.
+  # repeat_interval = "0s"
+
+  ## Maximum time the command or the request is allowed to run.
+  # timeout = "30s"
+`
+
+// AgentHealth runs a hook when a pipeline of the agent, the cloudwatch or the cloudwatchlogs output, keeps failing to
+// publish, so the failures of the agent can page without an alarm on the metrics of the agent itself.
+type AgentHealth struct {
+	Command            string            `toml:"command"`
+	WebhookURL         string            `toml:"webhook_url"`
+	UnhealthyThreshold internal.Duration `toml:"unhealthy_threshold"`
+	RepeatInterval     internal.Duration `toml:"repeat_interval"`
+	Timeout            internal.Duration `toml:"timeout"`
+	Log                telegraf.Logger   `toml:"-"`
+
+	hostname   string
+	client     *http.Client
+	runCommand func(ctx context.Context, command string, env []string, stdin []byte) ([]byte, error)
+}
+
+// payload is the json of the event given to the hook.
+type payload struct {
+	health.Event
+	Host         string `json:"host"`
+	AgentVersion string `json:"agent_version"`
+}
+
+func (a *AgentHealth) Description() string {
+	return "Run a command or post to a webhook when a pipeline of the agent keeps failing to publish"
+}
+
+func (a *AgentHealth) SampleConfig() string {
+	return sampleConfig
+}
+
+func (a *AgentHealth) Init() error {
+	if a.Command == "" && a.WebhookURL == "" {
+		return errors.New("agent_health: command or webhook_url is required")
+	}
+	if a.WebhookURL != "" && !strings.HasPrefix(a.WebhookURL, "http://") && !strings.HasPrefix(a.WebhookURL, "https://") {
+		return fmt.Errorf("agent_health: invalid webhook_url %q, it must be an http or https url", a.WebhookURL)
+	}
+	if a.UnhealthyThreshold.Duration <= 0 {
+		a.UnhealthyThreshold.Duration = defaultUnhealthyThreshold
+	}
+	if a.Timeout.Duration <= 0 {
+		a.Timeout.Duration = defaultTimeout
+	}
+	a.hostname, _ = os.Hostname()
+	a.client = &http.Client{Timeout: a.Timeout.Duration}
+	if a.runCommand == nil {
+		a.runCommand = runCommand
+	}
+	return nil
+}
+
+func (a *AgentHealth) Gather(acc telegraf.Accumulator) error {
+	return nil
+}
+
+func (a *AgentHealth) Start(acc telegraf.Accumulator) error {
+	health.Enable(health.Config{
+		Threshold:      a.UnhealthyThreshold.Duration,
+		RepeatInterval: a.RepeatInterval.Duration,
+		Notify:         a.notify,
+	})
+	return nil
+}
+
+func (a *AgentHealth) Stop() {
+	health.Disable()
+}
+
+func (a *AgentHealth) notify(e health.Event) {
+	if e.Status == health.StatusUnhealthy {
+		a.Log.Warnf("Pipeline %v has been failing for %vs: %v", e.Pipeline, e.FailingSeconds, e.LastError)
+	} else {
+		a.Log.Infof("Pipeline %v recovered after failing for %vs", e.Pipeline, e.FailingSeconds)
+	}
+	body, err := json.Marshal(payload{Event: e, Host: a.hostname, AgentVersion: agentinfo.Version()})
+	if err != nil {
+		a.Log.Errorf("Failed to marshal the health event of pipeline %v: %v", e.Pipeline, err)
+		return
+	}
+	if a.Command != "" {
+		ctx, cancel := context.WithTimeout(context.Background(), a.Timeout.Duration)
+		env := []string{statusEnv + "=" + e.Status, pipelineEnv + "=" + e.Pipeline}
+		output, err := a.runCommand(ctx, a.Command, env, body)
+		cancel()
+		if err != nil {
+			a.Log.Errorf("Health command %q failed: %v, output: %s", a.Command, err, strings.TrimSpace(string(output)))
+		} else {
+			a.Log.Debugf("Health command %q succeeded, output: %s", a.Command, strings.TrimSpace(string(output)))
+		}
+	}
+	if a.WebhookURL != "" {
+		if err := a.post(body); err != nil {
+			a.Log.Errorf("Failed to post the health event of pipeline %v to the webhook: %v", e.Pipeline, err)
+		}
+	}
+}
+
+func (a *AgentHealth) post(body []byte) error {
+	resp, err := a.client.Post(a.WebhookURL, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(ioutil.Discard, resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("unexpected status %v", resp.Status)
+	}
+	return nil
+}
+
+func runCommand(ctx context.Context, command string, env []string, stdin []byte) ([]byte, error) {
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.CommandContext(ctx, "cmd", "/C", command)
+	} else {
+		cmd = exec.CommandContext(ctx, "/bin/sh", "-c", command)
+	}
+	cmd.Env = append(os.Environ(), env...)
+	cmd.Stdin = bytes.NewReader(stdin)
+	return cmd.CombinedOutput()
+}
+
+func init() {
+	inputs.Add("agent_health", func() telegraf.Input { return &AgentHealth{} })
+}
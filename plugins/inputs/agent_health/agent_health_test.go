@@ -0,0 +1,89 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package agent_health
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/aws/amazon-cloudwatch-agent/internal/health"
+	"github.com/influxdata/telegraf/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+var unhealthyEvent = health.Event{
+	Status:              health.StatusUnhealthy,
+	Pipeline:            "cloudwatch",
+	Time:                time.Date(2021, 3, 2, 15, 9, 5, 0, time.UTC),
+	FailingSince:        time.Date(2021, 3, 2, 15, 4, 5, 0, time.UTC),
+	FailingSeconds:      300,
+	ConsecutiveFailures: 4,
+	LastError:           "RequestError: send request failed",
+}
+
+func TestNotifyWebhook(t *testing.T) {
+	var received map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		body, _ := ioutil.ReadAll(r.Body)
+		assert.NoError(t, json.Unmarshal(body, &received))
+	}))
+	defer server.Close()
+
+	a := &AgentHealth{WebhookURL: server.URL, Log: testutil.Logger{}}
+	assert.NoError(t, a.Init())
+	a.hostname = "ip-10-0-0-1"
+	a.notify(unhealthyEvent)
+	assert.Equal(t, "unhealthy", received["status"])
+	assert.Equal(t, "cloudwatch", received["pipeline"])
+	assert.Equal(t, "2021-03-02T15:04:05Z", received["failing_since"])
+	assert.Equal(t, 300.0, received["failing_duration_seconds"])
+	assert.Equal(t, 4.0, received["consecutive_failures"])
+	assert.Equal(t, "RequestError: send request failed", received["last_error"])
+	assert.Equal(t, "ip-10-0-0-1", received["host"])
+	assert.Contains(t, received, "agent_version")
+}
+
+func TestNotifyCommand(t *testing.T) {
+	var command string
+	var env []string
+	var stdin []byte
+	a := &AgentHealth{Command: "page-oncall --severity high", Log: testutil.Logger{}}
+	a.runCommand = func(ctx context.Context, c string, e []string, in []byte) ([]byte, error) {
+		command, env, stdin = c, e, in
+		return nil, nil
+	}
+	assert.NoError(t, a.Init())
+	a.notify(unhealthyEvent)
+	assert.Equal(t, "page-oncall --severity high", command)
+	assert.Equal(t, []string{"CWAGENT_HEALTH_STATUS=unhealthy", "CWAGENT_HEALTH_PIPELINE=cloudwatch"}, env)
+	var p payload
+	assert.NoError(t, json.Unmarshal(stdin, &p))
+	assert.Equal(t, unhealthyEvent, p.Event)
+}
+
+func TestRunCommand(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the command uses the shell")
+	}
+	output, err := runCommand(context.Background(), `echo "$CWAGENT_HEALTH_STATUS"; cat`, []string{"CWAGENT_HEALTH_STATUS=recovered"}, []byte(`{"status":"recovered"}`))
+	assert.NoError(t, err)
+	assert.Equal(t, "recovered\n{\"status\":\"recovered\"}", string(output))
+}
+
+func TestInit(t *testing.T) {
+	assert.Error(t, (&AgentHealth{}).Init())
+	assert.Error(t, (&AgentHealth{WebhookURL: "events.example.com"}).Init())
+	a := &AgentHealth{Command: "true"}
+	assert.NoError(t, a.Init())
+	assert.Equal(t, defaultUnhealthyThreshold, a.UnhealthyThreshold.Duration)
+	assert.Equal(t, defaultTimeout, a.Timeout.Duration)
+}
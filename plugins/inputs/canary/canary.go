@@ -0,0 +1,174 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package canary
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/aws/amazon-cloudwatch-agent/cfg/agentinfo"
+	configaws "github.com/aws/amazon-cloudwatch-agent/cfg/aws"
+	"github.com/aws/amazon-cloudwatch-agent/handlers"
+	"github.com/aws/amazon-cloudwatch-agent/internal/audit"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cloudwatch"
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/plugins/inputs"
+)
+
+const (
+	pluginName       = "canary"
+	defaultNamespace = "CWAgent/Canary"
+	metricName       = "Heartbeat"
+
+	dimensionHost         = "host"
+	dimensionAgentVersion = "AgentVersion"
+	dimensionConfigHash   = "ConfigHash"
+	// the length of the config hash dimension, enough to tell the configurations of a fleet apart
+	configHashLength = 12
+	// the config hash dimension when the configuration file can't be read, a dimension can't be empty
+	unknownConfigHash = "unknown"
+)
+
+var sampleConfig = `
+  ## Publish the heartbeat every 60 seconds, whatever the metrics_collection_interval of the agent is.
+  interval = "60s"
+
+  ## Amazon REGION the heartbeat is published to
+  region = "us-east-1"
+
+  ## Amazon Credentials, loaded in the same order as the cloudwatch output
+  #access_key = ""
+  #secret_key = ""
+  #token = ""
+  #role_arn = ""
+  #profile = ""
+  #shared_credential_file = ""
+
+  ## The namespace of the heartbeat, the metrics section of the configuration doesn't apply to it.
+  # namespace = "CWAgent/Canary"
+`
+
+// putMetricDataAPI is the subset of the cloudwatch client used to publish the heartbeat.
+type putMetricDataAPI interface {
+	PutMetricData(input *cloudwatch.PutMetricDataInput) (*cloudwatch.PutMetricDataOutput, error)
+}
+
+// Canary publishes a heartbeat of the agent with its version and the hash of its configuration, straight to
+// CloudWatch instead of through the cloudwatch output, so the heartbeat is published whatever the metrics section
+// of the configuration is, even without one. The fleet automation tells an agent alive and configured, and with
+// which configuration, from an agent publishing no data at all.
+type Canary struct {
+	Region           string `toml:"region"`
+	EndpointOverride string `toml:"endpoint_override"`
+	AccessKey        string `toml:"access_key"`
+	SecretKey        string `toml:"secret_key"`
+	RoleARN          string `toml:"role_arn"`
+	Profile          string `toml:"profile"`
+	Filename         string `toml:"shared_credential_file"`
+	Token            string `toml:"token"`
+	Namespace        string `toml:"namespace"`
+
+	Log telegraf.Logger `toml:"-"`
+
+	// the client, the host name and the clock, replaced in tests
+	svc      putMetricDataAPI
+	hostname func() (string, error)
+	now      func() time.Time
+
+	host string
+}
+
+func (c *Canary) SampleConfig() string {
+	return sampleConfig
+}
+
+func (c *Canary) Description() string {
+	return "Publish a heartbeat of the agent with its version and the hash of its configuration."
+}
+
+func (c *Canary) Init() error {
+	if c.Namespace == "" {
+		c.Namespace = defaultNamespace
+	}
+	if c.hostname == nil {
+		c.hostname = os.Hostname
+	}
+	if c.now == nil {
+		c.now = time.Now
+	}
+	host, err := c.hostname()
+	if err != nil {
+		return fmt.Errorf("%s: failed to get the host name: %v", pluginName, err)
+	}
+	c.host = host
+	if c.svc == nil {
+		c.svc = c.newClient()
+	}
+	return nil
+}
+
+func (c *Canary) newClient() putMetricDataAPI {
+	credentialConfig := &configaws.CredentialConfig{
+		Region:    c.Region,
+		AccessKey: c.AccessKey,
+		SecretKey: c.SecretKey,
+		RoleARN:   c.RoleARN,
+		Profile:   c.Profile,
+		Filename:  c.Filename,
+		Token:     c.Token,
+	}
+	svc := cloudwatch.New(
+		credentialConfig.Credentials(),
+		&aws.Config{
+			Endpoint: aws.String(c.EndpointOverride),
+			LogLevel: configaws.SDKLogLevel(),
+			Logger:   configaws.SDKLogger{},
+		})
+	svc.Handlers.Build.PushBackNamed(handlers.NewCustomHeaderHandler("User-Agent", agentinfo.UserAgent("")))
+	audit.EndpointConfigured(pluginName, c.Region, c.EndpointOverride)
+	return svc
+}
+
+// Gather publishes one heartbeat, the configuration hash is read at each collection since the configuration is
+// recorded once the inputs are loaded.
+func (c *Canary) Gather(acc telegraf.Accumulator) error {
+	input := &cloudwatch.PutMetricDataInput{
+		Namespace: aws.String(c.Namespace),
+		MetricData: []*cloudwatch.MetricDatum{{
+			MetricName: aws.String(metricName),
+			Dimensions: []*cloudwatch.Dimension{
+				{Name: aws.String(dimensionHost), Value: aws.String(c.host)},
+				{Name: aws.String(dimensionAgentVersion), Value: aws.String(agentinfo.Version())},
+				{Name: aws.String(dimensionConfigHash), Value: aws.String(configHash())},
+			},
+			Timestamp: aws.Time(c.now()),
+			Unit:      aws.String(cloudwatch.StandardUnitCount),
+			Value:     aws.Float64(1),
+		}},
+	}
+	if _, err := c.svc.PutMetricData(input); err != nil {
+		return fmt.Errorf("%s: failed to publish the heartbeat: %v", pluginName, err)
+	}
+	return nil
+}
+
+// configHash is the short hash of the configuration applied.
+func configHash() string {
+	hash := agentinfo.ConfigSha256
+	if hash == "" {
+		return unknownConfigHash
+	}
+	if len(hash) > configHashLength {
+		hash = hash[:configHashLength]
+	}
+	return hash
+}
+
+func init() {
+	inputs.Add(pluginName, func() telegraf.Input {
+		return &Canary{}
+	})
+}
@@ -0,0 +1,103 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package canary
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aws/amazon-cloudwatch-agent/cfg/agentinfo"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cloudwatch"
+	"github.com/influxdata/telegraf/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockPutMetricData struct {
+	inputs []cloudwatch.PutMetricDataInput
+	err    error
+}
+
+func (m *mockPutMetricData) PutMetricData(input *cloudwatch.PutMetricDataInput) (*cloudwatch.PutMetricDataOutput, error) {
+	m.inputs = append(m.inputs, *input)
+	return &cloudwatch.PutMetricDataOutput{}, m.err
+}
+
+func newCanary(t *testing.T, svc putMetricDataAPI, now time.Time) *Canary {
+	c := &Canary{
+		Log:      testutil.Logger{},
+		svc:      svc,
+		hostname: func() (string, error) { return "ip-10-0-0-1", nil },
+		now:      func() time.Time { return now },
+	}
+	require.NoError(t, c.Init())
+	return c
+}
+
+func dimensions(datum *cloudwatch.MetricDatum) map[string]string {
+	dims := map[string]string{}
+	for _, d := range datum.Dimensions {
+		dims[aws.StringValue(d.Name)] = aws.StringValue(d.Value)
+	}
+	return dims
+}
+
+func TestGather(t *testing.T) {
+	defer func(version, hash string) {
+		agentinfo.VersionStr, agentinfo.ConfigSha256 = version, hash
+	}(agentinfo.VersionStr, agentinfo.ConfigSha256)
+	agentinfo.VersionStr = "1.247350.0"
+	agentinfo.ConfigSha256 = "3f2a9c1be07d4e58a6b1c0d9e8f7a6b5c4d3e2f1a0b9c8d7e6f5a4b3c2d1e0f9"
+
+	now := time.Date(2021, 5, 12, 14, 10, 30, 0, time.UTC)
+	svc := &mockPutMetricData{}
+	c := newCanary(t, svc, now)
+	var acc testutil.Accumulator
+	require.NoError(t, c.Gather(&acc))
+
+	// the heartbeat is published straight to CloudWatch, not through the accumulator
+	assert.Empty(t, acc.Metrics)
+	require.Len(t, svc.inputs, 1)
+	assert.Equal(t, defaultNamespace, aws.StringValue(svc.inputs[0].Namespace))
+	require.Len(t, svc.inputs[0].MetricData, 1)
+	datum := svc.inputs[0].MetricData[0]
+	assert.Equal(t, metricName, aws.StringValue(datum.MetricName))
+	assert.Equal(t, 1.0, aws.Float64Value(datum.Value))
+	assert.Equal(t, cloudwatch.StandardUnitCount, aws.StringValue(datum.Unit))
+	assert.Equal(t, now, aws.TimeValue(datum.Timestamp))
+	assert.Equal(t, map[string]string{
+		"host":         "ip-10-0-0-1",
+		"AgentVersion": "1.247350.0",
+		"ConfigHash":   "3f2a9c1be07d",
+	}, dimensions(datum))
+
+	// the hash of a configuration which can't be read
+	agentinfo.ConfigSha256 = ""
+	require.NoError(t, c.Gather(&acc))
+	require.Len(t, svc.inputs, 2)
+	assert.Equal(t, "unknown", dimensions(svc.inputs[1].MetricData[0])["ConfigHash"])
+}
+
+func TestGather_Namespace(t *testing.T) {
+	svc := &mockPutMetricData{}
+	c := &Canary{
+		Namespace: "Fleet/Heartbeat",
+		Log:       testutil.Logger{},
+		svc:       svc,
+		hostname:  func() (string, error) { return "ip-10-0-0-1", nil },
+	}
+	require.NoError(t, c.Init())
+	require.NoError(t, c.Gather(&testutil.Accumulator{}))
+	require.Len(t, svc.inputs, 1)
+	assert.Equal(t, "Fleet/Heartbeat", aws.StringValue(svc.inputs[0].Namespace))
+}
+
+func TestGather_Error(t *testing.T) {
+	svc := &mockPutMetricData{err: errors.New("throttled")}
+	c := newCanary(t, svc, time.Now())
+	err := c.Gather(&testutil.Accumulator{})
+	assert.EqualError(t, err, "canary: failed to publish the heartbeat: throttled")
+}
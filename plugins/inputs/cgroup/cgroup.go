@@ -0,0 +1,148 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package cgroup
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/plugins/inputs"
+)
+
+const (
+	measurement = "cgroup"
+	defaultRoot = "/sys/fs/cgroup"
+	sliceSuffix = ".slice"
+)
+
+var sampleConfig = `
+  ## The cgroups reported, as paths relative to the cgroup mount or systemd slices. A slice, e.g. "user-1000.slice",
+  ## is expanded to its path in the hierarchy, "user.slice/user-1000.slice". Globs are accepted.
+  paths = ["system.slice/nginx.service", "batch.slice"]
+
+  ## The mount of the cgroup filesystem, both the unified (v2) and the legacy (v1) hierarchies are supported.
+  # cgroup_root = "/sys/fs/cgroup"
+`
+
+// now is replaced in tests.
+var now = time.Now
+
+type Cgroup struct {
+	Paths []string `toml:"paths"`
+	Root  string   `toml:"cgroup_root"`
+
+	Log telegraf.Logger `toml:"-"`
+
+	// the cpu usage of each cgroup at the previous gather
+	lastUsage map[string]cpuSample
+}
+
+type cpuSample struct {
+	usageUsec uint64
+	time      time.Time
+}
+
+func (c *Cgroup) SampleConfig() string {
+	return sampleConfig
+}
+
+func (c *Cgroup) Description() string {
+	return "Report the CPU, memory and IO usage of the configured cgroups and systemd slices."
+}
+
+func (c *Cgroup) Init() error {
+	if len(c.Paths) == 0 {
+		return fmt.Errorf("cgroup: paths is required")
+	}
+	if c.Root == "" {
+		c.Root = defaultRoot
+	}
+	c.lastUsage = map[string]cpuSample{}
+	return nil
+}
+
+func (c *Cgroup) Gather(acc telegraf.Accumulator) error {
+	h := detectHierarchy(c.Root)
+	seen := map[string]bool{}
+	for _, path := range c.resolve(h) {
+		seen[path] = true
+		fields, err := h.stats(path)
+		if err != nil {
+			acc.AddError(fmt.Errorf("failed to read cgroup %s: %v", path, err))
+			continue
+		}
+		if usage, ok := fields["cpu_usage_usec"].(uint64); ok {
+			t := now()
+			if last, ok := c.lastUsage[path]; ok && usage >= last.usageUsec && t.After(last.time) {
+				elapsed := float64(t.Sub(last.time).Microseconds())
+				fields["cpu_usage_percent"] = float64(usage-last.usageUsec) / elapsed * 100
+			}
+			c.lastUsage[path] = cpuSample{usageUsec: usage, time: t}
+		}
+		if usage, ok := fields["memory_usage"].(uint64); ok {
+			if limit, ok := fields["memory_limit"].(uint64); ok && limit > 0 {
+				fields["memory_utilization"] = float64(usage) / float64(limit) * 100
+			}
+		}
+		acc.AddFields(measurement, fields, map[string]string{"cgroup": path})
+	}
+	// forget the cgroups removed, e.g. the scopes of finished sessions
+	for path := range c.lastUsage {
+		if !seen[path] {
+			delete(c.lastUsage, path)
+		}
+	}
+	return nil
+}
+
+// resolve expands the slices and globs of the paths into the cgroups that exist, relative to the mount.
+func (c *Cgroup) resolve(h hierarchy) []string {
+	set := map[string]bool{}
+	for _, p := range c.Paths {
+		p = strings.Trim(expandSlice(p), "/")
+		matches, err := filepath.Glob(filepath.Join(h.base(), p))
+		if err != nil {
+			c.Log.Warnf("Invalid cgroup path %s: %v", p, err)
+			continue
+		}
+		if len(matches) == 0 {
+			c.Log.Debugf("No cgroup matches %s", p)
+		}
+		for _, m := range matches {
+			if rel, err := filepath.Rel(h.base(), m); err == nil {
+				set[filepath.ToSlash(rel)] = true
+			}
+		}
+	}
+	paths := make([]string, 0, len(set))
+	for p := range set {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// expandSlice returns the path of a systemd slice in the hierarchy, systemd nests the slices by the dashes of their
+// name, e.g. "app-web-blue.slice" is "app.slice/app-web.slice/app-web-blue.slice". The other paths are unchanged.
+func expandSlice(p string) string {
+	if strings.Contains(p, "/") || !strings.HasSuffix(p, sliceSuffix) || p == "-"+sliceSuffix {
+		return p
+	}
+	parts := strings.Split(strings.TrimSuffix(p, sliceSuffix), "-")
+	dirs := make([]string, len(parts))
+	for i := range parts {
+		dirs[i] = strings.Join(parts[:i+1], "-") + sliceSuffix
+	}
+	return strings.Join(dirs, "/")
+}
+
+func init() {
+	inputs.Add("cgroup", func() telegraf.Input {
+		return &Cgroup{}
+	})
+}
@@ -0,0 +1,175 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package cgroup
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/influxdata/telegraf/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func writeFiles(t *testing.T, root string, files map[string]string) {
+	for name, content := range files {
+		path := filepath.Join(root, name)
+		assert.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		assert.NoError(t, ioutil.WriteFile(path, []byte(content), 0644))
+	}
+}
+
+func mockNow(times ...time.Time) func() {
+	original := now
+	now = func() time.Time {
+		t := times[0]
+		if len(times) > 1 {
+			times = times[1:]
+		}
+		return t
+	}
+	return func() { now = original }
+}
+
+func newCgroup(t *testing.T, root string, paths ...string) *Cgroup {
+	c := &Cgroup{Paths: paths, Root: root, Log: testutil.Logger{}}
+	assert.NoError(t, c.Init())
+	return c
+}
+
+func TestGatherV2(t *testing.T) {
+	root, err := ioutil.TempDir("", "cgroup")
+	assert.NoError(t, err)
+	defer os.RemoveAll(root)
+	writeFiles(t, root, map[string]string{
+		"cgroup.controllers":                        "cpu io memory pids\n",
+		"system.slice/nginx.service/cpu.stat":       "usage_usec 1000000\nuser_usec 800000\nsystem_usec 200000\nnr_periods 10\nnr_throttled 2\nthrottled_usec 5000\n",
+		"system.slice/nginx.service/cpu.max":        "50000 100000\n",
+		"system.slice/nginx.service/memory.current": "104857600\n",
+		"system.slice/nginx.service/memory.max":     "209715200\n",
+		"system.slice/nginx.service/memory.events":  "low 0\nhigh 0\nmax 3\noom 1\noom_kill 1\n",
+		"system.slice/nginx.service/io.stat":        "8:0 rbytes=1024 wbytes=2048 rios=1 wios=2 dbytes=0 dios=0\n8:16 rbytes=1024 wbytes=0 rios=1 wios=0 dbytes=0 dios=0\n",
+		"system.slice/sshd.service/cpu.stat":        "usage_usec 10\n",
+		"system.slice/sshd.service/cpu.max":         "max 100000\n",
+		"system.slice/sshd.service/memory.current":  "4096\n",
+		"system.slice/sshd.service/memory.max":      "max\n",
+	})
+	start := time.Unix(1620828427, 0)
+	defer mockNow(start, start, start.Add(10*time.Second))()
+	c := newCgroup(t, root, "system.slice/nginx.service", "system.slice/sshd.service")
+
+	var acc testutil.Accumulator
+	assert.NoError(t, c.Gather(&acc))
+	acc.AssertContainsTaggedFields(t, "cgroup", map[string]interface{}{
+		"cpu_usage_usec":        uint64(1000000),
+		"cpu_throttled_periods": uint64(2),
+		"cpu_throttled_usec":    uint64(5000),
+		"cpu_limit_cores":       0.5,
+		"memory_usage":          uint64(104857600),
+		"memory_limit":          uint64(209715200),
+		"memory_utilization":    float64(50),
+		"memory_oom_kills":      uint64(1),
+		"io_read_bytes":         uint64(2048),
+		"io_write_bytes":        uint64(2048),
+		"io_reads":              uint64(2),
+		"io_writes":             uint64(2),
+	}, map[string]string{"cgroup": "system.slice/nginx.service"})
+	// the unlimited cgroups have no limit nor utilization
+	acc.AssertContainsTaggedFields(t, "cgroup", map[string]interface{}{
+		"cpu_usage_usec": uint64(10),
+		"memory_usage":   uint64(4096),
+	}, map[string]string{"cgroup": "system.slice/sshd.service"})
+
+	// the cpu usage is a percent of one CPU since the previous gather
+	writeFiles(t, root, map[string]string{"system.slice/nginx.service/cpu.stat": "usage_usec 6000000\n"})
+	acc.ClearMetrics()
+	assert.NoError(t, c.Gather(&acc))
+	m, ok := acc.Get("cgroup")
+	assert.True(t, ok)
+	assert.Equal(t, "system.slice/nginx.service", m.Tags["cgroup"])
+	assert.Equal(t, float64(50), m.Fields["cpu_usage_percent"])
+}
+
+func TestGatherV1(t *testing.T) {
+	root, err := ioutil.TempDir("", "cgroup")
+	assert.NoError(t, err)
+	defer os.RemoveAll(root)
+	writeFiles(t, root, map[string]string{
+		"cpu,cpuacct/batch.slice/cpuacct.usage":                   "3000000000\n",
+		"cpu,cpuacct/batch.slice/cpu.stat":                        "nr_periods 10\nnr_throttled 4\nthrottled_time 7000000\n",
+		"cpu,cpuacct/batch.slice/cpu.cfs_quota_us":                "200000\n",
+		"cpu,cpuacct/batch.slice/cpu.cfs_period_us":               "100000\n",
+		"memory/batch.slice/memory.usage_in_bytes":                "1048576\n",
+		"memory/batch.slice/memory.limit_in_bytes":                "9223372036854771712\n",
+		"memory/batch.slice/memory.oom_control":                   "oom_kill_disable 0\nunder_oom 0\noom_kill 2\n",
+		"blkio/batch.slice/blkio.throttle.io_service_bytes":       "8:0 Read 4096\n8:0 Write 8192\n8:0 Sync 0\n8:0 Async 12288\n8:0 Total 12288\nTotal 12288\n",
+		"blkio/batch.slice/blkio.throttle.io_serviced":            "8:0 Read 1\n8:0 Write 2\n8:0 Total 3\nTotal 3\n",
+		"cpu,cpuacct/user.slice/user-1000.slice/cpuacct.usage":    "1000\n",
+		"memory/user.slice/user-1000.slice/memory.usage_in_bytes": "2048\n",
+	})
+	c := newCgroup(t, root, "batch.slice", "user-1000.slice", "missing.slice")
+
+	var acc testutil.Accumulator
+	assert.NoError(t, c.Gather(&acc))
+	acc.AssertContainsTaggedFields(t, "cgroup", map[string]interface{}{
+		"cpu_usage_usec":        uint64(3000000),
+		"cpu_throttled_periods": uint64(4),
+		"cpu_throttled_usec":    uint64(7000),
+		"cpu_limit_cores":       float64(2),
+		"memory_usage":          uint64(1048576),
+		"memory_oom_kills":      uint64(2),
+		"io_read_bytes":         uint64(4096),
+		"io_write_bytes":        uint64(8192),
+		"io_reads":              uint64(1),
+		"io_writes":             uint64(2),
+	}, map[string]string{"cgroup": "batch.slice"})
+	// the slices are expanded to their path in the hierarchy
+	acc.AssertContainsTaggedFields(t, "cgroup", map[string]interface{}{
+		"cpu_usage_usec": uint64(1),
+		"memory_usage":   uint64(2048),
+	}, map[string]string{"cgroup": "user.slice/user-1000.slice"})
+	assert.Equal(t, 2, len(acc.Metrics))
+	assert.Empty(t, acc.Errors)
+}
+
+func TestGatherGlob(t *testing.T) {
+	root, err := ioutil.TempDir("", "cgroup")
+	assert.NoError(t, err)
+	defer os.RemoveAll(root)
+	writeFiles(t, root, map[string]string{
+		"cgroup.controllers":                           "cpu memory\n",
+		"system.slice/worker@1.service/memory.current": "1\n",
+		"system.slice/worker@2.service/memory.current": "2\n",
+		"system.slice/nginx.service/memory.current":    "3\n",
+	})
+	c := newCgroup(t, root, "system.slice/worker@*.service", "system.slice/worker@1.service")
+
+	var acc testutil.Accumulator
+	assert.NoError(t, c.Gather(&acc))
+	assert.Equal(t, 2, len(acc.Metrics))
+	acc.AssertContainsTaggedFields(t, "cgroup", map[string]interface{}{"memory_usage": uint64(1)},
+		map[string]string{"cgroup": "system.slice/worker@1.service"})
+	acc.AssertContainsTaggedFields(t, "cgroup", map[string]interface{}{"memory_usage": uint64(2)},
+		map[string]string{"cgroup": "system.slice/worker@2.service"})
+}
+
+func TestExpandSlice(t *testing.T) {
+	assert.Equal(t, "system.slice", expandSlice("system.slice"))
+	assert.Equal(t, "user.slice/user-1000.slice", expandSlice("user-1000.slice"))
+	assert.Equal(t, "app.slice/app-web.slice/app-web-blue.slice", expandSlice("app-web-blue.slice"))
+	assert.Equal(t, "system.slice/nginx.service", expandSlice("system.slice/nginx.service"))
+	assert.Equal(t, "nginx.service", expandSlice("nginx.service"))
+	assert.Equal(t, "-.slice", expandSlice("-.slice"))
+}
+
+func TestInit(t *testing.T) {
+	c := &Cgroup{}
+	assert.Error(t, c.Init())
+
+	c = &Cgroup{Paths: []string{"system.slice"}}
+	assert.NoError(t, c.Init())
+	assert.Equal(t, defaultRoot, c.Root)
+}
@@ -0,0 +1,257 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package cgroup
+
+import (
+	"bufio"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// the limits of cgroup v1 above this value are "unlimited", the kernel reports the largest page aligned int64
+const unlimitedV1 = uint64(1) << 62
+
+// hierarchy reads the stats of a cgroup, the files and their format differ between cgroup v1 and v2, the fields
+// reported are the same.
+type hierarchy interface {
+	// base is the directory the cgroup paths are resolved against
+	base() string
+	stats(path string) (map[string]interface{}, error)
+}
+
+// detectHierarchy returns v2 when the unified hierarchy is mounted at the root, v1 otherwise. On the hybrid layout
+// the controllers are still bound to v1.
+func detectHierarchy(root string) hierarchy {
+	if _, err := os.Stat(filepath.Join(root, "cgroup.controllers")); err == nil {
+		return v2{root: root}
+	}
+	return v1{root: root}
+}
+
+type v2 struct {
+	root string
+}
+
+func (h v2) base() string {
+	return h.root
+}
+
+func (h v2) stats(path string) (map[string]interface{}, error) {
+	dir := filepath.Join(h.root, path)
+	if _, err := os.Stat(dir); err != nil {
+		return nil, err
+	}
+	fields := map[string]interface{}{}
+
+	// cpu.stat is reported even when the cpu controller is disabled
+	if stat, err := readKeyValues(filepath.Join(dir, "cpu.stat")); err == nil {
+		setField(fields, "cpu_usage_usec", stat, "usage_usec")
+		setField(fields, "cpu_throttled_periods", stat, "nr_throttled")
+		setField(fields, "cpu_throttled_usec", stat, "throttled_usec")
+	}
+	// "max 100000" or "<quota> <period>"
+	if max, err := readString(filepath.Join(dir, "cpu.max")); err == nil {
+		parts := strings.Fields(max)
+		if len(parts) == 2 && parts[0] != "max" {
+			quota, qerr := strconv.ParseUint(parts[0], 10, 64)
+			period, perr := strconv.ParseUint(parts[1], 10, 64)
+			if qerr == nil && perr == nil && period > 0 {
+				fields["cpu_limit_cores"] = float64(quota) / float64(period)
+			}
+		}
+	}
+
+	if usage, err := readUint(filepath.Join(dir, "memory.current")); err == nil {
+		fields["memory_usage"] = usage
+	}
+	if max, err := readString(filepath.Join(dir, "memory.max")); err == nil && max != "max" {
+		if limit, err := strconv.ParseUint(max, 10, 64); err == nil {
+			fields["memory_limit"] = limit
+		}
+	}
+	if events, err := readKeyValues(filepath.Join(dir, "memory.events")); err == nil {
+		setField(fields, "memory_oom_kills", events, "oom_kill")
+	}
+
+	// one line per device: "8:0 rbytes=1024 wbytes=2048 rios=1 wios=2 dbytes=0 dios=0"
+	if lines, err := readLines(filepath.Join(dir, "io.stat")); err == nil {
+		io := map[string]uint64{}
+		for _, line := range lines {
+			for _, kv := range strings.Fields(line)[1:] {
+				parts := strings.SplitN(kv, "=", 2)
+				if len(parts) != 2 {
+					continue
+				}
+				if v, err := strconv.ParseUint(parts[1], 10, 64); err == nil {
+					io[parts[0]] += v
+				}
+			}
+		}
+		fields["io_read_bytes"] = io["rbytes"]
+		fields["io_write_bytes"] = io["wbytes"]
+		fields["io_reads"] = io["rios"]
+		fields["io_writes"] = io["wios"]
+	}
+	return fields, nil
+}
+
+type v1 struct {
+	root string
+}
+
+// base is the memory controller, the cgroups of the other controllers mirror it on systemd hosts
+func (h v1) base() string {
+	return filepath.Join(h.root, "memory")
+}
+
+// controller returns the directory of the cgroup in the first mounted controller of the names, e.g. cpuacct is
+// mounted as "cpu,cpuacct" on most distributions.
+func (h v1) controller(path string, names ...string) (string, bool) {
+	for _, name := range names {
+		dir := filepath.Join(h.root, name, path)
+		if _, err := os.Stat(dir); err == nil {
+			return dir, true
+		}
+	}
+	return "", false
+}
+
+func (h v1) stats(path string) (map[string]interface{}, error) {
+	fields := map[string]interface{}{}
+	found := false
+
+	if dir, ok := h.controller(path, "cpu,cpuacct", "cpuacct"); ok {
+		found = true
+		if usage, err := readUint(filepath.Join(dir, "cpuacct.usage")); err == nil {
+			fields["cpu_usage_usec"] = usage / 1000
+		}
+	}
+	if dir, ok := h.controller(path, "cpu,cpuacct", "cpu"); ok {
+		found = true
+		if stat, err := readKeyValues(filepath.Join(dir, "cpu.stat")); err == nil {
+			setField(fields, "cpu_throttled_periods", stat, "nr_throttled")
+			if v, ok := stat["throttled_time"]; ok {
+				fields["cpu_throttled_usec"] = v / 1000
+			}
+		}
+		quota, qerr := readString(filepath.Join(dir, "cpu.cfs_quota_us"))
+		period, perr := readUint(filepath.Join(dir, "cpu.cfs_period_us"))
+		// the quota is -1 when unlimited
+		if q, err := strconv.ParseInt(quota, 10, 64); qerr == nil && perr == nil && err == nil && q > 0 && period > 0 {
+			fields["cpu_limit_cores"] = float64(q) / float64(period)
+		}
+	}
+
+	if dir, ok := h.controller(path, "memory"); ok {
+		found = true
+		if usage, err := readUint(filepath.Join(dir, "memory.usage_in_bytes")); err == nil {
+			fields["memory_usage"] = usage
+		}
+		if limit, err := readUint(filepath.Join(dir, "memory.limit_in_bytes")); err == nil && limit < unlimitedV1 {
+			fields["memory_limit"] = limit
+		}
+		// oom_kill is reported by the kernels 4.13 and later
+		if oom, err := readKeyValues(filepath.Join(dir, "memory.oom_control")); err == nil {
+			setField(fields, "memory_oom_kills", oom, "oom_kill")
+		}
+	}
+
+	if dir, ok := h.controller(path, "blkio"); ok {
+		found = true
+		// one line per device and operation: "8:0 Read 1024", and a "Total" line
+		if bytes, err := readBlkio(filepath.Join(dir, "blkio.throttle.io_service_bytes")); err == nil {
+			fields["io_read_bytes"] = bytes["Read"]
+			fields["io_write_bytes"] = bytes["Write"]
+		}
+		if ops, err := readBlkio(filepath.Join(dir, "blkio.throttle.io_serviced")); err == nil {
+			fields["io_reads"] = ops["Read"]
+			fields["io_writes"] = ops["Write"]
+		}
+	}
+
+	if !found {
+		return nil, fmt.Errorf("no cgroup controller has %s", path)
+	}
+	return fields, nil
+}
+
+func setField(fields map[string]interface{}, name string, values map[string]uint64, key string) {
+	if v, ok := values[key]; ok {
+		fields[name] = v
+	}
+}
+
+func readLines(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	var lines []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if line := strings.TrimSpace(scanner.Text()); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines, scanner.Err()
+}
+
+func readString(path string) (string, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(b)), nil
+}
+
+func readUint(path string) (uint64, error) {
+	s, err := readString(path)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseUint(s, 10, 64)
+}
+
+// readKeyValues reads the "key value" lines of files like cpu.stat and memory.events.
+func readKeyValues(path string) (map[string]uint64, error) {
+	lines, err := readLines(path)
+	if err != nil {
+		return nil, err
+	}
+	values := map[string]uint64{}
+	for _, line := range lines {
+		parts := strings.Fields(line)
+		if len(parts) != 2 {
+			continue
+		}
+		if v, err := strconv.ParseUint(parts[1], 10, 64); err == nil {
+			values[parts[0]] = v
+		}
+	}
+	return values, nil
+}
+
+// readBlkio sums the per device values of a blkio file by operation.
+func readBlkio(path string) (map[string]uint64, error) {
+	lines, err := readLines(path)
+	if err != nil {
+		return nil, err
+	}
+	values := map[string]uint64{}
+	for _, line := range lines {
+		parts := strings.Fields(line)
+		if len(parts) != 3 {
+			continue
+		}
+		if v, err := strconv.ParseUint(parts[2], 10, 64); err == nil {
+			values[parts[1]] += v
+		}
+	}
+	return values, nil
+}
@@ -0,0 +1,332 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package cloudwatch_query
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/aws/amazon-cloudwatch-agent/cfg/agentinfo"
+	configaws "github.com/aws/amazon-cloudwatch-agent/cfg/aws"
+	"github.com/aws/amazon-cloudwatch-agent/handlers"
+	"github.com/aws/amazon-cloudwatch-agent/internal"
+	"github.com/aws/amazon-cloudwatch-agent/internal/audit"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cloudwatch"
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/plugins/inputs"
+)
+
+const (
+	pluginName    = "cloudwatch_query"
+	defaultPeriod = time.Minute
+	defaultDelay  = 3 * time.Minute
+	defaultStat   = "Average"
+	// the queries of a GetMetricData request
+	maxQueries = 500
+)
+
+var sampleConfig = `
+  ## Amazon REGION of the queried metrics
+  region = "us-east-1"
+
+  ## Amazon Credentials, loaded in the same order as the cloudwatch output
+  #access_key = ""
+  #secret_key = ""
+  #token = ""
+  #role_arn = ""
+  #profile = ""
+  #shared_credential_file = ""
+
+  ## The host name of the host running the queries, the other hosts sharing the configuration query nothing.
+  ## Every host runs the queries when empty.
+  # leader_host = "ip-10-0-0-1"
+
+  ## The period of the queried datapoints, the latest period ended delay ago is queried at each collection.
+  # period = "60s"
+  # delay = "3m"
+
+  ## The metrics and the expressions queried, the queries without return_data = false are reported, named by
+  ## their name, or their id without name.
+  [[inputs.cloudwatch_query.query]]
+    id = "errors"
+    namespace = "MyApp"
+    metric_name = "Errors"
+    stat = "Sum"
+    return_data = false
+    [inputs.cloudwatch_query.query.dimensions]
+      Service = "checkout"
+  [[inputs.cloudwatch_query.query]]
+    id = "requests"
+    namespace = "MyApp"
+    metric_name = "Requests"
+    stat = "Sum"
+    return_data = false
+    [inputs.cloudwatch_query.query.dimensions]
+      Service = "checkout"
+  [[inputs.cloudwatch_query.query]]
+    id = "error_ratio"
+    expression = "100 * errors / requests"
+    name = "checkout_error_ratio"
+`
+
+// getMetricDataAPI is the subset of the cloudwatch client used to query the metrics.
+type getMetricDataAPI interface {
+	GetMetricData(input *cloudwatch.GetMetricDataInput) (*cloudwatch.GetMetricDataOutput, error)
+}
+
+// Query is a metric or a metric math expression of GetMetricData.
+type Query struct {
+	ID         string            `toml:"id"`
+	Name       string            `toml:"name"`
+	Expression string            `toml:"expression"`
+	Namespace  string            `toml:"namespace"`
+	MetricName string            `toml:"metric_name"`
+	Dimensions map[string]string `toml:"dimensions"`
+	Stat       string            `toml:"stat"`
+	ReturnData *bool             `toml:"return_data"`
+	// the dimension the results of the query are tagged with their label, for the expressions returning several
+	// series like SEARCH
+	LabelDimension string `toml:"label_dimension"`
+}
+
+// CloudWatchQuery queries CloudWatch with GetMetricData and reports the results as the metrics of the host, so the
+// metrics computed from the metrics of a fleet, like the ratio of the errors of all the hosts, are published by the
+// agent of one host without a pipeline of its own.
+type CloudWatchQuery struct {
+	Region           string            `toml:"region"`
+	EndpointOverride string            `toml:"endpoint_override"`
+	AccessKey        string            `toml:"access_key"`
+	SecretKey        string            `toml:"secret_key"`
+	RoleARN          string            `toml:"role_arn"`
+	Profile          string            `toml:"profile"`
+	Filename         string            `toml:"shared_credential_file"`
+	Token            string            `toml:"token"`
+	LeaderHost       string            `toml:"leader_host"`
+	Period           internal.Duration `toml:"period"`
+	Delay            internal.Duration `toml:"delay"`
+	Queries          []Query           `toml:"query"`
+
+	Log telegraf.Logger `toml:"-"`
+
+	// the client, the host name and the clock, replaced in tests
+	svc      getMetricDataAPI
+	hostname func() (string, error)
+	now      func() time.Time
+
+	leader  bool
+	queries []*cloudwatch.MetricDataQuery
+	byID    map[string]Query
+	// the end of the last period reported by query id, a period is only reported once when the collection interval
+	// is shorter than the period
+	lastReported map[string]time.Time
+}
+
+func (c *CloudWatchQuery) SampleConfig() string {
+	return sampleConfig
+}
+
+func (c *CloudWatchQuery) Description() string {
+	return "Query CloudWatch with GetMetricData and report the results."
+}
+
+func (c *CloudWatchQuery) Init() error {
+	if len(c.Queries) == 0 {
+		return fmt.Errorf("%s: no query", pluginName)
+	}
+	if len(c.Queries) > maxQueries {
+		return fmt.Errorf("%s: %d queries, at most %d are supported", pluginName, len(c.Queries), maxQueries)
+	}
+	if c.Period.Duration <= 0 {
+		c.Period.Duration = defaultPeriod
+	}
+	if c.Period.Duration%time.Minute != 0 && c.Period.Duration != time.Second && c.Period.Duration != 5*time.Second &&
+		c.Period.Duration != 10*time.Second && c.Period.Duration != 30*time.Second {
+		return fmt.Errorf("%s: period %v is invalid, it is 1s, 5s, 10s, 30s or a multiple of 60s", pluginName, c.Period.Duration)
+	}
+	if c.Delay.Duration < 0 {
+		return fmt.Errorf("%s: delay %v is negative", pluginName, c.Delay.Duration)
+	}
+	if c.Delay.Duration == 0 {
+		c.Delay.Duration = defaultDelay
+	}
+	c.byID = map[string]Query{}
+	c.queries = nil
+	for _, q := range c.Queries {
+		query, err := c.metricDataQuery(q)
+		if err != nil {
+			return err
+		}
+		c.byID[q.ID] = q
+		c.queries = append(c.queries, query)
+	}
+	if c.hostname == nil {
+		c.hostname = os.Hostname
+	}
+	if c.now == nil {
+		c.now = time.Now
+	}
+	c.leader = true
+	if c.LeaderHost != "" {
+		hostname, err := c.hostname()
+		if err != nil {
+			return fmt.Errorf("%s: failed to get the host name to compare to the leader_host: %v", pluginName, err)
+		}
+		c.leader = strings.EqualFold(hostname, c.LeaderHost)
+		if !c.leader {
+			c.Log.Infof("The host %s is not the leader host %s, the queries are not run", hostname, c.LeaderHost)
+		}
+	}
+	c.lastReported = map[string]time.Time{}
+	if c.leader && c.svc == nil {
+		c.svc = c.newClient()
+	}
+	return nil
+}
+
+func (c *CloudWatchQuery) metricDataQuery(q Query) (*cloudwatch.MetricDataQuery, error) {
+	if q.ID == "" {
+		return nil, fmt.Errorf("%s: a query has no id", pluginName)
+	}
+	if _, ok := c.byID[q.ID]; ok {
+		return nil, fmt.Errorf("%s: the id %s is of several queries", pluginName, q.ID)
+	}
+	returnData := q.ReturnData == nil || *q.ReturnData
+	query := &cloudwatch.MetricDataQuery{
+		Id:         aws.String(q.ID),
+		ReturnData: aws.Bool(returnData),
+	}
+	switch {
+	case q.Expression != "" && q.MetricName != "":
+		return nil, fmt.Errorf("%s: the query %s has both an expression and a metric_name", pluginName, q.ID)
+	case q.Expression != "":
+		query.Expression = aws.String(q.Expression)
+		query.Period = aws.Int64(int64(c.Period.Duration.Seconds()))
+	case q.MetricName != "" && q.Namespace != "":
+		stat := q.Stat
+		if stat == "" {
+			stat = defaultStat
+		}
+		metric := &cloudwatch.Metric{
+			Namespace:  aws.String(q.Namespace),
+			MetricName: aws.String(q.MetricName),
+		}
+		for name, value := range q.Dimensions {
+			metric.Dimensions = append(metric.Dimensions, &cloudwatch.Dimension{
+				Name:  aws.String(name),
+				Value: aws.String(value),
+			})
+		}
+		query.MetricStat = &cloudwatch.MetricStat{
+			Metric: metric,
+			Period: aws.Int64(int64(c.Period.Duration.Seconds())),
+			Stat:   aws.String(stat),
+		}
+	default:
+		return nil, fmt.Errorf("%s: the query %s has neither an expression nor a namespace and a metric_name", pluginName, q.ID)
+	}
+	return query, nil
+}
+
+func (c *CloudWatchQuery) newClient() getMetricDataAPI {
+	credentialConfig := &configaws.CredentialConfig{
+		Region:    c.Region,
+		AccessKey: c.AccessKey,
+		SecretKey: c.SecretKey,
+		RoleARN:   c.RoleARN,
+		Profile:   c.Profile,
+		Filename:  c.Filename,
+		Token:     c.Token,
+	}
+	svc := cloudwatch.New(
+		credentialConfig.Credentials(),
+		&aws.Config{
+			Endpoint: aws.String(c.EndpointOverride),
+			LogLevel: configaws.SDKLogLevel(),
+			Logger:   configaws.SDKLogger{},
+		})
+	svc.Handlers.Build.PushBackNamed(handlers.NewCustomHeaderHandler("User-Agent", agentinfo.UserAgent("")))
+	audit.EndpointConfigured(pluginName, c.Region, c.EndpointOverride)
+	return svc
+}
+
+// Gather queries the latest period ended delay ago, the datapoints of CloudWatch are only complete some time after
+// the end of their period. The results are reported at the start of the period, like the datapoints queried.
+func (c *CloudWatchQuery) Gather(acc telegraf.Accumulator) error {
+	if !c.leader {
+		return nil
+	}
+	end := c.now().Add(-c.Delay.Duration).Truncate(c.Period.Duration)
+	start := end.Add(-c.Period.Duration)
+	input := &cloudwatch.GetMetricDataInput{
+		StartTime:         aws.Time(start),
+		EndTime:           aws.Time(end),
+		MetricDataQueries: c.queries,
+		ScanBy:            aws.String(cloudwatch.ScanByTimestampDescending),
+	}
+	reported := map[string]bool{}
+	for {
+		output, err := c.svc.GetMetricData(input)
+		if err != nil {
+			return fmt.Errorf("failed to query the metrics: %v", err)
+		}
+		for _, result := range output.MetricDataResults {
+			ok, err := c.report(acc, result, end)
+			if err != nil {
+				acc.AddError(err)
+			} else if ok {
+				reported[aws.StringValue(result.Id)] = true
+			}
+		}
+		for _, message := range output.Messages {
+			c.Log.Warnf("GetMetricData: %s %s", aws.StringValue(message.Code), aws.StringValue(message.Value))
+		}
+		if aws.StringValue(output.NextToken) == "" {
+			break
+		}
+		input.NextToken = output.NextToken
+	}
+	for id := range reported {
+		c.lastReported[id] = end
+	}
+	return nil
+}
+
+// report adds the latest datapoint of the result, if the period of the result wasn't reported yet.
+func (c *CloudWatchQuery) report(acc telegraf.Accumulator, result *cloudwatch.MetricDataResult, end time.Time) (bool, error) {
+	id := aws.StringValue(result.Id)
+	q, ok := c.byID[id]
+	if !ok {
+		return false, fmt.Errorf("%s: unexpected result of the id %s", pluginName, id)
+	}
+	if status := aws.StringValue(result.StatusCode); status != cloudwatch.StatusCodeComplete {
+		c.Log.Debugf("The result of the query %s is %s", id, status)
+	}
+	if len(result.Values) == 0 || !c.lastReported[id].Before(end) {
+		// no datapoint in the period yet, or the period was reported
+		return false, nil
+	}
+	name := q.Name
+	if name == "" {
+		name = q.ID
+	}
+	tags := map[string]string{}
+	if q.LabelDimension != "" && aws.StringValue(result.Label) != "" {
+		tags[q.LabelDimension] = aws.StringValue(result.Label)
+	}
+	t := end.Add(-c.Period.Duration)
+	if len(result.Timestamps) > 0 {
+		t = aws.TimeValue(result.Timestamps[0])
+	}
+	acc.AddFields(name, map[string]interface{}{"value": aws.Float64Value(result.Values[0])}, tags, t)
+	return true, nil
+}
+
+func init() {
+	inputs.Add(pluginName, func() telegraf.Input {
+		return &CloudWatchQuery{}
+	})
+}
@@ -0,0 +1,165 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package cloudwatch_query
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aws/amazon-cloudwatch-agent/internal"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cloudwatch"
+	"github.com/influxdata/telegraf/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockGetMetricData struct {
+	inputs  []cloudwatch.GetMetricDataInput
+	outputs []*cloudwatch.GetMetricDataOutput
+	err     error
+}
+
+func (m *mockGetMetricData) GetMetricData(input *cloudwatch.GetMetricDataInput) (*cloudwatch.GetMetricDataOutput, error) {
+	m.inputs = append(m.inputs, *input)
+	if m.err != nil {
+		return nil, m.err
+	}
+	output := m.outputs[0]
+	m.outputs = m.outputs[1:]
+	return output, nil
+}
+
+func result(id, label string, t time.Time, value float64) *cloudwatch.MetricDataResult {
+	return &cloudwatch.MetricDataResult{
+		Id:         aws.String(id),
+		Label:      aws.String(label),
+		StatusCode: aws.String(cloudwatch.StatusCodeComplete),
+		Timestamps: []*time.Time{aws.Time(t)},
+		Values:     []*float64{aws.Float64(value)},
+	}
+}
+
+func newCloudWatchQuery(t *testing.T, svc getMetricDataAPI, now *time.Time, queries ...Query) *CloudWatchQuery {
+	c := &CloudWatchQuery{
+		Queries:  queries,
+		Log:      testutil.Logger{},
+		svc:      svc,
+		hostname: func() (string, error) { return "leader", nil },
+		now:      func() time.Time { return *now },
+	}
+	require.NoError(t, c.Init())
+	return c
+}
+
+func TestGather(t *testing.T) {
+	now := time.Date(2021, 5, 12, 14, 10, 30, 0, time.UTC)
+	start := time.Date(2021, 5, 12, 14, 6, 0, 0, time.UTC)
+	svc := &mockGetMetricData{outputs: []*cloudwatch.GetMetricDataOutput{
+		{MetricDataResults: []*cloudwatch.MetricDataResult{result("error_ratio", "error_ratio", start, 2.5)}, NextToken: aws.String("next")},
+		{MetricDataResults: []*cloudwatch.MetricDataResult{
+			result("by_az", "us-east-1a", start, 10),
+			result("by_az", "us-east-1b", start, 20),
+		}},
+	}}
+	c := newCloudWatchQuery(t, svc, &now,
+		Query{ID: "errors", Namespace: "MyApp", MetricName: "Errors", Stat: "Sum", ReturnData: aws.Bool(false), Dimensions: map[string]string{"Service": "checkout"}},
+		Query{ID: "requests", Namespace: "MyApp", MetricName: "Requests", Stat: "Sum", ReturnData: aws.Bool(false)},
+		Query{ID: "error_ratio", Expression: "100 * errors / requests", Name: "checkout_error_ratio"},
+		Query{ID: "by_az", Expression: `SEARCH('{MyApp,AvailabilityZone} MetricName="Requests"', 'Sum')`, LabelDimension: "AvailabilityZone"},
+	)
+
+	var acc testutil.Accumulator
+	require.NoError(t, c.Gather(&acc))
+	// the latest minute ended 3 minutes ago
+	require.Len(t, svc.inputs, 2)
+	assert.Equal(t, start, aws.TimeValue(svc.inputs[0].StartTime))
+	assert.Equal(t, start.Add(time.Minute), aws.TimeValue(svc.inputs[0].EndTime))
+	assert.Nil(t, svc.inputs[0].NextToken)
+	assert.Equal(t, "next", aws.StringValue(svc.inputs[1].NextToken))
+	queries := svc.inputs[0].MetricDataQueries
+	require.Len(t, queries, 4)
+	assert.False(t, aws.BoolValue(queries[0].ReturnData))
+	assert.Equal(t, "Sum", aws.StringValue(queries[0].MetricStat.Stat))
+	assert.Equal(t, int64(60), aws.Int64Value(queries[0].MetricStat.Period))
+	assert.Equal(t, []*cloudwatch.Dimension{{Name: aws.String("Service"), Value: aws.String("checkout")}}, queries[0].MetricStat.Metric.Dimensions)
+	assert.True(t, aws.BoolValue(queries[2].ReturnData))
+	assert.Equal(t, "100 * errors / requests", aws.StringValue(queries[2].Expression))
+
+	require.Len(t, acc.Metrics, 3)
+	acc.AssertContainsTaggedFields(t, "checkout_error_ratio", map[string]interface{}{"value": 2.5}, map[string]string{})
+	acc.AssertContainsTaggedFields(t, "by_az", map[string]interface{}{"value": float64(10)}, map[string]string{"AvailabilityZone": "us-east-1a"})
+	acc.AssertContainsTaggedFields(t, "by_az", map[string]interface{}{"value": float64(20)}, map[string]string{"AvailabilityZone": "us-east-1b"})
+	assert.Equal(t, start, acc.Metrics[0].Time)
+
+	// the period was reported, the next collection of the same minute reports nothing
+	now = now.Add(20 * time.Second)
+	svc.outputs = []*cloudwatch.GetMetricDataOutput{{MetricDataResults: []*cloudwatch.MetricDataResult{result("error_ratio", "error_ratio", start, 3)}}}
+	acc.ClearMetrics()
+	require.NoError(t, c.Gather(&acc))
+	assert.Empty(t, acc.Metrics)
+
+	// the query without datapoint in the next minute is reported when it has one
+	now = now.Add(time.Minute)
+	next := start.Add(time.Minute)
+	svc.outputs = []*cloudwatch.GetMetricDataOutput{{MetricDataResults: []*cloudwatch.MetricDataResult{
+		{Id: aws.String("error_ratio"), StatusCode: aws.String(cloudwatch.StatusCodeComplete)},
+		result("by_az", "us-east-1a", next, 5),
+	}}}
+	require.NoError(t, c.Gather(&acc))
+	require.Len(t, acc.Metrics, 1)
+	acc.AssertContainsTaggedFields(t, "by_az", map[string]interface{}{"value": float64(5)}, map[string]string{"AvailabilityZone": "us-east-1a"})
+	svc.outputs = []*cloudwatch.GetMetricDataOutput{{MetricDataResults: []*cloudwatch.MetricDataResult{result("error_ratio", "error_ratio", next, 4)}}}
+	acc.ClearMetrics()
+	require.NoError(t, c.Gather(&acc))
+	require.Len(t, acc.Metrics, 1)
+	acc.AssertContainsTaggedFields(t, "checkout_error_ratio", map[string]interface{}{"value": float64(4)}, map[string]string{})
+}
+
+func TestGatherNotLeader(t *testing.T) {
+	now := time.Now()
+	svc := &mockGetMetricData{}
+	c := &CloudWatchQuery{
+		LeaderHost: "other",
+		Queries:    []Query{{ID: "ratio", Expression: "1"}},
+		Log:        testutil.Logger{},
+		svc:        svc,
+		hostname:   func() (string, error) { return "leader", nil },
+		now:        func() time.Time { return now },
+	}
+	require.NoError(t, c.Init())
+	var acc testutil.Accumulator
+	require.NoError(t, c.Gather(&acc))
+	assert.Empty(t, svc.inputs)
+
+	c.LeaderHost = "LEADER"
+	require.NoError(t, c.Init())
+	svc.outputs = []*cloudwatch.GetMetricDataOutput{{}}
+	require.NoError(t, c.Gather(&acc))
+	assert.Len(t, svc.inputs, 1)
+}
+
+func TestGatherError(t *testing.T) {
+	now := time.Now()
+	c := newCloudWatchQuery(t, &mockGetMetricData{err: errors.New("throttled")}, &now, Query{ID: "ratio", Expression: "1"})
+	var acc testutil.Accumulator
+	assert.Error(t, c.Gather(&acc))
+}
+
+func TestInitInvalid(t *testing.T) {
+	for name, c := range map[string]*CloudWatchQuery{
+		"no query":       {},
+		"no id":          {Queries: []Query{{Expression: "1"}}},
+		"duplicate id":   {Queries: []Query{{ID: "a", Expression: "1"}, {ID: "a", Expression: "2"}}},
+		"no metric":      {Queries: []Query{{ID: "a", Namespace: "MyApp"}}},
+		"both":           {Queries: []Query{{ID: "a", Expression: "1", Namespace: "MyApp", MetricName: "Errors"}}},
+		"period":         {Period: internal.Duration{Duration: 90 * time.Second}, Queries: []Query{{ID: "a", Expression: "1"}}},
+		"negative delay": {Delay: internal.Duration{Duration: -time.Minute}, Queries: []Query{{ID: "a", Expression: "1"}}},
+	} {
+		c.Log = testutil.Logger{}
+		c.svc = &mockGetMetricData{}
+		assert.Error(t, c.Init(), name)
+	}
+}
@@ -0,0 +1,138 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package collectd_listener
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"collectd.org/network"
+	"github.com/influxdata/telegraf"
+)
+
+// the auth file is checked for changes at most this often, on the lookups of the signed and encrypted packets
+const authFileCheckInterval = time.Second
+
+func parseSecurityLevel(level string) (network.SecurityLevel, bool) {
+	switch level {
+	case "", "none":
+		return network.None, true
+	case "sign":
+		return network.Sign, true
+	case "encrypt":
+		return network.Encrypt, true
+	}
+	return network.None, false
+}
+
+// authFile looks up the passwords of the users in the auth file of collectd, "<user>: <password>" by line. The file
+// is loaded again once its modification time or size changed, so the shared secrets are rotated without a restart.
+// The passwords loaded last are kept while the file can't be read, e.g. while it's replaced.
+type authFile struct {
+	path string
+	log  telegraf.Logger
+
+	mu        sync.Mutex
+	passwords map[string]string
+	modTime   time.Time
+	size      int64
+	checked   time.Time
+	failed    bool
+}
+
+func newAuthFile(path string, log telegraf.Logger) *authFile {
+	return &authFile{path: path, log: log}
+}
+
+// Password implements network.PasswordLookup.
+func (a *authFile) Password(user string) (string, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if now := time.Now(); now.Sub(a.checked) >= authFileCheckInterval {
+		a.checked = now
+		a.reload()
+	}
+	password, ok := a.passwords[user]
+	if !ok {
+		return "", fmt.Errorf("no such user: %q", user)
+	}
+	return password, nil
+}
+
+func (a *authFile) reload() {
+	fi, err := os.Stat(a.path)
+	if err == nil && fi.ModTime().Equal(a.modTime) && fi.Size() == a.size {
+		return
+	}
+	var passwords map[string]string
+	if err == nil {
+		passwords, err = readAuthFile(a.path)
+	}
+	if err != nil {
+		if !a.failed {
+			a.log.Errorf("Unable to load the auth file %s, the passwords loaded last are kept: %v", a.path, err)
+		}
+		a.failed = true
+		return
+	}
+	if a.passwords != nil {
+		a.log.Infof("Reloaded the auth file %s with %d users", a.path, len(passwords))
+	}
+	a.passwords, a.modTime, a.size, a.failed = passwords, fi.ModTime(), fi.Size(), false
+}
+
+func readAuthFile(path string) (map[string]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	passwords := map[string]string{}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.SplitN(strings.TrimSpace(scanner.Text()), ":", 2)
+		if len(fields) != 2 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		passwords[strings.TrimSpace(fields[0])] = strings.TrimSpace(fields[1])
+	}
+	return passwords, scanner.Err()
+}
+
+// packetSecurity returns the security level of the packet and its user. A packet is signed when it starts with a
+// signature, which covers all its parts, and encrypted when it's a single encrypted part, the parts following an
+// encrypted part aren't.
+func packetSecurity(packet []byte) (network.SecurityLevel, string) {
+	if len(packet) < partHeaderSize {
+		return network.None, ""
+	}
+	length := int(binary.BigEndian.Uint16(packet[2:]))
+	if length > len(packet) {
+		return network.None, ""
+	}
+	switch binary.BigEndian.Uint16(packet) {
+	case partSignSHA256:
+		// the hmac of 32 bytes is followed by the user
+		if length < partHeaderSize+32 {
+			return network.None, ""
+		}
+		return network.Sign, string(packet[partHeaderSize+32 : length])
+	case partEncryptAES256:
+		// the length of the user on 2 bytes is followed by the user
+		if length != len(packet) || length < partHeaderSize+2 {
+			return network.None, ""
+		}
+		userLength := int(binary.BigEndian.Uint16(packet[partHeaderSize:]))
+		if partHeaderSize+2+userLength > length {
+			return network.None, ""
+		}
+		return network.Encrypt, string(packet[partHeaderSize+2 : partHeaderSize+2+userLength])
+	}
+	return network.None, ""
+}
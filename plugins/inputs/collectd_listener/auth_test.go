@@ -0,0 +1,116 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package collectd_listener
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"collectd.org/api"
+	"collectd.org/network"
+	"github.com/influxdata/telegraf/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+// securedPacket is a packet of a gauge signed or encrypted with the password of the user, or neither.
+func securedPacket(t *testing.T, level network.SecurityLevel, user, password string) []byte {
+	b := network.NewBuffer(0)
+	switch level {
+	case network.Sign:
+		b.Sign(user, password)
+	case network.Encrypt:
+		b.Encrypt(user, password)
+	}
+	vl := &api.ValueList{
+		Identifier: api.Identifier{Host: "web", Plugin: "cpu", Type: "gauge"},
+		Time:       time.Now(),
+		Interval:   10 * time.Second,
+		Values:     []api.Value{api.Gauge(1)},
+	}
+	assert.NoError(t, b.Write(context.Background(), vl))
+	p, err := b.Bytes()
+	assert.NoError(t, err)
+	return p
+}
+
+func TestPacketSecurity(t *testing.T) {
+	level, user := packetSecurity(securedPacket(t, network.Sign, "alice", "secret"))
+	assert.Equal(t, network.Sign, level)
+	assert.Equal(t, "alice", user)
+	level, user = packetSecurity(securedPacket(t, network.Encrypt, "bob", "secret"))
+	assert.Equal(t, network.Encrypt, level)
+	assert.Equal(t, "bob", user)
+	level, user = packetSecurity(securedPacket(t, network.None, "", ""))
+	assert.Equal(t, network.None, level)
+	assert.Equal(t, "", user)
+
+	// the parts following an encrypted part aren't encrypted
+	encrypted := securedPacket(t, network.Encrypt, "bob", "secret")
+	level, _ = packetSecurity(packet(encrypted, gaugePart(1)))
+	assert.Equal(t, network.None, level)
+}
+
+func TestUserSecurityLevels(t *testing.T) {
+	dir, err := ioutil.TempDir("", "collectd")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	authPath := filepath.Join(dir, "auth_file")
+	assert.NoError(t, ioutil.WriteFile(authPath, []byte("alice: secret\nlegacy: old\n"), 0600))
+
+	acc := &testutil.Accumulator{}
+	l := &CollectdListener{
+		AuthFile:           authPath,
+		SecurityLevel:      "encrypt",
+		UserSecurityLevels: map[string]string{"legacy": "sign"},
+		Log:                testutil.Logger{},
+		acc:                acc,
+	}
+	assert.NoError(t, l.Init())
+
+	l.parse(securedPacket(t, network.Encrypt, "alice", "secret"))
+	l.parse(securedPacket(t, network.Sign, "legacy", "old"))
+	assert.Equal(t, 2, len(acc.Metrics))
+
+	// the packets under the level of their user are dropped
+	l.parse(securedPacket(t, network.Sign, "alice", "secret"))
+	l.parse(securedPacket(t, network.None, "", ""))
+	assert.Equal(t, 2, len(acc.Metrics))
+
+	l = &CollectdListener{UserSecurityLevels: map[string]string{"legacy": "strict"}, Log: testutil.Logger{}}
+	assert.EqualError(t, l.Init(), `collectd_listener: invalid collectd_user_security_levels level "strict" of legacy, it must be none, sign or encrypt`)
+}
+
+func TestAuthFile_Reload(t *testing.T) {
+	dir, err := ioutil.TempDir("", "collectd")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	authPath := filepath.Join(dir, "auth_file")
+	assert.NoError(t, ioutil.WriteFile(authPath, []byte("# rotated monthly\nalice: secret"), 0600))
+
+	a := newAuthFile(authPath, testutil.Logger{})
+	password, err := a.Password("alice")
+	assert.NoError(t, err)
+	assert.Equal(t, "secret", password)
+
+	// the rotated secret is loaded once the file is checked again
+	assert.NoError(t, ioutil.WriteFile(authPath, []byte("alice: rotated\n"), 0600))
+	a.checked = time.Time{}
+	password, err = a.Password("alice")
+	assert.NoError(t, err)
+	assert.Equal(t, "rotated", password)
+
+	// the passwords are kept while the file is missing
+	assert.NoError(t, os.Remove(authPath))
+	a.checked = time.Time{}
+	password, err = a.Password("alice")
+	assert.NoError(t, err)
+	assert.Equal(t, "rotated", password)
+
+	_, err = a.Password("bob")
+	assert.EqualError(t, err, `no such user: "bob"`)
+}
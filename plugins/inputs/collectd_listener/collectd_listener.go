@@ -0,0 +1,373 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package collectd_listener
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"collectd.org/network"
+	"github.com/aws/amazon-cloudwatch-agent/internal"
+	"github.com/aws/amazon-cloudwatch-agent/internal/cardinality"
+	"github.com/aws/amazon-cloudwatch-agent/internal/handoff"
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/plugins/inputs"
+	"github.com/influxdata/telegraf/plugins/parsers"
+	"github.com/influxdata/telegraf/plugins/parsers/collectd"
+)
+
+const (
+	// the maximum size of an UDP packet, and of the packets reassembled from a tcp stream
+	maxPacketSize = 64 * 1024
+	// a signed packet of a tcp stream ends at the next signature, or once no part is received for this long
+	signedPacketTimeout = 100 * time.Millisecond
+
+	closedConnError = "use of closed network connection"
+)
+
+var sampleConfig = `
+  ## URL to listen on, tcp or udp.
+  service_address = "udp://127.0.0.1:25826"
+
+  ## Only collectd, for the configurations of the socket_listener.
+  data_format = "collectd"
+
+  ## Authentication file for cryptographic security levels, loaded again once it changed
+  collectd_auth_file = "/etc/collectd/auth_file"
+  ## One of none (default), sign, or encrypt
+  collectd_security_level = "encrypt"
+  ## The distinct series published by max_series_interval, the series over it are dropped, or aggregated into the
+  ## series of their metric with the only dimension overflow="true" when series_overflow is "aggregate".
+  # max_series = 0
+  # series_overflow = "drop"
+  # max_series_interval = "60s"
+  ## The security levels of the users overriding collectd_security_level for their packets, the packets of an
+  ## other user or without a user need the collectd_security_level.
+  # [inputs.collectd_listener.collectd_user_security_levels]
+  #   legacy = "sign"
+  ## Split (default) the multi value metrics in a metric by value, or join them in a metric with a field by value.
+  # collectd_parse_multivalue = "split"
+  ## The types.db files merged over the bundled types of the standard plugins, by default the first one found in
+  ## /usr/share/collectd and the other install paths of collectd.
+  # collectd_typesdb = ["/usr/share/collectd/types.db"]
+`
+
+// CollectdListener receives the packets of the binary protocol of the collectd network plugin, in udp datagrams or
+// on a tcp stream, e.g. behind a relay where udp is blocked or lossy. The packets of a stream are reassembled from
+// their parts before they are parsed. The parser is built by the listener instead of the data format, so the values
+// are parsed with the bundled types when no types.db is found.
+type CollectdListener struct {
+	ServiceAddress     string            `toml:"service_address"`
+	DataFormat         string            `toml:"data_format"`
+	AuthFile           string            `toml:"collectd_auth_file"`
+	SecurityLevel      string            `toml:"collectd_security_level"`
+	UserSecurityLevels map[string]string `toml:"collectd_user_security_levels"`
+	ParseMultiValue    string            `toml:"collectd_parse_multivalue"`
+	TypesDB            []string          `toml:"collectd_typesdb"`
+	// the distinct series published by interval of MaxSeriesInterval, the ones over it are dropped or aggregated
+	// into the overflow series of their metric, see the statsd input
+	MaxSeries         int               `toml:"max_series"`
+	SeriesOverflow    string            `toml:"series_overflow"`
+	MaxSeriesInterval internal.Duration `toml:"max_series_interval"`
+
+	Log telegraf.Logger `toml:"-"`
+
+	// the parsers of the unsigned packets, and of the signed and encrypted ones
+	parser        parsers.Parser
+	securedParser parsers.Parser
+	// the security level of the packets, overridden by the ones of their users
+	securityLevel      network.SecurityLevel
+	userSecurityLevels map[string]network.SecurityLevel
+	seriesGuard        *cardinality.Guard
+
+	acc    telegraf.Accumulator
+	closer io.Closer
+	// the address listened on, the port is assigned when 0
+	addr net.Addr
+	wg   sync.WaitGroup
+}
+
+func (l *CollectdListener) SampleConfig() string {
+	return sampleConfig
+}
+
+func (l *CollectdListener) Description() string {
+	return "Receive the metrics of collectd on a tcp or udp socket."
+}
+
+func (l *CollectdListener) Init() error {
+	if l.DataFormat != "" && l.DataFormat != "collectd" {
+		return fmt.Errorf("collectd_listener: invalid data_format %q, it must be collectd", l.DataFormat)
+	}
+	// the level of each packet is checked against the one of its user before it's parsed
+	popts := &network.ParseOpts{SecurityLevel: network.None}
+	level, ok := parseSecurityLevel(l.SecurityLevel)
+	if !ok {
+		return fmt.Errorf("collectd_listener: invalid collectd_security_level %q, it must be none, sign or encrypt", l.SecurityLevel)
+	}
+	l.securityLevel = level
+	l.userSecurityLevels = map[string]network.SecurityLevel{}
+	for user, userLevel := range l.UserSecurityLevels {
+		level, ok := parseSecurityLevel(userLevel)
+		if !ok {
+			return fmt.Errorf("collectd_listener: invalid collectd_user_security_levels level %q of %s, it must be none, sign or encrypt", userLevel, user)
+		}
+		l.userSecurityLevels[user] = level
+	}
+	authFile := l.AuthFile
+	if authFile == "" {
+		authFile = collectd.DefaultAuthFile
+	}
+	popts.PasswordLookup = newAuthFile(authFile, l.Log)
+	db, err := l.loadTypesDB()
+	if err != nil {
+		return fmt.Errorf("collectd_listener: %v", err)
+	}
+	popts.TypesDB = db
+	parser := &collectd.CollectdParser{ParseMultiValue: l.ParseMultiValue}
+	parser.SetParseOpts(popts)
+	l.parser = parser
+	// the parts of a signed packet are parsed once verified, and again as unsigned parts filtered by this level
+	securedOpts := *popts
+	securedOpts.SecurityLevel = network.Sign
+	securedParser := &collectd.CollectdParser{ParseMultiValue: l.ParseMultiValue}
+	securedParser.SetParseOpts(&securedOpts)
+	l.securedParser = securedParser
+	l.seriesGuard, err = cardinality.NewGuard("collectd_listener", l.MaxSeries, l.SeriesOverflow, l.MaxSeriesInterval.Duration)
+	if err != nil {
+		return fmt.Errorf("collectd_listener: %v", err)
+	}
+	return nil
+}
+
+func (l *CollectdListener) Gather(_ telegraf.Accumulator) error {
+	return nil
+}
+
+func (l *CollectdListener) Start(acc telegraf.Accumulator) error {
+	l.acc = acc
+	spl := strings.SplitN(l.ServiceAddress, "://", 2)
+	if len(spl) != 2 {
+		return fmt.Errorf("invalid service address: %s", l.ServiceAddress)
+	}
+	protocol, addr := spl[0], spl[1]
+
+	switch protocol {
+	case "tcp", "tcp4", "tcp6":
+		// the listener of the previous agent is reused after a handoff, with its connections
+		ln, err := handoff.Listen(protocol, addr)
+		if err != nil {
+			return err
+		}
+		sl := &streamListener{Listener: ln, CollectdListener: l, connections: map[net.Conn]bool{}}
+		l.closer, l.addr = ln, ln.Addr()
+		l.wg.Add(1)
+		go func() {
+			defer l.wg.Done()
+			sl.listen()
+		}()
+	case "udp", "udp4", "udp6":
+		pc, err := handoff.ListenPacket(protocol, addr)
+		if err != nil {
+			return err
+		}
+		pl := &packetListener{PacketConn: pc, CollectdListener: l}
+		l.closer, l.addr = pc, pc.LocalAddr()
+		l.wg.Add(1)
+		go func() {
+			defer l.wg.Done()
+			pl.listen()
+		}()
+	default:
+		return fmt.Errorf("unknown protocol '%s' in '%s', it must be tcp or udp", protocol, l.ServiceAddress)
+	}
+	l.Log.Infof("Listening on %s://%s", protocol, l.addr)
+	return nil
+}
+
+func (l *CollectdListener) Stop() {
+	if l.closer != nil {
+		l.closer.Close()
+		l.closer = nil
+	}
+	l.wg.Wait()
+}
+
+// parse adds the metrics of a complete packet, unless its security level is lower than the one of its user.
+func (l *CollectdListener) parse(packet []byte) {
+	level, user := packetSecurity(packet)
+	required, ok := l.userSecurityLevels[user]
+	if !ok || user == "" {
+		required = l.securityLevel
+	}
+	if level < required {
+		l.Log.Debugf("Dropping a packet of security level %d of user %q, the required level is %d", level, user, required)
+		return
+	}
+	parser := l.parser
+	if level > network.None {
+		parser = l.securedParser
+	}
+	metrics, err := parser.Parse(packet)
+	if err != nil {
+		l.Log.Errorf("Unable to parse incoming packet: %v", err)
+	}
+	for _, m := range metrics {
+		tags, ok := l.seriesGuard.Admit(m.Name(), m.Tags())
+		if !ok {
+			continue
+		}
+		// the overflow series has no other dimension
+		if _, overflow := tags[cardinality.OverflowTag]; overflow && len(tags) == 1 {
+			for key := range m.Tags() {
+				m.RemoveTag(key)
+			}
+			m.AddTag(cardinality.OverflowTag, tags[cardinality.OverflowTag])
+		}
+		l.acc.AddMetric(m)
+	}
+}
+
+type streamListener struct {
+	net.Listener
+	*CollectdListener
+
+	connections    map[net.Conn]bool
+	connectionsMtx sync.Mutex
+}
+
+func (sl *streamListener) listen() {
+	wg := sync.WaitGroup{}
+	serve := func(c net.Conn, unread []byte) {
+		sl.connectionsMtx.Lock()
+		sl.connections[c] = true
+		sl.connectionsMtx.Unlock()
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sl.read(c, unread)
+		}()
+	}
+	for _, c := range handoff.Conns(sl.Listener) {
+		serve(c.Conn, c.Unread)
+	}
+	for {
+		c, err := sl.Accept()
+		if err != nil {
+			if !strings.HasSuffix(err.Error(), closedConnError) {
+				sl.Log.Error(err.Error())
+			}
+			break
+		}
+		serve(c, nil)
+	}
+
+	sl.connectionsMtx.Lock()
+	for c := range sl.connections {
+		if handoff.Handing() {
+			// the readers stop at once and keep their connection for the new agent
+			c.SetReadDeadline(time.Now())
+		} else {
+			c.Close()
+		}
+	}
+	sl.connectionsMtx.Unlock()
+	wg.Wait()
+}
+
+// read parses the packets of the connection, after the bytes unread by the previous agent. An unsigned packet is
+// parsed once the next part isn't buffered, a signed one only at the next signature or after signedPacketTimeout
+// since its signature covers all its parts.
+func (sl *streamListener) read(c net.Conn, unread []byte) {
+	defer func() {
+		sl.connectionsMtx.Lock()
+		delete(sl.connections, c)
+		sl.connectionsMtx.Unlock()
+	}()
+	defer c.Close()
+
+	r := bufio.NewReaderSize(io.MultiReader(bytes.NewReader(unread), c), maxPacketSize)
+	s := &packetStream{emit: sl.parse}
+	deadline := false
+	for {
+		if wait := s.signed && !partBuffered(r); wait != deadline {
+			deadline = wait
+			if wait {
+				sl.setReadDeadline(c, time.Now().Add(signedPacketTimeout))
+			} else {
+				sl.setReadDeadline(c, time.Time{})
+			}
+		}
+		part, err := readPart(r)
+		if isTimeout(err) && handoff.Handing() {
+			// the partial packet is completed by the parts read by the new agent
+			pending, _ := r.Peek(r.Buffered())
+			if err := handoff.KeepConn(sl.Listener, c, append(s.pending(), pending...)); err != nil {
+				sl.Log.Errorf("Unable to hand off the connection of %s: %v", c.RemoteAddr(), err)
+			}
+			return
+		}
+		if isTimeout(err) && deadline {
+			s.flush()
+			continue
+		}
+		if err != nil {
+			s.flush()
+			if err != io.EOF && !strings.HasSuffix(err.Error(), closedConnError) {
+				sl.Log.Errorf("Closing the connection of %s: %v", c.RemoteAddr(), err)
+			}
+			return
+		}
+		s.add(part)
+		if !s.signed && !partBuffered(r) {
+			s.flush()
+		}
+	}
+}
+
+// setReadDeadline sets the deadline of the connection unless the agent hands off, the deadline set to stop the
+// reader is never overridden.
+func (sl *streamListener) setReadDeadline(c net.Conn, t time.Time) {
+	c.SetReadDeadline(t)
+	if handoff.Handing() {
+		c.SetReadDeadline(time.Now())
+	}
+}
+
+func isTimeout(err error) bool {
+	netErr, ok := err.(net.Error)
+	return ok && netErr.Timeout()
+}
+
+type packetListener struct {
+	net.PacketConn
+	*CollectdListener
+}
+
+func (pl *packetListener) listen() {
+	buf := make([]byte, maxPacketSize)
+	for {
+		n, _, err := pl.ReadFrom(buf)
+		if err != nil {
+			if !strings.HasSuffix(err.Error(), closedConnError) {
+				pl.Log.Error(err.Error())
+			}
+			break
+		}
+		pl.parse(buf[:n])
+	}
+}
+
+func init() {
+	inputs.Add("collectd_listener", func() telegraf.Input {
+		return &CollectdListener{}
+	})
+}
@@ -0,0 +1,258 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package collectd_listener
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io/ioutil"
+	"math"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/aws/amazon-cloudwatch-agent/internal/handoff"
+	"github.com/influxdata/telegraf/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func part(typ uint16, payload []byte) []byte {
+	b := make([]byte, partHeaderSize, partHeaderSize+len(payload))
+	binary.BigEndian.PutUint16(b, typ)
+	binary.BigEndian.PutUint16(b[2:], uint16(partHeaderSize+len(payload)))
+	return append(b, payload...)
+}
+
+func stringPart(typ uint16, s string) []byte {
+	return part(typ, append([]byte(s), 0))
+}
+
+func timePart(t time.Time) []byte {
+	b := make([]byte, 8)
+	binary.BigEndian.PutUint64(b, uint64(t.Unix()))
+	return part(partTime, b)
+}
+
+// gaugePart is a values part of gauges, the values are little endian.
+func gaugePart(values ...float64) []byte {
+	b := make([]byte, 2+len(values), 2+9*len(values))
+	binary.BigEndian.PutUint16(b, uint16(len(values)))
+	for i, v := range values {
+		b[2+i] = 1
+		b = append(b, 0, 0, 0, 0, 0, 0, 0, 0)
+		binary.LittleEndian.PutUint64(b[len(b)-8:], math.Float64bits(v))
+	}
+	return part(0x0006, b)
+}
+
+func packet(parts ...[]byte) []byte {
+	return bytes.Join(parts, nil)
+}
+
+func newListener(t *testing.T, address string) (*CollectdListener, *testutil.Accumulator) {
+	l := &CollectdListener{ServiceAddress: address, Log: testutil.Logger{}}
+	assert.NoError(t, l.Init())
+	acc := &testutil.Accumulator{}
+	assert.NoError(t, l.Start(acc))
+	return l, acc
+}
+
+func TestStream(t *testing.T) {
+	l, acc := newListener(t, "tcp://127.0.0.1:0")
+	defer l.Stop()
+
+	ts := time.Unix(1600000000, 0)
+	p := packet(stringPart(partHost, "web"), timePart(ts), stringPart(partPlugin, "cpu"), stringPart(partType, "percent"), gaugePart(1))
+	c, err := net.Dial("tcp", l.addr.String())
+	assert.NoError(t, err)
+	defer c.Close()
+	// the packet is reassembled from the writes
+	_, err = c.Write(p[:10])
+	assert.NoError(t, err)
+	time.Sleep(50 * time.Millisecond)
+	_, err = c.Write(p[10:])
+	assert.NoError(t, err)
+	acc.Wait(1)
+
+	// the values of the next packets are parsed with the parts of the previous ones
+	_, err = c.Write(packet(stringPart(partPlugin, "memory"), gaugePart(2)))
+	assert.NoError(t, err)
+	acc.Wait(2)
+
+	expected := []struct {
+		name  string
+		value float64
+	}{{"cpu_value", 1}, {"memory_value", 2}}
+	for i, e := range expected {
+		m := acc.Metrics[i]
+		assert.Equal(t, e.name, m.Measurement)
+		assert.Equal(t, map[string]string{"host": "web", "type": "percent"}, m.Tags)
+		assert.Equal(t, map[string]interface{}{"value": e.value}, m.Fields)
+		assert.Equal(t, ts.UTC(), m.Time)
+	}
+}
+
+func TestStream_InvalidPart(t *testing.T) {
+	l, acc := newListener(t, "tcp://127.0.0.1:0")
+	defer l.Stop()
+
+	c, err := net.Dial("tcp", l.addr.String())
+	assert.NoError(t, err)
+	defer c.Close()
+	_, err = c.Write(append(packet(stringPart(partPlugin, "cpu"), stringPart(partType, "gauge"), gaugePart(1)), 0, 6, 0, 2))
+	assert.NoError(t, err)
+	acc.Wait(1)
+
+	// the connection can't be read past the invalid part
+	c.SetReadDeadline(time.Now().Add(5 * time.Second))
+	_, err = c.Read(make([]byte, 1))
+	assert.Error(t, err)
+	assert.False(t, isTimeout(err))
+}
+
+func TestPacket(t *testing.T) {
+	l, acc := newListener(t, "udp://127.0.0.1:0")
+	defer l.Stop()
+
+	c, err := net.Dial("udp", l.addr.String())
+	assert.NoError(t, err)
+	defer c.Close()
+	_, err = c.Write(packet(stringPart(partHost, "web"), stringPart(partPlugin, "cpu"), stringPart(partType, "gauge"), gaugePart(1)))
+	assert.NoError(t, err)
+	acc.Wait(1)
+	assert.Equal(t, "cpu_value", acc.Metrics[0].Measurement)
+	assert.Equal(t, "web", acc.Metrics[0].Tags["host"])
+}
+
+func TestStart_UnknownProtocol(t *testing.T) {
+	l := &CollectdListener{ServiceAddress: "unix:///tmp/collectd.sock", Log: testutil.Logger{}}
+	assert.NoError(t, l.Init())
+	err := l.Start(&testutil.Accumulator{})
+	assert.EqualError(t, err, "unknown protocol 'unix' in 'unix:///tmp/collectd.sock', it must be tcp or udp")
+}
+
+func TestPacketStream(t *testing.T) {
+	var packets [][]byte
+	s := &packetStream{emit: func(p []byte) {
+		packets = append(packets, append([]byte(nil), p...))
+	}}
+	add := func(parts ...[]byte) {
+		for _, p := range parts {
+			s.add(p)
+		}
+	}
+	host, plugin, value := stringPart(partHost, "web"), stringPart(partPlugin, "cpu"), gaugePart(1)
+	signature, encrypted := part(partSignSHA256, make([]byte, 36)), part(partEncryptAES256, make([]byte, 40))
+
+	add(host, plugin, value)
+	s.flush()
+	add(value)
+	s.flush()
+	// a signed packet ends at the next one, an encrypted packet is its part
+	add(signature, value, value, signature, host, value, encrypted, signature, value)
+	s.flush()
+	assert.Equal(t, [][]byte{
+		packet(host, plugin, value),
+		packet(host, plugin, value),
+		packet(signature, value, value),
+		packet(signature, host, value),
+		encrypted,
+		packet(signature, value),
+	}, packets)
+
+	// the context isn't carried over the signed packets
+	add(value)
+	s.flush()
+	assert.Equal(t, value, packets[len(packets)-1])
+}
+
+func TestStreamHandoff(t *testing.T) {
+	const address = "tcp://127.0.0.1:0"
+	l, acc := newListener(t, address)
+	c, err := net.Dial("tcp", l.addr.String())
+	assert.NoError(t, err)
+	defer c.Close()
+	p := packet(stringPart(partHost, "web"), stringPart(partPlugin, "cpu"), stringPart(partType, "gauge"), gaugePart(1))
+	_, err = c.Write(append(p, p[:10]...))
+	assert.NoError(t, err)
+	acc.Wait(1)
+
+	// the connection, the context and the partial packet are kept by the stopped listener for the next one
+	handoff.Begin()
+	l.Stop()
+	_, err = c.Write(p[10:])
+	assert.NoError(t, err)
+	handoff.Abort()
+
+	l, acc = newListener(t, address)
+	defer l.Stop()
+	acc.Wait(1)
+	assert.Equal(t, "cpu_value", acc.Metrics[0].Measurement)
+	assert.Equal(t, "web", acc.Metrics[0].Tags["host"])
+}
+
+func TestInit_TypesDB(t *testing.T) {
+	defer func(paths []string) { typesDBPaths = paths }(typesDBPaths)
+	dir, err := ioutil.TempDir("", "typesdb")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	missing, installed, configured := filepath.Join(dir, "missing.db"), filepath.Join(dir, "types.db"), filepath.Join(dir, "custom.db")
+	assert.NoError(t, ioutil.WriteFile(installed, []byte("load value:GAUGE:0:U\n"), 0644))
+	assert.NoError(t, ioutil.WriteFile(configured, []byte("# custom\nlatency p50:GAUGE:0:U, p99:GAUGE:0:U\n"), 0644))
+
+	p := packet(stringPart(partPlugin, "load"), stringPart(partType, "load"), gaugePart(1, 2, 3),
+		stringPart(partPlugin, "latency"), stringPart(partType, "latency"), gaugePart(4, 5))
+	tests := []struct {
+		name     string
+		paths    []string
+		typesDB  []string
+		expected []string
+	}{
+		// the bundled types parse the standard plugins
+		{"bundled", []string{missing}, nil, []string{"load_shortterm", "load_midterm", "load_longterm"}},
+		// the types installed with collectd are merged over the bundled ones
+		{"discovered", []string{missing, installed}, nil, nil},
+		// the configured types are merged over the bundled ones, the installed ones aren't loaded
+		{"configured", []string{installed}, []string{configured}, []string{"load_shortterm", "load_midterm", "load_longterm", "latency_p50", "latency_p99"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			typesDBPaths = tt.paths
+			l := &CollectdListener{TypesDB: tt.typesDB, Log: testutil.Logger{}}
+			assert.NoError(t, l.Init())
+			metrics, err := l.parser.Parse(p)
+			assert.NoError(t, err)
+			var names []string
+			for _, m := range metrics {
+				names = append(names, m.Name())
+			}
+			// the values not matching their type are dropped
+			assert.Equal(t, tt.expected, names)
+		})
+	}
+
+	l := &CollectdListener{TypesDB: []string{missing}, Log: testutil.Logger{}}
+	assert.Error(t, l.Init())
+	l = &CollectdListener{SecurityLevel: "strict", Log: testutil.Logger{}}
+	assert.EqualError(t, l.Init(), `collectd_listener: invalid collectd_security_level "strict", it must be none, sign or encrypt`)
+}
+
+func TestParse_MaxSeries(t *testing.T) {
+	l := &CollectdListener{MaxSeries: 1, SeriesOverflow: "aggregate", Log: testutil.Logger{}}
+	assert.NoError(t, l.Init())
+	acc := &testutil.Accumulator{}
+	l.acc = acc
+
+	for _, host := range []string{"web-01", "web-02"} {
+		l.parse(packet(stringPart(partHost, host), stringPart(partPlugin, "cpu"), stringPart(partType, "gauge"), gaugePart(1)))
+	}
+	assert.Len(t, acc.Metrics, 2)
+	assert.Equal(t, "web-01", acc.Metrics[0].Tags["host"])
+	assert.Equal(t, map[string]string{"overflow": "true"}, acc.Metrics[1].Tags)
+
+	l = &CollectdListener{MaxSeries: 1, SeriesOverflow: "sample", Log: testutil.Logger{}}
+	assert.EqualError(t, l.Init(), `collectd_listener: invalid series overflow "sample", it must be drop or aggregate`)
+}
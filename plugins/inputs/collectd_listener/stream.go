@@ -0,0 +1,145 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package collectd_listener
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+)
+
+// the part types of the binary protocol, https://collectd.org/wiki/index.php/Binary_protocol
+const (
+	partHost           = 0x0000
+	partTime           = 0x0001
+	partPlugin         = 0x0002
+	partPluginInstance = 0x0003
+	partType           = 0x0004
+	partTypeInstance   = 0x0005
+	partInterval       = 0x0007
+	partTimeHR         = 0x0008
+	partIntervalHR     = 0x0009
+	partSignSHA256     = 0x0200
+	partEncryptAES256  = 0x0210
+
+	partHeaderSize = 4
+	contextSlots   = 7
+)
+
+// contextParts are the slots of the parts the values are parsed with, the high resolution time and interval replace
+// the low resolution ones.
+var contextParts = map[uint16]int{
+	partHost:           0,
+	partTime:           1,
+	partTimeHR:         1,
+	partInterval:       2,
+	partIntervalHR:     2,
+	partPlugin:         3,
+	partPluginInstance: 4,
+	partType:           5,
+	partTypeInstance:   6,
+}
+
+// readPart reads the next part of the stream, a type and a length of 2 bytes followed by its payload. The part is
+// only valid until the next read.
+func readPart(r *bufio.Reader) ([]byte, error) {
+	header, err := r.Peek(partHeaderSize)
+	if err != nil {
+		return nil, err
+	}
+	length := int(binary.BigEndian.Uint16(header[2:]))
+	if length <= partHeaderSize {
+		return nil, fmt.Errorf("invalid part length %d", length)
+	}
+	part, err := r.Peek(length)
+	if err != nil {
+		return nil, err
+	}
+	r.Discard(length)
+	return part, nil
+}
+
+// partBuffered returns whether the next part is read without blocking.
+func partBuffered(r *bufio.Reader) bool {
+	if r.Buffered() < partHeaderSize {
+		return false
+	}
+	header, _ := r.Peek(partHeaderSize)
+	return int(binary.BigEndian.Uint16(header[2:])) <= r.Buffered()
+}
+
+// packetStream reassembles the packets of a tcp stream from their parts. A stream has no packet boundaries, the
+// values are parsed with the host, time, plugin and type parts received before them, even in a previous packet.
+// A signature covers all the parts until the next packet, a signed packet starts at a signature and an encrypted
+// one is a single part.
+type packetStream struct {
+	// emit parses a complete packet
+	emit func(packet []byte)
+
+	packet []byte
+	signed bool
+	// the last context parts of the unsigned packets emitted, by slot
+	context [][]byte
+}
+
+// add appends the part to the packet, the packet is emitted first when the part starts a new one.
+func (s *packetStream) add(part []byte) {
+	switch binary.BigEndian.Uint16(part) {
+	case partSignSHA256:
+		s.flush()
+		s.context = nil
+		s.signed = true
+	case partEncryptAES256:
+		s.flush()
+		s.context = nil
+		s.emit(part)
+		return
+	default:
+		if len(s.packet)+len(part) > maxPacketSize {
+			s.flush()
+		}
+	}
+	s.packet = append(s.packet, part...)
+}
+
+// flush emits the packet, an unsigned one after the context parts of the previous ones.
+func (s *packetStream) flush() {
+	if len(s.packet) == 0 {
+		s.signed = false
+		return
+	}
+	if s.signed {
+		s.emit(s.packet)
+	} else {
+		s.emit(s.pending())
+		s.updateContext()
+	}
+	s.packet, s.signed = s.packet[:0], false
+}
+
+// pending is the packet not emitted yet, with the context parts it is parsed with.
+func (s *packetStream) pending() []byte {
+	if s.signed {
+		return append([]byte(nil), s.packet...)
+	}
+	var b []byte
+	for _, part := range s.context {
+		b = append(b, part...)
+	}
+	return append(b, s.packet...)
+}
+
+// updateContext records the last context parts of the packet.
+func (s *packetStream) updateContext() {
+	for b := s.packet; len(b) >= partHeaderSize; {
+		length := int(binary.BigEndian.Uint16(b[2:]))
+		if slot, ok := contextParts[binary.BigEndian.Uint16(b)]; ok {
+			if s.context == nil {
+				s.context = make([][]byte, contextSlots)
+			}
+			s.context[slot] = append(s.context[slot][:0], b[:length]...)
+		}
+		b = b[length:]
+	}
+}
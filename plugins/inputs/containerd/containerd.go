@@ -0,0 +1,178 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package containerd
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aws/amazon-cloudwatch-agent/internal"
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/filter"
+	"github.com/influxdata/telegraf/plugins/inputs"
+)
+
+const (
+	measurement     = "containerd"
+	defaultEndpoint = "unix:///run/containerd/containerd.sock"
+	defaultTimeout  = 5 * time.Second
+)
+
+var sampleConfig = `
+  ## The containerd socket serving the CRI API
+  # endpoint = "unix:///run/containerd/containerd.sock"
+
+  ## Containers to include and exclude by name, globs accepted. All the running containers are reported when both are empty.
+  # container_name_include = []
+  # container_name_exclude = []
+
+  ## Maximum time the queries of containerd are allowed to run.
+  # timeout = "5s"
+`
+
+type Containerd struct {
+	Endpoint         string            `toml:"endpoint"`
+	ContainerInclude []string          `toml:"container_name_include"`
+	ContainerExclude []string          `toml:"container_name_exclude"`
+	Timeout          internal.Duration `toml:"timeout"`
+
+	Log telegraf.Logger `toml:"-"`
+
+	client          *client
+	containerFilter filter.Filter
+	// the cpu usage of the containers at the previous gather, by container id
+	lastCPU map[string]*cpuUsage
+}
+
+func (c *Containerd) SampleConfig() string {
+	return sampleConfig
+}
+
+func (c *Containerd) Description() string {
+	return "Report the cpu, memory and writable layer usage of the containers of containerd through the CRI API."
+}
+
+func (c *Containerd) Init() error {
+	if c.Endpoint == "" {
+		c.Endpoint = defaultEndpoint
+	}
+	if c.Timeout.Duration <= 0 {
+		c.Timeout.Duration = defaultTimeout
+	}
+	var err error
+	if c.containerFilter, err = filter.NewIncludeExcludeFilter(c.ContainerInclude, c.ContainerExclude); err != nil {
+		return fmt.Errorf("containerd: invalid container name filter: %v", err)
+	}
+	if c.client, err = newClient(c.Endpoint); err != nil {
+		return fmt.Errorf("containerd: %v", err)
+	}
+	c.lastCPU = map[string]*cpuUsage{}
+	return nil
+}
+
+func (c *Containerd) Gather(acc telegraf.Accumulator) error {
+	ctx, cancel := context.WithTimeout(context.Background(), c.Timeout.Duration)
+	defer cancel()
+	containers, err := c.client.runningContainers(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list the containers of %s: %v", c.Endpoint, err)
+	}
+	stats, err := c.client.containerStats(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list the container stats of %s: %v", c.Endpoint, err)
+	}
+
+	lastCPU := make(map[string]*cpuUsage, len(stats))
+	for _, s := range stats {
+		if s.Attributes == nil {
+			continue
+		}
+		container, ok := containers[s.Attributes.Id]
+		if !ok {
+			// the stats of the exited containers are listed too
+			continue
+		}
+		name := containerName(container)
+		if !c.containerFilter.Match(name) {
+			continue
+		}
+		if s.Cpu != nil {
+			lastCPU[container.Id] = s.Cpu
+		}
+		tags := map[string]string{
+			"container_name":  name,
+			"container_image": containerImage(container),
+			"container_id":    container.Id,
+		}
+		acc.AddFields(measurement, statsFields(s, c.lastCPU[container.Id]), tags)
+	}
+	// the removed containers are forgotten
+	c.lastCPU = lastCPU
+	return nil
+}
+
+func containerName(c *criContainer) string {
+	if c.Metadata != nil && c.Metadata.Name != "" {
+		return c.Metadata.Name
+	}
+	return c.Id
+}
+
+func containerImage(c *criContainer) string {
+	if c.Image != nil && c.Image.Image != "" {
+		return c.Image.Image
+	}
+	return c.ImageRef
+}
+
+// statsFields returns the fields of a stats sample, the values that containerd doesn't report are left out, e.g. the
+// memory usage and rss are only reported by runtime.v1.
+func statsFields(s *containerStats, lastCPU *cpuUsage) map[string]interface{} {
+	fields := map[string]interface{}{}
+	if s.Cpu != nil && s.Cpu.UsageCoreNanoSeconds != nil {
+		fields["cpu_usage_total"] = s.Cpu.UsageCoreNanoSeconds.Value
+		if percent, ok := cpuPercent(s.Cpu, lastCPU); ok {
+			fields["cpu_usage_percent"] = percent
+		}
+	}
+	if m := s.Memory; m != nil {
+		addValue(fields, "mem_working_set", m.WorkingSetBytes)
+		addValue(fields, "mem_usage", m.UsageBytes)
+		addValue(fields, "mem_rss", m.RssBytes)
+		addValue(fields, "mem_available", m.AvailableBytes)
+		addValue(fields, "mem_page_faults", m.PageFaults)
+		addValue(fields, "mem_major_page_faults", m.MajorPageFaults)
+	}
+	if w := s.WritableLayer; w != nil {
+		addValue(fields, "writable_layer_used_bytes", w.UsedBytes)
+		addValue(fields, "writable_layer_inodes_used", w.InodesUsed)
+	}
+	return fields
+}
+
+// cpuPercent returns the cpu usage of the container since the previous gather, where 100 is one fully used cpu.
+// It is not known at the first gather of a container.
+func cpuPercent(cpu, lastCPU *cpuUsage) (float64, bool) {
+	if lastCPU == nil || lastCPU.UsageCoreNanoSeconds == nil {
+		return 0, false
+	}
+	elapsed := cpu.Timestamp - lastCPU.Timestamp
+	if elapsed <= 0 || cpu.UsageCoreNanoSeconds.Value < lastCPU.UsageCoreNanoSeconds.Value {
+		return 0, false
+	}
+	return float64(cpu.UsageCoreNanoSeconds.Value-lastCPU.UsageCoreNanoSeconds.Value) / float64(elapsed) * 100, true
+}
+
+func addValue(fields map[string]interface{}, name string, v *uInt64Value) {
+	if v != nil {
+		fields[name] = v.Value
+	}
+}
+
+func init() {
+	inputs.Add("containerd", func() telegraf.Input {
+		return &Containerd{}
+	})
+}
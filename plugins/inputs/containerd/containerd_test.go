@@ -0,0 +1,194 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package containerd
+
+import (
+	"context"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/influxdata/telegraf/filter"
+	"github.com/influxdata/telegraf/testutil"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+)
+
+// fakeRuntime serves the stats of a running web container and of an exited container.
+type fakeRuntime struct {
+	t     *testing.T
+	stats []*containerStats
+}
+
+func (f *fakeRuntime) listContainers(req *listContainersRequest) *listContainersResponse {
+	// only the running containers are asked for
+	assert.Equal(f.t, int32(containerStateRunning), req.Filter.State.State)
+	return &listContainersResponse{Containers: []*criContainer{
+		{Id: "4c5b6a", Metadata: &containerMetadata{Name: "web"}, Image: &imageSpec{Image: "docker.io/library/nginx:1.19"}, State: containerStateRunning},
+		{Id: "1f2e3d", Metadata: &containerMetadata{Name: "db"}, ImageRef: "sha256:0d1e2f", State: containerStateRunning},
+	}}
+}
+
+func (f *fakeRuntime) listContainerStats(*listContainerStatsRequest) *listContainerStatsResponse {
+	return &listContainerStatsResponse{Stats: f.stats}
+}
+
+func serviceDesc(service string) *grpc.ServiceDesc {
+	return &grpc.ServiceDesc{
+		ServiceName: service,
+		HandlerType: (*interface{})(nil),
+		Methods: []grpc.MethodDesc{
+			{
+				MethodName: "ListContainers",
+				Handler: func(srv interface{}, ctx context.Context, dec func(interface{}) error, _ grpc.UnaryServerInterceptor) (interface{}, error) {
+					req := &listContainersRequest{}
+					if err := dec(req); err != nil {
+						return nil, err
+					}
+					return srv.(*fakeRuntime).listContainers(req), nil
+				},
+			},
+			{
+				MethodName: "ListContainerStats",
+				Handler: func(srv interface{}, ctx context.Context, dec func(interface{}) error, _ grpc.UnaryServerInterceptor) (interface{}, error) {
+					req := &listContainerStatsRequest{}
+					if err := dec(req); err != nil {
+						return nil, err
+					}
+					return srv.(*fakeRuntime).listContainerStats(req), nil
+				},
+			},
+		},
+	}
+}
+
+func newTestContainerd(t *testing.T, service string, runtime *fakeRuntime) (*Containerd, func()) {
+	dir, err := ioutil.TempDir("", "containerd")
+	assert.NoError(t, err)
+	socket := filepath.Join(dir, "containerd.sock")
+	listener, err := net.Listen("unix", socket)
+	assert.NoError(t, err)
+	server := grpc.NewServer()
+	server.RegisterService(serviceDesc(service), runtime)
+	go server.Serve(listener)
+
+	c := &Containerd{Endpoint: "unix://" + socket, Log: testutil.Logger{}}
+	assert.NoError(t, c.Init())
+	return c, func() {
+		c.client.close()
+		server.Stop()
+		os.RemoveAll(dir)
+	}
+}
+
+func webStats(timestamp int64, cpu uint64) *containerStats {
+	return &containerStats{
+		Attributes: &containerAttributes{Id: "4c5b6a", Metadata: &containerMetadata{Name: "web"}},
+		Cpu:        &cpuUsage{Timestamp: timestamp, UsageCoreNanoSeconds: &uInt64Value{Value: cpu}},
+		Memory: &memoryUsage{
+			Timestamp:       timestamp,
+			WorkingSetBytes: &uInt64Value{Value: 83886080},
+			UsageBytes:      &uInt64Value{Value: 104857600},
+			RssBytes:        &uInt64Value{Value: 52428800},
+			AvailableBytes:  &uInt64Value{Value: 335544320},
+			PageFaults:      &uInt64Value{Value: 1200},
+			MajorPageFaults: &uInt64Value{},
+		},
+		WritableLayer: &filesystemUsage{Timestamp: timestamp, UsedBytes: &uInt64Value{Value: 4096}, InodesUsed: &uInt64Value{Value: 12}},
+	}
+}
+
+func TestGather(t *testing.T) {
+	runtime := &fakeRuntime{t: t}
+	c, cleanup := newTestContainerd(t, criServiceV1, runtime)
+	defer cleanup()
+
+	db := &containerStats{
+		Attributes: &containerAttributes{Id: "1f2e3d"},
+		Memory:     &memoryUsage{WorkingSetBytes: &uInt64Value{Value: 2048}},
+	}
+	exited := &containerStats{
+		Attributes: &containerAttributes{Id: "9a8b7c"},
+		Memory:     &memoryUsage{WorkingSetBytes: &uInt64Value{Value: 1}},
+	}
+	runtime.stats = []*containerStats{webStats(1000000000, 400000000), db, exited}
+	var acc testutil.Accumulator
+	assert.NoError(t, c.Gather(&acc))
+	assert.Empty(t, acc.Errors)
+	assert.Equal(t, criServiceV1, c.client.service)
+	assert.Len(t, acc.Metrics, 2)
+	acc.AssertContainsTaggedFields(t, "containerd",
+		map[string]interface{}{
+			"cpu_usage_total":            uint64(400000000),
+			"mem_working_set":            uint64(83886080),
+			"mem_usage":                  uint64(104857600),
+			"mem_rss":                    uint64(52428800),
+			"mem_available":              uint64(335544320),
+			"mem_page_faults":            uint64(1200),
+			"mem_major_page_faults":      uint64(0),
+			"writable_layer_used_bytes":  uint64(4096),
+			"writable_layer_inodes_used": uint64(12),
+		},
+		map[string]string{"container_name": "web", "container_image": "docker.io/library/nginx:1.19", "container_id": "4c5b6a"})
+	acc.AssertContainsTaggedFields(t, "containerd",
+		map[string]interface{}{"mem_working_set": uint64(2048)},
+		map[string]string{"container_name": "db", "container_image": "sha256:0d1e2f", "container_id": "1f2e3d"})
+
+	// half a cpu was used in the 10s since the previous gather
+	runtime.stats = []*containerStats{webStats(11000000000, 5400000000)}
+	acc.ClearMetrics()
+	assert.NoError(t, c.Gather(&acc))
+	assert.Len(t, acc.Metrics, 1)
+	assert.Equal(t, 50.0, acc.Metrics[0].Fields["cpu_usage_percent"])
+}
+
+func TestGatherV1Alpha2(t *testing.T) {
+	runtime := &fakeRuntime{t: t, stats: []*containerStats{webStats(1000000000, 400000000)}}
+	c, cleanup := newTestContainerd(t, criServiceV1Alpha2, runtime)
+	defer cleanup()
+
+	var acc testutil.Accumulator
+	assert.NoError(t, c.Gather(&acc))
+	assert.Empty(t, acc.Errors)
+	assert.Equal(t, criServiceV1Alpha2, c.client.service)
+	assert.Len(t, acc.Metrics, 1)
+	assert.Equal(t, uint64(400000000), acc.Metrics[0].Fields["cpu_usage_total"])
+}
+
+func TestGatherContainerFilter(t *testing.T) {
+	runtime := &fakeRuntime{t: t, stats: []*containerStats{webStats(1000000000, 400000000), {
+		Attributes: &containerAttributes{Id: "1f2e3d"},
+		Memory:     &memoryUsage{WorkingSetBytes: &uInt64Value{Value: 2048}},
+	}}}
+	c, cleanup := newTestContainerd(t, criServiceV1, runtime)
+	defer cleanup()
+	var err error
+	c.containerFilter, err = filter.NewIncludeExcludeFilter(nil, []string{"w*"})
+	assert.NoError(t, err)
+
+	var acc testutil.Accumulator
+	assert.NoError(t, c.Gather(&acc))
+	assert.Len(t, acc.Metrics, 1)
+	assert.Equal(t, "db", acc.Metrics[0].Tags["container_name"])
+}
+
+func TestGatherNotReachable(t *testing.T) {
+	c := &Containerd{Endpoint: "unix:///nonexistent/containerd.sock", Log: testutil.Logger{}}
+	c.Timeout.Duration = 100 * time.Millisecond
+	assert.NoError(t, c.Init())
+	defer c.client.close()
+	var acc testutil.Accumulator
+	assert.Error(t, c.Gather(&acc))
+}
+
+func TestInitInvalidEndpoint(t *testing.T) {
+	assert.Error(t, (&Containerd{Endpoint: "tcp://127.0.0.1:10010"}).Init())
+	c := &Containerd{}
+	assert.NoError(t, c.Init())
+	defer c.client.close()
+	assert.Equal(t, defaultEndpoint, c.Endpoint)
+}
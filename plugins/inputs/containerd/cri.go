@@ -0,0 +1,240 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package containerd
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+
+	"github.com/golang/protobuf/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// The subset of the CRI RuntimeService read by the plugin, see
+// https://github.com/kubernetes/cri-api/blob/master/pkg/apis/runtime/v1/api.proto
+// The messages are the same in runtime.v1 and runtime.v1alpha2, the fields that are not read are left out.
+
+const (
+	criServiceV1       = "runtime.v1.RuntimeService"
+	criServiceV1Alpha2 = "runtime.v1alpha2.RuntimeService"
+
+	containerStateRunning = 1
+
+	maxMsgSize = 16 * 1024 * 1024
+)
+
+type listContainersRequest struct {
+	Filter *containerFilter `protobuf:"bytes,1,opt,name=filter,proto3"`
+}
+
+type containerFilter struct {
+	State *containerStateValue `protobuf:"bytes,2,opt,name=state,proto3"`
+}
+
+type containerStateValue struct {
+	State int32 `protobuf:"varint,1,opt,name=state,proto3"`
+}
+
+type listContainersResponse struct {
+	Containers []*criContainer `protobuf:"bytes,1,rep,name=containers,proto3"`
+}
+
+type criContainer struct {
+	Id       string             `protobuf:"bytes,1,opt,name=id,proto3"`
+	Metadata *containerMetadata `protobuf:"bytes,3,opt,name=metadata,proto3"`
+	Image    *imageSpec         `protobuf:"bytes,4,opt,name=image,proto3"`
+	ImageRef string             `protobuf:"bytes,5,opt,name=image_ref,json=imageRef,proto3"`
+	State    int32              `protobuf:"varint,6,opt,name=state,proto3"`
+}
+
+type containerMetadata struct {
+	Name    string `protobuf:"bytes,1,opt,name=name,proto3"`
+	Attempt uint32 `protobuf:"varint,2,opt,name=attempt,proto3"`
+}
+
+type imageSpec struct {
+	Image string `protobuf:"bytes,1,opt,name=image,proto3"`
+}
+
+// listContainerStatsRequest has no filter, the stats of all the containers are listed.
+type listContainerStatsRequest struct {
+}
+
+type listContainerStatsResponse struct {
+	Stats []*containerStats `protobuf:"bytes,1,rep,name=stats,proto3"`
+}
+
+type containerStats struct {
+	Attributes    *containerAttributes `protobuf:"bytes,1,opt,name=attributes,proto3"`
+	Cpu           *cpuUsage            `protobuf:"bytes,2,opt,name=cpu,proto3"`
+	Memory        *memoryUsage         `protobuf:"bytes,3,opt,name=memory,proto3"`
+	WritableLayer *filesystemUsage     `protobuf:"bytes,4,opt,name=writable_layer,json=writableLayer,proto3"`
+}
+
+type containerAttributes struct {
+	Id       string             `protobuf:"bytes,1,opt,name=id,proto3"`
+	Metadata *containerMetadata `protobuf:"bytes,2,opt,name=metadata,proto3"`
+}
+
+type cpuUsage struct {
+	Timestamp            int64        `protobuf:"varint,1,opt,name=timestamp,proto3"`
+	UsageCoreNanoSeconds *uInt64Value `protobuf:"bytes,2,opt,name=usage_core_nano_seconds,json=usageCoreNanoSeconds,proto3"`
+}
+
+// memoryUsage has the fields of runtime.v1, runtime.v1alpha2 only has the working set.
+type memoryUsage struct {
+	Timestamp       int64        `protobuf:"varint,1,opt,name=timestamp,proto3"`
+	WorkingSetBytes *uInt64Value `protobuf:"bytes,2,opt,name=working_set_bytes,json=workingSetBytes,proto3"`
+	AvailableBytes  *uInt64Value `protobuf:"bytes,3,opt,name=available_bytes,json=availableBytes,proto3"`
+	UsageBytes      *uInt64Value `protobuf:"bytes,4,opt,name=usage_bytes,json=usageBytes,proto3"`
+	RssBytes        *uInt64Value `protobuf:"bytes,5,opt,name=rss_bytes,json=rssBytes,proto3"`
+	PageFaults      *uInt64Value `protobuf:"bytes,6,opt,name=page_faults,json=pageFaults,proto3"`
+	MajorPageFaults *uInt64Value `protobuf:"bytes,7,opt,name=major_page_faults,json=majorPageFaults,proto3"`
+}
+
+type filesystemUsage struct {
+	Timestamp  int64        `protobuf:"varint,1,opt,name=timestamp,proto3"`
+	UsedBytes  *uInt64Value `protobuf:"bytes,3,opt,name=used_bytes,json=usedBytes,proto3"`
+	InodesUsed *uInt64Value `protobuf:"bytes,4,opt,name=inodes_used,json=inodesUsed,proto3"`
+}
+
+type uInt64Value struct {
+	Value uint64 `protobuf:"varint,1,opt,name=value,proto3"`
+}
+
+func (m *listContainersRequest) Reset()         { *m = listContainersRequest{} }
+func (m *listContainersRequest) String() string { return proto.CompactTextString(m) }
+func (*listContainersRequest) ProtoMessage()    {}
+
+func (m *containerFilter) Reset()         { *m = containerFilter{} }
+func (m *containerFilter) String() string { return proto.CompactTextString(m) }
+func (*containerFilter) ProtoMessage()    {}
+
+func (m *containerStateValue) Reset()         { *m = containerStateValue{} }
+func (m *containerStateValue) String() string { return proto.CompactTextString(m) }
+func (*containerStateValue) ProtoMessage()    {}
+
+func (m *listContainersResponse) Reset()         { *m = listContainersResponse{} }
+func (m *listContainersResponse) String() string { return proto.CompactTextString(m) }
+func (*listContainersResponse) ProtoMessage()    {}
+
+func (m *criContainer) Reset()         { *m = criContainer{} }
+func (m *criContainer) String() string { return proto.CompactTextString(m) }
+func (*criContainer) ProtoMessage()    {}
+
+func (m *containerMetadata) Reset()         { *m = containerMetadata{} }
+func (m *containerMetadata) String() string { return proto.CompactTextString(m) }
+func (*containerMetadata) ProtoMessage()    {}
+
+func (m *imageSpec) Reset()         { *m = imageSpec{} }
+func (m *imageSpec) String() string { return proto.CompactTextString(m) }
+func (*imageSpec) ProtoMessage()    {}
+
+func (m *listContainerStatsRequest) Reset()         { *m = listContainerStatsRequest{} }
+func (m *listContainerStatsRequest) String() string { return proto.CompactTextString(m) }
+func (*listContainerStatsRequest) ProtoMessage()    {}
+
+func (m *listContainerStatsResponse) Reset()         { *m = listContainerStatsResponse{} }
+func (m *listContainerStatsResponse) String() string { return proto.CompactTextString(m) }
+func (*listContainerStatsResponse) ProtoMessage()    {}
+
+func (m *containerStats) Reset()         { *m = containerStats{} }
+func (m *containerStats) String() string { return proto.CompactTextString(m) }
+func (*containerStats) ProtoMessage()    {}
+
+func (m *containerAttributes) Reset()         { *m = containerAttributes{} }
+func (m *containerAttributes) String() string { return proto.CompactTextString(m) }
+func (*containerAttributes) ProtoMessage()    {}
+
+func (m *cpuUsage) Reset()         { *m = cpuUsage{} }
+func (m *cpuUsage) String() string { return proto.CompactTextString(m) }
+func (*cpuUsage) ProtoMessage()    {}
+
+func (m *memoryUsage) Reset()         { *m = memoryUsage{} }
+func (m *memoryUsage) String() string { return proto.CompactTextString(m) }
+func (*memoryUsage) ProtoMessage()    {}
+
+func (m *filesystemUsage) Reset()         { *m = filesystemUsage{} }
+func (m *filesystemUsage) String() string { return proto.CompactTextString(m) }
+func (*filesystemUsage) ProtoMessage()    {}
+
+func (m *uInt64Value) Reset()         { *m = uInt64Value{} }
+func (m *uInt64Value) String() string { return proto.CompactTextString(m) }
+func (*uInt64Value) ProtoMessage()    {}
+
+// client calls the CRI RuntimeService of containerd on its unix socket.
+type client struct {
+	conn *grpc.ClientConn
+	// the version of the RuntimeService served by containerd, it is found by the first call
+	service string
+}
+
+func newClient(endpoint string) (*client, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid endpoint %q: %v", endpoint, err)
+	}
+	if u.Scheme != "unix" || u.Path == "" {
+		return nil, fmt.Errorf("invalid endpoint %q, the scheme must be unix", endpoint)
+	}
+	socket := u.Path
+	// the connection is established by the first call, containerd may not be started yet
+	conn, err := grpc.Dial(socket,
+		grpc.WithInsecure(),
+		grpc.WithContextDialer(func(ctx context.Context, addr string) (net.Conn, error) {
+			var d net.Dialer
+			return d.DialContext(ctx, "unix", addr)
+		}),
+		grpc.WithDefaultCallOptions(grpc.MaxCallRecvMsgSize(maxMsgSize)))
+	if err != nil {
+		return nil, fmt.Errorf("invalid endpoint %q: %v", endpoint, err)
+	}
+	return &client{conn: conn}, nil
+}
+
+// runningContainers returns the running containers by id.
+func (c *client) runningContainers(ctx context.Context) (map[string]*criContainer, error) {
+	req := &listContainersRequest{Filter: &containerFilter{State: &containerStateValue{State: containerStateRunning}}}
+	var resp listContainersResponse
+	if err := c.invoke(ctx, "ListContainers", req, &resp); err != nil {
+		return nil, err
+	}
+	containers := make(map[string]*criContainer, len(resp.Containers))
+	for _, container := range resp.Containers {
+		containers[container.Id] = container
+	}
+	return containers, nil
+}
+
+func (c *client) containerStats(ctx context.Context) ([]*containerStats, error) {
+	var resp listContainerStatsResponse
+	err := c.invoke(ctx, "ListContainerStats", &listContainerStatsRequest{}, &resp)
+	return resp.Stats, err
+}
+
+// invoke calls the method of runtime.v1, or of runtime.v1alpha2 for the versions of containerd older than 1.6.
+func (c *client) invoke(ctx context.Context, method string, req, resp proto.Message) error {
+	if c.service != "" {
+		return c.conn.Invoke(ctx, "/"+c.service+"/"+method, req, resp)
+	}
+	var err error
+	for _, service := range []string{criServiceV1, criServiceV1Alpha2} {
+		resp.Reset()
+		if err = c.conn.Invoke(ctx, "/"+service+"/"+method, req, resp); status.Code(err) != codes.Unimplemented {
+			if err == nil {
+				c.service = service
+			}
+			return err
+		}
+	}
+	return err
+}
+
+func (c *client) close() error {
+	return c.conn.Close()
+}
@@ -0,0 +1,283 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package cpu_cluster
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/plugins/inputs"
+)
+
+const (
+	measurement   = "cpu_cluster"
+	defaultSysCpu = "/sys/devices/system/cpu"
+)
+
+var sampleConfig = `
+  ## Report the cycles and the instructions of the clusters from the hardware counters of the cpus, the arm_pmu of
+  ## the Graviton instances, when the PMU is exposed to the instance.
+  # pmu_counters = false
+`
+
+// CPUCluster reports the frequency of the clusters of cpus, the cpufreq policies, and optionally their cycles and
+// instructions from the PMU, the frequency and turbo visibility of the x86 instances for the Graviton ones.
+type CPUCluster struct {
+	PMUCounters bool            `toml:"pmu_counters"`
+	Log         telegraf.Logger `toml:"-"`
+
+	// the sysfs cpu directory, replaced in tests
+	sysCpu  string
+	openPMU func(cpus []int) (pmu, error)
+	pmu     pmu
+	// the counters of the last gather by cpu, the deltas are reported
+	last map[int]pmuCount
+}
+
+// cluster is a set of cpus sharing a frequency, a cpufreq policy or a topology cluster.
+type cluster struct {
+	name string
+	cpus []int
+}
+
+// pmuCount are the counters of a cpu, scaled by the time they were running when the PMU is multiplexed.
+type pmuCount struct {
+	cycles       uint64
+	instructions uint64
+}
+
+// pmu reads the hardware counters of the cpus.
+type pmu interface {
+	read(cpu int) (pmuCount, error)
+	close()
+}
+
+func (c *CPUCluster) SampleConfig() string {
+	return sampleConfig
+}
+
+func (c *CPUCluster) Description() string {
+	return "Report the frequency of the cpu clusters and their cycles and instructions from the PMU."
+}
+
+func (c *CPUCluster) Init() error {
+	if c.sysCpu == "" {
+		c.sysCpu = defaultSysCpu
+	}
+	if c.openPMU == nil {
+		c.openPMU = openPerfPMU
+	}
+	return nil
+}
+
+// Start opens the counters of the PMU, they count from their opening until the input is stopped.
+func (c *CPUCluster) Start(_ telegraf.Accumulator) error {
+	if !c.PMUCounters {
+		return nil
+	}
+	clusters, err := c.clusters()
+	if err != nil {
+		return err
+	}
+	var cpus []int
+	for _, cl := range clusters {
+		cpus = append(cpus, cl.cpus...)
+	}
+	p, err := c.openPMU(cpus)
+	if err != nil {
+		// the hypervisor doesn't expose the PMU on every instance type, the frequency is still reported
+		c.Log.Warnf("The PMU counters are not available, only the frequency is reported: %v", err)
+		return nil
+	}
+	c.pmu = p
+	c.last = map[int]pmuCount{}
+	return nil
+}
+
+func (c *CPUCluster) Stop() {
+	if c.pmu != nil {
+		c.pmu.close()
+		c.pmu = nil
+	}
+}
+
+func (c *CPUCluster) Gather(acc telegraf.Accumulator) error {
+	clusters, err := c.clusters()
+	if err != nil {
+		return err
+	}
+	for _, cl := range clusters {
+		fields := c.frequency(cl)
+		if c.pmu != nil {
+			if err := c.pmuFields(cl, fields); err != nil {
+				acc.AddError(err)
+			}
+		}
+		if len(fields) > 0 {
+			acc.AddFields(measurement, fields, map[string]string{"cluster": cl.name})
+		}
+	}
+	return nil
+}
+
+// frequency reads the frequencies of the cpufreq policy of the cluster in kHz, the current one is the one of the
+// hardware when readable, the one requested by the governor otherwise.
+func (c *CPUCluster) frequency(cl cluster) map[string]interface{} {
+	fields := map[string]interface{}{}
+	policy := filepath.Join(c.sysCpu, "cpufreq", cl.name)
+	for field, files := range map[string][]string{
+		"frequency_mhz":     {"cpuinfo_cur_freq", "scaling_cur_freq"},
+		"min_frequency_mhz": {"cpuinfo_min_freq"},
+		"max_frequency_mhz": {"cpuinfo_max_freq"},
+	} {
+		for _, file := range files {
+			if khz, err := readUint(filepath.Join(policy, file)); err == nil {
+				fields[field] = float64(khz) / 1000
+				break
+			}
+		}
+	}
+	return fields
+}
+
+// pmuFields sums the cycles and instructions of the cpus of the cluster since the last gather, nothing is reported
+// by the first gather.
+func (c *CPUCluster) pmuFields(cl cluster, fields map[string]interface{}) error {
+	var delta pmuCount
+	complete := true
+	for _, cpu := range cl.cpus {
+		count, err := c.pmu.read(cpu)
+		if err != nil {
+			return fmt.Errorf("unable to read the PMU counters of cpu %d: %v", cpu, err)
+		}
+		last, ok := c.last[cpu]
+		c.last[cpu] = count
+		if !ok || count.cycles < last.cycles || count.instructions < last.instructions {
+			complete = false
+			continue
+		}
+		delta.cycles += count.cycles - last.cycles
+		delta.instructions += count.instructions - last.instructions
+	}
+	if !complete {
+		return nil
+	}
+	fields["cycles"] = delta.cycles
+	fields["instructions"] = delta.instructions
+	if delta.cycles > 0 {
+		fields["instructions_per_cycle"] = float64(delta.instructions) / float64(delta.cycles)
+	}
+	return nil
+}
+
+// clusters returns the cpufreq policies, or the topology clusters of the online cpus when there is no cpufreq
+// driver, e.g. on the instances without frequency scaling.
+func (c *CPUCluster) clusters() ([]cluster, error) {
+	policies, err := filepath.Glob(filepath.Join(c.sysCpu, "cpufreq", "policy*"))
+	if err != nil {
+		return nil, err
+	}
+	var clusters []cluster
+	for _, policy := range policies {
+		b, err := ioutil.ReadFile(filepath.Join(policy, "affected_cpus"))
+		if err != nil {
+			return nil, err
+		}
+		cpus, err := parseCPUs(strings.Join(strings.Fields(string(b)), ","))
+		if err != nil {
+			return nil, fmt.Errorf("unexpected content of %s: %v", policy, err)
+		}
+		clusters = append(clusters, cluster{name: filepath.Base(policy), cpus: cpus})
+	}
+	if len(clusters) > 0 {
+		sortClusters(clusters)
+		return clusters, nil
+	}
+
+	b, err := ioutil.ReadFile(filepath.Join(c.sysCpu, "online"))
+	if err != nil {
+		return nil, err
+	}
+	online, err := parseCPUs(strings.TrimSpace(string(b)))
+	if err != nil {
+		return nil, fmt.Errorf("unexpected content of %s/online: %v", c.sysCpu, err)
+	}
+	byID := map[string]*cluster{}
+	for _, cpu := range online {
+		id, err := ioutil.ReadFile(filepath.Join(c.sysCpu, fmt.Sprintf("cpu%d", cpu), "topology", "cluster_id"))
+		if os.IsNotExist(err) {
+			// cluster_id is only in the topology since Linux 5.16
+			id, err = ioutil.ReadFile(filepath.Join(c.sysCpu, fmt.Sprintf("cpu%d", cpu), "topology", "physical_package_id"))
+		}
+		name := "cluster0"
+		if err == nil {
+			name = "cluster" + strings.TrimSpace(string(id))
+		}
+		cl, ok := byID[name]
+		if !ok {
+			cl = &cluster{name: name}
+			byID[name] = cl
+		}
+		cl.cpus = append(cl.cpus, cpu)
+	}
+	for _, cl := range byID {
+		clusters = append(clusters, *cl)
+	}
+	sortClusters(clusters)
+	return clusters, nil
+}
+
+func sortClusters(clusters []cluster) {
+	sort.Slice(clusters, func(i, j int) bool {
+		return clusters[i].cpus[0] < clusters[j].cpus[0]
+	})
+}
+
+// parseCPUs parses a cpu list of the sysfs, like "0-3,8,10-11".
+func parseCPUs(s string) ([]int, error) {
+	var cpus []int
+	for _, r := range strings.Split(s, ",") {
+		if r == "" {
+			continue
+		}
+		bounds := strings.SplitN(r, "-", 2)
+		first, err := strconv.Atoi(bounds[0])
+		if err != nil {
+			return nil, err
+		}
+		last := first
+		if len(bounds) == 2 {
+			if last, err = strconv.Atoi(bounds[1]); err != nil {
+				return nil, err
+			}
+		}
+		for cpu := first; cpu <= last; cpu++ {
+			cpus = append(cpus, cpu)
+		}
+	}
+	if len(cpus) == 0 {
+		return nil, fmt.Errorf("no cpu in %q", s)
+	}
+	return cpus, nil
+}
+
+func readUint(path string) (uint64, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseUint(strings.TrimSpace(string(b)), 10, 64)
+}
+
+func init() {
+	inputs.Add("cpu_cluster", func() telegraf.Input {
+		return &CPUCluster{}
+	})
+}
@@ -0,0 +1,157 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package cpu_cluster
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/influxdata/telegraf/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeFiles(t *testing.T, root string, files map[string]string) {
+	for name, content := range files {
+		path := filepath.Join(root, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, ioutil.WriteFile(path, []byte(content), 0644))
+	}
+}
+
+type fakePMU struct {
+	counts map[int]pmuCount
+	closed bool
+}
+
+func (p *fakePMU) read(cpu int) (pmuCount, error) {
+	count, ok := p.counts[cpu]
+	if !ok {
+		return pmuCount{}, errors.New("no counter opened")
+	}
+	return count, nil
+}
+
+func (p *fakePMU) close() {
+	p.closed = true
+}
+
+func newCPUCluster(t *testing.T, root string, p *fakePMU) *CPUCluster {
+	c := &CPUCluster{PMUCounters: p != nil, Log: testutil.Logger{}, sysCpu: root}
+	c.openPMU = func(cpus []int) (pmu, error) {
+		if p == nil {
+			return nil, errors.New("perf_event_open of cpu 0: no such file or directory")
+		}
+		for _, cpu := range cpus {
+			if _, ok := p.counts[cpu]; !ok {
+				p.counts[cpu] = pmuCount{}
+			}
+		}
+		return p, nil
+	}
+	require.NoError(t, c.Init())
+	require.NoError(t, c.Start(&testutil.Accumulator{}))
+	return c
+}
+
+func TestGatherFrequency(t *testing.T) {
+	root, err := ioutil.TempDir("", "cpu_cluster")
+	require.NoError(t, err)
+	defer os.RemoveAll(root)
+	writeFiles(t, root, map[string]string{
+		"cpufreq/policy0/affected_cpus":    "0 1 2 3\n",
+		"cpufreq/policy0/scaling_cur_freq": "2500000\n",
+		"cpufreq/policy0/cpuinfo_min_freq": "1000000\n",
+		"cpufreq/policy0/cpuinfo_max_freq": "2600000\n",
+		"cpufreq/policy4/affected_cpus":    "4 5 6 7\n",
+		"cpufreq/policy4/cpuinfo_cur_freq": "2100000\n",
+		"cpufreq/policy4/scaling_cur_freq": "2400000\n",
+		"cpufreq/policy4/cpuinfo_max_freq": "2600000\n",
+	})
+
+	c := newCPUCluster(t, root, nil)
+	acc := &testutil.Accumulator{}
+	require.NoError(t, c.Gather(acc))
+	acc.AssertContainsTaggedFields(t, "cpu_cluster", map[string]interface{}{
+		"frequency_mhz":     float64(2500),
+		"min_frequency_mhz": float64(1000),
+		"max_frequency_mhz": float64(2600),
+	}, map[string]string{"cluster": "policy0"})
+	// the frequency of the hardware is preferred to the one of the governor
+	acc.AssertContainsTaggedFields(t, "cpu_cluster", map[string]interface{}{
+		"frequency_mhz":     float64(2100),
+		"max_frequency_mhz": float64(2600),
+	}, map[string]string{"cluster": "policy4"})
+	assert.Len(t, acc.Metrics, 2)
+}
+
+func TestGatherPMU(t *testing.T) {
+	root, err := ioutil.TempDir("", "cpu_cluster")
+	require.NoError(t, err)
+	defer os.RemoveAll(root)
+	// no cpufreq driver, the cpus are grouped by their topology cluster
+	writeFiles(t, root, map[string]string{
+		"online":                   "0-3\n",
+		"cpu0/topology/cluster_id": "0\n",
+		"cpu1/topology/cluster_id": "0\n",
+		"cpu2/topology/cluster_id": "1\n",
+		"cpu3/topology/cluster_id": "1\n",
+	})
+
+	p := &fakePMU{counts: map[int]pmuCount{}}
+	c := newCPUCluster(t, root, p)
+	acc := &testutil.Accumulator{}
+	require.NoError(t, c.Gather(acc))
+	assert.Empty(t, acc.Metrics, "the first gather has no delta")
+
+	p.counts[0] = pmuCount{cycles: 1000, instructions: 3000}
+	p.counts[1] = pmuCount{cycles: 1000, instructions: 1000}
+	p.counts[2] = pmuCount{cycles: 2000, instructions: 1000}
+	p.counts[3] = pmuCount{cycles: 2000, instructions: 1000}
+	require.NoError(t, c.Gather(acc))
+	acc.AssertContainsTaggedFields(t, "cpu_cluster", map[string]interface{}{
+		"cycles":                 uint64(2000),
+		"instructions":           uint64(4000),
+		"instructions_per_cycle": float64(2),
+	}, map[string]string{"cluster": "cluster0"})
+	acc.AssertContainsTaggedFields(t, "cpu_cluster", map[string]interface{}{
+		"cycles":                 uint64(4000),
+		"instructions":           uint64(2000),
+		"instructions_per_cycle": 0.5,
+	}, map[string]string{"cluster": "cluster1"})
+
+	c.Stop()
+	assert.True(t, p.closed)
+}
+
+func TestPMUUnavailable(t *testing.T) {
+	root, err := ioutil.TempDir("", "cpu_cluster")
+	require.NoError(t, err)
+	defer os.RemoveAll(root)
+	writeFiles(t, root, map[string]string{
+		"cpufreq/policy0/affected_cpus":    "0\n",
+		"cpufreq/policy0/scaling_cur_freq": "2500000\n",
+	})
+
+	c := newCPUCluster(t, root, nil)
+	c.PMUCounters = true
+	require.NoError(t, c.Start(&testutil.Accumulator{}), "the frequency is still reported")
+	acc := &testutil.Accumulator{}
+	require.NoError(t, c.Gather(acc))
+	acc.AssertContainsTaggedFields(t, "cpu_cluster", map[string]interface{}{"frequency_mhz": float64(2500)},
+		map[string]string{"cluster": "policy0"})
+}
+
+func TestParseCPUs(t *testing.T) {
+	cpus, err := parseCPUs("0-3,8,10-11")
+	require.NoError(t, err)
+	assert.Equal(t, []int{0, 1, 2, 3, 8, 10, 11}, cpus)
+	_, err = parseCPUs("")
+	assert.Error(t, err)
+	_, err = parseCPUs("a-b")
+	assert.Error(t, err)
+}
@@ -0,0 +1,92 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package cpu_cluster
+
+import (
+	"encoding/binary"
+	"fmt"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+// perfPMU counts the cycles and the instructions of each cpu with perf events, the generic hardware events are
+// mapped to the counters of the cpu by the kernel, by the arm_pmu driver on arm64.
+type perfPMU struct {
+	fds map[int][2]int
+}
+
+func openPerfPMU(cpus []int) (pmu, error) {
+	p := &perfPMU{fds: map[int][2]int{}}
+	for _, cpu := range cpus {
+		var fds [2]int
+		for i, event := range []uint64{unix.PERF_COUNT_HW_CPU_CYCLES, unix.PERF_COUNT_HW_INSTRUCTIONS} {
+			attr := unix.PerfEventAttr{
+				Type:        unix.PERF_TYPE_HARDWARE,
+				Config:      event,
+				Read_format: unix.PERF_FORMAT_TOTAL_TIME_ENABLED | unix.PERF_FORMAT_TOTAL_TIME_RUNNING,
+				Bits:        unix.PerfBitExcludeHv,
+			}
+			attr.Size = uint32(unsafe.Sizeof(attr))
+			fd, err := unix.PerfEventOpen(&attr, -1, cpu, -1, unix.PERF_FLAG_FD_CLOEXEC)
+			if err != nil {
+				if i == 1 {
+					unix.Close(fds[0])
+				}
+				p.close()
+				return nil, fmt.Errorf("perf_event_open of cpu %d: %v", cpu, err)
+			}
+			fds[i] = fd
+		}
+		p.fds[cpu] = fds
+	}
+	return p, nil
+}
+
+func (p *perfPMU) read(cpu int) (pmuCount, error) {
+	fds, ok := p.fds[cpu]
+	if !ok {
+		return pmuCount{}, fmt.Errorf("no counter opened")
+	}
+	var values [2]uint64
+	for i, fd := range fds {
+		v, err := readCounter(fd)
+		if err != nil {
+			return pmuCount{}, err
+		}
+		values[i] = v
+	}
+	return pmuCount{cycles: values[0], instructions: values[1]}, nil
+}
+
+// readCounter reads the value of the counter, scaled by the time it was enabled over the time it was running since
+// the events are multiplexed when there are more events than counters.
+func readCounter(fd int) (uint64, error) {
+	buf := make([]byte, 24)
+	n, err := unix.Read(fd, buf)
+	if err != nil {
+		return 0, err
+	}
+	if n != len(buf) {
+		return 0, fmt.Errorf("short read of %d bytes", n)
+	}
+	value := binary.LittleEndian.Uint64(buf[0:8])
+	enabled := binary.LittleEndian.Uint64(buf[8:16])
+	running := binary.LittleEndian.Uint64(buf[16:24])
+	if running == 0 {
+		return 0, nil
+	}
+	if running < enabled {
+		value = uint64(float64(value) * float64(enabled) / float64(running))
+	}
+	return value, nil
+}
+
+func (p *perfPMU) close() {
+	for _, fds := range p.fds {
+		unix.Close(fds[0])
+		unix.Close(fds[1])
+	}
+	p.fds = map[int][2]int{}
+}
@@ -0,0 +1,15 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+//go:build !linux
+// +build !linux
+
+package cpu_cluster
+
+import (
+	"errors"
+)
+
+func openPerfPMU(_ []int) (pmu, error) {
+	return nil, errors.New("the PMU counters are only read on Linux")
+}
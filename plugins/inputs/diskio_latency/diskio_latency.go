@@ -0,0 +1,229 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package diskio_latency
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/amazon-cloudwatch-agent/internal"
+	"github.com/aws/amazon-cloudwatch-agent/metric/distribution"
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/filter"
+	"github.com/influxdata/telegraf/plugins/inputs"
+)
+
+const (
+	// the measurement of the diskio input, the distributions extend its metrics
+	measurement           = "diskio"
+	defaultProcRoot       = "/proc"
+	defaultSampleInterval = time.Second
+	unit                  = "Milliseconds"
+)
+
+var sampleConfig = `
+  ## The devices reported, globs accepted, all of them when empty.
+  # devices = ["nvme*"]
+
+  ## The interval the latency is sampled at, the mean latency of the I/Os completed in each sample is an entry of
+  ## the distributions, weighted by their count.
+  # sample_interval = "1s"
+`
+
+// DiskIOLatency reports the distributions of the read and write latencies of the block devices of the diskio
+// input, from the time spent on the I/Os of /proc/diskstats, so p99 latency alarms can be set by device where
+// the cumulative read_time and write_time only give the mean.
+type DiskIOLatency struct {
+	Devices        []string          `toml:"devices"`
+	SampleInterval internal.Duration `toml:"sample_interval"`
+
+	Log telegraf.Logger `toml:"-"`
+
+	// the procfs mount, replaced in tests
+	procRoot      string
+	deviceFilter  filter.Filter
+	last          map[string]diskStats
+	distributions map[string]*latencies
+	mtx           sync.Mutex
+	done          chan struct{}
+	wg            sync.WaitGroup
+}
+
+// diskStats are the counters of the I/Os of a device completed since the boot and the milliseconds spent on them.
+type diskStats struct {
+	reads     uint64
+	readTime  uint64
+	writes    uint64
+	writeTime uint64
+}
+
+// latencies are the distributions of a device since the last gather.
+type latencies struct {
+	read  distribution.Distribution
+	write distribution.Distribution
+}
+
+func (d *DiskIOLatency) SampleConfig() string {
+	return sampleConfig
+}
+
+func (d *DiskIOLatency) Description() string {
+	return "Report the distributions of the read and write latencies of the block devices."
+}
+
+func (d *DiskIOLatency) Init() error {
+	if d.SampleInterval.Duration < 0 {
+		return fmt.Errorf("diskio_latency: sample_interval must not be negative")
+	}
+	if d.SampleInterval.Duration == 0 {
+		d.SampleInterval.Duration = defaultSampleInterval
+	}
+	var err error
+	if d.deviceFilter, err = filter.Compile(d.Devices); err != nil {
+		return fmt.Errorf("diskio_latency: invalid devices: %v", err)
+	}
+	if d.procRoot == "" {
+		d.procRoot = defaultProcRoot
+	}
+	return nil
+}
+
+func (d *DiskIOLatency) Start(_ telegraf.Accumulator) error {
+	d.last = map[string]diskStats{}
+	d.distributions = map[string]*latencies{}
+	if err := d.sample(); err != nil {
+		return err
+	}
+	d.done = make(chan struct{})
+	d.wg.Add(1)
+	go func() {
+		defer d.wg.Done()
+		ticker := time.NewTicker(d.SampleInterval.Duration)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				if err := d.sample(); err != nil {
+					d.Log.Errorf("Failed to sample the disk latencies: %v", err)
+				}
+			case <-d.done:
+				return
+			}
+		}
+	}()
+	return nil
+}
+
+func (d *DiskIOLatency) Stop() {
+	if d.done != nil {
+		close(d.done)
+		d.wg.Wait()
+		d.done = nil
+	}
+}
+
+// Gather reports the distributions of the devices with I/Os since the last gather.
+func (d *DiskIOLatency) Gather(acc telegraf.Accumulator) error {
+	d.mtx.Lock()
+	distributions := d.distributions
+	d.distributions = map[string]*latencies{}
+	d.mtx.Unlock()
+	for name, l := range distributions {
+		fields := map[string]interface{}{}
+		if l.read != nil {
+			fields["read_latency"] = l.read
+		}
+		if l.write != nil {
+			fields["write_latency"] = l.write
+		}
+		acc.AddFields(measurement, fields, map[string]string{"name": name})
+	}
+	return nil
+}
+
+// sample adds the mean latency of the I/Os completed by each device since the last sample to its distributions.
+func (d *DiskIOLatency) sample() error {
+	stats, err := d.readDiskStats()
+	if err != nil {
+		return err
+	}
+	d.mtx.Lock()
+	defer d.mtx.Unlock()
+	for name, s := range stats {
+		last, ok := d.last[name]
+		if !ok {
+			continue
+		}
+		l := d.distributions[name]
+		if l == nil {
+			l = &latencies{}
+			d.distributions[name] = l
+		}
+		l.read = d.add(l.read, name, last.reads, s.reads, last.readTime, s.readTime)
+		l.write = d.add(l.write, name, last.writes, s.writes, last.writeTime, s.writeTime)
+		if l.read == nil && l.write == nil {
+			delete(d.distributions, name)
+		}
+	}
+	// the removed devices are forgotten
+	d.last = stats
+	return nil
+}
+
+// add adds the mean latency of the I/Os completed between the counters to the distribution, weighted by the I/Os
+// so the distribution counts the I/Os. The counters wrap on 32-bit kernels, the sample is skipped then.
+func (d *DiskIOLatency) add(dist distribution.Distribution, name string, lastIOs, ios, lastTime, ioTime uint64) distribution.Distribution {
+	if ios <= lastIOs || ioTime < lastTime {
+		return dist
+	}
+	if dist == nil {
+		dist = distribution.NewDistribution()
+	}
+	count := float64(ios - lastIOs)
+	if err := dist.AddEntryWithUnit(float64(ioTime-lastTime)/count, count, unit); err != nil {
+		d.Log.Warnf("Failed to add the latency of device %s: %v", name, err)
+	}
+	return dist
+}
+
+// readDiskStats reads the counters of the devices of /proc/diskstats, the lines are like
+// "259 0 nvme0n1 18460 6 1053122 8228 5764 3854 265620 12311 0 14036 20540 ...", the major and minor numbers, the
+// name, the reads completed, merged, the sectors read, the milliseconds spent reading, then the same for the writes.
+func (d *DiskIOLatency) readDiskStats() (map[string]diskStats, error) {
+	path := filepath.Join(d.procRoot, "diskstats")
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	stats := map[string]diskStats{}
+	for _, line := range strings.Split(string(b), "\n") {
+		columns := strings.Fields(line)
+		if len(columns) < 11 {
+			continue
+		}
+		name := columns[2]
+		if d.deviceFilter != nil && !d.deviceFilter.Match(name) {
+			continue
+		}
+		var values [4]uint64
+		for i, column := range []int{3, 6, 7, 10} {
+			if values[i], err = strconv.ParseUint(columns[column], 10, 64); err != nil {
+				return nil, fmt.Errorf("unexpected content of %s: %v", path, err)
+			}
+		}
+		stats[name] = diskStats{reads: values[0], readTime: values[1], writes: values[2], writeTime: values[3]}
+	}
+	return stats, nil
+}
+
+func init() {
+	inputs.Add("diskio_latency", func() telegraf.Input {
+		return &DiskIOLatency{}
+	})
+}
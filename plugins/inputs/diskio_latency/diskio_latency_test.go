@@ -0,0 +1,123 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package diskio_latency
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/aws/amazon-cloudwatch-agent/metric/distribution"
+	"github.com/aws/amazon-cloudwatch-agent/metric/distribution/regular"
+	"github.com/influxdata/telegraf/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+const diskstatsFormat = "259 0 nvme0n1 %s 6 1053122 %s %s 3854 265620 %s 0 14036 20540 0 0 0 0\n" +
+	"259 1 nvme0n1p1 100 0 2000 50 10 0 80 20 0 60 70 0 0 0 0\n" +
+	"7 0 loop0 %s 0 0 %s 0 0 0 0 0 0 0 0 0 0 0\n"
+
+func writeDiskstats(t *testing.T, root string, reads, readTime, writes, writeTime, loopReads, loopTime string) {
+	content := []byte(fmt.Sprintf(diskstatsFormat, reads, readTime, writes, writeTime, loopReads, loopTime))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(root, "diskstats"), content, 0644))
+}
+
+func newDiskIOLatency(t *testing.T, root string, devices []string) *DiskIOLatency {
+	distribution.NewDistribution = regular.NewRegularDistribution
+	d := &DiskIOLatency{Devices: devices, Log: testutil.Logger{}, procRoot: root}
+	assert.NoError(t, d.Init())
+	d.last = map[string]diskStats{}
+	d.distributions = map[string]*latencies{}
+	return d
+}
+
+func TestSample(t *testing.T) {
+	root, err := ioutil.TempDir("", "diskio_latency")
+	assert.NoError(t, err)
+	defer os.RemoveAll(root)
+	d := newDiskIOLatency(t, root, []string{"nvme*", "loop*"})
+
+	writeDiskstats(t, root, "1000", "2000", "500", "1000", "10", "10")
+	assert.NoError(t, d.sample())
+	// 100 reads of 1ms, 10 writes of 5ms
+	writeDiskstats(t, root, "1100", "2100", "510", "1050", "10", "10")
+	assert.NoError(t, d.sample())
+	// 100 reads of 3ms, no write
+	writeDiskstats(t, root, "1200", "2400", "510", "1050", "10", "10")
+	assert.NoError(t, d.sample())
+
+	var acc testutil.Accumulator
+	assert.NoError(t, d.Gather(&acc))
+	// the idle devices have no distribution
+	assert.Len(t, acc.Metrics, 1)
+	m := acc.Metrics[0]
+	assert.Equal(t, "diskio", m.Measurement)
+	assert.Equal(t, map[string]string{"name": "nvme0n1"}, m.Tags)
+
+	read := m.Fields["read_latency"].(distribution.Distribution)
+	assert.Equal(t, float64(200), read.SampleCount())
+	assert.Equal(t, float64(400), read.Sum())
+	assert.Equal(t, float64(1), read.Minimum())
+	assert.Equal(t, float64(3), read.Maximum())
+	assert.Equal(t, "Milliseconds", read.Unit())
+	write := m.Fields["write_latency"].(distribution.Distribution)
+	assert.Equal(t, float64(10), write.SampleCount())
+	assert.Equal(t, float64(5), write.Maximum())
+
+	// the distributions are reset by the gather
+	acc.ClearMetrics()
+	assert.NoError(t, d.Gather(&acc))
+	assert.Len(t, acc.Metrics, 0)
+}
+
+func TestSampleDevicesAndWraps(t *testing.T) {
+	root, err := ioutil.TempDir("", "diskio_latency")
+	assert.NoError(t, err)
+	defer os.RemoveAll(root)
+	d := newDiskIOLatency(t, root, []string{"loop0"})
+
+	writeDiskstats(t, root, "1000", "2000", "500", "1000", "4294967290", "100")
+	assert.NoError(t, d.sample())
+	// the counters of the loop device wrap
+	writeDiskstats(t, root, "1100", "2100", "510", "1050", "10", "20")
+	assert.NoError(t, d.sample())
+	writeDiskstats(t, root, "1200", "2400", "510", "1050", "20", "40")
+	assert.NoError(t, d.sample())
+
+	var acc testutil.Accumulator
+	assert.NoError(t, d.Gather(&acc))
+	assert.Len(t, acc.Metrics, 1)
+	m := acc.Metrics[0]
+	assert.Equal(t, map[string]string{"name": "loop0"}, m.Tags)
+	read := m.Fields["read_latency"].(distribution.Distribution)
+	assert.Equal(t, float64(10), read.SampleCount())
+	assert.Equal(t, float64(2), read.Maximum())
+	assert.NotContains(t, m.Fields, "write_latency")
+}
+
+func TestStartStop(t *testing.T) {
+	root, err := ioutil.TempDir("", "diskio_latency")
+	assert.NoError(t, err)
+	defer os.RemoveAll(root)
+	writeDiskstats(t, root, "1000", "2000", "500", "1000", "10", "10")
+	d := &DiskIOLatency{Log: testutil.Logger{}, procRoot: root}
+	assert.NoError(t, d.Init())
+	var acc testutil.Accumulator
+	assert.NoError(t, d.Start(&acc))
+	assert.Contains(t, d.last, "nvme0n1p1")
+	d.Stop()
+
+	assert.NoError(t, os.Remove(filepath.Join(root, "diskstats")))
+	assert.Error(t, d.Start(&acc))
+}
+
+func TestInit(t *testing.T) {
+	d := &DiskIOLatency{}
+	assert.NoError(t, d.Init())
+	assert.Equal(t, defaultSampleInterval, d.SampleInterval.Duration)
+	assert.Nil(t, d.deviceFilter)
+	assert.Error(t, (&DiskIOLatency{Devices: []string{"["}}).Init())
+}
@@ -0,0 +1,129 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package docker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// The subset of the Docker Engine API read by the plugin, see https://docs.docker.com/engine/api/v1.40/
+
+type container struct {
+	ID    string   `json:"Id"`
+	Names []string `json:"Names"`
+	Image string   `json:"Image"`
+}
+
+type containerStats struct {
+	CPUStats    cpuStats                `json:"cpu_stats"`
+	PreCPUStats cpuStats                `json:"precpu_stats"`
+	MemoryStats memoryStats             `json:"memory_stats"`
+	Networks    map[string]networkStats `json:"networks"`
+	BlkioStats  blkioStats              `json:"blkio_stats"`
+}
+
+type cpuStats struct {
+	CPUUsage struct {
+		TotalUsage  uint64   `json:"total_usage"`
+		PercpuUsage []uint64 `json:"percpu_usage"`
+	} `json:"cpu_usage"`
+	SystemUsage    uint64 `json:"system_cpu_usage"`
+	OnlineCPUs     uint32 `json:"online_cpus"`
+	ThrottlingData struct {
+		ThrottledPeriods uint64 `json:"throttled_periods"`
+		ThrottledTime    uint64 `json:"throttled_time"`
+	} `json:"throttling_data"`
+}
+
+type memoryStats struct {
+	Usage uint64            `json:"usage"`
+	Limit uint64            `json:"limit"`
+	Stats map[string]uint64 `json:"stats"`
+}
+
+type networkStats struct {
+	RxBytes   uint64 `json:"rx_bytes"`
+	RxPackets uint64 `json:"rx_packets"`
+	RxErrors  uint64 `json:"rx_errors"`
+	RxDropped uint64 `json:"rx_dropped"`
+	TxBytes   uint64 `json:"tx_bytes"`
+	TxPackets uint64 `json:"tx_packets"`
+	TxErrors  uint64 `json:"tx_errors"`
+	TxDropped uint64 `json:"tx_dropped"`
+}
+
+type blkioStats struct {
+	IoServiceBytesRecursive []blkioEntry `json:"io_service_bytes_recursive"`
+	IoServicedRecursive     []blkioEntry `json:"io_serviced_recursive"`
+}
+
+type blkioEntry struct {
+	Op    string `json:"op"`
+	Value uint64 `json:"value"`
+}
+
+// client calls the Docker Engine API on a unix socket or a tcp address.
+type client struct {
+	baseURL    string
+	httpClient *http.Client
+}
+
+func newClient(endpoint string) (*client, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid endpoint %q: %v", endpoint, err)
+	}
+	transport := &http.Transport{}
+	switch u.Scheme {
+	case "unix":
+		socket := u.Path
+		transport.DialContext = func(ctx context.Context, _, _ string) (net.Conn, error) {
+			var d net.Dialer
+			return d.DialContext(ctx, "unix", socket)
+		}
+		return &client{baseURL: "http://docker", httpClient: &http.Client{Transport: transport}}, nil
+	case "tcp", "http":
+		return &client{baseURL: "http://" + u.Host, httpClient: &http.Client{Transport: transport}}, nil
+	}
+	return nil, fmt.Errorf("invalid endpoint %q, the scheme must be unix or tcp", endpoint)
+}
+
+// containers returns the running containers.
+func (c *client) containers(ctx context.Context) ([]container, error) {
+	var containers []container
+	err := c.get(ctx, "/containers/json", &containers)
+	return containers, err
+}
+
+// stats returns a single sample of the container stats, the precpu_stats are the previous sample of the engine.
+func (c *client) stats(ctx context.Context, id string) (*containerStats, error) {
+	var stats containerStats
+	err := c.get(ctx, "/containers/"+url.PathEscape(id)+"/stats?stream=false", &stats)
+	return &stats, err
+}
+
+func (c *client) get(ctx context.Context, path string, v interface{}) error {
+	req, err := http.NewRequest(http.MethodGet, c.baseURL+path, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := c.httpClient.Do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("GET %s returned %s: %s", path, resp.Status, strings.TrimSpace(string(body)))
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
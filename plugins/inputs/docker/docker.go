@@ -0,0 +1,214 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package docker
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/amazon-cloudwatch-agent/internal"
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/filter"
+	"github.com/influxdata/telegraf/plugins/inputs"
+)
+
+const (
+	measurement     = "docker"
+	defaultEndpoint = "unix:///var/run/docker.sock"
+	defaultTimeout  = 5 * time.Second
+)
+
+var sampleConfig = `
+  ## The Docker Engine API endpoint, "unix:///path/to/docker.sock" or "tcp://host:port"
+  # endpoint = "unix:///var/run/docker.sock"
+
+  ## Containers to include and exclude by name, globs accepted. All the running containers are reported when both are empty.
+  # container_name_include = []
+  # container_name_exclude = []
+
+  ## Maximum time the queries of the engine are allowed to run.
+  # timeout = "5s"
+`
+
+type Docker struct {
+	Endpoint         string            `toml:"endpoint"`
+	ContainerInclude []string          `toml:"container_name_include"`
+	ContainerExclude []string          `toml:"container_name_exclude"`
+	Timeout          internal.Duration `toml:"timeout"`
+
+	Log telegraf.Logger `toml:"-"`
+
+	client          *client
+	containerFilter filter.Filter
+}
+
+func (d *Docker) SampleConfig() string {
+	return sampleConfig
+}
+
+func (d *Docker) Description() string {
+	return "Report the cpu, memory, network and block I/O of the containers of the Docker engine."
+}
+
+func (d *Docker) Init() error {
+	if d.Endpoint == "" {
+		d.Endpoint = defaultEndpoint
+	}
+	if d.Timeout.Duration <= 0 {
+		d.Timeout.Duration = defaultTimeout
+	}
+	var err error
+	if d.containerFilter, err = filter.NewIncludeExcludeFilter(d.ContainerInclude, d.ContainerExclude); err != nil {
+		return fmt.Errorf("docker: invalid container name filter: %v", err)
+	}
+	if d.client, err = newClient(d.Endpoint); err != nil {
+		return fmt.Errorf("docker: %v", err)
+	}
+	return nil
+}
+
+func (d *Docker) Gather(acc telegraf.Accumulator) error {
+	ctx, cancel := context.WithTimeout(context.Background(), d.Timeout.Duration)
+	defer cancel()
+	containers, err := d.client.containers(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list the containers of %s: %v", d.Endpoint, err)
+	}
+
+	var wg sync.WaitGroup
+	for _, c := range containers {
+		name := containerName(c)
+		if !d.containerFilter.Match(name) {
+			continue
+		}
+		wg.Add(1)
+		go func(c container, name string) {
+			defer wg.Done()
+			stats, err := d.client.stats(ctx, c.ID)
+			if err != nil {
+				acc.AddError(fmt.Errorf("failed to get the stats of container %s: %v", name, err))
+				return
+			}
+			tags := map[string]string{
+				"container_name":  name,
+				"container_image": c.Image,
+				"container_id":    c.ID,
+			}
+			acc.AddFields(measurement, statsFields(stats), tags)
+		}(c, name)
+	}
+	wg.Wait()
+	return nil
+}
+
+// containerName returns the name of the container without the leading slash, e.g. "/nginx" is "nginx".
+func containerName(c container) string {
+	for _, name := range c.Names {
+		// the names of the linked containers have more than one slash, like "/web/db"
+		if strings.Count(name, "/") == 1 {
+			return strings.TrimPrefix(name, "/")
+		}
+	}
+	if len(c.Names) > 0 {
+		return strings.TrimPrefix(c.Names[0], "/")
+	}
+	return c.ID
+}
+
+// statsFields returns the fields of a stats sample, the network and block I/O counters are summed over the
+// interfaces and the devices of the container.
+func statsFields(s *containerStats) map[string]interface{} {
+	fields := map[string]interface{}{
+		"cpu_usage_percent":            cpuPercent(s),
+		"cpu_usage_total":              s.CPUStats.CPUUsage.TotalUsage,
+		"cpu_throttled_periods":        s.CPUStats.ThrottlingData.ThrottledPeriods,
+		"cpu_throttled_time":           s.CPUStats.ThrottlingData.ThrottledTime,
+		"mem_limit":                    s.MemoryStats.Limit,
+		"net_rx_bytes":                 uint64(0),
+		"net_rx_packets":               uint64(0),
+		"net_rx_errors":                uint64(0),
+		"net_rx_dropped":               uint64(0),
+		"net_tx_bytes":                 uint64(0),
+		"net_tx_packets":               uint64(0),
+		"net_tx_errors":                uint64(0),
+		"net_tx_dropped":               uint64(0),
+		"blkio_io_service_bytes_read":  uint64(0),
+		"blkio_io_service_bytes_write": uint64(0),
+		"blkio_io_serviced_read":       uint64(0),
+		"blkio_io_serviced_write":      uint64(0),
+	}
+
+	// the inactive page cache is reclaimable, it is not counted as used like "docker stats" does
+	usage := s.MemoryStats.Usage
+	cache, ok := s.MemoryStats.Stats["total_inactive_file"] // cgroup v1
+	if !ok {
+		cache = s.MemoryStats.Stats["inactive_file"] // cgroup v2
+	}
+	if cache < usage {
+		usage -= cache
+	}
+	fields["mem_usage"] = usage
+	if s.MemoryStats.Limit > 0 {
+		fields["mem_usage_percent"] = float64(usage) / float64(s.MemoryStats.Limit) * 100
+	}
+
+	for _, n := range s.Networks {
+		addUint(fields, "net_rx_bytes", n.RxBytes)
+		addUint(fields, "net_rx_packets", n.RxPackets)
+		addUint(fields, "net_rx_errors", n.RxErrors)
+		addUint(fields, "net_rx_dropped", n.RxDropped)
+		addUint(fields, "net_tx_bytes", n.TxBytes)
+		addUint(fields, "net_tx_packets", n.TxPackets)
+		addUint(fields, "net_tx_errors", n.TxErrors)
+		addUint(fields, "net_tx_dropped", n.TxDropped)
+	}
+	for _, e := range s.BlkioStats.IoServiceBytesRecursive {
+		switch strings.ToLower(e.Op) {
+		case "read":
+			addUint(fields, "blkio_io_service_bytes_read", e.Value)
+		case "write":
+			addUint(fields, "blkio_io_service_bytes_write", e.Value)
+		}
+	}
+	for _, e := range s.BlkioStats.IoServicedRecursive {
+		switch strings.ToLower(e.Op) {
+		case "read":
+			addUint(fields, "blkio_io_serviced_read", e.Value)
+		case "write":
+			addUint(fields, "blkio_io_serviced_write", e.Value)
+		}
+	}
+	return fields
+}
+
+// cpuPercent returns the cpu usage of the container since the previous sample of the engine, where 100 is one
+// fully used cpu, like "docker stats" reports it. The first sample of a container has no previous sample.
+func cpuPercent(s *containerStats) float64 {
+	if s.PreCPUStats.SystemUsage == 0 {
+		return 0
+	}
+	cpuDelta := float64(s.CPUStats.CPUUsage.TotalUsage) - float64(s.PreCPUStats.CPUUsage.TotalUsage)
+	systemDelta := float64(s.CPUStats.SystemUsage) - float64(s.PreCPUStats.SystemUsage)
+	onlineCPUs := float64(s.CPUStats.OnlineCPUs)
+	if onlineCPUs == 0 {
+		onlineCPUs = float64(len(s.CPUStats.CPUUsage.PercpuUsage))
+	}
+	if systemDelta <= 0 || cpuDelta <= 0 {
+		return 0
+	}
+	return cpuDelta / systemDelta * onlineCPUs * 100
+}
+
+func addUint(fields map[string]interface{}, name string, v uint64) {
+	fields[name] = fields[name].(uint64) + v
+}
+
+func init() {
+	inputs.Add("docker", func() telegraf.Input {
+		return &Docker{}
+	})
+}
@@ -0,0 +1,160 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package docker
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/influxdata/telegraf/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+const containersJSON = `[
+  {"Id": "1f2e3d", "Names": ["/web/db", "/db"], "Image": "postgres:12"},
+  {"Id": "4c5b6a", "Names": ["/web"], "Image": "nginx:1.19"}
+]`
+
+const dbStatsJSON = `{
+  "cpu_stats": {
+    "cpu_usage": {"total_usage": 400000000, "percpu_usage": [200000000, 200000000]},
+    "system_cpu_usage": 2000000000,
+    "online_cpus": 2,
+    "throttling_data": {"throttled_periods": 3, "throttled_time": 1500}
+  },
+  "precpu_stats": {
+    "cpu_usage": {"total_usage": 300000000},
+    "system_cpu_usage": 1000000000
+  },
+  "memory_stats": {"usage": 104857600, "limit": 419430400, "stats": {"total_inactive_file": 20971520}},
+  "networks": {
+    "eth0": {"rx_bytes": 1000, "rx_packets": 10, "tx_bytes": 2000, "tx_packets": 20, "rx_dropped": 1},
+    "eth1": {"rx_bytes": 500, "rx_packets": 5, "tx_bytes": 100, "tx_packets": 1, "tx_errors": 2}
+  },
+  "blkio_stats": {
+    "io_service_bytes_recursive": [
+      {"major": 259, "minor": 0, "op": "Read", "value": 4096},
+      {"major": 259, "minor": 0, "op": "Write", "value": 8192},
+      {"major": 259, "minor": 1, "op": "read", "value": 4096},
+      {"major": 259, "minor": 0, "op": "Total", "value": 12288}
+    ],
+    "io_serviced_recursive": [
+      {"major": 259, "minor": 0, "op": "Read", "value": 1},
+      {"major": 259, "minor": 0, "op": "Write", "value": 2}
+    ]
+  }
+}`
+
+// the first sample of a container has no precpu_stats and cgroup v2 memory stats
+const webStatsJSON = `{
+  "cpu_stats": {"cpu_usage": {"total_usage": 100}, "system_cpu_usage": 1000, "online_cpus": 1},
+  "memory_stats": {"usage": 2000, "limit": 0, "stats": {"inactive_file": 500}}
+}`
+
+func newTestServer(t *testing.T, stats map[string]string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/containers/json" {
+			w.Write([]byte(containersJSON))
+			return
+		}
+		id := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/containers/"), "/stats")
+		assert.Equal(t, "false", r.URL.Query().Get("stream"))
+		s, ok := stats[id]
+		if !ok {
+			http.Error(w, "no such container", http.StatusNotFound)
+			return
+		}
+		w.Write([]byte(s))
+	}))
+}
+
+func newDocker(t *testing.T, server *httptest.Server) *Docker {
+	d := &Docker{Endpoint: "tcp://" + strings.TrimPrefix(server.URL, "http://"), Log: testutil.Logger{}}
+	assert.NoError(t, d.Init())
+	return d
+}
+
+func TestGather(t *testing.T) {
+	server := newTestServer(t, map[string]string{"1f2e3d": dbStatsJSON, "4c5b6a": webStatsJSON})
+	defer server.Close()
+	d := newDocker(t, server)
+
+	var acc testutil.Accumulator
+	assert.NoError(t, d.Gather(&acc))
+	assert.Empty(t, acc.Errors)
+	acc.AssertContainsTaggedFields(t, "docker",
+		map[string]interface{}{
+			"cpu_usage_percent":            20.0,
+			"cpu_usage_total":              uint64(400000000),
+			"cpu_throttled_periods":        uint64(3),
+			"cpu_throttled_time":           uint64(1500),
+			"mem_usage":                    uint64(83886080),
+			"mem_limit":                    uint64(419430400),
+			"mem_usage_percent":            20.0,
+			"net_rx_bytes":                 uint64(1500),
+			"net_rx_packets":               uint64(15),
+			"net_rx_errors":                uint64(0),
+			"net_rx_dropped":               uint64(1),
+			"net_tx_bytes":                 uint64(2100),
+			"net_tx_packets":               uint64(21),
+			"net_tx_errors":                uint64(2),
+			"net_tx_dropped":               uint64(0),
+			"blkio_io_service_bytes_read":  uint64(8192),
+			"blkio_io_service_bytes_write": uint64(8192),
+			"blkio_io_serviced_read":       uint64(1),
+			"blkio_io_serviced_write":      uint64(2),
+		},
+		map[string]string{"container_name": "db", "container_image": "postgres:12", "container_id": "1f2e3d"})
+
+	assert.Len(t, acc.Metrics, 2)
+	web := acc.Metrics[0]
+	if web.Tags["container_name"] != "web" {
+		web = acc.Metrics[1]
+	}
+	assert.Equal(t, "web", web.Tags["container_name"])
+	assert.Equal(t, 0.0, web.Fields["cpu_usage_percent"])
+	assert.Equal(t, uint64(1500), web.Fields["mem_usage"])
+	// no limit
+	assert.NotContains(t, web.Fields, "mem_usage_percent")
+}
+
+func TestGatherContainerFilter(t *testing.T) {
+	server := newTestServer(t, map[string]string{"1f2e3d": dbStatsJSON})
+	defer server.Close()
+	d := newDocker(t, server)
+	d.ContainerExclude = []string{"w*"}
+	assert.NoError(t, d.Init())
+
+	var acc testutil.Accumulator
+	assert.NoError(t, d.Gather(&acc))
+	assert.Empty(t, acc.Errors)
+	assert.Len(t, acc.Metrics, 1)
+	assert.Equal(t, "db", acc.Metrics[0].Tags["container_name"])
+}
+
+func TestGatherErrors(t *testing.T) {
+	server := newTestServer(t, map[string]string{"1f2e3d": dbStatsJSON})
+	defer server.Close()
+	d := newDocker(t, server)
+
+	// the stats of a container can't be read
+	var acc testutil.Accumulator
+	assert.NoError(t, d.Gather(&acc))
+	assert.Len(t, acc.Errors, 1)
+	assert.Len(t, acc.Metrics, 1)
+
+	// the engine is not reachable
+	server.Close()
+	assert.Error(t, d.Gather(&acc))
+}
+
+func TestInitInvalidEndpoint(t *testing.T) {
+	assert.Error(t, (&Docker{Endpoint: "npipe:////./pipe/docker_engine"}).Init())
+	d := &Docker{}
+	assert.NoError(t, d.Init())
+	assert.Equal(t, defaultEndpoint, d.Endpoint)
+	assert.Equal(t, "http://docker", d.client.baseURL)
+}
@@ -0,0 +1,376 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package emf_listener
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"math"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/amazon-cloudwatch-agent/internal/handoff"
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/plugins/inputs"
+	"github.com/influxdata/telegraf/plugins/parsers"
+	"github.com/influxdata/telegraf/selfstat"
+)
+
+const (
+	// the maximum size of a CloudWatch Logs event
+	defaultMaxPayloadSize = 256 * 1024
+	// the maximum size of an UDP packet
+	maxPacketSize = 64 * 1024
+	// the udp clients without event for this long lose their bucket
+	clientIdleTimeout = 5 * time.Minute
+	// the drops are logged at most once in this interval, they are all counted in the metrics
+	warningInterval = time.Minute
+
+	statsMeasurement = "emf_listener"
+	closedConnError  = "use of closed network connection"
+)
+
+var sampleConfig = `
+  ## URL to listen on, tcp or udp.
+  service_address = "udp://127.0.0.1:25888"
+
+  ## The events admitted per second for each client, a tcp connection or an udp peer, the others are dropped.
+  ## 0 (default) is unlimited.
+  # rate_limit = 0.0
+
+  ## The events admitted at once above the rate, ceil(rate_limit) by default.
+  # rate_limit_burst = 0
+
+  ## The maximum size of an event in bytes, the larger ones are dropped.
+  # max_payload_size = 262144
+
+  data_format = "emf"
+`
+
+// EMFListener receives the EMF events, one by line, of the applications on a tcp or udp socket. Each client is
+// admitted through a token bucket and the events above its rate or too large are rejected, the 429 of the
+// listener, since the protocol has no response the events are dropped and counted.
+type EMFListener struct {
+	ServiceAddress string  `toml:"service_address"`
+	RateLimit      float64 `toml:"rate_limit"`
+	RateLimitBurst int     `toml:"rate_limit_burst"`
+	MaxPayloadSize int     `toml:"max_payload_size"`
+
+	Log telegraf.Logger `toml:"-"`
+
+	parser parsers.Parser
+	acc    telegraf.Accumulator
+	closer io.Closer
+	// the address listened on, the port is assigned when 0
+	addr net.Addr
+	wg   sync.WaitGroup
+
+	accepted  selfstat.Stat
+	throttled selfstat.Stat
+	oversized selfstat.Stat
+
+	warningMtx  sync.Mutex
+	lastWarning time.Time
+}
+
+func (l *EMFListener) SampleConfig() string {
+	return sampleConfig
+}
+
+func (l *EMFListener) Description() string {
+	return "Receive the EMF events of the applications with a rate and a size limit by client."
+}
+
+func (l *EMFListener) SetParser(parser parsers.Parser) {
+	l.parser = parser
+}
+
+func (l *EMFListener) Init() error {
+	if l.RateLimit < 0 {
+		return fmt.Errorf("emf_listener: rate_limit must not be negative")
+	}
+	if l.RateLimitBurst < 0 {
+		return fmt.Errorf("emf_listener: rate_limit_burst must not be negative")
+	}
+	if l.MaxPayloadSize < 0 {
+		return fmt.Errorf("emf_listener: max_payload_size must not be negative")
+	}
+	if l.RateLimit > 0 && l.RateLimitBurst == 0 {
+		l.RateLimitBurst = int(math.Ceil(l.RateLimit))
+	}
+	if l.MaxPayloadSize == 0 {
+		l.MaxPayloadSize = defaultMaxPayloadSize
+	}
+	tags := map[string]string{"service_address": l.ServiceAddress}
+	l.accepted = selfstat.Register(statsMeasurement, "events_accepted", tags)
+	l.throttled = selfstat.Register(statsMeasurement, "events_throttled", tags)
+	l.oversized = selfstat.Register(statsMeasurement, "events_oversized", tags)
+	return nil
+}
+
+func (l *EMFListener) Gather(_ telegraf.Accumulator) error {
+	return nil
+}
+
+func (l *EMFListener) Start(acc telegraf.Accumulator) error {
+	if l.parser == nil {
+		return fmt.Errorf("emf_listener: no parser, data_format must be emf")
+	}
+	l.acc = acc
+	spl := strings.SplitN(l.ServiceAddress, "://", 2)
+	if len(spl) != 2 {
+		return fmt.Errorf("invalid service address: %s", l.ServiceAddress)
+	}
+	protocol, addr := spl[0], spl[1]
+
+	switch protocol {
+	case "tcp", "tcp4", "tcp6":
+		// the listener of the previous agent is reused after a handoff, with its connections
+		ln, err := handoff.Listen(protocol, addr)
+		if err != nil {
+			return err
+		}
+		sl := &streamListener{Listener: ln, EMFListener: l, connections: map[string]net.Conn{}}
+		l.closer, l.addr = ln, ln.Addr()
+		l.wg.Add(1)
+		go func() {
+			defer l.wg.Done()
+			sl.listen()
+		}()
+	case "udp", "udp4", "udp6":
+		pc, err := handoff.ListenPacket(protocol, addr)
+		if err != nil {
+			return err
+		}
+		pl := &packetListener{PacketConn: pc, EMFListener: l, clients: map[string]*packetClient{}}
+		l.closer, l.addr = pc, pc.LocalAddr()
+		l.wg.Add(1)
+		go func() {
+			defer l.wg.Done()
+			pl.listen()
+		}()
+	default:
+		return fmt.Errorf("unknown protocol '%s' in '%s'", protocol, l.ServiceAddress)
+	}
+	l.Log.Infof("Listening on %s://%s", protocol, l.addr)
+	return nil
+}
+
+func (l *EMFListener) Stop() {
+	if l.closer != nil {
+		l.closer.Close()
+		l.closer = nil
+	}
+	l.wg.Wait()
+}
+
+// newBucket returns the token bucket of a new client, nil without rate limit.
+func (l *EMFListener) newBucket() *tokenBucket {
+	if l.RateLimit == 0 {
+		return nil
+	}
+	return newTokenBucket(l.RateLimit, l.RateLimitBurst, time.Now())
+}
+
+// admit parses the event of the client when its bucket has a token.
+func (l *EMFListener) admit(bucket *tokenBucket, event []byte, client net.Addr) {
+	event = bytes.TrimSpace(event)
+	if len(event) == 0 {
+		return
+	}
+	if len(event) > l.MaxPayloadSize {
+		l.reject(l.oversized, client, fmt.Sprintf("the events are larger than max_payload_size (%d bytes)", l.MaxPayloadSize))
+		return
+	}
+	if bucket != nil && !bucket.allow(time.Now()) {
+		l.reject(l.throttled, client, fmt.Sprintf("the events are above rate_limit (%v/s)", l.RateLimit))
+		return
+	}
+	l.accepted.Incr(1)
+	metrics, err := l.parser.Parse(event)
+	if err != nil {
+		l.Log.Errorf("Unable to parse incoming event: %v", err)
+		return
+	}
+	for _, m := range metrics {
+		l.acc.AddMetric(m)
+	}
+}
+
+// reject counts the dropped event, a warning is logged at most once by warningInterval so a flood of events isn't
+// a flood of logs.
+func (l *EMFListener) reject(stat selfstat.Stat, client net.Addr, reason string) {
+	stat.Incr(1)
+	l.warningMtx.Lock()
+	defer l.warningMtx.Unlock()
+	now := time.Now()
+	if now.Sub(l.lastWarning) < warningInterval {
+		return
+	}
+	l.lastWarning = now
+	l.Log.Warnf("Dropping the EMF events of %s, %s, the next drops are only counted for %v", client, reason, warningInterval)
+}
+
+type streamListener struct {
+	net.Listener
+	*EMFListener
+
+	connections    map[string]net.Conn
+	connectionsMtx sync.Mutex
+}
+
+func (sl *streamListener) listen() {
+	wg := sync.WaitGroup{}
+	serve := func(c net.Conn, unread []byte) {
+		sl.connectionsMtx.Lock()
+		sl.connections[c.RemoteAddr().String()] = c
+		sl.connectionsMtx.Unlock()
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sl.read(c, unread)
+		}()
+	}
+	for _, c := range handoff.Conns(sl.Listener) {
+		serve(c.Conn, c.Unread)
+	}
+	for {
+		c, err := sl.Accept()
+		if err != nil {
+			if !strings.HasSuffix(err.Error(), closedConnError) {
+				sl.Log.Error(err.Error())
+			}
+			break
+		}
+		serve(c, nil)
+	}
+
+	sl.connectionsMtx.Lock()
+	for _, c := range sl.connections {
+		if handoff.Handing() {
+			// the readers stop at once and keep their connection for the new agent
+			c.SetReadDeadline(time.Now())
+		} else {
+			c.Close()
+		}
+	}
+	sl.connectionsMtx.Unlock()
+	wg.Wait()
+}
+
+// read admits the events of the connection, one by line, after the bytes unread by the previous agent. The lines
+// larger than max_payload_size are discarded as they are read, so a client can't grow the memory of the agent.
+func (sl *streamListener) read(c net.Conn, unread []byte) {
+	defer func() {
+		sl.connectionsMtx.Lock()
+		delete(sl.connections, c.RemoteAddr().String())
+		sl.connectionsMtx.Unlock()
+	}()
+	defer c.Close()
+
+	bucket := sl.newBucket()
+	r := bufio.NewReaderSize(io.MultiReader(bytes.NewReader(unread), c), maxPacketSize)
+	var line []byte
+	oversized := false
+	for {
+		chunk, err := r.ReadSlice('\n')
+		if !oversized {
+			line = append(line, chunk...)
+			if len(bytes.TrimSpace(line)) > sl.MaxPayloadSize {
+				oversized, line = true, nil
+			}
+		}
+		if err == bufio.ErrBufferFull {
+			continue
+		}
+		if isTimeout(err) && handoff.Handing() {
+			// the partial line is completed by the bytes read by the new agent
+			if err := handoff.KeepConn(sl.Listener, c, line); err != nil {
+				sl.Log.Errorf("Unable to hand off the connection of %s: %v", c.RemoteAddr(), err)
+			}
+			return
+		}
+		if oversized {
+			sl.reject(sl.oversized, c.RemoteAddr(), fmt.Sprintf("the events are larger than max_payload_size (%d bytes)", sl.MaxPayloadSize))
+		} else {
+			sl.admit(bucket, line, c.RemoteAddr())
+		}
+		line, oversized = line[:0], false
+		if err != nil {
+			if err != io.EOF && !strings.HasSuffix(err.Error(), closedConnError) {
+				sl.Log.Error(err.Error())
+			}
+			return
+		}
+	}
+}
+
+func isTimeout(err error) bool {
+	netErr, ok := err.(net.Error)
+	return ok && netErr.Timeout()
+}
+
+type packetListener struct {
+	net.PacketConn
+	*EMFListener
+
+	clients   map[string]*packetClient
+	lastSweep time.Time
+}
+
+// packetClient is an udp peer, it loses its bucket after clientIdleTimeout without event.
+type packetClient struct {
+	bucket   *tokenBucket
+	lastSeen time.Time
+}
+
+func (pl *packetListener) listen() {
+	buf := make([]byte, maxPacketSize)
+	for {
+		n, addr, err := pl.ReadFrom(buf)
+		if err != nil {
+			if !strings.HasSuffix(err.Error(), closedConnError) {
+				pl.Log.Error(err.Error())
+			}
+			break
+		}
+		bucket := pl.bucket(addr, time.Now())
+		// a packet can hold several events, one by line
+		for _, event := range bytes.Split(buf[:n], []byte("\n")) {
+			pl.admit(bucket, event, addr)
+		}
+	}
+}
+
+func (pl *packetListener) bucket(addr net.Addr, now time.Time) *tokenBucket {
+	if pl.RateLimit == 0 {
+		return nil
+	}
+	if now.Sub(pl.lastSweep) > clientIdleTimeout {
+		for k, c := range pl.clients {
+			if now.Sub(c.lastSeen) > clientIdleTimeout {
+				delete(pl.clients, k)
+			}
+		}
+		pl.lastSweep = now
+	}
+	c, ok := pl.clients[addr.String()]
+	if !ok {
+		c = &packetClient{bucket: pl.newBucket()}
+		pl.clients[addr.String()] = c
+	}
+	c.lastSeen = now
+	return c.bucket
+}
+
+func init() {
+	inputs.Add("emf_listener", func() telegraf.Input {
+		return &EMFListener{}
+	})
+}
@@ -0,0 +1,151 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package emf_listener
+
+import (
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/amazon-cloudwatch-agent/internal/handoff"
+	"github.com/aws/amazon-cloudwatch-agent/plugins/parsers/emf"
+	"github.com/influxdata/telegraf/selfstat"
+	"github.com/influxdata/telegraf/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+const event = `{"_aws":{"LogGroupName":"group"},"value":1}`
+
+func newListener(t *testing.T, address string, rate float64, burst int, size int) (*EMFListener, *testutil.Accumulator) {
+	l := &EMFListener{
+		ServiceAddress: address,
+		RateLimit:      rate,
+		RateLimitBurst: burst,
+		MaxPayloadSize: size,
+		Log:            testutil.Logger{},
+	}
+	l.SetParser(&emf.EMFParser{MetricName: "emf"})
+	assert.NoError(t, l.Init())
+	// the stats are registered once by address
+	for _, stat := range []selfstat.Stat{l.accepted, l.throttled, l.oversized} {
+		stat.Set(0)
+	}
+	acc := &testutil.Accumulator{}
+	assert.NoError(t, l.Start(acc))
+	return l, acc
+}
+
+// waitFor waits for the events to be counted by the listener.
+func waitFor(t *testing.T, l *EMFListener, accepted, throttled, oversized int64) {
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		if l.accepted.Get() == accepted && l.throttled.Get() == throttled && l.oversized.Get() == oversized {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	assert.Equal(t, []int64{accepted, throttled, oversized}, []int64{l.accepted.Get(), l.throttled.Get(), l.oversized.Get()})
+}
+
+func TestStream(t *testing.T) {
+	// no token is refilled during the test
+	l, acc := newListener(t, "tcp://127.0.0.1:0", 0.001, 3, 100)
+	defer l.Stop()
+
+	c, err := net.Dial("tcp", l.addr.String())
+	assert.NoError(t, err)
+	_, err = c.Write([]byte(event + "\n\n" + "{" + strings.Repeat(" ", 200*1024) + "}\n" + event + "\n"))
+	assert.NoError(t, err)
+	waitFor(t, l, 2, 0, 1)
+
+	// the connection is throttled after the burst
+	_, err = c.Write([]byte(event + "\n" + event + "\n" + event))
+	assert.NoError(t, err)
+	assert.NoError(t, c.Close())
+	waitFor(t, l, 3, 2, 1)
+	acc.Wait(3)
+	tags := acc.Metrics[0].Tags
+	assert.Equal(t, "group", tags["log_group_name"])
+
+	// a new connection has its own bucket
+	c, err = net.Dial("tcp", l.addr.String())
+	assert.NoError(t, err)
+	_, err = c.Write([]byte(event + "\n"))
+	assert.NoError(t, err)
+	assert.NoError(t, c.Close())
+	waitFor(t, l, 4, 2, 1)
+}
+
+func TestPacket(t *testing.T) {
+	l, acc := newListener(t, "udp://127.0.0.1:0", 0.001, 2, 100)
+	defer l.Stop()
+
+	c, err := net.Dial("udp", l.addr.String())
+	assert.NoError(t, err)
+	defer c.Close()
+	_, err = c.Write([]byte(event + "\n" + "{" + strings.Repeat(" ", 200) + "}\n" + event))
+	assert.NoError(t, err)
+	waitFor(t, l, 2, 0, 1)
+	_, err = c.Write([]byte(event))
+	assert.NoError(t, err)
+	waitFor(t, l, 2, 1, 1)
+	acc.Wait(2)
+
+	// another peer has its own bucket
+	other, err := net.Dial("udp", l.addr.String())
+	assert.NoError(t, err)
+	defer other.Close()
+	_, err = other.Write([]byte(event))
+	assert.NoError(t, err)
+	waitFor(t, l, 3, 1, 1)
+}
+
+func TestUnlimited(t *testing.T) {
+	l, _ := newListener(t, "udp://127.0.0.1:0", 0, 0, 0)
+	defer l.Stop()
+	assert.Nil(t, l.newBucket())
+	assert.Equal(t, defaultMaxPayloadSize, l.MaxPayloadSize)
+}
+
+func TestInit(t *testing.T) {
+	l := &EMFListener{ServiceAddress: "udp://127.0.0.1:25888", RateLimit: 2.5, Log: testutil.Logger{}}
+	assert.NoError(t, l.Init())
+	assert.Equal(t, 3, l.RateLimitBurst)
+
+	assert.Error(t, (&EMFListener{RateLimit: -1}).Init())
+	assert.Error(t, (&EMFListener{RateLimitBurst: -1}).Init())
+	assert.Error(t, (&EMFListener{MaxPayloadSize: -1}).Init())
+
+	l = &EMFListener{ServiceAddress: "sctp://127.0.0.1:25888", Log: testutil.Logger{}}
+	l.SetParser(&emf.EMFParser{})
+	assert.NoError(t, l.Init())
+	assert.Error(t, l.Start(&testutil.Accumulator{}))
+	assert.Error(t, (&EMFListener{ServiceAddress: "udp://127.0.0.1:25888"}).Start(&testutil.Accumulator{}))
+}
+
+func TestStreamHandoff(t *testing.T) {
+	const address = "tcp://127.0.0.1:0"
+	l, acc := newListener(t, address, 0, 0, 0)
+	c, err := net.Dial("tcp", l.addr.String())
+	assert.NoError(t, err)
+	defer c.Close()
+	_, err = c.Write([]byte(event + "\n" + event[:10]))
+	assert.NoError(t, err)
+	acc.Wait(1)
+
+	// the connection and its partial event are kept by the stopped listener for the next one
+	handoff.Begin()
+	l.Stop()
+	_, err = c.Write([]byte(event[10:] + "\n"))
+	assert.NoError(t, err)
+	handoff.Abort()
+
+	l, acc = newListener(t, address, 0, 0, 0)
+	defer l.Stop()
+	acc.Wait(1)
+	assert.Equal(t, "group", acc.Metrics[0].Tags["log_group_name"])
+	// the event completed by the bytes read by the new listener
+	assert.Equal(t, event, acc.Metrics[0].Fields["value"])
+}
@@ -0,0 +1,40 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package emf_listener
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTokenBucket(t *testing.T) {
+	now := time.Unix(1600000000, 0)
+	b := newTokenBucket(2, 3, now)
+
+	// the burst is admitted at once
+	for i := 0; i < 3; i++ {
+		assert.True(t, b.allow(now))
+	}
+	assert.False(t, b.allow(now))
+
+	// refilled at the rate
+	now = now.Add(500 * time.Millisecond)
+	assert.True(t, b.allow(now))
+	assert.False(t, b.allow(now))
+
+	// never above the burst
+	now = now.Add(time.Hour)
+	for i := 0; i < 3; i++ {
+		assert.True(t, b.allow(now))
+	}
+	assert.False(t, b.allow(now))
+
+	// the clock going back doesn't take tokens
+	now = now.Add(-time.Minute)
+	assert.False(t, b.allow(now))
+	now = now.Add(time.Minute + 500*time.Millisecond)
+	assert.True(t, b.allow(now))
+}
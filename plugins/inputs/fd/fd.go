@@ -0,0 +1,220 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package fd
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/plugins/inputs"
+)
+
+const (
+	measurement      = "fd"
+	processNameTag   = "process_name"
+	defaultProcRoot  = "/proc"
+	maxOpenFilesLine = "Max open files"
+	unlimited        = "unlimited"
+)
+
+var sampleConfig = `
+  ## The number of processes reported, the ones closest to their open files limit. 0 only reports the file
+  ## descriptors of the host.
+  top_processes = 5
+`
+
+type FD struct {
+	TopProcesses int `toml:"top_processes"`
+
+	Log telegraf.Logger `toml:"-"`
+
+	// the procfs mount, replaced in tests
+	procRoot string
+}
+
+// process is the file descriptor usage of a process.
+type process struct {
+	pid   int
+	name  string
+	open  uint64
+	limit uint64
+}
+
+func (p process) utilization() float64 {
+	if p.limit == 0 {
+		return 0
+	}
+	return 100 * float64(p.open) / float64(p.limit)
+}
+
+func (f *FD) SampleConfig() string {
+	return sampleConfig
+}
+
+func (f *FD) Description() string {
+	return "Report the open file descriptors of the host and of the processes closest to their limit."
+}
+
+func (f *FD) Init() error {
+	if f.TopProcesses < 0 {
+		return fmt.Errorf("fd: top_processes must not be negative")
+	}
+	if f.procRoot == "" {
+		f.procRoot = defaultProcRoot
+	}
+	return nil
+}
+
+func (f *FD) Gather(acc telegraf.Accumulator) error {
+	fields, err := f.system()
+	if err != nil {
+		return err
+	}
+	acc.AddFields(measurement, fields, map[string]string{})
+	if f.TopProcesses == 0 {
+		return nil
+	}
+	for _, p := range f.top() {
+		acc.AddFields(measurement, map[string]interface{}{
+			"process_open":        p.open,
+			"process_limit":       p.limit,
+			"process_utilization": p.utilization(),
+		}, map[string]string{processNameTag: p.name})
+	}
+	return nil
+}
+
+// system reads the file descriptors allocated on the host and the maximum of the kernel, file-nr is like
+// "1024	0	9223372036854775807", the allocated, the allocated but unused and the maximum descriptors.
+func (f *FD) system() (map[string]interface{}, error) {
+	path := filepath.Join(f.procRoot, "sys", "fs", "file-nr")
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	values := strings.Fields(string(b))
+	if len(values) != 3 {
+		return nil, fmt.Errorf("unexpected content of %s: %q", path, string(b))
+	}
+	var v [3]uint64
+	for i := range values {
+		if v[i], err = strconv.ParseUint(values[i], 10, 64); err != nil {
+			return nil, fmt.Errorf("unexpected content of %s: %v", path, err)
+		}
+	}
+	allocated := v[0] - v[1]
+	fields := map[string]interface{}{
+		"allocated": allocated,
+		"max":       v[2],
+	}
+	if v[2] > 0 {
+		fields["utilization"] = 100 * float64(allocated) / float64(v[2])
+	}
+	return fields, nil
+}
+
+// top returns the processes with the highest utilization of their open files limit, a single one by name, the
+// closest to its limit, so the dimensions don't change with the pids.
+func (f *FD) top() []process {
+	dirs, err := ioutil.ReadDir(f.procRoot)
+	if err != nil {
+		f.Log.Errorf("Failed to list the processes: %v", err)
+		return nil
+	}
+	byName := map[string]process{}
+	for _, d := range dirs {
+		pid, err := strconv.Atoi(d.Name())
+		if err != nil || !d.IsDir() {
+			continue
+		}
+		// the processes can exit or deny the access to their descriptors, they are skipped
+		p, err := f.process(pid)
+		if err != nil {
+			f.Log.Debugf("Skipping process %d: %v", pid, err)
+			continue
+		}
+		if last, ok := byName[p.name]; !ok || less(last, p) {
+			byName[p.name] = p
+		}
+	}
+	processes := make([]process, 0, len(byName))
+	for _, p := range byName {
+		processes = append(processes, p)
+	}
+	sort.Slice(processes, func(i, j int) bool {
+		return less(processes[j], processes[i])
+	})
+	if len(processes) > f.TopProcesses {
+		processes = processes[:f.TopProcesses]
+	}
+	return processes
+}
+
+// less orders the processes by utilization, then by open descriptors, then by pid.
+func less(a, b process) bool {
+	if a.utilization() != b.utilization() {
+		return a.utilization() < b.utilization()
+	}
+	if a.open != b.open {
+		return a.open < b.open
+	}
+	return a.pid > b.pid
+}
+
+func (f *FD) process(pid int) (process, error) {
+	dir := filepath.Join(f.procRoot, strconv.Itoa(pid))
+	comm, err := ioutil.ReadFile(filepath.Join(dir, "comm"))
+	if err != nil {
+		return process{}, err
+	}
+	limit, err := readLimit(filepath.Join(dir, "limits"))
+	if err != nil {
+		return process{}, err
+	}
+	fds, err := os.Open(filepath.Join(dir, "fd"))
+	if err != nil {
+		return process{}, err
+	}
+	defer fds.Close()
+	names, err := fds.Readdirnames(-1)
+	if err != nil {
+		return process{}, err
+	}
+	return process{pid: pid, name: strings.TrimSpace(string(comm)), open: uint64(len(names)), limit: limit}, nil
+}
+
+// readLimit returns the soft limit of the open files of the process, 0 when unlimited, the line is like
+// "Max open files            1024                 524288               files".
+func readLimit(path string) (uint64, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+	for _, line := range strings.Split(string(b), "\n") {
+		if !strings.HasPrefix(line, maxOpenFilesLine) {
+			continue
+		}
+		values := strings.Fields(strings.TrimPrefix(line, maxOpenFilesLine))
+		if len(values) == 0 {
+			break
+		}
+		if values[0] == unlimited {
+			return 0, nil
+		}
+		return strconv.ParseUint(values[0], 10, 64)
+	}
+	return 0, fmt.Errorf("no open files limit in %s", path)
+}
+
+func init() {
+	inputs.Add("fd", func() telegraf.Input {
+		return &FD{}
+	})
+}
@@ -0,0 +1,138 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package fd
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/influxdata/telegraf/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+const limitsFormat = `Limit                     Soft Limit           Hard Limit           Units
+Max cpu time              unlimited            unlimited            seconds
+Max open files            %s                 524288               files
+Max locked memory         65536                65536                bytes
+`
+
+func writeFiles(t *testing.T, root string, files map[string]string) {
+	for name, content := range files {
+		path := filepath.Join(root, name)
+		assert.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		assert.NoError(t, ioutil.WriteFile(path, []byte(content), 0644))
+	}
+}
+
+// writeProcess writes the procfs entries of a process with open descriptors.
+func writeProcess(t *testing.T, root string, pid int, name string, limit string, open int) {
+	dir := fmt.Sprintf("%d", pid)
+	files := map[string]string{
+		dir + "/comm":   name + "\n",
+		dir + "/limits": fmt.Sprintf(limitsFormat, limit),
+	}
+	for i := 0; i < open; i++ {
+		files[fmt.Sprintf("%s/fd/%d", dir, i)] = ""
+	}
+	writeFiles(t, root, files)
+}
+
+func newFD(t *testing.T, root string, top int) *FD {
+	f := &FD{TopProcesses: top, Log: testutil.Logger{}, procRoot: root}
+	assert.NoError(t, f.Init())
+	return f
+}
+
+func TestGather(t *testing.T) {
+	root, err := ioutil.TempDir("", "fd")
+	assert.NoError(t, err)
+	defer os.RemoveAll(root)
+	writeFiles(t, root, map[string]string{
+		"sys/fs/file-nr": "4096\t0\t65536\n",
+		"self/comm":      "agent\n",
+	})
+	writeProcess(t, root, 1, "systemd", "1024", 2)
+	writeProcess(t, root, 100, "nginx", "16", 12)
+	// the worker closest to its limit is reported for nginx
+	writeProcess(t, root, 101, "nginx", "16", 14)
+	writeProcess(t, root, 200, "java", "8", 4)
+	writeProcess(t, root, 300, "sleep", "unlimited", 3)
+	// a process without access to its descriptors
+	writeFiles(t, root, map[string]string{"400/comm": "sshd\n", "400/limits": fmt.Sprintf(limitsFormat, "1024")})
+
+	var acc testutil.Accumulator
+	assert.NoError(t, acc.GatherError(newFD(t, root, 2).Gather))
+	acc.AssertContainsTaggedFields(t, "fd", map[string]interface{}{
+		"allocated":   uint64(4096),
+		"max":         uint64(65536),
+		"utilization": float64(6.25),
+	}, map[string]string{})
+	acc.AssertContainsTaggedFields(t, "fd", map[string]interface{}{
+		"process_open":        uint64(14),
+		"process_limit":       uint64(16),
+		"process_utilization": float64(87.5),
+	}, map[string]string{"process_name": "nginx"})
+	acc.AssertContainsTaggedFields(t, "fd", map[string]interface{}{
+		"process_open":        uint64(4),
+		"process_limit":       uint64(8),
+		"process_utilization": float64(50),
+	}, map[string]string{"process_name": "java"})
+	assert.Equal(t, 3, len(acc.Metrics))
+
+	// the processes without limit are last
+	acc.ClearMetrics()
+	assert.NoError(t, acc.GatherError(newFD(t, root, 10).Gather))
+	acc.AssertContainsTaggedFields(t, "fd", map[string]interface{}{
+		"process_open":        uint64(3),
+		"process_limit":       uint64(0),
+		"process_utilization": float64(0),
+	}, map[string]string{"process_name": "sleep"})
+	assert.Equal(t, 5, len(acc.Metrics))
+	assert.Equal(t, map[string]string{"process_name": "sleep"}, acc.Metrics[4].Tags)
+
+	acc.ClearMetrics()
+	assert.NoError(t, acc.GatherError(newFD(t, root, 0).Gather))
+	assert.Equal(t, 1, len(acc.Metrics))
+}
+
+func TestGatherErrors(t *testing.T) {
+	root, err := ioutil.TempDir("", "fd")
+	assert.NoError(t, err)
+	defer os.RemoveAll(root)
+
+	var acc testutil.Accumulator
+	assert.Error(t, acc.GatherError(newFD(t, root, 5).Gather))
+	writeFiles(t, root, map[string]string{"sys/fs/file-nr": "4096\t0\n"})
+	assert.Error(t, acc.GatherError(newFD(t, root, 5).Gather))
+	writeFiles(t, root, map[string]string{"sys/fs/file-nr": "4096\t0\tmany\n"})
+	assert.Error(t, acc.GatherError(newFD(t, root, 5).Gather))
+}
+
+func TestReadLimit(t *testing.T) {
+	root, err := ioutil.TempDir("", "fd")
+	assert.NoError(t, err)
+	defer os.RemoveAll(root)
+	writeFiles(t, root, map[string]string{
+		"limits":         fmt.Sprintf(limitsFormat, "1024"),
+		"limits_missing": "Limit                     Soft Limit           Hard Limit           Units\n",
+	})
+
+	limit, err := readLimit(filepath.Join(root, "limits"))
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(1024), limit)
+	_, err = readLimit(filepath.Join(root, "limits_missing"))
+	assert.Error(t, err)
+	_, err = readLimit(filepath.Join(root, "none"))
+	assert.Error(t, err)
+}
+
+func TestInit(t *testing.T) {
+	f := &FD{TopProcesses: 5, Log: testutil.Logger{}}
+	assert.NoError(t, f.Init())
+	assert.Equal(t, defaultProcRoot, f.procRoot)
+	assert.Error(t, (&FD{TopProcesses: -1, Log: testutil.Logger{}}).Init())
+}
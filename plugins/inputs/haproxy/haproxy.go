@@ -0,0 +1,351 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package haproxy
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/amazon-cloudwatch-agent/internal"
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/filter"
+	"github.com/influxdata/telegraf/plugins/inputs"
+)
+
+const (
+	measurement    = "haproxy"
+	defaultServer  = "unix:///var/run/haproxy.sock"
+	defaultTimeout = 5 * time.Second
+	showStat       = "show stat\n"
+)
+
+var sampleConfig = `
+  ## The stats of HAProxy, a stats socket "unix:///path/to/haproxy.sock" or "tcp://host:port", or the url of a stats
+  ## page "http://host:port/stats", the csv of the page is read.
+  # servers = ["unix:///var/run/haproxy.sock"]
+
+  ## The credentials of the stats pages, for the "stats auth" of HAProxy.
+  # username = ""
+  # password = ""
+
+  ## The proxies reported by name, globs accepted. All the proxies are reported when empty.
+  # proxies = []
+
+  ## Maximum time the stats are allowed to be read.
+  # timeout = "5s"
+`
+
+// the types of the stats lines
+var proxyTypes = map[string]string{
+	"0": "frontend",
+	"1": "backend",
+	"2": "server",
+	"3": "listener",
+}
+
+// fieldNames are the stats columns reported, by their csv name.
+// https://cbonte.github.io/haproxy-dconv/2.2/management.html#9.1
+var fieldNames = map[string]string{
+	"qcur":           "qcur",
+	"qmax":           "qmax",
+	"scur":           "scur",
+	"smax":           "smax",
+	"slim":           "slim",
+	"stot":           "stot",
+	"bin":            "bin",
+	"bout":           "bout",
+	"dreq":           "dreq",
+	"dresp":          "dresp",
+	"ereq":           "ereq",
+	"econ":           "econ",
+	"eresp":          "eresp",
+	"wretr":          "wretr",
+	"wredis":         "wredis",
+	"chkfail":        "chkfail",
+	"chkdown":        "chkdown",
+	"downtime":       "downtime",
+	"act":            "active_servers",
+	"bck":            "backup_servers",
+	"rate":           "rate",
+	"req_rate":       "req_rate",
+	"req_tot":        "req_tot",
+	"hrsp_1xx":       "hrsp_1xx",
+	"hrsp_2xx":       "hrsp_2xx",
+	"hrsp_3xx":       "hrsp_3xx",
+	"hrsp_4xx":       "hrsp_4xx",
+	"hrsp_5xx":       "hrsp_5xx",
+	"cli_abrt":       "cli_abrt",
+	"srv_abrt":       "srv_abrt",
+	"check_duration": "check_duration",
+	"qtime":          "qtime",
+	"ctime":          "ctime",
+	"rtime":          "rtime",
+	"ttime":          "ttime",
+}
+
+// rateFields are the error counters also reported as per second rates, since the previous gather.
+var rateFields = map[string]string{
+	"ereq":     "ereq_rate",
+	"econ":     "econ_rate",
+	"eresp":    "eresp_rate",
+	"hrsp_5xx": "hrsp_5xx_rate",
+}
+
+type HAProxy struct {
+	Servers  []string          `toml:"servers"`
+	Username string            `toml:"username"`
+	Password string            `toml:"password"`
+	Proxies  []string          `toml:"proxies"`
+	Timeout  internal.Duration `toml:"timeout"`
+
+	Log telegraf.Logger `toml:"-"`
+
+	client      *http.Client
+	proxyFilter filter.Filter
+
+	mu sync.Mutex
+	// the error counters of the previous gather, by server, proxy and name of the line
+	last map[string]counters
+}
+
+type counters struct {
+	t      time.Time
+	values map[string]int64
+}
+
+func (h *HAProxy) SampleConfig() string {
+	return sampleConfig
+}
+
+func (h *HAProxy) Description() string {
+	return "Report the sessions, queues, errors and health of the frontends, backends and servers of HAProxy."
+}
+
+func (h *HAProxy) Init() error {
+	if len(h.Servers) == 0 {
+		h.Servers = []string{defaultServer}
+	}
+	for _, server := range h.Servers {
+		u, err := url.Parse(server)
+		if err != nil {
+			return fmt.Errorf("haproxy: invalid server %q: %v", server, err)
+		}
+		switch u.Scheme {
+		case "unix", "tcp", "http", "https":
+		default:
+			return fmt.Errorf("haproxy: invalid server %q, unix://, tcp://, http:// or https:// is expected", server)
+		}
+	}
+	if h.Timeout.Duration <= 0 {
+		h.Timeout.Duration = defaultTimeout
+	}
+	var err error
+	if h.proxyFilter, err = filter.Compile(h.Proxies); err != nil {
+		return fmt.Errorf("haproxy: invalid proxies: %v", err)
+	}
+	h.client = &http.Client{Timeout: h.Timeout.Duration}
+	h.last = make(map[string]counters)
+	return nil
+}
+
+func (h *HAProxy) Gather(acc telegraf.Accumulator) error {
+	var wg sync.WaitGroup
+	for _, server := range h.Servers {
+		wg.Add(1)
+		go func(server string) {
+			defer wg.Done()
+			if err := h.gatherServer(acc, server); err != nil {
+				acc.AddError(fmt.Errorf("failed to read the stats of %s: %v", server, err))
+			}
+		}(server)
+	}
+	wg.Wait()
+	return nil
+}
+
+func (h *HAProxy) gatherServer(acc telegraf.Accumulator, server string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), h.Timeout.Duration)
+	defer cancel()
+	stats, err := h.readStats(ctx, server)
+	if err != nil {
+		return err
+	}
+	defer stats.Close()
+
+	now := time.Now()
+	r := csv.NewReader(stats)
+	r.FieldsPerRecord = -1
+	var header []string
+	seen := make(map[string]bool)
+	for {
+		record, err := r.Read()
+		if err == io.EOF {
+			h.prune(server, seen)
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if header == nil {
+			// the header line is "# pxname,svname,qcur,..."
+			if len(record) == 0 || !strings.HasPrefix(record[0], "# ") {
+				return fmt.Errorf("unexpected stats header %q", strings.Join(record, ","))
+			}
+			record[0] = strings.TrimPrefix(record[0], "# ")
+			header = record
+			continue
+		}
+		line := make(map[string]string, len(header))
+		for i, value := range record {
+			if i < len(header) {
+				line[header[i]] = value
+			}
+		}
+		proxy := line["pxname"]
+		if proxy == "" || (h.proxyFilter != nil && !h.proxyFilter.Match(proxy)) {
+			continue
+		}
+		tags := map[string]string{
+			"proxy":  proxy,
+			"server": line["svname"],
+			"type":   proxyTypes[line["type"]],
+		}
+		fields := lineFields(line)
+		key := server + "/" + proxy + "/" + line["svname"] + "/" + line["type"]
+		seen[key] = true
+		h.addRates(key, now, fields)
+		acc.AddFields(measurement, fields, tags, now)
+	}
+}
+
+// readStats returns the csv of the stats, from the stats socket or the stats page of the server.
+func (h *HAProxy) readStats(ctx context.Context, server string) (io.ReadCloser, error) {
+	u, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme == "unix" || u.Scheme == "tcp" {
+		address := u.Host
+		if u.Scheme == "unix" {
+			address = u.Path
+		}
+		var d net.Dialer
+		conn, err := d.DialContext(ctx, u.Scheme, address)
+		if err != nil {
+			return nil, err
+		}
+		if deadline, ok := ctx.Deadline(); ok {
+			conn.SetDeadline(deadline)
+		}
+		// the socket answers a single command and closes the connection
+		if _, err := conn.Write([]byte(showStat)); err != nil {
+			conn.Close()
+			return nil, err
+		}
+		return conn, nil
+	}
+
+	if !strings.HasSuffix(u.Path, ";csv") {
+		u.Path += ";csv"
+	}
+	req, err := http.NewRequest(http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	if h.Username != "" || h.Password != "" {
+		req.SetBasicAuth(h.Username, h.Password)
+	}
+	resp, err := h.client.Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		io.Copy(ioutil.Discard, resp.Body)
+		resp.Body.Close()
+		return nil, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return resp.Body, nil
+}
+
+// lineFields returns the fields of a stats line, the empty columns are not reported.
+func lineFields(line map[string]string) map[string]interface{} {
+	fields := make(map[string]interface{})
+	for column, name := range fieldNames {
+		if v, err := strconv.ParseInt(line[column], 10, 64); err == nil {
+			fields[name] = v
+		}
+	}
+	if status := line["status"]; status != "" {
+		fields["up"] = statusUp(status)
+	}
+	return fields
+}
+
+// statusUp returns 1 for the lines that take traffic, a frontend is OPEN, a backend and a server are UP, and 0
+// otherwise like DOWN, NOLB or MAINT. A server whose health is not checked is up.
+func statusUp(status string) int {
+	switch {
+	case strings.HasPrefix(status, "UP"), status == "OPEN", status == "no check":
+		return 1
+	default:
+		return 0
+	}
+}
+
+// addRates adds the per second rates of the error counters since the previous gather, the first gather and the
+// gathers after a reload of HAProxy, which resets the counters, have no rate.
+func (h *HAProxy) addRates(key string, now time.Time, fields map[string]interface{}) {
+	current := counters{t: now, values: make(map[string]int64, len(rateFields))}
+	for column := range rateFields {
+		if v, ok := fields[column].(int64); ok {
+			current.values[column] = v
+		}
+	}
+	h.mu.Lock()
+	previous, ok := h.last[key]
+	h.last[key] = current
+	h.mu.Unlock()
+	if !ok {
+		return
+	}
+	seconds := now.Sub(previous.t).Seconds()
+	if seconds <= 0 {
+		return
+	}
+	for column, name := range rateFields {
+		v, ok := current.values[column]
+		p, pok := previous.values[column]
+		if !ok || !pok || v < p {
+			continue
+		}
+		fields[name] = float64(v-p) / seconds
+	}
+}
+
+// prune forgets the counters of the lines of the server that are gone, e.g. the servers removed from a backend.
+func (h *HAProxy) prune(server string, seen map[string]bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for key := range h.last {
+		if strings.HasPrefix(key, server+"/") && !seen[key] {
+			delete(h.last, key)
+		}
+	}
+}
+
+func init() {
+	inputs.Add("haproxy", func() telegraf.Input {
+		return &HAProxy{}
+	})
+}
@@ -0,0 +1,233 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package haproxy
+
+import (
+	"bufio"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/influxdata/telegraf/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+// the columns of HAProxy 2.2, the line ends with a comma
+const statsHeader = "# pxname,svname,qcur,qmax,scur,smax,slim,stot,bin,bout,dreq,dresp,ereq,econ,eresp,wretr,wredis,status,weight," +
+	"act,bck,chkfail,chkdown,lastchg,downtime,qlimit,pid,iid,sid,throttle,lbtot,tracked,type,rate,rate_lim,rate_max," +
+	"check_status,check_code,check_duration,hrsp_1xx,hrsp_2xx,hrsp_3xx,hrsp_4xx,hrsp_5xx,hrsp_other,hanafail,req_rate," +
+	"req_rate_max,req_tot,cli_abrt,srv_abrt,qtime,ctime,rtime,ttime,"
+
+func statsLine(values map[string]string) string {
+	columns := strings.Split(strings.TrimPrefix(statsHeader, "# "), ",")
+	line := make([]string, len(columns))
+	for i, column := range columns {
+		line[i] = values[column]
+	}
+	return strings.Join(line, ",")
+}
+
+func statsCSV(ereq, eresp string) string {
+	return strings.Join([]string{
+		statsHeader,
+		statsLine(map[string]string{"pxname": "http-in", "svname": "FRONTEND", "scur": "3", "smax": "10", "slim": "2000", "stot": "150",
+			"ereq": ereq, "status": "OPEN", "type": "0", "rate": "5", "hrsp_2xx": "140", "hrsp_4xx": "8", "hrsp_5xx": "2", "req_rate": "4", "req_tot": "150"}),
+		statsLine(map[string]string{"pxname": "web", "svname": "web1", "qcur": "0", "qmax": "1", "scur": "1", "stot": "50", "econ": "1",
+			"eresp": eresp, "status": "UP", "act": "1", "bck": "0", "chkfail": "0", "type": "2", "check_duration": "1", "hrsp_5xx": "2",
+			"qtime": "0", "ctime": "1", "rtime": "12", "ttime": "15"}),
+		statsLine(map[string]string{"pxname": "web", "svname": "web2", "qcur": "0", "scur": "0", "econ": "4", "eresp": "0",
+			"status": "DOWN", "chkfail": "3", "chkdown": "1", "downtime": "60", "type": "2"}),
+		statsLine(map[string]string{"pxname": "web", "svname": "BACKEND", "qcur": "2", "scur": "1", "econ": "5", "eresp": eresp,
+			"status": "UP", "act": "1", "bck": "0", "type": "1"}),
+		statsLine(map[string]string{"pxname": "stats", "svname": "FRONTEND", "scur": "1", "status": "OPEN", "type": "0"}),
+		"",
+	}, "\n")
+}
+
+// serveSocket answers the show stat command on a unix socket like the stats socket of HAProxy.
+func serveSocket(t *testing.T, stats *string) (string, func()) {
+	dir, err := ioutil.TempDir("", "haproxy")
+	assert.NoError(t, err)
+	path := filepath.Join(dir, "haproxy.sock")
+	l, err := net.Listen("unix", path)
+	assert.NoError(t, err)
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			command, _ := bufio.NewReader(conn).ReadString('\n')
+			assert.Equal(t, "show stat\n", command)
+			conn.Write([]byte(*stats))
+			conn.Close()
+		}
+	}()
+	return "unix://" + path, func() {
+		l.Close()
+		os.RemoveAll(dir)
+	}
+}
+
+func TestGatherSocket(t *testing.T) {
+	stats := statsCSV("2", "3")
+	server, stop := serveSocket(t, &stats)
+	defer stop()
+	h := &HAProxy{Servers: []string{server}, Log: testutil.Logger{}}
+	assert.NoError(t, h.Init())
+
+	var acc testutil.Accumulator
+	assert.NoError(t, h.Gather(&acc))
+	assert.Empty(t, acc.Errors)
+	assert.Len(t, acc.Metrics, 5)
+	acc.AssertContainsTaggedFields(t, "haproxy",
+		map[string]interface{}{
+			"scur":     int64(3),
+			"smax":     int64(10),
+			"slim":     int64(2000),
+			"stot":     int64(150),
+			"ereq":     int64(2),
+			"rate":     int64(5),
+			"hrsp_2xx": int64(140),
+			"hrsp_4xx": int64(8),
+			"hrsp_5xx": int64(2),
+			"req_rate": int64(4),
+			"req_tot":  int64(150),
+			"up":       1,
+		},
+		map[string]string{"proxy": "http-in", "server": "FRONTEND", "type": "frontend"})
+	acc.AssertContainsTaggedFields(t, "haproxy",
+		map[string]interface{}{
+			"qcur":           int64(0),
+			"qmax":           int64(1),
+			"scur":           int64(1),
+			"stot":           int64(50),
+			"econ":           int64(1),
+			"eresp":          int64(3),
+			"active_servers": int64(1),
+			"backup_servers": int64(0),
+			"chkfail":        int64(0),
+			"check_duration": int64(1),
+			"hrsp_5xx":       int64(2),
+			"qtime":          int64(0),
+			"ctime":          int64(1),
+			"rtime":          int64(12),
+			"ttime":          int64(15),
+			"up":             1,
+		},
+		map[string]string{"proxy": "web", "server": "web1", "type": "server"})
+	acc.AssertContainsTaggedFields(t, "haproxy",
+		map[string]interface{}{
+			"qcur":     int64(0),
+			"scur":     int64(0),
+			"econ":     int64(4),
+			"eresp":    int64(0),
+			"chkfail":  int64(3),
+			"chkdown":  int64(1),
+			"downtime": int64(60),
+			"up":       0,
+		},
+		map[string]string{"proxy": "web", "server": "web2", "type": "server"})
+
+	// the rates of the error counters since the previous gather
+	h.last[server+"/web/BACKEND/1"] = counters{t: time.Now().Add(-10 * time.Second), values: h.last[server+"/web/BACKEND/1"].values}
+	stats = statsCSV("2", "53")
+	acc.ClearMetrics()
+	assert.NoError(t, h.Gather(&acc))
+	for _, m := range acc.Metrics {
+		if m.Tags["server"] != "BACKEND" {
+			continue
+		}
+		assert.InDelta(t, 5.0, m.Fields["eresp_rate"], 0.1)
+		assert.Equal(t, 0.0, m.Fields["econ_rate"])
+		assert.NotContains(t, m.Fields, "ereq_rate")
+	}
+
+	// HAProxy reloaded, the counters are reset
+	stats = statsCSV("0", "0")
+	acc.ClearMetrics()
+	assert.NoError(t, h.Gather(&acc))
+	for _, m := range acc.Metrics {
+		switch m.Tags["server"] {
+		case "FRONTEND":
+			assert.NotContains(t, m.Fields, "ereq_rate")
+		case "BACKEND", "web1":
+			assert.NotContains(t, m.Fields, "eresp_rate")
+		}
+	}
+}
+
+func TestGatherHTTP(t *testing.T) {
+	stats := statsCSV("0", "0")
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, password, ok := r.BasicAuth()
+		if !ok || user != "admin" || password != "secret" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		assert.Equal(t, "/stats;csv", r.URL.Path)
+		w.Write([]byte(stats))
+	}))
+	defer server.Close()
+
+	h := &HAProxy{Servers: []string{server.URL + "/stats"}, Username: "admin", Password: "secret", Proxies: []string{"web", "http-*"}, Log: testutil.Logger{}}
+	assert.NoError(t, h.Init())
+	var acc testutil.Accumulator
+	assert.NoError(t, h.Gather(&acc))
+	assert.Empty(t, acc.Errors)
+	assert.Len(t, acc.Metrics, 4)
+	for _, m := range acc.Metrics {
+		assert.NotEqual(t, "stats", m.Tags["proxy"])
+	}
+
+	h.Password = "wrong"
+	acc.ClearMetrics()
+	assert.NoError(t, h.Gather(&acc))
+	assert.Len(t, acc.Errors, 1)
+	assert.Empty(t, acc.Metrics)
+}
+
+func TestGatherErrors(t *testing.T) {
+	stats := "pxname,svname\nweb,BACKEND\n"
+	server, stop := serveSocket(t, &stats)
+	defer stop()
+	h := &HAProxy{Servers: []string{server, "unix:///nonexistent/haproxy.sock"}, Log: testutil.Logger{}}
+	assert.NoError(t, h.Init())
+	var acc testutil.Accumulator
+	assert.NoError(t, h.Gather(&acc))
+	assert.Len(t, acc.Errors, 2)
+	assert.Empty(t, acc.Metrics)
+}
+
+func TestPrune(t *testing.T) {
+	stats := statsCSV("0", "0")
+	server, stop := serveSocket(t, &stats)
+	defer stop()
+	h := &HAProxy{Servers: []string{server}, Proxies: []string{"web"}, Log: testutil.Logger{}}
+	assert.NoError(t, h.Init())
+	var acc testutil.Accumulator
+	assert.NoError(t, h.Gather(&acc))
+	assert.Len(t, h.last, 3)
+
+	// web2 is removed from the backend
+	stats = strings.Replace(stats, "web,web2,", "other,web2,", 1)
+	assert.NoError(t, h.Gather(&acc))
+	assert.Len(t, h.last, 2)
+	assert.NotContains(t, h.last, server+"/web/web2/2")
+}
+
+func TestInit(t *testing.T) {
+	h := &HAProxy{}
+	assert.NoError(t, h.Init())
+	assert.Equal(t, []string{defaultServer}, h.Servers)
+	assert.Equal(t, defaultTimeout, h.Timeout.Duration)
+	assert.Error(t, (&HAProxy{Servers: []string{"/var/run/haproxy.sock"}}).Init())
+	assert.Error(t, (&HAProxy{Servers: []string{"ftp://localhost"}}).Init())
+}
@@ -0,0 +1,122 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package kafka
+
+import (
+	"crypto/tls"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"time"
+)
+
+const (
+	clientID = "cloudwatch-agent"
+	// the largest response the plugin accepts, the metadata of a large cluster is a few megabytes
+	maxResponseSize = 64 * 1024 * 1024
+)
+
+// broker is a connection to a Kafka broker, the requests are sent one at a time.
+type broker struct {
+	addr          string
+	conn          net.Conn
+	timeout       time.Duration
+	correlationID int32
+}
+
+func dialBroker(addr string, timeout time.Duration, tlsConfig *tls.Config) (*broker, error) {
+	dialer := &net.Dialer{Timeout: timeout}
+	var conn net.Conn
+	var err error
+	if tlsConfig != nil {
+		conn, err = tls.DialWithDialer(dialer, "tcp", addr, tlsConfig)
+	} else {
+		conn, err = dialer.Dial("tcp", addr)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &broker{addr: addr, conn: conn, timeout: timeout}, nil
+}
+
+// request sends a request and returns the decoder of the body of its response.
+func (b *broker) request(apiKey, version int16, body []byte) (*decoder, error) {
+	b.correlationID++
+	var e encoder
+	e.int32(0) // size, set below
+	e.int16(apiKey)
+	e.int16(version)
+	e.int32(b.correlationID)
+	e.string(clientID)
+	e.buf = append(e.buf, body...)
+	binary.BigEndian.PutUint32(e.buf, uint32(len(e.buf)-4))
+
+	if err := b.conn.SetDeadline(time.Now().Add(b.timeout)); err != nil {
+		return nil, err
+	}
+	if _, err := b.conn.Write(e.buf); err != nil {
+		return nil, err
+	}
+	var header [8]byte
+	if _, err := io.ReadFull(b.conn, header[:]); err != nil {
+		return nil, err
+	}
+	size := int32(binary.BigEndian.Uint32(header[:4]))
+	if size < 4 || size > maxResponseSize {
+		return nil, fmt.Errorf("invalid response size %d", size)
+	}
+	if id := int32(binary.BigEndian.Uint32(header[4:])); id != b.correlationID {
+		return nil, fmt.Errorf("unexpected correlation id %d, expected %d", id, b.correlationID)
+	}
+	resp := make([]byte, size-4)
+	if _, err := io.ReadFull(b.conn, resp); err != nil {
+		return nil, err
+	}
+	return &decoder{buf: resp}, nil
+}
+
+func (b *broker) metadata() (*metadataResponse, error) {
+	d, err := b.request(apiMetadata, versionMetadata, encodeMetadataRequest())
+	if err != nil {
+		return nil, err
+	}
+	return decodeMetadataResponse(d)
+}
+
+func (b *broker) listOffsets(partitions map[string][]int32) (map[string]map[int32]int64, error) {
+	d, err := b.request(apiListOffsets, versionListOffsets, encodeListOffsetsRequest(partitions))
+	if err != nil {
+		return nil, err
+	}
+	return decodeListOffsetsResponse(d)
+}
+
+func (b *broker) findCoordinator(group string) (brokerMetadata, error) {
+	d, err := b.request(apiFindCoordinator, versionFindCoordinator, encodeFindCoordinatorRequest(group))
+	if err != nil {
+		return brokerMetadata{}, err
+	}
+	return decodeFindCoordinatorResponse(d)
+}
+
+func (b *broker) offsetFetch(group string) (map[string]map[int32]int64, error) {
+	d, err := b.request(apiOffsetFetch, versionOffsetFetch, encodeOffsetFetchRequest(group))
+	if err != nil {
+		return nil, err
+	}
+	return decodeOffsetFetchResponse(d)
+}
+
+func (b *broker) listGroups() ([]group, error) {
+	d, err := b.request(apiListGroups, versionListGroups, nil)
+	if err != nil {
+		return nil, err
+	}
+	return decodeListGroupsResponse(d)
+}
+
+func (b *broker) close() {
+	b.conn.Close()
+}
@@ -0,0 +1,271 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package kafka
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/amazon-cloudwatch-agent/internal"
+	"github.com/aws/amazon-cloudwatch-agent/metric/distribution"
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/filter"
+	"github.com/influxdata/telegraf/plugins/inputs"
+)
+
+const (
+	measurement    = "kafka"
+	defaultBroker  = "localhost:9092"
+	defaultTimeout = 5 * time.Second
+	// the topics of Kafka itself, like __consumer_offsets
+	internalTopicPrefix = "__"
+)
+
+var sampleConfig = `
+  ## The bootstrap brokers the metadata of the cluster is discovered from, "host:port".
+  # brokers = ["localhost:9092"]
+
+  ## The consumer groups whose lag is reported and the topics reported, globs accepted. All of them are reported
+  ## when empty, the internal topics of Kafka are never reported.
+  # consumer_groups = []
+  # topics = []
+
+  ## Report the lag of every partition of the consumer groups too, the lag of a group on a topic is a distribution
+  ## of the lags of its partitions otherwise.
+  # per_partition_lag = false
+
+  ## Connect to the brokers with TLS, the certificates of the brokers are verified with the CAs of the system.
+  # tls = false
+  # insecure_skip_verify = false
+
+  ## Maximum time the requests to a broker are allowed to run.
+  # timeout = "5s"
+`
+
+type Kafka struct {
+	Brokers            []string          `toml:"brokers"`
+	ConsumerGroups     []string          `toml:"consumer_groups"`
+	Topics             []string          `toml:"topics"`
+	PerPartitionLag    bool              `toml:"per_partition_lag"`
+	TLS                bool              `toml:"tls"`
+	InsecureSkipVerify bool              `toml:"insecure_skip_verify"`
+	Timeout            internal.Duration `toml:"timeout"`
+
+	Log telegraf.Logger `toml:"-"`
+
+	groupFilter filter.Filter
+	topicFilter filter.Filter
+	tlsConfig   *tls.Config
+}
+
+func (k *Kafka) SampleConfig() string {
+	return sampleConfig
+}
+
+func (k *Kafka) Description() string {
+	return "Report the partitions of the topics of a Kafka cluster and the lag of its consumer groups."
+}
+
+func (k *Kafka) Init() error {
+	if len(k.Brokers) == 0 {
+		k.Brokers = []string{defaultBroker}
+	}
+	for _, addr := range k.Brokers {
+		if _, _, err := net.SplitHostPort(addr); err != nil {
+			return fmt.Errorf("kafka: invalid broker %q: %v", addr, err)
+		}
+	}
+	if k.Timeout.Duration <= 0 {
+		k.Timeout.Duration = defaultTimeout
+	}
+	var err error
+	if k.groupFilter, err = filter.Compile(k.ConsumerGroups); err != nil {
+		return fmt.Errorf("kafka: invalid consumer_groups: %v", err)
+	}
+	if k.topicFilter, err = filter.Compile(k.Topics); err != nil {
+		return fmt.Errorf("kafka: invalid topics: %v", err)
+	}
+	if k.TLS {
+		k.tlsConfig = &tls.Config{InsecureSkipVerify: k.InsecureSkipVerify}
+	}
+	return nil
+}
+
+// partitionKey is a partition of a topic.
+type partitionKey struct {
+	topic string
+	id    int32
+}
+
+func (k *Kafka) Gather(acc telegraf.Accumulator) error {
+	s := &session{timeout: k.Timeout.Duration, tlsConfig: k.tlsConfig, conns: make(map[string]*broker)}
+	defer s.close()
+
+	md, err := s.bootstrap(k.Brokers)
+	if err != nil {
+		return fmt.Errorf("failed to get the metadata of the cluster from %s: %v", strings.Join(k.Brokers, ","), err)
+	}
+	addrs := make(map[int32]string, len(md.brokers))
+	for _, b := range md.brokers {
+		addrs[b.nodeID] = net.JoinHostPort(b.host, strconv.Itoa(int(b.port)))
+	}
+
+	// the partitions of the reported topics by the address of their leader
+	byLeader := make(map[string]map[string][]int32)
+	clusterFields := map[string]interface{}{
+		"cluster_brokers":                     len(md.brokers),
+		"cluster_topics":                      0,
+		"cluster_partitions":                  0,
+		"cluster_under_replicated_partitions": 0,
+		"cluster_offline_partitions":          0,
+	}
+	topicFields := make(map[string]map[string]interface{})
+	for _, t := range md.topics {
+		if !k.reportTopic(t.name, t.internal) {
+			continue
+		}
+		fields := map[string]interface{}{
+			"topic_partitions":                  len(t.partitions),
+			"topic_under_replicated_partitions": 0,
+			"topic_offline_partitions":          0,
+		}
+		for _, p := range t.partitions {
+			if len(p.isr) < len(p.replicas) {
+				fields["topic_under_replicated_partitions"] = fields["topic_under_replicated_partitions"].(int) + 1
+			}
+			addr, ok := addrs[p.leader]
+			if p.leader < 0 || !ok {
+				fields["topic_offline_partitions"] = fields["topic_offline_partitions"].(int) + 1
+				continue
+			}
+			if byLeader[addr] == nil {
+				byLeader[addr] = make(map[string][]int32)
+			}
+			byLeader[addr][t.name] = append(byLeader[addr][t.name], p.id)
+		}
+		topicFields[t.name] = fields
+		addInt(clusterFields, "cluster_topics", 1)
+		addInt(clusterFields, "cluster_partitions", fields["topic_partitions"].(int))
+		addInt(clusterFields, "cluster_under_replicated_partitions", fields["topic_under_replicated_partitions"].(int))
+		addInt(clusterFields, "cluster_offline_partitions", fields["topic_offline_partitions"].(int))
+	}
+	acc.AddFields(measurement, clusterFields, map[string]string{})
+
+	endOffsets := make(map[partitionKey]int64)
+	for addr, partitions := range byLeader {
+		offsets, err := s.listOffsets(addr, partitions)
+		if err != nil {
+			acc.AddError(fmt.Errorf("failed to get the offsets of the partitions led by %s: %v", addr, err))
+			continue
+		}
+		for topic, ids := range offsets {
+			for id, offset := range ids {
+				endOffsets[partitionKey{topic: topic, id: id}] = offset
+			}
+		}
+	}
+	for topic, fields := range topicFields {
+		var logEndOffset int64
+		for p, offset := range endOffsets {
+			if p.topic == topic {
+				logEndOffset += offset
+			}
+		}
+		fields["topic_log_end_offset"] = logEndOffset
+		acc.AddFields(measurement, fields, map[string]string{"topic": topic})
+	}
+
+	for _, g := range k.consumerGroups(acc, s, addrs) {
+		k.gatherGroup(acc, s, g, endOffsets)
+	}
+	return nil
+}
+
+func (k *Kafka) reportTopic(name string, internal bool) bool {
+	if internal || strings.HasPrefix(name, internalTopicPrefix) {
+		return false
+	}
+	return k.topicFilter == nil || k.topicFilter.Match(name)
+}
+
+// consumerGroups returns the reported consumer groups of all the brokers, a broker only lists the groups it
+// coordinates.
+func (k *Kafka) consumerGroups(acc telegraf.Accumulator, s *session, addrs map[int32]string) []string {
+	seen := make(map[string]bool)
+	var groups []string
+	for _, addr := range addrs {
+		list, err := s.listGroups(addr)
+		if err != nil {
+			acc.AddError(fmt.Errorf("failed to list the consumer groups of %s: %v", addr, err))
+			continue
+		}
+		for _, g := range list {
+			// the groups of Kafka Connect and the other protocols have no committed offsets, the groups that only
+			// commit offsets have no protocol
+			if g.protocolType != "consumer" && g.protocolType != "" {
+				continue
+			}
+			if seen[g.id] || (k.groupFilter != nil && !k.groupFilter.Match(g.id)) {
+				continue
+			}
+			seen[g.id] = true
+			groups = append(groups, g.id)
+		}
+	}
+	sort.Strings(groups)
+	return groups
+}
+
+// gatherGroup reports the lag of a consumer group on the reported topics, the difference between the log end
+// offset and the committed offset of each partition.
+func (k *Kafka) gatherGroup(acc telegraf.Accumulator, s *session, group string, endOffsets map[partitionKey]int64) {
+	committed, err := s.committedOffsets(group)
+	if err != nil {
+		acc.AddError(fmt.Errorf("failed to get the committed offsets of consumer group %s: %v", group, err))
+		return
+	}
+	for topic, offsets := range committed {
+		if !k.reportTopic(topic, false) {
+			continue
+		}
+		lags := distribution.NewDistribution()
+		for id, offset := range offsets {
+			end, ok := endOffsets[partitionKey{topic: topic, id: id}]
+			if !ok {
+				continue
+			}
+			lag := end - offset
+			if lag < 0 {
+				// the end offset was read before the commit
+				lag = 0
+			}
+			if err := lags.AddEntry(float64(lag), 1); err != nil {
+				k.Log.Warnf("Failed to add the lag %d of consumer group %s: %v", lag, group, err)
+			}
+			if k.PerPartitionLag {
+				acc.AddFields(measurement, map[string]interface{}{"consumer_partition_lag": lag},
+					map[string]string{"group": group, "topic": topic, "partition": strconv.Itoa(int(id))})
+			}
+		}
+		if lags.SampleCount() == 0 {
+			continue
+		}
+		acc.AddFields(measurement, map[string]interface{}{"consumer_lag": lags}, map[string]string{"group": group, "topic": topic})
+	}
+}
+
+func addInt(fields map[string]interface{}, name string, v int) {
+	fields[name] = fields[name].(int) + v
+}
+
+func init() {
+	inputs.Add("kafka", func() telegraf.Input {
+		return &Kafka{}
+	})
+}
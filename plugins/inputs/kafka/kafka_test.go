@@ -0,0 +1,309 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package kafka
+
+import (
+	"encoding/binary"
+	"io"
+	"net"
+	"strconv"
+	"testing"
+
+	"github.com/aws/amazon-cloudwatch-agent/metric/distribution"
+	"github.com/aws/amazon-cloudwatch-agent/metric/distribution/regular"
+	"github.com/influxdata/telegraf/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeBroker is a single broker cluster answering the requests of the plugin. The topic "orders" has an
+// under-replicated partition, the partition of "events" has no leader.
+type fakeBroker struct {
+	t        *testing.T
+	listener net.Listener
+	host     string
+	port     int32
+	// the api keys of the requests received
+	requests []int16
+}
+
+func newFakeBroker(t *testing.T) *fakeBroker {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	host, port, _ := net.SplitHostPort(l.Addr().String())
+	p, _ := strconv.Atoi(port)
+	b := &fakeBroker{t: t, listener: l, host: host, port: int32(p)}
+	go b.serve()
+	return b
+}
+
+func (b *fakeBroker) addr() string {
+	return b.listener.Addr().String()
+}
+
+func (b *fakeBroker) serve() {
+	for {
+		conn, err := b.listener.Accept()
+		if err != nil {
+			return
+		}
+		go b.handle(conn)
+	}
+}
+
+func (b *fakeBroker) handle(conn net.Conn) {
+	defer conn.Close()
+	for {
+		var size [4]byte
+		if _, err := io.ReadFull(conn, size[:]); err != nil {
+			return
+		}
+		req := make([]byte, binary.BigEndian.Uint32(size[:]))
+		if _, err := io.ReadFull(conn, req); err != nil {
+			return
+		}
+		d := &decoder{buf: req}
+		apiKey, _, correlationID := d.int16(), d.int16(), d.int32()
+		assert.Equal(b.t, clientID, d.string())
+		b.requests = append(b.requests, apiKey)
+
+		var e encoder
+		e.int32(0)
+		e.int32(correlationID)
+		switch apiKey {
+		case apiMetadata:
+			b.metadata(&e)
+		case apiListOffsets:
+			assert.Equal(b.t, int32(-1), d.int32())
+			assert.Equal(b.t, 1, d.arrayLen())
+			assert.Equal(b.t, "orders", d.string())
+			assert.Equal(b.t, 2, d.arrayLen())
+			for i := 0; i < 2; i++ {
+				d.int32()
+				assert.Equal(b.t, latestTimestamp, d.int64())
+			}
+			e.int32(1)
+			e.string("orders")
+			e.int32(2)
+			for _, p := range [][2]int64{{0, 100}, {1, 50}} {
+				e.int32(int32(p[0]))
+				e.int16(0)
+				e.int64(-1)
+				e.int64(p[1])
+			}
+		case apiListGroups:
+			e.int16(0)
+			e.int32(2)
+			e.string("billing")
+			e.string("consumer")
+			e.string("connect-sink")
+			e.string("connect")
+		case apiFindCoordinator:
+			assert.Equal(b.t, "billing", d.string())
+			e.int16(0)
+			e.int32(0)
+			e.string(b.host)
+			e.int32(b.port)
+		case apiOffsetFetch:
+			assert.Equal(b.t, "billing", d.string())
+			assert.Equal(b.t, int32(-1), d.int32())
+			e.int32(3)
+			e.string("orders")
+			e.int32(3)
+			for _, p := range [][2]int64{{0, 90}, {1, 60}, {2, -1}} {
+				e.int32(int32(p[0]))
+				e.int64(p[1])
+				e.string("")
+				e.int16(0)
+			}
+			// no end offset, the partition has no leader
+			e.string("events")
+			e.int32(1)
+			e.int32(0)
+			e.int64(5)
+			e.int16(-1)
+			e.int16(0)
+			e.string("__consumer_offsets")
+			e.int32(1)
+			e.int32(0)
+			e.int64(5)
+			e.int16(-1)
+			e.int16(0)
+			e.int16(0)
+		default:
+			b.t.Errorf("unexpected api key %d", apiKey)
+			return
+		}
+		binary.BigEndian.PutUint32(e.buf, uint32(len(e.buf)-4))
+		if _, err := conn.Write(e.buf); err != nil {
+			return
+		}
+	}
+}
+
+func (b *fakeBroker) metadata(e *encoder) {
+	e.int32(1)
+	e.int32(0)
+	e.string(b.host)
+	e.int32(b.port)
+	e.int16(-1) // null rack
+	e.int32(0)  // controller
+
+	type partition struct {
+		id, leader    int32
+		replicas, isr []int32
+	}
+	topics := []struct {
+		name       string
+		internal   bool
+		partitions []partition
+	}{
+		{"orders", false, []partition{{0, 0, []int32{0, 1}, []int32{0}}, {1, 0, []int32{0}, []int32{0}}}},
+		{"events", false, []partition{{0, -1, []int32{1}, []int32{}}}},
+		{"__consumer_offsets", true, []partition{{0, 0, []int32{0}, []int32{0}}}},
+	}
+	e.int32(int32(len(topics)))
+	for _, t := range topics {
+		e.int16(0)
+		e.string(t.name)
+		if t.internal {
+			e.buf = append(e.buf, 1)
+		} else {
+			e.buf = append(e.buf, 0)
+		}
+		e.int32(int32(len(t.partitions)))
+		for _, p := range t.partitions {
+			e.int16(0)
+			e.int32(p.id)
+			e.int32(p.leader)
+			for _, a := range [][]int32{p.replicas, p.isr} {
+				e.int32(int32(len(a)))
+				for _, v := range a {
+					e.int32(v)
+				}
+			}
+		}
+	}
+}
+
+func newKafka(t *testing.T, b *fakeBroker) *Kafka {
+	distribution.NewDistribution = regular.NewRegularDistribution
+	k := &Kafka{Brokers: []string{"127.0.0.1:1", b.addr()}, Log: testutil.Logger{}}
+	assert.NoError(t, k.Init())
+	return k
+}
+
+func TestGather(t *testing.T) {
+	b := newFakeBroker(t)
+	defer b.listener.Close()
+	k := newKafka(t, b)
+	k.PerPartitionLag = true
+
+	var acc testutil.Accumulator
+	assert.NoError(t, k.Gather(&acc))
+	assert.Empty(t, acc.Errors)
+	acc.AssertContainsTaggedFields(t, "kafka",
+		map[string]interface{}{
+			"cluster_brokers":                     1,
+			"cluster_topics":                      2,
+			"cluster_partitions":                  3,
+			"cluster_under_replicated_partitions": 2,
+			"cluster_offline_partitions":          1,
+		},
+		map[string]string{})
+	acc.AssertContainsTaggedFields(t, "kafka",
+		map[string]interface{}{
+			"topic_partitions":                  2,
+			"topic_under_replicated_partitions": 1,
+			"topic_offline_partitions":          0,
+			"topic_log_end_offset":              int64(150),
+		},
+		map[string]string{"topic": "orders"})
+	acc.AssertContainsTaggedFields(t, "kafka",
+		map[string]interface{}{
+			"topic_partitions":                  1,
+			"topic_under_replicated_partitions": 1,
+			"topic_offline_partitions":          1,
+			"topic_log_end_offset":              int64(0),
+		},
+		map[string]string{"topic": "events"})
+	acc.AssertContainsTaggedFields(t, "kafka", map[string]interface{}{"consumer_partition_lag": int64(10)},
+		map[string]string{"group": "billing", "topic": "orders", "partition": "0"})
+	// the end offset was read before the commit
+	acc.AssertContainsTaggedFields(t, "kafka", map[string]interface{}{"consumer_partition_lag": int64(0)},
+		map[string]string{"group": "billing", "topic": "orders", "partition": "1"})
+
+	var lags distribution.Distribution
+	for _, m := range acc.Metrics {
+		if d, ok := m.Fields["consumer_lag"]; ok {
+			assert.Nil(t, lags, "one consumer_lag is expected")
+			assert.Equal(t, map[string]string{"group": "billing", "topic": "orders"}, m.Tags)
+			lags = d.(distribution.Distribution)
+		}
+	}
+	assert.NotNil(t, lags)
+	assert.Equal(t, 2.0, lags.SampleCount())
+	assert.Equal(t, 10.0, lags.Maximum())
+	assert.Equal(t, 0.0, lags.Minimum())
+	assert.Equal(t, 10.0, lags.Sum())
+	// the cluster, 2 topics, 2 partitions and the lag of the group
+	assert.Len(t, acc.Metrics, 6)
+}
+
+func TestGatherFilters(t *testing.T) {
+	b := newFakeBroker(t)
+	defer b.listener.Close()
+	k := newKafka(t, b)
+	k.Topics = []string{"ev*"}
+	k.ConsumerGroups = []string{"payments"}
+	assert.NoError(t, k.Init())
+
+	var acc testutil.Accumulator
+	assert.NoError(t, k.Gather(&acc))
+	assert.Empty(t, acc.Errors)
+	assert.Len(t, acc.Metrics, 2)
+	assert.Equal(t, 1, acc.Metrics[0].Fields["cluster_topics"])
+	assert.Equal(t, "events", acc.Metrics[1].Tags["topic"])
+	// no offsets are listed without a leader and the group isn't reported
+	assert.Equal(t, []int16{apiMetadata, apiListGroups}, b.requests)
+}
+
+func TestGatherErrors(t *testing.T) {
+	k := &Kafka{Brokers: []string{"127.0.0.1:1"}}
+	assert.NoError(t, k.Init())
+	var acc testutil.Accumulator
+	assert.Error(t, k.Gather(&acc))
+	assert.Empty(t, acc.Metrics)
+}
+
+func TestInit(t *testing.T) {
+	k := &Kafka{}
+	assert.NoError(t, k.Init())
+	assert.Equal(t, []string{defaultBroker}, k.Brokers)
+	assert.Equal(t, defaultTimeout, k.Timeout.Duration)
+	assert.Nil(t, k.tlsConfig)
+
+	assert.Error(t, (&Kafka{Brokers: []string{"localhost"}}).Init())
+	k = &Kafka{TLS: true, InsecureSkipVerify: true}
+	assert.NoError(t, k.Init())
+	assert.True(t, k.tlsConfig.InsecureSkipVerify)
+}
+
+func TestDecoderShortResponse(t *testing.T) {
+	var e encoder
+	e.int32(1)
+	e.int32(0)
+	e.string("broker")
+	_, err := decodeMetadataResponse(&decoder{buf: e.buf})
+	assert.Equal(t, errShortResponse, err)
+
+	// an array longer than the response
+	_, err = decodeListGroupsResponse(&decoder{buf: []byte{0, 0, 0x7f, 0xff, 0xff, 0xff}})
+	assert.Equal(t, errShortResponse, err)
+
+	var groups encoder
+	groups.int16(16)
+	groups.int32(0)
+	_, err = decodeListGroupsResponse(&decoder{buf: groups.buf})
+	assert.Equal(t, kafkaError(16), err)
+}
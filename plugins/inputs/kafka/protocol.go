@@ -0,0 +1,317 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package kafka
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// The api keys and versions of the Kafka protocol the plugin uses, the versions are supported from Kafka 0.11 on.
+// https://kafka.apache.org/protocol
+const (
+	apiListOffsets     int16 = 2
+	apiMetadata        int16 = 3
+	apiOffsetFetch     int16 = 9
+	apiFindCoordinator int16 = 10
+	apiListGroups      int16 = 16
+
+	versionListOffsets     int16 = 1
+	versionMetadata        int16 = 1
+	versionOffsetFetch     int16 = 2
+	versionFindCoordinator int16 = 0
+	versionListGroups      int16 = 0
+
+	// the latest offset of a partition, i.e. its high watermark
+	latestTimestamp int64 = -1
+)
+
+var errShortResponse = errors.New("short response")
+
+// kafkaError is the error code of a response, https://kafka.apache.org/protocol#protocol_error_codes
+type kafkaError int16
+
+func (e kafkaError) Error() string {
+	return fmt.Sprintf("kafka error code %d", int16(e))
+}
+
+// encoder writes the primitive types of the protocol, all big endian.
+type encoder struct {
+	buf []byte
+}
+
+func (e *encoder) int16(v int16) {
+	e.buf = append(e.buf, byte(v>>8), byte(v))
+}
+
+func (e *encoder) int32(v int32) {
+	e.buf = append(e.buf, byte(v>>24), byte(v>>16), byte(v>>8), byte(v))
+}
+
+func (e *encoder) int64(v int64) {
+	e.int32(int32(v >> 32))
+	e.int32(int32(v))
+}
+
+func (e *encoder) string(s string) {
+	e.int16(int16(len(s)))
+	e.buf = append(e.buf, s...)
+}
+
+// decoder reads the primitive types of the protocol. The first error is kept and the later reads return zero
+// values, the error is checked once the whole response is read.
+type decoder struct {
+	buf []byte
+	err error
+}
+
+func (d *decoder) next(n int) []byte {
+	if d.err != nil {
+		return nil
+	}
+	if n < 0 || len(d.buf) < n {
+		d.err = errShortResponse
+		return nil
+	}
+	b := d.buf[:n]
+	d.buf = d.buf[n:]
+	return b
+}
+
+func (d *decoder) bool() bool {
+	b := d.next(1)
+	return b != nil && b[0] != 0
+}
+
+func (d *decoder) int16() int16 {
+	if b := d.next(2); b != nil {
+		return int16(binary.BigEndian.Uint16(b))
+	}
+	return 0
+}
+
+func (d *decoder) int32() int32 {
+	if b := d.next(4); b != nil {
+		return int32(binary.BigEndian.Uint32(b))
+	}
+	return 0
+}
+
+func (d *decoder) int64() int64 {
+	if b := d.next(8); b != nil {
+		return int64(binary.BigEndian.Uint64(b))
+	}
+	return 0
+}
+
+// string reads a nullable string too, null is read as "".
+func (d *decoder) string() string {
+	n := d.int16()
+	if n < 0 {
+		return ""
+	}
+	return string(d.next(int(n)))
+}
+
+// arrayLen returns the number of elements of an array, a null array has none.
+func (d *decoder) arrayLen() int {
+	n := int(d.int32())
+	if n < 0 {
+		return 0
+	}
+	// every element has at least one byte, this bounds the allocations of a corrupted response
+	if n > len(d.buf) {
+		d.err = errShortResponse
+		return 0
+	}
+	return n
+}
+
+type brokerMetadata struct {
+	nodeID int32
+	host   string
+	port   int32
+}
+
+type partitionMetadata struct {
+	err      kafkaError
+	id       int32
+	leader   int32
+	replicas []int32
+	isr      []int32
+}
+
+type topicMetadata struct {
+	err        kafkaError
+	name       string
+	internal   bool
+	partitions []partitionMetadata
+}
+
+type metadataResponse struct {
+	brokers []brokerMetadata
+	topics  []topicMetadata
+}
+
+// encodeMetadataRequest requests the metadata of all the topics.
+func encodeMetadataRequest() []byte {
+	var e encoder
+	// a null array is all the topics in v1
+	e.int32(-1)
+	return e.buf
+}
+
+func decodeMetadataResponse(d *decoder) (*metadataResponse, error) {
+	r := &metadataResponse{}
+	r.brokers = make([]brokerMetadata, d.arrayLen())
+	for i := range r.brokers {
+		b := &r.brokers[i]
+		b.nodeID = d.int32()
+		b.host = d.string()
+		b.port = d.int32()
+		d.string() // rack
+	}
+	d.int32() // controller id
+	r.topics = make([]topicMetadata, d.arrayLen())
+	for i := range r.topics {
+		t := &r.topics[i]
+		t.err = kafkaError(d.int16())
+		t.name = d.string()
+		t.internal = d.bool()
+		t.partitions = make([]partitionMetadata, d.arrayLen())
+		for j := range t.partitions {
+			p := &t.partitions[j]
+			p.err = kafkaError(d.int16())
+			p.id = d.int32()
+			p.leader = d.int32()
+			p.replicas = decodeInt32Array(d)
+			p.isr = decodeInt32Array(d)
+		}
+	}
+	return r, d.err
+}
+
+func decodeInt32Array(d *decoder) []int32 {
+	a := make([]int32, d.arrayLen())
+	for i := range a {
+		a[i] = d.int32()
+	}
+	return a
+}
+
+// encodeListOffsetsRequest requests the latest offsets of the partitions of the topics.
+func encodeListOffsetsRequest(partitions map[string][]int32) []byte {
+	var e encoder
+	e.int32(-1) // replica id of a consumer
+	e.int32(int32(len(partitions)))
+	for topic, ids := range partitions {
+		e.string(topic)
+		e.int32(int32(len(ids)))
+		for _, id := range ids {
+			e.int32(id)
+			e.int64(latestTimestamp)
+		}
+	}
+	return e.buf
+}
+
+// decodeListOffsetsResponse returns the offsets by topic and partition, the partitions in error are left out.
+func decodeListOffsetsResponse(d *decoder) (map[string]map[int32]int64, error) {
+	offsets := make(map[string]map[int32]int64)
+	for i, topics := 0, d.arrayLen(); i < topics; i++ {
+		topic := d.string()
+		for j, partitions := 0, d.arrayLen(); j < partitions; j++ {
+			id := d.int32()
+			code := d.int16()
+			d.int64() // timestamp
+			offset := d.int64()
+			if code != 0 {
+				continue
+			}
+			if offsets[topic] == nil {
+				offsets[topic] = make(map[int32]int64)
+			}
+			offsets[topic][id] = offset
+		}
+	}
+	return offsets, d.err
+}
+
+func encodeFindCoordinatorRequest(group string) []byte {
+	var e encoder
+	e.string(group)
+	return e.buf
+}
+
+func decodeFindCoordinatorResponse(d *decoder) (brokerMetadata, error) {
+	code := d.int16()
+	b := brokerMetadata{nodeID: d.int32(), host: d.string(), port: d.int32()}
+	if d.err != nil {
+		return b, d.err
+	}
+	if code != 0 {
+		return b, kafkaError(code)
+	}
+	return b, nil
+}
+
+// encodeOffsetFetchRequest requests the committed offsets of all the topics of the group.
+func encodeOffsetFetchRequest(group string) []byte {
+	var e encoder
+	e.string(group)
+	// a null array is all the topics from v2
+	e.int32(-1)
+	return e.buf
+}
+
+// decodeOffsetFetchResponse returns the committed offsets by topic and partition, the partitions without a
+// committed offset are left out.
+func decodeOffsetFetchResponse(d *decoder) (map[string]map[int32]int64, error) {
+	offsets := make(map[string]map[int32]int64)
+	for i, topics := 0, d.arrayLen(); i < topics; i++ {
+		topic := d.string()
+		for j, partitions := 0, d.arrayLen(); j < partitions; j++ {
+			id := d.int32()
+			offset := d.int64()
+			d.string() // metadata
+			code := d.int16()
+			if code != 0 || offset < 0 {
+				continue
+			}
+			if offsets[topic] == nil {
+				offsets[topic] = make(map[int32]int64)
+			}
+			offsets[topic][id] = offset
+		}
+	}
+	code := d.int16()
+	if d.err != nil {
+		return nil, d.err
+	}
+	if code != 0 {
+		return nil, kafkaError(code)
+	}
+	return offsets, nil
+}
+
+type group struct {
+	id           string
+	protocolType string
+}
+
+func decodeListGroupsResponse(d *decoder) ([]group, error) {
+	code := d.int16()
+	groups := make([]group, d.arrayLen())
+	for i := range groups {
+		groups[i] = group{id: d.string(), protocolType: d.string()}
+	}
+	if d.err != nil {
+		return nil, d.err
+	}
+	if code != 0 {
+		return nil, kafkaError(code)
+	}
+	return groups, nil
+}
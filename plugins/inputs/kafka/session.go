@@ -0,0 +1,113 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package kafka
+
+import (
+	"crypto/tls"
+	"net"
+	"strconv"
+	"time"
+)
+
+// session keeps the connections to the brokers of a gather, a connection is closed after a failed request since
+// the responses of its pending requests can't be told apart anymore.
+type session struct {
+	timeout   time.Duration
+	tlsConfig *tls.Config
+	conns     map[string]*broker
+	// the address of the broker the metadata was read from
+	seed string
+}
+
+func (s *session) broker(addr string) (*broker, error) {
+	if b, ok := s.conns[addr]; ok {
+		return b, nil
+	}
+	b, err := dialBroker(addr, s.timeout, s.tlsConfig)
+	if err != nil {
+		return nil, err
+	}
+	s.conns[addr] = b
+	return b, nil
+}
+
+func (s *session) fail(b *broker) {
+	b.close()
+	delete(s.conns, b.addr)
+}
+
+// bootstrap returns the metadata of the cluster from the first of the brokers that answers.
+func (s *session) bootstrap(addrs []string) (*metadataResponse, error) {
+	var lastErr error
+	for _, addr := range addrs {
+		b, err := s.broker(addr)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		md, err := b.metadata()
+		if err != nil {
+			s.fail(b)
+			lastErr = err
+			continue
+		}
+		s.seed = addr
+		return md, nil
+	}
+	return nil, lastErr
+}
+
+func (s *session) listOffsets(addr string, partitions map[string][]int32) (map[string]map[int32]int64, error) {
+	b, err := s.broker(addr)
+	if err != nil {
+		return nil, err
+	}
+	offsets, err := b.listOffsets(partitions)
+	if err != nil {
+		s.fail(b)
+	}
+	return offsets, err
+}
+
+func (s *session) listGroups(addr string) ([]group, error) {
+	b, err := s.broker(addr)
+	if err != nil {
+		return nil, err
+	}
+	groups, err := b.listGroups()
+	if _, ok := err.(kafkaError); err != nil && !ok {
+		s.fail(b)
+	}
+	return groups, err
+}
+
+// committedOffsets returns the committed offsets of a group from its coordinator.
+func (s *session) committedOffsets(group string) (map[string]map[int32]int64, error) {
+	seed, err := s.broker(s.seed)
+	if err != nil {
+		return nil, err
+	}
+	coordinator, err := seed.findCoordinator(group)
+	if err != nil {
+		if _, ok := err.(kafkaError); !ok {
+			s.fail(seed)
+		}
+		return nil, err
+	}
+	b, err := s.broker(net.JoinHostPort(coordinator.host, strconv.Itoa(int(coordinator.port))))
+	if err != nil {
+		return nil, err
+	}
+	offsets, err := b.offsetFetch(group)
+	if _, ok := err.(kafkaError); err != nil && !ok {
+		s.fail(b)
+	}
+	return offsets, err
+}
+
+func (s *session) close() {
+	for _, b := range s.conns {
+		b.close()
+	}
+}
@@ -0,0 +1,457 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package kernel
+
+import (
+	"bufio"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/plugins/inputs"
+)
+
+const (
+	measurement     = "kernel"
+	defaultProcRoot = "/proc"
+)
+
+// the counters of the TcpExt line of /proc/net/netstat reported, by their name in the kernel
+var tcpExtCounters = map[string]string{
+	"PruneCalled":        "tcp_prune_called",
+	"TCPMemoryPressures": "tcp_memory_pressures",
+	"TCPAbortOnMemory":   "tcp_abort_on_memory",
+}
+
+// the counters of /proc/net/stat/nf_conntrack reported, summed over the cpus
+var conntrackCounters = map[string]string{
+	"drop":          "conntrack_drop",
+	"early_drop":    "conntrack_early_drop",
+	"insert_failed": "conntrack_insert_failed",
+}
+
+var sampleConfig = `
+  ## The conntrack metrics are only reported when the nf_conntrack module is loaded.
+
+  ## Report the interrupts per second of each IRQ of /proc/interrupts, tagged with the irq and its device.
+  # per_irq = false
+`
+
+// Kernel reports the usage of the kernel network tables against their limits, the connection tracking table, the
+// ARP table and the socket memory, which drop the packets silently when they are full. It also reports the context
+// switches and the interrupts per second, and the entropy available to the random devices, the noisy neighbors
+// and the crypto stalls.
+type Kernel struct {
+	PerIRQ bool            `toml:"per_irq"`
+	Log    telegraf.Logger `toml:"-"`
+
+	// the procfs mount and the clock, replaced in tests
+	procRoot string
+	now      func() time.Time
+	// the activity counters of the last gather, the rates since are reported
+	last *activity
+}
+
+// activity are the cumulative counters of the context switches and the interrupts of the kernel.
+type activity struct {
+	time            time.Time
+	contextSwitches uint64
+	interrupts      uint64
+	irqs            map[string]irqCount
+}
+
+// irqCount are the interrupts of an IRQ summed over the cpus.
+type irqCount struct {
+	device string
+	count  uint64
+}
+
+func (k *Kernel) SampleConfig() string {
+	return sampleConfig
+}
+
+func (k *Kernel) Description() string {
+	return "Report the usage of the conntrack and ARP tables and of the socket memory of the kernel, its TcpExt memory counters, its context switches and interrupts, per IRQ optionally, and its available entropy."
+}
+
+func (k *Kernel) Init() error {
+	if k.procRoot == "" {
+		k.procRoot = defaultProcRoot
+	}
+	if k.now == nil {
+		k.now = time.Now
+	}
+	return nil
+}
+
+func (k *Kernel) Gather(acc telegraf.Accumulator) error {
+	fields := map[string]interface{}{}
+	var errs []string
+	for _, collect := range []func(map[string]interface{}) error{k.conntrack, k.arp, k.socketMemory, k.tcpExt, k.entropy} {
+		if err := collect(fields); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+	if err := k.activity(acc, fields); err != nil {
+		errs = append(errs, err.Error())
+	}
+	if len(fields) > 0 {
+		acc.AddFields(measurement, fields, map[string]string{})
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("kernel: %s", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// conntrack reads the connection tracking table, without the nf_conntrack module the files don't exist and nothing
+// is reported.
+func (k *Kernel) conntrack(fields map[string]interface{}) error {
+	count, err := k.readUint("sys", "net", "netfilter", "nf_conntrack_count")
+	if os.IsNotExist(err) {
+		k.Log.Debugf("No conntrack table: %v", err)
+		return nil
+	} else if err != nil {
+		return err
+	}
+	max, err := k.readUint("sys", "net", "netfilter", "nf_conntrack_max")
+	if err != nil {
+		return err
+	}
+	fields["conntrack_count"] = count
+	fields["conntrack_max"] = max
+	if max > 0 {
+		fields["conntrack_utilization"] = 100 * float64(count) / float64(max)
+	}
+
+	// older kernels don't have the statistics
+	stats, err := readConntrackStats(filepath.Join(k.procRoot, "net", "stat", "nf_conntrack"))
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	for name, field := range conntrackCounters {
+		if v, ok := stats[name]; ok {
+			fields[field] = v
+		}
+	}
+	return nil
+}
+
+// arp reads the IPv4 neighbour table, the kernel drops the new entries above gc_thresh3 ("neighbour table
+// overflow").
+func (k *Kernel) arp(fields map[string]interface{}) error {
+	b, err := ioutil.ReadFile(filepath.Join(k.procRoot, "net", "arp"))
+	if err != nil {
+		return err
+	}
+	entries := uint64(0)
+	// the first line is the header
+	for _, line := range strings.Split(strings.TrimSpace(string(b)), "\n")[1:] {
+		if strings.TrimSpace(line) != "" {
+			entries++
+		}
+	}
+	fields["arp_entries"] = entries
+	max, err := k.readUint("sys", "net", "ipv4", "neigh", "default", "gc_thresh3")
+	if err != nil {
+		return err
+	}
+	fields["arp_max"] = max
+	if max > 0 {
+		fields["arp_utilization"] = 100 * float64(entries) / float64(max)
+	}
+	return nil
+}
+
+// socketMemory reads the pages used by the tcp and udp sockets from sockstat, and their limits, tcp_mem and udp_mem
+// are the "low pressure max" thresholds in pages.
+func (k *Kernel) socketMemory(fields map[string]interface{}) error {
+	used, err := readSockstat(filepath.Join(k.procRoot, "net", "sockstat"))
+	if err != nil {
+		return err
+	}
+	for _, protocol := range []string{"tcp", "udp"} {
+		pages, ok := used[strings.ToUpper(protocol)]
+		if !ok {
+			continue
+		}
+		fields[protocol+"_mem_pages"] = pages
+		limits, err := k.readUints("sys", "net", "ipv4", protocol+"_mem")
+		if err != nil {
+			return err
+		}
+		if len(limits) != 3 {
+			return fmt.Errorf("unexpected content of %s_mem: %v", protocol, limits)
+		}
+		fields[protocol+"_mem_pressure_pages"] = limits[1]
+		fields[protocol+"_mem_max_pages"] = limits[2]
+		if limits[2] > 0 {
+			fields[protocol+"_mem_utilization"] = 100 * float64(pages) / float64(limits[2])
+		}
+	}
+	return nil
+}
+
+// tcpExt reads the counters of the tcp sockets under memory pressure, the TcpExt lines of netstat are the names then
+// the values.
+func (k *Kernel) tcpExt(fields map[string]interface{}) error {
+	path := filepath.Join(k.procRoot, "net", "netstat")
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	var names []string
+	for _, line := range strings.Split(string(b), "\n") {
+		if !strings.HasPrefix(line, "TcpExt:") {
+			continue
+		}
+		values := strings.Fields(strings.TrimPrefix(line, "TcpExt:"))
+		if names == nil {
+			names = values
+			continue
+		}
+		if len(values) != len(names) {
+			return fmt.Errorf("unexpected content of %s: %d names and %d values", path, len(names), len(values))
+		}
+		for i, name := range names {
+			field, ok := tcpExtCounters[name]
+			if !ok {
+				continue
+			}
+			v, err := strconv.ParseUint(values[i], 10, 64)
+			if err != nil {
+				return fmt.Errorf("unexpected content of %s: %v", path, err)
+			}
+			fields[field] = v
+		}
+		return nil
+	}
+	return fmt.Errorf("no TcpExt counters in %s", path)
+}
+
+// entropy reads the entropy available to /dev/random, the reads of /dev/random block when it's low on the kernels
+// before 5.6.
+func (k *Kernel) entropy(fields map[string]interface{}) error {
+	avail, err := k.readUint("sys", "kernel", "random", "entropy_avail")
+	if err != nil {
+		return err
+	}
+	fields["entropy_avail"] = avail
+	return nil
+}
+
+// activity reports the context switches and the interrupts per second since the last gather, and the interrupts of
+// each IRQ with per_irq, nothing is reported by the first gather.
+func (k *Kernel) activity(acc telegraf.Accumulator, fields map[string]interface{}) error {
+	current, err := k.readActivity()
+	if err != nil {
+		return err
+	}
+	last := k.last
+	k.last = current
+	if last == nil {
+		return nil
+	}
+	seconds := current.time.Sub(last.time).Seconds()
+	if seconds <= 0 {
+		return nil
+	}
+	// the counters are reset on reboot, which a restored state could miss
+	if current.contextSwitches >= last.contextSwitches {
+		fields["context_switches_per_sec"] = float64(current.contextSwitches-last.contextSwitches) / seconds
+	}
+	if current.interrupts >= last.interrupts {
+		fields["interrupts_per_sec"] = float64(current.interrupts-last.interrupts) / seconds
+	}
+	for irq, c := range current.irqs {
+		l, ok := last.irqs[irq]
+		if !ok || c.count < l.count {
+			continue
+		}
+		acc.AddFields(measurement, map[string]interface{}{
+			"interrupts_per_sec": float64(c.count-l.count) / seconds,
+		}, map[string]string{"irq": irq, "device": c.device})
+	}
+	return nil
+}
+
+// readActivity reads the ctxt and intr lines of /proc/stat, and /proc/interrupts with per_irq.
+func (k *Kernel) readActivity() (*activity, error) {
+	path := filepath.Join(k.procRoot, "stat")
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	a := &activity{time: k.now()}
+	found := 0
+	for _, line := range strings.Split(string(b), "\n") {
+		values := strings.Fields(line)
+		if len(values) < 2 || (values[0] != "ctxt" && values[0] != "intr") {
+			continue
+		}
+		// the first value of intr is the total, the interrupts of each IRQ follow
+		v, err := strconv.ParseUint(values[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("unexpected content of %s: %v", path, err)
+		}
+		if values[0] == "ctxt" {
+			a.contextSwitches = v
+		} else {
+			a.interrupts = v
+		}
+		found++
+	}
+	if found != 2 {
+		return nil, fmt.Errorf("no ctxt and intr lines in %s", path)
+	}
+	if k.PerIRQ {
+		if a.irqs, err = readInterrupts(filepath.Join(k.procRoot, "interrupts")); err != nil {
+			return nil, err
+		}
+	}
+	return a, nil
+}
+
+func (k *Kernel) readUint(path ...string) (uint64, error) {
+	values, err := k.readUints(path...)
+	if err != nil {
+		return 0, err
+	}
+	if len(values) != 1 {
+		return 0, fmt.Errorf("unexpected content of %s: %v", filepath.Join(path...), values)
+	}
+	return values[0], nil
+}
+
+// readUints reads the numbers of a file of the procfs separated by spaces.
+func (k *Kernel) readUints(path ...string) ([]uint64, error) {
+	file := filepath.Join(append([]string{k.procRoot}, path...)...)
+	b, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	var values []uint64
+	for _, s := range strings.Fields(string(b)) {
+		v, err := strconv.ParseUint(s, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("unexpected content of %s: %v", file, err)
+		}
+		values = append(values, v)
+	}
+	return values, nil
+}
+
+// readSockstat returns the memory pages of the protocols of sockstat, the lines are like
+// "TCP: inuse 27 orphan 0 tw 9 alloc 32 mem 3".
+func readSockstat(path string) (map[string]uint64, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	pages := map[string]uint64{}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		values := strings.Fields(scanner.Text())
+		if len(values) == 0 {
+			continue
+		}
+		protocol := strings.TrimSuffix(values[0], ":")
+		for i := 1; i+1 < len(values); i += 2 {
+			if values[i] != "mem" {
+				continue
+			}
+			v, err := strconv.ParseUint(values[i+1], 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("unexpected content of %s: %v", path, err)
+			}
+			pages[protocol] = v
+		}
+	}
+	return pages, scanner.Err()
+}
+
+// readConntrackStats sums the conntrack statistics of the cpus, the first line names the columns and each line of
+// a cpu has the values in hexadecimal.
+func readConntrackStats(path string) (map[string]uint64, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	lines := strings.Split(strings.TrimSpace(string(b)), "\n")
+	names := strings.Fields(lines[0])
+	stats := map[string]uint64{}
+	for _, line := range lines[1:] {
+		values := strings.Fields(line)
+		if len(values) != len(names) {
+			return nil, fmt.Errorf("unexpected content of %s: %d names and %d values", path, len(names), len(values))
+		}
+		for i, name := range names {
+			v, err := strconv.ParseUint(values[i], 16, 64)
+			if err != nil {
+				return nil, fmt.Errorf("unexpected content of %s: %v", path, err)
+			}
+			stats[name] += v
+		}
+	}
+	return stats, nil
+}
+
+// readInterrupts sums the interrupts of the cpus for each IRQ of /proc/interrupts. The first line names the cpus,
+// each line of an IRQ is like "  24:   1234   5678   PCI-MSI 65536-edge   nvme0q0", the device is the last word of
+// the numbered IRQs and the description of the others, like "LOC: ... Local timer interrupts". ERR and MIS are
+// error counters, not interrupts.
+func readInterrupts(path string) (map[string]irqCount, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	lines := strings.Split(strings.TrimRight(string(b), "\n"), "\n")
+	cpus := len(strings.Fields(lines[0]))
+	irqs := map[string]irqCount{}
+	for _, line := range lines[1:] {
+		values := strings.Fields(line)
+		if len(values) == 0 || !strings.HasSuffix(values[0], ":") {
+			continue
+		}
+		irq := strings.TrimSuffix(values[0], ":")
+		if irq == "ERR" || irq == "MIS" {
+			continue
+		}
+		var c irqCount
+		i := 1
+		for ; i < len(values) && i <= cpus; i++ {
+			v, err := strconv.ParseUint(values[i], 10, 64)
+			if err != nil {
+				// the cpus without column, the description starts
+				break
+			}
+			c.count += v
+		}
+		description := values[i:]
+		if len(description) == 0 {
+			continue
+		}
+		if _, err := strconv.Atoi(irq); err == nil {
+			c.device = description[len(description)-1]
+		} else {
+			c.device = strings.Join(description, " ")
+		}
+		irqs[irq] = c
+	}
+	return irqs, nil
+}
+
+func init() {
+	inputs.Add("kernel", func() telegraf.Input {
+		return &Kernel{}
+	})
+}
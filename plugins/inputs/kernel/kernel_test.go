@@ -0,0 +1,228 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package kernel
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/influxdata/telegraf/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+const arp = `IP address       HW type     Flags       HW address            Mask     Device
+10.0.0.1         0x1         0x2         0a:1b:2c:3d:4e:5f     *        eth0
+10.0.0.2         0x1         0x2         0a:1b:2c:3d:4e:60     *        eth0
+10.0.0.3         0x1         0x0         00:00:00:00:00:00     *        eth0
+`
+
+const sockstat = `sockets: used 180
+TCP: inuse 27 orphan 0 tw 9 alloc 32 mem 3
+UDP: inuse 8 mem 4
+UDPLITE: inuse 0
+RAW: inuse 0
+FRAG: inuse 0 memory 0
+`
+
+const netstat = `TcpExt: SyncookiesSent PruneCalled TCPMemoryPressures TCPAbortOnMemory
+TcpExt: 0 12 3 1
+IpExt: InNoRoutes InTruncatedPkts
+IpExt: 0 0
+`
+
+const conntrackStats = `entries  searched found new invalid ignore delete delete_list insert insert_failed drop early_drop icmp_error  expect_new expect_create expect_delete search_restart
+000000c8  00000000 00000000 00000000 00000010 00000000 00000000 00000000 00000000 00000002 0000000a 00000001 00000000  00000000 00000000 00000000 00000000
+000000c8  00000000 00000000 00000000 00000004 00000000 00000000 00000000 00000000 00000001 00000005 00000000 00000000  00000000 00000000 00000000 00000000
+`
+
+const stat = `cpu  2255 34 2290 22625563 6290 127 456 0 0 0
+cpu0 1132 34 1441 11311718 3675 127 438 0 0 0
+intr 1000 36 9 0 0
+ctxt 5000
+btime 1620828427
+processes 2000
+`
+
+const interrupts = `           CPU0       CPU1
+  0:         36          0   IO-APIC   2-edge      timer
+ 24:        100        200   PCI-MSI 65536-edge      nvme0q0
+NMI:          0          0   Non-maskable interrupts
+LOC:       5000       6000   Local timer interrupts
+ERR:          0
+MIS:          0
+`
+
+func writeFiles(t *testing.T, root string, files map[string]string) {
+	for name, content := range files {
+		path := filepath.Join(root, name)
+		assert.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		assert.NoError(t, ioutil.WriteFile(path, []byte(content), 0644))
+	}
+}
+
+func writeProc(t *testing.T, root string) {
+	writeFiles(t, root, map[string]string{
+		"net/arp":                               arp,
+		"net/sockstat":                          sockstat,
+		"net/netstat":                           netstat,
+		"sys/net/ipv4/neigh/default/gc_thresh3": "1024\n",
+		"sys/net/ipv4/tcp_mem":                  "100\t200\t300\n",
+		"sys/net/ipv4/udp_mem":                  "200\t300\t400\n",
+		"sys/kernel/random/entropy_avail":       "256\n",
+		"stat":                                  stat,
+	})
+}
+
+func newKernel(t *testing.T, root string) *Kernel {
+	k := &Kernel{Log: testutil.Logger{}, procRoot: root}
+	assert.NoError(t, k.Init())
+	return k
+}
+
+func TestGather(t *testing.T) {
+	root, err := ioutil.TempDir("", "kernel")
+	assert.NoError(t, err)
+	defer os.RemoveAll(root)
+	writeProc(t, root)
+	writeFiles(t, root, map[string]string{
+		"sys/net/netfilter/nf_conntrack_count": "200\n",
+		"sys/net/netfilter/nf_conntrack_max":   "800\n",
+		"net/stat/nf_conntrack":                conntrackStats,
+	})
+
+	var acc testutil.Accumulator
+	assert.NoError(t, acc.GatherError(newKernel(t, root).Gather))
+	acc.AssertContainsTaggedFields(t, "kernel", map[string]interface{}{
+		"conntrack_count":         uint64(200),
+		"conntrack_max":           uint64(800),
+		"conntrack_utilization":   float64(25),
+		"conntrack_drop":          uint64(15),
+		"conntrack_early_drop":    uint64(1),
+		"conntrack_insert_failed": uint64(3),
+		"arp_entries":             uint64(3),
+		"arp_max":                 uint64(1024),
+		"arp_utilization":         float64(100) * 3 / 1024,
+		"tcp_mem_pages":           uint64(3),
+		"tcp_mem_pressure_pages":  uint64(200),
+		"tcp_mem_max_pages":       uint64(300),
+		"tcp_mem_utilization":     float64(1),
+		"udp_mem_pages":           uint64(4),
+		"udp_mem_pressure_pages":  uint64(300),
+		"udp_mem_max_pages":       uint64(400),
+		"udp_mem_utilization":     float64(1),
+		"tcp_prune_called":        uint64(12),
+		"tcp_memory_pressures":    uint64(3),
+		"tcp_abort_on_memory":     uint64(1),
+		"entropy_avail":           uint64(256),
+	}, map[string]string{})
+}
+
+func TestGatherWithoutConntrack(t *testing.T) {
+	root, err := ioutil.TempDir("", "kernel")
+	assert.NoError(t, err)
+	defer os.RemoveAll(root)
+	writeProc(t, root)
+
+	var acc testutil.Accumulator
+	assert.NoError(t, acc.GatherError(newKernel(t, root).Gather))
+	assert.Equal(t, 1, len(acc.Metrics))
+	assert.False(t, acc.HasField("kernel", "conntrack_count"))
+	assert.True(t, acc.HasField("kernel", "arp_entries"))
+}
+
+func TestGatherErrors(t *testing.T) {
+	root, err := ioutil.TempDir("", "kernel")
+	assert.NoError(t, err)
+	defer os.RemoveAll(root)
+	var acc testutil.Accumulator
+	assert.Error(t, acc.GatherError(newKernel(t, root).Gather))
+	assert.Equal(t, 0, len(acc.Metrics))
+
+	// the readable tables are still reported
+	writeProc(t, root)
+	writeFiles(t, root, map[string]string{
+		"net/netstat":          "TcpExt: PruneCalled TCPMemoryPressures\nTcpExt: 12\n",
+		"sys/net/ipv4/udp_mem": "200\t300\n",
+	})
+	acc.ClearMetrics()
+	assert.Error(t, acc.GatherError(newKernel(t, root).Gather))
+	assert.True(t, acc.HasField("kernel", "arp_entries"))
+	assert.False(t, acc.HasField("kernel", "tcp_prune_called"))
+}
+
+func TestReadConntrackStats(t *testing.T) {
+	root, err := ioutil.TempDir("", "kernel")
+	assert.NoError(t, err)
+	defer os.RemoveAll(root)
+	writeFiles(t, root, map[string]string{
+		"stats":   conntrackStats,
+		"invalid": "entries drop\n000000c8\n",
+	})
+
+	stats, err := readConntrackStats(filepath.Join(root, "stats"))
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(400), stats["entries"])
+	assert.Equal(t, uint64(20), stats["invalid"])
+	_, err = readConntrackStats(filepath.Join(root, "invalid"))
+	assert.Error(t, err)
+}
+
+func TestGatherActivity(t *testing.T) {
+	root, err := ioutil.TempDir("", "kernel")
+	assert.NoError(t, err)
+	defer os.RemoveAll(root)
+	writeProc(t, root)
+	writeFiles(t, root, map[string]string{"interrupts": interrupts})
+
+	now := time.Unix(1620828427, 0)
+	k := newKernel(t, root)
+	k.PerIRQ = true
+	k.now = func() time.Time { return now }
+	var acc testutil.Accumulator
+	assert.NoError(t, acc.GatherError(k.Gather))
+	// the first gather has no rates
+	assert.False(t, acc.HasField("kernel", "context_switches_per_sec"))
+	assert.Equal(t, 1, len(acc.Metrics))
+
+	now = now.Add(10 * time.Second)
+	writeFiles(t, root, map[string]string{
+		"stat":       strings.Replace(strings.Replace(stat, "ctxt 5000", "ctxt 7000", 1), "intr 1000", "intr 1500", 1),
+		"interrupts": strings.Replace(strings.Replace(interrupts, "100        200", "150        250", 1), "5000       6000", "5100       6100", 1),
+	})
+	acc.ClearMetrics()
+	assert.NoError(t, acc.GatherError(k.Gather))
+	for _, m := range acc.Metrics {
+		if len(m.Tags) == 0 {
+			assert.Equal(t, float64(200), m.Fields["context_switches_per_sec"])
+			assert.Equal(t, float64(50), m.Fields["interrupts_per_sec"])
+		}
+	}
+	acc.AssertContainsTaggedFields(t, "kernel", map[string]interface{}{"interrupts_per_sec": float64(10)},
+		map[string]string{"irq": "24", "device": "nvme0q0"})
+	acc.AssertContainsTaggedFields(t, "kernel", map[string]interface{}{"interrupts_per_sec": float64(20)},
+		map[string]string{"irq": "LOC", "device": "Local timer interrupts"})
+	acc.AssertContainsTaggedFields(t, "kernel", map[string]interface{}{"interrupts_per_sec": float64(0)},
+		map[string]string{"irq": "0", "device": "timer"})
+	assert.Equal(t, 5, len(acc.Metrics))
+}
+
+func TestReadInterrupts(t *testing.T) {
+	root, err := ioutil.TempDir("", "kernel")
+	assert.NoError(t, err)
+	defer os.RemoveAll(root)
+	writeFiles(t, root, map[string]string{"interrupts": interrupts})
+
+	irqs, err := readInterrupts(filepath.Join(root, "interrupts"))
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]irqCount{
+		"0":   {device: "timer", count: 36},
+		"24":  {device: "nvme0q0", count: 300},
+		"NMI": {device: "Non-maskable interrupts", count: 0},
+		"LOC": {device: "Local timer interrupts", count: 11000},
+	}, irqs)
+}
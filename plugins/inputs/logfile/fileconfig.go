@@ -33,6 +33,9 @@ type FileConfig struct {
 	Blacklist string `toml:"blacklist"`
 
 	PublishMultiLogs bool `toml:"publish_multi_logs"`
+	//The number of log streams the files are published to when publish_multi_logs is enabled with a log group name,
+	//instead of one log stream per file. The files are spread over the streams by the hash of their path.
+	LogStreamBuckets int `toml:"log_stream_buckets"`
 
 	Encoding string `toml:"encoding"`
 	//The log group name for the input log file.
@@ -79,8 +82,16 @@ type FileConfig struct {
 	//Indicate retention in days for log group
 	RetentionInDays int `toml:"retention_in_days"`
 
+	//The weight of the file among the files published to the same log stream when the stream is slower than them,
+	//a file of priority 3 publishes 3 events for each event of a file of priority 1.
+	Priority int `toml:"priority"`
+
 	Filters []*LogFilter `toml:"filters"`
 
+	//Indicate the format of the log lines, "w3c" converts the lines of the W3C extended log files to json objects
+	//keyed by the #Fields directive, and each line is a log entry.
+	LogFormat string `toml:"log_format"`
+
 	//Time *time.Location Go type timezone info.
 	TimezoneLoc *time.Location
 	//Regexp go type timestampFromLogLine regex
@@ -110,6 +121,9 @@ func (config *FileConfig) init() error {
 	if config.LogGroupName == "" && !config.PublishMultiLogs {
 		config.LogGroupName = logGroupName(config.FilePath)
 	}
+	if config.LogStreamBuckets < 0 {
+		return fmt.Errorf("log_stream_buckets %v is invalid, it must be positive", config.LogStreamBuckets)
+	}
 	//If the timezone info is not specified, we will use the Local timezone as default value.
 	if config.Timezone == time.UTC.String() {
 		config.TimezoneLoc = time.UTC
@@ -150,6 +164,13 @@ func (config *FileConfig) init() error {
 	if config.RetentionInDays == 0 {
 		config.RetentionInDays = -1
 	}
+	if config.Priority < 0 {
+		return fmt.Errorf("priority %v is invalid, it must be positive", config.Priority)
+	}
+
+	if config.LogFormat != "" && config.LogFormat != logFormatW3C {
+		return fmt.Errorf("log_format %s is invalid, the supported format is %s", config.LogFormat, logFormatW3C)
+	}
 
 	for _, f := range config.Filters {
 		err = f.init()
@@ -199,6 +220,18 @@ func (config *FileConfig) timestampFromLogLine(logValue string) time.Time {
 	return time.Time{}
 }
 
+//The multiline start check of the tailer, nil when each line is a log entry.
+func (config *FileConfig) multilineStartFn() func(string) bool {
+	if config.MultiLineStartPattern == "" || config.LogFormat == logFormatW3C {
+		return nil
+	}
+	return config.isMultilineStart
+}
+
+func (config *FileConfig) isUTF16() bool {
+	return config.Encoding == "utf-16" || config.Encoding == "utf-16le" || config.Encoding == "UTF-16" || config.Encoding == "UTF-16LE"
+}
+
 //This method determine whether the line is a start line for multiline log entry.
 func (config *FileConfig) isMultilineStart(logValue string) bool {
 
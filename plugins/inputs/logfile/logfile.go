@@ -5,6 +5,7 @@ package logfile
 
 import (
 	"fmt"
+	"hash/fnv"
 	"io"
 	"io/ioutil"
 	"os"
@@ -14,6 +15,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/aws/amazon-cloudwatch-agent/internal/handoff"
 	"github.com/aws/amazon-cloudwatch-agent/internal/logscommon"
 	"github.com/aws/amazon-cloudwatch-agent/logs"
 	"github.com/aws/amazon-cloudwatch-agent/plugins/inputs/logfile/globpath"
@@ -22,6 +24,9 @@ import (
 	"github.com/influxdata/telegraf/plugins/inputs"
 )
 
+// logStreamBucketPlaceholder is replaced by the bucket of the file in the log stream name, see log_stream_buckets.
+const logStreamBucketPlaceholder = "{bucket}"
+
 type LogFile struct {
 	//array of file config for file to be monitored.
 	FileConfig []FileConfig `toml:"file_config"`
@@ -69,6 +74,9 @@ const sampleConfig = `
       blacklist = "logfile.log.bak"
       ## Publish all log files that match file_path
       publish_multi_logs = false
+      ## Publish the files to this number of log streams of the log group instead of one stream per file,
+      ## "{bucket}" in the log_stream_name is replaced by the bucket of the file
+      # log_stream_buckets = 16
       log_group_name = "logfile.log"
       log_stream_name = "<log_stream_name>"
       publish_multi_logs = false
@@ -85,6 +93,8 @@ const sampleConfig = `
       max_event_size = 262144
       ## Suffix to be added to truncated logline to indicate its truncation, defaults to "[Truncated...]"
       truncate_suffix = "[Truncated...]"
+      ## The weight of the file among the files of its log stream when the stream is slower than them, defaults to 1
+      # priority = 1
 
 `
 
@@ -181,11 +191,6 @@ func (t *LogFile) FindLogSrc() []logs.LogSrc {
 				seekFile = &tail.SeekInfo{Whence: io.SeekEnd, Offset: 0}
 			}
 
-			isutf16 := false
-			if fileconfig.Encoding == "utf-16" || fileconfig.Encoding == "utf-16le" || fileconfig.Encoding == "UTF-16" || fileconfig.Encoding == "UTF-16LE" {
-				isutf16 = true
-			}
-
 			tailer, err := tail.TailFile(filename,
 				tail.Config{
 					ReOpen:      false,
@@ -195,7 +200,7 @@ func (t *LogFile) FindLogSrc() []logs.LogSrc {
 					Pipe:        fileconfig.Pipe,
 					Poll:        true,
 					MaxLineSize: fileconfig.MaxEventSize,
-					IsUTF16:     isutf16,
+					IsUTF16:     fileconfig.isUTF16(),
 				})
 
 			if err != nil {
@@ -203,11 +208,6 @@ func (t *LogFile) FindLogSrc() []logs.LogSrc {
 				continue
 			}
 
-			var mlCheck func(string) bool
-			if fileconfig.MultiLineStartPattern != "" {
-				mlCheck = fileconfig.isMultilineStart
-			}
-
 			groupName := fileconfig.LogGroupName
 			streamName := fileconfig.LogStreamName
 
@@ -216,6 +216,8 @@ func (t *LogFile) FindLogSrc() []logs.LogSrc {
 			if fileconfig.PublishMultiLogs {
 				if groupName == "" {
 					groupName = generateLogGroupName(filename)
+				} else if fileconfig.LogStreamBuckets > 0 {
+					streamName = generateBucketLogStreamName(filename, fileconfig.LogStreamName, fileconfig.LogStreamBuckets)
 				} else {
 					streamName = generateLogStreamName(filename, fileconfig.LogStreamName)
 				}
@@ -232,7 +234,7 @@ func (t *LogFile) FindLogSrc() []logs.LogSrc {
 				t.getStateFilePath(filename),
 				tailer,
 				fileconfig.AutoRemoval,
-				mlCheck,
+				fileconfig.multilineStartFn(),
 				fileconfig.Filters,
 				fileconfig.timestampFromLogLine,
 				fileconfig.Enc,
@@ -240,6 +242,18 @@ func (t *LogFile) FindLogSrc() []logs.LogSrc {
 				fileconfig.TruncateSuffix,
 				fileconfig.RetentionInDays,
 			)
+			src.priority = fileconfig.Priority
+
+			if fileconfig.LogFormat == logFormatW3C {
+				// the #Fields of the file are before the offset when the tailing resumes or starts at the end
+				w3cOffset := int64(0)
+				if seekFile != nil && seekFile.Whence == io.SeekEnd {
+					w3cOffset = -1
+				} else if seekFile != nil {
+					w3cOffset = seekFile.Offset
+				}
+				src.w3c = newW3CParser(filename, w3cOffset)
+			}
 
 			src.AddCleanUpFn(func(ts *tailerSrc) func() {
 				return func() {
@@ -313,6 +327,12 @@ func (t *LogFile) getTargetFiles(fileconfig *FileConfig) ([]string, error) {
 
 //The plugin will look at the state folder, and restore the offset of the file seeked if such state exists.
 func (t *LogFile) restoreState(filename string) (int64, error) {
+	var handedOffset int64
+	if handoff.State(handoffKey(filename), &handedOffset) && handedOffset > 0 {
+		t.Log.Infof("Reading from offset %v in %s, handed off by the previous agent", handedOffset, filename)
+		return handedOffset, nil
+	}
+
 	filePath := t.getStateFilePath(filename)
 
 	if _, err := os.Stat(filePath); err != nil {
@@ -438,6 +458,19 @@ func generateLogStreamName(fileName string, streamName string) string {
 	return fmt.Sprintf("%s_%s", streamName, s)
 }
 
+// generateBucketLogStreamName returns the log stream of the bucket of the file, the files are spread over the buckets
+// by the hash of their path so a file is always published to the same stream. The {bucket} placeholder of the stream
+// name is replaced by the bucket number if present, otherwise the number is appended to the stream name.
+func generateBucketLogStreamName(fileName string, streamName string, buckets int) string {
+	h := fnv.New32a()
+	h.Write([]byte(fileName))
+	bucket := strconv.FormatUint(uint64(h.Sum32()%uint32(buckets)), 10)
+	if strings.Contains(streamName, logStreamBucketPlaceholder) {
+		return strings.Replace(streamName, logStreamBucketPlaceholder, bucket, -1)
+	}
+	return fmt.Sprintf("%s_%s", streamName, bucket)
+}
+
 // Directory should be skipped.
 // This func is to determine whether the file is actually a directory or a symbolic link pointing to a directory
 func isDirectory(filename string) (bool, error) {
@@ -10,6 +10,7 @@ import (
 	"os"
 	"path/filepath"
 	"strconv"
+	"strings"
 	"sync"
 	"testing"
 	"time"
@@ -1068,6 +1069,54 @@ func TestLogFileMultiLogsReadingWithBlacklist(t *testing.T) {
 	tt.Stop()
 }
 
+func TestLogFileMultiLogsStreamBuckets(t *testing.T) {
+	dir, err := ioutil.TempDir("", "test")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	for i := 0; i < 20; i++ {
+		require.NoError(t, ioutil.WriteFile(filepath.Join(dir, fmt.Sprintf("app%d.log", i)), []byte("line\n"), 0644))
+	}
+
+	tt := NewLogFile()
+	tt.Log = TestLogger{t}
+	tt.FileConfig = []FileConfig{{
+		FilePath:         filepath.Join(dir, "*.log"),
+		FromBeginning:    true,
+		PublishMultiLogs: true,
+		LogGroupName:     "app",
+		LogStreamName:    "i-0123-{bucket}",
+		LogStreamBuckets: 3,
+	}}
+	require.NoError(t, tt.FileConfig[0].init())
+	tt.started = true
+
+	lsrcs := tt.FindLogSrc()
+	assert.Len(t, lsrcs, 20)
+	streams := map[string]bool{}
+	for _, lsrc := range lsrcs {
+		assert.Equal(t, "app", lsrc.Group())
+		assert.Regexp(t, "^i-0123-[0-2]$", lsrc.Stream())
+		streams[lsrc.Stream()] = true
+		lsrc.Stop()
+	}
+	// the 20 files are spread over the 3 streams
+	assert.Len(t, streams, 3)
+	tt.Stop()
+}
+
+func TestGenerateBucketLogStreamName(t *testing.T) {
+	fileName := "/var/log/app/worker-1.log"
+	streamName := generateBucketLogStreamName(fileName, "{instance_id}-{bucket}", 16)
+	assert.Regexp(t, "^\\{instance_id\\}-([0-9]|1[0-5])$", streamName)
+	// a file is always in the same bucket
+	assert.Equal(t, streamName, generateBucketLogStreamName(fileName, "{instance_id}-{bucket}", 16))
+	bucket := strings.TrimPrefix(streamName, "{instance_id}-")
+	assert.Equal(t, "app_"+bucket, generateBucketLogStreamName(fileName, "app", 16))
+	assert.Equal(t, "app_0", generateBucketLogStreamName(fileName, "app", 1))
+
+	assert.Error(t, (&FileConfig{FilePath: fileName, LogStreamBuckets: -1}).init())
+}
+
 func TestGenerateLogGroupName(t *testing.T) {
 	multilineWaitPeriod = 10 * time.Millisecond
 	fileName := "C:\\tmp\\soak Test\\tmp0.log"
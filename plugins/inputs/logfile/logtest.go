@@ -0,0 +1,64 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package logfile
+
+import (
+	"fmt"
+
+	"github.com/aws/amazon-cloudwatch-agent/logs"
+	"github.com/aws/amazon-cloudwatch-agent/plugins/inputs/logfile/tail"
+)
+
+// TestFileConfig reads the sample file once through the same tailer source used by the agent, so the multiline
+// grouping, truncation, timestamp parsing and filters of the file config are applied, and returns the events that
+// would be published.
+func TestFileConfig(fileconfig *FileConfig, sampleFile string) ([]logs.LogEvent, error) {
+	if err := fileconfig.init(); err != nil {
+		return nil, err
+	}
+
+	tailer, err := tail.TailFile(sampleFile,
+		tail.Config{
+			ReOpen:      false,
+			Follow:      false,
+			MustExist:   true,
+			MaxLineSize: fileconfig.MaxEventSize,
+			IsUTF16:     fileconfig.isUTF16(),
+		})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read sample file %v: %v", sampleFile, err)
+	}
+	defer tailer.Cleanup()
+
+	src := NewTailerSrc(
+		fileconfig.LogGroupName, fileconfig.LogStreamName,
+		fileconfig.Destination,
+		"", // no state file, the sample is always read from the beginning
+		tailer,
+		false,
+		fileconfig.multilineStartFn(),
+		fileconfig.Filters,
+		fileconfig.timestampFromLogLine,
+		fileconfig.Enc,
+		fileconfig.MaxEventSize,
+		fileconfig.TruncateSuffix,
+		fileconfig.RetentionInDays,
+	)
+	defer src.Stop()
+	if fileconfig.LogFormat == logFormatW3C {
+		src.w3c = newW3CParser(sampleFile, 0)
+	}
+
+	var events []logs.LogEvent
+	done := make(chan struct{})
+	src.SetOutput(func(e logs.LogEvent) {
+		if e == nil {
+			close(done)
+			return
+		}
+		events = append(events, e)
+	})
+	<-done
+	return events, nil
+}
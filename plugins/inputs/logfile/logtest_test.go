@@ -0,0 +1,44 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package logfile
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTestFileConfig(t *testing.T) {
+	file, err := createTempFile("", "logtest-*.log")
+	assert.NoError(t, err)
+	defer os.Remove(file.Name())
+	_, err = file.WriteString("02 Jan 2021 15:04:05 ERROR first\n  at line 1\n  at line 2\n02 Jan 2021 15:04:06 DEBUG second\n02 Jan 2021 15:04:07 ERROR third")
+	assert.NoError(t, err)
+	file.Close()
+
+	fileconfig := &FileConfig{
+		FilePath:              file.Name(),
+		LogGroupName:          "group",
+		TimestampRegex:        "^(\\d{2} \\w{3} \\d{4} \\d{2}:\\d{2}:\\d{2}).*$",
+		TimestampLayout:       "02 Jan 2006 15:04:05",
+		Timezone:              "UTC",
+		MultiLineStartPattern: "{timestamp_regex}",
+		Filters:               []*LogFilter{{Type: excludeFilterType, Expression: "DEBUG"}},
+	}
+	events, err := TestFileConfig(fileconfig, file.Name())
+	assert.NoError(t, err)
+	assert.Len(t, events, 2)
+	assert.Equal(t, "02 Jan 2021 15:04:05 ERROR first\n  at line 1\n  at line 2", events[0].Message())
+	assert.Equal(t, "02 Jan 2021 15:04:07 ERROR third", events[1].Message())
+	assert.Equal(t, time.Date(2021, time.January, 2, 15, 4, 7, 0, time.UTC), events[1].Time())
+}
+
+func TestTestFileConfigInvalid(t *testing.T) {
+	_, err := TestFileConfig(&FileConfig{TimestampRegex: "("}, "sample.log")
+	assert.Error(t, err)
+	_, err = TestFileConfig(&FileConfig{}, "/nonexistent/sample.log")
+	assert.Error(t, err)
+}
@@ -0,0 +1,112 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package logfile
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/amazon-cloudwatch-agent/internal/logscommon"
+)
+
+// The status of the file of a state compared to its checkpoint.
+const (
+	// the file was uploaded up to its end
+	StateUploaded = "uploaded"
+	// the file has bytes after the checkpoint, they were not uploaded when the state was copied
+	StatePending = "pending"
+	// the file is smaller than the checkpoint, it was truncated or rotated after the checkpoint and the events
+	// written after the checkpoint before the truncation were not uploaded
+	StateLost = "lost"
+	// the file doesn't exist anymore, or the state is of a windows event log which can't be compared offline
+	StateUnknown = "unknown"
+)
+
+// FileState is a checkpoint of a state directory compared to the file it tracks.
+type FileState struct {
+	StateFile string `json:"state_file"`
+	// the tailed file, or the log group of a windows event log
+	Source       string    `json:"source"`
+	WindowsEvent bool      `json:"windows_event,omitempty"`
+	Offset       int64     `json:"offset"`
+	Size         int64     `json:"size,omitempty"`
+	Saved        time.Time `json:"saved"`
+	Status       string    `json:"status"`
+	// the bytes after the checkpoint, pending or lost
+	Bytes int64 `json:"bytes,omitempty"`
+}
+
+// ReplayState reads the checkpoints of a state directory, e.g. copied from a failed host, and compares each of
+// them to the size of its file under root, the root of the copied file system, or the files at their own path
+// when root is empty. Nothing is written to the state directory or to the files, so the directory isn't cleaned
+// up like when the agent starts.
+func ReplayState(stateDir, root string) ([]FileState, error) {
+	entries, err := ioutil.ReadDir(stateDir)
+	if err != nil {
+		return nil, err
+	}
+	var states []FileState
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		b, err := ioutil.ReadFile(filepath.Join(stateDir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		// e.g. "1024\n/var/log/messages", the other files of the directory like agent_audit_state.json are skipped
+		lines := strings.SplitN(string(b), "\n", 2)
+		if len(lines) != 2 || lines[1] == "" {
+			continue
+		}
+		offset, err := strconv.ParseInt(lines[0], 10, 64)
+		if err != nil {
+			continue
+		}
+		state := FileState{
+			StateFile: entry.Name(),
+			Source:    lines[1],
+			Offset:    offset,
+			Saved:     entry.ModTime(),
+			Status:    StateUnknown,
+		}
+		if strings.HasPrefix(entry.Name(), logscommon.WindowsEventLogPrefix) {
+			// the offset is the record number of the event log
+			state.WindowsEvent = true
+		} else {
+			compareFile(&state, root)
+		}
+		states = append(states, state)
+	}
+	sort.Slice(states, func(i, j int) bool {
+		return states[i].Source < states[j].Source
+	})
+	return states, nil
+}
+
+func compareFile(state *FileState, root string) {
+	path := state.Source
+	if root != "" {
+		path = filepath.Join(root, path)
+	}
+	info, err := os.Stat(path)
+	if err != nil || info.IsDir() {
+		return
+	}
+	state.Size = info.Size()
+	switch {
+	case state.Size == state.Offset:
+		state.Status = StateUploaded
+	case state.Size > state.Offset:
+		state.Status = StatePending
+		state.Bytes = state.Size - state.Offset
+	default:
+		state.Status = StateLost
+	}
+}
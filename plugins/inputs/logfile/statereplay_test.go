@@ -0,0 +1,70 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package logfile
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReplayState(t *testing.T) {
+	root, err := ioutil.TempDir("", "statereplay")
+	require.NoError(t, err)
+	defer os.RemoveAll(root)
+	stateDir := filepath.Join(root, "state")
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "var", "log"), 0755))
+	require.NoError(t, os.MkdirAll(stateDir, 0755))
+
+	files := map[string]string{
+		"/var/log/uploaded.log":  "0123456789",
+		"/var/log/pending.log":   "0123456789",
+		"/var/log/truncated.log": "0123",
+	}
+	for name, content := range files {
+		require.NoError(t, ioutil.WriteFile(filepath.Join(root, name), []byte(content), 0644))
+	}
+	states := map[string]string{
+		"_var_log_uploaded.log":                                 "10\n/var/log/uploaded.log",
+		"_var_log_pending.log":                                  "4\n/var/log/pending.log",
+		"_var_log_truncated.log":                                "8\n/var/log/truncated.log",
+		"_var_log_deleted.log":                                  "8\n/var/log/deleted.log",
+		"Amazon_CloudWatch_WindowsEventLog_group_stream_System": "1200\ngroup",
+		"agent_audit_state.json":                                `{"sequence": 3}`,
+	}
+	for name, content := range states {
+		require.NoError(t, ioutil.WriteFile(filepath.Join(stateDir, name), []byte(content), 0644))
+	}
+
+	replayed, err := ReplayState(stateDir, root)
+	require.NoError(t, err)
+	require.Len(t, replayed, 5)
+	for i := range replayed {
+		// the time of the last checkpoint
+		assert.False(t, replayed[i].Saved.IsZero())
+		replayed[i].Saved = time.Time{}
+	}
+	assert.Equal(t, []FileState{
+		{StateFile: "_var_log_deleted.log", Source: "/var/log/deleted.log", Offset: 8, Status: StateUnknown},
+		{StateFile: "_var_log_pending.log", Source: "/var/log/pending.log", Offset: 4, Size: 10, Status: StatePending, Bytes: 6},
+		{StateFile: "_var_log_truncated.log", Source: "/var/log/truncated.log", Offset: 8, Size: 4, Status: StateLost},
+		{StateFile: "_var_log_uploaded.log", Source: "/var/log/uploaded.log", Offset: 10, Size: 10, Status: StateUploaded},
+		{StateFile: "Amazon_CloudWatch_WindowsEventLog_group_stream_System", Source: "group", WindowsEvent: true, Offset: 1200, Status: StateUnknown},
+	}, replayed)
+
+	// the state directory is left as it is
+	entries, err := ioutil.ReadDir(stateDir)
+	require.NoError(t, err)
+	assert.Len(t, entries, len(states))
+}
+
+func TestReplayStateMissingDirectory(t *testing.T) {
+	_, err := ReplayState("/nonexistent/state", "")
+	assert.Error(t, err)
+}
@@ -12,6 +12,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/aws/amazon-cloudwatch-agent/internal/handoff"
 	"github.com/aws/amazon-cloudwatch-agent/logs"
 	"github.com/aws/amazon-cloudwatch-agent/plugins/inputs/logfile/tail"
 	"golang.org/x/text/encoding"
@@ -67,9 +68,12 @@ type tailerSrc struct {
 	maxEventSize    int
 	truncateSuffix  string
 	retentionInDays int
+	// the weight of the file among the files of its log stream when the stream is slower than them
+	priority int
 
 	outputFn        func(logs.LogEvent)
 	isMLStart       func(string) bool
+	w3c             *w3cParser
 	filters         []*LogFilter
 	offsetCh        chan fileOffset
 	done            chan struct{}
@@ -138,6 +142,11 @@ func (ts tailerSrc) Destination() string {
 func (ts tailerSrc) Retention() int {
 	return ts.retentionInDays
 }
+
+func (ts *tailerSrc) Priority() int {
+	return ts.priority
+}
+
 func (ts tailerSrc) Done(offset fileOffset) {
 	// ts.offsetCh will only be blocked when the runSaveState func has exited,
 	// which only happens when the original file has been removed, thus making
@@ -175,7 +184,7 @@ func (ts *tailerSrc) runTail() {
 					msg := msgBuf.String()
 					e := &LogEvent{
 						msg:    msg,
-						t:      ts.timestampFn(msg),
+						t:      ts.eventTime(msg),
 						offset: *fo,
 						src:    ts,
 					}
@@ -202,6 +211,14 @@ func (ts *tailerSrc) runTail() {
 				}
 			}
 
+			if ts.w3c != nil {
+				var ok bool
+				if text, ok = ts.w3c.parse(text); !ok {
+					fo.SetOffset(line.Offset)
+					continue
+				}
+			}
+
 			if ts.isMLStart == nil {
 				msgBuf.Reset()
 				msgBuf.WriteString(text)
@@ -229,7 +246,7 @@ func (ts *tailerSrc) runTail() {
 				msg := msgBuf.String()
 				e := &LogEvent{
 					msg:    msg,
-					t:      ts.timestampFn(msg),
+					t:      ts.eventTime(msg),
 					offset: *fo,
 					src:    ts,
 				}
@@ -256,7 +273,7 @@ func (ts *tailerSrc) runTail() {
 			msg := msgBuf.String()
 			e := &LogEvent{
 				msg:    msg,
-				t:      ts.timestampFn(msg),
+				t:      ts.eventTime(msg),
 				offset: *fo,
 				src:    ts,
 			}
@@ -271,6 +288,15 @@ func (ts *tailerSrc) runTail() {
 	}
 }
 
+// eventTime returns the timestamp of the message, or the time of the W3C fields of the message.
+func (ts *tailerSrc) eventTime(msg string) time.Time {
+	t := ts.timestampFn(msg)
+	if t.IsZero() && ts.w3c != nil {
+		return ts.w3c.timestamp
+	}
+	return t
+}
+
 func (ts *tailerSrc) cleanUp() {
 	if ts.autoRemoval {
 		if err := os.Remove(ts.tailer.Filename); err != nil {
@@ -291,12 +317,22 @@ func (ts *tailerSrc) runSaveState() {
 	defer t.Stop()
 
 	var offset, lastSavedOffset fileOffset
+	// the offset uploaded is handed to the new agent in memory during an upgrade
+	var offsetMtx sync.Mutex
+	unregister := handoff.RegisterState(handoffKey(ts.tailer.Filename), func() interface{} {
+		offsetMtx.Lock()
+		defer offsetMtx.Unlock()
+		return offset.offset
+	})
+	defer unregister()
 	for {
 		select {
 		case o := <-ts.offsetCh:
+			offsetMtx.Lock()
 			if o.seq > offset.seq || (o.seq == offset.seq && o.offset > offset.offset) {
 				offset = o
 			}
+			offsetMtx.Unlock()
 		case <-t.C:
 			if offset == lastSavedOffset {
 				continue
@@ -324,6 +360,11 @@ func (ts *tailerSrc) runSaveState() {
 	}
 }
 
+// handoffKey is the key of the offset of the file handed to the new agent.
+func handoffKey(filename string) string {
+	return "logfile:" + filename
+}
+
 func (ts *tailerSrc) saveState(offset int64) error {
 	if ts.stateFilePath == "" || offset == 0 {
 		return nil
@@ -0,0 +1,160 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package logfile
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	logFormatW3C = "w3c"
+
+	w3cDirectivePrefix = "#"
+	w3cFieldsDirective = "#Fields:"
+	w3cEmptyValue      = "-"
+)
+
+// w3cIntegerFields are the fields converted to integers, the other fields are kept as strings.
+var w3cIntegerFields = map[string]bool{
+	"sc-status":       true,
+	"sc-substatus":    true,
+	"sc-win32-status": true,
+	"sc-bytes":        true,
+	"cs-bytes":        true,
+	"time-taken":      true,
+	"s-port":          true,
+	"c-port":          true,
+	// Exchange
+	"total-bytes":     true,
+	"recipient-count": true,
+	"server-port":     true,
+	"client-port":     true,
+}
+
+// w3cParser converts the lines of a W3C extended log file, like the IIS and Exchange logs, to json objects keyed by
+// the names of the last #Fields directive. The directives may change the fields in the middle of a file, e.g. when
+// the logging of IIS is reconfigured.
+type w3cParser struct {
+	fields []string
+	// Exchange separates the fields with commas and quotes the values, IIS separates them with spaces
+	csv bool
+	// the timestamp of the last parsed line
+	timestamp time.Time
+}
+
+// newW3CParser returns a parser with the fields of the last #Fields directive of the file before the offset, for a
+// file whose tailing resumes after its header. A negative offset reads the whole file.
+func newW3CParser(filename string, offset int64) *w3cParser {
+	p := &w3cParser{}
+	if offset == 0 {
+		return p
+	}
+	f, err := os.Open(filename)
+	if err != nil {
+		log.Printf("W! [logfile] Failed to read the #Fields of %s: %v", filename, err)
+		return p
+	}
+	defer f.Close()
+	var r io.Reader = f
+	if offset > 0 {
+		r = io.LimitReader(f, offset)
+	}
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), defaultMaxEventSize)
+	for scanner.Scan() {
+		if line := scanner.Text(); strings.HasPrefix(line, w3cFieldsDirective) {
+			p.setFields(line)
+		}
+	}
+	return p
+}
+
+// parse returns the json object of a data line, or false for a directive.
+func (p *w3cParser) parse(line string) (string, bool) {
+	line = strings.TrimRight(line, "\r")
+	if strings.HasPrefix(line, w3cDirectivePrefix) {
+		if strings.HasPrefix(line, w3cFieldsDirective) {
+			p.setFields(line)
+		}
+		return "", false
+	}
+	if len(p.fields) == 0 || strings.TrimSpace(line) == "" {
+		// the line is published as is until the fields are known
+		p.timestamp = time.Time{}
+		return line, line != ""
+	}
+
+	values := p.split(line)
+	event := make(map[string]interface{}, len(values))
+	for i, value := range values {
+		if i >= len(p.fields) || value == w3cEmptyValue || value == "" {
+			continue
+		}
+		name := p.fields[i]
+		if w3cIntegerFields[name] {
+			if n, err := strconv.ParseInt(value, 10, 64); err == nil {
+				event[name] = n
+				continue
+			}
+		}
+		event[name] = value
+	}
+	p.timestamp = w3cTimestamp(event)
+	msg, err := json.Marshal(event)
+	if err != nil {
+		return line, true
+	}
+	return string(msg), true
+}
+
+func (p *w3cParser) setFields(directive string) {
+	fields := strings.TrimSpace(strings.TrimPrefix(directive, w3cFieldsDirective))
+	p.csv = strings.Contains(fields, ",")
+	if p.csv {
+		p.fields = strings.Split(fields, ",")
+	} else {
+		p.fields = strings.Fields(fields)
+	}
+	for i := range p.fields {
+		p.fields[i] = strings.TrimSpace(p.fields[i])
+	}
+}
+
+func (p *w3cParser) split(line string) []string {
+	if !p.csv {
+		return strings.Fields(line)
+	}
+	r := csv.NewReader(strings.NewReader(line))
+	r.LazyQuotes = true
+	values, err := r.Read()
+	if err != nil {
+		return strings.Split(line, ",")
+	}
+	return values
+}
+
+// w3cTimestamp returns the time of the event, W3C logs are written in UTC.
+func w3cTimestamp(event map[string]interface{}) time.Time {
+	if dateTime, ok := event["date-time"].(string); ok {
+		if t, err := time.Parse(time.RFC3339Nano, dateTime); err == nil {
+			return t
+		}
+	}
+	date, _ := event["date"].(string)
+	clock, _ := event["time"].(string)
+	if date != "" && clock != "" {
+		if t, err := time.Parse("2006-01-02 15:04:05", date+" "+clock); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
@@ -0,0 +1,93 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package logfile
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const iisLog = `#Software: Microsoft Internet Information Services 10.0
+#Version: 1.0
+#Date: 2021-03-02 15:04:05
+#Fields: date time s-ip cs-method cs-uri-stem cs-uri-query s-port cs-username c-ip cs(User-Agent) sc-status sc-substatus sc-win32-status time-taken
+2021-03-02 15:04:05 10.0.0.1 GET /index.html - 443 - 10.0.0.2 Mozilla/5.0+(Windows+NT+10.0) 200 0 0 15
+#Fields: date time cs-method cs-uri-stem sc-status sc-bytes
+2021-03-02 15:04:06 POST /api 500 1024
+`
+
+func TestW3CParserFieldsChange(t *testing.T) {
+	p := &w3cParser{}
+	var events []string
+	for _, line := range strings.Split(strings.TrimSpace(iisLog), "\n") {
+		if msg, ok := p.parse(line + "\r"); ok {
+			events = append(events, msg)
+		}
+	}
+	assert.Equal(t, []string{
+		`{"c-ip":"10.0.0.2","cs(User-Agent)":"Mozilla/5.0+(Windows+NT+10.0)","cs-method":"GET","cs-uri-stem":"/index.html","date":"2021-03-02",` +
+			`"s-ip":"10.0.0.1","s-port":443,"sc-status":200,"sc-substatus":0,"sc-win32-status":0,"time":"15:04:05","time-taken":15}`,
+		`{"cs-method":"POST","cs-uri-stem":"/api","date":"2021-03-02","sc-bytes":1024,"sc-status":500,"time":"15:04:06"}`,
+	}, events)
+	assert.Equal(t, time.Date(2021, 3, 2, 15, 4, 6, 0, time.UTC), p.timestamp)
+}
+
+func TestW3CParserExchange(t *testing.T) {
+	p := &w3cParser{}
+	_, ok := p.parse("#Fields: date-time,client-ip,event-id,recipient-count,message-subject")
+	assert.False(t, ok)
+	msg, ok := p.parse(`2021-03-02T15:04:05.123Z,10.0.0.2,RECEIVE,2,"Hello, world"`)
+	assert.True(t, ok)
+	assert.Equal(t, `{"client-ip":"10.0.0.2","date-time":"2021-03-02T15:04:05.123Z","event-id":"RECEIVE","message-subject":"Hello, world","recipient-count":2}`, msg)
+	assert.Equal(t, time.Date(2021, 3, 2, 15, 4, 5, 123000000, time.UTC), p.timestamp)
+}
+
+func TestW3CParserWithoutFields(t *testing.T) {
+	p := &w3cParser{}
+	msg, ok := p.parse("2021-03-02 15:04:05 GET /index.html")
+	assert.True(t, ok)
+	assert.Equal(t, "2021-03-02 15:04:05 GET /index.html", msg)
+	assert.True(t, p.timestamp.IsZero())
+	_, ok = p.parse("")
+	assert.False(t, ok)
+}
+
+func TestNewW3CParserResumesAfterHeader(t *testing.T) {
+	file, err := createTempFile("", "w3c-*.log")
+	assert.NoError(t, err)
+	defer os.Remove(file.Name())
+	_, err = file.WriteString(iisLog)
+	assert.NoError(t, err)
+	file.Close()
+
+	// the offset of the first data line, after the first #Fields
+	offset := int64(strings.Index(iisLog, "2021-03-02 15:04:05 10.0.0.1"))
+	assert.Equal(t, "time-taken", newW3CParser(file.Name(), offset).fields[13])
+	// the whole file
+	assert.Equal(t, []string{"date", "time", "cs-method", "cs-uri-stem", "sc-status", "sc-bytes"}, newW3CParser(file.Name(), -1).fields)
+	assert.Empty(t, newW3CParser(file.Name(), 0).fields)
+}
+
+func TestTestFileConfigW3C(t *testing.T) {
+	file, err := createTempFile("", "w3c-*.log")
+	assert.NoError(t, err)
+	defer os.Remove(file.Name())
+	_, err = file.WriteString(iisLog)
+	assert.NoError(t, err)
+	file.Close()
+
+	events, err := TestFileConfig(&FileConfig{FilePath: file.Name(), LogGroupName: "group", LogFormat: logFormatW3C}, file.Name())
+	assert.NoError(t, err)
+	assert.Len(t, events, 2)
+	assert.Equal(t, `{"cs-method":"POST","cs-uri-stem":"/api","date":"2021-03-02","sc-bytes":1024,"sc-status":500,"time":"15:04:06"}`, events[1].Message())
+	assert.Equal(t, time.Date(2021, 3, 2, 15, 4, 5, 0, time.UTC), events[0].Time())
+	assert.Equal(t, time.Date(2021, 3, 2, 15, 4, 6, 0, time.UTC), events[1].Time())
+
+	_, err = TestFileConfig(&FileConfig{FilePath: file.Name(), LogGroupName: "group", LogFormat: "csv"}, file.Name())
+	assert.Error(t, err)
+}
@@ -0,0 +1,244 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package netstat_ports
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/plugins/inputs"
+)
+
+const (
+	// the measurement of the netstat input, the breakdown has the metric names of the totals
+	measurement     = "netstat"
+	portTag         = "port"
+	processNameTag  = "process_name"
+	defaultProcRoot = "/proc"
+	socketPrefix    = "socket:["
+)
+
+var sampleConfig = `
+  ## The local ports the connection states are reported for, e.g. the ports the services listen on.
+  ports = [443]
+
+  ## Break down the connection states by the name of the process owning the sockets.
+  # by_process = false
+`
+
+// the fields of the connection states of /proc/net/tcp, by their hexadecimal code
+var tcpStates = map[string]string{
+	"01": "tcp_established",
+	"02": "tcp_syn_sent",
+	"03": "tcp_syn_recv",
+	"04": "tcp_fin_wait1",
+	"05": "tcp_fin_wait2",
+	"06": "tcp_time_wait",
+	"07": "tcp_close",
+	"08": "tcp_close_wait",
+	"09": "tcp_last_ack",
+	"0A": "tcp_listen",
+	"0B": "tcp_closing",
+}
+
+// NetstatPorts reports the tcp connection states of the netstat input broken down by local port, from an include
+// list, and by owning process, e.g. the established connections on the port 443 of an application.
+type NetstatPorts struct {
+	Ports     []int `toml:"ports"`
+	ByProcess bool  `toml:"by_process"`
+
+	Log telegraf.Logger `toml:"-"`
+
+	// the procfs mount, replaced in tests
+	procRoot string
+	ports    map[int]bool
+}
+
+// socket is a connection of /proc/net/tcp.
+type socket struct {
+	port  int
+	state string
+	inode string
+}
+
+// group is the tags of a breakdown, its port or process, or both.
+type group struct {
+	port    int
+	process string
+}
+
+func (n *NetstatPorts) SampleConfig() string {
+	return sampleConfig
+}
+
+func (n *NetstatPorts) Description() string {
+	return "Report the tcp connection states by local port and by process."
+}
+
+func (n *NetstatPorts) Init() error {
+	if len(n.Ports) == 0 && !n.ByProcess {
+		return fmt.Errorf("netstat_ports: ports or by_process must be set")
+	}
+	n.ports = map[int]bool{}
+	for _, port := range n.Ports {
+		if port <= 0 || port > 65535 {
+			return fmt.Errorf("netstat_ports: invalid port %d", port)
+		}
+		n.ports[port] = true
+	}
+	if n.procRoot == "" {
+		n.procRoot = defaultProcRoot
+	}
+	return nil
+}
+
+func (n *NetstatPorts) Gather(acc telegraf.Accumulator) error {
+	var sockets []socket
+	for _, file := range []string{"tcp", "tcp6"} {
+		s, err := readSockets(filepath.Join(n.procRoot, "net", file))
+		if os.IsNotExist(err) {
+			// tcp6 is missing when ipv6 is disabled
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		sockets = append(sockets, s...)
+	}
+	var owners map[string]string
+	if n.ByProcess {
+		owners = n.owners()
+	}
+
+	counts := map[group]map[string]interface{}{}
+	// the ports are reported without connection, so the alarms on them have data
+	if !n.ByProcess {
+		for port := range n.ports {
+			counts[group{port: port}] = newFields()
+		}
+	}
+	for _, s := range sockets {
+		g := group{}
+		if len(n.ports) > 0 {
+			if !n.ports[s.port] {
+				continue
+			}
+			g.port = s.port
+		}
+		if n.ByProcess {
+			// the sockets without owner, e.g. in time_wait, are only in the totals of the netstat input
+			process, ok := owners[s.inode]
+			if !ok {
+				continue
+			}
+			g.process = process
+		}
+		fields, ok := counts[g]
+		if !ok {
+			fields = newFields()
+			counts[g] = fields
+		}
+		fields[s.state] = fields[s.state].(int) + 1
+	}
+
+	for g, fields := range counts {
+		tags := map[string]string{}
+		if g.port > 0 {
+			tags[portTag] = strconv.Itoa(g.port)
+		}
+		if g.process != "" {
+			tags[processNameTag] = g.process
+		}
+		acc.AddFields(measurement, fields, tags)
+	}
+	return nil
+}
+
+func newFields() map[string]interface{} {
+	fields := make(map[string]interface{}, len(tcpStates))
+	for _, field := range tcpStates {
+		fields[field] = 0
+	}
+	return fields
+}
+
+// readSockets parses the sockets of /proc/net/tcp or tcp6, the lines are like
+// "0: 0100007F:01BB 00000000:0000 0A 00000000:00000000 00:00000000 00000000 0 0 12345 ...", the local address and
+// port, the remote ones, the state, and the inode at the tenth column.
+func readSockets(path string) ([]socket, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	lines := strings.Split(string(b), "\n")
+	var sockets []socket
+	// the first line is the header
+	for _, line := range lines[1:] {
+		columns := strings.Fields(line)
+		if len(columns) < 10 {
+			continue
+		}
+		local := strings.Split(columns[1], ":")
+		if len(local) != 2 {
+			return nil, fmt.Errorf("unexpected local address in %s: %q", path, columns[1])
+		}
+		port, err := strconv.ParseUint(local[1], 16, 16)
+		if err != nil {
+			return nil, fmt.Errorf("unexpected local port in %s: %v", path, err)
+		}
+		state, ok := tcpStates[strings.ToUpper(columns[3])]
+		if !ok {
+			continue
+		}
+		sockets = append(sockets, socket{port: int(port), state: state, inode: columns[9]})
+	}
+	return sockets, nil
+}
+
+// owners returns the names of the processes by inode of their sockets, the sockets are the links to "socket:[inode]"
+// of the descriptors of the processes.
+func (n *NetstatPorts) owners() map[string]string {
+	owners := map[string]string{}
+	dirs, err := ioutil.ReadDir(n.procRoot)
+	if err != nil {
+		n.Log.Errorf("Failed to list the processes: %v", err)
+		return owners
+	}
+	for _, d := range dirs {
+		if _, err := strconv.Atoi(d.Name()); err != nil || !d.IsDir() {
+			continue
+		}
+		// the processes can exit or deny the access to their descriptors, they are skipped
+		dir := filepath.Join(n.procRoot, d.Name())
+		comm, err := ioutil.ReadFile(filepath.Join(dir, "comm"))
+		if err != nil {
+			continue
+		}
+		fds, err := ioutil.ReadDir(filepath.Join(dir, "fd"))
+		if err != nil {
+			n.Log.Debugf("Skipping process %s: %v", d.Name(), err)
+			continue
+		}
+		name := strings.TrimSpace(string(comm))
+		for _, fd := range fds {
+			link, err := os.Readlink(filepath.Join(dir, "fd", fd.Name()))
+			if err != nil || !strings.HasPrefix(link, socketPrefix) {
+				continue
+			}
+			owners[strings.TrimSuffix(strings.TrimPrefix(link, socketPrefix), "]")] = name
+		}
+	}
+	return owners
+}
+
+func init() {
+	inputs.Add("netstat_ports", func() telegraf.Input {
+		return &NetstatPorts{}
+	})
+}
@@ -0,0 +1,143 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package netstat_ports
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/influxdata/telegraf/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+const tcpHeader = "  sl  local_address rem_address   st tx_queue rx_queue tr tm->when retrnsmt   uid  timeout inode\n"
+
+// the sockets of an nginx listening on 443 with two clients, one closing, and a java with a connection to a
+// database on 5432
+const tcp = tcpHeader +
+	"   0: 00000000:01BB 00000000:0000 0A 00000000:00000000 00:00000000 00000000     0        0 1001 1 0000000000000000 100 0 0 10 0\n" +
+	"   1: 0100007F:01BB 0100007F:C350 01 00000000:00000000 00:00000000 00000000     0        0 1002 1 0000000000000000 20 4 30 10 -1\n" +
+	"   2: 0100007F:01BB 0100007F:C351 08 00000000:00000000 00:00000000 00000000     0        0 1003 1 0000000000000000 20 4 30 10 -1\n" +
+	"   3: 0100007F:01BB 0100007F:C352 06 00000000:00000000 03:00000F9C 00000000     0        0 0 3 0000000000000000\n" +
+	"   4: 0100007F:D431 0100007F:1538 01 00000000:00000000 00:00000000 00000000  1000        0 2001 1 0000000000000000 20 4 30 10 -1\n"
+
+const tcp6 = tcpHeader +
+	"   0: 00000000000000000000000001000000:01BB 00000000000000000000000001000000:C353 01 00000000:00000000 00:00000000 00000000     0        0 1004 1 0000000000000000 20 4 30 10 -1\n"
+
+func writeProcRoot(t *testing.T) string {
+	root, err := ioutil.TempDir("", "netstat_ports")
+	assert.NoError(t, err)
+	for name, content := range map[string]string{
+		"net/tcp":    tcp,
+		"net/tcp6":   tcp6,
+		"100/comm":   "nginx\n",
+		"200/comm":   "java\n",
+		"self/comm":  "agent\n",
+		"100/fd/0":   "",
+		"200/fd/dev": "",
+	} {
+		path := filepath.Join(root, name)
+		assert.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		assert.NoError(t, ioutil.WriteFile(path, []byte(content), 0644))
+	}
+	for pid, inodes := range map[string][]string{
+		"100": {"1001", "1002", "1003", "1004"},
+		"200": {"2001"},
+	} {
+		for i, inode := range inodes {
+			assert.NoError(t, os.Symlink("socket:["+inode+"]", filepath.Join(root, pid, "fd", strconv.Itoa(3+i))))
+		}
+	}
+	return root
+}
+
+func fields(counts map[string]int) map[string]interface{} {
+	f := newFields()
+	for k, v := range counts {
+		f[k] = v
+	}
+	return f
+}
+
+func TestGatherByPort(t *testing.T) {
+	root := writeProcRoot(t)
+	defer os.RemoveAll(root)
+	n := &NetstatPorts{Ports: []int{443, 8080}, Log: testutil.Logger{}, procRoot: root}
+	assert.NoError(t, n.Init())
+
+	var acc testutil.Accumulator
+	assert.NoError(t, acc.GatherError(n.Gather))
+	assert.Len(t, acc.Metrics, 2)
+	acc.AssertContainsTaggedFields(t, "netstat", fields(map[string]int{
+		"tcp_listen":      1,
+		"tcp_established": 2,
+		"tcp_close_wait":  1,
+		"tcp_time_wait":   1,
+	}), map[string]string{"port": "443"})
+	// the ports without connection are reported
+	acc.AssertContainsTaggedFields(t, "netstat", newFields(), map[string]string{"port": "8080"})
+}
+
+func TestGatherByProcess(t *testing.T) {
+	root := writeProcRoot(t)
+	defer os.RemoveAll(root)
+	n := &NetstatPorts{ByProcess: true, Log: testutil.Logger{}, procRoot: root}
+	assert.NoError(t, n.Init())
+
+	var acc testutil.Accumulator
+	assert.NoError(t, acc.GatherError(n.Gather))
+	assert.Len(t, acc.Metrics, 2)
+	// the connection in time_wait has no owner
+	acc.AssertContainsTaggedFields(t, "netstat", fields(map[string]int{
+		"tcp_listen":      1,
+		"tcp_established": 2,
+		"tcp_close_wait":  1,
+	}), map[string]string{"process_name": "nginx"})
+	acc.AssertContainsTaggedFields(t, "netstat", fields(map[string]int{
+		"tcp_established": 1,
+	}), map[string]string{"process_name": "java"})
+}
+
+func TestGatherByPortAndProcess(t *testing.T) {
+	root := writeProcRoot(t)
+	defer os.RemoveAll(root)
+	n := &NetstatPorts{Ports: []int{443}, ByProcess: true, Log: testutil.Logger{}, procRoot: root}
+	assert.NoError(t, n.Init())
+
+	var acc testutil.Accumulator
+	assert.NoError(t, acc.GatherError(n.Gather))
+	assert.Len(t, acc.Metrics, 1)
+	acc.AssertContainsTaggedFields(t, "netstat", fields(map[string]int{
+		"tcp_listen":      1,
+		"tcp_established": 2,
+		"tcp_close_wait":  1,
+	}), map[string]string{"port": "443", "process_name": "nginx"})
+}
+
+func TestInit(t *testing.T) {
+	assert.Error(t, (&NetstatPorts{}).Init())
+	assert.Error(t, (&NetstatPorts{Ports: []int{0}}).Init())
+	assert.Error(t, (&NetstatPorts{Ports: []int{65536}}).Init())
+	assert.NoError(t, (&NetstatPorts{Ports: []int{443}}).Init())
+}
+
+func TestGatherWithoutTCP6(t *testing.T) {
+	root := writeProcRoot(t)
+	defer os.RemoveAll(root)
+	assert.NoError(t, os.Remove(filepath.Join(root, "net", "tcp6")))
+	n := &NetstatPorts{Ports: []int{443}, Log: testutil.Logger{}, procRoot: root}
+	assert.NoError(t, n.Init())
+
+	var acc testutil.Accumulator
+	assert.NoError(t, acc.GatherError(n.Gather))
+	acc.AssertContainsTaggedFields(t, "netstat", fields(map[string]int{
+		"tcp_listen":      1,
+		"tcp_established": 1,
+		"tcp_close_wait":  1,
+		"tcp_time_wait":   1,
+	}), map[string]string{"port": "443"})
+}
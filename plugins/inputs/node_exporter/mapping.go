@@ -0,0 +1,340 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package node_exporter
+
+import (
+	"sort"
+	"strings"
+)
+
+const cpuTotal = "cpu-total"
+
+// the modes of node_cpu_seconds_total, their sum is the time of the cpu like the total of the cpu input
+var cpuModes = []string{"user", "system", "idle", "nice", "iowait", "irq", "softirq", "steal"}
+
+// the counters of the node_exporter are reported as the delta since the previous scrape, like the diskio and net
+// inputs report them with report_deltas
+var (
+	diskioCounters = []struct{ series, field string }{
+		{"node_disk_reads_completed_total", "reads"},
+		{"node_disk_writes_completed_total", "writes"},
+		{"node_disk_read_bytes_total", "read_bytes"},
+		{"node_disk_written_bytes_total", "write_bytes"},
+	}
+	// the times are in seconds in the node_exporter and in milliseconds in the diskio input
+	diskioTimes = []struct{ series, field string }{
+		{"node_disk_read_time_seconds_total", "read_time"},
+		{"node_disk_write_time_seconds_total", "write_time"},
+		{"node_disk_io_time_seconds_total", "io_time"},
+	}
+	netCounters = []struct{ series, field string }{
+		{"node_network_receive_bytes_total", "bytes_recv"},
+		{"node_network_transmit_bytes_total", "bytes_sent"},
+		{"node_network_receive_packets_total", "packets_recv"},
+		{"node_network_transmit_packets_total", "packets_sent"},
+		{"node_network_receive_errs_total", "err_in"},
+		{"node_network_transmit_errs_total", "err_out"},
+		{"node_network_receive_drop_total", "drop_in"},
+		{"node_network_transmit_drop_total", "drop_out"},
+	}
+)
+
+// mapped are the metrics the node_exporter metrics are mapped to.
+var mapped = toSet([]string{
+	"cpu_time_active", "cpu_time_guest", "cpu_time_guest_nice", "cpu_time_idle", "cpu_time_iowait", "cpu_time_irq", "cpu_time_nice",
+	"cpu_time_softirq", "cpu_time_steal", "cpu_time_system", "cpu_time_user",
+	"cpu_usage_active", "cpu_usage_guest", "cpu_usage_guest_nice", "cpu_usage_idle", "cpu_usage_iowait", "cpu_usage_irq", "cpu_usage_nice",
+	"cpu_usage_softirq", "cpu_usage_steal", "cpu_usage_system", "cpu_usage_user",
+	"mem_active", "mem_available", "mem_available_percent", "mem_buffered", "mem_cached", "mem_free", "mem_inactive", "mem_total",
+	"mem_used", "mem_used_percent",
+	"swap_free", "swap_used", "swap_used_percent",
+	"disk_free", "disk_inodes_free", "disk_inodes_total", "disk_inodes_used", "disk_total", "disk_used", "disk_used_percent",
+	"diskio_iops_in_progress", "diskio_io_time", "diskio_reads", "diskio_read_bytes", "diskio_read_time", "diskio_writes",
+	"diskio_write_bytes", "diskio_write_time",
+	"net_bytes_sent", "net_bytes_recv", "net_drop_in", "net_drop_out", "net_err_in", "net_err_out", "net_packets_sent", "net_packets_recv",
+	"netstat_tcp_established",
+	"processes_blocked", "processes_running",
+})
+
+type metric struct {
+	name   string
+	tags   map[string]string
+	fields map[string]interface{}
+}
+
+// converter maps the samples of a scrape to the metrics of the host inputs, with the same names and tags.
+type converter struct {
+	s      samples
+	perCPU bool
+	// the counters of the previous and the current scrape
+	last, counters map[string]float64
+	metrics        []metric
+}
+
+func convert(s samples, perCPU bool, last, counters map[string]float64) []metric {
+	c := &converter{s: s, perCPU: perCPU, last: last, counters: counters}
+	c.cpu()
+	c.mem()
+	c.swap()
+	c.disk()
+	c.diskio()
+	c.net()
+	c.gauges("netstat", map[string]string{"node_netstat_Tcp_CurrEstab": "tcp_established"})
+	c.gauges("processes", map[string]string{"node_procs_running": "running", "node_procs_blocked": "blocked"})
+	return c.metrics
+}
+
+func (c *converter) add(name string, tags map[string]string, fields map[string]interface{}) {
+	if len(fields) > 0 {
+		c.metrics = append(c.metrics, metric{name: name, tags: tags, fields: fields})
+	}
+}
+
+// delta returns the increase of the counter since the previous scrape, there is none on the first scrape and when
+// the counter was reset, e.g. the node_exporter restarted.
+func (c *converter) delta(key string, v float64) (float64, bool) {
+	c.counters[key] = v
+	last, ok := c.last[key]
+	if !ok || v < last {
+		return 0, false
+	}
+	return v - last, true
+}
+
+// value returns the value of the sample of a metric without labels.
+func (c *converter) value(name string) (float64, bool) {
+	if s := c.s[name]; len(s) > 0 {
+		return s[0].value, true
+	}
+	return 0, false
+}
+
+// byLabel returns the values of a metric by the value of a label.
+func (c *converter) byLabel(name string, label string) map[string]float64 {
+	values := map[string]float64{}
+	for _, s := range c.s[name] {
+		values[s.labels[label]] = s.value
+	}
+	return values
+}
+
+func (c *converter) cpu() {
+	// the seconds of each mode by cpu
+	times := map[string]map[string]float64{}
+	total := map[string]float64{}
+	add := func(series, mode, to string) {
+		for _, s := range c.s[series] {
+			if s.labels["mode"] != mode {
+				continue
+			}
+			cpu := "cpu" + s.labels["cpu"]
+			if times[cpu] == nil {
+				times[cpu] = map[string]float64{}
+			}
+			times[cpu][to] += s.value
+			total[to] += s.value
+		}
+	}
+	for _, mode := range cpuModes {
+		add("node_cpu_seconds_total", mode, mode)
+	}
+	if len(total) == 0 {
+		return
+	}
+	add("node_cpu_guest_seconds_total", "user", "guest")
+	add("node_cpu_guest_seconds_total", "nice", "guest_nice")
+
+	c.cpuMetric(cpuTotal, total)
+	if c.perCPU {
+		cpus := make([]string, 0, len(times))
+		for cpu := range times {
+			cpus = append(cpus, cpu)
+		}
+		sort.Strings(cpus)
+		for _, cpu := range cpus {
+			c.cpuMetric(cpu, times[cpu])
+		}
+	}
+}
+
+func (c *converter) cpuMetric(cpu string, times map[string]float64) {
+	var sum float64
+	for _, mode := range cpuModes {
+		sum += times[mode]
+	}
+	times["active"] = sum - times["idle"]
+
+	fields := map[string]interface{}{}
+	deltas := map[string]float64{}
+	complete := true
+	for mode, v := range times {
+		fields["time_"+mode] = v
+		d, ok := c.delta("cpu|"+cpu+"|"+mode, v)
+		deltas[mode] = d
+		complete = complete && ok
+	}
+	totalDelta, ok := c.delta("cpu|"+cpu+"|total", sum)
+	// the usage is the percent of the time spent in each mode since the previous scrape
+	if complete && ok && totalDelta > 0 {
+		for mode, d := range deltas {
+			fields["usage_"+mode] = 100 * d / totalDelta
+		}
+	}
+	c.add("cpu", map[string]string{"cpu": cpu}, fields)
+}
+
+func (c *converter) mem() {
+	total, ok := c.value("node_memory_MemTotal_bytes")
+	if !ok || total == 0 {
+		return
+	}
+	free, _ := c.value("node_memory_MemFree_bytes")
+	buffered, _ := c.value("node_memory_Buffers_bytes")
+	cached, _ := c.value("node_memory_Cached_bytes")
+	fields := map[string]interface{}{
+		"total":    total,
+		"free":     free,
+		"buffered": buffered,
+		"cached":   cached,
+		// like the mem input, the used memory excludes the buffers and the page cache
+		"used":         total - free - buffered - cached,
+		"used_percent": 100 * (total - free - buffered - cached) / total,
+	}
+	if available, ok := c.value("node_memory_MemAvailable_bytes"); ok {
+		fields["available"] = available
+		fields["available_percent"] = 100 * available / total
+	}
+	if active, ok := c.value("node_memory_Active_bytes"); ok {
+		fields["active"] = active
+	}
+	if inactive, ok := c.value("node_memory_Inactive_bytes"); ok {
+		fields["inactive"] = inactive
+	}
+	c.add("mem", map[string]string{}, fields)
+}
+
+func (c *converter) swap() {
+	total, ok := c.value("node_memory_SwapTotal_bytes")
+	if !ok {
+		return
+	}
+	free, _ := c.value("node_memory_SwapFree_bytes")
+	fields := map[string]interface{}{"free": free, "used": total - free}
+	if total > 0 {
+		fields["used_percent"] = 100 * (total - free) / total
+	} else {
+		fields["used_percent"] = float64(0)
+	}
+	c.add("swap", map[string]string{}, fields)
+}
+
+func (c *converter) disk() {
+	key := func(s sample) string {
+		return s.labels["device"] + "|" + s.labels["fstype"] + "|" + s.labels["mountpoint"]
+	}
+	values := func(name string) map[string]float64 {
+		v := map[string]float64{}
+		for _, s := range c.s[name] {
+			v[key(s)] = s.value
+		}
+		return v
+	}
+	free, avail := values("node_filesystem_free_bytes"), values("node_filesystem_avail_bytes")
+	files, filesFree := values("node_filesystem_files"), values("node_filesystem_files_free")
+	deviceError := values("node_filesystem_device_error")
+
+	for _, s := range c.s["node_filesystem_size_bytes"] {
+		k := key(s)
+		// the size of a filesystem that failed to stat is stale
+		if deviceError[k] == 1 {
+			continue
+		}
+		used := s.value - free[k]
+		fields := map[string]interface{}{"total": s.value, "free": avail[k], "used": used}
+		// like the disk input, the percent of the space available to the unprivileged users
+		if used+avail[k] > 0 {
+			fields["used_percent"] = 100 * used / (used + avail[k])
+		} else {
+			fields["used_percent"] = float64(0)
+		}
+		if total, ok := files[k]; ok {
+			fields["inodes_total"] = total
+			fields["inodes_free"] = filesFree[k]
+			fields["inodes_used"] = total - filesFree[k]
+		}
+		c.add("disk", map[string]string{
+			"device": strings.TrimPrefix(s.labels["device"], "/dev/"),
+			"fstype": s.labels["fstype"],
+			"path":   s.labels["mountpoint"],
+		}, fields)
+	}
+}
+
+func (c *converter) diskio() {
+	counters := c.byDevice(diskioCounters)
+	times := c.byDevice(diskioTimes)
+	inProgress := c.byLabel("node_disk_io_now", "device")
+	for _, device := range c.devices("node_disk_reads_completed_total") {
+		fields := map[string]interface{}{}
+		c.deltas(fields, "diskio|"+device+"|", counters, device, 1)
+		c.deltas(fields, "diskio|"+device+"|", times, device, 1000)
+		if v, ok := inProgress[device]; ok {
+			fields["iops_in_progress"] = v
+		}
+		c.add("diskio", map[string]string{"name": device}, fields)
+	}
+}
+
+func (c *converter) net() {
+	counters := c.byDevice(netCounters)
+	for _, device := range c.devices("node_network_receive_bytes_total") {
+		// the net input doesn't report the loopback interface
+		if device == "lo" {
+			continue
+		}
+		fields := map[string]interface{}{}
+		c.deltas(fields, "net|"+device+"|", counters, device, 1)
+		c.add("net", map[string]string{"interface": device}, fields)
+	}
+}
+
+// byDevice returns the values of the series by field and device.
+func (c *converter) byDevice(series []struct{ series, field string }) map[string]map[string]float64 {
+	values := make(map[string]map[string]float64, len(series))
+	for _, s := range series {
+		values[s.field] = c.byLabel(s.series, "device")
+	}
+	return values
+}
+
+// deltas sets the fields to the increase of the counters of the device, multiplied by scale.
+func (c *converter) deltas(fields map[string]interface{}, prefix string, counters map[string]map[string]float64, device string, scale float64) {
+	for field, values := range counters {
+		if v, ok := values[device]; ok {
+			if d, ok := c.delta(prefix+field, v*scale); ok {
+				fields[field] = d
+			}
+		}
+	}
+}
+
+// devices returns the sorted devices of the series.
+func (c *converter) devices(series string) []string {
+	devices := make([]string, 0, len(c.s[series]))
+	for device := range c.byLabel(series, "device") {
+		devices = append(devices, device)
+	}
+	sort.Strings(devices)
+	return devices
+}
+
+func (c *converter) gauges(name string, series map[string]string) {
+	fields := map[string]interface{}{}
+	for s, field := range series {
+		if v, ok := c.value(s); ok {
+			fields[field] = v
+		}
+	}
+	c.add(name, map[string]string{}, fields)
+}
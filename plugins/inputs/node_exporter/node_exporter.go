@@ -0,0 +1,187 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package node_exporter
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/aws/amazon-cloudwatch-agent/internal"
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/plugins/inputs"
+	"github.com/prometheus/prometheus/pkg/labels"
+	"github.com/prometheus/prometheus/pkg/textparse"
+)
+
+const (
+	defaultURL     = "http://localhost:9100/metrics"
+	defaultTimeout = 5 * time.Second
+	acceptHeader   = "text/plain;version=0.0.4;q=1,*/*;q=0.1"
+	metricWildcard = "*"
+)
+
+var sampleConfig = `
+  ## The metrics endpoint of the node_exporter.
+  # url = "http://localhost:9100/metrics"
+
+  ## The metrics published, named like the metrics of the cpu, mem, swap, disk, diskio, net, netstat and processes
+  ## inputs, e.g. "mem_used_percent". "*" publishes every metric of the mapping.
+  metrics = ["cpu_usage_idle", "mem_used_percent", "disk_used_percent"]
+  ## The metrics not published, to prune "*".
+  # metrics_exclude = []
+
+  ## Report the cpu metrics of each core besides cpu-total.
+  # percpu = false
+
+  ## Maximum time the scrape is allowed to take.
+  # timeout = "5s"
+`
+
+type NodeExporter struct {
+	URL            string            `toml:"url"`
+	Metrics        []string          `toml:"metrics"`
+	MetricsExclude []string          `toml:"metrics_exclude"`
+	PerCPU         bool              `toml:"percpu"`
+	Timeout        internal.Duration `toml:"timeout"`
+
+	Log telegraf.Logger `toml:"-"`
+
+	client  *http.Client
+	include map[string]bool
+	exclude map[string]bool
+	// the counters of the previous scrape, by metric and tags
+	last map[string]float64
+}
+
+func (n *NodeExporter) SampleConfig() string {
+	return sampleConfig
+}
+
+func (n *NodeExporter) Description() string {
+	return "Scrape a local node_exporter and publish its well known metrics under the names of the host metrics of the agent."
+}
+
+func (n *NodeExporter) Init() error {
+	if n.URL == "" {
+		n.URL = defaultURL
+	}
+	u, err := url.Parse(n.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("node_exporter: invalid url %q, an http or https url is expected", n.URL)
+	}
+	if len(n.Metrics) == 0 {
+		return fmt.Errorf("node_exporter: metrics is required")
+	}
+	if n.Timeout.Duration <= 0 {
+		n.Timeout.Duration = defaultTimeout
+	}
+	n.include = toSet(n.Metrics)
+	n.exclude = toSet(n.MetricsExclude)
+	for name := range n.include {
+		if name != metricWildcard && !mapped[name] {
+			n.Log.Warnf("Metric %s is not mapped from the node_exporter metrics", name)
+		}
+	}
+	n.client = &http.Client{Timeout: n.Timeout.Duration}
+	n.last = map[string]float64{}
+	return nil
+}
+
+func (n *NodeExporter) Gather(acc telegraf.Accumulator) error {
+	b, err := n.scrape()
+	if err != nil {
+		return err
+	}
+	s, err := parse(b)
+	if err != nil {
+		return fmt.Errorf("failed to parse the metrics of %s: %v", n.URL, err)
+	}
+	counters := map[string]float64{}
+	for _, m := range convert(s, n.PerCPU, n.last, counters) {
+		fields := map[string]interface{}{}
+		for k, v := range m.fields {
+			if n.wanted(m.name + "_" + k) {
+				fields[k] = v
+			}
+		}
+		if len(fields) > 0 {
+			acc.AddFields(m.name, fields, m.tags)
+		}
+	}
+	// the counters of the devices gone are dropped with the previous scrape
+	n.last = counters
+	return nil
+}
+
+func (n *NodeExporter) wanted(name string) bool {
+	return (n.include[metricWildcard] || n.include[name]) && !n.exclude[name]
+}
+
+func (n *NodeExporter) scrape() ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), n.Timeout.Duration)
+	defer cancel()
+	req, err := http.NewRequest(http.MethodGet, n.URL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", acceptHeader)
+	resp, err := n.client.Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to scrape %s: %v", n.URL, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to scrape %s, status: %s", n.URL, resp.Status)
+	}
+	return ioutil.ReadAll(resp.Body)
+}
+
+type sample struct {
+	labels map[string]string
+	value  float64
+}
+
+// samples are the samples of the scrape by metric name.
+type samples map[string][]sample
+
+func parse(b []byte) (samples, error) {
+	s := samples{}
+	p := textparse.NewPromParser(b)
+	for {
+		entry, err := p.Next()
+		if err == io.EOF {
+			return s, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if entry != textparse.EntrySeries {
+			continue
+		}
+		_, _, v := p.Series()
+		var lset labels.Labels
+		p.Metric(&lset)
+		name := lset.Get(labels.MetricName)
+		s[name] = append(s[name], sample{labels: lset.Map(), value: v})
+	}
+}
+
+func toSet(list []string) map[string]bool {
+	set := make(map[string]bool, len(list))
+	for _, s := range list {
+		set[s] = true
+	}
+	return set
+}
+
+func init() {
+	inputs.Add("node_exporter", func() telegraf.Input {
+		return &NodeExporter{}
+	})
+}
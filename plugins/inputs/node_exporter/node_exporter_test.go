@@ -0,0 +1,184 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package node_exporter
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/influxdata/telegraf/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+const firstScrape = `# HELP node_cpu_seconds_total Seconds the CPUs spent in each mode.
+# TYPE node_cpu_seconds_total counter
+node_cpu_seconds_total{cpu="0",mode="idle"} 100
+node_cpu_seconds_total{cpu="0",mode="user"} 20
+node_cpu_seconds_total{cpu="0",mode="system"} 10
+node_cpu_seconds_total{cpu="1",mode="idle"} 100
+node_cpu_seconds_total{cpu="1",mode="user"} 20
+node_cpu_seconds_total{cpu="1",mode="system"} 10
+# TYPE node_memory_MemTotal_bytes gauge
+node_memory_MemTotal_bytes 1000
+node_memory_MemFree_bytes 200
+node_memory_Buffers_bytes 50
+node_memory_Cached_bytes 150
+node_memory_MemAvailable_bytes 400
+node_memory_SwapTotal_bytes 0
+node_memory_SwapFree_bytes 0
+node_filesystem_size_bytes{device="/dev/nvme0n1p1",fstype="xfs",mountpoint="/"} 1000
+node_filesystem_free_bytes{device="/dev/nvme0n1p1",fstype="xfs",mountpoint="/"} 300
+node_filesystem_avail_bytes{device="/dev/nvme0n1p1",fstype="xfs",mountpoint="/"} 100
+node_filesystem_files{device="/dev/nvme0n1p1",fstype="xfs",mountpoint="/"} 64
+node_filesystem_files_free{device="/dev/nvme0n1p1",fstype="xfs",mountpoint="/"} 16
+node_filesystem_device_error{device="/dev/nvme0n1p1",fstype="xfs",mountpoint="/"} 0
+node_filesystem_size_bytes{device="nfs:/export",fstype="nfs4",mountpoint="/mnt"} 1000
+node_filesystem_device_error{device="nfs:/export",fstype="nfs4",mountpoint="/mnt"} 1
+node_disk_reads_completed_total{device="nvme0n1"} 10
+node_disk_read_time_seconds_total{device="nvme0n1"} 1.5
+node_disk_io_now{device="nvme0n1"} 3
+node_network_receive_bytes_total{device="eth0"} 1000
+node_network_transmit_bytes_total{device="eth0"} 500
+node_network_receive_bytes_total{device="lo"} 1000
+node_netstat_Tcp_CurrEstab 12
+node_procs_running 2
+node_procs_blocked 0
+node_load1 0.5
+`
+
+const secondScrape = `node_cpu_seconds_total{cpu="0",mode="idle"} 115
+node_cpu_seconds_total{cpu="0",mode="user"} 25
+node_cpu_seconds_total{cpu="0",mode="system"} 10
+node_cpu_seconds_total{cpu="1",mode="idle"} 105
+node_cpu_seconds_total{cpu="1",mode="user"} 30
+node_cpu_seconds_total{cpu="1",mode="system"} 15
+node_disk_reads_completed_total{device="nvme0n1"} 25
+node_disk_read_time_seconds_total{device="nvme0n1"} 2
+node_disk_io_now{device="nvme0n1"} 1
+node_network_receive_bytes_total{device="eth0"} 3000
+node_network_transmit_bytes_total{device="eth0"} 100
+`
+
+func serve(t *testing.T, scrapes ...string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/metrics", r.URL.Path)
+		if len(scrapes) == 0 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		fmt.Fprint(w, scrapes[0])
+		scrapes = scrapes[1:]
+	}))
+}
+
+func newNodeExporter(t *testing.T, url string, metrics ...string) *NodeExporter {
+	n := &NodeExporter{URL: url + "/metrics", Metrics: metrics, Log: testutil.Logger{}}
+	assert.NoError(t, n.Init())
+	return n
+}
+
+func TestGather(t *testing.T) {
+	ts := serve(t, firstScrape, secondScrape)
+	defer ts.Close()
+	n := newNodeExporter(t, ts.URL, "*")
+
+	var acc testutil.Accumulator
+	assert.NoError(t, acc.GatherError(n.Gather))
+	acc.AssertContainsTaggedFields(t, "mem", map[string]interface{}{
+		"total": float64(1000), "free": float64(200), "buffered": float64(50), "cached": float64(150),
+		"used": float64(600), "used_percent": float64(60), "available": float64(400), "available_percent": float64(40),
+	}, map[string]string{})
+	acc.AssertContainsTaggedFields(t, "swap", map[string]interface{}{
+		"free": float64(0), "used": float64(0), "used_percent": float64(0),
+	}, map[string]string{})
+	// the filesystems that failed to stat are skipped
+	acc.AssertContainsTaggedFields(t, "disk", map[string]interface{}{
+		"total": float64(1000), "free": float64(100), "used": float64(700), "used_percent": float64(87.5),
+		"inodes_total": float64(64), "inodes_free": float64(16), "inodes_used": float64(48),
+	}, map[string]string{"device": "nvme0n1p1", "fstype": "xfs", "path": "/"})
+	disks := 0
+	for _, m := range acc.Metrics {
+		if m.Measurement == "disk" {
+			disks++
+		}
+	}
+	assert.Equal(t, 1, disks)
+	// the counters have no delta on the first scrape
+	acc.AssertContainsTaggedFields(t, "cpu", map[string]interface{}{
+		"time_idle": float64(200), "time_user": float64(40), "time_system": float64(20), "time_active": float64(60),
+	}, map[string]string{"cpu": "cpu-total"})
+	acc.AssertContainsTaggedFields(t, "diskio", map[string]interface{}{"iops_in_progress": float64(3)},
+		map[string]string{"name": "nvme0n1"})
+	assert.False(t, acc.HasMeasurement("net"))
+	acc.AssertContainsFields(t, "netstat", map[string]interface{}{"tcp_established": float64(12)})
+	acc.AssertContainsFields(t, "processes", map[string]interface{}{"running": float64(2), "blocked": float64(0)})
+
+	acc.ClearMetrics()
+	assert.NoError(t, acc.GatherError(n.Gather))
+	// 40 seconds elapsed on the two cpus, 20 idle, 15 user and 5 system
+	acc.AssertContainsTaggedFields(t, "cpu", map[string]interface{}{
+		"time_idle": float64(220), "time_user": float64(55), "time_system": float64(25), "time_active": float64(80),
+		"usage_idle": float64(50), "usage_user": float64(37.5), "usage_system": float64(12.5), "usage_active": float64(50),
+	}, map[string]string{"cpu": "cpu-total"})
+	acc.AssertContainsTaggedFields(t, "diskio", map[string]interface{}{
+		"reads": float64(15), "read_time": float64(500), "iops_in_progress": float64(1),
+	}, map[string]string{"name": "nvme0n1"})
+	// the counter reset of bytes_sent is skipped, the loopback interface isn't reported
+	acc.AssertContainsTaggedFields(t, "net", map[string]interface{}{"bytes_recv": float64(2000)},
+		map[string]string{"interface": "eth0"})
+	assert.Equal(t, 3, len(acc.Metrics))
+}
+
+func TestGatherMetrics(t *testing.T) {
+	ts := serve(t, firstScrape)
+	defer ts.Close()
+	n := newNodeExporter(t, ts.URL, "mem_used_percent", "disk_used_percent", "swap_used_percent")
+	n.MetricsExclude = []string{"swap_used_percent"}
+	assert.NoError(t, n.Init())
+
+	var acc testutil.Accumulator
+	assert.NoError(t, acc.GatherError(n.Gather))
+	assert.Equal(t, 2, len(acc.Metrics))
+	acc.AssertContainsTaggedFields(t, "mem", map[string]interface{}{"used_percent": float64(60)}, map[string]string{})
+	acc.AssertContainsTaggedFields(t, "disk", map[string]interface{}{"used_percent": float64(87.5)},
+		map[string]string{"device": "nvme0n1p1", "fstype": "xfs", "path": "/"})
+}
+
+func TestGatherPerCPU(t *testing.T) {
+	ts := serve(t, firstScrape, secondScrape)
+	defer ts.Close()
+	n := newNodeExporter(t, ts.URL, "cpu_usage_idle")
+	n.PerCPU = true
+
+	var acc testutil.Accumulator
+	assert.NoError(t, acc.GatherError(n.Gather))
+	assert.Equal(t, 0, len(acc.Metrics))
+	assert.NoError(t, acc.GatherError(n.Gather))
+	acc.AssertContainsTaggedFields(t, "cpu", map[string]interface{}{"usage_idle": float64(50)}, map[string]string{"cpu": "cpu-total"})
+	acc.AssertContainsTaggedFields(t, "cpu", map[string]interface{}{"usage_idle": float64(75)}, map[string]string{"cpu": "cpu0"})
+	acc.AssertContainsTaggedFields(t, "cpu", map[string]interface{}{"usage_idle": float64(25)}, map[string]string{"cpu": "cpu1"})
+}
+
+func TestGatherErrors(t *testing.T) {
+	ts := serve(t, "node_load1{ 1\n")
+	defer ts.Close()
+	n := newNodeExporter(t, ts.URL, "*")
+
+	var acc testutil.Accumulator
+	assert.Error(t, acc.GatherError(n.Gather))
+	// the server has no more scrapes
+	assert.Error(t, acc.GatherError(n.Gather))
+}
+
+func TestInit(t *testing.T) {
+	n := &NodeExporter{Metrics: []string{"mem_used_percent"}, Log: testutil.Logger{}}
+	assert.NoError(t, n.Init())
+	assert.Equal(t, defaultURL, n.URL)
+	assert.Equal(t, defaultTimeout, n.Timeout.Duration)
+
+	assert.Error(t, (&NodeExporter{Log: testutil.Logger{}}).Init())
+	assert.Error(t, (&NodeExporter{URL: "unix:///var/run/node_exporter.sock", Metrics: []string{"*"}, Log: testutil.Logger{}}).Init())
+}
@@ -0,0 +1,174 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package numa
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/plugins/inputs"
+)
+
+const (
+	// the fields extend the mem measurement, e.g. mem_numa_free
+	measurement = "mem"
+	nodeTag     = "numa_node"
+	defaultRoot = "/sys/devices/system/node"
+	nodePrefix  = "node"
+)
+
+// numastatFields maps the counters of numastat to their field, the pages allocated on the node by where they were
+// meant to be allocated.
+var numastatFields = map[string]string{
+	"numa_hit":       "numa_hit",
+	"numa_miss":      "numa_miss",
+	"numa_foreign":   "numa_foreign",
+	"interleave_hit": "numa_interleave_hit",
+	"local_node":     "numa_local",
+	"other_node":     "numa_other",
+}
+
+var sampleConfig = `
+  ## No configuration, the memory and the allocation counters of every NUMA node are reported.
+`
+
+type Numa struct {
+	Log telegraf.Logger `toml:"-"`
+
+	// the sysfs directory of the nodes, replaced in tests
+	root string
+}
+
+func (n *Numa) SampleConfig() string {
+	return sampleConfig
+}
+
+func (n *Numa) Description() string {
+	return "Report the free and used memory and the allocation hits and misses of each NUMA node."
+}
+
+func (n *Numa) Init() error {
+	if n.root == "" {
+		n.root = defaultRoot
+	}
+	return nil
+}
+
+func (n *Numa) Gather(acc telegraf.Accumulator) error {
+	nodes, err := n.nodes()
+	if err != nil {
+		return err
+	}
+	for _, node := range nodes {
+		dir := filepath.Join(n.root, nodePrefix+node)
+		fields := map[string]interface{}{}
+		if err := readMeminfo(filepath.Join(dir, "meminfo"), fields); err != nil {
+			acc.AddError(fmt.Errorf("failed to read the memory of numa node %s: %v", node, err))
+			continue
+		}
+		if err := readNumastat(filepath.Join(dir, "numastat"), fields); err != nil {
+			acc.AddError(fmt.Errorf("failed to read the numastat of numa node %s: %v", node, err))
+			continue
+		}
+		acc.AddFields(measurement, fields, map[string]string{nodeTag: node})
+	}
+	return nil
+}
+
+// nodes returns the ids of the online nodes in numeric order, a host without NUMA has the single node 0.
+func (n *Numa) nodes() ([]string, error) {
+	matches, err := filepath.Glob(filepath.Join(n.root, nodePrefix+"[0-9]*"))
+	if err != nil {
+		return nil, err
+	}
+	if len(matches) == 0 {
+		return nil, fmt.Errorf("no numa node found in %s", n.root)
+	}
+	ids := make([]int, 0, len(matches))
+	for _, m := range matches {
+		if id, err := strconv.Atoi(strings.TrimPrefix(filepath.Base(m), nodePrefix)); err == nil {
+			ids = append(ids, id)
+		}
+	}
+	sort.Ints(ids)
+	nodes := make([]string, len(ids))
+	for i, id := range ids {
+		nodes[i] = strconv.Itoa(id)
+	}
+	return nodes, nil
+}
+
+// readMeminfo reads the memory of the node, the lines are like "Node 0 MemFree:  1024 kB".
+func readMeminfo(path string, fields map[string]interface{}) error {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	values := map[string]uint64{}
+	s := bufio.NewScanner(bytes.NewReader(b))
+	for s.Scan() {
+		f := strings.Fields(s.Text())
+		if len(f) < 4 {
+			continue
+		}
+		v, err := strconv.ParseUint(f[3], 10, 64)
+		if err != nil {
+			continue
+		}
+		if len(f) > 4 && f[4] == "kB" {
+			v *= 1024
+		}
+		values[strings.TrimSuffix(f[2], ":")] = v
+	}
+	total, ok := values["MemTotal"]
+	if !ok {
+		return fmt.Errorf("no MemTotal in %s", path)
+	}
+	free := values["MemFree"]
+	fields["numa_total"] = total
+	fields["numa_free"] = free
+	fields["numa_used"] = total - free
+	if total > 0 {
+		fields["numa_used_percent"] = 100 * float64(total-free) / float64(total)
+	}
+	return nil
+}
+
+// readNumastat reads the allocation counters of the node, they are in pages.
+func readNumastat(path string, fields map[string]interface{}) error {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	s := bufio.NewScanner(bytes.NewReader(b))
+	for s.Scan() {
+		f := strings.Fields(s.Text())
+		if len(f) != 2 {
+			continue
+		}
+		field, ok := numastatFields[f[0]]
+		if !ok {
+			continue
+		}
+		v, err := strconv.ParseUint(f[1], 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid %s in %s: %v", f[0], path, err)
+		}
+		fields[field] = v
+	}
+	return nil
+}
+
+func init() {
+	inputs.Add("numa", func() telegraf.Input {
+		return &Numa{}
+	})
+}
@@ -0,0 +1,97 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package numa
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/influxdata/telegraf/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func writeFiles(t *testing.T, root string, files map[string]string) {
+	for name, content := range files {
+		path := filepath.Join(root, name)
+		assert.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		assert.NoError(t, ioutil.WriteFile(path, []byte(content), 0644))
+	}
+}
+
+func newNuma(t *testing.T, root string) *Numa {
+	n := &Numa{Log: testutil.Logger{}, root: root}
+	assert.NoError(t, n.Init())
+	return n
+}
+
+func TestGather(t *testing.T) {
+	root, err := ioutil.TempDir("", "numa")
+	assert.NoError(t, err)
+	defer os.RemoveAll(root)
+	writeFiles(t, root, map[string]string{
+		"online":         "0-1,10\n",
+		"node0/meminfo":  "Node 0 MemTotal:       4096 kB\nNode 0 MemFree:        1024 kB\nNode 0 MemUsed:        3072 kB\nNode 0 HugePages_Total:     0\n",
+		"node0/numastat": "numa_hit 1000\nnuma_miss 10\nnuma_foreign 5\ninterleave_hit 2\nlocal_node 990\nother_node 20\n",
+		"node1/meminfo":  "Node 1 MemTotal:       4096 kB\nNode 1 MemFree:        4096 kB\n",
+		"node1/numastat": "numa_hit 7\nnuma_miss 0\n",
+		"node10/meminfo": "Node 10 MemTotal:       0 kB\nNode 10 MemFree:        0 kB\n",
+		// the node10 numastat is missing
+	})
+	n := newNuma(t, root)
+
+	var acc testutil.Accumulator
+	assert.Error(t, acc.GatherError(n.Gather))
+	acc.AssertContainsTaggedFields(t, "mem", map[string]interface{}{
+		"numa_total":          uint64(4194304),
+		"numa_free":           uint64(1048576),
+		"numa_used":           uint64(3145728),
+		"numa_used_percent":   float64(75),
+		"numa_hit":            uint64(1000),
+		"numa_miss":           uint64(10),
+		"numa_foreign":        uint64(5),
+		"numa_interleave_hit": uint64(2),
+		"numa_local":          uint64(990),
+		"numa_other":          uint64(20),
+	}, map[string]string{"numa_node": "0"})
+	acc.AssertContainsTaggedFields(t, "mem", map[string]interface{}{
+		"numa_total":        uint64(4194304),
+		"numa_free":         uint64(4194304),
+		"numa_used":         uint64(0),
+		"numa_used_percent": float64(0),
+		"numa_hit":          uint64(7),
+		"numa_miss":         uint64(0),
+	}, map[string]string{"numa_node": "1"})
+	assert.Equal(t, 2, len(acc.Metrics))
+	assert.Equal(t, 1, len(acc.Errors))
+}
+
+func TestGatherErrors(t *testing.T) {
+	root, err := ioutil.TempDir("", "numa")
+	assert.NoError(t, err)
+	defer os.RemoveAll(root)
+
+	// no node
+	var acc testutil.Accumulator
+	assert.Error(t, acc.GatherError(newNuma(t, root).Gather))
+
+	writeFiles(t, root, map[string]string{
+		"node0/meminfo":  "Node 0 MemFree:        1024 kB\n",
+		"node0/numastat": "numa_hit 1000\n",
+		"node1/meminfo":  "Node 1 MemTotal:       4096 kB\n",
+		"node1/numastat": "numa_hit -1\n",
+	})
+	acc.ClearMetrics()
+	acc.Errors = nil
+	assert.NoError(t, newNuma(t, root).Gather(&acc))
+	assert.Equal(t, 0, len(acc.Metrics))
+	assert.Equal(t, 2, len(acc.Errors))
+}
+
+func TestInit(t *testing.T) {
+	n := &Numa{Log: testutil.Logger{}}
+	assert.NoError(t, n.Init())
+	assert.Equal(t, defaultRoot, n.root)
+}
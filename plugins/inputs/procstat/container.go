@@ -0,0 +1,171 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package procstat
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultContainerEndpoint = "unix:///var/run/docker.sock"
+	containerTimeout         = 5 * time.Second
+
+	containerNamePatternTag = "container_name_pattern"
+	containerNameTag        = "container_name"
+	containerIDTag          = "container_id"
+	// the length of the short container ids of docker
+	shortContainerIDLength = 12
+)
+
+// the id of a container in the cgroup of its processes, e.g. /docker/<id>, docker-<id>.scope or
+// cri-containerd-<id>.scope
+var containerIDPattern = regexp.MustCompile(`[0-9a-f]{64}`)
+
+// container is the part of a container of the Docker Engine API matching the processes.
+type container struct {
+	ID     string            `json:"Id"`
+	Names  []string          `json:"Names"`
+	Labels map[string]string `json:"Labels"`
+}
+
+// name is the name of the container without the leading slash of the API.
+func (c container) name() string {
+	if len(c.Names) == 0 {
+		return ""
+	}
+	return strings.TrimPrefix(c.Names[0], "/")
+}
+
+// containerLister lists the running containers of the runtime.
+type containerLister interface {
+	containers() ([]container, error)
+}
+
+// dockerClient lists the containers with the Docker Engine API, on a unix socket or a tcp address.
+type dockerClient struct {
+	baseURL    string
+	httpClient *http.Client
+}
+
+func newDockerClient(endpoint string) (*dockerClient, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid container_endpoint %q: %v", endpoint, err)
+	}
+	transport := &http.Transport{}
+	switch u.Scheme {
+	case "unix":
+		socket := u.Path
+		transport.DialContext = func(ctx context.Context, _, _ string) (net.Conn, error) {
+			var d net.Dialer
+			return d.DialContext(ctx, "unix", socket)
+		}
+		return &dockerClient{baseURL: "http://docker", httpClient: &http.Client{Transport: transport, Timeout: containerTimeout}}, nil
+	case "tcp", "http":
+		return &dockerClient{baseURL: "http://" + u.Host, httpClient: &http.Client{Transport: transport, Timeout: containerTimeout}}, nil
+	}
+	return nil, fmt.Errorf("invalid container_endpoint %q, the scheme must be unix or tcp", endpoint)
+}
+
+func (c *dockerClient) containers() ([]container, error) {
+	resp, err := c.httpClient.Get(c.baseURL + "/containers/json")
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 512))
+		return nil, fmt.Errorf("GET /containers/json returned %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+	var containers []container
+	err = json.NewDecoder(resp.Body).Decode(&containers)
+	return containers, err
+}
+
+// filtersContainers returns whether the processes are narrowed to the ones of the matching containers.
+func (p *Procstat) filtersContainers() bool {
+	return p.containerName != nil || len(p.ContainerLabels) > 0
+}
+
+// listContainers lists the running containers at the start of the gather, no process matches when they are not
+// listed.
+func (p *Procstat) listContainers() error {
+	p.runningContainers = map[string]container{}
+	containers, err := p.containerLister.containers()
+	if err != nil {
+		return fmt.Errorf("procstat: failed to list the containers: %v", err)
+	}
+	for _, c := range containers {
+		p.runningContainers[c.ID] = c
+	}
+	return nil
+}
+
+// matchContainer returns the container of the process when it matches container_name_pattern and all the
+// container_labels. The container of the process is found from its cgroup, the processes out of a container don't
+// match.
+func (p *Procstat) matchContainer(key startedProcess) (container, bool) {
+	id, ok := p.lastProcessContainers[key]
+	if !ok {
+		id = p.readContainerID(key.pid)
+	}
+	p.processContainers[key] = id
+	c, ok := p.runningContainers[id]
+	if id == "" || !ok {
+		return container{}, false
+	}
+	if p.containerName != nil && !p.containerName.MatchString(c.name()) {
+		return container{}, false
+	}
+	for k, v := range p.ContainerLabels {
+		if label, ok := c.Labels[k]; !ok || label != v {
+			return container{}, false
+		}
+	}
+	return c, true
+}
+
+// readContainerID reads the id of the container of the process from /proc/<pid>/cgroup, the last id of the file
+// since the cgroups of a nested container contain the id of the outer one first.
+func (p *Procstat) readContainerID(pid int) string {
+	b, err := ioutil.ReadFile(filepath.Join(p.procRoot, strconv.Itoa(pid), "cgroup"))
+	if err != nil {
+		return ""
+	}
+	ids := containerIDPattern.FindAllString(string(b), -1)
+	if len(ids) == 0 {
+		return ""
+	}
+	return ids[len(ids)-1]
+}
+
+// addContainerTags tags a copy of the tags with the name of the container of the process, and its short id when
+// container_id_tag is set.
+func (p *Procstat) addContainerTags(c container, tags map[string]string) map[string]string {
+	copied := make(map[string]string, len(tags)+2)
+	for k, v := range tags {
+		copied[k] = v
+	}
+	copied[containerNameTag] = c.name()
+	if p.ContainerIDTag {
+		id := c.ID
+		if len(id) > shortContainerIDLength {
+			id = id[:shortContainerIDLength]
+		}
+		copied[containerIDTag] = id
+	}
+	return copied
+}
@@ -0,0 +1,491 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package procstat
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"hash/fnv"
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/plugins/inputs"
+	telegrafProcstat "github.com/influxdata/telegraf/plugins/inputs/procstat"
+	"github.com/shirou/gopsutil/process"
+)
+
+const (
+	measurement       = "procstat"
+	lookupMeasurement = "procstat_lookup"
+	defaultProcRoot   = "/proc"
+	environmentTag    = "environment_pattern"
+)
+
+// the lines of /proc/<pid>/status breaking down the resident memory, in kB
+var memoryStatusFields = map[string]string{
+	"RssAnon":  "memory_rss_anon",
+	"RssFile":  "memory_rss_file",
+	"RssShmem": "memory_rss_shmem",
+}
+
+var sampleConfig = (&telegrafProcstat.Procstat{}).SampleConfig() + `
+  ## The uptime of the processes is reported from their start time, in seconds, and the restart_count field of
+  ## procstat_lookup counts the processes started since the previous collection, from the second one. The alive
+  ## field of procstat_lookup is 1 when a process matches and 0 when none does, reported at every collection.
+
+  ## On Linux, the resident memory of the processes is also broken down into its anonymous, file backed and shared
+  ## memory, the memory_rss_anon, memory_rss_file and memory_rss_shmem fields.
+
+  ## On Windows, the handles, the GDI and USER objects and the peak working set of the processes are also reported,
+  ## the num_handles, num_gdi_objects, num_user_objects and memory_peak_working_set fields.
+
+  ## Tag the metrics with the path of the executable of the process, a hash of its command line, or a hash of the
+  ## pattern, instead of the pid or the full command line when many instances of a process run.
+  # exe_path_tag = false
+  # cmdline_hash_tag = false
+  # pattern_hash_tag = false
+
+  ## On Linux, only report the processes of the selector above with an environment variable matching the regular
+  ## expression, e.g. the interpreters of an application, the variables are matched as NAME=value.
+  # environment_pattern = "^APP_NAME=payments$"
+
+  ## On Linux, only report the processes of the selector above running in a container whose name matches the regular
+  ## expression and with all the labels, the containers are listed with the Docker Engine API of container_endpoint.
+  ## The metrics are tagged with the name of the container, and its short id when container_id_tag is set.
+  # container_name_pattern = "^payments-"
+  # container_endpoint = "unix:///var/run/docker.sock"
+  # container_id_tag = false
+  # [inputs.procstat.container_labels]
+  #   "com.example.team" = "payments"
+`
+
+// Procstat reports the procstat metrics of telegraf, the cpu, the memory, the file descriptors, the threads and the
+// context switches of the matching processes, and the breakdown of their resident memory, so the leaks of a
+// process are found without a profile of it.
+type Procstat struct {
+	PidFinder   string `toml:"pid_finder"`
+	PidFile     string `toml:"pid_file"`
+	Exe         string `toml:"exe"`
+	Pattern     string `toml:"pattern"`
+	Prefix      string `toml:"prefix"`
+	CmdLineTag  bool   `toml:"cmdline_tag"`
+	ProcessName string `toml:"process_name"`
+	User        string `toml:"user"`
+	SystemdUnit string `toml:"systemd_unit"`
+	CGroup      string `toml:"cgroup"`
+	PidTag      bool   `toml:"pid_tag"`
+	WinService  string `toml:"win_service"`
+
+	ExePathTag     bool `toml:"exe_path_tag"`
+	CmdLineHashTag bool `toml:"cmdline_hash_tag"`
+	PatternHashTag bool `toml:"pattern_hash_tag"`
+
+	EnvironmentPattern string `toml:"environment_pattern"`
+
+	ContainerNamePattern string            `toml:"container_name_pattern"`
+	ContainerLabels      map[string]string `toml:"container_labels"`
+	ContainerEndpoint    string            `toml:"container_endpoint"`
+	ContainerIDTag       bool              `toml:"container_id_tag"`
+
+	// the procfs mount, the lookup of the processes, the clock, the containers and the Windows resources of the
+	// processes, replaced in tests
+	procRoot        string
+	newProcess      func(pid int) (processInfo, error)
+	now             func() time.Time
+	procstat        telegraf.Input
+	containerLister containerLister
+	readResources   func(pid int) (processResources, error)
+
+	environment *regexp.Regexp
+	// whether the environment of the processes matches, by pid and start time, the environment of a process
+	// doesn't change once it started
+	lastEnvironment map[startedProcess]bool
+	environments    map[startedProcess]bool
+	matched         int
+
+	containerName *regexp.Regexp
+	// the running containers by id, and the container ids of the processes by pid and start time, empty out of a
+	// container
+	runningContainers     map[string]container
+	lastProcessContainers map[startedProcess]string
+	processContainers     map[startedProcess]string
+
+	// the processes matched by the previous and the current gather, by pid and start time since the pids are
+	// reused, nil before the first gather
+	lastStarted map[startedProcess]bool
+	started     map[startedProcess]bool
+}
+
+type startedProcess struct {
+	pid       int
+	createdAt int64
+}
+
+// processResources are the Windows resources of a process, their leaks don't show in its memory.
+type processResources struct {
+	handles        uint32
+	gdiObjects     uint32
+	userObjects    uint32
+	peakWorkingSet uint64
+}
+
+// processInfo is the part of a gopsutil process tagging the metrics.
+type processInfo interface {
+	Exe() (string, error)
+	Cmdline() (string, error)
+}
+
+func (p *Procstat) SampleConfig() string {
+	return sampleConfig
+}
+
+func (p *Procstat) Description() string {
+	return "Monitor process cpu, memory, file descriptors, handles, threads and context switches."
+}
+
+func (p *Procstat) Init() error {
+	if p.procRoot == "" {
+		p.procRoot = defaultProcRoot
+	}
+	if p.newProcess == nil {
+		p.newProcess = func(pid int) (processInfo, error) {
+			return process.NewProcess(int32(pid))
+		}
+	}
+	if p.now == nil {
+		p.now = time.Now
+	}
+	if p.readResources == nil {
+		p.readResources = readProcessResources
+	}
+	if p.EnvironmentPattern != "" {
+		environment, err := regexp.Compile(p.EnvironmentPattern)
+		if err != nil {
+			return fmt.Errorf("procstat: environment_pattern %q is invalid: %v", p.EnvironmentPattern, err)
+		}
+		p.environment = environment
+	}
+	if p.ContainerNamePattern != "" {
+		containerName, err := regexp.Compile(p.ContainerNamePattern)
+		if err != nil {
+			return fmt.Errorf("procstat: container_name_pattern %q is invalid: %v", p.ContainerNamePattern, err)
+		}
+		p.containerName = containerName
+	}
+	if p.filtersContainers() && p.containerLister == nil {
+		endpoint := p.ContainerEndpoint
+		if endpoint == "" {
+			endpoint = defaultContainerEndpoint
+		}
+		client, err := newDockerClient(endpoint)
+		if err != nil {
+			return fmt.Errorf("procstat: %v", err)
+		}
+		p.containerLister = client
+	}
+	if p.procstat == nil {
+		p.procstat = &telegrafProcstat.Procstat{
+			PidFinder:   p.PidFinder,
+			PidFile:     p.PidFile,
+			Exe:         p.Exe,
+			Pattern:     p.Pattern,
+			Prefix:      p.Prefix,
+			CmdLineTag:  p.CmdLineTag,
+			ProcessName: p.ProcessName,
+			User:        p.User,
+			SystemdUnit: p.SystemdUnit,
+			CGroup:      p.CGroup,
+			PidTag:      p.PidTag,
+			WinService:  p.WinService,
+		}
+	}
+	return nil
+}
+
+func (p *Procstat) Gather(acc telegraf.Accumulator) error {
+	p.started = map[startedProcess]bool{}
+	p.environments = map[startedProcess]bool{}
+	p.processContainers = map[startedProcess]string{}
+	p.matched = 0
+	if p.filtersContainers() {
+		if err := p.listContainers(); err != nil {
+			acc.AddError(err)
+		}
+	}
+	err := p.procstat.Gather(&accumulator{Accumulator: acc, p: p})
+	p.lastEnvironment = p.environments
+	p.lastProcessContainers = p.processContainers
+	return err
+}
+
+// accumulator adds the fields of the agent to the procstat metrics of telegraf.
+type accumulator struct {
+	telegraf.Accumulator
+	p *Procstat
+}
+
+func (a *accumulator) AddFields(name string, fields map[string]interface{}, tags map[string]string, t ...time.Time) {
+	switch name {
+	case measurement:
+		if pid, ok := a.p.pid(fields, tags); ok {
+			key := a.p.startedProcess(pid, fields)
+			if !a.p.matchEnvironment(key) {
+				return
+			}
+			if a.p.filtersContainers() {
+				c, ok := a.p.matchContainer(key)
+				if !ok {
+					return
+				}
+				tags = a.p.addContainerTags(c, tags)
+			}
+			a.p.matched++
+			a.p.addMemory(pid, fields)
+			a.p.addResources(pid, fields)
+			a.p.addUptime(pid, fields)
+			tags = a.p.addTags(pid, tags)
+		}
+		tags = a.p.addSelectorTags(tags)
+	case lookupMeasurement:
+		a.p.countMatched(fields)
+		addAlive(fields)
+		a.p.addRestartCount(fields)
+		tags = a.p.addSelectorTags(tags)
+	}
+	a.Accumulator.AddFields(name, fields, tags, t...)
+}
+
+// pid returns the pid of a procstat metric, a field unless pid_tag is set.
+func (p *Procstat) pid(fields map[string]interface{}, tags map[string]string) (int, bool) {
+	if pid, ok := fields["pid"].(int32); ok {
+		return int(pid), true
+	}
+	if pid, err := strconv.Atoi(tags["pid"]); err == nil {
+		return pid, true
+	}
+	return 0, false
+}
+
+// startedProcess returns the key of the process of a procstat metric, by pid and start time.
+func (p *Procstat) startedProcess(pid int, fields map[string]interface{}) startedProcess {
+	prefix := ""
+	if p.Prefix != "" {
+		prefix = p.Prefix + "_"
+	}
+	createdAt, _ := fields[prefix+"created_at"].(int64)
+	return startedProcess{pid: pid, createdAt: createdAt}
+}
+
+// matchEnvironment returns whether an environment variable of the process matches environment_pattern, always
+// true without it. The environment is read from procfs, a process whose environment isn't readable, e.g. a process
+// of another user when the agent isn't root, doesn't match.
+func (p *Procstat) matchEnvironment(key startedProcess) bool {
+	if p.environment == nil {
+		return true
+	}
+	matched, ok := p.lastEnvironment[key]
+	if !ok {
+		matched = p.readEnvironment(key.pid)
+	}
+	p.environments[key] = matched
+	return matched
+}
+
+// readEnvironment matches the variables of /proc/<pid>/environ, separated by NUL bytes.
+func (p *Procstat) readEnvironment(pid int) bool {
+	b, err := ioutil.ReadFile(filepath.Join(p.procRoot, strconv.Itoa(pid), "environ"))
+	if err != nil {
+		return false
+	}
+	for _, variable := range bytes.Split(b, []byte{0}) {
+		if len(variable) > 0 && p.environment.Match(variable) {
+			return true
+		}
+	}
+	return false
+}
+
+// countMatched replaces the processes found by the selector with the ones matching environment_pattern and the
+// container options, when the lookup succeeded.
+func (p *Procstat) countMatched(fields map[string]interface{}) {
+	if (p.environment == nil && !p.filtersContainers()) || fields["result_code"] != 0 {
+		return
+	}
+	fields["pid_count"] = p.matched
+	fields["running"] = p.matched
+}
+
+// addAlive adds whether a process matches, a gauge reported even when no process is found or the lookup failed, so
+// an alarm on a process down doesn't rely on the treatment of the missing data.
+func addAlive(fields map[string]interface{}) {
+	alive := 0
+	if count, ok := fields["pid_count"].(int); ok && count > 0 {
+		alive = 1
+	}
+	fields["alive"] = alive
+}
+
+// addSelectorTags tags a copy of the tags with environment_pattern and container_name_pattern, like the selectors
+// tag their metrics.
+func (p *Procstat) addSelectorTags(tags map[string]string) map[string]string {
+	if p.environment == nil && p.containerName == nil {
+		return tags
+	}
+	copied := make(map[string]string, len(tags)+2)
+	for k, v := range tags {
+		copied[k] = v
+	}
+	if p.environment != nil {
+		copied[environmentTag] = p.EnvironmentPattern
+	}
+	if p.containerName != nil {
+		copied[containerNamePatternTag] = p.ContainerNamePattern
+	}
+	return copied
+}
+
+// addMemory adds the breakdown of the resident memory of the process from its status, nothing is added when the
+// status isn't readable, e.g. on the platforms without procfs or when the process exited.
+func (p *Procstat) addMemory(pid int, fields map[string]interface{}) {
+	b, err := ioutil.ReadFile(filepath.Join(p.procRoot, strconv.Itoa(pid), "status"))
+	if err != nil {
+		return
+	}
+	prefix := ""
+	if p.Prefix != "" {
+		prefix = p.Prefix + "_"
+	}
+	scanner := bufio.NewScanner(bytes.NewReader(b))
+	for scanner.Scan() {
+		// e.g. "RssAnon:	    2048 kB"
+		key, value, ok := parseStatusLine(scanner.Text())
+		if !ok {
+			continue
+		}
+		if field, ok := memoryStatusFields[key]; ok {
+			fields[prefix+field] = value
+		}
+	}
+}
+
+// addResources adds the handles, the GDI and USER objects and the peak working set of the process, the leak
+// indicators of the Windows services. Nothing is added on the other platforms or when the process isn't readable,
+// e.g. a protected process.
+func (p *Procstat) addResources(pid int, fields map[string]interface{}) {
+	r, err := p.readResources(pid)
+	if err != nil {
+		return
+	}
+	prefix := ""
+	if p.Prefix != "" {
+		prefix = p.Prefix + "_"
+	}
+	fields[prefix+"num_handles"] = r.handles
+	fields[prefix+"num_gdi_objects"] = r.gdiObjects
+	fields[prefix+"num_user_objects"] = r.userObjects
+	fields[prefix+"memory_peak_working_set"] = r.peakWorkingSet
+}
+
+// addUptime adds the time since the process started from the created_at field of telegraf, in ns since the epoch,
+// and records the process for the restart count.
+func (p *Procstat) addUptime(pid int, fields map[string]interface{}) {
+	prefix := ""
+	if p.Prefix != "" {
+		prefix = p.Prefix + "_"
+	}
+	createdAt, ok := fields[prefix+"created_at"].(int64)
+	if !ok {
+		return
+	}
+	p.started[startedProcess{pid: pid, createdAt: createdAt}] = true
+	if uptime := p.now().Sub(time.Unix(0, createdAt)).Seconds(); uptime >= 0 {
+		fields[prefix+"uptime"] = uptime
+	}
+}
+
+// addRestartCount adds the number of processes started since the previous gather, the processes restarted by a
+// supervisor or a crash loop show up as new processes even when the number of processes doesn't change. Nothing is
+// added by the first gather, the processes running when the agent starts are not restarts.
+func (p *Procstat) addRestartCount(fields map[string]interface{}) {
+	if p.lastStarted != nil {
+		restarts := 0
+		for started := range p.started {
+			if !p.lastStarted[started] {
+				restarts++
+			}
+		}
+		fields["restart_count"] = restarts
+	}
+	p.lastStarted = p.started
+}
+
+// addTags adds the exe_path, cmdline_hash and pattern_hash tags when they are set, to a copy of the tags of
+// telegraf. A tag is left out when the process exited or its executable isn't readable, e.g. a process of
+// another user.
+func (p *Procstat) addTags(pid int, tags map[string]string) map[string]string {
+	if !p.ExePathTag && !p.CmdLineHashTag && !p.PatternHashTag {
+		return tags
+	}
+	copied := make(map[string]string, len(tags)+3)
+	for k, v := range tags {
+		copied[k] = v
+	}
+	if p.PatternHashTag && p.Pattern != "" {
+		copied["pattern_hash"] = hash(p.Pattern)
+	}
+	if !p.ExePathTag && !p.CmdLineHashTag {
+		return copied
+	}
+	proc, err := p.newProcess(pid)
+	if err != nil {
+		return copied
+	}
+	if p.ExePathTag {
+		if exe, err := proc.Exe(); err == nil && exe != "" {
+			copied["exe_path"] = exe
+		}
+	}
+	if p.CmdLineHashTag {
+		if cmdline, err := proc.Cmdline(); err == nil && cmdline != "" {
+			copied["cmdline_hash"] = hash(cmdline)
+		}
+	}
+	return copied
+}
+
+// hash is a short stable hash of a command line or a pattern, a dimension shorter than them.
+func hash(s string) string {
+	h := fnv.New32a()
+	h.Write([]byte(s))
+	return fmt.Sprintf("%08x", h.Sum32())
+}
+
+// parseStatusLine parses a line of /proc/<pid>/status with a value in kB, the value is returned in bytes.
+func parseStatusLine(line string) (string, uint64, bool) {
+	parts := strings.SplitN(line, ":", 2)
+	if len(parts) != 2 {
+		return "", 0, false
+	}
+	values := strings.Fields(parts[1])
+	if len(values) != 2 || values[1] != "kB" {
+		return "", 0, false
+	}
+	kb, err := strconv.ParseUint(values[0], 10, 64)
+	if err != nil {
+		return "", 0, false
+	}
+	return parts[0], kb * 1024, true
+}
+
+func init() {
+	inputs.Add(measurement, func() telegraf.Input {
+		return &Procstat{}
+	})
+}
@@ -0,0 +1,388 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package procstat
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/plugins/inputs"
+	"github.com/influxdata/telegraf/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const status = `Name:	nginx
+State:	S (sleeping)
+Pid:	42
+VmRSS:	    7168 kB
+RssAnon:	    4096 kB
+RssFile:	    2048 kB
+RssShmem:	    1024 kB
+VmSwap:	       0 kB
+Threads:	4
+voluntary_ctxt_switches:	150
+`
+
+// fakeProcstat reports the procstat metrics of telegraf for a process.
+type fakeProcstat struct {
+	fields map[string]interface{}
+	tags   map[string]string
+}
+
+func (f *fakeProcstat) SampleConfig() string { return "" }
+func (f *fakeProcstat) Description() string  { return "" }
+func (f *fakeProcstat) Gather(acc telegraf.Accumulator) error {
+	acc.AddFields("procstat", f.fields, f.tags)
+	acc.AddFields("procstat_lookup", map[string]interface{}{"pid_count": 1}, map[string]string{"pid_finder": "native"})
+	return nil
+}
+
+func newTestProcstat(t *testing.T, p *Procstat, fake *fakeProcstat) (*Procstat, func()) {
+	dir, err := ioutil.TempDir("", "procstat")
+	require.NoError(t, err)
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "42"), 0755))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "42", "status"), []byte(status), 0644))
+	p.procRoot = dir
+	p.procstat = fake
+	require.NoError(t, p.Init())
+	return p, func() { os.RemoveAll(dir) }
+}
+
+func TestGather(t *testing.T) {
+	p, cleanup := newTestProcstat(t, &Procstat{}, &fakeProcstat{
+		fields: map[string]interface{}{"pid": int32(42), "num_fds": 12, "num_threads": int32(4), "memory_rss": uint64(7340032)},
+		tags:   map[string]string{"exe": "nginx", "process_name": "nginx"},
+	})
+	defer cleanup()
+
+	var acc testutil.Accumulator
+	require.NoError(t, p.Gather(&acc))
+	acc.AssertContainsTaggedFields(t, "procstat", map[string]interface{}{
+		"pid":              int32(42),
+		"num_fds":          12,
+		"num_threads":      int32(4),
+		"memory_rss":       uint64(7340032),
+		"memory_rss_anon":  uint64(4194304),
+		"memory_rss_file":  uint64(2097152),
+		"memory_rss_shmem": uint64(1048576),
+	}, map[string]string{"exe": "nginx", "process_name": "nginx"})
+	acc.AssertContainsTaggedFields(t, "procstat_lookup", map[string]interface{}{"pid_count": 1, "alive": 1}, map[string]string{"pid_finder": "native"})
+}
+
+func TestGather_PidTagAndPrefix(t *testing.T) {
+	p, cleanup := newTestProcstat(t, &Procstat{Prefix: "web", PidTag: true}, &fakeProcstat{
+		fields: map[string]interface{}{"web_num_fds": 12},
+		tags:   map[string]string{"pid": "42"},
+	})
+	defer cleanup()
+
+	var acc testutil.Accumulator
+	require.NoError(t, p.Gather(&acc))
+	acc.AssertContainsTaggedFields(t, "procstat", map[string]interface{}{
+		"web_num_fds":          12,
+		"web_memory_rss_anon":  uint64(4194304),
+		"web_memory_rss_file":  uint64(2097152),
+		"web_memory_rss_shmem": uint64(1048576),
+	}, map[string]string{"pid": "42"})
+}
+
+func TestGather_WindowsResources(t *testing.T) {
+	p := &Procstat{Prefix: "svc", readResources: func(pid int) (processResources, error) {
+		if pid != 42 {
+			return processResources{}, errors.New("access denied")
+		}
+		return processResources{handles: 1500, gdiObjects: 12, userObjects: 7, peakWorkingSet: 52428800}, nil
+	}}
+	fake := &fakeProcstat{
+		fields: map[string]interface{}{"pid": int32(42), "svc_num_threads": int32(4)},
+		tags:   map[string]string{"win_service": "W3SVC"},
+	}
+	p, cleanup := newTestProcstat(t, p, fake)
+	defer cleanup()
+
+	var acc testutil.Accumulator
+	require.NoError(t, p.Gather(&acc))
+	acc.AssertContainsTaggedFields(t, "procstat", map[string]interface{}{
+		"pid":                         int32(42),
+		"svc_num_threads":             int32(4),
+		"svc_memory_rss_anon":         uint64(4194304),
+		"svc_memory_rss_file":         uint64(2097152),
+		"svc_memory_rss_shmem":        uint64(1048576),
+		"svc_num_handles":             uint32(1500),
+		"svc_num_gdi_objects":         uint32(12),
+		"svc_num_user_objects":        uint32(7),
+		"svc_memory_peak_working_set": uint64(52428800),
+	}, map[string]string{"win_service": "W3SVC"})
+
+	// a process which isn't readable has none of the fields
+	acc.ClearMetrics()
+	fake.fields = map[string]interface{}{"pid": int32(43), "svc_num_threads": int32(2)}
+	require.NoError(t, p.Gather(&acc))
+	acc.AssertContainsTaggedFields(t, "procstat", map[string]interface{}{"pid": int32(43), "svc_num_threads": int32(2)}, map[string]string{"win_service": "W3SVC"})
+}
+
+func TestGather_ProcessExited(t *testing.T) {
+	p, cleanup := newTestProcstat(t, &Procstat{}, &fakeProcstat{
+		fields: map[string]interface{}{"pid": int32(43), "num_fds": 3},
+		tags:   map[string]string{},
+	})
+	defer cleanup()
+
+	var acc testutil.Accumulator
+	require.NoError(t, p.Gather(&acc))
+	acc.AssertContainsFields(t, "procstat", map[string]interface{}{"pid": int32(43), "num_fds": 3})
+}
+
+func TestGather_UptimeAndRestartCount(t *testing.T) {
+	started := time.Unix(1620828000, 0)
+	fake := &fakeProcstat{
+		fields: map[string]interface{}{"pid": int32(42), "created_at": started.UnixNano()},
+		tags:   map[string]string{"exe": "nginx"},
+	}
+	p, cleanup := newTestProcstat(t, &Procstat{}, fake)
+	defer cleanup()
+	now := started.Add(90 * time.Second)
+	p.now = func() time.Time { return now }
+
+	var acc testutil.Accumulator
+	require.NoError(t, p.Gather(&acc))
+	acc.AssertContainsFields(t, "procstat", map[string]interface{}{
+		"pid":              int32(42),
+		"created_at":       started.UnixNano(),
+		"uptime":           float64(90),
+		"memory_rss_anon":  uint64(4194304),
+		"memory_rss_file":  uint64(2097152),
+		"memory_rss_shmem": uint64(1048576),
+	})
+	// the processes running at the first gather are not restarts
+	acc.AssertContainsFields(t, "procstat_lookup", map[string]interface{}{"pid_count": 1, "alive": 1})
+
+	now = now.Add(time.Minute)
+	acc.ClearMetrics()
+	require.NoError(t, p.Gather(&acc))
+	acc.AssertContainsFields(t, "procstat_lookup", map[string]interface{}{"pid_count": 1, "alive": 1, "restart_count": 0})
+
+	// the process crashed and was restarted with the same pid
+	fake.fields = map[string]interface{}{"pid": int32(42), "created_at": now.Add(-10 * time.Second).UnixNano()}
+	acc.ClearMetrics()
+	require.NoError(t, p.Gather(&acc))
+	assert.Equal(t, float64(10), acc.Metrics[0].Fields["uptime"])
+	acc.AssertContainsFields(t, "procstat_lookup", map[string]interface{}{"pid_count": 1, "alive": 1, "restart_count": 1})
+}
+
+// fakeProcesses reports the procstat metrics of telegraf for several processes of a selector.
+type fakeProcesses struct {
+	fakeProcstat
+	pids []int32
+}
+
+func (f *fakeProcesses) Gather(acc telegraf.Accumulator) error {
+	for _, pid := range f.pids {
+		acc.AddFields("procstat", map[string]interface{}{"pid": pid}, f.tags)
+	}
+	acc.AddFields("procstat_lookup", map[string]interface{}{"pid_count": len(f.pids), "running": len(f.pids), "result_code": 0}, f.tags)
+	return nil
+}
+
+func TestGather_EnvironmentPattern(t *testing.T) {
+	dir, err := ioutil.TempDir("", "procstat")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	environments := map[string]string{
+		"42": "PATH=/usr/bin\x00APP_NAME=payments\x00",
+		"43": "APP_NAME=payments-canary\x00",
+		"44": "APP_NAME=orders\x00",
+	}
+	for pid, environ := range environments {
+		require.NoError(t, os.MkdirAll(filepath.Join(dir, pid), 0755))
+		require.NoError(t, ioutil.WriteFile(filepath.Join(dir, pid, "environ"), []byte(environ), 0644))
+	}
+	// the environment of 45 isn't readable
+	fake := &fakeProcesses{fakeProcstat: fakeProcstat{tags: map[string]string{"exe": "python"}}, pids: []int32{42, 43, 44, 45}}
+	p := &Procstat{EnvironmentPattern: "^APP_NAME=payments$", procRoot: dir, procstat: fake}
+	require.NoError(t, p.Init())
+
+	var acc testutil.Accumulator
+	require.NoError(t, p.Gather(&acc))
+	require.Len(t, acc.Metrics, 2)
+	tags := map[string]string{"exe": "python", "environment_pattern": "^APP_NAME=payments$"}
+	acc.AssertContainsTaggedFields(t, "procstat", map[string]interface{}{"pid": int32(42)}, tags)
+	acc.AssertContainsTaggedFields(t, "procstat_lookup", map[string]interface{}{"pid_count": 1, "running": 1, "result_code": 0, "alive": 1}, tags)
+
+	// the environment of the processes seen by the previous gather isn't read again
+	require.NoError(t, os.Remove(filepath.Join(dir, "42", "environ")))
+	acc.ClearMetrics()
+	require.NoError(t, p.Gather(&acc))
+	acc.AssertContainsTaggedFields(t, "procstat", map[string]interface{}{"pid": int32(42)}, tags)
+
+	assert.Error(t, (&Procstat{EnvironmentPattern: "APP_NAME=("}).Init())
+}
+
+type fakeContainers struct {
+	running []container
+	err     error
+}
+
+func (f *fakeContainers) containers() ([]container, error) {
+	return f.running, f.err
+}
+
+func TestGather_Container(t *testing.T) {
+	dir, err := ioutil.TempDir("", "procstat")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	payments := strings.Repeat("a", 64)
+	canary := strings.Repeat("b", 64)
+	orders := strings.Repeat("c", 64)
+	cgroups := map[string]string{
+		"42": "0::/system.slice/docker-" + payments + ".scope\n",
+		"43": "12:memory:/docker/" + canary + "\n11:cpu:/docker/" + canary + "\n",
+		"44": "0::/kubepods/besteffort/pod1/" + orders + "\n",
+		"45": "0::/user.slice/user-1000.slice/session-1.scope\n",
+	}
+	for pid, cgroup := range cgroups {
+		require.NoError(t, os.MkdirAll(filepath.Join(dir, pid), 0755))
+		require.NoError(t, ioutil.WriteFile(filepath.Join(dir, pid, "cgroup"), []byte(cgroup), 0644))
+	}
+	containers := &fakeContainers{running: []container{
+		{ID: payments, Names: []string{"/payments-1"}, Labels: map[string]string{"team": "payments", "track": "stable"}},
+		{ID: canary, Names: []string{"/payments-canary"}, Labels: map[string]string{"team": "payments", "track": "canary"}},
+		{ID: orders, Names: []string{"/orders-1"}, Labels: map[string]string{"team": "orders"}},
+	}}
+	// 45 isn't in a container
+	fake := &fakeProcesses{fakeProcstat: fakeProcstat{tags: map[string]string{"exe": "python"}}, pids: []int32{42, 43, 44, 45}}
+	p := &Procstat{
+		ContainerNamePattern: "^payments-",
+		ContainerLabels:      map[string]string{"track": "stable"},
+		ContainerIDTag:       true,
+		procRoot:             dir,
+		procstat:             fake,
+		containerLister:      containers,
+	}
+	require.NoError(t, p.Init())
+
+	var acc testutil.Accumulator
+	require.NoError(t, p.Gather(&acc))
+	require.Len(t, acc.Metrics, 2)
+	tags := map[string]string{"exe": "python", "container_name_pattern": "^payments-"}
+	acc.AssertContainsTaggedFields(t, "procstat", map[string]interface{}{"pid": int32(42)},
+		map[string]string{"exe": "python", "container_name_pattern": "^payments-", "container_name": "payments-1", "container_id": "aaaaaaaaaaaa"})
+	acc.AssertContainsTaggedFields(t, "procstat_lookup", map[string]interface{}{"pid_count": 1, "running": 1, "result_code": 0, "alive": 1}, tags)
+
+	// a container matching the name only
+	p.ContainerLabels = nil
+	p.ContainerIDTag = false
+	acc.ClearMetrics()
+	require.NoError(t, p.Gather(&acc))
+	require.Len(t, acc.Metrics, 3)
+	acc.AssertContainsTaggedFields(t, "procstat", map[string]interface{}{"pid": int32(43)},
+		map[string]string{"exe": "python", "container_name_pattern": "^payments-", "container_name": "payments-canary"})
+
+	// no process matches when the containers are not listed
+	containers.err = errors.New("permission denied")
+	acc.ClearMetrics()
+	require.NoError(t, p.Gather(&acc))
+	require.Len(t, acc.Errors, 1)
+	acc.AssertContainsTaggedFields(t, "procstat_lookup", map[string]interface{}{"pid_count": 0, "running": 0, "result_code": 0, "alive": 0, "restart_count": 0}, tags)
+
+	assert.Error(t, (&Procstat{ContainerNamePattern: "payments-("}).Init())
+	assert.Error(t, (&Procstat{ContainerNamePattern: "payments", ContainerEndpoint: "ftp://docker"}).Init())
+}
+
+// fakeLookupError reports the procstat_lookup of telegraf when the lookup failed, e.g. the pid file is missing.
+type fakeLookupError struct {
+	fakeProcstat
+}
+
+func (f *fakeLookupError) Gather(acc telegraf.Accumulator) error {
+	acc.AddFields("procstat_lookup", map[string]interface{}{"pid_count": 0, "running": 0, "result_code": 1},
+		map[string]string{"pid_finder": "native", "result": "lookup_error"})
+	return nil
+}
+
+func TestGather_Alive(t *testing.T) {
+	// no process matches
+	p := &Procstat{procstat: &fakeProcesses{fakeProcstat: fakeProcstat{tags: map[string]string{"exe": "nginx"}}}}
+	require.NoError(t, p.Init())
+	var acc testutil.Accumulator
+	require.NoError(t, p.Gather(&acc))
+	acc.AssertContainsFields(t, "procstat_lookup", map[string]interface{}{"pid_count": 0, "running": 0, "result_code": 0, "alive": 0})
+
+	p = &Procstat{procstat: &fakeLookupError{}}
+	require.NoError(t, p.Init())
+	acc.ClearMetrics()
+	require.NoError(t, p.Gather(&acc))
+	acc.AssertContainsFields(t, "procstat_lookup", map[string]interface{}{"pid_count": 0, "running": 0, "result_code": 1, "alive": 0})
+}
+
+type fakeProcess struct {
+	exe     string
+	cmdline string
+}
+
+func (f *fakeProcess) Exe() (string, error)     { return f.exe, nil }
+func (f *fakeProcess) Cmdline() (string, error) { return f.cmdline, nil }
+
+func TestGather_Tags(t *testing.T) {
+	p := &Procstat{Pattern: "java -jar app.jar", ExePathTag: true, CmdLineHashTag: true, PatternHashTag: true}
+	p.newProcess = func(pid int) (processInfo, error) {
+		assert.Equal(t, 42, pid)
+		return &fakeProcess{exe: "/usr/bin/java", cmdline: "java -Xmx1g -jar app.jar"}, nil
+	}
+	tags := map[string]string{"pattern": "java -jar app.jar"}
+	p, cleanup := newTestProcstat(t, p, &fakeProcstat{
+		fields: map[string]interface{}{"pid": int32(42), "num_fds": 12},
+		tags:   tags,
+	})
+	defer cleanup()
+
+	var acc testutil.Accumulator
+	require.NoError(t, p.Gather(&acc))
+	acc.AssertContainsTaggedFields(t, "procstat", map[string]interface{}{
+		"pid":              int32(42),
+		"num_fds":          12,
+		"memory_rss_anon":  uint64(4194304),
+		"memory_rss_file":  uint64(2097152),
+		"memory_rss_shmem": uint64(1048576),
+	}, map[string]string{
+		"pattern":      "java -jar app.jar",
+		"pattern_hash": hash("java -jar app.jar"),
+		"exe_path":     "/usr/bin/java",
+		"cmdline_hash": hash("java -Xmx1g -jar app.jar"),
+	})
+	// the tags of telegraf are not modified
+	assert.Equal(t, map[string]string{"pattern": "java -jar app.jar"}, tags)
+	assert.Len(t, hash("java -Xmx1g -jar app.jar"), 8)
+	assert.NotEqual(t, hash("java -jar app.jar"), hash("java -Xmx1g -jar app.jar"))
+}
+
+func TestParseStatusLine(t *testing.T) {
+	key, value, ok := parseStatusLine("RssAnon:	    4096 kB")
+	assert.True(t, ok)
+	assert.Equal(t, "RssAnon", key)
+	assert.Equal(t, uint64(4194304), value)
+
+	for _, line := range []string{"Threads:	4", "Name:	nginx", "no colon", "VmRSS: x kB"} {
+		_, _, ok := parseStatusLine(line)
+		assert.False(t, ok, line)
+	}
+}
+
+func TestInit(t *testing.T) {
+	// the procstat input of the agent replaces the one of telegraf
+	p, ok := inputs.Inputs["procstat"]().(*Procstat)
+	require.True(t, ok)
+	p.Exe = "nginx"
+	p.PidFinder = "native"
+	require.NoError(t, p.Init())
+	assert.Equal(t, defaultProcRoot, p.procRoot)
+	assert.NotNil(t, p.procstat)
+}
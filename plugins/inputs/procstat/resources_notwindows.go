@@ -0,0 +1,17 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+//go:build !windows
+// +build !windows
+
+package procstat
+
+import (
+	"errors"
+)
+
+var errResourcesNotSupported = errors.New("the handles and the GDI objects of the processes are only reported on Windows")
+
+func readProcessResources(pid int) (processResources, error) {
+	return processResources{}, errResourcesNotSupported
+}
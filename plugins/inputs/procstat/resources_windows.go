@@ -0,0 +1,74 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+//go:build windows
+// +build windows
+
+package procstat
+
+import (
+	"syscall"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+const (
+	// the GetGuiResources flags of the current GDI and USER objects of a process
+	grGDIObjects  = 0
+	grUserObjects = 1
+)
+
+var (
+	modkernel32 = windows.NewLazySystemDLL("kernel32.dll")
+	moduser32   = windows.NewLazySystemDLL("user32.dll")
+	modpsapi    = windows.NewLazySystemDLL("psapi.dll")
+
+	procGetProcessHandleCount = modkernel32.NewProc("GetProcessHandleCount")
+	procGetGuiResources       = moduser32.NewProc("GetGuiResources")
+	procGetProcessMemoryInfo  = modpsapi.NewProc("GetProcessMemoryInfo")
+)
+
+// processMemoryCounters is the PROCESS_MEMORY_COUNTERS structure of GetProcessMemoryInfo.
+type processMemoryCounters struct {
+	CB                         uint32
+	PageFaultCount             uint32
+	PeakWorkingSetSize         uintptr
+	WorkingSetSize             uintptr
+	QuotaPeakPagedPoolUsage    uintptr
+	QuotaPagedPoolUsage        uintptr
+	QuotaPeakNonPagedPoolUsage uintptr
+	QuotaNonPagedPoolUsage     uintptr
+	PagefileUsage              uintptr
+	PeakPagefileUsage          uintptr
+}
+
+// readProcessResources reads the handles, the GDI and USER objects and the peak working set of the process. The
+// GDI and USER objects of the services are usually 0, they only have some when they have windows.
+func readProcessResources(pid int) (processResources, error) {
+	h, err := windows.OpenProcess(windows.PROCESS_QUERY_LIMITED_INFORMATION, false, uint32(pid))
+	if err != nil {
+		return processResources{}, err
+	}
+	defer windows.CloseHandle(h)
+
+	var r processResources
+	r1, _, e1 := syscall.Syscall(procGetProcessHandleCount.Addr(), 2, uintptr(h), uintptr(unsafe.Pointer(&r.handles)), 0)
+	if r1 == 0 {
+		return processResources{}, e1
+	}
+	// GetGuiResources returns 0 on errors, like for a process without any object
+	gdi, _, _ := syscall.Syscall(procGetGuiResources.Addr(), 2, uintptr(h), grGDIObjects, 0)
+	user, _, _ := syscall.Syscall(procGetGuiResources.Addr(), 2, uintptr(h), grUserObjects, 0)
+	r.gdiObjects = uint32(gdi)
+	r.userObjects = uint32(user)
+
+	var counters processMemoryCounters
+	counters.CB = uint32(unsafe.Sizeof(counters))
+	r1, _, e1 = syscall.Syscall(procGetProcessMemoryInfo.Addr(), 3, uintptr(h), uintptr(unsafe.Pointer(&counters)), uintptr(counters.CB))
+	if r1 == 0 {
+		return processResources{}, e1
+	}
+	r.peakWorkingSet = uint64(counters.PeakWorkingSetSize)
+	return r, nil
+}
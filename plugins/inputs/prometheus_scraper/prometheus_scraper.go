@@ -6,6 +6,7 @@ package prometheus_scraper
 import (
 	"sync"
 
+	"github.com/aws/amazon-cloudwatch-agent/internal/ec2servicediscovery"
 	"github.com/aws/amazon-cloudwatch-agent/internal/ecsservicediscovery"
 	"github.com/influxdata/telegraf"
 	"github.com/influxdata/telegraf/plugins/inputs"
@@ -15,6 +16,7 @@ type PrometheusScraper struct {
 	PrometheusConfigPath string                                      `toml:"prometheus_config_path"`
 	ClusterName          string                                      `toml:"cluster_name"`
 	ECSSDConfig          *ecsservicediscovery.ServiceDiscoveryConfig `toml:"ecs_service_discovery"`
+	EC2SDConfig          *ec2servicediscovery.ServiceDiscoveryConfig `toml:"ec2_service_discovery"`
 	mbCh                 chan PrometheusMetricBatch
 	shutDownChan         chan interface{}
 	wg                   sync.WaitGroup
@@ -76,6 +78,11 @@ func (p *PrometheusScraper) Start(accIn telegraf.Accumulator) error {
 	p.wg.Add(1)
 	go ecsservicediscovery.StartECSServiceDiscovery(ecssd, p.shutDownChan, &p.wg)
 
+	// start EC2 Service Discovery
+	ec2sd := &ec2servicediscovery.ServiceDiscovery{Config: p.EC2SDConfig}
+	p.wg.Add(1)
+	go ec2servicediscovery.StartEC2ServiceDiscovery(ec2sd, p.shutDownChan, &p.wg)
+
 	// start metric collecting
 	p.wg.Add(1)
 	go Start(p.PrometheusConfigPath, receiver, p.shutDownChan, &p.wg, mth)
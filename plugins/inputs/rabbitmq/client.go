@@ -0,0 +1,117 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package rabbitmq
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+)
+
+// The subset of the RabbitMQ management HTTP API read by the plugin, see https://rawcdn.githack.com/rabbitmq/rabbitmq-server/v3.8.9/deps/rabbitmq_management/priv/www/api/index.html
+
+type rate struct {
+	Rate float64 `json:"rate"`
+}
+
+type messageStats struct {
+	Publish    rate `json:"publish_details"`
+	DeliverGet rate `json:"deliver_get_details"`
+	Ack        rate `json:"ack_details"`
+	Redeliver  rate `json:"redeliver_details"`
+}
+
+type overview struct {
+	ClusterName  string       `json:"cluster_name"`
+	MessageStats messageStats `json:"message_stats"`
+	QueueTotals  struct {
+		Messages        int64 `json:"messages"`
+		MessagesReady   int64 `json:"messages_ready"`
+		MessagesUnacked int64 `json:"messages_unacknowledged"`
+	} `json:"queue_totals"`
+	ObjectTotals struct {
+		Connections int64 `json:"connections"`
+		Channels    int64 `json:"channels"`
+		Consumers   int64 `json:"consumers"`
+		Queues      int64 `json:"queues"`
+	} `json:"object_totals"`
+}
+
+type node struct {
+	Name          string `json:"name"`
+	Running       bool   `json:"running"`
+	MemUsed       int64  `json:"mem_used"`
+	MemLimit      int64  `json:"mem_limit"`
+	MemAlarm      bool   `json:"mem_alarm"`
+	DiskFree      int64  `json:"disk_free"`
+	DiskFreeLimit int64  `json:"disk_free_limit"`
+	DiskFreeAlarm bool   `json:"disk_free_alarm"`
+	FdUsed        int64  `json:"fd_used"`
+	FdTotal       int64  `json:"fd_total"`
+	SocketsUsed   int64  `json:"sockets_used"`
+}
+
+type queue struct {
+	Name            string       `json:"name"`
+	VHost           string       `json:"vhost"`
+	Messages        int64        `json:"messages"`
+	MessagesReady   int64        `json:"messages_ready"`
+	MessagesUnacked int64        `json:"messages_unacknowledged"`
+	Consumers       int64        `json:"consumers"`
+	Memory          int64        `json:"memory"`
+	MessageStats    messageStats `json:"message_stats"`
+}
+
+// the columns of the queues read, the other statistics of every queue are not sent by the server
+const queueColumns = "name,vhost,messages,messages_ready,messages_unacknowledged,consumers,memory," +
+	"message_stats.publish_details.rate,message_stats.deliver_get_details.rate,message_stats.ack_details.rate," +
+	"message_stats.redeliver_details.rate"
+
+// client calls the management API with the credentials of a user tagged monitoring.
+type client struct {
+	baseURL    string
+	username   string
+	password   string
+	httpClient *http.Client
+}
+
+func (c *client) overview(ctx context.Context) (*overview, error) {
+	var o overview
+	err := c.get(ctx, "/api/overview", &o)
+	return &o, err
+}
+
+func (c *client) nodes(ctx context.Context) ([]node, error) {
+	var nodes []node
+	err := c.get(ctx, "/api/nodes", &nodes)
+	return nodes, err
+}
+
+func (c *client) queues(ctx context.Context) ([]queue, error) {
+	var queues []queue
+	err := c.get(ctx, "/api/queues?columns="+queueColumns, &queues)
+	return queues, err
+}
+
+func (c *client) get(ctx context.Context, path string, v interface{}) error {
+	req, err := http.NewRequest(http.MethodGet, c.baseURL+path, nil)
+	if err != nil {
+		return err
+	}
+	req.SetBasicAuth(c.username, c.password)
+	resp, err := c.httpClient.Do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("GET %s returned %s: %s", strings.SplitN(path, "?", 2)[0], resp.Status, strings.TrimSpace(string(body)))
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
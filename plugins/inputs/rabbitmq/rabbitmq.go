@@ -0,0 +1,196 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package rabbitmq
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/amazon-cloudwatch-agent/internal"
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/filter"
+	"github.com/influxdata/telegraf/plugins/inputs"
+)
+
+const (
+	measurement     = "rabbitmq"
+	defaultURL      = "http://localhost:15672"
+	defaultUsername = "guest"
+	defaultPassword = "guest"
+	defaultTimeout  = 5 * time.Second
+)
+
+var sampleConfig = `
+  ## The url of the management plugin of RabbitMQ.
+  # url = "http://localhost:15672"
+
+  ## The credentials of a user with the monitoring tag.
+  # username = "guest"
+  # password = "guest"
+
+  ## The vhosts reported, and the queues to include and exclude by name, globs accepted. All of them are reported
+  ## when empty.
+  # vhosts = []
+  # queue_name_include = []
+  # queue_name_exclude = []
+
+  ## Maximum time the queries of the management API are allowed to run.
+  # timeout = "5s"
+`
+
+type RabbitMQ struct {
+	URL          string            `toml:"url"`
+	Username     string            `toml:"username"`
+	Password     string            `toml:"password"`
+	VHosts       []string          `toml:"vhosts"`
+	QueueInclude []string          `toml:"queue_name_include"`
+	QueueExclude []string          `toml:"queue_name_exclude"`
+	Timeout      internal.Duration `toml:"timeout"`
+
+	Log telegraf.Logger `toml:"-"`
+
+	client      *client
+	vhostFilter filter.Filter
+	queueFilter filter.Filter
+}
+
+func (r *RabbitMQ) SampleConfig() string {
+	return sampleConfig
+}
+
+func (r *RabbitMQ) Description() string {
+	return "Report the queues, message rates, consumers and memory alarms of a RabbitMQ cluster through the management API."
+}
+
+func (r *RabbitMQ) Init() error {
+	if r.URL == "" {
+		r.URL = defaultURL
+	}
+	u, err := url.Parse(r.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("rabbitmq: invalid url %q, http://host:port or https://host:port is expected", r.URL)
+	}
+	if r.Username == "" && r.Password == "" {
+		r.Username, r.Password = defaultUsername, defaultPassword
+	}
+	if r.Timeout.Duration <= 0 {
+		r.Timeout.Duration = defaultTimeout
+	}
+	if r.vhostFilter, err = filter.Compile(r.VHosts); err != nil {
+		return fmt.Errorf("rabbitmq: invalid vhosts: %v", err)
+	}
+	if r.queueFilter, err = filter.NewIncludeExcludeFilter(r.QueueInclude, r.QueueExclude); err != nil {
+		return fmt.Errorf("rabbitmq: invalid queue name filter: %v", err)
+	}
+	r.client = &client{
+		baseURL:    strings.TrimSuffix(r.URL, "/"),
+		username:   r.Username,
+		password:   r.Password,
+		httpClient: &http.Client{},
+	}
+	return nil
+}
+
+func (r *RabbitMQ) Gather(acc telegraf.Accumulator) error {
+	ctx, cancel := context.WithTimeout(context.Background(), r.Timeout.Duration)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	for _, gather := range []func(context.Context, telegraf.Accumulator) error{r.gatherOverview, r.gatherNodes, r.gatherQueues} {
+		wg.Add(1)
+		go func(gather func(context.Context, telegraf.Accumulator) error) {
+			defer wg.Done()
+			if err := gather(ctx, acc); err != nil {
+				acc.AddError(fmt.Errorf("failed to query the management API of %s: %v", r.URL, err))
+			}
+		}(gather)
+	}
+	wg.Wait()
+	return nil
+}
+
+func (r *RabbitMQ) gatherOverview(ctx context.Context, acc telegraf.Accumulator) error {
+	o, err := r.client.overview(ctx)
+	if err != nil {
+		return err
+	}
+	acc.AddFields(measurement, map[string]interface{}{
+		"overview_messages":         o.QueueTotals.Messages,
+		"overview_messages_ready":   o.QueueTotals.MessagesReady,
+		"overview_messages_unacked": o.QueueTotals.MessagesUnacked,
+		"overview_connections":      o.ObjectTotals.Connections,
+		"overview_channels":         o.ObjectTotals.Channels,
+		"overview_consumers":        o.ObjectTotals.Consumers,
+		"overview_queues":           o.ObjectTotals.Queues,
+		"overview_publish_rate":     o.MessageStats.Publish.Rate,
+		"overview_deliver_rate":     o.MessageStats.DeliverGet.Rate,
+	}, map[string]string{})
+	return nil
+}
+
+func (r *RabbitMQ) gatherNodes(ctx context.Context, acc telegraf.Accumulator) error {
+	nodes, err := r.client.nodes(ctx)
+	if err != nil {
+		return err
+	}
+	for _, n := range nodes {
+		fields := map[string]interface{}{"node_running": boolToInt(n.Running)}
+		// the statistics of the nodes that are down are not known
+		if n.Running {
+			fields["node_mem_used"] = n.MemUsed
+			fields["node_mem_limit"] = n.MemLimit
+			fields["node_mem_alarm"] = boolToInt(n.MemAlarm)
+			fields["node_disk_free"] = n.DiskFree
+			fields["node_disk_free_limit"] = n.DiskFreeLimit
+			fields["node_disk_free_alarm"] = boolToInt(n.DiskFreeAlarm)
+			fields["node_fd_used"] = n.FdUsed
+			fields["node_fd_total"] = n.FdTotal
+			fields["node_sockets_used"] = n.SocketsUsed
+		}
+		acc.AddFields(measurement, fields, map[string]string{"node": n.Name})
+	}
+	return nil
+}
+
+func (r *RabbitMQ) gatherQueues(ctx context.Context, acc telegraf.Accumulator) error {
+	queues, err := r.client.queues(ctx)
+	if err != nil {
+		return err
+	}
+	for _, q := range queues {
+		if (r.vhostFilter != nil && !r.vhostFilter.Match(q.VHost)) || !r.queueFilter.Match(q.Name) {
+			continue
+		}
+		acc.AddFields(measurement, map[string]interface{}{
+			"queue_messages":         q.Messages,
+			"queue_messages_ready":   q.MessagesReady,
+			"queue_messages_unacked": q.MessagesUnacked,
+			"queue_consumers":        q.Consumers,
+			"queue_memory":           q.Memory,
+			"queue_publish_rate":     q.MessageStats.Publish.Rate,
+			"queue_deliver_rate":     q.MessageStats.DeliverGet.Rate,
+			"queue_ack_rate":         q.MessageStats.Ack.Rate,
+			"queue_redeliver_rate":   q.MessageStats.Redeliver.Rate,
+		}, map[string]string{"vhost": q.VHost, "queue": q.Name})
+	}
+	return nil
+}
+
+func boolToInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+func init() {
+	inputs.Add("rabbitmq", func() telegraf.Input {
+		return &RabbitMQ{}
+	})
+}
@@ -0,0 +1,172 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package rabbitmq
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/influxdata/telegraf/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+const overviewJSON = `{
+  "cluster_name": "rabbit@mq-1",
+  "message_stats": {"publish": 1200, "publish_details": {"rate": 12.5}, "deliver_get_details": {"rate": 11.0}},
+  "queue_totals": {"messages": 42, "messages_ready": 40, "messages_unacknowledged": 2},
+  "object_totals": {"connections": 7, "channels": 9, "consumers": 3, "queues": 3, "exchanges": 8}
+}`
+
+const nodesJSON = `[
+  {"name": "rabbit@mq-1", "running": true, "mem_used": 1000, "mem_limit": 4000, "mem_alarm": true,
+   "disk_free": 50000, "disk_free_limit": 10000, "disk_free_alarm": false, "fd_used": 30, "fd_total": 1024, "sockets_used": 5},
+  {"name": "rabbit@mq-2", "running": false}
+]`
+
+// the queues without traffic have no message_stats
+const queuesJSON = `[
+  {"name": "orders", "vhost": "/", "messages": 40, "messages_ready": 38, "messages_unacknowledged": 2, "consumers": 2, "memory": 55000,
+   "message_stats": {"publish_details": {"rate": 10.0}, "deliver_get_details": {"rate": 9.5}, "ack_details": {"rate": 9.0}, "redeliver_details": {"rate": 0.5}}},
+  {"name": "orders.dlq", "vhost": "/", "messages": 2, "messages_ready": 2, "messages_unacknowledged": 0, "consumers": 0, "memory": 10000},
+  {"name": "events", "vhost": "billing", "messages": 0, "consumers": 1, "memory": 9000}
+]`
+
+func newTestServer(t *testing.T) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, password, ok := r.BasicAuth()
+		if !ok || user != "monitor" || password != "secret" {
+			http.Error(w, `{"error":"not_authorised"}`, http.StatusUnauthorized)
+			return
+		}
+		switch r.URL.Path {
+		case "/api/overview":
+			w.Write([]byte(overviewJSON))
+		case "/api/nodes":
+			w.Write([]byte(nodesJSON))
+		case "/api/queues":
+			assert.Equal(t, queueColumns, r.URL.Query().Get("columns"))
+			w.Write([]byte(queuesJSON))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+}
+
+func newRabbitMQ(t *testing.T, server *httptest.Server) *RabbitMQ {
+	r := &RabbitMQ{URL: server.URL + "/", Username: "monitor", Password: "secret", Log: testutil.Logger{}}
+	assert.NoError(t, r.Init())
+	return r
+}
+
+func TestGather(t *testing.T) {
+	server := newTestServer(t)
+	defer server.Close()
+	r := newRabbitMQ(t, server)
+
+	var acc testutil.Accumulator
+	assert.NoError(t, r.Gather(&acc))
+	assert.Empty(t, acc.Errors)
+	assert.Len(t, acc.Metrics, 6)
+	acc.AssertContainsTaggedFields(t, "rabbitmq",
+		map[string]interface{}{
+			"overview_messages":         int64(42),
+			"overview_messages_ready":   int64(40),
+			"overview_messages_unacked": int64(2),
+			"overview_connections":      int64(7),
+			"overview_channels":         int64(9),
+			"overview_consumers":        int64(3),
+			"overview_queues":           int64(3),
+			"overview_publish_rate":     12.5,
+			"overview_deliver_rate":     11.0,
+		},
+		map[string]string{})
+	acc.AssertContainsTaggedFields(t, "rabbitmq",
+		map[string]interface{}{
+			"node_running":         1,
+			"node_mem_used":        int64(1000),
+			"node_mem_limit":       int64(4000),
+			"node_mem_alarm":       1,
+			"node_disk_free":       int64(50000),
+			"node_disk_free_limit": int64(10000),
+			"node_disk_free_alarm": 0,
+			"node_fd_used":         int64(30),
+			"node_fd_total":        int64(1024),
+			"node_sockets_used":    int64(5),
+		},
+		map[string]string{"node": "rabbit@mq-1"})
+	acc.AssertContainsTaggedFields(t, "rabbitmq",
+		map[string]interface{}{"node_running": 0},
+		map[string]string{"node": "rabbit@mq-2"})
+	acc.AssertContainsTaggedFields(t, "rabbitmq",
+		map[string]interface{}{
+			"queue_messages":         int64(40),
+			"queue_messages_ready":   int64(38),
+			"queue_messages_unacked": int64(2),
+			"queue_consumers":        int64(2),
+			"queue_memory":           int64(55000),
+			"queue_publish_rate":     10.0,
+			"queue_deliver_rate":     9.5,
+			"queue_ack_rate":         9.0,
+			"queue_redeliver_rate":   0.5,
+		},
+		map[string]string{"vhost": "/", "queue": "orders"})
+	acc.AssertContainsTaggedFields(t, "rabbitmq",
+		map[string]interface{}{
+			"queue_messages":         int64(0),
+			"queue_messages_ready":   int64(0),
+			"queue_messages_unacked": int64(0),
+			"queue_consumers":        int64(1),
+			"queue_memory":           int64(9000),
+			"queue_publish_rate":     0.0,
+			"queue_deliver_rate":     0.0,
+			"queue_ack_rate":         0.0,
+			"queue_redeliver_rate":   0.0,
+		},
+		map[string]string{"vhost": "billing", "queue": "events"})
+}
+
+func TestGatherFilters(t *testing.T) {
+	server := newTestServer(t)
+	defer server.Close()
+	r := newRabbitMQ(t, server)
+	r.VHosts = []string{"/"}
+	r.QueueExclude = []string{"*.dlq"}
+	assert.NoError(t, r.Init())
+
+	var acc testutil.Accumulator
+	assert.NoError(t, r.Gather(&acc))
+	var queues []string
+	for _, m := range acc.Metrics {
+		if q, ok := m.Tags["queue"]; ok {
+			queues = append(queues, q)
+		}
+	}
+	assert.Equal(t, []string{"orders"}, queues)
+}
+
+func TestGatherErrors(t *testing.T) {
+	server := newTestServer(t)
+	defer server.Close()
+	r := newRabbitMQ(t, server)
+	r.Password = "wrong"
+	assert.NoError(t, r.Init())
+
+	var acc testutil.Accumulator
+	assert.NoError(t, r.Gather(&acc))
+	assert.Len(t, acc.Errors, 3)
+	assert.Contains(t, acc.Errors[0].Error(), "401 Unauthorized")
+	assert.Empty(t, acc.Metrics)
+}
+
+func TestInit(t *testing.T) {
+	r := &RabbitMQ{}
+	assert.NoError(t, r.Init())
+	assert.Equal(t, defaultURL, r.URL)
+	assert.Equal(t, defaultUsername, r.Username)
+	assert.Equal(t, defaultPassword, r.Password)
+	assert.Equal(t, defaultTimeout, r.Timeout.Duration)
+	assert.Error(t, (&RabbitMQ{URL: "localhost:15672"}).Init())
+	assert.Error(t, (&RabbitMQ{URL: "amqp://localhost:5672"}).Init())
+}
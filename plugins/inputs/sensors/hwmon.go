@@ -0,0 +1,145 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package sensors
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/influxdata/telegraf"
+)
+
+// hwmonChip is a hardware monitoring chip of /sys/class/hwmon, e.g. coretemp or nvme.
+type hwmonChip struct {
+	name  string
+	hwmon string
+	// the directory of the sensor files, the hwmon directory or its device on the old kernels
+	dir string
+}
+
+// gatherHwmon reads the sensors of the chips, the temperatures are in millidegrees Celsius and the fan speeds in RPM
+// in the sysfs. The hosts without chip, most of the virtual machines, report nothing.
+func (s *Sensors) gatherHwmon(acc telegraf.Accumulator) error {
+	chips, err := s.hwmonChips()
+	if os.IsNotExist(err) {
+		s.Log.Debugf("No hwmon class: %v", err)
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("sensors: %v", err)
+	}
+	for _, chip := range chips {
+		s.gatherTemperatures(acc, chip)
+		s.gatherFans(acc, chip)
+	}
+	return nil
+}
+
+// hwmonChips returns the chips of the hwmon class, the chips with the same name, like the coretemp of each socket,
+// are told apart by their device.
+func (s *Sensors) hwmonChips() ([]hwmonChip, error) {
+	entries, err := ioutil.ReadDir(s.hwmonRoot)
+	if err != nil {
+		return nil, err
+	}
+	var chips []hwmonChip
+	counts := map[string]int{}
+	for _, entry := range entries {
+		hwmon := filepath.Join(s.hwmonRoot, entry.Name())
+		dir := hwmon
+		name, err := readString(filepath.Join(dir, "name"))
+		if os.IsNotExist(err) {
+			dir = filepath.Join(dir, "device")
+			name, err = readString(filepath.Join(dir, "name"))
+		}
+		if err != nil {
+			s.Log.Debugf("Skipping the hwmon chip %s: %v", entry.Name(), err)
+			continue
+		}
+		chips = append(chips, hwmonChip{name: name, hwmon: hwmon, dir: dir})
+		counts[name]++
+	}
+	for i, chip := range chips {
+		if counts[chip.name] < 2 {
+			continue
+		}
+		device, err := filepath.EvalSymlinks(filepath.Join(chip.hwmon, "device"))
+		if err != nil {
+			// the virtual chips have no device, their hwmon directory is unique
+			device = chip.hwmon
+		}
+		chips[i].name = chip.name + "-" + filepath.Base(device)
+	}
+	return chips, nil
+}
+
+func (s *Sensors) gatherTemperatures(acc telegraf.Accumulator, chip hwmonChip) {
+	for _, sensor := range sensorPrefixes(chip.dir, "temp") {
+		fields := map[string]interface{}{}
+		for _, field := range []string{"input", "max", "crit"} {
+			v, err := readInt(filepath.Join(chip.dir, sensor+"_"+field))
+			if err != nil {
+				// the optional limits are missing, and the input of an absent sensor can't be read
+				continue
+			}
+			fields["temp_"+field] = float64(v) / 1000
+		}
+		if _, ok := fields["temp_input"]; !ok {
+			s.Log.Debugf("Skipping the sensor %s of %s without input", sensor, chip.name)
+			continue
+		}
+		acc.AddFields(measurement, fields, sensorTags(chip, sensor))
+	}
+}
+
+func (s *Sensors) gatherFans(acc telegraf.Accumulator, chip hwmonChip) {
+	for _, sensor := range sensorPrefixes(chip.dir, "fan") {
+		v, err := readInt(filepath.Join(chip.dir, sensor+"_input"))
+		if err != nil {
+			s.Log.Debugf("Skipping the sensor %s of %s without input: %v", sensor, chip.name, err)
+			continue
+		}
+		acc.AddFields(measurement, map[string]interface{}{"fan_input": v}, sensorTags(chip, sensor))
+	}
+}
+
+// sensorPrefixes returns the sensors of a type of the chip by their input files, e.g. temp1 for temp1_input.
+func sensorPrefixes(dir, sensorType string) []string {
+	matches, _ := filepath.Glob(filepath.Join(dir, sensorType+"*_input"))
+	var sensors []string
+	for _, match := range matches {
+		sensors = append(sensors, strings.TrimSuffix(filepath.Base(match), "_input"))
+	}
+	sort.Strings(sensors)
+	return sensors
+}
+
+// sensorTags returns the dimensions of the sensor, its label like "Package id 0" or "Core 1", else its name.
+func sensorTags(chip hwmonChip, sensor string) map[string]string {
+	label, err := readString(filepath.Join(chip.dir, sensor+"_label"))
+	if err != nil || label == "" {
+		label = sensor
+	}
+	return map[string]string{"chip": chip.name, "label": label}
+}
+
+func readString(path string) (string, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(b)), nil
+}
+
+func readInt(path string) (int64, error) {
+	s, err := readString(path)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseInt(s, 10, 64)
+}
@@ -0,0 +1,52 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package sensors
+
+import (
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/plugins/inputs"
+)
+
+const (
+	measurement      = "sensors"
+	defaultHwmonRoot = "/sys/class/hwmon"
+)
+
+var sampleConfig = `
+  ## No configuration, the temperatures and the fan speeds are read from /sys/class/hwmon on Linux and from the
+  ## ACPI thermal zones on Windows.
+`
+
+// Sensors reports the temperatures and the fan speeds of the hardware monitoring chips, by chip and sensor label.
+type Sensors struct {
+	Log telegraf.Logger `toml:"-"`
+
+	// the hwmon class directory, replaced in tests
+	hwmonRoot string
+}
+
+func (s *Sensors) SampleConfig() string {
+	return sampleConfig
+}
+
+func (s *Sensors) Description() string {
+	return "Report the temperatures and the fan speeds of the hardware sensors."
+}
+
+func (s *Sensors) Init() error {
+	if s.hwmonRoot == "" {
+		s.hwmonRoot = defaultHwmonRoot
+	}
+	return nil
+}
+
+func (s *Sensors) Gather(acc telegraf.Accumulator) error {
+	return s.gather(acc)
+}
+
+func init() {
+	inputs.Add("sensors", func() telegraf.Input {
+		return &Sensors{}
+	})
+}
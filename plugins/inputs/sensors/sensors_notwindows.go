@@ -0,0 +1,15 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+//go:build !windows
+// +build !windows
+
+package sensors
+
+import (
+	"github.com/influxdata/telegraf"
+)
+
+func (s *Sensors) gather(acc telegraf.Accumulator) error {
+	return s.gatherHwmon(acc)
+}
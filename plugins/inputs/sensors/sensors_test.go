@@ -0,0 +1,97 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+//go:build !windows
+// +build !windows
+
+package sensors
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/influxdata/telegraf/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func writeFiles(t *testing.T, root string, files map[string]string) {
+	for name, content := range files {
+		path := filepath.Join(root, name)
+		assert.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		assert.NoError(t, ioutil.WriteFile(path, []byte(content), 0644))
+	}
+}
+
+func newSensors(t *testing.T, root string) *Sensors {
+	s := &Sensors{Log: testutil.Logger{}, hwmonRoot: root}
+	assert.NoError(t, s.Init())
+	return s
+}
+
+func TestGather(t *testing.T) {
+	root, err := ioutil.TempDir("", "sensors")
+	assert.NoError(t, err)
+	defer os.RemoveAll(root)
+	hwmon := filepath.Join(root, "class", "hwmon")
+	writeFiles(t, root, map[string]string{
+		"class/hwmon/hwmon0/name":        "coretemp\n",
+		"class/hwmon/hwmon0/temp1_input": "45000\n",
+		"class/hwmon/hwmon0/temp1_label": "Package id 0\n",
+		"class/hwmon/hwmon0/temp1_max":   "80000\n",
+		"class/hwmon/hwmon0/temp1_crit":  "100000\n",
+		"class/hwmon/hwmon0/temp2_input": "42500\n",
+		"class/hwmon/hwmon0/temp2_label": "Core 0\n",
+		"class/hwmon/hwmon1/name":        "coretemp\n",
+		"class/hwmon/hwmon1/temp1_input": "47000\n",
+		"class/hwmon/hwmon1/temp1_label": "Package id 1\n",
+		"class/hwmon/hwmon2/name":        "nct6775\n",
+		"class/hwmon/hwmon2/fan1_input":  "1200\n",
+		"class/hwmon/hwmon2/fan2_input":  "800\n",
+		"class/hwmon/hwmon2/fan2_label":  "CPU fan\n",
+		// an absent sensor, its input can't be read
+		"class/hwmon/hwmon2/temp3_input": "",
+		// the old kernels have the sensors in the device directory
+		"class/hwmon/hwmon3/device/name":        "acpitz\n",
+		"class/hwmon/hwmon3/device/temp1_input": "27800\n",
+		"devices/platform/coretemp.0/uevent":    "",
+		"devices/platform/coretemp.1/uevent":    "",
+	})
+	assert.NoError(t, os.Symlink(filepath.Join(root, "devices", "platform", "coretemp.0"), filepath.Join(hwmon, "hwmon0", "device")))
+	assert.NoError(t, os.Symlink(filepath.Join(root, "devices", "platform", "coretemp.1"), filepath.Join(hwmon, "hwmon1", "device")))
+
+	var acc testutil.Accumulator
+	assert.NoError(t, acc.GatherError(newSensors(t, hwmon).Gather))
+	assert.Equal(t, 6, len(acc.Metrics))
+	acc.AssertContainsTaggedFields(t, "sensors", map[string]interface{}{
+		"temp_input": 45.0,
+		"temp_max":   80.0,
+		"temp_crit":  100.0,
+	}, map[string]string{"chip": "coretemp-coretemp.0", "label": "Package id 0"})
+	acc.AssertContainsTaggedFields(t, "sensors", map[string]interface{}{
+		"temp_input": 42.5,
+	}, map[string]string{"chip": "coretemp-coretemp.0", "label": "Core 0"})
+	acc.AssertContainsTaggedFields(t, "sensors", map[string]interface{}{
+		"temp_input": 47.0,
+	}, map[string]string{"chip": "coretemp-coretemp.1", "label": "Package id 1"})
+	acc.AssertContainsTaggedFields(t, "sensors", map[string]interface{}{
+		"fan_input": int64(1200),
+	}, map[string]string{"chip": "nct6775", "label": "fan1"})
+	acc.AssertContainsTaggedFields(t, "sensors", map[string]interface{}{
+		"fan_input": int64(800),
+	}, map[string]string{"chip": "nct6775", "label": "CPU fan"})
+	acc.AssertContainsTaggedFields(t, "sensors", map[string]interface{}{
+		"temp_input": 27.8,
+	}, map[string]string{"chip": "acpitz", "label": "temp1"})
+}
+
+func TestGatherWithoutHwmon(t *testing.T) {
+	root, err := ioutil.TempDir("", "sensors")
+	assert.NoError(t, err)
+	defer os.RemoveAll(root)
+
+	var acc testutil.Accumulator
+	assert.NoError(t, acc.GatherError(newSensors(t, filepath.Join(root, "hwmon")).Gather))
+	assert.Empty(t, acc.Metrics)
+}
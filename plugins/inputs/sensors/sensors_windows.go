@@ -0,0 +1,69 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+//go:build windows
+// +build windows
+
+package sensors
+
+import (
+	"fmt"
+	"unsafe"
+
+	"github.com/influxdata/telegraf"
+
+	win "github.com/aws/amazon-cloudwatch-agent/plugins/inputs/win_perf_counters"
+)
+
+const (
+	// the temperature of the ACPI thermal zones in Kelvin, the counter of the MSAcpi_ThermalZoneTemperature class
+	thermalZoneCounter = `\Thermal Zone Information(*)\Temperature`
+	thermalZoneChip    = "thermal_zone"
+	absoluteZero       = 273.15
+)
+
+// gather reads the temperature of the thermal zones, the counter is instantaneous so a single sample is enough.
+func (s *Sensors) gather(acc telegraf.Accumulator) error {
+	var query win.PDH_HQUERY
+	var counter win.PDH_HCOUNTER
+	if ret := win.PdhOpenQuery(0, 0, &query); ret != win.ERROR_SUCCESS {
+		return fmt.Errorf("sensors: %s", win.PdhFormatError(ret))
+	}
+	defer win.PdhCloseQuery(query)
+
+	ret := win.PdhAddEnglishCounter(query, thermalZoneCounter, 0, &counter)
+	if ret == win.PDH_CSTATUS_NO_OBJECT || ret == win.PDH_CSTATUS_NO_COUNTER {
+		// most of the virtual machines have no thermal zone
+		s.Log.Debugf("No thermal zone: %s", win.PdhFormatError(ret))
+		return nil
+	} else if ret != win.ERROR_SUCCESS {
+		return fmt.Errorf("sensors: %s", win.PdhFormatError(ret))
+	}
+	if ret := win.PdhCollectQueryData(query); ret != win.ERROR_SUCCESS {
+		return fmt.Errorf("sensors: %s", win.PdhFormatError(ret))
+	}
+
+	var bufSize uint32
+	var bufCount uint32
+	var emptyBuf [1]win.PDH_FMT_COUNTERVALUE_ITEM_DOUBLE // need at least 1 addressable null ptr.
+	ret = win.PdhGetFormattedCounterArrayDouble(counter, &bufSize, &bufCount, &emptyBuf[0])
+	if ret == win.ERROR_SUCCESS || ret == win.PDH_NO_DATA {
+		return nil
+	} else if ret != win.PDH_MORE_DATA {
+		return fmt.Errorf("sensors: %s", win.PdhFormatError(ret))
+	}
+	// the size of the buffer is in bytes, the names of the instances are stored after the items
+	itemSize := uint32(unsafe.Sizeof(win.PDH_FMT_COUNTERVALUE_ITEM_DOUBLE{}))
+	filledBuf := make([]win.PDH_FMT_COUNTERVALUE_ITEM_DOUBLE, bufSize/itemSize+1)
+	if ret := win.PdhGetFormattedCounterArrayDouble(counter, &bufSize, &bufCount, &filledBuf[0]); ret != win.ERROR_SUCCESS {
+		return fmt.Errorf("sensors: %s", win.PdhFormatError(ret))
+	}
+	for _, item := range filledBuf[:bufCount] {
+		if item.FmtValue.CStatus != win.PDH_CSTATUS_VALID_DATA && item.FmtValue.CStatus != win.PDH_CSTATUS_NEW_DATA {
+			continue
+		}
+		tags := map[string]string{"chip": thermalZoneChip, "label": win.UTF16PtrToString(item.SzName)}
+		acc.AddFields(measurement, map[string]interface{}{"temp_input": item.FmtValue.DoubleValue - absoluteZero}, tags)
+	}
+	return nil
+}
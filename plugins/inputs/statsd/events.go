@@ -0,0 +1,287 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package statsd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/amazon-cloudwatch-agent/logs"
+)
+
+const (
+	// the prefixes of the dogstatsd events and service checks, see
+	// https://docs.datadoghq.com/developers/dogstatsd/datagram_shell
+	eventPrefix        = "_e{"
+	serviceCheckPrefix = "_sc|"
+
+	eventType        = "event"
+	serviceCheckType = "service_check"
+
+	defaultEventsDestination = "cloudwatchlogs"
+)
+
+var serviceCheckStatuses = []string{"ok", "warning", "critical", "unknown"}
+
+// dataDogEvent is the structured log event of a dogstatsd event or service check.
+type dataDogEvent struct {
+	Type           string            `json:"type"`
+	Title          string            `json:"title,omitempty"`
+	Text           string            `json:"text,omitempty"`
+	Name           string            `json:"name,omitempty"`
+	Status         string            `json:"status,omitempty"`
+	CheckMessage   string            `json:"message,omitempty"`
+	Hostname       string            `json:"hostname,omitempty"`
+	Priority       string            `json:"priority,omitempty"`
+	AlertType      string            `json:"alert_type,omitempty"`
+	AggregationKey string            `json:"aggregation_key,omitempty"`
+	SourceTypeName string            `json:"source_type_name,omitempty"`
+	Tags           map[string]string `json:"tags,omitempty"`
+
+	t time.Time
+}
+
+func (e *dataDogEvent) Message() string {
+	message, err := json.Marshal(e)
+	if err != nil {
+		return ""
+	}
+	return string(message)
+}
+
+func (e *dataDogEvent) Time() time.Time {
+	return e.t
+}
+
+func (e *dataDogEvent) Done() {}
+
+// isDataDogEvent tells whether the line is a dogstatsd event or service check rather than a metric.
+func isDataDogEvent(line string) bool {
+	return strings.HasPrefix(line, eventPrefix) || strings.HasPrefix(line, serviceCheckPrefix)
+}
+
+// parseDataDogEvent parses the dogstatsd event or service check of the line and queues it for the events log
+// group, it's dropped when no log group is configured.
+func (s *Statsd) parseDataDogEvent(line string) error {
+	var e *dataDogEvent
+	var err error
+	if strings.HasPrefix(line, eventPrefix) {
+		e, err = parseEvent(line)
+	} else {
+		e, err = parseServiceCheck(line)
+	}
+	if err != nil {
+		log.Printf("E! Error: %v, unable to parse the %s: %s\n", err, e.Type, line)
+		return err
+	}
+	if s.eventsSrc != nil {
+		s.eventsSrc.queue(e)
+	}
+	return nil
+}
+
+// parseEvent parses _e{<title length>,<text length>}:<title>|<text>|d:<timestamp>|h:<hostname>|p:<priority>|
+// t:<alert type>|k:<aggregation key>|s:<source type name>|#<tags>
+func parseEvent(line string) (*dataDogEvent, error) {
+	e := &dataDogEvent{Type: eventType, t: time.Now()}
+	header := strings.SplitN(line[len(eventPrefix):], "}:", 2)
+	if len(header) != 2 {
+		return e, errors.New("missing the lengths of the title and text")
+	}
+	lengths := strings.Split(header[0], ",")
+	if len(lengths) != 2 {
+		return e, errors.New("missing the lengths of the title and text")
+	}
+	titleLength, err := strconv.Atoi(lengths[0])
+	if err != nil || titleLength < 1 {
+		return e, fmt.Errorf("invalid title length %s", lengths[0])
+	}
+	textLength, err := strconv.Atoi(lengths[1])
+	if err != nil || textLength < 0 {
+		return e, fmt.Errorf("invalid text length %s", lengths[1])
+	}
+	body := header[1]
+	if len(body) < titleLength+1+textLength || body[titleLength] != '|' {
+		return e, errors.New("the title and text are shorter than their lengths")
+	}
+	fields := body[titleLength+1+textLength:]
+	if fields != "" && fields[0] != '|' {
+		return e, errors.New("the title and text are longer than their lengths")
+	}
+	e.Title = body[:titleLength]
+	// the new lines of the text are escaped to keep the event on one line
+	e.Text = strings.ReplaceAll(body[titleLength+1:titleLength+1+textLength], `\n`, "\n")
+	for _, field := range splitEventFields(fields) {
+		switch {
+		case strings.HasPrefix(field, "d:"):
+			e.t, err = parseEventTimestamp(field[2:])
+		case strings.HasPrefix(field, "h:"):
+			e.Hostname = field[2:]
+		case strings.HasPrefix(field, "p:"):
+			e.Priority = field[2:]
+		case strings.HasPrefix(field, "t:"):
+			e.AlertType = field[2:]
+		case strings.HasPrefix(field, "k:"):
+			e.AggregationKey = field[2:]
+		case strings.HasPrefix(field, "s:"):
+			e.SourceTypeName = field[2:]
+		case strings.HasPrefix(field, "#"):
+			e.Tags = parseEventTags(field[1:])
+		}
+		if err != nil {
+			return e, err
+		}
+	}
+	return e, nil
+}
+
+// parseServiceCheck parses _sc|<name>|<status>|d:<timestamp>|h:<hostname>|#<tags>|m:<message>
+func parseServiceCheck(line string) (*dataDogEvent, error) {
+	e := &dataDogEvent{Type: serviceCheckType, t: time.Now()}
+	fields := strings.SplitN(line[len(serviceCheckPrefix):], "|", 2)
+	if len(fields) != 2 || fields[0] == "" {
+		return e, errors.New("missing the name and status")
+	}
+	e.Name = fields[0]
+	fields = strings.SplitN(fields[1], "|", 2)
+	status, err := strconv.Atoi(fields[0])
+	if err != nil || status < 0 || status >= len(serviceCheckStatuses) {
+		return e, fmt.Errorf("invalid status %s, it must be 0, 1, 2 or 3", fields[0])
+	}
+	e.Status = serviceCheckStatuses[status]
+	if len(fields) == 1 {
+		return e, nil
+	}
+	for _, field := range splitEventFields("|" + fields[1]) {
+		switch {
+		case strings.HasPrefix(field, "d:"):
+			e.t, err = parseEventTimestamp(field[2:])
+		case strings.HasPrefix(field, "h:"):
+			e.Hostname = field[2:]
+		case strings.HasPrefix(field, "#"):
+			e.Tags = parseEventTags(field[1:])
+		case strings.HasPrefix(field, "m:"):
+			e.CheckMessage = strings.ReplaceAll(field[2:], `\n`, "\n")
+		}
+		if err != nil {
+			return e, err
+		}
+	}
+	return e, nil
+}
+
+// splitEventFields splits the optional fields following the title and text or the status, each starting with "|".
+func splitEventFields(fields string) []string {
+	if fields == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimPrefix(fields, "|"), "|")
+}
+
+func parseEventTimestamp(timestamp string) (time.Time, error) {
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid timestamp %s", timestamp)
+	}
+	return time.Unix(seconds, 0), nil
+}
+
+// parseEventTags parses the tags of the event, every tag is kept since they aren't dimensions.
+func parseEventTags(tagstr string) map[string]string {
+	tags := map[string]string{}
+	for _, tag := range strings.Split(tagstr, ",") {
+		ts := strings.SplitN(tag, ":", 2)
+		if ts[0] == "" {
+			continue
+		}
+		if len(ts) == 1 {
+			tags[ts[0]] = ""
+		} else {
+			tags[ts[0]] = ts[1]
+		}
+	}
+	return tags
+}
+
+// eventsLogSrc publishes the dogstatsd events and service checks to the events log group, the ones arriving while
+// its queue is full are dropped.
+type eventsLogSrc struct {
+	group       string
+	stream      string
+	destination string
+
+	events   chan *dataDogEvent
+	dropped  func()
+	stopOnce sync.Once
+	done     chan struct{}
+}
+
+func newEventsLogSrc(group, stream, destination string, size int, dropped func()) *eventsLogSrc {
+	if destination == "" {
+		destination = defaultEventsDestination
+	}
+	return &eventsLogSrc{
+		group:       group,
+		stream:      stream,
+		destination: destination,
+		events:      make(chan *dataDogEvent, size),
+		dropped:     dropped,
+		done:        make(chan struct{}),
+	}
+}
+
+func (s *eventsLogSrc) queue(e *dataDogEvent) {
+	select {
+	case s.events <- e:
+	default:
+		s.dropped()
+	}
+}
+
+func (s *eventsLogSrc) SetOutput(fn func(logs.LogEvent)) {
+	if fn == nil {
+		return
+	}
+	go func() {
+		for {
+			select {
+			case e := <-s.events:
+				fn(e)
+			case <-s.done:
+				fn(nil)
+				return
+			}
+		}
+	}()
+}
+
+func (s *eventsLogSrc) Group() string {
+	return s.group
+}
+
+func (s *eventsLogSrc) Stream() string {
+	return s.stream
+}
+
+func (s *eventsLogSrc) Destination() string {
+	return s.destination
+}
+
+func (s *eventsLogSrc) Description() string {
+	return "statsd events"
+}
+
+func (s *eventsLogSrc) Retention() int {
+	return -1
+}
+
+func (s *eventsLogSrc) Stop() {
+	s.stopOnce.Do(func() { close(s.done) })
+}
@@ -0,0 +1,95 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package statsd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aws/amazon-cloudwatch-agent/logs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseEvent(t *testing.T) {
+	e, err := parseEvent(`_e{10,16}:deployment|web 1.2\nstarted|d:1600000000|h:web-01|p:low|t:success|k:deploy|s:jenkins|#env:prod,canary`)
+	assert.NoError(t, err)
+	assert.Equal(t, "deployment", e.Title)
+	assert.Equal(t, "web 1.2\nstarted", e.Text)
+	assert.Equal(t, time.Unix(1600000000, 0), e.Time())
+	assert.Equal(t, "web-01", e.Hostname)
+	assert.Equal(t, "low", e.Priority)
+	assert.Equal(t, "success", e.AlertType)
+	assert.Equal(t, "deploy", e.AggregationKey)
+	assert.Equal(t, "jenkins", e.SourceTypeName)
+	assert.Equal(t, map[string]string{"env": "prod", "canary": ""}, e.Tags)
+
+	e, err = parseEvent("_e{5,0}:title|")
+	assert.NoError(t, err)
+	assert.Equal(t, `{"type":"event","title":"title"}`, e.Message())
+
+	for _, line := range []string{"_e{5}:title|text", "_e{5,10}:title|text", "_e{x,4}:title|text", "_e{5,4}:title|text|d:now", "_e{5,3}:title|text"} {
+		_, err = parseEvent(line)
+		assert.Error(t, err, line)
+	}
+}
+
+func TestParseServiceCheck(t *testing.T) {
+	e, err := parseServiceCheck(`_sc|redis.can_connect|2|d:1600000000|h:cache-01|#env:prod|m:connection\nrefused`)
+	assert.NoError(t, err)
+	assert.Equal(t, "redis.can_connect", e.Name)
+	assert.Equal(t, "critical", e.Status)
+	assert.Equal(t, time.Unix(1600000000, 0), e.Time())
+	assert.Equal(t, "cache-01", e.Hostname)
+	assert.Equal(t, map[string]string{"env": "prod"}, e.Tags)
+	assert.Equal(t, "connection\nrefused", e.CheckMessage)
+
+	e, err = parseServiceCheck("_sc|app.ok|0")
+	assert.NoError(t, err)
+	assert.Equal(t, `{"type":"service_check","name":"app.ok","status":"ok"}`, e.Message())
+
+	for _, line := range []string{"_sc|app.ok", "_sc||0", "_sc|app.ok|4"} {
+		_, err = parseServiceCheck(line)
+		assert.Error(t, err, line)
+	}
+}
+
+func TestParse_DataDogEvents(t *testing.T) {
+	s := NewTestStatsd()
+	s.ParseDataDogTags = true
+	s.eventsSrc = newEventsLogSrc("statsd-events", "host", "", 10, func() {})
+
+	assert.NoError(t, s.parseStatsdLine("_e{5,4}:title|text|#env:prod"))
+	assert.NoError(t, s.parseStatsdLine("_sc|app.ok|1"))
+	// the events aren't metrics
+	assert.Empty(t, s.counters)
+	assert.Empty(t, s.gauges)
+
+	srcs := s.FindLogSrc()
+	assert.Len(t, srcs, 1)
+	assert.Equal(t, "statsd-events", srcs[0].Group())
+	assert.Equal(t, "cloudwatchlogs", srcs[0].Destination())
+	assert.Empty(t, s.FindLogSrc())
+
+	received := make(chan logs.LogEvent, 3)
+	srcs[0].SetOutput(func(e logs.LogEvent) { received <- e })
+	assert.JSONEq(t, `{"type":"event","title":"title","text":"text","tags":{"env":"prod"}}`, (<-received).Message())
+	assert.JSONEq(t, `{"type":"service_check","name":"app.ok","status":"warning"}`, (<-received).Message())
+	srcs[0].Stop()
+	assert.Nil(t, <-received)
+}
+
+func TestParse_DataDogEventsDropped(t *testing.T) {
+	s := NewTestStatsd()
+	dropped := 0
+	s.eventsSrc = newEventsLogSrc("statsd-events", "host", "", 1, func() { dropped++ })
+
+	assert.NoError(t, s.parseStatsdLine("_sc|app.ok|0"))
+	assert.NoError(t, s.parseStatsdLine("_sc|app.ok|0"))
+	assert.Equal(t, 1, dropped)
+
+	// without a log group the events are dropped
+	s = NewTestStatsd()
+	assert.NoError(t, s.parseStatsdLine("_sc|app.ok|0"))
+	assert.Nil(t, s.FindLogSrc())
+}
@@ -4,16 +4,25 @@
 package statsd
 
 import (
+	"bufio"
+	"bytes"
 	"errors"
 	"fmt"
+	"io"
 	"log"
+	"math"
 	"net"
+	"os"
 	"sort"
 	"strconv"
 	"strings"
 	"sync"
 	"time"
 
+	"github.com/aws/amazon-cloudwatch-agent/internal"
+	"github.com/aws/amazon-cloudwatch-agent/internal/cardinality"
+	"github.com/aws/amazon-cloudwatch-agent/internal/handoff"
+	"github.com/aws/amazon-cloudwatch-agent/logs"
 	"github.com/aws/amazon-cloudwatch-agent/metric/distribution"
 	"github.com/aws/amazon-cloudwatch-agent/plugins/inputs/statsd/graphite"
 
@@ -21,6 +30,7 @@ import (
 
 	"github.com/influxdata/telegraf"
 	"github.com/influxdata/telegraf/plugins/inputs"
+	"github.com/influxdata/telegraf/selfstat"
 )
 
 const (
@@ -32,14 +42,38 @@ const (
 
 	defaultSeparator           = "_"
 	defaultAllowPendingMessage = 10000
+
+	// the value of the datadog tags once their key has max_data_dog_tag_values values
+	otherTagValue = "<other>"
+
+	// the error of the reads of a closed listener or connection
+	closedConnError = "use of closed network connection"
+
+	// the measurement of the counters of the listener reported by the internal input
+	statsMeasurement = "statsd"
+	// the wait for the next packet before a batch is queued again while the parser is behind
+	batchRetryInterval = 10 * time.Millisecond
+
+	// the timings are published as distributions, CloudWatch computes their percentiles
+	percentileModeDistribution = "distribution"
+	// the percentiles of the timings are computed by the agent and published as metrics of their own
+	percentileModeMetrics = "metrics"
+
+	// the counters are published as the sum of their increments since the last collection
+	counterModeSum = "sum"
+	// the counters are published as the per second rate of their increments since the last collection
+	counterModeRate = "rate"
 )
 
+var defaultPercentiles = []float64{50, 90, 99, 99.9}
+
 var dropwarn = "E! Error: statsd message queue full. " +
 	"We have dropped %d messages so far. " +
 	"You may want to increase allowed_pending_messages in the config\n"
 
 type Statsd struct {
-	// Address & Port to serve from
+	// Address & Port to serve from, udp without scheme, else udp://, tcp:// or unix:// followed by the address or
+	// the path of the socket
 	ServiceAddress string
 
 	// Number of messages allowed to queue up in between calls to Gather. If this
@@ -56,6 +90,42 @@ type Statsd struct {
 	// This flag enables parsing of tags in the dogstatsd extention to the
 	// statsd protocol (http://docs.datadoghq.com/guides/dogstatsd/)
 	ParseDataDogTags bool
+	// AllowedDataDogTags are the keys of the datadog tags kept as dimensions, every key is kept when empty.
+	AllowedDataDogTags []string
+	// MaxDataDogTagValues is the number of distinct values of each datadog tag key kept as dimensions since the
+	// start, the other values are replaced by <other> so a tag like a request id doesn't publish a metric by
+	// request. The values aren't limited when 0.
+	MaxDataDogTagValues int
+
+	// PercentileMode is how the timings are published, as distributions or as the metrics of their Percentiles
+	PercentileMode string `toml:"percentile_mode"`
+	// Percentiles are the percentiles of the timings published in the metrics mode, p50, p90, p99 and p99.9 by
+	// default
+	Percentiles []float64 `toml:"percentiles"`
+
+	// CounterMode is how the counters are published, as the sums of their increments or as their per second rates
+	CounterMode string `toml:"counter_mode"`
+
+	// ReadBufferSize is the size in bytes of the receive buffer of the udp socket, the system default when 0. The
+	// kernel drops the packets arriving once the buffer is full, e.g. during a burst.
+	ReadBufferSize int `toml:"read_buffer_size"`
+	// ReadBatchSize is the number of udp packets batched into one message of the queue while the queue is full,
+	// the packets are only dropped once the batch is full. The packets aren't batched when 0 or 1.
+	ReadBatchSize int `toml:"read_batch_size"`
+
+	// EventsLogGroupName is the log group the dogstatsd events and service checks are published to as structured
+	// log events, they are dropped when empty. EventsLogStreamName is their log stream, and EventsDestination the
+	// output they are published with, cloudwatchlogs by default.
+	EventsLogGroupName  string `toml:"events_log_group_name"`
+	EventsLogStreamName string `toml:"events_log_stream_name"`
+	EventsDestination   string `toml:"events_destination"`
+
+	// MaxSeries is the number of distinct series, metric names with their dimensions, published by interval of
+	// MaxSeriesInterval, the minute by default. The series over it are dropped, or aggregated into the overflow
+	// series of their metric when SeriesOverflow is aggregate. The series aren't limited when 0.
+	MaxSeries         int               `toml:"max_series"`
+	SeriesOverflow    string            `toml:"series_overflow"`
+	MaxSeriesInterval internal.Duration `toml:"max_series_interval"`
 
 	// UDPPacketSize is deprecated, it's only here for legacy support
 	// we now always create 1 max size buffer and then copy only what we need
@@ -80,12 +150,51 @@ type Statsd struct {
 	sets     map[string]cachedset
 	timings  map[string]cachedtimings
 
-	// bucket -> influx templates
+	// Templates map the parts of the dotted buckets to the metric name, the dimensions and the field, e.g.
+	// "api.*.*.latency measurement.service.endpoint.measurement" publishes api.users.get.latency as api_latency
+	// with the service and endpoint dimensions instead of a metric by endpoint
 	Templates []string
 
-	listener *net.UDPConn
+	listener net.PacketConn
+	// the listener of the tcp and unix services, and its connections
+	streamListener net.Listener
+	connections    map[net.Conn]bool
+	connectionsMtx sync.Mutex
 
 	graphiteParser *graphite.GraphiteParser
+
+	// the values of each datadog tag key seen since the start, and the keys reported for reaching
+	// max_data_dog_tag_values
+	tagValues   map[string]map[string]bool
+	tagsLimited map[string]bool
+
+	// the time of the last collection, the rates of the counters are the ones since then
+	lastGather time.Time
+
+	// the packets batched while the queue is full
+	batch        []byte
+	batchPackets int
+
+	// the port of the udp socket, its kernel drops are read from the udp tables of procRoot
+	udpPort         int
+	procRoot        string
+	kernelDropsRead bool
+	lastKernelDrops int64
+
+	// the source of the dogstatsd events and service checks, and whether the log agent found it
+	eventsSrc      *eventsLogSrc
+	eventsSrcFound bool
+
+	// the guard of the series over max_series
+	seriesGuard *cardinality.Guard
+
+	// the counters of the listener reported by the internal input
+	packetsReceived selfstat.Stat
+	packetsDropped  selfstat.Stat
+	packetsBatched  selfstat.Stat
+	kernelDrops     selfstat.Stat
+	eventsReceived  selfstat.Stat
+	eventsDropped   selfstat.Stat
 }
 
 // One statsd metric, form is <bucket>:<value>|<mtype>|@<samplerate>
@@ -119,6 +228,9 @@ type cachedcounter struct {
 	name   string
 	fields map[string]interface{}
 	tags   map[string]string
+	// the fractions of the sampled increments not counted yet, and the increments since the last collection
+	remainders map[string]float64
+	increments map[string]float64
 }
 
 type cachedtimings struct {
@@ -132,7 +244,8 @@ func (_ *Statsd) Description() string {
 }
 
 const sampleConfig = `
-  ## Address and port to host UDP listener on
+  ## Address and port to host UDP listener on, or tcp://, udp:// or unix:// followed by the address or the
+  ## path of the socket, e.g. "tcp://:8125" or "unix:///var/run/statsd.sock"
   service_address = ":8125"
 
   ## The following configuration options control when telegraf clears it's cache
@@ -153,17 +266,52 @@ const sampleConfig = `
   ## Parses tags in the datadog statsd format
   ## http://docs.datadoghq.com/guides/dogstatsd/
   parse_data_dog_tags = false
+  ## The keys of the datadog tags kept as dimensions, every key is kept when empty
+  # allowed_data_dog_tags = ["environment", "service"]
+  ## The distinct values of each datadog tag key kept as dimensions, the other values are replaced by <other>
+  # max_data_dog_tag_values = 100
+
+  ## How the timings & histograms are published, "distribution" publishes their distribution and CloudWatch
+  ## computes their percentiles, "metrics" publishes the percentiles computed by the agent as metrics of their own
+  # percentile_mode = "distribution"
+  ## The percentiles published in the metrics mode
+  # percentiles = [50.0, 90.0, 99.0, 99.9]
+
+  ## How the counters are published, "sum" publishes the sum of their increments since the last collection,
+  ## "rate" publishes the per second rate of their increments since the last collection
+  # counter_mode = "sum"
 
   ## Statsd data translation templates, more info can be read here:
   ## https://github.com/influxdata/telegraf/blob/master/docs/DATA_FORMATS_INPUT.md#graphite
+  ## The parts of the buckets mapped to tags are published as dimensions instead of parts of the metric name.
   # templates = [
-  #     "cpu.* measurement*"
+  #     "cpu.* measurement*",
+  #     "api.*.*.latency measurement.service.endpoint.measurement"
   # ]
 
   ## Number of UDP messages allowed to queue up, once filled,
   ## the statsd server will start dropping packets
   allowed_pending_messages = 10000
 
+  ## The dogstatsd events (_e{...}) and service checks (_sc|...) are published as structured log events to this
+  ## log group of the events_destination output, and dropped when it's not set.
+  # events_log_group_name = "statsd-events"
+  # events_log_stream_name = "STREAM_NAME"
+  # events_destination = "cloudwatchlogs"
+
+  ## The receive buffer of the UDP socket in bytes, the system default when 0. The kernel drops the
+  ## packets once it is full, see the kernel_drops counter.
+  # read_buffer_size = 0
+  ## The UDP packets batched into one queued message while the queue is full, before they are dropped.
+  # read_batch_size = 0
+
+  ## The distinct series (metric names with their dimensions) published by max_series_interval, the series over
+  ## it are dropped, or aggregated into the series of their metric with the only dimension overflow="true" when
+  ## series_overflow is "aggregate". The series aren't limited when 0, see the cardinality series_limited counter.
+  # max_series = 0
+  # series_overflow = "drop"
+  # max_series_interval = "60s"
+
   ## The aggregation interval for the metrics
   metric_aggregation_interval = "60s"
 
@@ -177,9 +325,14 @@ func (s *Statsd) Gather(acc telegraf.Accumulator) error {
 	s.Lock()
 	defer s.Unlock()
 	now := time.Now()
+	s.reportKernelDrops()
 
 	for _, metric := range s.timings {
-		acc.AddFields(metric.name, metric.fields, metric.tags, now)
+		fields := metric.fields
+		if s.PercentileMode == percentileModeMetrics {
+			fields = s.percentileFields(metric.fields)
+		}
+		acc.AddFields(metric.name, fields, metric.tags, now)
 	}
 	if s.DeleteTimings {
 		s.timings = make(map[string]cachedtimings)
@@ -192,8 +345,23 @@ func (s *Statsd) Gather(acc telegraf.Accumulator) error {
 		s.gauges = make(map[string]cachedgauge)
 	}
 
+	elapsed := now.Sub(s.lastGather).Seconds()
+	s.lastGather = now
 	for _, metric := range s.counters {
-		acc.AddFields(metric.name, metric.fields, metric.tags, now)
+		if s.CounterMode != counterModeRate {
+			acc.AddFields(metric.name, metric.fields, metric.tags, now)
+			continue
+		}
+		fields := make(map[string]interface{}, len(metric.fields))
+		for field := range metric.fields {
+			rate := float64(0)
+			if elapsed > 0 {
+				rate = metric.increments[field] / elapsed
+			}
+			fields[field] = rate
+			metric.increments[field] = 0
+		}
+		acc.AddFields(metric.name, fields, metric.tags, now)
 	}
 	if s.DeleteCounters {
 		s.counters = make(map[string]cachedcounter)
@@ -214,6 +382,29 @@ func (s *Statsd) Gather(acc telegraf.Accumulator) error {
 }
 
 func (s *Statsd) Start(_ telegraf.Accumulator) error {
+	switch s.PercentileMode {
+	case "":
+		s.PercentileMode = percentileModeDistribution
+	case percentileModeDistribution, percentileModeMetrics:
+	default:
+		return fmt.Errorf("invalid percentile_mode %q, it must be %s or %s", s.PercentileMode, percentileModeDistribution, percentileModeMetrics)
+	}
+	if len(s.Percentiles) == 0 {
+		s.Percentiles = defaultPercentiles
+	}
+	switch s.CounterMode {
+	case "":
+		s.CounterMode = counterModeSum
+	case counterModeSum, counterModeRate:
+	default:
+		return fmt.Errorf("invalid counter_mode %q, it must be %s or %s", s.CounterMode, counterModeSum, counterModeRate)
+	}
+	for _, p := range s.Percentiles {
+		if p <= 0 || p > 100 {
+			return fmt.Errorf("invalid percentile %v, it must be greater than 0 and at most 100", p)
+		}
+	}
+
 	// Make data structures
 	s.done = make(chan struct{})
 	s.in = make(chan []byte, s.AllowedPendingMessages)
@@ -222,54 +413,308 @@ func (s *Statsd) Start(_ telegraf.Accumulator) error {
 	s.counters = make(map[string]cachedcounter)
 	s.sets = make(map[string]cachedset)
 	s.timings = make(map[string]cachedtimings)
+	s.lastGather = time.Now()
+	if s.procRoot == "" {
+		s.procRoot = "/proc"
+	}
+	s.registerStats()
 
 	if s.MetricSeparator == "" {
 		s.MetricSeparator = defaultSeparator
 	}
+	// the templates are parsed once, an invalid one would otherwise leave every bucket unmapped
+	if err := (&graphite.Config{Separator: s.MetricSeparator, Templates: s.Templates}).Validate(); err != nil {
+		return fmt.Errorf("invalid templates: %v", err)
+	}
+	p, err := graphite.NewGraphiteParser(s.MetricSeparator, s.Templates, nil)
+	if err != nil {
+		return fmt.Errorf("invalid templates: %v", err)
+	}
+	s.graphiteParser = p
 
-	s.wg.Add(2)
-	// Start the UDP listener
-	go s.udpListen()
+	s.seriesGuard, err = cardinality.NewGuard("statsd", s.MaxSeries, s.SeriesOverflow, s.MaxSeriesInterval.Duration)
+	if err != nil {
+		return err
+	}
+
+	if s.EventsLogGroupName != "" {
+		s.eventsSrc = newEventsLogSrc(s.EventsLogGroupName, s.EventsLogStreamName, s.EventsDestination,
+			s.AllowedPendingMessages, func() { s.eventsDropped.Incr(1) })
+	}
+
+	network, address := parseServiceAddress(s.ServiceAddress)
+	switch network {
+	case "udp", "udp4", "udp6":
+		s.wg.Add(1)
+		go s.udpListen(network, address)
+	case "tcp", "tcp4", "tcp6", "unix":
+		ln, err := listenStream(network, address)
+		if err != nil {
+			return fmt.Errorf("failed to listen on %s: %v", s.ServiceAddress, err)
+		}
+		s.streamListener = ln
+		s.connections = map[net.Conn]bool{}
+		s.wg.Add(1)
+		go s.streamListen()
+	default:
+		return fmt.Errorf("unknown protocol %q in service_address %s, it must be udp, tcp or unix", network, s.ServiceAddress)
+	}
+
+	s.wg.Add(1)
 	// Start the line parser
 	go s.parser()
 	log.Printf("I! Started the statsd service on %s\n", s.ServiceAddress)
 	return nil
 }
 
+// parseServiceAddress splits the address in its network and the address of the network, an address without scheme
+// is an udp one.
+func parseServiceAddress(serviceAddress string) (string, string) {
+	spl := strings.SplitN(serviceAddress, "://", 2)
+	if len(spl) != 2 {
+		return "udp", serviceAddress
+	}
+	return spl[0], spl[1]
+}
+
+// listenStream listens on the tcp address or the unix socket, the listener of the previous agent is reused after a
+// handoff, with its connections. The socket file left by an agent which didn't stop cleanly is replaced.
+func listenStream(network, address string) (net.Listener, error) {
+	ln, err := handoff.Listen(network, address)
+	if err != nil && network == "unix" {
+		if _, statErr := os.Stat(address); statErr == nil {
+			if err := os.Remove(address); err != nil {
+				return nil, err
+			}
+			ln, err = handoff.Listen(network, address)
+		}
+	}
+	return ln, err
+}
+
 // udpListen starts listening for udp packets on the configured port.
-func (s *Statsd) udpListen() error {
+func (s *Statsd) udpListen(network, address string) error {
 	defer s.wg.Done()
 	var err error
-	address, _ := net.ResolveUDPAddr("udp", s.ServiceAddress)
-	s.listener, err = net.ListenUDP("udp", address)
+	// the socket of the previous agent is reused after a handoff, the packets queued meanwhile are read
+	s.listener, err = handoff.ListenPacket(network, address)
 	if err != nil {
 		log.Fatalf("ERROR: ListenUDP - %s", err)
 	}
 	log.Println("I! Statsd listener listening on: ", s.listener.LocalAddr().String())
+	if s.ReadBufferSize > 0 {
+		if rb, ok := s.listener.(interface{ SetReadBuffer(int) error }); !ok {
+			log.Printf("W! Statsd listener can't set the read buffer of %s", s.ServiceAddress)
+		} else if err := rb.SetReadBuffer(s.ReadBufferSize); err != nil {
+			log.Printf("W! Statsd listener can't set the read buffer of %s to %d bytes: %v", s.ServiceAddress, s.ReadBufferSize, err)
+		}
+	}
+	if addr, ok := s.listener.LocalAddr().(*net.UDPAddr); ok {
+		s.Lock()
+		s.udpPort = addr.Port
+		s.Unlock()
+	}
 
 	buf := make([]byte, UDP_MAX_PACKET_SIZE)
+	deadline := false
 	for {
 		select {
 		case <-s.done:
 			return nil
 		default:
-			n, _, err := s.listener.ReadFromUDP(buf)
+			if s.batchPackets > 0 {
+				// the batch is queued again once the parser caught up, even without a new packet
+				s.listener.SetReadDeadline(time.Now().Add(batchRetryInterval))
+				deadline = true
+			} else if deadline {
+				s.listener.SetReadDeadline(time.Time{})
+				deadline = false
+			}
+			n, _, err := s.listener.ReadFrom(buf)
+			if netErr, ok := err.(net.Error); ok && netErr.Timeout() {
+				s.queueBatch()
+				continue
+			}
 			if err != nil && !strings.Contains(err.Error(), "closed network") {
 				log.Printf("E! Error READ: %s\n", err.Error())
 				continue
 			}
-			bufCopy := make([]byte, n)
-			copy(bufCopy, buf[:n])
+			s.packetsReceived.Incr(1)
+			s.queuePacket(buf[:n])
+		}
+	}
+}
+
+// registerStats registers the counters of the listener, reported by the internal input.
+func (s *Statsd) registerStats() {
+	tags := map[string]string{"service_address": s.ServiceAddress}
+	s.packetsReceived = selfstat.Register(statsMeasurement, "packets_received", tags)
+	s.packetsDropped = selfstat.Register(statsMeasurement, "packets_dropped", tags)
+	s.packetsBatched = selfstat.Register(statsMeasurement, "packets_batched", tags)
+	s.kernelDrops = selfstat.Register(statsMeasurement, "kernel_drops", tags)
+	s.eventsReceived = selfstat.Register(statsMeasurement, "events_received", tags)
+	s.eventsDropped = selfstat.Register(statsMeasurement, "events_dropped", tags)
+}
+
+// queuePacket queues a copy of the packet for the parser. While the queue is full, the packets are batched into one
+// message of up to read_batch_size packets, queued as soon as the queue has room, so a burst isn't dropped while the
+// parser catches up.
+func (s *Statsd) queuePacket(packet []byte) {
+	s.queueBatch()
+	if s.batchPackets == 0 {
+		bufCopy := make([]byte, len(packet))
+		copy(bufCopy, packet)
+		select {
+		case s.in <- bufCopy:
+			return
+		default:
+		}
+	}
+	if s.batchPackets < s.ReadBatchSize {
+		if s.batchPackets > 0 {
+			s.batch = append(s.batch, '\n')
+		}
+		s.batch = append(s.batch, packet...)
+		s.batchPackets++
+		s.packetsBatched.Incr(1)
+		return
+	}
+	s.drops++
+	s.packetsDropped.Incr(1)
+	if s.drops == 1 || s.AllowedPendingMessages == 0 || s.drops%s.AllowedPendingMessages == 0 {
+		log.Printf(dropwarn, s.drops)
+	}
+}
+
+// queueBatch queues the packets batched when the queue has room.
+func (s *Statsd) queueBatch() {
+	if s.batchPackets == 0 {
+		return
+	}
+	select {
+	case s.in <- s.batch:
+		s.batch, s.batchPackets = nil, 0
+	default:
+	}
+}
+
+// reportKernelDrops updates the packets dropped by the kernel for the udp socket since it was bound, a warning is
+// logged when they grew since the last collection since the metrics are then lower than what the clients sent.
+func (s *Statsd) reportKernelDrops() {
+	if s.udpPort == 0 {
+		return
+	}
+	drops, ok, err := readUDPDrops(s.procRoot, s.udpPort)
+	if err != nil || !ok {
+		return
+	}
+	if s.kernelDropsRead && drops > s.lastKernelDrops {
+		log.Printf("W! The kernel dropped %d statsd packets of %s since the last collection, the read_buffer_size may be too small", drops-s.lastKernelDrops, s.ServiceAddress)
+	}
+	s.kernelDropsRead, s.lastKernelDrops = true, drops
+	s.kernelDrops.Set(drops)
+}
+
+// streamListen accepts the connections of the tcp or unix listener until it is closed, after the connections handed
+// off by the previous agent.
+func (s *Statsd) streamListen() {
+	defer s.wg.Done()
+	log.Println("I! Statsd listener listening on: ", s.streamListener.Addr().String())
+
+	var wg sync.WaitGroup
+	serve := func(c net.Conn, unread []byte) {
+		s.connectionsMtx.Lock()
+		s.connections[c] = true
+		s.connectionsMtx.Unlock()
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.read(c, unread)
+		}()
+	}
+	for _, c := range handoff.Conns(s.streamListener) {
+		serve(c.Conn, c.Unread)
+	}
+	for {
+		c, err := s.streamListener.Accept()
+		if err != nil {
+			if !strings.HasSuffix(err.Error(), closedConnError) {
+				log.Printf("E! Error accepting a statsd connection: %s\n", err.Error())
+			}
+			break
+		}
+		serve(c, nil)
+	}
 
+	s.connectionsMtx.Lock()
+	for c := range s.connections {
+		if handoff.Handing() {
+			// the readers stop at once and keep their connection for the new agent
+			c.SetReadDeadline(time.Now())
+		} else {
+			c.Close()
+		}
+	}
+	s.connectionsMtx.Unlock()
+	wg.Wait()
+}
+
+// read queues the lines of the connection, after the bytes unread by the previous agent. The lines aren't dropped
+// when the queue is full, the reads wait for the parser instead so the clients are slowed down rather than losing
+// metrics. The lines longer than UDP_MAX_PACKET_SIZE are discarded.
+func (s *Statsd) read(c net.Conn, unread []byte) {
+	defer func() {
+		s.connectionsMtx.Lock()
+		delete(s.connections, c)
+		s.connectionsMtx.Unlock()
+	}()
+	defer c.Close()
+	r := bufio.NewReaderSize(io.MultiReader(bytes.NewReader(unread), c), UDP_MAX_PACKET_SIZE)
+	var line []byte
+	oversized := false
+	for {
+		chunk, err := r.ReadSlice('\n')
+		if !oversized {
+			line = append(line, chunk...)
+			if len(line) > UDP_MAX_PACKET_SIZE {
+				oversized, line = true, nil
+			}
+		}
+		if err == bufio.ErrBufferFull {
+			continue
+		}
+		if netErr, ok := err.(net.Error); ok && netErr.Timeout() && handoff.Handing() {
+			// the partial line is completed by the bytes read by the new agent
+			s.keepConn(c, line)
+			return
+		}
+		if oversized {
+			log.Printf("W! Dropping a statsd line of %s larger than %d bytes\n", c.RemoteAddr(), UDP_MAX_PACKET_SIZE)
+		} else if len(bytes.TrimSpace(line)) > 0 {
 			select {
-			case s.in <- bufCopy:
-			default:
-				s.drops++
-				if s.drops == 1 || s.AllowedPendingMessages == 0 || s.drops%s.AllowedPendingMessages == 0 {
-					log.Printf(dropwarn, s.drops)
+			case s.in <- append([]byte(nil), line...):
+			case <-s.done:
+				if handoff.Handing() {
+					// the line not queued and the bytes buffered are read again by the new agent
+					buffered, _ := r.Peek(r.Buffered())
+					s.keepConn(c, append(line, buffered...))
 				}
+				return
 			}
 		}
+		line, oversized = line[:0], false
+		if err != nil {
+			if err != io.EOF && !strings.HasSuffix(err.Error(), closedConnError) {
+				log.Printf("E! Error reading a statsd connection: %s\n", err.Error())
+			}
+			return
+		}
+	}
+}
+
+func (s *Statsd) keepConn(c net.Conn, unread []byte) {
+	if err := handoff.KeepConn(s.streamListener, c, unread); err != nil {
+		log.Printf("E! Unable to hand off the statsd connection of %s: %v\n", c.RemoteAddr(), err)
 	}
 }
 
@@ -298,6 +743,12 @@ func (s *Statsd) parser() error {
 // parseStatsdLine will parse the given statsd line, validating it as it goes.
 // If the line is valid, it will be cached for the next call to Gather()
 func (s *Statsd) parseStatsdLine(line string) error {
+	if isDataDogEvent(line) {
+		if s.eventsReceived != nil {
+			s.eventsReceived.Incr(1)
+		}
+		return s.parseDataDogEvent(line)
+	}
 
 	lineTags := make(map[string]string)
 	if s.ParseDataDogTags {
@@ -325,8 +776,8 @@ func (s *Statsd) parseStatsdLine(line string) error {
 						k = ts[0]
 						v = ts[1]
 					}
-					if k != "" {
-						lineTags[k] = v
+					if k != "" && s.allowDataDogTag(k) {
+						lineTags[k] = s.limitDataDogTag(k, v)
 					}
 				}
 			} else {
@@ -365,6 +816,8 @@ func (s *Statsd) parseStatsdLine(line string) error {
 				samplerate, err := strconv.ParseFloat(sr[1:], 64)
 				if err != nil {
 					log.Printf(errmsg, err.Error(), line)
+				} else if samplerate <= 0 || samplerate > 1 {
+					log.Printf(errmsg, "it must be greater than 0 and at most 1", line)
 				} else {
 					// sample rate successfully parsed
 					m.samplerate = samplerate
@@ -410,11 +863,11 @@ func (s *Statsd) parseStatsdLine(line string) error {
 					return errors.New("Error Parsing statsd line")
 				}
 				v = int64(v2)
+				m.floatvalue = v2
+			} else {
+				m.floatvalue = float64(v)
 			}
-			// If a sample rate is given with a counter, divide value by the rate
-			if m.samplerate != 0 && m.mtype == "c" {
-				v = int64(float64(v) / m.samplerate)
-			}
+			// the sample rate is applied as the counter is aggregated, so the fractions of the increments add up
 			m.intvalue = v
 		case "s":
 			m.strvalue = pipesplit[0]
@@ -441,6 +894,14 @@ func (s *Statsd) parseStatsdLine(line string) error {
 			}
 		}
 
+		// the series over max_series are dropped or published as the overflow series of the same metric type
+		tags, ok := s.seriesGuard.Admit(m.name, m.tags)
+		if !ok {
+			continue
+		}
+		tags["metric_type"] = m.tags["metric_type"]
+		m.tags = tags
+
 		// Make a unique key for the measurement name/tags
 		var tg []string
 		for k, v := range m.tags {
@@ -455,6 +916,88 @@ func (s *Statsd) parseStatsdLine(line string) error {
 	return nil
 }
 
+// percentileFields replaces the distribution of each field of a timing with its percentiles, the field value
+// becomes p50, p90... and the other fields <field>_p50, <field>_p90...
+func (s *Statsd) percentileFields(fields map[string]interface{}) map[string]interface{} {
+	percentiles := make(map[string]interface{}, len(fields)*len(s.Percentiles))
+	for field, value := range fields {
+		d, ok := value.(distribution.Distribution)
+		if !ok || d.SampleCount() == 0 {
+			continue
+		}
+		values, counts := d.ValuesAndCounts()
+		for _, p := range s.Percentiles {
+			name := "p" + strings.Replace(strconv.FormatFloat(p, 'f', -1, 64), ".", "_", -1)
+			if field != defaultFieldName {
+				name = field + "_" + name
+			}
+			percentiles[name] = percentile(values, counts, d.SampleCount(), p)
+		}
+	}
+	return percentiles
+}
+
+// percentile returns the smallest value of the distribution with at least p percent of the count at or below it.
+// The values of the distribution are approximated by their bucket, so the percentiles are as precise as the buckets.
+func percentile(values, counts []float64, total, p float64) float64 {
+	order := make([]int, len(values))
+	for i := range order {
+		order[i] = i
+	}
+	sort.Slice(order, func(i, j int) bool { return values[order[i]] < values[order[j]] })
+	rank := total * p / 100
+	cumulative := 0.0
+	for _, i := range order {
+		cumulative += counts[i]
+		if cumulative >= rank {
+			return values[i]
+		}
+	}
+	return values[order[len(order)-1]]
+}
+
+// allowDataDogTag returns whether the datadog tag key is kept as a dimension.
+func (s *Statsd) allowDataDogTag(key string) bool {
+	if len(s.AllowedDataDogTags) == 0 {
+		return true
+	}
+	for _, allowed := range s.AllowedDataDogTags {
+		if key == allowed {
+			return true
+		}
+	}
+	return false
+}
+
+// limitDataDogTag returns the value of the datadog tag, or <other> for the new values of a key once it has
+// max_data_dog_tag_values values.
+func (s *Statsd) limitDataDogTag(key, value string) string {
+	if s.MaxDataDogTagValues <= 0 {
+		return value
+	}
+	if s.tagValues == nil {
+		s.tagValues = make(map[string]map[string]bool)
+		s.tagsLimited = make(map[string]bool)
+	}
+	values, ok := s.tagValues[key]
+	if !ok {
+		values = make(map[string]bool)
+		s.tagValues[key] = values
+	}
+	if values[value] {
+		return value
+	}
+	if len(values) >= s.MaxDataDogTagValues {
+		if !s.tagsLimited[key] {
+			s.tagsLimited[key] = true
+			log.Printf("W! The datadog tag %s has %d values, its new values are replaced by %s", key, len(values), otherTagValue)
+		}
+		return otherTagValue
+	}
+	values[value] = true
+	return value
+}
+
 // parseName parses the given bucket name with the list of bucket maps in the
 // config file. If there is a match, it will parse the name of the metric and
 // map of tags.
@@ -551,18 +1094,30 @@ func (s *Statsd) aggregate(m metric) {
 		_, ok := s.counters[m.hash]
 		if !ok {
 			s.counters[m.hash] = cachedcounter{
-				name:   m.name,
-				fields: make(map[string]interface{}),
-				tags:   m.tags,
+				name:       m.name,
+				fields:     make(map[string]interface{}),
+				tags:       m.tags,
+				remainders: make(map[string]float64),
+				increments: make(map[string]float64),
 			}
 		}
+		cached := s.counters[m.hash]
 		// check if the field exists
-		_, ok = s.counters[m.hash].fields[m.field]
+		_, ok = cached.fields[m.field]
 		if !ok {
-			s.counters[m.hash].fields[m.field] = int64(0)
+			cached.fields[m.field] = int64(0)
 		}
-		s.counters[m.hash].fields[m.field] =
-			s.counters[m.hash].fields[m.field].(int64) + m.intvalue
+		// a sampled increment counts for 1/samplerate increments, e.g. 3 increments of 1 sampled at @0.3 count
+		// for 10, the fraction left is carried to the next increment
+		increment := float64(m.intvalue)
+		if m.samplerate > 0 {
+			increment = m.floatvalue / m.samplerate
+		}
+		total := increment + cached.remainders[m.field]
+		whole := math.Trunc(total)
+		cached.remainders[m.field] = total - whole
+		cached.fields[m.field] = cached.fields[m.field].(int64) + int64(whole)
+		cached.increments[m.field] += increment
 	case "g":
 		// check if the measurement exists
 		_, ok := s.gauges[m.hash]
@@ -606,12 +1161,30 @@ func (s *Statsd) aggregate(m metric) {
 func (s *Statsd) Stop() {
 	log.Println("D! Stopping the statsd service")
 	close(s.done)
-	s.listener.Close()
+	if s.streamListener != nil {
+		s.streamListener.Close()
+	} else {
+		s.listener.Close()
+	}
 	s.wg.Wait()
 	close(s.in)
+	if s.eventsSrc != nil {
+		s.eventsSrc.Stop()
+	}
 	log.Println("D! Stopped the statsd service")
 }
 
+// FindLogSrc returns the source of the dogstatsd events and service checks to the log agent, once.
+func (s *Statsd) FindLogSrc() []logs.LogSrc {
+	s.Lock()
+	defer s.Unlock()
+	if s.eventsSrc == nil || s.eventsSrcFound {
+		return nil
+	}
+	s.eventsSrcFound = true
+	return []logs.LogSrc{s.eventsSrc}
+}
+
 func init() {
 	inputs.Add("statsd", func() telegraf.Input {
 		return &Statsd{
@@ -6,9 +6,15 @@ package statsd
 import (
 	"errors"
 	"fmt"
+	"io/ioutil"
 	"math"
+	"net"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
+	"github.com/aws/amazon-cloudwatch-agent/internal/cardinality"
 	"github.com/aws/amazon-cloudwatch-agent/metric/distribution"
 	"github.com/aws/amazon-cloudwatch-agent/metric/distribution/seh1"
 
@@ -254,6 +260,40 @@ func TestParse_Counters(t *testing.T) {
 	}
 }
 
+// The fractions of the sampled increments add up
+func TestParse_CountersSampleRate(t *testing.T) {
+	s := NewTestStatsd()
+	for i := 0; i < 3; i++ {
+		assert.NoError(t, s.parseStatsdLine("sampled.inc:1|c|@0.3"))
+	}
+	// the sample rates out of range are ignored
+	assert.NoError(t, s.parseStatsdLine("out.of.range:1|c|@2"))
+
+	assert.NoError(t, test_validate_counter("sampled_inc", 10, s.counters))
+	assert.NoError(t, test_validate_counter("out_of_range", 1, s.counters))
+}
+
+func TestParse_CountersAsRates(t *testing.T) {
+	s := NewTestStatsd()
+	s.CounterMode = counterModeRate
+	s.lastGather = time.Now().Add(-10 * time.Second)
+	acc := &testutil.Accumulator{}
+
+	assert.NoError(t, s.parseStatsdLine("requests:50|c"))
+	// sampled 1/50 of the time
+	assert.NoError(t, s.parseStatsdLine("requests:1|c|@0.02"))
+	s.Gather(acc)
+
+	assert.Equal(t, 1, len(acc.Metrics))
+	assert.InDelta(t, 10, acc.Metrics[0].Fields["value"], 0.1)
+
+	// the rate of a counter kept between the collections is the one of its increments since the last collection
+	acc.ClearMetrics()
+	s.Gather(acc)
+	assert.Equal(t, 1, len(acc.Metrics))
+	assert.Equal(t, float64(0), acc.Metrics[0].Fields["value"])
+}
+
 // Tests low-level functionality of timings
 func TestParse_Timings(t *testing.T) {
 	s := NewTestStatsd()
@@ -293,6 +333,83 @@ func TestParse_Timings(t *testing.T) {
 	assert.Equal(t, dist, fields[defaultFieldName])
 }
 
+// Tests that the percentiles of the timings are published instead of their distribution in the metrics mode
+func TestParse_TimingsPercentiles(t *testing.T) {
+	s := NewTestStatsd()
+	s.PercentileMode = percentileModeMetrics
+	s.Percentiles = []float64{50, 90, 99.9}
+	acc := &testutil.Accumulator{}
+
+	for i := 1; i <= 100; i++ {
+		assert.NoError(t, s.parseStatsdLine(fmt.Sprintf("test.timing:%d|ms", i)))
+	}
+	// sampled 1/10 of the time, weighs as much as 10 timings
+	assert.NoError(t, s.parseStatsdLine("test.timing:1000|ms|@0.1"))
+
+	s.Gather(acc)
+
+	metrics := acc.Metrics
+	assert.Equal(t, 1, len(metrics))
+	fields := metrics[0].Fields
+	assert.Equal(t, 3, len(fields))
+	// the values are the ones of the buckets of the distribution
+	assert.InEpsilon(t, 55, fields["p50"], 0.05)
+	assert.InEpsilon(t, 100, fields["p90"], 0.05)
+	assert.InEpsilon(t, 1000, fields["p99_9"], 0.05)
+}
+
+func TestStart_InvalidPercentiles(t *testing.T) {
+	s := &Statsd{PercentileMode: "summary"}
+	assert.EqualError(t, s.Start(&testutil.Accumulator{}), `invalid percentile_mode "summary", it must be distribution or metrics`)
+
+	s = &Statsd{Percentiles: []float64{50, 101}}
+	assert.EqualError(t, s.Start(&testutil.Accumulator{}), "invalid percentile 101, it must be greater than 0 and at most 100")
+}
+
+func TestStart_StreamListeners(t *testing.T) {
+	dir, err := ioutil.TempDir("", "statsd")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	socket := filepath.Join(dir, "statsd.sock")
+	// the socket file of an agent which didn't stop cleanly
+	assert.NoError(t, ioutil.WriteFile(socket, nil, 0600))
+
+	for _, serviceAddress := range []string{"tcp://127.0.0.1:0", "unix://" + socket} {
+		s := &Statsd{ServiceAddress: serviceAddress, AllowedPendingMessages: 1, MetricSeparator: "_"}
+		acc := &testutil.Accumulator{}
+		assert.NoError(t, s.Start(acc))
+
+		addr := s.streamListener.Addr()
+		c, err := net.Dial(addr.Network(), addr.String())
+		assert.NoError(t, err)
+		// more lines than the queue holds, none is dropped
+		_, err = c.Write([]byte("test.gauge:1|g\ntest.gauge:2|g\ntest.gauge:3|g\ntest.count:1|c\n"))
+		assert.NoError(t, err)
+		c.Close()
+
+		assert.Eventually(t, func() bool {
+			acc.ClearMetrics()
+			s.Gather(acc)
+			return len(acc.Metrics) == 2
+		}, 5*time.Second, 10*time.Millisecond, serviceAddress)
+		acc.AssertContainsFields(t, "test_gauge", map[string]interface{}{"value": float64(3)})
+		s.Stop()
+	}
+	// the socket file is removed once the listener is closed
+	_, err = os.Stat(socket)
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestStart_UnknownProtocol(t *testing.T) {
+	s := &Statsd{ServiceAddress: "sctp://:8125"}
+	assert.EqualError(t, s.Start(&testutil.Accumulator{}), `unknown protocol "sctp" in service_address sctp://:8125, it must be udp, tcp or unix`)
+}
+
+func TestStart_InvalidCounterMode(t *testing.T) {
+	s := &Statsd{CounterMode: "average"}
+	assert.EqualError(t, s.Start(&testutil.Accumulator{}), `invalid counter_mode "average", it must be sum or rate`)
+}
+
 func TestParseScientificNotation(t *testing.T) {
 	s := NewTestStatsd()
 	sciNotationLines := []string{
@@ -766,6 +883,35 @@ func TestParse_DataDogTags(t *testing.T) {
 	}
 }
 
+// Test that only the allowed DataDog tags are kept, with a limited number of values
+func TestParse_DataDogTagsLimited(t *testing.T) {
+	s := NewTestStatsd()
+	s.ParseDataDogTags = true
+	s.AllowedDataDogTags = []string{"environment", "request_id"}
+	s.MaxDataDogTagValues = 2
+
+	lines := []string{
+		"my_counter:1|c|#host:localhost,environment:prod,request_id:a1",
+		"my_counter:1|c|#host:localhost,environment:prod,request_id:b2",
+		"my_counter:1|c|#host:localhost,environment:prod,request_id:c3",
+		"my_counter:1|c|#host:localhost,environment:prod,request_id:d4",
+		"my_counter:1|c|#host:localhost,environment:prod,request_id:a1",
+	}
+	for _, line := range lines {
+		assert.NoError(t, s.parseStatsdLine(line))
+	}
+
+	counts := map[string]int64{}
+	for _, c := range s.counters {
+		_, ok := c.tags["host"]
+		assert.False(t, ok, "the host tag isn't allowed")
+		assert.Equal(t, "prod", c.tags["environment"])
+		counts[c.tags["request_id"]] = c.fields["value"].(int64)
+	}
+	// the values after the first 2 of request_id are replaced
+	assert.Equal(t, map[string]int64{"a1": 2, "b2": 1, "<other>": 2}, counts)
+}
+
 func tagsForItem(m interface{}) map[string]string {
 	switch m.(type) {
 	case map[string]cachedcounter:
@@ -1482,3 +1628,53 @@ func test_validate_gauge(
 func init() {
 	distribution.NewDistribution = seh1.NewSEH1Distribution
 }
+
+func TestParse_TemplateDimensions(t *testing.T) {
+	s := NewTestStatsd()
+	s.Templates = []string{
+		"api.*.*.latency measurement.service.endpoint.measurement",
+	}
+
+	for _, line := range []string{"api.users.get.latency:1|c", "api.orders.post.latency:2|c"} {
+		assert.NoError(t, s.parseStatsdLine(line))
+	}
+
+	// every endpoint of every service is a metric of the same name
+	assert.Equal(t, 2, len(s.counters))
+	for _, c := range s.counters {
+		assert.Equal(t, "api_latency", c.name)
+		assert.Contains(t, []string{"users", "orders"}, c.tags["service"])
+		assert.Contains(t, []string{"get", "post"}, c.tags["endpoint"])
+	}
+}
+
+func TestStart_InvalidTemplates(t *testing.T) {
+	s := &Statsd{Templates: []string{"api.* service.endpoint"}}
+	assert.EqualError(t, s.Start(&testutil.Accumulator{}), "invalid templates: no measurement in template `service.endpoint`")
+}
+
+func TestParse_MaxSeries(t *testing.T) {
+	s := NewTestStatsd()
+	s.ParseDataDogTags = true
+	s.seriesGuard, _ = cardinality.NewGuard("statsd", 2, cardinality.OverflowAggregate, time.Minute)
+
+	for _, id := range []string{"1", "2", "3", "4"} {
+		assert.NoError(t, s.parseStatsdLine("requests:1|c|#request_id:"+id))
+	}
+
+	// the series over the limit are counted in the overflow series
+	assert.Equal(t, 3, len(s.counters))
+	for _, c := range s.counters {
+		if c.tags["overflow"] == "true" {
+			assert.Equal(t, map[string]string{"metric_type": "counter", "overflow": "true"}, c.tags)
+			assert.Equal(t, int64(2), c.fields["value"])
+		} else {
+			assert.Contains(t, []string{"1", "2"}, c.tags["request_id"])
+		}
+	}
+}
+
+func TestStart_InvalidSeriesOverflow(t *testing.T) {
+	s := &Statsd{MaxSeries: 10, SeriesOverflow: "sample"}
+	assert.EqualError(t, s.Start(&testutil.Accumulator{}), `invalid series overflow "sample", it must be drop or aggregate`)
+}
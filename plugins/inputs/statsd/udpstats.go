@@ -0,0 +1,70 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package statsd
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// udpTables are the tables of the udp sockets of the network namespace under the proc root, on Linux.
+var udpTables = []string{"net/udp", "net/udp6"}
+
+// readUDPDrops returns the packets dropped by the kernel for the udp sockets bound to the port, e.g. when their
+// receive buffer is full. It returns false when the tables aren't available, off Linux.
+func readUDPDrops(procRoot string, port int) (int64, bool, error) {
+	var drops int64
+	found := false
+	for _, table := range udpTables {
+		f, err := os.Open(filepath.Join(procRoot, table))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return 0, false, err
+		}
+		n, err := parseUDPDrops(f, port)
+		f.Close()
+		if err != nil {
+			return 0, false, err
+		}
+		drops += n
+		found = true
+	}
+	return drops, found, nil
+}
+
+// parseUDPDrops sums the drops column of the sockets of the port, the table is like
+//
+//	 sl  local_address rem_address   st tx_queue rx_queue tr tm->when retrnsmt   uid  timeout inode ref pointer drops
+//	123: 00000000:1FBD 00000000:0000 07 00000000:00000000 00:00000000 00000000     0        0 12345 2 0000000000000000 8
+func parseUDPDrops(r io.Reader, port int) (int64, error) {
+	var drops int64
+	scanner := bufio.NewScanner(r)
+	// skip the header
+	scanner.Scan()
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 13 {
+			continue
+		}
+		local := strings.Split(fields[1], ":")
+		if len(local) != 2 {
+			continue
+		}
+		if p, err := strconv.ParseInt(local[1], 16, 32); err != nil || int(p) != port {
+			continue
+		}
+		n, err := strconv.ParseInt(fields[len(fields)-1], 10, 64)
+		if err != nil {
+			continue
+		}
+		drops += n
+	}
+	return drops, scanner.Err()
+}
@@ -0,0 +1,102 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package statsd
+
+import (
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/influxdata/telegraf/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+const udpTable = `   sl  local_address rem_address   st tx_queue rx_queue tr tm->when retrnsmt   uid  timeout inode ref pointer drops
+  123: 00000000:1FBD 00000000:0000 07 00000000:00000000 00:00000000 00000000     0        0 12345 2 0000000000000000 8
+  124: 0100007F:0035 00000000:0000 07 00000000:00000000 00:00000000 00000000   101        0 12346 2 0000000000000000 3
+`
+
+const udp6Table = `  sl  local_address                         remote_address                        st tx_queue rx_queue tr tm->when retrnsmt   uid  timeout inode ref pointer drops
+  125: 00000000000000000000000000000000:1FBD 00000000000000000000000000000000:0000 07 00000000:00000000 00:00000000 00000000     0        0 12347 2 0000000000000000 4
+`
+
+func TestReadUDPDrops(t *testing.T) {
+	dir, err := ioutil.TempDir("", "statsd")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	assert.NoError(t, os.Mkdir(filepath.Join(dir, "net"), 0700))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "net", "udp"), []byte(udpTable), 0600))
+
+	drops, ok, err := readUDPDrops(dir, 8125)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, int64(8), drops)
+
+	// the ipv6 sockets of the port are counted as well
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "net", "udp6"), []byte(udp6Table), 0600))
+	drops, _, err = readUDPDrops(dir, 8125)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(12), drops)
+
+	drops, _, err = readUDPDrops(dir, 8126)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), drops)
+
+	// off Linux
+	_, ok, err = readUDPDrops(filepath.Join(dir, "nonexistent"), 8125)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+}
+
+func TestQueuePacket_Batched(t *testing.T) {
+	s := NewTestStatsd()
+	s.ServiceAddress = ":18125"
+	s.in = make(chan []byte, 1)
+	s.ReadBatchSize = 2
+	s.AllowedPendingMessages = 1
+	s.registerStats()
+
+	for _, packet := range []string{"a:1|c", "b:1|c", "c:1|c\nd:1|c", "e:1|c"} {
+		s.queuePacket([]byte(packet))
+	}
+	// the queue is full after the first packet, the next two are batched and the last one is dropped
+	assert.Equal(t, "a:1|c", string(<-s.in))
+	assert.Equal(t, 1, s.drops)
+	assert.Equal(t, int64(1), s.packetsDropped.Get())
+	assert.Equal(t, int64(2), s.packetsBatched.Get())
+
+	s.queueBatch()
+	assert.Equal(t, "b:1|c\nc:1|c\nd:1|c", string(<-s.in))
+	assert.Equal(t, 0, s.batchPackets)
+}
+
+func TestStart_UDPReadBuffer(t *testing.T) {
+	s := &Statsd{ServiceAddress: "udp://127.0.0.1:0", AllowedPendingMessages: 10, MetricSeparator: "_", ReadBufferSize: 1 << 20, ReadBatchSize: 8}
+	acc := &testutil.Accumulator{}
+	assert.NoError(t, s.Start(acc))
+	defer s.Stop()
+
+	port := func() int {
+		s.Lock()
+		defer s.Unlock()
+		return s.udpPort
+	}
+	assert.Eventually(t, func() bool { return port() != 0 }, 5*time.Second, 10*time.Millisecond)
+	c, err := net.Dial("udp", "127.0.0.1:"+strconv.Itoa(port()))
+	assert.NoError(t, err)
+	defer c.Close()
+	_, err = c.Write([]byte("test.count:1|c"))
+	assert.NoError(t, err)
+
+	assert.Eventually(t, func() bool {
+		acc.ClearMetrics()
+		s.Gather(acc)
+		return len(acc.Metrics) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, int64(1), s.packetsReceived.Get())
+}
@@ -0,0 +1,147 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package systemd
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/amazon-cloudwatch-agent/internal"
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/plugins/inputs"
+)
+
+const (
+	measurement    = "systemd"
+	defaultTimeout = 5 * time.Second
+	properties     = "Id,LoadState,ActiveState,SubState,NRestarts"
+)
+
+var sampleConfig = `
+  ## The units reported, a unit without a type suffix is a service, e.g. "nginx" is "nginx.service".
+  units = ["nginx.service", "sshd"]
+
+  ## Maximum time systemctl is allowed to run.
+  # timeout = "5s"
+`
+
+// runCommand is replaced in tests.
+var runCommand = func(ctx context.Context, name string, args ...string) ([]byte, error) {
+	return exec.CommandContext(ctx, name, args...).Output()
+}
+
+type Systemd struct {
+	Units   []string          `toml:"units"`
+	Timeout internal.Duration `toml:"timeout"`
+
+	Log telegraf.Logger `toml:"-"`
+
+	// the NRestarts of each unit at the previous gather
+	lastRestarts map[string]int64
+}
+
+func (s *Systemd) SampleConfig() string {
+	return sampleConfig
+}
+
+func (s *Systemd) Description() string {
+	return "Report whether the configured systemd units are active or failed, and how often they are restarted."
+}
+
+func (s *Systemd) Init() error {
+	if len(s.Units) == 0 {
+		return fmt.Errorf("systemd: units is required")
+	}
+	if s.Timeout.Duration <= 0 {
+		s.Timeout.Duration = defaultTimeout
+	}
+	s.lastRestarts = map[string]int64{}
+	return nil
+}
+
+func (s *Systemd) Gather(acc telegraf.Accumulator) error {
+	ctx, cancel := context.WithTimeout(context.Background(), s.Timeout.Duration)
+	defer cancel()
+	args := append([]string{"show", "--property=" + properties, "--"}, s.Units...)
+	out, err := runCommand(ctx, "systemctl", args...)
+	if err != nil {
+		return fmt.Errorf("failed to run systemctl show: %v", err)
+	}
+
+	units := parseShow(string(out))
+	if len(units) != len(s.Units) {
+		return fmt.Errorf("unexpected output from systemctl show, expected %d units, got %d", len(s.Units), len(units))
+	}
+	for _, u := range units {
+		if u["LoadState"] == "not-found" {
+			s.Log.Warnf("Unit %s is not found", u["Id"])
+		}
+		fields := map[string]interface{}{
+			"active": boolToInt(u["ActiveState"] == "active"),
+			"failed": boolToInt(u["ActiveState"] == "failed"),
+		}
+		// NRestarts is only reported by systemd 235 and later
+		if restartCount, err := strconv.ParseInt(u["NRestarts"], 10, 64); err == nil {
+			fields["restart_count"] = restartCount
+			// the counter restarts from 0 when the unit is reloaded
+			if last, ok := s.lastRestarts[u["Id"]]; ok && restartCount >= last {
+				fields["restarts"] = restartCount - last
+			} else {
+				fields["restarts"] = int64(0)
+			}
+			s.lastRestarts[u["Id"]] = restartCount
+		}
+		acc.AddFields(measurement, fields, map[string]string{"unit": u["Id"]})
+	}
+	return nil
+}
+
+// parseShow parses the output of "systemctl show", the properties of each unit are separated by an empty line:
+//
+//	Id=nginx.service
+//	LoadState=loaded
+//	ActiveState=active
+//	SubState=running
+//	NRestarts=0
+//
+//	Id=sshd.service
+//	...
+func parseShow(out string) []map[string]string {
+	var units []map[string]string
+	var unit map[string]string
+	for _, line := range strings.Split(out, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			unit = nil
+			continue
+		}
+		kv := strings.SplitN(line, "=", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		if unit == nil {
+			unit = map[string]string{}
+			units = append(units, unit)
+		}
+		unit[kv[0]] = kv[1]
+	}
+	return units
+}
+
+func boolToInt(b bool) int64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+func init() {
+	inputs.Add("systemd", func() telegraf.Input {
+		return &Systemd{}
+	})
+}
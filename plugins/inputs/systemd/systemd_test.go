@@ -0,0 +1,119 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package systemd
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/influxdata/telegraf/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func mockShow(t *testing.T, outputs ...string) func() {
+	original := runCommand
+	runCommand = func(ctx context.Context, name string, args ...string) ([]byte, error) {
+		assert.Equal(t, "systemctl", name)
+		assert.Equal(t, []string{"show", "--property=" + properties, "--", "nginx.service", "sshd"}, args)
+		if len(outputs) == 0 {
+			return nil, errors.New("exit status 1")
+		}
+		out := outputs[0]
+		outputs = outputs[1:]
+		return []byte(out), nil
+	}
+	return func() { runCommand = original }
+}
+
+func newSystemd(t *testing.T) *Systemd {
+	s := &Systemd{Units: []string{"nginx.service", "sshd"}, Log: testutil.Logger{}}
+	assert.NoError(t, s.Init())
+	return s
+}
+
+func TestGather(t *testing.T) {
+	defer mockShow(t, `Id=nginx.service
+LoadState=loaded
+ActiveState=active
+SubState=running
+NRestarts=2
+
+Id=sshd.service
+LoadState=loaded
+ActiveState=failed
+SubState=failed
+NRestarts=5
+`, `Id=nginx.service
+LoadState=loaded
+ActiveState=activating
+SubState=auto-restart
+NRestarts=4
+
+Id=sshd.service
+LoadState=loaded
+ActiveState=active
+SubState=running
+NRestarts=0
+`)()
+	s := newSystemd(t)
+
+	var acc testutil.Accumulator
+	assert.NoError(t, s.Gather(&acc))
+	acc.AssertContainsTaggedFields(t, "systemd",
+		map[string]interface{}{"active": int64(1), "failed": int64(0), "restart_count": int64(2), "restarts": int64(0)},
+		map[string]string{"unit": "nginx.service"})
+	acc.AssertContainsTaggedFields(t, "systemd",
+		map[string]interface{}{"active": int64(0), "failed": int64(1), "restart_count": int64(5), "restarts": int64(0)},
+		map[string]string{"unit": "sshd.service"})
+
+	// restarts are counted since the previous gather, the counter of sshd was reset by a reload
+	acc.ClearMetrics()
+	assert.NoError(t, s.Gather(&acc))
+	acc.AssertContainsTaggedFields(t, "systemd",
+		map[string]interface{}{"active": int64(0), "failed": int64(0), "restart_count": int64(4), "restarts": int64(2)},
+		map[string]string{"unit": "nginx.service"})
+	acc.AssertContainsTaggedFields(t, "systemd",
+		map[string]interface{}{"active": int64(1), "failed": int64(0), "restart_count": int64(0), "restarts": int64(0)},
+		map[string]string{"unit": "sshd.service"})
+}
+
+func TestGatherWithoutRestartCount(t *testing.T) {
+	defer mockShow(t, `Id=nginx.service
+LoadState=loaded
+ActiveState=active
+SubState=running
+NRestarts=
+
+Id=sshd.service
+LoadState=not-found
+ActiveState=inactive
+SubState=dead
+`)()
+	s := newSystemd(t)
+
+	var acc testutil.Accumulator
+	assert.NoError(t, s.Gather(&acc))
+	acc.AssertContainsTaggedFields(t, "systemd",
+		map[string]interface{}{"active": int64(1), "failed": int64(0)},
+		map[string]string{"unit": "nginx.service"})
+	acc.AssertContainsTaggedFields(t, "systemd",
+		map[string]interface{}{"active": int64(0), "failed": int64(0)},
+		map[string]string{"unit": "sshd.service"})
+}
+
+func TestGatherErrors(t *testing.T) {
+	defer mockShow(t, "Id=nginx.service\nActiveState=active\n")()
+	s := newSystemd(t)
+
+	var acc testutil.Accumulator
+	assert.Error(t, s.Gather(&acc))
+	// systemctl failed
+	assert.Error(t, s.Gather(&acc))
+	assert.Empty(t, acc.Metrics)
+}
+
+func TestInitWithoutUnits(t *testing.T) {
+	assert.Error(t, (&Systemd{}).Init())
+}
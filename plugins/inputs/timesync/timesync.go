@@ -0,0 +1,257 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package timesync
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/amazon-cloudwatch-agent/internal"
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/plugins/inputs"
+)
+
+const (
+	SourceAuto   = "auto"
+	SourceChrony = "chrony"
+	SourceNtp    = "ntp"
+
+	measurement    = "timesync"
+	defaultTimeout = 5 * time.Second
+)
+
+var sampleConfig = `
+  ## The time synchronization daemon queried, "chrony" runs "chronyc tracking", "ntp" runs "ntpq -c rv".
+  ## "auto" uses chrony if chronyc is installed, ntp otherwise.
+  # source = "auto"
+
+  ## Maximum time the query is allowed to run.
+  # timeout = "5s"
+`
+
+// lookPath and runCommand are replaced in tests.
+var (
+	lookPath   = exec.LookPath
+	runCommand = func(ctx context.Context, name string, args ...string) ([]byte, error) {
+		return exec.CommandContext(ctx, name, args...).CombinedOutput()
+	}
+)
+
+type TimeSync struct {
+	Source  string            `toml:"source"`
+	Timeout internal.Duration `toml:"timeout"`
+}
+
+func (t *TimeSync) SampleConfig() string {
+	return sampleConfig
+}
+
+func (t *TimeSync) Description() string {
+	return "Report the clock offset, jitter, stratum and synchronization status of chrony or ntpd."
+}
+
+func (t *TimeSync) Init() error {
+	switch t.Source {
+	case "":
+		t.Source = SourceAuto
+	case SourceAuto, SourceChrony, SourceNtp:
+	default:
+		return fmt.Errorf("timesync source %q is invalid, must be one of %q, %q or %q", t.Source, SourceAuto, SourceChrony, SourceNtp)
+	}
+	if t.Timeout.Duration <= 0 {
+		t.Timeout.Duration = defaultTimeout
+	}
+	return nil
+}
+
+func (t *TimeSync) Gather(acc telegraf.Accumulator) error {
+	source := t.Source
+	if source == SourceAuto {
+		source = SourceNtp
+		if _, err := lookPath("chronyc"); err == nil {
+			source = SourceChrony
+		}
+	}
+
+	var (
+		fields map[string]interface{}
+		tags   map[string]string
+		err    error
+	)
+	switch source {
+	case SourceChrony:
+		var out []byte
+		if out, err = t.run("chronyc", "-n", "tracking"); err == nil {
+			fields, tags, err = parseChronyTracking(string(out))
+		}
+	default:
+		var out []byte
+		if out, err = t.run("ntpq", "-n", "-c", "rv"); err == nil {
+			fields, tags, err = parseNtpqReadVar(string(out))
+		}
+	}
+	if err != nil {
+		// the daemon not answering means the clock is not synchronized either
+		acc.AddFields(measurement, map[string]interface{}{"synced": 0}, map[string]string{"source": source})
+		return err
+	}
+	tags["source"] = source
+	acc.AddFields(measurement, fields, tags)
+	return nil
+}
+
+func (t *TimeSync) run(name string, args ...string) ([]byte, error) {
+	path, err := lookPath(name)
+	if err != nil {
+		return nil, fmt.Errorf("%s not found: %v", name, err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), t.Timeout.Duration)
+	defer cancel()
+	out, err := runCommand(ctx, path, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to run %s %s: %v - %s", name, strings.Join(args, " "), err, strings.TrimSpace(string(out)))
+	}
+	return out, nil
+}
+
+// parseChronyTracking parses the output of "chronyc tracking", like:
+//
+//	Reference ID    : A9FEA97B (169.254.169.123)
+//	Stratum         : 4
+//	Ref time (UTC)  : Thu May 12 14:27:07 2016
+//	System time     : 0.000020390 seconds fast of NTP time
+//	Last offset     : +0.000012651 seconds
+//	RMS offset      : 0.000025577 seconds
+//	Frequency       : 16.001 ppm slow
+//	Residual freq   : -0.000 ppm
+//	Skew            : 0.006 ppm
+//	Root delay      : 0.001655 seconds
+//	Root dispersion : 0.003307 seconds
+//	Update interval : 507.2 seconds
+//	Leap status     : Normal
+//
+// The offset is positive when the system clock is ahead of the reference, and the jitter is the RMS offset.
+// All the times are reported in seconds.
+func parseChronyTracking(out string) (map[string]interface{}, map[string]string, error) {
+	fields := map[string]interface{}{}
+	tags := map[string]string{}
+	leapStatus := ""
+	for _, line := range strings.Split(strings.TrimSpace(out), "\n") {
+		parts := strings.SplitN(line, ":", 2)
+		if len(parts) != 2 {
+			return nil, nil, fmt.Errorf("unexpected output from chronyc, expected ':' in %q", line)
+		}
+		name := strings.TrimSpace(parts[0])
+		values := strings.Fields(parts[1])
+		if len(values) == 0 {
+			continue
+		}
+		switch name {
+		case "Reference ID":
+			tags["reference_id"] = values[0]
+		case "Stratum":
+			if stratum, err := strconv.ParseInt(values[0], 10, 64); err == nil {
+				fields["stratum"] = stratum
+			}
+		case "System time":
+			if offset, err := strconv.ParseFloat(values[0], 64); err == nil {
+				if strings.Contains(parts[1], "slow") {
+					offset = -offset
+				}
+				fields["offset"] = offset
+			}
+		case "RMS offset":
+			setFloatField(fields, "jitter", values[0], 1)
+		case "Root delay":
+			setFloatField(fields, "root_delay", values[0], 1)
+		case "Root dispersion":
+			setFloatField(fields, "root_dispersion", values[0], 1)
+		case "Frequency":
+			if frequency, err := strconv.ParseFloat(values[0], 64); err == nil {
+				if strings.Contains(parts[1], "slow") {
+					frequency = -frequency
+				}
+				fields["frequency"] = frequency
+			}
+		case "Leap status":
+			leapStatus = strings.TrimSpace(parts[1])
+		}
+	}
+	if len(fields) == 0 {
+		return nil, nil, fmt.Errorf("unexpected output from chronyc: %q", out)
+	}
+	synced := 0
+	if leapStatus != "" && leapStatus != "Not synchronised" && tags["reference_id"] != "00000000" {
+		synced = 1
+	}
+	fields["synced"] = synced
+	return fields, tags, nil
+}
+
+// parseNtpqReadVar parses the output of "ntpq -c rv", like:
+//
+//	associd=0 status=0615 leap_none, sync_ntp, 1 event, clock_sync,
+//	version="ntpd 4.2.8p15@1.3728-o", processor="x86_64", system="Linux",
+//	leap=00, stratum=4, precision=-23, rootdelay=1.234, rootdisp=20.140,
+//	refid=169.254.169.123, reftime=e2d4f6a1.2b8c4e3f, tc=10, peer=12345,
+//	offset=-0.123456, frequency=-12.345, sys_jitter=0.045678, clk_jitter=0.021,
+//	clk_wander=0.003
+//
+// ntpq reports the times in milliseconds, they are converted to seconds. Its offset is positive when the system clock
+// is behind the server, so it is negated to match the offset of chrony.
+func parseNtpqReadVar(out string) (map[string]interface{}, map[string]string, error) {
+	fields := map[string]interface{}{}
+	tags := map[string]string{}
+	vars := map[string]string{}
+	for _, item := range strings.FieldsFunc(out, func(r rune) bool { return r == ',' || r == '\n' }) {
+		item = strings.TrimSpace(item)
+		for _, pair := range strings.Fields(item) {
+			if kv := strings.SplitN(pair, "=", 2); len(kv) == 2 {
+				vars[kv[0]] = strings.Trim(kv[1], `"`)
+			}
+		}
+	}
+	if _, ok := vars["stratum"]; !ok {
+		return nil, nil, fmt.Errorf("unexpected output from ntpq: %q", out)
+	}
+
+	if stratum, err := strconv.ParseInt(vars["stratum"], 10, 64); err == nil {
+		fields["stratum"] = stratum
+	}
+	setFloatField(fields, "offset", vars["offset"], -0.001)
+	setFloatField(fields, "jitter", vars["sys_jitter"], 0.001)
+	setFloatField(fields, "root_delay", vars["rootdelay"], 0.001)
+	setFloatField(fields, "root_dispersion", vars["rootdisp"], 0.001)
+	setFloatField(fields, "frequency", vars["frequency"], 1)
+	if refid, ok := vars["refid"]; ok {
+		tags["reference_id"] = refid
+	}
+
+	// leap=11 is the alarm condition of an unsynchronized clock
+	synced := 1
+	if vars["leap"] == "11" || strings.Contains(out, "leap_alarm") || strings.Contains(out, "sync_unspec") {
+		synced = 0
+	}
+	fields["synced"] = synced
+	return fields, tags, nil
+}
+
+func setFloatField(fields map[string]interface{}, name string, value string, scale float64) {
+	if value == "" {
+		return
+	}
+	if f, err := strconv.ParseFloat(value, 64); err == nil {
+		fields[name] = f * scale
+	}
+}
+
+func init() {
+	inputs.Add("timesync", func() telegraf.Input {
+		return &TimeSync{}
+	})
+}
@@ -0,0 +1,166 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package timesync
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/influxdata/telegraf/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+const chronyTracking = `Reference ID    : A9FEA97B (169.254.169.123)
+Stratum         : 4
+Ref time (UTC)  : Thu May 12 14:27:07 2016
+System time     : 0.000020390 seconds slow of NTP time
+Last offset     : +0.000012651 seconds
+RMS offset      : 0.000025577 seconds
+Frequency       : 16.001 ppm slow
+Residual freq   : -0.000 ppm
+Skew            : 0.006 ppm
+Root delay      : 0.001655 seconds
+Root dispersion : 0.003307 seconds
+Update interval : 507.2 seconds
+Leap status     : Normal
+`
+
+const chronyTrackingNotSynced = `Reference ID    : 00000000 ()
+Stratum         : 0
+Ref time (UTC)  : Thu Jan 01 00:00:00 1970
+System time     : 0.000000000 seconds fast of NTP time
+Last offset     : +0.000000000 seconds
+RMS offset      : 0.000000000 seconds
+Frequency       : 0.000 ppm fast
+Residual freq   : +0.000 ppm
+Skew            : 0.000 ppm
+Root delay      : 1.000000000 seconds
+Root dispersion : 1.000000000 seconds
+Update interval : 0.0 seconds
+Leap status     : Not synchronised
+`
+
+const ntpqReadVar = `associd=0 status=0615 leap_none, sync_ntp, 1 event, clock_sync,
+version="ntpd 4.2.8p15@1.3728-o", processor="x86_64", system="Linux",
+leap=00, stratum=3, precision=-23, rootdelay=1.500, rootdisp=20.000,
+refid=169.254.169.123, reftime=e2d4f6a1.2b8c4e3f, tc=10, peer=12345,
+offset=-0.250000, frequency=-12.345, sys_jitter=0.040000, clk_jitter=0.021,
+clk_wander=0.003
+`
+
+const ntpqReadVarNotSynced = `associd=0 status=c016 leap_alarm, sync_unspec, 1 event, restart,
+version="ntpd 4.2.8p15@1.3728-o", processor="x86_64", system="Linux",
+leap=11, stratum=16, precision=-23, rootdelay=0.000, rootdisp=0.030,
+refid=INIT, reftime=00000000.00000000, tc=3, peer=0, offset=0.000000,
+frequency=0.000, sys_jitter=0.000000, clk_jitter=0.000, clk_wander=0.000
+`
+
+func mockCommands(t *testing.T, installed map[string]bool, outputs map[string]string) func() {
+	origLookPath, origRunCommand := lookPath, runCommand
+	lookPath = func(file string) (string, error) {
+		if installed[file] {
+			return "/usr/bin/" + file, nil
+		}
+		return "", errors.New("executable file not found in $PATH")
+	}
+	runCommand = func(ctx context.Context, name string, args ...string) ([]byte, error) {
+		out, ok := outputs[name]
+		if !ok {
+			return []byte("connection refused"), errors.New("exit status 1")
+		}
+		return []byte(out), nil
+	}
+	return func() {
+		lookPath, runCommand = origLookPath, origRunCommand
+	}
+}
+
+func TestParseChronyTracking(t *testing.T) {
+	fields, tags, err := parseChronyTracking(chronyTracking)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"reference_id": "A9FEA97B"}, tags)
+	assert.Equal(t, map[string]interface{}{
+		"stratum":         int64(4),
+		"offset":          -0.000020390,
+		"jitter":          0.000025577,
+		"root_delay":      0.001655,
+		"root_dispersion": 0.003307,
+		"frequency":       -16.001,
+		"synced":          1,
+	}, fields)
+
+	fields, _, err = parseChronyTracking(chronyTrackingNotSynced)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, fields["synced"])
+
+	_, _, err = parseChronyTracking("506 Cannot talk to daemon")
+	assert.Error(t, err)
+}
+
+func TestParseNtpqReadVar(t *testing.T) {
+	fields, tags, err := parseNtpqReadVar(ntpqReadVar)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"reference_id": "169.254.169.123"}, tags)
+	assert.Equal(t, int64(3), fields["stratum"])
+	assert.InDelta(t, 0.00025, fields["offset"], 1e-12)
+	assert.InDelta(t, 0.00004, fields["jitter"], 1e-12)
+	assert.InDelta(t, 0.0015, fields["root_delay"], 1e-12)
+	assert.InDelta(t, 0.02, fields["root_dispersion"], 1e-12)
+	assert.Equal(t, -12.345, fields["frequency"])
+	assert.Equal(t, 1, fields["synced"])
+
+	fields, _, err = parseNtpqReadVar(ntpqReadVarNotSynced)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, fields["synced"])
+	assert.Equal(t, int64(16), fields["stratum"])
+
+	_, _, err = parseNtpqReadVar("ntpq: read: Connection refused")
+	assert.Error(t, err)
+}
+
+func TestGatherAutoSource(t *testing.T) {
+	defer mockCommands(t, map[string]bool{"chronyc": true, "ntpq": true}, map[string]string{
+		"/usr/bin/chronyc": chronyTracking,
+		"/usr/bin/ntpq":    ntpqReadVar,
+	})()
+
+	ts := &TimeSync{}
+	assert.NoError(t, ts.Init())
+	var acc testutil.Accumulator
+	assert.NoError(t, ts.Gather(&acc))
+	assert.True(t, acc.HasTag("timesync", "source"))
+	assert.Equal(t, "chrony", acc.Metrics[0].Tags["source"])
+	assert.Equal(t, int64(4), acc.Metrics[0].Fields["stratum"])
+}
+
+func TestGatherNtpSourceWithoutChrony(t *testing.T) {
+	defer mockCommands(t, map[string]bool{"ntpq": true}, map[string]string{
+		"/usr/bin/ntpq": ntpqReadVar,
+	})()
+
+	ts := &TimeSync{}
+	assert.NoError(t, ts.Init())
+	var acc testutil.Accumulator
+	assert.NoError(t, ts.Gather(&acc))
+	assert.Equal(t, "ntp", acc.Metrics[0].Tags["source"])
+	assert.Equal(t, int64(3), acc.Metrics[0].Fields["stratum"])
+}
+
+func TestGatherDaemonNotRunning(t *testing.T) {
+	defer mockCommands(t, map[string]bool{"chronyc": true}, map[string]string{})()
+
+	ts := &TimeSync{Source: SourceChrony}
+	assert.NoError(t, ts.Init())
+	var acc testutil.Accumulator
+	assert.Error(t, ts.Gather(&acc))
+	assert.Equal(t, 1, len(acc.Metrics))
+	assert.Equal(t, map[string]interface{}{"synced": 0}, acc.Metrics[0].Fields)
+	assert.Equal(t, map[string]string{"source": "chrony"}, acc.Metrics[0].Tags)
+}
+
+func TestInvalidSource(t *testing.T) {
+	ts := &TimeSync{Source: "w32time"}
+	assert.Error(t, ts.Init())
+}
@@ -0,0 +1,275 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package top_processes
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/plugins/inputs"
+)
+
+const (
+	measurement     = "top_processes"
+	processNameTag  = "process_name"
+	defaultProcRoot = "/proc"
+	defaultTopN     = 5
+
+	rankByCPU    = "cpu"
+	rankByMemory = "memory"
+
+	// the clock ticks of the cpu times of /proc/<pid>/stat, USER_HZ is 100 on every architecture of Linux
+	clockTicks = 100
+)
+
+var sampleConfig = `
+  ## The number of processes reported for each criterion of rank_by.
+  top_n = 5
+  ## The criteria ranking the processes, cpu and memory, the processes among the top_n of one of them are reported.
+  rank_by = ["cpu", "memory"]
+`
+
+// TopProcesses reports the processes using the most cpu and memory, so what consumed the cpu during an incident is
+// known without a procstat entry for every process. The processes are reported by name, the instances of a name
+// summed, so the dimensions don't change with the pids.
+type TopProcesses struct {
+	TopN   int      `toml:"top_n"`
+	RankBy []string `toml:"rank_by"`
+
+	Log telegraf.Logger `toml:"-"`
+
+	// the procfs mount and the clock, replaced in tests
+	procRoot string
+	now      func() time.Time
+
+	// the cpu ticks of the processes at the last gather, by pid and start time since the pids are reused
+	lastTicks map[processKey]uint64
+	lastTime  time.Time
+}
+
+type processKey struct {
+	pid   int
+	start uint64
+}
+
+// process is the usage of the processes of a name.
+type process struct {
+	name  string
+	count int
+	// the cpu used since the last gather, in percent of a cpu, unknown at the first gather
+	cpu    float64
+	hasCPU bool
+	rss    uint64
+}
+
+func (t *TopProcesses) SampleConfig() string {
+	return sampleConfig
+}
+
+func (t *TopProcesses) Description() string {
+	return "Report the processes using the most cpu and memory."
+}
+
+func (t *TopProcesses) Init() error {
+	if t.TopN <= 0 {
+		t.TopN = defaultTopN
+	}
+	if len(t.RankBy) == 0 {
+		t.RankBy = []string{rankByCPU, rankByMemory}
+	}
+	for _, r := range t.RankBy {
+		if r != rankByCPU && r != rankByMemory {
+			return fmt.Errorf("top_processes: unsupported rank_by %q, it is cpu or memory", r)
+		}
+	}
+	if t.procRoot == "" {
+		t.procRoot = defaultProcRoot
+	}
+	if t.now == nil {
+		t.now = time.Now
+	}
+	return nil
+}
+
+func (t *TopProcesses) Gather(acc telegraf.Accumulator) error {
+	processes, err := t.processes()
+	if err != nil {
+		return err
+	}
+	memTotal, err := t.memTotal()
+	if err != nil {
+		t.Log.Debugf("The memory utilization is not reported: %v", err)
+	}
+	for _, p := range t.top(processes) {
+		fields := map[string]interface{}{
+			"memory_rss":    p.rss,
+			"process_count": p.count,
+		}
+		if memTotal > 0 {
+			fields["memory_utilization"] = 100 * float64(p.rss) / float64(memTotal)
+		}
+		if p.hasCPU {
+			fields["cpu_usage"] = p.cpu
+		}
+		acc.AddFields(measurement, fields, map[string]string{processNameTag: p.name})
+	}
+	return nil
+}
+
+// top returns the processes among the top_n of each criterion of rank_by, ordered by name.
+func (t *TopProcesses) top(processes []process) []process {
+	selected := map[string]process{}
+	for _, r := range t.RankBy {
+		ranked := append([]process{}, processes...)
+		sort.Slice(ranked, func(i, j int) bool {
+			a, b := ranked[i], ranked[j]
+			if r == rankByCPU && a.cpu != b.cpu {
+				return a.cpu > b.cpu
+			}
+			if a.rss != b.rss {
+				return a.rss > b.rss
+			}
+			return a.name < b.name
+		})
+		if r == rankByCPU && (len(ranked) == 0 || !ranked[0].hasCPU) {
+			// the cpu is only known from the second gather
+			continue
+		}
+		if len(ranked) > t.TopN {
+			ranked = ranked[:t.TopN]
+		}
+		for _, p := range ranked {
+			selected[p.name] = p
+		}
+	}
+	top := make([]process, 0, len(selected))
+	for _, p := range selected {
+		top = append(top, p)
+	}
+	sort.Slice(top, func(i, j int) bool {
+		return top[i].name < top[j].name
+	})
+	return top
+}
+
+// processes reads the processes of procfs and sums their usage by name.
+func (t *TopProcesses) processes() ([]process, error) {
+	dirs, err := ioutil.ReadDir(t.procRoot)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the processes: %v", err)
+	}
+	now := t.now()
+	elapsed := now.Sub(t.lastTime).Seconds()
+	measured := t.lastTicks != nil && elapsed > 0
+	ticks := make(map[processKey]uint64, len(t.lastTicks))
+	byName := map[string]*process{}
+	for _, d := range dirs {
+		pid, err := strconv.Atoi(d.Name())
+		if err != nil || !d.IsDir() {
+			continue
+		}
+		// the processes can exit while they are read, they are skipped
+		key, name, used, err := t.stat(pid)
+		if err != nil {
+			t.Log.Debugf("Skipping process %d: %v", pid, err)
+			continue
+		}
+		p, ok := byName[name]
+		if !ok {
+			p = &process{name: name}
+			byName[name] = p
+		}
+		p.count++
+		p.rss += t.rss(pid)
+		ticks[key] = used
+		p.hasCPU = measured
+		// the processes started since the last gather are counted from the next one
+		if last, ok := t.lastTicks[key]; ok && measured && used >= last {
+			p.cpu += 100 * float64(used-last) / clockTicks / elapsed
+		}
+	}
+	t.lastTicks = ticks
+	t.lastTime = now
+
+	processes := make([]process, 0, len(byName))
+	for _, p := range byName {
+		processes = append(processes, *p)
+	}
+	return processes, nil
+}
+
+// stat reads the name, the start time and the cpu ticks used by the process, /proc/<pid>/stat is like
+// "42 (nginx) S 1 42 42 0 -1 4194560 ... utime stime ... starttime ...".
+func (t *TopProcesses) stat(pid int) (processKey, string, uint64, error) {
+	path := filepath.Join(t.procRoot, strconv.Itoa(pid), "stat")
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return processKey{}, "", 0, err
+	}
+	s := string(b)
+	// the name of the process can contain spaces and parentheses
+	open, end := strings.IndexByte(s, '('), strings.LastIndexByte(s, ')')
+	if open < 0 || end < open {
+		return processKey{}, "", 0, fmt.Errorf("unexpected content of %s", path)
+	}
+	fields := strings.Fields(s[end+1:])
+	// the fields after the name start with the state, the 3rd field, utime is the 14th, stime the 15th and
+	// starttime the 22nd
+	if len(fields) < 20 {
+		return processKey{}, "", 0, fmt.Errorf("unexpected content of %s", path)
+	}
+	var v [3]uint64
+	for i, index := range []int{11, 12, 19} {
+		if v[i], err = strconv.ParseUint(fields[index], 10, 64); err != nil {
+			return processKey{}, "", 0, fmt.Errorf("unexpected content of %s: %v", path, err)
+		}
+	}
+	return processKey{pid: pid, start: v[2]}, s[open+1 : end], v[0] + v[1], nil
+}
+
+// rss reads the resident memory of the process in bytes from its status, 0 for the kernel threads.
+func (t *TopProcesses) rss(pid int) uint64 {
+	kb, _ := readKB(filepath.Join(t.procRoot, strconv.Itoa(pid), "status"), "VmRSS")
+	return kb * 1024
+}
+
+func (t *TopProcesses) memTotal() (uint64, error) {
+	kb, err := readKB(filepath.Join(t.procRoot, "meminfo"), "MemTotal")
+	return kb * 1024, err
+}
+
+// readKB reads the value in kB of a key of a procfs file, like "VmRSS:	    7168 kB".
+func readKB(path, key string) (uint64, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+	scanner := bufio.NewScanner(bytes.NewReader(b))
+	for scanner.Scan() {
+		parts := strings.SplitN(scanner.Text(), ":", 2)
+		if len(parts) != 2 || parts[0] != key {
+			continue
+		}
+		values := strings.Fields(parts[1])
+		if len(values) != 2 || values[1] != "kB" {
+			break
+		}
+		return strconv.ParseUint(values[0], 10, 64)
+	}
+	return 0, fmt.Errorf("no %s in %s", key, path)
+}
+
+func init() {
+	inputs.Add(measurement, func() telegraf.Input {
+		return &TopProcesses{}
+	})
+}
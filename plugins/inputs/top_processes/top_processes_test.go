@@ -0,0 +1,124 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package top_processes
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/influxdata/telegraf/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+// writeProcess writes the procfs entries of a process which used ticks of cpu and has a resident memory in kB.
+func writeProcess(t *testing.T, root string, pid int, name string, ticks int, start int, rssKB int) {
+	dir := filepath.Join(root, fmt.Sprintf("%d", pid))
+	assert.NoError(t, os.MkdirAll(dir, 0755))
+	stat := fmt.Sprintf("%d (%s) S 1 %d %d 0 -1 4194560 100 0 0 0 %d 0 0 0 20 0 1 0 %d 1000000 100 18446744073709551615\n",
+		pid, name, pid, pid, ticks, start)
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "stat"), []byte(stat), 0644))
+	status := fmt.Sprintf("Name:\t%s\nVmRSS:\t%8d kB\nThreads:\t1\n", name, rssKB)
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "status"), []byte(status), 0644))
+}
+
+func newTopProcesses(t *testing.T, root string, top int, rankBy ...string) (*TopProcesses, *time.Time) {
+	now := time.Unix(1620828427, 0)
+	tp := &TopProcesses{TopN: top, RankBy: rankBy, Log: testutil.Logger{}, procRoot: root}
+	tp.now = func() time.Time { return now }
+	assert.NoError(t, tp.Init())
+	return tp, &now
+}
+
+func TestGather(t *testing.T) {
+	root, err := ioutil.TempDir("", "top_processes")
+	assert.NoError(t, err)
+	defer os.RemoveAll(root)
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(root, "meminfo"), []byte("MemTotal:        1048576 kB\nMemFree:          524288 kB\n"), 0644))
+	writeProcess(t, root, 1, "systemd", 100, 1, 8192)
+	writeProcess(t, root, 100, "nginx", 1000, 500, 65536)
+	writeProcess(t, root, 101, "nginx", 1000, 501, 65536)
+	writeProcess(t, root, 200, "java", 5000, 600, 262144)
+	writeProcess(t, root, 300, "my app (v2)", 10, 700, 1024)
+
+	tp, now := newTopProcesses(t, root, 2)
+	var acc testutil.Accumulator
+	// the cpu is unknown at the first gather, the processes are ranked by memory
+	assert.NoError(t, acc.GatherError(tp.Gather))
+	assert.Len(t, acc.Metrics, 2)
+	acc.AssertContainsTaggedFields(t, "top_processes", map[string]interface{}{
+		"memory_rss":         uint64(268435456),
+		"memory_utilization": float64(25),
+		"process_count":      1,
+	}, map[string]string{"process_name": "java"})
+	acc.AssertContainsTaggedFields(t, "top_processes", map[string]interface{}{
+		"memory_rss":         uint64(134217728),
+		"memory_utilization": float64(12.5),
+		"process_count":      2,
+	}, map[string]string{"process_name": "nginx"})
+
+	// 10 seconds later, the app used a cpu, nginx half a cpu per worker
+	*now = now.Add(10 * time.Second)
+	writeProcess(t, root, 100, "nginx", 1500, 500, 65536)
+	writeProcess(t, root, 101, "nginx", 1500, 501, 65536)
+	writeProcess(t, root, 300, "my app (v2)", 1010, 700, 1024)
+	// the pid of systemd is reused, its ticks are not compared to the ones of the previous process
+	writeProcess(t, root, 1, "systemd", 5000, 2, 8192)
+	acc.ClearMetrics()
+	assert.NoError(t, acc.GatherError(tp.Gather))
+	assert.Len(t, acc.Metrics, 3)
+	acc.AssertContainsTaggedFields(t, "top_processes", map[string]interface{}{
+		"cpu_usage":          float64(100),
+		"memory_rss":         uint64(1048576),
+		"memory_utilization": float64(0.09765625),
+		"process_count":      1,
+	}, map[string]string{"process_name": "my app (v2)"})
+	acc.AssertContainsTaggedFields(t, "top_processes", map[string]interface{}{
+		"cpu_usage":          float64(100),
+		"memory_rss":         uint64(134217728),
+		"memory_utilization": float64(12.5),
+		"process_count":      2,
+	}, map[string]string{"process_name": "nginx"})
+	acc.AssertContainsTaggedFields(t, "top_processes", map[string]interface{}{
+		"cpu_usage":          float64(0),
+		"memory_rss":         uint64(268435456),
+		"memory_utilization": float64(25),
+		"process_count":      1,
+	}, map[string]string{"process_name": "java"})
+}
+
+func TestGatherRankByCPU(t *testing.T) {
+	root, err := ioutil.TempDir("", "top_processes")
+	assert.NoError(t, err)
+	defer os.RemoveAll(root)
+	writeProcess(t, root, 100, "nginx", 1000, 500, 65536)
+	writeProcess(t, root, 200, "java", 5000, 600, 262144)
+
+	tp, now := newTopProcesses(t, root, 1, "cpu")
+	var acc testutil.Accumulator
+	assert.NoError(t, acc.GatherError(tp.Gather))
+	assert.Empty(t, acc.Metrics)
+
+	*now = now.Add(10 * time.Second)
+	writeProcess(t, root, 100, "nginx", 1250, 500, 65536)
+	assert.NoError(t, acc.GatherError(tp.Gather))
+	// without meminfo the memory utilization is not reported
+	acc.AssertContainsTaggedFields(t, "top_processes", map[string]interface{}{
+		"cpu_usage":     float64(25),
+		"memory_rss":    uint64(67108864),
+		"process_count": 1,
+	}, map[string]string{"process_name": "nginx"})
+	assert.Len(t, acc.Metrics, 1)
+}
+
+func TestInit(t *testing.T) {
+	tp := &TopProcesses{Log: testutil.Logger{}}
+	assert.NoError(t, tp.Init())
+	assert.Equal(t, defaultTopN, tp.TopN)
+	assert.Equal(t, []string{"cpu", "memory"}, tp.RankBy)
+	assert.Error(t, (&TopProcesses{RankBy: []string{"io"}, Log: testutil.Logger{}}).Init())
+}
@@ -0,0 +1,17 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+//go:build !windows
+// +build !windows
+
+package windows_gpu
+
+import (
+	"errors"
+)
+
+var errNoGPU = errors.New("the GPU performance counters are only available on Windows")
+
+func openPdhCounters() (counters, error) {
+	return nil, errNoGPU
+}
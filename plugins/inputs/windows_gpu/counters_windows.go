@@ -0,0 +1,91 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+//go:build windows
+// +build windows
+
+package windows_gpu
+
+import (
+	"errors"
+	"fmt"
+	"unsafe"
+
+	win "github.com/aws/amazon-cloudwatch-agent/plugins/inputs/win_perf_counters"
+)
+
+var errNoGPU = errors.New("no GPU Engine performance counters")
+
+// pdhCounters is a PDH query of the GPU counters.
+type pdhCounters struct {
+	query    win.PDH_HQUERY
+	counters map[string]win.PDH_HCOUNTER
+}
+
+func openPdhCounters() (counters, error) {
+	p := &pdhCounters{counters: map[string]win.PDH_HCOUNTER{}}
+	if ret := win.PdhOpenQuery(0, 0, &p.query); ret != win.ERROR_SUCCESS {
+		return nil, fmt.Errorf("windows_gpu: %s", win.PdhFormatError(ret))
+	}
+	for _, path := range []string{engineUtilizationCounter, dedicatedUsageCounter, sharedUsageCounter, totalCommittedCounter} {
+		var counter win.PDH_HCOUNTER
+		ret := win.PdhAddEnglishCounter(p.query, path, 0, &counter)
+		if ret == win.PDH_CSTATUS_NO_OBJECT || ret == win.PDH_CSTATUS_NO_COUNTER {
+			p.close()
+			return nil, errNoGPU
+		} else if ret != win.ERROR_SUCCESS {
+			p.close()
+			return nil, fmt.Errorf("windows_gpu: %s: %s", path, win.PdhFormatError(ret))
+		}
+		p.counters[path] = counter
+	}
+	// the first sample of the utilization, it is a rate computed from two samples
+	win.PdhCollectQueryData(p.query)
+	return p, nil
+}
+
+func (p *pdhCounters) read() (map[string][]counterValue, error) {
+	if ret := win.PdhCollectQueryData(p.query); ret != win.ERROR_SUCCESS {
+		return nil, fmt.Errorf("windows_gpu: %s", win.PdhFormatError(ret))
+	}
+	values := map[string][]counterValue{}
+	for path, counter := range p.counters {
+		v, err := readArray(counter)
+		if err != nil {
+			return nil, fmt.Errorf("windows_gpu: %s: %v", path, err)
+		}
+		values[path] = v
+	}
+	return values, nil
+}
+
+// readArray reads the values of the instances of a counter, the first call returns the size of the buffer.
+func readArray(counter win.PDH_HCOUNTER) ([]counterValue, error) {
+	var bufSize uint32
+	var bufCount uint32
+	var emptyBuf [1]win.PDH_FMT_COUNTERVALUE_ITEM_DOUBLE // need at least 1 addressable null ptr.
+	ret := win.PdhGetFormattedCounterArrayDouble(counter, &bufSize, &bufCount, &emptyBuf[0])
+	if ret == win.ERROR_SUCCESS || ret == win.PDH_NO_DATA {
+		return nil, nil
+	} else if ret != win.PDH_MORE_DATA {
+		return nil, errors.New(win.PdhFormatError(ret))
+	}
+	// the size of the buffer is in bytes, the names of the instances are stored after the items
+	itemSize := uint32(unsafe.Sizeof(win.PDH_FMT_COUNTERVALUE_ITEM_DOUBLE{}))
+	filledBuf := make([]win.PDH_FMT_COUNTERVALUE_ITEM_DOUBLE, bufSize/itemSize+1)
+	if ret := win.PdhGetFormattedCounterArrayDouble(counter, &bufSize, &bufCount, &filledBuf[0]); ret != win.ERROR_SUCCESS {
+		return nil, errors.New(win.PdhFormatError(ret))
+	}
+	var values []counterValue
+	for _, item := range filledBuf[:bufCount] {
+		if item.FmtValue.CStatus != win.PDH_CSTATUS_VALID_DATA && item.FmtValue.CStatus != win.PDH_CSTATUS_NEW_DATA {
+			continue
+		}
+		values = append(values, counterValue{instance: win.UTF16PtrToString(item.SzName), value: item.FmtValue.DoubleValue})
+	}
+	return values, nil
+}
+
+func (p *pdhCounters) close() {
+	win.PdhCloseQuery(p.query)
+}
@@ -0,0 +1,200 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package windows_gpu
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/plugins/inputs"
+)
+
+const (
+	measurement = "windows_gpu"
+	adapterTag  = "adapter"
+	engineTag   = "engine_type"
+
+	// the counters of the WDDM graphics kernel, the instances of the engines are per process
+	engineUtilizationCounter    = `\GPU Engine(*)\Utilization Percentage`
+	dedicatedUsageCounter       = `\GPU Adapter Memory(*)\Dedicated Usage`
+	sharedUsageCounter          = `\GPU Adapter Memory(*)\Shared Usage`
+	totalCommittedCounter       = `\GPU Adapter Memory(*)\Total Committed`
+	unknownEngineType           = "other"
+	maxEngineUtilizationPercent = 100
+)
+
+var sampleConfig = `
+  ## No configuration, the utilization of the engines and the memory of the GPU adapters are read from the
+  ## GPU Engine and GPU Adapter Memory performance counters.
+`
+
+// memoryFields are the fields of the counters of the adapter memory, in bytes.
+var memoryFields = map[string]string{
+	dedicatedUsageCounter: "memory_dedicated_usage",
+	sharedUsageCounter:    "memory_shared_usage",
+	totalCommittedCounter: "memory_total_committed",
+}
+
+// counterValue is the value of an instance of a counter.
+type counterValue struct {
+	instance string
+	value    float64
+}
+
+// counters reads the instances of the GPU counters, the values of the rate counters are the ones since the
+// previous read.
+type counters interface {
+	read() (map[string][]counterValue, error)
+	close()
+}
+
+// WindowsGPU reports the utilization and the memory of the GPU adapters of Windows by adapter, and the utilization
+// of their engines by engine type, from the counters of the graphics kernel so every vendor is covered.
+type WindowsGPU struct {
+	Log telegraf.Logger `toml:"-"`
+
+	// opens the performance counters, replaced in tests
+	openCounters func() (counters, error)
+	counters     counters
+}
+
+// engine is an engine of an adapter, e.g. the 3D engine 0 of the adapter luid_0x00000000_0x0000D1C2_phys_0.
+type engine struct {
+	adapter    string
+	number     string
+	engineType string
+}
+
+func (g *WindowsGPU) SampleConfig() string {
+	return sampleConfig
+}
+
+func (g *WindowsGPU) Description() string {
+	return "Report the utilization and the memory of the GPU adapters from the Windows performance counters."
+}
+
+func (g *WindowsGPU) Init() error {
+	if g.openCounters == nil {
+		g.openCounters = openPdhCounters
+	}
+	return nil
+}
+
+// Start opens the query of the counters, it is kept open so the utilization is the one between two gathers.
+func (g *WindowsGPU) Start(_ telegraf.Accumulator) error {
+	c, err := g.openCounters()
+	if err == errNoGPU {
+		// the counters only exist with a WDDM 2 driver, e.g. not on the instances without a GPU
+		g.Log.Infof("No GPU to report: %v", err)
+		return nil
+	} else if err != nil {
+		return err
+	}
+	g.counters = c
+	return nil
+}
+
+func (g *WindowsGPU) Stop() {
+	if g.counters != nil {
+		g.counters.close()
+		g.counters = nil
+	}
+}
+
+func (g *WindowsGPU) Gather(acc telegraf.Accumulator) error {
+	if g.counters == nil {
+		return nil
+	}
+	values, err := g.counters.read()
+	if err != nil {
+		return err
+	}
+
+	adapters := map[string]map[string]interface{}{}
+	adapterFields := func(adapter string) map[string]interface{} {
+		fields, ok := adapters[adapter]
+		if !ok {
+			fields = map[string]interface{}{}
+			adapters[adapter] = fields
+		}
+		return fields
+	}
+	for counter, field := range memoryFields {
+		for _, v := range values[counter] {
+			adapterFields(v.instance)[field] = v.value
+		}
+	}
+
+	// the utilization of an engine is the sum of the one of the processes using it, the one of an engine type is
+	// the one of its busiest engine and the one of the adapter the one of its busiest engine type, like the Task
+	// Manager
+	engines := map[engine]float64{}
+	for _, v := range values[engineUtilizationCounter] {
+		e, ok := parseEngine(v.instance)
+		if !ok {
+			g.Log.Debugf("Skipping the unexpected GPU engine %q", v.instance)
+			continue
+		}
+		engines[e] += v.value
+	}
+	engineTypes := map[engine]float64{}
+	for e, utilization := range engines {
+		if utilization > maxEngineUtilizationPercent {
+			utilization = maxEngineUtilizationPercent
+		}
+		key := engine{adapter: e.adapter, engineType: e.engineType}
+		if utilization >= engineTypes[key] {
+			engineTypes[key] = utilization
+		}
+		fields := adapterFields(e.adapter)
+		if current, ok := fields["utilization"].(float64); !ok || utilization > current {
+			fields["utilization"] = utilization
+		}
+	}
+
+	for adapter, fields := range adapters {
+		acc.AddFields(measurement, fields, map[string]string{adapterTag: adapter})
+	}
+	keys := make([]engine, 0, len(engineTypes))
+	for key := range engineTypes {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].adapter != keys[j].adapter {
+			return keys[i].adapter < keys[j].adapter
+		}
+		return keys[i].engineType < keys[j].engineType
+	})
+	for _, key := range keys {
+		acc.AddFields(measurement, map[string]interface{}{"engine_utilization": engineTypes[key]},
+			map[string]string{adapterTag: key.adapter, engineTag: key.engineType})
+	}
+	return nil
+}
+
+// parseEngine parses the instance of an engine, like "pid_1234_luid_0x00000000_0x0000D1C2_phys_0_eng_3_engtype_3D",
+// the adapter part is the instance of its adapter memory.
+func parseEngine(instance string) (engine, bool) {
+	start := strings.Index(instance, "luid_")
+	end := strings.Index(instance, "_eng_")
+	if start < 0 || end < start {
+		return engine{}, false
+	}
+	parts := strings.SplitN(instance[end+len("_eng_"):], "_engtype_", 2)
+	if len(parts) != 2 || parts[0] == "" {
+		return engine{}, false
+	}
+	e := engine{adapter: instance[start:end], number: parts[0], engineType: parts[1]}
+	if e.engineType == "" {
+		e.engineType = unknownEngineType
+	}
+	return e, true
+}
+
+func init() {
+	inputs.Add(measurement, func() telegraf.Input {
+		return &WindowsGPU{}
+	})
+}
@@ -0,0 +1,94 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package windows_gpu
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/influxdata/telegraf/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeCounters struct {
+	values map[string][]counterValue
+	closed bool
+}
+
+func (f *fakeCounters) read() (map[string][]counterValue, error) {
+	return f.values, nil
+}
+
+func (f *fakeCounters) close() {
+	f.closed = true
+}
+
+func newWindowsGPU(c counters, err error) *WindowsGPU {
+	g := &WindowsGPU{Log: testutil.Logger{}}
+	g.openCounters = func() (counters, error) {
+		return c, err
+	}
+	return g
+}
+
+func TestGather(t *testing.T) {
+	const adapter = "luid_0x00000000_0x0000D1C2_phys_0"
+	c := &fakeCounters{values: map[string][]counterValue{
+		engineUtilizationCounter: {
+			{"pid_100_luid_0x00000000_0x0000D1C2_phys_0_eng_0_engtype_3D", 40},
+			{"pid_200_luid_0x00000000_0x0000D1C2_phys_0_eng_0_engtype_3D", 30},
+			{"pid_200_luid_0x00000000_0x0000D1C2_phys_0_eng_1_engtype_3D", 10},
+			{"pid_300_luid_0x00000000_0x0000D1C2_phys_0_eng_4_engtype_VideoDecode", 95},
+			{"pid_400_luid_0x00000000_0x0000D1C2_phys_0_eng_4_engtype_VideoDecode", 20},
+			{"pid_400_luid_0x00000000_0x0000D1C2_phys_0_eng_7_engtype_", 1},
+			{"unexpected", 50},
+		},
+		dedicatedUsageCounter: {{adapter, 1073741824}},
+		sharedUsageCounter:    {{adapter, 268435456}},
+		totalCommittedCounter: {{adapter, 1610612736}},
+	}}
+	g := newWindowsGPU(c, nil)
+	assert.NoError(t, g.Init())
+	var acc testutil.Accumulator
+	assert.NoError(t, g.Start(&acc))
+	assert.NoError(t, acc.GatherError(g.Gather))
+
+	assert.Len(t, acc.Metrics, 4)
+	acc.AssertContainsTaggedFields(t, measurement, map[string]interface{}{
+		"utilization":            float64(100),
+		"memory_dedicated_usage": float64(1073741824),
+		"memory_shared_usage":    float64(268435456),
+		"memory_total_committed": float64(1610612736),
+	}, map[string]string{adapterTag: adapter})
+	// the utilization of the engines is summed across the processes and capped, the busiest engine of a type is reported
+	acc.AssertContainsTaggedFields(t, measurement, map[string]interface{}{"engine_utilization": float64(70)},
+		map[string]string{adapterTag: adapter, engineTag: "3D"})
+	acc.AssertContainsTaggedFields(t, measurement, map[string]interface{}{"engine_utilization": float64(100)},
+		map[string]string{adapterTag: adapter, engineTag: "VideoDecode"})
+	acc.AssertContainsTaggedFields(t, measurement, map[string]interface{}{"engine_utilization": float64(1)},
+		map[string]string{adapterTag: adapter, engineTag: unknownEngineType})
+
+	g.Stop()
+	assert.True(t, c.closed)
+}
+
+func TestStartWithoutGPU(t *testing.T) {
+	g := newWindowsGPU(nil, errNoGPU)
+	assert.NoError(t, g.Init())
+	var acc testutil.Accumulator
+	assert.NoError(t, g.Start(&acc))
+	assert.NoError(t, acc.GatherError(g.Gather))
+	assert.Empty(t, acc.Metrics)
+	g.Stop()
+
+	assert.Error(t, newWindowsGPU(nil, errors.New("access denied")).Start(&acc))
+}
+
+func TestParseEngine(t *testing.T) {
+	e, ok := parseEngine("pid_1234_luid_0x00000000_0x0000D1C2_phys_0_eng_3_engtype_Copy")
+	assert.True(t, ok)
+	assert.Equal(t, engine{adapter: "luid_0x00000000_0x0000D1C2_phys_0", number: "3", engineType: "Copy"}, e)
+	_, ok = parseEngine("pid_1234_luid_0x00000000_0x0000D1C2_phys_0")
+	assert.False(t, ok)
+}
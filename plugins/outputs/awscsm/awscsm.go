@@ -36,6 +36,9 @@ const (
 	tagName            = "awscsm"
 
 	maxQueueBacklogSize = 5000
+
+	// the control plane endpoint is resolved with the DNS suffix of the region's partition
+	controlPlaneService = "control.sdkmetrics"
 )
 
 var (
@@ -161,7 +164,7 @@ func (c *CSM) Connect() error {
 
 	commonSession := session.New(commonCfg.Config)
 
-	endpoint := fmt.Sprintf("https://control.sdkmetrics.%s.amazonaws.com", region)
+	endpoint := configaws.ResolveEndpoint(controlPlaneService, region)
 	if len(c.EndpointOverride) > 0 {
 		endpoint = c.EndpointOverride
 	}
@@ -30,14 +30,18 @@ type aggregator struct {
 	metricChan   chan<- telegraf.Metric
 	shutdownChan <-chan struct{}
 	wg           *sync.WaitGroup
+	checkpoint   *Checkpoint
 }
 
-func NewAggregator(metricChan chan<- telegraf.Metric, shutdownChan <-chan struct{}, wg *sync.WaitGroup) Aggregator {
+// NewAggregator adds the metrics being aggregated to the checkpoint on shutdown rather than flushing them, when the
+// checkpoint isn't nil.
+func NewAggregator(metricChan chan<- telegraf.Metric, shutdownChan <-chan struct{}, wg *sync.WaitGroup, checkpoint *Checkpoint) Aggregator {
 	return &aggregator{
 		durationMap:  make(map[time.Duration]*durationAggregator),
 		metricChan:   metricChan,
 		shutdownChan: shutdownChan,
 		wg:           wg,
+		checkpoint:   checkpoint,
 	}
 }
 
@@ -53,12 +57,29 @@ func computeHash(m telegraf.Metric) string {
 }
 
 func (agg *aggregator) AddMetric(m telegraf.Metric) {
+	aggDurationMapKey, ok := aggregationDuration(m)
+	if !ok {
+		agg.metricChan <- m
+		return
+	}
+
+	var durationAgg *durationAggregator
+	if durationAgg, ok = agg.durationMap[aggDurationMapKey]; !ok {
+		durationAgg = newDurationAggregator(aggDurationMapKey, agg.metricChan, agg.shutdownChan, agg.wg, agg.checkpoint)
+		agg.durationMap[aggDurationMapKey] = durationAgg
+	}
+
+	durationAgg.addMetric(m)
+}
+
+// aggregationDuration removes the aggregation interval tag from the metric and returns the parsed interval.
+// It returns false if the metric should pass through without aggregation.
+func aggregationDuration(m telegraf.Metric) (time.Duration, bool) {
 	var aggregationInterval string
 	var ok bool
 	if aggregationInterval, ok = m.Tags()[aggregationIntervalTagKey]; !ok {
 		// no aggregation interval field key, pass through directly.
-		agg.metricChan <- m
-		return
+		return 0, false
 	}
 
 	// remove aggregation interval field key since it is irrelevant any more
@@ -69,23 +90,16 @@ func (agg *aggregator) AddMetric(m telegraf.Metric) {
 	if aggregationDuration, err = time.ParseDuration(aggregationInterval); err != nil {
 		log.Printf("W! aggregation interval string value %v cannot be parsed into time.Duration type. No aggregation will be performed. %v",
 			aggregationInterval, err)
-		agg.metricChan <- m
-		return
+		return 0, false
 	}
 
 	aggDurationMapKey := aggregationDuration.Truncate(time.Second)
-	var durationAgg *durationAggregator
-	if durationAgg, ok = agg.durationMap[aggDurationMapKey]; !ok {
-		durationAgg = newDurationAggregator(aggDurationMapKey, agg.metricChan, agg.shutdownChan, agg.wg)
-		agg.durationMap[aggDurationMapKey] = durationAgg
-	}
 
 	//auto configure high resolution
 	if aggDurationMapKey < time.Minute {
 		m.AddTag(highResolutionTagKey, "true")
 	}
-
-	durationAgg.addMetric(m)
+	return aggDurationMapKey, true
 }
 
 type durationAggregator struct {
@@ -93,6 +107,7 @@ type durationAggregator struct {
 	metricChan          chan<- telegraf.Metric
 	shutdownChan        <-chan struct{}
 	wg                  *sync.WaitGroup
+	checkpoint          *Checkpoint
 	ticker              *time.Ticker
 	metricMap           map[string]telegraf.Metric //metric hash string + time sec int64 -> Metric object
 	aggregationChan     chan telegraf.Metric
@@ -101,13 +116,15 @@ type durationAggregator struct {
 func newDurationAggregator(durationInSeconds time.Duration,
 	metricChan chan<- telegraf.Metric,
 	shutdownChan <-chan struct{},
-	wg *sync.WaitGroup) *durationAggregator {
+	wg *sync.WaitGroup,
+	checkpoint *Checkpoint) *durationAggregator {
 
 	durationAgg := &durationAggregator{
 		aggregationDuration: durationInSeconds,
 		metricChan:          metricChan,
 		shutdownChan:        shutdownChan,
 		wg:                  wg,
+		checkpoint:          checkpoint,
 		metricMap:           make(map[string]telegraf.Metric),
 		aggregationChan:     make(chan telegraf.Metric, durationAggregationChanBufferSize),
 	}
@@ -127,66 +144,17 @@ func (durationAgg *durationAggregator) aggregating() {
 	for {
 		select {
 		case m := <-durationAgg.aggregationChan:
-			// https://docs.aws.amazon.com/AmazonCloudWatch/latest/APIReference/API_MetricDatum.html
-			aggregatedTime := m.Time().Truncate(durationAgg.aggregationDuration)
-			metricMapKey := fmt.Sprint(computeHash(m), aggregatedTime.Unix())
-			var aggregatedMetric telegraf.Metric
-			var ok bool
-			var err error
-			if aggregatedMetric, ok = durationAgg.metricMap[metricMapKey]; !ok {
-				aggregatedMetric, err = metric.New(m.Name(), m.Tags(), map[string]interface{}{}, aggregatedTime)
-				if err != nil {
-					log.Printf("E! CloudWatch metrics aggregation failed: %v. The metric %v will be dropped.", err, m.Name())
-					continue
-				}
-				durationAgg.metricMap[metricMapKey] = aggregatedMetric
-			}
-			//When the code comes here, it means the aggregatedMetric object has the same metric name, tags and aggregated time.
-			//We just need to aggregate the additional fields if any and the values for the fields.
-			for k, v := range m.Fields() {
-				var value float64
-				var dist distribution.Distribution
-				switch t := v.(type) {
-				case int:
-					value = float64(t)
-				case int32:
-					value = float64(t)
-				case int64:
-					value = float64(t)
-				case float64:
-					value = t
-				case bool:
-					if t {
-						value = 1
-					} else {
-						value = 0
-					}
-				case time.Time:
-					value = float64(t.Unix())
-				case distribution.Distribution:
-					dist = t
-				default:
-					// Skip unsupported type.
-					continue
-				}
-				var existingValue interface{}
-				if existingValue, ok = aggregatedMetric.Fields()[k]; !ok {
-					existingValue = distribution.NewDistribution()
-					aggregatedMetric.AddField(k, existingValue)
-				}
-				existingDist := existingValue.(distribution.Distribution)
-				if dist != nil {
-					existingDist.AddDistribution(dist)
-				} else {
-					err = existingDist.AddEntry(value, 1)
-					if err != nil {
-						log.Printf("W! error: %s, metric %s, value %v", err, m.Name(), value)
-					}
-				}
-			}
+			durationAgg.aggregate(m)
 		case <-durationAgg.ticker.C:
 			durationAgg.flush()
 		case <-durationAgg.shutdownChan:
+			if durationAgg.checkpoint != nil {
+				log.Printf("D! CloudWatch: aggregating routine receives the shutdown signal, checkpoint the metrics now for aggregation interval %v", durationAgg.aggregationDuration)
+				durationAgg.drain()
+				durationAgg.checkpoint.add(durationAgg.aggregationDuration, durationAgg.metricMap)
+				durationAgg.wg.Done()
+				return
+			}
 			log.Printf("D! CloudWatch: aggregating routine receives the shutdown signal, do the final flush now for aggregation interval %v", durationAgg.aggregationDuration)
 			durationAgg.flush()
 			log.Printf("D! CloudWatch: aggregating routine receives the shutdown signal, exiting.")
@@ -196,6 +164,68 @@ func (durationAgg *durationAggregator) aggregating() {
 	}
 }
 
+// aggregate merges the fields of the metric into the distributions of the aggregated metric with the same name, tags
+// and aggregated time.
+func (durationAgg *durationAggregator) aggregate(m telegraf.Metric) {
+	// https://docs.aws.amazon.com/AmazonCloudWatch/latest/APIReference/API_MetricDatum.html
+	aggregatedTime := m.Time().Truncate(durationAgg.aggregationDuration)
+	metricMapKey := fmt.Sprint(computeHash(m), aggregatedTime.Unix())
+	var aggregatedMetric telegraf.Metric
+	var ok bool
+	var err error
+	if aggregatedMetric, ok = durationAgg.metricMap[metricMapKey]; !ok {
+		aggregatedMetric, err = metric.New(m.Name(), m.Tags(), map[string]interface{}{}, aggregatedTime)
+		if err != nil {
+			log.Printf("E! CloudWatch metrics aggregation failed: %v. The metric %v will be dropped.", err, m.Name())
+			return
+		}
+		durationAgg.metricMap[metricMapKey] = aggregatedMetric
+	}
+	//When the code comes here, it means the aggregatedMetric object has the same metric name, tags and aggregated time.
+	//We just need to aggregate the additional fields if any and the values for the fields.
+	for k, v := range m.Fields() {
+		var value float64
+		var dist distribution.Distribution
+		switch t := v.(type) {
+		case int:
+			value = float64(t)
+		case int32:
+			value = float64(t)
+		case int64:
+			value = float64(t)
+		case float64:
+			value = t
+		case bool:
+			if t {
+				value = 1
+			} else {
+				value = 0
+			}
+		case time.Time:
+			value = float64(t.Unix())
+		case distribution.Distribution:
+			dist = t
+		default:
+			// Skip unsupported type.
+			continue
+		}
+		var existingValue interface{}
+		if existingValue, ok = aggregatedMetric.Fields()[k]; !ok {
+			existingValue = distribution.NewDistribution()
+			aggregatedMetric.AddField(k, existingValue)
+		}
+		existingDist := existingValue.(distribution.Distribution)
+		if dist != nil {
+			existingDist.AddDistribution(dist)
+		} else {
+			err = existingDist.AddEntry(value, 1)
+			if err != nil {
+				log.Printf("W! error: %s, metric %s, value %v", err, m.Name(), value)
+			}
+		}
+	}
+}
+
 func (durationAgg *durationAggregator) addMetric(m telegraf.Metric) {
 	durationAgg.aggregationChan <- m
 }
@@ -206,3 +236,15 @@ func (durationAgg *durationAggregator) flush() {
 	}
 	durationAgg.metricMap = make(map[string]telegraf.Metric)
 }
+
+// drain aggregates the metrics queued before the shutdown.
+func (durationAgg *durationAggregator) drain() {
+	for {
+		select {
+		case m := <-durationAgg.aggregationChan:
+			durationAgg.aggregate(m)
+		default:
+			return
+		}
+	}
+}
@@ -201,7 +201,7 @@ func testPreparation() (chan telegraf.Metric, chan struct{}, Aggregator) {
 	distribution.NewDistribution = seh1.NewSEH1Distribution
 	metricChan := make(chan telegraf.Metric, metricChanBufferSize)
 	shutdownChan := make(chan struct{})
-	aggregator := NewAggregator(metricChan, shutdownChan, &wg)
+	aggregator := NewAggregator(metricChan, shutdownChan, &wg, nil)
 	return metricChan, shutdownChan, aggregator
 }
 
@@ -0,0 +1,87 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package cloudwatch
+
+import (
+	"log"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/request"
+)
+
+const (
+	// the limits of a PutMetricData request, 1000 datums and 1MB, leaving room for the last datum of a batch
+	maxDatumsPerCallLimit = 1000
+	maxPayloadSizeLimit   = 1000000
+	// the limit is halved at most this often, so the requests throttled at once halve it once, and grows back at
+	// most this often
+	batchLimitShrinkInterval = time.Second
+	batchLimitGrowInterval   = 10 * time.Second
+	// the limit grows back by this part of max_datums_per_call
+	batchLimitGrowthDivisor = 20
+)
+
+// BatchLimit adapts the number of datums of the PutMetricData requests to the throttling: the limit is halved when
+// a request is throttled, and grows back by a twentieth of max_datums_per_call by successful request every ten seconds
+// at most, up to max_datums_per_call. The datums are then sent in fewer requests once the throttling stops.
+type BatchLimit struct {
+	max int
+
+	mu         sync.Mutex
+	current    int
+	lastChange time.Time
+}
+
+func NewBatchLimit(maxDatumsPerCall int) *BatchLimit {
+	return &BatchLimit{max: maxDatumsPerCall, current: maxDatumsPerCall}
+}
+
+// Get returns the number of datums of the next requests.
+func (l *BatchLimit) Get() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.current
+}
+
+func (l *BatchLimit) throttled() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.current == 1 || time.Since(l.lastChange) < batchLimitShrinkInterval {
+		return
+	}
+	l.current /= 2
+	l.lastChange = time.Now()
+	log.Printf("D! CloudWatch: PutMetricData is throttled, the requests are limited to %d datums", l.current)
+}
+
+func (l *BatchLimit) succeeded() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.current == l.max || time.Since(l.lastChange) < batchLimitGrowInterval {
+		return
+	}
+	growth := l.max / batchLimitGrowthDivisor
+	if growth < 1 {
+		growth = 1
+	}
+	l.current += growth
+	if l.current > l.max {
+		l.current = l.max
+	}
+	l.lastChange = time.Now()
+}
+
+// throttleHandler halves the limit on the throttled attempts of the PutMetricData requests, including the ones the
+// sdk retries.
+func (l *BatchLimit) throttleHandler() request.NamedHandler {
+	return request.NamedHandler{
+		Name: "cloudwatch.BatchLimitHandler",
+		Fn: func(r *request.Request) {
+			if r.Operation != nil && r.Operation.Name == opPutMetricData && r.IsErrorThrottle() {
+				l.throttled()
+			}
+		},
+	}
+}
@@ -0,0 +1,56 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package cloudwatch
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBatchLimit(t *testing.T) {
+	l := NewBatchLimit(100)
+	assert.Equal(t, 100, l.Get())
+	l.succeeded()
+	assert.Equal(t, 100, l.Get())
+
+	l.throttled()
+	assert.Equal(t, 50, l.Get())
+	// the requests throttled at once halve the limit once
+	l.throttled()
+	assert.Equal(t, 50, l.Get())
+	l.lastChange = time.Now().Add(-batchLimitShrinkInterval)
+	l.throttled()
+	assert.Equal(t, 25, l.Get())
+
+	// the limit grows back once the throttling stops
+	l.succeeded()
+	assert.Equal(t, 25, l.Get())
+	l.lastChange = time.Now().Add(-batchLimitGrowInterval)
+	l.succeeded()
+	assert.Equal(t, 30, l.Get())
+	l.current = 98
+	l.lastChange = time.Now().Add(-batchLimitGrowInterval)
+	l.succeeded()
+	assert.Equal(t, 100, l.Get())
+}
+
+func TestBatchLimit_ThrottleHandler(t *testing.T) {
+	l := NewBatchLimit(20)
+	handler := l.throttleHandler()
+	r := &request.Request{Operation: &request.Operation{Name: opPutMetricData}}
+	r.Error = awserr.New("InternalServiceFault", "", nil)
+	handler.Fn(r)
+	assert.Equal(t, 20, l.Get())
+	r.Error = awserr.New("Throttling", "Rate exceeded", nil)
+	handler.Fn(r)
+	assert.Equal(t, 10, l.Get())
+	l.lastChange = time.Time{}
+	r.Operation.Name = opPutLogEvents
+	handler.Fn(r)
+	assert.Equal(t, 10, l.Get())
+}
@@ -0,0 +1,158 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package cloudwatch
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/aws/amazon-cloudwatch-agent/metric/distribution"
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/metric"
+)
+
+// Checkpoint persists the metrics being aggregated when the output is closed, so the samples of the aggregation
+// windows a restart falls in aren't lost. The metrics are aggregated again once the output is started, the file is
+// removed once they are restored.
+type Checkpoint struct {
+	path string
+
+	mu      sync.Mutex
+	metrics []checkpointedMetric
+}
+
+// checkpointedMetric is an aggregated metric of the checkpoint file, its fields are the marshaled distributions.
+type checkpointedMetric struct {
+	AggregationInterval string                     `json:"aggregation_interval"`
+	Name                string                     `json:"name"`
+	Tags                map[string]string          `json:"tags,omitempty"`
+	Time                time.Time                  `json:"timestamp"`
+	Fields              map[string]json.RawMessage `json:"fields"`
+}
+
+// NewCheckpoint returns nil when the path is empty, the aggregated metrics are flushed on shutdown then.
+func NewCheckpoint(path string) *Checkpoint {
+	if path == "" {
+		return nil
+	}
+	return &Checkpoint{path: path}
+}
+
+// add keeps the aggregated metrics of the aggregation interval until the checkpoint is saved.
+func (c *Checkpoint) add(aggregationInterval time.Duration, metrics map[string]telegraf.Metric) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, m := range metrics {
+		fields := make(map[string]json.RawMessage, len(m.FieldList()))
+		for _, field := range m.FieldList() {
+			dist, ok := field.Value.(distribution.Distribution)
+			if !ok {
+				continue
+			}
+			data, err := distribution.Marshal(dist)
+			if err != nil {
+				log.Printf("W! CloudWatch: unable to checkpoint the field %s of metric %s: %v", field.Key, m.Name(), err)
+				continue
+			}
+			fields[field.Key] = data
+		}
+		if len(fields) == 0 {
+			continue
+		}
+		c.metrics = append(c.metrics, checkpointedMetric{
+			AggregationInterval: aggregationInterval.String(),
+			Name:                m.Name(),
+			Tags:                m.Tags(),
+			Time:                m.Time(),
+			Fields:              fields,
+		})
+	}
+}
+
+// Save writes the aggregated metrics to the checkpoint file, through a temporary file so a partial checkpoint is
+// never restored.
+func (c *Checkpoint) Save() error {
+	if c == nil {
+		return nil
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.metrics) == 0 {
+		return nil
+	}
+	data, err := json.Marshal(c.metrics)
+	if err != nil {
+		return err
+	}
+	if err = os.MkdirAll(filepath.Dir(c.path), 0755); err != nil {
+		return err
+	}
+	tmp := c.path + ".tmp"
+	if err = ioutil.WriteFile(tmp, data, 0600); err != nil {
+		return err
+	}
+	if err = os.Rename(tmp, c.path); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	log.Printf("I! CloudWatch: checkpointed %d aggregated metrics to %s", len(c.metrics), c.path)
+	c.metrics = nil
+	return nil
+}
+
+// Restore reads the metrics of the checkpoint file and removes it. The metrics carry their aggregation interval tag,
+// so they are aggregated again with the metrics of the same window.
+func (c *Checkpoint) Restore() ([]telegraf.Metric, error) {
+	if c == nil {
+		return nil, nil
+	}
+	data, err := ioutil.ReadFile(c.path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	// the checkpoint is restored once, even when it can't be parsed
+	if err = os.Remove(c.path); err != nil {
+		return nil, err
+	}
+	var checkpointed []checkpointedMetric
+	if err = json.Unmarshal(data, &checkpointed); err != nil {
+		return nil, fmt.Errorf("invalid checkpoint file %s: %v", c.path, err)
+	}
+	metrics := make([]telegraf.Metric, 0, len(checkpointed))
+	for _, cm := range checkpointed {
+		fields := make(map[string]interface{}, len(cm.Fields))
+		for k, v := range cm.Fields {
+			dist, err := distribution.Unmarshal(v)
+			if err != nil {
+				log.Printf("W! CloudWatch: unable to restore the field %s of metric %s: %v", k, cm.Name, err)
+				continue
+			}
+			fields[k] = dist
+		}
+		if len(fields) == 0 {
+			continue
+		}
+		tags := make(map[string]string, len(cm.Tags)+1)
+		for k, v := range cm.Tags {
+			tags[k] = v
+		}
+		tags[aggregationIntervalTagKey] = cm.AggregationInterval
+		m, err := metric.New(cm.Name, tags, fields, cm.Time)
+		if err != nil {
+			log.Printf("W! CloudWatch: unable to restore the metric %s: %v", cm.Name, err)
+			continue
+		}
+		metrics = append(metrics, m)
+	}
+	return metrics, nil
+}
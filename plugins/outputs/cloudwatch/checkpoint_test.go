@@ -0,0 +1,79 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package cloudwatch
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/amazon-cloudwatch-agent/metric/distribution"
+	"github.com/aws/amazon-cloudwatch-agent/metric/distribution/seh1"
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/metric"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckpoint_Disabled(t *testing.T) {
+	c := NewCheckpoint("")
+	assert.Nil(t, c)
+	assert.NoError(t, c.Save())
+	metrics, err := c.Restore()
+	assert.NoError(t, err)
+	assert.Empty(t, metrics)
+}
+
+func TestCheckpoint_SaveAndRestore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "checkpoint")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "state", "cloudwatch-aggregations.json")
+
+	distribution.NewDistribution = seh1.NewSEH1Distribution
+	metricChan := make(chan telegraf.Metric, metricChanBufferSize)
+	shutdownChan := make(chan struct{})
+	var aggregatorWaitGroup sync.WaitGroup
+	c := NewCheckpoint(file)
+	aggregator := NewAggregator(metricChan, shutdownChan, &aggregatorWaitGroup, c)
+
+	timestamp := time.Now()
+	tags := map[string]string{"d1key": "d1value", aggregationIntervalTagKey: "1s"}
+	for _, value := range []float64{1, 3} {
+		m, _ := metric.New(metricName, tags, map[string]interface{}{"value": value}, timestamp)
+		aggregator.AddMetric(m)
+	}
+	// let the aggregating routine start before the shutdown
+	time.Sleep(100 * time.Millisecond)
+	close(shutdownChan)
+	aggregatorWaitGroup.Wait()
+	assertNoMetricsInChan(t, metricChan)
+	require.NoError(t, c.Save())
+	_, err = os.Stat(file)
+	require.NoError(t, err)
+
+	metrics, err := NewCheckpoint(file).Restore()
+	require.NoError(t, err)
+	require.Len(t, metrics, 1)
+	m := metrics[0]
+	assert.Equal(t, metricName, m.Name())
+	assert.Equal(t, timestamp.Truncate(time.Second).Unix(), m.Time().Unix())
+	assert.Equal(t, map[string]string{"d1key": "d1value", aggregationIntervalTagKey: "1s", highResolutionTagKey: "true"}, m.Tags())
+	dist, ok := m.Fields()["value"].(distribution.Distribution)
+	require.True(t, ok)
+	assert.Equal(t, 2.0, dist.SampleCount())
+	assert.Equal(t, 4.0, dist.Sum())
+	assert.Equal(t, 1.0, dist.Minimum())
+	assert.Equal(t, 3.0, dist.Maximum())
+
+	// the checkpoint is restored once
+	_, err = os.Stat(file)
+	assert.True(t, os.IsNotExist(err))
+	metrics, err = NewCheckpoint(file).Restore()
+	assert.NoError(t, err)
+	assert.Empty(t, metrics)
+}
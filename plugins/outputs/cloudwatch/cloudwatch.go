@@ -6,13 +6,17 @@ package cloudwatch
 import (
 	"log"
 	"math"
+	"os"
 	"reflect"
-	"runtime"
 	"sort"
 	"strings"
 	"sync"
 	"time"
 
+	"github.com/aws/amazon-cloudwatch-agent/internal/audit"
+	"github.com/aws/amazon-cloudwatch-agent/internal/flush"
+	"github.com/aws/amazon-cloudwatch-agent/internal/health"
+	"github.com/aws/amazon-cloudwatch-agent/internal/metricscommon"
 	"github.com/aws/amazon-cloudwatch-agent/internal/publisher"
 	"github.com/aws/amazon-cloudwatch-agent/internal/retryer"
 
@@ -23,8 +27,11 @@ import (
 	"github.com/aws/amazon-cloudwatch-agent/metric/distribution"
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/client"
+	"github.com/aws/aws-sdk-go/aws/request"
 	"github.com/aws/aws-sdk-go/service/cloudwatch"
 	"github.com/aws/aws-sdk-go/service/cloudwatch/cloudwatchiface"
+	"github.com/aws/aws-sdk-go/service/cloudwatchlogs"
 	"github.com/influxdata/telegraf"
 	"github.com/influxdata/telegraf/plugins/outputs"
 )
@@ -40,49 +47,106 @@ const (
 	highResolutionTagKey           = "aws:StorageResolution"
 	defaultRetryCount              = 5 // this is the retry count, the total attempts would be retry count + 1 at most.
 	backoffRetryBase               = 200
+	defaultMaxBackoff              = time.Minute
 	MaxDimensions                  = 30
 )
 
+const (
+	// the distributions of the values, seh1 by default
+	distributionTypeSEH1                 = "seh1"
+	distributionTypeExponentialHistogram = "exponential_histogram"
+	distributionTypeTDigest              = "tdigest"
+)
+
 const (
 	opPutLogEvents       = "PutLogEvents"
 	opPutMetricData      = "PutMetricData"
 	dropOriginalWildcard = "*"
+	// the name of the pipeline in the health notifications
+	pipelineName = "cloudwatch"
 )
 
 type CloudWatch struct {
-	Region             string                   `toml:"region"`
-	EndpointOverride   string                   `toml:"endpoint_override"`
-	AccessKey          string                   `toml:"access_key"`
-	SecretKey          string                   `toml:"secret_key"`
-	RoleARN            string                   `toml:"role_arn"`
-	Profile            string                   `toml:"profile"`
-	Filename           string                   `toml:"shared_credential_file"`
-	Token              string                   `toml:"token"`
-	ForceFlushInterval internal.Duration        `toml:"force_flush_interval"` // unit is second
-	MaxDatumsPerCall   int                      `toml:"max_datums_per_call"`
-	MaxValuesPerDatum  int                      `toml:"max_values_per_datum"`
-	MetricConfigs      []MetricDecorationConfig `toml:"metric_decoration"`
-	RollupDimensions   [][]string               `toml:"rollup_dimensions"`
-	DropOriginConfigs  map[string][]string      `toml:"drop_original_metrics"`
-	Namespace          string                   `toml:"namespace"` // CloudWatch Metrics Namespace
+	Region              string                   `toml:"region"`
+	EndpointOverride    string                   `toml:"endpoint_override"`
+	AccessKey           string                   `toml:"access_key"`
+	SecretKey           string                   `toml:"secret_key"`
+	RoleARN             string                   `toml:"role_arn"`
+	Profile             string                   `toml:"profile"`
+	Filename            string                   `toml:"shared_credential_file"`
+	Token               string                   `toml:"token"`
+	ForceFlushInterval  internal.Duration        `toml:"force_flush_interval"` // unit is second
+	MaxDatumsPerCall    int                      `toml:"max_datums_per_call"`
+	MaxPayloadSize      int                      `toml:"max_payload_size"`
+	MaxValuesPerDatum   int                      `toml:"max_values_per_datum"`
+	DistributionType    string                   `toml:"distribution_type"`
+	TDigestCompression  float64                  `toml:"tdigest_compression"`
+	SEH1Epsilon         float64                  `toml:"seh1_epsilon"`
+	NegativeValues      string                   `toml:"negative_values"`
+	UnitConflicts       string                   `toml:"unit_conflicts"`
+	MetricConfigs       []MetricDecorationConfig `toml:"metric_decoration"`
+	MetricRenameConfigs []MetricRenameConfig     `toml:"metric_rename"`
+	RollupDimensions    [][]string               `toml:"rollup_dimensions"`
+	DropOriginConfigs   map[string][]string      `toml:"drop_original_metrics"`
+	Namespace           string                   `toml:"namespace"` // CloudWatch Metrics Namespace
+	DownsamplingConfigs []DownsamplingConfig     `toml:"downsampling"`
+	// NamespaceRouting are the rules sending the metrics to a namespace by the value of a dimension
+	NamespaceRouting []NamespaceRoutingConfig `toml:"namespace_routing"`
+	// DimensionNormalization is the policy enforced on the dimensions of every metric
+	DimensionNormalization *DimensionNormalizationConfig `toml:"dimension_normalization"`
+	// QuarantineFile is the file the datums PutMetricData would reject are written to
+	QuarantineFile string `toml:"quarantine_file"`
+	// APICallTimeout and DNSResolutionTimeout bound the API calls and the resolution of the endpoint
+	APICallTimeout       internal.Duration `toml:"api_call_timeout"`
+	DNSResolutionTimeout internal.Duration `toml:"dns_resolution_timeout"`
+	// MaxRetries is the attempts of a request, MaxBackoff bounds the backoff between them and MaxRequestsPerSecond
+	// limits the attempts of the requests, adapting to the throttling, when positive
+	MaxRetries           int               `toml:"max_retries"`
+	MaxBackoff           internal.Duration `toml:"max_backoff"`
+	MaxRequestsPerSecond float64           `toml:"max_requests_per_second"`
+	// CheckpointFile is the file the metrics being aggregated are persisted to on shutdown and restored from on start
+	CheckpointFile string `toml:"checkpoint_file"`
+	// OutputMode publishes the datums with PutMetricData, or as embedded metric format documents to the log stream of
+	// LogGroupName and LogStreamName with emf
+	OutputMode    string `toml:"output_mode"`
+	LogGroupName  string `toml:"log_group_name"`
+	LogStreamName string `toml:"log_stream_name"`
 
 	Log telegraf.Logger `toml:"-"`
 
 	svc                    cloudwatchiface.CloudWatchAPI
+	emf                    *EMFWriter
 	aggregator             Aggregator
 	aggregatorShutdownChan chan struct{}
 	aggregatorWaitGroup    sync.WaitGroup
+	checkpoint             *Checkpoint
 	metricChan             chan telegraf.Metric
-	datumBatchChan         chan []*cloudwatch.MetricDatum
+	datumBatchChan         chan datumBatch
 	datumBatchFullChan     chan bool
-	metricDatumBatch       *MetricDatumBatch
+	datumBatchFlushChan    chan bool
+	metricDatumBatches     map[string]*MetricDatumBatch
+	rollup                 *Rollup
 	shutdownChan           chan struct{}
 	pushTicker             *time.Ticker
 	metricDecorations      *MetricDecorations
+	metricRenames          *MetricRenames
 	retries                int
 	publisher              *publisher.Publisher
 	retryer                *retryer.LogThrottleRetryer
 	droppingOriginMetrics  map[string]map[string]struct{}
+	downsampling           *Downsampling
+	dimensionNormalization *DimensionNormalization
+	namespaceRouting       *NamespaceRouting
+	quotaGuard             *QuotaGuard
+	batchLimit             *BatchLimit
+	rateLimiter            *retryer.RateLimiter
+	requestStats           *retryer.RequestStats
+}
+
+// datumBatch are the datums of a PutMetricData request, of a single namespace.
+type datumBatch struct {
+	namespace string
+	datums    []*cloudwatch.MetricDatum
 }
 
 var sampleConfig = `
@@ -104,13 +168,98 @@ var sampleConfig = `
   #profile = ""
   #shared_credential_file = ""
 
+  ## The timeout of an API call, from the connection to the end of the response, and the timeout of the resolution
+  ## of the endpoint, as part of the call.
+  # api_call_timeout = "1m"
+  # dns_resolution_timeout = "10s"
+
   ## Namespace for the CloudWatch MetricDatums
   namespace = "InfluxData/Telegraf"
 
   ## RollupDimensions
   # RollupDimensions = [["host"],["host", "ImageId"],[]]
+
+  ## Downsampling collapses the metrics older than older_than into aggregates of interval
+  ## before publishing them, e.g. when the metrics buffered during an outage are drained.
+  # [[outputs.cloudwatch.downsampling]]
+  #   older_than = "1h"
+  #   interval = "1m"
+
+  ## Dimension normalization enforces the case, the allowed characters and the max length of the dimension names
+  ## and values, so the sources reporting the same dimension differently publish to the same series.
+  # [outputs.cloudwatch.dimension_normalization]
+  #   key_case = "none"
+  #   value_case = "lower"
+  #   allowed_characters = "a-zA-Z0-9_.:/-"
+  #   replacement = "_"
+  #   max_length = 255
+
+  ## Namespace routing sends the metrics with a dimension to another namespace, {value} is replaced with the value
+  ## of the dimension. The first matching rule applies, the other metrics are sent to the namespace of the output.
+  # [[outputs.cloudwatch.namespace_routing]]
+  #   dimension = "team"
+  #   namespace = "Teams/{value}"
+  #   drop_dimension = true
+  #   [outputs.cloudwatch.namespace_routing.namespaces]
+  #     payments = "Payments"
+
+  ## The datums PutMetricData would reject, e.g. a metric name longer than 255 characters or a NaN value, are
+  ## quarantined instead of failing their whole request. They are counted in the datums_quarantined stat of the
+  ## internal input, and written as json lines to the quarantine file when set.
+  # quarantine_file = "/opt/aws/amazon-cloudwatch-agent/logs/cloudwatch-quarantine.log"
+
+  ## The distribution of the values of the statsd timings and the other distributions, "seh1",
+  ## "exponential_histogram" whose buckets are the ones of the OpenTelemetry base-2 exponential histograms, or
+  ## "tdigest" whose centroids keep the high percentiles accurate. The compression bounds the centroids of a
+  ## t-digest, at most max_values_per_datum.
+  # distribution_type = "seh1"
+  # tdigest_compression = 100.0
+  ## The growth of the seh1 buckets, from 0.01 to 1. A lower epsilon publishes values closer to the actual ones,
+  ## e.g. 20.09 instead of 20.13 for 20 with 0.01, in more buckets.
+  # seh1_epsilon = 0.1
+  ## The negative values of the distributions, which can't be bucketed, are dropped by default, added as 0 with
+  ## clamp, or only added to the sample count, sum, minimum and maximum with track.
+  # negative_values = "drop"
+  ## The values added to the distributions in a unit converted to their unit, e.g. Milliseconds to Seconds, are
+  ## converted, the other units are merged keeping the unit of the distribution with prefer_left, rejected with fail,
+  ## or dropped with drop.
+  # unit_conflicts = "prefer_left"
+
+  ## The file the metrics being aggregated are persisted to on shutdown, they are aggregated again on start so a
+  ## restart during an aggregation interval doesn't lose samples. Without it they are published on shutdown.
+  # checkpoint_file = "/opt/aws/amazon-cloudwatch-agent/logs/state/cloudwatch-aggregations.json"
+
+  ## The datums of a PutMetricData request, up to 1000, and the payload in bytes they are packed in, up to 1000000.
+  ## The gzipped requests are limited to fewer datums while they are throttled.
+  # max_datums_per_call = 20
+  # max_payload_size = 200000
+
+  ## A request is sent up to max_retries times, waiting twice longer between the attempts up to max_backoff. The
+  ## attempts of the requests are limited to max_requests_per_second when set, halved while they are throttled.
+  ## The throttled and retried attempts are counted in the requests_throttled and requests_retried stats of the
+  ## internal input.
+  # max_retries = 5
+  # max_backoff = "1m"
+  # max_requests_per_second = 50.0
+
+  ## The datums are published with PutMetricData by default, or written to the log stream as embedded metric format
+  ## documents with "emf", CloudWatch extracts their metrics and Logs Insights queries them by any dimension.
+  # output_mode = "emf"
+  # log_group_name = "/aws/cwagent/metrics"
+  # log_stream_name = "i-0123456789abcdef0"
 `
 
+// maxDatumsPerCall is max_datums_per_call, 20 by default and at most the 1000 datums of a request.
+func (c *CloudWatch) maxDatumsPerCall() int {
+	if c.MaxDatumsPerCall <= 0 {
+		return defaultMaxDatumsPerCall
+	}
+	if c.MaxDatumsPerCall > maxDatumsPerCallLimit {
+		return maxDatumsPerCallLimit
+	}
+	return c.MaxDatumsPerCall
+}
+
 func (c *CloudWatch) SampleConfig() string {
 	return sampleConfig
 }
@@ -121,12 +270,43 @@ func (c *CloudWatch) Description() string {
 
 func (c *CloudWatch) Connect() error {
 	var err error
+	if err = validateNamespace(c.Namespace); err != nil {
+		return err
+	}
+	if err = validateDistributionType(c.DistributionType); err != nil {
+		return err
+	}
+	if err = distribution.SetNegativeValuePolicy(c.NegativeValues); err != nil {
+		return err
+	}
+	if err = distribution.SetUnitConflictPolicy(c.UnitConflicts); err != nil {
+		return err
+	}
+	if err = validateOutputMode(c.OutputMode); err != nil {
+		return err
+	}
 	c.publisher, _ = publisher.NewPublisher(publisher.NewNonBlockingFifoQueue(metricChanBufferSize), maxConcurrentPublisher, 2*time.Second, c.WriteToCloudWatch)
 
 	if c.metricDecorations, err = NewMetricDecorations(c.MetricConfigs); err != nil {
 		return err
 	}
 
+	if c.metricRenames, err = NewMetricRenames(c.MetricRenameConfigs); err != nil {
+		return err
+	}
+
+	if c.downsampling, err = NewDownsampling(c.DownsamplingConfigs); err != nil {
+		return err
+	}
+
+	if c.dimensionNormalization, err = NewDimensionNormalization(c.DimensionNormalization); err != nil {
+		return err
+	}
+
+	if c.namespaceRouting, err = NewNamespaceRouting(c.NamespaceRouting, c.Log); err != nil {
+		return err
+	}
+
 	credentialConfig := &configaws.CredentialConfig{
 		Region:    c.Region,
 		AccessKey: c.AccessKey,
@@ -135,6 +315,9 @@ func (c *CloudWatch) Connect() error {
 		Profile:   c.Profile,
 		Filename:  c.Filename,
 		Token:     c.Token,
+
+		APICallTimeout:       c.APICallTimeout.Duration,
+		DNSResolutionTimeout: c.DNSResolutionTimeout.Duration,
 	}
 	configProvider := credentialConfig.Credentials()
 
@@ -150,46 +333,89 @@ func (c *CloudWatch) Connect() error {
 
 	svc.Handlers.Build.PushBackNamed(handlers.NewRequestCompressionHandler([]string{opPutLogEvents, opPutMetricData}))
 	svc.Handlers.Build.PushBackNamed(handlers.NewCustomHeaderHandler("User-Agent", agentinfo.UserAgent("")))
+	c.batchLimit = NewBatchLimit(c.maxDatumsPerCall())
+	svc.Handlers.Retry.PushBackNamed(c.batchLimit.throttleHandler())
+	c.rateLimiter = retryer.NewRateLimiter(c.MaxRequestsPerSecond, opPutMetricData, opPutLogEvents)
+	c.requestStats = retryer.NewRequestStats("cloudwatch", opPutMetricData, opPutLogEvents)
+	c.addRequestHandlers(&svc.Handlers)
 
-	//Format unique roll up list
+	//Format unique roll up list, the rollup dimensions are named like the normalized dimensions
+	for _, rollup := range c.RollupDimensions {
+		for i, key := range rollup {
+			rollup[i] = c.dimensionNormalization.Key(key)
+		}
+	}
 	c.RollupDimensions = GetUniqueRollupList(c.RollupDimensions)
+	c.namespaceRouting.normalizeDimensions(c.dimensionNormalization)
 
 	//Construct map for metrics that dropping origin
 	c.droppingOriginMetrics = GetDroppingDimensionMap(c.DropOriginConfigs)
 
+	audit.EndpointConfigured("cloudwatch", c.Region, c.EndpointOverride)
+
+	if c.OutputMode == OutputModeEMF {
+		c.emf = c.newEMFWriter(configProvider, logThrottleRetryer)
+	}
+
 	c.svc = svc
 	c.retryer = logThrottleRetryer
+	c.quotaGuard = NewQuotaGuard(c.QuarantineFile, c.Log)
 	c.startRoutines()
 	return nil
 }
 
 func (c *CloudWatch) startRoutines() {
 	c.metricChan = make(chan telegraf.Metric, metricChanBufferSize)
-	c.datumBatchChan = make(chan []*cloudwatch.MetricDatum, datumBatchChanBufferSize)
+	c.datumBatchChan = make(chan datumBatch, datumBatchChanBufferSize)
 	c.datumBatchFullChan = make(chan bool, 1)
+	c.datumBatchFlushChan = make(chan bool, 1)
 	c.shutdownChan = make(chan struct{})
 	c.aggregatorShutdownChan = make(chan struct{})
-	c.aggregator = NewAggregator(c.metricChan, c.aggregatorShutdownChan, &c.aggregatorWaitGroup)
+	c.checkpoint = NewCheckpoint(c.CheckpointFile)
+	c.aggregator = NewAggregator(c.metricChan, c.aggregatorShutdownChan, &c.aggregatorWaitGroup, c.checkpoint)
 	if c.ForceFlushInterval.Duration == 0 {
 		c.ForceFlushInterval.Duration = pushIntervalInSec * time.Second
 	}
-	if c.MaxDatumsPerCall == 0 {
-		c.MaxDatumsPerCall = defaultMaxDatumsPerCall
+	c.MaxDatumsPerCall = c.maxDatumsPerCall()
+	if c.MaxPayloadSize <= 0 || c.MaxPayloadSize > maxPayloadSizeLimit {
+		c.MaxPayloadSize = bottomLinePayloadSizeToPublish
+	}
+	if c.batchLimit == nil {
+		c.batchLimit = NewBatchLimit(c.MaxDatumsPerCall)
 	}
 	if c.MaxValuesPerDatum == 0 {
 		c.MaxValuesPerDatum = defaultMaxValuesPerDatum
 	}
-	setNewDistributionFunc(c.MaxValuesPerDatum)
-	perRequestConstSize := overallConstPerRequestSize + len(c.Namespace) + namespaceOverheads
-	c.metricDatumBatch = newMetricDatumBatch(c.MaxDatumsPerCall, perRequestConstSize)
+	setNewDistributionFunc(c.MaxValuesPerDatum, c.DistributionType, c.TDigestCompression, c.SEH1Epsilon)
+	c.metricDatumBatches = map[string]*MetricDatumBatch{}
+	c.rollup = NewRollup(c.MaxValuesPerDatum, c.SEH1Epsilon)
 	go c.pushMetricDatum()
 	go c.publish()
+	c.restoreCheckpoint()
+}
+
+// restoreCheckpoint aggregates the metrics checkpointed on the last shutdown again.
+func (c *CloudWatch) restoreCheckpoint() {
+	metrics, err := c.checkpoint.Restore()
+	if err != nil {
+		log.Printf("E! CloudWatch: unable to restore the aggregated metrics: %v", err)
+		return
+	}
+	for _, m := range metrics {
+		c.aggregator.AddMetric(m)
+	}
+	if len(metrics) > 0 {
+		log.Printf("I! CloudWatch: restored %d aggregated metrics from %s", len(metrics), c.CheckpointFile)
+	}
 }
 
 func (c *CloudWatch) Close() error {
 	log.Println("D! Stopping the CloudWatch output plugin")
 	close(c.aggregatorShutdownChan)
 	c.aggregatorWaitGroup.Wait()
+	if err := c.checkpoint.Save(); err != nil {
+		log.Printf("E! CloudWatch: unable to checkpoint the aggregated metrics: %v", err)
+	}
 	for i := 0; i < 5; i++ {
 		if len(c.metricChan) == 0 && len(c.datumBatchChan) == 0 {
 			break
@@ -204,12 +430,16 @@ func (c *CloudWatch) Close() error {
 	close(c.shutdownChan)
 	c.publisher.Close()
 	c.retryer.Stop()
+	c.quotaGuard.Close()
 	log.Println("D! Stopped the CloudWatch output plugin")
 	return nil
 }
 
 func (c *CloudWatch) Write(metrics []telegraf.Metric) error {
+	now := time.Now()
 	for _, m := range metrics {
+		c.dimensionNormalization.Apply(m)
+		c.downsampling.Apply(m, now)
 		c.aggregator.AddMetric(m)
 	}
 	return nil
@@ -221,25 +451,48 @@ func (c *CloudWatch) Write(metrics []telegraf.Metric) error {
 func (c *CloudWatch) pushMetricDatum() {
 	ticker := time.NewTicker(time.Second)
 	defer ticker.Stop()
+	flushRequested := flush.Requested()
 	for {
 		select {
 		case point := <-c.metricChan:
-			datums := c.BuildMetricDatum(point)
-			numberOfPartitions := len(datums)
-			for i := 0; i < numberOfPartitions; i++ {
-				c.metricDatumBatch.Partition = append(c.metricDatumBatch.Partition, datums[i])
-				c.metricDatumBatch.Size += payload(datums[i])
-				if c.metricDatumBatch.isFull() {
-					// if batch is full
-					c.datumBatchChan <- c.metricDatumBatch.Partition
-					c.metricDatumBatch.clear()
+			// the routing is before the datums are built since it can remove a dimension
+			namespace := c.namespaceRouting.Route(point)
+			if namespace == "" {
+				namespace = c.Namespace
+			}
+			for namespace, datums := range c.buildMetricDatums(point, namespace, c.rollup) {
+				for _, datum := range datums {
+					c.pushDatum(namespace, datum)
 				}
 			}
+			if c.rollup.full() {
+				c.flushRollup()
+			}
 		case <-ticker.C:
-			if c.timeToPublish(c.metricDatumBatch) {
-				// if the time to publish comes
-				c.datumBatchChan <- c.metricDatumBatch.Partition
-				c.metricDatumBatch.clear()
+			if c.rollup.age() >= c.ForceFlushInterval.Duration {
+				c.flushRollup()
+			}
+			for namespace, batch := range c.metricDatumBatches {
+				if c.timeToPublish(batch) {
+					// if the time to publish comes
+					c.datumBatchChan <- datumBatch{namespace: namespace, datums: batch.Partition}
+					batch.clear()
+				}
+			}
+		case <-flushRequested:
+			// the batches are queued whatever their age and published at once, the aggregated metrics are only
+			// flushed at the end of their aggregation interval
+			flushRequested = flush.Requested()
+			c.flushRollup()
+			for namespace, batch := range c.metricDatumBatches {
+				if len(batch.Partition) > 0 {
+					c.datumBatchChan <- datumBatch{namespace: namespace, datums: batch.Partition}
+					batch.clear()
+				}
+			}
+			select {
+			case c.datumBatchFlushChan <- true:
+			default:
 			}
 		case <-c.shutdownChan:
 			return
@@ -247,17 +500,62 @@ func (c *CloudWatch) pushMetricDatum() {
 	}
 }
 
+// pushDatum adds the datum to the batch of the namespace, the datums are packed by their payload and the batch is
+// sent first when the datum doesn't fit.
+func (c *CloudWatch) pushDatum(namespace string, datum *cloudwatch.MetricDatum) {
+	if !c.quotaGuard.Check(datum) {
+		return
+	}
+	batch := c.metricDatumBatch(namespace)
+	batch.MaxDatumsPerCall = c.batchLimit.Get()
+	size := payload(datum)
+	if !batch.fits(size) {
+		c.datumBatchChan <- datumBatch{namespace: namespace, datums: batch.Partition}
+		batch.clear()
+	}
+	batch.Partition = append(batch.Partition, datum)
+	batch.Size += size
+	if batch.isFull() {
+		// if batch is full
+		c.datumBatchChan <- datumBatch{namespace: namespace, datums: batch.Partition}
+		batch.clear()
+	}
+}
+
+// flushRollup adds the datums of the rollup dimensions merged since the last flush to the batches.
+func (c *CloudWatch) flushRollup() {
+	for namespace, datums := range c.rollup.flush() {
+		for _, datum := range datums {
+			c.pushDatum(namespace, datum)
+		}
+	}
+}
+
+// metricDatumBatch returns the batch of the datums of a namespace, the size of the namespace is part of the
+// size of its requests.
+func (c *CloudWatch) metricDatumBatch(namespace string) *MetricDatumBatch {
+	batch, ok := c.metricDatumBatches[namespace]
+	if !ok {
+		perRequestConstSize := overallConstPerRequestSize + len(namespace) + namespaceOverheads
+		batch = newMetricDatumBatch(c.MaxDatumsPerCall, c.MaxPayloadSize, perRequestConstSize)
+		c.metricDatumBatches[namespace] = batch
+	}
+	return batch
+}
+
 type MetricDatumBatch struct {
 	MaxDatumsPerCall    int
 	Partition           []*cloudwatch.MetricDatum
 	BeginTime           time.Time
 	Size                int
+	MaxPayloadSize      int
 	perRequestConstSize int
 }
 
-func newMetricDatumBatch(maxDatumsPerCall, perRequestConstSize int) *MetricDatumBatch {
+func newMetricDatumBatch(maxDatumsPerCall, maxPayloadSize, perRequestConstSize int) *MetricDatumBatch {
 	return &MetricDatumBatch{
 		MaxDatumsPerCall:    maxDatumsPerCall,
+		MaxPayloadSize:      maxPayloadSize,
 		Partition:           make([]*cloudwatch.MetricDatum, 0, maxDatumsPerCall),
 		BeginTime:           time.Now(),
 		Size:                perRequestConstSize,
@@ -272,7 +570,12 @@ func (b *MetricDatumBatch) clear() {
 }
 
 func (b *MetricDatumBatch) isFull() bool {
-	return len(b.Partition) >= b.MaxDatumsPerCall || b.Size >= bottomLinePayloadSizeToPublish
+	return len(b.Partition) >= b.MaxDatumsPerCall || b.Size >= b.MaxPayloadSize
+}
+
+// fits tells whether a datum of the payload size can be added to the batch, a datum always fits an empty batch.
+func (b *MetricDatumBatch) fits(size int) bool {
+	return len(b.Partition) == 0 || (len(b.Partition) < b.MaxDatumsPerCall && b.Size+size <= b.MaxPayloadSize)
 }
 
 func (c *CloudWatch) timeToPublish(b *MetricDatumBatch) bool {
@@ -299,6 +602,8 @@ func (c *CloudWatch) publish() {
 			shouldPublish = true
 		case <-c.metricDatumBatchFull():
 			shouldPublish = true
+		case <-c.datumBatchFlushChan:
+			shouldPublish = true
 		default:
 			shouldPublish = false
 		}
@@ -332,29 +637,76 @@ func (c *CloudWatch) pushMetricDatumBatch() {
 	}
 }
 
-//sleep some back off time before retries.
+// addRequestHandlers limits the rate of the requests and counts their throttled and retried attempts.
+func (c *CloudWatch) addRequestHandlers(h *request.Handlers) {
+	c.rateLimiter.AddHandlers(h)
+	c.requestStats.AddHandlers(h)
+}
+
+// maxRetries is max_retries, the 5 attempts of a request by default.
+func (c *CloudWatch) maxRetries() int {
+	if c.MaxRetries <= 0 {
+		return defaultRetryCount
+	}
+	return c.MaxRetries
+}
+
+// sleep some back off time before retries, at most max_backoff.
 func (c *CloudWatch) backoffSleep() {
-	var backoffInMillis int64 = 60 * 1000 // 1 minute
+	sleepDuration := defaultMaxBackoff
+	if c.MaxBackoff.Duration > 0 {
+		sleepDuration = c.MaxBackoff.Duration
+	}
 	if c.retries <= defaultRetryCount {
-		backoffInMillis = int64(backoffRetryBase * math.Pow(2, float64(c.retries)))
+		if backoff := time.Millisecond * time.Duration(backoffRetryBase*math.Pow(2, float64(c.retries))); backoff < sleepDuration {
+			sleepDuration = backoff
+		}
 	}
-	sleepDuration := time.Millisecond * time.Duration(backoffInMillis)
 	log.Printf("W! %v retries, going to sleep %v before retrying.", c.retries, sleepDuration)
 	c.retries++
 	time.Sleep(sleepDuration)
 }
 
+// newEMFWriter returns the writer of the embedded metric format documents, the log stream is the host name when it
+// isn't configured.
+func (c *CloudWatch) newEMFWriter(configProvider client.ConfigProvider, logThrottleRetryer *retryer.LogThrottleRetryer) *EMFWriter {
+	svc := cloudwatchlogs.New(
+		configProvider,
+		&aws.Config{
+			Retryer:  logThrottleRetryer,
+			LogLevel: configaws.SDKLogLevel(),
+			Logger:   configaws.SDKLogger{},
+		})
+	svc.Handlers.Build.PushBackNamed(handlers.NewRequestCompressionHandler([]string{opPutLogEvents}))
+	svc.Handlers.Build.PushBackNamed(handlers.NewCustomHeaderHandler("User-Agent", agentinfo.UserAgent("")))
+	svc.Handlers.Build.PushBackNamed(handlers.NewCustomHeaderHandler("x-amzn-logs-format", "json/emf"))
+	c.addRequestHandlers(&svc.Handlers)
+	stream := c.LogStreamName
+	if stream == "" {
+		stream, _ = os.Hostname()
+	}
+	return NewEMFWriter(svc, c.LogGroupName, stream)
+}
+
 func (c *CloudWatch) WriteToCloudWatch(req interface{}) {
-	datums := req.([]*cloudwatch.MetricDatum)
+	batch := req.(datumBatch)
+	if c.emf != nil {
+		c.writeToCloudWatchLogs(batch)
+		return
+	}
 	params := &cloudwatch.PutMetricDataInput{
-		MetricData: datums,
-		Namespace:  aws.String(c.Namespace),
+		MetricData: batch.datums,
+		Namespace:  aws.String(batch.namespace),
 	}
 	var err error
-	for i := 0; i < defaultRetryCount; i++ {
+	for i := 0; i < c.maxRetries(); i++ {
+		if i > 0 {
+			c.requestStats.Retried(opPutMetricData)
+		}
 		_, err = c.svc.PutMetricData(params)
 
 		if err != nil {
+			health.RecordFailure(pipelineName, err)
 			awsErr, ok := err.(awserr.Error)
 			if !ok {
 				log.Printf("E! Cannot cast PutMetricData error %v into awserr.Error.", err)
@@ -375,6 +727,9 @@ func (c *CloudWatch) WriteToCloudWatch(req interface{}) {
 			}
 		} else {
 			c.retries = 0
+			c.batchLimit.succeeded()
+			c.rateLimiter.Succeeded()
+			health.RecordSuccess(pipelineName)
 		}
 		break
 	}
@@ -383,20 +738,37 @@ func (c *CloudWatch) WriteToCloudWatch(req interface{}) {
 	}
 }
 
+// writeToCloudWatchLogs writes the datums of the batch as embedded metric format documents, retrying like the
+// PutMetricData requests.
+func (c *CloudWatch) writeToCloudWatchLogs(batch datumBatch) {
+	var err error
+	for i := 0; i < c.maxRetries(); i++ {
+		if i > 0 {
+			c.requestStats.Retried(opPutLogEvents)
+		}
+		if err = c.emf.Write(batch.namespace, batch.datums); err == nil {
+			c.retries = 0
+			c.rateLimiter.Succeeded()
+			health.RecordSuccess(pipelineName)
+			return
+		}
+		health.RecordFailure(pipelineName, err)
+		awsErr, ok := err.(awserr.Error)
+		if !ok || awsErr.Code() != "ThrottlingException" && awsErr.Code() != cloudwatchlogs.ErrCodeServiceUnavailableException {
+			break
+		}
+		log.Printf("W! cloudwatch PutLogEvents, error: %s, message: %s", awsErr.Code(), awsErr.Message())
+		c.backoffSleep()
+	}
+	log.Println("E! WriteToCloudWatch failure, err: ", err)
+}
+
 func (c *CloudWatch) decorateMetricName(category string, name string) (decoratedName string) {
 	if c.metricDecorations != nil {
 		decoratedName = c.metricDecorations.getRename(category, name)
 	}
 	if decoratedName == "" {
-		if name == "value" {
-			decoratedName = category
-		} else {
-			separator := "_"
-			if runtime.GOOS == "windows" {
-				separator = " "
-			}
-			decoratedName = strings.Join([]string{category, name}, separator)
-		}
+		decoratedName = metricscommon.MetricName(category, name)
 	}
 	return
 }
@@ -411,6 +783,17 @@ func (c *CloudWatch) decorateMetricUnit(category string, name string) (decorated
 // Create MetricDatums according to metric roll up requirement for each field in a Point. Only fields with values that can be
 // converted to float64 are supported. Non-supported fields are skipped.
 func (c *CloudWatch) BuildMetricDatum(point telegraf.Metric) []*cloudwatch.MetricDatum {
+	var datums []*cloudwatch.MetricDatum
+	for _, namespaceDatums := range c.buildMetricDatums(point, "", nil) {
+		datums = append(datums, namespaceDatums...)
+	}
+	return datums
+}
+
+// buildMetricDatums builds the datums of the point by namespace, the fields renamed into another namespace are built
+// in theirs. The fields of the rollup dimensions are merged into the rollup instead when it isn't nil, their datums
+// are built once it's flushed.
+func (c *CloudWatch) buildMetricDatums(point telegraf.Metric, namespace string, rollup *Rollup) map[string][]*cloudwatch.MetricDatum {
 	//high resolution logic
 	isHighResolution := false
 	highResolutionValue, ok := point.Tags()[highResolutionTagKey]
@@ -423,58 +806,40 @@ func (c *CloudWatch) BuildMetricDatum(point telegraf.Metric) []*cloudwatch.Metri
 	dimensionsList := c.ProcessRollup(rawDimensions)
 	//https://pratheekadidela.in/2016/02/11/is-append-in-go-efficient/
 	//https://www.ardanlabs.com/blog/2013/08/understanding-slices-in-go-programming.html
-	var datums []*cloudwatch.MetricDatum
+	datums := map[string][]*cloudwatch.MetricDatum{}
 	for k, v := range point.Fields() {
 		var unit string
 		var value float64
+		var dist distribution.Distribution
 		var distList []distribution.Distribution
 
 		switch t := v.(type) {
-		case uint:
-			value = float64(t)
-		case uint8:
-			value = float64(t)
-		case uint16:
-			value = float64(t)
-		case uint32:
-			value = float64(t)
-		case uint64:
-			value = float64(t)
-		case int:
-			value = float64(t)
-		case int8:
-			value = float64(t)
-		case int16:
-			value = float64(t)
-		case int32:
-			value = float64(t)
-		case int64:
-			value = float64(t)
-		case float32:
-			value = float64(t)
-		case float64:
-			value = t
-		case bool:
-			if t {
-				value = 1
-			} else {
-				value = 0
-			}
-		case time.Time:
-			value = float64(t.Unix())
 		case distribution.Distribution:
-			if t.Size() == 0 {
+			if t.SampleCount() == 0 {
 				// the distribution does not have a value
 				continue
 			}
-			distList = resize(t, c.MaxValuesPerDatum)
+			dist = t
+			if t.Size() == 0 {
+				// the tracked negative values are only published as statistics
+				distList = []distribution.Distribution{t}
+			} else {
+				distList = resize(t, c.MaxValuesPerDatum, c.SEH1Epsilon)
+			}
 			unit = t.Unit()
 		default:
-			// Skip unsupported type.
-			continue
+			var ok bool
+			if value, ok = metricscommon.ToFloat(v); !ok {
+				// Skip unsupported type.
+				continue
+			}
 		}
 
-		metricName := aws.String(c.decorateMetricName(point.Name(), k))
+		name, fieldNamespace := c.metricRenames.Rename(c.decorateMetricName(point.Name(), k))
+		if fieldNamespace == "" {
+			fieldNamespace = namespace
+		}
+		metricName := aws.String(name)
 		if unit == "" {
 			unit = c.decorateMetricUnit(point.Name(), k)
 		}
@@ -485,6 +850,9 @@ func (c *CloudWatch) BuildMetricDatum(point telegraf.Metric) []*cloudwatch.Metri
 			if index == 0 && c.IsDropping(point.Name(), k) {
 				continue
 			}
+			if index > 0 && rollup.add(fieldNamespace, metricName, dimensions, point.Time(), unit, isHighResolution, value, dist) {
+				continue
+			}
 			if len(distList) == 0 {
 				datum := &cloudwatch.MetricDatum{
 					MetricName: metricName,
@@ -498,37 +866,45 @@ func (c *CloudWatch) BuildMetricDatum(point telegraf.Metric) []*cloudwatch.Metri
 				if isHighResolution {
 					datum.SetStorageResolution(1)
 				}
-				datums = append(datums, datum)
+				datums[fieldNamespace] = append(datums[fieldNamespace], datum)
 			} else {
-				for _, dist := range distList {
-					datum := &cloudwatch.MetricDatum{
-						MetricName: metricName,
-						Dimensions: dimensions,
-						Timestamp:  aws.Time(point.Time()),
-					}
-					values, counts := dist.ValuesAndCounts()
-					datum.SetValues(aws.Float64Slice(values))
-					datum.SetCounts(aws.Float64Slice(counts))
-					datum.SetStatisticValues(&cloudwatch.StatisticSet{
-						Maximum:     aws.Float64(dist.Maximum()),
-						Minimum:     aws.Float64(dist.Minimum()),
-						SampleCount: aws.Float64(dist.SampleCount()),
-						Sum:         aws.Float64(dist.Sum()),
-					})
-					if unit != "" {
-						datum.SetUnit(unit)
-					}
-					if isHighResolution {
-						datum.SetStorageResolution(1)
-					}
-					datums = append(datums, datum)
-				}
+				datums[fieldNamespace] = append(datums[fieldNamespace], distributionDatums(metricName, dimensions, point.Time(), unit, isHighResolution, distList)...)
 			}
 		}
 	}
 	return datums
 }
 
+// distributionDatums builds a datum by distribution of the list.
+func distributionDatums(metricName *string, dimensions []*cloudwatch.Dimension, timestamp time.Time, unit string, isHighResolution bool, distList []distribution.Distribution) []*cloudwatch.MetricDatum {
+	datums := make([]*cloudwatch.MetricDatum, 0, len(distList))
+	for _, dist := range distList {
+		datum := &cloudwatch.MetricDatum{
+			MetricName: metricName,
+			Dimensions: dimensions,
+			Timestamp:  aws.Time(timestamp),
+		}
+		if values, counts := dist.ValuesAndCounts(); len(values) > 0 {
+			datum.SetValues(aws.Float64Slice(values))
+			datum.SetCounts(aws.Float64Slice(counts))
+		}
+		datum.SetStatisticValues(&cloudwatch.StatisticSet{
+			Maximum:     aws.Float64(dist.Maximum()),
+			Minimum:     aws.Float64(dist.Minimum()),
+			SampleCount: aws.Float64(dist.SampleCount()),
+			Sum:         aws.Float64(dist.Sum()),
+		})
+		if unit != "" {
+			datum.SetUnit(unit)
+		}
+		if isHighResolution {
+			datum.SetStorageResolution(1)
+		}
+		datums = append(datums, datum)
+	}
+	return datums
+}
+
 // Make a list of Dimensions by using a Point's tags. CloudWatch supports up to
 // 30 dimensions per metric so we only keep up to the first 30 alphabetically.
 // This always includes the "host" tag if it exists.
@@ -14,6 +14,7 @@ import (
 
 	"github.com/aws/amazon-cloudwatch-agent/internal"
 	"github.com/aws/amazon-cloudwatch-agent/internal/publisher"
+	"github.com/aws/amazon-cloudwatch-agent/internal/retryer"
 	"github.com/aws/amazon-cloudwatch-agent/metric/distribution"
 	"github.com/aws/amazon-cloudwatch-agent/metric/distribution/regular"
 	"github.com/aws/aws-sdk-go/aws"
@@ -22,6 +23,7 @@ import (
 	"github.com/aws/aws-sdk-go/service/cloudwatch/cloudwatchiface"
 	"github.com/influxdata/telegraf"
 	"github.com/influxdata/telegraf/metric"
+	"github.com/influxdata/telegraf/selfstat"
 	"github.com/influxdata/telegraf/testutil"
 	"github.com/influxdata/toml"
 	"github.com/influxdata/toml/ast"
@@ -94,9 +96,9 @@ func TestBuildMetricDatums(t *testing.T) {
 	}
 
 	invalidDistribution := distribution.NewDistribution()
+	// the negative values are dropped by default
 	err := invalidDistribution.AddEntry(-1, 1)
-	expectedErrMsg := "negative value"
-	assert.EqualError(err, expectedErrMsg)
+	assert.NoError(err)
 	invalidMetrics := []telegraf.Metric{
 		testutil.TestMetric("Foo"),
 		testutil.TestMetric(invalidDistribution),
@@ -108,6 +110,27 @@ func TestBuildMetricDatums(t *testing.T) {
 	}
 }
 
+func TestBuildMetricDatums_TrackedNegativeValues(t *testing.T) {
+	defer distribution.SetNegativeValuePolicy(distribution.NegativeValuesDrop)
+	c := &CloudWatch{MaxValuesPerDatum: 150, NegativeValues: distribution.NegativeValuesTrack}
+	assert.NoError(t, distribution.SetNegativeValuePolicy(c.NegativeValues))
+
+	dist := regular.NewRegularDistribution()
+	assert.NoError(t, dist.AddEntry(-2, 1))
+	assert.NoError(t, dist.AddEntry(-4, 1))
+	datums := c.BuildMetricDatum(testutil.TestMetric(dist))
+	assert.Equal(t, 1, len(datums))
+	// the tracked negative values only have statistics
+	assert.Empty(t, datums[0].Values)
+	assert.Empty(t, datums[0].Counts)
+	assert.Equal(t, &cloudwatch.StatisticSet{
+		Maximum:     aws.Float64(-2),
+		Minimum:     aws.Float64(-4),
+		SampleCount: aws.Float64(2),
+		Sum:         aws.Float64(-6),
+	}, datums[0].StatisticValues)
+}
+
 func TestProcessRollup(t *testing.T) {
 	svc := new(mockCloudWatchClient)
 	cloudWatchOutput := newCloudWatchClient(svc)
@@ -276,7 +299,7 @@ func TestIsFlushable(t *testing.T) {
 
 	assert := assert.New(t)
 	perRequestConstSize := overallConstPerRequestSize + len("CWAgent") + namespaceOverheads
-	batch := newMetricDatumBatch(defaultMaxDatumsPerCall, perRequestConstSize)
+	batch := newMetricDatumBatch(defaultMaxDatumsPerCall, bottomLinePayloadSizeToPublish, perRequestConstSize)
 	tags := map[string]string{}
 	datum := cloudwatch.MetricDatum{
 		MetricName: aws.String("test_metric"),
@@ -293,7 +316,7 @@ func TestIsFlushable(t *testing.T) {
 func TestIsFull(t *testing.T) {
 	assert := assert.New(t)
 	perRequestConstSize := overallConstPerRequestSize + len("CWAgent") + namespaceOverheads
-	batch := newMetricDatumBatch(defaultMaxDatumsPerCall, perRequestConstSize)
+	batch := newMetricDatumBatch(defaultMaxDatumsPerCall, bottomLinePayloadSizeToPublish, perRequestConstSize)
 	tags := map[string]string{}
 	datum := cloudwatch.MetricDatum{
 		MetricName: aws.String("test_metric"),
@@ -313,6 +336,30 @@ func TestIsFull(t *testing.T) {
 	assert.True(batch.isFull())
 }
 
+func TestFits(t *testing.T) {
+	perRequestConstSize := overallConstPerRequestSize + len("CWAgent") + namespaceOverheads
+	datum := &cloudwatch.MetricDatum{
+		MetricName: aws.String("test_metric"),
+		Value:      aws.Float64(1),
+		Timestamp:  aws.Time(time.Now()),
+	}
+	size := payload(datum)
+	batch := newMetricDatumBatch(defaultMaxDatumsPerCall, perRequestConstSize+2*size, perRequestConstSize)
+	// a datum always fits an empty batch
+	assert.True(t, batch.fits(3*size))
+	for i := 0; i < 2; i++ {
+		assert.True(t, batch.fits(size))
+		batch.Partition = append(batch.Partition, datum)
+		batch.Size += size
+	}
+	assert.False(t, batch.fits(size))
+	assert.True(t, batch.isFull())
+
+	batch = newMetricDatumBatch(2, bottomLinePayloadSizeToPublish, perRequestConstSize)
+	batch.Partition = append(batch.Partition, datum, datum)
+	assert.False(t, batch.fits(size))
+}
+
 type mockCloudWatchClient struct {
 	cloudwatchiface.CloudWatchAPI
 	mock.Mock
@@ -545,7 +592,7 @@ func TestBackoffRetries(t *testing.T) {
 
 func TestCloudWatch_metricDatumBatchFull(t *testing.T) {
 	c := &CloudWatch{
-		datumBatchChan:     make(chan []*cloudwatch.MetricDatum, datumBatchChanBufferSize),
+		datumBatchChan:     make(chan datumBatch, datumBatchChanBufferSize),
 		datumBatchFullChan: make(chan bool, 1),
 	}
 
@@ -556,7 +603,7 @@ func TestCloudWatch_metricDatumBatchFull(t *testing.T) {
 	}
 
 	for i := 0; i < datumBatchChanBufferSize; i++ {
-		c.datumBatchChan <- []*cloudwatch.MetricDatum{}
+		c.datumBatchChan <- datumBatch{}
 	}
 
 	select {
@@ -577,7 +624,7 @@ func TestCloudWatch_metricDatumBatchFull(t *testing.T) {
 
 func TestBuildMetricDatums_SkipEmptyTags(t *testing.T) {
 	c := &CloudWatch{
-		datumBatchChan:     make(chan []*cloudwatch.MetricDatum, 0),
+		datumBatchChan:     make(chan datumBatch, 0),
 		datumBatchFullChan: make(chan bool, 1),
 	}
 	input := testutil.MustMetric(
@@ -625,3 +672,25 @@ func TestIsDroppingWildCard(t *testing.T) {
 	assert.True(t, c.IsDropping("nvidia_smi", "any_metric_name"))
 	assert.True(t, c.IsDropping("nvidia_smi", "utilization_gpu"))
 }
+
+func TestBackoffSleep_MaxBackoff(t *testing.T) {
+	c := &CloudWatch{MaxBackoff: internal.Duration{Duration: 500 * time.Millisecond}}
+	for _, expected := range []time.Duration{200 * time.Millisecond, 400 * time.Millisecond, 500 * time.Millisecond} {
+		start := time.Now()
+		c.backoffSleep()
+		elapsed := time.Since(start)
+		assert.True(t, elapsed >= expected && elapsed < expected+100*time.Millisecond, elapsed)
+	}
+	assert.Equal(t, defaultRetryCount, c.maxRetries())
+}
+
+func TestWriteToCloudWatch_MaxRetries(t *testing.T) {
+	svc := new(mockCloudWatchClient)
+	svc.On("PutMetricData", mock.Anything).Return(&cloudwatch.PutMetricDataOutput{}, awserr.New(cloudwatch.ErrCodeLimitExceededFault, "", nil))
+	c := &CloudWatch{svc: svc, MaxRetries: 2, MaxBackoff: internal.Duration{Duration: 10 * time.Millisecond}, requestStats: retryer.NewRequestStats("cloudwatch", opPutMetricData)}
+	stat := selfstat.Register("cloudwatch", "requests_retried", map[string]string{"operation": opPutMetricData})
+	retried := stat.Get()
+	c.WriteToCloudWatch(datumBatch{namespace: "CWAgent"})
+	svc.AssertNumberOfCalls(t, "PutMetricData", 2)
+	assert.Equal(t, retried+1, stat.Get())
+}
@@ -7,13 +7,17 @@ package cloudwatch
 // follow the format "Prefix_Metric"
 var defaultUnits = map[string]string{
 	"procstat_cpu_usage": "Percent",
+	"procstat_uptime":    "Seconds",
 
-	"procstat_memory_data":   "Bytes",
-	"procstat_memory_locked": "Bytes",
-	"procstat_memory_rss":    "Bytes",
-	"procstat_memory_stack":  "Bytes",
-	"procstat_memory_swap":   "Bytes",
-	"procstat_memory_vms":    "Bytes",
+	"procstat_memory_data":      "Bytes",
+	"procstat_memory_locked":    "Bytes",
+	"procstat_memory_rss":       "Bytes",
+	"procstat_memory_rss_anon":  "Bytes",
+	"procstat_memory_rss_file":  "Bytes",
+	"procstat_memory_rss_shmem": "Bytes",
+	"procstat_memory_stack":     "Bytes",
+	"procstat_memory_swap":      "Bytes",
+	"procstat_memory_vms":       "Bytes",
 
 	"procstat_read_bytes":  "Bytes",
 	"procstat_write_bytes": "Bytes",
@@ -57,6 +61,8 @@ var defaultUnits = map[string]string{
 	"diskio_write_bytes":      "Bytes",
 	"diskio_read_time":        "Milliseconds",
 	"diskio_write_time":       "Milliseconds",
+	"diskio_read_latency":     "Milliseconds",
+	"diskio_write_latency":    "Milliseconds",
 
 	"swap_used":         "Bytes",
 	"swap_total":        "Bytes",
@@ -83,6 +89,15 @@ var defaultUnits = map[string]string{
 	"net_packets_sent": "Count",
 	"net_packets_recv": "Count",
 
+	"net_bytes_sent_per_sec":   "Bytes/Second",
+	"net_bytes_recv_per_sec":   "Bytes/Second",
+	"net_drop_in_per_sec":      "Count/Second",
+	"net_drop_out_per_sec":     "Count/Second",
+	"net_err_in_per_sec":       "Count/Second",
+	"net_err_out_per_sec":      "Count/Second",
+	"net_packets_sent_per_sec": "Count/Second",
+	"net_packets_recv_per_sec": "Count/Second",
+
 	"netstat_tcp_established": "Count",
 	"netstat_tcp_syn_sent":    "Count",
 	"netstat_tcp_syn_recv":    "Count",
@@ -109,3 +124,23 @@ var defaultUnits = map[string]string{
 	"processes_sleeping":      "Count",
 	"processes_dead":          "Count",
 }
+
+// default units of the measurements with an underscore in their name, which can't follow the "Prefix_Metric" format
+var defaultMeasurementUnits = map[string]map[string]string{
+	"procstat_lookup": {
+		"restart_count": "Count",
+	},
+	"top_processes": {
+		"cpu_usage":          "Percent",
+		"memory_rss":         "Bytes",
+		"memory_utilization": "Percent",
+		"process_count":      "Count",
+	},
+	"windows_gpu": {
+		"utilization":            "Percent",
+		"engine_utilization":     "Percent",
+		"memory_dedicated_usage": "Bytes",
+		"memory_shared_usage":    "Bytes",
+		"memory_total_committed": "Bytes",
+	},
+}
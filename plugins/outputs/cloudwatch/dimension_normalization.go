@@ -0,0 +1,170 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package cloudwatch
+
+import (
+	"fmt"
+	"hash/fnv"
+	"log"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/influxdata/telegraf"
+)
+
+const (
+	caseLower = "lower"
+	caseUpper = "upper"
+	caseNone  = "none"
+
+	defaultDimensionReplacement = "_"
+	// the limits of CloudWatch on the dimension names and values
+	maxDimensionNameLength  = 255
+	maxDimensionValueLength = 1024
+	// the shortest max_length that leaves room for the hash suffix of the overflowing values
+	minDimensionMaxLength = 16
+	// the tags of the agent, like aws:StorageResolution, are removed before the dimensions are built
+	internalTagPrefix = "aws:"
+)
+
+// DimensionNormalizationConfig is the policy enforced on the dimensions of every metric, so the sources that report
+// the same dimension with a different case or characters publish to the same series.
+type DimensionNormalizationConfig struct {
+	// lower, upper or none
+	KeyCase   string `toml:"key_case"`
+	ValueCase string `toml:"value_case"`
+	// the characters kept in the names and values as the content of a regexp character class, e.g. "a-zA-Z0-9_.-",
+	// the other characters are replaced. All the characters are kept when empty.
+	AllowedCharacters string `toml:"allowed_characters"`
+	Replacement       string `toml:"replacement"`
+	// the names and values longer than max_length are truncated and suffixed with a hash of the whole value, so the
+	// values that only differ after max_length stay distinct
+	MaxLength int `toml:"max_length"`
+}
+
+type DimensionNormalization struct {
+	keyCase      string
+	valueCase    string
+	invalidChars *regexp.Regexp
+	replacement  string
+	maxKeyLength int
+	maxLength    int
+}
+
+// NewDimensionNormalization returns nil when no policy is configured.
+func NewDimensionNormalization(config *DimensionNormalizationConfig) (*DimensionNormalization, error) {
+	if config == nil {
+		return nil, nil
+	}
+	n := &DimensionNormalization{
+		keyCase:      config.KeyCase,
+		valueCase:    config.ValueCase,
+		replacement:  config.Replacement,
+		maxKeyLength: maxDimensionNameLength,
+		maxLength:    maxDimensionValueLength,
+	}
+	for _, c := range []string{n.keyCase, n.valueCase} {
+		switch c {
+		case "", caseNone, caseLower, caseUpper:
+		default:
+			return nil, fmt.Errorf("invalid dimension_normalization case %q, lower, upper or none is expected", c)
+		}
+	}
+	if config.AllowedCharacters != "" {
+		var err error
+		if n.invalidChars, err = regexp.Compile("[^" + config.AllowedCharacters + "]"); err != nil {
+			return nil, fmt.Errorf("invalid dimension_normalization allowed_characters %q: %v", config.AllowedCharacters, err)
+		}
+		if n.replacement == "" {
+			n.replacement = defaultDimensionReplacement
+		}
+	}
+	if config.MaxLength != 0 {
+		if config.MaxLength < minDimensionMaxLength || config.MaxLength > maxDimensionValueLength {
+			return nil, fmt.Errorf("invalid dimension_normalization max_length %d, it must be between %d and %d",
+				config.MaxLength, minDimensionMaxLength, maxDimensionValueLength)
+		}
+		n.maxLength = config.MaxLength
+		if n.maxLength < n.maxKeyLength {
+			n.maxKeyLength = n.maxLength
+		}
+	}
+	return n, nil
+}
+
+// Apply normalizes the tags of the metric. When two tags normalize to the same name, the tag already named like the
+// normalized name is kept, or else the first one in alphabetical order.
+func (n *DimensionNormalization) Apply(m telegraf.Metric) {
+	if n == nil {
+		return
+	}
+	tags := m.Tags()
+	keys := make([]string, 0, len(tags))
+	for k := range tags {
+		if !strings.HasPrefix(k, internalTagPrefix) {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	// the original name of each normalized name
+	originals := make(map[string]string, len(keys))
+	for _, k := range keys {
+		key := n.Key(k)
+		if original, ok := originals[key]; ok {
+			if original == key || k != key {
+				log.Printf("D! Dropping dimension %s of metric %s, it normalizes to %s like %s", k, m.Name(), key, original)
+				continue
+			}
+			log.Printf("D! Dropping dimension %s of metric %s, it normalizes to %s like %s", original, m.Name(), key, k)
+		}
+		originals[key] = k
+	}
+	for _, k := range keys {
+		m.RemoveTag(k)
+	}
+	for key, k := range originals {
+		m.AddTag(key, n.normalize(tags[k], n.valueCase, n.maxLength))
+	}
+}
+
+// Key returns the normalized name of a dimension, e.g. of the rollup dimensions.
+func (n *DimensionNormalization) Key(key string) string {
+	if n == nil || strings.HasPrefix(key, internalTagPrefix) {
+		return key
+	}
+	return n.normalize(key, n.keyCase, n.maxKeyLength)
+}
+
+func (n *DimensionNormalization) normalize(s string, c string, maxLength int) string {
+	switch c {
+	case caseLower:
+		s = strings.ToLower(s)
+	case caseUpper:
+		s = strings.ToUpper(s)
+	}
+	if n.invalidChars != nil {
+		s = n.invalidChars.ReplaceAllString(s, n.replacement)
+	}
+	if len(s) > maxLength {
+		h := fnv.New32a()
+		h.Write([]byte(s))
+		suffix := fmt.Sprintf("-%08x", h.Sum32())
+		s = truncate(s, maxLength-len(suffix)) + suffix
+	}
+	return s
+}
+
+// truncate cuts the string at a rune boundary.
+func truncate(s string, length int) string {
+	for length > 0 && length < len(s) && !isRuneStart(s[length]) {
+		length--
+	}
+	return s[:length]
+}
+
+func isRuneStart(b byte) bool {
+	return b&0xC0 != 0x80
+}
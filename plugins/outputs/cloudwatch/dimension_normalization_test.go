@@ -0,0 +1,109 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package cloudwatch
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/influxdata/telegraf/metric"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewDimensionNormalization_Invalid(t *testing.T) {
+	_, err := NewDimensionNormalization(&DimensionNormalizationConfig{KeyCase: "title"})
+	assert.Error(t, err)
+	_, err = NewDimensionNormalization(&DimensionNormalizationConfig{AllowedCharacters: "a-"})
+	assert.NoError(t, err)
+	_, err = NewDimensionNormalization(&DimensionNormalizationConfig{AllowedCharacters: "z-a"})
+	assert.Error(t, err)
+	_, err = NewDimensionNormalization(&DimensionNormalizationConfig{MaxLength: 8})
+	assert.Error(t, err)
+	n, err := NewDimensionNormalization(nil)
+	assert.NoError(t, err)
+	assert.Nil(t, n)
+}
+
+func TestDimensionNormalization_Apply(t *testing.T) {
+	n, err := NewDimensionNormalization(&DimensionNormalizationConfig{
+		KeyCase:           caseLower,
+		ValueCase:         caseLower,
+		AllowedCharacters: "a-z0-9_.-",
+	})
+	assert.NoError(t, err)
+
+	m, _ := metric.New(metricName, map[string]string{
+		"Env":                "Prod",
+		"env":                "prod-eu",
+		"Service Name":       "Checkout API",
+		"InstanceId":         "i-0123",
+		highResolutionTagKey: "true",
+	}, map[string]interface{}{"value": 1}, time.Now())
+	n.Apply(m)
+	assert.Equal(t, map[string]string{
+		"env":                "prod-eu",
+		"service_name":       "checkout_api",
+		"instanceid":         "i-0123",
+		highResolutionTagKey: "true",
+	}, m.Tags())
+
+	// the first one in alphabetical order is kept when no tag has the normalized name
+	m, _ = metric.New(metricName, map[string]string{"ENV": "a", "Env": "b"}, map[string]interface{}{"value": 1}, time.Now())
+	n.Apply(m)
+	assert.Equal(t, map[string]string{"env": "a"}, m.Tags())
+
+	var nilNormalization *DimensionNormalization
+	m, _ = metric.New(metricName, map[string]string{"Env": "Prod"}, map[string]interface{}{"value": 1}, time.Now())
+	nilNormalization.Apply(m)
+	assert.Equal(t, map[string]string{"Env": "Prod"}, m.Tags())
+	assert.Equal(t, "Env", nilNormalization.Key("Env"))
+}
+
+func TestDimensionNormalization_MaxLength(t *testing.T) {
+	n, err := NewDimensionNormalization(&DimensionNormalizationConfig{MaxLength: 32})
+	assert.NoError(t, err)
+
+	long := strings.Repeat("a", 40)
+	m, _ := metric.New(metricName, map[string]string{
+		"path":  "/var/log/" + long + "/1",
+		"path2": "/var/log/" + long + "/2",
+		"rune":  strings.Repeat("b", 22) + "é" + strings.Repeat("c", 10),
+	}, map[string]interface{}{"value": 1}, time.Now())
+	n.Apply(m)
+	tags := m.Tags()
+	assert.Len(t, tags["path"], 32)
+	assert.Len(t, tags["path2"], 32)
+	assert.True(t, strings.HasPrefix(tags["path"], "/var/log/aaaa"))
+	// the values only differing after max_length stay distinct
+	assert.NotEqual(t, tags["path"], tags["path2"])
+	// the same value is always hashed the same
+	assert.Equal(t, n.normalize("/var/log/"+long+"/1", caseNone, 32), tags["path"])
+	// cut at a rune boundary, before the é that would be split
+	assert.Equal(t, strings.Repeat("b", 22)+"-", tags["rune"][:23])
+	assert.Len(t, tags["rune"], 31)
+
+	assert.Equal(t, strings.Repeat("k", 23), n.Key(strings.Repeat("k", 40))[:23])
+	assert.Len(t, n.Key(strings.Repeat("k", 40)), 32)
+}
+
+func TestDimensionNormalization_Rollup(t *testing.T) {
+	c := &CloudWatch{
+		RollupDimensions:       [][]string{{"InstanceId"}, {"InstanceId", "InstanceType"}},
+		DimensionNormalization: &DimensionNormalizationConfig{KeyCase: caseLower},
+	}
+	var err error
+	c.dimensionNormalization, err = NewDimensionNormalization(c.DimensionNormalization)
+	assert.NoError(t, err)
+	dimensions := BuildDimensions(map[string]string{"instanceid": "i-0123", "instancetype": "m5.large", "host": "h"})
+	for _, rollup := range c.RollupDimensions {
+		for i, key := range rollup {
+			rollup[i] = c.dimensionNormalization.Key(key)
+		}
+	}
+	rollups := c.ProcessRollup(dimensions)
+	assert.Len(t, rollups, 3)
+	assert.Len(t, rollups[1], 1)
+	assert.Equal(t, "instanceid", *rollups[1][0].Name)
+}
@@ -0,0 +1,67 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package cloudwatch
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/aws/amazon-cloudwatch-agent/internal"
+	"github.com/influxdata/telegraf"
+)
+
+// DownsamplingConfig collapses the metrics older than OlderThan into aggregates of Interval when they reach the
+// output, e.g. when the backlog buffered during an outage is drained.
+type DownsamplingConfig struct {
+	OlderThan internal.Duration `toml:"older_than"`
+	Interval  internal.Duration `toml:"interval"`
+}
+
+type Downsampling struct {
+	// sorted by OlderThan in descending order, so the oldest metrics match the coarsest policy first
+	policies []DownsamplingConfig
+}
+
+func NewDownsampling(configs []DownsamplingConfig) (*Downsampling, error) {
+	policies := make([]DownsamplingConfig, 0, len(configs))
+	for _, config := range configs {
+		if config.OlderThan.Duration <= 0 {
+			return nil, fmt.Errorf("invalid downsampling older_than %v, it must be positive", config.OlderThan.Duration)
+		}
+		if config.Interval.Duration < time.Second {
+			return nil, fmt.Errorf("invalid downsampling interval %v, it must be at least 1s", config.Interval.Duration)
+		}
+		policies = append(policies, config)
+	}
+	sort.SliceStable(policies, func(i, j int) bool {
+		return policies[i].OlderThan.Duration > policies[j].OlderThan.Duration
+	})
+	return &Downsampling{policies: policies}, nil
+}
+
+// Apply sets the aggregation interval of the metric to the interval of the policy matching its age.
+// The metrics already aggregated with an interval coarser than the policy are left untouched.
+func (d *Downsampling) Apply(m telegraf.Metric, now time.Time) {
+	if d == nil {
+		return
+	}
+	age := now.Sub(m.Time())
+	for _, policy := range d.policies {
+		if age < policy.OlderThan.Duration {
+			continue
+		}
+		if aggregationInterval, ok := m.GetTag(aggregationIntervalTagKey); ok {
+			if current, err := time.ParseDuration(aggregationInterval); err == nil && current >= policy.Interval.Duration {
+				return
+			}
+		}
+		// downsampled aggregates are no longer high resolution unless the interval is below one minute
+		if policy.Interval.Duration >= time.Minute {
+			m.RemoveTag(highResolutionTagKey)
+		}
+		m.AddTag(aggregationIntervalTagKey, policy.Interval.Duration.String())
+		return
+	}
+}
@@ -0,0 +1,94 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package cloudwatch
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aws/amazon-cloudwatch-agent/internal"
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/metric"
+	"github.com/stretchr/testify/assert"
+)
+
+func newDownsamplingConfig(olderThan, interval time.Duration) DownsamplingConfig {
+	return DownsamplingConfig{
+		OlderThan: internal.Duration{Duration: olderThan},
+		Interval:  internal.Duration{Duration: interval},
+	}
+}
+
+func TestNewDownsampling_Invalid(t *testing.T) {
+	_, err := NewDownsampling([]DownsamplingConfig{newDownsamplingConfig(0, time.Minute)})
+	assert.Error(t, err)
+	_, err = NewDownsampling([]DownsamplingConfig{newDownsamplingConfig(time.Hour, time.Millisecond)})
+	assert.Error(t, err)
+}
+
+func TestDownsampling_Apply(t *testing.T) {
+	d, err := NewDownsampling([]DownsamplingConfig{
+		newDownsamplingConfig(time.Hour, time.Minute),
+		newDownsamplingConfig(24*time.Hour, 5*time.Minute),
+	})
+	assert.NoError(t, err)
+	now := time.Now()
+
+	recent, _ := metric.New(metricName, map[string]string{"d1key": "d1value"}, map[string]interface{}{"value": 1}, now.Add(-time.Minute))
+	d.Apply(recent, now)
+	assert.False(t, recent.HasTag(aggregationIntervalTagKey))
+
+	old, _ := metric.New(metricName, map[string]string{highResolutionTagKey: "true"}, map[string]interface{}{"value": 1}, now.Add(-2*time.Hour))
+	d.Apply(old, now)
+	interval, _ := old.GetTag(aggregationIntervalTagKey)
+	assert.Equal(t, "1m0s", interval)
+	assert.False(t, old.HasTag(highResolutionTagKey))
+
+	older, _ := metric.New(metricName, map[string]string{aggregationIntervalTagKey: "10s"}, map[string]interface{}{"value": 1}, now.Add(-48*time.Hour))
+	d.Apply(older, now)
+	interval, _ = older.GetTag(aggregationIntervalTagKey)
+	assert.Equal(t, "5m0s", interval)
+
+	// already coarser than the policy
+	coarse, _ := metric.New(metricName, map[string]string{aggregationIntervalTagKey: "10m"}, map[string]interface{}{"value": 1}, now.Add(-2*time.Hour))
+	d.Apply(coarse, now)
+	interval, _ = coarse.GetTag(aggregationIntervalTagKey)
+	assert.Equal(t, "10m", interval)
+
+	// no policy configured
+	var nilDownsampling *Downsampling
+	nilDownsampling.Apply(recent, now)
+	assert.False(t, recent.HasTag(aggregationIntervalTagKey))
+}
+
+func TestDryRun_Downsampling(t *testing.T) {
+	c := &CloudWatch{
+		Namespace:           "CWAgent",
+		DownsamplingConfigs: []DownsamplingConfig{newDownsamplingConfig(time.Hour, time.Minute)},
+	}
+	tags := map[string]string{"d1key": "d1value"}
+	start := time.Now().Add(-2 * time.Hour).Truncate(time.Minute)
+	var metrics []telegraf.Metric
+	for i := 0; i < 12; i++ {
+		// 10s samples of two minutes
+		m, _ := metric.New("cpu", tags, map[string]interface{}{"usage_idle": float64(i)}, start.Add(time.Duration(i)*10*time.Second))
+		metrics = append(metrics, m)
+	}
+	recent, _ := metric.New("cpu", tags, map[string]interface{}{"usage_idle": float64(1)}, time.Now())
+	metrics = append(metrics, recent)
+
+	datums, err := c.DryRun(metrics)
+	assert.NoError(t, err)
+	assert.Len(t, datums, 3)
+	var sampleCount float64
+	for _, datum := range datums {
+		if datum.StatisticValues != nil {
+			sampleCount += *datum.StatisticValues.SampleCount
+			assert.True(t, datum.Timestamp.Before(start.Add(2*time.Minute)))
+		} else {
+			assert.Equal(t, float64(1), *datum.Value)
+		}
+	}
+	assert.Equal(t, float64(12), sampleCount)
+}
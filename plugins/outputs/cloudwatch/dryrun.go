@@ -0,0 +1,84 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package cloudwatch
+
+import (
+	"time"
+
+	"github.com/aws/amazon-cloudwatch-agent/metric/distribution"
+	"github.com/aws/aws-sdk-go/service/cloudwatch"
+	"github.com/influxdata/telegraf"
+)
+
+// DryRun returns the metric datums the plugin would publish for the metrics, after the aggregation of the metrics
+// tagged with aws:AggregationInterval or downsampled, the metric decorations and renames, the rollup and the drop_original_metrics.
+// The datums of the rollup dimensions are merged like the plugin merges them in a flush window.
+// The metrics are aggregated right away instead of waiting for the aggregation interval, and nothing is sent
+// to CloudWatch.
+func (c *CloudWatch) DryRun(metrics []telegraf.Metric) ([]*cloudwatch.MetricDatum, error) {
+	var err error
+	if c.metricDecorations, err = NewMetricDecorations(c.MetricConfigs); err != nil {
+		return nil, err
+	}
+	if c.metricRenames, err = NewMetricRenames(c.MetricRenameConfigs); err != nil {
+		return nil, err
+	}
+	if c.downsampling, err = NewDownsampling(c.DownsamplingConfigs); err != nil {
+		return nil, err
+	}
+	c.RollupDimensions = GetUniqueRollupList(c.RollupDimensions)
+	c.droppingOriginMetrics = GetDroppingDimensionMap(c.DropOriginConfigs)
+	if err = validateDistributionType(c.DistributionType); err != nil {
+		return nil, err
+	}
+	if err = distribution.SetNegativeValuePolicy(c.NegativeValues); err != nil {
+		return nil, err
+	}
+	if err = distribution.SetUnitConflictPolicy(c.UnitConflicts); err != nil {
+		return nil, err
+	}
+	if c.MaxValuesPerDatum == 0 {
+		c.MaxValuesPerDatum = defaultMaxValuesPerDatum
+	}
+	setNewDistributionFunc(c.MaxValuesPerDatum, c.DistributionType, c.TDigestCompression, c.SEH1Epsilon)
+
+	// there can't be more aggregated metrics than the input metrics
+	metricChan := make(chan telegraf.Metric, len(metrics))
+	durationMap := make(map[time.Duration]*durationAggregator)
+	now := time.Now()
+	for _, m := range metrics {
+		c.downsampling.Apply(m, now)
+		aggDurationMapKey, ok := aggregationDuration(m)
+		if !ok {
+			metricChan <- m
+			continue
+		}
+		var durationAgg *durationAggregator
+		if durationAgg, ok = durationMap[aggDurationMapKey]; !ok {
+			durationAgg = &durationAggregator{
+				aggregationDuration: aggDurationMapKey,
+				metricChan:          metricChan,
+				metricMap:           make(map[string]telegraf.Metric),
+			}
+			durationMap[aggDurationMapKey] = durationAgg
+		}
+		durationAgg.aggregate(m)
+	}
+	for _, durationAgg := range durationMap {
+		durationAgg.flush()
+	}
+	close(metricChan)
+
+	var datums []*cloudwatch.MetricDatum
+	rollup := NewRollup(c.MaxValuesPerDatum, c.SEH1Epsilon)
+	for m := range metricChan {
+		for _, namespaceDatums := range c.buildMetricDatums(m, c.Namespace, rollup) {
+			datums = append(datums, namespaceDatums...)
+		}
+	}
+	for _, rollupDatums := range rollup.flush() {
+		datums = append(datums, rollupDatums...)
+	}
+	return datums, nil
+}
@@ -0,0 +1,50 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package cloudwatch
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/metric"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDryRun(t *testing.T) {
+	c := &CloudWatch{
+		Namespace:        "namespace",
+		RollupDimensions: [][]string{{}},
+		MetricConfigs: []MetricDecorationConfig{
+			{Category: "mem", Metric: "used_percent", Rename: "mem_used", Unit: "Percent"},
+		},
+	}
+	now := time.Now().Truncate(time.Minute)
+	var metrics []telegraf.Metric
+	for i, v := range []float64{1, 2, 3} {
+		m, _ := metric.New("cpu", map[string]string{"host": "h", aggregationIntervalTagKey: "30s"}, map[string]interface{}{"usage_user": v}, now.Add(time.Duration(i)*time.Millisecond))
+		metrics = append(metrics, m)
+	}
+	m, _ := metric.New("mem", map[string]string{"host": "h"}, map[string]interface{}{"used_percent": 50.0}, now)
+	metrics = append(metrics, m)
+
+	datums, err := c.DryRun(metrics)
+	assert.NoError(t, err)
+	// one original and one rollup datum for each metric
+	assert.Len(t, datums, 4)
+	for _, datum := range datums {
+		switch aws.StringValue(datum.MetricName) {
+		case "cpu_usage_user":
+			assert.Equal(t, 3.0, aws.Float64Value(datum.StatisticValues.SampleCount))
+			assert.Equal(t, 6.0, aws.Float64Value(datum.StatisticValues.Sum))
+			assert.Equal(t, int64(1), aws.Int64Value(datum.StorageResolution))
+		case "mem_used":
+			assert.Equal(t, 50.0, aws.Float64Value(datum.Value))
+			assert.Equal(t, "Percent", aws.StringValue(datum.Unit))
+		default:
+			assert.Fail(t, "unexpected metric", aws.StringValue(datum.MetricName))
+		}
+	}
+}
@@ -0,0 +1,256 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package cloudwatch
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/cloudwatch"
+	"github.com/aws/aws-sdk-go/service/cloudwatchlogs"
+	"github.com/aws/aws-sdk-go/service/cloudwatchlogs/cloudwatchlogsiface"
+)
+
+const (
+	// the datums are published with PutMetricData, by default, or as embedded metric format documents to CloudWatch Logs
+	outputModePutMetricData = "put_metric_data"
+	OutputModeEMF           = "emf"
+
+	DefaultEMFLogGroupName = "/aws/cwagent/metrics"
+
+	// the limits of an embedded metric format document and of a PutLogEvents request
+	emfMaxMetricsPerDocument = 100
+	putLogEventsMaxEvents    = 10000
+	putLogEventsMaxSize      = 1048576
+	putLogEventsEventHeader  = 26
+	putLogEventsMaxSpan      = 24 * time.Hour
+)
+
+func validateOutputMode(mode string) error {
+	switch mode {
+	case "", outputModePutMetricData, OutputModeEMF:
+		return nil
+	}
+	return fmt.Errorf("invalid output_mode %s, supported modes are %s and %s", mode, outputModePutMetricData, OutputModeEMF)
+}
+
+// EMFWriter writes the datums as embedded metric format documents to a log stream, CloudWatch extracts their metrics
+// and Logs Insights queries the documents by any of their dimensions. The datums of the same timestamp and dimensions
+// share a document.
+type EMFWriter struct {
+	svc    cloudwatchlogsiface.CloudWatchLogsAPI
+	group  string
+	stream string
+
+	// the writes of the concurrent publishers are serialized, the log stream has a single sequence token
+	mu            sync.Mutex
+	sequenceToken *string
+}
+
+func NewEMFWriter(svc cloudwatchlogsiface.CloudWatchLogsAPI, group, stream string) *EMFWriter {
+	if group == "" {
+		group = DefaultEMFLogGroupName
+	}
+	return &EMFWriter{svc: svc, group: group, stream: stream}
+}
+
+// Write writes the documents of the datums of the namespace, in as many PutLogEvents requests as their limits require.
+func (w *EMFWriter) Write(namespace string, datums []*cloudwatch.MetricDatum) error {
+	events, err := emfEvents(namespace, datums)
+	if err != nil {
+		return err
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for len(events) > 0 {
+		n := putLogEventsBatchSize(events)
+		if err := w.putLogEvents(events[:n]); err != nil {
+			return err
+		}
+		events = events[n:]
+	}
+	return nil
+}
+
+// putLogEventsBatchSize returns how many of the sorted events fit in a request.
+func putLogEventsBatchSize(events []*cloudwatchlogs.InputLogEvent) int {
+	size := 0
+	first := aws.Int64Value(events[0].Timestamp)
+	for i, e := range events {
+		size += len(aws.StringValue(e.Message)) + putLogEventsEventHeader
+		span := time.Duration(aws.Int64Value(e.Timestamp)-first) * time.Millisecond
+		if i > 0 && (i == putLogEventsMaxEvents || size > putLogEventsMaxSize || span >= putLogEventsMaxSpan) {
+			return i
+		}
+	}
+	return len(events)
+}
+
+// putLogEvents sends the events, the log group and stream are created when they don't exist yet.
+func (w *EMFWriter) putLogEvents(events []*cloudwatchlogs.InputLogEvent) error {
+	var err error
+	for attempt := 0; attempt < 3; attempt++ {
+		var output *cloudwatchlogs.PutLogEventsOutput
+		output, err = w.svc.PutLogEvents(&cloudwatchlogs.PutLogEventsInput{
+			LogEvents:     events,
+			LogGroupName:  aws.String(w.group),
+			LogStreamName: aws.String(w.stream),
+			SequenceToken: w.sequenceToken,
+		})
+		switch e := err.(type) {
+		case nil:
+			w.sequenceToken = output.NextSequenceToken
+			return nil
+		case *cloudwatchlogs.InvalidSequenceTokenException:
+			w.sequenceToken = e.ExpectedSequenceToken
+		case *cloudwatchlogs.DataAlreadyAcceptedException:
+			w.sequenceToken = e.ExpectedSequenceToken
+			return nil
+		case *cloudwatchlogs.ResourceNotFoundException:
+			if err := w.createLogGroupAndStream(); err != nil {
+				return err
+			}
+		default:
+			return err
+		}
+	}
+	return err
+}
+
+func (w *EMFWriter) createLogGroupAndStream() error {
+	_, err := w.svc.CreateLogStream(&cloudwatchlogs.CreateLogStreamInput{
+		LogGroupName:  aws.String(w.group),
+		LogStreamName: aws.String(w.stream),
+	})
+	if awsErr, ok := err.(awserr.Error); ok && awsErr.Code() == cloudwatchlogs.ErrCodeResourceNotFoundException {
+		_, err = w.svc.CreateLogGroup(&cloudwatchlogs.CreateLogGroupInput{LogGroupName: aws.String(w.group)})
+		if awsErr, ok := err.(awserr.Error); err == nil || ok && awsErr.Code() == cloudwatchlogs.ErrCodeResourceAlreadyExistsException {
+			_, err = w.svc.CreateLogStream(&cloudwatchlogs.CreateLogStreamInput{
+				LogGroupName:  aws.String(w.group),
+				LogStreamName: aws.String(w.stream),
+			})
+		}
+	}
+	if awsErr, ok := err.(awserr.Error); ok && awsErr.Code() == cloudwatchlogs.ErrCodeResourceAlreadyExistsException {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("unable to create the log stream %s/%s: %v", w.group, w.stream, err)
+	}
+	return nil
+}
+
+// emfDocument is an embedded metric format document, the metrics of a timestamp and dimensions.
+type emfDocument struct {
+	timestamp  time.Time
+	dimensions []*cloudwatch.Dimension
+	metrics    []map[string]interface{}
+	values     map[string]interface{}
+}
+
+// emfEvents returns the log events of the documents of the datums, sorted by timestamp.
+func emfEvents(namespace string, datums []*cloudwatch.MetricDatum) ([]*cloudwatchlogs.InputLogEvent, error) {
+	// the documents by timestamp and dimensions, a name appears once by document
+	documents := map[string][]*emfDocument{}
+	var keys []string
+	for _, datum := range datums {
+		key := fmt.Sprintf("%d|%s", aws.TimeValue(datum.Timestamp).UnixNano(), dimensionsKey(datum.Dimensions))
+		name := aws.StringValue(datum.MetricName)
+		var document *emfDocument
+		for _, d := range documents[key] {
+			if _, ok := d.values[name]; !ok && len(d.metrics) < emfMaxMetricsPerDocument {
+				document = d
+				break
+			}
+		}
+		if document == nil {
+			if len(documents[key]) == 0 {
+				keys = append(keys, key)
+			}
+			document = &emfDocument{
+				timestamp:  aws.TimeValue(datum.Timestamp),
+				dimensions: datum.Dimensions,
+				values:     map[string]interface{}{},
+			}
+			documents[key] = append(documents[key], document)
+		}
+		definition := map[string]interface{}{"Name": name}
+		if datum.Unit != nil {
+			definition["Unit"] = aws.StringValue(datum.Unit)
+		}
+		if aws.Int64Value(datum.StorageResolution) == 1 {
+			definition["StorageResolution"] = 1
+		}
+		document.metrics = append(document.metrics, definition)
+		document.values[name] = emfValue(datum)
+	}
+
+	events := make([]*cloudwatchlogs.InputLogEvent, 0, len(keys))
+	for _, key := range keys {
+		for _, d := range documents[key] {
+			message, err := d.marshal(namespace)
+			if err != nil {
+				return nil, err
+			}
+			events = append(events, &cloudwatchlogs.InputLogEvent{
+				Message:   aws.String(message),
+				Timestamp: aws.Int64(d.timestamp.UnixNano() / int64(time.Millisecond)),
+			})
+		}
+	}
+	sort.SliceStable(events, func(i, j int) bool {
+		return aws.Int64Value(events[i].Timestamp) < aws.Int64Value(events[j].Timestamp)
+	})
+	return events, nil
+}
+
+// emfValue returns the value of the datum, or the values, counts and statistics of a distribution.
+func emfValue(datum *cloudwatch.MetricDatum) interface{} {
+	if datum.StatisticValues == nil {
+		return aws.Float64Value(datum.Value)
+	}
+	value := map[string]interface{}{
+		"Max":   aws.Float64Value(datum.StatisticValues.Maximum),
+		"Min":   aws.Float64Value(datum.StatisticValues.Minimum),
+		"Count": aws.Float64Value(datum.StatisticValues.SampleCount),
+		"Sum":   aws.Float64Value(datum.StatisticValues.Sum),
+	}
+	if len(datum.Values) > 0 {
+		value["Values"] = aws.Float64ValueSlice(datum.Values)
+		value["Counts"] = aws.Float64ValueSlice(datum.Counts)
+	}
+	return value
+}
+
+func (d *emfDocument) marshal(namespace string) (string, error) {
+	names := make([]string, 0, len(d.dimensions))
+	content := make(map[string]interface{}, len(d.dimensions)+len(d.values)+1)
+	for _, dimension := range d.dimensions {
+		names = append(names, aws.StringValue(dimension.Name))
+		content[aws.StringValue(dimension.Name)] = aws.StringValue(dimension.Value)
+	}
+	for name, value := range d.values {
+		content[name] = value
+	}
+	content["_aws"] = map[string]interface{}{
+		"Timestamp": d.timestamp.UnixNano() / int64(time.Millisecond),
+		"CloudWatchMetrics": []interface{}{
+			map[string]interface{}{
+				"Namespace":  namespace,
+				"Dimensions": [][]string{names},
+				"Metrics":    d.metrics,
+			},
+		},
+	}
+	b, err := json.Marshal(content)
+	if err != nil {
+		return "", fmt.Errorf("unable to marshal the embedded metric format document: %v", err)
+	}
+	return string(b), nil
+}
@@ -0,0 +1,135 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package cloudwatch
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/cloudwatch"
+	"github.com/aws/aws-sdk-go/service/cloudwatchlogs"
+	"github.com/aws/aws-sdk-go/service/cloudwatchlogs/cloudwatchlogsiface"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockLogsClient struct {
+	cloudwatchlogsiface.CloudWatchLogsAPI
+
+	putErrs []error
+	inputs  []*cloudwatchlogs.PutLogEventsInput
+	created []string
+}
+
+func (m *mockLogsClient) PutLogEvents(input *cloudwatchlogs.PutLogEventsInput) (*cloudwatchlogs.PutLogEventsOutput, error) {
+	m.inputs = append(m.inputs, input)
+	if len(m.putErrs) > 0 {
+		err := m.putErrs[0]
+		m.putErrs = m.putErrs[1:]
+		return nil, err
+	}
+	return &cloudwatchlogs.PutLogEventsOutput{NextSequenceToken: aws.String("next")}, nil
+}
+
+func (m *mockLogsClient) CreateLogGroup(input *cloudwatchlogs.CreateLogGroupInput) (*cloudwatchlogs.CreateLogGroupOutput, error) {
+	m.created = append(m.created, "group "+aws.StringValue(input.LogGroupName))
+	return &cloudwatchlogs.CreateLogGroupOutput{}, nil
+}
+
+func (m *mockLogsClient) CreateLogStream(input *cloudwatchlogs.CreateLogStreamInput) (*cloudwatchlogs.CreateLogStreamOutput, error) {
+	m.created = append(m.created, "stream "+aws.StringValue(input.LogStreamName))
+	if len(m.created) == 1 {
+		return nil, awserr.New(cloudwatchlogs.ErrCodeResourceNotFoundException, "the log group doesn't exist", nil)
+	}
+	return &cloudwatchlogs.CreateLogStreamOutput{}, nil
+}
+
+func TestEMFEvents(t *testing.T) {
+	now := time.Unix(1578326400, 0)
+	dimensions := []*cloudwatch.Dimension{{Name: aws.String("InstanceId"), Value: aws.String("i-0123456789abcdef0")}}
+	datums := []*cloudwatch.MetricDatum{
+		{MetricName: aws.String("mem_used_percent"), Dimensions: dimensions, Timestamp: aws.Time(now.Add(time.Second)), Value: aws.Float64(42), Unit: aws.String("Percent")},
+		{MetricName: aws.String("cpu_usage_idle"), Dimensions: dimensions, Timestamp: aws.Time(now), Value: aws.Float64(90), StorageResolution: aws.Int64(1)},
+		{MetricName: aws.String("cpu_usage_user"), Dimensions: dimensions, Timestamp: aws.Time(now), Value: aws.Float64(5)},
+		// the second datum of a distribution is in another document
+		{MetricName: aws.String("cpu_usage_idle"), Dimensions: dimensions, Timestamp: aws.Time(now), Values: aws.Float64Slice([]float64{1, 2}), Counts: aws.Float64Slice([]float64{3, 1}),
+			StatisticValues: &cloudwatch.StatisticSet{Maximum: aws.Float64(2), Minimum: aws.Float64(1), SampleCount: aws.Float64(4), Sum: aws.Float64(5)}},
+	}
+	events, err := emfEvents("CWAgent", datums)
+	require.NoError(t, err)
+	require.Len(t, events, 3)
+	// the events are sorted by timestamp
+	assert.Equal(t, now.UnixNano()/int64(time.Millisecond), aws.Int64Value(events[0].Timestamp))
+	assert.Equal(t, now.UnixNano()/int64(time.Millisecond), aws.Int64Value(events[1].Timestamp))
+	assert.Equal(t, now.Add(time.Second).UnixNano()/int64(time.Millisecond), aws.Int64Value(events[2].Timestamp))
+
+	var document map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(aws.StringValue(events[0].Message)), &document))
+	assert.Equal(t, map[string]interface{}{
+		"InstanceId":     "i-0123456789abcdef0",
+		"cpu_usage_idle": 90.0,
+		"cpu_usage_user": 5.0,
+		"_aws": map[string]interface{}{
+			"Timestamp": 1578326400000.0,
+			"CloudWatchMetrics": []interface{}{map[string]interface{}{
+				"Namespace":  "CWAgent",
+				"Dimensions": []interface{}{[]interface{}{"InstanceId"}},
+				"Metrics": []interface{}{
+					map[string]interface{}{"Name": "cpu_usage_idle", "StorageResolution": 1.0},
+					map[string]interface{}{"Name": "cpu_usage_user"},
+				},
+			}},
+		},
+	}, document)
+	require.NoError(t, json.Unmarshal([]byte(aws.StringValue(events[1].Message)), &document))
+	assert.Equal(t, map[string]interface{}{"Values": []interface{}{1.0, 2.0}, "Counts": []interface{}{3.0, 1.0}, "Max": 2.0, "Min": 1.0, "Count": 4.0, "Sum": 5.0}, document["cpu_usage_idle"])
+	assert.Contains(t, aws.StringValue(events[2].Message), `"Unit":"Percent"`)
+}
+
+func TestEMFWriter_Write(t *testing.T) {
+	svc := &mockLogsClient{putErrs: []error{
+		&cloudwatchlogs.ResourceNotFoundException{Message_: aws.String("the log stream doesn't exist")},
+		&cloudwatchlogs.InvalidSequenceTokenException{Message_: aws.String("invalid token"), ExpectedSequenceToken: aws.String("expected")},
+	}}
+	w := NewEMFWriter(svc, "", "ip-10-0-0-1")
+	datum := &cloudwatch.MetricDatum{MetricName: aws.String("mem_used_percent"), Timestamp: aws.Time(time.Now()), Value: aws.Float64(42)}
+	require.NoError(t, w.Write("CWAgent", []*cloudwatch.MetricDatum{datum}))
+
+	assert.Equal(t, []string{"stream ip-10-0-0-1", "group /aws/cwagent/metrics", "stream ip-10-0-0-1"}, svc.created)
+	require.Len(t, svc.inputs, 3)
+	assert.Nil(t, svc.inputs[1].SequenceToken)
+	assert.Equal(t, "expected", aws.StringValue(svc.inputs[2].SequenceToken))
+	assert.Equal(t, "next", aws.StringValue(w.sequenceToken))
+
+	svc.putErrs = []error{awserr.New("ThrottlingException", "rate exceeded", nil)}
+	assert.Error(t, w.Write("CWAgent", []*cloudwatch.MetricDatum{datum}))
+}
+
+func TestPutLogEventsBatchSize(t *testing.T) {
+	now := time.Now().UnixNano() / int64(time.Millisecond)
+	event := func(size int, offset time.Duration) *cloudwatchlogs.InputLogEvent {
+		return &cloudwatchlogs.InputLogEvent{Message: aws.String(strings.Repeat("x", size)), Timestamp: aws.Int64(now + int64(offset/time.Millisecond))}
+	}
+	events := []*cloudwatchlogs.InputLogEvent{event(600000, 0), event(600000, 0), event(10, 0)}
+	assert.Equal(t, 1, putLogEventsBatchSize(events))
+	assert.Equal(t, 2, putLogEventsBatchSize(events[1:]))
+	events = []*cloudwatchlogs.InputLogEvent{event(10, 0), event(10, time.Hour), event(10, 25*time.Hour)}
+	assert.Equal(t, 2, putLogEventsBatchSize(events))
+	events = make([]*cloudwatchlogs.InputLogEvent, putLogEventsMaxEvents+1)
+	for i := range events {
+		events[i] = event(10, 0)
+	}
+	assert.Equal(t, putLogEventsMaxEvents, putLogEventsBatchSize(events))
+}
+
+func TestValidateOutputMode(t *testing.T) {
+	assert.NoError(t, validateOutputMode(""))
+	assert.NoError(t, validateOutputMode("put_metric_data"))
+	assert.NoError(t, validateOutputMode("emf"))
+	assert.Error(t, validateOutputMode("logs"))
+}
@@ -39,6 +39,13 @@ func NewMetricDecorations(metricConfigs []MetricDecorationConfig) (*MetricDecora
 			return result, err
 		}
 	}
+	for measurement, units := range defaultMeasurementUnits {
+		for field, unit := range units {
+			if err := result.addDecorations(measurement, field, "", unit); err != nil {
+				return result, err
+			}
+		}
+	}
 
 	for _, metricConfig := range metricConfigs {
 		err := result.addDecorations(metricConfig.Category, metricConfig.Metric, metricConfig.Rename, metricConfig.Unit)
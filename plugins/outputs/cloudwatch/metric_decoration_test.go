@@ -126,3 +126,13 @@ func TestProcstatDefaultUnit(t *testing.T) {
 	assert.Equal(t, "Bytes", m.getUnit("procstat", "rlimit_memory_vms_hard"))
 	assert.Equal(t, "Bytes", m.getUnit("procstat", "rlimit_memory_vms_soft"))
 }
+
+func TestUnderscoredMeasurementDefaultUnit(t *testing.T) {
+	m, err := NewMetricDecorations(nil)
+	assert.True(t, err == nil)
+
+	assert.Equal(t, "Percent", m.getUnit("top_processes", "cpu_usage"))
+	assert.Equal(t, "Bytes", m.getUnit("top_processes", "memory_rss"))
+	assert.Equal(t, "", m.getUnit("top", "processes_cpu_usage"))
+	assert.Equal(t, "Count", m.getUnit("procstat_lookup", "restart_count"))
+}
@@ -0,0 +1,94 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package cloudwatch
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// the renames of the metric names are cached up to this many names, the cache is cleared once full
+const maxCachedRenames = 10000
+
+// MetricRenameConfig renames the metrics whose name matches a regular expression and can send them to another
+// namespace than the one of the output, e.g. to adopt a naming convention without changing the emitters.
+type MetricRenameConfig struct {
+	// the regular expression matching the metric names, after the renames of the metric decorations
+	Pattern string `toml:"pattern"`
+	// the replacement of the matches of the pattern, $1 or ${name} are replaced with the groups of the pattern, the
+	// name is kept when empty
+	Rename string `toml:"rename"`
+	// the namespace of the matching metrics, the namespace of the output or of the namespace_routing when empty
+	Namespace string `toml:"namespace"`
+}
+
+// MetricRenames returns the name and the namespace of the metrics from the first rule matching their name.
+type MetricRenames struct {
+	rules   []metricRenameRule
+	renames map[string]metricRename
+}
+
+type metricRenameRule struct {
+	pattern   *regexp.Regexp
+	rename    string
+	namespace string
+}
+
+type metricRename struct {
+	name      string
+	namespace string
+}
+
+// NewMetricRenames returns nil when no rule is configured.
+func NewMetricRenames(configs []MetricRenameConfig) (*MetricRenames, error) {
+	if len(configs) == 0 {
+		return nil, nil
+	}
+	r := &MetricRenames{renames: map[string]metricRename{}}
+	for _, config := range configs {
+		if config.Rename == "" && config.Namespace == "" {
+			return nil, fmt.Errorf("the metric_rename of %s has neither a rename nor a namespace", config.Pattern)
+		}
+		pattern, err := regexp.Compile(config.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid metric_rename pattern %s: %v", config.Pattern, err)
+		}
+		if config.Namespace != "" {
+			if err := validateNamespace(config.Namespace); err != nil {
+				return nil, fmt.Errorf("invalid metric_rename of %s: %v", config.Pattern, err)
+			}
+		}
+		r.rules = append(r.rules, metricRenameRule{pattern: pattern, rename: config.Rename, namespace: config.Namespace})
+	}
+	return r, nil
+}
+
+// Rename returns the name of the metric and its namespace, empty when the metric keeps the namespace of its point. It
+// is called by the single routine building the datums.
+func (r *MetricRenames) Rename(name string) (string, string) {
+	if r == nil {
+		return name, ""
+	}
+	if renamed, ok := r.renames[name]; ok {
+		return renamed.name, renamed.namespace
+	}
+	renamed := metricRename{name: name}
+	for _, rule := range r.rules {
+		if !rule.pattern.MatchString(name) {
+			continue
+		}
+		if rule.rename != "" {
+			if n := rule.pattern.ReplaceAllString(name, rule.rename); n != "" {
+				renamed.name = n
+			}
+		}
+		renamed.namespace = rule.namespace
+		break
+	}
+	if len(r.renames) >= maxCachedRenames {
+		r.renames = map[string]metricRename{}
+	}
+	r.renames[name] = renamed
+	return renamed.name, renamed.namespace
+}
@@ -0,0 +1,71 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package cloudwatch
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/influxdata/telegraf/metric"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewMetricRenames_Invalid(t *testing.T) {
+	_, err := NewMetricRenames([]MetricRenameConfig{{Pattern: "^disk_"}})
+	assert.Error(t, err)
+	_, err = NewMetricRenames([]MetricRenameConfig{{Pattern: "disk_(", Rename: "Disk"}})
+	assert.Error(t, err)
+	_, err = NewMetricRenames([]MetricRenameConfig{{Pattern: "^disk_", Namespace: "AWS/EBS"}})
+	assert.Error(t, err)
+	r, err := NewMetricRenames(nil)
+	assert.NoError(t, err)
+	assert.Nil(t, r)
+	name, namespace := r.Rename("disk_used")
+	assert.Equal(t, "disk_used", name)
+	assert.Equal(t, "", namespace)
+}
+
+func TestMetricRenames_Rename(t *testing.T) {
+	r, err := NewMetricRenames([]MetricRenameConfig{
+		{Pattern: "^disk_(.+)_percent$", Rename: "Disk.${1}Percent", Namespace: "Storage"},
+		{Pattern: "^disk_", Rename: "Disk."},
+		{Pattern: "^mem_", Namespace: "Memory"},
+		{Pattern: "^cpu_", Rename: "Processor."},
+	})
+	require.NoError(t, err)
+
+	for name, expected := range map[string][2]string{
+		"disk_used_percent": {"Disk.usedPercent", "Storage"},
+		"disk_inodes_free":  {"Disk.inodes_free", ""},
+		"mem_used":          {"mem_used", "Memory"},
+		// the first matching rule applies
+		"cpu_usage_idle": {"Processor.usage_idle", ""},
+		"swap_used":      {"swap_used", ""},
+	} {
+		// the second rename is cached
+		for i := 0; i < 2; i++ {
+			renamed, namespace := r.Rename(name)
+			assert.Equal(t, expected[0], renamed, name)
+			assert.Equal(t, expected[1], namespace, name)
+		}
+	}
+	assert.Len(t, r.renames, 5)
+}
+
+func TestBuildMetricDatums_Renamed(t *testing.T) {
+	c := &CloudWatch{}
+	var err error
+	c.metricDecorations, err = NewMetricDecorations(nil)
+	require.NoError(t, err)
+	c.metricRenames, err = NewMetricRenames([]MetricRenameConfig{{Pattern: "^mem_(.+)$", Rename: "Memory.$1", Namespace: "Memory"}})
+	require.NoError(t, err)
+
+	m, _ := metric.New("mem", map[string]string{"host": "ip-10-0-0-1"}, map[string]interface{}{"used": 1024.0}, time.Now())
+	datums := c.buildMetricDatums(m, "CWAgent", nil)
+	assert.Empty(t, datums["CWAgent"])
+	require.Len(t, datums["Memory"], 1)
+	assert.Equal(t, "Memory.used", aws.StringValue(datums["Memory"][0].MetricName))
+}
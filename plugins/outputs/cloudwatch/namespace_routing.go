@@ -0,0 +1,117 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package cloudwatch
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/influxdata/telegraf"
+)
+
+// the placeholder of the namespace template replaced with the value of the dimension
+const namespaceValuePlaceholder = "{value}"
+
+// NamespaceRoutingConfig routes the metrics with a dimension to another namespace than the one of the output, e.g.
+// a namespace by team on a host shared by several teams.
+type NamespaceRoutingConfig struct {
+	Dimension string `toml:"dimension"`
+	// the namespace of the metrics with the dimension, {value} is replaced with the value of the dimension, e.g.
+	// "Teams/{value}"
+	Namespace string `toml:"namespace"`
+	// the namespaces by value of the dimension, the other values are routed to namespace, or to the next rule when
+	// namespace is empty
+	Namespaces map[string]string `toml:"namespaces"`
+	// removes the dimension from the routed metrics, the namespace already tells them apart
+	DropDimension bool `toml:"drop_dimension"`
+}
+
+// NamespaceRouting returns the namespace of the metrics from the first rule matching their dimensions.
+type NamespaceRouting struct {
+	rules []NamespaceRoutingConfig
+	log   telegraf.Logger
+	// the validation of the namespaces of the routed values, the metrics of the invalid ones are not routed
+	valid map[string]bool
+}
+
+// NewNamespaceRouting returns nil when no rule is configured.
+func NewNamespaceRouting(configs []NamespaceRoutingConfig, log telegraf.Logger) (*NamespaceRouting, error) {
+	if len(configs) == 0 {
+		return nil, nil
+	}
+	r := &NamespaceRouting{log: log, valid: map[string]bool{}}
+	for _, config := range configs {
+		if config.Dimension == "" {
+			return nil, fmt.Errorf("the namespace_routing dimension is required")
+		}
+		if config.Namespace == "" && len(config.Namespaces) == 0 {
+			return nil, fmt.Errorf("the namespace_routing of %s has neither a namespace nor namespaces", config.Dimension)
+		}
+		// the namespaces without a value are checked upfront, the templates once their value is known
+		for _, namespace := range config.Namespaces {
+			if err := validateNamespace(namespace); err != nil {
+				return nil, fmt.Errorf("invalid namespace_routing of %s: %v", config.Dimension, err)
+			}
+		}
+		if config.Namespace != "" && !strings.Contains(config.Namespace, namespaceValuePlaceholder) {
+			if err := validateNamespace(config.Namespace); err != nil {
+				return nil, fmt.Errorf("invalid namespace_routing of %s: %v", config.Dimension, err)
+			}
+		}
+		r.rules = append(r.rules, config)
+	}
+	return r, nil
+}
+
+// Route returns the namespace of the metric, empty when no rule matches, and removes the dimension of the matching
+// rule when it is dropped. It is called by the single routine building the datums.
+func (r *NamespaceRouting) Route(m telegraf.Metric) string {
+	if r == nil {
+		return ""
+	}
+	for _, rule := range r.rules {
+		value, ok := m.GetTag(rule.Dimension)
+		if !ok || value == "" {
+			continue
+		}
+		namespace, ok := rule.Namespaces[value]
+		if !ok {
+			namespace = strings.Replace(rule.Namespace, namespaceValuePlaceholder, value, -1)
+		}
+		if namespace == "" {
+			continue
+		}
+		if !r.isValid(namespace) {
+			return ""
+		}
+		if rule.DropDimension {
+			m.RemoveTag(rule.Dimension)
+		}
+		return namespace
+	}
+	return ""
+}
+
+func (r *NamespaceRouting) isValid(namespace string) bool {
+	valid, ok := r.valid[namespace]
+	if !ok {
+		err := validateNamespace(namespace)
+		if err != nil {
+			r.log.Warnf("The metrics are sent to the namespace of the output instead: %v", err)
+		}
+		valid = err == nil
+		r.valid[namespace] = valid
+	}
+	return valid
+}
+
+// normalizeDimensions names the dimensions of the rules like the normalized dimensions of the metrics.
+func (r *NamespaceRouting) normalizeDimensions(normalization *DimensionNormalization) {
+	if r == nil {
+		return
+	}
+	for i := range r.rules {
+		r.rules[i].Dimension = normalization.Key(r.rules[i].Dimension)
+	}
+}
@@ -0,0 +1,84 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package cloudwatch
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aws/amazon-cloudwatch-agent/internal/publisher"
+	"github.com/aws/aws-sdk-go/service/cloudwatch"
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/metric"
+	"github.com/influxdata/telegraf/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestNewNamespaceRouting_Invalid(t *testing.T) {
+	_, err := NewNamespaceRouting([]NamespaceRoutingConfig{{Namespace: "Teams/{value}"}}, testutil.Logger{})
+	assert.Error(t, err)
+	_, err = NewNamespaceRouting([]NamespaceRoutingConfig{{Dimension: "team"}}, testutil.Logger{})
+	assert.Error(t, err)
+	_, err = NewNamespaceRouting([]NamespaceRoutingConfig{{Dimension: "team", Namespace: "AWS/EC2"}}, testutil.Logger{})
+	assert.Error(t, err)
+	_, err = NewNamespaceRouting([]NamespaceRoutingConfig{{Dimension: "team", Namespaces: map[string]string{"a": "a|b"}}}, testutil.Logger{})
+	assert.Error(t, err)
+	r, err := NewNamespaceRouting(nil, testutil.Logger{})
+	assert.NoError(t, err)
+	assert.Nil(t, r)
+	assert.Equal(t, "", r.Route(newRoutedMetric(map[string]string{"team": "payments"})))
+}
+
+func newRoutedMetric(tags map[string]string) telegraf.Metric {
+	m, _ := metric.New("cpu", tags, map[string]interface{}{"usage_idle": 42}, time.Now())
+	return m
+}
+
+func TestNamespaceRouting_Route(t *testing.T) {
+	r, err := NewNamespaceRouting([]NamespaceRoutingConfig{
+		{Dimension: "team", Namespace: "Teams/{value}", Namespaces: map[string]string{"payments": "Payments"}, DropDimension: true},
+		{Dimension: "env", Namespaces: map[string]string{"prod": "Prod"}},
+	}, testutil.Logger{})
+	assert.NoError(t, err)
+
+	m := newRoutedMetric(map[string]string{"team": "search", "env": "prod"})
+	assert.Equal(t, "Teams/search", r.Route(m))
+	assert.False(t, m.HasTag("team"))
+	assert.Equal(t, "Payments", r.Route(newRoutedMetric(map[string]string{"team": "payments"})))
+	// the values without a namespace go to the next rule
+	m = newRoutedMetric(map[string]string{"env": "prod"})
+	assert.Equal(t, "Prod", r.Route(m))
+	assert.True(t, m.HasTag("env"))
+	assert.Equal(t, "", r.Route(newRoutedMetric(map[string]string{"env": "dev"})))
+	assert.Equal(t, "", r.Route(newRoutedMetric(map[string]string{"team": ""})))
+	// an invalid namespace is not routed and the dimension is kept
+	m = newRoutedMetric(map[string]string{"team": "a|b"})
+	assert.Equal(t, "", r.Route(m))
+	assert.True(t, m.HasTag("team"))
+}
+
+func TestWrite_NamespaceRouting(t *testing.T) {
+	svc := new(mockCloudWatchClient)
+	svc.On("PutMetricData", mock.Anything).Return(&cloudwatch.PutMetricDataOutput{}, nil)
+	c := newCloudWatchClient(svc)
+	c.Namespace = "CWAgent"
+	c.namespaceRouting, _ = NewNamespaceRouting([]NamespaceRoutingConfig{{Dimension: "team", Namespace: "Teams/{value}"}}, testutil.Logger{})
+	c.publisher, _ = publisher.NewPublisher(publisher.NewNonBlockingFifoQueue(10), 10, 2*time.Second, c.WriteToCloudWatch)
+
+	c.Write([]telegraf.Metric{
+		newRoutedMetric(map[string]string{"team": "search"}),
+		newRoutedMetric(map[string]string{"team": "payments"}),
+		newRoutedMetric(map[string]string{"host": "a"}),
+	})
+	time.Sleep(time.Second + 2*c.ForceFlushInterval.Duration)
+	c.Close()
+
+	namespaces := map[string]int{}
+	for _, call := range svc.Calls {
+		input := call.Arguments.Get(0).(*cloudwatch.PutMetricDataInput)
+		namespaces[*input.Namespace] += len(input.MetricData)
+	}
+	assert.Equal(t, map[string]int{"Teams/search": 1, "Teams/payments": 1, "CWAgent": 1}, namespaces)
+}
@@ -0,0 +1,210 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package cloudwatch
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cloudwatch"
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/selfstat"
+	"gopkg.in/natefinch/lumberjack.v2"
+)
+
+const (
+	// the limits of PutMetricData, a datum beyond them fails its whole request
+	maxNamespaceLength  = 255
+	maxMetricNameLength = 255
+	maxTimestampAge     = 14 * 24 * time.Hour
+	maxTimestampAhead   = 2 * time.Hour
+	reservedNamespace   = "AWS/"
+
+	reasonMetricName     = "metric_name"
+	reasonDimensionCount = "dimension_count"
+	reasonDimension      = "dimension"
+	reasonValue          = "value"
+	reasonTimestamp      = "timestamp"
+
+	// the quarantine file is rotated beyond this size in MB, only its last rotation is kept
+	quarantineFileMaxSize = 10
+	// the metric and reason pairs warned about, the next quarantined datums are only logged in debug
+	maxQuarantineWarnings = 1000
+)
+
+var (
+	namespacePattern = regexp.MustCompile(`^[0-9A-Za-z.\-_/#: ]+$`)
+	// the values of the datums are in [-2^360, 2^360]
+	maxValueMagnitude = math.Pow(2, 360)
+)
+
+// validateNamespace checks the namespace against the rules of CloudWatch, since every request of an invalid
+// namespace fails.
+func validateNamespace(namespace string) error {
+	switch {
+	case namespace == "" || len(namespace) > maxNamespaceLength:
+		return fmt.Errorf("the namespace %q must have 1 to %d characters", namespace, maxNamespaceLength)
+	case !namespacePattern.MatchString(namespace):
+		return fmt.Errorf("the namespace %q can only contain alphanumeric characters, periods, hyphens, underscores, slashes, hashes, colons and spaces", namespace)
+	case strings.HasPrefix(namespace, ":"):
+		return fmt.Errorf("the namespace %q must not start with a colon", namespace)
+	case strings.HasPrefix(namespace, reservedNamespace):
+		return fmt.Errorf("the namespace %q must not start with %s, it is reserved for the AWS services", namespace, reservedNamespace)
+	}
+	return nil
+}
+
+// QuotaGuard checks the datums against the limits of PutMetricData before they are batched, so a datum CloudWatch
+// would reject is quarantined instead of failing the other datums of its request. The quarantined datums are counted
+// by reason in the datums_quarantined stat of the cloudwatch measurement of the internal input, and written to the
+// quarantine file when configured.
+type QuotaGuard struct {
+	log telegraf.Logger
+	now func() time.Time
+
+	mu       sync.Mutex
+	file     io.WriteCloser
+	stats    map[string]selfstat.Stat
+	warnings map[string]bool
+}
+
+// quarantinedDatum is a line of the quarantine file.
+type quarantinedDatum struct {
+	Time       time.Time         `json:"timestamp"`
+	Reason     string            `json:"reason"`
+	Message    string            `json:"message"`
+	MetricName string            `json:"metric_name"`
+	Dimensions map[string]string `json:"dimensions,omitempty"`
+	DatumTime  *time.Time        `json:"datum_timestamp,omitempty"`
+}
+
+// NewQuotaGuard writes the quarantined datums to the file when it is set.
+func NewQuotaGuard(quarantineFile string, log telegraf.Logger) *QuotaGuard {
+	g := &QuotaGuard{
+		log:      log,
+		now:      time.Now,
+		stats:    map[string]selfstat.Stat{},
+		warnings: map[string]bool{},
+	}
+	if quarantineFile != "" {
+		g.file = &lumberjack.Logger{Filename: quarantineFile, MaxSize: quarantineFileMaxSize, MaxBackups: 1}
+	}
+	return g
+}
+
+// Check returns false when the datum is quarantined.
+func (g *QuotaGuard) Check(datum *cloudwatch.MetricDatum) bool {
+	if g == nil {
+		return true
+	}
+	reason, message := g.violation(datum)
+	if reason == "" {
+		return true
+	}
+	g.quarantine(datum, reason, message)
+	return false
+}
+
+// violation returns the reason and the description of the first limit the datum violates.
+func (g *QuotaGuard) violation(datum *cloudwatch.MetricDatum) (string, string) {
+	name := aws.StringValue(datum.MetricName)
+	if name == "" || len(name) > maxMetricNameLength {
+		return reasonMetricName, fmt.Sprintf("the metric name must have 1 to %d characters", maxMetricNameLength)
+	}
+	if len(datum.Dimensions) > MaxDimensions {
+		return reasonDimensionCount, fmt.Sprintf("%d dimensions, the maximum is %d", len(datum.Dimensions), MaxDimensions)
+	}
+	for _, d := range datum.Dimensions {
+		key, value := aws.StringValue(d.Name), aws.StringValue(d.Value)
+		if key == "" || len(key) > maxDimensionNameLength {
+			return reasonDimension, fmt.Sprintf("the name of dimension %q must have 1 to %d characters", key, maxDimensionNameLength)
+		}
+		if value == "" || len(value) > maxDimensionValueLength {
+			return reasonDimension, fmt.Sprintf("the value of dimension %q must have 1 to %d characters", key, maxDimensionValueLength)
+		}
+	}
+	values := append([]*float64{datum.Value}, datum.Values...)
+	if s := datum.StatisticValues; s != nil {
+		values = append(values, s.Maximum, s.Minimum, s.SampleCount, s.Sum)
+	}
+	for _, v := range values {
+		if v == nil {
+			continue
+		}
+		if math.IsNaN(*v) || math.IsInf(*v, 0) || math.Abs(*v) > maxValueMagnitude {
+			return reasonValue, fmt.Sprintf("the value %v is not supported", *v)
+		}
+	}
+	if datum.Timestamp != nil {
+		now := g.now()
+		if datum.Timestamp.Before(now.Add(-maxTimestampAge)) || datum.Timestamp.After(now.Add(maxTimestampAhead)) {
+			return reasonTimestamp, fmt.Sprintf("the timestamp %v is more than %v old or %v ahead", datum.Timestamp.Format(time.RFC3339), maxTimestampAge, maxTimestampAhead)
+		}
+	}
+	return "", ""
+}
+
+func (g *QuotaGuard) quarantine(datum *cloudwatch.MetricDatum, reason, message string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	stat, ok := g.stats[reason]
+	if !ok {
+		stat = selfstat.Register("cloudwatch", "datums_quarantined", map[string]string{"reason": reason})
+		g.stats[reason] = stat
+	}
+	stat.Incr(1)
+
+	name := aws.StringValue(datum.MetricName)
+	key := name + "/" + reason
+	if !g.warnings[key] && len(g.warnings) < maxQuarantineWarnings {
+		g.warnings[key] = true
+		g.log.Warnf("Quarantined the datums of metric %q which PutMetricData would reject: %s", name, message)
+	} else {
+		g.log.Debugf("Quarantined a datum of metric %q: %s", name, message)
+	}
+
+	if g.file == nil {
+		return
+	}
+	q := quarantinedDatum{
+		Time:       g.now(),
+		Reason:     reason,
+		Message:    message,
+		MetricName: name,
+		DatumTime:  datum.Timestamp,
+	}
+	if len(datum.Dimensions) > 0 {
+		q.Dimensions = make(map[string]string, len(datum.Dimensions))
+		for _, d := range datum.Dimensions {
+			q.Dimensions[aws.StringValue(d.Name)] = aws.StringValue(d.Value)
+		}
+	}
+	line, err := json.Marshal(q)
+	if err != nil {
+		g.log.Errorf("Unable to marshal a quarantined datum: %v", err)
+		return
+	}
+	if _, err := g.file.Write(append(line, '\n')); err != nil {
+		g.log.Errorf("Unable to write a quarantined datum: %v", err)
+	}
+}
+
+// Close closes the quarantine file.
+func (g *QuotaGuard) Close() {
+	if g == nil {
+		return
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.file != nil {
+		g.file.Close()
+	}
+}
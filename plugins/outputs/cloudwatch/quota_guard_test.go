@@ -0,0 +1,112 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package cloudwatch
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cloudwatch"
+	"github.com/influxdata/telegraf/selfstat"
+	"github.com/influxdata/telegraf/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateNamespace(t *testing.T) {
+	for _, namespace := range []string{"CWAgent", "My App/prod-1", "team#1:metrics"} {
+		assert.NoError(t, validateNamespace(namespace), namespace)
+	}
+	for _, namespace := range []string{"", strings.Repeat("a", 256), "AWS/EC2", ":metrics", "app\tprod", "Métriques"} {
+		assert.Error(t, validateNamespace(namespace), namespace)
+	}
+}
+
+func TestQuotaGuard_Check(t *testing.T) {
+	now := time.Now()
+	g := NewQuotaGuard("", testutil.Logger{})
+	g.now = func() time.Time { return now }
+
+	datum := func() *cloudwatch.MetricDatum {
+		return &cloudwatch.MetricDatum{
+			MetricName: aws.String("cpu_usage_idle"),
+			Dimensions: []*cloudwatch.Dimension{{Name: aws.String("host"), Value: aws.String("h1")}},
+			Timestamp:  aws.Time(now),
+			Value:      aws.Float64(42),
+		}
+	}
+	assert.True(t, g.Check(datum()))
+
+	cases := map[string]func(d *cloudwatch.MetricDatum){
+		reasonMetricName: func(d *cloudwatch.MetricDatum) { d.MetricName = aws.String(strings.Repeat("m", 256)) },
+		reasonDimensionCount: func(d *cloudwatch.MetricDatum) {
+			for i := 0; i < MaxDimensions; i++ {
+				d.Dimensions = append(d.Dimensions, &cloudwatch.Dimension{Name: aws.String(string(rune('a' + i))), Value: aws.String("v")})
+			}
+		},
+		reasonDimension: func(d *cloudwatch.MetricDatum) { d.Dimensions[0].Value = aws.String(strings.Repeat("v", 1025)) },
+		reasonValue:     func(d *cloudwatch.MetricDatum) { d.Value = aws.Float64(math.NaN()) },
+		reasonTimestamp: func(d *cloudwatch.MetricDatum) { d.Timestamp = aws.Time(now.Add(-15 * 24 * time.Hour)) },
+	}
+	for reason, change := range cases {
+		d := datum()
+		change(d)
+		actual, _ := g.violation(d)
+		assert.Equal(t, reason, actual)
+		assert.False(t, g.Check(d), reason)
+	}
+
+	// a distribution with an infinite statistic
+	d := datum()
+	d.Value = nil
+	d.SetValues(aws.Float64Slice([]float64{1, 2}))
+	d.SetStatisticValues(&cloudwatch.StatisticSet{Maximum: aws.Float64(math.Inf(1)), Minimum: aws.Float64(1), SampleCount: aws.Float64(2), Sum: aws.Float64(3)})
+	reason, _ := g.violation(d)
+	assert.Equal(t, reasonValue, reason)
+}
+
+func TestQuotaGuard_QuarantineFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "quarantine")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "quarantine.log")
+
+	g := NewQuotaGuard(file, testutil.Logger{})
+	datum := &cloudwatch.MetricDatum{
+		MetricName: aws.String("mem_used"),
+		Dimensions: []*cloudwatch.Dimension{{Name: aws.String("host"), Value: aws.String("h1")}},
+		Timestamp:  aws.Time(time.Now()),
+		Value:      aws.Float64(math.Inf(-1)),
+	}
+	assert.False(t, g.Check(datum))
+	assert.False(t, g.Check(datum))
+	g.Close()
+
+	b, err := ioutil.ReadFile(file)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(b)), "\n")
+	require.Equal(t, 2, len(lines))
+	var q quarantinedDatum
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &q))
+	assert.Equal(t, reasonValue, q.Reason)
+	assert.Equal(t, "mem_used", q.MetricName)
+	assert.Equal(t, map[string]string{"host": "h1"}, q.Dimensions)
+
+	found := false
+	for _, m := range selfstat.Metrics() {
+		if m.Name() == "internal_cloudwatch" && m.Tags()["reason"] == reasonValue {
+			found = true
+			v, _ := m.GetField("datums_quarantined")
+			assert.True(t, v.(int64) >= 2)
+		}
+	}
+	assert.True(t, found)
+}
@@ -0,0 +1,161 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package cloudwatch
+
+import (
+	"strings"
+	"time"
+
+	"github.com/aws/amazon-cloudwatch-agent/metric/distribution"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cloudwatch"
+)
+
+// the rollup is flushed once it merges this many series, bounding its memory whatever the number of the rollup
+// dimensions and their values
+const maxRollupSeries = 10000
+
+// Rollup merges the fields of the metrics by rollup dimensions into a distribution by series and minute, or by second
+// for the high resolution metrics, instead of building a datum by metric. The datums of a flush window are built
+// once the rollup is flushed, a datum by series however many metrics share the rollup dimensions.
+type Rollup struct {
+	maxValuesPerDatum int
+	seh1Epsilon       float64
+
+	series  map[rollupKey]*rollupSeries
+	started time.Time
+}
+
+type rollupKey struct {
+	namespace      string
+	metricName     string
+	dimensions     string
+	unit           string
+	timestamp      int64
+	highResolution bool
+}
+
+type rollupSeries struct {
+	namespace      string
+	metricName     *string
+	dimensions     []*cloudwatch.Dimension
+	timestamp      time.Time
+	unit           string
+	highResolution bool
+	dist           distribution.Distribution
+	// the series only merged values, a single value is published as is
+	values bool
+}
+
+func NewRollup(maxValuesPerDatum int, seh1Epsilon float64) *Rollup {
+	return &Rollup{
+		maxValuesPerDatum: maxValuesPerDatum,
+		seh1Epsilon:       seh1Epsilon,
+		series:            map[rollupKey]*rollupSeries{},
+	}
+}
+
+// add merges the value, or the distribution when it isn't nil, into the series of the rollup dimensions. It returns
+// false when the datum is built as is instead, the negative values can't be merged into the distributions.
+func (r *Rollup) add(namespace string, metricName *string, dimensions []*cloudwatch.Dimension, timestamp time.Time, unit string, highResolution bool, value float64, dist distribution.Distribution) bool {
+	if r == nil || dist == nil && value < 0 {
+		return false
+	}
+	window := time.Minute
+	if highResolution {
+		window = time.Second
+	}
+	timestamp = timestamp.Truncate(window)
+	key := rollupKey{
+		namespace:      namespace,
+		metricName:     aws.StringValue(metricName),
+		dimensions:     dimensionsKey(dimensions),
+		unit:           unit,
+		timestamp:      timestamp.Unix(),
+		highResolution: highResolution,
+	}
+	s, ok := r.series[key]
+	if !ok {
+		s = &rollupSeries{
+			namespace:      namespace,
+			metricName:     metricName,
+			dimensions:     dimensions,
+			timestamp:      timestamp,
+			unit:           unit,
+			highResolution: highResolution,
+			dist:           distribution.NewDistribution(),
+			values:         true,
+		}
+	}
+	if dist != nil {
+		s.dist.AddDistribution(dist)
+		s.values = false
+	} else if err := s.dist.AddEntryWithUnit(value, 1, unit); err != nil {
+		return false
+	}
+	if !ok {
+		if len(r.series) == 0 {
+			r.started = time.Now()
+		}
+		r.series[key] = s
+	}
+	return true
+}
+
+func dimensionsKey(dimensions []*cloudwatch.Dimension) string {
+	var b strings.Builder
+	for _, d := range dimensions {
+		b.WriteString(aws.StringValue(d.Name))
+		b.WriteByte('=')
+		b.WriteString(aws.StringValue(d.Value))
+		b.WriteByte(',')
+	}
+	return b.String()
+}
+
+// full checks if the rollup reached the maximum number of series.
+func (r *Rollup) full() bool {
+	return len(r.series) >= maxRollupSeries
+}
+
+// age returns how long the oldest series of the flush window was merged, 0 when the rollup is empty.
+func (r *Rollup) age() time.Duration {
+	if len(r.series) == 0 {
+		return 0
+	}
+	return time.Since(r.started)
+}
+
+// flush returns the datums of the series by namespace and starts a new flush window.
+func (r *Rollup) flush() map[string][]*cloudwatch.MetricDatum {
+	datums := map[string][]*cloudwatch.MetricDatum{}
+	for _, s := range r.series {
+		if s.dist.SampleCount() == 0 {
+			continue
+		}
+		if s.values && s.dist.SampleCount() == 1 {
+			datum := &cloudwatch.MetricDatum{
+				MetricName: s.metricName,
+				Dimensions: s.dimensions,
+				Timestamp:  aws.Time(s.timestamp),
+				Value:      aws.Float64(s.dist.Sum()),
+			}
+			if s.unit != "" {
+				datum.SetUnit(s.unit)
+			}
+			if s.highResolution {
+				datum.SetStorageResolution(1)
+			}
+			datums[s.namespace] = append(datums[s.namespace], datum)
+			continue
+		}
+		distList := []distribution.Distribution{s.dist}
+		if s.dist.Size() > 0 {
+			distList = resize(s.dist, r.maxValuesPerDatum, r.seh1Epsilon)
+		}
+		datums[s.namespace] = append(datums[s.namespace], distributionDatums(s.metricName, s.dimensions, s.timestamp, s.unit, s.highResolution, distList)...)
+	}
+	r.series = map[rollupKey]*rollupSeries{}
+	return datums
+}
@@ -0,0 +1,111 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package cloudwatch
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aws/amazon-cloudwatch-agent/metric/distribution"
+	"github.com/aws/amazon-cloudwatch-agent/metric/distribution/seh1"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cloudwatch"
+	"github.com/influxdata/telegraf/metric"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRollup_MergesTheRollupDimensions(t *testing.T) {
+	distribution.NewDistribution = seh1.NewSEH1Distribution
+	c := &CloudWatch{MaxValuesPerDatum: defaultMaxValuesPerDatum, RollupDimensions: [][]string{{"InstanceId"}}}
+	rollup := NewRollup(c.MaxValuesPerDatum, c.SEH1Epsilon)
+
+	now := time.Now().Truncate(time.Minute)
+	var datums []*cloudwatch.MetricDatum
+	for i, device := range []string{"nvme0n1", "nvme1n1", "nvme2n1"} {
+		m, _ := metric.New("disk", map[string]string{"InstanceId": "i-0123456789abcdef0", "device": device},
+			map[string]interface{}{"used_percent": float64(10 * (i + 1))}, now.Add(time.Duration(i)*time.Second))
+		datums = append(datums, c.buildMetricDatums(m, "CWAgent", rollup)["CWAgent"]...)
+	}
+	// the original datums are built right away
+	assert.Len(t, datums, 3)
+
+	flushed := rollup.flush()
+	require.Len(t, flushed["CWAgent"], 1)
+	datum := flushed["CWAgent"][0]
+	assert.Equal(t, "disk_used_percent", aws.StringValue(datum.MetricName))
+	assert.Equal(t, []*cloudwatch.Dimension{{Name: aws.String("InstanceId"), Value: aws.String("i-0123456789abcdef0")}}, datum.Dimensions)
+	assert.Equal(t, now, aws.TimeValue(datum.Timestamp))
+	assert.Nil(t, datum.Value)
+	assert.Equal(t, 3.0, aws.Float64Value(datum.StatisticValues.SampleCount))
+	assert.Equal(t, 60.0, aws.Float64Value(datum.StatisticValues.Sum))
+	assert.Equal(t, 10.0, aws.Float64Value(datum.StatisticValues.Minimum))
+	assert.Equal(t, 30.0, aws.Float64Value(datum.StatisticValues.Maximum))
+
+	// the flush starts a new window
+	assert.Empty(t, rollup.flush())
+	assert.Equal(t, time.Duration(0), rollup.age())
+}
+
+func TestRollup_Series(t *testing.T) {
+	distribution.NewDistribution = seh1.NewSEH1Distribution
+	rollup := NewRollup(defaultMaxValuesPerDatum, 0)
+	name := aws.String("cpu_usage_user")
+	dimensions := []*cloudwatch.Dimension{{Name: aws.String("AutoScalingGroupName"), Value: aws.String("web")}}
+	now := time.Now().Truncate(time.Minute)
+
+	assert.True(t, rollup.add("CWAgent", name, dimensions, now, "Percent", false, 1, nil))
+	// the next minute, the high resolution second and the other namespace are other series
+	assert.True(t, rollup.add("CWAgent", name, dimensions, now.Add(time.Minute), "Percent", false, 2, nil))
+	assert.True(t, rollup.add("CWAgent", name, dimensions, now.Add(time.Second), "Percent", true, 3, nil))
+	assert.True(t, rollup.add("Other", name, dimensions, now, "Percent", false, 4, nil))
+	// the negative values are built as is
+	assert.False(t, rollup.add("CWAgent", name, dimensions, now, "Percent", false, -1, nil))
+
+	dist := distribution.NewDistribution()
+	assert.NoError(t, dist.AddEntry(5, 2))
+	assert.True(t, rollup.add("CWAgent", name, dimensions, now.Add(30*time.Second), "Percent", false, 0, dist))
+
+	flushed := rollup.flush()
+	require.Len(t, flushed["CWAgent"], 3)
+	require.Len(t, flushed["Other"], 1)
+	// a single value is published as is
+	assert.Equal(t, 4.0, aws.Float64Value(flushed["Other"][0].Value))
+	assert.Equal(t, "Percent", aws.StringValue(flushed["Other"][0].Unit))
+	for _, datum := range flushed["CWAgent"] {
+		switch aws.TimeValue(datum.Timestamp) {
+		case now:
+			// the value and the distribution of the same minute
+			assert.Equal(t, 3.0, aws.Float64Value(datum.StatisticValues.SampleCount))
+			assert.Equal(t, 11.0, aws.Float64Value(datum.StatisticValues.Sum))
+			assert.Nil(t, datum.StorageResolution)
+		case now.Add(time.Minute):
+			assert.Equal(t, 2.0, aws.Float64Value(datum.Value))
+		case now.Add(time.Second):
+			assert.Equal(t, 3.0, aws.Float64Value(datum.Value))
+			assert.Equal(t, int64(1), aws.Int64Value(datum.StorageResolution))
+		default:
+			assert.Fail(t, "unexpected timestamp", aws.TimeValue(datum.Timestamp))
+		}
+	}
+}
+
+func TestRollup_Full(t *testing.T) {
+	distribution.NewDistribution = seh1.NewSEH1Distribution
+	rollup := NewRollup(defaultMaxValuesPerDatum, 0)
+	now := time.Now()
+	for i := 0; i < maxRollupSeries; i++ {
+		assert.False(t, rollup.full())
+		rollup.add("CWAgent", aws.String("m"), []*cloudwatch.Dimension{{Name: aws.String("d"), Value: aws.String(string(rune('a' + i%26)))}},
+			now.Add(time.Duration(i/26)*time.Minute), "", false, 1, nil)
+	}
+	assert.True(t, rollup.full())
+	assert.Len(t, rollup.flush()["CWAgent"], maxRollupSeries)
+	assert.False(t, rollup.full())
+}
+
+func TestRollup_Disabled(t *testing.T) {
+	var rollup *Rollup
+	assert.False(t, rollup.add("CWAgent", aws.String("m"), nil, time.Now(), "", false, 1, nil))
+}
@@ -4,14 +4,18 @@
 package cloudwatch
 
 import (
+	"fmt"
 	"log"
+	"math"
 	"math/rand"
 	"sort"
 	"time"
 
 	"github.com/aws/amazon-cloudwatch-agent/metric/distribution"
+	"github.com/aws/amazon-cloudwatch-agent/metric/distribution/exponential"
 	"github.com/aws/amazon-cloudwatch-agent/metric/distribution/regular"
 	"github.com/aws/amazon-cloudwatch-agent/metric/distribution/seh1"
+	"github.com/aws/amazon-cloudwatch-agent/metric/distribution/tdigest"
 	"github.com/aws/aws-sdk-go/service/cloudwatch"
 )
 
@@ -53,20 +57,53 @@ func publishJitter(publishInterval time.Duration) (publishJitter time.Duration)
 	return
 }
 
-func setNewDistributionFunc(maxValuesPerDatumLimit int) {
-	if maxValuesPerDatumLimit >= maxValuesPerDatum {
-		distribution.NewDistribution = seh1.NewSEH1Distribution
+// setNewDistributionFunc selects the distribution of the values, the exponential histograms and the t-digests fit
+// the limit of values by themselves while the seh1 distributions of the epsilon are resized when the limit is lower
+// than maxValuesPerDatum.
+func setNewDistributionFunc(maxValuesPerDatumLimit int, distributionType string, compression float64, epsilon float64) {
+	if distributionType == distributionTypeTDigest {
+		if compression <= 0 {
+			compression = tdigest.DefaultCompression
+		}
+		// a t-digest has at most compression centroids
+		compression = math.Min(compression, float64(maxValuesPerDatumLimit))
+		distribution.NewDistribution = func() distribution.Distribution {
+			return tdigest.NewTDigestDistributionWithCompression(compression)
+		}
+	} else if distributionType == distributionTypeExponentialHistogram {
+		distribution.NewDistribution = func() distribution.Distribution {
+			return exponential.NewExponentialHistogramDistributionWithMaxSize(maxValuesPerDatumLimit)
+		}
+	} else if maxValuesPerDatumLimit >= maxValuesPerDatum {
+		epsilon = seh1.ClampEpsilon(epsilon)
+		distribution.NewDistribution = func() distribution.Distribution {
+			return seh1.NewSEH1DistributionWithEpsilon(epsilon)
+		}
 	} else {
 		distribution.NewDistribution = regular.NewRegularDistribution
 	}
 }
 
-func resize(dist distribution.Distribution, listMaxSize int) (distList []distribution.Distribution) {
+func validateDistributionType(distributionType string) error {
+	switch distributionType {
+	case "", distributionTypeSEH1, distributionTypeExponentialHistogram, distributionTypeTDigest:
+		return nil
+	}
+	return fmt.Errorf("invalid distribution_type %q, it must be %s, %s or %s", distributionType, distributionTypeSEH1, distributionTypeExponentialHistogram, distributionTypeTDigest)
+}
+
+// resize buckets the values of the regular distributions in seh1 distributions of the epsilon, of at most
+// listMaxSize values each. The other distributions with more than listMaxSize values are split.
+func resize(dist distribution.Distribution, listMaxSize int, epsilon float64) (distList []distribution.Distribution) {
 	var ok bool
-	// If this is SEH1 distribution, it has already considered the list max size.
-	if _, ok = dist.(*seh1.SEH1Distribution); ok {
-		distList = append(distList, dist)
-		return
+	if synchronized, ok := dist.(*distribution.SynchronizedDistribution); ok {
+		dist = synchronized.Unwrap()
+	}
+	// The seh1 distributions, the exponential histograms and the t-digests have already considered the list max size,
+	// they are only split when their values still exceed it.
+	switch dist.(type) {
+	case *seh1.SEH1Distribution, *exponential.ExponentialHistogramDistribution, *tdigest.TDigestDistribution:
+		return split(dist, listMaxSize)
 	}
 	var regularDist *regular.RegularDistribution
 	if regularDist, ok = dist.(*regular.RegularDistribution); !ok {
@@ -75,11 +112,11 @@ func resize(dist distribution.Distribution, listMaxSize int) (distList []distrib
 	}
 	values, _ := regularDist.ValuesAndCounts()
 	sort.Float64s(values)
-	newSEH1Dist := seh1.NewSEH1Distribution().(*seh1.SEH1Distribution)
+	newSEH1Dist := seh1.NewSEH1DistributionWithEpsilon(epsilon).(*seh1.SEH1Distribution)
 	for i := 0; i < len(values); i++ {
 		if !newSEH1Dist.CanAdd(values[i], listMaxSize) {
 			distList = append(distList, newSEH1Dist)
-			newSEH1Dist = seh1.NewSEH1Distribution().(*seh1.SEH1Distribution)
+			newSEH1Dist = seh1.NewSEH1DistributionWithEpsilon(epsilon).(*seh1.SEH1Distribution)
 		}
 		newSEH1Dist.AddEntry(values[i], regularDist.GetCount(values[i]))
 	}
@@ -89,6 +126,153 @@ func resize(dist distribution.Distribution, listMaxSize int) (distList []distrib
 	return
 }
 
+// split splits the values of the distribution by range in parts of at most listMaxSize values. The sample counts
+// and the sums of the parts add up to the ones of the distribution, and their minimums and maximums are within its
+// minimum and maximum, so the statistics CloudWatch computes from the datums of the parts are the ones of the
+// distribution.
+func split(dist distribution.Distribution, listMaxSize int) []distribution.Distribution {
+	if dist.Size() <= listMaxSize || listMaxSize <= 0 {
+		return []distribution.Distribution{dist}
+	}
+	values, counts := dist.ValuesAndCounts()
+	indexes := make([]int, len(values))
+	for i := range indexes {
+		indexes[i] = i
+	}
+	sort.Slice(indexes, func(i, j int) bool { return values[indexes[i]] < values[indexes[j]] })
+
+	var parts []*distributionPart
+	var estimatedSum, sampleCount float64
+	for start := 0; start < len(indexes); start += listMaxSize {
+		end := start + listMaxSize
+		if end > len(indexes) {
+			end = len(indexes)
+		}
+		part := &distributionPart{unit: dist.Unit()}
+		for _, i := range indexes[start:end] {
+			part.values = append(part.values, values[i])
+			part.counts = append(part.counts, counts[i])
+			part.sampleCount += counts[i]
+			part.sum += values[i] * counts[i]
+		}
+		estimatedSum += part.sum
+		sampleCount += part.sampleCount
+		parts = append(parts, part)
+	}
+
+	// the negative values of the track policy are in the statistics of the distribution but not in its values, their
+	// count and their sum go to the first part, which holds the minimum of the distribution
+	tracked := dist.SampleCount() - sampleCount
+	if tracked > 0 && dist.Minimum() < 0 && values[indexes[0]] >= 0 {
+		parts[0].sampleCount += tracked
+		parts[0].sum += dist.Sum() - estimatedSum
+		sampleCount += tracked
+		estimatedSum = dist.Sum()
+	}
+
+	// the minimums and maximums of the parts are contiguous from the minimum to the maximum of the distribution,
+	// parted halfway between the last value of a part and the first value of the next one
+	for i, part := range parts {
+		part.minimum = dist.Minimum()
+		if i > 0 {
+			part.minimum = parts[i-1].maximum
+		}
+		part.maximum = dist.Maximum()
+		if i < len(parts)-1 {
+			middle := (part.values[len(part.values)-1] + parts[i+1].values[0]) / 2
+			part.maximum = math.Min(math.Max(middle, part.minimum), dist.Maximum())
+		}
+	}
+
+	// the values are the representative values of the buckets, the sums of the parts are scaled to add up to the
+	// actual sum within the bounds of the parts, the difference the bounds leave is shared by the parts by their room
+	var sum, room float64
+	for _, part := range parts {
+		if estimatedSum != 0 {
+			part.sum *= dist.Sum() / estimatedSum
+		} else if sampleCount > 0 {
+			part.sum = dist.Sum() * part.sampleCount / sampleCount
+		}
+		part.sum = math.Min(math.Max(part.sum, part.minimum*part.sampleCount), part.maximum*part.sampleCount)
+		sum += part.sum
+	}
+	residual := dist.Sum() - sum
+	for _, part := range parts {
+		room += partRoom(part, residual)
+	}
+	distList := make([]distribution.Distribution, len(parts))
+	for i, part := range parts {
+		if room > 0 {
+			part.sum += residual * partRoom(part, residual) / room
+		}
+		distList[i] = part
+	}
+	return distList
+}
+
+// partRoom is how much the sum of the part can grow, or shrink when the residual is negative, within its bounds.
+func partRoom(part *distributionPart, residual float64) float64 {
+	if residual > 0 {
+		return part.maximum*part.sampleCount - part.sum
+	}
+	return part.sum - part.minimum*part.sampleCount
+}
+
+// distributionPart is a part of the values of a distribution split to fit a datum, it is only published.
+type distributionPart struct {
+	values      []float64
+	counts      []float64
+	maximum     float64
+	minimum     float64
+	sampleCount float64
+	sum         float64
+	unit        string
+}
+
+func (p *distributionPart) Maximum() float64 {
+	return p.maximum
+}
+
+func (p *distributionPart) Minimum() float64 {
+	return p.minimum
+}
+
+func (p *distributionPart) SampleCount() float64 {
+	return p.sampleCount
+}
+
+func (p *distributionPart) Sum() float64 {
+	return p.sum
+}
+
+func (p *distributionPart) ValuesAndCounts() ([]float64, []float64) {
+	return p.values, p.counts
+}
+
+func (p *distributionPart) Unit() string {
+	return p.unit
+}
+
+func (p *distributionPart) Size() int {
+	return len(p.values)
+}
+
+func (p *distributionPart) AddEntryWithUnit(value float64, weight float64, unit string) error {
+	return fmt.Errorf("the entries can't be added to a part of a distribution")
+}
+
+func (p *distributionPart) AddEntry(value float64, weight float64) error {
+	return p.AddEntryWithUnit(value, weight, p.unit)
+}
+
+func (p *distributionPart) AddDistribution(distribution.Distribution) {
+	log.Printf("E! The distributions can't be added to a part of a distribution.")
+}
+
+func (p *distributionPart) AddDistributionWithWeight(distribution.Distribution, float64) {
+	log.Printf("E! The distributions can't be added to a part of a distribution.")
+}
+
 func payload(datum *cloudwatch.MetricDatum) (size int) {
 	size += timestampSize
 
@@ -108,6 +292,8 @@ func payload(datum *cloudwatch.MetricDatum) (size int) {
 	valuesCountsLen := len(datum.Values)
 	if valuesCountsLen != 0 {
 		size += valuesCountsLen*valuesCountsOverheads + statisticsSize
+	} else if datum.StatisticValues != nil {
+		size += statisticsSize
 	} else {
 		size += valueOverheads
 	}
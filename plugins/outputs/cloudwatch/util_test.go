@@ -5,11 +5,13 @@ package cloudwatch
 
 import (
 	"log"
+	"math"
 	"sort"
 	"testing"
 	"time"
 
 	"github.com/aws/amazon-cloudwatch-agent/metric/distribution"
+	"github.com/aws/amazon-cloudwatch-agent/metric/distribution/exponential"
 	"github.com/aws/amazon-cloudwatch-agent/metric/distribution/regular"
 	"github.com/aws/amazon-cloudwatch-agent/metric/distribution/seh1"
 	"github.com/aws/aws-sdk-go/aws"
@@ -25,24 +27,58 @@ func TestPublishJitter(t *testing.T) {
 }
 
 func TestSetNewDistributionFunc(t *testing.T) {
-	setNewDistributionFunc(maxValuesPerDatum)
+	setNewDistributionFunc(maxValuesPerDatum, "", 0, 0)
 	_, ok := distribution.NewDistribution().(*seh1.SEH1Distribution)
 	assert.True(t, ok)
 
-	setNewDistributionFunc(defaultMaxValuesPerDatum)
+	setNewDistributionFunc(defaultMaxValuesPerDatum, "", 0, 0)
 	_, ok = distribution.NewDistribution().(*regular.RegularDistribution)
 	assert.True(t, ok)
+
+	// the exponential histograms fit the limit by themselves
+	setNewDistributionFunc(2, distributionTypeExponentialHistogram, 0, 0)
+	dist := distribution.NewDistribution()
+	_, ok = dist.(*exponential.ExponentialHistogramDistribution)
+	assert.True(t, ok)
+	for _, v := range []float64{1, 10, 100, 1000} {
+		assert.NoError(t, dist.AddEntry(v, 1))
+	}
+	assert.Equal(t, []distribution.Distribution{dist}, resize(dist, 2, 0))
+	assert.Equal(t, 2, dist.Size())
+
+	// the compression of the t-digests is bounded by the limit
+	setNewDistributionFunc(defaultMaxValuesPerDatum, distributionTypeTDigest, 1000, 0)
+	dist = distribution.NewDistribution()
+	for i := 0; i < 10000; i++ {
+		assert.NoError(t, dist.AddEntry(float64(i), 1))
+	}
+	assert.True(t, dist.Size() <= defaultMaxValuesPerDatum)
+	assert.Equal(t, []distribution.Distribution{dist}, resize(dist, defaultMaxValuesPerDatum, 0))
+	setNewDistributionFunc(defaultMaxValuesPerDatum, "", 0, 0)
+
+	// the seh1 epsilon applies to the distributions and to the resized ones
+	setNewDistributionFunc(maxValuesPerDatum, "", 0, 0.01)
+	assert.InDelta(t, 0.01, distribution.NewDistribution().(*seh1.SEH1Distribution).Epsilon(), 1e-12)
+	setNewDistributionFunc(defaultMaxValuesPerDatum, "", 0, 0)
+	dist = distribution.NewDistribution()
+	assert.NoError(t, dist.AddEntry(20, 1))
+	distList := resize(dist, defaultMaxValuesPerDatum, 0.01)
+	assert.Len(t, distList, 1)
+	assert.InDelta(t, 0.01, distList[0].(*seh1.SEH1Distribution).Epsilon(), 1e-12)
+
+	assert.NoError(t, validateDistributionType(""))
+	assert.EqualError(t, validateDistributionType("regular"), `invalid distribution_type "regular", it must be seh1, exponential_histogram or tdigest`)
 }
 
 func TestResize(t *testing.T) {
 	maxListSize := 2
-	setNewDistributionFunc(maxListSize)
+	setNewDistributionFunc(maxListSize, "", 0, 0)
 
 	dist := distribution.NewDistribution()
 
 	dist.AddEntry(1, 1)
 
-	distList := resize(dist, maxListSize)
+	distList := resize(dist, maxListSize, 0)
 	assert.Equal(t, 1, len(distList))
 
 	actualDist := distList[0]
@@ -62,7 +98,7 @@ func TestResize(t *testing.T) {
 	assert.NoError(t, dist.AddEntry(3, 1))
 	assert.NoError(t, dist.AddEntry(4, 1))
 
-	distList = resize(dist, maxListSize)
+	distList = resize(dist, maxListSize, 0)
 	assert.Equal(t, 2, len(distList))
 
 	actualDist = distList[0]
@@ -94,6 +130,66 @@ func TestResize(t *testing.T) {
 	assert.Equal(t, float64(7), sum)
 }
 
+func TestResize_Split(t *testing.T) {
+	dist := seh1.NewSEH1Distribution()
+	for i := 1; i <= 10; i++ {
+		assert.NoError(t, dist.AddEntryWithUnit(float64(i), float64(i), "Seconds"))
+	}
+	assert.Equal(t, 10, dist.Size())
+
+	distList := resize(dist, 3, 0)
+	assert.Equal(t, 4, len(distList))
+	var sampleCount, sum float64
+	previousMaximum := math.Inf(-1)
+	for _, part := range distList {
+		values, counts := part.ValuesAndCounts()
+		assert.LessOrEqual(t, part.Size(), 3)
+		assert.Equal(t, len(values), len(counts))
+		assert.Equal(t, "Seconds", part.Unit())
+		assert.LessOrEqual(t, previousMaximum, part.Minimum())
+		assert.LessOrEqual(t, part.Minimum(), part.Sum()/part.SampleCount())
+		assert.LessOrEqual(t, part.Sum()/part.SampleCount(), part.Maximum())
+		previousMaximum = part.Maximum()
+		sampleCount += part.SampleCount()
+		sum += part.Sum()
+	}
+	assert.Equal(t, dist.SampleCount(), sampleCount)
+	assert.InDelta(t, dist.Sum(), sum, 1e-9)
+	assert.Equal(t, dist.Minimum(), distList[0].Minimum())
+	assert.Equal(t, dist.Maximum(), distList[3].Maximum())
+
+	// the distributions within the limit aren't split
+	distList = resize(dist, 10, 0)
+	assert.Equal(t, []distribution.Distribution{dist}, distList)
+}
+
+func TestResize_SplitTracked(t *testing.T) {
+	assert.NoError(t, distribution.SetNegativeValuePolicy(distribution.NegativeValuesTrack))
+	defer distribution.SetNegativeValuePolicy(distribution.NegativeValuesDrop)
+	dist := seh1.NewSEH1Distribution()
+	for i := 1; i <= 10; i++ {
+		assert.NoError(t, dist.AddEntryWithUnit(float64(i), 1, "Seconds"))
+	}
+	assert.NoError(t, dist.AddEntryWithUnit(-5, 2, "Seconds"))
+	assert.NoError(t, dist.AddEntryWithUnit(-1, 1, "Seconds"))
+	assert.Equal(t, 10, dist.Size())
+
+	distList := resize(dist, 3, 0)
+	assert.Equal(t, 4, len(distList))
+	var sampleCount, sum float64
+	for _, part := range distList {
+		assert.LessOrEqual(t, part.Minimum(), part.Sum()/part.SampleCount())
+		assert.LessOrEqual(t, part.Sum()/part.SampleCount(), part.Maximum())
+		sampleCount += part.SampleCount()
+		sum += part.Sum()
+	}
+	// the tracked values are in the first part
+	assert.Equal(t, float64(6), distList[0].SampleCount())
+	assert.Equal(t, float64(-5), distList[0].Minimum())
+	assert.Equal(t, dist.SampleCount(), sampleCount)
+	assert.InDelta(t, dist.Sum(), sum, 1e-9)
+}
+
 func TestPayload_ValuesAndCounts(t *testing.T) {
 	datum := new(cloudwatch.MetricDatum)
 	datum.SetCounts(aws.Float64Slice([]float64{1, 2, 3}))
@@ -14,7 +14,10 @@ import (
 	configaws "github.com/aws/amazon-cloudwatch-agent/cfg/aws"
 	"github.com/aws/amazon-cloudwatch-agent/handlers"
 	"github.com/aws/amazon-cloudwatch-agent/internal"
+	"github.com/aws/amazon-cloudwatch-agent/internal/audit"
+	"github.com/aws/amazon-cloudwatch-agent/internal/logscommon"
 	"github.com/aws/amazon-cloudwatch-agent/internal/retryer"
+	"github.com/aws/amazon-cloudwatch-agent/internal/sidecar"
 	"github.com/aws/amazon-cloudwatch-agent/logs"
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/request"
@@ -38,6 +41,8 @@ const (
 	metricRetryTimeout = 2 * time.Minute
 
 	attributesInFields = "attributesInFields"
+
+	opPutLogEvents = "PutLogEvents"
 )
 
 type CloudWatchLogs struct {
@@ -54,19 +59,58 @@ type CloudWatchLogs struct {
 	LogStreamName string `toml:"log_stream_name"`
 	LogGroupName  string `toml:"log_group_name"`
 
+	// The replacement of the characters invalid in the log group and stream names once their placeholders are
+	// resolved, and whether the names over the limit are hashed or truncated
+	LogNameReplacement string `toml:"log_name_replacement"`
+	LogNameOverflow    string `toml:"log_name_overflow"`
+
 	// Retention for log group
 	RetentionInDays int `toml:"retention_in_days"`
+	// Interval of the checks of the retention of the log groups, and whether the retention changed out of the agent
+	// is restored
+	RetentionCheckInterval internal.Duration `toml:"retention_check_interval"`
+	RetentionRestore       bool              `toml:"retention_restore"`
 
 	ForceFlushInterval internal.Duration `toml:"force_flush_interval"` // unit is second
 
+	// The file the log events rejected by PutLogEvents are appended to, they are discarded when empty
+	RejectedLogEventsFile string `toml:"rejected_log_events_file"`
+
+	// The timeouts of the API calls and of the resolution of the endpoint
+	APICallTimeout       internal.Duration `toml:"api_call_timeout"`
+	DNSResolutionTimeout internal.Duration `toml:"dns_resolution_timeout"`
+
+	// MaxRetries bounds the attempts of a PutLogEvents request, MaxBackoff the backoff between them and
+	// MaxRequestsPerSecond limits the attempts of the requests of all the log streams, adapting to the throttling
+	MaxRetries           int               `toml:"max_retries"`
+	MaxBackoff           internal.Duration `toml:"max_backoff"`
+	MaxRequestsPerSecond float64           `toml:"max_requests_per_second"`
+
 	Log telegraf.Logger `toml:"-"`
 
 	pusherStopChan  chan struct{}
 	pusherWaitGroup sync.WaitGroup
 	cwDests         map[Target]*cwDest
+	retention       *retentionReconciler
+	deadLetter      *deadLetterFile
+	names           *logscommon.NameSanitizer
+	rateLimiter     *retryer.RateLimiter
+	requestStats    *retryer.RequestStats
 }
 
 func (c *CloudWatchLogs) Connect() error {
+	audit.EndpointConfigured("cloudwatchlogs", c.Region, c.EndpointOverride)
+	names, err := logscommon.NewNameSanitizer(c.LogNameReplacement, c.LogNameOverflow)
+	if err != nil {
+		return fmt.Errorf("invalid log name sanitization: %v", err)
+	}
+	c.names = names
+	c.retention = newRetentionReconciler(c.RetentionCheckInterval.Duration, c.RetentionRestore, c.Log)
+	c.deadLetter = newDeadLetterFile(c.RejectedLogEventsFile)
+	c.rateLimiter = retryer.NewRateLimiter(c.MaxRequestsPerSecond, opPutLogEvents)
+	c.requestStats = retryer.NewRequestStats("cloudwatchlogs", opPutLogEvents)
+	c.pusherWaitGroup.Add(1)
+	go c.retention.run(c.pusherStopChan, &c.pusherWaitGroup)
 	return nil
 }
 
@@ -108,6 +152,7 @@ func (c *CloudWatchLogs) CreateDest(group, stream string, retention int) logs.Lo
 }
 
 func (c *CloudWatchLogs) getDest(t Target) *cwDest {
+	t = c.sanitizeTarget(t)
 	if cwd, ok := c.cwDests[t]; ok {
 		return cwd
 	}
@@ -120,6 +165,9 @@ func (c *CloudWatchLogs) getDest(t Target) *cwDest {
 		Profile:   c.Profile,
 		Filename:  c.Filename,
 		Token:     c.Token,
+
+		APICallTimeout:       c.APICallTimeout.Duration,
+		DNSResolutionTimeout: c.DNSResolutionTimeout.Duration,
 	}
 
 	logThrottleRetryer := retryer.NewLogThrottleRetryer(c.Log)
@@ -132,15 +180,43 @@ func (c *CloudWatchLogs) getDest(t Target) *cwDest {
 			Logger:   configaws.SDKLogger{},
 		},
 	)
-	client.Handlers.Build.PushBackNamed(handlers.NewRequestCompressionHandler([]string{"PutLogEvents"}))
+	client.Handlers.Build.PushBackNamed(handlers.NewRequestCompressionHandler([]string{opPutLogEvents}))
 	client.Handlers.Build.PushBackNamed(handlers.NewCustomHeaderHandler("User-Agent", agentinfo.UserAgent(t.Group)))
-
-	pusher := NewPusher(t, client, c.ForceFlushInterval.Duration, maxRetryTimeout, c.Log, c.pusherStopChan, &c.pusherWaitGroup)
+	c.rateLimiter.AddHandlers(&client.Handlers)
+	c.requestStats.AddHandlers(&client.Handlers)
+
+	retries := retryPolicy{
+		maxRetries:   c.MaxRetries,
+		maxBackoff:   c.MaxBackoff.Duration,
+		rateLimiter:  c.rateLimiter,
+		requestStats: c.requestStats,
+	}
+	pusher := NewPusher(t, client, c.ForceFlushInterval.Duration, maxRetryTimeout, c.Log, c.pusherStopChan, &c.pusherWaitGroup, c.deadLetter, retries)
 	cwd := &cwDest{pusher: pusher, retryer: logThrottleRetryer}
 	c.cwDests[t] = cwd
+	if c.retention != nil {
+		c.retention.register(t.Group, t.Retention, client)
+	}
 	return cwd
 }
 
+// sanitizeTarget makes the names of the target valid, the names CloudWatch Logs would reject are logged once, when
+// their destination is created.
+func (c *CloudWatchLogs) sanitizeTarget(t Target) Target {
+	if c.names == nil {
+		return t
+	}
+	group, stream := c.names.LogGroupName(t.Group), c.names.LogStreamName(t.Stream)
+	if group == t.Group && stream == t.Stream {
+		return t
+	}
+	sanitized := Target{Group: group, Stream: stream, Retention: t.Retention}
+	if _, ok := c.cwDests[sanitized]; !ok {
+		c.Log.Warnf("Log group %q and stream %q are invalid for CloudWatch Logs, publishing to log group %q and stream %q", t.Group, t.Stream, group, stream)
+	}
+	return sanitized
+}
+
 func (c *CloudWatchLogs) writeMetricAsStructuredLog(m telegraf.Metric) {
 	t, err := c.getTargetFromMetric(m)
 	if err != nil {
@@ -280,6 +356,7 @@ type cwDest struct {
 }
 
 func (cd *cwDest) Publish(events []logs.LogEvent) error {
+	sidecar.Activity()
 	for _, e := range events {
 		if !cd.isEMF {
 			msg := e.Message()
@@ -357,8 +434,36 @@ var sampleConfig = `
   #profile = ""
   #shared_credential_file = ""
 
+  ## The timeout of an API call, from the connection to the end of the response, and the timeout of the resolution
+  ## of the endpoint, as part of the call.
+  # api_call_timeout = "1m"
+  # dns_resolution_timeout = "10s"
+
   # The log stream name.
   log_stream_name = "<log_stream_name>"
+
+  ## The characters CloudWatch Logs rejects in the log group and stream names, once their placeholders are resolved,
+  ## are replaced with log_name_replacement. The names longer than 512 bytes are truncated and suffixed with a hash
+  ## of the whole name with log_name_overflow = "hash", or only truncated with "truncate".
+  # log_name_replacement = "_"
+  # log_name_overflow = "hash"
+
+  ## The retention of the log groups with a retention_in_days is checked at this interval, a retention changed out
+  ## of the agent is logged and recorded as a RetentionDrifted audit event, and restored when retention_restore is set.
+  # retention_check_interval = "1h"
+  # retention_restore = false
+
+  ## The log events rejected by PutLogEvents as expired or too old are appended to this file as json lines instead of
+  ## being discarded. The events rejected as too new are sent again later, and appended to it when they still are.
+  # rejected_log_events_file = "/opt/aws/amazon-cloudwatch-agent/logs/rejected_log_events.json"
+
+  ## A PutLogEvents request is retried for 14 days by default, or sent up to max_retries times, waiting twice longer
+  ## between the attempts up to max_backoff. The attempts of the requests of all the log streams are limited to
+  ## max_requests_per_second when set, halved while they are throttled. The throttled and retried attempts are counted
+  ## in the requests_throttled and requests_retried stats of the internal input.
+  # max_retries = 10
+  # max_backoff = "1m"
+  # max_requests_per_second = 50.0
 `
 
 // SampleConfig returns the default configuration of the Output
@@ -6,7 +6,9 @@ package cloudwatchlogs
 import (
 	"testing"
 
+	"github.com/aws/amazon-cloudwatch-agent/internal/logscommon"
 	"github.com/influxdata/telegraf/plugins/outputs"
+	"github.com/influxdata/telegraf/testutil"
 )
 
 func TestCreateDest(t *testing.T) {
@@ -44,3 +46,19 @@ func TestCreateDest(t *testing.T) {
 		t.Errorf("Empty create dest should return dest to default group and stream, %v/%v found", d.pusher.Group, d.pusher.Stream)
 	}
 }
+
+func TestCreateDest_Sanitized(t *testing.T) {
+	c := outputs.Outputs["cloudwatchlogs"]().(*CloudWatchLogs)
+	c.Log = testutil.Logger{}
+	c.names, _ = logscommon.NewNameSanitizer("", "")
+
+	d0 := c.CreateDest("app logs", "arn:aws:ecs:task", -1).(*cwDest)
+	if d0.pusher.Group != "app_logs" || d0.pusher.Stream != "arn_aws_ecs_task" {
+		t.Errorf("Wrong target for the created cwDest: %s/%s, expecting app_logs/arn_aws_ecs_task", d0.pusher.Group, d0.pusher.Stream)
+	}
+
+	d1 := c.CreateDest("app_logs", "arn_aws_ecs_task", -1).(*cwDest)
+	if d0 != d1 {
+		t.Errorf("Create dest with the names sanitized to the same names should return the same cwDest")
+	}
+}
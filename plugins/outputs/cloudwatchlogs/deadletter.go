@@ -0,0 +1,72 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package cloudwatchlogs
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"sync"
+
+	"github.com/aws/aws-sdk-go/service/cloudwatchlogs"
+)
+
+const (
+	rejectedTooOld  = "too_old"
+	rejectedTooNew  = "too_new"
+	rejectedExpired = "expired"
+)
+
+// deadLetterFile appends the log events rejected by PutLogEvents to a local file, one json object by line, so they
+// can be inspected or replayed instead of being discarded. The file is shared by the pushers of every log stream.
+type deadLetterFile struct {
+	path string
+	mu   sync.Mutex
+}
+
+type deadLetterRecord struct {
+	LogGroupName  string `json:"log_group_name"`
+	LogStreamName string `json:"log_stream_name"`
+	Reason        string `json:"reason"`
+	Timestamp     int64  `json:"timestamp"`
+	Message       string `json:"message"`
+}
+
+func newDeadLetterFile(path string) *deadLetterFile {
+	if path == "" {
+		return nil
+	}
+	return &deadLetterFile{path: path}
+}
+
+// write appends the events of the target rejected for the reason. The file is opened at each write, so it can be
+// rotated or removed while the agent runs.
+func (d *deadLetterFile) write(t Target, reason string, events []*cloudwatchlogs.InputLogEvent) error {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	for _, e := range events {
+		err := encoder.Encode(deadLetterRecord{
+			LogGroupName:  t.Group,
+			LogStreamName: t.Stream,
+			Reason:        reason,
+			Timestamp:     *e.Timestamp,
+			Message:       *e.Message,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	f, err := os.OpenFile(d.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err = f.Write(buf.Bytes()); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -4,14 +4,18 @@
 package cloudwatchlogs
 
 import (
+	"fmt"
 	"math/rand"
 	"sort"
 	"sync"
 	"time"
 
+	"github.com/aws/amazon-cloudwatch-agent/internal/flush"
+	"github.com/aws/amazon-cloudwatch-agent/internal/health"
+	"github.com/aws/amazon-cloudwatch-agent/internal/retryer"
+	"github.com/aws/amazon-cloudwatch-agent/internal/sidecar"
 	"github.com/aws/amazon-cloudwatch-agent/logs"
 	"github.com/aws/amazon-cloudwatch-agent/profiler"
-	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/service/cloudwatchlogs"
 	"github.com/influxdata/telegraf"
@@ -20,6 +24,15 @@ import (
 const (
 	reqSizeLimit   = 1024 * 1024
 	reqEventsLimit = 10000
+	// the name of the pipeline in the health notifications
+	pipelineName = "cloudwatchlogs"
+	// the events rejected as too new are sent again a minute later at the earliest, at most maxTooNewAttempts times
+	tooNewRetryInterval = time.Minute
+	maxTooNewAttempts   = 5
+	maxFutureEventAge   = 2 * time.Hour
+	// the backoff between the attempts of a request doubles from retryBaseWait up to max_backoff
+	retryBaseWait     = 200 * time.Millisecond
+	defaultMaxBackoff = time.Minute
 )
 
 var (
@@ -31,6 +44,7 @@ type CloudWatchLogsService interface {
 	CreateLogStream(input *cloudwatchlogs.CreateLogStreamInput) (*cloudwatchlogs.CreateLogStreamOutput, error)
 	CreateLogGroup(input *cloudwatchlogs.CreateLogGroupInput) (*cloudwatchlogs.CreateLogGroupOutput, error)
 	PutRetentionPolicy(input *cloudwatchlogs.PutRetentionPolicyInput) (*cloudwatchlogs.PutRetentionPolicyOutput, error)
+	DescribeLogGroups(input *cloudwatchlogs.DescribeLogGroupsInput) (*cloudwatchlogs.DescribeLogGroupsOutput, error)
 }
 
 type pusher struct {
@@ -56,9 +70,34 @@ type pusher struct {
 	initNonBlockingChOnce sync.Once
 	startNonBlockCh       chan struct{}
 	wg                    *sync.WaitGroup
+
+	// the file the rejected events are written to, nil when they are discarded
+	deadLetter *deadLetterFile
+	// the events rejected as too new waiting to be sent again, and how many times the ones in the batch were sent
+	tooNew         []tooNewEvent
+	tooNewAttempts map[*cloudwatchlogs.InputLogEvent]int
+
+	retries retryPolicy
+}
+
+// retryPolicy bounds the attempts of the PutLogEvents requests of a pusher and the backoff between them, besides the
+// RetryDuration. The rate limiter and the stats are shared by the pushers of the output.
+type retryPolicy struct {
+	// maxRetries is the attempts of a request, unbounded when it isn't positive
+	maxRetries   int
+	maxBackoff   time.Duration
+	rateLimiter  *retryer.RateLimiter
+	requestStats *retryer.RequestStats
+}
+
+// tooNewEvent is an event rejected as too new, PutLogEvents accepts it once its timestamp is less than 2 hours ahead.
+type tooNewEvent struct {
+	event    *cloudwatchlogs.InputLogEvent
+	retryAt  time.Time
+	attempts int
 }
 
-func NewPusher(target Target, service CloudWatchLogsService, flushTimeout time.Duration, retryDuration time.Duration, logger telegraf.Logger, stop <-chan struct{}, wg *sync.WaitGroup) *pusher {
+func NewPusher(target Target, service CloudWatchLogsService, flushTimeout time.Duration, retryDuration time.Duration, logger telegraf.Logger, stop <-chan struct{}, wg *sync.WaitGroup, deadLetter *deadLetterFile, retries retryPolicy) *pusher {
 	p := &pusher{
 		Target:          target,
 		Service:         service,
@@ -71,6 +110,9 @@ func NewPusher(target Target, service CloudWatchLogsService, flushTimeout time.D
 		stop:            stop,
 		startNonBlockCh: make(chan struct{}),
 		wg:              wg,
+		deadLetter:      deadLetter,
+		tooNewAttempts:  map[*cloudwatchlogs.InputLogEvent]int{},
+		retries:         retries,
 	}
 	p.putRetentionPolicy()
 	p.wg.Add(1)
@@ -143,42 +185,34 @@ func (p *pusher) start() {
 		}
 	}()
 
+	drainStarted := sidecar.DrainStarted()
+	flushRequested := flush.Requested()
 	for {
 		select {
-		case e := <-ec:
-			// Start timer when first event of the batch is added (happens after a flush timer timeout)
-			if len(p.events) == 0 {
-				p.resetFlushTimer()
-			}
-
-			ce := p.convertEvent(e)
-			et := time.Unix(*ce.Timestamp/1000, *ce.Timestamp%1000) // Cloudwatch Log Timestamp is in Millisecond
-
-			// A batch of log events in a single request cannot span more than 24 hours.
-			if (p.minT != nil && et.Sub(*p.minT) > 24*time.Hour) || (p.maxT != nil && p.maxT.Sub(et) > 24*time.Hour) {
+		case <-flushRequested:
+			flushRequested = flush.Requested()
+			if len(p.events) > 0 {
 				p.send()
 			}
-
-			size := len(*ce.Message) + eventHeaderSize
-			if p.bufferredSize+size > reqSizeLimit || len(p.events) == reqEventsLimit {
+			p.resetFlushTimer()
+		case <-drainStarted:
+			// the batches are published at once during the drain of the sidecar, the agent is about to stop
+			drainStarted = nil
+			p.FlushTimeout = sidecar.DrainFlushInterval
+			if len(p.events) > 0 {
 				p.send()
 			}
-
-			if len(p.events) > 0 && *ce.Timestamp < *p.events[len(p.events)-1].Timestamp {
-				p.needSort = true
+			p.resetFlushTimer()
+		case e := <-ec:
+			// Start timer when first event of the batch is added (happens after a flush timer timeout)
+			if len(p.events) == 0 {
+				p.resetFlushTimer()
 			}
 
-			p.events = append(p.events, ce)
-			p.doneCallbacks = append(p.doneCallbacks, e.Done)
-			p.bufferredSize += size
-			if p.minT == nil || p.minT.After(et) {
-				p.minT = &et
-			}
-			if p.maxT == nil || p.maxT.Before(et) {
-				p.maxT = &et
-			}
+			p.addToBatch(p.convertEvent(e), e.Done)
 
 		case <-p.flushTimer.C:
+			p.requeueTooNew()
 			if time.Since(p.lastSentTime) >= p.FlushTimeout && len(p.events) > 0 {
 				p.send()
 			} else {
@@ -188,13 +222,46 @@ func (p *pusher) start() {
 			if len(p.events) > 0 {
 				p.send()
 			}
+			p.discardTooNew()
 			return
 		}
 	}
 }
 
+// addToBatch adds the event to the batch, the batch is sent first when the event doesn't fit in it.
+func (p *pusher) addToBatch(ce *cloudwatchlogs.InputLogEvent, done func()) {
+	et := time.Unix(*ce.Timestamp/1000, *ce.Timestamp%1000) // Cloudwatch Log Timestamp is in Millisecond
+
+	// A batch of log events in a single request cannot span more than 24 hours.
+	if (p.minT != nil && et.Sub(*p.minT) > 24*time.Hour) || (p.maxT != nil && p.maxT.Sub(et) > 24*time.Hour) {
+		p.send()
+	}
+
+	size := len(*ce.Message) + eventHeaderSize
+	if p.bufferredSize+size > reqSizeLimit || len(p.events) == reqEventsLimit {
+		p.send()
+	}
+
+	if len(p.events) > 0 && *ce.Timestamp < *p.events[len(p.events)-1].Timestamp {
+		p.needSort = true
+	}
+
+	p.events = append(p.events, ce)
+	p.doneCallbacks = append(p.doneCallbacks, done)
+	p.bufferredSize += size
+	if p.minT == nil || p.minT.After(et) {
+		p.minT = &et
+	}
+	if p.maxT == nil || p.maxT.Before(et) {
+		p.maxT = &et
+	}
+}
+
 func (p *pusher) reset() {
 	for i := 0; i < len(p.events); i++ {
+		if len(p.tooNewAttempts) > 0 {
+			delete(p.tooNewAttempts, p.events[i])
+		}
 		p.events[i] = nil
 	}
 	p.events = p.events[:0]
@@ -225,23 +292,19 @@ func (p *pusher) send() {
 
 	retryCount := 0
 	for {
+		if retryCount > 0 {
+			p.retries.requestStats.Retried(opPutLogEvents)
+		}
 		input.SequenceToken = p.sequenceToken
 		output, err := p.Service.PutLogEvents(input)
 		if err == nil {
+			health.RecordSuccess(pipelineName)
+			p.retries.rateLimiter.Succeeded()
 			if output.NextSequenceToken != nil {
 				p.sequenceToken = output.NextSequenceToken
 			}
 			if output.RejectedLogEventsInfo != nil {
-				info := output.RejectedLogEventsInfo
-				if info.TooOldLogEventEndIndex != nil {
-					p.Log.Warnf("%d log events for log '%s/%s' are too old", *info.TooOldLogEventEndIndex, p.Group, p.Stream)
-				}
-				if info.TooNewLogEventStartIndex != nil {
-					p.Log.Warnf("%d log events for log '%s/%s' are too new", *info.TooNewLogEventStartIndex, p.Group, p.Stream)
-				}
-				if info.ExpiredLogEventEndIndex != nil {
-					p.Log.Warnf("%d log events for log '%s/%s' are expired", *info.ExpiredLogEventEndIndex, p.Group, p.Stream)
-				}
+				p.handleRejected(output.RejectedLogEventsInfo)
 			}
 			for i := len(p.doneCallbacks) - 1; i >= 0; i-- {
 				done := p.doneCallbacks[i]
@@ -259,6 +322,7 @@ func (p *pusher) send() {
 
 		awsErr, ok := err.(awserr.Error)
 		if !ok {
+			p.recordFailure(err)
 			p.Log.Errorf("Non aws error received when sending logs to %v/%v: %v. CloudWatch agent will not retry and logs will be missing!", p.Group, p.Stream, err)
 			// Messages will be discarded but done callbacks not called
 			p.reset()
@@ -269,6 +333,7 @@ func (p *pusher) send() {
 		case *cloudwatchlogs.ResourceNotFoundException:
 			err := p.createLogGroupAndStream()
 			if err != nil {
+				p.recordFailure(err)
 				p.Log.Errorf("Unable to create log stream %v/%v: %v", p.Group, p.Stream, e.Message())
 				break
 			}
@@ -285,15 +350,19 @@ func (p *pusher) send() {
 			p.sequenceToken = e.ExpectedSequenceToken
 		case *cloudwatchlogs.InvalidParameterException,
 			*cloudwatchlogs.DataAlreadyAcceptedException:
+			if _, ok := e.(*cloudwatchlogs.InvalidParameterException); ok {
+				p.recordFailure(e)
+			}
 			p.Log.Errorf("%v, will not retry the request", e)
 			p.reset()
 			return
 		default:
+			p.recordFailure(awsErr)
 			p.Log.Errorf("Aws error received when sending logs to %v/%v: %v", p.Group, p.Stream, awsErr)
 		}
 
-		wait := retryWait(retryCount)
-		if time.Since(startTime)+wait > p.RetryDuration {
+		wait := retryWait(retryCount, p.retries.maxBackoff)
+		if time.Since(startTime)+wait > p.RetryDuration || (p.retries.maxRetries > 0 && retryCount+1 >= p.retries.maxRetries) {
 			p.Log.Errorf("All %v retries to %v/%v failed for PutLogEvents, request dropped.", retryCount, p.Group, p.Stream)
 			p.reset()
 			return
@@ -314,12 +383,132 @@ func (p *pusher) send() {
 
 }
 
-func retryWait(n int) time.Duration {
-	const base = 200 * time.Millisecond
-	const max = 1 * time.Minute
-	d := base * time.Duration(1<<int64(n))
-	if n > 5 {
-		d = max
+// handleRejected counts the events of the batch rejected by PutLogEvents by reason. The expired and the too old events
+// are written to the dead-letter file, the too new events are sent again in a later batch.
+func (p *pusher) handleRejected(info *cloudwatchlogs.RejectedLogEventsInfo) {
+	// the expired and the too old events are at the start of the sorted batch, the too new ones at its end
+	n := len(p.events)
+	expiredEnd := rejectedIndex(info.ExpiredLogEventEndIndex, 0, n)
+	tooOldEnd := rejectedIndex(info.TooOldLogEventEndIndex, expiredEnd, n)
+	tooNewStart := rejectedIndex(info.TooNewLogEventStartIndex, tooOldEnd, n)
+	if info.TooNewLogEventStartIndex == nil {
+		tooNewStart = n
+	}
+
+	if expired := p.events[:expiredEnd]; len(expired) > 0 {
+		p.Log.Warnf("%d log events for log '%s/%s' are expired", len(expired), p.Group, p.Stream)
+		p.addStats("expiredLogEvents", float64(len(expired)))
+		p.writeDeadLetter(rejectedExpired, expired)
+	}
+	if tooOld := p.events[expiredEnd:tooOldEnd]; len(tooOld) > 0 {
+		p.Log.Warnf("%d log events for log '%s/%s' are too old", len(tooOld), p.Group, p.Stream)
+		p.addStats("tooOldLogEvents", float64(len(tooOld)))
+		p.writeDeadLetter(rejectedTooOld, tooOld)
+	}
+	if tooNew := p.events[tooNewStart:]; len(tooNew) > 0 {
+		p.Log.Warnf("%d log events for log '%s/%s' are too new, they will be sent again later", len(tooNew), p.Group, p.Stream)
+		p.addStats("tooNewLogEvents", float64(len(tooNew)))
+		p.deferTooNew(tooNew)
+	}
+}
+
+// rejectedIndex returns the index of RejectedLogEventsInfo within [min, n].
+func rejectedIndex(index *int64, min, n int) int {
+	if index == nil || int(*index) < min {
+		return min
+	}
+	if int(*index) > n {
+		return n
+	}
+	return int(*index)
+}
+
+// deferTooNew keeps the too new events to send them again once they are less than 2 hours ahead, the events sent too
+// many times or beyond the reqEventsLimit kept are written to the dead-letter file.
+func (p *pusher) deferTooNew(events []*cloudwatchlogs.InputLogEvent) {
+	now := time.Now()
+	var discarded []*cloudwatchlogs.InputLogEvent
+	for _, e := range events {
+		attempts := p.tooNewAttempts[e] + 1
+		if attempts >= maxTooNewAttempts || len(p.tooNew) >= reqEventsLimit {
+			discarded = append(discarded, e)
+			continue
+		}
+		retryAt := now.Add(tooNewRetryInterval)
+		if acceptedAt := time.Unix(0, *e.Timestamp*int64(time.Millisecond)).Add(-maxFutureEventAge); acceptedAt.After(retryAt) {
+			retryAt = acceptedAt
+		}
+		p.tooNew = append(p.tooNew, tooNewEvent{event: e, retryAt: retryAt, attempts: attempts})
+	}
+	if len(discarded) > 0 {
+		p.Log.Warnf("%d log events for log '%s/%s' are still too new, they will not be sent again", len(discarded), p.Group, p.Stream)
+		p.writeDeadLetter(rejectedTooNew, discarded)
+	}
+}
+
+// requeueTooNew adds the too new events due to be sent again to the batch.
+func (p *pusher) requeueTooNew() {
+	if len(p.tooNew) == 0 {
+		return
+	}
+	now := time.Now()
+	pending := p.tooNew[:0]
+	var due []tooNewEvent
+	for _, e := range p.tooNew {
+		if e.retryAt.After(now) {
+			pending = append(pending, e)
+		} else {
+			due = append(due, e)
+		}
+	}
+	p.tooNew = pending
+	for _, e := range due {
+		p.tooNewAttempts[e.event] = e.attempts
+		p.addToBatch(e.event, func() {})
+	}
+}
+
+// discardTooNew writes the too new events not sent again yet to the dead-letter file when the pusher stops.
+func (p *pusher) discardTooNew() {
+	if len(p.tooNew) == 0 {
+		return
+	}
+	events := make([]*cloudwatchlogs.InputLogEvent, 0, len(p.tooNew))
+	for _, e := range p.tooNew {
+		events = append(events, e.event)
+	}
+	p.tooNew = nil
+	p.Log.Warnf("%d too new log events for log '%s/%s' were not sent again before the stop", len(events), p.Group, p.Stream)
+	p.writeDeadLetter(rejectedTooNew, events)
+}
+
+// writeDeadLetter writes the rejected events to the dead-letter file, if any.
+func (p *pusher) writeDeadLetter(reason string, events []*cloudwatchlogs.InputLogEvent) {
+	if p.deadLetter == nil {
+		return
+	}
+	if err := p.deadLetter.write(p.Target, reason, events); err != nil {
+		p.Log.Errorf("Unable to write %d rejected log events for log '%s/%s' to %s: %v", len(events), p.Group, p.Stream, p.deadLetter.path, err)
+		return
+	}
+	p.addStats("deadLetterLogEvents", float64(len(events)))
+}
+
+// recordFailure records the failure of the request in the health of the pipeline, with the log group and stream.
+func (p *pusher) recordFailure(err error) {
+	health.RecordFailure(pipelineName, fmt.Errorf("%v/%v: %v", p.Group, p.Stream, err))
+}
+
+// retryWait returns the backoff before the retry n of a request, at most maxBackoff, or a minute when it isn't set.
+func retryWait(n int, maxBackoff time.Duration) time.Duration {
+	if maxBackoff <= 0 {
+		maxBackoff = defaultMaxBackoff
+	}
+	d := maxBackoff
+	if n <= 5 {
+		if backoff := retryBaseWait * time.Duration(1<<int64(n)); backoff < maxBackoff {
+			d = backoff
+		}
 	}
 	return time.Duration(seededRand.Int63n(int64(d/2)) + int64(d/2))
 }
@@ -365,19 +554,15 @@ func (p *pusher) createLogGroupAndStream() error {
 	return err
 }
 
+// putRetentionPolicy applies the retention of the target, the retentionReconciler keeps it applied afterwards.
 func (p *pusher) putRetentionPolicy() {
 	if p.Retention > 0 {
-		i := aws.Int64(int64(p.Retention))
-		putRetentionInput := &cloudwatchlogs.PutRetentionPolicyInput{
-			LogGroupName:    &p.Group,
-			RetentionInDays: i,
-		}
-		_, err := p.Service.PutRetentionPolicy(putRetentionInput)
+		err := putRetentionPolicy(p.Service, p.Group, p.Retention)
 		if err != nil {
 			// since this gets called both before we start pushing logs, and after we first attempt
 			// to push a log to a non-existent log group, we don't want to dirty the log with an error
 			// if the error is that the log group doesn't exist (yet).
-			if awsErr, ok := err.(awserr.Error); ok && awsErr.Code() == cloudwatchlogs.ErrCodeResourceNotFoundException {
+			if isResourceNotFound(err) {
 				p.Log.Debugf("Log group %v not created yet: %v", p.Group, err)
 			} else {
 				p.Log.Errorf("Unable to put retention policy for log group %v: %v ", p.Group, err)
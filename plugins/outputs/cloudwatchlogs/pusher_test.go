@@ -5,20 +5,26 @@ package cloudwatchlogs
 
 import (
 	"bytes"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"log"
 	"os"
+	"path/filepath"
 	"strings"
 	"sync"
 	"testing"
 	"time"
 
+	"github.com/aws/amazon-cloudwatch-agent/internal/flush"
+	"github.com/aws/amazon-cloudwatch-agent/internal/retryer"
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/service/cloudwatchlogs"
 	"github.com/influxdata/telegraf/models"
+	"github.com/influxdata/telegraf/selfstat"
 )
 
 var wg sync.WaitGroup
@@ -28,6 +34,7 @@ type svcMock struct {
 	clg func(input *cloudwatchlogs.CreateLogGroupInput) (*cloudwatchlogs.CreateLogGroupOutput, error)
 	cls func(input *cloudwatchlogs.CreateLogStreamInput) (*cloudwatchlogs.CreateLogStreamOutput, error)
 	prp func(input *cloudwatchlogs.PutRetentionPolicyInput) (*cloudwatchlogs.PutRetentionPolicyOutput, error)
+	dlg func(input *cloudwatchlogs.DescribeLogGroupsInput) (*cloudwatchlogs.DescribeLogGroupsOutput, error)
 }
 
 func (s *svcMock) PutLogEvents(in *cloudwatchlogs.PutLogEventsInput) (*cloudwatchlogs.PutLogEventsOutput, error) {
@@ -55,6 +62,13 @@ func (s *svcMock) PutRetentionPolicy(in *cloudwatchlogs.PutRetentionPolicyInput)
 	return nil, nil
 }
 
+func (s *svcMock) DescribeLogGroups(in *cloudwatchlogs.DescribeLogGroupsInput) (*cloudwatchlogs.DescribeLogGroupsOutput, error) {
+	if s.dlg != nil {
+		return s.dlg(in)
+	}
+	return &cloudwatchlogs.DescribeLogGroupsOutput{}, nil
+}
+
 func TestNewPusher(t *testing.T) {
 	var s svcMock
 	stop, p := testPreparation(-1, &s, time.Second, maxRetryTimeout)
@@ -130,6 +144,32 @@ func TestAddSingleEvent(t *testing.T) {
 	wg.Wait()
 }
 
+func TestFlushRequestSendsAtOnce(t *testing.T) {
+	var s svcMock
+	sent := make(chan int, 1)
+	s.ple = func(in *cloudwatchlogs.PutLogEventsInput) (*cloudwatchlogs.PutLogEventsOutput, error) {
+		sent <- len(in.LogEvents)
+		return &cloudwatchlogs.PutLogEventsOutput{}, nil
+	}
+
+	stop, p := testPreparation(-1, &s, 1*time.Hour, maxRetryTimeout)
+	p.AddEvent(evtMock{"MSG", time.Now(), nil})
+	time.Sleep(10 * time.Millisecond)
+	flush.Request()
+
+	select {
+	case n := <-sent:
+		if n != 1 {
+			t.Errorf("PutLogEvents called with %d events, expecting 1", n)
+		}
+	case <-time.After(time.Second):
+		t.Errorf("PutLogEvents has not been called after the flush request.")
+	}
+
+	close(stop)
+	wg.Wait()
+}
+
 func TestStopPusherWouldDoFinalSend(t *testing.T) {
 	var s svcMock
 	called := false
@@ -633,43 +673,90 @@ func TestLogRejectedLogEntryInfo(t *testing.T) {
 	var s svcMock
 	nst := "NEXT_SEQ_TOKEN"
 
+	var calls []*cloudwatchlogs.PutLogEventsInput
 	s.ple = func(in *cloudwatchlogs.PutLogEventsInput) (*cloudwatchlogs.PutLogEventsOutput, error) {
+		calls = append(calls, &cloudwatchlogs.PutLogEventsInput{LogEvents: append([]*cloudwatchlogs.InputLogEvent{}, in.LogEvents...)})
+		info := &cloudwatchlogs.RejectedLogEventsInfo{TooNewLogEventStartIndex: aws.Int64(0)}
+		if len(calls) == 1 {
+			info = &cloudwatchlogs.RejectedLogEventsInfo{
+				ExpiredLogEventEndIndex:  aws.Int64(1),
+				TooOldLogEventEndIndex:   aws.Int64(3),
+				TooNewLogEventStartIndex: aws.Int64(8),
+			}
+		}
 		return &cloudwatchlogs.PutLogEventsOutput{
-			NextSequenceToken: &nst,
-			RejectedLogEventsInfo: &cloudwatchlogs.RejectedLogEventsInfo{
-				TooOldLogEventEndIndex:   aws.Int64(100),
-				TooNewLogEventStartIndex: aws.Int64(200),
-				ExpiredLogEventEndIndex:  aws.Int64(300),
-			},
+			NextSequenceToken:     &nst,
+			RejectedLogEventsInfo: info,
 		}, nil
 	}
 
+	dir, err := ioutil.TempDir("", "rejected")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "rejected.json")
+
 	var logbuf bytes.Buffer
 	log.SetOutput(io.MultiWriter(&logbuf, os.Stdout))
 
 	stop, p := testPreparation(-1, &s, 1*time.Hour, maxRetryTimeout)
-	p.AddEvent(evtMock{"msg", time.Now(), nil})
+	p.deadLetter = newDeadLetterFile(path)
+	start := time.Now().Add(-time.Minute)
+	for i := 0; i < 10; i++ {
+		p.AddEvent(evtMock{fmt.Sprintf("msg %d", i), start.Add(time.Duration(i) * time.Second), nil})
+	}
 	time.Sleep(10 * time.Millisecond)
 	p.send()
 
 	loglines := strings.Split(strings.TrimSpace(logbuf.String()), "\n")
 	if len(loglines) != 4 { // 3 warnings and 1 debug
-		t.Errorf("Expecting 3 error logs, but %d received", len(loglines))
+		t.Errorf("Expecting 3 warning logs, but %d received", len(loglines))
 	}
-	logline := loglines[0]
-	if !strings.Contains(logline, "W!") || !strings.Contains(logline, "100") {
-		t.Errorf("Expecting error log events too old, but received '%s' in the log", logbuf.String())
+	for i, expected := range []string{"1 log events for log 'G/S' are expired", "2 log events for log 'G/S' are too old", "2 log events for log 'G/S' are too new"} {
+		if !strings.Contains(loglines[i], "W!") || !strings.Contains(loglines[i], expected) {
+			t.Errorf("Expecting warning '%s', but received '%s' in the log", expected, logbuf.String())
+		}
 	}
-	logline = loglines[1]
-	if !strings.Contains(logline, "W!") || !strings.Contains(logline, "200") {
-		t.Errorf("Expecting error log events too new, but received '%s' in the log", logbuf.String())
+	log.SetOutput(os.Stderr)
+
+	// the too new events are sent again until they are sent too many times
+	if len(p.tooNew) != 2 {
+		t.Fatalf("Expecting 2 too new events waiting to be sent again, but %d are", len(p.tooNew))
 	}
-	logline = loglines[2]
-	if !strings.Contains(logline, "W!") || !strings.Contains(logline, "300") {
-		t.Errorf("Expecting error log events expired, but received '%s' in the log", logbuf.String())
+	for i := 1; i < maxTooNewAttempts; i++ {
+		for j := range p.tooNew {
+			p.tooNew[j].retryAt = time.Time{}
+		}
+		p.requeueTooNew()
+		p.send()
+		if len(calls) != i+1 || len(calls[i].LogEvents) != 2 || *calls[i].LogEvents[0].Message != "msg 8" {
+			t.Fatalf("Expecting the too new events to be sent again, but %v were sent", calls)
+		}
+	}
+	if len(p.tooNew) != 0 || len(p.tooNewAttempts) != 0 {
+		t.Errorf("Expecting no too new event left, but %d are", len(p.tooNew))
 	}
 
-	log.SetOutput(os.Stderr)
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var reasons []string
+	for _, line := range strings.Split(strings.TrimSpace(string(b)), "\n") {
+		var record deadLetterRecord
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			t.Fatalf("Unexpected dead-letter record '%s': %v", line, err)
+		}
+		if record.LogGroupName != "G" || record.LogStreamName != "S" {
+			t.Errorf("Unexpected target of the dead-letter record '%s'", line)
+		}
+		reasons = append(reasons, record.Reason+" "+record.Message)
+	}
+	expected := []string{"expired msg 0", "too_old msg 1", "too_old msg 2", "too_new msg 8", "too_new msg 9"}
+	if strings.Join(reasons, ",") != strings.Join(expected, ",") {
+		t.Errorf("Expecting the dead-letter records %v, but got %v", expected, reasons)
+	}
 
 	close(stop)
 	wg.Wait()
@@ -806,8 +893,47 @@ func TestResendWouldStopAfterExhaustedRetries(t *testing.T) {
 	wg.Wait()
 }
 
+func TestResendWouldStopAfterMaxRetries(t *testing.T) {
+	var s svcMock
+	var cnt int
+	s.ple = func(in *cloudwatchlogs.PutLogEventsInput) (*cloudwatchlogs.PutLogEventsOutput, error) {
+		cnt++
+		return nil, &cloudwatchlogs.ServiceUnavailableException{}
+	}
+	stats := retryer.NewRequestStats("cloudwatchlogs_test", opPutLogEvents)
+	retried := selfstat.Register("cloudwatchlogs_test", "requests_retried", map[string]string{"operation": opPutLogEvents})
+	before := retried.Get()
+
+	stop := make(chan struct{})
+	retries := retryPolicy{maxRetries: 3, maxBackoff: 10 * time.Millisecond, requestStats: stats}
+	p := NewPusher(Target{"G", "S", -1}, &s, 10*time.Millisecond, maxRetryTimeout, models.NewLogger("cloudwatchlogs", "test", ""), stop, &wg, nil, retries)
+	p.AddEvent(evtMock{"msg", time.Now(), nil})
+	time.Sleep(500 * time.Millisecond)
+
+	if cnt != 3 {
+		t.Errorf("Expecting the request to be sent 3 times, but it was sent %v times", cnt)
+	}
+	if retried.Get()-before != 2 {
+		t.Errorf("Expecting 2 retries to be counted, but %v were counted", retried.Get()-before)
+	}
+
+	close(stop)
+	wg.Wait()
+}
+
+func TestRetryWait(t *testing.T) {
+	for n, expected := range []time.Duration{200 * time.Millisecond, 400 * time.Millisecond, 800 * time.Millisecond} {
+		if wait := retryWait(n, 500*time.Millisecond); wait > 500*time.Millisecond || (n < 2 && (wait < expected/2 || wait > expected)) {
+			t.Errorf("Unexpected wait %v before the retry %v with a backoff of at most 500ms", wait, n)
+		}
+	}
+	if wait := retryWait(10, 0); wait < 30*time.Second || wait > time.Minute {
+		t.Errorf("Expecting the wait before the retry 10 to be at most a minute by default, but it is %v", wait)
+	}
+}
+
 func testPreparation(retention int, s *svcMock, flushTimeout time.Duration, retryDuration time.Duration) (chan struct{}, *pusher) {
 	stop := make(chan struct{})
-	p := NewPusher(Target{"G", "S", retention}, s, flushTimeout, retryDuration, models.NewLogger("cloudwatchlogs", "test", ""), stop, &wg)
+	p := NewPusher(Target{"G", "S", retention}, s, flushTimeout, retryDuration, models.NewLogger("cloudwatchlogs", "test", ""), stop, &wg, nil, retryPolicy{})
 	return stop, p
 }
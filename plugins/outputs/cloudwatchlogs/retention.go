@@ -0,0 +1,176 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package cloudwatchlogs
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/aws/amazon-cloudwatch-agent/internal/audit"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/cloudwatchlogs"
+	"github.com/influxdata/telegraf"
+)
+
+const defaultRetentionCheckInterval = time.Hour
+
+// retentionReconciler periodically compares the retention of the log groups with their retention_in_days, so a
+// retention changed out of the agent, e.g. in the console, is detected instead of being only applied when the
+// stream is created. The drifts are logged and recorded as audit events, and the configured retention is restored
+// when restore is set.
+type retentionReconciler struct {
+	interval time.Duration
+	restore  bool
+	log      telegraf.Logger
+
+	mu     sync.Mutex
+	groups map[string]*retentionGroup
+}
+
+// retentionGroup is a log group with a configured retention, drifted is the retention last reported as a drift so
+// an unchanged drift is only reported once.
+type retentionGroup struct {
+	service   CloudWatchLogsService
+	retention int
+	drifted   *int64
+}
+
+func newRetentionReconciler(interval time.Duration, restore bool, log telegraf.Logger) *retentionReconciler {
+	if interval <= 0 {
+		interval = defaultRetentionCheckInterval
+	}
+	return &retentionReconciler{
+		interval: interval,
+		restore:  restore,
+		log:      log,
+		groups:   map[string]*retentionGroup{},
+	}
+}
+
+// register adds the log group to the reconciled ones, the groups without retention are left as they are.
+func (r *retentionReconciler) register(group string, retention int, service CloudWatchLogsService) {
+	if retention <= 0 {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.groups[group]; !ok {
+		r.groups[group] = &retentionGroup{service: service, retention: retention}
+	}
+}
+
+func (r *retentionReconciler) run(stop <-chan struct{}, wg *sync.WaitGroup) {
+	defer wg.Done()
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			r.reconcile()
+		case <-stop:
+			return
+		}
+	}
+}
+
+// reconcile checks the retention of each registered log group.
+func (r *retentionReconciler) reconcile() {
+	r.mu.Lock()
+	names := make([]string, 0, len(r.groups))
+	for name := range r.groups {
+		names = append(names, name)
+	}
+	r.mu.Unlock()
+	sort.Strings(names)
+
+	for _, name := range names {
+		r.mu.Lock()
+		g := r.groups[name]
+		r.mu.Unlock()
+		r.check(name, g)
+	}
+}
+
+func (r *retentionReconciler) check(name string, g *retentionGroup) {
+	actual, found, err := describeRetention(g.service, name)
+	if err != nil {
+		r.log.Errorf("Unable to check the retention policy of log group %v: %v", name, err)
+		return
+	}
+	if !found {
+		// the group is created with the first events and its retention is applied then
+		r.log.Debugf("Log group %v not created yet, its retention policy is not checked", name)
+		return
+	}
+	if actual == int64(g.retention) {
+		g.drifted = nil
+		return
+	}
+
+	restored := false
+	if r.restore {
+		if err := putRetentionPolicy(g.service, name, g.retention); err != nil {
+			r.log.Errorf("Unable to restore the retention policy of log group %v: %v", name, err)
+		} else {
+			restored = true
+		}
+	}
+	if !restored && g.drifted != nil && *g.drifted == actual {
+		// already reported
+		return
+	}
+	g.drifted = nil
+	if !restored {
+		g.drifted = &actual
+	}
+	audit.RetentionDrifted(name, g.retention, actual, restored)
+	if restored {
+		r.log.Warnf("The retention of log group %v was changed to %v out of the agent, restored retention_in_days %v", name, retentionString(actual), g.retention)
+	} else {
+		r.log.Warnf("The retention of log group %v was changed to %v out of the agent, retention_in_days is %v", name, retentionString(actual), g.retention)
+	}
+}
+
+// describeRetention returns the retention in days of the log group, 0 when its events never expire.
+func describeRetention(service CloudWatchLogsService, group string) (int64, bool, error) {
+	input := &cloudwatchlogs.DescribeLogGroupsInput{LogGroupNamePrefix: aws.String(group)}
+	for {
+		output, err := service.DescribeLogGroups(input)
+		if err != nil {
+			return 0, false, err
+		}
+		for _, lg := range output.LogGroups {
+			if aws.StringValue(lg.LogGroupName) == group {
+				return aws.Int64Value(lg.RetentionInDays), true, nil
+			}
+		}
+		if output.NextToken == nil {
+			return 0, false, nil
+		}
+		input.NextToken = output.NextToken
+	}
+}
+
+func putRetentionPolicy(service CloudWatchLogsService, group string, retention int) error {
+	_, err := service.PutRetentionPolicy(&cloudwatchlogs.PutRetentionPolicyInput{
+		LogGroupName:    aws.String(group),
+		RetentionInDays: aws.Int64(int64(retention)),
+	})
+	return err
+}
+
+func isResourceNotFound(err error) bool {
+	awsErr, ok := err.(awserr.Error)
+	return ok && awsErr.Code() == cloudwatchlogs.ErrCodeResourceNotFoundException
+}
+
+func retentionString(days int64) string {
+	if days == 0 {
+		return "never expire"
+	}
+	return fmt.Sprintf("%d days", days)
+}
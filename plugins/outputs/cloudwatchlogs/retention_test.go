@@ -0,0 +1,97 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package cloudwatchlogs
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cloudwatchlogs"
+	"github.com/influxdata/telegraf/models"
+	"github.com/stretchr/testify/assert"
+)
+
+// retentionMock is a log group of the service, with its retention and the pages of DescribeLogGroups.
+type retentionMock struct {
+	svcMock
+	retention *int64
+	describes int
+	puts      []int64
+}
+
+func newRetentionMock(retention *int64) *retentionMock {
+	m := &retentionMock{retention: retention}
+	m.dlg = func(in *cloudwatchlogs.DescribeLogGroupsInput) (*cloudwatchlogs.DescribeLogGroupsOutput, error) {
+		m.describes++
+		if in.NextToken == nil {
+			// the prefix matches another group on the first page
+			return &cloudwatchlogs.DescribeLogGroupsOutput{
+				LogGroups: []*cloudwatchlogs.LogGroup{{LogGroupName: aws.String("G-other"), RetentionInDays: aws.Int64(1)}},
+				NextToken: aws.String("next"),
+			}, nil
+		}
+		return &cloudwatchlogs.DescribeLogGroupsOutput{
+			LogGroups: []*cloudwatchlogs.LogGroup{{LogGroupName: aws.String("G"), RetentionInDays: m.retention}},
+		}, nil
+	}
+	m.prp = func(in *cloudwatchlogs.PutRetentionPolicyInput) (*cloudwatchlogs.PutRetentionPolicyOutput, error) {
+		m.puts = append(m.puts, *in.RetentionInDays)
+		m.retention = in.RetentionInDays
+		return nil, nil
+	}
+	return m
+}
+
+func TestRetentionReconciler_InSync(t *testing.T) {
+	m := newRetentionMock(aws.Int64(7))
+	r := newRetentionReconciler(0, true, models.NewLogger("cloudwatchlogs", "test", ""))
+	assert.Equal(t, defaultRetentionCheckInterval, r.interval)
+	r.register("G", 7, m)
+	r.reconcile()
+	assert.Equal(t, 2, m.describes)
+	assert.Empty(t, m.puts)
+}
+
+func TestRetentionReconciler_DriftDetected(t *testing.T) {
+	m := newRetentionMock(aws.Int64(30))
+	r := newRetentionReconciler(0, false, models.NewLogger("cloudwatchlogs", "test", ""))
+	r.register("G", 7, m)
+	r.reconcile()
+	assert.Empty(t, m.puts)
+	assert.Equal(t, int64(30), *r.groups["G"].drifted)
+
+	// the retention is changed back in the console
+	m.retention = aws.Int64(7)
+	r.reconcile()
+	assert.Nil(t, r.groups["G"].drifted)
+}
+
+func TestRetentionReconciler_DriftRestored(t *testing.T) {
+	// the retention was removed, the events never expire
+	m := newRetentionMock(nil)
+	r := newRetentionReconciler(0, true, models.NewLogger("cloudwatchlogs", "test", ""))
+	r.register("G", 7, m)
+	r.reconcile()
+	assert.Equal(t, []int64{7}, m.puts)
+	assert.Nil(t, r.groups["G"].drifted)
+
+	r.reconcile()
+	assert.Equal(t, []int64{7}, m.puts)
+}
+
+func TestRetentionReconciler_GroupNotCreated(t *testing.T) {
+	var s svcMock
+	r := newRetentionReconciler(0, true, models.NewLogger("cloudwatchlogs", "test", ""))
+	r.register("G", 7, &s)
+	// the groups without retention aren't reconciled
+	r.register("H", -1, &s)
+	assert.Len(t, r.groups, 1)
+	prpc := 0
+	s.prp = func(in *cloudwatchlogs.PutRetentionPolicyInput) (*cloudwatchlogs.PutRetentionPolicyOutput, error) {
+		prpc++
+		return nil, nil
+	}
+	r.reconcile()
+	assert.Equal(t, 0, prpc)
+}
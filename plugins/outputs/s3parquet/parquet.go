@@ -0,0 +1,57 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package s3parquet
+
+import (
+	"bytes"
+	"errors"
+
+	"github.com/xitongsys/parquet-go/parquet"
+	"github.com/xitongsys/parquet-go/source"
+	"github.com/xitongsys/parquet-go/writer"
+)
+
+// row is a datapoint of a metric, the values of a single datapoint are reported as a statistic set of 1 sample.
+type row struct {
+	Timestamp   int64   `parquet:"name=timestamp, type=TIMESTAMP_MILLIS"` // milliseconds since epoch
+	MetricName  string  `parquet:"name=metric_name, type=UTF8, encoding=PLAIN_DICTIONARY"`
+	Dimensions  string  `parquet:"name=dimensions, type=UTF8, encoding=PLAIN_DICTIONARY"` // json object of the dimensions, sorted by name
+	Value       float64 `parquet:"name=value, type=DOUBLE"`
+	SampleCount float64 `parquet:"name=sample_count, type=DOUBLE"`
+	Sum         float64 `parquet:"name=sum, type=DOUBLE"`
+	Minimum     float64 `parquet:"name=minimum, type=DOUBLE"`
+	Maximum     float64 `parquet:"name=maximum, type=DOUBLE"`
+}
+
+var errWriteOnly = errors.New("the parquet file is write only")
+
+// bufferFile is the in memory source.ParquetFile the files are encoded to before the upload, it is only written to.
+type bufferFile struct {
+	bytes.Buffer
+}
+
+func (f *bufferFile) Seek(int64, int) (int64, error)            { return 0, errWriteOnly }
+func (f *bufferFile) Read([]byte) (int, error)                  { return 0, errWriteOnly }
+func (f *bufferFile) Close() error                              { return nil }
+func (f *bufferFile) Open(string) (source.ParquetFile, error)   { return nil, errWriteOnly }
+func (f *bufferFile) Create(string) (source.ParquetFile, error) { return nil, errWriteOnly }
+
+// encodeParquet returns the content of a parquet file holding the rows.
+func encodeParquet(rows []row, codec parquet.CompressionCodec) ([]byte, error) {
+	file := &bufferFile{}
+	w, err := writer.NewParquetWriter(file, new(row), 1)
+	if err != nil {
+		return nil, err
+	}
+	w.CompressionType = codec
+	for i := range rows {
+		if err := w.Write(rows[i]); err != nil {
+			return nil, err
+		}
+	}
+	if err := w.WriteStop(); err != nil {
+		return nil, err
+	}
+	return file.Bytes(), nil
+}
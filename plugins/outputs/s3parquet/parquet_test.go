@@ -0,0 +1,73 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package s3parquet
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+
+	"github.com/apache/thrift/lib/go/thrift"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xitongsys/parquet-go/parquet"
+	"github.com/xitongsys/parquet-go/reader"
+	"github.com/xitongsys/parquet-go/source"
+)
+
+// readerFile is a source.ParquetFile reading the content of an encoded file.
+type readerFile struct {
+	*bytes.Reader
+	content []byte
+}
+
+func newReaderFile(content []byte) *readerFile {
+	return &readerFile{Reader: bytes.NewReader(content), content: content}
+}
+
+func (f *readerFile) Write([]byte) (int, error)                 { return 0, errWriteOnly }
+func (f *readerFile) Close() error                              { return nil }
+func (f *readerFile) Open(string) (source.ParquetFile, error)   { return newReaderFile(f.content), nil }
+func (f *readerFile) Create(string) (source.ParquetFile, error) { return nil, errWriteOnly }
+
+// readParquet returns the rows of the file and the metadata of its footer, as written in the file.
+func readParquet(t *testing.T, content []byte) ([]row, *parquet.FileMetaData) {
+	r, err := reader.NewParquetReader(newReaderFile(content), new(row), 1)
+	require.NoError(t, err)
+	defer r.ReadStop()
+	rows := make([]row, r.GetNumRows())
+	require.NoError(t, r.Read(&rows))
+
+	// the reader renames the schema of its footer to the fields of the row
+	footerLength := int(binary.LittleEndian.Uint32(content[len(content)-8:]))
+	d := thrift.NewTDeserializer()
+	d.Protocol = thrift.NewTCompactProtocolFactory().GetProtocol(d.Transport)
+	footer := parquet.NewFileMetaData()
+	require.NoError(t, d.Read(footer, content[len(content)-8-footerLength:len(content)-8]))
+	return rows, footer
+}
+
+func TestEncodeParquet(t *testing.T) {
+	rows := []row{
+		{Timestamp: 1614697445000, MetricName: "cpu_usage_idle", Dimensions: `{"cpu":"cpu-total"}`, Value: 98.5, SampleCount: 1, Sum: 98.5, Minimum: 98.5, Maximum: 98.5},
+		{Timestamp: 1614697505000, MetricName: "latency", Dimensions: `{}`, Value: 2, SampleCount: 3, Sum: 6, Minimum: 1, Maximum: 3},
+	}
+	for _, codec := range []parquet.CompressionCodec{parquet.CompressionCodec_UNCOMPRESSED, parquet.CompressionCodec_GZIP} {
+		content, err := encodeParquet(rows, codec)
+		require.NoError(t, err)
+		decoded, footer := readParquet(t, content)
+		assert.Equal(t, rows, decoded)
+
+		assert.Equal(t, int64(2), footer.NumRows)
+		var names []string
+		for _, element := range footer.Schema[1:] {
+			names = append(names, element.Name)
+		}
+		assert.Equal(t, []string{"timestamp", "metric_name", "dimensions", "value", "sample_count", "sum", "minimum", "maximum"}, names)
+		assert.Equal(t, parquet.ConvertedType_TIMESTAMP_MILLIS, *footer.Schema[1].ConvertedType)
+		for _, chunk := range footer.RowGroups[0].Columns {
+			assert.Equal(t, codec, chunk.MetaData.Codec)
+		}
+	}
+}
@@ -0,0 +1,298 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package s3parquet
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/amazon-cloudwatch-agent/cfg/agentinfo"
+	configaws "github.com/aws/amazon-cloudwatch-agent/cfg/aws"
+	"github.com/aws/amazon-cloudwatch-agent/handlers"
+	"github.com/aws/amazon-cloudwatch-agent/internal/audit"
+	"github.com/aws/amazon-cloudwatch-agent/internal/metricscommon"
+	"github.com/aws/amazon-cloudwatch-agent/metric/distribution"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/plugins/outputs"
+	"github.com/xitongsys/parquet-go/parquet"
+)
+
+const (
+	defaultNamespace       = "CWAgent"
+	defaultMaxRowsPerFile  = 100000
+	defaultMaxPendingFiles = 24
+	compressionGzip        = "gzip"
+	compressionNone        = "none"
+	internalTagPrefix      = "aws:"
+)
+
+var sampleConfig = `
+  ## Amazon REGION of the bucket
+  region = "us-east-1"
+
+  ## Amazon Credentials, loaded in the same order as the cloudwatch output
+  #access_key = ""
+  #secret_key = ""
+  #token = ""
+  #role_arn = ""
+  #profile = ""
+  #shared_credential_file = ""
+
+  ## The bucket and the key prefix of the parquet files. The files are partitioned by namespace and date, e.g.
+  ## <prefix>/namespace=CWAgent/date=2021-03-02/<host>-20210302T1500Z-0.parquet
+  bucket = "my-metrics-bucket"
+  # prefix = "cloudwatch-agent"
+
+  ## The namespace of the metrics, the same as the cloudwatch output
+  namespace = "CWAgent"
+
+  ## The compression of the column data, "gzip" or "none"
+  # compression = "gzip"
+
+  ## A file is written every hour, or as soon as an hour holds this number of datapoints
+  # max_rows_per_file = 100000
+
+  ## The number of files kept in memory while the uploads fail, the oldest file is dropped beyond
+  # max_pending_files = 24
+`
+
+// putObjectAPI is the subset of the s3 client used to upload the files.
+type putObjectAPI interface {
+	PutObject(input *s3.PutObjectInput) (*s3.PutObjectOutput, error)
+}
+
+type S3Parquet struct {
+	Region           string `toml:"region"`
+	EndpointOverride string `toml:"endpoint_override"`
+	AccessKey        string `toml:"access_key"`
+	SecretKey        string `toml:"secret_key"`
+	RoleARN          string `toml:"role_arn"`
+	Profile          string `toml:"profile"`
+	Filename         string `toml:"shared_credential_file"`
+	Token            string `toml:"token"`
+	Bucket           string `toml:"bucket"`
+	Prefix           string `toml:"prefix"`
+	Namespace        string `toml:"namespace"`
+	Compression      string `toml:"compression"`
+	MaxRowsPerFile   int    `toml:"max_rows_per_file"`
+	MaxPendingFiles  int    `toml:"max_pending_files"`
+
+	Log telegraf.Logger `toml:"-"`
+
+	svc      putObjectAPI
+	codec    parquet.CompressionCodec
+	hostname string
+	now      func() time.Time
+
+	mu      sync.Mutex
+	hours   map[int64][]row // the buffered rows by the start of their hour
+	pending []*parquetFile  // the files sealed and not uploaded yet
+	seq     int
+}
+
+type parquetFile struct {
+	key  string
+	rows []row
+}
+
+func (p *S3Parquet) SampleConfig() string {
+	return sampleConfig
+}
+
+func (p *S3Parquet) Description() string {
+	return "Configuration for exporting the metrics to S3 as hourly parquet files"
+}
+
+func (p *S3Parquet) Connect() error {
+	if p.Bucket == "" {
+		return fmt.Errorf("s3_parquet: bucket is required")
+	}
+	switch p.Compression {
+	case "", compressionGzip:
+		p.codec = parquet.CompressionCodec_GZIP
+	case compressionNone:
+		p.codec = parquet.CompressionCodec_UNCOMPRESSED
+	default:
+		return fmt.Errorf("s3_parquet: compression %q is invalid, must be %q or %q", p.Compression, compressionGzip, compressionNone)
+	}
+	if p.Namespace == "" {
+		p.Namespace = defaultNamespace
+	}
+	if p.MaxRowsPerFile <= 0 {
+		p.MaxRowsPerFile = defaultMaxRowsPerFile
+	}
+	if p.MaxPendingFiles <= 0 {
+		p.MaxPendingFiles = defaultMaxPendingFiles
+	}
+	if p.now == nil {
+		p.now = time.Now
+	}
+	p.hostname, _ = os.Hostname()
+	p.hours = map[int64][]row{}
+
+	if p.svc == nil {
+		credentialConfig := &configaws.CredentialConfig{
+			Region:    p.Region,
+			AccessKey: p.AccessKey,
+			SecretKey: p.SecretKey,
+			RoleARN:   p.RoleARN,
+			Profile:   p.Profile,
+			Filename:  p.Filename,
+			Token:     p.Token,
+		}
+		svc := s3.New(
+			credentialConfig.Credentials(),
+			&aws.Config{
+				Endpoint: aws.String(p.EndpointOverride),
+				LogLevel: configaws.SDKLogLevel(),
+				Logger:   configaws.SDKLogger{},
+			})
+		svc.Handlers.Build.PushBackNamed(handlers.NewCustomHeaderHandler("User-Agent", agentinfo.UserAgent("")))
+		audit.EndpointConfigured("s3_parquet", p.Region, p.EndpointOverride)
+		p.svc = svc
+	}
+	return nil
+}
+
+// Write buffers the datapoints by hour, the hours that ended are written to S3. The metrics are never returned to
+// telegraf on failure to avoid buffering them twice, the files that failed to upload are retried on the next write.
+func (p *S3Parquet) Write(metrics []telegraf.Metric) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, m := range metrics {
+		hour := m.Time().UTC().Truncate(time.Hour).Unix()
+		p.hours[hour] = append(p.hours[hour], toRows(m)...)
+		if len(p.hours[hour]) >= p.MaxRowsPerFile {
+			p.seal(hour)
+		}
+	}
+	current := p.now().UTC().Truncate(time.Hour).Unix()
+	for _, hour := range p.sortedHours() {
+		if hour < current {
+			p.seal(hour)
+		}
+	}
+	p.upload()
+	return nil
+}
+
+// Close writes all the buffered datapoints, including the current hour.
+func (p *S3Parquet) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, hour := range p.sortedHours() {
+		p.seal(hour)
+	}
+	if err := p.upload(); err != nil {
+		return fmt.Errorf("s3_parquet: %d files could not be written to bucket %s: %v", len(p.pending), p.Bucket, err)
+	}
+	return nil
+}
+
+func (p *S3Parquet) sortedHours() []int64 {
+	hours := make([]int64, 0, len(p.hours))
+	for hour := range p.hours {
+		hours = append(hours, hour)
+	}
+	sort.Slice(hours, func(i, j int) bool { return hours[i] < hours[j] })
+	return hours
+}
+
+// seal moves the rows of the hour to a pending file.
+func (p *S3Parquet) seal(hour int64) {
+	rows := p.hours[hour]
+	delete(p.hours, hour)
+	if len(rows) == 0 {
+		return
+	}
+	p.pending = append(p.pending, &parquetFile{key: p.objectKey(time.Unix(hour, 0).UTC()), rows: rows})
+	p.seq++
+	if dropped := len(p.pending) - p.MaxPendingFiles; dropped > 0 {
+		for _, f := range p.pending[:dropped] {
+			p.Log.Errorf("Dropping %d datapoints of %s, the pending files exceed max_pending_files %d", len(f.rows), f.key, p.MaxPendingFiles)
+		}
+		p.pending = p.pending[dropped:]
+	}
+}
+
+// objectKey returns the partitioned key of a file of the hour, e.g.
+// prefix/namespace=CWAgent/date=2021-03-02/myhost-20210302T1500Z-3.parquet
+func (p *S3Parquet) objectKey(hour time.Time) string {
+	name := fmt.Sprintf("%s-%s-%d.parquet", p.hostname, hour.Format("20060102T1504Z"), p.seq)
+	return path.Join(p.Prefix, "namespace="+p.Namespace, "date="+hour.Format("2006-01-02"), name)
+}
+
+// upload writes the pending files in order and stops at the first failure.
+func (p *S3Parquet) upload() error {
+	for len(p.pending) > 0 {
+		f := p.pending[0]
+		content, err := encodeParquet(f.rows, p.codec)
+		if err != nil {
+			p.Log.Errorf("Dropping %d datapoints of %s, failed to encode the parquet file: %v", len(f.rows), f.key, err)
+			p.pending = p.pending[1:]
+			continue
+		}
+		_, err = p.svc.PutObject(&s3.PutObjectInput{
+			Bucket:      aws.String(p.Bucket),
+			Key:         aws.String(f.key),
+			Body:        bytes.NewReader(content),
+			ContentType: aws.String("application/octet-stream"),
+		})
+		if err != nil {
+			p.Log.Errorf("Failed to write %s to bucket %s, will retry on the next write: %v", f.key, p.Bucket, err)
+			return err
+		}
+		p.Log.Debugf("Wrote %d datapoints to s3://%s/%s", len(f.rows), p.Bucket, f.key)
+		p.pending = p.pending[1:]
+	}
+	return nil
+}
+
+// toRows returns a row for each numeric field of the metric, named as the cloudwatch output names them.
+func toRows(m telegraf.Metric) []row {
+	dimensions := map[string]string{}
+	for _, tag := range m.TagList() {
+		if !strings.HasPrefix(tag.Key, internalTagPrefix) {
+			dimensions[tag.Key] = tag.Value
+		}
+	}
+	encoded, _ := json.Marshal(dimensions)
+
+	timestamp := m.Time().UnixNano() / int64(time.Millisecond)
+	rows := make([]row, 0, len(m.FieldList()))
+	for _, f := range m.FieldList() {
+		r := row{Timestamp: timestamp, MetricName: metricscommon.MetricName(m.Name(), f.Key), Dimensions: string(encoded)}
+		switch v := f.Value.(type) {
+		case distribution.Distribution:
+			if v.SampleCount() == 0 {
+				continue
+			}
+			r.SampleCount, r.Sum, r.Minimum, r.Maximum = v.SampleCount(), v.Sum(), v.Minimum(), v.Maximum()
+			r.Value = r.Sum / r.SampleCount
+		default:
+			value, ok := metricscommon.ToFloat(f.Value)
+			if !ok {
+				continue
+			}
+			r.Value, r.SampleCount, r.Sum, r.Minimum, r.Maximum = value, 1, value, value, value
+		}
+		rows = append(rows, r)
+	}
+	return rows
+}
+
+func init() {
+	outputs.Add("s3_parquet", func() telegraf.Output {
+		return &S3Parquet{}
+	})
+}
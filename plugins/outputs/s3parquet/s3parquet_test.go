@@ -0,0 +1,147 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package s3parquet
+
+import (
+	"errors"
+	"io/ioutil"
+	"testing"
+	"time"
+
+	"github.com/aws/amazon-cloudwatch-agent/metric/distribution/seh1"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var errUpload = errors.New("service unavailable")
+
+type mockS3 struct {
+	objects map[string][]byte
+	keys    []string
+	err     error
+}
+
+func (m *mockS3) PutObject(input *s3.PutObjectInput) (*s3.PutObjectOutput, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	content, _ := ioutil.ReadAll(input.Body)
+	m.objects[aws.StringValue(input.Key)] = content
+	m.keys = append(m.keys, aws.StringValue(input.Key))
+	return &s3.PutObjectOutput{}, nil
+}
+
+func newTestOutput(t *testing.T, now time.Time) (*S3Parquet, *mockS3) {
+	svc := &mockS3{objects: map[string][]byte{}}
+	p := &S3Parquet{
+		Bucket:    "bucket",
+		Prefix:    "export",
+		Namespace: "MyApp",
+		Log:       testutil.Logger{},
+		svc:       svc,
+		now:       func() time.Time { return now },
+	}
+	assert.NoError(t, p.Connect())
+	p.hostname = "myhost"
+	return p, svc
+}
+
+func newMetric(name string, tags map[string]string, fields map[string]interface{}, ts time.Time) telegraf.Metric {
+	return testutil.MustMetric(name, tags, fields, ts)
+}
+
+func TestWriteHourlyFiles(t *testing.T) {
+	hour := time.Date(2021, 3, 2, 15, 0, 0, 0, time.UTC)
+	p, svc := newTestOutput(t, hour.Add(30*time.Minute))
+
+	assert.NoError(t, p.Write([]telegraf.Metric{
+		newMetric("cpu", map[string]string{"cpu": "cpu-total", "aws:StorageResolution": "true"},
+			map[string]interface{}{"usage_idle": 98.5, "usage_user": int64(1)}, hour.Add(-time.Minute)),
+		newMetric("mem", map[string]string{}, map[string]interface{}{"used_percent": 40.0, "state": "ok"}, hour.Add(time.Minute)),
+	}))
+	// only the previous hour is complete
+	assert.Equal(t, []string{"export/namespace=MyApp/date=2021-03-02/myhost-20210302T1400Z-0.parquet"}, svc.keys)
+	rows, _ := readParquet(t, svc.objects[svc.keys[0]])
+	timestamp := hour.Add(-time.Minute).UnixNano() / 1e6
+	assert.Equal(t, []row{
+		{Timestamp: timestamp, MetricName: "cpu_usage_idle", Dimensions: `{"cpu":"cpu-total"}`, Value: 98.5, SampleCount: 1, Sum: 98.5, Minimum: 98.5, Maximum: 98.5},
+		{Timestamp: timestamp, MetricName: "cpu_usage_user", Dimensions: `{"cpu":"cpu-total"}`, Value: 1, SampleCount: 1, Sum: 1, Minimum: 1, Maximum: 1},
+	}, rows)
+
+	assert.NoError(t, p.Close())
+	assert.Len(t, svc.keys, 2)
+	assert.Equal(t, "export/namespace=MyApp/date=2021-03-02/myhost-20210302T1500Z-1.parquet", svc.keys[1])
+	rows, _ = readParquet(t, svc.objects[svc.keys[1]])
+	// the string field is not numeric
+	require.Len(t, rows, 1)
+	assert.Equal(t, "mem_used_percent", rows[0].MetricName)
+}
+
+func TestWriteDistribution(t *testing.T) {
+	hour := time.Date(2021, 3, 2, 15, 0, 0, 0, time.UTC)
+	p, svc := newTestOutput(t, hour.Add(2*time.Hour))
+	dist := seh1.NewSEH1Distribution()
+	assert.NoError(t, dist.AddEntry(1, 1))
+	assert.NoError(t, dist.AddEntry(3, 2))
+
+	assert.NoError(t, p.Write([]telegraf.Metric{
+		newMetric("statsd_latency", map[string]string{}, map[string]interface{}{"value": dist}, hour),
+	}))
+	assert.Len(t, svc.keys, 1)
+	rows, _ := readParquet(t, svc.objects[svc.keys[0]])
+	assert.Equal(t, []row{
+		{Timestamp: hour.UnixNano() / 1e6, MetricName: "statsd_latency", Dimensions: `{}`, Value: 7.0 / 3, SampleCount: 3, Sum: 7, Minimum: 1, Maximum: 3},
+	}, rows)
+}
+
+func TestWriteMaxRowsPerFile(t *testing.T) {
+	hour := time.Date(2021, 3, 2, 15, 0, 0, 0, time.UTC)
+	p, svc := newTestOutput(t, hour)
+	p.MaxRowsPerFile = 2
+
+	for i := 0; i < 5; i++ {
+		assert.NoError(t, p.Write([]telegraf.Metric{
+			newMetric("mem", map[string]string{}, map[string]interface{}{"used_percent": float64(i)}, hour.Add(time.Duration(i)*time.Second)),
+		}))
+	}
+	assert.Len(t, svc.keys, 2)
+	assert.NoError(t, p.Close())
+	assert.Len(t, svc.keys, 3)
+	rows, _ := readParquet(t, svc.objects[svc.keys[2]])
+	require.Len(t, rows, 1)
+	assert.Equal(t, 4.0, rows[0].Value)
+}
+
+func TestWriteRetriesFailedUploads(t *testing.T) {
+	hour := time.Date(2021, 3, 2, 15, 0, 0, 0, time.UTC)
+	p, svc := newTestOutput(t, hour.Add(10*time.Hour))
+	p.MaxPendingFiles = 2
+	svc.err = errUpload
+
+	for i := 0; i < 3; i++ {
+		assert.NoError(t, p.Write([]telegraf.Metric{
+			newMetric("mem", map[string]string{}, map[string]interface{}{"used_percent": float64(i)}, hour.Add(time.Duration(i)*time.Hour)),
+		}))
+	}
+	assert.Empty(t, svc.keys)
+	// the oldest file is dropped beyond max_pending_files
+	assert.Len(t, p.pending, 2)
+	assert.Error(t, p.Close())
+
+	svc.err = nil
+	assert.NoError(t, p.Write(nil))
+	assert.Equal(t, []string{
+		"export/namespace=MyApp/date=2021-03-02/myhost-20210302T1600Z-1.parquet",
+		"export/namespace=MyApp/date=2021-03-02/myhost-20210302T1700Z-2.parquet",
+	}, svc.keys)
+}
+
+func TestConnectInvalidConfig(t *testing.T) {
+	assert.Error(t, (&S3Parquet{}).Connect())
+	assert.Error(t, (&S3Parquet{Bucket: "bucket", Compression: "snappy", svc: &mockS3{}}).Connect())
+}
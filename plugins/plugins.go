@@ -5,31 +5,64 @@ package plugins
 
 import (
 	//Enable cloudwatch-agent process plugins
+	_ "github.com/aws/amazon-cloudwatch-agent/plugins/processors/cpuaggregator"
+	_ "github.com/aws/amazon-cloudwatch-agent/plugins/processors/deadband"
 	_ "github.com/aws/amazon-cloudwatch-agent/plugins/processors/delta"
+	_ "github.com/aws/amazon-cloudwatch-agent/plugins/processors/derivedmetrics"
+	_ "github.com/aws/amazon-cloudwatch-agent/plugins/processors/dimensionfilter"
+	_ "github.com/aws/amazon-cloudwatch-agent/plugins/processors/dimensionlookup"
 	_ "github.com/aws/amazon-cloudwatch-agent/plugins/processors/ec2tagger"
 	_ "github.com/aws/amazon-cloudwatch-agent/plugins/processors/ecsdecorator"
 	_ "github.com/aws/amazon-cloudwatch-agent/plugins/processors/emfProcessor"
+	_ "github.com/aws/amazon-cloudwatch-agent/plugins/processors/instancenormalizer"
 	_ "github.com/aws/amazon-cloudwatch-agent/plugins/processors/k8sdecorator"
+	_ "github.com/aws/amazon-cloudwatch-agent/plugins/processors/recovery"
 
 	// Enabled parsers registry
 	_ "github.com/aws/amazon-cloudwatch-agent/plugins/parsers"
 
 	// Enabled cloudwatch-agent input plugins
+	_ "github.com/aws/amazon-cloudwatch-agent/plugins/inputs/agent_audit"
+	_ "github.com/aws/amazon-cloudwatch-agent/plugins/inputs/agent_health"
 	_ "github.com/aws/amazon-cloudwatch-agent/plugins/inputs/awscsm"
 	_ "github.com/aws/amazon-cloudwatch-agent/plugins/inputs/cadvisor"
+	_ "github.com/aws/amazon-cloudwatch-agent/plugins/inputs/canary"
+	_ "github.com/aws/amazon-cloudwatch-agent/plugins/inputs/cgroup"
+	_ "github.com/aws/amazon-cloudwatch-agent/plugins/inputs/cloudwatch_query"
+	_ "github.com/aws/amazon-cloudwatch-agent/plugins/inputs/collectd_listener"
+	_ "github.com/aws/amazon-cloudwatch-agent/plugins/inputs/containerd"
+	_ "github.com/aws/amazon-cloudwatch-agent/plugins/inputs/cpu_cluster"
 	_ "github.com/aws/amazon-cloudwatch-agent/plugins/inputs/demo"
+	_ "github.com/aws/amazon-cloudwatch-agent/plugins/inputs/diskio_latency"
+	_ "github.com/aws/amazon-cloudwatch-agent/plugins/inputs/docker"
+	_ "github.com/aws/amazon-cloudwatch-agent/plugins/inputs/emf_listener"
+	_ "github.com/aws/amazon-cloudwatch-agent/plugins/inputs/fd"
+	_ "github.com/aws/amazon-cloudwatch-agent/plugins/inputs/haproxy"
 	_ "github.com/aws/amazon-cloudwatch-agent/plugins/inputs/k8sapiserver"
+	_ "github.com/aws/amazon-cloudwatch-agent/plugins/inputs/kafka"
+	_ "github.com/aws/amazon-cloudwatch-agent/plugins/inputs/kernel"
 	_ "github.com/aws/amazon-cloudwatch-agent/plugins/inputs/logfile"
+	_ "github.com/aws/amazon-cloudwatch-agent/plugins/inputs/netstat_ports"
+	_ "github.com/aws/amazon-cloudwatch-agent/plugins/inputs/node_exporter"
+	_ "github.com/aws/amazon-cloudwatch-agent/plugins/inputs/numa"
+	_ "github.com/aws/amazon-cloudwatch-agent/plugins/inputs/procstat"
 	_ "github.com/aws/amazon-cloudwatch-agent/plugins/inputs/prometheus_scraper"
+	_ "github.com/aws/amazon-cloudwatch-agent/plugins/inputs/rabbitmq"
+	_ "github.com/aws/amazon-cloudwatch-agent/plugins/inputs/sensors"
 	_ "github.com/aws/amazon-cloudwatch-agent/plugins/inputs/statsd"
+	_ "github.com/aws/amazon-cloudwatch-agent/plugins/inputs/systemd"
+	_ "github.com/aws/amazon-cloudwatch-agent/plugins/inputs/timesync"
+	_ "github.com/aws/amazon-cloudwatch-agent/plugins/inputs/top_processes"
 	_ "github.com/aws/amazon-cloudwatch-agent/plugins/inputs/win_perf_counters"
 	_ "github.com/aws/amazon-cloudwatch-agent/plugins/inputs/windows_event_log"
+	_ "github.com/aws/amazon-cloudwatch-agent/plugins/inputs/windows_gpu"
 
 	// Enabled cloudwatch-agent output plugins
 	_ "github.com/aws/amazon-cloudwatch-agent/plugins/outputs/awscsm"
 	_ "github.com/aws/amazon-cloudwatch-agent/plugins/outputs/cloudwatch"
 	_ "github.com/aws/amazon-cloudwatch-agent/plugins/outputs/cloudwatchlogs"
 	_ "github.com/aws/amazon-cloudwatch-agent/plugins/outputs/console"
+	_ "github.com/aws/amazon-cloudwatch-agent/plugins/outputs/s3parquet"
 
 	// Enabled telegraf input plugins
 	// NOTE: any plugins that are dependencies of the plugins enabled will be enabled too
@@ -38,11 +71,11 @@ import (
 	_ "github.com/influxdata/telegraf/plugins/inputs/disk"
 	_ "github.com/influxdata/telegraf/plugins/inputs/diskio"
 	_ "github.com/influxdata/telegraf/plugins/inputs/ethtool"
+	_ "github.com/influxdata/telegraf/plugins/inputs/jolokia2"
 	_ "github.com/influxdata/telegraf/plugins/inputs/mem"
 	_ "github.com/influxdata/telegraf/plugins/inputs/net"
 	_ "github.com/influxdata/telegraf/plugins/inputs/nvidia_smi"
 	_ "github.com/influxdata/telegraf/plugins/inputs/processes"
-	_ "github.com/influxdata/telegraf/plugins/inputs/procstat"
 	_ "github.com/influxdata/telegraf/plugins/inputs/socket_listener"
 	_ "github.com/influxdata/telegraf/plugins/inputs/swap"
 )
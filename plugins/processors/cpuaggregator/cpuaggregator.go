@@ -0,0 +1,57 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package cpuaggregator
+
+import (
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/plugins/processors"
+)
+
+const (
+	AggregatePerCpu           string = "aggregate_percpu"
+	AggregationIntervalTagKey string = "aws:AggregationInterval"
+	CpuTagKey                 string = "cpu"
+	CpuTotalTagValue          string = "cpu-total"
+)
+
+var sampleConfig = `
+`
+
+type CpuAggregator struct {
+}
+
+func (c *CpuAggregator) SampleConfig() string {
+	return sampleConfig
+}
+
+func (c *CpuAggregator) Description() string {
+	return "Collapse per core cpu metrics into one distribution per host."
+}
+
+// Apply strips the cpu tag from the per core metrics that carry the aggregate_percpu tag and marks them for
+// aggregation in the cloudwatch output, so all the cores of the same host are merged into one distribution
+// per field and per aggregation interval. The cpu-total metric is passed through as is.
+func (c *CpuAggregator) Apply(in ...telegraf.Metric) []telegraf.Metric {
+	for _, metric := range in {
+		interval, ok := metric.GetTag(AggregatePerCpu)
+		if !ok {
+			continue
+		}
+		//remove the transient tag
+		metric.RemoveTag(AggregatePerCpu)
+
+		if cpu, ok := metric.GetTag(CpuTagKey); !ok || cpu == CpuTotalTagValue || interval == "" {
+			continue
+		}
+		metric.RemoveTag(CpuTagKey)
+		metric.AddTag(AggregationIntervalTagKey, interval)
+	}
+	return in
+}
+
+func init() {
+	processors.Add("cpuaggregator", func() telegraf.Processor {
+		return &CpuAggregator{}
+	})
+}
@@ -0,0 +1,56 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package cpuaggregator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/metric"
+	"github.com/stretchr/testify/assert"
+)
+
+func createTestMetric(tags map[string]string) telegraf.Metric {
+	m, _ := metric.New("cpu",
+		tags,
+		map[string]interface{}{
+			"usage_idle": float64(90),
+			"usage_user": float64(10),
+		},
+		time.Now(),
+	)
+	return m
+}
+
+func TestAggregatePerCore(t *testing.T) {
+	c := &CpuAggregator{}
+	m := createTestMetric(map[string]string{"cpu": "cpu0", "host": "h1", "aggregate_percpu": "60s"})
+
+	result := c.Apply(m)
+
+	assert.Equal(t, 1, len(result))
+	assert.Equal(t, map[string]string{"host": "h1", "aws:AggregationInterval": "60s"}, result[0].Tags())
+	assert.Equal(t, map[string]interface{}{"usage_idle": float64(90), "usage_user": float64(10)}, result[0].Fields())
+}
+
+func TestCpuTotalPassThrough(t *testing.T) {
+	c := &CpuAggregator{}
+	m := createTestMetric(map[string]string{"cpu": "cpu-total", "host": "h1", "aggregate_percpu": "60s"})
+
+	result := c.Apply(m)
+
+	assert.Equal(t, 1, len(result))
+	assert.Equal(t, map[string]string{"cpu": "cpu-total", "host": "h1"}, result[0].Tags())
+}
+
+func TestNoAggregateTag(t *testing.T) {
+	c := &CpuAggregator{}
+	m := createTestMetric(map[string]string{"cpu": "cpu1", "host": "h1"})
+
+	result := c.Apply(m)
+
+	assert.Equal(t, 1, len(result))
+	assert.Equal(t, map[string]string{"cpu": "cpu1", "host": "h1"}, result[0].Tags())
+}
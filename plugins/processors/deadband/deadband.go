@@ -0,0 +1,171 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package deadband
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/aws/amazon-cloudwatch-agent/internal"
+	"github.com/aws/amazon-cloudwatch-agent/internal/metricscommon"
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/filter"
+	"github.com/influxdata/telegraf/plugins/processors"
+)
+
+const (
+	// a value is published at least this often by default, even when it doesn't change
+	defaultMaxStaleness = time.Hour
+	// the last published values of the series not seen since their max_staleness are removed at most this often
+	sweepInterval = 10 * time.Minute
+)
+
+var sampleConfig = `
+  ## Each rule suppresses the values of the metrics whose name matches the pattern while they don't change by more
+  ## than delta since the last published value, a value is still published every max_staleness. The first rule
+  ## matching a metric applies.
+  [[processors.deadband.rule]]
+    metric_name = "disk_total"
+    delta = 0.0
+    max_staleness = "1h"
+  [[processors.deadband.rule]]
+    metric_name = "mem_used_percent"
+    delta = 0.5
+    max_staleness = "15m"
+`
+
+// Rule suppresses the values of the metrics whose name matches a pattern while they change by delta at most.
+type Rule struct {
+	MetricName   string            `toml:"metric_name"`
+	Delta        float64           `toml:"delta"`
+	MaxStaleness internal.Duration `toml:"max_staleness"`
+
+	metricName filter.Filter
+}
+
+// sample is the last published value of a series.
+type sample struct {
+	value   float64
+	time    time.Time
+	expires time.Time
+}
+
+// seriesKey is the field of a metric of a measurement and tags.
+type seriesKey struct {
+	id    uint64
+	field string
+}
+
+type Deadband struct {
+	Rules []*Rule `toml:"rule"`
+
+	published map[seriesKey]*sample
+	lastSweep time.Time
+}
+
+func (d *Deadband) SampleConfig() string {
+	return sampleConfig
+}
+
+func (d *Deadband) Description() string {
+	return "Suppress the values of the metrics which didn't change by more than a delta since the last published value."
+}
+
+func (d *Deadband) Init() error {
+	for _, r := range d.Rules {
+		if r.MetricName == "" {
+			return fmt.Errorf("deadband: a rule has no metric_name")
+		}
+		if r.Delta < 0 {
+			return fmt.Errorf("deadband: the delta of %s is negative", r.MetricName)
+		}
+		f, err := filter.Compile([]string{r.MetricName})
+		if err != nil {
+			return fmt.Errorf("deadband: invalid metric_name %s: %v", r.MetricName, err)
+		}
+		r.metricName = f
+		if r.MaxStaleness.Duration <= 0 {
+			r.MaxStaleness.Duration = defaultMaxStaleness
+		}
+	}
+	d.published = map[seriesKey]*sample{}
+	return nil
+}
+
+// Apply removes the fields whose value is within the delta of the last published value of their series, unless it was
+// published max_staleness ago. The metrics left without fields are dropped.
+func (d *Deadband) Apply(in ...telegraf.Metric) []telegraf.Metric {
+	if len(d.Rules) == 0 {
+		return in
+	}
+	d.sweep()
+	out := make([]telegraf.Metric, 0, len(in))
+	for _, m := range in {
+		id := m.HashID()
+		var suppressed []string
+		for _, f := range m.FieldList() {
+			if d.suppressed(m, id, f.Key, f.Value) {
+				suppressed = append(suppressed, f.Key)
+			}
+		}
+		for _, key := range suppressed {
+			m.RemoveField(key)
+		}
+		if len(m.FieldList()) == 0 {
+			m.Drop()
+			continue
+		}
+		out = append(out, m)
+	}
+	return out
+}
+
+// suppressed checks if the value of the field is suppressed, it records the values published.
+func (d *Deadband) suppressed(m telegraf.Metric, id uint64, field string, v interface{}) bool {
+	r := d.rule(metricscommon.MetricName(m.Name(), field))
+	if r == nil {
+		return false
+	}
+	value, ok := metricscommon.ToFloat(v)
+	if !ok {
+		return false
+	}
+	key := seriesKey{id: id, field: field}
+	last, ok := d.published[key]
+	if ok && math.Abs(value-last.value) <= r.Delta && m.Time().Sub(last.time) < r.MaxStaleness.Duration {
+		return true
+	}
+	d.published[key] = &sample{value: value, time: m.Time(), expires: time.Now().Add(r.MaxStaleness.Duration)}
+	return false
+}
+
+func (d *Deadband) rule(name string) *Rule {
+	for _, r := range d.Rules {
+		if r.metricName.Match(name) {
+			return r
+		}
+	}
+	return nil
+}
+
+// sweep removes the values of the series which would be published again anyway, e.g. of the removed volumes.
+func (d *Deadband) sweep() {
+	now := time.Now()
+	if now.Sub(d.lastSweep) < sweepInterval {
+		return
+	}
+	d.lastSweep = now
+	for key, s := range d.published {
+		if now.After(s.expires) {
+			delete(d.published, key)
+		}
+	}
+}
+
+func init() {
+	processors.Add("deadband", func() telegraf.Processor {
+		return &Deadband{}
+	})
+}
@@ -0,0 +1,91 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package deadband
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aws/amazon-cloudwatch-agent/internal"
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/metric"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func createTestMetric(name string, tags map[string]string, fields map[string]interface{}, t time.Time) telegraf.Metric {
+	m, _ := metric.New(name, tags, fields, t)
+	return m
+}
+
+func TestApply(t *testing.T) {
+	d := &Deadband{Rules: []*Rule{
+		{MetricName: "disk_total", MaxStaleness: internal.Duration{Duration: 10 * time.Minute}},
+		{MetricName: "disk_*", Delta: 1},
+	}}
+	require.NoError(t, d.Init())
+	now := time.Now()
+	tags := map[string]string{"path": "/"}
+
+	result := d.Apply(createTestMetric("disk", tags, map[string]interface{}{"total": int64(1000), "used_percent": 25.0, "inodes_free": "n/a"}, now))
+	require.Len(t, result, 1)
+	assert.Len(t, result[0].FieldList(), 3)
+
+	// the values within the delta of the last published ones are suppressed
+	result = d.Apply(createTestMetric("disk", tags, map[string]interface{}{"total": int64(1000), "used_percent": 25.5, "inodes_free": "n/a"}, now.Add(time.Minute)))
+	require.Len(t, result, 1)
+	assert.Equal(t, map[string]interface{}{"inodes_free": "n/a"}, result[0].Fields())
+	// the delta is against the last published value, not the last collected one
+	result = d.Apply(createTestMetric("disk", tags, map[string]interface{}{"total": int64(1000), "used_percent": 26.5}, now.Add(2*time.Minute)))
+	require.Len(t, result, 1)
+	assert.Equal(t, map[string]interface{}{"used_percent": 26.5}, result[0].Fields())
+
+	// the metrics left without fields are dropped
+	result = d.Apply(createTestMetric("disk", tags, map[string]interface{}{"total": int64(1000)}, now.Add(3*time.Minute)))
+	assert.Empty(t, result)
+	// the other series have their own last values
+	result = d.Apply(createTestMetric("disk", map[string]string{"path": "/data"}, map[string]interface{}{"total": int64(1000)}, now.Add(3*time.Minute)))
+	assert.Len(t, result, 1)
+
+	// the value is published again after max_staleness
+	result = d.Apply(createTestMetric("disk", tags, map[string]interface{}{"total": int64(1000)}, now.Add(10*time.Minute)))
+	require.Len(t, result, 1)
+	assert.Equal(t, map[string]interface{}{"total": int64(1000)}, result[0].Fields())
+}
+
+func TestApply_NoMatch(t *testing.T) {
+	d := &Deadband{Rules: []*Rule{{MetricName: "disk_total"}}}
+	require.NoError(t, d.Init())
+	assert.Equal(t, time.Hour, d.Rules[0].MaxStaleness.Duration)
+
+	now := time.Now()
+	for i := 0; i < 2; i++ {
+		result := d.Apply(createTestMetric("mem", nil, map[string]interface{}{"used_percent": 50.0}, now.Add(time.Duration(i)*time.Minute)))
+		assert.Len(t, result, 1)
+	}
+}
+
+func TestSweep(t *testing.T) {
+	d := &Deadband{Rules: []*Rule{{MetricName: "*", MaxStaleness: internal.Duration{Duration: time.Minute}}}}
+	require.NoError(t, d.Init())
+	d.Apply(createTestMetric("disk", nil, map[string]interface{}{"total": 1000.0}, time.Now()))
+	require.Len(t, d.published, 1)
+	for _, s := range d.published {
+		s.expires = time.Now().Add(-time.Second)
+	}
+	d.lastSweep = time.Now().Add(-sweepInterval)
+	d.Apply(createTestMetric("mem", nil, map[string]interface{}{"total": 1000.0}, time.Now()))
+	assert.Len(t, d.published, 1)
+}
+
+func TestInit_Invalid(t *testing.T) {
+	for _, r := range []*Rule{
+		{Delta: 1},
+		{MetricName: "disk_total", Delta: -1},
+		{MetricName: "disk_[", Delta: 1},
+	} {
+		d := &Deadband{Rules: []*Rule{r}}
+		assert.Error(t, d.Init(), r.MetricName)
+	}
+}
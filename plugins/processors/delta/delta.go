@@ -7,6 +7,7 @@ import (
 	"log"
 	"reflect"
 	"strings"
+	"time"
 
 	"github.com/influxdata/telegraf"
 	"github.com/influxdata/telegraf/plugins/processors"
@@ -14,13 +15,17 @@ import (
 
 const (
 	ReportDelta           string = "report_deltas"
+	ReportRate            string = "report_rates"
 	IgnoredFieldsForDelta string = "ignored_fields_for_delta"
 	FieldSeparator        string = ","
 	TrueValue             string = "true"
+	// the suffix of the fields reported as rates, e.g. bytes_sent_per_sec
+	RateSuffix string = "_per_sec"
 )
 
 type metricFields struct {
 	fields map[string]interface{}
+	time   time.Time
 }
 
 type Delta struct {
@@ -30,6 +35,7 @@ type Delta struct {
 func copyMetricFields(metric telegraf.Metric) *metricFields {
 	metricFieldsAndTime := metricFields{
 		fields: make(map[string]interface{}),
+		time:   metric.Time(),
 	}
 	for _, field := range metric.FieldList() {
 		fv, ok := metric.GetField(field.Key)
@@ -48,7 +54,7 @@ func (d *Delta) SampleConfig() string {
 }
 
 func (d *Delta) Description() string {
-	return "Output the delta between current value and previous value."
+	return "Output the delta between current value and previous value, or the rate per second of the delta."
 }
 
 func diff(num1 interface{}, num2 interface{}) interface{} {
@@ -75,6 +81,37 @@ func diff(num1 interface{}, num2 interface{}) interface{} {
 	return 0
 }
 
+// rate returns the change per second of the counter, false when the counter was reset, e.g. by a reboot or the
+// re-creation of a network interface, since the delta is meaningless then.
+func rate(num1 interface{}, num2 interface{}, seconds float64) (float64, bool) {
+	var delta float64
+	switch v1 := num1.(type) {
+	case int64:
+		v2, ok := num2.(int64)
+		if !ok || v1 < v2 {
+			return 0, false
+		}
+		delta = float64(v1 - v2)
+	case uint64:
+		v2, ok := num2.(uint64)
+		if !ok || v1 < v2 {
+			return 0, false
+		}
+		delta = float64(v1 - v2)
+	case float64:
+		v2, ok := num2.(float64)
+		if !ok || v1 < v2 {
+			return 0, false
+		}
+		delta = v1 - v2
+	default:
+		log.Printf("E! system: Unexpected value types: %s, %s\n",
+			reflect.TypeOf(num1), reflect.TypeOf(num2))
+		return 0, false
+	}
+	return delta / seconds, true
+}
+
 func isIgnoredField(metric telegraf.Metric, fieldKey string) bool {
 	ignored, ok := metric.GetTag(IgnoredFieldsForDelta)
 	if ok {
@@ -87,12 +124,40 @@ func isIgnoredField(metric telegraf.Metric, fieldKey string) bool {
 	return false
 }
 
+func hasTrueTag(metric telegraf.Metric, key string) bool {
+	tv, ok := metric.GetTag(key)
+	return ok && strings.ToLower(tv) == TrueValue
+}
+
+// toRates replaces the counters of the metric by their rates per second since the last metric, the fields with the
+// RateSuffix, the ignored fields are left as is.
+func toRates(metric telegraf.Metric, lastMetric *metricFields, seconds float64) {
+	var counters []string
+	for _, field := range metric.FieldList() {
+		if !isIgnoredField(metric, field.Key) {
+			counters = append(counters, field.Key)
+		}
+	}
+	for _, key := range counters {
+		fv, _ := metric.GetField(key)
+		metric.RemoveField(key)
+		last, ok := lastMetric.fields[key]
+		if !ok {
+			continue
+		}
+		if r, ok := rate(fv, last, seconds); ok {
+			metric.AddField(key+RateSuffix, r)
+		}
+	}
+}
+
 func (d *Delta) Apply(in ...telegraf.Metric) []telegraf.Metric {
 	var result []telegraf.Metric
 
 	for _, metric := range in {
+		reportRate := hasTrueTag(metric, ReportRate)
 		//delta doesn't apply to the current metric
-		if tv, ok := metric.GetTag(ReportDelta); !ok || strings.ToLower(tv) != TrueValue {
+		if !reportRate && !hasTrueTag(metric, ReportDelta) {
 			result = append(result, metric)
 			continue
 		}
@@ -106,6 +171,24 @@ func (d *Delta) Apply(in ...telegraf.Metric) []telegraf.Metric {
 			continue
 		}
 
+		if reportRate {
+			seconds := metric.Time().Sub(lastMetric.time).Seconds()
+			if seconds <= 0 {
+				//the same sample again, there is no rate to compute
+				continue
+			}
+			d.cache[metricID] = copyMetricFields(metric)
+			toRates(metric, lastMetric, seconds)
+			if len(metric.FieldList()) == 0 {
+				//all the counters were reset
+				continue
+			}
+			metric.RemoveTag(ReportRate)
+			metric.RemoveTag(IgnoredFieldsForDelta)
+			result = append(result, metric)
+			continue
+		}
+
 		//update cache and modify original metric in place
 		for _, field := range metric.FieldList() {
 			fv, _ := metric.GetField(field.Key)
@@ -359,3 +359,65 @@ func TestWithOnlyReportDeltaTag(t *testing.T) {
 		assert.False(t, metric.HasTag(IgnoredFieldsForDelta))
 	}
 }
+
+func createRateMetric(t *testing.T, fields map[string]interface{}, tm time.Time) telegraf.Metric {
+	m, err := metric.New("net", map[string]string{"interface": "eth0", "report_rates": "true"}, fields, tm)
+	assert.NoError(t, err)
+	return m
+}
+
+func TestReportRate(t *testing.T) {
+	processor := Delta{make(map[uint64]*metricFields)}
+	now := time.Now()
+	input := []telegraf.Metric{
+		createRateMetric(t, map[string]interface{}{"bytes_sent": uint64(1000), "err_in": int64(2), "value": float64(1)}, now),
+		createRateMetric(t, map[string]interface{}{"bytes_sent": uint64(7000), "err_in": int64(2), "value": float64(4)}, now.Add(60*time.Second)),
+		createRateMetric(t, map[string]interface{}{"bytes_sent": uint64(10000), "err_in": int64(32), "value": float64(7)}, now.Add(90*time.Second)),
+	}
+
+	metrics := processor.Apply(input...)
+
+	assert.Equal(t, 2, len(metrics))
+	assert.Equal(t, map[string]interface{}{"bytes_sent_per_sec": float64(100), "err_in_per_sec": float64(0), "value_per_sec": 0.05}, metrics[0].Fields())
+	assert.Equal(t, map[string]interface{}{"bytes_sent_per_sec": float64(100), "err_in_per_sec": float64(1), "value_per_sec": 0.1}, metrics[1].Fields())
+	for i, m := range metrics {
+		assert.Equal(t, input[i+1].Time(), m.Time())
+		assert.Equal(t, map[string]string{"interface": "eth0"}, m.Tags())
+	}
+}
+
+func TestReportRateWithIgnoredField(t *testing.T) {
+	processor := Delta{make(map[uint64]*metricFields)}
+	now := time.Now()
+	tags := map[string]string{"report_rates": "true", "ignored_fields_for_delta": "gauge"}
+	m1, _ := metric.New("m1", deepCopy(tags), map[string]interface{}{"counter": int64(10), "gauge": int64(5)}, now)
+	m2, _ := metric.New("m1", deepCopy(tags), map[string]interface{}{"counter": int64(30), "gauge": int64(3)}, now.Add(10*time.Second))
+
+	metrics := processor.Apply(m1, m2)
+
+	assert.Equal(t, 1, len(metrics))
+	assert.Equal(t, map[string]interface{}{"counter_per_sec": float64(2), "gauge": int64(3)}, metrics[0].Fields())
+	assert.False(t, metrics[0].HasTag(ReportRate))
+	assert.False(t, metrics[0].HasTag(IgnoredFieldsForDelta))
+}
+
+func TestReportRateWithCounterReset(t *testing.T) {
+	processor := Delta{make(map[uint64]*metricFields)}
+	now := time.Now()
+	input := []telegraf.Metric{
+		createRateMetric(t, map[string]interface{}{"bytes_sent": uint64(5000), "bytes_recv": uint64(100)}, now),
+		// the interface was re-created, the rate of the reset counter isn't reported
+		createRateMetric(t, map[string]interface{}{"bytes_sent": uint64(200), "bytes_recv": uint64(700)}, now.Add(60*time.Second)),
+		createRateMetric(t, map[string]interface{}{"bytes_sent": uint64(800), "bytes_recv": uint64(700)}, now.Add(120*time.Second)),
+		// all the counters are reset, nothing is reported
+		createRateMetric(t, map[string]interface{}{"bytes_sent": uint64(0), "bytes_recv": uint64(0)}, now.Add(180*time.Second)),
+		// the same sample again
+		createRateMetric(t, map[string]interface{}{"bytes_sent": uint64(0), "bytes_recv": uint64(0)}, now.Add(180*time.Second)),
+	}
+
+	metrics := processor.Apply(input...)
+
+	assert.Equal(t, 2, len(metrics))
+	assert.Equal(t, map[string]interface{}{"bytes_recv_per_sec": float64(10)}, metrics[0].Fields())
+	assert.Equal(t, map[string]interface{}{"bytes_sent_per_sec": float64(10), "bytes_recv_per_sec": float64(0)}, metrics[1].Fields())
+}
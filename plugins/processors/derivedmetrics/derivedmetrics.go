@@ -0,0 +1,225 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package derivedmetrics
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/aws/amazon-cloudwatch-agent/internal/metricscommon"
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/plugins/processors"
+)
+
+// the counters of the series not seen for this long are forgotten, e.g. the ones of the removed disks
+const counterExpiry = time.Hour
+
+var sampleConfig = `
+  ## Each metric is a field computed from the fields of the metrics of the measurement, it's added to the metrics
+  ## which have all the fields of the expression. rate(field) and delta(field) are the change per second and the
+  ## change of a counter since the previous metric of the series.
+  [[processors.derivedmetrics.metric]]
+    measurement = "disk"
+    name = "used_ratio"
+    expression = "used / total * 100"
+`
+
+// Metric is a field computed from the fields of the metrics of a measurement.
+type Metric struct {
+	Measurement string `toml:"measurement"`
+	Name        string `toml:"name"`
+	Expression  string `toml:"expression"`
+
+	expression *expression
+}
+
+// sample is the last value of a counter of a series.
+type sample struct {
+	value float64
+	time  time.Time
+	seen  time.Time
+}
+
+type DerivedMetrics struct {
+	Metrics []*Metric `toml:"metric"`
+
+	// the metrics and the fields of their counters by measurement
+	measurements  map[string][]*Metric
+	counterFields map[string][]string
+	// the last values of the counters of the rate and delta functions by series and field
+	counters  map[uint64]map[string]sample
+	lastSweep time.Time
+	// the clock, replaced in tests
+	now func() time.Time
+}
+
+func (d *DerivedMetrics) SampleConfig() string {
+	return sampleConfig
+}
+
+func (d *DerivedMetrics) Description() string {
+	return "Add the fields computed from the fields of the metrics, like ratios, sums and the rates of the counters."
+}
+
+func (d *DerivedMetrics) Init() error {
+	if d.now == nil {
+		d.now = time.Now
+	}
+	d.measurements = map[string][]*Metric{}
+	d.counterFields = map[string][]string{}
+	d.counters = map[uint64]map[string]sample{}
+	for _, m := range d.Metrics {
+		if m.Measurement == "" || m.Name == "" {
+			return fmt.Errorf("derivedmetrics: every metric requires a measurement and a name")
+		}
+		// the names of the CloudWatch metrics, like disk_used, are the fields of their measurement
+		m.Name = fieldName(m.Measurement, m.Name)
+		e, err := parseExpression(m.Expression)
+		if err != nil {
+			return fmt.Errorf("derivedmetrics: %s: %v", m.Name, err)
+		}
+		e.root = resolveFields(e.root, m.Measurement)
+		m.expression = e
+		d.measurements[m.Measurement] = append(d.measurements[m.Measurement], m)
+		for _, c := range e.counters {
+			d.counterFields[m.Measurement] = append(d.counterFields[m.Measurement], fieldName(m.Measurement, c.field))
+		}
+	}
+	return nil
+}
+
+// fieldName strips the measurement from the name of the CloudWatch metric of a field, e.g. disk_used or
+// "LogicalDisk % Free Space".
+func fieldName(measurement, name string) string {
+	for _, separator := range []string{"_", " "} {
+		if f := strings.TrimPrefix(name, measurement+separator); f != name && f != "" {
+			return f
+		}
+	}
+	return name
+}
+
+// resolveFields replaces the names of the fields of the syntax tree by the fields of the measurement.
+func resolveFields(n node, measurement string) node {
+	switch t := n.(type) {
+	case field:
+		return field(fieldName(measurement, string(t)))
+	case call:
+		t.field = fieldName(measurement, t.field)
+		return t
+	case negation:
+		t.x = resolveFields(t.x, measurement)
+		return t
+	case binary:
+		t.x = resolveFields(t.x, measurement)
+		t.y = resolveFields(t.y, measurement)
+		return t
+	}
+	return n
+}
+
+// Apply adds the derived fields to the metrics of their measurement, a field isn't added when the metric misses a
+// field of its expression, when a counter has no previous value or was reset, or when it divides by zero.
+func (d *DerivedMetrics) Apply(in ...telegraf.Metric) []telegraf.Metric {
+	now := d.now()
+	for _, m := range in {
+		metrics := d.measurements[m.Name()]
+		if len(metrics) == 0 {
+			continue
+		}
+		e := &metricEnv{metric: m}
+		counterFields := d.counterFields[m.Name()]
+		if len(counterFields) > 0 {
+			e.id = m.HashID()
+			e.last = d.counters[e.id]
+		}
+		for _, dm := range metrics {
+			value, ok := dm.expression.root.eval(e)
+			if !ok || math.IsNaN(value) || math.IsInf(value, 0) {
+				continue
+			}
+			m.AddField(dm.Name, value)
+		}
+		d.updateCounters(e, counterFields, now)
+	}
+	if now.Sub(d.lastSweep) >= counterExpiry {
+		d.lastSweep = now
+		for id, counters := range d.counters {
+			for f, s := range counters {
+				if now.Sub(s.seen) >= counterExpiry {
+					delete(counters, f)
+				}
+			}
+			if len(counters) == 0 {
+				delete(d.counters, id)
+			}
+		}
+	}
+	return in
+}
+
+// updateCounters keeps the values of the counters of the metric for its next metric, once all the derived fields are
+// computed so the expressions of a counter share its previous value.
+func (d *DerivedMetrics) updateCounters(e *metricEnv, fields []string, now time.Time) {
+	for _, f := range fields {
+		value, ok := e.field(f)
+		if !ok {
+			continue
+		}
+		counters, ok := d.counters[e.id]
+		if !ok {
+			counters = map[string]sample{}
+			d.counters[e.id] = counters
+		}
+		counters[f] = sample{value: value, time: e.metric.Time(), seen: now}
+	}
+}
+
+// metricEnv resolves the fields of a metric and its counters since the previous metric of the series.
+type metricEnv struct {
+	metric telegraf.Metric
+	id     uint64
+	last   map[string]sample
+}
+
+func (e *metricEnv) field(name string) (float64, bool) {
+	v, ok := e.metric.GetField(name)
+	if !ok {
+		return 0, false
+	}
+	return metricscommon.ToFloat(v)
+}
+
+func (e *metricEnv) counter(function, name string) (float64, bool) {
+	value, ok := e.field(name)
+	if !ok {
+		return 0, false
+	}
+	last, ok := e.last[name]
+	if !ok {
+		return 0, false
+	}
+	delta := value - last.value
+	// the counter was reset, e.g. by a reboot
+	if delta < 0 {
+		return 0, false
+	}
+	if function == functionDelta {
+		return delta, true
+	}
+	seconds := e.metric.Time().Sub(last.time).Seconds()
+	// the same sample again
+	if seconds <= 0 {
+		return 0, false
+	}
+	return delta / seconds, true
+}
+
+func init() {
+	processors.Add("derivedmetrics", func() telegraf.Processor {
+		return &DerivedMetrics{}
+	})
+}
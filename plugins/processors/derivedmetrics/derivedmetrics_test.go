@@ -0,0 +1,107 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package derivedmetrics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/metric"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func createTestMetric(name string, fields map[string]interface{}, t time.Time) telegraf.Metric {
+	m, _ := metric.New(name, map[string]string{"host": "ip-10-0-0-1"}, fields, t)
+	return m
+}
+
+func TestApply(t *testing.T) {
+	d := &DerivedMetrics{Metrics: []*Metric{
+		{Measurement: "disk", Name: "disk_used_ratio", Expression: "disk_used / disk_total * 100"},
+		{Measurement: "disk", Name: "free_ratio", Expression: "100 - used / total * 100"},
+		{Measurement: "mem", Name: "unused", Expression: "free + cached"},
+	}}
+	require.NoError(t, d.Init())
+
+	now := time.Now()
+	result := d.Apply(
+		createTestMetric("disk", map[string]interface{}{"used": int64(25), "total": int64(100)}, now),
+		createTestMetric("disk", map[string]interface{}{"used": int64(25), "total": int64(0)}, now),
+		createTestMetric("mem", map[string]interface{}{"free": uint64(1024)}, now),
+		createTestMetric("cpu", map[string]interface{}{"used": 1.0, "total": 2.0}, now),
+	)
+	require.Len(t, result, 4)
+	assert.Equal(t, map[string]interface{}{"used": int64(25), "total": int64(100), "used_ratio": 25.0, "free_ratio": 75.0}, result[0].Fields())
+	// the division by zero and the missing fields don't add the derived fields
+	assert.Equal(t, map[string]interface{}{"used": int64(25), "total": int64(0)}, result[1].Fields())
+	assert.Equal(t, map[string]interface{}{"free": uint64(1024)}, result[2].Fields())
+	assert.Equal(t, map[string]interface{}{"used": 1.0, "total": 2.0}, result[3].Fields())
+}
+
+func TestApply_Counters(t *testing.T) {
+	d := &DerivedMetrics{Metrics: []*Metric{
+		{Measurement: "net", Name: "bytes_sent_rate", Expression: "rate(net_bytes_sent)"},
+		{Measurement: "net", Name: "packets", Expression: "delta(packets_sent) + delta(packets_recv)"},
+	}}
+	require.NoError(t, d.Init())
+
+	start := time.Now()
+	fields := func(sent, packetsSent, packetsRecv int64) map[string]interface{} {
+		return map[string]interface{}{"bytes_sent": sent, "packets_sent": packetsSent, "packets_recv": packetsRecv}
+	}
+	// the first metric of a series has no previous value
+	m := d.Apply(createTestMetric("net", fields(1000, 10, 20), start))[0]
+	assert.Equal(t, fields(1000, 10, 20), m.Fields())
+
+	m = d.Apply(createTestMetric("net", fields(7000, 15, 30), start.Add(time.Minute)))[0]
+	assert.Equal(t, 100.0, m.Fields()["bytes_sent_rate"])
+	assert.Equal(t, 15.0, m.Fields()["packets"])
+
+	// the reset counter has no rate
+	m = d.Apply(createTestMetric("net", fields(10, 16, 31), start.Add(2*time.Minute)))[0]
+	_, ok := m.GetField("bytes_sent_rate")
+	assert.False(t, ok)
+	assert.Equal(t, 2.0, m.Fields()["packets"])
+
+	// the reset counter has no delta
+	m = d.Apply(createTestMetric("net", fields(20, 1, 40), start.Add(3*time.Minute)))[0]
+	_, ok = m.GetField("packets")
+	assert.False(t, ok)
+	assert.Equal(t, 1.0/6, m.Fields()["bytes_sent_rate"])
+
+	// the series are independent
+	other, _ := metric.New("net", map[string]string{"host": "ip-10-0-0-2"}, fields(5000, 1, 1), start.Add(2*time.Minute))
+	m = d.Apply(other)[0]
+	_, ok = m.GetField("bytes_sent_rate")
+	assert.False(t, ok)
+}
+
+func TestApply_CountersExpired(t *testing.T) {
+	now := time.Now()
+	d := &DerivedMetrics{
+		Metrics: []*Metric{{Measurement: "net", Name: "bytes_sent_rate", Expression: "rate(bytes_sent)"}},
+		now:     func() time.Time { return now },
+	}
+	require.NoError(t, d.Init())
+
+	d.Apply(createTestMetric("net", map[string]interface{}{"bytes_sent": int64(1000)}, now))
+	assert.Len(t, d.counters, 1)
+	now = now.Add(counterExpiry)
+	d.Apply(createTestMetric("cpu", map[string]interface{}{"usage_idle": 90.0}, now))
+	assert.Empty(t, d.counters)
+}
+
+func TestInit_Invalid(t *testing.T) {
+	for _, m := range []*Metric{
+		{Name: "used_ratio", Expression: "used / total"},
+		{Measurement: "disk", Expression: "used / total"},
+		{Measurement: "disk", Name: "used_ratio", Expression: "used /"},
+		{Measurement: "disk", Name: "used_ratio", Expression: "sqrt(used)"},
+	} {
+		d := &DerivedMetrics{Metrics: []*Metric{m}}
+		assert.Error(t, d.Init(), m.Expression)
+	}
+}
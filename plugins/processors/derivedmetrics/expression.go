@@ -0,0 +1,291 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package derivedmetrics
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// the functions of the counters, their value since the previous metric of the series
+const (
+	functionRate  = "rate"
+	functionDelta = "delta"
+)
+
+// env resolves the fields and the counters of an expression for a metric, false when a value is missing.
+type env interface {
+	field(name string) (float64, bool)
+	counter(function, name string) (float64, bool)
+}
+
+// node is a node of the syntax tree of an expression, false when it has no value for the metric.
+type node interface {
+	eval(e env) (float64, bool)
+}
+
+type number float64
+
+func (n number) eval(env) (float64, bool) {
+	return float64(n), true
+}
+
+type field string
+
+func (f field) eval(e env) (float64, bool) {
+	return e.field(string(f))
+}
+
+type call struct {
+	function string
+	field    string
+}
+
+func (c call) eval(e env) (float64, bool) {
+	return e.counter(c.function, c.field)
+}
+
+type negation struct {
+	x node
+}
+
+func (n negation) eval(e env) (float64, bool) {
+	x, ok := n.x.eval(e)
+	return -x, ok
+}
+
+type binary struct {
+	op   byte
+	x, y node
+}
+
+func (b binary) eval(e env) (float64, bool) {
+	x, ok := b.x.eval(e)
+	if !ok {
+		return 0, false
+	}
+	y, ok := b.y.eval(e)
+	if !ok {
+		return 0, false
+	}
+	switch b.op {
+	case '+':
+		return x + y, true
+	case '-':
+		return x - y, true
+	case '*':
+		return x * y, true
+	}
+	if y == 0 {
+		return 0, false
+	}
+	return x / y, true
+}
+
+// expression is a parsed expression and the counters of its functions.
+type expression struct {
+	root     node
+	counters []call
+}
+
+// parseExpression parses the arithmetic of the fields and numbers with +, -, *, / and the parentheses, the fields
+// are names of letters, digits and underscores, or any name between double quotes like "% Free Space", and
+// rate(field) and delta(field) are the change per second and the change of the counter since the previous metric.
+func parseExpression(s string) (*expression, error) {
+	p := &parser{input: s}
+	p.next()
+	root, err := p.sum()
+	if err != nil {
+		return nil, fmt.Errorf("invalid expression %q: %v", s, err)
+	}
+	if p.token.kind != tokenEnd {
+		return nil, fmt.Errorf("invalid expression %q: unexpected %s", s, p.token)
+	}
+	return &expression{root: root, counters: p.counters}, nil
+}
+
+type tokenKind int
+
+const (
+	tokenEnd tokenKind = iota
+	tokenNumber
+	tokenName
+	tokenOperator
+	tokenInvalid
+)
+
+type token struct {
+	kind  tokenKind
+	text  string
+	value float64
+}
+
+func (t token) String() string {
+	if t.kind == tokenEnd {
+		return "end of expression"
+	}
+	return strconv.Quote(t.text)
+}
+
+type parser struct {
+	input    string
+	pos      int
+	token    token
+	counters []call
+}
+
+// next reads the token following the current one.
+func (p *parser) next() {
+	for p.pos < len(p.input) && p.input[p.pos] == ' ' {
+		p.pos++
+	}
+	if p.pos == len(p.input) {
+		p.token = token{kind: tokenEnd}
+		return
+	}
+	start := p.pos
+	c := p.input[p.pos]
+	switch {
+	case strings.IndexByte("+-*/()", c) >= 0:
+		p.pos++
+		p.token = token{kind: tokenOperator, text: string(c)}
+	case c == '"':
+		end := strings.IndexByte(p.input[start+1:], '"')
+		if end <= 0 {
+			p.pos = len(p.input)
+			p.token = token{kind: tokenInvalid, text: p.input[start:]}
+			return
+		}
+		p.pos = start + end + 2
+		p.token = token{kind: tokenName, text: p.input[start+1 : start+end+1]}
+	case c >= '0' && c <= '9' || c == '.':
+		for p.pos < len(p.input) && (isDigit(p.input[p.pos]) || p.input[p.pos] == '.' || p.isExponent()) {
+			p.pos++
+		}
+		text := p.input[start:p.pos]
+		value, err := strconv.ParseFloat(text, 64)
+		if err != nil {
+			p.token = token{kind: tokenInvalid, text: text}
+			return
+		}
+		p.token = token{kind: tokenNumber, text: text, value: value}
+	case isNameStart(rune(c)):
+		for p.pos < len(p.input) && (isNameStart(rune(p.input[p.pos])) || isDigit(p.input[p.pos])) {
+			p.pos++
+		}
+		p.token = token{kind: tokenName, text: p.input[start:p.pos]}
+	default:
+		p.pos++
+		p.token = token{kind: tokenInvalid, text: string(c)}
+	}
+}
+
+// isExponent checks if the number continues with an exponent, e.g. the e or the sign of 1e-3.
+func (p *parser) isExponent() bool {
+	c := p.input[p.pos]
+	if c == 'e' || c == 'E' {
+		return true
+	}
+	previous := p.input[p.pos-1]
+	return (c == '+' || c == '-') && (previous == 'e' || previous == 'E')
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isNameStart(r rune) bool {
+	return r == '_' || r < unicode.MaxASCII && unicode.IsLetter(r)
+}
+
+func (p *parser) isOperator(op string) bool {
+	return p.token.kind == tokenOperator && p.token.text == op
+}
+
+// sum parses the terms added or subtracted.
+func (p *parser) sum() (node, error) {
+	x, err := p.product()
+	if err != nil {
+		return nil, err
+	}
+	for p.isOperator("+") || p.isOperator("-") {
+		op := p.token.text[0]
+		p.next()
+		y, err := p.product()
+		if err != nil {
+			return nil, err
+		}
+		x = binary{op: op, x: x, y: y}
+	}
+	return x, nil
+}
+
+// product parses the factors multiplied or divided.
+func (p *parser) product() (node, error) {
+	x, err := p.factor()
+	if err != nil {
+		return nil, err
+	}
+	for p.isOperator("*") || p.isOperator("/") {
+		op := p.token.text[0]
+		p.next()
+		y, err := p.factor()
+		if err != nil {
+			return nil, err
+		}
+		x = binary{op: op, x: x, y: y}
+	}
+	return x, nil
+}
+
+// factor parses a number, a field, a counter function, a negation or an expression between parentheses.
+func (p *parser) factor() (node, error) {
+	t := p.token
+	switch {
+	case t.kind == tokenNumber:
+		p.next()
+		return number(t.value), nil
+	case t.kind == tokenName:
+		p.next()
+		if !p.isOperator("(") {
+			return field(t.text), nil
+		}
+		if t.text != functionRate && t.text != functionDelta {
+			return nil, fmt.Errorf("unknown function %s, it must be %s or %s", t.text, functionRate, functionDelta)
+		}
+		p.next()
+		if p.token.kind != tokenName {
+			return nil, fmt.Errorf("%s requires a field, got %s", t.text, p.token)
+		}
+		c := call{function: t.text, field: p.token.text}
+		p.next()
+		if !p.isOperator(")") {
+			return nil, fmt.Errorf("missing ) after the field of %s, got %s", t.text, p.token)
+		}
+		p.next()
+		p.counters = append(p.counters, c)
+		return c, nil
+	case p.isOperator("-"):
+		p.next()
+		x, err := p.factor()
+		if err != nil {
+			return nil, err
+		}
+		return negation{x: x}, nil
+	case p.isOperator("("):
+		p.next()
+		x, err := p.sum()
+		if err != nil {
+			return nil, err
+		}
+		if !p.isOperator(")") {
+			return nil, fmt.Errorf("missing ), got %s", p.token)
+		}
+		p.next()
+		return x, nil
+	}
+	return nil, fmt.Errorf("unexpected %s", t)
+}
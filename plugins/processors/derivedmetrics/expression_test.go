@@ -0,0 +1,66 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package derivedmetrics
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testEnv map[string]float64
+
+func (e testEnv) field(name string) (float64, bool) {
+	v, ok := e[name]
+	return v, ok
+}
+
+func (e testEnv) counter(function, name string) (float64, bool) {
+	v, ok := e[function+":"+name]
+	return v, ok
+}
+
+func TestParseExpression(t *testing.T) {
+	e := testEnv{"used": 25, "total": 200, "% Free Space": 40, "rate:bytes": 10, "delta:packets": 3}
+	for expression, expected := range map[string]float64{
+		"used / total * 100":       12.5,
+		"100 - used / total * 100": 87.5,
+		"(used + 25) * 2":          100,
+		"-used + 1e2":              75,
+		"2.5e-1 * total":           50,
+		`"% Free Space" / 100`:     0.4,
+		"rate(bytes) * 8":          80,
+		"delta(packets) - -1":      4,
+	} {
+		p, err := parseExpression(expression)
+		require.NoError(t, err, expression)
+		value, ok := p.root.eval(e)
+		assert.True(t, ok, expression)
+		assert.Equal(t, expected, value, expression)
+	}
+}
+
+func TestParseExpression_Counters(t *testing.T) {
+	p, err := parseExpression("rate(bytes_sent) + delta(bytes_recv) + bytes_dropped")
+	require.NoError(t, err)
+	assert.Equal(t, []call{{function: functionRate, field: "bytes_sent"}, {function: functionDelta, field: "bytes_recv"}}, p.counters)
+}
+
+func TestParseExpression_NoValue(t *testing.T) {
+	e := testEnv{"used": 25, "total": 0}
+	for _, expression := range []string{"used / total", "used + missing", "rate(used)"} {
+		p, err := parseExpression(expression)
+		require.NoError(t, err, expression)
+		_, ok := p.root.eval(e)
+		assert.False(t, ok, expression)
+	}
+}
+
+func TestParseExpression_Invalid(t *testing.T) {
+	for _, expression := range []string{"", "used +", "(used", "used total", "rate(1)", "rate(used", "log(used)", `"used`, "used % 2", "1.2.3"} {
+		_, err := parseExpression(expression)
+		assert.Error(t, err, expression)
+	}
+}
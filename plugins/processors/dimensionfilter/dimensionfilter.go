@@ -0,0 +1,182 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package dimensionfilter
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/aws/amazon-cloudwatch-agent/internal/metricscommon"
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/filter"
+	"github.com/influxdata/telegraf/plugins/processors"
+)
+
+var sampleConfig = `
+  ## Each rule keeps or drops the dimensions of the metrics whose name matches the pattern, the rules matching a
+  ## metric all apply in order. keep drops every other dimension, drop removes the listed ones.
+  [[processors.dimensionfilter.rule]]
+    metric_name = "cpu_usage_*"
+    drop = ["cpu"]
+  [[processors.dimensionfilter.rule]]
+    metric_name = "*"
+    # keep = ["InstanceId", "path"]
+    drop = ["host"]
+`
+
+// Rule keeps or drops the dimensions of the metrics whose name matches a pattern.
+type Rule struct {
+	MetricName string   `toml:"metric_name"`
+	Keep       []string `toml:"keep"`
+	Drop       []string `toml:"drop"`
+
+	metricName filter.Filter
+	keep       map[string]struct{}
+	drop       map[string]struct{}
+}
+
+type DimensionFilter struct {
+	Rules []*Rule `toml:"rule"`
+}
+
+func (d *DimensionFilter) SampleConfig() string {
+	return sampleConfig
+}
+
+func (d *DimensionFilter) Description() string {
+	return "Keep or drop the dimensions of the metrics by metric name pattern."
+}
+
+func (d *DimensionFilter) Init() error {
+	for _, r := range d.Rules {
+		if r.MetricName == "" {
+			return fmt.Errorf("dimensionfilter: a rule has no metric_name")
+		}
+		if len(r.Keep) == 0 && len(r.Drop) == 0 {
+			return fmt.Errorf("dimensionfilter: the rule of %s neither keeps nor drops dimensions", r.MetricName)
+		}
+		f, err := filter.Compile([]string{r.MetricName})
+		if err != nil {
+			return fmt.Errorf("dimensionfilter: invalid metric_name %s: %v", r.MetricName, err)
+		}
+		r.metricName = f
+		r.keep = toSet(r.Keep)
+		r.drop = toSet(r.Drop)
+	}
+	return nil
+}
+
+func toSet(values []string) map[string]struct{} {
+	if len(values) == 0 {
+		return nil
+	}
+	set := make(map[string]struct{}, len(values))
+	for _, v := range values {
+		set[v] = struct{}{}
+	}
+	return set
+}
+
+// Apply removes the dimensions of the rules matching the metric names of the fields. The fields of a metric whose
+// names match different rules are split into a metric by the dimensions they keep.
+func (d *DimensionFilter) Apply(in ...telegraf.Metric) []telegraf.Metric {
+	if len(d.Rules) == 0 {
+		return in
+	}
+	out := make([]telegraf.Metric, 0, len(in))
+	for _, m := range in {
+		// the fields by the dimensions they drop
+		groups := map[string][]string{}
+		dropped := map[string][]string{}
+		for _, f := range m.FieldList() {
+			// the names of the metric_decoration renames aren't matched
+			tags := d.droppedTags(m, metricscommon.MetricName(m.Name(), f.Key))
+			key := strings.Join(tags, ",")
+			groups[key] = append(groups[key], f.Key)
+			dropped[key] = tags
+		}
+		if len(groups) <= 1 {
+			for _, tags := range dropped {
+				for _, t := range tags {
+					m.RemoveTag(t)
+				}
+			}
+			out = append(out, m)
+			continue
+		}
+		keys := make([]string, 0, len(groups))
+		for key := range groups {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			split := m.Copy()
+			for _, f := range m.FieldList() {
+				if !contains(groups[key], f.Key) {
+					split.RemoveField(f.Key)
+				}
+			}
+			for _, t := range dropped[key] {
+				split.RemoveTag(t)
+			}
+			out = append(out, split)
+		}
+		m.Drop()
+	}
+	return out
+}
+
+// droppedTags returns the sorted tags the rules drop from the metric of the name.
+func (d *DimensionFilter) droppedTags(m telegraf.Metric, name string) []string {
+	keys := make([]string, 0, len(m.TagList()))
+	for _, t := range m.TagList() {
+		keys = append(keys, t.Key)
+	}
+	return d.DroppedDimensions(name, keys)
+}
+
+// DroppedDimensions returns the sorted dimensions the rules drop from the metric of the name, the metric catalog of
+// the translator uses it to list the dimensions left.
+func (d *DimensionFilter) DroppedDimensions(name string, dimensions []string) []string {
+	var dropped []string
+	for _, r := range d.Rules {
+		if !r.metricName.Match(name) {
+			continue
+		}
+		for _, key := range dimensions {
+			if internalTag(key) || contains(dropped, key) {
+				continue
+			}
+			_, kept := r.keep[key]
+			_, drop := r.drop[key]
+			if drop || r.keep != nil && !kept {
+				dropped = append(dropped, key)
+			}
+		}
+	}
+	sort.Strings(dropped)
+	return dropped
+}
+
+// internalTag checks if the tag is used by the agent rather than published as a dimension, like the metricPath of
+// the outputs and the aws: tags of the aggregation interval and the storage resolution.
+func internalTag(key string) bool {
+	return key == "metricPath" || strings.HasPrefix(key, "aws:")
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func init() {
+	processors.Add("dimensionfilter", func() telegraf.Processor {
+		return &DimensionFilter{}
+	})
+}
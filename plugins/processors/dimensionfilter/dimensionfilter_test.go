@@ -0,0 +1,86 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package dimensionfilter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/metric"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func createTestMetric(name string, tags map[string]string, fields map[string]interface{}) telegraf.Metric {
+	m, _ := metric.New(name, tags, fields, time.Now())
+	return m
+}
+
+func TestApply(t *testing.T) {
+	d := &DimensionFilter{Rules: []*Rule{
+		{MetricName: "*", Drop: []string{"host"}},
+		{MetricName: "disk_*", Keep: []string{"InstanceId", "path"}},
+	}}
+	require.NoError(t, d.Init())
+
+	result := d.Apply(
+		createTestMetric("cpu", map[string]string{"host": "ip-10-0-0-1", "cpu": "cpu-total", "metricPath": "metrics"},
+			map[string]interface{}{"usage_idle": 90.0}),
+		createTestMetric("disk", map[string]string{"host": "ip-10-0-0-1", "InstanceId": "i-0123456789abcdef0", "path": "/", "device": "nvme0n1", "fstype": "xfs"},
+			map[string]interface{}{"used_percent": 25.0, "free": int64(1024)}),
+	)
+	require.Len(t, result, 2)
+	// the tags of the agent are never dropped
+	assert.Equal(t, map[string]string{"cpu": "cpu-total", "metricPath": "metrics"}, result[0].Tags())
+	assert.Equal(t, map[string]string{"InstanceId": "i-0123456789abcdef0", "path": "/"}, result[1].Tags())
+	assert.Equal(t, map[string]interface{}{"used_percent": 25.0, "free": int64(1024)}, result[1].Fields())
+}
+
+func TestApply_Split(t *testing.T) {
+	d := &DimensionFilter{Rules: []*Rule{{MetricName: "cpu_usage_*", Drop: []string{"cpu"}}}}
+	require.NoError(t, d.Init())
+
+	result := d.Apply(createTestMetric("cpu", map[string]string{"cpu": "cpu0", "aws:StorageResolution": "true"},
+		map[string]interface{}{"usage_idle": 90.0, "usage_user": 5.0, "time_idle": 1000.0}))
+	require.Len(t, result, 2)
+	// the fields keeping all their dimensions first
+	assert.Equal(t, map[string]string{"cpu": "cpu0", "aws:StorageResolution": "true"}, result[0].Tags())
+	assert.Equal(t, map[string]interface{}{"time_idle": 1000.0}, result[0].Fields())
+	assert.Equal(t, map[string]string{"aws:StorageResolution": "true"}, result[1].Tags())
+	assert.Equal(t, map[string]interface{}{"usage_idle": 90.0, "usage_user": 5.0}, result[1].Fields())
+	assert.Equal(t, "cpu", result[1].Name())
+}
+
+func TestApply_NoMatch(t *testing.T) {
+	d := &DimensionFilter{Rules: []*Rule{{MetricName: "mem_*", Drop: []string{"host"}}}}
+	require.NoError(t, d.Init())
+
+	m := createTestMetric("cpu", map[string]string{"host": "ip-10-0-0-1"}, map[string]interface{}{"usage_idle": 90.0})
+	result := d.Apply(m)
+	require.Len(t, result, 1)
+	assert.Equal(t, map[string]string{"host": "ip-10-0-0-1"}, result[0].Tags())
+}
+
+func TestDroppedDimensions(t *testing.T) {
+	d := &DimensionFilter{Rules: []*Rule{
+		{MetricName: "cpu_usage_*", Drop: []string{"cpu"}},
+		{MetricName: "*", Keep: []string{"cpu", "InstanceId"}},
+	}}
+	require.NoError(t, d.Init())
+
+	assert.Equal(t, []string{"cpu", "host"}, d.DroppedDimensions("cpu_usage_idle", []string{"host", "cpu", "InstanceId", "aws:StorageResolution"}))
+	assert.Equal(t, []string{"host"}, d.DroppedDimensions("mem_used_percent", []string{"host", "InstanceId"}))
+}
+
+func TestInit_Invalid(t *testing.T) {
+	for _, r := range []*Rule{
+		{Drop: []string{"host"}},
+		{MetricName: "cpu_*"},
+		{MetricName: "cpu_[", Drop: []string{"host"}},
+	} {
+		d := &DimensionFilter{Rules: []*Rule{r}}
+		assert.Error(t, d.Init(), r.MetricName)
+	}
+}
@@ -0,0 +1,155 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package dimensionlookup
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/aws/amazon-cloudwatch-agent/internal"
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/plugins/processors"
+)
+
+const defaultReloadInterval = time.Minute
+
+var sampleConfig = `
+  ## Each table maps the values of a dimension to friendly ones, the values not in the table are kept as is.
+  [[processors.dimensionlookup.table]]
+    dimension = "device"
+    ## A JSON object of the values and their friendly value, merged over the values below and read again when
+    ## it changes, checked at most once per reload_interval.
+    # file = "/opt/aws/amazon-cloudwatch-agent/etc/devices.json"
+    # reload_interval = "60s"
+    [processors.dimensionlookup.table.values]
+      "2f4b0a6e-8c1d-4b0e-9d4a-3c2e1f0a9b8c" = "data"
+`
+
+// Table is the lookup table of the values of a dimension.
+type Table struct {
+	Dimension      string            `toml:"dimension"`
+	Values         map[string]string `toml:"values"`
+	File           string            `toml:"file"`
+	ReloadInterval internal.Duration `toml:"reload_interval"`
+
+	mu      sync.Mutex
+	lookup  map[string]string
+	modTime time.Time
+	checked time.Time
+	// the error of the last read of the file, logged once until the file changes
+	lastErr string
+}
+
+type DimensionLookup struct {
+	Tables []*Table `toml:"table"`
+
+	// the clock, replaced in tests
+	now func() time.Time
+}
+
+func (d *DimensionLookup) SampleConfig() string {
+	return sampleConfig
+}
+
+func (d *DimensionLookup) Description() string {
+	return "Map the values of dimensions to friendly ones with lookup tables."
+}
+
+func (d *DimensionLookup) Init() error {
+	if d.now == nil {
+		d.now = time.Now
+	}
+	for _, t := range d.Tables {
+		if t.Dimension == "" {
+			return fmt.Errorf("dimensionlookup: a table has no dimension")
+		}
+		if t.ReloadInterval.Duration <= 0 {
+			t.ReloadInterval.Duration = defaultReloadInterval
+		}
+		t.lookup = t.Values
+		if t.File != "" {
+			// the agent doesn't start with a file it can't read, the later changes are only logged once they fail
+			if err := d.reload(t); err != nil {
+				return fmt.Errorf("dimensionlookup: %v", err)
+			}
+		}
+	}
+	return nil
+}
+
+// Apply replaces the values of the dimensions found in their table, the dimensions without a friendly value are left
+// as published.
+func (d *DimensionLookup) Apply(in ...telegraf.Metric) []telegraf.Metric {
+	for _, t := range d.Tables {
+		lookup := d.table(t)
+		for _, metric := range in {
+			value, ok := metric.GetTag(t.Dimension)
+			if !ok {
+				continue
+			}
+			if friendly, ok := lookup[value]; ok && friendly != "" {
+				metric.AddTag(t.Dimension, friendly)
+			}
+		}
+	}
+	return in
+}
+
+// table returns the lookup table, the file is read again when it changed since the last check.
+func (d *DimensionLookup) table(t *Table) map[string]string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.File == "" || d.now().Sub(t.checked) < t.ReloadInterval.Duration {
+		return t.lookup
+	}
+	if err := d.reload(t); err != nil && err.Error() != t.lastErr {
+		t.lastErr = err.Error()
+		log.Printf("E! dimensionlookup: %v, the previous values of %s are kept", err, t.Dimension)
+	}
+	return t.lookup
+}
+
+// reload reads the file of the table when its modification time changed, its values are merged over the values of
+// the configuration.
+func (d *DimensionLookup) reload(t *Table) error {
+	t.checked = d.now()
+	info, err := os.Stat(t.File)
+	if err != nil {
+		return fmt.Errorf("unable to read the lookup table %s: %v", t.File, err)
+	}
+	if info.ModTime().Equal(t.modTime) {
+		return nil
+	}
+	b, err := ioutil.ReadFile(t.File)
+	if err != nil {
+		return fmt.Errorf("unable to read the lookup table %s: %v", t.File, err)
+	}
+	var values map[string]string
+	if err := json.Unmarshal(b, &values); err != nil {
+		return fmt.Errorf("invalid lookup table %s, it must be a JSON object of strings: %v", t.File, err)
+	}
+	lookup := make(map[string]string, len(t.Values)+len(values))
+	for k, v := range t.Values {
+		lookup[k] = v
+	}
+	for k, v := range values {
+		lookup[k] = v
+	}
+	if !t.modTime.IsZero() {
+		log.Printf("I! dimensionlookup: reloaded the %d values of %s from %s", len(values), t.Dimension, t.File)
+	}
+	t.lookup, t.modTime, t.lastErr = lookup, info.ModTime(), ""
+	return nil
+}
+
+func init() {
+	processors.Add("dimensionlookup", func() telegraf.Processor {
+		return &DimensionLookup{}
+	})
+}
@@ -0,0 +1,93 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package dimensionlookup
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/aws/amazon-cloudwatch-agent/internal"
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/metric"
+	"github.com/stretchr/testify/assert"
+)
+
+func createTestMetric(tags map[string]string) telegraf.Metric {
+	m, _ := metric.New("disk",
+		tags,
+		map[string]interface{}{
+			"used_percent": float64(10),
+		},
+		time.Now(),
+	)
+	return m
+}
+
+func TestApply(t *testing.T) {
+	d := &DimensionLookup{Tables: []*Table{
+		{Dimension: "device", Values: map[string]string{"nvme0n1p1": "root", "nvme1n1": "data"}},
+		{Dimension: "InstanceId", Values: map[string]string{"i-0123456789abcdef0": "checkout"}},
+	}}
+	assert.NoError(t, d.Init())
+
+	result := d.Apply(
+		createTestMetric(map[string]string{"device": "nvme1n1", "InstanceId": "i-0123456789abcdef0"}),
+		createTestMetric(map[string]string{"device": "xvdf", "path": "/mnt"}),
+	)
+	assert.Equal(t, map[string]string{"device": "data", "InstanceId": "checkout"}, result[0].Tags())
+	// the values without a friendly value are kept
+	assert.Equal(t, map[string]string{"device": "xvdf", "path": "/mnt"}, result[1].Tags())
+}
+
+func TestApply_FileReloaded(t *testing.T) {
+	dir, err := ioutil.TempDir("", "dimensionlookup")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "devices.json")
+	assert.NoError(t, ioutil.WriteFile(file, []byte(`{"nvme1n1": "data"}`), 0600))
+
+	now := time.Now()
+	d := &DimensionLookup{
+		Tables: []*Table{{
+			Dimension:      "device",
+			Values:         map[string]string{"nvme0n1p1": "root", "nvme1n1": "scratch"},
+			File:           file,
+			ReloadInterval: internal.Duration{Duration: time.Minute},
+		}},
+		now: func() time.Time { return now },
+	}
+	assert.NoError(t, d.Init())
+	apply := func(device string) string {
+		return d.Apply(createTestMetric(map[string]string{"device": device}))[0].Tags()["device"]
+	}
+	// the values of the file override the ones of the configuration
+	assert.Equal(t, "data", apply("nvme1n1"))
+	assert.Equal(t, "root", apply("nvme0n1p1"))
+
+	assert.NoError(t, ioutil.WriteFile(file, []byte(`{"nvme1n1": "logs"}`), 0600))
+	assert.NoError(t, os.Chtimes(file, now, now.Add(time.Second)))
+	// the file is only checked once the reload interval elapsed
+	assert.Equal(t, "data", apply("nvme1n1"))
+	now = now.Add(time.Minute)
+	assert.Equal(t, "logs", apply("nvme1n1"))
+
+	// an invalid file keeps the previous values
+	assert.NoError(t, ioutil.WriteFile(file, []byte(`["nvme1n1"]`), 0600))
+	assert.NoError(t, os.Chtimes(file, now, now.Add(2*time.Second)))
+	now = now.Add(time.Minute)
+	assert.Equal(t, "logs", apply("nvme1n1"))
+}
+
+func TestInit_Errors(t *testing.T) {
+	d := &DimensionLookup{Tables: []*Table{{Values: map[string]string{"a": "b"}}}}
+	assert.EqualError(t, d.Init(), "dimensionlookup: a table has no dimension")
+
+	d = &DimensionLookup{Tables: []*Table{{Dimension: "device", File: "/nonexistent/devices.json"}}}
+	err := d.Init()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "dimensionlookup: unable to read the lookup table /nonexistent/devices.json")
+}
@@ -74,8 +74,8 @@ const sampleConfig = `
 `
 
 const (
-	ec2InstanceTagKeyASG = "aws:autoscaling:groupName"
-	cwDimensionASG       = "AutoScalingGroupName"
+	EC2InstanceTagKeyASG = "aws:autoscaling:groupName"
+	CWDimensionASG       = "AutoScalingGroupName"
 	mdKeyInstanceId      = "InstanceId"
 	mdKeyImageId         = "ImageId"
 	mdKeyInstaneType     = "InstanceType"
@@ -198,9 +198,9 @@ func (t *Tagger) updateTags() error {
 		}
 		for _, tag := range result.Tags {
 			key := *tag.Key
-			if ec2InstanceTagKeyASG == key {
+			if EC2InstanceTagKeyASG == key {
 				// rename to match CW dimension as applied by AutoScaling service, not the EC2 tag
-				key = cwDimensionASG
+				key = CWDimensionASG
 			}
 			tags[key] = *tag.Value
 		}
@@ -266,8 +266,8 @@ func (t *Tagger) ec2TagsRetrieved() bool {
 	defer t.RUnlock()
 	if t.ec2TagCache != nil {
 		for _, key := range t.EC2InstanceTagKeys {
-			if key == ec2InstanceTagKeyASG {
-				key = cwDimensionASG
+			if key == EC2InstanceTagKeyASG {
+				key = CWDimensionASG
 			}
 			if key == "*" {
 				continue
@@ -351,8 +351,8 @@ func (t *Tagger) Init() error {
 		// if the customer said 'AutoScalingGroupName' (the CW dimension), do what they mean not what they said
 		// and filter for the EC2 tag name called 'aws:autoscaling:groupName'
 		for i, key := range t.EC2InstanceTagKeys {
-			if cwDimensionASG == key {
-				t.EC2InstanceTagKeys[i] = ec2InstanceTagKeyASG
+			if CWDimensionASG == key {
+				t.EC2InstanceTagKeys[i] = EC2InstanceTagKeyASG
 			}
 		}
 
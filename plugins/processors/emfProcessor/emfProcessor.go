@@ -4,8 +4,6 @@
 package emfProcessor
 
 import (
-	"log"
-
 	"github.com/aws/amazon-cloudwatch-agent/internal/structuredlogscommon"
 	"github.com/influxdata/telegraf"
 	"github.com/influxdata/telegraf/plugins/processors"
@@ -17,8 +15,10 @@ type EmfProcessor struct {
 	MetricDeclarations      []*metricDeclaration `toml:"metric_declaration"`
 	MetricNamespace         string               `toml:"metric_namespace"`
 	MetricUnit              map[string]string    `toml:"metric_unit"`
+	// the units of the metrics of a job, they override the metric_unit of the same metrics
+	MetricUnitByJob map[string]map[string]string `toml:"metric_unit_by_job"`
 
-	validMetricUnit map[string]string
+	units *unitResolver
 }
 
 var supportedUnits = map[string]struct{}{
@@ -63,8 +63,15 @@ func (e *EmfProcessor) SampleConfig() string {
       labels_separator = ";"
       metric_selectors = ["^nginx_ingress_controller_requests$"]
       source_labels = ["Service"]
+    ## the metric names are exact or globs where "*" matches any characters, the exact names and then the longest
+    ## globs are preferred
     [processors.emfProcessor.metric_unit]
       nginx_ingress_controller_requests = "Count"
+      "*_seconds" = "Seconds"
+      "*_bytes" = "Bytes"
+    ## the units of the metrics of a prometheus job, the other metrics of the job fall back to metric_unit
+    [processors.emfProcessor.metric_unit_by_job.jmx]
+      "*_time" = "Milliseconds"
 `
 }
 
@@ -79,14 +86,7 @@ func (e *EmfProcessor) Apply(in ...telegraf.Metric) (result []telegraf.Metric) {
 		}
 
 		// Metric units validation
-		e.validMetricUnit = make(map[string]string)
-		for k, v := range e.MetricUnit {
-			if _, ok := supportedUnits[v]; ok {
-				e.validMetricUnit[k] = v
-			} else {
-				log.Println("W! detect unsupported unit: ", v)
-			}
-		}
+		e.units = newUnitResolver(e.MetricUnit, e.MetricUnitByJob)
 
 		e.inited = true
 	}
@@ -97,9 +97,10 @@ func (e *EmfProcessor) Apply(in ...telegraf.Metric) (result []telegraf.Metric) {
 		fields := metric.Fields()
 
 		var rules []structuredlogscommon.MetricRule
+		units := e.units.units(tags, fields)
 		// metric go through each MetricDeclaration filter to build MetricRules
 		for _, declaration := range e.MetricDeclarations {
-			retRule := declaration.process(tags, fields, e.MetricNamespace, e.validMetricUnit)
+			retRule := declaration.process(tags, fields, e.MetricNamespace, units)
 			if retRule != nil {
 				rules = append(rules, *retRule)
 			}
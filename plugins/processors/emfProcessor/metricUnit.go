@@ -0,0 +1,134 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package emfProcessor
+
+import (
+	"log"
+	"sort"
+	"strings"
+)
+
+const (
+	jobTag       = "job"
+	unitWildcard = "*"
+)
+
+// unitMapping maps the metric names to their unit, the names are exact or globs where "*" matches any characters,
+// e.g. "*_seconds" sets the unit of every metric ending with _seconds.
+type unitMapping struct {
+	exact    map[string]string
+	patterns []unitPattern
+}
+
+type unitPattern struct {
+	pattern string
+	unit    string
+}
+
+func newUnitMapping(units map[string]string) *unitMapping {
+	u := &unitMapping{exact: map[string]string{}}
+	for k, v := range units {
+		if _, ok := supportedUnits[v]; !ok {
+			log.Println("W! detect unsupported unit: ", v)
+			continue
+		}
+		if strings.Contains(k, unitWildcard) {
+			u.patterns = append(u.patterns, unitPattern{pattern: k, unit: v})
+		} else {
+			u.exact[k] = v
+		}
+	}
+	// the most specific pattern wins, the one with the most characters besides the wildcards, e.g.
+	// "*_bytes_total" is preferred over "*_total" for node_network_receive_bytes_total
+	sort.Slice(u.patterns, func(i, j int) bool {
+		li := len(u.patterns[i].pattern) - strings.Count(u.patterns[i].pattern, unitWildcard)
+		lj := len(u.patterns[j].pattern) - strings.Count(u.patterns[j].pattern, unitWildcard)
+		if li != lj {
+			return li > lj
+		}
+		return u.patterns[i].pattern < u.patterns[j].pattern
+	})
+	return u
+}
+
+func (u *unitMapping) empty() bool {
+	return len(u.exact) == 0 && len(u.patterns) == 0
+}
+
+func (u *unitMapping) lookup(name string) (string, bool) {
+	if unit, ok := u.exact[name]; ok {
+		return unit, true
+	}
+	for _, p := range u.patterns {
+		if matchGlob(p.pattern, name) {
+			return p.unit, true
+		}
+	}
+	return "", false
+}
+
+// unitResolver resolves the unit of the metrics of a job from the mapping of the job, then falls back to the global
+// mapping, so a job only lists the units it overrides.
+type unitResolver struct {
+	global *unitMapping
+	jobs   map[string]*unitMapping
+}
+
+func newUnitResolver(global map[string]string, byJob map[string]map[string]string) *unitResolver {
+	r := &unitResolver{global: newUnitMapping(global), jobs: map[string]*unitMapping{}}
+	for job, units := range byJob {
+		if m := newUnitMapping(units); !m.empty() {
+			r.jobs[job] = m
+		}
+	}
+	return r
+}
+
+func (r *unitResolver) empty() bool {
+	return r.global.empty() && len(r.jobs) == 0
+}
+
+func (r *unitResolver) unit(job string, name string) (string, bool) {
+	if m, ok := r.jobs[job]; ok {
+		if unit, ok := m.lookup(name); ok {
+			return unit, true
+		}
+	}
+	return r.global.lookup(name)
+}
+
+// units returns the unit of each field of the metric that has one.
+func (r *unitResolver) units(tags map[string]string, fields map[string]interface{}) map[string]string {
+	units := make(map[string]string, len(fields))
+	if r.empty() {
+		return units
+	}
+	for name := range fields {
+		if unit, ok := r.unit(tags[jobTag], name); ok {
+			units[name] = unit
+		}
+	}
+	return units
+}
+
+// matchGlob reports whether the name matches the pattern, where "*" matches any sequence of characters.
+func matchGlob(pattern, name string) bool {
+	parts := strings.Split(pattern, unitWildcard)
+	if len(parts) == 1 {
+		return pattern == name
+	}
+	if !strings.HasPrefix(name, parts[0]) {
+		return false
+	}
+	name = name[len(parts[0]):]
+	last := parts[len(parts)-1]
+	for _, part := range parts[1 : len(parts)-1] {
+		i := strings.Index(name, part)
+		if i < 0 {
+			return false
+		}
+		name = name[i+len(part):]
+	}
+	return len(name) >= len(last) && strings.HasSuffix(name, last)
+}
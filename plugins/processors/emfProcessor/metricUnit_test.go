@@ -0,0 +1,111 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package emfProcessor
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aws/amazon-cloudwatch-agent/internal/structuredlogscommon"
+	"github.com/influxdata/telegraf/metric"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_matchGlob(t *testing.T) {
+	assert.True(t, matchGlob("*_seconds", "http_request_duration_seconds"))
+	assert.True(t, matchGlob("*_seconds", "_seconds"))
+	assert.False(t, matchGlob("*_seconds", "http_request_duration_seconds_count"))
+	assert.True(t, matchGlob("jvm_*", "jvm_memory_bytes_used"))
+	assert.True(t, matchGlob("node_*_bytes_*", "node_network_receive_bytes_total"))
+	assert.False(t, matchGlob("node_*_bytes_*", "node_network_receive_packets_total"))
+	assert.True(t, matchGlob("*", "anything"))
+	assert.True(t, matchGlob("a*a", "aa"))
+	assert.False(t, matchGlob("a*a", "a"))
+	assert.True(t, matchGlob("exact", "exact"))
+	assert.False(t, matchGlob("exact", "exactly"))
+}
+
+func Test_unitMapping_lookup(t *testing.T) {
+	m := newUnitMapping(map[string]string{
+		"*_seconds":                         "Seconds",
+		"*_total":                           "Count",
+		"*_bytes_total":                     "Bytes",
+		"process_cpu_seconds":               "Milliseconds",
+		"*_ratio":                           "UnsupportedUnit",
+		"nginx_ingress_controller_requests": "Count",
+	})
+
+	for name, expected := range map[string]string{
+		"http_request_duration_seconds":     "Seconds",
+		"process_cpu_seconds":               "Milliseconds",
+		"http_requests_total":               "Count",
+		"node_network_receive_bytes_total":  "Bytes",
+		"nginx_ingress_controller_requests": "Count",
+	} {
+		unit, ok := m.lookup(name)
+		assert.True(t, ok, name)
+		assert.Equal(t, expected, unit, name)
+	}
+	// the unsupported units are dropped
+	_, ok := m.lookup("cache_hit_ratio")
+	assert.False(t, ok)
+	_, ok = m.lookup("up")
+	assert.False(t, ok)
+}
+
+func Test_unitResolver_unit(t *testing.T) {
+	r := newUnitResolver(
+		map[string]string{"*_seconds": "Seconds", "*_bytes": "Bytes", "jvm_gc_collection_seconds": "Seconds"},
+		map[string]map[string]string{
+			"jmx":   {"*_seconds": "Milliseconds"},
+			"empty": {"*_seconds": "UnsupportedUnit"},
+		})
+
+	// the job mapping overrides the global one, even its exact names
+	unit, ok := r.unit("jmx", "jvm_gc_collection_seconds")
+	assert.True(t, ok)
+	assert.Equal(t, "Milliseconds", unit)
+	// and inherits the metrics it doesn't map
+	unit, ok = r.unit("jmx", "jvm_memory_bytes")
+	assert.True(t, ok)
+	assert.Equal(t, "Bytes", unit)
+
+	unit, ok = r.unit("nginx", "jvm_gc_collection_seconds")
+	assert.True(t, ok)
+	assert.Equal(t, "Seconds", unit)
+	unit, ok = r.unit("empty", "request_seconds")
+	assert.True(t, ok)
+	assert.Equal(t, "Seconds", unit)
+	_, ok = r.unit("jmx", "up")
+	assert.False(t, ok)
+}
+
+func TestEmfProcessor_Apply_WithMetricUnitByJob(t *testing.T) {
+	ts := time.Now()
+	md := &metricDeclaration{
+		SourceLabels:    []string{"job"},
+		LabelMatcher:    "^(jmx|nginx)$",
+		MetricSelectors: []string{"_seconds$", "_bytes$"},
+		Dimensions:      [][]string{{"job"}},
+	}
+	e := &EmfProcessor{
+		MetricDeclarations: []*metricDeclaration{md},
+		MetricNamespace:    "ContainerInsights/Prometheus",
+		MetricUnit:         map[string]string{"*_seconds": "Seconds", "*_bytes": "Bytes"},
+		MetricUnitByJob:    map[string]map[string]string{"jmx": {"*_seconds": "Milliseconds"}},
+	}
+	jmx, _ := metric.New("prometheus_scraper", map[string]string{"job": "jmx"},
+		map[string]interface{}{"gc_seconds": 1.0}, ts)
+	nginx, _ := metric.New("prometheus_scraper", map[string]string{"job": "nginx"},
+		map[string]interface{}{"request_seconds": 1.0, "sent_bytes": 1.0}, ts)
+
+	result := e.Apply(jmx, nginx)
+	assert.Equal(t, 2, len(result))
+	rules, _ := result[0].GetField("CloudWatchMetrics")
+	assert.Equal(t, []structuredlogscommon.MetricAttr{{Name: "gc_seconds", Unit: "Milliseconds"}},
+		rules.([]structuredlogscommon.MetricRule)[0].Metrics)
+	rules, _ = result[1].GetField("CloudWatchMetrics")
+	assert.ElementsMatch(t, []structuredlogscommon.MetricAttr{{Name: "request_seconds", Unit: "Seconds"}, {Name: "sent_bytes", Unit: "Bytes"}},
+		rules.([]structuredlogscommon.MetricRule)[0].Metrics)
+}
@@ -0,0 +1,137 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package instancenormalizer
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/plugins/processors"
+)
+
+const (
+	NormalizeInstances string = "normalize_instances"
+	InstanceTagKey     string = "instance"
+	ObjectNameTagKey   string = "objectname"
+	// the default patterns only apply to the instances of this object, the instances of the other objects like the
+	// disk or network interface indexes are stable
+	defaultObjectName = "Process"
+)
+
+// The default patterns remove the volatile parts of the process instance names, in this order:
+// the guid suffix ("Session{1b4e28ba-2fa1-11d2-883f-0016d3cca427}"), the "#N" suffix of duplicated process names
+// ("svchost#3"), the pid suffix of ".NET CLR" style instances ("w3wp_1234") and the pid prefix of the W3SVC_W3WP
+// style instances ("1234_DefaultAppPool").
+var defaultPatterns = []string{
+	`[_#\- ]?\{?[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}\}?$`,
+	`#\d+$`,
+	`_\d+$`,
+	`^\d+_`,
+}
+
+var sampleConfig = `
+  ## Regular expressions removed from the instance tag, in order, for the objects which have no
+  ## object_patterns. Defaults to the guid suffix, the "#N" suffix, the "_<pid>" suffix and the
+  ## "<pid>_" prefix for the Process object only.
+  # patterns = []
+
+  ## Regular expressions removed from the instance tag of the given objects, in order.
+  # [processors.instancenormalizer.object_patterns]
+  #   W3SVC_W3WP = ['^\d+_']
+`
+
+type InstanceNormalizer struct {
+	Patterns       []string            `toml:"patterns"`
+	ObjectPatterns map[string][]string `toml:"object_patterns"`
+
+	patternsP       []*regexp.Regexp
+	objectPatternsP map[string][]*regexp.Regexp
+}
+
+func (n *InstanceNormalizer) SampleConfig() string {
+	return sampleConfig
+}
+
+func (n *InstanceNormalizer) Description() string {
+	return "Normalize volatile perf counter instance names into stable dimension values."
+}
+
+func (n *InstanceNormalizer) Init() error {
+	var err error
+	if len(n.Patterns) > 0 {
+		if n.patternsP, err = compilePatterns(n.Patterns); err != nil {
+			return err
+		}
+	}
+	n.objectPatternsP = map[string][]*regexp.Regexp{}
+	if len(n.Patterns) == 0 {
+		if n.objectPatternsP[defaultObjectName], err = compilePatterns(defaultPatterns); err != nil {
+			return err
+		}
+	}
+	for objectName, patterns := range n.ObjectPatterns {
+		if n.objectPatternsP[objectName], err = compilePatterns(patterns); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func compilePatterns(patterns []string) ([]*regexp.Regexp, error) {
+	compiled := make([]*regexp.Regexp, 0, len(patterns))
+	for _, p := range patterns {
+		pattern, err := regexp.Compile(p)
+		if err != nil {
+			return nil, fmt.Errorf("instancenormalizer pattern has issue, regexp: Compile( %v ): %v", p, err)
+		}
+		compiled = append(compiled, pattern)
+	}
+	return compiled, nil
+}
+
+// Apply rewrites the instance tag of the metrics that carry the normalize_instances tag, so a process that restarts
+// with a new pid or a new "#N" index keeps publishing to the same series.
+func (n *InstanceNormalizer) Apply(in ...telegraf.Metric) []telegraf.Metric {
+	for _, metric := range in {
+		if _, ok := metric.GetTag(NormalizeInstances); !ok {
+			continue
+		}
+		//remove the transient tag
+		metric.RemoveTag(NormalizeInstances)
+
+		instance, ok := metric.GetTag(InstanceTagKey)
+		if !ok {
+			continue
+		}
+		objectName, _ := metric.GetTag(ObjectNameTagKey)
+		normalized := n.normalize(objectName, instance)
+		if normalized != instance {
+			metric.AddTag(InstanceTagKey, normalized)
+		}
+	}
+	return in
+}
+
+func (n *InstanceNormalizer) normalize(objectName, instance string) string {
+	patterns, ok := n.objectPatternsP[objectName]
+	if !ok {
+		patterns = n.patternsP
+	}
+	normalized := instance
+	for _, pattern := range patterns {
+		normalized = pattern.ReplaceAllString(normalized, "")
+	}
+	// never publish an empty dimension value
+	if normalized == "" {
+		return instance
+	}
+	return normalized
+}
+
+func init() {
+	processors.Add("instancenormalizer", func() telegraf.Processor {
+		return &InstanceNormalizer{}
+	})
+}
@@ -0,0 +1,105 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package instancenormalizer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/metric"
+	"github.com/stretchr/testify/assert"
+)
+
+func createTestMetric(tags map[string]string) telegraf.Metric {
+	m, _ := metric.New("Process",
+		tags,
+		map[string]interface{}{
+			"% Processor Time": float64(10),
+		},
+		time.Now(),
+	)
+	return m
+}
+
+func TestDefaultPatterns(t *testing.T) {
+	n := &InstanceNormalizer{}
+	assert.NoError(t, n.Init())
+
+	for instance, expected := range map[string]string{
+		"svchost#3":           "svchost",
+		"w3wp_1234":           "w3wp",
+		"1234_DefaultAppPool": "DefaultAppPool",
+		"Session{1b4e28ba-2fa1-11d2-883f-0016d3cca427}": "Session",
+		"vmms_1b4e28ba-2fa1-11d2-883f-0016d3cca427":     "vmms",
+		"_Total": "_Total",
+		"C:":     "C:",
+		"1234":   "1234",
+	} {
+		m := createTestMetric(map[string]string{"instance": instance, "objectname": "Process", "normalize_instances": "true"})
+		result := n.Apply(m)
+		assert.Equal(t, 1, len(result))
+		assert.Equal(t, map[string]string{"instance": expected, "objectname": "Process"}, result[0].Tags(), instance)
+	}
+}
+
+func TestDefaultPatternsOtherObjects(t *testing.T) {
+	n := &InstanceNormalizer{}
+	assert.NoError(t, n.Init())
+
+	// the indexes of the disks and network interfaces are stable
+	for objectName, instance := range map[string]string{
+		"PhysicalDisk":      "0_1",
+		"Network Interface": "Ethernet_2",
+		"Processor":         "0_3",
+	} {
+		m := createTestMetric(map[string]string{"instance": instance, "objectname": objectName, "normalize_instances": "true"})
+		result := n.Apply(m)
+		assert.Equal(t, map[string]string{"instance": instance, "objectname": objectName}, result[0].Tags(), objectName)
+	}
+}
+
+func TestObjectPatterns(t *testing.T) {
+	n := &InstanceNormalizer{ObjectPatterns: map[string][]string{"W3SVC_W3WP": {`^\d+_`}}}
+	assert.NoError(t, n.Init())
+
+	m := createTestMetric(map[string]string{"instance": "1234_DefaultAppPool", "objectname": "W3SVC_W3WP", "normalize_instances": "true"})
+	result := n.Apply(m)
+	assert.Equal(t, map[string]string{"instance": "DefaultAppPool", "objectname": "W3SVC_W3WP"}, result[0].Tags())
+
+	// the default patterns still apply to the Process object
+	m = createTestMetric(map[string]string{"instance": "svchost#3", "objectname": "Process", "normalize_instances": "true"})
+	result = n.Apply(m)
+	assert.Equal(t, map[string]string{"instance": "svchost", "objectname": "Process"}, result[0].Tags())
+}
+
+func TestCustomPatterns(t *testing.T) {
+	n := &InstanceNormalizer{Patterns: []string{`\s\(\d+\)$`}}
+	assert.NoError(t, n.Init())
+
+	m := createTestMetric(map[string]string{"instance": "chrome (12)", "normalize_instances": "true"})
+	result := n.Apply(m)
+	assert.Equal(t, map[string]string{"instance": "chrome"}, result[0].Tags())
+
+	m = createTestMetric(map[string]string{"instance": "w3wp_1234", "normalize_instances": "true"})
+	result = n.Apply(m)
+	assert.Equal(t, map[string]string{"instance": "w3wp_1234"}, result[0].Tags())
+}
+
+func TestInvalidPattern(t *testing.T) {
+	n := &InstanceNormalizer{Patterns: []string{`(`}}
+	assert.Error(t, n.Init())
+	n = &InstanceNormalizer{ObjectPatterns: map[string][]string{"Process": {`(`}}}
+	assert.Error(t, n.Init())
+}
+
+func TestNoNormalizeTag(t *testing.T) {
+	n := &InstanceNormalizer{}
+	assert.NoError(t, n.Init())
+	m := createTestMetric(map[string]string{"instance": "svchost#3"})
+
+	result := n.Apply(m)
+
+	assert.Equal(t, map[string]string{"instance": "svchost#3"}, result[0].Tags())
+}
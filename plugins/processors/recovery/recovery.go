@@ -0,0 +1,258 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package recovery
+
+import (
+	"context"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/amazon-cloudwatch-agent/internal"
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/plugins/processors"
+)
+
+const (
+	// The transient tags added by the translator to the procstat inputs monitored for recovery.
+	RestartServiceTagKey = "recovery_restart_service"
+	CommandTagKey        = "recovery_command"
+	MinIntervalTagKey    = "recovery_min_interval"
+	MaxAttemptsTagKey    = "recovery_max_attempts"
+	DropPidCountTagKey   = "recovery_drop_pid_count"
+
+	recoveryTagPrefix     = "recovery_"
+	procstatLookupMetric  = "procstat_lookup"
+	pidCountField         = "pid_count"
+	defaultMinInterval    = 5 * time.Minute
+	defaultMaxAttempts    = 3
+	defaultCommandTimeout = time.Minute
+)
+
+var sampleConfig = `
+  ## Maximum time the recovery command or the service restart is allowed to run.
+  # timeout = "60s"
+`
+
+// target tracks the recovery attempts of one monitored procstat target.
+type target struct {
+	attempts    int
+	lastAttempt time.Time
+	running     bool
+	exhausted   bool
+}
+
+type Recovery struct {
+	Timeout internal.Duration `toml:"timeout"`
+	Log     telegraf.Logger   `toml:"-"`
+
+	mu      sync.Mutex
+	targets map[string]*target
+
+	now            func() time.Time
+	restartService func(ctx context.Context, name string) error
+	runCommand     func(ctx context.Context, command string) ([]byte, error)
+}
+
+func (r *Recovery) SampleConfig() string {
+	return sampleConfig
+}
+
+func (r *Recovery) Description() string {
+	return "Run the configured recovery action when a monitored procstat target is detected down."
+}
+
+func (r *Recovery) Init() error {
+	if r.Timeout.Duration <= 0 {
+		r.Timeout.Duration = defaultCommandTimeout
+	}
+	r.targets = make(map[string]*target)
+	if r.now == nil {
+		r.now = time.Now
+	}
+	if r.restartService == nil {
+		r.restartService = restartService
+	}
+	if r.runCommand == nil {
+		r.runCommand = runCommand
+	}
+	return nil
+}
+
+// Apply checks the procstat_lookup metrics of the inputs that carry the recovery tags, and starts the recovery action
+// when no process matches the target. The recovery tags are removed from all the metrics.
+func (r *Recovery) Apply(in ...telegraf.Metric) []telegraf.Metric {
+	out := in[:0]
+	for _, metric := range in {
+		tags := recoveryTags(metric)
+		if len(tags) == 0 {
+			out = append(out, metric)
+			continue
+		}
+		//remove the transient tags
+		for key := range tags {
+			metric.RemoveTag(key)
+		}
+
+		if metric.Name() == procstatLookupMetric {
+			if pidCount, ok := toInt64(metric.Fields()[pidCountField]); ok {
+				r.check(metric.Tags(), tags, pidCount)
+			}
+			if tags[DropPidCountTagKey] == "true" {
+				metric.RemoveField(pidCountField)
+				if len(metric.FieldList()) == 0 {
+					metric.Drop()
+					continue
+				}
+			}
+		}
+		out = append(out, metric)
+	}
+	return out
+}
+
+func (r *Recovery) check(metricTags map[string]string, tags map[string]string, pidCount int64) {
+	key := targetKey(metricTags, tags)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	t, ok := r.targets[key]
+	if !ok {
+		t = &target{}
+		r.targets[key] = t
+	}
+	if pidCount > 0 {
+		if t.attempts > 0 {
+			r.Log.Infof("Target %v is up again after %d recovery attempt(s)", key, t.attempts)
+		}
+		t.attempts = 0
+		t.exhausted = false
+		return
+	}
+	if t.running {
+		return
+	}
+
+	maxAttempts := defaultMaxAttempts
+	if val, err := strconv.Atoi(tags[MaxAttemptsTagKey]); err == nil && val > 0 {
+		maxAttempts = val
+	}
+	minInterval := defaultMinInterval
+	if val, err := time.ParseDuration(tags[MinIntervalTagKey]); err == nil && val > 0 {
+		minInterval = val
+	}
+	now := r.now()
+	if t.attempts >= maxAttempts {
+		if !t.exhausted {
+			r.Log.Warnf("Target %v is still down after %d recovery attempt(s), no more attempts until it is up again", key, t.attempts)
+			t.exhausted = true
+		}
+		return
+	}
+	if !t.lastAttempt.IsZero() && now.Sub(t.lastAttempt) < minInterval {
+		return
+	}
+
+	t.attempts++
+	t.lastAttempt = now
+	t.running = true
+	attempt := t.attempts
+	// the action runs in the background so the metrics are not delayed by a slow restart
+	go func() {
+		r.recover(key, attempt, maxAttempts, tags)
+		r.mu.Lock()
+		t.running = false
+		r.mu.Unlock()
+	}()
+}
+
+func (r *Recovery) recover(key string, attempt, maxAttempts int, tags map[string]string) {
+	if name := tags[RestartServiceTagKey]; name != "" {
+		r.Log.Infof("Target %v is down, restarting service %v (attempt %d/%d)", key, name, attempt, maxAttempts)
+		ctx, cancel := context.WithTimeout(context.Background(), r.Timeout.Duration)
+		err := r.restartService(ctx, name)
+		cancel()
+		if err != nil {
+			r.Log.Errorf("Failed to restart service %v for target %v: %v", name, key, err)
+		} else {
+			r.Log.Infof("Restarted service %v for target %v", name, key)
+		}
+	}
+	if command := tags[CommandTagKey]; command != "" {
+		r.Log.Infof("Target %v is down, running recovery command %q (attempt %d/%d)", key, command, attempt, maxAttempts)
+		ctx, cancel := context.WithTimeout(context.Background(), r.Timeout.Duration)
+		output, err := r.runCommand(ctx, command)
+		cancel()
+		if err != nil {
+			r.Log.Errorf("Recovery command %q for target %v failed: %v, output: %s", command, key, err, strings.TrimSpace(string(output)))
+		} else {
+			r.Log.Infof("Recovery command %q for target %v succeeded, output: %s", command, key, strings.TrimSpace(string(output)))
+		}
+	}
+}
+
+func recoveryTags(metric telegraf.Metric) map[string]string {
+	var tags map[string]string
+	for _, tag := range metric.TagList() {
+		if strings.HasPrefix(tag.Key, recoveryTagPrefix) {
+			if tags == nil {
+				tags = make(map[string]string)
+			}
+			tags[tag.Key] = tag.Value
+		}
+	}
+	return tags
+}
+
+// targetKey identifies the target by the procstat selector tags and the recovery action.
+func targetKey(metricTags map[string]string, tags map[string]string) string {
+	var parts []string
+	for _, key := range []string{"exe", "pidfile", "pattern"} {
+		if val, ok := metricTags[key]; ok {
+			parts = append(parts, key+"="+val)
+		}
+	}
+	for _, key := range []string{RestartServiceTagKey, CommandTagKey} {
+		if val, ok := tags[key]; ok {
+			parts = append(parts, strings.TrimPrefix(key, recoveryTagPrefix)+"="+val)
+		}
+	}
+	return strings.Join(parts, ",")
+}
+
+func runCommand(ctx context.Context, command string) ([]byte, error) {
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.CommandContext(ctx, "cmd", "/C", command)
+	} else {
+		cmd = exec.CommandContext(ctx, "/bin/sh", "-c", command)
+	}
+	return cmd.CombinedOutput()
+}
+
+func toInt64(val interface{}) (int64, bool) {
+	switch v := val.(type) {
+	case int:
+		return int64(v), true
+	case int32:
+		return int64(v), true
+	case int64:
+		return v, true
+	case uint64:
+		return int64(v), true
+	case float64:
+		return int64(v), true
+	default:
+		return 0, false
+	}
+}
+
+func init() {
+	processors.Add("recovery", func() telegraf.Processor {
+		return &Recovery{}
+	})
+}
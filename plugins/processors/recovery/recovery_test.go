@@ -0,0 +1,171 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package recovery
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/influxdata/telegraf"
+	"github.com/influxdata/telegraf/metric"
+	"github.com/influxdata/telegraf/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+type recorder struct {
+	sync.Mutex
+	services []string
+	commands []string
+}
+
+func newTestRecovery(t *testing.T, now *time.Time) (*Recovery, *recorder) {
+	rec := &recorder{}
+	r := &Recovery{
+		Log: testutil.Logger{},
+		now: func() time.Time { return *now },
+		restartService: func(ctx context.Context, name string) error {
+			rec.Lock()
+			rec.services = append(rec.services, name)
+			rec.Unlock()
+			return nil
+		},
+		runCommand: func(ctx context.Context, command string) ([]byte, error) {
+			rec.Lock()
+			rec.commands = append(rec.commands, command)
+			rec.Unlock()
+			return []byte("failed"), errors.New("exit status 1")
+		},
+	}
+	assert.NoError(t, r.Init())
+	return r, rec
+}
+
+// apply runs the processor and waits for the recovery action it started, if any.
+func apply(r *Recovery, m telegraf.Metric) []telegraf.Metric {
+	result := r.Apply(m)
+	for {
+		r.mu.Lock()
+		running := false
+		for _, t := range r.targets {
+			running = running || t.running
+		}
+		r.mu.Unlock()
+		if !running {
+			return result
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func createLookupMetric(pidCount int64, tags map[string]string) telegraf.Metric {
+	allTags := map[string]string{"exe": "nginx", "pid_finder": "native"}
+	for k, v := range tags {
+		allTags[k] = v
+	}
+	m, _ := metric.New("procstat_lookup",
+		allTags,
+		map[string]interface{}{
+			"pid_count":   pidCount,
+			"running":     pidCount,
+			"result_code": int64(0),
+		},
+		time.Now(),
+	)
+	return m
+}
+
+func TestRecoveryRestartsServiceWhenDown(t *testing.T) {
+	now := time.Unix(1600000000, 0)
+	r, rec := newTestRecovery(t, &now)
+	tags := map[string]string{RestartServiceTagKey: "nginx", MinIntervalTagKey: "60s", MaxAttemptsTagKey: "2"}
+
+	result := apply(r, createLookupMetric(1, tags))
+	assert.Equal(t, 1, len(result))
+	assert.Equal(t, map[string]string{"exe": "nginx", "pid_finder": "native"}, result[0].Tags())
+	assert.Empty(t, rec.services)
+
+	apply(r, createLookupMetric(0, tags))
+	assert.Equal(t, []string{"nginx"}, rec.services)
+
+	// rate limited by the min interval
+	now = now.Add(30 * time.Second)
+	apply(r, createLookupMetric(0, tags))
+	assert.Equal(t, []string{"nginx"}, rec.services)
+
+	now = now.Add(30 * time.Second)
+	apply(r, createLookupMetric(0, tags))
+	assert.Equal(t, []string{"nginx", "nginx"}, rec.services)
+
+	// no more attempts beyond the max attempts
+	now = now.Add(time.Hour)
+	apply(r, createLookupMetric(0, tags))
+	assert.Equal(t, []string{"nginx", "nginx"}, rec.services)
+
+	// the attempts are reset once the target is up again
+	apply(r, createLookupMetric(1, tags))
+	now = now.Add(time.Hour)
+	apply(r, createLookupMetric(0, tags))
+	assert.Equal(t, []string{"nginx", "nginx", "nginx"}, rec.services)
+}
+
+func TestRecoveryRunsCommandWhenDown(t *testing.T) {
+	now := time.Unix(1600000000, 0)
+	r, rec := newTestRecovery(t, &now)
+	tags := map[string]string{CommandTagKey: "/opt/fix.sh --all"}
+
+	apply(r, createLookupMetric(0, tags))
+	assert.Equal(t, []string{"/opt/fix.sh --all"}, rec.commands)
+	assert.Empty(t, rec.services)
+
+	// the default min interval applies
+	now = now.Add(time.Minute)
+	apply(r, createLookupMetric(0, tags))
+	assert.Equal(t, 1, len(rec.commands))
+	now = now.Add(defaultMinInterval)
+	apply(r, createLookupMetric(0, tags))
+	assert.Equal(t, 2, len(rec.commands))
+}
+
+func TestRecoveryDropPidCount(t *testing.T) {
+	now := time.Unix(1600000000, 0)
+	r, rec := newTestRecovery(t, &now)
+
+	m := createLookupMetric(0, map[string]string{CommandTagKey: "fix", DropPidCountTagKey: "true"})
+	result := apply(r, m)
+	assert.Equal(t, 1, len(result))
+	_, ok := result[0].GetField("pid_count")
+	assert.False(t, ok)
+	assert.Equal(t, 1, len(rec.commands))
+
+	m, _ = metric.New("procstat_lookup",
+		map[string]string{"exe": "nginx", CommandTagKey: "fix", DropPidCountTagKey: "true"},
+		map[string]interface{}{"pid_count": int64(3)},
+		time.Now(),
+	)
+	assert.Empty(t, apply(r, m))
+}
+
+func TestRecoveryIgnoresOtherMetrics(t *testing.T) {
+	now := time.Unix(1600000000, 0)
+	r, rec := newTestRecovery(t, &now)
+
+	m, _ := metric.New("procstat",
+		map[string]string{"exe": "nginx", CommandTagKey: "fix"},
+		map[string]interface{}{"cpu_usage": float64(0)},
+		time.Now(),
+	)
+	other, _ := metric.New("cpu",
+		map[string]string{"cpu": "cpu-total"},
+		map[string]interface{}{"usage_idle": float64(90)},
+		time.Now(),
+	)
+	result := r.Apply(m, other)
+	assert.Equal(t, 2, len(result))
+	assert.Equal(t, map[string]string{"exe": "nginx"}, result[0].Tags())
+	assert.Equal(t, map[string]string{"cpu": "cpu-total"}, result[1].Tags())
+	assert.Empty(t, rec.commands)
+}
@@ -0,0 +1,23 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+//go:build !windows
+// +build !windows
+
+package recovery
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// restartService restarts the systemd unit.
+func restartService(ctx context.Context, name string) error {
+	output, err := exec.CommandContext(ctx, "systemctl", "restart", name).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("systemctl restart failed: %v, output: %s", err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+//go:build windows
+// +build windows
+
+package recovery
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"golang.org/x/sys/windows/svc"
+	"golang.org/x/sys/windows/svc/mgr"
+)
+
+// restartService stops the Windows service if it is not stopped yet, and starts it again.
+func restartService(ctx context.Context, name string) error {
+	m, err := mgr.Connect()
+	if err != nil {
+		return fmt.Errorf("failed to connect to the service control manager: %v", err)
+	}
+	defer m.Disconnect()
+
+	s, err := m.OpenService(name)
+	if err != nil {
+		return fmt.Errorf("failed to open the service: %v", err)
+	}
+	defer s.Close()
+
+	status, err := s.Query()
+	if err != nil {
+		return fmt.Errorf("failed to query the service: %v", err)
+	}
+	if status.State != svc.Stopped {
+		if status.State != svc.StopPending {
+			if _, err = s.Control(svc.Stop); err != nil {
+				return fmt.Errorf("failed to stop the service: %v", err)
+			}
+		}
+		for status.State != svc.Stopped {
+			select {
+			case <-ctx.Done():
+				return fmt.Errorf("timed out waiting for the service to stop")
+			case <-time.After(500 * time.Millisecond):
+			}
+			if status, err = s.Query(); err != nil {
+				return fmt.Errorf("failed to query the service: %v", err)
+			}
+		}
+	}
+	if err = s.Start(); err != nil {
+		return fmt.Errorf("failed to start the service: %v", err)
+	}
+	return nil
+}
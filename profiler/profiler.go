@@ -6,6 +6,7 @@ package profiler
 import (
 	"fmt"
 	"log"
+	"sort"
 	"strings"
 	"sync"
 )
@@ -48,6 +49,21 @@ func (p *profiler) ReportAndClear() {
 	log.Printf("D! Profiler dump:\n%s", strings.Join(output, "\n"))
 }
 
+// Snapshot returns the stats accumulated since the last report, sorted, without clearing them.
+func (p *profiler) Snapshot() []string {
+	p.Lock()
+	defer p.Unlock()
+	var output []string
+	for k, v := range p.stats {
+		output = append(output, fmt.Sprintf("[%s: %f]", k, v))
+	}
+	sort.Strings(output)
+	if len(output) == 0 {
+		output = append(output, noStatsInProfiler)
+	}
+	return output
+}
+
 func (p *profiler) reportAndClear() []string {
 	var output []string
 	for k, v := range p.stats {
@@ -54,3 +54,19 @@ func TestProfilerGetStats(t *testing.T) {
 	_, ok = stats[name]
 	assert.False(t, ok)
 }
+
+func TestProfilerSnapshot(t *testing.T) {
+	Profiler.ReportAndClear()
+	assert.Equal(t, []string{noStatsInProfiler}, Profiler.Snapshot())
+
+	Profiler.AddStats([]string{"pluginB", "StatsB"}, 0.1)
+	Profiler.AddStats([]string{"pluginA", "StatsA"}, 2)
+	stats := []string{
+		"[pluginA_StatsA: 2.000000]",
+		"[pluginB_StatsB: 0.100000]",
+	}
+	assert.Equal(t, stats, Profiler.Snapshot())
+	// the stats are kept for the next report
+	assert.Len(t, Profiler.GetStats(), 2)
+	Profiler.ReportAndClear()
+}
@@ -0,0 +1,721 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package catalog
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"github.com/aws/amazon-cloudwatch-agent/internal/metricscommon"
+	"github.com/aws/amazon-cloudwatch-agent/plugins/outputs/cloudwatch"
+	"github.com/aws/amazon-cloudwatch-agent/plugins/processors/cpuaggregator"
+	"github.com/aws/amazon-cloudwatch-agent/plugins/processors/delta"
+	"github.com/aws/amazon-cloudwatch-agent/plugins/processors/dimensionfilter"
+	"github.com/aws/amazon-cloudwatch-agent/plugins/processors/ec2tagger"
+	"github.com/aws/amazon-cloudwatch-agent/plugins/processors/instancenormalizer"
+	"github.com/aws/amazon-cloudwatch-agent/plugins/processors/recovery"
+	"github.com/influxdata/telegraf/metric"
+	"github.com/influxdata/telegraf/models"
+)
+
+const (
+	defaultNamespace = "CWAgent"
+	metricsPath      = "metrics"
+	noInstance       = "------"
+	dropAllWildcard  = "*"
+	hostDimension    = "host"
+)
+
+// Catalog lists what the translated agent configuration publishes to CloudWatch, so dashboards and alarms can be
+// generated from the agent configuration.
+type Catalog struct {
+	Metrics []Metric `json:"metrics"`
+	// DynamicSources are the inputs whose metric names are only known at runtime, like statsd or collectd.
+	DynamicSources []DynamicSource `json:"dynamic_sources,omitempty"`
+}
+
+type Metric struct {
+	Namespace         string     `json:"namespace"`
+	MetricName        string     `json:"metric_name"`
+	Unit              string     `json:"unit,omitempty"`
+	Dimensions        [][]string `json:"dimensions"`
+	StorageResolution int        `json:"storage_resolution"`
+	Source            string     `json:"source"`
+	// LogGroupName is the log group of the embedded metric format documents CloudWatch extracts the metric from with
+	// the emf output mode, empty when the metric is published with PutMetricData.
+	LogGroupName string `json:"log_group_name,omitempty"`
+}
+
+type DynamicSource struct {
+	Namespace  string     `json:"namespace"`
+	Source     string     `json:"source"`
+	Dimensions [][]string `json:"dimensions"`
+	// LogGroupName is the log group of the embedded metric format documents, like the one of the metrics.
+	LogGroupName string `json:"log_group_name,omitempty"`
+}
+
+// pluginDimensions are the tags set by the input plugins, before the tagexclude of the input and the output.
+var pluginDimensions = map[string][]string{
+	"cgroup":         {"cgroup"},
+	"containerd":     {"container_id", "container_image", "container_name"},
+	"cpu":            {"cpu"},
+	"cpu_cluster":    {"cluster"},
+	"disk":           {"device", "fstype", "mode", "path"},
+	"diskio":         {"name"},
+	"diskio_latency": {"name"},
+	"docker":         {"container_id", "container_image", "container_name"},
+	"ethtool":        {"driver", "interface"},
+	"haproxy":        {"proxy", "server", "type"},
+	"kafka":          {"group", "partition", "topic"},
+	"kernel":         {},
+	"mem":            {},
+	"net":            {"interface"},
+	"netstat":        {},
+	"nvidia_smi":     {"compute_mode", "index", "name", "pstate", "uuid"},
+	"processes":      {},
+	"rabbitmq":       {"node", "queue", "vhost"},
+	"sensors":        {"chip", "label"},
+	"swap":           {},
+	"systemd":        {"unit"},
+	"timesync":       {"reference_id", "source"},
+	"top_processes":  {"process_name"},
+	"windows_gpu":    {"adapter", "engine_type"},
+}
+
+// fieldDimensions are the tags of the fields of the inputs reporting several groups of fields with their own tags, by
+// field prefix, the longer prefixes first. The other inputs tag all of their fields with their pluginDimensions.
+var fieldDimensions = map[string][]struct {
+	prefix string
+	tags   []string
+}{
+	"kafka": {
+		{"consumer_partition_lag", []string{"group", "partition", "topic"}},
+		{"consumer_", []string{"group", "topic"}},
+		{"topic_", []string{"topic"}},
+		{"cluster_", []string{}},
+	},
+	"rabbitmq": {
+		{"overview_", []string{}},
+		{"node_", []string{"node"}},
+		{"queue_", []string{"queue", "vhost"}},
+	},
+	"windows_gpu": {
+		{"engine_utilization", []string{"adapter", "engine_type"}},
+		{"", []string{"adapter"}},
+	},
+}
+
+// fieldTags returns the tags the input sets on the field.
+func fieldTags(pluginName, field string) []string {
+	for _, d := range fieldDimensions[pluginName] {
+		if strings.HasPrefix(field, d.prefix) {
+			return d.tags
+		}
+	}
+	return pluginDimensions[pluginName]
+}
+
+// pluginMeasurements are the measurements of the inputs extending the measurement of another input.
+var pluginMeasurements = map[string]string{
+	"diskio_latency": "diskio",
+}
+
+// nodeExporterMeasurements are the measurements the node_exporter metrics are mapped to and their tags, the longer
+// prefixes first.
+var nodeExporterMeasurements = []struct {
+	measurement string
+	tags        []string
+}{
+	{"diskio", []string{"name"}},
+	{"disk", []string{"device", "fstype", "path"}},
+	{"cpu", []string{"cpu"}},
+	{"mem", []string{}},
+	{"swap", []string{}},
+	{"netstat", []string{}},
+	{"net", []string{"interface"}},
+	{"processes", []string{}},
+}
+
+// procstatSelectors are the procstat options, each one tags the metrics with its own key.
+var procstatSelectors = []struct{ option, tag string }{
+	{"pid_file", "pidfile"},
+	{"exe", "exe"},
+	{"pattern", "pattern"},
+	{"user", "user"},
+	{"systemd_unit", "systemd_unit"},
+	{"cgroup", "cgroup"},
+	{"win_service", "win_service"},
+}
+
+// procstatLookupFields are the procstat fields of the procstat_lookup measurement, by selector instead of process.
+var procstatLookupFields = map[string]bool{
+	"alive":         true,
+	"pid_count":     true,
+	"restart_count": true,
+}
+
+// procstatTagOptions are the procstat options tagging the metrics with another key than the selector one.
+var procstatTagOptions = []struct{ option, tag string }{
+	{"pid_tag", "pid"},
+	{"exe_path_tag", "exe_path"},
+	{"cmdline_hash_tag", "cmdline_hash"},
+	{"pattern_hash_tag", "pattern_hash"},
+	{"container_id_tag", "container_id"},
+}
+
+// procstatTags returns the tags of the procstat options set in the input.
+func procstatTags(input map[string]interface{}) []string {
+	var tags []string
+	for _, o := range procstatTagOptions {
+		if boolValue(input, o.option) {
+			tags = append(tags, o.tag)
+		}
+	}
+	return tags
+}
+
+// transientTags are the tags the translator adds for the processors or the output, they never become dimensions. The
+// processors remove them, so their keys are the ones of the processors. TestFixtureTags checks every tag of the
+// translated sample configs is either known here or set by the user.
+var transientTags = map[string]bool{
+	"metricPath":                          true,
+	delta.ReportDelta:                     true,
+	delta.ReportRate:                      true,
+	delta.IgnoredFieldsForDelta:           true,
+	cpuaggregator.AggregatePerCpu:         true,
+	instancenormalizer.NormalizeInstances: true,
+}
+
+// typedConfig are the options of the toml configuration the catalog decodes with the types of their plugins.
+type typedConfig struct {
+	Outputs struct {
+		Cloudwatch []struct {
+			MetricRenameConfigs []cloudwatch.MetricRenameConfig     `toml:"metric_rename"`
+			NamespaceRouting    []cloudwatch.NamespaceRoutingConfig `toml:"namespace_routing"`
+		} `toml:"cloudwatch"`
+	} `toml:"outputs"`
+	Processors struct {
+		DimensionFilter []*dimensionfilter.DimensionFilter `toml:"dimensionfilter"`
+	} `toml:"processors"`
+}
+
+// FromToml builds the catalog of the translated toml configuration, targetOs is used for the metric names as the
+// cloudwatch output joins the measurement and field names with a space on windows.
+func FromToml(tomlConfig string, targetOs string) (*Catalog, error) {
+	var conf map[string]interface{}
+	if _, err := toml.Decode(tomlConfig, &conf); err != nil {
+		return nil, fmt.Errorf("failed to decode the toml config: %v", err)
+	}
+	var typed typedConfig
+	if _, err := toml.Decode(tomlConfig, &typed); err != nil {
+		return nil, fmt.Errorf("failed to decode the toml config: %v", err)
+	}
+	c := &Catalog{Metrics: []Metric{}}
+
+	outputs := tables(mapValue(conf, "outputs")["cloudwatch"])
+	if len(outputs) == 0 {
+		return c, nil
+	}
+	output := outputs[0]
+	namespace := stringValue(output, "namespace")
+	if namespace == "" {
+		namespace = defaultNamespace
+	}
+	b := &builder{
+		namespace:   namespace,
+		targetOs:    targetOs,
+		excluded:    toSet(stringSlice(output["tagexclude"])),
+		rollups:     rollupDimensions(output["rollup_dimensions"]),
+		drops:       map[string]map[string]bool{},
+		renames:     map[string]string{},
+		units:       map[string]string{},
+		omitHost:    boolValue(mapValue(conf, "agent"), "omit_hostname"),
+		decorations: map[string]bool{},
+	}
+	var err error
+	typedOutput := typed.Outputs.Cloudwatch[0]
+	if b.metricRenames, err = cloudwatch.NewMetricRenames(typedOutput.MetricRenameConfigs); err != nil {
+		return nil, err
+	}
+	if b.namespaceRouting, err = cloudwatch.NewNamespaceRouting(typedOutput.NamespaceRouting, models.NewLogger("outputs", "cloudwatch", "")); err != nil {
+		return nil, err
+	}
+	if stringValue(output, "output_mode") == cloudwatch.OutputModeEMF {
+		b.logGroupName = stringValue(output, "log_group_name")
+		if b.logGroupName == "" {
+			b.logGroupName = cloudwatch.DefaultEMFLogGroupName
+		}
+	}
+	for _, filter := range typed.Processors.DimensionFilter {
+		if err := filter.Init(); err != nil {
+			return nil, err
+		}
+		b.dimensionFilters = append(b.dimensionFilters, filter)
+	}
+	for category, fields := range mapValue(output, "drop_original_metrics") {
+		b.drops[category] = toSet(stringSlice(fields))
+	}
+	for _, decoration := range tables(output["metric_decoration"]) {
+		key := decorationKey(stringValue(decoration, "category"), stringValue(decoration, "name"))
+		if rename := stringValue(decoration, "rename"); rename != "" {
+			b.renames[key] = rename
+		}
+		if unit := stringValue(decoration, "unit"); unit != "" {
+			b.units[key] = unit
+		}
+	}
+	for _, tagger := range tables(mapValue(conf, "processors")["ec2tagger"]) {
+		b.decorations = toSet(stringSlice(tagger["ec2_metadata_tags"]))
+		for _, key := range stringSlice(tagger["ec2_instance_tag_keys"]) {
+			// the ec2tagger names the dimension of the auto scaling group tag as the EC2 metrics do
+			if key == ec2tagger.EC2InstanceTagKeyASG {
+				key = ec2tagger.CWDimensionASG
+			}
+			b.decorations[key] = true
+		}
+	}
+
+	inputs := mapValue(conf, "inputs")
+	pluginNames := make([]string, 0, len(inputs))
+	for name := range inputs {
+		pluginNames = append(pluginNames, name)
+	}
+	sort.Strings(pluginNames)
+	for _, pluginName := range pluginNames {
+		for _, input := range tables(inputs[pluginName]) {
+			if stringValue(mapValue(input, "tags"), "metricPath") != metricsPath {
+				continue
+			}
+			b.routed(input).addInput(c, pluginName, input)
+		}
+	}
+
+	sort.SliceStable(c.Metrics, func(i, j int) bool {
+		if c.Metrics[i].Namespace != c.Metrics[j].Namespace {
+			return c.Metrics[i].Namespace < c.Metrics[j].Namespace
+		}
+		return c.Metrics[i].MetricName < c.Metrics[j].MetricName
+	})
+	return c, nil
+}
+
+type builder struct {
+	namespace   string
+	targetOs    string
+	excluded    map[string]bool
+	rollups     [][]string
+	drops       map[string]map[string]bool
+	renames     map[string]string
+	units       map[string]string
+	omitHost    bool
+	decorations map[string]bool
+	// the metric_rename rules, applied after the renames of the metric decorations
+	metricRenames *cloudwatch.MetricRenames
+	// the namespace_routing rules, applied to the tags of the inputs
+	namespaceRouting *cloudwatch.NamespaceRouting
+	// the dimensionfilter processors, their rules match the names before the renames
+	dimensionFilters []*dimensionfilter.DimensionFilter
+	// the log group of the emf output mode
+	logGroupName string
+}
+
+// routed returns the builder of the metrics of the input, with the namespace of the namespace_routing rule matching
+// its tags and without the dimension the rule drops. Only the tags set in the configuration, like the
+// append_dimensions, are matched, the values of the other dimensions are only known at runtime.
+func (b *builder) routed(input map[string]interface{}) *builder {
+	tags := map[string]string{}
+	for key, value := range mapValue(input, "tags") {
+		if s, ok := value.(string); ok {
+			tags[key] = s
+		}
+	}
+	m, err := metric.New(metricsPath, tags, map[string]interface{}{"value": 0}, time.Now())
+	if err != nil {
+		return b
+	}
+	namespace := b.namespaceRouting.Route(m)
+	if namespace == "" {
+		return b
+	}
+	routed := *b
+	routed.namespace = namespace
+	routed.excluded = map[string]bool{}
+	for key := range b.excluded {
+		routed.excluded[key] = true
+	}
+	for key := range tags {
+		if !m.HasTag(key) {
+			routed.excluded[key] = true
+		}
+	}
+	return &routed
+}
+
+func (b *builder) addInput(c *Catalog, pluginName string, input map[string]interface{}) {
+	tags := mapValue(input, "tags")
+	resolution := 60
+	if stringValue(tags, "aws:StorageResolution") == "true" {
+		resolution = 1
+	}
+	extra := []string{}
+	for key := range tags {
+		if !transientTags[key] && !strings.HasPrefix(key, "aws:") && !strings.HasPrefix(key, "recovery_") {
+			extra = append(extra, key)
+		}
+	}
+	excluded := toSet(stringSlice(input["tagexclude"]))
+
+	switch pluginName {
+	case "win_perf_counters":
+		for _, object := range tables(input["object"]) {
+			dimensions := []string{"objectname"}
+			if instances := stringSlice(object["Instances"]); len(instances) != 0 && instances[0] != noInstance {
+				dimensions = append(dimensions, "instance")
+			}
+			measurement := stringValue(object, "Measurement")
+			if measurement == "" {
+				measurement = stringValue(object, "ObjectName")
+			}
+			base := b.dimensions(append(dimensions, extra...), excluded)
+			for _, counter := range stringSlice(object["Counters"]) {
+				b.addMetric(c, pluginName, measurement, counter, base, resolution)
+			}
+		}
+	case "procstat":
+		dimensions := append([]string{}, extra...)
+		for _, selector := range procstatSelectors {
+			if stringValue(input, selector.option) != "" {
+				dimensions = append(dimensions, selector.tag)
+				break
+			}
+		}
+		// environment_pattern narrows the processes of the selector, it tags the metrics too
+		if stringValue(input, "environment_pattern") != "" {
+			dimensions = append(dimensions, "environment_pattern")
+		}
+		// so do the container options, the processes are tagged with their container
+		var containerTags []string
+		if stringValue(input, "container_name_pattern") != "" {
+			dimensions = append(dimensions, "container_name_pattern")
+		}
+		if stringValue(input, "container_name_pattern") != "" || input["container_labels"] != nil {
+			containerTags = append(containerTags, "container_name")
+		}
+		for _, field := range stringSlice(input["fieldpass"]) {
+			// the recovery processor only reads the pid_count of its targets dropping it
+			if field == "pid_count" && stringValue(tags, recovery.DropPidCountTagKey) == "true" {
+				continue
+			}
+			if procstatLookupFields[field] {
+				lookup := b.dimensions(append(dimensions, "pid_finder", "result"), excluded)
+				b.addMetric(c, pluginName, "procstat_lookup", field, lookup, resolution)
+				continue
+			}
+			base := b.dimensions(append(append(append(dimensions, "process_name", "user"), containerTags...), procstatTags(input)...), excluded)
+			b.addMetric(c, pluginName, pluginName, field, base, resolution)
+		}
+	case "node_exporter":
+		metrics := stringSlice(input["metrics"])
+		if len(metrics) == 0 || metrics[0] == dropAllWildcard {
+			c.DynamicSources = append(c.DynamicSources, DynamicSource{
+				Namespace:    b.namespace,
+				Source:       pluginName,
+				Dimensions:   b.dimensionSets(pluginName, "", b.dimensions(extra, excluded)),
+				LogGroupName: b.logGroupName,
+			})
+			return
+		}
+		for _, metric := range metrics {
+			for _, m := range nodeExporterMeasurements {
+				if field := strings.TrimPrefix(metric, m.measurement+"_"); field != metric {
+					base := b.dimensions(append(append([]string{}, m.tags...), extra...), excluded)
+					b.addMetric(c, pluginName, m.measurement, field, base, resolution)
+					break
+				}
+			}
+		}
+	case "cloudwatch_query":
+		// the results of the queries are named by the queries, their field is value
+		for _, query := range tables(input["query"]) {
+			if returnData, ok := query["return_data"].(bool); ok && !returnData {
+				continue
+			}
+			name := stringValue(query, "name")
+			if name == "" {
+				name = stringValue(query, "id")
+			}
+			var tags []string
+			if label := stringValue(query, "label_dimension"); label != "" {
+				tags = append(tags, label)
+			}
+			base := b.dimensions(append(tags, extra...), excluded)
+			b.addMetric(c, pluginName, name, "value", base, resolution)
+		}
+	case "fd":
+		// the fields of the processes are tagged with their name, the ones of the host aren't
+		for _, field := range stringSlice(input["fieldpass"]) {
+			var tags []string
+			if strings.HasPrefix(field, "process_") {
+				tags = []string{"process_name"}
+			}
+			base := b.dimensions(append(tags, extra...), excluded)
+			b.addMetric(c, pluginName, pluginName, field, base, resolution)
+		}
+	case "numa":
+		// the numa fields extend the mem measurement
+		fields := stringSlice(input["fieldpass"])
+		if len(fields) == 0 || fields[0] == dropAllWildcard {
+			c.DynamicSources = append(c.DynamicSources, DynamicSource{
+				Namespace:    b.namespace,
+				Source:       pluginName,
+				Dimensions:   b.dimensionSets("mem", "", b.dimensions(extra, excluded)),
+				LogGroupName: b.logGroupName,
+			})
+			return
+		}
+		base := b.dimensions(append([]string{"numa_node"}, extra...), excluded)
+		for _, field := range fields {
+			b.addMetric(c, pluginName, "mem", field, base, resolution)
+		}
+	case "netstat_ports":
+		// the breakdown of the connection states has the metric names of the netstat input
+		fields := stringSlice(input["fieldpass"])
+		var tags []string
+		if ports, _ := input["ports"].([]interface{}); len(ports) > 0 {
+			tags = append(tags, "port")
+		}
+		if by, _ := input["by_process"].(bool); by {
+			tags = append(tags, "process_name")
+		}
+		if len(fields) == 0 || fields[0] == dropAllWildcard {
+			c.DynamicSources = append(c.DynamicSources, DynamicSource{
+				Namespace:    b.namespace,
+				Source:       pluginName,
+				Dimensions:   b.dimensionSets("netstat", "", b.dimensions(append(tags, extra...), excluded)),
+				LogGroupName: b.logGroupName,
+			})
+			return
+		}
+		base := b.dimensions(append(tags, extra...), excluded)
+		for _, field := range fields {
+			b.addMetric(c, pluginName, "netstat", field, base, resolution)
+		}
+	default:
+		pluginTags, known := pluginDimensions[pluginName]
+		fields := stringSlice(input["fieldpass"])
+		if !known || len(fields) == 0 || hasPattern(fields) {
+			c.DynamicSources = append(c.DynamicSources, DynamicSource{
+				Namespace:    b.namespace,
+				Source:       pluginName,
+				Dimensions:   b.dimensionSets(pluginName, "", b.dimensions(append(append([]string{}, pluginTags...), extra...), excluded)),
+				LogGroupName: b.logGroupName,
+			})
+			return
+		}
+		// the delta processor reports the rates of the counters with the _per_sec suffix, not of the ignored fields
+		suffix := ""
+		if stringValue(tags, delta.ReportRate) == delta.TrueValue {
+			suffix = delta.RateSuffix
+		}
+		ignored := toSet(strings.Split(stringValue(tags, delta.IgnoredFieldsForDelta), delta.FieldSeparator))
+		measurement := pluginName
+		if m, ok := pluginMeasurements[pluginName]; ok {
+			measurement = m
+		}
+		for _, field := range fields {
+			name := field
+			if !ignored[field] {
+				name += suffix
+			}
+			base := b.dimensions(append(append([]string{}, fieldTags(pluginName, field)...), extra...), excluded)
+			b.addMetric(c, pluginName, measurement, name, base, resolution)
+			// the interrupts of each IRQ are reported next to the total
+			if perIRQ, _ := input["per_irq"].(bool); perIRQ && pluginName == "kernel" && field == "interrupts_per_sec" {
+				b.addMetric(c, pluginName, measurement, field, b.dimensions(append([]string{"device", "irq"}, extra...), excluded), resolution)
+			}
+		}
+	}
+}
+
+func (b *builder) addMetric(c *Catalog, source, measurement, field string, dimensions []string, resolution int) {
+	key := decorationKey(measurement, field)
+	original := metricscommon.MetricNameForOS(measurement, field, b.targetOs)
+	name := b.renames[key]
+	if name == "" {
+		name = original
+	}
+	sets := b.dimensionSets(measurement, field, b.filterDimensions(original, dimensions))
+	if len(sets) == 0 {
+		// the original metric is dropped and none of the rollups applies, nothing is published
+		return
+	}
+	name, namespace := b.metricRenames.Rename(name)
+	if namespace == "" {
+		namespace = b.namespace
+	}
+	c.Metrics = append(c.Metrics, Metric{
+		Namespace:         namespace,
+		MetricName:        name,
+		Unit:              b.units[key],
+		Dimensions:        sets,
+		StorageResolution: resolution,
+		Source:            source,
+		LogGroupName:      b.logGroupName,
+	})
+}
+
+// filterDimensions returns the dimensions the dimensionfilter processors keep on the metric, matched by the name it
+// has before the renames.
+func (b *builder) filterDimensions(name string, dimensions []string) []string {
+	for _, filter := range b.dimensionFilters {
+		dropped := toSet(filter.DroppedDimensions(name, dimensions))
+		kept := []string{}
+		for _, key := range dimensions {
+			if !dropped[key] {
+				kept = append(kept, key)
+			}
+		}
+		dimensions = kept
+	}
+	return dimensions
+}
+
+// dimensions returns the sorted dimension names of the metrics, with host first as the cloudwatch output does.
+func (b *builder) dimensions(tags []string, excluded map[string]bool) []string {
+	set := toSet(tags)
+	for key := range b.decorations {
+		set[key] = true
+	}
+	names := []string{}
+	for key := range set {
+		if key != hostDimension && !excluded[key] && !b.excluded[key] {
+			names = append(names, key)
+		}
+	}
+	sort.Strings(names)
+	if !b.omitHost && !excluded[hostDimension] && !b.excluded[hostDimension] {
+		names = append([]string{hostDimension}, names...)
+	}
+	return names
+}
+
+// dimensionSets returns the full dimension set, unless the metric is listed in drop_original_metrics, followed by
+// every rollup whose dimensions are all present on the metric.
+func (b *builder) dimensionSets(measurement, field string, dimensions []string) [][]string {
+	sets := [][]string{}
+	if drops := b.drops[measurement]; !drops[dropAllWildcard] && !drops[field] {
+		sets = append(sets, dimensions)
+	}
+	present := toSet(dimensions)
+	for _, rollup := range b.rollups {
+		complete := true
+		for _, key := range rollup {
+			if !present[key] {
+				complete = false
+				break
+			}
+		}
+		if complete && !equal(rollup, dimensions) && !contains(sets, rollup) {
+			sets = append(sets, rollup)
+		}
+	}
+	return sets
+}
+
+// hasPattern checks if the fieldpass has a glob pattern, the names of the fields it passes are only known at runtime.
+func hasPattern(fields []string) bool {
+	for _, field := range fields {
+		if strings.ContainsAny(field, "*?[") {
+			return true
+		}
+	}
+	return false
+}
+
+func decorationKey(category, name string) string {
+	return category + "/" + name
+}
+
+func rollupDimensions(value interface{}) [][]string {
+	rollups := [][]string{}
+	if list, ok := value.([]interface{}); ok {
+		for _, rollup := range list {
+			rollups = append(rollups, stringSlice(rollup))
+		}
+	}
+	return rollups
+}
+
+func mapValue(m map[string]interface{}, key string) map[string]interface{} {
+	if v, ok := m[key].(map[string]interface{}); ok {
+		return v
+	}
+	return map[string]interface{}{}
+}
+
+func tables(value interface{}) []map[string]interface{} {
+	switch v := value.(type) {
+	case []map[string]interface{}:
+		return v
+	case map[string]interface{}:
+		return []map[string]interface{}{v}
+	}
+	return nil
+}
+
+func stringValue(m map[string]interface{}, key string) string {
+	if v, ok := m[key].(string); ok {
+		return v
+	}
+	return ""
+}
+
+func boolValue(m map[string]interface{}, key string) bool {
+	v, _ := m[key].(bool)
+	return v
+}
+
+func stringSlice(value interface{}) []string {
+	result := []string{}
+	if list, ok := value.([]interface{}); ok {
+		for _, item := range list {
+			if s, ok := item.(string); ok {
+				result = append(result, s)
+			}
+		}
+	}
+	return result
+}
+
+func toSet(items []string) map[string]bool {
+	set := make(map[string]bool, len(items))
+	for _, item := range items {
+		set[item] = true
+	}
+	return set
+}
+
+func equal(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func contains(sets [][]string, set []string) bool {
+	for _, s := range sets {
+		if equal(s, set) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,751 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package catalog
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const linuxToml = `
+[agent]
+  omit_hostname = false
+
+[inputs]
+
+  [[inputs.cpu]]
+    fieldpass = ["usage_idle", "time_active"]
+    percpu = true
+    [inputs.cpu.tags]
+      "aws:StorageResolution" = "true"
+      metricPath = "metrics"
+
+  [[inputs.disk]]
+    fieldpass = ["used_percent"]
+    tagexclude = ["mode"]
+    [inputs.disk.tags]
+      d1 = "foo"
+      metricPath = "metrics"
+
+  [[inputs.procstat]]
+    exe = "nginx"
+    fieldpass = ["cpu_usage", "pid_count"]
+    pid_finder = "native"
+    tagexclude = ["user", "result"]
+    [inputs.procstat.tags]
+      metricPath = "metrics"
+      recovery_restart_service = "nginx"
+
+  [[inputs.statsd]]
+    service_address = ":8125"
+    [inputs.statsd.tags]
+      metricPath = "metrics"
+
+  [[inputs.logfile]]
+    [inputs.logfile.tags]
+      metricPath = "logs"
+
+[outputs]
+
+  [[outputs.cloudwatch]]
+    namespace = "MyApp"
+    rollup_dimensions = [["InstanceId"], []]
+    tagexclude = ["host", "metricPath"]
+    [outputs.cloudwatch.drop_original_metrics]
+      cpu = ["time_active"]
+
+    [[outputs.cloudwatch.metric_decoration]]
+      category = "disk"
+      name = "used_percent"
+      rename = "DiskUsed"
+      unit = "Percent"
+
+[processors]
+
+  [[processors.ec2tagger]]
+    ec2_metadata_tags = ["InstanceId"]
+`
+
+const windowsToml = `
+[inputs]
+
+  [[inputs.win_perf_counters]]
+
+    [[inputs.win_perf_counters.object]]
+      Counters = ["% Free Space"]
+      Instances = ["*"]
+      Measurement = "LogicalDisk"
+      ObjectName = "LogicalDisk"
+
+    [[inputs.win_perf_counters.object]]
+      Counters = ["% Committed Bytes In Use"]
+      Instances = ["------"]
+      Measurement = "Memory"
+      ObjectName = "Memory"
+    [inputs.win_perf_counters.tags]
+      metricPath = "metrics"
+
+[outputs]
+
+  [[outputs.cloudwatch]]
+    tagexclude = ["metricPath"]
+`
+
+func TestFromTomlLinux(t *testing.T) {
+	c, err := FromToml(linuxToml, "linux")
+	assert.NoError(t, err)
+	assert.Equal(t, []Metric{
+		{
+			Namespace:         "MyApp",
+			MetricName:        "DiskUsed",
+			Unit:              "Percent",
+			Dimensions:        [][]string{{"InstanceId", "d1", "device", "fstype", "path"}, {"InstanceId"}, {}},
+			StorageResolution: 60,
+			Source:            "disk",
+		},
+		{
+			Namespace:         "MyApp",
+			MetricName:        "cpu_time_active",
+			Dimensions:        [][]string{{"InstanceId"}, {}},
+			StorageResolution: 1,
+			Source:            "cpu",
+		},
+		{
+			Namespace:         "MyApp",
+			MetricName:        "cpu_usage_idle",
+			Dimensions:        [][]string{{"InstanceId", "cpu"}, {"InstanceId"}, {}},
+			StorageResolution: 1,
+			Source:            "cpu",
+		},
+		{
+			Namespace:         "MyApp",
+			MetricName:        "procstat_cpu_usage",
+			Dimensions:        [][]string{{"InstanceId", "exe", "process_name"}, {"InstanceId"}, {}},
+			StorageResolution: 60,
+			Source:            "procstat",
+		},
+		{
+			Namespace:         "MyApp",
+			MetricName:        "procstat_lookup_pid_count",
+			Dimensions:        [][]string{{"InstanceId", "exe", "pid_finder"}, {"InstanceId"}, {}},
+			StorageResolution: 60,
+			Source:            "procstat",
+		},
+	}, c.Metrics)
+	assert.Equal(t, []DynamicSource{
+		{Namespace: "MyApp", Source: "statsd", Dimensions: [][]string{{"InstanceId"}, {}}},
+	}, c.DynamicSources)
+}
+
+func TestFromTomlWindows(t *testing.T) {
+	c, err := FromToml(windowsToml, "windows")
+	assert.NoError(t, err)
+	assert.Equal(t, []Metric{
+		{
+			Namespace:         "CWAgent",
+			MetricName:        "LogicalDisk % Free Space",
+			Dimensions:        [][]string{{"host", "instance", "objectname"}},
+			StorageResolution: 60,
+			Source:            "win_perf_counters",
+		},
+		{
+			Namespace:         "CWAgent",
+			MetricName:        "Memory % Committed Bytes In Use",
+			Dimensions:        [][]string{{"host", "objectname"}},
+			StorageResolution: 60,
+			Source:            "win_perf_counters",
+		},
+	}, c.Metrics)
+	assert.Empty(t, c.DynamicSources)
+}
+
+func TestFromTomlNodeExporter(t *testing.T) {
+	toml := `
+[inputs]
+
+  [[inputs.node_exporter]]
+    metrics = ["mem_used_percent", "disk_used_percent", "diskio_reads"]
+    [inputs.node_exporter.tags]
+      metricPath = "metrics"
+
+  [[inputs.node_exporter]]
+    metrics = ["*"]
+    url = "http://localhost:9101/metrics"
+    [inputs.node_exporter.tags]
+      metricPath = "metrics"
+
+[outputs]
+
+  [[outputs.cloudwatch]]
+    tagexclude = ["metricPath"]
+`
+	c, err := FromToml(toml, "linux")
+	assert.NoError(t, err)
+	assert.Equal(t, []Metric{
+		{
+			Namespace:         "CWAgent",
+			MetricName:        "disk_used_percent",
+			Dimensions:        [][]string{{"host", "device", "fstype", "path"}},
+			StorageResolution: 60,
+			Source:            "node_exporter",
+		},
+		{
+			Namespace:         "CWAgent",
+			MetricName:        "diskio_reads",
+			Dimensions:        [][]string{{"host", "name"}},
+			StorageResolution: 60,
+			Source:            "node_exporter",
+		},
+		{
+			Namespace:         "CWAgent",
+			MetricName:        "mem_used_percent",
+			Dimensions:        [][]string{{"host"}},
+			StorageResolution: 60,
+			Source:            "node_exporter",
+		},
+	}, c.Metrics)
+	assert.Equal(t, []DynamicSource{
+		{Namespace: "CWAgent", Source: "node_exporter", Dimensions: [][]string{{"host"}}},
+	}, c.DynamicSources)
+}
+
+func TestFromTomlWithoutCloudWatchOutput(t *testing.T) {
+	c, err := FromToml("[inputs]\n\n  [[inputs.logfile]]\n", "linux")
+	assert.NoError(t, err)
+	assert.Empty(t, c.Metrics)
+
+	_, err = FromToml("[inputs", "linux")
+	assert.Error(t, err)
+}
+
+func TestFromTomlNuma(t *testing.T) {
+	toml := `
+[inputs]
+
+  [[inputs.mem]]
+    fieldpass = ["used_percent"]
+    [inputs.mem.tags]
+      metricPath = "metrics"
+
+  [[inputs.numa]]
+    fieldpass = ["numa_hit"]
+    [inputs.numa.tags]
+      ignored_fields_for_delta = "numa_free,numa_total,numa_used,numa_used_percent"
+      metricPath = "metrics"
+      report_deltas = "true"
+
+[outputs]
+
+  [[outputs.cloudwatch]]
+    tagexclude = ["metricPath"]
+`
+	c, err := FromToml(toml, "linux")
+	assert.NoError(t, err)
+	assert.Equal(t, []Metric{
+		{
+			Namespace:         "CWAgent",
+			MetricName:        "mem_numa_hit",
+			Dimensions:        [][]string{{"host", "numa_node"}},
+			StorageResolution: 60,
+			Source:            "numa",
+		},
+		{
+			Namespace:         "CWAgent",
+			MetricName:        "mem_used_percent",
+			Dimensions:        [][]string{{"host"}},
+			StorageResolution: 60,
+			Source:            "mem",
+		},
+	}, c.Metrics)
+}
+
+func TestFromTomlNetRates(t *testing.T) {
+	toml := `
+[inputs]
+
+  [[inputs.net]]
+    fieldpass = ["bytes_sent"]
+    [inputs.net.tags]
+      metricPath = "metrics"
+      report_rates = "true"
+
+[outputs]
+
+  [[outputs.cloudwatch]]
+    tagexclude = ["metricPath"]
+`
+	c, err := FromToml(toml, "linux")
+	assert.NoError(t, err)
+	assert.Equal(t, []Metric{
+		{
+			Namespace:         "CWAgent",
+			MetricName:        "net_bytes_sent_per_sec",
+			Dimensions:        [][]string{{"host", "interface"}},
+			StorageResolution: 60,
+			Source:            "net",
+		},
+	}, c.Metrics)
+}
+
+func TestFromTomlFD(t *testing.T) {
+	toml := `
+[inputs]
+
+  [[inputs.fd]]
+    fieldpass = ["utilization", "process_utilization"]
+    top_processes = 5
+    [inputs.fd.tags]
+      metricPath = "metrics"
+
+[outputs]
+
+  [[outputs.cloudwatch]]
+    tagexclude = ["metricPath"]
+`
+	c, err := FromToml(toml, "linux")
+	assert.NoError(t, err)
+	assert.Equal(t, []Metric{
+		{
+			Namespace:         "CWAgent",
+			MetricName:        "fd_process_utilization",
+			Dimensions:        [][]string{{"host", "process_name"}},
+			StorageResolution: 60,
+			Source:            "fd",
+		},
+		{
+			Namespace:         "CWAgent",
+			MetricName:        "fd_utilization",
+			Dimensions:        [][]string{{"host"}},
+			StorageResolution: 60,
+			Source:            "fd",
+		},
+	}, c.Metrics)
+}
+
+func TestFromTomlKernel(t *testing.T) {
+	toml := `
+[inputs]
+
+  [[inputs.kernel]]
+    fieldpass = ["conntrack_drop"]
+    [inputs.kernel.tags]
+      ignored_fields_for_delta = "conntrack_count"
+      metricPath = "metrics"
+      report_deltas = "true"
+
+[outputs]
+
+  [[outputs.cloudwatch]]
+    tagexclude = ["metricPath"]
+`
+	c, err := FromToml(toml, "linux")
+	assert.NoError(t, err)
+	assert.Equal(t, []Metric{
+		{
+			Namespace:         "CWAgent",
+			MetricName:        "kernel_conntrack_drop",
+			Dimensions:        [][]string{{"host"}},
+			StorageResolution: 60,
+			Source:            "kernel",
+		},
+	}, c.Metrics)
+}
+
+func TestFromTomlProcstatDimensions(t *testing.T) {
+	toml := `
+[inputs]
+
+  [[inputs.procstat]]
+    cmdline_hash_tag = true
+    fieldpass = ["cpu_usage"]
+    pattern = "java"
+    pid_finder = "native"
+    tagexclude = ["process_name", "pattern", "result"]
+    [inputs.procstat.tags]
+      metricPath = "metrics"
+
+[outputs]
+
+  [[outputs.cloudwatch]]
+    tagexclude = ["metricPath"]
+`
+	c, err := FromToml(toml, "linux")
+	assert.NoError(t, err)
+	assert.Equal(t, []Metric{
+		{
+			Namespace:         "CWAgent",
+			MetricName:        "procstat_cpu_usage",
+			Dimensions:        [][]string{{"host", "cmdline_hash", "user"}},
+			StorageResolution: 60,
+			Source:            "procstat",
+		},
+	}, c.Metrics)
+}
+
+func TestFromTomlProcstatRestartCount(t *testing.T) {
+	toml := `
+[inputs]
+
+  [[inputs.procstat]]
+    fieldpass = ["uptime", "restart_count"]
+    pid_finder = "native"
+    systemd_unit = "nginx.service"
+    tagexclude = ["user", "result"]
+    [inputs.procstat.tags]
+      metricPath = "metrics"
+
+[outputs]
+
+  [[outputs.cloudwatch]]
+    tagexclude = ["metricPath"]
+`
+	c, err := FromToml(toml, "linux")
+	assert.NoError(t, err)
+	assert.Equal(t, []Metric{
+		{
+			Namespace:         "CWAgent",
+			MetricName:        "procstat_lookup_restart_count",
+			Dimensions:        [][]string{{"host", "pid_finder", "systemd_unit"}},
+			StorageResolution: 60,
+			Source:            "procstat",
+		},
+		{
+			Namespace:         "CWAgent",
+			MetricName:        "procstat_uptime",
+			Dimensions:        [][]string{{"host", "process_name", "systemd_unit"}},
+			StorageResolution: 60,
+			Source:            "procstat",
+		},
+	}, c.Metrics)
+}
+
+func TestFromTomlProcstatEnvironmentPattern(t *testing.T) {
+	toml := `
+[inputs]
+
+  [[inputs.procstat]]
+    environment_pattern = "^APP_NAME=payments$"
+    exe = "python"
+    fieldpass = ["alive", "cpu_usage", "pid_count"]
+    pid_finder = "native"
+    tagexclude = ["user", "exe", "result"]
+    [inputs.procstat.tags]
+      metricPath = "metrics"
+
+[outputs]
+
+  [[outputs.cloudwatch]]
+    tagexclude = ["metricPath"]
+`
+	c, err := FromToml(toml, "linux")
+	assert.NoError(t, err)
+	assert.Equal(t, []Metric{
+		{
+			Namespace:         "CWAgent",
+			MetricName:        "procstat_cpu_usage",
+			Dimensions:        [][]string{{"host", "environment_pattern", "process_name"}},
+			StorageResolution: 60,
+			Source:            "procstat",
+		},
+		{
+			Namespace:         "CWAgent",
+			MetricName:        "procstat_lookup_alive",
+			Dimensions:        [][]string{{"host", "environment_pattern", "pid_finder"}},
+			StorageResolution: 60,
+			Source:            "procstat",
+		},
+		{
+			Namespace:         "CWAgent",
+			MetricName:        "procstat_lookup_pid_count",
+			Dimensions:        [][]string{{"host", "environment_pattern", "pid_finder"}},
+			StorageResolution: 60,
+			Source:            "procstat",
+		},
+	}, c.Metrics)
+}
+
+func TestFromTomlProcstatContainer(t *testing.T) {
+	toml := `
+[inputs]
+
+  [[inputs.procstat]]
+    container_id_tag = true
+    container_name_pattern = "^payments-"
+    exe = "python"
+    fieldpass = ["cpu_usage", "pid_count"]
+    pid_finder = "native"
+    tagexclude = ["user", "exe", "result"]
+    [inputs.procstat.container_labels]
+      team = "payments"
+    [inputs.procstat.tags]
+      metricPath = "metrics"
+
+[outputs]
+
+  [[outputs.cloudwatch]]
+    tagexclude = ["metricPath"]
+`
+	c, err := FromToml(toml, "linux")
+	assert.NoError(t, err)
+	assert.Equal(t, []Metric{
+		{
+			Namespace:         "CWAgent",
+			MetricName:        "procstat_cpu_usage",
+			Dimensions:        [][]string{{"host", "container_id", "container_name", "container_name_pattern", "process_name"}},
+			StorageResolution: 60,
+			Source:            "procstat",
+		},
+		{
+			Namespace:         "CWAgent",
+			MetricName:        "procstat_lookup_pid_count",
+			Dimensions:        [][]string{{"host", "container_name_pattern", "pid_finder"}},
+			StorageResolution: 60,
+			Source:            "procstat",
+		},
+	}, c.Metrics)
+}
+
+func TestFromTomlTopProcesses(t *testing.T) {
+	toml := `
+[inputs]
+
+  [[inputs.top_processes]]
+    fieldpass = ["cpu_usage"]
+    rank_by = ["cpu"]
+    top_n = 10
+    [inputs.top_processes.tags]
+      metricPath = "metrics"
+
+[outputs]
+
+  [[outputs.cloudwatch]]
+    tagexclude = ["metricPath"]
+`
+	c, err := FromToml(toml, "linux")
+	assert.NoError(t, err)
+	assert.Equal(t, []Metric{
+		{
+			Namespace:         "CWAgent",
+			MetricName:        "top_processes_cpu_usage",
+			Dimensions:        [][]string{{"host", "process_name"}},
+			StorageResolution: 60,
+			Source:            "top_processes",
+		},
+	}, c.Metrics)
+}
+
+func TestFromTomlSensors(t *testing.T) {
+	toml := `
+[inputs]
+
+  [[inputs.sensors]]
+    fieldpass = ["temp_input"]
+    [inputs.sensors.tags]
+      metricPath = "metrics"
+
+[outputs]
+
+  [[outputs.cloudwatch]]
+    tagexclude = ["metricPath"]
+`
+	c, err := FromToml(toml, "linux")
+	assert.NoError(t, err)
+	assert.Equal(t, []Metric{
+		{
+			Namespace:         "CWAgent",
+			MetricName:        "sensors_temp_input",
+			Dimensions:        [][]string{{"host", "chip", "label"}},
+			StorageResolution: 60,
+			Source:            "sensors",
+		},
+	}, c.Metrics)
+}
+
+func TestFromTomlWindowsGPU(t *testing.T) {
+	toml := `
+[inputs]
+
+  [[inputs.windows_gpu]]
+    fieldpass = ["utilization", "engine_utilization"]
+    [inputs.windows_gpu.tags]
+      metricPath = "metrics"
+
+[outputs]
+
+  [[outputs.cloudwatch]]
+    tagexclude = ["host", "metricPath"]
+`
+	c, err := FromToml(toml, "windows")
+	assert.NoError(t, err)
+	assert.Equal(t, []Metric{
+		{
+			Namespace:         "CWAgent",
+			MetricName:        "windows_gpu engine_utilization",
+			Dimensions:        [][]string{{"adapter", "engine_type"}},
+			StorageResolution: 60,
+			Source:            "windows_gpu",
+		},
+		{
+			Namespace:         "CWAgent",
+			MetricName:        "windows_gpu utilization",
+			Dimensions:        [][]string{{"adapter"}},
+			StorageResolution: 60,
+			Source:            "windows_gpu",
+		},
+	}, c.Metrics)
+}
+
+func TestFromTomlCloudWatchQuery(t *testing.T) {
+	toml := `
+[inputs]
+
+  [[inputs.cloudwatch_query]]
+    tagexclude = ["host"]
+
+    [[inputs.cloudwatch_query.query]]
+      id = "errors"
+      metric_name = "Errors"
+      namespace = "MyApp"
+      return_data = false
+
+    [[inputs.cloudwatch_query.query]]
+      expression = "100 * errors / requests"
+      id = "error_ratio"
+      name = "checkout_error_ratio"
+
+    [[inputs.cloudwatch_query.query]]
+      expression = "SEARCH('{MyApp,AvailabilityZone} MetricName=\"Requests\"', 'Sum')"
+      id = "requests_by_az"
+      label_dimension = "AvailabilityZone"
+    [inputs.cloudwatch_query.tags]
+      metricPath = "metrics"
+
+[outputs]
+
+  [[outputs.cloudwatch]]
+    tagexclude = ["metricPath"]
+`
+	c, err := FromToml(toml, "linux")
+	assert.NoError(t, err)
+	assert.Equal(t, []Metric{
+		{
+			Namespace:         "CWAgent",
+			MetricName:        "checkout_error_ratio",
+			Dimensions:        [][]string{{}},
+			StorageResolution: 60,
+			Source:            "cloudwatch_query",
+		},
+		{
+			Namespace:         "CWAgent",
+			MetricName:        "requests_by_az",
+			Dimensions:        [][]string{{"AvailabilityZone"}},
+			StorageResolution: 60,
+			Source:            "cloudwatch_query",
+		},
+	}, c.Metrics)
+}
+
+func TestFromTomlCPUCluster(t *testing.T) {
+	toml := `
+[inputs]
+
+  [[inputs.cpu_cluster]]
+    fieldpass = ["frequency_mhz", "instructions_per_cycle"]
+    pmu_counters = true
+    [inputs.cpu_cluster.tags]
+      metricPath = "metrics"
+
+[outputs]
+
+  [[outputs.cloudwatch]]
+    tagexclude = ["metricPath"]
+`
+	c, err := FromToml(toml, "linux")
+	assert.NoError(t, err)
+	assert.Equal(t, []Metric{
+		{
+			Namespace:         "CWAgent",
+			MetricName:        "cpu_cluster_frequency_mhz",
+			Dimensions:        [][]string{{"host", "cluster"}},
+			StorageResolution: 60,
+			Source:            "cpu_cluster",
+		},
+		{
+			Namespace:         "CWAgent",
+			MetricName:        "cpu_cluster_instructions_per_cycle",
+			Dimensions:        [][]string{{"host", "cluster"}},
+			StorageResolution: 60,
+			Source:            "cpu_cluster",
+		},
+	}, c.Metrics)
+}
+
+func TestFromTomlNetstatPorts(t *testing.T) {
+	toml := `
+[inputs]
+
+  [[inputs.netstat]]
+    fieldpass = ["tcp_established"]
+    [inputs.netstat.tags]
+      metricPath = "metrics"
+
+  [[inputs.netstat_ports]]
+    by_process = true
+    fieldpass = ["tcp_established"]
+    ports = [443]
+    [inputs.netstat_ports.tags]
+      metricPath = "metrics"
+
+[outputs]
+
+  [[outputs.cloudwatch]]
+    tagexclude = ["metricPath"]
+`
+	c, err := FromToml(toml, "linux")
+	assert.NoError(t, err)
+	assert.Equal(t, []Metric{
+		{
+			Namespace:         "CWAgent",
+			MetricName:        "netstat_tcp_established",
+			Dimensions:        [][]string{{"host"}},
+			StorageResolution: 60,
+			Source:            "netstat",
+		},
+		{
+			Namespace:         "CWAgent",
+			MetricName:        "netstat_tcp_established",
+			Dimensions:        [][]string{{"host", "port", "process_name"}},
+			StorageResolution: 60,
+			Source:            "netstat_ports",
+		},
+	}, c.Metrics)
+}
+
+func TestFromTomlDiskIOLatency(t *testing.T) {
+	toml := `
+[inputs]
+
+  [[inputs.diskio_latency]]
+    fieldpass = ["read_latency"]
+    [inputs.diskio_latency.tags]
+      metricPath = "metrics"
+
+[outputs]
+
+  [[outputs.cloudwatch]]
+    tagexclude = ["metricPath"]
+`
+	c, err := FromToml(toml, "linux")
+	assert.NoError(t, err)
+	assert.Equal(t, []Metric{
+		{
+			Namespace:         "CWAgent",
+			MetricName:        "diskio_read_latency",
+			Dimensions:        [][]string{{"host", "name"}},
+			StorageResolution: 60,
+			Source:            "diskio_latency",
+		},
+	}, c.Metrics)
+}
@@ -0,0 +1,757 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package catalog
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"github.com/aws/amazon-cloudwatch-agent/plugins/processors/delta"
+	"github.com/influxdata/telegraf/metric"
+	"github.com/influxdata/telegraf/plugins/processors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// the translated sample configs, the toTomlConfig tests check they are the output of the translator
+const fixturesDir = "../totomlconfig/sampleConfig"
+
+type fixture struct {
+	name     string
+	targetOs string
+	toml     string
+	inputs   map[string]interface{}
+	// the dimension keys set by the user in the json config
+	userTags map[string]bool
+	// the metric_decoration renames of the cloudwatch output
+	renames map[string]string
+}
+
+func loadFixtures(t *testing.T) []fixture {
+	paths, err := filepath.Glob(filepath.Join(fixturesDir, "*.conf"))
+	require.NoError(t, err)
+	require.NotEmpty(t, paths)
+	var fixtures []fixture
+	for _, path := range paths {
+		data, err := ioutil.ReadFile(path)
+		require.NoError(t, err)
+		var conf map[string]interface{}
+		_, err = toml.Decode(string(data), &conf)
+		require.NoError(t, err, path)
+		f := fixture{
+			name:     filepath.Base(path),
+			targetOs: "linux",
+			toml:     string(data),
+			inputs:   mapValue(conf, "inputs"),
+			userTags: map[string]bool{},
+			renames:  map[string]string{},
+		}
+		for _, output := range tables(mapValue(conf, "outputs")["cloudwatch"]) {
+			for _, decoration := range tables(output["metric_decoration"]) {
+				f.renames[decorationKey(stringValue(decoration, "category"), stringValue(decoration, "name"))] = stringValue(decoration, "rename")
+			}
+		}
+		if strings.Contains(f.name, "_windows") {
+			f.targetOs = "windows"
+		} else if strings.Contains(f.name, "_darwin") {
+			f.targetOs = "darwin"
+		}
+		if jsonData, err := ioutil.ReadFile(strings.TrimSuffix(path, ".conf") + ".json"); err == nil {
+			var jsonConf interface{}
+			require.NoError(t, json.Unmarshal(jsonData, &jsonConf), path)
+			collectUserTags(jsonConf, f.userTags)
+		}
+		fixtures = append(fixtures, f)
+	}
+	return fixtures
+}
+
+// collectUserTags collects the keys of every append_dimensions of the json config.
+func collectUserTags(value interface{}, tags map[string]bool) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			if dimensions, ok := item.(map[string]interface{}); ok && key == "append_dimensions" {
+				for dimension := range dimensions {
+					tags[dimension] = true
+				}
+			}
+			collectUserTags(item, tags)
+		}
+	case []interface{}:
+		for _, item := range v {
+			collectUserTags(item, tags)
+		}
+	}
+}
+
+// metricInputs returns the inputs published by the cloudwatch output, by plugin name.
+func (f fixture) metricInputs() map[string][]map[string]interface{} {
+	result := map[string][]map[string]interface{}{}
+	for pluginName, value := range f.inputs {
+		for _, input := range tables(value) {
+			if stringValue(mapValue(input, "tags"), "metricPath") == metricsPath {
+				result[pluginName] = append(result[pluginName], input)
+			}
+		}
+	}
+	return result
+}
+
+// fixtureCatalogs are the catalogs of the translated sample configs, by file name.
+var fixtureCatalogs = map[string]Catalog{
+	"advanced_config_linux.conf": {
+		Metrics: []Metric{
+			{Namespace: "CWAgent", MetricName: "cpu_usage_idle", Dimensions: [][]string{{"AutoScalingGroupName", "ImageId", "InstanceId", "InstanceType", "cpu"}}, StorageResolution: 60, Source: "cpu"},
+			{Namespace: "CWAgent", MetricName: "cpu_usage_iowait", Dimensions: [][]string{{"AutoScalingGroupName", "ImageId", "InstanceId", "InstanceType", "cpu"}}, StorageResolution: 60, Source: "cpu"},
+			{Namespace: "CWAgent", MetricName: "cpu_usage_system", Dimensions: [][]string{{"AutoScalingGroupName", "ImageId", "InstanceId", "InstanceType", "cpu"}}, StorageResolution: 60, Source: "cpu"},
+			{Namespace: "CWAgent", MetricName: "cpu_usage_user", Dimensions: [][]string{{"AutoScalingGroupName", "ImageId", "InstanceId", "InstanceType", "cpu"}}, StorageResolution: 60, Source: "cpu"},
+			{Namespace: "CWAgent", MetricName: "disk_inodes_free", Dimensions: [][]string{{"AutoScalingGroupName", "ImageId", "InstanceId", "InstanceType", "device", "fstype", "path"}}, StorageResolution: 60, Source: "disk"},
+			{Namespace: "CWAgent", MetricName: "disk_used_percent", Dimensions: [][]string{{"AutoScalingGroupName", "ImageId", "InstanceId", "InstanceType", "device", "fstype", "path"}}, StorageResolution: 60, Source: "disk"},
+			{Namespace: "CWAgent", MetricName: "diskio_io_time", Dimensions: [][]string{{"AutoScalingGroupName", "ImageId", "InstanceId", "InstanceType", "name"}}, StorageResolution: 60, Source: "diskio"},
+			{Namespace: "CWAgent", MetricName: "diskio_read_bytes", Dimensions: [][]string{{"AutoScalingGroupName", "ImageId", "InstanceId", "InstanceType", "name"}}, StorageResolution: 60, Source: "diskio"},
+			{Namespace: "CWAgent", MetricName: "diskio_reads", Dimensions: [][]string{{"AutoScalingGroupName", "ImageId", "InstanceId", "InstanceType", "name"}}, StorageResolution: 60, Source: "diskio"},
+			{Namespace: "CWAgent", MetricName: "diskio_write_bytes", Dimensions: [][]string{{"AutoScalingGroupName", "ImageId", "InstanceId", "InstanceType", "name"}}, StorageResolution: 60, Source: "diskio"},
+			{Namespace: "CWAgent", MetricName: "diskio_writes", Dimensions: [][]string{{"AutoScalingGroupName", "ImageId", "InstanceId", "InstanceType", "name"}}, StorageResolution: 60, Source: "diskio"},
+			{Namespace: "CWAgent", MetricName: "ethtool_bw_in_allowance_exceeded", Dimensions: [][]string{{"AutoScalingGroupName", "ImageId", "InstanceId", "InstanceType", "driver", "interface"}}, StorageResolution: 60, Source: "ethtool"},
+			{Namespace: "CWAgent", MetricName: "ethtool_bw_out_allowance_exceeded", Dimensions: [][]string{{"AutoScalingGroupName", "ImageId", "InstanceId", "InstanceType", "driver", "interface"}}, StorageResolution: 60, Source: "ethtool"},
+			{Namespace: "CWAgent", MetricName: "ethtool_conntrack_allowance_exceeded", Dimensions: [][]string{{"AutoScalingGroupName", "ImageId", "InstanceId", "InstanceType", "driver", "interface"}}, StorageResolution: 60, Source: "ethtool"},
+			{Namespace: "CWAgent", MetricName: "ethtool_linklocal_allowance_exceeded", Dimensions: [][]string{{"AutoScalingGroupName", "ImageId", "InstanceId", "InstanceType", "driver", "interface"}}, StorageResolution: 60, Source: "ethtool"},
+			{Namespace: "CWAgent", MetricName: "ethtool_pps_allowance_exceeded", Dimensions: [][]string{{"AutoScalingGroupName", "ImageId", "InstanceId", "InstanceType", "driver", "interface"}}, StorageResolution: 60, Source: "ethtool"},
+			{Namespace: "CWAgent", MetricName: "mem_used_percent", Dimensions: [][]string{{"AutoScalingGroupName", "ImageId", "InstanceId", "InstanceType"}}, StorageResolution: 60, Source: "mem"},
+			{Namespace: "CWAgent", MetricName: "netstat_tcp_established", Dimensions: [][]string{{"AutoScalingGroupName", "ImageId", "InstanceId", "InstanceType"}}, StorageResolution: 60, Source: "netstat"},
+			{Namespace: "CWAgent", MetricName: "netstat_tcp_time_wait", Dimensions: [][]string{{"AutoScalingGroupName", "ImageId", "InstanceId", "InstanceType"}}, StorageResolution: 60, Source: "netstat"},
+			{Namespace: "CWAgent", MetricName: "nvidia_smi_power_draw", Dimensions: [][]string{{"AutoScalingGroupName", "ImageId", "InstanceId", "InstanceType", "index", "name"}}, StorageResolution: 60, Source: "nvidia_smi"},
+			{Namespace: "CWAgent", MetricName: "nvidia_smi_temperature_gpu", Dimensions: [][]string{{"AutoScalingGroupName", "ImageId", "InstanceId", "InstanceType", "index", "name"}}, StorageResolution: 60, Source: "nvidia_smi"},
+			{Namespace: "CWAgent", MetricName: "nvidia_smi_utilization_gpu", Dimensions: [][]string{{"AutoScalingGroupName", "ImageId", "InstanceId", "InstanceType", "index", "name"}}, StorageResolution: 60, Source: "nvidia_smi"},
+			{Namespace: "CWAgent", MetricName: "nvidia_smi_utilization_memory", Dimensions: [][]string{{"AutoScalingGroupName", "ImageId", "InstanceId", "InstanceType", "index", "name"}}, StorageResolution: 60, Source: "nvidia_smi"},
+			{Namespace: "CWAgent", MetricName: "swap_used_percent", Dimensions: [][]string{{"AutoScalingGroupName", "ImageId", "InstanceId", "InstanceType"}}, StorageResolution: 60, Source: "swap"},
+		},
+	},
+	"advanced_config_windows.conf": {
+		Metrics: []Metric{
+			{Namespace: "CWAgent", MetricName: "LogicalDisk % Free Space", Dimensions: [][]string{{"AutoScalingGroupName", "ImageId", "InstanceId", "InstanceType", "instance", "objectname"}}, StorageResolution: 1, Source: "win_perf_counters"},
+			{Namespace: "CWAgent", MetricName: "Memory % Committed Bytes In Use", Dimensions: [][]string{{"AutoScalingGroupName", "ImageId", "InstanceId", "InstanceType", "objectname"}}, StorageResolution: 1, Source: "win_perf_counters"},
+			{Namespace: "CWAgent", MetricName: "Paging File % Usage", Dimensions: [][]string{{"AutoScalingGroupName", "ImageId", "InstanceId", "InstanceType", "instance", "objectname"}}, StorageResolution: 1, Source: "win_perf_counters"},
+			{Namespace: "CWAgent", MetricName: "PhysicalDisk % Disk Time", Dimensions: [][]string{{"AutoScalingGroupName", "ImageId", "InstanceId", "InstanceType", "instance", "objectname"}}, StorageResolution: 1, Source: "win_perf_counters"},
+			{Namespace: "CWAgent", MetricName: "PhysicalDisk Disk Read Bytes/sec", Dimensions: [][]string{{"AutoScalingGroupName", "ImageId", "InstanceId", "InstanceType", "instance", "objectname"}}, StorageResolution: 1, Source: "win_perf_counters"},
+			{Namespace: "CWAgent", MetricName: "PhysicalDisk Disk Reads/sec", Dimensions: [][]string{{"AutoScalingGroupName", "ImageId", "InstanceId", "InstanceType", "instance", "objectname"}}, StorageResolution: 1, Source: "win_perf_counters"},
+			{Namespace: "CWAgent", MetricName: "PhysicalDisk Disk Write Bytes/sec", Dimensions: [][]string{{"AutoScalingGroupName", "ImageId", "InstanceId", "InstanceType", "instance", "objectname"}}, StorageResolution: 1, Source: "win_perf_counters"},
+			{Namespace: "CWAgent", MetricName: "PhysicalDisk Disk Writes/sec", Dimensions: [][]string{{"AutoScalingGroupName", "ImageId", "InstanceId", "InstanceType", "instance", "objectname"}}, StorageResolution: 1, Source: "win_perf_counters"},
+			{Namespace: "CWAgent", MetricName: "Processor % Idle Time", Dimensions: [][]string{{"AutoScalingGroupName", "ImageId", "InstanceId", "InstanceType", "instance", "objectname"}}, StorageResolution: 1, Source: "win_perf_counters"},
+			{Namespace: "CWAgent", MetricName: "Processor % Interrupt Time", Dimensions: [][]string{{"AutoScalingGroupName", "ImageId", "InstanceId", "InstanceType", "instance", "objectname"}}, StorageResolution: 1, Source: "win_perf_counters"},
+			{Namespace: "CWAgent", MetricName: "Processor % User Time", Dimensions: [][]string{{"AutoScalingGroupName", "ImageId", "InstanceId", "InstanceType", "instance", "objectname"}}, StorageResolution: 1, Source: "win_perf_counters"},
+			{Namespace: "CWAgent", MetricName: "TCPv4 Connections Established", Dimensions: [][]string{{"AutoScalingGroupName", "ImageId", "InstanceId", "InstanceType", "objectname"}}, StorageResolution: 1, Source: "win_perf_counters"},
+			{Namespace: "CWAgent", MetricName: "TCPv6 Connections Established", Dimensions: [][]string{{"AutoScalingGroupName", "ImageId", "InstanceId", "InstanceType", "objectname"}}, StorageResolution: 1, Source: "win_perf_counters"},
+		},
+	},
+	"agent_audit_config_linux.conf": {},
+	"basic_config_linux.conf": {
+		Metrics: []Metric{
+			{Namespace: "CWAgent", MetricName: "disk_used_percent", Dimensions: [][]string{{"AutoScalingGroupName", "ImageId", "InstanceId", "InstanceType", "device", "fstype", "path"}}, StorageResolution: 60, Source: "disk"},
+			{Namespace: "CWAgent", MetricName: "mem_used_percent", Dimensions: [][]string{{"AutoScalingGroupName", "ImageId", "InstanceId", "InstanceType"}}, StorageResolution: 60, Source: "mem"},
+		},
+	},
+	"basic_config_windows.conf": {
+		Metrics: []Metric{
+			{Namespace: "CWAgent", MetricName: "LogicalDisk % Free Space", Dimensions: [][]string{{"AutoScalingGroupName", "ImageId", "InstanceId", "InstanceType", "instance", "objectname"}}, StorageResolution: 60, Source: "win_perf_counters"},
+			{Namespace: "CWAgent", MetricName: "Memory % Committed Bytes In Use", Dimensions: [][]string{{"AutoScalingGroupName", "ImageId", "InstanceId", "InstanceType", "objectname"}}, StorageResolution: 60, Source: "win_perf_counters"},
+		},
+	},
+	"canary_config_linux.conf": {},
+	"cgroup_config_linux.conf": {
+		Metrics: []Metric{
+			{Namespace: "CWAgent", MetricName: "cgroup_cpu_throttled_periods", Dimensions: [][]string{{"InstanceId", "cgroup"}}, StorageResolution: 60, Source: "cgroup"},
+			{Namespace: "CWAgent", MetricName: "cgroup_cpu_usage_percent", Dimensions: [][]string{{"InstanceId", "cgroup"}}, StorageResolution: 60, Source: "cgroup"},
+			{Namespace: "CWAgent", MetricName: "cgroup_io_read_bytes", Dimensions: [][]string{{"InstanceId", "cgroup"}}, StorageResolution: 60, Source: "cgroup"},
+			{Namespace: "CWAgent", MetricName: "cgroup_io_write_bytes", Dimensions: [][]string{{"InstanceId", "cgroup"}}, StorageResolution: 60, Source: "cgroup"},
+			{Namespace: "CWAgent", MetricName: "cgroup_memory_oom_kills", Dimensions: [][]string{{"InstanceId", "cgroup"}}, StorageResolution: 60, Source: "cgroup"},
+			{Namespace: "CWAgent", MetricName: "cgroup_memory_usage", Dimensions: [][]string{{"InstanceId", "cgroup"}}, StorageResolution: 60, Source: "cgroup"},
+			{Namespace: "CWAgent", MetricName: "cgroup_memory_utilization", Dimensions: [][]string{{"InstanceId", "cgroup"}}, StorageResolution: 60, Source: "cgroup"},
+		},
+	},
+	"cloudwatch_query_config_linux.conf": {
+		Metrics: []Metric{
+			{Namespace: "CWAgent", MetricName: "checkout_error_ratio", Dimensions: [][]string{{}}, StorageResolution: 60, Source: "cloudwatch_query"},
+		},
+	},
+	"collectd_config_linux.conf": {
+		DynamicSources: []DynamicSource{
+			{Namespace: "CWAgent", Source: "collectd_listener", Dimensions: [][]string{{"host"}}},
+		},
+	},
+	"complete_darwin_config.conf": {
+		Metrics: []Metric{
+			{Namespace: "CWAgent", MetricName: "CPU_USAGE_IDLE", Unit: "unit", Dimensions: [][]string{{"AutoScalingGroupName", "ImageId", "InstanceId", "InstanceType", "cpu", "d1", "d2"}, {"ImageId"}, {"InstanceId", "InstanceType"}, {"d1"}, {}}, StorageResolution: 1, Source: "cpu"},
+			{Namespace: "CWAgent", MetricName: "DISK_FREE", Unit: "unit", Dimensions: [][]string{{"AutoScalingGroupName", "ImageId", "InstanceId", "InstanceType", "d3", "d4", "device", "fstype", "path"}, {"ImageId"}, {"InstanceId", "InstanceType"}, {}}, StorageResolution: 60, Source: "disk"},
+			{Namespace: "CWAgent", MetricName: "cpu_time_active", Dimensions: [][]string{{"AutoScalingGroupName", "ImageId", "InstanceId", "InstanceType", "cpu", "d1", "d2"}, {"ImageId"}, {"InstanceId", "InstanceType"}, {"d1"}, {}}, StorageResolution: 1, Source: "cpu"},
+			{Namespace: "CWAgent", MetricName: "cpu_usage_active", Dimensions: [][]string{{"AutoScalingGroupName", "ImageId", "InstanceId", "InstanceType", "cpu", "d1", "d2"}, {"ImageId"}, {"InstanceId", "InstanceType"}, {"d1"}, {}}, StorageResolution: 1, Source: "cpu"},
+			{Namespace: "CWAgent", MetricName: "cpu_usage_guest", Dimensions: [][]string{{"AutoScalingGroupName", "ImageId", "InstanceId", "InstanceType", "cpu", "d1", "d2"}, {"ImageId"}, {"InstanceId", "InstanceType"}, {"d1"}, {}}, StorageResolution: 1, Source: "cpu"},
+			{Namespace: "CWAgent", MetricName: "cpu_usage_nice", Unit: "unit", Dimensions: [][]string{{"AutoScalingGroupName", "ImageId", "InstanceId", "InstanceType", "cpu", "d1", "d2"}, {"ImageId"}, {"InstanceId", "InstanceType"}, {"d1"}, {}}, StorageResolution: 1, Source: "cpu"},
+			{Namespace: "CWAgent", MetricName: "disk_total", Dimensions: [][]string{{"AutoScalingGroupName", "ImageId", "InstanceId", "InstanceType", "d3", "d4", "device", "fstype", "path"}, {"ImageId"}, {"InstanceId", "InstanceType"}, {}}, StorageResolution: 60, Source: "disk"},
+			{Namespace: "CWAgent", MetricName: "disk_used", Dimensions: [][]string{{"AutoScalingGroupName", "ImageId", "InstanceId", "InstanceType", "d3", "d4", "device", "fstype", "path"}, {"ImageId"}, {"InstanceId", "InstanceType"}, {}}, StorageResolution: 60, Source: "disk"},
+			{Namespace: "CWAgent", MetricName: "diskio_io_time", Dimensions: [][]string{{"AutoScalingGroupName", "ImageId", "InstanceId", "InstanceType", "name"}, {"ImageId"}, {"InstanceId", "InstanceType"}, {}}, StorageResolution: 60, Source: "diskio"},
+			{Namespace: "CWAgent", MetricName: "diskio_read_time", Dimensions: [][]string{{"AutoScalingGroupName", "ImageId", "InstanceId", "InstanceType", "name"}, {"ImageId"}, {"InstanceId", "InstanceType"}, {}}, StorageResolution: 60, Source: "diskio"},
+			{Namespace: "CWAgent", MetricName: "diskio_reads", Dimensions: [][]string{{"AutoScalingGroupName", "ImageId", "InstanceId", "InstanceType", "name"}, {"ImageId"}, {"InstanceId", "InstanceType"}, {}}, StorageResolution: 60, Source: "diskio"},
+			{Namespace: "CWAgent", MetricName: "diskio_write_time", Dimensions: [][]string{{"AutoScalingGroupName", "ImageId", "InstanceId", "InstanceType", "name"}, {"ImageId"}, {"InstanceId", "InstanceType"}, {}}, StorageResolution: 60, Source: "diskio"},
+			{Namespace: "CWAgent", MetricName: "diskio_writes", Dimensions: [][]string{{"AutoScalingGroupName", "ImageId", "InstanceId", "InstanceType", "name"}, {"ImageId"}, {"InstanceId", "InstanceType"}, {}}, StorageResolution: 60, Source: "diskio"},
+			{Namespace: "CWAgent", MetricName: "mem_cached", Dimensions: [][]string{{"AutoScalingGroupName", "ImageId", "InstanceId", "InstanceType"}, {"ImageId"}, {"InstanceId", "InstanceType"}, {}}, StorageResolution: 1, Source: "mem"},
+			{Namespace: "CWAgent", MetricName: "mem_total", Dimensions: [][]string{{"AutoScalingGroupName", "ImageId", "InstanceId", "InstanceType"}, {"ImageId"}, {"InstanceId", "InstanceType"}, {}}, StorageResolution: 1, Source: "mem"},
+			{Namespace: "CWAgent", MetricName: "mem_used", Dimensions: [][]string{{"AutoScalingGroupName", "ImageId", "InstanceId", "InstanceType"}, {"ImageId"}, {"InstanceId", "InstanceType"}, {}}, StorageResolution: 1, Source: "mem"},
+			{Namespace: "CWAgent", MetricName: "net_bytes_recv", Dimensions: [][]string{{"AutoScalingGroupName", "ImageId", "InstanceId", "InstanceType", "interface"}, {"ImageId"}, {"InstanceId", "InstanceType"}, {}}, StorageResolution: 1, Source: "net"},
+			{Namespace: "CWAgent", MetricName: "net_bytes_sent", Dimensions: [][]string{{"AutoScalingGroupName", "ImageId", "InstanceId", "InstanceType", "interface"}, {"ImageId"}, {"InstanceId", "InstanceType"}, {}}, StorageResolution: 1, Source: "net"},
+			{Namespace: "CWAgent", MetricName: "net_drop_in", Dimensions: [][]string{{"AutoScalingGroupName", "ImageId", "InstanceId", "InstanceType", "interface"}, {"ImageId"}, {"InstanceId", "InstanceType"}, {}}, StorageResolution: 1, Source: "net"},
+			{Namespace: "CWAgent", MetricName: "net_drop_out", Dimensions: [][]string{{"AutoScalingGroupName", "ImageId", "InstanceId", "InstanceType", "interface"}, {"ImageId"}, {"InstanceId", "InstanceType"}, {}}, StorageResolution: 1, Source: "net"},
+			{Namespace: "CWAgent", MetricName: "netstat_tcp_close", Dimensions: [][]string{{"AutoScalingGroupName", "ImageId", "InstanceId", "InstanceType"}, {"ImageId"}, {"InstanceId", "InstanceType"}, {}}, StorageResolution: 60, Source: "netstat"},
+			{Namespace: "CWAgent", MetricName: "netstat_tcp_established", Dimensions: [][]string{{"AutoScalingGroupName", "ImageId", "InstanceId", "InstanceType"}, {"ImageId"}, {"InstanceId", "InstanceType"}, {}}, StorageResolution: 60, Source: "netstat"},
+			{Namespace: "CWAgent", MetricName: "netstat_tcp_syn_sent", Dimensions: [][]string{{"AutoScalingGroupName", "ImageId", "InstanceId", "InstanceType"}, {"ImageId"}, {"InstanceId", "InstanceType"}, {}}, StorageResolution: 60, Source: "netstat"},
+			{Namespace: "CWAgent", MetricName: "processes_running", Dimensions: [][]string{{"AutoScalingGroupName", "ImageId", "InstanceId", "InstanceType"}, {"ImageId"}, {"InstanceId", "InstanceType"}, {}}, StorageResolution: 1, Source: "processes"},
+			{Namespace: "CWAgent", MetricName: "processes_sleeping", Dimensions: [][]string{{"AutoScalingGroupName", "ImageId", "InstanceId", "InstanceType"}, {"ImageId"}, {"InstanceId", "InstanceType"}, {}}, StorageResolution: 1, Source: "processes"},
+			{Namespace: "CWAgent", MetricName: "procstat_cpu_usage", Dimensions: [][]string{{"AutoScalingGroupName", "ImageId", "InstanceId", "InstanceType", "pidfile", "process_name"}, {"ImageId"}, {"InstanceId", "InstanceType"}, {}}, StorageResolution: 1, Source: "procstat"},
+			{Namespace: "CWAgent", MetricName: "procstat_memory_rss", Dimensions: [][]string{{"AutoScalingGroupName", "ImageId", "InstanceId", "InstanceType", "pidfile", "process_name"}, {"ImageId"}, {"InstanceId", "InstanceType"}, {}}, StorageResolution: 1, Source: "procstat"},
+			{Namespace: "CWAgent", MetricName: "swap_free", Dimensions: [][]string{{"AutoScalingGroupName", "ImageId", "InstanceId", "InstanceType"}, {"ImageId"}, {"InstanceId", "InstanceType"}, {}}, StorageResolution: 1, Source: "swap"},
+			{Namespace: "CWAgent", MetricName: "swap_used", Dimensions: [][]string{{"AutoScalingGroupName", "ImageId", "InstanceId", "InstanceType"}, {"ImageId"}, {"InstanceId", "InstanceType"}, {}}, StorageResolution: 1, Source: "swap"},
+			{Namespace: "CWAgent", MetricName: "swap_used_percent", Dimensions: [][]string{{"AutoScalingGroupName", "ImageId", "InstanceId", "InstanceType"}, {"ImageId"}, {"InstanceId", "InstanceType"}, {}}, StorageResolution: 1, Source: "swap"},
+		},
+		DynamicSources: []DynamicSource{
+			{Namespace: "CWAgent", Source: "collectd_listener", Dimensions: [][]string{{"AutoScalingGroupName", "ImageId", "InstanceId", "InstanceType"}, {"ImageId"}, {"InstanceId", "InstanceType"}, {}}},
+			{Namespace: "CWAgent", Source: "statsd", Dimensions: [][]string{{"AutoScalingGroupName", "ImageId", "InstanceId", "InstanceType"}, {"ImageId"}, {"InstanceId", "InstanceType"}, {}}},
+		},
+	},
+	"complete_linux_config.conf": {
+		Metrics: []Metric{
+			{Namespace: "CWAgent", MetricName: "CPU_USAGE_IDLE", Unit: "unit", Dimensions: [][]string{{"AutoScalingGroupName", "ImageId", "InstanceId", "InstanceType", "cpu", "d1", "d2"}, {"ImageId"}, {"InstanceId", "InstanceType"}, {"d1"}, {}}, StorageResolution: 1, Source: "cpu"},
+			{Namespace: "CWAgent", MetricName: "DISK_FREE", Unit: "unit", Dimensions: [][]string{{"AutoScalingGroupName", "ImageId", "InstanceId", "InstanceType", "d3", "d4", "device", "fstype", "path"}, {"ImageId"}, {"InstanceId", "InstanceType"}, {}}, StorageResolution: 60, Source: "disk"},
+			{Namespace: "CWAgent", MetricName: "cpu_time_active", Dimensions: [][]string{{"ImageId"}, {"InstanceId", "InstanceType"}, {"d1"}, {}}, StorageResolution: 1, Source: "cpu"},
+			{Namespace: "CWAgent", MetricName: "cpu_usage_active", Dimensions: [][]string{{"AutoScalingGroupName", "ImageId", "InstanceId", "InstanceType", "cpu", "d1", "d2"}, {"ImageId"}, {"InstanceId", "InstanceType"}, {"d1"}, {}}, StorageResolution: 1, Source: "cpu"},
+			{Namespace: "CWAgent", MetricName: "cpu_usage_guest", Dimensions: [][]string{{"AutoScalingGroupName", "ImageId", "InstanceId", "InstanceType", "cpu", "d1", "d2"}, {"ImageId"}, {"InstanceId", "InstanceType"}, {"d1"}, {}}, StorageResolution: 1, Source: "cpu"},
+			{Namespace: "CWAgent", MetricName: "cpu_usage_nice", Unit: "unit", Dimensions: [][]string{{"AutoScalingGroupName", "ImageId", "InstanceId", "InstanceType", "cpu", "d1", "d2"}, {"ImageId"}, {"InstanceId", "InstanceType"}, {"d1"}, {}}, StorageResolution: 1, Source: "cpu"},
+			{Namespace: "CWAgent", MetricName: "disk_total", Dimensions: [][]string{{"AutoScalingGroupName", "ImageId", "InstanceId", "InstanceType", "d3", "d4", "device", "fstype", "path"}, {"ImageId"}, {"InstanceId", "InstanceType"}, {}}, StorageResolution: 60, Source: "disk"},
+			{Namespace: "CWAgent", MetricName: "disk_used", Dimensions: [][]string{{"AutoScalingGroupName", "ImageId", "InstanceId", "InstanceType", "d3", "d4", "device", "fstype", "path"}, {"ImageId"}, {"InstanceId", "InstanceType"}, {}}, StorageResolution: 60, Source: "disk"},
+			{Namespace: "CWAgent", MetricName: "diskio_io_time", Dimensions: [][]string{{"AutoScalingGroupName", "ImageId", "InstanceId", "InstanceType", "name"}, {"ImageId"}, {"InstanceId", "InstanceType"}, {}}, StorageResolution: 60, Source: "diskio"},
+			{Namespace: "CWAgent", MetricName: "diskio_read_time", Dimensions: [][]string{{"AutoScalingGroupName", "ImageId", "InstanceId", "InstanceType", "name"}, {"ImageId"}, {"InstanceId", "InstanceType"}, {}}, StorageResolution: 60, Source: "diskio"},
+			{Namespace: "CWAgent", MetricName: "diskio_reads", Dimensions: [][]string{{"AutoScalingGroupName", "ImageId", "InstanceId", "InstanceType", "name"}, {"ImageId"}, {"InstanceId", "InstanceType"}, {}}, StorageResolution: 60, Source: "diskio"},
+			{Namespace: "CWAgent", MetricName: "diskio_write_time", Dimensions: [][]string{{"AutoScalingGroupName", "ImageId", "InstanceId", "InstanceType", "name"}, {"ImageId"}, {"InstanceId", "InstanceType"}, {}}, StorageResolution: 60, Source: "diskio"},
+			{Namespace: "CWAgent", MetricName: "diskio_writes", Dimensions: [][]string{{"AutoScalingGroupName", "ImageId", "InstanceId", "InstanceType", "name"}, {"ImageId"}, {"InstanceId", "InstanceType"}, {}}, StorageResolution: 60, Source: "diskio"},
+			{Namespace: "CWAgent", MetricName: "mem_cached", Dimensions: [][]string{{"AutoScalingGroupName", "ImageId", "InstanceId", "InstanceType"}, {"ImageId"}, {"InstanceId", "InstanceType"}, {}}, StorageResolution: 1, Source: "mem"},
+			{Namespace: "CWAgent", MetricName: "mem_total", Dimensions: [][]string{{"AutoScalingGroupName", "ImageId", "InstanceId", "InstanceType"}, {"ImageId"}, {"InstanceId", "InstanceType"}, {}}, StorageResolution: 1, Source: "mem"},
+			{Namespace: "CWAgent", MetricName: "mem_used", Dimensions: [][]string{{"AutoScalingGroupName", "ImageId", "InstanceId", "InstanceType"}, {"ImageId"}, {"InstanceId", "InstanceType"}, {}}, StorageResolution: 1, Source: "mem"},
+			{Namespace: "CWAgent", MetricName: "net_bytes_recv", Dimensions: [][]string{{"AutoScalingGroupName", "ImageId", "InstanceId", "InstanceType", "interface"}, {"ImageId"}, {"InstanceId", "InstanceType"}, {}}, StorageResolution: 1, Source: "net"},
+			{Namespace: "CWAgent", MetricName: "net_bytes_sent", Dimensions: [][]string{{"AutoScalingGroupName", "ImageId", "InstanceId", "InstanceType", "interface"}, {"ImageId"}, {"InstanceId", "InstanceType"}, {}}, StorageResolution: 1, Source: "net"},
+			{Namespace: "CWAgent", MetricName: "net_drop_in", Dimensions: [][]string{{"AutoScalingGroupName", "ImageId", "InstanceId", "InstanceType", "interface"}, {"ImageId"}, {"InstanceId", "InstanceType"}, {}}, StorageResolution: 1, Source: "net"},
+			{Namespace: "CWAgent", MetricName: "net_drop_out", Dimensions: [][]string{{"AutoScalingGroupName", "ImageId", "InstanceId", "InstanceType", "interface"}, {"ImageId"}, {"InstanceId", "InstanceType"}, {}}, StorageResolution: 1, Source: "net"},
+			{Namespace: "CWAgent", MetricName: "netstat_tcp_close", Dimensions: [][]string{{"AutoScalingGroupName", "ImageId", "InstanceId", "InstanceType"}, {"ImageId"}, {"InstanceId", "InstanceType"}, {}}, StorageResolution: 60, Source: "netstat"},
+			{Namespace: "CWAgent", MetricName: "netstat_tcp_established", Dimensions: [][]string{{"AutoScalingGroupName", "ImageId", "InstanceId", "InstanceType"}, {"ImageId"}, {"InstanceId", "InstanceType"}, {}}, StorageResolution: 60, Source: "netstat"},
+			{Namespace: "CWAgent", MetricName: "netstat_tcp_syn_sent", Dimensions: [][]string{{"AutoScalingGroupName", "ImageId", "InstanceId", "InstanceType"}, {"ImageId"}, {"InstanceId", "InstanceType"}, {}}, StorageResolution: 60, Source: "netstat"},
+			{Namespace: "CWAgent", MetricName: "processes_dead", Dimensions: [][]string{{"AutoScalingGroupName", "ImageId", "InstanceId", "InstanceType"}, {"ImageId"}, {"InstanceId", "InstanceType"}, {}}, StorageResolution: 1, Source: "processes"},
+			{Namespace: "CWAgent", MetricName: "processes_running", Dimensions: [][]string{{"AutoScalingGroupName", "ImageId", "InstanceId", "InstanceType"}, {"ImageId"}, {"InstanceId", "InstanceType"}, {}}, StorageResolution: 1, Source: "processes"},
+			{Namespace: "CWAgent", MetricName: "processes_sleeping", Dimensions: [][]string{{"AutoScalingGroupName", "ImageId", "InstanceId", "InstanceType"}, {"ImageId"}, {"InstanceId", "InstanceType"}, {}}, StorageResolution: 1, Source: "processes"},
+			{Namespace: "CWAgent", MetricName: "procstat_cpu_usage", Dimensions: [][]string{{"AutoScalingGroupName", "ImageId", "InstanceId", "InstanceType", "pidfile", "process_name"}, {"ImageId"}, {"InstanceId", "InstanceType"}, {}}, StorageResolution: 1, Source: "procstat"},
+			{Namespace: "CWAgent", MetricName: "procstat_memory_rss", Dimensions: [][]string{{"AutoScalingGroupName", "ImageId", "InstanceId", "InstanceType", "pidfile", "process_name"}, {"ImageId"}, {"InstanceId", "InstanceType"}, {}}, StorageResolution: 1, Source: "procstat"},
+			{Namespace: "CWAgent", MetricName: "swap_free", Dimensions: [][]string{{"AutoScalingGroupName", "ImageId", "InstanceId", "InstanceType"}, {"ImageId"}, {"InstanceId", "InstanceType"}, {}}, StorageResolution: 1, Source: "swap"},
+			{Namespace: "CWAgent", MetricName: "swap_used", Dimensions: [][]string{{"AutoScalingGroupName", "ImageId", "InstanceId", "InstanceType"}, {"ImageId"}, {"InstanceId", "InstanceType"}, {}}, StorageResolution: 1, Source: "swap"},
+			{Namespace: "CWAgent", MetricName: "swap_used_percent", Dimensions: [][]string{{"AutoScalingGroupName", "ImageId", "InstanceId", "InstanceType"}, {"ImageId"}, {"InstanceId", "InstanceType"}, {}}, StorageResolution: 1, Source: "swap"},
+		},
+		DynamicSources: []DynamicSource{
+			{Namespace: "CWAgent", Source: "collectd_listener", Dimensions: [][]string{{"AutoScalingGroupName", "ImageId", "InstanceId", "InstanceType"}, {"ImageId"}, {"InstanceId", "InstanceType"}, {}}},
+			{Namespace: "CWAgent", Source: "statsd", Dimensions: [][]string{{"AutoScalingGroupName", "ImageId", "InstanceId", "InstanceType"}, {"ImageId"}, {"InstanceId", "InstanceType"}, {}}},
+		},
+	},
+	"complete_windows_config.conf": {
+		Metrics: []Metric{
+			{Namespace: "CWAgent", MetricName: "CPU_IDLE", Unit: "PERCENT", Dimensions: [][]string{{"AutoScalingGroupName", "ImageId", "InstanceId", "InstanceType", "d1", "d2", "instance", "objectname"}, {"ImageId"}, {"InstanceId", "InstanceType"}, {"d1"}, {}}, StorageResolution: 60, Source: "win_perf_counters"},
+			{Namespace: "CWAgent", MetricName: "DISK_READ", Dimensions: [][]string{{"AutoScalingGroupName", "ImageId", "InstanceId", "InstanceType", "instance", "objectname"}, {"ImageId"}, {"InstanceId", "InstanceType"}, {}}, StorageResolution: 60, Source: "win_perf_counters"},
+			{Namespace: "CWAgent", MetricName: "LogicalDisk % Disk Write Time", Dimensions: [][]string{{"AutoScalingGroupName", "ImageId", "InstanceId", "InstanceType", "instance", "objectname"}, {"ImageId"}, {"InstanceId", "InstanceType"}, {}}, StorageResolution: 60, Source: "win_perf_counters"},
+			{Namespace: "CWAgent", MetricName: "LogicalDisk % Idle Time", Unit: "PERCENT", Dimensions: [][]string{{"AutoScalingGroupName", "ImageId", "InstanceId", "InstanceType", "instance", "objectname"}, {"ImageId"}, {"InstanceId", "InstanceType"}, {}}, StorageResolution: 60, Source: "win_perf_counters"},
+			{Namespace: "CWAgent", MetricName: "LogicalDisk % User Time", Dimensions: [][]string{{"AutoScalingGroupName", "ImageId", "InstanceId", "InstanceType", "instance", "objectname"}, {"ImageId"}, {"InstanceId", "InstanceType"}, {}}, StorageResolution: 60, Source: "win_perf_counters"},
+			{Namespace: "CWAgent", MetricName: "Memory Available Bytes", Dimensions: [][]string{{"AutoScalingGroupName", "ImageId", "InstanceId", "InstanceType", "d3", "objectname"}, {"ImageId"}, {"InstanceId", "InstanceType"}, {}}, StorageResolution: 1, Source: "win_perf_counters"},
+			{Namespace: "CWAgent", MetricName: "Memory Cache Faults/sec", Dimensions: [][]string{{"AutoScalingGroupName", "ImageId", "InstanceId", "InstanceType", "d3", "objectname"}, {"ImageId"}, {"InstanceId", "InstanceType"}, {}}, StorageResolution: 1, Source: "win_perf_counters"},
+			{Namespace: "CWAgent", MetricName: "Memory Page Faults/sec", Dimensions: [][]string{{"AutoScalingGroupName", "ImageId", "InstanceId", "InstanceType", "d3", "objectname"}, {"ImageId"}, {"InstanceId", "InstanceType"}, {}}, StorageResolution: 1, Source: "win_perf_counters"},
+			{Namespace: "CWAgent", MetricName: "Memory Pages/sec", Dimensions: [][]string{{"AutoScalingGroupName", "ImageId", "InstanceId", "InstanceType", "d3", "objectname"}, {"ImageId"}, {"InstanceId", "InstanceType"}, {}}, StorageResolution: 1, Source: "win_perf_counters"},
+			{Namespace: "CWAgent", MetricName: "Network Interface Bytes Received/sec", Dimensions: [][]string{{"AutoScalingGroupName", "ImageId", "InstanceId", "InstanceType", "d3", "instance", "objectname"}, {"ImageId"}, {"InstanceId", "InstanceType"}, {}}, StorageResolution: 1, Source: "win_perf_counters"},
+			{Namespace: "CWAgent", MetricName: "Network Interface Bytes Sent/sec", Dimensions: [][]string{{"AutoScalingGroupName", "ImageId", "InstanceId", "InstanceType", "d3", "instance", "objectname"}, {"ImageId"}, {"InstanceId", "InstanceType"}, {}}, StorageResolution: 1, Source: "win_perf_counters"},
+			{Namespace: "CWAgent", MetricName: "Network Interface Packets Received/sec", Dimensions: [][]string{{"AutoScalingGroupName", "ImageId", "InstanceId", "InstanceType", "d3", "instance", "objectname"}, {"ImageId"}, {"InstanceId", "InstanceType"}, {}}, StorageResolution: 1, Source: "win_perf_counters"},
+			{Namespace: "CWAgent", MetricName: "Network Interface Packets Sent/sec", Dimensions: [][]string{{"AutoScalingGroupName", "ImageId", "InstanceId", "InstanceType", "d3", "instance", "objectname"}, {"ImageId"}, {"InstanceId", "InstanceType"}, {}}, StorageResolution: 1, Source: "win_perf_counters"},
+			{Namespace: "CWAgent", MetricName: "Processor % Interrupt Time", Dimensions: [][]string{{"AutoScalingGroupName", "ImageId", "InstanceId", "InstanceType", "d1", "d2", "instance", "objectname"}, {"ImageId"}, {"InstanceId", "InstanceType"}, {"d1"}, {}}, StorageResolution: 60, Source: "win_perf_counters"},
+			{Namespace: "CWAgent", MetricName: "Processor % Processor Time", Dimensions: [][]string{{"AutoScalingGroupName", "ImageId", "InstanceId", "InstanceType", "d1", "d2", "instance", "objectname"}, {"ImageId"}, {"InstanceId", "InstanceType"}, {"d1"}, {}}, StorageResolution: 60, Source: "win_perf_counters"},
+			{Namespace: "CWAgent", MetricName: "Processor % User Time", Dimensions: [][]string{{"AutoScalingGroupName", "ImageId", "InstanceId", "InstanceType", "d1", "d2", "instance", "objectname"}, {"ImageId"}, {"InstanceId", "InstanceType"}, {"d1"}, {}}, StorageResolution: 60, Source: "win_perf_counters"},
+			{Namespace: "CWAgent", MetricName: "System Context Switches/sec", Dimensions: [][]string{{"AutoScalingGroupName", "ImageId", "InstanceId", "InstanceType", "d1", "d2", "objectname"}, {"ImageId"}, {"InstanceId", "InstanceType"}, {"d1"}, {}}, StorageResolution: 60, Source: "win_perf_counters"},
+			{Namespace: "CWAgent", MetricName: "System Processor Queue Length", Dimensions: [][]string{{"AutoScalingGroupName", "ImageId", "InstanceId", "InstanceType", "d1", "d2", "objectname"}, {"ImageId"}, {"InstanceId", "InstanceType"}, {"d1"}, {}}, StorageResolution: 60, Source: "win_perf_counters"},
+			{Namespace: "CWAgent", MetricName: "System System Calls/sec", Dimensions: [][]string{{"AutoScalingGroupName", "ImageId", "InstanceId", "InstanceType", "d1", "d2", "objectname"}, {"ImageId"}, {"InstanceId", "InstanceType"}, {"d1"}, {}}, StorageResolution: 60, Source: "win_perf_counters"},
+			{Namespace: "CWAgent", MetricName: "customizedObjectName customizedCounter1", Dimensions: [][]string{{"AutoScalingGroupName", "ImageId", "InstanceId", "InstanceType", "instance", "objectname"}, {"ImageId"}, {"InstanceId", "InstanceType"}, {}}, StorageResolution: 60, Source: "win_perf_counters"},
+			{Namespace: "CWAgent", MetricName: "procstat cpu_time_system", Dimensions: [][]string{{"AutoScalingGroupName", "ImageId", "InstanceId", "InstanceType", "exe", "process_name"}, {"ImageId"}, {"InstanceId", "InstanceType"}, {}}, StorageResolution: 60, Source: "procstat"},
+			{Namespace: "CWAgent", MetricName: "procstat cpu_time_user", Dimensions: [][]string{{"AutoScalingGroupName", "ImageId", "InstanceId", "InstanceType", "exe", "process_name"}, {"ImageId"}, {"InstanceId", "InstanceType"}, {}}, StorageResolution: 60, Source: "procstat"},
+		},
+		DynamicSources: []DynamicSource{
+			{Namespace: "CWAgent", Source: "statsd", Dimensions: [][]string{{"AutoScalingGroupName", "ImageId", "InstanceId", "InstanceType"}, {"ImageId"}, {"InstanceId", "InstanceType"}, {}}},
+		},
+	},
+	"containerd_config_linux.conf": {
+		Metrics: []Metric{
+			{Namespace: "CWAgent", MetricName: "containerd_cpu_usage_percent", Dimensions: [][]string{{"InstanceId", "container_image", "container_name"}}, StorageResolution: 1, Source: "containerd"},
+			{Namespace: "CWAgent", MetricName: "containerd_mem_rss", Dimensions: [][]string{{"InstanceId", "container_image", "container_name"}}, StorageResolution: 1, Source: "containerd"},
+			{Namespace: "CWAgent", MetricName: "containerd_mem_working_set", Dimensions: [][]string{{"InstanceId", "container_image", "container_name"}}, StorageResolution: 1, Source: "containerd"},
+			{Namespace: "CWAgent", MetricName: "containerd_writable_layer_used_bytes", Dimensions: [][]string{{"InstanceId", "container_image", "container_name"}}, StorageResolution: 1, Source: "containerd"},
+		},
+	},
+	"cpu_aggregation_config_linux.conf": {
+		Metrics: []Metric{
+			{Namespace: "CWAgent", MetricName: "cpu_usage_idle", Dimensions: [][]string{{"host", "cpu"}}, StorageResolution: 1, Source: "cpu"},
+			{Namespace: "CWAgent", MetricName: "cpu_usage_user", Dimensions: [][]string{{"host", "cpu"}}, StorageResolution: 1, Source: "cpu"},
+		},
+	},
+	"cpu_cluster_config_linux.conf": {
+		Metrics: []Metric{
+			{Namespace: "CWAgent", MetricName: "cpu_cluster_frequency_mhz", Dimensions: [][]string{{"InstanceId", "cluster"}}, StorageResolution: 60, Source: "cpu_cluster"},
+			{Namespace: "CWAgent", MetricName: "cpu_cluster_instructions_per_cycle", Dimensions: [][]string{{"InstanceId", "cluster"}}, StorageResolution: 60, Source: "cpu_cluster"},
+			{Namespace: "CWAgent", MetricName: "cpu_cluster_max_frequency_mhz", Dimensions: [][]string{{"InstanceId", "cluster"}}, StorageResolution: 60, Source: "cpu_cluster"},
+		},
+	},
+	"csm_only_config_linux.conf":         {},
+	"csm_only_config_windows.conf":       {},
+	"csm_service_addresses_linux.conf":   {},
+	"csm_service_addresses_windows.conf": {},
+	"deadband_config_linux.conf": {
+		Metrics: []Metric{
+			{Namespace: "CWAgent", MetricName: "disk_total", Dimensions: [][]string{{"host", "device", "fstype", "path"}}, StorageResolution: 60, Source: "disk"},
+			{Namespace: "CWAgent", MetricName: "disk_used_percent", Dimensions: [][]string{{"host", "device", "fstype", "path"}}, StorageResolution: 60, Source: "disk"},
+		},
+	},
+	"delta_config_linux.conf": {
+		Metrics: []Metric{
+			{Namespace: "CWAgent", MetricName: "DRIVER_DISKIO_IOPS_IN_PROGRESS", Unit: "Count", Dimensions: [][]string{{"AutoScalingGroupName", "ImageId", "InstanceId", "InstanceType", "name"}}, StorageResolution: 60, Source: "diskio"},
+			{Namespace: "CWAgent", MetricName: "DRIVER_DISKIO_READ_TIME", Unit: "Milliseconds", Dimensions: [][]string{{"AutoScalingGroupName", "ImageId", "InstanceId", "InstanceType", "name"}}, StorageResolution: 60, Source: "diskio"},
+			{Namespace: "CWAgent", MetricName: "DRIVER_DISKIO_WRITE_TIME", Unit: "Milliseconds", Dimensions: [][]string{{"AutoScalingGroupName", "ImageId", "InstanceId", "InstanceType", "name"}}, StorageResolution: 60, Source: "diskio"},
+		},
+	},
+	"dimension_lookups_config_linux.conf": {
+		Metrics: []Metric{
+			{Namespace: "CWAgent", MetricName: "disk_used_percent", Dimensions: [][]string{{"InstanceId", "device", "fstype", "path"}}, StorageResolution: 60, Source: "disk"},
+		},
+	},
+	"dimension_normalization_config_linux.conf": {
+		Metrics: []Metric{
+			{Namespace: "CWAgent", MetricName: "cpu_usage_idle", Dimensions: [][]string{{"host", "cpu"}}, StorageResolution: 60, Source: "cpu"},
+		},
+	},
+	"dimension_rules_config_linux.conf": {
+		Metrics: []Metric{
+			{Namespace: "CWAgent", MetricName: "cpu_usage_idle", Dimensions: [][]string{{}}, StorageResolution: 60, Source: "cpu"},
+		},
+	},
+	"diskio_latency_config_linux.conf": {
+		Metrics: []Metric{
+			{Namespace: "CWAgent", MetricName: "diskio_read_latency", Dimensions: [][]string{{"InstanceId", "name"}}, StorageResolution: 60, Source: "diskio_latency"},
+			{Namespace: "CWAgent", MetricName: "diskio_reads", Dimensions: [][]string{{"InstanceId", "name"}}, StorageResolution: 60, Source: "diskio"},
+			{Namespace: "CWAgent", MetricName: "diskio_write_latency", Dimensions: [][]string{{"InstanceId", "name"}}, StorageResolution: 60, Source: "diskio_latency"},
+			{Namespace: "CWAgent", MetricName: "diskio_writes", Dimensions: [][]string{{"InstanceId", "name"}}, StorageResolution: 60, Source: "diskio"},
+		},
+	},
+	"docker_config_linux.conf": {
+		Metrics: []Metric{
+			{Namespace: "CWAgent", MetricName: "docker_blkio_io_service_bytes_read", Dimensions: [][]string{{"InstanceId", "container_image", "container_name"}}, StorageResolution: 1, Source: "docker"},
+			{Namespace: "CWAgent", MetricName: "docker_blkio_io_service_bytes_write", Dimensions: [][]string{{"InstanceId", "container_image", "container_name"}}, StorageResolution: 1, Source: "docker"},
+			{Namespace: "CWAgent", MetricName: "docker_cpu_usage_percent", Dimensions: [][]string{{"InstanceId", "container_image", "container_name"}}, StorageResolution: 1, Source: "docker"},
+			{Namespace: "CWAgent", MetricName: "docker_mem_usage_percent", Dimensions: [][]string{{"InstanceId", "container_image", "container_name"}}, StorageResolution: 1, Source: "docker"},
+			{Namespace: "CWAgent", MetricName: "docker_net_rx_bytes", Dimensions: [][]string{{"InstanceId", "container_image", "container_name"}}, StorageResolution: 1, Source: "docker"},
+			{Namespace: "CWAgent", MetricName: "docker_net_tx_bytes", Dimensions: [][]string{{"InstanceId", "container_image", "container_name"}}, StorageResolution: 1, Source: "docker"},
+		},
+	},
+	"downsampling_config_linux.conf": {
+		Metrics: []Metric{
+			{Namespace: "CWAgent", MetricName: "cpu_usage_idle", Dimensions: [][]string{{"host", "cpu"}}, StorageResolution: 1, Source: "cpu"},
+		},
+	},
+	"drop_origin_linux.conf": {
+		Metrics: []Metric{
+			{Namespace: "CWAgent", MetricName: "CPU_USAGE_IDLE", Unit: "unit", Dimensions: [][]string{{"AutoScalingGroupName", "ImageId", "InstanceId", "InstanceType", "cpu", "d1", "d2"}}, StorageResolution: 1, Source: "cpu"},
+			{Namespace: "CWAgent", MetricName: "cpu_usage_active", Dimensions: [][]string{{"AutoScalingGroupName", "ImageId", "InstanceId", "InstanceType", "cpu", "d1", "d2"}}, StorageResolution: 1, Source: "cpu"},
+			{Namespace: "CWAgent", MetricName: "cpu_usage_guest", Dimensions: [][]string{{"AutoScalingGroupName", "ImageId", "InstanceId", "InstanceType", "cpu", "d1", "d2"}}, StorageResolution: 1, Source: "cpu"},
+			{Namespace: "CWAgent", MetricName: "cpu_usage_nice", Unit: "unit", Dimensions: [][]string{{"AutoScalingGroupName", "ImageId", "InstanceId", "InstanceType", "cpu", "d1", "d2"}}, StorageResolution: 1, Source: "cpu"},
+			{Namespace: "CWAgent", MetricName: "disk_free", Dimensions: [][]string{{"AutoScalingGroupName", "ImageId", "InstanceId", "InstanceType", "d3", "d4", "device", "fstype", "path"}}, StorageResolution: 60, Source: "disk"},
+			{Namespace: "CWAgent", MetricName: "disk_total", Dimensions: [][]string{{"AutoScalingGroupName", "ImageId", "InstanceId", "InstanceType", "d3", "d4", "device", "fstype", "path"}}, StorageResolution: 60, Source: "disk"},
+			{Namespace: "CWAgent", MetricName: "disk_used", Dimensions: [][]string{{"AutoScalingGroupName", "ImageId", "InstanceId", "InstanceType", "d3", "d4", "device", "fstype", "path"}}, StorageResolution: 60, Source: "disk"},
+			{Namespace: "CWAgent", MetricName: "nvidia_smi_power_draw", Dimensions: [][]string{{"AutoScalingGroupName", "ImageId", "InstanceId", "InstanceType", "index", "name"}}, StorageResolution: 60, Source: "nvidia_smi"},
+			{Namespace: "CWAgent", MetricName: "nvidia_smi_utilization_memory", Dimensions: [][]string{{"AutoScalingGroupName", "ImageId", "InstanceId", "InstanceType", "index", "name"}}, StorageResolution: 60, Source: "nvidia_smi"},
+		},
+	},
+	"emf_listener_config_linux.conf": {},
+	"emf_output_config_linux.conf": {
+		Metrics: []Metric{
+			{Namespace: "CWAgent", MetricName: "mem_used_percent", Dimensions: [][]string{{"host"}}, StorageResolution: 60, Source: "mem", LogGroupName: "/aws/cwagent/metrics"},
+		},
+	},
+	"fd_config_linux.conf": {
+		Metrics: []Metric{
+			{Namespace: "CWAgent", MetricName: "fd_allocated", Dimensions: [][]string{{"InstanceId"}}, StorageResolution: 60, Source: "fd"},
+			{Namespace: "CWAgent", MetricName: "fd_process_open", Dimensions: [][]string{{"InstanceId", "process_name"}}, StorageResolution: 60, Source: "fd"},
+			{Namespace: "CWAgent", MetricName: "fd_process_utilization", Dimensions: [][]string{{"InstanceId", "process_name"}}, StorageResolution: 60, Source: "fd"},
+			{Namespace: "CWAgent", MetricName: "fd_utilization", Dimensions: [][]string{{"InstanceId"}}, StorageResolution: 60, Source: "fd"},
+		},
+	},
+	"haproxy_config_linux.conf": {
+		Metrics: []Metric{
+			{Namespace: "CWAgent", MetricName: "haproxy_eresp_rate", Dimensions: [][]string{{"InstanceId", "proxy", "server", "type"}}, StorageResolution: 60, Source: "haproxy"},
+			{Namespace: "CWAgent", MetricName: "haproxy_hrsp_5xx_rate", Dimensions: [][]string{{"InstanceId", "proxy", "server", "type"}}, StorageResolution: 60, Source: "haproxy"},
+			{Namespace: "CWAgent", MetricName: "haproxy_qcur", Dimensions: [][]string{{"InstanceId", "proxy", "server", "type"}}, StorageResolution: 60, Source: "haproxy"},
+			{Namespace: "CWAgent", MetricName: "haproxy_scur", Dimensions: [][]string{{"InstanceId", "proxy", "server", "type"}}, StorageResolution: 60, Source: "haproxy"},
+			{Namespace: "CWAgent", MetricName: "haproxy_up", Dimensions: [][]string{{"InstanceId", "proxy", "server", "type"}}, StorageResolution: 60, Source: "haproxy"},
+		},
+	},
+	"health_hook_config_linux.conf": {
+		Metrics: []Metric{
+			{Namespace: "CWAgent", MetricName: "mem_used_percent", Dimensions: [][]string{{"host"}}, StorageResolution: 60, Source: "mem"},
+		},
+	},
+	"instance_normalization_config_windows.conf": {
+		Metrics: []Metric{
+			{Namespace: "CWAgent", MetricName: "Memory % Committed Bytes In Use", Dimensions: [][]string{{"host", "objectname"}}, StorageResolution: 60, Source: "win_perf_counters"},
+			{Namespace: "CWAgent", MetricName: "PhysicalDisk % Disk Time", Dimensions: [][]string{{"host", "instance", "objectname"}}, StorageResolution: 60, Source: "win_perf_counters"},
+			{Namespace: "CWAgent", MetricName: "Process % Processor Time", Dimensions: [][]string{{"host", "instance", "objectname"}}, StorageResolution: 60, Source: "win_perf_counters"},
+			{Namespace: "CWAgent", MetricName: "W3SVC_W3WP Requests / Sec", Dimensions: [][]string{{"host", "instance", "objectname"}}, StorageResolution: 60, Source: "win_perf_counters"},
+		},
+	},
+	"jmx_config_linux.conf": {
+		DynamicSources: []DynamicSource{
+			{Namespace: "CWAgent", Source: "jolokia2_agent", Dimensions: [][]string{{"host", "App"}}},
+			{Namespace: "CWAgent", Source: "jolokia2_agent", Dimensions: [][]string{{"host"}}},
+		},
+	},
+	"kafka_config_linux.conf": {
+		Metrics: []Metric{
+			{Namespace: "CWAgent", MetricName: "kafka_cluster_offline_partitions", Dimensions: [][]string{{"InstanceId"}}, StorageResolution: 1, Source: "kafka"},
+			{Namespace: "CWAgent", MetricName: "kafka_consumer_lag", Dimensions: [][]string{{"InstanceId", "group", "topic"}}, StorageResolution: 1, Source: "kafka"},
+			{Namespace: "CWAgent", MetricName: "kafka_consumer_partition_lag", Dimensions: [][]string{{"InstanceId", "group", "partition", "topic"}}, StorageResolution: 1, Source: "kafka"},
+			{Namespace: "CWAgent", MetricName: "kafka_topic_under_replicated_partitions", Dimensions: [][]string{{"InstanceId", "topic"}}, StorageResolution: 1, Source: "kafka"},
+		},
+	},
+	"kernel_config_linux.conf": {
+		Metrics: []Metric{
+			{Namespace: "CWAgent", MetricName: "kernel_arp_utilization", Dimensions: [][]string{{"InstanceId"}}, StorageResolution: 60, Source: "kernel"},
+			{Namespace: "CWAgent", MetricName: "kernel_conntrack_drop", Dimensions: [][]string{{"InstanceId"}}, StorageResolution: 60, Source: "kernel"},
+			{Namespace: "CWAgent", MetricName: "kernel_conntrack_utilization", Dimensions: [][]string{{"InstanceId"}}, StorageResolution: 60, Source: "kernel"},
+			{Namespace: "CWAgent", MetricName: "kernel_context_switches_per_sec", Dimensions: [][]string{{"InstanceId"}}, StorageResolution: 60, Source: "kernel"},
+			{Namespace: "CWAgent", MetricName: "kernel_entropy_avail", Dimensions: [][]string{{"InstanceId"}}, StorageResolution: 60, Source: "kernel"},
+			{Namespace: "CWAgent", MetricName: "kernel_interrupts_per_sec", Dimensions: [][]string{{"InstanceId"}}, StorageResolution: 60, Source: "kernel"},
+			{Namespace: "CWAgent", MetricName: "kernel_interrupts_per_sec", Dimensions: [][]string{{"InstanceId", "device", "irq"}}, StorageResolution: 60, Source: "kernel"},
+			{Namespace: "CWAgent", MetricName: "kernel_tcp_mem_utilization", Dimensions: [][]string{{"InstanceId"}}, StorageResolution: 60, Source: "kernel"},
+			{Namespace: "CWAgent", MetricName: "kernel_tcp_memory_pressures", Dimensions: [][]string{{"InstanceId"}}, StorageResolution: 60, Source: "kernel"},
+		},
+	},
+	"log_ecs_metric_only.conf":             {},
+	"log_filter.conf":                      {},
+	"log_metric_and_log.conf":              {},
+	"log_metric_only.conf":                 {},
+	"log_only_config_windows.conf":         {},
+	"log_stream_buckets_config_linux.conf": {},
+	"log_w3c_config_windows.conf":          {},
+	"measurement_wildcard_config_linux.conf": {
+		DynamicSources: []DynamicSource{
+			{Namespace: "CWAgent", Source: "cpu", Dimensions: [][]string{{"host", "cpu"}}},
+			{Namespace: "CWAgent", Source: "diskio", Dimensions: [][]string{{"host", "name"}}},
+			{Namespace: "CWAgent", Source: "mem", Dimensions: [][]string{{"host"}}},
+		},
+	},
+	"metric_rename_config_linux.conf": {
+		Metrics: []Metric{
+			{Namespace: "Memory", MetricName: "Memory.used_percent", Dimensions: [][]string{{"host"}}, StorageResolution: 60, Source: "mem"},
+		},
+	},
+	"metric_transforms_config_linux.conf": {
+		Metrics: []Metric{
+			{Namespace: "CWAgent", MetricName: "disk_total", Dimensions: [][]string{{"host", "device", "fstype", "path"}}, StorageResolution: 60, Source: "disk"},
+			{Namespace: "CWAgent", MetricName: "disk_used", Dimensions: [][]string{{"host", "device", "fstype", "path"}}, StorageResolution: 60, Source: "disk"},
+		},
+	},
+	"namespace_routing_config_linux.conf": {
+		Metrics: []Metric{
+			{Namespace: "Teams/search", MetricName: "cpu_usage_idle", Dimensions: [][]string{{"host", "cpu"}}, StorageResolution: 1, Source: "cpu"},
+		},
+	},
+	"net_rates_config_linux.conf": {
+		Metrics: []Metric{
+			{Namespace: "CWAgent", MetricName: "net_bytes_recv_per_sec", Dimensions: [][]string{{"host", "interface"}}, StorageResolution: 60, Source: "net"},
+			{Namespace: "CWAgent", MetricName: "net_bytes_sent_per_sec", Dimensions: [][]string{{"host", "interface"}}, StorageResolution: 60, Source: "net"},
+		},
+	},
+	"netstat_ports_config_linux.conf": {
+		Metrics: []Metric{
+			{Namespace: "CWAgent", MetricName: "netstat_tcp_established", Dimensions: [][]string{{"InstanceId"}}, StorageResolution: 60, Source: "netstat"},
+			{Namespace: "CWAgent", MetricName: "netstat_tcp_established", Dimensions: [][]string{{"InstanceId", "port", "process_name"}}, StorageResolution: 60, Source: "netstat_ports"},
+			{Namespace: "CWAgent", MetricName: "netstat_tcp_time_wait", Dimensions: [][]string{{"InstanceId"}}, StorageResolution: 60, Source: "netstat"},
+			{Namespace: "CWAgent", MetricName: "netstat_tcp_time_wait", Dimensions: [][]string{{"InstanceId", "port", "process_name"}}, StorageResolution: 60, Source: "netstat_ports"},
+		},
+	},
+	"node_exporter_config_linux.conf": {
+		Metrics: []Metric{
+			{Namespace: "CWAgent", MetricName: "cpu_usage_idle", Dimensions: [][]string{{"InstanceId", "cpu"}}, StorageResolution: 60, Source: "node_exporter"},
+			{Namespace: "CWAgent", MetricName: "cpu_usage_iowait", Dimensions: [][]string{{"InstanceId", "cpu"}}, StorageResolution: 60, Source: "node_exporter"},
+			{Namespace: "CWAgent", MetricName: "disk_used_percent", Dimensions: [][]string{{"InstanceId", "device", "fstype", "path"}}, StorageResolution: 60, Source: "node_exporter"},
+			{Namespace: "CWAgent", MetricName: "diskio_reads", Dimensions: [][]string{{"InstanceId", "name"}}, StorageResolution: 60, Source: "node_exporter"},
+			{Namespace: "CWAgent", MetricName: "mem_used_percent", Dimensions: [][]string{{"InstanceId"}}, StorageResolution: 60, Source: "node_exporter"},
+			{Namespace: "CWAgent", MetricName: "net_bytes_recv", Dimensions: [][]string{{"InstanceId", "interface"}}, StorageResolution: 60, Source: "node_exporter"},
+		},
+	},
+	"numa_config_linux.conf": {
+		Metrics: []Metric{
+			{Namespace: "CWAgent", MetricName: "mem_numa_free", Dimensions: [][]string{{"InstanceId", "numa_node"}}, StorageResolution: 60, Source: "numa"},
+			{Namespace: "CWAgent", MetricName: "mem_numa_hit", Dimensions: [][]string{{"InstanceId", "numa_node"}}, StorageResolution: 60, Source: "numa"},
+			{Namespace: "CWAgent", MetricName: "mem_numa_miss", Dimensions: [][]string{{"InstanceId", "numa_node"}}, StorageResolution: 60, Source: "numa"},
+			{Namespace: "CWAgent", MetricName: "mem_numa_used_percent", Dimensions: [][]string{{"InstanceId", "numa_node"}}, StorageResolution: 60, Source: "numa"},
+			{Namespace: "CWAgent", MetricName: "mem_used_percent", Dimensions: [][]string{{"InstanceId"}}, StorageResolution: 60, Source: "mem"},
+		},
+	},
+	"parquet_export_config_linux.conf": {
+		Metrics: []Metric{
+			{Namespace: "MyApp", MetricName: "mem_used_percent", Dimensions: [][]string{{"host"}}, StorageResolution: 60, Source: "mem"},
+		},
+	},
+	"procstat_recovery_config_linux.conf": {
+		Metrics: []Metric{
+			{Namespace: "CWAgent", MetricName: "procstat_cpu_usage", Dimensions: [][]string{{"host", "exe", "process_name"}}, StorageResolution: 60, Source: "procstat"},
+			{Namespace: "CWAgent", MetricName: "procstat_cpu_usage", Dimensions: [][]string{{"host", "pattern", "process_name"}}, StorageResolution: 60, Source: "procstat"},
+			{Namespace: "CWAgent", MetricName: "procstat_lookup_pid_count", Dimensions: [][]string{{"host", "pattern", "pid_finder"}}, StorageResolution: 60, Source: "procstat"},
+			{Namespace: "CWAgent", MetricName: "procstat_memory_rss", Dimensions: [][]string{{"host", "exe", "process_name"}}, StorageResolution: 60, Source: "procstat"},
+		},
+	},
+	"prometheus_config_linux.conf":        {},
+	"prometheus_config_windows.conf":      {},
+	"prometheus_ec2_sd_config_linux.conf": {},
+	"rabbitmq_config_linux.conf": {
+		Metrics: []Metric{
+			{Namespace: "CWAgent", MetricName: "rabbitmq_node_disk_free_alarm", Dimensions: [][]string{{"InstanceId", "node"}}, StorageResolution: 60, Source: "rabbitmq"},
+			{Namespace: "CWAgent", MetricName: "rabbitmq_node_mem_alarm", Dimensions: [][]string{{"InstanceId", "node"}}, StorageResolution: 60, Source: "rabbitmq"},
+			{Namespace: "CWAgent", MetricName: "rabbitmq_queue_consumers", Dimensions: [][]string{{"InstanceId", "queue", "vhost"}}, StorageResolution: 60, Source: "rabbitmq"},
+			{Namespace: "CWAgent", MetricName: "rabbitmq_queue_messages_ready", Dimensions: [][]string{{"InstanceId", "queue", "vhost"}}, StorageResolution: 60, Source: "rabbitmq"},
+			{Namespace: "CWAgent", MetricName: "rabbitmq_queue_publish_rate", Dimensions: [][]string{{"InstanceId", "queue", "vhost"}}, StorageResolution: 60, Source: "rabbitmq"},
+		},
+	},
+	"retries_config_linux.conf": {
+		Metrics: []Metric{
+			{Namespace: "CWAgent", MetricName: "mem_used_percent", Dimensions: [][]string{{"host"}}, StorageResolution: 60, Source: "mem"},
+		},
+	},
+	"sensors_config_linux.conf": {
+		Metrics: []Metric{
+			{Namespace: "CWAgent", MetricName: "sensors_fan_input", Dimensions: [][]string{{"InstanceId", "chip", "label"}}, StorageResolution: 60, Source: "sensors"},
+			{Namespace: "CWAgent", MetricName: "sensors_temp_input", Dimensions: [][]string{{"InstanceId", "chip", "label"}}, StorageResolution: 60, Source: "sensors"},
+		},
+	},
+	"sensors_config_windows.conf": {
+		Metrics: []Metric{
+			{Namespace: "CWAgent", MetricName: "sensors temp_input", Dimensions: [][]string{{"InstanceId", "chip", "label"}}, StorageResolution: 60, Source: "sensors"},
+		},
+	},
+	"standard_config_linux.conf": {
+		Metrics: []Metric{
+			{Namespace: "CWAgent", MetricName: "cpu_usage_idle", Dimensions: [][]string{{"AutoScalingGroupName", "ImageId", "InstanceId", "InstanceType", "cpu"}}, StorageResolution: 60, Source: "cpu"},
+			{Namespace: "CWAgent", MetricName: "cpu_usage_iowait", Dimensions: [][]string{{"AutoScalingGroupName", "ImageId", "InstanceId", "InstanceType", "cpu"}}, StorageResolution: 60, Source: "cpu"},
+			{Namespace: "CWAgent", MetricName: "cpu_usage_system", Dimensions: [][]string{{"AutoScalingGroupName", "ImageId", "InstanceId", "InstanceType", "cpu"}}, StorageResolution: 60, Source: "cpu"},
+			{Namespace: "CWAgent", MetricName: "cpu_usage_user", Dimensions: [][]string{{"AutoScalingGroupName", "ImageId", "InstanceId", "InstanceType", "cpu"}}, StorageResolution: 60, Source: "cpu"},
+			{Namespace: "CWAgent", MetricName: "disk_inodes_free", Dimensions: [][]string{{"AutoScalingGroupName", "ImageId", "InstanceId", "InstanceType", "device", "fstype", "path"}}, StorageResolution: 60, Source: "disk"},
+			{Namespace: "CWAgent", MetricName: "disk_used_percent", Dimensions: [][]string{{"AutoScalingGroupName", "ImageId", "InstanceId", "InstanceType", "device", "fstype", "path"}}, StorageResolution: 60, Source: "disk"},
+			{Namespace: "CWAgent", MetricName: "diskio_io_time", Dimensions: [][]string{{"AutoScalingGroupName", "ImageId", "InstanceId", "InstanceType", "name"}}, StorageResolution: 60, Source: "diskio"},
+			{Namespace: "CWAgent", MetricName: "mem_used_percent", Dimensions: [][]string{{"AutoScalingGroupName", "ImageId", "InstanceId", "InstanceType"}}, StorageResolution: 60, Source: "mem"},
+			{Namespace: "CWAgent", MetricName: "swap_used_percent", Dimensions: [][]string{{"AutoScalingGroupName", "ImageId", "InstanceId", "InstanceType"}}, StorageResolution: 60, Source: "swap"},
+		},
+	},
+	"standard_config_linux_with_common_config.conf": {
+		Metrics: []Metric{
+			{Namespace: "CWAgent", MetricName: "cpu_usage_idle", Dimensions: [][]string{{"AutoScalingGroupName", "ImageId", "InstanceId", "InstanceType", "cpu"}}, StorageResolution: 60, Source: "cpu"},
+			{Namespace: "CWAgent", MetricName: "cpu_usage_iowait", Dimensions: [][]string{{"AutoScalingGroupName", "ImageId", "InstanceId", "InstanceType", "cpu"}}, StorageResolution: 60, Source: "cpu"},
+			{Namespace: "CWAgent", MetricName: "cpu_usage_system", Dimensions: [][]string{{"AutoScalingGroupName", "ImageId", "InstanceId", "InstanceType", "cpu"}}, StorageResolution: 60, Source: "cpu"},
+			{Namespace: "CWAgent", MetricName: "cpu_usage_user", Dimensions: [][]string{{"AutoScalingGroupName", "ImageId", "InstanceId", "InstanceType", "cpu"}}, StorageResolution: 60, Source: "cpu"},
+			{Namespace: "CWAgent", MetricName: "disk_inodes_free", Dimensions: [][]string{{"AutoScalingGroupName", "ImageId", "InstanceId", "InstanceType", "device", "fstype", "path"}}, StorageResolution: 60, Source: "disk"},
+			{Namespace: "CWAgent", MetricName: "disk_used_percent", Dimensions: [][]string{{"AutoScalingGroupName", "ImageId", "InstanceId", "InstanceType", "device", "fstype", "path"}}, StorageResolution: 60, Source: "disk"},
+			{Namespace: "CWAgent", MetricName: "diskio_io_time", Dimensions: [][]string{{"AutoScalingGroupName", "ImageId", "InstanceId", "InstanceType", "name"}}, StorageResolution: 60, Source: "diskio"},
+			{Namespace: "CWAgent", MetricName: "mem_used_percent", Dimensions: [][]string{{"AutoScalingGroupName", "ImageId", "InstanceId", "InstanceType"}}, StorageResolution: 60, Source: "mem"},
+			{Namespace: "CWAgent", MetricName: "swap_used_percent", Dimensions: [][]string{{"AutoScalingGroupName", "ImageId", "InstanceId", "InstanceType"}}, StorageResolution: 60, Source: "swap"},
+		},
+	},
+	"standard_config_windows.conf": {
+		Metrics: []Metric{
+			{Namespace: "CWAgent", MetricName: "LogicalDisk % Free Space", Dimensions: [][]string{{"AutoScalingGroupName", "ImageId", "InstanceId", "InstanceType", "instance", "objectname"}}, StorageResolution: 60, Source: "win_perf_counters"},
+			{Namespace: "CWAgent", MetricName: "Memory % Committed Bytes In Use", Dimensions: [][]string{{"AutoScalingGroupName", "ImageId", "InstanceId", "InstanceType", "objectname"}}, StorageResolution: 60, Source: "win_perf_counters"},
+			{Namespace: "CWAgent", MetricName: "Paging File % Usage", Dimensions: [][]string{{"AutoScalingGroupName", "ImageId", "InstanceId", "InstanceType", "instance", "objectname"}}, StorageResolution: 60, Source: "win_perf_counters"},
+			{Namespace: "CWAgent", MetricName: "PhysicalDisk % Disk Time", Dimensions: [][]string{{"AutoScalingGroupName", "ImageId", "InstanceId", "InstanceType", "instance", "objectname"}}, StorageResolution: 60, Source: "win_perf_counters"},
+			{Namespace: "CWAgent", MetricName: "Processor % Idle Time", Dimensions: [][]string{{"AutoScalingGroupName", "ImageId", "InstanceId", "InstanceType", "instance", "objectname"}}, StorageResolution: 60, Source: "win_perf_counters"},
+			{Namespace: "CWAgent", MetricName: "Processor % Interrupt Time", Dimensions: [][]string{{"AutoScalingGroupName", "ImageId", "InstanceId", "InstanceType", "instance", "objectname"}}, StorageResolution: 60, Source: "win_perf_counters"},
+			{Namespace: "CWAgent", MetricName: "Processor % User Time", Dimensions: [][]string{{"AutoScalingGroupName", "ImageId", "InstanceId", "InstanceType", "instance", "objectname"}}, StorageResolution: 60, Source: "win_perf_counters"},
+		},
+	},
+	"standard_config_windows_with_common_config.conf": {
+		Metrics: []Metric{
+			{Namespace: "CWAgent", MetricName: "LogicalDisk % Free Space", Dimensions: [][]string{{"AutoScalingGroupName", "ImageId", "InstanceId", "InstanceType", "instance", "objectname"}}, StorageResolution: 60, Source: "win_perf_counters"},
+			{Namespace: "CWAgent", MetricName: "Memory % Committed Bytes In Use", Dimensions: [][]string{{"AutoScalingGroupName", "ImageId", "InstanceId", "InstanceType", "objectname"}}, StorageResolution: 60, Source: "win_perf_counters"},
+			{Namespace: "CWAgent", MetricName: "Paging File % Usage", Dimensions: [][]string{{"AutoScalingGroupName", "ImageId", "InstanceId", "InstanceType", "instance", "objectname"}}, StorageResolution: 60, Source: "win_perf_counters"},
+			{Namespace: "CWAgent", MetricName: "PhysicalDisk % Disk Time", Dimensions: [][]string{{"AutoScalingGroupName", "ImageId", "InstanceId", "InstanceType", "instance", "objectname"}}, StorageResolution: 60, Source: "win_perf_counters"},
+			{Namespace: "CWAgent", MetricName: "Processor % Idle Time", Dimensions: [][]string{{"AutoScalingGroupName", "ImageId", "InstanceId", "InstanceType", "instance", "objectname"}}, StorageResolution: 60, Source: "win_perf_counters"},
+			{Namespace: "CWAgent", MetricName: "Processor % Interrupt Time", Dimensions: [][]string{{"AutoScalingGroupName", "ImageId", "InstanceId", "InstanceType", "instance", "objectname"}}, StorageResolution: 60, Source: "win_perf_counters"},
+			{Namespace: "CWAgent", MetricName: "Processor % User Time", Dimensions: [][]string{{"AutoScalingGroupName", "ImageId", "InstanceId", "InstanceType", "instance", "objectname"}}, StorageResolution: 60, Source: "win_perf_counters"},
+		},
+	},
+	"statsd_config_linux.conf": {
+		DynamicSources: []DynamicSource{
+			{Namespace: "CWAgent", Source: "statsd", Dimensions: [][]string{{"host"}}},
+		},
+	},
+	"statsd_config_windows.conf": {
+		DynamicSources: []DynamicSource{
+			{Namespace: "CWAgent", Source: "statsd", Dimensions: [][]string{{"host"}}},
+		},
+	},
+	"systemd_config_linux.conf": {
+		Metrics: []Metric{
+			{Namespace: "CWAgent", MetricName: "systemd_active", Dimensions: [][]string{{"host", "unit"}}, StorageResolution: 60, Source: "systemd"},
+			{Namespace: "CWAgent", MetricName: "systemd_failed", Dimensions: [][]string{{"host", "unit"}}, StorageResolution: 60, Source: "systemd"},
+			{Namespace: "CWAgent", MetricName: "systemd_restarts", Dimensions: [][]string{{"host", "unit"}}, StorageResolution: 60, Source: "systemd"},
+		},
+	},
+	"timeouts_config_linux.conf": {
+		Metrics: []Metric{
+			{Namespace: "CWAgent", MetricName: "systemd_active", Dimensions: [][]string{{"host", "unit"}}, StorageResolution: 60, Source: "systemd"},
+			{Namespace: "CWAgent", MetricName: "timesync_offset", Dimensions: [][]string{{"host", "source"}}, StorageResolution: 60, Source: "timesync"},
+		},
+	},
+	"timesync_config_linux.conf": {
+		Metrics: []Metric{
+			{Namespace: "CWAgent", MetricName: "timesync_jitter", Dimensions: [][]string{{"host", "source"}}, StorageResolution: 60, Source: "timesync"},
+			{Namespace: "CWAgent", MetricName: "timesync_offset", Dimensions: [][]string{{"host", "source"}}, StorageResolution: 60, Source: "timesync"},
+			{Namespace: "CWAgent", MetricName: "timesync_stratum", Dimensions: [][]string{{"host", "source"}}, StorageResolution: 60, Source: "timesync"},
+			{Namespace: "CWAgent", MetricName: "timesync_synced", Dimensions: [][]string{{"host", "source"}}, StorageResolution: 60, Source: "timesync"},
+		},
+	},
+	"top_processes_config_linux.conf": {
+		Metrics: []Metric{
+			{Namespace: "CWAgent", MetricName: "top_processes_cpu_usage", Dimensions: [][]string{{"InstanceId", "process_name"}}, StorageResolution: 60, Source: "top_processes"},
+			{Namespace: "CWAgent", MetricName: "top_processes_memory_rss", Dimensions: [][]string{{"InstanceId", "process_name"}}, StorageResolution: 60, Source: "top_processes"},
+			{Namespace: "CWAgent", MetricName: "top_processes_memory_utilization", Dimensions: [][]string{{"InstanceId", "process_name"}}, StorageResolution: 60, Source: "top_processes"},
+		},
+	},
+	"windows_eventlog_only_config.conf": {},
+	"windows_gpu_config_windows.conf": {
+		Metrics: []Metric{
+			{Namespace: "CWAgent", MetricName: "windows_gpu engine_utilization", Dimensions: [][]string{{"InstanceId", "adapter", "engine_type"}}, StorageResolution: 60, Source: "windows_gpu"},
+			{Namespace: "CWAgent", MetricName: "windows_gpu memory_dedicated_usage", Dimensions: [][]string{{"InstanceId", "adapter"}}, StorageResolution: 60, Source: "windows_gpu"},
+			{Namespace: "CWAgent", MetricName: "windows_gpu memory_shared_usage", Dimensions: [][]string{{"InstanceId", "adapter"}}, StorageResolution: 60, Source: "windows_gpu"},
+			{Namespace: "CWAgent", MetricName: "windows_gpu utilization", Dimensions: [][]string{{"InstanceId", "adapter"}}, StorageResolution: 60, Source: "windows_gpu"},
+		},
+	},
+}
+
+// TestFixtureCatalogs checks the names, the namespaces and the dimensions of the metrics of every sample config.
+func TestFixtureCatalogs(t *testing.T) {
+	for _, f := range loadFixtures(t) {
+		expected, ok := fixtureCatalogs[f.name]
+		if !ok {
+			t.Errorf("%s: the sample config has no expected catalog", f.name)
+			continue
+		}
+		c, err := FromToml(f.toml, f.targetOs)
+		require.NoError(t, err, f.name)
+		assert.ElementsMatch(t, expected.Metrics, c.Metrics, f.name)
+		assert.ElementsMatch(t, expected.DynamicSources, c.DynamicSources, f.name)
+	}
+}
+
+// TestFixtureTags fails when the translator tags the inputs with a key the catalog doesn't know, it would be listed as
+// a dimension although a processor removes it or renames the fields with it.
+func TestFixtureTags(t *testing.T) {
+	for _, f := range loadFixtures(t) {
+		for pluginName, inputs := range f.metricInputs() {
+			for _, input := range inputs {
+				for key := range mapValue(input, "tags") {
+					if transientTags[key] || strings.HasPrefix(key, "aws:") || strings.HasPrefix(key, "recovery_") || f.userTags[key] {
+						continue
+					}
+					t.Errorf("%s: the %s tag of the %s input is neither a transient tag of the catalog nor a dimension of the user", f.name, key, pluginName)
+				}
+			}
+		}
+	}
+}
+
+// TestFixtureSources fails when an input published by the cloudwatch output is missing from the catalog.
+func TestFixtureSources(t *testing.T) {
+	for _, f := range loadFixtures(t) {
+		c, err := FromToml(f.toml, f.targetOs)
+		require.NoError(t, err, f.name)
+		sources := map[string]bool{}
+		for _, m := range c.Metrics {
+			sources[m.Source] = true
+		}
+		for _, s := range c.DynamicSources {
+			sources[s.Source] = true
+		}
+		for pluginName := range f.metricInputs() {
+			assert.True(t, sources[pluginName], "%s: the %s input is missing from the catalog", f.name, pluginName)
+		}
+	}
+}
+
+// TestFixtureDeltaNames runs the delta processor on the fields of the inputs it reports as deltas or rates, the catalog
+// must list the metric names of the fields it returns.
+func TestFixtureDeltaNames(t *testing.T) {
+	checked := 0
+	for _, f := range loadFixtures(t) {
+		c, err := FromToml(f.toml, f.targetOs)
+		require.NoError(t, err, f.name)
+		names := map[string]bool{}
+		for _, m := range c.Metrics {
+			names[m.Source+"/"+m.MetricName] = true
+		}
+		separator := "_"
+		if f.targetOs == "windows" {
+			separator = " "
+		}
+		for pluginName, inputs := range f.metricInputs() {
+			if _, known := pluginDimensions[pluginName]; !known {
+				continue
+			}
+			measurement := pluginName
+			if m, ok := pluginMeasurements[pluginName]; ok {
+				measurement = m
+			}
+			for _, input := range inputs {
+				tags := map[string]string{}
+				for key, value := range mapValue(input, "tags") {
+					tags[key], _ = value.(string)
+				}
+				if tags[delta.ReportDelta] != delta.TrueValue && tags[delta.ReportRate] != delta.TrueValue {
+					continue
+				}
+				fields := stringSlice(input["fieldpass"])
+				if len(fields) == 0 || hasPattern(fields) {
+					continue
+				}
+				d := processors.Processors["delta"]()
+				now := time.Now()
+				for i, value := range []int64{1, 2} {
+					values := map[string]interface{}{}
+					for _, field := range fields {
+						values[field] = value
+					}
+					m, err := metric.New(measurement, tags, values, now.Add(time.Duration(i)*time.Second))
+					require.NoError(t, err)
+					for _, out := range d.Apply(m) {
+						for _, field := range out.FieldList() {
+							name := measurement + separator + field.Key
+							if rename := f.renames[decorationKey(measurement, field.Key)]; rename != "" {
+								name = rename
+							}
+							assert.True(t, names[pluginName+"/"+name], "%s: the catalog has no %s metric of the %s input", f.name, name, pluginName)
+							checked++
+						}
+					}
+				}
+			}
+		}
+	}
+	assert.NotZero(t, checked)
+}
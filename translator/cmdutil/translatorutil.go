@@ -4,6 +4,7 @@
 package cmdutil
 
 import (
+	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"log"
@@ -13,6 +14,7 @@ import (
 	"strings"
 
 	"github.com/aws/amazon-cloudwatch-agent/translator"
+	"github.com/aws/amazon-cloudwatch-agent/translator/catalog"
 	"github.com/aws/amazon-cloudwatch-agent/translator/config"
 	"github.com/aws/amazon-cloudwatch-agent/translator/context"
 	"github.com/aws/amazon-cloudwatch-agent/translator/jsonconfig"
@@ -48,6 +50,31 @@ func TranslateJsonMapToTomlFile(jsonConfigValue map[string]interface{}, tomlConf
 	}
 }
 
+// TranslateJsonMapToCatalogFile writes the catalog of the metrics the json config publishes to CloudWatch, the catalog
+// is written to stdout when catalogFilePath is "-".
+func TranslateJsonMapToCatalogFile(jsonConfigValue map[string]interface{}, catalogFilePath string) {
+	res := totomlconfig.ToTomlConfig(jsonConfigValue)
+	if !translator.IsTranslateSuccess() {
+		log.Panic("E! Failed to generate configuration validation content.")
+	}
+	c, err := catalog.FromToml(res, translator.GetTargetPlatform())
+	if err != nil {
+		log.Panicf("E! Failed to generate the metric catalog. Reason: %s", err.Error())
+	}
+	bytes, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		log.Panicf("E! Failed to marshal the metric catalog. Reason: %s", err.Error())
+	}
+	if catalogFilePath == "-" {
+		fmt.Println(string(bytes))
+		return
+	}
+	if err := ioutil.WriteFile(catalogFilePath, bytes, tomlFileMode); err != nil {
+		log.Panicf("E! Failed to create the metric catalog file. Reason: %s", err.Error())
+	}
+	log.Printf("I! Metric catalog written to %s", catalogFilePath)
+}
+
 // TranslateJsonMapToEnvConfigFile populates env-config.json based on the input json config.
 func TranslateJsonMapToEnvConfigFile(jsonConfigValue map[string]interface{}, envConfigPath string) {
 	if envConfigPath == "" {
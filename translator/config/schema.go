@@ -35,7 +35,7 @@ var schema = `{
       "description": "General configuration for Amazon CloudWatch Agent",
       "properties": {
         "metrics_collection_interval": {
-          "description": "How often the metrics defined will be collected",
+          "description": "How often the metrics defined will be collected, unit is second. The metrics collected more often than every 60 seconds are published as high resolution metrics, the intervals below 10 seconds publish as many more datums and PutMetricData requests, which are charged",
           "$ref": "#/definitions/timeIntervalDefinition"
         },
         "logfile": {
@@ -57,6 +57,19 @@ var schema = `{
           "description": "Specifies running the CloudWatch agent with AWS SDK debug logging. Multiple options must be separated by vertical bars.",
           "type": "string"
         },
+        "sidecar": {
+          "description": "Specifies the agent runs as a sidecar container, its stop signal drains the logs the application writes until it stopped, at most 20 seconds, before stopping.",
+          "type": "boolean"
+        },
+        "endpoint_mode": {
+          "description": "Specifies the endpoints of the CloudWatch, CloudWatch Logs, EC2, STS and ECS clients of the agent, standard by default, fips for the FIPS endpoints or dualstack for the IPv4 and IPv6 endpoints. The endpoint_override of a section still wins.",
+          "type": "string",
+          "enum": [
+            "standard",
+            "fips",
+            "dualstack"
+          ]
+        },
         "credentials": {
           "description": "The credentials with which agent can access aws resources",
           "$ref": "#/definitions/credentialsDefinition"
@@ -64,6 +77,98 @@ var schema = `{
         "omit_hostname": {
           "description": "Hostname will be tagged by default unless you specifying append_dimensions, this flag allow you to omit hostname from tags without specifying append_dimensions",
           "type": "boolean"
+        },
+        "canary": {
+          "description": "The heartbeat of the agent published with its version and the hash of its configuration, whatever the rest of the configuration is",
+          "type": "object",
+          "properties": {
+            "namespace": {
+              "description": "The namespace of the heartbeat, CWAgent/Canary by default",
+              "type": "string",
+              "minLength": 1,
+              "maxLength": 255
+            },
+            "interval": {
+              "description": "The interval the heartbeat is published at, unit is second, 60 by default",
+              "type": "integer",
+              "minimum": 60
+            }
+          },
+          "additionalProperties": false
+        },
+        "health_hook": {
+          "description": "The command run and the webhook posted to when a pipeline of the agent keeps failing to publish",
+          "type": "object",
+          "properties": {
+            "command": {
+              "description": "The command run with the json of the event on its stdin",
+              "type": "string",
+              "minLength": 1
+            },
+            "webhook_url": {
+              "description": "The url the json of the event is posted to",
+              "type": "string",
+              "pattern": "^https?://.+$"
+            },
+            "unhealthy_threshold": {
+              "description": "How long a pipeline fails without any success before it is unhealthy, unit is second",
+              "type": "integer",
+              "minimum": 10
+            },
+            "repeat_interval": {
+              "description": "Repeat the notification while the pipeline stays unhealthy, unit is second. It is sent once when 0",
+              "type": "integer",
+              "minimum": 0
+            },
+            "timeout": {
+              "description": "The timeout of the command and of the post to the webhook, unit is second, the exec timeout of the agent by default",
+              "$ref": "#/definitions/timeoutDefinition"
+            }
+          },
+          "anyOf": [
+            {
+              "required": [
+                "command"
+              ]
+            },
+            {
+              "required": [
+                "webhook_url"
+              ]
+            }
+          ],
+          "additionalProperties": false
+        },
+        "metrics_output": {
+          "description": "How the collected metrics are published, with PutMetricData or as embedded metric format documents to CloudWatch Logs",
+          "type": "object",
+          "properties": {
+            "mode": {
+              "description": "put_metric_data by default, or emf to write the metrics to the log stream, CloudWatch extracts their metrics and Logs Insights queries them by any dimension",
+              "type": "string",
+              "enum": [
+                "put_metric_data",
+                "emf"
+              ]
+            },
+            "log_group_name": {
+              "description": "The log group of the emf documents, /aws/cwagent/metrics by default",
+              "type": "string",
+              "minLength": 1,
+              "maxLength": 512
+            },
+            "log_stream_name": {
+              "description": "The log stream of the emf documents, the host name by default",
+              "type": "string",
+              "minLength": 1,
+              "maxLength": 512
+            }
+          },
+          "additionalProperties": false
+        },
+        "timeouts": {
+          "description": "The timeouts of the agent, overridden by the timeouts of the metrics and logs sections and by the timeout of the collectors running commands",
+          "$ref": "#/definitions/timeoutsDefinition"
         }
       },
       "additionalProperties": true
@@ -148,6 +253,9 @@ var schema = `{
             "cpu": {
               "$ref": "#/definitions/metricsDefinition/definitions/cpuDefinitions"
             },
+            "cpu_cluster": {
+              "$ref": "#/definitions/metricsDefinition/definitions/cpuClusterDefinitions"
+            },
             "disk": {
               "$ref": "#/definitions/metricsDefinition/definitions/diskDefinitions"
             },
@@ -180,6 +288,54 @@ var schema = `{
             },
             "nvidia_smi": {
               "$ref": "#/definitions/metricsDefinition/definitions/nvidiaGpuDefinitions"
+            },
+            "jmx": {
+              "$ref": "#/definitions/metricsDefinition/definitions/jmxDefinitions"
+            },
+            "timesync": {
+              "$ref": "#/definitions/metricsDefinition/definitions/timesyncDefinitions"
+            },
+            "systemd": {
+              "$ref": "#/definitions/metricsDefinition/definitions/systemdDefinitions"
+            },
+            "docker": {
+              "$ref": "#/definitions/metricsDefinition/definitions/dockerDefinitions"
+            },
+            "containerd": {
+              "$ref": "#/definitions/metricsDefinition/definitions/containerdDefinitions"
+            },
+            "kafka": {
+              "$ref": "#/definitions/metricsDefinition/definitions/kafkaDefinitions"
+            },
+            "haproxy": {
+              "$ref": "#/definitions/metricsDefinition/definitions/haproxyDefinitions"
+            },
+            "rabbitmq": {
+              "$ref": "#/definitions/metricsDefinition/definitions/rabbitmqDefinitions"
+            },
+            "cgroup": {
+              "$ref": "#/definitions/metricsDefinition/definitions/cgroupDefinitions"
+            },
+            "node_exporter": {
+              "$ref": "#/definitions/metricsDefinition/definitions/nodeExporterDefinitions"
+            },
+            "fd": {
+              "$ref": "#/definitions/metricsDefinition/definitions/fdDefinitions"
+            },
+            "top_processes": {
+              "$ref": "#/definitions/metricsDefinition/definitions/topProcessesDefinitions"
+            },
+            "kernel": {
+              "$ref": "#/definitions/metricsDefinition/definitions/kernelDefinitions"
+            },
+            "sensors": {
+              "$ref": "#/definitions/metricsDefinition/definitions/sensorsDefinitions"
+            },
+            "windows_gpu": {
+              "$ref": "#/definitions/metricsDefinition/definitions/windowsGPUDefinitions"
+            },
+            "cloudwatch_query": {
+              "$ref": "#/definitions/metricsDefinition/definitions/cloudwatchQueryDefinitions"
             }
           },
           "minProperties": 1,
@@ -191,135 +347,613 @@ var schema = `{
           "description": "Max time to wait before batch publishing the metrics, unit is second.",
           "$ref": "#/definitions/timeIntervalDefinition"
         },
-        "credentials": {
-          "description": "The credentials with which agent can access aws resources",
-          "$ref": "#/definitions/credentialsDefinition"
-        },
-        "endpoint_override": {
-          "description": "The override endpoint to use to access cloudwatch",
-          "$ref": "#/definitions/endpointOverrideDefinition"
-        }
-      },
-      "additionalProperties": false,
-      "required": [
-        "metrics_collected"
-      ],
-      "definitions": {
-        "basicMetricDefinition": {
-          "type": "object",
-          "properties": {
-            "metrics_collection_interval": {
-              "$ref": "#/definitions/timeIntervalDefinition"
+        "downsampling": {
+          "description": "Collapses the metrics older than older_than into aggregates of interval before publishing them, e.g. when the metrics buffered during an outage are drained. Units are seconds.",
+          "type": "array",
+          "items": {
+            "type": "object",
+            "properties": {
+              "older_than": {
+                "$ref": "#/definitions/timeIntervalDefinition"
+              },
+              "interval": {
+                "$ref": "#/definitions/timeIntervalDefinition"
+              }
             },
-            "append_dimensions": {
-              "$ref": "#/definitions/generalAppendDimensionsDefinition"
+            "required": [
+              "older_than",
+              "interval"
+            ],
+            "additionalProperties": false
+          },
+          "minItems": 1,
+          "maxItems": 10
+        },
+        "namespace_routing": {
+          "description": "Sends the metrics with a dimension to another namespace, {value} in namespace is replaced with the value of the dimension. The first matching rule applies, the other metrics are sent to the namespace of the metrics section.",
+          "type": "array",
+          "items": {
+            "type": "object",
+            "properties": {
+              "dimension": {
+                "type": "string",
+                "minLength": 1,
+                "maxLength": 255
+              },
+              "namespace": {
+                "type": "string",
+                "minLength": 1,
+                "maxLength": 255
+              },
+              "namespaces": {
+                "type": "object",
+                "additionalProperties": {
+                  "type": "string",
+                  "minLength": 1,
+                  "maxLength": 255
+                }
+              },
+              "drop_dimension": {
+                "type": "boolean"
+              }
             },
-            "measurement": {
-              "$ref": "#/definitions/metricsDefinition/definitions/metricsMeasurementDefinition"
-            }
+            "required": [
+              "dimension"
+            ],
+            "anyOf": [
+              {
+                "required": [
+                  "namespace"
+                ]
+              },
+              {
+                "required": [
+                  "namespaces"
+                ]
+              }
+            ],
+            "additionalProperties": false
           },
-          "required": [
-            "measurement"
-          ]
+          "minItems": 1,
+          "maxItems": 10
         },
-        "basicResourcesDefinition": {
-          "type": "object",
-          "properties": {
-            "resources": {
-              "type": "array",
-              "items": {
+        "metric_rename": {
+          "description": "Renames the metrics whose name matches a regular expression and can send them to another namespace. The first matching rule applies, after the renames of the metric decorations.",
+          "type": "array",
+          "items": {
+            "type": "object",
+            "properties": {
+              "pattern": {
+                "description": "the regular expression matching the metric names, e.g. ^disk_(.+)$",
                 "type": "string",
                 "minLength": 1,
-                "maxLength": 4096
+                "maxLength": 1024
               },
-              "maxItems": 256
-            }
-          }
+              "rename": {
+                "description": "the replacement of the matches of the pattern, $1 is replaced with the first group of the pattern, e.g. Disk.$1",
+                "type": "string",
+                "minLength": 1,
+                "maxLength": 255
+              },
+              "namespace": {
+                "description": "the namespace the matching metrics are sent to instead of the namespace of the metrics section",
+                "type": "string",
+                "minLength": 1,
+                "maxLength": 255
+              }
+            },
+            "required": [
+              "pattern"
+            ],
+            "anyOf": [
+              {
+                "required": [
+                  "rename"
+                ]
+              },
+              {
+                "required": [
+                  "namespace"
+                ]
+              }
+            ],
+            "additionalProperties": false
+          },
+          "minItems": 1
         },
-        "collectdDefinitions": {
+        "dimension_normalization": {
+          "description": "The normalization policy of the dimension names and values, so the sources reporting the same dimension with a different case or characters publish to the same series",
           "type": "object",
           "properties": {
-            "service_address": {
+            "key_case": {
               "type": "string",
-              "minLength": 1,
-              "maxLength": 255
+              "enum": [
+                "lower",
+                "upper",
+                "none"
+              ]
             },
-            "name_prefix": {
+            "value_case": {
               "type": "string",
-              "minLength": 1,
-              "maxLength": 255
+              "enum": [
+                "lower",
+                "upper",
+                "none"
+              ]
             },
-            "collectd_auth_file": {
+            "allowed_characters": {
+              "description": "the characters kept as the content of a regex character class, e.g. a-zA-Z0-9_.-, the other characters are replaced",
               "type": "string",
               "minLength": 1,
-              "maxLength": 4096
+              "maxLength": 255
             },
-            "collectd_security_level": {
+            "replacement": {
+              "description": "the replacement of the characters not allowed, _ by default",
               "type": "string",
-              "enum": [
-                "none",
-                "sign",
-                "encrypt"
-              ]
-            },
-            "collectd_typesdb": {
-              "type": "array",
-              "maxItems": 10,
-              "items": {
-                "type": "string",
-                "minLength": 1,
-                "maxLength": 4096
-              }
+              "maxLength": 8
             },
-            "metrics_aggregation_interval": {
-              "$ref": "#/definitions/timeIntervalWithZeroDefinition"
+            "max_length": {
+              "description": "the names and values longer than max_length are truncated and suffixed with a hash of the whole value",
+              "type": "integer",
+              "minimum": 16,
+              "maximum": 1024
             }
           },
+          "minProperties": 1,
           "additionalProperties": false
         },
-        "cpuDefinitions": {
-          "type": "object",
-          "allOf": [
-            {
-              "$ref": "#/definitions/metricsDefinition/definitions/basicMetricDefinition"
-            },
-            {
-              "type": "object",
-              "properties": {
-                "resources": {
-                  "type": "array",
-                  "maxItems": 1,
-                  "items": {
-                    "type": "string",
-                    "enum": [
-                      "*"
-                    ]
-                  }
-                },
-                "totalcpu": {
-                  "type": "boolean"
+        "dimension_lookups": {
+          "description": "The lookup tables mapping the raw values of dimensions to friendly ones before the metrics are published, e.g. the device UUIDs to mount labels",
+          "type": "array",
+          "minItems": 1,
+          "items": {
+            "type": "object",
+            "properties": {
+              "dimension": {
+                "description": "the dimension whose values are looked up, the values not in the table are kept",
+                "type": "string",
+                "minLength": 1,
+                "maxLength": 255
+              },
+              "values": {
+                "description": "the raw values and their friendly value",
+                "type": "object",
+                "minProperties": 1,
+                "additionalProperties": {
+                  "type": "string",
+                  "minLength": 1,
+                  "maxLength": 1024
                 }
+              },
+              "file": {
+                "description": "a JSON object of the raw values and their friendly value, merged over values and read again when it changes",
+                "type": "string",
+                "minLength": 1
+              },
+              "reload_interval": {
+                "description": "the seconds between the checks of the changes of file, 60 by default",
+                "type": "integer",
+                "minimum": 1
               }
-            }
-          ]
-        },
-        "diskDefinitions": {
-          "type": "object",
-          "allOf": [
-            {
-              "$ref": "#/definitions/metricsDefinition/definitions/basicMetricDefinition"
             },
-            {
-              "$ref": "#/definitions/metricsDefinition/definitions/basicResourcesDefinition"
+            "required": [
+              "dimension"
+            ],
+            "additionalProperties": false
+          }
+        },
+        "deadband": {
+          "description": "The rules suppressing the values of the metrics by metric name pattern while they don't change by more than a delta since the last published value, e.g. disk_total",
+          "type": "array",
+          "minItems": 1,
+          "items": {
+            "type": "object",
+            "properties": {
+              "metric_name": {
+                "description": "the glob pattern of the metric names, e.g. disk_*, the first rule matching a metric applies",
+                "type": "string",
+                "minLength": 1,
+                "maxLength": 255
+              },
+              "delta": {
+                "description": "the change since the last published value up to which the values are suppressed, 0 by default to suppress the unchanged values only",
+                "type": "number",
+                "minimum": 0
+              },
+              "max_staleness": {
+                "description": "the seconds after which a value is published again even when it didn't change, 3600 by default",
+                "type": "integer",
+                "minimum": 1
+              }
             },
-            {
-              "type": "object",
-              "properties": {
-                "ignore_file_system_types": {
-                  "type": "array",
-                  "items": {
-                    "type": "string",
-                    "minLength": 1,
+            "required": [
+              "metric_name"
+            ],
+            "additionalProperties": false
+          }
+        },
+        "dimension_rules": {
+          "description": "The rules keeping or dropping the dimensions of the metrics by metric name pattern before they are published, e.g. the cpu dimension of the per-core metrics",
+          "type": "array",
+          "minItems": 1,
+          "items": {
+            "type": "object",
+            "properties": {
+              "metric_name": {
+                "description": "the glob pattern of the metric names, e.g. cpu_usage_*, the rules matching a metric all apply in order",
+                "type": "string",
+                "minLength": 1,
+                "maxLength": 255
+              },
+              "keep": {
+                "description": "the dimensions kept, the other dimensions are dropped",
+                "type": "array",
+                "minItems": 1,
+                "items": {
+                  "type": "string",
+                  "minLength": 1,
+                  "maxLength": 255
+                }
+              },
+              "drop": {
+                "description": "the dimensions dropped",
+                "type": "array",
+                "minItems": 1,
+                "items": {
+                  "type": "string",
+                  "minLength": 1,
+                  "maxLength": 255
+                }
+              }
+            },
+            "required": [
+              "metric_name"
+            ],
+            "anyOf": [
+              {
+                "required": [
+                  "keep"
+                ]
+              },
+              {
+                "required": [
+                  "drop"
+                ]
+              }
+            ],
+            "additionalProperties": false
+          }
+        },
+        "metric_transforms": {
+          "description": "The metrics computed from the collected ones before they are published, e.g. ratios, sums and the rates of the counters",
+          "type": "array",
+          "minItems": 1,
+          "items": {
+            "type": "object",
+            "properties": {
+              "measurement": {
+                "description": "the measurement of the fields of the expression, e.g. disk, the derived metric is added to its metrics",
+                "type": "string",
+                "minLength": 1,
+                "maxLength": 255
+              },
+              "name": {
+                "description": "the name of the derived metric, e.g. disk_used_ratio",
+                "type": "string",
+                "minLength": 1,
+                "maxLength": 255
+              },
+              "expression": {
+                "description": "the arithmetic of the fields and the numbers with +, -, *, /, the parentheses and the change per second rate(field) or the change delta(field) of the counters, e.g. disk_used / disk_total * 100",
+                "type": "string",
+                "minLength": 1,
+                "maxLength": 1024
+              }
+            },
+            "required": [
+              "measurement",
+              "name",
+              "expression"
+            ],
+            "additionalProperties": false
+          }
+        },
+        "parquet_export": {
+          "description": "Writes the metrics hourly to S3 as parquet files partitioned by namespace and date, in parallel with the CloudWatch publication",
+          "type": "object",
+          "properties": {
+            "bucket": {
+              "type": "string",
+              "minLength": 3,
+              "maxLength": 63
+            },
+            "prefix": {
+              "type": "string",
+              "maxLength": 512
+            },
+            "compression": {
+              "type": "string",
+              "enum": [
+                "gzip",
+                "none"
+              ]
+            },
+            "max_rows_per_file": {
+              "description": "A file is written as soon as an hour holds this number of datapoints",
+              "type": "integer",
+              "minimum": 1
+            },
+            "max_pending_files": {
+              "description": "The number of files kept in memory while the uploads fail",
+              "type": "integer",
+              "minimum": 1
+            }
+          },
+          "required": [
+            "bucket"
+          ],
+          "additionalProperties": false
+        },
+        "credentials": {
+          "description": "The credentials with which agent can access aws resources",
+          "$ref": "#/definitions/credentialsDefinition"
+        },
+        "endpoint_override": {
+          "description": "The override endpoint to use to access cloudwatch",
+          "$ref": "#/definitions/endpointOverrideDefinition"
+        },
+        "quarantine_file": {
+          "description": "The file the datums rejected by the CloudWatch limits before publishing are written to",
+          "type": "string",
+          "minLength": 1,
+          "maxLength": 4096
+        },
+        "distribution_type": {
+          "description": "The distribution of the values, seh1, exponential_histogram whose buckets are the ones of the OpenTelemetry base-2 exponential histograms, or tdigest for accurate high percentiles",
+          "type": "string",
+          "enum": [
+            "seh1",
+            "exponential_histogram",
+            "tdigest"
+          ]
+        },
+        "tdigest_compression": {
+          "description": "The compression bounding the centroids of the tdigest distributions, 100 by default",
+          "type": "number",
+          "minimum": 10,
+          "maximum": 5000
+        },
+        "seh1_epsilon": {
+          "description": "The growth of the buckets of the seh1 distributions, 0.1 by default, a lower epsilon publishes values closer to the actual ones in more buckets",
+          "type": "number",
+          "minimum": 0.01,
+          "maximum": 1
+        },
+        "max_datums_per_call": {
+          "description": "The datums of a PutMetricData request, 20 by default, the requests throttled are limited to fewer datums until the throttling stops",
+          "type": "integer",
+          "minimum": 1,
+          "maximum": 1000
+        },
+        "max_payload_size": {
+          "description": "The payload in bytes the datums of a PutMetricData request are packed in, 200000 by default",
+          "type": "integer",
+          "minimum": 1000,
+          "maximum": 1000000
+        },
+        "max_retries": {
+          "description": "The attempts of the PutMetricData and PutLogEvents requests of the metrics, 5 by default",
+          "type": "integer",
+          "minimum": 1,
+          "maximum": 100
+        },
+        "max_backoff": {
+          "description": "The maximum backoff in seconds between the attempts of a request, 60 by default",
+          "type": "integer",
+          "minimum": 1,
+          "maximum": 3600
+        },
+        "max_requests_per_second": {
+          "description": "The attempts per second of the requests of the metrics, unlimited by default, the rate is halved while the requests are throttled",
+          "type": "number",
+          "minimum": 0,
+          "exclusiveMinimum": true
+        },
+        "negative_values": {
+          "description": "The policy of the negative values of the distributions, drop by default, clamp to add them as 0, or track to add them to the statistics only",
+          "type": "string",
+          "enum": [
+            "drop",
+            "clamp",
+            "track"
+          ]
+        },
+        "unit_conflicts": {
+          "description": "The policy of the values added to the distributions in a unit which can't be converted to theirs, prefer_left to keep the unit of the distribution by default, fail or drop",
+          "type": "string",
+          "enum": [
+            "prefer_left",
+            "fail",
+            "drop"
+          ]
+        },
+        "checkpoint_aggregations": {
+          "description": "Whether the metrics being aggregated are persisted to the state folder on shutdown and aggregated again on start",
+          "type": "boolean"
+        },
+        "timeouts": {
+          "description": "The timeouts of the calls to cloudwatch, the timeouts of the agent by default",
+          "$ref": "#/definitions/apiTimeoutsDefinition"
+        }
+      },
+      "additionalProperties": false,
+      "required": [
+        "metrics_collected"
+      ],
+      "definitions": {
+        "basicMetricDefinition": {
+          "type": "object",
+          "properties": {
+            "metrics_collection_interval": {
+              "$ref": "#/definitions/timeIntervalDefinition"
+            },
+            "append_dimensions": {
+              "$ref": "#/definitions/generalAppendDimensionsDefinition"
+            },
+            "measurement": {
+              "$ref": "#/definitions/metricsDefinition/definitions/metricsMeasurementDefinition"
+            },
+            "measurement_exclude": {
+              "description": "Linux and macOS only, the measurements dropped, e.g. from the fields published by the \"*\" measurement",
+              "$ref": "#/definitions/metricsDefinition/definitions/metricsMeasurementWithoutDecorationDefinition"
+            },
+            "normalize_instances": {
+              "description": "Windows only, normalizes volatile instance names like process#1, w3wp_1234 or guid suffixed instances into stable dimension values, the default patterns only apply to the Process object",
+              "type": "boolean"
+            },
+            "normalize_instances_patterns": {
+              "description": "Windows only, the regular expressions removed from the instance names of the object in order when normalize_instances is true, instead of the default patterns",
+              "type": "array",
+              "items": {
+                "type": "string",
+                "minLength": 1,
+                "maxLength": 1024
+              },
+              "minItems": 1,
+              "maxItems": 64
+            }
+          },
+          "required": [
+            "measurement"
+          ]
+        },
+        "basicResourcesDefinition": {
+          "type": "object",
+          "properties": {
+            "resources": {
+              "type": "array",
+              "items": {
+                "type": "string",
+                "minLength": 1,
+                "maxLength": 4096
+              },
+              "maxItems": 256
+            }
+          }
+        },
+        "collectdDefinitions": {
+          "type": "object",
+          "properties": {
+            "service_address": {
+              "type": "string",
+              "minLength": 1,
+              "maxLength": 255
+            },
+            "name_prefix": {
+              "type": "string",
+              "minLength": 1,
+              "maxLength": 255
+            },
+            "collectd_auth_file": {
+              "type": "string",
+              "minLength": 1,
+              "maxLength": 4096
+            },
+            "collectd_security_level": {
+              "type": "string",
+              "enum": [
+                "none",
+                "sign",
+                "encrypt"
+              ]
+            },
+            "collectd_user_security_levels": {
+              "description": "The security levels of the users overriding collectd_security_level for their packets",
+              "type": "object",
+              "additionalProperties": {
+                "type": "string",
+                "enum": [
+                  "none",
+                  "sign",
+                  "encrypt"
+                ]
+              }
+            },
+            "collectd_typesdb": {
+              "type": "array",
+              "maxItems": 10,
+              "items": {
+                "type": "string",
+                "minLength": 1,
+                "maxLength": 4096
+              }
+            },
+            "max_series": {
+              "description": "The distinct series published by metrics_aggregation_interval, the ones over it are dropped or aggregated into the overflow series of their metric",
+              "type": "integer",
+              "minimum": 1,
+              "maximum": 2147483647
+            },
+            "series_overflow": {
+              "type": "string",
+              "enum": [
+                "drop",
+                "aggregate"
+              ]
+            },
+            "metrics_aggregation_interval": {
+              "$ref": "#/definitions/timeIntervalWithZeroDefinition"
+            }
+          },
+          "additionalProperties": false
+        },
+        "cpuDefinitions": {
+          "type": "object",
+          "allOf": [
+            {
+              "$ref": "#/definitions/metricsDefinition/definitions/basicMetricDefinition"
+            },
+            {
+              "type": "object",
+              "properties": {
+                "resources": {
+                  "type": "array",
+                  "maxItems": 1,
+                  "items": {
+                    "type": "string",
+                    "enum": [
+                      "*"
+                    ]
+                  }
+                },
+                "totalcpu": {
+                  "type": "boolean"
+                },
+                "aggregate_percpu": {
+                  "description": "Publish the per core metrics as one distribution across all the cores instead of one series per core",
+                  "type": "boolean"
+                }
+              }
+            }
+          ]
+        },
+        "diskDefinitions": {
+          "type": "object",
+          "allOf": [
+            {
+              "$ref": "#/definitions/metricsDefinition/definitions/basicMetricDefinition"
+            },
+            {
+              "$ref": "#/definitions/metricsDefinition/definitions/basicResourcesDefinition"
+            },
+            {
+              "type": "object",
+              "properties": {
+                "ignore_file_system_types": {
+                  "type": "array",
+                  "items": {
+                    "type": "string",
+                    "minLength": 1,
                     "maxLength": 4096
                   },
                   "maxItems": 256
@@ -365,15 +999,223 @@ var schema = `{
               "type": "string",
               "minLength": 1,
               "maxLength": 255
-            }
-          },
-          "additionalProperties": false
-        },
-        "swapDefinitions": {
+            },
+            "allowed_data_dog_tags": {
+              "description": "The keys of the dogstatsd tags kept as dimensions, every key is kept when not set",
+              "type": "array",
+              "items": {
+                "type": "string",
+                "minLength": 1,
+                "maxLength": 255
+              },
+              "minItems": 1,
+              "uniqueItems": true
+            },
+            "max_data_dog_tag_values": {
+              "description": "The distinct values of each dogstatsd tag key kept as dimensions, the other values are replaced by <other>",
+              "type": "integer",
+              "minimum": 1,
+              "maximum": 2147483647
+            },
+            "events_log_group_name": {
+              "description": "The log group the dogstatsd events and service checks are published to as structured log events, they are dropped when not set. It requires the logs section",
+              "$ref": "#/definitions/logsDefinition/definitions/logGroupNameDefinition"
+            },
+            "events_log_stream_name": {
+              "description": "The log stream of the dogstatsd events and service checks, the log_stream_name of the logs section by default",
+              "$ref": "#/definitions/logsDefinition/definitions/logStreamNameDefinition"
+            },
+            "templates": {
+              "description": "The graphite templates mapping the parts of the dotted buckets to the metric name and to dimensions, e.g. api.*.*.latency measurement.service.endpoint.measurement",
+              "type": "array",
+              "items": {
+                "type": "string",
+                "minLength": 1,
+                "maxLength": 1024
+              },
+              "minItems": 1
+            },
+            "read_buffer_size": {
+              "description": "The size in bytes of the receive buffer of the UDP socket, the kernel drops the packets once it is full",
+              "type": "integer",
+              "minimum": 1024,
+              "maximum": 2147483647
+            },
+            "read_batch_size": {
+              "description": "The UDP packets batched into one queued message while the queue is full, before they are dropped",
+              "type": "integer",
+              "minimum": 1,
+              "maximum": 10000
+            },
+            "counter_mode": {
+              "description": "How the counters are published, sum publishes the sum of their increments and rate publishes their per second rate",
+              "type": "string",
+              "enum": [
+                "sum",
+                "rate"
+              ]
+            },
+            "max_series": {
+              "description": "The distinct series, metric names with their dimensions, published by metrics_aggregation_interval, the ones over it are dropped or aggregated into the overflow series of their metric",
+              "type": "integer",
+              "minimum": 1,
+              "maximum": 2147483647
+            },
+            "series_overflow": {
+              "description": "Whether the series over max_series are dropped, or aggregated into the series of their metric with the only dimension overflow=true",
+              "type": "string",
+              "enum": [
+                "drop",
+                "aggregate"
+              ]
+            },
+            "percentile_mode": {
+              "description": "How the timings are published, distribution publishes their distribution and metrics publishes their percentiles as metrics of their own",
+              "type": "string",
+              "enum": [
+                "distribution",
+                "metrics"
+              ]
+            },
+            "percentiles": {
+              "description": "The percentiles of the timings published in the metrics mode, 50, 90, 99 and 99.9 by default",
+              "type": "array",
+              "items": {
+                "type": "number",
+                "minimum": 0,
+                "exclusiveMinimum": true,
+                "maximum": 100
+              },
+              "minItems": 1,
+              "uniqueItems": true
+            }
+          },
+          "additionalProperties": false
+        },
+        "cloudwatchQueryDefinitions": {
+          "type": "object",
+          "properties": {
+            "leader_host": {
+              "description": "the host name of the host running the queries, every host runs them when not set",
+              "type": "string",
+              "minLength": 1,
+              "maxLength": 255
+            },
+            "metrics_collection_interval": {
+              "description": "the interval of the collections and the period of the queried datapoints",
+              "$ref": "#/definitions/timeIntervalDefinition"
+            },
+            "delay": {
+              "description": "the seconds the queried period ends before the collection, 180 by default",
+              "type": "integer",
+              "minimum": 0,
+              "maximum": 86400
+            },
+            "queries": {
+              "type": "array",
+              "minItems": 1,
+              "maxItems": 500,
+              "items": {
+                "type": "object",
+                "properties": {
+                  "id": {
+                    "description": "the id of the query, used in the expressions of the other queries",
+                    "type": "string",
+                    "pattern": "^[a-z][a-zA-Z0-9_]*$",
+                    "maxLength": 255
+                  },
+                  "name": {
+                    "description": "the name of the reported metric, the id by default",
+                    "type": "string",
+                    "minLength": 1,
+                    "maxLength": 255
+                  },
+                  "expression": {
+                    "description": "a metric math expression",
+                    "type": "string",
+                    "minLength": 1,
+                    "maxLength": 1024
+                  },
+                  "namespace": {
+                    "type": "string",
+                    "minLength": 1,
+                    "maxLength": 255
+                  },
+                  "metric_name": {
+                    "type": "string",
+                    "minLength": 1,
+                    "maxLength": 255
+                  },
+                  "dimensions": {
+                    "type": "object",
+                    "maxProperties": 30,
+                    "additionalProperties": {
+                      "type": "string",
+                      "minLength": 1,
+                      "maxLength": 255
+                    }
+                  },
+                  "stat": {
+                    "description": "the statistic of the metric, Average by default",
+                    "type": "string",
+                    "minLength": 1,
+                    "maxLength": 255
+                  },
+                  "return_data": {
+                    "description": "report the results of the query, false for the queries only used in the expressions",
+                    "type": "boolean"
+                  },
+                  "label_dimension": {
+                    "description": "the dimension the results are tagged with their label, for the expressions returning several series",
+                    "type": "string",
+                    "minLength": 1,
+                    "maxLength": 255
+                  }
+                },
+                "required": [
+                  "id"
+                ],
+                "oneOf": [
+                  {
+                    "required": [
+                      "expression"
+                    ]
+                  },
+                  {
+                    "required": [
+                      "namespace",
+                      "metric_name"
+                    ]
+                  }
+                ],
+                "additionalProperties": false
+              }
+            }
+          },
+          "required": [
+            "queries"
+          ],
+          "additionalProperties": false
+        },
+        "swapDefinitions": {
           "$ref": "#/definitions/metricsDefinition/definitions/basicMetricDefinition"
         },
         "memDefinitions": {
-          "$ref": "#/definitions/metricsDefinition/definitions/basicMetricDefinition"
+          "type": "object",
+          "allOf": [
+            {
+              "$ref": "#/definitions/metricsDefinition/definitions/basicMetricDefinition"
+            },
+            {
+              "type": "object",
+              "properties": {
+                "numa": {
+                  "description": "report the numa_ metrics of the measurement for each NUMA node, linux only",
+                  "type": "boolean"
+                }
+              }
+            }
+          ]
         },
         "netDefinitions": {
           "type": "object",
@@ -383,15 +1225,57 @@ var schema = `{
             },
             {
               "$ref": "#/definitions/metricsDefinition/definitions/basicResourcesDefinition"
+            },
+            {
+              "type": "object",
+              "properties": {
+                "report_rates": {
+                  "description": "report the counters as rates per second, the metrics with the _per_sec suffix, instead of deltas, false by default",
+                  "type": "boolean"
+                }
+              }
             }
           ]
         },
         "netstatDefinitions": {
-          "$ref": "#/definitions/metricsDefinition/definitions/basicMetricDefinition"
+          "allOf": [
+            {
+              "$ref": "#/definitions/metricsDefinition/definitions/basicMetricDefinition"
+            },
+            {
+              "properties": {
+                "ports": {
+                  "description": "Linux only, the local ports the tcp connection states are also reported for, with the port dimension",
+                  "type": "array",
+                  "items": {
+                    "type": "integer",
+                    "minimum": 1,
+                    "maximum": 65535
+                  },
+                  "minItems": 1,
+                  "maxItems": 64,
+                  "uniqueItems": true
+                },
+                "by_process": {
+                  "description": "Linux only, also report the tcp connection states by owning process, with the process_name dimension, false by default",
+                  "type": "boolean"
+                }
+              }
+            }
+          ]
         },
         "processesDefinitions": {
           "$ref": "#/definitions/metricsDefinition/definitions/basicMetricDefinition"
         },
+        "sensorsDefinitions": {
+          "$ref": "#/definitions/metricsDefinition/definitions/basicMetricDefinition"
+        },
+        "windowsGPUDefinitions": {
+          "$ref": "#/definitions/metricsDefinition/definitions/basicMetricDefinition"
+        },
+        "cpuClusterDefinitions": {
+          "$ref": "#/definitions/metricsDefinition/definitions/basicMetricDefinition"
+        },
         "procstatDefinitions": {
           "type": "array",
           "minItems": 1,
@@ -406,91 +1290,703 @@ var schema = `{
                 "properties": {
                   "pid_file": {
                     "type": "string",
-                    "minLength": 1,
-                    "maxLength": 255,
-                    "descriptions": "the path of pid_file"
+                    "minLength": 1,
+                    "maxLength": 255,
+                    "descriptions": "the path of pid_file"
+                  },
+                  "exe": {
+                    "type": "string",
+                    "minLength": 1,
+                    "maxLength": 255,
+                    "descriptions": "a regex matches the names of processes"
+                  },
+                  "pattern": {
+                    "type": "string",
+                    "minLength": 1,
+                    "maxLength": 255,
+                    "descriptions": "a regex matches the whole command of processes"
+                  },
+                  "systemd_unit": {
+                    "type": "string",
+                    "minLength": 1,
+                    "maxLength": 255,
+                    "descriptions": "the systemd unit whose processes are matched, e.g. nginx.service"
+                  },
+                  "cgroup": {
+                    "type": "string",
+                    "minLength": 1,
+                    "maxLength": 1024,
+                    "descriptions": "the cgroup whose processes are matched, a path or a name relative to /sys/fs/cgroup, e.g. systemd/system.slice/app.service"
+                  },
+                  "service_name": {
+                    "type": "string",
+                    "minLength": 1,
+                    "maxLength": 256,
+                    "descriptions": "the name of the Windows service whose process is matched, e.g. W3SVC"
+                  },
+                  "environment_pattern": {
+                    "type": "string",
+                    "minLength": 1,
+                    "maxLength": 1024,
+                    "descriptions": "the regular expression an environment variable of the processes of the selector matches, as NAME=value, linux only, e.g. ^APP_NAME=payments$"
+                  },
+                  "container_name_pattern": {
+                    "type": "string",
+                    "minLength": 1,
+                    "maxLength": 1024,
+                    "descriptions": "the regular expression the name of the container of the processes of the selector matches, linux only, e.g. ^payments-"
+                  },
+                  "container_labels": {
+                    "type": "object",
+                    "descriptions": "the labels the container of the processes of the selector has, linux only",
+                    "additionalProperties": {
+                      "type": "string"
+                    },
+                    "minProperties": 1
+                  },
+                  "container_endpoint": {
+                    "type": "string",
+                    "minLength": 1,
+                    "descriptions": "the Docker Engine API listing the containers, unix:///var/run/docker.sock by default"
+                  },
+                  "dimensions": {
+                    "type": "array",
+                    "descriptions": "the dimensions of the metrics instead of process_name and the tag of the selector, e.g. the user and a hash of the command line when many instances of a process run",
+                    "items": {
+                      "type": "string",
+                      "enum": [
+                        "process_name",
+                        "user",
+                        "pid",
+                        "exe_path",
+                        "cmdline_hash",
+                        "pattern_hash",
+                        "pidfile",
+                        "exe",
+                        "pattern",
+                        "systemd_unit",
+                        "cgroup",
+                        "win_service",
+                        "environment_pattern",
+                        "container_name_pattern",
+                        "container_name",
+                        "container_id"
+                      ]
+                    },
+                    "uniqueItems": true
+                  },
+                  "measurement": {
+                    "$ref": "#/definitions/metricsDefinition/definitions/metricsMeasurementWithoutDecorationDefinition"
+                  },
+                  "recovery": {
+                    "type": "object",
+                    "descriptions": "the recovery action run when no process matches the target",
+                    "properties": {
+                      "restart_service": {
+                        "type": "string",
+                        "minLength": 1,
+                        "maxLength": 255,
+                        "descriptions": "the Windows service or systemd unit restarted"
+                      },
+                      "command": {
+                        "type": "string",
+                        "minLength": 1,
+                        "maxLength": 1024,
+                        "descriptions": "the command run"
+                      },
+                      "min_interval": {
+                        "$ref": "#/definitions/timeIntervalDefinition"
+                      },
+                      "max_attempts": {
+                        "type": "integer",
+                        "minimum": 1,
+                        "maximum": 100,
+                        "descriptions": "the maximum number of attempts while the target stays down"
+                      }
+                    },
+                    "anyOf": [
+                      {
+                        "required": [
+                          "restart_service"
+                        ]
+                      },
+                      {
+                        "required": [
+                          "command"
+                        ]
+                      }
+                    ],
+                    "additionalProperties": false
+                  }
+                },
+                "anyOf": [
+                  {
+                    "required": [
+                      "pid_file"
+                    ]
+                  },
+                  {
+                    "required": [
+                      "exe"
+                    ]
+                  },
+                  {
+                    "required": [
+                      "pattern"
+                    ]
+                  },
+                  {
+                    "required": [
+                      "systemd_unit"
+                    ]
+                  },
+                  {
+                    "required": [
+                      "cgroup"
+                    ]
+                  },
+                  {
+                    "required": [
+                      "service_name"
+                    ]
+                  }
+                ]
+              }
+            ]
+          }
+        },
+        "ethtoolDefinitions": {
+          "type": "object",
+          "properties": {
+            "interface_include": {
+              "type": "array",
+              "items": {
+                "type": "string",
+                "minLength": 1,
+                "maxLength": 255
+              }
+            },
+            "interface_exclude": {
+              "type": "array",
+              "items": {
+                "type": "string",
+                "minLength": 1,
+                "maxLength": 255
+              }
+            },
+            "metrics_include": {
+              "type": "array",
+              "items": {
+                "type": "string",
+                "minLength": 1,
+                "maxLength": 255
+              }
+            }
+          },
+          "additionalProperties": false
+        },
+        "nvidiaGpuDefinitions": {
+          "type": "object",
+          "properties": {
+            "measurement": {
+              "type": "array",
+              "items": {
+                "type": "string",
+                "minLength": 1,
+                "maxLength": 255
+              }
+            }
+          },
+          "metrics_collection_interval": {
+            "$ref": "#/definitions/timeIntervalDefinition"
+          }
+        },
+        "timesyncDefinitions": {
+          "type": "object",
+          "allOf": [
+            {
+              "$ref": "#/definitions/metricsDefinition/definitions/basicMetricDefinition"
+            },
+            {
+              "type": "object",
+              "properties": {
+                "source": {
+                  "description": "the time synchronization daemon queried, auto uses chrony if chronyc is installed and ntp otherwise",
+                  "type": "string",
+                  "enum": [
+                    "auto",
+                    "chrony",
+                    "ntp"
+                  ]
+                },
+                "timeout": {
+                  "description": "the timeout of chronyc and ntpq, unit is second, the exec timeout of the agent by default",
+                  "$ref": "#/definitions/timeoutDefinition"
+                }
+              }
+            }
+          ]
+        },
+        "systemdDefinitions": {
+          "type": "object",
+          "allOf": [
+            {
+              "$ref": "#/definitions/metricsDefinition/definitions/basicMetricDefinition"
+            },
+            {
+              "type": "object",
+              "properties": {
+                "units": {
+                  "description": "the systemd units reported, a unit without a type suffix is a service",
+                  "type": "array",
+                  "items": {
+                    "type": "string",
+                    "minLength": 1
+                  },
+                  "minItems": 1,
+                  "uniqueItems": true
+                },
+                "timeout": {
+                  "description": "the timeout of systemctl, unit is second, the exec timeout of the agent by default",
+                  "$ref": "#/definitions/timeoutDefinition"
+                }
+              },
+              "required": [
+                "units"
+              ]
+            }
+          ]
+        },
+        "cgroupDefinitions": {
+          "type": "object",
+          "allOf": [
+            {
+              "$ref": "#/definitions/metricsDefinition/definitions/basicMetricDefinition"
+            },
+            {
+              "type": "object",
+              "properties": {
+                "paths": {
+                  "description": "the cgroups reported, as paths relative to the cgroup mount or systemd slices, globs accepted",
+                  "type": "array",
+                  "items": {
+                    "type": "string",
+                    "minLength": 1
+                  },
+                  "minItems": 1,
+                  "uniqueItems": true
+                },
+                "cgroup_root": {
+                  "description": "the mount of the cgroup filesystem, /sys/fs/cgroup by default",
+                  "type": "string",
+                  "pattern": "^/.*$"
+                }
+              },
+              "required": [
+                "paths"
+              ]
+            }
+          ]
+        },
+        "fdDefinitions": {
+          "type": "object",
+          "allOf": [
+            {
+              "$ref": "#/definitions/metricsDefinition/definitions/basicMetricDefinition"
+            },
+            {
+              "type": "object",
+              "properties": {
+                "top_processes": {
+                  "description": "the number of processes reported, the ones closest to their open files limit, 5 by default",
+                  "type": "integer",
+                  "minimum": 0,
+                  "maximum": 100
+                }
+              }
+            }
+          ]
+        },
+        "kernelDefinitions": {
+          "type": "object",
+          "allOf": [
+            {
+              "$ref": "#/definitions/metricsDefinition/definitions/basicMetricDefinition"
+            },
+            {
+              "type": "object",
+              "properties": {
+                "report_deltas": {
+                  "description": "report the drop and pressure counters as deltas, true by default",
+                  "type": "boolean"
+                },
+                "per_irq": {
+                  "description": "report the interrupts per second of each IRQ, tagged with irq and device, false by default",
+                  "type": "boolean"
+                }
+              }
+            }
+          ]
+        },
+        "topProcessesDefinitions": {
+          "type": "object",
+          "allOf": [
+            {
+              "$ref": "#/definitions/metricsDefinition/definitions/basicMetricDefinition"
+            },
+            {
+              "type": "object",
+              "properties": {
+                "top_n": {
+                  "description": "the number of processes reported for each criterion of rank_by, 5 by default",
+                  "type": "integer",
+                  "minimum": 1,
+                  "maximum": 50
+                },
+                "rank_by": {
+                  "description": "the criteria ranking the processes, the processes among the top_n of one of them are reported, cpu and memory by default",
+                  "type": "array",
+                  "items": {
+                    "type": "string",
+                    "enum": [
+                      "cpu",
+                      "memory"
+                    ]
+                  },
+                  "minItems": 1,
+                  "uniqueItems": true
+                }
+              }
+            }
+          ]
+        },
+        "nodeExporterDefinitions": {
+          "type": "object",
+          "allOf": [
+            {
+              "$ref": "#/definitions/metricsDefinition/definitions/basicMetricDefinition"
+            },
+            {
+              "type": "object",
+              "properties": {
+                "url": {
+                  "description": "the metrics endpoint of the node_exporter, http://localhost:9100/metrics by default",
+                  "type": "string",
+                  "pattern": "^https?://.+$"
+                },
+                "resources": {
+                  "description": "\"*\" reports the cpu metrics of each core besides cpu-total",
+                  "type": "array",
+                  "maxItems": 1,
+                  "items": {
+                    "type": "string",
+                    "enum": [
+                      "*"
+                    ]
+                  }
+                }
+              }
+            }
+          ]
+        },
+        "dockerDefinitions": {
+          "type": "object",
+          "allOf": [
+            {
+              "$ref": "#/definitions/metricsDefinition/definitions/basicMetricDefinition"
+            },
+            {
+              "type": "object",
+              "properties": {
+                "endpoint": {
+                  "description": "the Docker Engine API endpoint, unix:///path/to/docker.sock or tcp://host:port",
+                  "type": "string",
+                  "pattern": "^(unix|tcp)://.+$"
+                },
+                "container_name_include": {
+                  "description": "the names of the containers reported, globs accepted",
+                  "type": "array",
+                  "items": {
+                    "type": "string",
+                    "minLength": 1
+                  },
+                  "uniqueItems": true
+                },
+                "container_name_exclude": {
+                  "description": "the names of the containers not reported, globs accepted",
+                  "type": "array",
+                  "items": {
+                    "type": "string",
+                    "minLength": 1
+                  },
+                  "uniqueItems": true
+                }
+              }
+            }
+          ]
+        },
+        "containerdDefinitions": {
+          "type": "object",
+          "allOf": [
+            {
+              "$ref": "#/definitions/metricsDefinition/definitions/basicMetricDefinition"
+            },
+            {
+              "type": "object",
+              "properties": {
+                "endpoint": {
+                  "description": "the containerd socket serving the CRI API, unix:///path/to/containerd.sock",
+                  "type": "string",
+                  "pattern": "^unix://.+$"
+                },
+                "container_name_include": {
+                  "description": "the names of the containers reported, globs accepted",
+                  "type": "array",
+                  "items": {
+                    "type": "string",
+                    "minLength": 1
+                  },
+                  "uniqueItems": true
+                },
+                "container_name_exclude": {
+                  "description": "the names of the containers not reported, globs accepted",
+                  "type": "array",
+                  "items": {
+                    "type": "string",
+                    "minLength": 1
+                  },
+                  "uniqueItems": true
+                }
+              }
+            }
+          ]
+        },
+        "kafkaDefinitions": {
+          "type": "object",
+          "allOf": [
+            {
+              "$ref": "#/definitions/metricsDefinition/definitions/basicMetricDefinition"
+            },
+            {
+              "type": "object",
+              "properties": {
+                "brokers": {
+                  "description": "the bootstrap brokers of the Kafka cluster, host:port",
+                  "type": "array",
+                  "items": {
+                    "type": "string",
+                    "pattern": "^.+:[0-9]+$"
+                  },
+                  "minItems": 1,
+                  "uniqueItems": true
+                },
+                "consumer_groups": {
+                  "description": "the consumer groups whose lag is reported, globs accepted",
+                  "type": "array",
+                  "items": {
+                    "type": "string",
+                    "minLength": 1
+                  },
+                  "uniqueItems": true
+                },
+                "topics": {
+                  "description": "the topics reported, globs accepted",
+                  "type": "array",
+                  "items": {
+                    "type": "string",
+                    "minLength": 1
+                  },
+                  "uniqueItems": true
+                },
+                "tls": {
+                  "description": "connect to the brokers with TLS",
+                  "type": "boolean"
+                }
+              }
+            }
+          ]
+        },
+        "haproxyDefinitions": {
+          "type": "object",
+          "allOf": [
+            {
+              "$ref": "#/definitions/metricsDefinition/definitions/basicMetricDefinition"
+            },
+            {
+              "type": "object",
+              "properties": {
+                "servers": {
+                  "description": "the stats sockets unix:///path or tcp://host:port, or the urls of the stats pages of HAProxy",
+                  "type": "array",
+                  "items": {
+                    "type": "string",
+                    "pattern": "^(unix|tcp|https?)://.+$"
                   },
-                  "exe": {
+                  "minItems": 1,
+                  "uniqueItems": true
+                },
+                "username": {
+                  "description": "the username of the stats pages",
+                  "type": "string"
+                },
+                "password": {
+                  "description": "the password of the stats pages",
+                  "type": "string"
+                },
+                "proxies": {
+                  "description": "the proxies reported, globs accepted",
+                  "type": "array",
+                  "items": {
                     "type": "string",
-                    "minLength": 1,
-                    "maxLength": 255,
-                    "descriptions": "a regex matches the names of processes"
+                    "minLength": 1
                   },
-                  "pattern": {
+                  "uniqueItems": true
+                }
+              }
+            }
+          ]
+        },
+        "rabbitmqDefinitions": {
+          "type": "object",
+          "allOf": [
+            {
+              "$ref": "#/definitions/metricsDefinition/definitions/basicMetricDefinition"
+            },
+            {
+              "type": "object",
+              "properties": {
+                "url": {
+                  "description": "the url of the management plugin of RabbitMQ",
+                  "type": "string",
+                  "pattern": "^https?://.+$"
+                },
+                "username": {
+                  "description": "the user of the management API, with the monitoring tag",
+                  "type": "string"
+                },
+                "password": {
+                  "description": "the password of the user",
+                  "type": "string"
+                },
+                "vhosts": {
+                  "description": "the vhosts reported, globs accepted",
+                  "type": "array",
+                  "items": {
                     "type": "string",
-                    "minLength": 1,
-                    "maxLength": 255,
-                    "descriptions": "a regex matches the whole command of processes"
+                    "minLength": 1
                   },
-                  "measurement": {
-                    "$ref": "#/definitions/metricsDefinition/definitions/metricsMeasurementWithoutDecorationDefinition"
-                  }
+                  "uniqueItems": true
                 },
-                "anyOf": [
-                  {
-                    "required": [
-                      "pid_file"
-                    ]
+                "queue_name_include": {
+                  "description": "the queues reported, globs accepted",
+                  "type": "array",
+                  "items": {
+                    "type": "string",
+                    "minLength": 1
                   },
-                  {
-                    "required": [
-                      "exe"
-                    ]
+                  "uniqueItems": true
+                },
+                "queue_name_exclude": {
+                  "description": "the queues not reported, globs accepted",
+                  "type": "array",
+                  "items": {
+                    "type": "string",
+                    "minLength": 1
                   },
-                  {
-                    "required": [
-                      "pattern"
-                    ]
-                  }
-                ]
+                  "uniqueItems": true
+                }
               }
-            ]
-          }
+            }
+          ]
         },
-        "ethtoolDefinitions": {
-          "type": "object",
-          "properties": {
-            "interface_include": {
-              "type": "array",
-              "items": {
-                "type": "string",
-                "minLength": 1,
-                "maxLength": 255
-              }
-            },
-            "interface_exclude": {
-              "type": "array",
-              "items": {
+        "jmxDefinitions": {
+          "type": "array",
+          "minItems": 1,
+          "maxItems": 255,
+          "items": {
+            "type": "object",
+            "properties": {
+              "endpoint": {
                 "type": "string",
                 "minLength": 1,
-                "maxLength": 255
-              }
-            },
-            "metrics_include": {
-              "type": "array",
-              "items": {
+                "maxLength": 1024,
+                "description": "the url of the Jolokia agent of the JVM, defaults to http://localhost:8778/jolokia"
+              },
+              "username": {
                 "type": "string",
                 "minLength": 1,
                 "maxLength": 255
-              }
-            }
-          },
-          "additionalProperties": false
-        },
-        "nvidiaGpuDefinitions": {
-          "type": "object",
-          "properties": {
-            "measurement": {
-              "type": "array",
-              "items": {
+              },
+              "password": {
                 "type": "string",
                 "minLength": 1,
                 "maxLength": 255
+              },
+              "response_timeout": {
+                "$ref": "#/definitions/timeIntervalDefinition"
+              },
+              "jvm_metrics": {
+                "description": "the predefined JVM health metrics, defaults to all of them",
+                "type": "array",
+                "items": {
+                  "type": "string",
+                  "enum": [
+                    "heap",
+                    "gc",
+                    "threads"
+                  ]
+                },
+                "uniqueItems": true
+              },
+              "mbeans": {
+                "description": "the user specified MBean attributes to collect",
+                "type": "array",
+                "maxItems": 255,
+                "items": {
+                  "type": "object",
+                  "properties": {
+                    "name": {
+                      "type": "string",
+                      "minLength": 1,
+                      "maxLength": 255
+                    },
+                    "mbean": {
+                      "type": "string",
+                      "minLength": 1,
+                      "maxLength": 1024
+                    },
+                    "paths": {
+                      "type": "array",
+                      "items": {
+                        "type": "string",
+                        "minLength": 1,
+                        "maxLength": 255
+                      }
+                    },
+                    "tag_keys": {
+                      "type": "array",
+                      "items": {
+                        "type": "string",
+                        "minLength": 1,
+                        "maxLength": 255
+                      }
+                    }
+                  },
+                  "required": [
+                    "name",
+                    "mbean"
+                  ],
+                  "additionalProperties": false
+                }
+              },
+              "metrics_collection_interval": {
+                "$ref": "#/definitions/timeIntervalDefinition"
+              },
+              "append_dimensions": {
+                "$ref": "#/definitions/generalAppendDimensionsDefinition"
               }
-            }
-          },
-          "metrics_collection_interval": {
-            "$ref": "#/definitions/timeIntervalDefinition"
+            },
+            "additionalProperties": false
           }
         },
         "metricsMeasurementWithoutDecorationDefinition": {
@@ -544,6 +2040,9 @@ var schema = `{
         "logs_collected": {
           "type": "object",
           "properties": {
+            "agent_audit": {
+              "$ref": "#/definitions/logsDefinition/definitions/logsAgentAuditDefinition"
+            },
             "files": {
               "$ref": "#/definitions/logsDefinition/definitions/logsFilesDefinition"
             },
@@ -557,6 +2056,12 @@ var schema = `{
         "metrics_collected": {
           "type": "object",
           "properties": {
+            "emf": {
+              "$ref": "#/definitions/emfListenerDefinition"
+            },
+            "structuredlog": {
+              "$ref": "#/definitions/emfListenerDefinition"
+            },
             "ecs": {
               "type": "object",
               "properties": {
@@ -597,6 +2102,9 @@ var schema = `{
                 },
                 "ecs_service_discovery": {
                   "$ref": "#/definitions/ecsServiceDiscoveryDefinition"
+                },
+                "ec2_service_discovery": {
+                  "$ref": "#/definitions/ec2ServiceDiscoveryDefinition"
                 }
               },
               "additionalProperties": false
@@ -611,6 +2119,50 @@ var schema = `{
           "description": "Max time to wait before batch publishing the log, unit is second.",
           "$ref": "#/definitions/timeIntervalDefinition"
         },
+        "retention_check_interval": {
+          "description": "Interval of the checks of the retention of the log groups with a retention_in_days, a retention changed out of the agent is reported as a drift, unit is second.",
+          "$ref": "#/definitions/timeIntervalDefinition"
+        },
+        "retention_restore": {
+          "description": "Restore the retention_in_days of the log groups when their retention was changed out of the agent.",
+          "type": "boolean"
+        },
+        "rejected_log_events_file": {
+          "description": "The file the log events rejected by CloudWatch Logs as expired or too old are appended to as json lines, instead of being discarded.",
+          "type": "string",
+          "minLength": 1
+        },
+        "max_retries": {
+          "description": "The attempts of the PutLogEvents requests, a request is retried for 14 days by default",
+          "type": "integer",
+          "minimum": 1,
+          "maximum": 100
+        },
+        "max_backoff": {
+          "description": "The maximum backoff in seconds between the attempts of a request, 60 by default",
+          "type": "integer",
+          "minimum": 1,
+          "maximum": 3600
+        },
+        "max_requests_per_second": {
+          "description": "The attempts per second of the PutLogEvents requests of all the log streams, unlimited by default, the rate is halved while the requests are throttled",
+          "type": "number",
+          "minimum": 0,
+          "exclusiveMinimum": true
+        },
+        "log_name_replacement": {
+          "description": "The replacement of the characters CloudWatch Logs rejects in the log group and log stream names once their placeholders are resolved, _ by default.",
+          "type": "string",
+          "pattern": "^[._\\-/#A-Za-z0-9]{1,8}$"
+        },
+        "log_name_overflow": {
+          "description": "How the log group and log stream names longer than 512 characters are shortened, truncated and suffixed with a hash of the whole name, or only truncated.",
+          "type": "string",
+          "enum": [
+            "hash",
+            "truncate"
+          ]
+        },
         "credentials": {
           "description": "The credentials with which agent can access aws resources",
           "$ref": "#/definitions/credentialsDefinition"
@@ -618,6 +2170,10 @@ var schema = `{
         "endpoint_override": {
           "description": "The override endpoint to use to access cloudwatch logs",
           "$ref": "#/definitions/endpointOverrideDefinition"
+        },
+        "timeouts": {
+          "description": "The timeouts of the calls to cloudwatch logs, the timeouts of the agent by default",
+          "$ref": "#/definitions/apiTimeoutsDefinition"
         }
       },
       "additionalProperties": false,
@@ -687,6 +2243,16 @@ var schema = `{
                   "publish_multi_logs": {
                     "type": "boolean"
                   },
+                  "log_stream_buckets": {
+                    "description": "the number of log streams the files are published to with publish_multi_logs, instead of one log stream per file",
+                    "type": "integer",
+                    "minimum": 1
+                  },
+                  "priority": {
+                    "description": "the weight of the files among the files of their log stream when the stream is slower than them, 1 by default",
+                    "type": "integer",
+                    "minimum": 1
+                  },
                   "retention_in_days": {
                     "$ref": "#/definitions/logsDefinition/definitions/retentionInDaysDefinition"
                   },
@@ -695,6 +2261,13 @@ var schema = `{
                     "items": {
                       "$ref": "#/definitions/logsDefinition/definitions/filterDefinition"
                     }
+                  },
+                  "log_format": {
+                    "description": "w3c publishes the lines of the W3C extended log files, like the IIS and Exchange logs, as json objects keyed by the #Fields directive",
+                    "type": "string",
+                    "enum": [
+                      "w3c"
+                    ]
                   }
                 },
                 "required": [
@@ -712,6 +2285,25 @@ var schema = `{
           ],
           "additionalProperties": false
         },
+        "logsAgentAuditDefinition": {
+          "type": "object",
+          "descriptions": "Publishes the audit events of the agent configuration, credential and endpoint changes",
+          "properties": {
+            "log_group_name": {
+              "$ref": "#/definitions/logsDefinition/definitions/logGroupNameDefinition"
+            },
+            "log_stream_name": {
+              "$ref": "#/definitions/logsDefinition/definitions/logStreamNameDefinition"
+            },
+            "retention_in_days": {
+              "$ref": "#/definitions/logsDefinition/definitions/retentionInDaysDefinition"
+            }
+          },
+          "required": [
+            "log_group_name"
+          ],
+          "additionalProperties": false
+        },
         "logsWindowsEventsDefinition": {
           "type": "object",
           "descriptions": "Specifies the logs to collect from servers running Windows Server",
@@ -871,11 +2463,123 @@ var schema = `{
       },
       "additionalProperties": false
     },
+    "timeoutDefinition": {
+      "type": "number",
+      "minimum": 0,
+      "exclusiveMinimum": true,
+      "maximum": 3600
+    },
+    "apiTimeoutsDefinition": {
+      "type": "object",
+      "properties": {
+        "api_call": {
+          "description": "The timeout of an API call, from the connection to the end of the response, unit is second, 60 by default",
+          "$ref": "#/definitions/timeoutDefinition"
+        },
+        "dns_resolution": {
+          "description": "The timeout of the resolution of the endpoint, unit is second, 10 by default",
+          "$ref": "#/definitions/timeoutDefinition"
+        }
+      },
+      "additionalProperties": false
+    },
+    "timeoutsDefinition": {
+      "type": "object",
+      "properties": {
+        "api_call": {
+          "description": "The timeout of an API call, from the connection to the end of the response, unit is second, 60 by default",
+          "$ref": "#/definitions/timeoutDefinition"
+        },
+        "dns_resolution": {
+          "description": "The timeout of the resolution of the endpoints, unit is second, 10 by default",
+          "$ref": "#/definitions/timeoutDefinition"
+        },
+        "exec": {
+          "description": "The timeout of the commands run by the systemd and timesync collectors, the health hook and the procstat recovery, unit is second, the default of each of them otherwise",
+          "$ref": "#/definitions/timeoutDefinition"
+        }
+      },
+      "additionalProperties": false
+    },
     "endpointOverrideDefinition": {
       "type": "string",
       "minLength": 4,
       "maxLength": 2048
     },
+    "ec2ServiceDiscoveryDefinition": {
+      "type": "object",
+      "descriptions": "Define EC2 service discovery for Prometheus, the exporters of the instances selected by their tags",
+      "properties": {
+        "instance_list": {
+          "type": "array",
+          "minItems": 1,
+          "items": {
+            "$ref": "#/definitions/ec2ServiceDiscoveryDefinition/definitions/instanceList"
+          }
+        },
+        "sd_frequency": {
+          "description": "EC2 service discovery frequency",
+          "type": "string"
+        },
+        "sd_http_sd_listen_address": {
+          "description": "Local address to serve the discovered targets in the Prometheus http_sd format, e.g. 127.0.0.1:9405. Disabled if not specified",
+          "type": "string"
+        },
+        "sd_region": {
+          "description": "The region of the instances, the region of the agent by default",
+          "type": "string"
+        },
+        "sd_result_file": {
+          "description": "EC2 service discovery result file full path",
+          "type": "string"
+        },
+        "sd_vpc_id": {
+          "description": "The VPC of the instances, the VPC of the agent by default",
+          "type": "string",
+          "pattern": "^vpc-[0-9a-f]+$"
+        }
+      },
+      "required": [
+        "instance_list"
+      ],
+      "additionalProperties": false,
+      "definitions": {
+        "instanceList": {
+          "type": "object",
+          "descriptions": "Define the instances discovered by their tags",
+          "properties": {
+            "sd_instance_tags": {
+              "description": "The tags the instances must all have, the values accept the * and ? wildcards",
+              "type": "object",
+              "minProperties": 1,
+              "maxProperties": 50,
+              "additionalProperties": {
+                "type": "string",
+                "minLength": 1,
+                "maxLength": 256
+              }
+            },
+            "sd_job_name": {
+              "description": "Service discovery result job name",
+              "type": "string"
+            },
+            "sd_metrics_path": {
+              "description": "Prometheus metrics path of the exporters",
+              "type": "string"
+            },
+            "sd_metrics_ports": {
+              "description": "Prometheus metrics port list of the exporters",
+              "type": "string"
+            }
+          },
+          "required": [
+            "sd_instance_tags",
+            "sd_metrics_ports"
+          ],
+          "additionalProperties": false
+        }
+      }
+    },
     "ecsServiceDiscoveryDefinition": {
       "type": "object",
       "descriptions": "Define ECS service discovery for Prometheus",
@@ -903,6 +2607,10 @@ var schema = `{
           "description": "ECS service discovery frequency",
           "type": "string"
         },
+        "sd_http_sd_listen_address": {
+          "description": "Local address to serve the discovered targets in the Prometheus http_sd format, e.g. 127.0.0.1:9405. Disabled if not specified",
+          "type": "string"
+        },
         "sd_result_file": {
           "description": "ECS service discovery result file full path",
           "type": "string"
@@ -986,6 +2694,32 @@ var schema = `{
         }
       }
     },
+    "emfListenerDefinition": {
+      "type": "object",
+      "descriptions": "Define the listener of the EMF events of the applications",
+      "properties": {
+        "service_address": {
+          "description": "The tcp or udp address the EMF events are received on",
+          "type": "string"
+        },
+        "rate_limit": {
+          "description": "The EMF events admitted per second for each client, the others are dropped",
+          "type": "number",
+          "minimum": 0
+        },
+        "rate_limit_burst": {
+          "description": "The EMF events admitted at once above the rate limit",
+          "type": "integer",
+          "minimum": 0
+        },
+        "max_payload_size": {
+          "description": "The maximum size in bytes of an EMF event, the larger ones are dropped",
+          "type": "integer",
+          "minimum": 1,
+          "maximum": 1048576
+        }
+      }
+    },
     "emfProcessorDefinition": {
       "type": "object",
       "descriptions": "Define EMF Processor to set metric filter",
@@ -999,7 +2733,7 @@ var schema = `{
           "type": "string"
         },
         "metric_unit": {
-          "description": "The metric name, metric unit map",
+          "description": "The metric name, metric unit map, the names are exact or globs where * matches any characters, e.g. *_seconds",
           "type": "object",
           "additionalProperties": {
             "type": "string",
@@ -1007,6 +2741,18 @@ var schema = `{
             "maxLength": 256
           }
         },
+        "metric_unit_by_job": {
+          "description": "The prometheus job name, metric unit map of the job map, it overrides metric_unit for the metrics of the job",
+          "type": "object",
+          "additionalProperties": {
+            "type": "object",
+            "additionalProperties": {
+              "type": "string",
+              "minLength": 1,
+              "maxLength": 256
+            }
+          }
+        },
         "metric_declaration": {
           "type": "array",
           "items": {
@@ -39,6 +39,8 @@ type Context struct {
 	inputJsonDirPath    string
 	multiConfig         string
 	outputTomlFilePath  string
+	catalogFilePath     string
+	schemaServerAddress string
 	mode                string
 	credentials         map[string]string
 	proxy               map[string]string
@@ -87,6 +89,22 @@ func (ctx *Context) SetOutputTomlFilePath(outputTomlFilePath string) {
 	ctx.outputTomlFilePath = outputTomlFilePath
 }
 
+func (ctx *Context) CatalogFilePath() string {
+	return ctx.catalogFilePath
+}
+
+func (ctx *Context) SetCatalogFilePath(catalogFilePath string) {
+	ctx.catalogFilePath = catalogFilePath
+}
+
+func (ctx *Context) SchemaServerAddress() string {
+	return ctx.schemaServerAddress
+}
+
+func (ctx *Context) SetSchemaServerAddress(schemaServerAddress string) {
+	ctx.schemaServerAddress = schemaServerAddress
+}
+
 func (ctx *Context) Mode() string {
 	if ctx.mode == "" {
 		ctx.mode = config.ModeEC2
@@ -0,0 +1,224 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+// Package schemaserver serves the json schema of the agent config, so the editors and the config UIs complete and
+// validate the agent configs from the schema of the installed agent instead of a copy of it. The schema is extended
+// with the metric names registered by the plugins of the os, which the embedded schema doesn't list.
+package schemaserver
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aws/amazon-cloudwatch-agent/translator/config"
+	metricsConfig "github.com/aws/amazon-cloudwatch-agent/translator/translate/metrics/config"
+	"github.com/xeipuuv/gojsonschema"
+)
+
+const (
+	schemaPath      = "/schema.json"
+	validatePath    = "/validate"
+	shutdownTimeout = 5 * time.Second
+	// the largest config validated, the agent configs are a few KB
+	maxConfigSize = 4 << 20
+)
+
+// Schema returns the json schema of the agent config for the os, the measurement of each plugin of metrics_collected
+// lists the metric names registered for the plugin as examples, so they are completed without changing the
+// validation.
+func Schema(targetOs string) (map[string]interface{}, error) {
+	var schema map[string]interface{}
+	if err := json.Unmarshal([]byte(config.GetJsonSchema()), &schema); err != nil {
+		return nil, fmt.Errorf("unable to parse the json schema: %v", err)
+	}
+	plugins, ok := lookup(schema, "#/definitions/metricsDefinition/properties/metrics_collected/properties")
+	if !ok {
+		return nil, fmt.Errorf("no metrics_collected in the json schema")
+	}
+
+	registered := registeredMetrics(targetOs)
+	names := make([]string, 0, len(registered))
+	for name := range registered {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		definition, ok := plugins[name].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		extension := map[string]interface{}{
+			"properties": map[string]interface{}{
+				"measurement": map[string]interface{}{
+					"items": map[string]interface{}{"examples": registered[name]},
+				},
+			},
+		}
+		// the plugins with several sections, like procstat, are arrays of them
+		if ref, ok := definition["$ref"].(string); ok {
+			if resolved, ok := lookup(schema, ref); ok && resolved["type"] == "array" {
+				extension = map[string]interface{}{"items": extension}
+			}
+		}
+		plugins[name] = map[string]interface{}{"allOf": []interface{}{definition, extension}}
+	}
+	return schema, nil
+}
+
+func registeredMetrics(targetOs string) map[string][]string {
+	switch targetOs {
+	case config.OS_TYPE_WINDOWS:
+		return metricsConfig.Registered_Metrics_Windows
+	case config.OS_TYPE_DARWIN:
+		return metricsConfig.Registered_Metrics_Darwin
+	default:
+		return metricsConfig.Registered_Metrics_Linux
+	}
+}
+
+// lookup returns the object of the schema at the json pointer of a $ref.
+func lookup(schema map[string]interface{}, ref string) (map[string]interface{}, bool) {
+	current := schema
+	for _, key := range strings.Split(strings.TrimPrefix(ref, "#/"), "/") {
+		next, ok := current[key].(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		current = next
+	}
+	return current, true
+}
+
+// ValidationError is an error of a validated config, at its path in the config.
+type ValidationError struct {
+	Path    string `json:"path"`
+	Message string `json:"message"`
+}
+
+// ValidationResult is the result of the validation of a config.
+type ValidationResult struct {
+	Valid  bool              `json:"valid"`
+	Errors []ValidationError `json:"errors"`
+}
+
+// Server serves the schema of the agent config at /schema.json, and validates the configs posted to /validate:
+//
+//	"json.schemas": [{"fileMatch": ["amazon-cloudwatch-agent.json"], "url": "http://localhost:8888/schema.json"}]
+//
+// The os of the schema is the one of the server, or the os query parameter, e.g. /schema.json?os=windows.
+type Server struct {
+	targetOs string
+	server   *http.Server
+}
+
+func NewServer(address string, targetOs string) *Server {
+	s := &Server{targetOs: targetOs}
+	mux := http.NewServeMux()
+	mux.HandleFunc(schemaPath, s.handleSchema)
+	mux.HandleFunc(validatePath, s.handleValidate)
+	s.server = &http.Server{Addr: address, Handler: mux}
+	return s
+}
+
+// schema returns the schema of the os of the request, it writes the error response when it fails.
+func (s *Server) schema(w http.ResponseWriter, r *http.Request) (map[string]interface{}, bool) {
+	targetOs := s.targetOs
+	if os := strings.ToLower(r.URL.Query().Get("os")); os != "" {
+		if os != config.OS_TYPE_LINUX && os != config.OS_TYPE_WINDOWS && os != config.OS_TYPE_DARWIN {
+			http.Error(w, fmt.Sprintf("unsupported os %q", os), http.StatusBadRequest)
+			return nil, false
+		}
+		targetOs = os
+	}
+	schema, err := Schema(targetOs)
+	if err != nil {
+		log.Printf("E! Schema server fails to build the json schema: %v\n", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return nil, false
+	}
+	return schema, true
+}
+
+func (s *Server) handleSchema(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	schema, ok := s.schema(w, r)
+	if !ok {
+		return
+	}
+	writeJson(w, schema)
+}
+
+func (s *Server) handleValidate(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	body, err := ioutil.ReadAll(http.MaxBytesReader(w, r.Body, maxConfigSize))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
+		return
+	}
+	var input interface{}
+	if err := json.Unmarshal(body, &input); err != nil {
+		http.Error(w, fmt.Sprintf("invalid json config: %v", err), http.StatusBadRequest)
+		return
+	}
+	schema, ok := s.schema(w, r)
+	if !ok {
+		return
+	}
+	result, err := gojsonschema.Validate(gojsonschema.NewGoLoader(schema), gojsonschema.NewGoLoader(input))
+	if err != nil {
+		log.Printf("E! Schema server fails to validate the config: %v\n", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	res := ValidationResult{Valid: result.Valid(), Errors: []ValidationError{}}
+	for _, e := range result.Errors() {
+		res.Errors = append(res.Errors, ValidationError{Path: config.GetFormattedPath(e.Context().String()), Message: e.Description()})
+	}
+	writeJson(w, res)
+}
+
+func writeJson(w http.ResponseWriter, v interface{}) {
+	body, err := json.Marshal(v)
+	if err != nil {
+		log.Printf("E! Schema server fails to marshal the response: %v\n", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(body)
+}
+
+// Start listens on the address and serves the requests in the background.
+func (s *Server) Start() error {
+	listener, err := net.Listen("tcp", s.server.Addr)
+	if err != nil {
+		return err
+	}
+	log.Printf("I! Schema server is listening on http://%v%v\n", listener.Addr(), schemaPath)
+	go func() {
+		if err := s.server.Serve(listener); err != nil && err != http.ErrServerClosed {
+			log.Printf("E! Schema server stopped: %v\n", err)
+		}
+	}()
+	return nil
+}
+
+func (s *Server) Stop() {
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	s.server.Shutdown(ctx)
+}
@@ -0,0 +1,80 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package schemaserver
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/aws/amazon-cloudwatch-agent/translator/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchema(t *testing.T) {
+	schema, err := Schema(config.OS_TYPE_LINUX)
+	require.NoError(t, err)
+	plugins, ok := lookup(schema, "#/definitions/metricsDefinition/properties/metrics_collected/properties")
+	require.True(t, ok)
+
+	cpu := plugins["cpu"].(map[string]interface{})["allOf"].([]interface{})
+	assert.Equal(t, map[string]interface{}{"$ref": "#/definitions/metricsDefinition/definitions/cpuDefinitions"}, cpu[0])
+	examples := cpu[1].(map[string]interface{})["properties"].(map[string]interface{})["measurement"].(map[string]interface{})["items"].(map[string]interface{})["examples"]
+	assert.Contains(t, examples, "usage_idle")
+
+	// the procstat sections are an array
+	procstat := plugins["procstat"].(map[string]interface{})["allOf"].([]interface{})
+	_, ok = procstat[1].(map[string]interface{})["items"]
+	assert.True(t, ok)
+}
+
+func TestServer_Schema(t *testing.T) {
+	s := NewServer("", config.OS_TYPE_LINUX)
+
+	w := httptest.NewRecorder()
+	s.server.Handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, schemaPath+"?os=windows", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+	var schema map[string]interface{}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &schema))
+	assert.Equal(t, "Amazon CloudWatch Agent JSON Schema", schema["description"])
+
+	w = httptest.NewRecorder()
+	s.server.Handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, schemaPath+"?os=plan9", nil))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = httptest.NewRecorder()
+	s.server.Handler.ServeHTTP(w, httptest.NewRequest(http.MethodPost, schemaPath, nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+}
+
+func TestServer_Validate(t *testing.T) {
+	s := NewServer("", config.OS_TYPE_LINUX)
+	validate := func(body string) (int, ValidationResult) {
+		w := httptest.NewRecorder()
+		s.server.Handler.ServeHTTP(w, httptest.NewRequest(http.MethodPost, validatePath, strings.NewReader(body)))
+		var res ValidationResult
+		if w.Code == http.StatusOK {
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+		}
+		return w.Code, res
+	}
+
+	code, res := validate(`{"metrics": {"metrics_collected": {"cpu": {"measurement": ["usage_idle"]}}}}`)
+	assert.Equal(t, http.StatusOK, code)
+	assert.True(t, res.Valid)
+	assert.Empty(t, res.Errors)
+
+	code, res = validate(`{"agent": {"metrics_collection_interval": "60"}}`)
+	assert.Equal(t, http.StatusOK, code)
+	assert.False(t, res.Valid)
+	require.NotEmpty(t, res.Errors)
+	assert.Equal(t, "/agent/metrics_collection_interval", res.Errors[0].Path)
+
+	code, _ = validate(`{"agent":`)
+	assert.Equal(t, http.StatusBadRequest, code)
+}
@@ -19,6 +19,8 @@ const (
 	userAgentKey      = "user_agent"
 	debugKey          = "debug"
 	awsSdkLogLevelKey = "aws_sdk_log_level"
+	sidecarKey        = "sidecar"
+	endpointModeKey   = "endpoint_mode"
 )
 
 func ToEnvConfig(jsonConfigValue map[string]interface{}) []byte {
@@ -40,6 +42,14 @@ func ToEnvConfig(jsonConfigValue map[string]interface{}) []byte {
 		if awsSdkLogLevel, ok := agentMap[awsSdkLogLevelKey].(string); ok {
 			envVars[envconfig.AWS_SDK_LOG_LEVEL] = awsSdkLogLevel
 		}
+		// Set CWAGENT_SIDECAR to TRUE in env config if present and true in agent section
+		if isSidecar, ok := agentMap[sidecarKey].(bool); ok && isSidecar {
+			envVars[envconfig.CWAGENT_SIDECAR] = "TRUE"
+		}
+		// Set CWAGENT_ENDPOINT_MODE to env config if specified by the json config in agent section
+		if endpointMode, ok := agentMap[endpointModeKey].(string); ok {
+			envVars[envconfig.CWAGENT_ENDPOINT_MODE] = endpointMode
+		}
 	}
 
 	proxy := util.GetHttpProxy(context.CurrentContext().Proxy())
@@ -81,6 +81,22 @@ func TestCompleteConfig(t *testing.T) {
 	checkIfTranslateSucceed(t, ReadFromFile("../totomlconfig/sampleConfig/complete_windows_config.json"), "windows", expectedEnvVars)
 }
 
+func TestSidecarConfig(t *testing.T) {
+	resetContext()
+	expectedEnvVars := map[string]string{
+		"CWAGENT_SIDECAR": "TRUE",
+	}
+	checkIfTranslateSucceed(t, `{"agent":{"sidecar":true},"logs":{"logs_collected":{"files":{"collect_list":[{"file_path":"/var/log/app/*.log"}]}}}}`, "linux", expectedEnvVars)
+}
+
+func TestEndpointModeConfig(t *testing.T) {
+	resetContext()
+	expectedEnvVars := map[string]string{
+		"CWAGENT_ENDPOINT_MODE": "fips",
+	}
+	checkIfTranslateSucceed(t, `{"agent":{"endpoint_mode":"fips"},"metrics":{"metrics_collected":{"mem":{"measurement":["used_percent"]}}}}`, "linux", expectedEnvVars)
+}
+
 func TestWindowsEventOnlyConfig(t *testing.T) {
 	resetContext()
 	expectedEnvVars := map[string]string{}
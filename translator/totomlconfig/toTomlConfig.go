@@ -9,9 +9,12 @@ import (
 
 	"github.com/aws/amazon-cloudwatch-agent/translator/translate"
 	_ "github.com/aws/amazon-cloudwatch-agent/translator/translate/agent"
+	_ "github.com/aws/amazon-cloudwatch-agent/translator/translate/canary"
 	_ "github.com/aws/amazon-cloudwatch-agent/translator/translate/csm"
 	_ "github.com/aws/amazon-cloudwatch-agent/translator/translate/globaltags"
+	_ "github.com/aws/amazon-cloudwatch-agent/translator/translate/healthhook"
 	_ "github.com/aws/amazon-cloudwatch-agent/translator/translate/logs"
+	_ "github.com/aws/amazon-cloudwatch-agent/translator/translate/logs/logs_collected/agent_audit"
 	_ "github.com/aws/amazon-cloudwatch-agent/translator/translate/logs/logs_collected/files"
 	_ "github.com/aws/amazon-cloudwatch-agent/translator/translate/logs/logs_collected/files/collect_list"
 	_ "github.com/aws/amazon-cloudwatch-agent/translator/translate/logs/logs_collected/windows_events"
@@ -25,29 +28,57 @@ import (
 	_ "github.com/aws/amazon-cloudwatch-agent/translator/translate/logs/metrics_collected/kubernetes/k8sapiserver"
 	_ "github.com/aws/amazon-cloudwatch-agent/translator/translate/logs/metrics_collected/kubernetes/k8sdecorator"
 	_ "github.com/aws/amazon-cloudwatch-agent/translator/translate/logs/metrics_collected/prometheus"
+	_ "github.com/aws/amazon-cloudwatch-agent/translator/translate/logs/metrics_collected/prometheus/ec2servicediscovery"
+	_ "github.com/aws/amazon-cloudwatch-agent/translator/translate/logs/metrics_collected/prometheus/ec2servicediscovery/instance"
 	_ "github.com/aws/amazon-cloudwatch-agent/translator/translate/logs/metrics_collected/prometheus/ecsservicediscovery"
 	_ "github.com/aws/amazon-cloudwatch-agent/translator/translate/logs/metrics_collected/prometheus/ecsservicediscovery/dockerlabel"
 	_ "github.com/aws/amazon-cloudwatch-agent/translator/translate/logs/metrics_collected/prometheus/ecsservicediscovery/serviceendpoint"
 	_ "github.com/aws/amazon-cloudwatch-agent/translator/translate/logs/metrics_collected/prometheus/ecsservicediscovery/taskdefinition"
 	_ "github.com/aws/amazon-cloudwatch-agent/translator/translate/logs/metrics_collected/prometheus/emfprocessor"
 	_ "github.com/aws/amazon-cloudwatch-agent/translator/translate/metrics/append_dimensions"
+	_ "github.com/aws/amazon-cloudwatch-agent/translator/translate/metrics/deadband"
+	_ "github.com/aws/amazon-cloudwatch-agent/translator/translate/metrics/dimension_lookups"
+	_ "github.com/aws/amazon-cloudwatch-agent/translator/translate/metrics/dimension_normalization"
+	_ "github.com/aws/amazon-cloudwatch-agent/translator/translate/metrics/dimension_rules"
+	_ "github.com/aws/amazon-cloudwatch-agent/translator/translate/metrics/downsampling"
 	_ "github.com/aws/amazon-cloudwatch-agent/translator/translate/metrics/drop_origin"
 	_ "github.com/aws/amazon-cloudwatch-agent/translator/translate/metrics/metric_decoration"
+	_ "github.com/aws/amazon-cloudwatch-agent/translator/translate/metrics/metric_rename"
+	_ "github.com/aws/amazon-cloudwatch-agent/translator/translate/metrics/metric_transforms"
 	_ "github.com/aws/amazon-cloudwatch-agent/translator/translate/metrics/metrics_collect/agentInternal"
+	_ "github.com/aws/amazon-cloudwatch-agent/translator/translate/metrics/metrics_collect/cgroup"
+	_ "github.com/aws/amazon-cloudwatch-agent/translator/translate/metrics/metrics_collect/cloudwatch_query"
 	_ "github.com/aws/amazon-cloudwatch-agent/translator/translate/metrics/metrics_collect/collectd"
+	_ "github.com/aws/amazon-cloudwatch-agent/translator/translate/metrics/metrics_collect/containerd"
 	_ "github.com/aws/amazon-cloudwatch-agent/translator/translate/metrics/metrics_collect/cpu"
+	_ "github.com/aws/amazon-cloudwatch-agent/translator/translate/metrics/metrics_collect/cpu_cluster"
 	_ "github.com/aws/amazon-cloudwatch-agent/translator/translate/metrics/metrics_collect/customizedmetrics"
 	_ "github.com/aws/amazon-cloudwatch-agent/translator/translate/metrics/metrics_collect/disk"
 	_ "github.com/aws/amazon-cloudwatch-agent/translator/translate/metrics/metrics_collect/diskio"
+	_ "github.com/aws/amazon-cloudwatch-agent/translator/translate/metrics/metrics_collect/docker"
 	_ "github.com/aws/amazon-cloudwatch-agent/translator/translate/metrics/metrics_collect/ethtool"
+	_ "github.com/aws/amazon-cloudwatch-agent/translator/translate/metrics/metrics_collect/fd"
 	_ "github.com/aws/amazon-cloudwatch-agent/translator/translate/metrics/metrics_collect/gpu"
+	_ "github.com/aws/amazon-cloudwatch-agent/translator/translate/metrics/metrics_collect/haproxy"
+	_ "github.com/aws/amazon-cloudwatch-agent/translator/translate/metrics/metrics_collect/jmx"
+	_ "github.com/aws/amazon-cloudwatch-agent/translator/translate/metrics/metrics_collect/kafka"
+	_ "github.com/aws/amazon-cloudwatch-agent/translator/translate/metrics/metrics_collect/kernel"
 	_ "github.com/aws/amazon-cloudwatch-agent/translator/translate/metrics/metrics_collect/mem"
 	_ "github.com/aws/amazon-cloudwatch-agent/translator/translate/metrics/metrics_collect/net"
 	_ "github.com/aws/amazon-cloudwatch-agent/translator/translate/metrics/metrics_collect/netstat"
+	_ "github.com/aws/amazon-cloudwatch-agent/translator/translate/metrics/metrics_collect/node_exporter"
 	_ "github.com/aws/amazon-cloudwatch-agent/translator/translate/metrics/metrics_collect/processes"
 	_ "github.com/aws/amazon-cloudwatch-agent/translator/translate/metrics/metrics_collect/procstat"
+	_ "github.com/aws/amazon-cloudwatch-agent/translator/translate/metrics/metrics_collect/rabbitmq"
+	_ "github.com/aws/amazon-cloudwatch-agent/translator/translate/metrics/metrics_collect/sensors"
 	_ "github.com/aws/amazon-cloudwatch-agent/translator/translate/metrics/metrics_collect/statsd"
 	_ "github.com/aws/amazon-cloudwatch-agent/translator/translate/metrics/metrics_collect/swap"
+	_ "github.com/aws/amazon-cloudwatch-agent/translator/translate/metrics/metrics_collect/systemd"
+	_ "github.com/aws/amazon-cloudwatch-agent/translator/translate/metrics/metrics_collect/timesync"
+	_ "github.com/aws/amazon-cloudwatch-agent/translator/translate/metrics/metrics_collect/top_processes"
+	_ "github.com/aws/amazon-cloudwatch-agent/translator/translate/metrics/metrics_collect/windows_gpu"
+	_ "github.com/aws/amazon-cloudwatch-agent/translator/translate/metrics/namespace_routing"
+	_ "github.com/aws/amazon-cloudwatch-agent/translator/translate/metrics/parquet_export"
 	_ "github.com/aws/amazon-cloudwatch-agent/translator/translate/metrics/rollup_dimensions"
 
 	"github.com/BurntSushi/toml"
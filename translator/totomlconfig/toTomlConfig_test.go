@@ -97,6 +97,11 @@ func TestPrometheusConfig(t *testing.T) {
 	os.Unsetenv(config.HOST_NAME)
 }
 
+func TestPrometheusEC2SDConfig(t *testing.T) {
+	resetContext()
+	checkTomlTranslation(t, "./sampleConfig/prometheus_ec2_sd_config_linux.json", "./sampleConfig/prometheus_ec2_sd_config_linux.conf", "linux")
+}
+
 func TestBasicConfig(t *testing.T) {
 	resetContext()
 	checkTomlTranslation(t, "./sampleConfig/basic_config_linux.json", "./sampleConfig/basic_config_linux.conf", "linux")
@@ -124,11 +129,91 @@ func TestDropOriginConfig(t *testing.T) {
 	checkTomlTranslation(t, "./sampleConfig/drop_origin_linux.json", "./sampleConfig/drop_origin_linux.conf", "linux")
 }
 
+func TestNamespaceRoutingConfig(t *testing.T) {
+	resetContext()
+	checkTomlTranslation(t, "./sampleConfig/namespace_routing_config_linux.json", "./sampleConfig/namespace_routing_config_linux.conf", "linux")
+}
+
+func TestDownsamplingConfig(t *testing.T) {
+	resetContext()
+	checkTomlTranslation(t, "./sampleConfig/downsampling_config_linux.json", "./sampleConfig/downsampling_config_linux.conf", "linux")
+}
+
+func TestDimensionNormalizationConfig(t *testing.T) {
+	resetContext()
+	checkTomlTranslation(t, "./sampleConfig/dimension_normalization_config_linux.json", "./sampleConfig/dimension_normalization_config_linux.conf", "linux")
+}
+
+func TestDimensionLookupsConfig(t *testing.T) {
+	resetContext()
+	checkTomlTranslation(t, "./sampleConfig/dimension_lookups_config_linux.json", "./sampleConfig/dimension_lookups_config_linux.conf", "linux")
+}
+
+func TestEMFOutputConfig(t *testing.T) {
+	resetContext()
+	checkTomlTranslation(t, "./sampleConfig/emf_output_config_linux.json", "./sampleConfig/emf_output_config_linux.conf", "linux")
+}
+
+func TestDeadbandConfig(t *testing.T) {
+	resetContext()
+	checkTomlTranslation(t, "./sampleConfig/deadband_config_linux.json", "./sampleConfig/deadband_config_linux.conf", "linux")
+}
+
+func TestRetriesConfig(t *testing.T) {
+	resetContext()
+	checkTomlTranslation(t, "./sampleConfig/retries_config_linux.json", "./sampleConfig/retries_config_linux.conf", "linux")
+}
+
+func TestMetricRenameConfig(t *testing.T) {
+	resetContext()
+	checkTomlTranslation(t, "./sampleConfig/metric_rename_config_linux.json", "./sampleConfig/metric_rename_config_linux.conf", "linux")
+}
+
+func TestMetricTransformsConfig(t *testing.T) {
+	resetContext()
+	checkTomlTranslation(t, "./sampleConfig/metric_transforms_config_linux.json", "./sampleConfig/metric_transforms_config_linux.conf", "linux")
+}
+
+func TestDimensionRulesConfig(t *testing.T) {
+	resetContext()
+	checkTomlTranslation(t, "./sampleConfig/dimension_rules_config_linux.json", "./sampleConfig/dimension_rules_config_linux.conf", "linux")
+}
+
+func TestAgentAuditConfig(t *testing.T) {
+	resetContext()
+	checkTomlTranslation(t, "./sampleConfig/agent_audit_config_linux.json", "./sampleConfig/agent_audit_config_linux.conf", "linux")
+}
+
+func TestHealthHookConfig(t *testing.T) {
+	resetContext()
+	checkTomlTranslation(t, "./sampleConfig/health_hook_config_linux.json", "./sampleConfig/health_hook_config_linux.conf", "linux")
+}
+
+func TestTimeoutsConfig(t *testing.T) {
+	resetContext()
+	checkTomlTranslation(t, "./sampleConfig/timeouts_config_linux.json", "./sampleConfig/timeouts_config_linux.conf", "linux")
+}
+
+func TestCanaryConfig(t *testing.T) {
+	resetContext()
+	checkTomlTranslation(t, "./sampleConfig/canary_config_linux.json", "./sampleConfig/canary_config_linux.conf", "linux")
+}
+
 func TestLogOnlyConfig(t *testing.T) {
 	resetContext()
 	checkTomlTranslation(t, "./sampleConfig/log_only_config_windows.json", "./sampleConfig/log_only_config_windows.conf", "windows")
 }
 
+func TestLogStreamBucketsConfig(t *testing.T) {
+	resetContext()
+	checkTomlTranslation(t, "./sampleConfig/log_stream_buckets_config_linux.json", "./sampleConfig/log_stream_buckets_config_linux.conf", "linux")
+}
+
+func TestLogW3CConfig(t *testing.T) {
+	resetContext()
+	checkTomlTranslation(t, "./sampleConfig/log_w3c_config_windows.json", "./sampleConfig/log_w3c_config_windows.conf", "windows")
+}
+
 func TestStandardConfigWithCommonConfig(t *testing.T) {
 	resetContext()
 	readCommonConfig()
@@ -144,12 +229,154 @@ func TestCsmOnlyConfig(t *testing.T) {
 	checkTomlTranslation(t, "./sampleConfig/csm_only_config.json", "./sampleConfig/csm_only_config_linux.conf", "darwin")
 }
 
+func TestNetRatesConfigLinux(t *testing.T) {
+	resetContext()
+	checkTomlTranslation(t, "./sampleConfig/net_rates_config_linux.json", "./sampleConfig/net_rates_config_linux.conf", "linux")
+}
+
 func TestDeltaConfigLinux(t *testing.T) {
 	resetContext()
 	checkTomlTranslation(t, "./sampleConfig/delta_config_linux.json", "./sampleConfig/delta_config_linux.conf", "linux")
 	checkTomlTranslation(t, "./sampleConfig/delta_config_linux.json", "./sampleConfig/delta_config_linux.conf", "darwin")
 }
 
+func TestCpuAggregationConfigLinux(t *testing.T) {
+	resetContext()
+	checkTomlTranslation(t, "./sampleConfig/cpu_aggregation_config_linux.json", "./sampleConfig/cpu_aggregation_config_linux.conf", "linux")
+	checkTomlTranslation(t, "./sampleConfig/cpu_aggregation_config_linux.json", "./sampleConfig/cpu_aggregation_config_linux.conf", "darwin")
+}
+
+func TestProcstatRecoveryConfigLinux(t *testing.T) {
+	resetContext()
+	checkTomlTranslation(t, "./sampleConfig/procstat_recovery_config_linux.json", "./sampleConfig/procstat_recovery_config_linux.conf", "linux")
+}
+
+func TestJmxConfig(t *testing.T) {
+	resetContext()
+	checkTomlTranslation(t, "./sampleConfig/jmx_config_linux.json", "./sampleConfig/jmx_config_linux.conf", "linux")
+	checkTomlTranslation(t, "./sampleConfig/jmx_config_linux.json", "./sampleConfig/jmx_config_linux.conf", "darwin")
+}
+
+func TestParquetExportConfig(t *testing.T) {
+	resetContext()
+	checkTomlTranslation(t, "./sampleConfig/parquet_export_config_linux.json", "./sampleConfig/parquet_export_config_linux.conf", "linux")
+}
+
+func TestContainerdConfig(t *testing.T) {
+	resetContext()
+	checkTomlTranslation(t, "./sampleConfig/containerd_config_linux.json", "./sampleConfig/containerd_config_linux.conf", "linux")
+}
+
+func TestDockerConfig(t *testing.T) {
+	resetContext()
+	checkTomlTranslation(t, "./sampleConfig/docker_config_linux.json", "./sampleConfig/docker_config_linux.conf", "linux")
+}
+
+func TestMeasurementWildcardConfig(t *testing.T) {
+	resetContext()
+	checkTomlTranslation(t, "./sampleConfig/measurement_wildcard_config_linux.json", "./sampleConfig/measurement_wildcard_config_linux.conf", "linux")
+}
+
+func TestHAProxyConfig(t *testing.T) {
+	resetContext()
+	checkTomlTranslation(t, "./sampleConfig/haproxy_config_linux.json", "./sampleConfig/haproxy_config_linux.conf", "linux")
+}
+
+func TestKafkaConfig(t *testing.T) {
+	resetContext()
+	checkTomlTranslation(t, "./sampleConfig/kafka_config_linux.json", "./sampleConfig/kafka_config_linux.conf", "linux")
+	checkTomlTranslation(t, "./sampleConfig/kafka_config_linux.json", "./sampleConfig/kafka_config_linux.conf", "darwin")
+}
+
+func TestRabbitMQConfig(t *testing.T) {
+	resetContext()
+	checkTomlTranslation(t, "./sampleConfig/rabbitmq_config_linux.json", "./sampleConfig/rabbitmq_config_linux.conf", "linux")
+	checkTomlTranslation(t, "./sampleConfig/rabbitmq_config_linux.json", "./sampleConfig/rabbitmq_config_linux.conf", "darwin")
+}
+
+func TestCgroupConfig(t *testing.T) {
+	resetContext()
+	checkTomlTranslation(t, "./sampleConfig/cgroup_config_linux.json", "./sampleConfig/cgroup_config_linux.conf", "linux")
+}
+
+func TestFDConfig(t *testing.T) {
+	resetContext()
+	checkTomlTranslation(t, "./sampleConfig/fd_config_linux.json", "./sampleConfig/fd_config_linux.conf", "linux")
+}
+
+func TestKernelConfig(t *testing.T) {
+	resetContext()
+	checkTomlTranslation(t, "./sampleConfig/kernel_config_linux.json", "./sampleConfig/kernel_config_linux.conf", "linux")
+}
+
+func TestSensorsConfig(t *testing.T) {
+	resetContext()
+	checkTomlTranslation(t, "./sampleConfig/sensors_config_linux.json", "./sampleConfig/sensors_config_linux.conf", "linux")
+	resetContext()
+	checkTomlTranslation(t, "./sampleConfig/sensors_config_windows.json", "./sampleConfig/sensors_config_windows.conf", "windows")
+}
+
+func TestWindowsGPUConfig(t *testing.T) {
+	resetContext()
+	checkTomlTranslation(t, "./sampleConfig/windows_gpu_config_windows.json", "./sampleConfig/windows_gpu_config_windows.conf", "windows")
+}
+
+func TestCloudWatchQueryConfig(t *testing.T) {
+	resetContext()
+	checkTomlTranslation(t, "./sampleConfig/cloudwatch_query_config_linux.json", "./sampleConfig/cloudwatch_query_config_linux.conf", "linux")
+}
+
+func TestCPUClusterConfig(t *testing.T) {
+	resetContext()
+	checkTomlTranslation(t, "./sampleConfig/cpu_cluster_config_linux.json", "./sampleConfig/cpu_cluster_config_linux.conf", "linux")
+}
+
+func TestDiskIOLatencyConfig(t *testing.T) {
+	resetContext()
+	checkTomlTranslation(t, "./sampleConfig/diskio_latency_config_linux.json", "./sampleConfig/diskio_latency_config_linux.conf", "linux")
+}
+
+func TestNetstatPortsConfig(t *testing.T) {
+	resetContext()
+	checkTomlTranslation(t, "./sampleConfig/netstat_ports_config_linux.json", "./sampleConfig/netstat_ports_config_linux.conf", "linux")
+}
+
+func TestEMFListenerConfig(t *testing.T) {
+	resetContext()
+	checkTomlTranslation(t, "./sampleConfig/emf_listener_config_linux.json", "./sampleConfig/emf_listener_config_linux.conf", "linux")
+}
+
+func TestTopProcessesConfig(t *testing.T) {
+	resetContext()
+	checkTomlTranslation(t, "./sampleConfig/top_processes_config_linux.json", "./sampleConfig/top_processes_config_linux.conf", "linux")
+}
+
+func TestNodeExporterConfig(t *testing.T) {
+	resetContext()
+	checkTomlTranslation(t, "./sampleConfig/node_exporter_config_linux.json", "./sampleConfig/node_exporter_config_linux.conf", "linux")
+}
+
+func TestNumaConfig(t *testing.T) {
+	resetContext()
+	checkTomlTranslation(t, "./sampleConfig/numa_config_linux.json", "./sampleConfig/numa_config_linux.conf", "linux")
+}
+
+func TestSystemdConfig(t *testing.T) {
+	resetContext()
+	checkTomlTranslation(t, "./sampleConfig/systemd_config_linux.json", "./sampleConfig/systemd_config_linux.conf", "linux")
+}
+
+func TestTimeSyncConfig(t *testing.T) {
+	resetContext()
+	checkTomlTranslation(t, "./sampleConfig/timesync_config_linux.json", "./sampleConfig/timesync_config_linux.conf", "linux")
+	checkTomlTranslation(t, "./sampleConfig/timesync_config_linux.json", "./sampleConfig/timesync_config_linux.conf", "darwin")
+}
+
+func TestInstanceNormalizationConfigWindows(t *testing.T) {
+	resetContext()
+	checkTomlTranslation(t, "./sampleConfig/instance_normalization_config_windows.json", "./sampleConfig/instance_normalization_config_windows.conf", "windows")
+}
+
 func TestCsmServiceAddressesConfig(t *testing.T) {
 	resetContext()
 	checkTomlTranslation(t, "./sampleConfig/csm_service_addresses.json", "./sampleConfig/csm_service_addresses_windows.conf", "windows")
@@ -28,43 +28,92 @@ type (
 	}
 
 	inputConfig struct {
+		AgentAudit        []agentAuditConfig     `toml:"agent_audit"`
+		AgentHealth       []agentHealthConfig    `toml:"agent_health"`
 		AwsCsmListener    []awsCsmListenerConfig `toml:"awscsm_listener"`
 		Cadvisor          []cadvisorConfig
+		Canary            []canaryConfig
+		Cgroup            []cgroupConfig
+		CloudWatchQuery   []cloudWatchQueryConfig  `toml:"cloudwatch_query"`
+		CollectdListener  []collectdListenerConfig `toml:"collectd_listener"`
+		Containerd        []containerdConfig
 		Cpu               []cpuConfig
+		CPUCluster        []cpuClusterConfig `toml:"cpu_cluster"`
 		Disk              []diskConfig
 		DiskIo            []diskioConfig
+		DiskIOLatency     []diskIOLatencyConfig `toml:"diskio_latency"`
+		Docker            []dockerConfig
+		EMFListener       []emfListenerConfig `toml:"emf_listener"`
 		Eththool          []ethtoolConfig
+		FD                []fdConfig            `toml:"fd"`
+		HAProxy           []haproxyConfig       `toml:"haproxy"`
+		Jolokia2Agent     []jolokia2AgentConfig `toml:"jolokia2_agent"`
 		K8sapiserver      []k8sApiServerConfig
+		Kafka             []kafkaConfig
+		Kernel            []kernelConfig
 		Logfile           []logFileConfig
 		Mem               []memConfig
 		Net               []netConfig
 		NetStat           []netStatConfig
+		NetStatPorts      []netStatPortsConfig `toml:"netstat_ports"`
+		NodeExporter      []nodeExporterConfig `toml:"node_exporter"`
+		Numa              []numaConfig
 		NvidiaSmi         []nvidiaSmi `toml:"nvidia_smi"`
 		Processes         []processesConfig
 		PrometheusScraper []prometheusScraperConfig `toml:"prometheus_scraper"`
 		ProcStat          []procStatConfig
-		SocketListener    []socketListenerConfig `toml:"socket_listener"`
+		RabbitMQ          []rabbitmqConfig `toml:"rabbitmq"`
+		Sensors           []sensorsConfig
 		Statsd            []statsdConfig
 		Swap              []swapConfig
+		Systemd           []systemdConfig
+		Timesync          []timesyncConfig
+		TopProcesses      []topProcessesConfig    `toml:"top_processes"`
 		WindowsEventLog   []windowsEventLogConfig `toml:"windows_event_log"`
+		WindowsGPU        []windowsGPUConfig      `toml:"windows_gpu"`
+		WinPerfCounters   []winPerfCountersConfig `toml:"win_perf_counters"`
 	}
 
 	outputConfig struct {
 		AwsCsm         []awsCsmConfig `toml:"aws_csm"`
 		CloudWatch     []cloudWatchOutputConfig
 		CloudWatchLogs []cloudWatchLogsConfig
+		S3Parquet      []s3ParquetConfig `toml:"s3_parquet"`
 	}
 
 	processorsConfig struct {
-		Delta        []processorDelta
-		EcsDecorator []ecsDecoratorConfig
-		Ec2tagger    []ec2TaggerConfig
-		EmfProcessor []emfProcessorConfig
-		K8sDecorator []k8sDecoratorConfig
+		CpuAggregator      []processorCpuAggregator
+		Deadband           []processorDeadband
+		Delta              []processorDelta
+		DerivedMetrics     []processorDerivedMetrics
+		DimensionFilter    []processorDimensionFilter
+		DimensionLookup    []processorDimensionLookup
+		EcsDecorator       []ecsDecoratorConfig
+		Ec2tagger          []ec2TaggerConfig
+		EmfProcessor       []emfProcessorConfig
+		InstanceNormalizer []processorInstanceNormalizer
+		K8sDecorator       []k8sDecoratorConfig
+		Recovery           []processorRecovery
 	}
 
 	// Input Plugins
 
+	agentAuditConfig struct {
+		Destination     string
+		FileStateFolder string `toml:"file_state_folder"`
+		LogGroupName    string `toml:"log_group_name"`
+		LogStreamName   string `toml:"log_stream_name"`
+		RetentionInDays int    `toml:"retention_in_days"`
+	}
+
+	agentHealthConfig struct {
+		Command            string
+		RepeatInterval     string `toml:"repeat_interval"`
+		Timeout            string
+		UnhealthyThreshold string `toml:"unhealthy_threshold"`
+		WebhookURL         string `toml:"webhook_url"`
+	}
+
 	awsCsmListenerConfig struct {
 		DataFormat     string   `toml:"data_format"`
 		ServiceAddress []string `toml:"service_address"`
@@ -77,8 +126,71 @@ type (
 		Tags                  map[string]string
 	}
 
+	canaryConfig struct {
+		Interval  string
+		Namespace string
+		Region    string
+	}
+
+	cgroupConfig struct {
+		CgroupRoot string `toml:"cgroup_root"`
+		FieldPass  []string
+		Interval   string
+		Paths      []string
+		Tags       map[string]string
+	}
+
+	cloudWatchQueryConfig struct {
+		Delay      string
+		Interval   string
+		LeaderHost string `toml:"leader_host"`
+		Period     string
+		Query      []cloudWatchQuery
+		Region     string
+		TagExclude []string
+		Tags       map[string]string
+	}
+
+	cloudWatchQuery struct {
+		Dimensions     map[string]string
+		Expression     string
+		Id             string
+		LabelDimension string `toml:"label_dimension"`
+		MetricName     string `toml:"metric_name"`
+		Name           string
+		Namespace      string
+		ReturnData     *bool `toml:"return_data"`
+		Stat           string
+	}
+
+	collectdListenerConfig struct {
+		CollectdAuthFile           string            `toml:"collectd_auth_file"`
+		CollectdSecurityLevel      string            `toml:"collectd_security_level"`
+		CollectdTypesDb            []string          `toml:"collectd_typesdb"`
+		CollectdUserSecurityLevels map[string]string `toml:"collectd_user_security_levels"`
+		DataFormat                 string            `toml:"data_format"`
+		MaxSeries                  int               `toml:"max_series"`
+		MaxSeriesInterval          string            `toml:"max_series_interval"`
+		NamePrefix                 string            `toml:"name_prefix"`
+		NameOverride               string            `toml:"name_override"`
+		SeriesOverflow             string            `toml:"series_overflow"`
+		ServiceAddress             string            `toml:"service_address"`
+		Tags                       map[string]string
+	}
+
+	containerdConfig struct {
+		ContainerNameExclude []string `toml:"container_name_exclude"`
+		ContainerNameInclude []string `toml:"container_name_include"`
+		Endpoint             string
+		FieldPass            []string
+		Interval             string
+		TagExclude           []string
+		Tags                 map[string]string
+	}
+
 	cpuConfig struct {
 		CollectCpuTime bool `toml:"collect_cpu_time"`
+		FieldDrop      []string
 		FieldPass      []string
 		Interval       string
 		PerCpu         bool
@@ -87,6 +199,13 @@ type (
 		Tags           map[string]string
 	}
 
+	cpuClusterConfig struct {
+		FieldPass   []string
+		Interval    string
+		PMUCounters bool `toml:"pmu_counters"`
+		Tags        map[string]string
+	}
+
 	diskConfig struct {
 		FieldPass   []string
 		IgnoreFs    []string `toml:"ignore_fs"`
@@ -97,8 +216,37 @@ type (
 	}
 
 	diskioConfig struct {
+		FieldDrop []string
+		FieldPass []string
+		Interval  string
+		Tags      map[string]string
+	}
+
+	diskIOLatencyConfig struct {
+		Devices   []string
 		FieldPass []string
 		Interval  string
+		Tags      map[string]string
+	}
+
+	dockerConfig struct {
+		ContainerNameExclude []string `toml:"container_name_exclude"`
+		ContainerNameInclude []string `toml:"container_name_include"`
+		Endpoint             string
+		FieldPass            []string
+		Interval             string
+		TagExclude           []string
+		Tags                 map[string]string
+	}
+
+	emfListenerConfig struct {
+		DataFormat     string  `toml:"data_format"`
+		MaxPayloadSize int     `toml:"max_payload_size"`
+		NameOverride   string  `toml:"name_override"`
+		RateLimit      float64 `toml:"rate_limit"`
+		RateLimitBurst int     `toml:"rate_limit_burst"`
+		ServiceAddress string  `toml:"service_address"`
+		Tags           map[string]string
 	}
 
 	ethtoolConfig struct {
@@ -107,6 +255,13 @@ type (
 		Tags             map[string]string
 	}
 
+	fdConfig struct {
+		FieldPass    []string
+		Interval     string
+		Tags         map[string]string
+		TopProcesses int `toml:"top_processes"`
+	}
+
 	eventConfig struct {
 		BatchReadSize   int      `toml:"batch_read_size"`
 		EventLevels     []string `toml:"event_levels"`
@@ -123,16 +278,54 @@ type (
 	}
 
 	fileConfig struct {
-		AutoRemoval     bool   `toml:"auto_removal"`
-		FilePath        string `toml:"file_path"`
-		FromBeginning   bool   `toml:"from_beginning"`
-		LogGroupName    string `toml:"log_group_name"`
-		LogStreamName   string `toml:"log_stream_name"`
-		Pipe            bool
-		RetentionInDays int `toml:"retention_in_days"`
-		Timezone        string
+		AutoRemoval      bool   `toml:"auto_removal"`
+		FilePath         string `toml:"file_path"`
+		FromBeginning    bool   `toml:"from_beginning"`
+		LogFormat        string `toml:"log_format"`
+		LogGroupName     string `toml:"log_group_name"`
+		LogStreamBuckets int    `toml:"log_stream_buckets"`
+		LogStreamName    string `toml:"log_stream_name"`
+		Pipe             bool
+		Priority         int
+		PublishMultiLogs bool `toml:"publish_multi_logs"`
+		RetentionInDays  int  `toml:"retention_in_days"`
+		Timezone         string
+		Tags             map[string]string
+		Filters          []fileConfigFilter
+	}
+
+	haproxyConfig struct {
+		FieldPass []string
+		Interval  string
+		Password  string
+		Proxies   []string
+		Servers   []string
+		Tags      map[string]string
+		Username  string
+	}
+
+	instanceList struct {
+		SdInstanceTags map[string]string `toml:"sd_instance_tags"`
+		SdJobName      string            `toml:"sd_job_name"`
+		SdMetricsPath  string            `toml:"sd_metrics_path"`
+		SdMetricsPorts string            `toml:"sd_metrics_ports"`
+	}
+
+	jolokia2AgentConfig struct {
+		Interval        string
+		Metric          []jolokia2MetricConfig
+		Password        string
+		ResponseTimeout string `toml:"response_timeout"`
 		Tags            map[string]string
-		Filters         []fileConfigFilter
+		Urls            []string
+		Username        string
+	}
+
+	jolokia2MetricConfig struct {
+		Mbean   string
+		Name    string
+		Paths   []string
+		TagKeys []string `toml:"tag_keys"`
 	}
 
 	k8sApiServerConfig struct {
@@ -141,7 +334,26 @@ type (
 		Tags     map[string]string
 	}
 
+	kafkaConfig struct {
+		Brokers         []string
+		ConsumerGroups  []string `toml:"consumer_groups"`
+		FieldPass       []string
+		Interval        string
+		PerPartitionLag bool `toml:"per_partition_lag"`
+		Tags            map[string]string
+		TLS             bool
+		Topics          []string
+	}
+
+	kernelConfig struct {
+		FieldPass []string
+		Interval  string
+		PerIRQ    bool `toml:"per_irq"`
+		Tags      map[string]string
+	}
+
 	memConfig struct {
+		FieldDrop []string
 		FieldPass []string
 		Interval  string
 		Tags      map[string]string
@@ -159,6 +371,29 @@ type (
 		Tags      map[string]string
 	}
 
+	netStatPortsConfig struct {
+		ByProcess bool `toml:"by_process"`
+		FieldPass []string
+		Interval  string
+		Ports     []int
+		Tags      map[string]string
+	}
+
+	nodeExporterConfig struct {
+		Interval       string
+		Metrics        []string
+		MetricsExclude []string `toml:"metrics_exclude"`
+		PerCpu         bool     `toml:"percpu"`
+		Tags           map[string]string
+		URL            string `toml:"url"`
+	}
+
+	numaConfig struct {
+		FieldPass []string
+		Interval  string
+		Tags      map[string]string
+	}
+
 	nvidiaSmi struct {
 		FieldPass  []string
 		Interval   string
@@ -175,12 +410,23 @@ type (
 		ClusterName          string                              `toml:"cluster_name"`
 		PrometheusConfigPath string                              `toml:"prometheus_config_path"`
 		EcsServiceDiscovery  prometheusEcsServiceDiscoveryConfig `toml:"ecs_service_discovery"`
+		Ec2ServiceDiscovery  prometheusEc2ServiceDiscoveryConfig `toml:"ec2_service_discovery"`
 		Tags                 map[string]string
 	}
 
+	prometheusEc2ServiceDiscoveryConfig struct {
+		SdFrequency           string         `toml:"sd_frequency"`
+		SdHttpSdListenAddress string         `toml:"sd_http_sd_listen_address"`
+		SdRegion              string         `toml:"sd_region"`
+		SdResultFile          string         `toml:"sd_result_file"`
+		SdVpcId               string         `toml:"sd_vpc_id"`
+		InstanceList          []instanceList `toml:"instance_list"`
+	}
+
 	prometheusEcsServiceDiscoveryConfig struct {
 		SdClusterRegion         string                    `toml:"sd_cluster_region"`
 		SdFrequency             string                    `toml:"sd_frequency"`
+		SdHttpSdListenAddress   string                    `toml:"sd_http_sd_listen_address"`
 		SdResultFile            string                    `toml:"sd_result_file"`
 		SdTargetCluster         string                    `toml:"sd_target_cluster"`
 		DockerLabel             map[string]string         `toml:"docker_label"`
@@ -189,13 +435,27 @@ type (
 	}
 
 	procStatConfig struct {
+		Exe        string
 		FieldPass  []string
+		Pattern    string
 		PidFile    string `toml:"pid_file"`
 		PidFinder  string `toml:"pid_finder"`
 		TagExclude []string
 		Tags       map[string]string
 	}
 
+	rabbitmqConfig struct {
+		FieldPass        []string
+		Interval         string
+		Password         string
+		QueueNameExclude []string `toml:"queue_name_exclude"`
+		QueueNameInclude []string `toml:"queue_name_include"`
+		Tags             map[string]string
+		URL              string
+		Username         string
+		VHosts           []string
+	}
+
 	serviceNameListForTasks struct {
 		SdContainerNamePattern string `toml:"sd_container_name_pattern"`
 		SdJobName              string `toml:"sd_job_name"`
@@ -204,24 +464,32 @@ type (
 		SdServiceNamePattern   string `toml:"sd_service_name_pattern"`
 	}
 
-	socketListenerConfig struct {
-		CollectdAuthFile      string   `toml:"collectd_auth_file"`
-		CollectdSecurityLevel string   `toml:"collectd_security_level"`
-		CollectdTypesDb       []string `toml:"collectd_typesdb"`
-		DataFormat            string   `toml:"data_format"`
-		NamePrefix            string   `toml:"name_prefix"`
-		NameOverride          string   `toml:"name_override"`
-		ServiceAddress        string   `toml:"service_address"`
-		Tags                  map[string]string
+	sensorsConfig struct {
+		FieldPass []string
+		Interval  string
+		Tags      map[string]string
 	}
 
 	statsdConfig struct {
-		AllowedPendingMessages int `toml:"allowed_pending_messages"`
+		AllowedDataDogTags     []string `toml:"allowed_data_dog_tags"`
+		AllowedPendingMessages int      `toml:"allowed_pending_messages"`
+		CounterMode            string   `toml:"counter_mode"`
+		EventsLogGroupName     string   `toml:"events_log_group_name"`
+		EventsLogStreamName    string   `toml:"events_log_stream_name"`
 		Interval               string
-		MetricSeparator        string `toml:"metric_separator"`
-		ParseDataDogTags       bool   `toml:"parse_data_dog_tags"`
-		ServiceAddress         string `toml:"service_address"`
+		MaxDataDogTagValues    int       `toml:"max_data_dog_tag_values"`
+		MaxSeries              int       `toml:"max_series"`
+		MaxSeriesInterval      string    `toml:"max_series_interval"`
+		MetricSeparator        string    `toml:"metric_separator"`
+		ParseDataDogTags       bool      `toml:"parse_data_dog_tags"`
+		PercentileMode         string    `toml:"percentile_mode"`
+		Percentiles            []float64 `toml:"percentiles"`
+		ReadBatchSize          int       `toml:"read_batch_size"`
+		ReadBufferSize         int       `toml:"read_buffer_size"`
+		SeriesOverflow         string    `toml:"series_overflow"`
+		ServiceAddress         string    `toml:"service_address"`
 		Tags                   map[string]string
+		Templates              []string
 	}
 
 	swapConfig struct {
@@ -229,6 +497,14 @@ type (
 		Tags      map[string]string
 	}
 
+	systemdConfig struct {
+		FieldPass []string
+		Interval  string
+		Tags      map[string]string
+		Timeout   string
+		Units     []string
+	}
+
 	taskDefinitionList struct {
 		SdJobName                  string `toml:"sd_job_name"`
 		SdMetricsPath              string `toml:"sd_metrics_path"`
@@ -236,6 +512,23 @@ type (
 		SdTaskDefinitionArnPattern string `toml:"sd_task_definition_arn_pattern"`
 	}
 
+	timesyncConfig struct {
+		FieldPass  []string
+		Interval   string
+		Source     string
+		TagExclude []string
+		Tags       map[string]string
+		Timeout    string
+	}
+
+	topProcessesConfig struct {
+		FieldPass []string
+		Interval  string
+		RankBy    []string `toml:"rank_by"`
+		Tags      map[string]string
+		TopN      int `toml:"top_n"`
+	}
+
 	windowsEventLogConfig struct {
 		Destination     string
 		FileStateFolder string        `toml:"file_state_folder"`
@@ -243,6 +536,27 @@ type (
 		Tags            map[string]string
 	}
 
+	windowsGPUConfig struct {
+		FieldPass []string
+		Interval  string
+		Tags      map[string]string
+	}
+
+	winPerfCountersConfig struct {
+		DisableReplacer bool
+		Interval        string
+		Object          []winPerfCounterObject
+		Tags            map[string]string
+	}
+
+	winPerfCounterObject struct {
+		Counters      []string
+		Instances     []string
+		Measurement   string
+		ObjectName    string
+		WarnOnMissing bool
+	}
+
 	// Output plugins
 
 	awsCsmConfig struct {
@@ -252,18 +566,58 @@ type (
 	}
 
 	cloudWatchOutputConfig struct {
-		EndpointOverride    string `toml:"endpoint_override"`
-		ForceFlushInterval  string `toml:"force_flush_interval"`
-		MaxDatumsPerCall    int    `toml:"max_datums_per_call"`
-		MaxValuesPerDatum   int    `toml:"max_values_per_datum"`
-		Namespace           string
-		Region              string
-		RoleArn             string     `toml:"role_arn"`
-		RollupDimensions    [][]string `toml:"rollup_dimensions"`
-		TagExclude          []string
-		DropOriginalMetrics map[string][]string      `toml:"drop_original_metrics"`
-		MetricDecorations   []metricDecorationConfig `toml:"metric_decoration"`
-		TagPass             map[string][]string
+		APICallTimeout         string                       `toml:"api_call_timeout"`
+		CheckpointFile         string                       `toml:"checkpoint_file"`
+		DNSResolutionTimeout   string                       `toml:"dns_resolution_timeout"`
+		DimensionNormalization dimensionNormalizationConfig `toml:"dimension_normalization"`
+		DistributionType       string                       `toml:"distribution_type"`
+		Downsampling           []downsamplingConfig
+		EndpointOverride       string  `toml:"endpoint_override"`
+		ForceFlushInterval     string  `toml:"force_flush_interval"`
+		LogGroupName           string  `toml:"log_group_name"`
+		LogStreamName          string  `toml:"log_stream_name"`
+		MaxBackoff             string  `toml:"max_backoff"`
+		MaxDatumsPerCall       int     `toml:"max_datums_per_call"`
+		MaxPayloadSize         int     `toml:"max_payload_size"`
+		MaxRequestsPerSecond   float64 `toml:"max_requests_per_second"`
+		MaxRetries             int     `toml:"max_retries"`
+		MaxValuesPerDatum      int     `toml:"max_values_per_datum"`
+		Namespace              string
+		NamespaceRouting       []namespaceRoutingConfig `toml:"namespace_routing"`
+		NegativeValues         string                   `toml:"negative_values"`
+		OutputMode             string                   `toml:"output_mode"`
+		QuarantineFile         string                   `toml:"quarantine_file"`
+		Region                 string
+		RoleArn                string     `toml:"role_arn"`
+		RollupDimensions       [][]string `toml:"rollup_dimensions"`
+		SEH1Epsilon            float64    `toml:"seh1_epsilon"`
+		TDigestCompression     float64    `toml:"tdigest_compression"`
+		TagExclude             []string
+		UnitConflicts          string                   `toml:"unit_conflicts"`
+		DropOriginalMetrics    map[string][]string      `toml:"drop_original_metrics"`
+		MetricDecorations      []metricDecorationConfig `toml:"metric_decoration"`
+		MetricRename           []metricRenameConfig     `toml:"metric_rename"`
+		TagPass                map[string][]string
+	}
+
+	dimensionNormalizationConfig struct {
+		AllowedCharacters string `toml:"allowed_characters"`
+		KeyCase           string `toml:"key_case"`
+		MaxLength         int    `toml:"max_length"`
+		Replacement       string
+		ValueCase         string `toml:"value_case"`
+	}
+
+	downsamplingConfig struct {
+		OlderThan string `toml:"older_than"`
+		Interval  string
+	}
+
+	namespaceRoutingConfig struct {
+		Dimension     string
+		DropDimension bool `toml:"drop_dimension"`
+		Namespace     string
+		Namespaces    map[string]string
 	}
 
 	metricDecorationConfig struct {
@@ -273,14 +627,31 @@ type (
 		Unit     string
 	}
 
+	metricRenameConfig struct {
+		Namespace string
+		Pattern   string
+		Rename    string
+	}
+
 	cloudWatchLogsConfig struct {
-		EndpointOverride   string `toml:"endpoint_override"`
-		ForceFlushInterval string `toml:"force_flush_interval"`
-		LogStreamName      string `toml:"log_stream_name"`
-		Region             string
-		RoleArn            string `toml:"role_arn"`
-		TagExclude         []string
-		TagPass            map[string][]string
+		APICallTimeout         string  `toml:"api_call_timeout"`
+		DNSResolutionTimeout   string  `toml:"dns_resolution_timeout"`
+		EndpointOverride       string  `toml:"endpoint_override"`
+		ForceFlushInterval     string  `toml:"force_flush_interval"`
+		LogNameOverflow        string  `toml:"log_name_overflow"`
+		LogNameReplacement     string  `toml:"log_name_replacement"`
+		LogStreamName          string  `toml:"log_stream_name"`
+		MaxBackoff             string  `toml:"max_backoff"`
+		MaxRequestsPerSecond   float64 `toml:"max_requests_per_second"`
+		MaxRetries             int     `toml:"max_retries"`
+		Region                 string
+		RejectedLogEventsFile  string `toml:"rejected_log_events_file"`
+		RetentionCheckInterval string `toml:"retention_check_interval"`
+		RetentionRestore       bool   `toml:"retention_restore"`
+		RoleArn                string `toml:"role_arn"`
+		TagExclude             []string
+		UnitConflicts          string `toml:"unit_conflicts"`
+		TagPass                map[string][]string
 	}
 
 	fileConfigFilter struct {
@@ -288,10 +659,84 @@ type (
 		Type       string
 	}
 
+	s3ParquetConfig struct {
+		Bucket          string
+		Compression     string
+		MaxPendingFiles int `toml:"max_pending_files"`
+		MaxRowsPerFile  int `toml:"max_rows_per_file"`
+		Namespace       string
+		Prefix          string
+		Region          string
+		RoleArn         string `toml:"role_arn"`
+		TagExclude      []string
+		TagPass         map[string][]string
+	}
+
 	// Processors
+	processorCpuAggregator struct {
+	}
+
+	processorDeadband struct {
+		Order   int
+		Rule    []processorDeadbandRule
+		TagPass map[string][]string
+	}
+
+	processorDeadbandRule struct {
+		Delta        float64
+		MaxStaleness string `toml:"max_staleness"`
+		MetricName   string `toml:"metric_name"`
+	}
+
 	processorDelta struct {
 	}
 
+	processorDerivedMetrics struct {
+		Metric  []processorDerivedMetric
+		Order   int
+		TagPass map[string][]string
+	}
+
+	processorDerivedMetric struct {
+		Expression  string
+		Measurement string
+		Name        string
+	}
+
+	processorDimensionFilter struct {
+		Order   int
+		Rule    []processorDimensionFilterRule
+		TagPass map[string][]string
+	}
+
+	processorDimensionFilterRule struct {
+		Drop       []string
+		Keep       []string
+		MetricName string `toml:"metric_name"`
+	}
+
+	processorDimensionLookup struct {
+		Order   int
+		Table   []processorDimensionLookupTable
+		TagPass map[string][]string
+	}
+
+	processorDimensionLookupTable struct {
+		Dimension      string
+		File           string
+		ReloadInterval string `toml:"reload_interval"`
+		Values         map[string]string
+	}
+
+	processorInstanceNormalizer struct {
+		ObjectPatterns map[string][]string `toml:"object_patterns"`
+		Patterns       []string
+	}
+
+	processorRecovery struct {
+		Timeout string
+	}
+
 	ecsDecoratorConfig struct {
 		HostIp  string `toml:"host_ip"`
 		Order   int
@@ -311,6 +756,7 @@ type (
 		Order                  int
 		MetricDeclaration      []emfProcessorMetricDeclaration `toml:"metric_declaration"`
 		MetricUnit             map[string]string               `toml:"metric_unit"`
+		MetricUnitByJob        map[string]map[string]string    `toml:"metric_unit_by_job"`
 		TagPass                map[string][]string
 	}
 
@@ -24,11 +24,13 @@ func RegisterRule(fieldname string, r translator.Rule) {
 }
 
 type Agent struct {
-	Interval    string
-	Credentials map[string]interface{}
-	Region      string
-	Internal    bool
-	Role_arn    string
+	Interval      string
+	Credentials   map[string]interface{}
+	Region        string
+	Internal      bool
+	Role_arn      string
+	Timeouts      map[string]interface{}
+	MetricsOutput map[string]interface{}
 }
 
 var Global_Config Agent = *new(Agent)
@@ -177,3 +177,32 @@ func restoreProxyEnv() {
 	os.Setenv("https_proxy", httpsProxy)
 	os.Setenv("no_proxy", noProxy)
 }
+
+func TestTimeouts(t *testing.T) {
+	a := new(Agent)
+	translator.SetTargetPlatform(config.OS_TYPE_LINUX)
+	var input interface{}
+	err := json.Unmarshal([]byte(`{"agent":{"timeouts": {"api_call": 120, "exec": 0.5}}}`), &input)
+	assert.NoError(t, err)
+	_, val := a.ApplyRule(input)
+	assert.NotContains(t, val, TimeoutsKey)
+
+	// the timeouts of the components override the ones of the agent section
+	timeout, ok := Timeout(APICallTimeoutKey, nil)
+	assert.True(t, ok)
+	assert.Equal(t, "120s", timeout)
+	timeout, ok = Timeout(APICallTimeoutKey, float64(30))
+	assert.True(t, ok)
+	assert.Equal(t, "30s", timeout)
+	timeout, ok = Timeout(ExecTimeoutKey, nil)
+	assert.True(t, ok)
+	assert.Equal(t, "500ms", timeout)
+	_, ok = Timeout(DNSResolutionTimeoutKey, nil)
+	assert.False(t, ok)
+
+	err = json.Unmarshal([]byte(`{"agent":{}}`), &input)
+	assert.NoError(t, err)
+	a.ApplyRule(input)
+	_, ok = Timeout(APICallTimeoutKey, nil)
+	assert.False(t, ok)
+}
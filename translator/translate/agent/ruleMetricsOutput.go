@@ -0,0 +1,42 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package agent
+
+//
+//	"agent": {
+//		"metrics_output": {
+//			"mode": "emf",
+//			"log_group_name": "/aws/cwagent/metrics",
+//			"log_stream_name": "{instance_id}"
+//		}
+//	}
+//
+
+type MetricsOutput struct {
+}
+
+const (
+	MetricsOutputKey = "metrics_output"
+
+	MetricsOutputModeKey          = "mode"
+	MetricsOutputLogGroupNameKey  = "log_group_name"
+	MetricsOutputLogStreamNameKey = "log_stream_name"
+)
+
+// The publication of the collected metrics will be provided to the cloudwatch output, with PutMetricData by default or
+// as embedded metric format documents to CloudWatch Logs.
+// This should be applied before interpreting other component.
+func (obj *MetricsOutput) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	Global_Config.MetricsOutput = map[string]interface{}{}
+	m := input.(map[string]interface{})
+	if output, ok := m[MetricsOutputKey].(map[string]interface{}); ok {
+		Global_Config.MetricsOutput = output
+	}
+	return
+}
+
+func init() {
+	obj := new(MetricsOutput)
+	RegisterRule(MetricsOutputKey, obj)
+}
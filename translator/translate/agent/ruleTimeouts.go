@@ -0,0 +1,66 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package agent
+
+import (
+	"fmt"
+)
+
+//
+//	"agent": {
+//		"timeouts": {
+//			"api_call": 120,
+//			"dns_resolution": 20,
+//			"exec": 15
+//		}
+//	}
+//
+
+type Timeouts struct {
+}
+
+const (
+	TimeoutsKey = "timeouts"
+
+	APICallTimeoutKey       = "api_call"
+	DNSResolutionTimeoutKey = "dns_resolution"
+	ExecTimeoutKey          = "exec"
+)
+
+// The timeouts will be provided to the corresponding input, processor and output plugins, they are overridden by the
+// timeouts of the components.
+// This should be applied before interpreting other component.
+func (obj *Timeouts) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	Global_Config.Timeouts = map[string]interface{}{}
+	m := input.(map[string]interface{})
+	if timeouts, ok := m[TimeoutsKey].(map[string]interface{}); ok {
+		Global_Config.Timeouts = timeouts
+	}
+	return
+}
+
+// Timeout returns the timeout of the key, in seconds, from the override of the component or else from the timeouts of
+// the agent section, as a duration of the toml configuration. It returns false when neither is set.
+func Timeout(key string, override interface{}) (string, bool) {
+	val, ok := override.(float64)
+	if !ok {
+		val, ok = Global_Config.Timeouts[key].(float64)
+	}
+	if !ok || val <= 0 {
+		return "", false
+	}
+	return formatSeconds(val), true
+}
+
+func formatSeconds(val float64) string {
+	if val == float64(int(val)) {
+		return fmt.Sprintf("%ds", int(val))
+	}
+	return fmt.Sprintf("%dms", int(val*1000))
+}
+
+func init() {
+	obj := new(Timeouts)
+	RegisterRule(TimeoutsKey, obj)
+}
@@ -0,0 +1,82 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package canary
+
+import (
+	"fmt"
+
+	parent "github.com/aws/amazon-cloudwatch-agent/translator/translate"
+	"github.com/aws/amazon-cloudwatch-agent/translator/translate/agent"
+)
+
+//
+//	"agent": {
+//		"canary": {
+//			"namespace": "CWAgent/Canary",
+//			"interval": 60
+//		}
+//	}
+//
+
+// SectionKey is the heartbeat of the agent section, published whatever the rest of the configuration is.
+const SectionKey = "canary"
+
+const (
+	namespaceKey = "namespace"
+	intervalKey  = "interval"
+
+	defaultInterval = 60
+	inputPluginKey  = "canary"
+)
+
+func GetCurPath() string {
+	return agent.GetCurPath() + SectionKey + "/"
+}
+
+type Canary struct {
+}
+
+// ApplyRule translates the heartbeat, published with the region and the credentials of the agent at its own
+// interval instead of the metrics_collection_interval of the agent.
+func (c *Canary) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	m := input.(map[string]interface{})
+	returnKey = ""
+	returnVal = ""
+	agentMap, ok := m[agent.SectionKey].(map[string]interface{})
+	if !ok {
+		return
+	}
+	canaryMap, ok := agentMap[SectionKey].(map[string]interface{})
+	if !ok {
+		return
+	}
+
+	result := map[string]interface{}{}
+	if val, ok := canaryMap[namespaceKey].(string); ok && val != "" {
+		result[namespaceKey] = val
+	}
+	// the interval is in seconds
+	interval := defaultInterval
+	if val, ok := canaryMap[intervalKey].(float64); ok {
+		interval = int(val)
+	}
+	result[intervalKey] = fmt.Sprintf("%ds", interval)
+	result[agent.RegionKey] = agent.Global_Config.Region
+	for k, v := range agent.Global_Config.Credentials {
+		result[k] = v
+	}
+
+	returnKey = SectionKey
+	returnVal = map[string]interface{}{
+		"inputs": map[string]interface{}{inputPluginKey: []interface{}{result}},
+	}
+	return
+}
+
+func init() {
+	c := new(Canary)
+	parent.RegisterLinuxRule(SectionKey, c)
+	parent.RegisterDarwinRule(SectionKey, c)
+	parent.RegisterWindowsRule(SectionKey, c)
+}
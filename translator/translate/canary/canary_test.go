@@ -0,0 +1,52 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package canary
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/aws/amazon-cloudwatch-agent/translator/translate/agent"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCanary(t *testing.T) {
+	agent.Global_Config.Region = "us-east-1"
+	agent.Global_Config.Credentials = map[string]interface{}{"role_arn": "arn:aws:iam::123456789012:role/canary"}
+	defer func() {
+		agent.Global_Config.Region = ""
+		agent.Global_Config.Credentials = map[string]interface{}{}
+	}()
+	c := new(Canary)
+	var input interface{}
+	err := json.Unmarshal([]byte(`{"agent":{"region":"us-east-1","canary":{"namespace":"Fleet/Heartbeat","interval":300}}}`), &input)
+	assert.NoError(t, err)
+	key, actual := c.ApplyRule(input)
+	assert.Equal(t, SectionKey, key)
+	expected := map[string]interface{}{
+		"inputs": map[string]interface{}{
+			"canary": []interface{}{map[string]interface{}{
+				"namespace": "Fleet/Heartbeat",
+				"interval":  "300s",
+				"region":    "us-east-1",
+				"role_arn":  "arn:aws:iam::123456789012:role/canary",
+			}},
+		},
+	}
+	assert.Equal(t, expected, actual)
+}
+
+func TestCanary_Default(t *testing.T) {
+	c := new(Canary)
+	var input interface{}
+	assert.NoError(t, json.Unmarshal([]byte(`{"agent":{"region":"us-east-1"}}`), &input))
+	key, _ := c.ApplyRule(input)
+	assert.Equal(t, "", key)
+
+	assert.NoError(t, json.Unmarshal([]byte(`{"agent":{"canary":{}}}`), &input))
+	key, actual := c.ApplyRule(input)
+	assert.Equal(t, SectionKey, key)
+	result := actual.(map[string]interface{})["inputs"].(map[string]interface{})["canary"].([]interface{})[0]
+	assert.Equal(t, "60s", result.(map[string]interface{})["interval"])
+}
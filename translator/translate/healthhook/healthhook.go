@@ -0,0 +1,91 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package healthhook
+
+import (
+	"fmt"
+
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+	parent "github.com/aws/amazon-cloudwatch-agent/translator/translate"
+	"github.com/aws/amazon-cloudwatch-agent/translator/translate/agent"
+)
+
+//
+//	"agent": {
+//		"health_hook": {
+//			"command": "/usr/local/bin/page-oncall",
+//			"webhook_url": "https://events.example.com/cwagent",
+//			"unhealthy_threshold": 300,
+//			"repeat_interval": 3600,
+//			"timeout": 30
+//		}
+//	}
+//
+
+// SectionKey is the hook of the agent section run when a pipeline of the agent keeps failing to publish.
+const SectionKey = "health_hook"
+
+const (
+	commandKey            = "command"
+	webhookURLKey         = "webhook_url"
+	unhealthyThresholdKey = "unhealthy_threshold"
+	repeatIntervalKey     = "repeat_interval"
+	timeoutKey            = "timeout"
+
+	inputPluginKey = "agent_health"
+)
+
+func GetCurPath() string {
+	return agent.GetCurPath() + SectionKey + "/"
+}
+
+type HealthHook struct {
+}
+
+func (h *HealthHook) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	m := input.(map[string]interface{})
+	returnKey = ""
+	returnVal = ""
+	agentMap, ok := m[agent.SectionKey].(map[string]interface{})
+	if !ok {
+		return
+	}
+	hookMap, ok := agentMap[SectionKey].(map[string]interface{})
+	if !ok {
+		return
+	}
+
+	result := map[string]interface{}{}
+	for _, key := range []string{commandKey, webhookURLKey} {
+		if val, ok := hookMap[key].(string); ok && val != "" {
+			result[key] = val
+		}
+	}
+	if len(result) == 0 {
+		translator.AddErrorMessages(GetCurPath(), "command or webhook_url is required for the health hook")
+		return
+	}
+	// the intervals are in seconds
+	for _, key := range []string{unhealthyThresholdKey, repeatIntervalKey} {
+		if val, ok := hookMap[key].(float64); ok {
+			result[key] = fmt.Sprintf("%ds", int(val))
+		}
+	}
+	if val, ok := agent.Timeout(agent.ExecTimeoutKey, hookMap[timeoutKey]); ok {
+		result[timeoutKey] = val
+	}
+
+	returnKey = SectionKey
+	returnVal = map[string]interface{}{
+		"inputs": map[string]interface{}{inputPluginKey: []interface{}{result}},
+	}
+	return
+}
+
+func init() {
+	h := new(HealthHook)
+	parent.RegisterLinuxRule(SectionKey, h)
+	parent.RegisterDarwinRule(SectionKey, h)
+	parent.RegisterWindowsRule(SectionKey, h)
+}
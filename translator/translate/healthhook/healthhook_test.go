@@ -0,0 +1,47 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package healthhook
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHealthHook(t *testing.T) {
+	h := new(HealthHook)
+	var input interface{}
+	err := json.Unmarshal([]byte(`{"agent":{"region":"us-east-1","health_hook":{"command":"/usr/local/bin/page-oncall","unhealthy_threshold":600,"repeat_interval":3600}}}`), &input)
+	assert.NoError(t, err)
+	key, actual := h.ApplyRule(input)
+	assert.Equal(t, SectionKey, key)
+	expected := map[string]interface{}{
+		"inputs": map[string]interface{}{
+			"agent_health": []interface{}{map[string]interface{}{
+				"command":             "/usr/local/bin/page-oncall",
+				"unhealthy_threshold": "600s",
+				"repeat_interval":     "3600s",
+			}},
+		},
+	}
+	assert.Equal(t, expected, actual)
+}
+
+func TestHealthHook_Missing(t *testing.T) {
+	translator.ResetMessages()
+	h := new(HealthHook)
+	var input interface{}
+	assert.NoError(t, json.Unmarshal([]byte(`{"agent":{"region":"us-east-1"}}`), &input))
+	key, _ := h.ApplyRule(input)
+	assert.Equal(t, "", key)
+	assert.Empty(t, translator.ErrorMessages)
+
+	assert.NoError(t, json.Unmarshal([]byte(`{"agent":{"health_hook":{"unhealthy_threshold":600}}}`), &input))
+	key, _ = h.ApplyRule(input)
+	assert.Equal(t, "", key)
+	assert.Len(t, translator.ErrorMessages, 1)
+	translator.ResetMessages()
+}
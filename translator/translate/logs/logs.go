@@ -103,6 +103,10 @@ func (l *Logs) ApplyRule(input interface{}) (returnKey string, returnVal interfa
 			translator.SetMetricPathForOneInput(result, SectionKey, "socket_listener", []string{})
 		}
 
+		if _, ok = inputs["emf_listener"]; ok {
+			translator.SetMetricPathForOneInput(result, SectionKey, "emf_listener", []string{})
+		}
+
 		returnKey = SectionKey
 		returnVal = result
 	}
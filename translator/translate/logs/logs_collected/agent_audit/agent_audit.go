@@ -0,0 +1,75 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package agent_audit
+
+import (
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+	"github.com/aws/amazon-cloudwatch-agent/translator/jsonconfig/mergeJsonRule"
+	"github.com/aws/amazon-cloudwatch-agent/translator/jsonconfig/mergeJsonUtil"
+	"github.com/aws/amazon-cloudwatch-agent/translator/translate/logs"
+	parent "github.com/aws/amazon-cloudwatch-agent/translator/translate/logs/logs_collected"
+	logsutil "github.com/aws/amazon-cloudwatch-agent/translator/translate/logs/util"
+	"github.com/aws/amazon-cloudwatch-agent/translator/translate/util"
+)
+
+type AgentAudit struct {
+}
+
+const (
+	SectionKey                = "agent_audit"
+	LogGroupNameSectionKey    = "log_group_name"
+	LogStreamNameSectionKey   = "log_stream_name"
+	RetentionInDaysSectionKey = "retention_in_days"
+)
+
+func GetCurPath() string {
+	return parent.GetCurPath() + SectionKey + "/"
+}
+
+func (a *AgentAudit) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	im := input.(map[string]interface{})
+	auditInput, ok := im[SectionKey]
+	if !ok {
+		returnKey = ""
+		returnVal = ""
+		return
+	}
+
+	auditConfig := map[string]interface{}{
+		"destination":       "cloudwatchlogs",
+		"file_state_folder": logsutil.GetFileStateFolder(),
+	}
+	_, logGroupName := translator.DefaultCase(LogGroupNameSectionKey, "", auditInput)
+	if logGroupName == "" {
+		translator.AddErrorMessages(GetCurPath(), "log_group_name is required for the agent audit log")
+		returnKey = ""
+		returnVal = ""
+		return
+	}
+	auditConfig[LogGroupNameSectionKey] = util.ResolvePlaceholder(logGroupName.(string), logs.GlobalLogConfig.MetadataInfo)
+	if _, logStreamName := translator.DefaultCase(LogStreamNameSectionKey, "", auditInput); logStreamName != "" {
+		auditConfig[LogStreamNameSectionKey] = util.ResolvePlaceholder(logStreamName.(string), logs.GlobalLogConfig.MetadataInfo)
+	}
+	_, auditConfig[RetentionInDaysSectionKey] = translator.DefaultRetentionInDaysCase(RetentionInDaysSectionKey, float64(-1), auditInput)
+
+	returnKey = "inputs"
+	returnVal = map[string]interface{}{
+		"agent_audit": []interface{}{auditConfig},
+	}
+	return
+}
+
+var MergeRuleMap = map[string]mergeJsonRule.MergeRule{}
+
+func (a *AgentAudit) Merge(source map[string]interface{}, result map[string]interface{}) {
+	mergeJsonUtil.MergeMap(source, result, SectionKey, MergeRuleMap, GetCurPath())
+}
+
+func init() {
+	a := new(AgentAudit)
+	parent.RegisterLinuxRule(SectionKey, a)
+	parent.RegisterDarwinRule(SectionKey, a)
+	parent.RegisterWindowsRule(SectionKey, a)
+	parent.MergeRuleMap[SectionKey] = a
+}
@@ -0,0 +1,68 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package agent_audit
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+	"github.com/aws/amazon-cloudwatch-agent/translator/config"
+	"github.com/aws/amazon-cloudwatch-agent/translator/context"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApplyRule(t *testing.T) {
+	a := new(AgentAudit)
+	var input interface{}
+	err := json.Unmarshal([]byte(`{
+		"agent_audit": {
+			"log_group_name": "audit",
+			"log_stream_name": "stream",
+			"retention_in_days": 30
+		}
+	}`), &input)
+	assert.NoError(t, err)
+
+	context.CurrentContext().SetOs(config.OS_TYPE_LINUX)
+	translator.SetTargetPlatform(config.OS_TYPE_LINUX)
+	key, actual := a.ApplyRule(input)
+	expected := map[string]interface{}{
+		"agent_audit": []interface{}{
+			map[string]interface{}{
+				"destination":       "cloudwatchlogs",
+				"file_state_folder": "/opt/aws/amazon-cloudwatch-agent/logs/state",
+				"log_group_name":    "audit",
+				"log_stream_name":   "stream",
+				"retention_in_days": 30,
+			},
+		},
+	}
+	assert.Equal(t, "inputs", key)
+	assert.Equal(t, expected, actual)
+}
+
+func TestApplyRule_MissingLogGroupName(t *testing.T) {
+	translator.ResetMessages()
+	a := new(AgentAudit)
+	var input interface{}
+	err := json.Unmarshal([]byte(`{"agent_audit": {"log_stream_name": "stream"}}`), &input)
+	assert.NoError(t, err)
+
+	key, _ := a.ApplyRule(input)
+	assert.Equal(t, "", key)
+	assert.Equal(t, 1, len(translator.ErrorMessages))
+}
+
+func TestApplyRule_NotConfigured(t *testing.T) {
+	a := new(AgentAudit)
+	var input interface{}
+	err := json.Unmarshal([]byte(`{"files": {}}`), &input)
+	assert.NoError(t, err)
+
+	key, val := a.ApplyRule(input)
+	assert.Equal(t, "", key)
+	assert.Equal(t, "", val)
+}
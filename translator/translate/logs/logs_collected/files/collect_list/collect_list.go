@@ -65,6 +65,7 @@ func (f *FileConfig) ApplyRule(input interface{}) (returnKey string, returnVal i
 			res = append(res, result)
 		}
 		logUtil.ValidateLogRetentionSettings(res, GetCurPath())
+		logUtil.ValidateLogNames(res, GetCurPath())
 		outputLogConfig(res)
 	} else {
 		returnKey = ""
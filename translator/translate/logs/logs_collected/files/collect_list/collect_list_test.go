@@ -414,6 +414,147 @@ func TestEncoding_Invalid(t *testing.T) {
 	assert.Equal(t, "Under path : /logs/logs_collected/files/collect_list/encoding | Error : Encoding xxx is an invalid value.", translator.ErrorMessages[len(translator.ErrorMessages)-1])
 }
 
+func TestLogFormat(t *testing.T) {
+	f := new(FileConfig)
+	var input interface{}
+	e := json.Unmarshal([]byte(`{
+		"collect_list":[
+			{
+				"file_path":"C:\\inetpub\\logs\\LogFiles\\W3SVC1\\*.log",
+				"log_format":"w3c"
+			}
+		]
+	}`), &input)
+	if e != nil {
+		assert.Fail(t, e.Error())
+	}
+	_, val := f.ApplyRule(input)
+	expectVal := []interface{}{map[string]interface{}{
+		"file_path":         "C:\\inetpub\\logs\\LogFiles\\W3SVC1\\*.log",
+		"from_beginning":    true,
+		"pipe":              false,
+		"retention_in_days": -1,
+		"log_format":        "w3c",
+	}}
+	assert.Equal(t, expectVal, val)
+}
+
+func TestLogFormat_Invalid(t *testing.T) {
+	translator.ResetMessages()
+	f := new(FileConfig)
+	var input interface{}
+	e := json.Unmarshal([]byte(`{
+		"collect_list":[
+			{
+				"file_path":"path1",
+				"log_format":"csv"
+			}
+		]
+	}`), &input)
+	if e != nil {
+		assert.Fail(t, e.Error())
+	}
+	_, val := f.ApplyRule(input)
+	expectVal := []interface{}{map[string]interface{}{
+		"file_path":         "path1",
+		"from_beginning":    true,
+		"pipe":              false,
+		"retention_in_days": -1,
+	}}
+	assert.Equal(t, expectVal, val)
+	assert.Equal(t, 1, len(translator.ErrorMessages))
+	assert.Equal(t, "Under path : /logs/logs_collected/files/collect_list/log_format | Error : log_format csv is invalid, the supported format is w3c.", translator.ErrorMessages[len(translator.ErrorMessages)-1])
+}
+
+func TestLogStreamBuckets(t *testing.T) {
+	f := new(FileConfig)
+	var input interface{}
+	e := json.Unmarshal([]byte(`{
+		"collect_list":[
+			{
+				"file_path":"/var/log/app/*.log",
+				"log_group_name":"app",
+				"log_stream_name":"{bucket}",
+				"publish_multi_logs":true,
+				"log_stream_buckets":16
+			}
+		]
+	}`), &input)
+	if e != nil {
+		assert.Fail(t, e.Error())
+	}
+	_, val := f.ApplyRule(input)
+	expectVal := []interface{}{map[string]interface{}{
+		"file_path":          "/var/log/app/*.log",
+		"log_group_name":     "app",
+		"log_stream_name":    "{bucket}",
+		"publish_multi_logs": true,
+		"log_stream_buckets": 16,
+		"from_beginning":     true,
+		"pipe":               false,
+		"retention_in_days":  -1,
+	}}
+	assert.Equal(t, expectVal, val)
+}
+
+func TestLogStreamBuckets_Invalid(t *testing.T) {
+	translator.ResetMessages()
+	f := new(FileConfig)
+	var input interface{}
+	e := json.Unmarshal([]byte(`{
+		"collect_list":[
+			{
+				"file_path":"path1",
+				"log_stream_buckets":1.5,
+				"publish_multi_logs":true
+			},
+			{
+				"file_path":"path2",
+				"log_stream_buckets":8
+			}
+		]
+	}`), &input)
+	if e != nil {
+		assert.Fail(t, e.Error())
+	}
+	_, val := f.ApplyRule(input)
+	for _, config := range val.([]interface{}) {
+		assert.NotContains(t, config, "log_stream_buckets")
+	}
+	assert.Equal(t, []string{
+		"Under path : /logs/logs_collected/files/collect_list/log_stream_buckets | Error : log_stream_buckets 1.5 is invalid, it must be a positive integer.",
+		"Under path : /logs/logs_collected/files/collect_list/log_stream_buckets | Error : log_stream_buckets requires publish_multi_logs to be true.",
+	}, translator.ErrorMessages)
+}
+
+func TestPriority(t *testing.T) {
+	translator.ResetMessages()
+	f := new(FileConfig)
+	var input interface{}
+	e := json.Unmarshal([]byte(`{
+		"collect_list":[
+			{
+				"file_path":"/var/log/audit/audit.log",
+				"priority":10
+			},
+			{
+				"file_path":"/var/log/app.log",
+				"priority":0
+			}
+		]
+	}`), &input)
+	if e != nil {
+		assert.Fail(t, e.Error())
+	}
+	_, val := f.ApplyRule(input)
+	configs := val.([]interface{})
+	assert.Equal(t, 10, configs[0].(map[string]interface{})["priority"])
+	assert.NotContains(t, configs[1], "priority")
+	assert.Equal(t, []string{
+		"Under path : /logs/logs_collected/files/collect_list/priority | Error : priority 0 is invalid, it must be a positive integer.",
+	}, translator.ErrorMessages)
+}
+
 func TestAutoRemoval(t *testing.T) {
 	f := new(FileConfig)
 	var input interface{}
@@ -0,0 +1,38 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package collect_list
+
+import (
+	"fmt"
+
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+)
+
+const (
+	LogFormatSectionKey = "log_format"
+	logFormatW3C        = "w3c"
+)
+
+type LogFormat struct {
+}
+
+func (l *LogFormat) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	key, val := translator.DefaultCase(LogFormatSectionKey, "", input)
+	if val == "" {
+		return
+	}
+	if val != logFormatW3C {
+		translator.AddErrorMessages(GetCurPath()+LogFormatSectionKey, fmt.Sprintf("log_format %v is invalid, the supported format is %s.", val, logFormatW3C))
+		return
+	}
+	returnKey = key
+	returnVal = val
+	return
+}
+
+func init() {
+	l := new(LogFormat)
+	r := []Rule{l}
+	RegisterRule(LogFormatSectionKey, r)
+}
@@ -0,0 +1,40 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package collect_list
+
+import (
+	"fmt"
+
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+)
+
+const LogStreamBucketsSectionKey = "log_stream_buckets"
+
+type LogStreamBuckets struct {
+}
+
+func (l *LogStreamBuckets) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	_, val := translator.DefaultCase(LogStreamBucketsSectionKey, "", input)
+	if val == "" {
+		return
+	}
+	buckets, ok := val.(float64)
+	if !ok || buckets < 1 || buckets != float64(int(buckets)) {
+		translator.AddErrorMessages(GetCurPath()+LogStreamBucketsSectionKey, fmt.Sprintf("log_stream_buckets %v is invalid, it must be a positive integer.", val))
+		return
+	}
+	if publishMultiLogs, _ := input.(map[string]interface{})[PublishMultiLogsSectionKey].(bool); !publishMultiLogs {
+		translator.AddErrorMessages(GetCurPath()+LogStreamBucketsSectionKey, "log_stream_buckets requires publish_multi_logs to be true.")
+		return
+	}
+	returnKey = LogStreamBucketsSectionKey
+	returnVal = int(buckets)
+	return
+}
+
+func init() {
+	l := new(LogStreamBuckets)
+	r := []Rule{l}
+	RegisterRule(LogStreamBucketsSectionKey, r)
+}
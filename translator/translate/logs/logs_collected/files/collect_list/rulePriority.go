@@ -0,0 +1,37 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package collect_list
+
+import (
+	"fmt"
+
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+)
+
+const PrioritySectionKey = "priority"
+
+// Priority is the weight of the file among the files of its log stream when the stream is slower than them.
+type Priority struct {
+}
+
+func (p *Priority) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	_, val := translator.DefaultCase(PrioritySectionKey, "", input)
+	if val == "" {
+		return
+	}
+	priority, ok := val.(float64)
+	if !ok || priority < 1 || priority != float64(int(priority)) {
+		translator.AddErrorMessages(GetCurPath()+PrioritySectionKey, fmt.Sprintf("priority %v is invalid, it must be a positive integer.", val))
+		return
+	}
+	returnKey = PrioritySectionKey
+	returnVal = int(priority)
+	return
+}
+
+func init() {
+	p := new(Priority)
+	r := []Rule{p}
+	RegisterRule(PrioritySectionKey, r)
+}
@@ -59,6 +59,7 @@ func (c *CollectList) ApplyRule(input interface{}) (returnKey string, returnVal
 		}
 	}
 	logUtil.ValidateLogRetentionSettings(result, GetCurPath())
+	logUtil.ValidateLogNames(result, GetCurPath())
 	return EventConfigTomlKey, result
 }
 
@@ -197,3 +197,147 @@ func TestLogs_EndpointOverride(t *testing.T) {
 
 	ctx.SetMode(config.ModeEC2) //reset back to default mode
 }
+
+func TestLogs_RetentionCheck(t *testing.T) {
+	l := new(Logs)
+	agent.Global_Config.Region = "us-east-1"
+
+	var input interface{}
+	err := json.Unmarshal([]byte(`{"logs":{"retention_check_interval":600,"retention_restore":true}}`), &input)
+	if err != nil {
+		assert.Fail(t, err.Error())
+	}
+
+	ctx := context.CurrentContext()
+	ctx.SetMode(config.ModeOnPrem)
+
+	hostname, _ := os.Hostname()
+	_, actual := l.ApplyRule(input)
+	expected := map[string]interface{}{
+		"outputs": map[string]interface{}{
+			"cloudwatchlogs": []interface{}{
+				map[string]interface{}{
+					"region":                   "us-east-1",
+					"log_stream_name":          hostname,
+					"force_flush_interval":     "5s",
+					"retention_check_interval": "600s",
+					"retention_restore":        true,
+					"tagexclude":               []string{"metricPath"},
+					"tagpass":                  map[string][]string{"metricPath": {"logs"}},
+				},
+			},
+		},
+	}
+
+	assert.Equal(t, expected, actual, "Expected to be equal")
+
+	ctx.SetMode(config.ModeEC2) //reset back to default mode
+}
+
+func TestLogs_Retries(t *testing.T) {
+	l := new(Logs)
+	agent.Global_Config.Region = "us-east-1"
+
+	var input interface{}
+	err := json.Unmarshal([]byte(`{"logs":{"max_retries":8,"max_backoff":30,"max_requests_per_second":20.5}}`), &input)
+	if err != nil {
+		assert.Fail(t, err.Error())
+	}
+
+	ctx := context.CurrentContext()
+	ctx.SetMode(config.ModeOnPrem)
+
+	hostname, _ := os.Hostname()
+	_, actual := l.ApplyRule(input)
+	expected := map[string]interface{}{
+		"outputs": map[string]interface{}{
+			"cloudwatchlogs": []interface{}{
+				map[string]interface{}{
+					"region":                  "us-east-1",
+					"log_stream_name":         hostname,
+					"force_flush_interval":    "5s",
+					"max_retries":             8,
+					"max_backoff":             "30s",
+					"max_requests_per_second": 20.5,
+					"tagexclude":              []string{"metricPath"},
+					"tagpass":                 map[string][]string{"metricPath": {"logs"}},
+				},
+			},
+		},
+	}
+
+	assert.Equal(t, expected, actual, "Expected to be equal")
+
+	ctx.SetMode(config.ModeEC2) //reset back to default mode
+}
+
+func TestLogs_RejectedLogEventsFile(t *testing.T) {
+	l := new(Logs)
+	agent.Global_Config.Region = "us-east-1"
+
+	var input interface{}
+	err := json.Unmarshal([]byte(`{"logs":{"rejected_log_events_file":"/var/log/rejected_log_events.json"}}`), &input)
+	if err != nil {
+		assert.Fail(t, err.Error())
+	}
+
+	ctx := context.CurrentContext()
+	ctx.SetMode(config.ModeOnPrem)
+
+	hostname, _ := os.Hostname()
+	_, actual := l.ApplyRule(input)
+	expected := map[string]interface{}{
+		"outputs": map[string]interface{}{
+			"cloudwatchlogs": []interface{}{
+				map[string]interface{}{
+					"region":                   "us-east-1",
+					"log_stream_name":          hostname,
+					"force_flush_interval":     "5s",
+					"rejected_log_events_file": "/var/log/rejected_log_events.json",
+					"tagexclude":               []string{"metricPath"},
+					"tagpass":                  map[string][]string{"metricPath": {"logs"}},
+				},
+			},
+		},
+	}
+
+	assert.Equal(t, expected, actual, "Expected to be equal")
+
+	ctx.SetMode(config.ModeEC2) //reset back to default mode
+}
+
+func TestLogs_LogNameSanitization(t *testing.T) {
+	l := new(Logs)
+	agent.Global_Config.Region = "us-east-1"
+
+	var input interface{}
+	err := json.Unmarshal([]byte(`{"logs":{"log_name_replacement":"-","log_name_overflow":"truncate"}}`), &input)
+	if err != nil {
+		assert.Fail(t, err.Error())
+	}
+
+	ctx := context.CurrentContext()
+	ctx.SetMode(config.ModeOnPrem)
+
+	hostname, _ := os.Hostname()
+	_, actual := l.ApplyRule(input)
+	expected := map[string]interface{}{
+		"outputs": map[string]interface{}{
+			"cloudwatchlogs": []interface{}{
+				map[string]interface{}{
+					"region":               "us-east-1",
+					"log_stream_name":      hostname,
+					"force_flush_interval": "5s",
+					"log_name_replacement": "-",
+					"log_name_overflow":    "truncate",
+					"tagexclude":           []string{"metricPath"},
+					"tagpass":              map[string][]string{"metricPath": {"logs"}},
+				},
+			},
+		},
+	}
+
+	assert.Equal(t, expected, actual, "Expected to be equal")
+
+	ctx.SetMode(config.ModeEC2) //reset back to default mode
+}
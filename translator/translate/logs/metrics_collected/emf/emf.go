@@ -20,6 +20,11 @@ import (
 //
 const SectionKey = "emf"
 
+const (
+	socketListener = "socket_listener"
+	emfListener    = "emf_listener"
+)
+
 var ChildRule = map[string]translator.Rule{}
 
 func GetCurPath() string {
@@ -31,6 +36,16 @@ func RegisterRule(fieldname string, r translator.Rule) {
 	ChildRule[fieldname] = r
 }
 
+// listenerKey returns the input receiving the events, the emf_listener when a limit of the clients is configured.
+func listenerKey(result map[string]interface{}) string {
+	for _, key := range []string{SectionKeyRateLimit, SectionKeyRateLimitBurst, SectionKeyMaxPayloadSize} {
+		if _, ok := result[key]; ok {
+			return emfListener
+		}
+	}
+	return socketListener
+}
+
 type EMF struct {
 }
 
@@ -68,7 +83,7 @@ func (obj *EMF) ApplyRule(input interface{}) (returnKey string, returnVal interf
 			result = translator.ProcessRuleToApply(m[SectionKey], ChildRule, result)
 			resArray = append(resArray, result)
 		}
-		returnKey = "socket_listener"
+		returnKey = listenerKey(result)
 		returnVal = resArray
 	}
 	return
@@ -54,3 +54,31 @@ func TestEMF_MinimumConfig(t *testing.T) {
 
 	assert.Equal(t, expect, actual)
 }
+
+func TestEMF_Limits(t *testing.T) {
+	obj := new(EMF)
+	var input interface{}
+	err := json.Unmarshal([]byte(`{"emf": {
+					"service_address": "tcp://127.0.0.1:25888",
+					"rate_limit": 500,
+					"rate_limit_burst": 1000,
+					"max_payload_size": 65536
+					}}`), &input)
+	assert.NoError(t, err)
+
+	key, actual := obj.ApplyRule(input)
+
+	expect := []interface{}{
+		map[string]interface{}{
+			"service_address":  "tcp://127.0.0.1:25888",
+			"data_format":      "emf",
+			"name_override":    "emf",
+			"rate_limit":       float64(500),
+			"rate_limit_burst": 1000,
+			"max_payload_size": 65536,
+		},
+	}
+
+	assert.Equal(t, "emf_listener", key)
+	assert.Equal(t, expect, actual)
+}
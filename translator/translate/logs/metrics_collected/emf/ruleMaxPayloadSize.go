@@ -0,0 +1,27 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package emf
+
+import (
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+)
+
+// MaxPayloadSize is the maximum size in bytes of an event admitted by the listener.
+type MaxPayloadSize struct {
+}
+
+const SectionKeyMaxPayloadSize = "max_payload_size"
+
+func (obj *MaxPayloadSize) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	if _, ok := input.(map[string]interface{})[SectionKeyMaxPayloadSize]; !ok {
+		return "", nil
+	}
+	returnKey, returnVal = translator.DefaultIntegralCase(SectionKeyMaxPayloadSize, float64(0), input)
+	return
+}
+
+func init() {
+	obj := new(MaxPayloadSize)
+	RegisterRule(SectionKeyMaxPayloadSize, obj)
+}
@@ -0,0 +1,27 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package emf
+
+import (
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+)
+
+// RateLimit is the events admitted per second for each client of the listener.
+type RateLimit struct {
+}
+
+const SectionKeyRateLimit = "rate_limit"
+
+func (obj *RateLimit) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	if _, ok := input.(map[string]interface{})[SectionKeyRateLimit]; !ok {
+		return "", nil
+	}
+	returnKey, returnVal = translator.DefaultCase(SectionKeyRateLimit, float64(0), input)
+	return
+}
+
+func init() {
+	obj := new(RateLimit)
+	RegisterRule(SectionKeyRateLimit, obj)
+}
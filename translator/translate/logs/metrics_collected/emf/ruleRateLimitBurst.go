@@ -0,0 +1,27 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package emf
+
+import (
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+)
+
+// RateLimitBurst is the events admitted at once above the rate limit.
+type RateLimitBurst struct {
+}
+
+const SectionKeyRateLimitBurst = "rate_limit_burst"
+
+func (obj *RateLimitBurst) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	if _, ok := input.(map[string]interface{})[SectionKeyRateLimitBurst]; !ok {
+		return "", nil
+	}
+	returnKey, returnVal = translator.DefaultIntegralCase(SectionKeyRateLimitBurst, float64(0), input)
+	return
+}
+
+func init() {
+	obj := new(RateLimitBurst)
+	RegisterRule(SectionKeyRateLimitBurst, obj)
+}
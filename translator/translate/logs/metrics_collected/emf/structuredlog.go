@@ -57,7 +57,7 @@ func (obj *StructuredLog) ApplyRule(input interface{}) (returnKey string, return
 			result = translator.ProcessRuleToApply(m[SectionKeyStructuredLog], ChildRule, result)
 			resArray = append(resArray, result)
 		}
-		returnKey = "socket_listener"
+		returnKey = listenerKey(result)
 		returnVal = resArray
 	}
 	return
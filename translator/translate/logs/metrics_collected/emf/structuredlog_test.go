@@ -54,3 +54,26 @@ func TestStructuredLog_MinimumConfig(t *testing.T) {
 
 	assert.Equal(t, expect, actual)
 }
+
+func TestStructuredLog_Limits(t *testing.T) {
+	obj := new(StructuredLog)
+	var input interface{}
+	err := json.Unmarshal([]byte(`{"structuredlog": {
+					"max_payload_size": 65536
+					}}`), &input)
+	assert.NoError(t, err)
+
+	key, actual := obj.ApplyRule(input)
+
+	expect := []interface{}{
+		map[string]interface{}{
+			"service_address":  "udp://127.0.0.1:25888",
+			"data_format":      "emf",
+			"name_override":    "emf",
+			"max_payload_size": 65536,
+		},
+	}
+
+	assert.Equal(t, "emf_listener", key)
+	assert.Equal(t, expect, actual)
+}
@@ -0,0 +1,33 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package instance
+
+import (
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+)
+
+const (
+	SectionKeySDInstanceTags = "sd_instance_tags"
+)
+
+type SDInstanceTags struct {
+}
+
+// Mandatory Key, the instances must have all the tags
+func (d *SDInstanceTags) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	im := input.(map[string]interface{})
+	if val, ok := im[SectionKeySDInstanceTags].(map[string]interface{}); !ok || len(val) == 0 {
+		returnKey = ""
+		returnVal = ""
+		translator.AddErrorMessages(GetCurPath()+SectionKeySDInstanceTags, "mandatory key: sd_instance_tags is not defined.")
+	} else {
+		returnKey = SectionKeySDInstanceTags
+		returnVal = val
+	}
+	return
+}
+
+func init() {
+	RegisterRule(SectionKeySDInstanceTags, new(SDInstanceTags))
+}
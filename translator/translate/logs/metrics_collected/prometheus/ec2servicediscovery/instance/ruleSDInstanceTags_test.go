@@ -0,0 +1,30 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package instance
+
+import (
+	"testing"
+
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSDInstanceTags(t *testing.T) {
+	r := new(SDInstanceTags)
+	tags := map[string]interface{}{"Cluster": "kafka-prod"}
+	key, val := r.ApplyRule(map[string]interface{}{SectionKeySDInstanceTags: tags})
+	assert.Equal(t, SectionKeySDInstanceTags, key)
+	assert.Equal(t, tags, val)
+
+	translator.ResetMessages()
+	key, _ = r.ApplyRule(map[string]interface{}{SectionKeySDInstanceTags: map[string]interface{}{}})
+	assert.Equal(t, "", key)
+	assert.Len(t, translator.ErrorMessages, 1)
+
+	translator.ResetMessages()
+	key, _ = r.ApplyRule(map[string]interface{}{})
+	assert.Equal(t, "", key)
+	assert.Len(t, translator.ErrorMessages, 1)
+	translator.ResetMessages()
+}
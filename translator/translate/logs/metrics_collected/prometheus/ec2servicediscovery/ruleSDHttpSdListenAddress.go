@@ -0,0 +1,28 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package ec2servicediscovery
+
+const (
+	SectionKeySDHttpSdListenAddress = "sd_http_sd_listen_address"
+)
+
+type SDHttpSdListenAddress struct {
+}
+
+// Optional Key
+func (d *SDHttpSdListenAddress) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	im := input.(map[string]interface{})
+	if val, ok := im[SectionKeySDHttpSdListenAddress]; !ok {
+		returnKey = ""
+		returnVal = ""
+	} else {
+		returnKey = SectionKeySDHttpSdListenAddress
+		returnVal = val
+	}
+	return
+}
+
+func init() {
+	RegisterRule(SectionKeySDHttpSdListenAddress, new(SDHttpSdListenAddress))
+}
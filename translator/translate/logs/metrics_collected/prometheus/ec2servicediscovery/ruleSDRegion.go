@@ -0,0 +1,32 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package ec2servicediscovery
+
+import (
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+	"github.com/aws/amazon-cloudwatch-agent/translator/translate/agent"
+)
+
+const (
+	SectionKeySDRegion = "sd_region"
+)
+
+type SDRegion struct {
+}
+
+// The region of the agent by default
+func (d *SDRegion) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	returnKey, returnVal = translator.DefaultCase(SectionKeySDRegion, "", input)
+	if returnVal == "" {
+		returnVal = agent.Global_Config.Region
+	}
+	if returnVal == "" {
+		translator.AddErrorMessages(GetCurPath(), "EC2 service discovery region is not defined")
+	}
+	return
+}
+
+func init() {
+	RegisterRule(SectionKeySDRegion, new(SDRegion))
+}
@@ -0,0 +1,28 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package ec2servicediscovery
+
+const (
+	SectionKeySDVpcId = "sd_vpc_id"
+)
+
+type SDVpcId struct {
+}
+
+// Optional Key
+func (d *SDVpcId) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	im := input.(map[string]interface{})
+	if val, ok := im[SectionKeySDVpcId]; !ok {
+		returnKey = ""
+		returnVal = ""
+	} else {
+		returnKey = SectionKeySDVpcId
+		returnVal = val
+	}
+	return
+}
+
+func init() {
+	RegisterRule(SectionKeySDVpcId, new(SDVpcId))
+}
@@ -0,0 +1,25 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package emfprocessor
+
+const (
+	SectionKeyMetricUnitByJob = "metric_unit_by_job"
+)
+
+type MetricUnitByJob struct {
+}
+
+func (mub *MetricUnitByJob) ApplyRule(input interface{}) (string, interface{}) {
+	im := input.(map[string]interface{})
+
+	if val, ok := im[SectionKeyMetricUnitByJob]; !ok {
+		return "", nil
+	} else {
+		return SectionKeyMetricUnitByJob, val
+	}
+}
+
+func init() {
+	RegisterRule(SectionKeyMetricUnitByJob, new(MetricUnitByJob))
+}
@@ -0,0 +1,36 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package logs
+
+import (
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+)
+
+const (
+	LogNameReplacementSectionKey = "log_name_replacement"
+	LogNameOverflowSectionKey    = "log_name_overflow"
+)
+
+// LogNameSanitization sets how the log group and stream names CloudWatch Logs rejects are made valid, the output
+// replaces the invalid characters with "_" and hashes the names over the limit by default.
+type LogNameSanitization struct {
+}
+
+func (r *LogNameSanitization) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	res := map[string]interface{}{}
+	for _, sectionKey := range []string{LogNameReplacementSectionKey, LogNameOverflowSectionKey} {
+		if key, val := translator.DefaultCase(sectionKey, "", input); val != "" {
+			res[key] = val
+		}
+	}
+	if len(res) > 0 {
+		returnKey = Output_Cloudwatch_Logs
+		returnVal = res
+	}
+	return
+}
+
+func init() {
+	RegisterRule("logNameSanitization", new(LogNameSanitization))
+}
@@ -0,0 +1,28 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package logs
+
+import (
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+)
+
+const RejectedLogEventsFileSectionKey = "rejected_log_events_file"
+
+// RejectedLogEventsFile sets the file the log events rejected by CloudWatch Logs are written to, instead of being
+// discarded.
+type RejectedLogEventsFile struct {
+}
+
+func (r *RejectedLogEventsFile) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	key, val := translator.DefaultCase(RejectedLogEventsFileSectionKey, "", input)
+	if val != "" {
+		returnKey = Output_Cloudwatch_Logs
+		returnVal = map[string]interface{}{key: val}
+	}
+	return
+}
+
+func init() {
+	RegisterRule(RejectedLogEventsFileSectionKey, new(RejectedLogEventsFile))
+}
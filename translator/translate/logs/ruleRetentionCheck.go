@@ -0,0 +1,40 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package logs
+
+import (
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+)
+
+const (
+	RetentionCheckIntervalSectionKey = "retention_check_interval"
+	RetentionRestoreSectionKey       = "retention_restore"
+)
+
+// RetentionCheck sets how the retention of the log groups with a retention_in_days is reconciled, the output checks
+// it every hour and only reports the drifts by default.
+type RetentionCheck struct {
+}
+
+func (r *RetentionCheck) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	im := input.(map[string]interface{})
+	res := map[string]interface{}{}
+	if _, ok := im[RetentionCheckIntervalSectionKey]; ok {
+		key, val := translator.DefaultTimeIntervalCase(RetentionCheckIntervalSectionKey, float64(3600), input)
+		res[key] = val
+	}
+	if _, ok := im[RetentionRestoreSectionKey]; ok {
+		key, val := translator.DefaultCase(RetentionRestoreSectionKey, false, input)
+		res[key] = val
+	}
+	if len(res) > 0 {
+		returnKey = Output_Cloudwatch_Logs
+		returnVal = res
+	}
+	return
+}
+
+func init() {
+	RegisterRule("retentionCheck", new(RetentionCheck))
+}
@@ -0,0 +1,45 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package logs
+
+import (
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+)
+
+const (
+	MaxRetriesSectionKey           = "max_retries"
+	MaxBackoffSectionKey           = "max_backoff"
+	MaxRequestsPerSecondSectionKey = "max_requests_per_second"
+)
+
+// Retries bounds the attempts of the PutLogEvents requests and the backoff between them, and limits the rate of the
+// requests, like the same options of the metrics section.
+type Retries struct {
+}
+
+func (r *Retries) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	im := input.(map[string]interface{})
+	res := map[string]interface{}{}
+	if _, ok := im[MaxRetriesSectionKey]; ok {
+		key, val := translator.DefaultIntegralCase(MaxRetriesSectionKey, "", input)
+		res[key] = val
+	}
+	if _, ok := im[MaxBackoffSectionKey]; ok {
+		key, val := translator.DefaultTimeIntervalCase(MaxBackoffSectionKey, "", input)
+		res[key] = val
+	}
+	if _, ok := im[MaxRequestsPerSecondSectionKey]; ok {
+		key, val := translator.DefaultCase(MaxRequestsPerSecondSectionKey, "", input)
+		res[key] = val
+	}
+	if len(res) > 0 {
+		returnKey = Output_Cloudwatch_Logs
+		returnVal = res
+	}
+	return
+}
+
+func init() {
+	RegisterRule("retries", new(Retries))
+}
@@ -0,0 +1,33 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package logs
+
+import (
+	"github.com/aws/amazon-cloudwatch-agent/translator/translate/agent"
+)
+
+// Timeouts are the timeouts of the calls to CloudWatch Logs, they override the timeouts of the agent section.
+type Timeouts struct {
+}
+
+func (r *Timeouts) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	res := map[string]interface{}{}
+	overrides, _ := input.(map[string]interface{})[agent.TimeoutsKey].(map[string]interface{})
+	if val, ok := agent.Timeout(agent.APICallTimeoutKey, overrides[agent.APICallTimeoutKey]); ok {
+		res["api_call_timeout"] = val
+	}
+	if val, ok := agent.Timeout(agent.DNSResolutionTimeoutKey, overrides[agent.DNSResolutionTimeoutKey]); ok {
+		res["dns_resolution_timeout"] = val
+	}
+	if len(res) > 0 {
+		returnKey = Output_Cloudwatch_Logs
+		returnVal = res
+	}
+	return
+}
+
+func init() {
+	r := new(Timeouts)
+	RegisterRule(agent.TimeoutsKey, r)
+}
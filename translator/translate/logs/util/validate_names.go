@@ -0,0 +1,31 @@
+package util
+
+import (
+	"fmt"
+
+	"github.com/aws/amazon-cloudwatch-agent/internal/logscommon"
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+)
+
+const logStreamKey = "log_stream_name"
+
+// ValidateLogNames reports the log group and stream names CloudWatch Logs rejects once their placeholders are
+// resolved, the output sanitizes them with the log_name_replacement and log_name_overflow of the logs section.
+func ValidateLogNames(logConfigs []interface{}, currPath string) {
+	for _, logConfig := range logConfigs {
+		logConfigMap, ok := logConfig.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if logGroup, ok := logConfigMap[logGroupKey].(string); ok && !logscommon.ValidLogGroupName(logGroup) {
+			translator.AddInfoMessages(
+				currPath,
+				fmt.Sprintf("log_group_name %q has characters or a length CloudWatch Logs rejects, it is sanitized when published", logGroup))
+		}
+		if logStream, ok := logConfigMap[logStreamKey].(string); ok && !logscommon.ValidLogStreamName(logStream) {
+			translator.AddInfoMessages(
+				currPath,
+				fmt.Sprintf("log_stream_name %q has characters or a length CloudWatch Logs rejects, it is sanitized when published", logStream))
+		}
+	}
+}
@@ -5,5 +5,8 @@ package config
 
 // TagDenyList This served as the denylist tag name, which is registered under the plugin name
 var TagDenyList = map[string][]string{
+	"containerd": {"container_id"},
+	"docker":     {"container_id"},
 	"nvidia_smi": {"compute_mode", "pstate", "uuid"},
+	"timesync":   {"reference_id"},
 }
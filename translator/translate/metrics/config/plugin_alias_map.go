@@ -3,6 +3,7 @@ package config
 // pluginAliasMap This provides the real plugin name mapping to the measurement name in user config
 var pluginAliasMap = map[string]string{
 	"nvidia_gpu": "nvidia_smi",
+	"jmx":        "jolokia2_agent",
 }
 
 func GetRealPluginName(inputPluginName string) string {
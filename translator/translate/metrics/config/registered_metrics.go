@@ -8,19 +8,64 @@ package config
 var Registered_Metrics_Linux = map[string][]string{
 	"cpu": {"time_active", "time_guest", "time_guest_nice", "time_idle", "time_iowait", "time_irq", "time_nice", "time_softirq", "time_steal", "time_system", "time_user",
 		"usage_active", "usage_guest", "usage_guest_nice", "usage_idle", "usage_iowait", "usage_irq", "usage_nice", "usage_softirq", "usage_steal", "usage_system", "usage_user"},
-	"disk":      {"free", "inodes_free", "inodes_total", "inodes_used", "total", "used", "used_percent"},
-	"diskio":    {"iops_in_progress", "io_time", "reads", "read_bytes", "read_time", "writes", "write_bytes", "write_time"},
-	"swap":      {"free", "used", "used_percent"},
-	"mem":       {"active", "available", "available_percent", "buffered", "cached", "free", "inactive", "total", "used", "used_percent"},
+	"disk": {"free", "inodes_free", "inodes_total", "inodes_used", "total", "used", "used_percent"},
+	"diskio": {"iops_in_progress", "io_time", "reads", "read_bytes", "read_time", "writes", "write_bytes", "write_time",
+		"read_latency", "write_latency"},
+	"swap": {"free", "used", "used_percent"},
+	"mem": {"active", "available", "available_percent", "buffered", "cached", "free", "inactive", "total", "used", "used_percent",
+		"numa_free", "numa_foreign", "numa_hit", "numa_interleave_hit", "numa_local", "numa_miss", "numa_other", "numa_total", "numa_used", "numa_used_percent"},
 	"net":       {"bytes_sent", "bytes_recv", "drop_in", "drop_out", "err_in", "err_out", "packets_sent", "packets_recv"},
 	"netstat":   {"tcp_close", "tcp_close_wait", "tcp_closing", "tcp_established", "tcp_fin_wait1", "tcp_fin_wait2", "tcp_last_ack", "tcp_listen", "tcp_none", "tcp_syn_sent", "tcp_syn_recv", "tcp_time_wait", "udp_socket"},
 	"processes": {"blocked", "dead", "idle", "paging", "running", "sleeping", "stopped", "total", "total_threads", "wait", "zombies"},
-	"internal":  {"memstats_alloc_bytes", "memstats_heap_in_use_bytes", "agent_metrics_dropped", "agent_metrics_gathered"},
+	"internal": {"memstats_alloc_bytes", "memstats_heap_in_use_bytes", "agent_metrics_dropped", "agent_metrics_gathered",
+		"emf_listener_events_accepted", "emf_listener_events_throttled", "emf_listener_events_oversized",
+		"gather_gather_time_ns", "gather_timeouts", "gather_skipped_intervals", "cloudwatch_datums_quarantined"},
+	"timesync": {"offset", "jitter", "stratum", "synced", "root_delay", "root_dispersion", "frequency"},
+	"systemd":  {"active", "failed", "restart_count", "restarts"},
+	"docker": {"cpu_usage_percent", "cpu_usage_total", "cpu_throttled_periods", "cpu_throttled_time", "mem_usage", "mem_limit", "mem_usage_percent",
+		"net_rx_bytes", "net_rx_packets", "net_rx_errors", "net_rx_dropped", "net_tx_bytes", "net_tx_packets", "net_tx_errors", "net_tx_dropped",
+		"blkio_io_service_bytes_read", "blkio_io_service_bytes_write", "blkio_io_serviced_read", "blkio_io_serviced_write"},
+	"containerd": {"cpu_usage_total", "cpu_usage_percent", "mem_working_set", "mem_usage", "mem_rss", "mem_available", "mem_page_faults", "mem_major_page_faults",
+		"writable_layer_used_bytes", "writable_layer_inodes_used"},
+	"kafka": {"cluster_brokers", "cluster_topics", "cluster_partitions", "cluster_under_replicated_partitions", "cluster_offline_partitions",
+		"topic_partitions", "topic_under_replicated_partitions", "topic_offline_partitions", "topic_log_end_offset", "consumer_lag", "consumer_partition_lag"},
+	"haproxy": {"qcur", "qmax", "scur", "smax", "slim", "stot", "bin", "bout", "dreq", "dresp", "ereq", "econ", "eresp", "wretr", "wredis",
+		"chkfail", "chkdown", "downtime", "active_servers", "backup_servers", "rate", "req_rate", "req_tot", "hrsp_1xx", "hrsp_2xx", "hrsp_3xx",
+		"hrsp_4xx", "hrsp_5xx", "cli_abrt", "srv_abrt", "check_duration", "qtime", "ctime", "rtime", "ttime", "up",
+		"ereq_rate", "econ_rate", "eresp_rate", "hrsp_5xx_rate"},
+	"rabbitmq": {"overview_messages", "overview_messages_ready", "overview_messages_unacked", "overview_connections", "overview_channels",
+		"overview_consumers", "overview_queues", "overview_publish_rate", "overview_deliver_rate",
+		"node_running", "node_mem_used", "node_mem_limit", "node_mem_alarm", "node_disk_free", "node_disk_free_limit", "node_disk_free_alarm",
+		"node_fd_used", "node_fd_total", "node_sockets_used",
+		"queue_messages", "queue_messages_ready", "queue_messages_unacked", "queue_consumers", "queue_memory",
+		"queue_publish_rate", "queue_deliver_rate", "queue_ack_rate", "queue_redeliver_rate"},
+	"node_exporter": {"cpu_time_active", "cpu_time_guest", "cpu_time_guest_nice", "cpu_time_idle", "cpu_time_iowait", "cpu_time_irq", "cpu_time_nice",
+		"cpu_time_softirq", "cpu_time_steal", "cpu_time_system", "cpu_time_user",
+		"cpu_usage_active", "cpu_usage_guest", "cpu_usage_guest_nice", "cpu_usage_idle", "cpu_usage_iowait", "cpu_usage_irq", "cpu_usage_nice",
+		"cpu_usage_softirq", "cpu_usage_steal", "cpu_usage_system", "cpu_usage_user",
+		"mem_active", "mem_available", "mem_available_percent", "mem_buffered", "mem_cached", "mem_free", "mem_inactive", "mem_total",
+		"mem_used", "mem_used_percent", "swap_free", "swap_used", "swap_used_percent",
+		"disk_free", "disk_inodes_free", "disk_inodes_total", "disk_inodes_used", "disk_total", "disk_used", "disk_used_percent",
+		"diskio_iops_in_progress", "diskio_io_time", "diskio_reads", "diskio_read_bytes", "diskio_read_time", "diskio_writes",
+		"diskio_write_bytes", "diskio_write_time",
+		"net_bytes_sent", "net_bytes_recv", "net_drop_in", "net_drop_out", "net_err_in", "net_err_out", "net_packets_sent", "net_packets_recv",
+		"netstat_tcp_established", "processes_blocked", "processes_running"},
+	"cgroup": {"cpu_usage_usec", "cpu_usage_percent", "cpu_throttled_periods", "cpu_throttled_usec", "cpu_limit_cores",
+		"memory_usage", "memory_limit", "memory_utilization", "memory_oom_kills", "io_read_bytes", "io_write_bytes", "io_reads", "io_writes"},
+	"fd": {"allocated", "max", "utilization", "process_open", "process_limit", "process_utilization"},
+	"kernel": {"conntrack_count", "conntrack_max", "conntrack_utilization", "conntrack_drop", "conntrack_early_drop", "conntrack_insert_failed",
+		"arp_entries", "arp_max", "arp_utilization", "tcp_mem_pages", "tcp_mem_pressure_pages", "tcp_mem_max_pages", "tcp_mem_utilization",
+		"udp_mem_pages", "udp_mem_pressure_pages", "udp_mem_max_pages", "udp_mem_utilization", "tcp_memory_pressures", "tcp_prune_called",
+		"tcp_abort_on_memory", "context_switches_per_sec", "interrupts_per_sec", "entropy_avail"},
+	"sensors":       {"temp_input", "temp_max", "temp_crit", "fan_input"},
+	"cpu_cluster":   {"frequency_mhz", "min_frequency_mhz", "max_frequency_mhz", "cycles", "instructions", "instructions_per_cycle"},
+	"top_processes": {"cpu_usage", "memory_rss", "memory_utilization", "process_count"},
+	"windows_gpu":   {"utilization", "engine_utilization", "memory_dedicated_usage", "memory_shared_usage", "memory_total_committed"},
 	"procstat": {"cpu_time", "cpu_time_guest", "cpu_time_guest_nice", "cpu_time_idle", "cpu_time_iowait", "cpu_time_irq", "cpu_time_nice", "cpu_time_soft_irq", "cpu_time_steal", "cpu_time_stolen", "cpu_time_system", "cpu_time_user", "cpu_usage", "involuntary_context_switches",
-		"memory_data", "memory_locked", "memory_rss", "memory_stack", "memory_swap", "memory_vms", "nice_priority", "num_fds", "num_threads", "pid",
+		"memory_data", "memory_locked", "memory_rss", "memory_rss_anon", "memory_rss_file", "memory_rss_shmem", "memory_stack", "memory_swap", "memory_vms", "nice_priority", "num_fds", "num_threads", "pid",
 		"read_bytes", "read_count", "realtime_priority", "rlimit_cpu_time_hard", "rlimit_cpu_time_soft", "rlimit_file_locks_hard", "rlimit_file_locks_soft", "rlimit_memory_data_hard", "rlimit_memory_data_soft", "rlimit_memory_locked_hard", "rlimit_memory_locked_soft",
 		"rlimit_memory_rss_hard", "rlimit_memory_rss_soft", "rlimit_memory_stack_hard", "rlimit_memory_stack_soft", "rlimit_memory_vms_hard", "rlimit_memory_vms_soft", "rlimit_nice_priority_hard", "rlimit_nice_priority_soft", "rlimit_num_fds_hard", "rlimit_num_fds_soft",
-		"rlimit_realtime_priority_hard", "rlimit_realtime_priority_soft", "rlimit_signals_pending_hard", "rlimit_signals_pending_soft", "signals_pending", "voluntary_context_switches", "write_bytes", "write_count", "pid_count"},
+		"rlimit_realtime_priority_hard", "rlimit_realtime_priority_soft", "rlimit_signals_pending_hard", "rlimit_signals_pending_soft", "signals_pending", "voluntary_context_switches", "write_bytes", "write_count", "pid_count", "uptime", "restart_count", "alive"},
 	"nvidia_smi": {"utilization_gpu", "temperature_gpu", "power_draw", "utilization_memory", "fan_speed", "memory_total", "memory_used", "memory_free", "temperature_gpu", "pcie_link_gen_current", "pcie_link_width_current",
 		"encoder_stats_session_count", "encoder_stats_average_fps", "encoder_stats_average_latency", "clocks_current_graphics", "clocks_current_sm", "clocks_current_memory", "clocks_current_video"},
 }
@@ -37,10 +82,21 @@ var Registered_Metrics_Darwin = map[string][]string{
 	"net":       {"bytes_sent", "bytes_recv", "drop_in", "drop_out", "err_in", "err_out", "packets_sent", "packets_recv"},
 	"netstat":   {"tcp_close", "tcp_close_wait", "tcp_closing", "tcp_established", "tcp_fin_wait1", "tcp_fin_wait2", "tcp_last_ack", "tcp_listen", "tcp_none", "tcp_syn_sent", "tcp_syn_recv", "tcp_time_wait", "udp_socket"},
 	"processes": {"blocked", "idle", "running", "sleeping", "stopped", "total", "zombies"},
-	"internal":  {"memstats_alloc_bytes", "memstats_heap_in_use_bytes", "agent_metrics_dropped", "agent_metrics_gathered"},
+	"internal": {"memstats_alloc_bytes", "memstats_heap_in_use_bytes", "agent_metrics_dropped", "agent_metrics_gathered",
+		"emf_listener_events_accepted", "emf_listener_events_throttled", "emf_listener_events_oversized",
+		"gather_gather_time_ns", "gather_timeouts", "gather_skipped_intervals", "cloudwatch_datums_quarantined"},
+	"timesync": {"offset", "jitter", "stratum", "synced", "root_delay", "root_dispersion", "frequency"},
+	"kafka": {"cluster_brokers", "cluster_topics", "cluster_partitions", "cluster_under_replicated_partitions", "cluster_offline_partitions",
+		"topic_partitions", "topic_under_replicated_partitions", "topic_offline_partitions", "topic_log_end_offset", "consumer_lag", "consumer_partition_lag"},
+	"rabbitmq": {"overview_messages", "overview_messages_ready", "overview_messages_unacked", "overview_connections", "overview_channels",
+		"overview_consumers", "overview_queues", "overview_publish_rate", "overview_deliver_rate",
+		"node_running", "node_mem_used", "node_mem_limit", "node_mem_alarm", "node_disk_free", "node_disk_free_limit", "node_disk_free_alarm",
+		"node_fd_used", "node_fd_total", "node_sockets_used",
+		"queue_messages", "queue_messages_ready", "queue_messages_unacked", "queue_consumers", "queue_memory",
+		"queue_publish_rate", "queue_deliver_rate", "queue_ack_rate", "queue_redeliver_rate"},
 	"procstat": {"cpu_time_system", "cpu_time_user", "cpu_usage",
 		"memory_data", "memory_locked", "memory_rss", "memory_stack", "memory_swap", "memory_vms", "pid",
-		"pid_count"},
+		"pid_count", "uptime", "restart_count", "alive"},
 	"nvidia_smi": {"utilization_gpu", "temperature_gpu", "power_draw", "utilization_memory", "utilization_encoder", "utilization_decoder", "fan_speed", "memory_total", "memory_used", "memory_free", "temperature_gpu", "pcie_link_gen_current", "pcie_link_width_current",
 		"encoder_stats_session_count", "encoder_stats_average_fps", "encoder_stats_average_latency", "clocks_current_graphics", "clocks_current_sm", "clocks_current_memory", "clocks_current_video"},
 }
@@ -54,6 +110,8 @@ var Registered_Metrics_Windows = map[string][]string{
 }
 
 var DisableWinPerfCounters = map[string]bool{
-	"statsd":   true,
-	"procstat": true,
+	"statsd":      true,
+	"procstat":    true,
+	"sensors":     true,
+	"windows_gpu": true,
 }
@@ -0,0 +1,82 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package deadband
+
+import (
+	"fmt"
+
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+	parent "github.com/aws/amazon-cloudwatch-agent/translator/translate/metrics"
+)
+
+type deadband struct {
+}
+
+const (
+	SectionKey      = "deadband"
+	metricNameKey   = "metric_name"
+	deltaKey        = "delta"
+	maxStalenessKey = "max_staleness"
+
+	processorName = "deadband"
+	// the values are suppressed once the dimensions are filtered, by series of the published dimensions, and before
+	// the EMF processor
+	processorOrder = 8
+)
+
+func GetCurPath() string {
+	curPath := parent.GetCurPath() + SectionKey + "/"
+	return curPath
+}
+
+// ApplyRule translates the rules suppressing the unchanged values by metric name into the deadband processor, e.g.
+// "deadband": [{"metric_name": "disk_total", "max_staleness": 3600}, {"metric_name": "mem_used_percent", "delta": 0.5}]
+func (d *deadband) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	im := input.(map[string]interface{})
+
+	returnKey = ""
+	returnVal = ""
+	rules, ok := im[SectionKey].([]interface{})
+	if !ok || len(rules) == 0 {
+		return
+	}
+
+	result := []interface{}{}
+	for _, r := range rules {
+		rule, ok := r.(map[string]interface{})
+		if !ok {
+			translator.AddErrorMessages(GetCurPath(), fmt.Sprintf("Invalid rule %v, an object is expected", r))
+			return
+		}
+		metricName, _ := rule[metricNameKey].(string)
+		if metricName == "" {
+			translator.AddErrorMessages(GetCurPath(), "Every rule requires a metric_name")
+			return
+		}
+		suppression := map[string]interface{}{metricNameKey: metricName}
+		if delta, ok := rule[deltaKey]; ok {
+			if v, isNumber := delta.(float64); !isNumber || v < 0 {
+				translator.AddErrorMessages(GetCurPath(), fmt.Sprintf("Invalid delta %v of %s, a non-negative number is expected", delta, metricName))
+				return
+			}
+			suppression[deltaKey] = delta
+		}
+		if _, ok := rule[maxStalenessKey]; ok {
+			_, val := translator.DefaultTimeIntervalCase(maxStalenessKey, "", rule)
+			suppression[maxStalenessKey] = val
+		}
+		result = append(result, suppression)
+	}
+
+	returnKey = parent.ProcessorsKey
+	returnVal = map[string]interface{}{
+		processorName: []interface{}{map[string]interface{}{"order": processorOrder, "rule": result}},
+	}
+	return
+}
+
+func init() {
+	d := new(deadband)
+	parent.RegisterRule(SectionKey, d)
+}
@@ -0,0 +1,61 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package deadband
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeadband(t *testing.T) {
+	d := new(deadband)
+	var input interface{}
+	err := json.Unmarshal([]byte(`{
+      "deadband": [
+        {"metric_name": "disk_total", "max_staleness": 3600},
+        {"metric_name": "mem_used_percent", "delta": 0.5}
+      ]
+    }`), &input)
+	assert.NoError(t, err)
+	actualKey, actualVal := d.ApplyRule(input)
+	expectedVal := map[string]interface{}{
+		"deadband": []interface{}{
+			map[string]interface{}{
+				"order": 8,
+				"rule": []interface{}{
+					map[string]interface{}{"metric_name": "disk_total", "max_staleness": "3600s"},
+					map[string]interface{}{"metric_name": "mem_used_percent", "delta": 0.5},
+				},
+			},
+		},
+	}
+	assert.Equal(t, "processors", actualKey)
+	assert.Equal(t, expectedVal, actualVal)
+}
+
+func TestNoDeadband(t *testing.T) {
+	d := new(deadband)
+	var input interface{}
+	err := json.Unmarshal([]byte(`{"namespace": "CWAgent"}`), &input)
+	assert.NoError(t, err)
+	actualKey, actualVal := d.ApplyRule(input)
+	assert.Equal(t, "", actualKey)
+	assert.Equal(t, "", actualVal)
+}
+
+func TestInvalidDeadband(t *testing.T) {
+	translator.ResetMessages()
+	d := new(deadband)
+	var input interface{}
+	err := json.Unmarshal([]byte(`{"deadband": [{"metric_name": "disk_total", "delta": -1}]}`), &input)
+	assert.NoError(t, err)
+	actualKey, _ := d.ApplyRule(input)
+	assert.Equal(t, "", actualKey)
+	assert.Equal(t, []string{"Under path : /metrics/deadband/ | Error : Invalid delta -1 of disk_total, a non-negative number is expected"}, translator.ErrorMessages)
+	translator.ResetMessages()
+}
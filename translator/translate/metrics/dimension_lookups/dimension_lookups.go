@@ -0,0 +1,93 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package dimension_lookups
+
+import (
+	"fmt"
+
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+	parent "github.com/aws/amazon-cloudwatch-agent/translator/translate/metrics"
+)
+
+type dimensionLookups struct {
+}
+
+const (
+	SectionKey        = "dimension_lookups"
+	dimensionKey      = "dimension"
+	valuesKey         = "values"
+	fileKey           = "file"
+	reloadIntervalKey = "reload_interval"
+
+	processorName = "dimensionlookup"
+	// the lookups apply after the processors adding dimensions, like ec2tagger, and before the EMF processor
+	processorOrder = 5
+)
+
+func GetCurPath() string {
+	curPath := parent.GetCurPath() + SectionKey + "/"
+	return curPath
+}
+
+// ApplyRule translates the lookup tables of the dimension values into the dimensionlookup processor, e.g.
+// "dimension_lookups": [{"dimension": "InstanceId", "file": "/etc/cwagent/services.json", "reload_interval": 60}]
+func (d *dimensionLookups) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	im := input.(map[string]interface{})
+
+	returnKey = ""
+	returnVal = ""
+	lookups, ok := im[SectionKey].([]interface{})
+	if !ok || len(lookups) == 0 {
+		return
+	}
+
+	tables := []interface{}{}
+	for _, l := range lookups {
+		lookup, ok := l.(map[string]interface{})
+		if !ok {
+			translator.AddErrorMessages(GetCurPath(), fmt.Sprintf("Invalid lookup %v, an object is expected", l))
+			return
+		}
+		dimension, _ := lookup[dimensionKey].(string)
+		if dimension == "" {
+			translator.AddErrorMessages(GetCurPath(), "Every lookup requires a dimension")
+			return
+		}
+		table := map[string]interface{}{dimensionKey: dimension}
+		if values, ok := lookup[valuesKey].(map[string]interface{}); ok && len(values) > 0 {
+			tableValues := map[string]string{}
+			for k, v := range values {
+				s, isString := v.(string)
+				if !isString {
+					translator.AddErrorMessages(GetCurPath(), fmt.Sprintf("Invalid value %v of %s, a string is expected", v, k))
+					return
+				}
+				tableValues[k] = s
+			}
+			table[valuesKey] = tableValues
+		}
+		if file, ok := lookup[fileKey].(string); ok {
+			table[fileKey] = file
+			if interval, ok := lookup[reloadIntervalKey].(float64); ok {
+				table[reloadIntervalKey] = fmt.Sprintf("%ds", int(interval))
+			}
+		}
+		if _, hasValues := table[valuesKey]; !hasValues && table[fileKey] == nil {
+			translator.AddErrorMessages(GetCurPath(), fmt.Sprintf("The lookup of %s requires values or a file", dimension))
+			return
+		}
+		tables = append(tables, table)
+	}
+
+	returnKey = parent.ProcessorsKey
+	returnVal = map[string]interface{}{
+		processorName: []interface{}{map[string]interface{}{"order": processorOrder, "table": tables}},
+	}
+	return
+}
+
+func init() {
+	d := new(dimensionLookups)
+	parent.RegisterRule(SectionKey, d)
+}
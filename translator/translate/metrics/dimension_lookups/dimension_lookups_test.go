@@ -0,0 +1,61 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package dimension_lookups
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDimensionLookups(t *testing.T) {
+	d := new(dimensionLookups)
+	var input interface{}
+	err := json.Unmarshal([]byte(`{
+      "dimension_lookups": [
+        {"dimension": "device", "values": {"nvme1n1": "data"}},
+        {"dimension": "InstanceId", "file": "/etc/cwagent/services.json", "reload_interval": 300}
+      ]
+    }`), &input)
+	assert.NoError(t, err)
+	actualKey, actualVal := d.ApplyRule(input)
+	expectedVal := map[string]interface{}{
+		"dimensionlookup": []interface{}{
+			map[string]interface{}{
+				"order": 5,
+				"table": []interface{}{
+					map[string]interface{}{"dimension": "device", "values": map[string]string{"nvme1n1": "data"}},
+					map[string]interface{}{"dimension": "InstanceId", "file": "/etc/cwagent/services.json", "reload_interval": "300s"},
+				},
+			},
+		},
+	}
+	assert.Equal(t, "processors", actualKey)
+	assert.Equal(t, expectedVal, actualVal)
+}
+
+func TestNoDimensionLookups(t *testing.T) {
+	d := new(dimensionLookups)
+	var input interface{}
+	err := json.Unmarshal([]byte(`{"namespace": "CWAgent", "dimension_lookups": []}`), &input)
+	assert.NoError(t, err)
+	actualKey, actualVal := d.ApplyRule(input)
+	assert.Equal(t, "", actualKey)
+	assert.Equal(t, "", actualVal)
+}
+
+func TestInvalidDimensionLookups(t *testing.T) {
+	translator.ResetMessages()
+	d := new(dimensionLookups)
+	var input interface{}
+	err := json.Unmarshal([]byte(`{"dimension_lookups": [{"dimension": "device"}]}`), &input)
+	assert.NoError(t, err)
+	actualKey, _ := d.ApplyRule(input)
+	assert.Equal(t, "", actualKey)
+	assert.Equal(t, []string{"Under path : /metrics/dimension_lookups/ | Error : The lookup of device requires values or a file"}, translator.ErrorMessages)
+	translator.ResetMessages()
+}
@@ -0,0 +1,73 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package dimension_normalization
+
+import (
+	"fmt"
+
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+	parent "github.com/aws/amazon-cloudwatch-agent/translator/translate/metrics"
+)
+
+type dimensionNormalization struct {
+}
+
+const (
+	SectionKey           = "dimension_normalization"
+	keyCaseKey           = "key_case"
+	valueCaseKey         = "value_case"
+	allowedCharactersKey = "allowed_characters"
+	replacementKey       = "replacement"
+	maxLengthKey         = "max_length"
+)
+
+func GetCurPath() string {
+	curPath := parent.GetCurPath() + SectionKey + "/"
+	return curPath
+}
+
+// ApplyRule translates the normalization policy of the dimensions, e.g.
+// "dimension_normalization": {"key_case": "lower", "allowed_characters": "a-z0-9_.-", "max_length": 255}
+func (d *dimensionNormalization) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	im := input.(map[string]interface{})
+
+	returnKey = ""
+	returnVal = ""
+	policy, ok := im[SectionKey].(map[string]interface{})
+	if !ok {
+		return
+	}
+
+	result := map[string]interface{}{}
+	for _, key := range []string{keyCaseKey, valueCaseKey, allowedCharactersKey, replacementKey} {
+		if val, ok := policy[key]; ok {
+			s, isString := val.(string)
+			if !isString {
+				translator.AddErrorMessages(GetCurPath(), fmt.Sprintf("Invalid %s %v, a string is expected", key, val))
+				return
+			}
+			result[key] = s
+		}
+	}
+	if val, ok := policy[maxLengthKey]; ok {
+		maxLength, isNumber := val.(float64)
+		if !isNumber {
+			translator.AddErrorMessages(GetCurPath(), fmt.Sprintf("Invalid %s %v, a number is expected", maxLengthKey, val))
+			return
+		}
+		result[maxLengthKey] = int(maxLength)
+	}
+	if len(result) == 0 {
+		return
+	}
+
+	returnKey = parent.OutputsKey
+	returnVal = map[string]interface{}{SectionKey: result}
+	return
+}
+
+func init() {
+	d := new(dimensionNormalization)
+	parent.RegisterRule(SectionKey, d)
+}
@@ -0,0 +1,59 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package dimension_normalization
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDimensionNormalization(t *testing.T) {
+	d := new(dimensionNormalization)
+	var input interface{}
+	err := json.Unmarshal([]byte(`{
+      "dimension_normalization": {
+        "key_case": "lower",
+        "value_case": "none",
+        "allowed_characters": "a-z0-9_.-",
+        "max_length": 255
+      }
+    }`), &input)
+	assert.NoError(t, err)
+	actualKey, actualVal := d.ApplyRule(input)
+	expectedVal := map[string]interface{}{
+		"dimension_normalization": map[string]interface{}{
+			"key_case":           "lower",
+			"value_case":         "none",
+			"allowed_characters": "a-z0-9_.-",
+			"max_length":         255,
+		},
+	}
+	assert.Equal(t, "outputs", actualKey)
+	assert.Equal(t, expectedVal, actualVal)
+}
+
+func TestNoDimensionNormalization(t *testing.T) {
+	d := new(dimensionNormalization)
+	var input interface{}
+	err := json.Unmarshal([]byte(`{"namespace": "CWAgent", "dimension_normalization": {}}`), &input)
+	assert.NoError(t, err)
+	actualKey, actualVal := d.ApplyRule(input)
+	assert.Equal(t, "", actualKey)
+	assert.Equal(t, "", actualVal)
+}
+
+func TestInvalidDimensionNormalization(t *testing.T) {
+	translator.ResetMessages()
+	d := new(dimensionNormalization)
+	var input interface{}
+	err := json.Unmarshal([]byte(`{"dimension_normalization": {"max_length": "255"}}`), &input)
+	assert.NoError(t, err)
+	actualKey, _ := d.ApplyRule(input)
+	assert.Equal(t, "", actualKey)
+	assert.Equal(t, 1, len(translator.ErrorMessages))
+}
@@ -0,0 +1,91 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package dimension_rules
+
+import (
+	"fmt"
+
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+	parent "github.com/aws/amazon-cloudwatch-agent/translator/translate/metrics"
+)
+
+type dimensionRules struct {
+}
+
+const (
+	SectionKey    = "dimension_rules"
+	metricNameKey = "metric_name"
+	keepKey       = "keep"
+	dropKey       = "drop"
+
+	processorName = "dimensionfilter"
+	// the dimensions are filtered once the metrics are derived, whose counters are kept by series of all the
+	// dimensions, and before the EMF processor
+	processorOrder = 7
+)
+
+func GetCurPath() string {
+	curPath := parent.GetCurPath() + SectionKey + "/"
+	return curPath
+}
+
+// ApplyRule translates the rules keeping or dropping the dimensions by metric name into the dimensionfilter processor, e.g.
+// "dimension_rules": [{"metric_name": "cpu_usage_*", "drop": ["cpu"]}, {"metric_name": "*", "drop": ["host"]}]
+func (d *dimensionRules) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	im := input.(map[string]interface{})
+
+	returnKey = ""
+	returnVal = ""
+	rules, ok := im[SectionKey].([]interface{})
+	if !ok || len(rules) == 0 {
+		return
+	}
+
+	result := []interface{}{}
+	for _, r := range rules {
+		rule, ok := r.(map[string]interface{})
+		if !ok {
+			translator.AddErrorMessages(GetCurPath(), fmt.Sprintf("Invalid rule %v, an object is expected", r))
+			return
+		}
+		metricName, _ := rule[metricNameKey].(string)
+		if metricName == "" {
+			translator.AddErrorMessages(GetCurPath(), "Every rule requires a metric_name")
+			return
+		}
+		filter := map[string]interface{}{metricNameKey: metricName}
+		for _, key := range []string{keepKey, dropKey} {
+			values, ok := rule[key].([]interface{})
+			if !ok || len(values) == 0 {
+				continue
+			}
+			dimensions := []string{}
+			for _, v := range values {
+				s, isString := v.(string)
+				if !isString {
+					translator.AddErrorMessages(GetCurPath(), fmt.Sprintf("Invalid dimension %v of %s, a string is expected", v, metricName))
+					return
+				}
+				dimensions = append(dimensions, s)
+			}
+			filter[key] = dimensions
+		}
+		if len(filter) == 1 {
+			translator.AddErrorMessages(GetCurPath(), fmt.Sprintf("The rule of %s requires the dimensions to keep or drop", metricName))
+			return
+		}
+		result = append(result, filter)
+	}
+
+	returnKey = parent.ProcessorsKey
+	returnVal = map[string]interface{}{
+		processorName: []interface{}{map[string]interface{}{"order": processorOrder, "rule": result}},
+	}
+	return
+}
+
+func init() {
+	d := new(dimensionRules)
+	parent.RegisterRule(SectionKey, d)
+}
@@ -0,0 +1,61 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package dimension_rules
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDimensionRules(t *testing.T) {
+	d := new(dimensionRules)
+	var input interface{}
+	err := json.Unmarshal([]byte(`{
+      "dimension_rules": [
+        {"metric_name": "cpu_usage_*", "drop": ["cpu"]},
+        {"metric_name": "disk_*", "keep": ["InstanceId", "path"], "drop": ["path"]}
+      ]
+    }`), &input)
+	assert.NoError(t, err)
+	actualKey, actualVal := d.ApplyRule(input)
+	expectedVal := map[string]interface{}{
+		"dimensionfilter": []interface{}{
+			map[string]interface{}{
+				"order": 7,
+				"rule": []interface{}{
+					map[string]interface{}{"metric_name": "cpu_usage_*", "drop": []string{"cpu"}},
+					map[string]interface{}{"metric_name": "disk_*", "keep": []string{"InstanceId", "path"}, "drop": []string{"path"}},
+				},
+			},
+		},
+	}
+	assert.Equal(t, "processors", actualKey)
+	assert.Equal(t, expectedVal, actualVal)
+}
+
+func TestNoDimensionRules(t *testing.T) {
+	d := new(dimensionRules)
+	var input interface{}
+	err := json.Unmarshal([]byte(`{"namespace": "CWAgent"}`), &input)
+	assert.NoError(t, err)
+	actualKey, actualVal := d.ApplyRule(input)
+	assert.Equal(t, "", actualKey)
+	assert.Equal(t, "", actualVal)
+}
+
+func TestInvalidDimensionRules(t *testing.T) {
+	translator.ResetMessages()
+	d := new(dimensionRules)
+	var input interface{}
+	err := json.Unmarshal([]byte(`{"dimension_rules": [{"metric_name": "cpu_usage_*"}]}`), &input)
+	assert.NoError(t, err)
+	actualKey, _ := d.ApplyRule(input)
+	assert.Equal(t, "", actualKey)
+	assert.Equal(t, []string{"Under path : /metrics/dimension_rules/ | Error : The rule of cpu_usage_* requires the dimensions to keep or drop"}, translator.ErrorMessages)
+	translator.ResetMessages()
+}
@@ -0,0 +1,66 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package downsampling
+
+import (
+	"fmt"
+
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+	parent "github.com/aws/amazon-cloudwatch-agent/translator/translate/metrics"
+)
+
+type downsampling struct {
+}
+
+const (
+	SectionKey   = "downsampling"
+	olderThanKey = "older_than"
+	intervalKey  = "interval"
+)
+
+func GetCurPath() string {
+	curPath := parent.GetCurPath() + SectionKey + "/"
+	return curPath
+}
+
+// ApplyRule translates the downsampling policies, e.g.
+// "downsampling": [{"older_than": 3600, "interval": 60}] collapses the metrics older than 1 hour into 1 minute aggregates.
+func (d *downsampling) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	im := input.(map[string]interface{})
+
+	returnKey = ""
+	returnVal = ""
+	policies, ok := im[SectionKey].([]interface{})
+	if !ok || len(policies) == 0 {
+		return
+	}
+
+	result := make([]interface{}, 0, len(policies))
+	for _, p := range policies {
+		policy, isMap := p.(map[string]interface{})
+		if !isMap {
+			translator.AddErrorMessages(GetCurPath(), fmt.Sprintf("Invalid downsampling policy %v, expected {\"older_than\": <seconds>, \"interval\": <seconds>}", p))
+			return
+		}
+		olderThan, hasOlderThan := policy[olderThanKey].(float64)
+		interval, hasInterval := policy[intervalKey].(float64)
+		if !hasOlderThan || !hasInterval {
+			translator.AddErrorMessages(GetCurPath(), fmt.Sprintf("Invalid downsampling policy %v, both %s and %s are required", p, olderThanKey, intervalKey))
+			return
+		}
+		result = append(result, map[string]interface{}{
+			olderThanKey: fmt.Sprintf("%ds", int(olderThan)),
+			intervalKey:  fmt.Sprintf("%ds", int(interval)),
+		})
+	}
+
+	returnKey = parent.OutputsKey
+	returnVal = map[string]interface{}{SectionKey: result}
+	return
+}
+
+func init() {
+	d := new(downsampling)
+	parent.RegisterRule(SectionKey, d)
+}
@@ -0,0 +1,55 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package downsampling
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDownsampling(t *testing.T) {
+	d := new(downsampling)
+	var input interface{}
+	err := json.Unmarshal([]byte(`{
+      "downsampling": [
+        {"older_than": 3600, "interval": 60},
+        {"older_than": 86400, "interval": 300}
+      ]
+    }`), &input)
+	assert.NoError(t, err)
+	actualKey, actualVal := d.ApplyRule(input)
+	expectedVal := map[string]interface{}{
+		"downsampling": []interface{}{
+			map[string]interface{}{"older_than": "3600s", "interval": "60s"},
+			map[string]interface{}{"older_than": "86400s", "interval": "300s"},
+		},
+	}
+	assert.Equal(t, "outputs", actualKey)
+	assert.Equal(t, expectedVal, actualVal)
+}
+
+func TestNoDownsampling(t *testing.T) {
+	d := new(downsampling)
+	var input interface{}
+	err := json.Unmarshal([]byte(`{"namespace": "CWAgent"}`), &input)
+	assert.NoError(t, err)
+	actualKey, actualVal := d.ApplyRule(input)
+	assert.Equal(t, "", actualKey)
+	assert.Equal(t, "", actualVal)
+}
+
+func TestInvalidDownsampling(t *testing.T) {
+	translator.ResetMessages()
+	d := new(downsampling)
+	var input interface{}
+	err := json.Unmarshal([]byte(`{"downsampling": [{"older_than": 3600}]}`), &input)
+	assert.NoError(t, err)
+	actualKey, _ := d.ApplyRule(input)
+	assert.Equal(t, "", actualKey)
+	assert.Equal(t, 1, len(translator.ErrorMessages))
+}
@@ -0,0 +1,78 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package metric_rename
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+	parent "github.com/aws/amazon-cloudwatch-agent/translator/translate/metrics"
+)
+
+type metricRename struct {
+}
+
+const (
+	SectionKey   = "metric_rename"
+	patternKey   = "pattern"
+	renameKey    = "rename"
+	namespaceKey = "namespace"
+)
+
+func GetCurPath() string {
+	curPath := parent.GetCurPath() + SectionKey + "/"
+	return curPath
+}
+
+// ApplyRule translates the rename rules of the metric names, e.g.
+// "metric_rename": [{"pattern": "^disk_(.+)$", "rename": "Disk.$1", "namespace": "Storage"}] renames the disk metrics
+// and sends them to the Storage namespace.
+func (m *metricRename) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	im := input.(map[string]interface{})
+
+	returnKey = ""
+	returnVal = ""
+	rules, ok := im[SectionKey].([]interface{})
+	if !ok || len(rules) == 0 {
+		return
+	}
+
+	result := make([]interface{}, 0, len(rules))
+	for _, r := range rules {
+		rule, isMap := r.(map[string]interface{})
+		if !isMap {
+			translator.AddErrorMessages(GetCurPath(), fmt.Sprintf("Invalid metric rename rule %v, expected {\"pattern\": <regex>, \"rename\": <name>}", r))
+			return
+		}
+		pattern, _ := rule[patternKey].(string)
+		rename, _ := rule[renameKey].(string)
+		namespace, _ := rule[namespaceKey].(string)
+		if pattern == "" || (rename == "" && namespace == "") {
+			translator.AddErrorMessages(GetCurPath(), fmt.Sprintf("Invalid metric rename rule %v, %s and either %s or %s are required", r, patternKey, renameKey, namespaceKey))
+			return
+		}
+		if _, err := regexp.Compile(pattern); err != nil {
+			translator.AddErrorMessages(GetCurPath(), fmt.Sprintf("Invalid metric rename pattern %s: %v", pattern, err))
+			return
+		}
+		translated := map[string]interface{}{patternKey: pattern}
+		if rename != "" {
+			translated[renameKey] = rename
+		}
+		if namespace != "" {
+			translated[namespaceKey] = namespace
+		}
+		result = append(result, translated)
+	}
+
+	returnKey = parent.OutputsKey
+	returnVal = map[string]interface{}{SectionKey: result}
+	return
+}
+
+func init() {
+	m := new(metricRename)
+	parent.RegisterRule(SectionKey, m)
+}
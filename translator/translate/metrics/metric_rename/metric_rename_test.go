@@ -0,0 +1,57 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package metric_rename
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetricRename(t *testing.T) {
+	m := new(metricRename)
+	var input interface{}
+	err := json.Unmarshal([]byte(`{
+      "metric_rename": [
+        {"pattern": "^disk_(.+)$", "rename": "Disk.$1", "namespace": "Storage"},
+        {"pattern": "^mem_", "namespace": "Memory"}
+      ]
+    }`), &input)
+	assert.NoError(t, err)
+	actualKey, actualVal := m.ApplyRule(input)
+	expectedVal := map[string]interface{}{
+		"metric_rename": []interface{}{
+			map[string]interface{}{"pattern": "^disk_(.+)$", "rename": "Disk.$1", "namespace": "Storage"},
+			map[string]interface{}{"pattern": "^mem_", "namespace": "Memory"},
+		},
+	}
+	assert.Equal(t, "outputs", actualKey)
+	assert.Equal(t, expectedVal, actualVal)
+}
+
+func TestNoMetricRename(t *testing.T) {
+	m := new(metricRename)
+	var input interface{}
+	err := json.Unmarshal([]byte(`{"namespace": "CWAgent"}`), &input)
+	assert.NoError(t, err)
+	actualKey, actualVal := m.ApplyRule(input)
+	assert.Equal(t, "", actualKey)
+	assert.Equal(t, "", actualVal)
+}
+
+func TestInvalidMetricRename(t *testing.T) {
+	translator.ResetMessages()
+	m := new(metricRename)
+	var input interface{}
+	err := json.Unmarshal([]byte(`{"metric_rename": [{"pattern": "^disk_(", "rename": "Disk"}]}`), &input)
+	assert.NoError(t, err)
+	actualKey, _ := m.ApplyRule(input)
+	assert.Equal(t, "", actualKey)
+	assert.Len(t, translator.ErrorMessages, 1)
+	assert.Contains(t, translator.ErrorMessages[0], "Invalid metric rename pattern ^disk_(")
+	translator.ResetMessages()
+}
@@ -0,0 +1,73 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package metric_transforms
+
+import (
+	"fmt"
+
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+	parent "github.com/aws/amazon-cloudwatch-agent/translator/translate/metrics"
+)
+
+type metricTransforms struct {
+}
+
+const (
+	SectionKey     = "metric_transforms"
+	measurementKey = "measurement"
+	nameKey        = "name"
+	expressionKey  = "expression"
+
+	processorName = "derivedmetrics"
+	// the metrics are derived after the dimension lookups, from the values of the deltas and before the EMF processor
+	processorOrder = 6
+)
+
+func GetCurPath() string {
+	curPath := parent.GetCurPath() + SectionKey + "/"
+	return curPath
+}
+
+// ApplyRule translates the metrics computed from the collected ones into the derivedmetrics processor, e.g.
+// "metric_transforms": [{"measurement": "disk", "name": "disk_used_ratio", "expression": "disk_used / disk_total * 100"}]
+func (m *metricTransforms) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	im := input.(map[string]interface{})
+
+	returnKey = ""
+	returnVal = ""
+	transforms, ok := im[SectionKey].([]interface{})
+	if !ok || len(transforms) == 0 {
+		return
+	}
+
+	metrics := []interface{}{}
+	for _, t := range transforms {
+		transform, ok := t.(map[string]interface{})
+		if !ok {
+			translator.AddErrorMessages(GetCurPath(), fmt.Sprintf("Invalid transform %v, an object is expected", t))
+			return
+		}
+		metric := map[string]interface{}{}
+		for _, key := range []string{measurementKey, nameKey, expressionKey} {
+			value, _ := transform[key].(string)
+			if value == "" {
+				translator.AddErrorMessages(GetCurPath(), "Every transform requires a measurement, a name and an expression")
+				return
+			}
+			metric[key] = value
+		}
+		metrics = append(metrics, metric)
+	}
+
+	returnKey = parent.ProcessorsKey
+	returnVal = map[string]interface{}{
+		processorName: []interface{}{map[string]interface{}{"order": processorOrder, "metric": metrics}},
+	}
+	return
+}
+
+func init() {
+	m := new(metricTransforms)
+	parent.RegisterRule(SectionKey, m)
+}
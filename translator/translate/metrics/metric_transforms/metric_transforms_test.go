@@ -0,0 +1,61 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package metric_transforms
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetricTransforms(t *testing.T) {
+	m := new(metricTransforms)
+	var input interface{}
+	err := json.Unmarshal([]byte(`{
+      "metric_transforms": [
+        {"measurement": "disk", "name": "disk_used_ratio", "expression": "disk_used / disk_total * 100"},
+        {"measurement": "net", "name": "bits_sent_per_sec", "expression": "rate(bytes_sent) * 8"}
+      ]
+    }`), &input)
+	assert.NoError(t, err)
+	actualKey, actualVal := m.ApplyRule(input)
+	expectedVal := map[string]interface{}{
+		"derivedmetrics": []interface{}{
+			map[string]interface{}{
+				"order": 6,
+				"metric": []interface{}{
+					map[string]interface{}{"measurement": "disk", "name": "disk_used_ratio", "expression": "disk_used / disk_total * 100"},
+					map[string]interface{}{"measurement": "net", "name": "bits_sent_per_sec", "expression": "rate(bytes_sent) * 8"},
+				},
+			},
+		},
+	}
+	assert.Equal(t, "processors", actualKey)
+	assert.Equal(t, expectedVal, actualVal)
+}
+
+func TestNoMetricTransforms(t *testing.T) {
+	m := new(metricTransforms)
+	var input interface{}
+	err := json.Unmarshal([]byte(`{"namespace": "CWAgent", "metric_transforms": []}`), &input)
+	assert.NoError(t, err)
+	actualKey, actualVal := m.ApplyRule(input)
+	assert.Equal(t, "", actualKey)
+	assert.Equal(t, "", actualVal)
+}
+
+func TestInvalidMetricTransforms(t *testing.T) {
+	translator.ResetMessages()
+	m := new(metricTransforms)
+	var input interface{}
+	err := json.Unmarshal([]byte(`{"metric_transforms": [{"measurement": "disk", "name": "disk_used_ratio"}]}`), &input)
+	assert.NoError(t, err)
+	actualKey, _ := m.ApplyRule(input)
+	assert.Equal(t, "", actualKey)
+	assert.Equal(t, []string{"Under path : /metrics/metric_transforms/ | Error : Every transform requires a measurement, a name and an expression"}, translator.ErrorMessages)
+	translator.ResetMessages()
+}
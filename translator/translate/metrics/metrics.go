@@ -10,7 +10,9 @@ import (
 	"github.com/aws/amazon-cloudwatch-agent/translator/jsonconfig/mergeJsonRule"
 	"github.com/aws/amazon-cloudwatch-agent/translator/jsonconfig/mergeJsonUtil"
 	parent "github.com/aws/amazon-cloudwatch-agent/translator/translate"
+	"github.com/aws/amazon-cloudwatch-agent/translator/translate/agent"
 	"github.com/aws/amazon-cloudwatch-agent/translator/translate/metrics/config"
+	metricsutil "github.com/aws/amazon-cloudwatch-agent/translator/translate/metrics/util"
 	"github.com/aws/amazon-cloudwatch-agent/translator/translate/util"
 )
 
@@ -19,10 +21,15 @@ type Rule translator.Rule
 var ChildRule = map[string]Rule{}
 
 const (
-	SectionKey = "metrics"
-	OutputsKey = "outputs"
+	SectionKey       = "metrics"
+	OutputsKey       = "outputs"
+	ParquetOutputKey = "s3_parquet"
+	ProcessorsKey    = "processors"
 )
 
+// parquetSharedKeys are the settings of the cloudwatch output also used by the s3_parquet output.
+var parquetSharedKeys = []string{"region", "namespace", "access_key", "secret_key", "token", "role_arn", "profile", "shared_credential_file"}
+
 func GetCurPath() string {
 	curPath := parent.GetCurPath() + SectionKey + "/"
 	return curPath
@@ -39,6 +46,7 @@ func (m *Metrics) ApplyRule(input interface{}) (returnKey string, returnVal inte
 	im := input.(map[string]interface{})
 	result := map[string]interface{}{}
 	outputPlugInfo := map[string]interface{}{}
+	var parquetPlugInfo map[string]interface{}
 
 	//Check if this plugin exist in the input instance
 	//If not, not process
@@ -48,12 +56,19 @@ func (m *Metrics) ApplyRule(input interface{}) (returnKey string, returnVal inte
 		returnVal = ""
 	} else {
 		//If yes, process it
+		metricsutil.WarnHighResolutionCost("agent", agent.Global_Config.Interval)
 		for _, rule := range ChildRule {
 			key, val := rule.ApplyRule(im[SectionKey])
 			//If key == "", then no instance of this class in input
 			if key != "" {
 				if key == OutputsKey {
 					outputPlugInfo = translator.MergeTwoUniqueMaps(outputPlugInfo, val.(map[string]interface{}))
+				} else if key == ParquetOutputKey {
+					parquetPlugInfo = val.(map[string]interface{})
+				} else if key == ProcessorsKey {
+					// the processors of append_dimensions and dimension_lookups
+					processors, _ := result[ProcessorsKey].(map[string]interface{})
+					result[ProcessorsKey] = translator.MergePlugins(processors, val.(map[string]interface{}))
 				} else if config.ContainsKey(key) {
 					addCloudWatchOutputConfig(key, val, outputPlugInfo)
 				} else {
@@ -64,6 +79,14 @@ func (m *Metrics) ApplyRule(input interface{}) (returnKey string, returnVal inte
 
 		cloudwatchInfo := map[string]interface{}{}
 		cloudwatchInfo["cloudwatch"] = []interface{}{outputPlugInfo}
+		if parquetPlugInfo != nil {
+			for _, k := range parquetSharedKeys {
+				if v, ok := outputPlugInfo[k]; ok {
+					parquetPlugInfo[k] = v
+				}
+			}
+			cloudwatchInfo[ParquetOutputKey] = []interface{}{parquetPlugInfo}
+		}
 		result["outputs"] = cloudwatchInfo
 		translator.SetMetricPath(result, SectionKey)
 		returnKey = SectionKey
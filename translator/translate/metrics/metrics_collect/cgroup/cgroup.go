@@ -0,0 +1,57 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package cgroup
+
+import (
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+	parent "github.com/aws/amazon-cloudwatch-agent/translator/translate/metrics/metrics_collect"
+	"github.com/aws/amazon-cloudwatch-agent/translator/translate/metrics/util"
+)
+
+var ChildRule = map[string]translator.Rule{}
+
+const SectionKey_Cgroup = "cgroup"
+
+func GetCurPath() string {
+	curPath := parent.GetCurPath() + SectionKey_Cgroup + "/"
+	return curPath
+}
+
+func RegisterRule(fieldname string, r translator.Rule) {
+	ChildRule[fieldname] = r
+}
+
+type Cgroup struct {
+}
+
+func (c *Cgroup) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	m := input.(map[string]interface{})
+	res := []interface{}{}
+	result := map[string]interface{}{}
+	//Check if this plugin exist in the input instance
+	//If not, not process
+	if _, ok := m[SectionKey_Cgroup]; !ok {
+		returnKey = ""
+		returnVal = ""
+	} else {
+		//Check if there are some config entry with rules applied
+		result = translator.ProcessRuleToApply(m[SectionKey_Cgroup], ChildRule, result)
+
+		//Process common config, like measurement
+		hasValidMetric := util.ProcessLinuxCommonConfig(m[SectionKey_Cgroup], SectionKey_Cgroup, GetCurPath(), result)
+		if hasValidMetric {
+			res = append(res, result)
+			returnKey = SectionKey_Cgroup
+			returnVal = res
+		} else {
+			returnKey = ""
+		}
+	}
+	return
+}
+
+func init() {
+	c := new(Cgroup)
+	parent.RegisterLinuxRule(SectionKey_Cgroup, c)
+}
@@ -0,0 +1,49 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package cgroup
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCgroup(t *testing.T) {
+	c := new(Cgroup)
+	var input interface{}
+	err := json.Unmarshal([]byte(`{"cgroup":{"measurement": ["cpu_usage_percent", "memory_usage"], "paths": ["batch.slice", "system.slice/nginx.service"]}}`), &input)
+	assert.NoError(t, err)
+	_, actual := c.ApplyRule(input)
+	expected := []interface{}{map[string]interface{}{
+		"fieldpass": []string{"cpu_usage_percent", "memory_usage"},
+		"paths":     []interface{}{"batch.slice", "system.slice/nginx.service"},
+	}}
+	assert.Equal(t, expected, actual, "Expected to be equal")
+}
+
+func TestCgroupWithRoot(t *testing.T) {
+	c := new(Cgroup)
+	var input interface{}
+	err := json.Unmarshal([]byte(`{"cgroup":{"measurement": ["memory_usage"], "paths": ["batch.slice"], "cgroup_root": "/rootfs/sys/fs/cgroup"}}`), &input)
+	assert.NoError(t, err)
+	_, actual := c.ApplyRule(input)
+	expected := []interface{}{map[string]interface{}{
+		"fieldpass":   []string{"memory_usage"},
+		"paths":       []interface{}{"batch.slice"},
+		"cgroup_root": "/rootfs/sys/fs/cgroup",
+	}}
+	assert.Equal(t, expected, actual, "Expected to be equal")
+}
+
+func TestCgroupWithoutPaths(t *testing.T) {
+	translator.ResetMessages()
+	c := new(Cgroup)
+	var input interface{}
+	err := json.Unmarshal([]byte(`{"cgroup":{"measurement": ["memory_usage"], "paths": []}}`), &input)
+	assert.NoError(t, err)
+	c.ApplyRule(input)
+	assert.Equal(t, 1, len(translator.ErrorMessages))
+}
@@ -0,0 +1,26 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package cgroup
+
+import (
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+)
+
+type CgroupRoot struct {
+}
+
+const SectionKey_CgroupRoot = "cgroup_root"
+
+func (obj *CgroupRoot) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	returnKey, returnVal = translator.DefaultCase(SectionKey_CgroupRoot, "", input)
+	if returnVal == "" {
+		return "", nil
+	}
+	return
+}
+
+func init() {
+	obj := new(CgroupRoot)
+	RegisterRule(SectionKey_CgroupRoot, obj)
+}
@@ -0,0 +1,27 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package cgroup
+
+import (
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+)
+
+type Paths struct {
+}
+
+const SectionKey_Paths = "paths"
+
+func (obj *Paths) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	returnKey, returnVal = translator.DefaultCase(SectionKey_Paths, []interface{}{}, input)
+	if paths, ok := returnVal.([]interface{}); !ok || len(paths) == 0 {
+		translator.AddErrorMessages(GetCurPath()+SectionKey_Paths, "paths is required, it lists the cgroups or systemd slices reported")
+		return "", nil
+	}
+	return
+}
+
+func init() {
+	obj := new(Paths)
+	RegisterRule(SectionKey_Paths, obj)
+}
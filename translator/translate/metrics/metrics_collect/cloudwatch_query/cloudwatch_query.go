@@ -0,0 +1,83 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package cloudwatch_query
+
+import (
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+	"github.com/aws/amazon-cloudwatch-agent/translator/translate/agent"
+	parent "github.com/aws/amazon-cloudwatch-agent/translator/translate/metrics/metrics_collect"
+	"github.com/aws/amazon-cloudwatch-agent/translator/translate/metrics/util"
+	translateUtil "github.com/aws/amazon-cloudwatch-agent/translator/translate/util"
+)
+
+//
+//   "cloudwatch_query": {
+//       "leader_host": "ip-10-0-0-1",
+//       "metrics_collection_interval": 60,
+//       "queries": [
+//           {"id": "errors", "namespace": "MyApp", "metric_name": "Errors", "stat": "Sum", "return_data": false},
+//           {"id": "requests", "namespace": "MyApp", "metric_name": "Requests", "stat": "Sum", "return_data": false},
+//           {"id": "error_ratio", "expression": "100 * errors / requests", "name": "error_ratio"}
+//       ]
+//   }
+//
+const (
+	SectionKey = "cloudwatch_query"
+	periodKey  = "period"
+	hostTag    = "host"
+)
+
+var ChildRule = map[string]translator.Rule{}
+
+func GetCurPath() string {
+	curPath := parent.GetCurPath() + SectionKey + "/"
+	return curPath
+}
+
+func RegisterRule(fieldname string, r translator.Rule) {
+	ChildRule[fieldname] = r
+}
+
+type CloudWatchQuery struct {
+}
+
+// ApplyRule translates the queries republished by the leader host. The period of the queries is the collection
+// interval, the region and the credentials are the ones of the agent, and the host isn't a dimension since the
+// results are the ones of the fleet whichever host is the leader.
+func (obj *CloudWatchQuery) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	m := input.(map[string]interface{})
+	resArray := []interface{}{}
+	result := map[string]interface{}{}
+	//Check if this plugin exist in the input instance
+	//If not, not process
+	if _, ok := m[SectionKey]; !ok {
+		returnKey = ""
+		returnVal = ""
+	} else {
+		//If exists, process it
+		//Check if there are some config entry with rules applied
+		result = translator.ProcessRuleToApply(m[SectionKey], ChildRule, result)
+		result[periodKey] = result[util.Collect_Interval_Mapped_Key]
+		result[agent.RegionKey] = agent.Global_Config.Region
+		for k, v := range agent.Global_Config.Credentials {
+			result[k] = v
+		}
+		result["tagexclude"] = []string{hostTag}
+		// the results of the sub-minute periods are republished as high resolution metrics
+		if period, ok := result[periodKey].(string); ok && util.IsHighResolution(period) {
+			result[util.Append_Dimensions_Mapped_Key] = map[string]interface{}{translateUtil.High_Resolution_Tag_Key: "true"}
+		}
+		resArray = append(resArray, result)
+		returnKey = SectionKey
+		returnVal = resArray
+	}
+	return
+}
+
+func init() {
+	obj := new(CloudWatchQuery)
+	parent.RegisterLinuxRule(SectionKey, obj)
+	parent.RegisterDarwinRule(SectionKey, obj)
+	parent.RegisterWindowsRule(SectionKey, obj)
+}
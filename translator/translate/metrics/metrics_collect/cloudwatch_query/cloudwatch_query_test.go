@@ -0,0 +1,97 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package cloudwatch_query
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+	"github.com/aws/amazon-cloudwatch-agent/translator/translate/agent"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCloudWatchQuery(t *testing.T) {
+	agent.Global_Config.Region = "us-east-1"
+	agent.Global_Config.Credentials = map[string]interface{}{"profile": "metrics"}
+	defer func() {
+		agent.Global_Config.Region = ""
+		agent.Global_Config.Credentials = map[string]interface{}{}
+	}()
+	obj := new(CloudWatchQuery)
+	var input interface{}
+	err := json.Unmarshal([]byte(`{"cloudwatch_query": {
+					"leader_host": "ip-10-0-0-1",
+					"metrics_collection_interval": 300,
+					"queries": [
+						{"id": "errors", "namespace": "MyApp", "metric_name": "Errors", "stat": "Sum",
+						 "dimensions": {"Service": "checkout"}, "return_data": false},
+						{"id": "error_ratio", "expression": "100 * errors / requests", "name": "checkout_error_ratio"}
+					]
+					}}`), &input)
+	assert.NoError(t, err)
+
+	_, actual := obj.ApplyRule(input)
+
+	expect := []interface{}{
+		map[string]interface{}{
+			"leader_host": "ip-10-0-0-1",
+			"interval":    "300s",
+			"period":      "300s",
+			"delay":       "180s",
+			"region":      "us-east-1",
+			"profile":     "metrics",
+			"tagexclude":  []string{"host"},
+			"query": []interface{}{
+				map[string]interface{}{
+					"id":          "errors",
+					"namespace":   "MyApp",
+					"metric_name": "Errors",
+					"stat":        "Sum",
+					"dimensions":  map[string]interface{}{"Service": "checkout"},
+					"return_data": false,
+				},
+				map[string]interface{}{
+					"id":         "error_ratio",
+					"expression": "100 * errors / requests",
+					"name":       "checkout_error_ratio",
+				},
+			},
+		},
+	}
+	assert.Equal(t, expect, actual)
+}
+
+func TestCloudWatchQueryHighResolution(t *testing.T) {
+	obj := new(CloudWatchQuery)
+	var input interface{}
+	err := json.Unmarshal([]byte(`{"cloudwatch_query": {
+					"metrics_collection_interval": 10,
+					"queries": [{"id": "errors", "namespace": "MyApp", "metric_name": "Errors", "stat": "Sum"}]
+					}}`), &input)
+	assert.NoError(t, err)
+
+	_, actual := obj.ApplyRule(input)
+
+	result := actual.([]interface{})[0].(map[string]interface{})
+	assert.Equal(t, "10s", result["period"])
+	assert.Equal(t, map[string]interface{}{"aws:StorageResolution": "true"}, result["tags"])
+}
+
+func TestCloudWatchQueryInvalidQuery(t *testing.T) {
+	obj := new(CloudWatchQuery)
+	for _, queries := range []string{
+		`[]`,
+		`[{"expression": "1"}]`,
+		`[{"id": "errors", "namespace": "MyApp"}]`,
+		`[{"id": "errors", "namespace": "MyApp", "metric_name": "Errors", "expression": "1"}]`,
+	} {
+		translator.ResetMessages()
+		var input interface{}
+		err := json.Unmarshal([]byte(`{"cloudwatch_query": {"queries": `+queries+`}}`), &input)
+		assert.NoError(t, err)
+		obj.ApplyRule(input)
+		assert.Len(t, translator.ErrorMessages, 1, queries)
+	}
+}
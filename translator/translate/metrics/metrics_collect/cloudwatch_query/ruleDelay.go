@@ -0,0 +1,24 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package cloudwatch_query
+
+import (
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+)
+
+type Delay struct {
+}
+
+const SectionKey_Delay = "delay"
+
+// ApplyRule translates the seconds the queried period ends before the collection, the datapoints of CloudWatch are
+// complete some time after the end of their period.
+func (obj *Delay) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	return translator.DefaultTimeIntervalCase(SectionKey_Delay, float64(180), input)
+}
+
+func init() {
+	obj := new(Delay)
+	RegisterRule(SectionKey_Delay, obj)
+}
@@ -0,0 +1,23 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package cloudwatch_query
+
+type LeaderHost struct {
+}
+
+const SectionKey_LeaderHost = "leader_host"
+
+func (obj *LeaderHost) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	m := input.(map[string]interface{})
+	if leader, ok := m[SectionKey_LeaderHost].(string); ok && leader != "" {
+		returnKey = SectionKey_LeaderHost
+		returnVal = leader
+	}
+	return
+}
+
+func init() {
+	obj := new(LeaderHost)
+	RegisterRule(SectionKey_LeaderHost, obj)
+}
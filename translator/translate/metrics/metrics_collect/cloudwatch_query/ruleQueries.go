@@ -0,0 +1,74 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package cloudwatch_query
+
+import (
+	"fmt"
+
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+)
+
+type Queries struct {
+}
+
+const (
+	SectionKey_Queries = "queries"
+	queryKey           = "query"
+	idKey              = "id"
+	expressionKey      = "expression"
+	namespaceKey       = "namespace"
+	metricNameKey      = "metric_name"
+	dimensionsKey      = "dimensions"
+	returnDataKey      = "return_data"
+)
+
+// queryStringKeys are the settings of a query copied as they are.
+var queryStringKeys = []string{idKey, "name", expressionKey, namespaceKey, metricNameKey, "stat", "label_dimension"}
+
+// ApplyRule translates the queries of GetMetricData, each query is a metric or a metric math expression of the
+// queries before it.
+func (obj *Queries) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	m := input.(map[string]interface{})
+	queries, ok := m[SectionKey_Queries].([]interface{})
+	if !ok || len(queries) == 0 {
+		translator.AddErrorMessages(GetCurPath()+SectionKey_Queries, "at least one query is required")
+		return
+	}
+	result := []interface{}{}
+	for _, q := range queries {
+		query, ok := q.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		translated := map[string]interface{}{}
+		for _, key := range queryStringKeys {
+			if val, ok := query[key].(string); ok && val != "" {
+				translated[key] = val
+			}
+		}
+		_, expression := translated[expressionKey]
+		_, metric := translated[metricNameKey]
+		_, namespace := translated[namespaceKey]
+		if _, ok := translated[idKey]; !ok || expression == (metric || namespace) || (metric != namespace) {
+			translator.AddErrorMessages(GetCurPath()+SectionKey_Queries,
+				fmt.Sprintf("the query %v needs an id, and an expression or a namespace and a metric_name", query[idKey]))
+			return
+		}
+		if dimensions, ok := query[dimensionsKey].(map[string]interface{}); ok && len(dimensions) > 0 {
+			translated[dimensionsKey] = dimensions
+		}
+		if returnData, ok := query[returnDataKey].(bool); ok {
+			translated[returnDataKey] = returnData
+		}
+		result = append(result, translated)
+	}
+	returnKey = queryKey
+	returnVal = result
+	return
+}
+
+func init() {
+	obj := new(Queries)
+	RegisterRule(SectionKey_Queries, obj)
+}
@@ -22,9 +22,10 @@ import (
 //       "metrics_aggregation_interval": 60
 //   }
 //
+// the collectd_listener receives the binary protocol of collectd on udp, or on tcp where udp is blocked or lossy
 const (
 	SectionKey       = "collectd"
-	SectionMappedKey = "socket_listener"
+	SectionMappedKey = "collectd_listener"
 )
 
 var ChildRule = map[string]translator.Rule{}
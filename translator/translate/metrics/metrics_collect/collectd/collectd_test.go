@@ -7,6 +7,7 @@ import (
 	"encoding/json"
 	"testing"
 
+	"github.com/aws/amazon-cloudwatch-agent/translator"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -55,10 +56,92 @@ func TestCollectD_MinimumConfig(t *testing.T) {
 			"name_prefix":             "collectd_",
 			"collectd_auth_file":      "/etc/collectd/auth_file",
 			"collectd_security_level": "encrypt",
-			"collectd_typesdb":        []interface{}{"/usr/share/collectd/types.db"},
 			"tags":                    map[string]interface{}{"aws:AggregationInterval": "60s"},
 		},
 	}
 
 	assert.Equal(t, expect, actual)
 }
+
+func TestCollectD_UserSecurityLevels(t *testing.T) {
+	obj := new(CollectD)
+	var input interface{}
+	err := json.Unmarshal([]byte(`{"collectd": {
+		"collectd_user_security_levels": {"legacy": "sign"}
+	}}`), &input)
+	assert.NoError(t, err)
+
+	_, actual := obj.ApplyRule(input)
+
+	assert.Equal(t, map[string]string{"legacy": "sign"}, actual.([]interface{})[0].(map[string]interface{})["collectd_user_security_levels"])
+}
+
+func TestCollectD_TCPServiceAddress(t *testing.T) {
+	obj := new(CollectD)
+	var input interface{}
+	err := json.Unmarshal([]byte(`{"collectd": {
+		"service_address": "tcp://127.0.0.1:25826"
+	}}`), &input)
+	assert.NoError(t, err)
+
+	key, actual := obj.ApplyRule(input)
+
+	assert.Equal(t, "collectd_listener", key)
+	assert.Equal(t, "tcp://127.0.0.1:25826", actual.([]interface{})[0].(map[string]interface{})["service_address"])
+}
+
+func TestCollectD_IPVersionServiceAddress(t *testing.T) {
+	for _, address := range []string{"udp4://127.0.0.1:25826", "udp6://[::1]:25826", "tcp4://127.0.0.1:25826", "tcp6://[::1]:25826"} {
+		translator.ResetMessages()
+		c := new(CollectD)
+		var input interface{}
+		err := json.Unmarshal([]byte(`{"collectd": {"service_address": "`+address+`"}}`), &input)
+		assert.NoError(t, err)
+		_, actual := c.ApplyRule(input)
+		assert.Empty(t, translator.ErrorMessages, address)
+		assert.Equal(t, address, actual.([]interface{})[0].(map[string]interface{})["service_address"])
+	}
+}
+
+func TestCollectD_InvalidServiceAddress(t *testing.T) {
+	translator.ResetMessages()
+	obj := new(CollectD)
+	var input interface{}
+	err := json.Unmarshal([]byte(`{"collectd": {
+		"service_address": "127.0.0.1:25826"
+	}}`), &input)
+	assert.NoError(t, err)
+
+	obj.ApplyRule(input)
+	assert.Equal(t, []string{"Under path : /metrics/metrics_collected/collectd/service_address | Error : service_address must be udp://, udp4://, udp6://, tcp://, tcp4:// or tcp6:// followed by an address."}, translator.ErrorMessages)
+	translator.ResetMessages()
+}
+
+func TestCollectD_MaxSeries(t *testing.T) {
+	obj := new(CollectD)
+	var input interface{}
+	err := json.Unmarshal([]byte(`{"collectd": {
+		"max_series": 500,
+		"series_overflow": "aggregate",
+		"metrics_aggregation_interval": 30
+	}}`), &input)
+	assert.NoError(t, err)
+
+	_, actual := obj.ApplyRule(input)
+
+	expect := []interface{}{
+		map[string]interface{}{
+			"data_format":             "collectd",
+			"service_address":         "udp://127.0.0.1:25826",
+			"name_prefix":             "collectd_",
+			"collectd_auth_file":      "/etc/collectd/auth_file",
+			"collectd_security_level": "encrypt",
+			"max_series":              500,
+			"series_overflow":         "aggregate",
+			"max_series_interval":     "30s",
+			"tags":                    map[string]interface{}{"aws:AggregationInterval": "30s"},
+		},
+	}
+
+	assert.Equal(t, expect, actual)
+}
@@ -12,8 +12,13 @@ type TypesDB struct {
 
 const SectionKey_TypesDB = "collectd_typesdb"
 
+// ApplyRule only sets the configured types.db files, the collectd_listener finds the one installed with collectd and
+// bundles the types of the standard plugins.
 func (obj *TypesDB) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
-	returnKey, returnVal = translator.DefaultCase(SectionKey_TypesDB, []interface{}{"/usr/share/collectd/types.db"}, input)
+	returnKey, returnVal = translator.DefaultCase(SectionKey_TypesDB, "", input)
+	if returnVal == "" {
+		return "", nil
+	}
 	return
 }
 
@@ -0,0 +1,31 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package collected
+
+import (
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+)
+
+type UserSecurityLevels struct {
+}
+
+const SectionKey_UserSecurityLevels = "collectd_user_security_levels"
+
+// ApplyRule translates the security levels of the users overriding the collectd_security_level for their packets.
+func (obj *UserSecurityLevels) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	returnKey, returnVal = translator.DefaultCase(SectionKey_UserSecurityLevels, "", input)
+	if returnVal == "" {
+		return "", nil
+	}
+	levels := map[string]string{}
+	for user, level := range returnVal.(map[string]interface{}) {
+		levels[user] = level.(string)
+	}
+	return returnKey, levels
+}
+
+func init() {
+	obj := new(UserSecurityLevels)
+	RegisterRule(SectionKey_UserSecurityLevels, obj)
+}
@@ -0,0 +1,28 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package collected
+
+import (
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+	"github.com/aws/amazon-cloudwatch-agent/translator/translate/metrics/util"
+)
+
+type MaxSeries struct {
+}
+
+const SectionKey_MaxSeries = util.Max_Series_Key
+
+func (obj *MaxSeries) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	returnKey, returnVal = translator.DefaultCase(SectionKey_MaxSeries, "", input)
+	if returnVal != "" {
+		// By default json unmarshal will store number as float64
+		return returnKey, int(returnVal.(float64))
+	}
+	return "", nil
+}
+
+func init() {
+	obj := new(MaxSeries)
+	RegisterRule(SectionKey_MaxSeries, obj)
+}
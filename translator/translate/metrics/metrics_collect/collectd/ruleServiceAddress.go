@@ -4,6 +4,8 @@
 package collected
 
 import (
+	"strings"
+
 	"github.com/aws/amazon-cloudwatch-agent/translator"
 )
 
@@ -12,9 +14,19 @@ type ServiceAddress struct {
 
 const SectionKey_ServiceAddress = "service_address"
 
+// serviceAddressSchemes are the schemes of the addresses the collectd_listener listens on.
+var serviceAddressSchemes = []string{"udp://", "udp4://", "udp6://", "tcp://", "tcp4://", "tcp6://"}
+
 func (obj *ServiceAddress) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
 	returnKey, returnVal = translator.DefaultCase(SectionKey_ServiceAddress, "udp://127.0.0.1:25826", input)
-	return
+	address, _ := returnVal.(string)
+	for _, scheme := range serviceAddressSchemes {
+		if strings.HasPrefix(address, scheme) && len(address) > len(scheme) {
+			return
+		}
+	}
+	translator.AddErrorMessages(GetCurPath()+SectionKey_ServiceAddress, "service_address must be udp://, udp4://, udp6://, tcp://, tcp4:// or tcp6:// followed by an address.")
+	return "", nil
 }
 
 func init() {
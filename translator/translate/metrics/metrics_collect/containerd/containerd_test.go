@@ -0,0 +1,38 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package containerd
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContainerd(t *testing.T) {
+	c := new(Containerd)
+	var input interface{}
+	err := json.Unmarshal([]byte(`{"containerd":{"measurement": ["cpu_usage_percent", "mem_working_set"], "container_name_include": ["web*"]}}`), &input)
+	assert.NoError(t, err)
+	_, actual := c.ApplyRule(input)
+	expected := []interface{}{map[string]interface{}{
+		"container_name_include": []interface{}{"web*"},
+		"endpoint":               "unix:///run/containerd/containerd.sock",
+		"fieldpass":              []string{"cpu_usage_percent", "mem_working_set"},
+		"tagexclude":             []string{"container_id"},
+	}}
+	assert.Equal(t, expected, actual, "Expected to be equal")
+}
+
+func TestContainerdEndpoint(t *testing.T) {
+	c := new(Containerd)
+	var input interface{}
+	err := json.Unmarshal([]byte(`{"containerd":{"measurement": ["mem_working_set"], "endpoint": "unix:///run/k3s/containerd/containerd.sock", "container_name_exclude": ["pause"]}}`), &input)
+	assert.NoError(t, err)
+	_, actual := c.ApplyRule(input)
+	result := actual.([]interface{})[0].(map[string]interface{})
+	assert.Equal(t, "unix:///run/k3s/containerd/containerd.sock", result["endpoint"])
+	assert.Equal(t, []interface{}{"pause"}, result["container_name_exclude"])
+	assert.NotContains(t, result, "container_name_include")
+}
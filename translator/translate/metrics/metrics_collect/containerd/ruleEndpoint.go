@@ -0,0 +1,23 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package containerd
+
+import (
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+)
+
+type Endpoint struct {
+}
+
+const SectionKey_Endpoint = "endpoint"
+
+func (obj *Endpoint) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	returnKey, returnVal = translator.DefaultCase(SectionKey_Endpoint, "unix:///run/containerd/containerd.sock", input)
+	return
+}
+
+func init() {
+	obj := new(Endpoint)
+	RegisterRule(SectionKey_Endpoint, obj)
+}
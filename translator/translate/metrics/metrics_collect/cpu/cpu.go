@@ -47,6 +47,8 @@ func (c *Cpu) ApplyRule(input interface{}) (returnKey string, returnVal interfac
 		//Process common config, like measurement
 		hasValidMetric := util.ProcessLinuxCommonConfig(m[SectionKey_CPU], SectionKey_CPU, GetCurPath(), result)
 		if hasValidMetric {
+			//Process aggregate_percpu
+			util.ProcessAggregatePerCpu(m[SectionKey_CPU], result)
 			res = append(res, result)
 			returnKey = SectionKey_CPU
 			returnVal = res
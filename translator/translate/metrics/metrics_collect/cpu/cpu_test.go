@@ -62,3 +62,26 @@ func TestInvalidMetrics(t *testing.T) {
 		panic(err)
 	}
 }
+
+func TestAggregatePerCpu(t *testing.T) {
+	c := new(Cpu)
+	var input interface{}
+	err := json.Unmarshal([]byte(`{"cpu": {
+					"resources": [
+						"*"
+					],
+					"measurement": [
+						"cpu_usage_idle"
+					],
+					"metrics_collection_interval": 60,
+					"aggregate_percpu": true
+				}}`), &input)
+	if err == nil {
+		actualKey, actualVal := c.ApplyRule(input)
+		assert.Equal(t, "cpu", actualKey, "return key should be cpu")
+		tags := actualVal.([]interface{})[0].(map[string]interface{})["tags"]
+		assert.Equal(t, map[string]interface{}{"aggregate_percpu": "60s"}, tags, "Expect to be equal")
+	} else {
+		panic(err)
+	}
+}
@@ -13,6 +13,9 @@ type CollectCpuTime struct {
 }
 
 func (c *CollectCpuTime) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	if util.HasMeasurementWildcard(input) {
+		return "collect_cpu_time", true
+	}
 	measurementNames := util.GetMeasurementName(input)
 	for _, measurementName := range measurementNames {
 		measurementName = strings.TrimPrefix(measurementName, "cpu_")
@@ -13,6 +13,9 @@ type ReportActive struct {
 }
 
 func (r *ReportActive) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	if util.HasMeasurementWildcard(input) {
+		return "report_active", true
+	}
 	measurementNames := util.GetMeasurementName(input)
 	for _, measurementName := range measurementNames {
 		if strings.HasSuffix(measurementName, "active") {
@@ -61,3 +61,13 @@ func TestReportActive_UsageActive(t *testing.T) {
 		panic(err)
 	}
 }
+
+func TestReportActive_MeasurementWildcard(t *testing.T) {
+	r := new(ReportActive)
+	var input interface{}
+	err := json.Unmarshal([]byte(`{"measurement": ["*"]}`), &input)
+	assert.NoError(t, err)
+	actualReturnKey, actualReturnValue := r.ApplyRule(input)
+	assert.Equal(t, "report_active", actualReturnKey)
+	assert.Equal(t, true, actualReturnValue)
+}
@@ -0,0 +1,74 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package cpu_cluster
+
+import (
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+	parent "github.com/aws/amazon-cloudwatch-agent/translator/translate/metrics/metrics_collect"
+	"github.com/aws/amazon-cloudwatch-agent/translator/translate/metrics/util"
+)
+
+var ChildRule = map[string]translator.Rule{}
+
+const (
+	SectionKey_CPUCluster = "cpu_cluster"
+	pmuCountersKey        = "pmu_counters"
+)
+
+// the fields read from the PMU, the counters are only opened when one of them is collected
+var pmuFields = map[string]bool{
+	"cycles":                  true,
+	"instructions":            true,
+	"instructions_per_cycle":  true,
+	util.Measurement_Wildcard: true,
+}
+
+func GetCurPath() string {
+	curPath := parent.GetCurPath() + SectionKey_CPUCluster + "/"
+	return curPath
+}
+
+func RegisterRule(fieldname string, r translator.Rule) {
+	ChildRule[fieldname] = r
+}
+
+type CPUCluster struct {
+}
+
+func (c *CPUCluster) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	m := input.(map[string]interface{})
+	res := []interface{}{}
+	result := map[string]interface{}{}
+	//Check if this plugin exist in the input instance
+	//If not, not process
+	if _, ok := m[SectionKey_CPUCluster]; !ok {
+		returnKey = ""
+		returnVal = ""
+	} else {
+		//Check if there are some config entry with rules applied
+		result = translator.ProcessRuleToApply(m[SectionKey_CPUCluster], ChildRule, result)
+
+		//Process common config, like measurement
+		hasValidMetric := util.ProcessLinuxCommonConfig(m[SectionKey_CPUCluster], SectionKey_CPUCluster, GetCurPath(), result)
+		if hasValidMetric {
+			for _, field := range result["fieldpass"].([]string) {
+				if pmuFields[field] {
+					result[pmuCountersKey] = true
+					break
+				}
+			}
+			res = append(res, result)
+			returnKey = SectionKey_CPUCluster
+			returnVal = res
+		} else {
+			returnKey = ""
+		}
+	}
+	return
+}
+
+func init() {
+	c := new(CPUCluster)
+	parent.RegisterLinuxRule(SectionKey_CPUCluster, c)
+}
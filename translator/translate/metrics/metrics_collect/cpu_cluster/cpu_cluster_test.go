@@ -0,0 +1,37 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package cpu_cluster
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCPUCluster(t *testing.T) {
+	c := new(CPUCluster)
+	var input interface{}
+	err := json.Unmarshal([]byte(`{"cpu_cluster":{"measurement": ["frequency_mhz", "cpu_cluster_max_frequency_mhz"], "metrics_collection_interval": 60}}`), &input)
+	assert.NoError(t, err)
+	_, actual := c.ApplyRule(input)
+	expected := []interface{}{map[string]interface{}{
+		"fieldpass": []string{"frequency_mhz", "max_frequency_mhz"},
+		"interval":  "60s",
+	}}
+	assert.Equal(t, expected, actual, "Expected to be equal")
+}
+
+func TestCPUClusterWithPMUCounters(t *testing.T) {
+	c := new(CPUCluster)
+	var input interface{}
+	err := json.Unmarshal([]byte(`{"cpu_cluster":{"measurement": ["frequency_mhz", "instructions_per_cycle"]}}`), &input)
+	assert.NoError(t, err)
+	_, actual := c.ApplyRule(input)
+	expected := []interface{}{map[string]interface{}{
+		"fieldpass":    []string{"frequency_mhz", "instructions_per_cycle"},
+		"pmu_counters": true,
+	}}
+	assert.Equal(t, expected, actual, "Expected to be equal")
+}
@@ -37,6 +37,7 @@ func (c *customizedMetric) ApplyRule(input interface{}) (returnKey string, retur
 	sort.Strings(inputObjectNames)
 	for _, objectName := range inputObjectNames {
 		singleConfig := util.ProcessWindowsCommonConfig(inputmap[objectName], objectName, GetObjectPath(objectName))
+		util.ProcessNormalizeInstances(inputmap[objectName], singleConfig)
 		win_Perf_Counters_Array = addToWinPerfArray(singleConfig, win_Perf_Counters_Array)
 	}
 
@@ -46,6 +46,12 @@ func (d *DiskIO) ApplyRule(input interface{}) (returnKey string, returnVal inter
 
 		//Process common config, like measurement
 		hasValidMetric := util.ProcessLinuxCommonConfig(m[SectionKey_DiskIO_Linux], SectionKey_DiskIO_Linux, GetCurPath(), result)
+		if hasValidMetric {
+			// the latency distributions are collected by the diskio_latency input
+			fieldpass, _ := splitLatencyFields(result["fieldpass"].([]string))
+			hasValidMetric = len(fieldpass) > 0
+			result["fieldpass"] = fieldpass
+		}
 		if hasValidMetric {
 			//Process report_deltas
 			util.ProcessReportDeltasForDiskIO(m[SectionKey_DiskIO_Linux], result)
@@ -0,0 +1,57 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package diskio
+
+import (
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+	parent "github.com/aws/amazon-cloudwatch-agent/translator/translate/metrics/metrics_collect"
+	"github.com/aws/amazon-cloudwatch-agent/translator/translate/metrics/util"
+)
+
+const SectionKey_DiskIOLatency = "diskio_latency"
+
+// the fields of the diskio_latency input, they are only collected when listed since the wildcard of the existing
+// configs would add their distributions
+var latencyFields = map[string]bool{
+	"read_latency":  true,
+	"write_latency": true,
+}
+
+// splitLatencyFields splits the fieldpass of the diskio section between the diskio and the diskio_latency inputs.
+func splitLatencyFields(fieldpass []string) (diskio, latency []string) {
+	for _, field := range fieldpass {
+		if latencyFields[field] {
+			latency = append(latency, field)
+		} else {
+			diskio = append(diskio, field)
+		}
+	}
+	return
+}
+
+// DiskIOLatency translates the latency distributions of the diskio section into the diskio_latency input.
+type DiskIOLatency struct {
+}
+
+func (d *DiskIOLatency) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	m := input.(map[string]interface{})
+	if _, ok := m[SectionKey_DiskIO_Linux]; !ok {
+		return "", ""
+	}
+	// the devices of the section are the ones of the distributions too
+	result := translator.ProcessRuleToApply(m[SectionKey_DiskIO_Linux], ChildRule, map[string]interface{}{})
+	if !util.ProcessLinuxCommonConfig(m[SectionKey_DiskIO_Linux], SectionKey_DiskIO_Linux, GetCurPath(), result) {
+		return "", ""
+	}
+	_, latency := splitLatencyFields(result["fieldpass"].([]string))
+	if len(latency) == 0 {
+		return "", ""
+	}
+	result["fieldpass"] = latency
+	return SectionKey_DiskIOLatency, []interface{}{result}
+}
+
+func init() {
+	parent.RegisterLinuxRule(SectionKey_DiskIOLatency, new(DiskIOLatency))
+}
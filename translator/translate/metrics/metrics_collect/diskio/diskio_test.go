@@ -239,3 +239,46 @@ func TestDiskIOWithReportDeltaFalse(t *testing.T) {
 		assert.Equal(t, d, actual, "Expected to be equal")
 	}
 }
+
+func TestDiskIOWithLatency(t *testing.T) {
+	var input interface{}
+	err := json.Unmarshal([]byte(`{"diskio": {
+					"resources": ["nvme0n1"],
+					"measurement": ["reads", "read_latency", "diskio_write_latency"]
+					}}`), &input)
+	assert.NoError(t, err)
+	_, actual := new(DiskIO).ApplyRule(input)
+	assert.Equal(t, []interface{}{map[string]interface{}{
+		"devices":   []interface{}{"nvme0n1"},
+		"fieldpass": []string{"reads"},
+		"tags":      map[string]interface{}{"report_deltas": "true"},
+	}}, actual)
+
+	key, actual := new(DiskIOLatency).ApplyRule(input)
+	assert.Equal(t, "diskio_latency", key)
+	assert.Equal(t, []interface{}{map[string]interface{}{
+		"devices":   []interface{}{"nvme0n1"},
+		"fieldpass": []string{"read_latency", "write_latency"},
+	}}, actual)
+}
+
+func TestDiskIOWithOnlyLatency(t *testing.T) {
+	var input interface{}
+	err := json.Unmarshal([]byte(`{"diskio": {"measurement": ["write_latency"]}}`), &input)
+	assert.NoError(t, err)
+	key, _ := new(DiskIO).ApplyRule(input)
+	assert.Equal(t, "", key)
+	key, actual := new(DiskIOLatency).ApplyRule(input)
+	assert.Equal(t, "diskio_latency", key)
+	assert.Equal(t, []interface{}{map[string]interface{}{
+		"fieldpass": []string{"write_latency"},
+	}}, actual)
+}
+
+func TestDiskIOWildcardWithoutLatency(t *testing.T) {
+	var input interface{}
+	err := json.Unmarshal([]byte(`{"diskio": {"measurement": ["*"]}}`), &input)
+	assert.NoError(t, err)
+	key, _ := new(DiskIOLatency).ApplyRule(input)
+	assert.Equal(t, "", key)
+}
@@ -0,0 +1,57 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package docker
+
+import (
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+	parent "github.com/aws/amazon-cloudwatch-agent/translator/translate/metrics/metrics_collect"
+	"github.com/aws/amazon-cloudwatch-agent/translator/translate/metrics/util"
+)
+
+var ChildRule = map[string]translator.Rule{}
+
+const SectionKey_Docker = "docker"
+
+func GetCurPath() string {
+	curPath := parent.GetCurPath() + SectionKey_Docker + "/"
+	return curPath
+}
+
+func RegisterRule(fieldname string, r translator.Rule) {
+	ChildRule[fieldname] = r
+}
+
+type Docker struct {
+}
+
+func (d *Docker) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	m := input.(map[string]interface{})
+	res := []interface{}{}
+	result := map[string]interface{}{}
+	//Check if this plugin exist in the input instance
+	//If not, not process
+	if _, ok := m[SectionKey_Docker]; !ok {
+		returnKey = ""
+		returnVal = ""
+	} else {
+		//Check if there are some config entry with rules applied
+		result = translator.ProcessRuleToApply(m[SectionKey_Docker], ChildRule, result)
+
+		//Process common config, like measurement
+		hasValidMetric := util.ProcessLinuxCommonConfig(m[SectionKey_Docker], SectionKey_Docker, GetCurPath(), result)
+		if hasValidMetric {
+			res = append(res, result)
+			returnKey = SectionKey_Docker
+			returnVal = res
+		} else {
+			returnKey = ""
+		}
+	}
+	return
+}
+
+func init() {
+	d := new(Docker)
+	parent.RegisterLinuxRule(SectionKey_Docker, d)
+}
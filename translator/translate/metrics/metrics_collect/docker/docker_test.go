@@ -0,0 +1,38 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package docker
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDocker(t *testing.T) {
+	d := new(Docker)
+	var input interface{}
+	err := json.Unmarshal([]byte(`{"docker":{"measurement": ["cpu_usage_percent", "mem_usage"], "container_name_include": ["web*"]}}`), &input)
+	assert.NoError(t, err)
+	_, actual := d.ApplyRule(input)
+	expected := []interface{}{map[string]interface{}{
+		"container_name_include": []interface{}{"web*"},
+		"endpoint":               "unix:///var/run/docker.sock",
+		"fieldpass":              []string{"cpu_usage_percent", "mem_usage"},
+		"tagexclude":             []string{"container_id"},
+	}}
+	assert.Equal(t, expected, actual, "Expected to be equal")
+}
+
+func TestDockerEndpoint(t *testing.T) {
+	d := new(Docker)
+	var input interface{}
+	err := json.Unmarshal([]byte(`{"docker":{"measurement": ["mem_usage"], "endpoint": "tcp://127.0.0.1:2375", "container_name_exclude": ["ecs-agent"]}}`), &input)
+	assert.NoError(t, err)
+	_, actual := d.ApplyRule(input)
+	result := actual.([]interface{})[0].(map[string]interface{})
+	assert.Equal(t, "tcp://127.0.0.1:2375", result["endpoint"])
+	assert.Equal(t, []interface{}{"ecs-agent"}, result["container_name_exclude"])
+	assert.NotContains(t, result, "container_name_include")
+}
@@ -0,0 +1,26 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package docker
+
+import (
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+)
+
+type ContainerNameExclude struct {
+}
+
+const SectionKey_ContainerNameExclude = "container_name_exclude"
+
+func (obj *ContainerNameExclude) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	returnKey, returnVal = translator.DefaultCase(SectionKey_ContainerNameExclude, []interface{}{}, input)
+	if list, ok := returnVal.([]interface{}); ok && len(list) == 0 {
+		return "", nil
+	}
+	return
+}
+
+func init() {
+	obj := new(ContainerNameExclude)
+	RegisterRule(SectionKey_ContainerNameExclude, obj)
+}
@@ -0,0 +1,26 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package docker
+
+import (
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+)
+
+type ContainerNameInclude struct {
+}
+
+const SectionKey_ContainerNameInclude = "container_name_include"
+
+func (obj *ContainerNameInclude) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	returnKey, returnVal = translator.DefaultCase(SectionKey_ContainerNameInclude, []interface{}{}, input)
+	if list, ok := returnVal.([]interface{}); ok && len(list) == 0 {
+		return "", nil
+	}
+	return
+}
+
+func init() {
+	obj := new(ContainerNameInclude)
+	RegisterRule(SectionKey_ContainerNameInclude, obj)
+}
@@ -5,7 +5,10 @@ package ethtool
 
 import (
 	"github.com/aws/amazon-cloudwatch-agent/translator"
+	"github.com/aws/amazon-cloudwatch-agent/translator/translate/agent"
 	parent "github.com/aws/amazon-cloudwatch-agent/translator/translate/metrics/metrics_collect"
+	"github.com/aws/amazon-cloudwatch-agent/translator/translate/metrics/util"
+	translateUtil "github.com/aws/amazon-cloudwatch-agent/translator/translate/util"
 )
 
 var ChildRule = map[string]translator.Rule{}
@@ -48,6 +51,10 @@ func (n *Ethtool) ApplyRule(input interface{}) (returnKey string, returnVal inte
 		//If exists, process it
 		//Check if there are some config entry with rules applied
 		result = translator.ProcessRuleToApply(m[SectionKey_Ethtool], ChildRule, result)
+		// the counters are collected at the interval of the agent
+		if util.IsHighResolution(agent.Global_Config.Interval) {
+			result[util.Append_Dimensions_Mapped_Key] = map[string]interface{}{translateUtil.High_Resolution_Tag_Key: "true"}
+		}
 		resArr = append(resArr, result)
 		returnKey = SectionKey_Ethtool
 		returnVal = resArr
@@ -7,6 +7,7 @@ import (
 	"encoding/json"
 	"testing"
 
+	"github.com/aws/amazon-cloudwatch-agent/translator/translate/agent"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -54,3 +55,22 @@ func TestFullConfig(t *testing.T) {
 		assert.Equal(t, d, actual, "Expected to be equal")
 	}
 }
+
+func TestHighResolutionConfig(t *testing.T) {
+	agent.Global_Config.Interval = "1s"
+	defer func() { agent.Global_Config.Interval = "" }()
+	d := new(Ethtool)
+	var input interface{}
+	e := json.Unmarshal([]byte(`{"ethtool": {}}`), &input)
+	assert.NoError(t, e)
+
+	_, actual := d.ApplyRule(input)
+
+	expected := []interface{}{map[string]interface{}{
+		"interface_include": []string{"*"},
+		"fieldpass":         []string{},
+		"tags":              map[string]interface{}{"aws:StorageResolution": "true"},
+	},
+	}
+	assert.Equal(t, expected, actual)
+}
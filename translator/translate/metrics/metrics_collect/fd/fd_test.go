@@ -0,0 +1,48 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package fd
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFD(t *testing.T) {
+	f := new(FD)
+	var input interface{}
+	err := json.Unmarshal([]byte(`{"fd":{"measurement": ["utilization", "fd_process_utilization"]}}`), &input)
+	assert.NoError(t, err)
+	_, actual := f.ApplyRule(input)
+	expected := []interface{}{map[string]interface{}{
+		"fieldpass":     []string{"utilization", "process_utilization"},
+		"top_processes": 5,
+	}}
+	assert.Equal(t, expected, actual, "Expected to be equal")
+}
+
+func TestFDWithTopProcesses(t *testing.T) {
+	f := new(FD)
+	var input interface{}
+	err := json.Unmarshal([]byte(`{"fd":{"measurement": ["utilization"], "top_processes": 0}}`), &input)
+	assert.NoError(t, err)
+	_, actual := f.ApplyRule(input)
+	expected := []interface{}{map[string]interface{}{
+		"fieldpass":     []string{"utilization"},
+		"top_processes": 0,
+	}}
+	assert.Equal(t, expected, actual, "Expected to be equal")
+}
+
+func TestFDWithInvalidTopProcesses(t *testing.T) {
+	translator.ResetMessages()
+	f := new(FD)
+	var input interface{}
+	err := json.Unmarshal([]byte(`{"fd":{"measurement": ["utilization"], "top_processes": "5"}}`), &input)
+	assert.NoError(t, err)
+	f.ApplyRule(input)
+	assert.Equal(t, 1, len(translator.ErrorMessages))
+}
@@ -0,0 +1,25 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package fd
+
+import (
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+)
+
+type TopProcesses struct {
+}
+
+const (
+	SectionKey_TopProcesses = "top_processes"
+	defaultTopProcesses     = 5
+)
+
+func (obj *TopProcesses) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	return translator.DefaultIntegralCase(SectionKey_TopProcesses, float64(defaultTopProcesses), input)
+}
+
+func init() {
+	obj := new(TopProcesses)
+	RegisterRule(SectionKey_TopProcesses, obj)
+}
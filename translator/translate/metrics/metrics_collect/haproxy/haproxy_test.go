@@ -0,0 +1,43 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package haproxy
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHAProxy(t *testing.T) {
+	h := new(HAProxy)
+	var input interface{}
+	err := json.Unmarshal([]byte(`{"haproxy":{"measurement": ["scur", "eresp_rate", "up"], "proxies": ["web*"]}}`), &input)
+	assert.NoError(t, err)
+	_, actual := h.ApplyRule(input)
+	expected := []interface{}{map[string]interface{}{
+		"fieldpass": []string{"scur", "eresp_rate", "up"},
+		"proxies":   []interface{}{"web*"},
+		"servers":   []interface{}{"unix:///var/run/haproxy.sock"},
+	}}
+	assert.Equal(t, expected, actual, "Expected to be equal")
+}
+
+func TestHAProxyStatsPage(t *testing.T) {
+	h := new(HAProxy)
+	var input interface{}
+	err := json.Unmarshal([]byte(`{"haproxy":{"measurement": ["up"], "servers": ["http://localhost:8404/stats"], "username": "admin", "password": "secret", "proxies": []}}`), &input)
+	assert.NoError(t, err)
+	_, actual := h.ApplyRule(input)
+	result := actual.([]interface{})[0].(map[string]interface{})
+	assert.Equal(t, []interface{}{"http://localhost:8404/stats"}, result["servers"])
+	assert.Equal(t, "admin", result["username"])
+	assert.Equal(t, "secret", result["password"])
+	assert.NotContains(t, result, "proxies")
+
+	err = json.Unmarshal([]byte(`{"haproxy":{"measurement": ["up"], "username": ""}}`), &input)
+	assert.NoError(t, err)
+	_, actual = h.ApplyRule(input)
+	assert.NotContains(t, actual.([]interface{})[0], "username")
+}
@@ -0,0 +1,26 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package haproxy
+
+import (
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+)
+
+type Proxies struct {
+}
+
+const SectionKey_Proxies = "proxies"
+
+func (obj *Proxies) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	returnKey, returnVal = translator.DefaultCase(SectionKey_Proxies, []interface{}{}, input)
+	if list, ok := returnVal.([]interface{}); ok && len(list) == 0 {
+		return "", nil
+	}
+	return
+}
+
+func init() {
+	obj := new(Proxies)
+	RegisterRule(SectionKey_Proxies, obj)
+}
@@ -0,0 +1,23 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package haproxy
+
+import (
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+)
+
+type Servers struct {
+}
+
+const SectionKey_Servers = "servers"
+
+func (obj *Servers) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	returnKey, returnVal = translator.DefaultCase(SectionKey_Servers, []interface{}{"unix:///var/run/haproxy.sock"}, input)
+	return
+}
+
+func init() {
+	obj := new(Servers)
+	RegisterRule(SectionKey_Servers, obj)
+}
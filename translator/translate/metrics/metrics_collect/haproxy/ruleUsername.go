@@ -0,0 +1,26 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package haproxy
+
+import (
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+)
+
+type Username struct {
+}
+
+const SectionKey_Username = "username"
+
+func (obj *Username) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	returnKey, returnVal = translator.DefaultCase(SectionKey_Username, "", input)
+	if returnVal == "" {
+		return "", nil
+	}
+	return
+}
+
+func init() {
+	obj := new(Username)
+	RegisterRule(SectionKey_Username, obj)
+}
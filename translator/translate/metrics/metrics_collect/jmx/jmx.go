@@ -0,0 +1,252 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package jmx
+
+import (
+	"fmt"
+
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+	"github.com/aws/amazon-cloudwatch-agent/translator/jsonconfig/mergeJsonRule"
+	"github.com/aws/amazon-cloudwatch-agent/translator/jsonconfig/mergeJsonUtil"
+	"github.com/aws/amazon-cloudwatch-agent/translator/translate/agent"
+	"github.com/aws/amazon-cloudwatch-agent/translator/translate/metrics/config"
+	parent "github.com/aws/amazon-cloudwatch-agent/translator/translate/metrics/metrics_collect"
+	"github.com/aws/amazon-cloudwatch-agent/translator/translate/metrics/util"
+	translateUtil "github.com/aws/amazon-cloudwatch-agent/translator/translate/util"
+)
+
+//
+//	"jmx": [
+//		{
+//			"endpoint": "http://localhost:8778/jolokia",
+//			"jvm_metrics": ["heap", "gc", "threads"],
+//			"mbeans": [
+//				{
+//					"name": "tomcat_requests",
+//					"mbean": "Catalina:type=GlobalRequestProcessor,name=*",
+//					"paths": ["requestCount", "errorCount"],
+//					"tag_keys": ["name"]
+//				}
+//			],
+//			"metrics_collection_interval": 60
+//		}
+//	]
+//
+
+// SectionKey is the name of the JVM metrics in user config, collected through the Jolokia agent of the JVM.
+const SectionKey = "jmx"
+
+const (
+	endpointKey        = "endpoint"
+	usernameKey        = "username"
+	passwordKey        = "password"
+	responseTimeoutKey = "response_timeout"
+	jvmMetricsKey      = "jvm_metrics"
+	mbeansKey          = "mbeans"
+	mbeanNameKey       = "name"
+	mbeanKey           = "mbean"
+	pathsKey           = "paths"
+	tagKeysKey         = "tag_keys"
+
+	urlsMappedKey            = "urls"
+	responseTimeoutMappedKey = "response_timeout"
+	metricMappedKey          = "metric"
+
+	defaultEndpoint = "http://localhost:8778/jolokia"
+)
+
+// jvmMetrics are the predefined MBean reads of the JVM health metrics.
+var jvmMetrics = map[string]map[string]interface{}{
+	"heap": {
+		"name":  "jvm_memory",
+		"mbean": "java.lang:type=Memory",
+		"paths": []string{"HeapMemoryUsage", "NonHeapMemoryUsage", "ObjectPendingFinalizationCount"},
+	},
+	"gc": {
+		"name":     "jvm_garbage_collector",
+		"mbean":    "java.lang:type=GarbageCollector,name=*",
+		"paths":    []string{"CollectionTime", "CollectionCount"},
+		"tag_keys": []string{"name"},
+	},
+	"threads": {
+		"name":  "jvm_threading",
+		"mbean": "java.lang:type=Threading",
+		"paths": []string{"ThreadCount", "PeakThreadCount", "DaemonThreadCount", "TotalStartedThreadCount"},
+	},
+}
+
+var defaultJvmMetrics = []string{"heap", "gc", "threads"}
+
+func GetCurPath() string {
+	curPath := parent.GetCurPath() + SectionKey + "/"
+	return curPath
+}
+
+type Jmx struct {
+}
+
+func (j *Jmx) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	im := input.(map[string]interface{})
+	returnKey = ""
+	returnVal = ""
+	configArray, ok := im[SectionKey].([]interface{})
+	if !ok {
+		return
+	}
+	// jmx is not the real telegraf plugin's name, need to register the real plugin name to enable it.
+	telegrafPluginName := config.GetRealPluginName(SectionKey)
+
+	resArray := []interface{}{}
+	for _, jmxConfig := range configArray {
+		jmxMap, ok := jmxConfig.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		result := map[string]interface{}{}
+
+		endpoint := defaultEndpoint
+		if val, ok := jmxMap[endpointKey].(string); ok && val != "" {
+			endpoint = val
+		}
+		result[urlsMappedKey] = []string{endpoint}
+		if val, ok := jmxMap[usernameKey].(string); ok && val != "" {
+			result[usernameKey] = val
+		}
+		if val, ok := jmxMap[passwordKey].(string); ok && val != "" {
+			result[passwordKey] = val
+		}
+		if val, ok := jmxMap[responseTimeoutKey].(float64); ok {
+			result[responseTimeoutMappedKey] = fmt.Sprintf("%ds", int(val))
+		}
+
+		metrics := applyJvmMetrics(jmxMap)
+		metrics = append(metrics, applyMBeans(jmxMap)...)
+		if len(metrics) == 0 {
+			translator.AddErrorMessages(GetCurPath(), "no jvm_metrics or mbeans are configured for "+endpoint)
+			continue
+		}
+		result[metricMappedKey] = metrics
+
+		isHighResolution := util.IsHighResolution(agent.Global_Config.Interval)
+		if key, val := util.ProcessMetricsCollectionInterval(jmxMap, "", telegrafPluginName); key != "" {
+			result[key] = val
+			isHighResolution = util.IsHighResolution(val.(string))
+		}
+		if val, ok := jmxMap[util.Append_Dimensions_Key]; ok {
+			result[util.Append_Dimensions_Mapped_Key] = val
+			translateUtil.Cleanup(val)
+		}
+		if isHighResolution {
+			if result[util.Append_Dimensions_Mapped_Key] != nil {
+				translateUtil.AddHighResolutionTag(result[util.Append_Dimensions_Mapped_Key])
+			} else {
+				result[util.Append_Dimensions_Mapped_Key] = map[string]interface{}{translateUtil.High_Resolution_Tag_Key: "true"}
+			}
+		}
+		resArray = append(resArray, result)
+	}
+
+	if len(resArray) == 0 {
+		return
+	}
+	returnKey = telegrafPluginName
+	returnVal = resArray
+	return
+}
+
+func applyJvmMetrics(jmxMap map[string]interface{}) []interface{} {
+	names := defaultJvmMetrics
+	if val, ok := jmxMap[jvmMetricsKey].([]interface{}); ok {
+		names = []string{}
+		for _, name := range val {
+			if nameStr, ok := name.(string); ok {
+				names = append(names, nameStr)
+			}
+		}
+	}
+
+	metrics := []interface{}{}
+	for _, name := range names {
+		metric, ok := jvmMetrics[name]
+		if !ok {
+			translator.AddErrorMessages(GetCurPath()+jvmMetricsKey, "jvm_metrics "+name+" is invalid")
+			continue
+		}
+		metrics = append(metrics, copyMetric(metric))
+	}
+	return metrics
+}
+
+// copyMetric copies the predefined metric and its lists, so the translated configs of the instances don't share them.
+func copyMetric(metric map[string]interface{}) map[string]interface{} {
+	result := make(map[string]interface{}, len(metric))
+	for k, v := range metric {
+		if list, ok := v.([]string); ok {
+			v = append([]string(nil), list...)
+		}
+		result[k] = v
+	}
+	return result
+}
+
+func applyMBeans(jmxMap map[string]interface{}) []interface{} {
+	mbeans, ok := jmxMap[mbeansKey].([]interface{})
+	if !ok {
+		return nil
+	}
+
+	metrics := []interface{}{}
+	for _, mbean := range mbeans {
+		mbeanMap, ok := mbean.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		name, _ := mbeanMap[mbeanNameKey].(string)
+		objectName, _ := mbeanMap[mbeanKey].(string)
+		if name == "" || objectName == "" {
+			translator.AddErrorMessages(GetCurPath()+mbeansKey, "name and mbean are required for the mbeans")
+			continue
+		}
+		metric := map[string]interface{}{
+			mbeanNameKey: name,
+			mbeanKey:     objectName,
+		}
+		if paths := toStringList(mbeanMap[pathsKey]); len(paths) > 0 {
+			metric[pathsKey] = paths
+		}
+		if tagKeys := toStringList(mbeanMap[tagKeysKey]); len(tagKeys) > 0 {
+			metric[tagKeysKey] = tagKeys
+		}
+		metrics = append(metrics, metric)
+	}
+	return metrics
+}
+
+func toStringList(val interface{}) []string {
+	list, ok := val.([]interface{})
+	if !ok {
+		return nil
+	}
+	result := []string{}
+	for _, item := range list {
+		if itemStr, ok := item.(string); ok {
+			result = append(result, itemStr)
+		}
+	}
+	return result
+}
+
+var MergeRuleMap = map[string]mergeJsonRule.MergeRule{}
+
+func (j *Jmx) Merge(source map[string]interface{}, result map[string]interface{}) {
+	mergeJsonUtil.MergeList(source, result, SectionKey)
+}
+
+func init() {
+	j := new(Jmx)
+	parent.RegisterLinuxRule(SectionKey, j)
+	parent.RegisterDarwinRule(SectionKey, j)
+	parent.RegisterWindowsRule(SectionKey, j)
+	parent.MergeRuleMap[SectionKey] = j
+}
@@ -0,0 +1,112 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package jmx
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+	"github.com/aws/amazon-cloudwatch-agent/translator/translate/agent"
+	"github.com/stretchr/testify/assert"
+)
+
+func checkResult(t *testing.T, inputBytes []byte, expectedKey string, expectedOutput interface{}) {
+	j := new(Jmx)
+	var input interface{}
+	err := json.Unmarshal(inputBytes, &input)
+	assert.NoError(t, err)
+	actualKey, actualOutput := j.ApplyRule(input)
+	assert.Equal(t, expectedKey, actualKey)
+	assert.Equal(t, expectedOutput, actualOutput, "Expect to be equal")
+}
+
+func TestDefaultConfig(t *testing.T) {
+	agent.Global_Config = *new(agent.Agent)
+	input := []byte(`{"jmx": [{}]}`)
+	expectedVal := []interface{}{map[string]interface{}{
+		"urls": []string{"http://localhost:8778/jolokia"},
+		"metric": []interface{}{
+			jvmMetrics["heap"],
+			jvmMetrics["gc"],
+			jvmMetrics["threads"],
+		},
+	}}
+	checkResult(t, input, "jolokia2_agent", expectedVal)
+}
+
+func TestFullConfig(t *testing.T) {
+	agent.Global_Config = *new(agent.Agent)
+	input := []byte(`{"jmx": [
+		{
+			"endpoint": "http://10.0.0.1:8080/jolokia",
+			"username": "monitor",
+			"password": "secret",
+			"response_timeout": 10,
+			"jvm_metrics": ["heap"],
+			"mbeans": [
+				{
+					"name": "tomcat_requests",
+					"mbean": "Catalina:type=GlobalRequestProcessor,name=*",
+					"paths": ["requestCount", "errorCount"],
+					"tag_keys": ["name"]
+				},
+				{
+					"name": "kafka_messages_in",
+					"mbean": "kafka.server:type=BrokerTopicMetrics,name=MessagesInPerSec"
+				}
+			],
+			"metrics_collection_interval": 10,
+			"append_dimensions": {"App": "tomcat"}
+		}
+	]}`)
+	expectedVal := []interface{}{map[string]interface{}{
+		"urls":             []string{"http://10.0.0.1:8080/jolokia"},
+		"username":         "monitor",
+		"password":         "secret",
+		"response_timeout": "10s",
+		"interval":         "10s",
+		"tags":             map[string]interface{}{"App": "tomcat", "aws:StorageResolution": "true"},
+		"metric": []interface{}{
+			jvmMetrics["heap"],
+			map[string]interface{}{
+				"name":     "tomcat_requests",
+				"mbean":    "Catalina:type=GlobalRequestProcessor,name=*",
+				"paths":    []string{"requestCount", "errorCount"},
+				"tag_keys": []string{"name"},
+			},
+			map[string]interface{}{
+				"name":  "kafka_messages_in",
+				"mbean": "kafka.server:type=BrokerTopicMetrics,name=MessagesInPerSec",
+			},
+		},
+	}}
+	checkResult(t, input, "jolokia2_agent", expectedVal)
+}
+
+func TestInvalidConfig(t *testing.T) {
+	agent.Global_Config = *new(agent.Agent)
+	translator.ResetMessages()
+	input := []byte(`{"jmx": [
+		{"jvm_metrics": ["classes"]},
+		{"jvm_metrics": [], "mbeans": [{"name": "missing_mbean"}]}
+	]}`)
+	checkResult(t, input, "", "")
+	assert.Equal(t, 4, len(translator.ErrorMessages))
+}
+
+func TestJvmMetricsNotShared(t *testing.T) {
+	agent.Global_Config = *new(agent.Agent)
+	var input interface{}
+	assert.NoError(t, json.Unmarshal([]byte(`{"jmx": [{}, {"endpoint": "http://10.0.0.1:8080/jolokia"}]}`), &input))
+	_, actual := new(Jmx).ApplyRule(input)
+	instances := actual.([]interface{})
+	first := instances[0].(map[string]interface{})["metric"].([]interface{})[0].(map[string]interface{})
+	second := instances[1].(map[string]interface{})["metric"].([]interface{})[0].(map[string]interface{})
+
+	// the lists of an instance can be changed without changing the other instances or the predefined metrics
+	first["paths"].([]string)[0] = "Changed"
+	assert.Equal(t, "HeapMemoryUsage", second["paths"].([]string)[0])
+	assert.Equal(t, "HeapMemoryUsage", jvmMetrics["heap"]["paths"].([]string)[0])
+}
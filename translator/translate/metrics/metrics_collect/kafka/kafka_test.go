@@ -0,0 +1,39 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package kafka
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKafka(t *testing.T) {
+	k := new(Kafka)
+	var input interface{}
+	err := json.Unmarshal([]byte(`{"kafka":{"measurement": ["consumer_lag", "topic_offline_partitions"], "consumer_groups": ["billing-*"]}}`), &input)
+	assert.NoError(t, err)
+	_, actual := k.ApplyRule(input)
+	expected := []interface{}{map[string]interface{}{
+		"brokers":         []interface{}{"localhost:9092"},
+		"consumer_groups": []interface{}{"billing-*"},
+		"fieldpass":       []string{"consumer_lag", "topic_offline_partitions"},
+	}}
+	assert.Equal(t, expected, actual, "Expected to be equal")
+}
+
+func TestKafkaPerPartitionLag(t *testing.T) {
+	k := new(Kafka)
+	var input interface{}
+	err := json.Unmarshal([]byte(`{"kafka":{"measurement": ["kafka_consumer_partition_lag"], "brokers": ["b-1.msk:9094", "b-2.msk:9094"], "topics": ["orders"], "tls": true}}`), &input)
+	assert.NoError(t, err)
+	_, actual := k.ApplyRule(input)
+	result := actual.([]interface{})[0].(map[string]interface{})
+	assert.Equal(t, []interface{}{"b-1.msk:9094", "b-2.msk:9094"}, result["brokers"])
+	assert.Equal(t, []interface{}{"orders"}, result["topics"])
+	assert.Equal(t, true, result["per_partition_lag"])
+	assert.Equal(t, true, result["tls"])
+	assert.NotContains(t, result, "consumer_groups")
+}
@@ -0,0 +1,23 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package kafka
+
+import (
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+)
+
+type Brokers struct {
+}
+
+const SectionKey_Brokers = "brokers"
+
+func (obj *Brokers) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	returnKey, returnVal = translator.DefaultCase(SectionKey_Brokers, []interface{}{"localhost:9092"}, input)
+	return
+}
+
+func init() {
+	obj := new(Brokers)
+	RegisterRule(SectionKey_Brokers, obj)
+}
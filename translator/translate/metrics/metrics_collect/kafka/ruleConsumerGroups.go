@@ -0,0 +1,26 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package kafka
+
+import (
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+)
+
+type ConsumerGroups struct {
+}
+
+const SectionKey_ConsumerGroups = "consumer_groups"
+
+func (obj *ConsumerGroups) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	returnKey, returnVal = translator.DefaultCase(SectionKey_ConsumerGroups, []interface{}{}, input)
+	if list, ok := returnVal.([]interface{}); ok && len(list) == 0 {
+		return "", nil
+	}
+	return
+}
+
+func init() {
+	obj := new(ConsumerGroups)
+	RegisterRule(SectionKey_ConsumerGroups, obj)
+}
@@ -0,0 +1,32 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package kafka
+
+import (
+	"strings"
+
+	"github.com/aws/amazon-cloudwatch-agent/translator/translate/metrics/util"
+)
+
+// PerPartitionLag enables the lag of every partition when it is one of the measurements.
+type PerPartitionLag struct {
+}
+
+const SectionKey_PerPartitionLag = "per_partition_lag"
+
+func (obj *PerPartitionLag) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	for _, measurementName := range util.GetMeasurementName(input) {
+		if name := strings.TrimPrefix(measurementName, "kafka_"); name == "consumer_partition_lag" || name == util.Measurement_Wildcard {
+			returnKey = SectionKey_PerPartitionLag
+			returnVal = true
+			return
+		}
+	}
+	return
+}
+
+func init() {
+	obj := new(PerPartitionLag)
+	RegisterRule(SectionKey_PerPartitionLag, obj)
+}
@@ -0,0 +1,26 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package kafka
+
+import (
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+)
+
+type TLS struct {
+}
+
+const SectionKey_TLS = "tls"
+
+func (obj *TLS) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	returnKey, returnVal = translator.DefaultCase(SectionKey_TLS, false, input)
+	if returnVal != true {
+		return "", nil
+	}
+	return
+}
+
+func init() {
+	obj := new(TLS)
+	RegisterRule(SectionKey_TLS, obj)
+}
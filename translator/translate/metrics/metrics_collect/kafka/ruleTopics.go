@@ -0,0 +1,26 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package kafka
+
+import (
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+)
+
+type Topics struct {
+}
+
+const SectionKey_Topics = "topics"
+
+func (obj *Topics) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	returnKey, returnVal = translator.DefaultCase(SectionKey_Topics, []interface{}{}, input)
+	if list, ok := returnVal.([]interface{}); ok && len(list) == 0 {
+		return "", nil
+	}
+	return
+}
+
+func init() {
+	obj := new(Topics)
+	RegisterRule(SectionKey_Topics, obj)
+}
@@ -0,0 +1,65 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package kernel
+
+import (
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+	parent "github.com/aws/amazon-cloudwatch-agent/translator/translate/metrics/metrics_collect"
+	"github.com/aws/amazon-cloudwatch-agent/translator/translate/metrics/util"
+)
+
+var ChildRule = map[string]translator.Rule{}
+
+const (
+	SectionKey_Kernel = "kernel"
+	perIRQKey         = "per_irq"
+)
+
+func GetCurPath() string {
+	curPath := parent.GetCurPath() + SectionKey_Kernel + "/"
+	return curPath
+}
+
+func RegisterRule(fieldname string, r translator.Rule) {
+	ChildRule[fieldname] = r
+}
+
+type Kernel struct {
+}
+
+func (k *Kernel) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	m := input.(map[string]interface{})
+	res := []interface{}{}
+	result := map[string]interface{}{}
+	//Check if this plugin exist in the input instance
+	//If not, not process
+	if _, ok := m[SectionKey_Kernel]; !ok {
+		returnKey = ""
+		returnVal = ""
+	} else {
+		//Check if there are some config entry with rules applied
+		result = translator.ProcessRuleToApply(m[SectionKey_Kernel], ChildRule, result)
+
+		//Process common config, like measurement
+		hasValidMetric := util.ProcessLinuxCommonConfig(m[SectionKey_Kernel], SectionKey_Kernel, GetCurPath(), result)
+		if hasValidMetric {
+			// the interrupts of each IRQ are reported next to the total
+			if perIRQ, ok := m[SectionKey_Kernel].(map[string]interface{})[perIRQKey].(bool); ok && perIRQ {
+				result[perIRQKey] = true
+			}
+			util.ProcessReportDeltasForKernel(m[SectionKey_Kernel], result)
+			res = append(res, result)
+			returnKey = SectionKey_Kernel
+			returnVal = res
+		} else {
+			returnKey = ""
+		}
+	}
+	return
+}
+
+func init() {
+	k := new(Kernel)
+	parent.RegisterLinuxRule(SectionKey_Kernel, k)
+}
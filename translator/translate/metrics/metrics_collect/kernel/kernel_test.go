@@ -0,0 +1,53 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package kernel
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKernel(t *testing.T) {
+	k := new(Kernel)
+	var input interface{}
+	err := json.Unmarshal([]byte(`{"kernel":{"measurement": ["conntrack_utilization", "kernel_conntrack_drop"]}}`), &input)
+	assert.NoError(t, err)
+	_, actual := k.ApplyRule(input)
+	expected := []interface{}{map[string]interface{}{
+		"fieldpass": []string{"conntrack_utilization", "conntrack_drop"},
+		"tags": map[string]interface{}{
+			"report_deltas":            "true",
+			"ignored_fields_for_delta": "arp_entries,arp_max,arp_utilization,conntrack_count,conntrack_max,conntrack_utilization,context_switches_per_sec,entropy_avail,interrupts_per_sec,tcp_mem_max_pages,tcp_mem_pages,tcp_mem_pressure_pages,tcp_mem_utilization,udp_mem_max_pages,udp_mem_pages,udp_mem_pressure_pages,udp_mem_utilization",
+		},
+	}}
+	assert.Equal(t, expected, actual, "Expected to be equal")
+}
+
+func TestKernelWithoutDeltas(t *testing.T) {
+	k := new(Kernel)
+	var input interface{}
+	err := json.Unmarshal([]byte(`{"kernel":{"measurement": ["arp_utilization"], "report_deltas": false, "metrics_collection_interval": 60}}`), &input)
+	assert.NoError(t, err)
+	_, actual := k.ApplyRule(input)
+	expected := []interface{}{map[string]interface{}{
+		"fieldpass": []string{"arp_utilization"},
+		"interval":  "60s",
+	}}
+	assert.Equal(t, expected, actual, "Expected to be equal")
+}
+
+func TestKernelPerIRQ(t *testing.T) {
+	k := new(Kernel)
+	var input interface{}
+	err := json.Unmarshal([]byte(`{"kernel":{"measurement": ["interrupts_per_sec", "context_switches_per_sec", "entropy_avail"], "per_irq": true, "report_deltas": false}}`), &input)
+	assert.NoError(t, err)
+	_, actual := k.ApplyRule(input)
+	expected := []interface{}{map[string]interface{}{
+		"fieldpass": []string{"interrupts_per_sec", "context_switches_per_sec", "entropy_avail"},
+		"per_irq":   true,
+	}}
+	assert.Equal(t, expected, actual, "Expected to be equal")
+}
@@ -45,6 +45,8 @@ func (m *Mem) ApplyRule(input interface{}) (returnKey string, returnVal interfac
 
 		//Process common config, like measurement
 		hasValidMetric := util.ProcessLinuxCommonConfig(im[SectionKey_Mem_Linux], SectionKey_Mem_Linux, GetCurPath(), result)
+		// the numa metrics are reported by the numa input
+		hasValidMetric = hasValidMetric && removeNumaFields(im[SectionKey_Mem_Linux], result)
 		if hasValidMetric {
 			resArray = append(resArray, result)
 			returnKey = SectionKey_Mem_Linux
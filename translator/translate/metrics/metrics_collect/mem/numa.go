@@ -0,0 +1,114 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package mem
+
+import (
+	"strings"
+
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+	parent "github.com/aws/amazon-cloudwatch-agent/translator/translate/metrics/metrics_collect"
+	"github.com/aws/amazon-cloudwatch-agent/translator/translate/metrics/util"
+)
+
+const (
+	SectionKey_Numa     = "numa"
+	numaKey             = "numa"
+	numaPrefix          = "numa_"
+	fieldPassKey        = "fieldpass"
+	fieldDropKey        = "fielddrop"
+	measurementKey      = "measurement"
+	measurementExclKey  = "measurement_exclude"
+	measurementNameKey  = "name"
+	measurementWildcard = "*"
+)
+
+// Numa is the numa mode of mem, the numa_ metrics of the measurement are reported for each NUMA node by the numa
+// input.
+type Numa struct {
+}
+
+func (n *Numa) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	im := input.(map[string]interface{})
+	m, ok := im[SectionKey_Mem_Linux].(map[string]interface{})
+	if !ok || !numaEnabled(m) {
+		return "", ""
+	}
+	// the common config of mem, with the numa metrics only
+	numaInput := map[string]interface{}{}
+	for k, v := range m {
+		numaInput[k] = v
+	}
+	for _, key := range []string{measurementKey, measurementExclKey} {
+		if list, ok := m[key].([]interface{}); ok {
+			numaInput[key] = numaMeasurements(list)
+		}
+	}
+	if list, ok := numaInput[measurementKey].([]interface{}); !ok || len(list) == 0 {
+		translator.AddErrorMessages(GetCurPath()+numaKey, "numa is true but no numa_ metric is in the measurement")
+		return "", ""
+	}
+
+	result := map[string]interface{}{}
+	if !util.ProcessLinuxCommonConfig(numaInput, SectionKey_Mem_Linux, GetCurPath(), result) {
+		return "", ""
+	}
+	util.ProcessReportDeltasForNuma(numaInput, result)
+	return SectionKey_Numa, []interface{}{result}
+}
+
+func numaEnabled(m map[string]interface{}) bool {
+	enabled, _ := m[numaKey].(bool)
+	return enabled
+}
+
+func isNumaField(name string) bool {
+	return strings.HasPrefix(strings.TrimPrefix(strings.TrimSpace(name), SectionKey_Mem_Linux+"_"), numaPrefix)
+}
+
+// numaMeasurements returns the numa metrics of the measurement list, "*" includes them all.
+func numaMeasurements(list []interface{}) []interface{} {
+	res := []interface{}{}
+	for _, item := range list {
+		name, _ := item.(string)
+		if m, ok := item.(map[string]interface{}); ok {
+			name, _ = m[measurementNameKey].(string)
+		}
+		if name == measurementWildcard || isNumaField(name) {
+			res = append(res, item)
+		}
+	}
+	return res
+}
+
+// removeNumaFields removes the numa metrics from the fields of the mem input, it returns false when no field is
+// left. The numa metrics are only reported in the numa mode.
+func removeNumaFields(input interface{}, result map[string]interface{}) bool {
+	enabled := numaEnabled(input.(map[string]interface{}))
+	for _, key := range []string{fieldPassKey, fieldDropKey} {
+		fields, ok := result[key].([]string)
+		if !ok {
+			continue
+		}
+		kept := []string{}
+		for _, field := range fields {
+			if !isNumaField(field) {
+				kept = append(kept, field)
+			} else if !enabled && key == fieldPassKey {
+				translator.AddErrorMessages(GetCurPath(), "measurement name "+field+" requires numa to be true")
+			}
+		}
+		if len(kept) > 0 {
+			result[key] = kept
+		} else {
+			delete(result, key)
+		}
+	}
+	_, ok := result[fieldPassKey]
+	return ok
+}
+
+func init() {
+	n := new(Numa)
+	parent.RegisterLinuxRule(SectionKey_Numa, n)
+}
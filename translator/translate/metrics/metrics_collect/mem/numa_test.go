@@ -0,0 +1,78 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package mem
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNuma(t *testing.T) {
+	translator.ResetMessages()
+	var input interface{}
+	err := json.Unmarshal([]byte(`{"mem":{"measurement": ["used_percent", "mem_numa_free", "numa_hit", "numa_miss"], "numa": true}}`), &input)
+	assert.NoError(t, err)
+
+	_, actual := new(Mem).ApplyRule(input)
+	assert.Equal(t, []interface{}{map[string]interface{}{
+		"fieldpass": []string{"used_percent"},
+	}}, actual)
+
+	key, actual := new(Numa).ApplyRule(input)
+	assert.Equal(t, "numa", key)
+	assert.Equal(t, []interface{}{map[string]interface{}{
+		"fieldpass": []string{"numa_free", "numa_hit", "numa_miss"},
+		"tags": map[string]interface{}{
+			"report_deltas":            "true",
+			"ignored_fields_for_delta": "numa_free,numa_total,numa_used,numa_used_percent",
+		},
+	}}, actual)
+	assert.Empty(t, translator.ErrorMessages)
+}
+
+func TestNumaOnly(t *testing.T) {
+	var input interface{}
+	err := json.Unmarshal([]byte(`{"mem":{"measurement": ["numa_used_percent"], "measurement_exclude": ["used"], "numa": true, "report_deltas": false, "metrics_collection_interval": 60}}`), &input)
+	assert.NoError(t, err)
+
+	// no mem metric is left
+	key, _ := new(Mem).ApplyRule(input)
+	assert.Equal(t, "", key)
+
+	_, actual := new(Numa).ApplyRule(input)
+	assert.Equal(t, []interface{}{map[string]interface{}{
+		"fieldpass": []string{"numa_used_percent"},
+		"interval":  "60s",
+	}}, actual)
+}
+
+func TestNumaDisabled(t *testing.T) {
+	translator.ResetMessages()
+	var input interface{}
+	err := json.Unmarshal([]byte(`{"mem":{"measurement": ["used_percent", "numa_hit"]}}`), &input)
+	assert.NoError(t, err)
+
+	_, actual := new(Mem).ApplyRule(input)
+	assert.Equal(t, []interface{}{map[string]interface{}{
+		"fieldpass": []string{"used_percent"},
+	}}, actual)
+	assert.Equal(t, 1, len(translator.ErrorMessages))
+
+	key, _ := new(Numa).ApplyRule(input)
+	assert.Equal(t, "", key)
+}
+
+func TestNumaWithoutNumaMetrics(t *testing.T) {
+	translator.ResetMessages()
+	var input interface{}
+	err := json.Unmarshal([]byte(`{"mem":{"measurement": ["used_percent"], "numa": true}}`), &input)
+	assert.NoError(t, err)
+
+	key, _ := new(Numa).ApplyRule(input)
+	assert.Equal(t, "", key)
+	assert.Equal(t, 1, len(translator.ErrorMessages))
+}
@@ -65,3 +65,22 @@ func TestNetWithReportDeltaFalse(t *testing.T) {
 		panic(err)
 	}
 }
+
+func TestNetWithReportRates(t *testing.T) {
+	n := new(Net)
+	var input interface{}
+	err := json.Unmarshal([]byte(`{"net":{"measurement": [
+						"bytes_sent",
+						"bytes_recv",
+						"err_in"],"report_rates":true}}`), &input)
+	if err == nil {
+		_, actual := n.ApplyRule(input)
+		expected := []interface{}{map[string]interface{}{
+			"fieldpass": []string{"bytes_sent", "bytes_recv", "err_in"},
+			"tags":      map[string]interface{}{"report_rates": "true"},
+		}}
+		assert.Equal(t, expected, actual, "Expected to be equal")
+	} else {
+		panic(err)
+	}
+}
@@ -0,0 +1,59 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package netstat
+
+import (
+	"fmt"
+
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+	parent "github.com/aws/amazon-cloudwatch-agent/translator/translate/metrics/metrics_collect"
+	"github.com/aws/amazon-cloudwatch-agent/translator/translate/metrics/util"
+)
+
+const (
+	SectionKey_NetstatPorts = "netstat_ports"
+	portsKey                = "ports"
+	byProcessKey            = "by_process"
+)
+
+// NetStatPorts translates the breakdown of the connection states of the netstat section, by local port and by
+// process, into the netstat_ports input, the totals stay in the netstat input.
+type NetStatPorts struct {
+}
+
+func (n *NetStatPorts) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	m := input.(map[string]interface{})
+	section, ok := m[SectionKey_Netstat].(map[string]interface{})
+	if !ok {
+		return "", ""
+	}
+	result := map[string]interface{}{}
+	// the ports are validated by the schema, json unmarshal stores them as float64
+	if ports, ok := section[portsKey].([]interface{}); ok && len(ports) > 0 {
+		portList := make([]int, len(ports))
+		for i, port := range ports {
+			p, ok := port.(float64)
+			if !ok {
+				translator.AddErrorMessages(GetCurPath()+portsKey, fmt.Sprintf("port %v is not a number", port))
+				return "", ""
+			}
+			portList[i] = int(p)
+		}
+		result[portsKey] = portList
+	}
+	if byProcess, ok := section[byProcessKey].(bool); ok && byProcess {
+		result[byProcessKey] = true
+	}
+	if len(result) == 0 {
+		return "", ""
+	}
+	if !util.ProcessLinuxCommonConfig(section, SectionKey_Netstat, GetCurPath(), result) {
+		return "", ""
+	}
+	return SectionKey_NetstatPorts, []interface{}{result}
+}
+
+func init() {
+	parent.RegisterLinuxRule(SectionKey_NetstatPorts, new(NetStatPorts))
+}
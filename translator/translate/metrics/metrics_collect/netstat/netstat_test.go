@@ -25,3 +25,41 @@ func TestNetStat(t *testing.T) {
 		assert.Equal(t, expected, actual, "Expected to be equal")
 	}
 }
+
+func TestNetStatPorts(t *testing.T) {
+	n := new(NetStatPorts)
+	var input interface{}
+	err := json.Unmarshal([]byte(`{"netstat":{"measurement": ["tcp_established", "tcp_time_wait"],
+						"ports": [443, 8080], "by_process": true}}`), &input)
+	assert.NoError(t, err)
+	key, actual := n.ApplyRule(input)
+	assert.Equal(t, "netstat_ports", key)
+	expected := []interface{}{map[string]interface{}{
+		"fieldpass":  []string{"tcp_established", "tcp_time_wait"},
+		"ports":      []int{443, 8080},
+		"by_process": true,
+	}}
+	assert.Equal(t, expected, actual, "Expected to be equal")
+}
+
+func TestNetStatPortsWithoutBreakdown(t *testing.T) {
+	n := new(NetStatPorts)
+	var input interface{}
+	err := json.Unmarshal([]byte(`{"netstat":{"measurement": ["tcp_established"], "ports": [], "by_process": false}}`), &input)
+	assert.NoError(t, err)
+	key, _ := n.ApplyRule(input)
+	assert.Equal(t, "", key)
+}
+
+func TestNetStatWithPorts(t *testing.T) {
+	n := new(NetStat)
+	var input interface{}
+	err := json.Unmarshal([]byte(`{"netstat":{"measurement": ["tcp_established"], "ports": [443]}}`), &input)
+	assert.NoError(t, err)
+	_, actual := n.ApplyRule(input)
+	// the breakdown options are only in the netstat_ports input
+	expected := []interface{}{map[string]interface{}{
+		"fieldpass": []string{"tcp_established"},
+	}}
+	assert.Equal(t, expected, actual, "Expected to be equal")
+}
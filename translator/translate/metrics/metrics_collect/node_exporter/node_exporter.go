@@ -0,0 +1,71 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package node_exporter
+
+import (
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+	parent "github.com/aws/amazon-cloudwatch-agent/translator/translate/metrics/metrics_collect"
+	"github.com/aws/amazon-cloudwatch-agent/translator/translate/metrics/util"
+)
+
+var ChildRule = map[string]translator.Rule{}
+
+const (
+	SectionKey_NodeExporter = "node_exporter"
+	metricsKey              = "metrics"
+	metricsExcludeKey       = "metrics_exclude"
+	fieldPassKey            = "fieldpass"
+	fieldDropKey            = "fielddrop"
+)
+
+func GetCurPath() string {
+	curPath := parent.GetCurPath() + SectionKey_NodeExporter + "/"
+	return curPath
+}
+
+func RegisterRule(fieldname string, r translator.Rule) {
+	ChildRule[fieldname] = r
+}
+
+type NodeExporter struct {
+}
+
+func (n *NodeExporter) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	m := input.(map[string]interface{})
+	res := []interface{}{}
+	result := map[string]interface{}{}
+	//Check if this plugin exist in the input instance
+	//If not, not process
+	if _, ok := m[SectionKey_NodeExporter]; !ok {
+		returnKey = ""
+		returnVal = ""
+	} else {
+		//Check if there are some config entry with rules applied
+		result = translator.ProcessRuleToApply(m[SectionKey_NodeExporter], ChildRule, result)
+
+		//Process common config, like measurement
+		hasValidMetric := util.ProcessLinuxCommonConfig(m[SectionKey_NodeExporter], SectionKey_NodeExporter, GetCurPath(), result)
+		if hasValidMetric {
+			// the measurements are the names of the host metrics, e.g. mem_used_percent, the plugin reports several
+			// measurements so it filters them itself instead of the fieldpass of the field names
+			result[metricsKey] = result[fieldPassKey]
+			delete(result, fieldPassKey)
+			if val, ok := result[fieldDropKey]; ok {
+				result[metricsExcludeKey] = val
+				delete(result, fieldDropKey)
+			}
+			res = append(res, result)
+			returnKey = SectionKey_NodeExporter
+			returnVal = res
+		} else {
+			returnKey = ""
+		}
+	}
+	return
+}
+
+func init() {
+	n := new(NodeExporter)
+	parent.RegisterLinuxRule(SectionKey_NodeExporter, n)
+}
@@ -0,0 +1,53 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package node_exporter
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNodeExporter(t *testing.T) {
+	n := new(NodeExporter)
+	var input interface{}
+	err := json.Unmarshal([]byte(`{"node_exporter":{"measurement": ["cpu_usage_idle", "mem_used_percent", "disk_used_percent"]}}`), &input)
+	assert.NoError(t, err)
+	_, actual := n.ApplyRule(input)
+	expected := []interface{}{map[string]interface{}{
+		"metrics": []string{"cpu_usage_idle", "mem_used_percent", "disk_used_percent"},
+		"percpu":  false,
+		"url":     "http://localhost:9100/metrics",
+	}}
+	assert.Equal(t, expected, actual, "Expected to be equal")
+}
+
+func TestNodeExporterWildcard(t *testing.T) {
+	n := new(NodeExporter)
+	var input interface{}
+	err := json.Unmarshal([]byte(`{"node_exporter":{"measurement": ["*"], "measurement_exclude": ["cpu_time_guest"],
+		"resources": ["*"], "url": "http://127.0.0.1:9101/metrics"}}`), &input)
+	assert.NoError(t, err)
+	_, actual := n.ApplyRule(input)
+	expected := []interface{}{map[string]interface{}{
+		"metrics":         []string{"*"},
+		"metrics_exclude": []string{"cpu_time_guest"},
+		"percpu":          true,
+		"url":             "http://127.0.0.1:9101/metrics",
+	}}
+	assert.Equal(t, expected, actual, "Expected to be equal")
+}
+
+func TestNodeExporterInvalidMeasurement(t *testing.T) {
+	translator.ResetMessages()
+	n := new(NodeExporter)
+	var input interface{}
+	err := json.Unmarshal([]byte(`{"node_exporter":{"measurement": ["node_load1"]}}`), &input)
+	assert.NoError(t, err)
+	key, _ := n.ApplyRule(input)
+	assert.Equal(t, "", key)
+	assert.Equal(t, 1, len(translator.ErrorMessages))
+}
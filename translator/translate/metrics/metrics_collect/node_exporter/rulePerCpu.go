@@ -0,0 +1,29 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package node_exporter
+
+import (
+	"github.com/aws/amazon-cloudwatch-agent/translator/translate/metrics/util"
+)
+
+type PerCpu struct {
+}
+
+const per_cpu_key = "percpu"
+
+// PerCpu reports the cpu metrics of each core with "resources": ["*"], like the cpu section does.
+func (t *PerCpu) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	returnKey = per_cpu_key
+	if util.ContainAsterisk(input, util.Resource_Key) {
+		returnVal = true
+	} else {
+		returnVal = false
+	}
+	return
+}
+
+func init() {
+	p := new(PerCpu)
+	RegisterRule(per_cpu_key, p)
+}
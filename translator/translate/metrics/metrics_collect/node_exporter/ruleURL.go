@@ -0,0 +1,23 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package node_exporter
+
+import (
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+)
+
+type URL struct {
+}
+
+const SectionKey_URL = "url"
+
+func (obj *URL) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	returnKey, returnVal = translator.DefaultCase(SectionKey_URL, "http://localhost:9100/metrics", input)
+	return
+}
+
+func init() {
+	obj := new(URL)
+	RegisterRule(SectionKey_URL, obj)
+}
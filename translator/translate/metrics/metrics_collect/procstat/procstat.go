@@ -51,6 +51,9 @@ func (p *Procstat) ApplyRule(input interface{}) (returnKey string, returnVal int
 				result[key] = val
 			}
 		}
+		processDimensions(processConfig, result)
+		processContainer(processConfig, result)
+		processRecovery(processConfig, result)
 		resArray = append(resArray, result)
 	}
 
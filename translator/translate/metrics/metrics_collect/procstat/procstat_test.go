@@ -7,6 +7,8 @@ import (
 	"encoding/json"
 	"testing"
 
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+	"github.com/aws/amazon-cloudwatch-agent/translator/config"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -79,6 +81,175 @@ func TestPatternConfig(t *testing.T) {
 	checkResult(t, input, expectedVal)
 }
 
+func TestSystemdUnitConfig(t *testing.T) {
+	input := []byte(`{"procstat": [
+	{
+	    "measurement": [
+		{"name": "cpu_usage", "rename": "cwagent_cpu_usage", "unit": "Percent"},
+		{"name": "memory_rss", "rename": "cwagent_mem_usage", "unit": "Bytes"}
+	    ],
+	    "systemd_unit": "nginx.service"
+	}
+      ]}`)
+	expectedVal := []interface{}{map[string]interface{}{
+		"systemd_unit": "nginx.service",
+		"pid_finder":   "native",
+		"fieldpass":    []string{"cpu_usage", "memory_rss"},
+		"tagexclude":   []string{"user", "result"},
+	}}
+	checkResult(t, input, expectedVal)
+}
+
+func TestCGroupConfig(t *testing.T) {
+	input := []byte(`{"procstat": [
+	{
+	    "measurement": [
+		{"name": "cpu_usage", "rename": "cwagent_cpu_usage", "unit": "Percent"},
+		{"name": "memory_rss", "rename": "cwagent_mem_usage", "unit": "Bytes"}
+	    ],
+	    "cgroup": "system.slice/app.slice"
+	}
+      ]}`)
+	expectedVal := []interface{}{map[string]interface{}{
+		"cgroup":     "system.slice/app.slice",
+		"pid_finder": "native",
+		"fieldpass":  []string{"cpu_usage", "memory_rss"},
+		"tagexclude": []string{"user", "result"},
+	}}
+	checkResult(t, input, expectedVal)
+}
+
+func TestServiceNameConfig(t *testing.T) {
+	translator.SetTargetPlatform(config.OS_TYPE_WINDOWS)
+	defer translator.SetTargetPlatform(config.OS_TYPE_LINUX)
+	input := []byte(`{"procstat": [
+	{
+	    "measurement": ["cpu_usage", "memory_rss"],
+	    "service_name": "W3SVC"
+	}
+      ]}`)
+	expectedVal := []interface{}{map[string]interface{}{
+		"win_service": "W3SVC",
+		"pid_finder":  "native",
+		"fieldpass":   []string{"cpu_usage", "memory_rss"},
+		"tagexclude":  []string{"user", "result"},
+	}}
+	checkResult(t, input, expectedVal)
+}
+
+func TestServiceNameConfigNotWindows(t *testing.T) {
+	translator.ResetMessages()
+	input := []byte(`{"procstat": [
+	{
+	    "measurement": ["cpu_usage"],
+	    "service_name": "nginx"
+	}
+      ]}`)
+	expectedVal := []interface{}{map[string]interface{}{
+		"pid_finder": "native",
+		"fieldpass":  []string{"cpu_usage"},
+		"tagexclude": []string{"user", "result"},
+	}}
+	checkResult(t, input, expectedVal)
+	assert.Equal(t, 1, len(translator.ErrorMessages))
+}
+
+func TestEnvironmentPatternConfig(t *testing.T) {
+	input := []byte(`{"procstat": [
+	{
+	    "measurement": ["cpu_usage"],
+	    "exe": "python",
+	    "environment_pattern": "^APP_NAME=payments$",
+	    "dimensions": ["process_name", "environment_pattern"]
+	}
+      ]}`)
+	expectedVal := []interface{}{map[string]interface{}{
+		"exe":                 "python",
+		"environment_pattern": "^APP_NAME=payments$",
+		"pid_finder":          "native",
+		"fieldpass":           []string{"cpu_usage"},
+		"tagexclude":          []string{"user", "exe", "result"},
+	}}
+	checkResult(t, input, expectedVal)
+}
+
+func TestEnvironmentPatternConfigNotLinux(t *testing.T) {
+	translator.SetTargetPlatform(config.OS_TYPE_WINDOWS)
+	defer translator.SetTargetPlatform(config.OS_TYPE_LINUX)
+	translator.ResetMessages()
+	input := []byte(`{"procstat": [
+	{
+	    "measurement": ["cpu_usage"],
+	    "exe": "python",
+	    "environment_pattern": "APP_NAME=payments"
+	}
+      ]}`)
+	expectedVal := []interface{}{map[string]interface{}{
+		"exe":        "python",
+		"pid_finder": "native",
+		"fieldpass":  []string{"cpu_usage"},
+		"tagexclude": []string{"user", "result"},
+	}}
+	checkResult(t, input, expectedVal)
+	assert.Equal(t, 1, len(translator.ErrorMessages))
+}
+
+func TestContainerConfig(t *testing.T) {
+	input := []byte(`{"procstat": [
+	{
+	    "measurement": ["cpu_usage"],
+	    "exe": "python",
+	    "container_name_pattern": "^payments-",
+	    "container_labels": {"team": "payments"},
+	    "container_endpoint": "unix:///run/docker.sock",
+	    "dimensions": ["process_name", "container_name", "container_id"]
+	}
+      ]}`)
+	expectedVal := []interface{}{map[string]interface{}{
+		"exe":                    "python",
+		"container_name_pattern": "^payments-",
+		"container_labels":       map[string]interface{}{"team": "payments"},
+		"container_endpoint":     "unix:///run/docker.sock",
+		"container_id_tag":       true,
+		"pid_finder":             "native",
+		"fieldpass":              []string{"cpu_usage"},
+		"tagexclude":             []string{"user", "exe", "container_name_pattern", "result"},
+	}}
+	checkResult(t, input, expectedVal)
+}
+
+func TestContainerConfigInvalid(t *testing.T) {
+	translator.ResetMessages()
+	input := []byte(`{"procstat": [
+	{
+	    "measurement": ["cpu_usage"],
+	    "exe": "python",
+	    "dimensions": ["process_name", "container_id"]
+	}
+      ]}`)
+	expectedVal := []interface{}{map[string]interface{}{
+		"exe":        "python",
+		"pid_finder": "native",
+		"fieldpass":  []string{"cpu_usage"},
+		"tagexclude": []string{"user", "result"},
+	}}
+	checkResult(t, input, expectedVal)
+	assert.Equal(t, 1, len(translator.ErrorMessages))
+
+	translator.SetTargetPlatform(config.OS_TYPE_DARWIN)
+	defer translator.SetTargetPlatform(config.OS_TYPE_LINUX)
+	translator.ResetMessages()
+	input = []byte(`{"procstat": [
+	{
+	    "measurement": ["cpu_usage"],
+	    "exe": "python",
+	    "container_name_pattern": "^payments-"
+	}
+      ]}`)
+	checkResult(t, input, expectedVal)
+	assert.Equal(t, 1, len(translator.ErrorMessages))
+}
+
 func TestMultiLookupConfig(t *testing.T) {
 	input := []byte(`{"procstat": [
 	{
@@ -184,3 +355,115 @@ func TestIntervalErrorConfig(t *testing.T) {
 	}}
 	checkResult(t, input, expectedVal)
 }
+
+func TestRecoveryConfig(t *testing.T) {
+	input := []byte(`{"procstat": [
+	{
+	    "measurement": ["cpu_usage"],
+	    "exe": "nginx",
+	    "recovery": {"restart_service": "nginx", "min_interval": 300, "max_attempts": 5}
+	},
+	{
+	    "measurement": ["cpu_usage", "pid_count"],
+	    "pattern": "myapp",
+	    "recovery": {"command": "/opt/myapp/restart.sh"}
+	}
+      ]}`)
+	expectedVal := []interface{}{map[string]interface{}{
+		"exe":        "nginx",
+		"pid_finder": "native",
+		"fieldpass":  []string{"cpu_usage", "pid_count"},
+		"tagexclude": []string{"user", "result"},
+		"tags": map[string]interface{}{
+			"recovery_restart_service": "nginx",
+			"recovery_min_interval":    "300s",
+			"recovery_max_attempts":    "5",
+			"recovery_drop_pid_count":  "true",
+		},
+	}, map[string]interface{}{
+		"pattern":    "myapp",
+		"pid_finder": "native",
+		"fieldpass":  []string{"cpu_usage", "pid_count"},
+		"tagexclude": []string{"user", "result"},
+		"tags": map[string]interface{}{
+			"recovery_command": "/opt/myapp/restart.sh",
+		},
+	}}
+	checkResult(t, input, expectedVal)
+}
+
+func TestRecoveryConfigWithoutAction(t *testing.T) {
+	translator.ResetMessages()
+	input := []byte(`{"procstat": [
+	{
+	    "measurement": ["cpu_usage"],
+	    "exe": "nginx",
+	    "recovery": {"min_interval": 300}
+	}
+      ]}`)
+	expectedVal := []interface{}{map[string]interface{}{
+		"exe":        "nginx",
+		"pid_finder": "native",
+		"fieldpass":  []string{"cpu_usage"},
+		"tagexclude": []string{"user", "result"},
+	}}
+	checkResult(t, input, expectedVal)
+	assert.Equal(t, 1, len(translator.ErrorMessages))
+}
+
+func TestDimensionsConfig(t *testing.T) {
+	input := []byte(`{"procstat": [
+	{
+	    "measurement": ["cpu_usage", "memory_rss"],
+	    "pattern": "java -jar /opt/app/.*\\.jar",
+	    "dimensions": ["user", "pattern_hash", "cmdline_hash"]
+	}
+      ]}`)
+	expectedVal := []interface{}{map[string]interface{}{
+		"pattern":          "java -jar /opt/app/.*\\.jar",
+		"pid_finder":       "native",
+		"fieldpass":        []string{"cpu_usage", "memory_rss"},
+		"pattern_hash_tag": true,
+		"cmdline_hash_tag": true,
+		"tagexclude":       []string{"process_name", "pattern", "result"},
+	}}
+	checkResult(t, input, expectedVal)
+}
+
+func TestDimensionsConfigWithPid(t *testing.T) {
+	input := []byte(`{"procstat": [
+	{
+	    "measurement": ["cpu_usage"],
+	    "exe": "nginx",
+	    "dimensions": ["exe", "process_name", "pid", "exe_path"]
+	}
+      ]}`)
+	expectedVal := []interface{}{map[string]interface{}{
+		"exe":          "nginx",
+		"pid_finder":   "native",
+		"fieldpass":    []string{"cpu_usage"},
+		"pid_tag":      true,
+		"exe_path_tag": true,
+		"tagexclude":   []string{"user", "result"},
+	}}
+	checkResult(t, input, expectedVal)
+}
+
+func TestDimensionsConfigInvalid(t *testing.T) {
+	translator.ResetMessages()
+	input := []byte(`{"procstat": [
+	{
+	    "measurement": ["cpu_usage"],
+	    "exe": "nginx",
+	    "dimensions": ["pattern_hash", "cmdline"]
+	}
+      ]}`)
+	expectedVal := []interface{}{map[string]interface{}{
+		"exe":        "nginx",
+		"pid_finder": "native",
+		"fieldpass":  []string{"cpu_usage"},
+		"tagexclude": []string{"user", "result"},
+	}}
+	checkResult(t, input, expectedVal)
+	assert.Equal(t, 1, len(translator.ErrorMessages))
+}
@@ -0,0 +1,25 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package procstat
+
+type CGroup struct{}
+
+const keyCGroup = "cgroup"
+
+func (t *CGroup) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	m := input.(map[string]interface{})
+	if _, ok := m[keyCGroup]; !ok {
+		returnKey = ""
+		returnVal = ""
+	} else {
+		returnKey = keyCGroup
+		returnVal = m[keyCGroup]
+	}
+	return
+}
+
+func init() {
+	e := new(CGroup)
+	RegisterRule(keyCGroup, e)
+}
@@ -0,0 +1,45 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package procstat
+
+import (
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+	"github.com/aws/amazon-cloudwatch-agent/translator/config"
+)
+
+const (
+	keyContainerNamePattern = "container_name_pattern"
+	keyContainerLabels      = "container_labels"
+	keyContainerEndpoint    = "container_endpoint"
+
+	dimensionContainerName = "container_name"
+)
+
+// processContainer narrows the processes of the other selector to the ones of the containers with a matching name
+// and labels, the containers are listed with the Docker Engine API of the container_endpoint.
+func processContainer(input interface{}, result map[string]interface{}) {
+	m := input.(map[string]interface{})
+	if !hasContainerOptions(m) {
+		return
+	}
+	if translator.GetTargetPlatform() != config.OS_TYPE_LINUX {
+		translator.AddErrorMessages(GetCurPath()+keyContainerNamePattern, "the container options are only supported on Linux.")
+		return
+	}
+	if val, ok := m[keyContainerNamePattern].(string); ok {
+		result[keyContainerNamePattern] = val
+	}
+	if labels, ok := m[keyContainerLabels].(map[string]interface{}); ok && len(labels) > 0 {
+		result[keyContainerLabels] = labels
+	}
+	if val, ok := m[keyContainerEndpoint].(string); ok {
+		result[keyContainerEndpoint] = val
+	}
+}
+
+func hasContainerOptions(m map[string]interface{}) bool {
+	_, hasName := m[keyContainerNamePattern]
+	_, hasLabels := m[keyContainerLabels]
+	return hasName || hasLabels
+}
@@ -0,0 +1,102 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package procstat
+
+import (
+	"fmt"
+
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+)
+
+const (
+	keyDimensions = "dimensions"
+
+	dimensionProcessName = "process_name"
+	dimensionUser        = "user"
+	dimensionPid         = "pid"
+	dimensionResult      = "result"
+)
+
+// optionalDimensions are the dimensions only tagged when selected, with the option of the input tagging them.
+var optionalDimensions = map[string]string{
+	dimensionPid:   "pid_tag",
+	"exe_path":     "exe_path_tag",
+	"cmdline_hash": "cmdline_hash_tag",
+	"pattern_hash": "pattern_hash_tag",
+	"container_id": "container_id_tag",
+}
+
+// selectorDimensions are the tags of the options selecting the processes, each option tags the metrics with its own key.
+var selectorDimensions = []struct{ option, tag string }{
+	{"pid_file", "pidfile"},
+	{"exe", "exe"},
+	{"pattern", "pattern"},
+	{"systemd_unit", "systemd_unit"},
+	{"cgroup", "cgroup"},
+	{"service_name", "win_service"},
+	{"environment_pattern", "environment_pattern"},
+	{"container_name_pattern", "container_name_pattern"},
+}
+
+// processDimensions replaces the tags excluded by default with the ones not in dimensions, so the metrics of the
+// entry only have the selected dimensions, e.g. the user and a hash of the command line instead of the pid of
+// each instance of a process.
+func processDimensions(input interface{}, result map[string]interface{}) {
+	m := input.(map[string]interface{})
+	dimensions, ok := m[keyDimensions].([]interface{})
+	if !ok {
+		return
+	}
+
+	// the tags of each process, the containers of the processes are tagged with the container options
+	processTags := []string{dimensionProcessName, dimensionUser}
+	if hasContainerOptions(m) {
+		processTags = append(processTags, dimensionContainerName)
+	}
+	selected := map[string]bool{}
+	for _, d := range dimensions {
+		name, _ := d.(string)
+		_, optional := optionalDimensions[name]
+		if !optional && !containsString(processTags, name) && !isSelectorDimension(name) {
+			translator.AddErrorMessages(GetCurPath()+keyDimensions, fmt.Sprintf("%v is not a procstat dimension.", d))
+			return
+		}
+		selected[name] = true
+	}
+	if _, ok := m["pattern"]; selected["pattern_hash"] && !ok {
+		translator.AddErrorMessages(GetCurPath()+keyDimensions, "pattern_hash requires pattern.")
+		return
+	}
+	if selected["container_id"] && !hasContainerOptions(m) {
+		translator.AddErrorMessages(GetCurPath()+keyDimensions, "container_id requires container_name_pattern or container_labels.")
+		return
+	}
+
+	for name := range selected {
+		if option, ok := optionalDimensions[name]; ok {
+			result[option] = true
+		}
+	}
+	excluded := []string{}
+	for _, tag := range processTags {
+		if !selected[tag] {
+			excluded = append(excluded, tag)
+		}
+	}
+	for _, selector := range selectorDimensions {
+		if _, ok := m[selector.option]; ok && !selected[selector.tag] {
+			excluded = append(excluded, selector.tag)
+		}
+	}
+	result[tagExcludeKey] = append(excluded, dimensionResult)
+}
+
+func isSelectorDimension(name string) bool {
+	for _, selector := range selectorDimensions {
+		if selector.tag == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,36 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package procstat
+
+import (
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+	"github.com/aws/amazon-cloudwatch-agent/translator/config"
+)
+
+type EnvironmentPattern struct{}
+
+// keyEnvironmentPattern narrows the processes of the other selector to the ones with a matching environment
+// variable, the environment is read from procfs.
+const keyEnvironmentPattern = "environment_pattern"
+
+func (e *EnvironmentPattern) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	m := input.(map[string]interface{})
+	if _, ok := m[keyEnvironmentPattern]; !ok {
+		returnKey = ""
+		returnVal = ""
+	} else if translator.GetTargetPlatform() != config.OS_TYPE_LINUX {
+		translator.AddErrorMessages(GetCurPath()+keyEnvironmentPattern, "environment_pattern is only supported on Linux.")
+		returnKey = ""
+		returnVal = ""
+	} else {
+		returnKey = keyEnvironmentPattern
+		returnVal = m[keyEnvironmentPattern]
+	}
+	return
+}
+
+func init() {
+	e := new(EnvironmentPattern)
+	RegisterRule(keyEnvironmentPattern, e)
+}
@@ -0,0 +1,72 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package procstat
+
+import (
+	"fmt"
+
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+	"github.com/aws/amazon-cloudwatch-agent/translator/translate/metrics/util"
+)
+
+const (
+	keyRecovery       = "recovery"
+	keyRestartService = "restart_service"
+	keyCommand        = "command"
+	keyMinInterval    = "min_interval"
+	keyMaxAttempts    = "max_attempts"
+	keyFieldPass      = "fieldpass"
+	fieldPidCount     = "pid_count"
+)
+
+// processRecovery adds the recovery tags to the procstat input so the recovery processor runs the recovery action
+// when no process matches the target. The pid_count field is collected for the check even if it is not in the
+// measurement, in which case it is dropped by the processor after the check.
+func processRecovery(input interface{}, result map[string]interface{}) {
+	m := input.(map[string]interface{})
+	recovery, ok := m[keyRecovery].(map[string]interface{})
+	if !ok {
+		return
+	}
+
+	tags := map[string]interface{}{}
+	if val, ok := recovery[keyRestartService].(string); ok && val != "" {
+		tags[util.Recovery_Restart_Service_Key] = val
+	}
+	if val, ok := recovery[keyCommand].(string); ok && val != "" {
+		tags[util.Recovery_Command_Key] = val
+	}
+	if len(tags) == 0 {
+		translator.AddErrorMessages(GetCurPath()+keyRecovery, "recovery requires restart_service or command.")
+		return
+	}
+	if val, ok := recovery[keyMinInterval].(float64); ok {
+		tags[util.Recovery_Min_Interval_Key] = fmt.Sprintf("%ds", int(val))
+	}
+	if val, ok := recovery[keyMaxAttempts].(float64); ok {
+		tags[util.Recovery_Max_Attempts_Key] = fmt.Sprintf("%d", int(val))
+	}
+
+	if fieldPass, ok := result[keyFieldPass].([]string); ok && !containsString(fieldPass, fieldPidCount) {
+		result[keyFieldPass] = append(fieldPass, fieldPidCount)
+		tags[util.Recovery_Drop_Pid_Count_Key] = "true"
+	}
+
+	if result[util.Tags_Key] == nil {
+		result[util.Tags_Key] = map[string]interface{}{}
+	}
+	tagsMap := result[util.Tags_Key].(map[string]interface{})
+	for key, val := range tags {
+		tagsMap[key] = val
+	}
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,39 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package procstat
+
+import (
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+	"github.com/aws/amazon-cloudwatch-agent/translator/config"
+)
+
+type ServiceName struct{}
+
+const (
+	keyServiceName = "service_name"
+	// the option of the input matching the process of a Windows service, its pid is queried from the service
+	// control manager
+	keyWinService = "win_service"
+)
+
+func (t *ServiceName) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	m := input.(map[string]interface{})
+	if _, ok := m[keyServiceName]; !ok {
+		returnKey = ""
+		returnVal = ""
+	} else if translator.GetTargetPlatform() != config.OS_TYPE_WINDOWS {
+		translator.AddErrorMessages(GetCurPath()+keyServiceName, "service_name is only supported on Windows, use systemd_unit instead.")
+		returnKey = ""
+		returnVal = ""
+	} else {
+		returnKey = keyWinService
+		returnVal = m[keyServiceName]
+	}
+	return
+}
+
+func init() {
+	e := new(ServiceName)
+	RegisterRule(keyServiceName, e)
+}
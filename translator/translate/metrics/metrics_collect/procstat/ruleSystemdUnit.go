@@ -0,0 +1,25 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package procstat
+
+type SystemdUnit struct{}
+
+const keySystemdUnit = "systemd_unit"
+
+func (t *SystemdUnit) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	m := input.(map[string]interface{})
+	if _, ok := m[keySystemdUnit]; !ok {
+		returnKey = ""
+		returnVal = ""
+	} else {
+		returnKey = keySystemdUnit
+		returnVal = m[keySystemdUnit]
+	}
+	return
+}
+
+func init() {
+	e := new(SystemdUnit)
+	RegisterRule(keySystemdUnit, e)
+}
@@ -0,0 +1,39 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package rabbitmq
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRabbitMQ(t *testing.T) {
+	r := new(RabbitMQ)
+	var input interface{}
+	err := json.Unmarshal([]byte(`{"rabbitmq":{"measurement": ["queue_messages_ready", "rabbitmq_node_mem_alarm"], "queue_name_exclude": ["amq.gen-*"]}}`), &input)
+	assert.NoError(t, err)
+	_, actual := r.ApplyRule(input)
+	expected := []interface{}{map[string]interface{}{
+		"fieldpass":          []string{"queue_messages_ready", "node_mem_alarm"},
+		"queue_name_exclude": []interface{}{"amq.gen-*"},
+		"url":                "http://localhost:15672",
+	}}
+	assert.Equal(t, expected, actual, "Expected to be equal")
+}
+
+func TestRabbitMQCredentials(t *testing.T) {
+	r := new(RabbitMQ)
+	var input interface{}
+	err := json.Unmarshal([]byte(`{"rabbitmq":{"measurement": ["queue_consumers"], "url": "https://mq.example.com:15671", "username": "cwagent", "password": "secret", "vhosts": ["/", "billing"], "queue_name_include": []}}`), &input)
+	assert.NoError(t, err)
+	_, actual := r.ApplyRule(input)
+	result := actual.([]interface{})[0].(map[string]interface{})
+	assert.Equal(t, "https://mq.example.com:15671", result["url"])
+	assert.Equal(t, "cwagent", result["username"])
+	assert.Equal(t, "secret", result["password"])
+	assert.Equal(t, []interface{}{"/", "billing"}, result["vhosts"])
+	assert.NotContains(t, result, "queue_name_include")
+}
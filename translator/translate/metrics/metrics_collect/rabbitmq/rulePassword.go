@@ -0,0 +1,26 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package rabbitmq
+
+import (
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+)
+
+type Password struct {
+}
+
+const SectionKey_Password = "password"
+
+func (obj *Password) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	returnKey, returnVal = translator.DefaultCase(SectionKey_Password, "", input)
+	if returnVal == "" {
+		return "", nil
+	}
+	return
+}
+
+func init() {
+	obj := new(Password)
+	RegisterRule(SectionKey_Password, obj)
+}
@@ -0,0 +1,26 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package rabbitmq
+
+import (
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+)
+
+type QueueNameExclude struct {
+}
+
+const SectionKey_QueueNameExclude = "queue_name_exclude"
+
+func (obj *QueueNameExclude) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	returnKey, returnVal = translator.DefaultCase(SectionKey_QueueNameExclude, []interface{}{}, input)
+	if list, ok := returnVal.([]interface{}); ok && len(list) == 0 {
+		return "", nil
+	}
+	return
+}
+
+func init() {
+	obj := new(QueueNameExclude)
+	RegisterRule(SectionKey_QueueNameExclude, obj)
+}
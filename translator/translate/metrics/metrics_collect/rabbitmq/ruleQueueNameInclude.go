@@ -0,0 +1,26 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package rabbitmq
+
+import (
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+)
+
+type QueueNameInclude struct {
+}
+
+const SectionKey_QueueNameInclude = "queue_name_include"
+
+func (obj *QueueNameInclude) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	returnKey, returnVal = translator.DefaultCase(SectionKey_QueueNameInclude, []interface{}{}, input)
+	if list, ok := returnVal.([]interface{}); ok && len(list) == 0 {
+		return "", nil
+	}
+	return
+}
+
+func init() {
+	obj := new(QueueNameInclude)
+	RegisterRule(SectionKey_QueueNameInclude, obj)
+}
@@ -0,0 +1,24 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package sensors
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSensors(t *testing.T) {
+	s := new(Sensors)
+	var input interface{}
+	err := json.Unmarshal([]byte(`{"sensors":{"measurement": ["temp_input", "sensors_fan_input"], "metrics_collection_interval": 60}}`), &input)
+	assert.NoError(t, err)
+	_, actual := s.ApplyRule(input)
+	expected := []interface{}{map[string]interface{}{
+		"fieldpass": []string{"temp_input", "fan_input"},
+		"interval":  "60s",
+	}}
+	assert.Equal(t, expected, actual, "Expected to be equal")
+}
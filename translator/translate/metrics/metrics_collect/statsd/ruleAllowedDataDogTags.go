@@ -0,0 +1,31 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package statsd
+
+import (
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+)
+
+type AllowedDataDogTags struct {
+}
+
+const SectionKey_AllowedDataDogTags = "allowed_data_dog_tags"
+
+// ApplyRule keeps only the dogstatsd tags of the keys listed as dimensions.
+func (obj *AllowedDataDogTags) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	returnKey, returnVal = translator.DefaultCase(SectionKey_AllowedDataDogTags, "", input)
+	if returnVal == "" {
+		return "", nil
+	}
+	keys := []string{}
+	for _, key := range returnVal.([]interface{}) {
+		keys = append(keys, key.(string))
+	}
+	return returnKey, keys
+}
+
+func init() {
+	obj := new(AllowedDataDogTags)
+	RegisterRule(SectionKey_AllowedDataDogTags, obj)
+}
@@ -0,0 +1,34 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package statsd
+
+import (
+	"strings"
+
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+	"github.com/aws/amazon-cloudwatch-agent/translator/translate/util"
+)
+
+type EventsLogGroupName struct {
+}
+
+const SectionKey_EventsLogGroupName = "events_log_group_name"
+
+// ApplyRule translates the log group the dogstatsd events and service checks are published to, they are dropped without one.
+func (obj *EventsLogGroupName) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	returnKey, returnVal = translator.DefaultCase(SectionKey_EventsLogGroupName, "", input)
+	if returnVal == "" {
+		return "", nil
+	}
+	name := returnVal.(string)
+	if strings.Contains(name, "{") {
+		name = util.ResolvePlaceholder(name, util.GetMetadataInfo(util.Ec2MetadataInfoProvider))
+	}
+	return returnKey, name
+}
+
+func init() {
+	obj := new(EventsLogGroupName)
+	RegisterRule(SectionKey_EventsLogGroupName, obj)
+}
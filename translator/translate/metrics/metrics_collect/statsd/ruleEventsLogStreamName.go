@@ -0,0 +1,34 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package statsd
+
+import (
+	"strings"
+
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+	"github.com/aws/amazon-cloudwatch-agent/translator/translate/util"
+)
+
+type EventsLogStreamName struct {
+}
+
+const SectionKey_EventsLogStreamName = "events_log_stream_name"
+
+// ApplyRule translates the log stream of the dogstatsd events and service checks, the log_stream_name of the logs section by default.
+func (obj *EventsLogStreamName) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	returnKey, returnVal = translator.DefaultCase(SectionKey_EventsLogStreamName, "", input)
+	if returnVal == "" {
+		return "", nil
+	}
+	name := returnVal.(string)
+	if strings.Contains(name, "{") {
+		name = util.ResolvePlaceholder(name, util.GetMetadataInfo(util.Ec2MetadataInfoProvider))
+	}
+	return returnKey, name
+}
+
+func init() {
+	obj := new(EventsLogStreamName)
+	RegisterRule(SectionKey_EventsLogStreamName, obj)
+}
@@ -0,0 +1,27 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package statsd
+
+import (
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+)
+
+type MaxDataDogTagValues struct {
+}
+
+const SectionKey_MaxDataDogTagValues = "max_data_dog_tag_values"
+
+func (obj *MaxDataDogTagValues) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	returnKey, returnVal = translator.DefaultCase(SectionKey_MaxDataDogTagValues, "", input)
+	if returnVal != "" {
+		// By default json unmarshal will store number as float64
+		return returnKey, int(returnVal.(float64))
+	}
+	return "", nil
+}
+
+func init() {
+	obj := new(MaxDataDogTagValues)
+	RegisterRule(SectionKey_MaxDataDogTagValues, obj)
+}
@@ -0,0 +1,21 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package statsd
+
+import (
+	"github.com/aws/amazon-cloudwatch-agent/translator/translate/metrics/util"
+)
+
+// MaxSeriesInterval counts the series of max_series by metrics_aggregation_interval.
+type MaxSeriesInterval struct {
+}
+
+func (obj *MaxSeriesInterval) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	return util.ProcessMaxSeriesInterval(input, "60s")
+}
+
+func init() {
+	obj := new(MaxSeriesInterval)
+	RegisterRule(util.Max_Series_Interval_Mapped_Key, obj)
+}
@@ -0,0 +1,30 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package statsd
+
+import (
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+)
+
+type PercentileMode struct {
+}
+
+const (
+	SectionKey_PercentileMode = "percentile_mode"
+
+	percentileModeMetrics = "metrics"
+)
+
+func (obj *PercentileMode) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	returnKey, returnVal = translator.DefaultCase(SectionKey_PercentileMode, "", input)
+	if returnVal == "" {
+		return "", nil
+	}
+	return
+}
+
+func init() {
+	obj := new(PercentileMode)
+	RegisterRule(SectionKey_PercentileMode, obj)
+}
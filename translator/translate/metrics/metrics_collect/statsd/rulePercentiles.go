@@ -0,0 +1,36 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package statsd
+
+import (
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+)
+
+type Percentiles struct {
+}
+
+const SectionKey_Percentiles = "percentiles"
+
+// ApplyRule translates the percentiles of the timings, only published in the metrics mode since CloudWatch computes
+// the percentiles of the distributions.
+func (obj *Percentiles) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	returnKey, returnVal = translator.DefaultCase(SectionKey_Percentiles, "", input)
+	if returnVal == "" {
+		return "", nil
+	}
+	if input.(map[string]interface{})[SectionKey_PercentileMode] != percentileModeMetrics {
+		translator.AddErrorMessages(GetCurPath()+SectionKey_Percentiles, "percentiles requires the percentile_mode metrics.")
+		return "", nil
+	}
+	percentiles := []float64{}
+	for _, p := range returnVal.([]interface{}) {
+		percentiles = append(percentiles, p.(float64))
+	}
+	return returnKey, percentiles
+}
+
+func init() {
+	obj := new(Percentiles)
+	RegisterRule(SectionKey_Percentiles, obj)
+}
@@ -0,0 +1,27 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package statsd
+
+import (
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+)
+
+type ReadBatchSize struct {
+}
+
+const SectionKey_ReadBatchSize = "read_batch_size"
+
+func (obj *ReadBatchSize) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	returnKey, returnVal = translator.DefaultCase(SectionKey_ReadBatchSize, "", input)
+	if returnVal != "" {
+		// By default json unmarshal will store number as float64
+		return returnKey, int(returnVal.(float64))
+	}
+	return "", nil
+}
+
+func init() {
+	obj := new(ReadBatchSize)
+	RegisterRule(SectionKey_ReadBatchSize, obj)
+}
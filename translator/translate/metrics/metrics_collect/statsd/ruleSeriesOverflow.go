@@ -0,0 +1,26 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package statsd
+
+import (
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+)
+
+type SeriesOverflow struct {
+}
+
+const SectionKey_SeriesOverflow = "series_overflow"
+
+func (obj *SeriesOverflow) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	returnKey, returnVal = translator.DefaultCase(SectionKey_SeriesOverflow, "", input)
+	if returnVal == "" {
+		return "", nil
+	}
+	return
+}
+
+func init() {
+	obj := new(SeriesOverflow)
+	RegisterRule(SectionKey_SeriesOverflow, obj)
+}
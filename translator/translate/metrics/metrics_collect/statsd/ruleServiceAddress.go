@@ -4,6 +4,8 @@
 package statsd
 
 import (
+	"strings"
+
 	"github.com/aws/amazon-cloudwatch-agent/translator"
 )
 
@@ -12,9 +14,23 @@ type ServiceAddress struct {
 
 const SectionKey_ServiceAddress = "service_address"
 
+// serviceAddressSchemes are the schemes of the addresses the statsd input listens on, the addresses without scheme
+// are udp ones.
+var serviceAddressSchemes = []string{"udp://", "tcp://", "unix://"}
+
 func (obj *ServiceAddress) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
 	returnKey, returnVal = translator.DefaultCase(SectionKey_ServiceAddress, ":8125", input)
-	return
+	address, _ := returnVal.(string)
+	if !strings.Contains(address, "://") {
+		return
+	}
+	for _, scheme := range serviceAddressSchemes {
+		if strings.HasPrefix(address, scheme) && len(address) > len(scheme) {
+			return
+		}
+	}
+	translator.AddErrorMessages(GetCurPath()+SectionKey_ServiceAddress, "service_address must be an address, or udp://, tcp:// or unix:// followed by an address or the path of a socket.")
+	return "", nil
 }
 
 func init() {
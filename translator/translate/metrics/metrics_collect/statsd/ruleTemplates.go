@@ -0,0 +1,39 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package statsd
+
+import (
+	"strings"
+
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+)
+
+type Templates struct {
+}
+
+const SectionKey_Templates = "templates"
+
+// ApplyRule translates the graphite templates mapping the parts of the dotted buckets to the metric name and
+// dimensions.
+func (obj *Templates) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	returnKey, returnVal = translator.DefaultCase(SectionKey_Templates, "", input)
+	if returnVal == "" {
+		return "", nil
+	}
+	templates := []string{}
+	for _, t := range returnVal.([]interface{}) {
+		// the statsd listener doesn't start with a template naming no part of the bucket as the measurement
+		if !strings.Contains(t.(string), "measurement") {
+			translator.AddErrorMessages(GetCurPath()+SectionKey_Templates, "templates must name the parts of the buckets of the metric name with measurement.")
+			return "", nil
+		}
+		templates = append(templates, t.(string))
+	}
+	return returnKey, templates
+}
+
+func init() {
+	obj := new(Templates)
+	RegisterRule(SectionKey_Templates, obj)
+}
@@ -7,6 +7,7 @@ import (
 	"encoding/json"
 	"testing"
 
+	"github.com/aws/amazon-cloudwatch-agent/translator"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -36,6 +37,216 @@ func TestStatsD_HappyCase(t *testing.T) {
 	assert.Equal(t, expect, actual)
 }
 
+func TestStatsD_DataDogTags(t *testing.T) {
+	obj := new(StatsD)
+	var input interface{}
+	err := json.Unmarshal([]byte(`{"statsd": {
+					"allowed_data_dog_tags": ["environment", "service"],
+					"max_data_dog_tag_values": 100
+					}}`), &input)
+	assert.NoError(t, err)
+
+	_, actual := obj.ApplyRule(input)
+
+	expect := []interface{}{
+		map[string]interface{}{
+			"service_address":         ":8125",
+			"interval":                "10s",
+			"parse_data_dog_tags":     true,
+			"allowed_data_dog_tags":   []string{"environment", "service"},
+			"max_data_dog_tag_values": 100,
+			"tags":                    map[string]interface{}{"aws:AggregationInterval": "60s"},
+		},
+	}
+
+	assert.Equal(t, expect, actual)
+}
+
+func TestStatsD_Percentiles(t *testing.T) {
+	obj := new(StatsD)
+	var input interface{}
+	err := json.Unmarshal([]byte(`{"statsd": {
+					"percentile_mode": "metrics",
+					"percentiles": [50, 99.9]
+					}}`), &input)
+	assert.NoError(t, err)
+
+	_, actual := obj.ApplyRule(input)
+
+	expect := []interface{}{
+		map[string]interface{}{
+			"service_address":     ":8125",
+			"interval":            "10s",
+			"parse_data_dog_tags": true,
+			"percentile_mode":     "metrics",
+			"percentiles":         []float64{50, 99.9},
+			"tags":                map[string]interface{}{"aws:AggregationInterval": "60s"},
+		},
+	}
+
+	assert.Equal(t, expect, actual)
+}
+
+func TestStatsD_PercentilesWithoutMetricsMode(t *testing.T) {
+	translator.ResetMessages()
+	obj := new(StatsD)
+	var input interface{}
+	err := json.Unmarshal([]byte(`{"statsd": {
+					"percentiles": [50, 99.9]
+					}}`), &input)
+	assert.NoError(t, err)
+
+	obj.ApplyRule(input)
+	assert.Equal(t, []string{"Under path : /metrics/metrics_collected/statsd/percentiles | Error : percentiles requires the percentile_mode metrics."}, translator.ErrorMessages)
+	translator.ResetMessages()
+}
+
+func TestStatsD_CounterMode(t *testing.T) {
+	obj := new(StatsD)
+	var input interface{}
+	err := json.Unmarshal([]byte(`{"statsd": {
+					"counter_mode": "rate"
+					}}`), &input)
+	assert.NoError(t, err)
+
+	_, actual := obj.ApplyRule(input)
+
+	expect := []interface{}{
+		map[string]interface{}{
+			"service_address":     ":8125",
+			"interval":            "10s",
+			"parse_data_dog_tags": true,
+			"counter_mode":        "rate",
+			"tags":                map[string]interface{}{"aws:AggregationInterval": "60s"},
+		},
+	}
+
+	assert.Equal(t, expect, actual)
+}
+
+func TestStatsD_ReadBuffer(t *testing.T) {
+	obj := new(StatsD)
+	var input interface{}
+	err := json.Unmarshal([]byte(`{"statsd": {
+					"read_buffer_size": 8388608,
+					"read_batch_size": 64
+					}}`), &input)
+	assert.NoError(t, err)
+
+	_, actual := obj.ApplyRule(input)
+
+	expect := []interface{}{
+		map[string]interface{}{
+			"service_address":     ":8125",
+			"interval":            "10s",
+			"parse_data_dog_tags": true,
+			"read_buffer_size":    8388608,
+			"read_batch_size":     64,
+			"tags":                map[string]interface{}{"aws:AggregationInterval": "60s"},
+		},
+	}
+
+	assert.Equal(t, expect, actual)
+}
+
+func TestStatsD_Templates(t *testing.T) {
+	obj := new(StatsD)
+	var input interface{}
+	err := json.Unmarshal([]byte(`{"statsd": {
+					"templates": ["api.*.*.latency measurement.service.endpoint.measurement"]
+					}}`), &input)
+	assert.NoError(t, err)
+
+	_, actual := obj.ApplyRule(input)
+
+	expect := []interface{}{
+		map[string]interface{}{
+			"service_address":     ":8125",
+			"interval":            "10s",
+			"parse_data_dog_tags": true,
+			"templates":           []string{"api.*.*.latency measurement.service.endpoint.measurement"},
+			"tags":                map[string]interface{}{"aws:AggregationInterval": "60s"},
+		},
+	}
+
+	assert.Equal(t, expect, actual)
+}
+
+func TestStatsD_TemplatesWithoutMeasurement(t *testing.T) {
+	translator.ResetMessages()
+	obj := new(StatsD)
+	var input interface{}
+	err := json.Unmarshal([]byte(`{"statsd": {
+					"templates": ["api.* service.endpoint"]
+					}}`), &input)
+	assert.NoError(t, err)
+
+	obj.ApplyRule(input)
+	assert.Equal(t, []string{"Under path : /metrics/metrics_collected/statsd/templates | Error : templates must name the parts of the buckets of the metric name with measurement."}, translator.ErrorMessages)
+	translator.ResetMessages()
+}
+
+func TestStatsD_Events(t *testing.T) {
+	obj := new(StatsD)
+	var input interface{}
+	err := json.Unmarshal([]byte(`{"statsd": {
+					"events_log_group_name": "statsd-events",
+					"events_log_stream_name": "web"
+					}}`), &input)
+	assert.NoError(t, err)
+
+	_, actual := obj.ApplyRule(input)
+
+	expect := []interface{}{
+		map[string]interface{}{
+			"service_address":        ":8125",
+			"interval":               "10s",
+			"parse_data_dog_tags":    true,
+			"events_log_group_name":  "statsd-events",
+			"events_log_stream_name": "web",
+			"tags":                   map[string]interface{}{"aws:AggregationInterval": "60s"},
+		},
+	}
+
+	assert.Equal(t, expect, actual)
+}
+
+func TestStatsD_StreamServiceAddress(t *testing.T) {
+	obj := new(StatsD)
+	var input interface{}
+	err := json.Unmarshal([]byte(`{"statsd": {
+					"service_address": "unix:///var/run/statsd.sock"
+					}}`), &input)
+	assert.NoError(t, err)
+
+	_, actual := obj.ApplyRule(input)
+
+	expect := []interface{}{
+		map[string]interface{}{
+			"service_address":     "unix:///var/run/statsd.sock",
+			"interval":            "10s",
+			"parse_data_dog_tags": true,
+			"tags":                map[string]interface{}{"aws:AggregationInterval": "60s"},
+		},
+	}
+
+	assert.Equal(t, expect, actual)
+}
+
+func TestStatsD_InvalidServiceAddress(t *testing.T) {
+	translator.ResetMessages()
+	obj := new(StatsD)
+	var input interface{}
+	err := json.Unmarshal([]byte(`{"statsd": {
+					"service_address": "sctp://:8125"
+					}}`), &input)
+	assert.NoError(t, err)
+
+	obj.ApplyRule(input)
+	assert.Equal(t, []string{"Under path : /metrics/metrics_collected/statsd/service_address | Error : service_address must be an address, or udp://, tcp:// or unix:// followed by an address or the path of a socket."}, translator.ErrorMessages)
+	translator.ResetMessages()
+}
+
 func TestStatsD_MinimumConfig(t *testing.T) {
 	obj := new(StatsD)
 	var input interface{}
@@ -100,3 +311,28 @@ func TestStatsD_MetricSeparator(t *testing.T) {
 
 	assert.Equal(t, expect, actual)
 }
+
+func TestStatsD_MaxSeries(t *testing.T) {
+	obj := new(StatsD)
+	var input interface{}
+	err := json.Unmarshal([]byte(`{"statsd": {
+					"max_series": 1000
+					}}`), &input)
+	assert.NoError(t, err)
+
+	_, actual := obj.ApplyRule(input)
+
+	// the series are counted by the default aggregation interval
+	expect := []interface{}{
+		map[string]interface{}{
+			"service_address":     ":8125",
+			"interval":            "10s",
+			"parse_data_dog_tags": true,
+			"max_series":          1000,
+			"max_series_interval": "60s",
+			"tags":                map[string]interface{}{"aws:AggregationInterval": "60s"},
+		},
+	}
+
+	assert.Equal(t, expect, actual)
+}
@@ -0,0 +1,27 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package systemd
+
+import (
+	"github.com/aws/amazon-cloudwatch-agent/translator/translate/agent"
+)
+
+// Timeout is the timeout of the commands of the collector, it overrides the exec timeout of the agent section.
+type Timeout struct {
+}
+
+const SectionKey_Timeout = "timeout"
+
+func (obj *Timeout) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	m := input.(map[string]interface{})
+	if val, ok := agent.Timeout(agent.ExecTimeoutKey, m[SectionKey_Timeout]); ok {
+		returnKey, returnVal = SectionKey_Timeout, val
+	}
+	return
+}
+
+func init() {
+	obj := new(Timeout)
+	RegisterRule(SectionKey_Timeout, obj)
+}
@@ -0,0 +1,27 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package systemd
+
+import (
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+)
+
+type Units struct {
+}
+
+const SectionKey_Units = "units"
+
+func (obj *Units) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	returnKey, returnVal = translator.DefaultCase(SectionKey_Units, []interface{}{}, input)
+	if units, ok := returnVal.([]interface{}); !ok || len(units) == 0 {
+		translator.AddErrorMessages(GetCurPath()+SectionKey_Units, "units is required, it lists the systemd units reported")
+		return "", nil
+	}
+	return
+}
+
+func init() {
+	obj := new(Units)
+	RegisterRule(SectionKey_Units, obj)
+}
@@ -0,0 +1,35 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package systemd
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSystemd(t *testing.T) {
+	s := new(Systemd)
+	var input interface{}
+	err := json.Unmarshal([]byte(`{"systemd":{"measurement": ["active", "failed", "restarts"], "units": ["nginx.service", "sshd"]}}`), &input)
+	assert.NoError(t, err)
+	_, actual := s.ApplyRule(input)
+	expected := []interface{}{map[string]interface{}{
+		"fieldpass": []string{"active", "failed", "restarts"},
+		"units":     []interface{}{"nginx.service", "sshd"},
+	}}
+	assert.Equal(t, expected, actual, "Expected to be equal")
+}
+
+func TestSystemdWithoutUnits(t *testing.T) {
+	translator.ResetMessages()
+	s := new(Systemd)
+	var input interface{}
+	err := json.Unmarshal([]byte(`{"systemd":{"measurement": ["active"], "units": []}}`), &input)
+	assert.NoError(t, err)
+	s.ApplyRule(input)
+	assert.Equal(t, 1, len(translator.ErrorMessages))
+}
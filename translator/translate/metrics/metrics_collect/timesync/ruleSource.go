@@ -0,0 +1,31 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package timesync
+
+import (
+	"fmt"
+
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+)
+
+type Source struct {
+}
+
+const SectionKey_Source = "source"
+
+var validSources = map[string]bool{"auto": true, "chrony": true, "ntp": true}
+
+func (obj *Source) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	returnKey, returnVal = translator.DefaultCase(SectionKey_Source, "auto", input)
+	if source, ok := returnVal.(string); !ok || !validSources[source] {
+		translator.AddErrorMessages(GetCurPath()+SectionKey_Source, fmt.Sprintf("source %v is invalid, must be one of auto, chrony or ntp", returnVal))
+		return "", nil
+	}
+	return
+}
+
+func init() {
+	obj := new(Source)
+	RegisterRule(SectionKey_Source, obj)
+}
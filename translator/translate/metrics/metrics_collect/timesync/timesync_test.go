@@ -0,0 +1,45 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package timesync
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTimeSync(t *testing.T) {
+	ts := new(TimeSync)
+	var input interface{}
+	err := json.Unmarshal([]byte(`{"timesync":{"measurement": ["offset", "jitter", "synced"], "source": "chrony"}}`), &input)
+	assert.NoError(t, err)
+	_, actual := ts.ApplyRule(input)
+	expected := []interface{}{map[string]interface{}{
+		"fieldpass":  []string{"offset", "jitter", "synced"},
+		"source":     "chrony",
+		"tagexclude": []string{"reference_id"},
+	}}
+	assert.Equal(t, expected, actual, "Expected to be equal")
+}
+
+func TestTimeSyncDefaultSource(t *testing.T) {
+	ts := new(TimeSync)
+	var input interface{}
+	err := json.Unmarshal([]byte(`{"timesync":{"measurement": ["stratum"]}}`), &input)
+	assert.NoError(t, err)
+	_, actual := ts.ApplyRule(input)
+	assert.Equal(t, "auto", actual.([]interface{})[0].(map[string]interface{})["source"])
+}
+
+func TestTimeSyncInvalidSource(t *testing.T) {
+	translator.ResetMessages()
+	ts := new(TimeSync)
+	var input interface{}
+	err := json.Unmarshal([]byte(`{"timesync":{"measurement": ["offset"], "source": "w32time"}}`), &input)
+	assert.NoError(t, err)
+	ts.ApplyRule(input)
+	assert.Equal(t, 1, len(translator.ErrorMessages))
+}
@@ -0,0 +1,24 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package top_processes
+
+import (
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+)
+
+type RankBy struct {
+}
+
+const SectionKey_RankBy = "rank_by"
+
+var defaultRankBy = []interface{}{"cpu", "memory"}
+
+func (obj *RankBy) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	return translator.DefaultStringArrayCase(SectionKey_RankBy, defaultRankBy, input)
+}
+
+func init() {
+	obj := new(RankBy)
+	RegisterRule(SectionKey_RankBy, obj)
+}
@@ -0,0 +1,25 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package top_processes
+
+import (
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+)
+
+type TopN struct {
+}
+
+const (
+	SectionKey_TopN = "top_n"
+	defaultTopN     = 5
+)
+
+func (obj *TopN) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	return translator.DefaultIntegralCase(SectionKey_TopN, float64(defaultTopN), input)
+}
+
+func init() {
+	obj := new(TopN)
+	RegisterRule(SectionKey_TopN, obj)
+}
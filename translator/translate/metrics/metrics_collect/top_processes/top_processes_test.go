@@ -0,0 +1,50 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package top_processes
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTopProcesses(t *testing.T) {
+	tp := new(TopProcesses)
+	var input interface{}
+	err := json.Unmarshal([]byte(`{"top_processes":{"measurement": ["cpu_usage", "top_processes_memory_rss"]}}`), &input)
+	assert.NoError(t, err)
+	_, actual := tp.ApplyRule(input)
+	expected := []interface{}{map[string]interface{}{
+		"fieldpass": []string{"cpu_usage", "memory_rss"},
+		"top_n":     5,
+		"rank_by":   []string{"cpu", "memory"},
+	}}
+	assert.Equal(t, expected, actual, "Expected to be equal")
+}
+
+func TestTopProcessesWithOptions(t *testing.T) {
+	tp := new(TopProcesses)
+	var input interface{}
+	err := json.Unmarshal([]byte(`{"top_processes":{"measurement": ["cpu_usage"], "top_n": 10, "rank_by": ["cpu"]}}`), &input)
+	assert.NoError(t, err)
+	_, actual := tp.ApplyRule(input)
+	expected := []interface{}{map[string]interface{}{
+		"fieldpass": []string{"cpu_usage"},
+		"top_n":     10,
+		"rank_by":   []string{"cpu"},
+	}}
+	assert.Equal(t, expected, actual, "Expected to be equal")
+}
+
+func TestTopProcessesWithInvalidTopN(t *testing.T) {
+	translator.ResetMessages()
+	tp := new(TopProcesses)
+	var input interface{}
+	err := json.Unmarshal([]byte(`{"top_processes":{"measurement": ["cpu_usage"], "top_n": "5"}}`), &input)
+	assert.NoError(t, err)
+	tp.ApplyRule(input)
+	assert.Equal(t, 1, len(translator.ErrorMessages))
+}
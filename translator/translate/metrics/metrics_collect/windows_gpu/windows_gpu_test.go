@@ -0,0 +1,29 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package windows_gpu
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+	"github.com/aws/amazon-cloudwatch-agent/translator/config"
+)
+
+func TestWindowsGPU(t *testing.T) {
+	translator.SetTargetPlatform(config.OS_TYPE_WINDOWS)
+	defer translator.SetTargetPlatform(config.OS_TYPE_LINUX)
+	w := new(WindowsGPU)
+	var input interface{}
+	err := json.Unmarshal([]byte(`{"windows_gpu":{"measurement": ["utilization", "windows_gpu_memory_dedicated_usage"], "metrics_collection_interval": 60}}`), &input)
+	assert.NoError(t, err)
+	_, actual := w.ApplyRule(input)
+	expected := []interface{}{map[string]interface{}{
+		"fieldpass": []string{"utilization", "memory_dedicated_usage"},
+		"interval":  "60s",
+	}}
+	assert.Equal(t, expected, actual, "Expected to be equal")
+}
@@ -91,3 +91,217 @@ func TestMetrics_EndpointOverride(t *testing.T) {
 	)
 	assert.Equal(t, expected, actual, "Expected to be equal")
 }
+
+func TestMetrics_QuarantineFile(t *testing.T) {
+	m := new(Metrics)
+	var input interface{}
+	agent.Global_Config.Region = "auto"
+	err := json.Unmarshal([]byte(`{"metrics":{"quarantine_file":"/tmp/cloudwatch-quarantine.log"}}`), &input)
+	assert.NoError(t, err)
+	_, actual := m.ApplyRule(input)
+	expected := map[string]interface{}(
+		map[string]interface{}{
+			"outputs": map[string]interface{}{
+				"cloudwatch": []interface{}{
+					map[string]interface{}{
+						"force_flush_interval": "60s",
+						"namespace":            "CWAgent",
+						"region":               "auto",
+						"quarantine_file":      "/tmp/cloudwatch-quarantine.log",
+						"tagexclude":           []string{"metricPath"},
+						"tagpass":              map[string][]string{"metricPath": []string{"metrics"}},
+					},
+				},
+			},
+		},
+	)
+	assert.Equal(t, expected, actual, "Expected to be equal")
+}
+
+func TestMetrics_DistributionType(t *testing.T) {
+	m := new(Metrics)
+	var input interface{}
+	agent.Global_Config.Region = "auto"
+	err := json.Unmarshal([]byte(`{"metrics":{"distribution_type":"tdigest","tdigest_compression":200}}`), &input)
+	assert.NoError(t, err)
+	_, actual := m.ApplyRule(input)
+	expected := map[string]interface{}(
+		map[string]interface{}{
+			"outputs": map[string]interface{}{
+				"cloudwatch": []interface{}{
+					map[string]interface{}{
+						"force_flush_interval": "60s",
+						"namespace":            "CWAgent",
+						"region":               "auto",
+						"distribution_type":    "tdigest",
+						"tdigest_compression":  float64(200),
+						"tagexclude":           []string{"metricPath"},
+						"tagpass":              map[string][]string{"metricPath": []string{"metrics"}},
+					},
+				},
+			},
+		},
+	)
+	assert.Equal(t, expected, actual, "Expected to be equal")
+}
+
+func TestMetrics_SEH1Epsilon(t *testing.T) {
+	m := new(Metrics)
+	var input interface{}
+	agent.Global_Config.Region = "auto"
+	err := json.Unmarshal([]byte(`{"metrics":{"seh1_epsilon":0.01}}`), &input)
+	assert.NoError(t, err)
+	_, actual := m.ApplyRule(input)
+	expected := map[string]interface{}(
+		map[string]interface{}{
+			"outputs": map[string]interface{}{
+				"cloudwatch": []interface{}{
+					map[string]interface{}{
+						"force_flush_interval": "60s",
+						"namespace":            "CWAgent",
+						"region":               "auto",
+						"seh1_epsilon":         0.01,
+						"tagexclude":           []string{"metricPath"},
+						"tagpass":              map[string][]string{"metricPath": []string{"metrics"}},
+					},
+				},
+			},
+		},
+	)
+	assert.Equal(t, expected, actual, "Expected to be equal")
+}
+
+func TestMetrics_CheckpointAggregations(t *testing.T) {
+	m := new(Metrics)
+	var input interface{}
+	agent.Global_Config.Region = "auto"
+	err := json.Unmarshal([]byte(`{"metrics":{"checkpoint_aggregations":true}}`), &input)
+	assert.NoError(t, err)
+	_, actual := m.ApplyRule(input)
+	expected := map[string]interface{}(
+		map[string]interface{}{
+			"outputs": map[string]interface{}{
+				"cloudwatch": []interface{}{
+					map[string]interface{}{
+						"checkpoint_file":      "/opt/aws/amazon-cloudwatch-agent/logs/state/cloudwatch-aggregations.json",
+						"force_flush_interval": "60s",
+						"namespace":            "CWAgent",
+						"region":               "auto",
+						"tagexclude":           []string{"metricPath"},
+						"tagpass":              map[string][]string{"metricPath": []string{"metrics"}},
+					},
+				},
+			},
+		},
+	)
+	assert.Equal(t, expected, actual, "Expected to be equal")
+}
+
+func TestMetrics_NegativeValues(t *testing.T) {
+	m := new(Metrics)
+	var input interface{}
+	agent.Global_Config.Region = "auto"
+	err := json.Unmarshal([]byte(`{"metrics":{"negative_values":"track"}}`), &input)
+	assert.NoError(t, err)
+	_, actual := m.ApplyRule(input)
+	expected := map[string]interface{}(
+		map[string]interface{}{
+			"outputs": map[string]interface{}{
+				"cloudwatch": []interface{}{
+					map[string]interface{}{
+						"force_flush_interval": "60s",
+						"namespace":            "CWAgent",
+						"negative_values":      "track",
+						"region":               "auto",
+						"tagexclude":           []string{"metricPath"},
+						"tagpass":              map[string][]string{"metricPath": []string{"metrics"}},
+					},
+				},
+			},
+		},
+	)
+	assert.Equal(t, expected, actual, "Expected to be equal")
+}
+
+func TestMetrics_UnitConflicts(t *testing.T) {
+	m := new(Metrics)
+	var input interface{}
+	agent.Global_Config.Region = "auto"
+	err := json.Unmarshal([]byte(`{"metrics":{"unit_conflicts":"fail"}}`), &input)
+	assert.NoError(t, err)
+	_, actual := m.ApplyRule(input)
+	expected := map[string]interface{}(
+		map[string]interface{}{
+			"outputs": map[string]interface{}{
+				"cloudwatch": []interface{}{
+					map[string]interface{}{
+						"force_flush_interval": "60s",
+						"namespace":            "CWAgent",
+						"region":               "auto",
+						"tagexclude":           []string{"metricPath"},
+						"tagpass":              map[string][]string{"metricPath": []string{"metrics"}},
+						"unit_conflicts":       "fail",
+					},
+				},
+			},
+		},
+	)
+	assert.Equal(t, expected, actual, "Expected to be equal")
+}
+
+func TestMetrics_Batching(t *testing.T) {
+	m := new(Metrics)
+	var input interface{}
+	agent.Global_Config.Region = "auto"
+	err := json.Unmarshal([]byte(`{"metrics":{"max_datums_per_call":1000,"max_payload_size":800000}}`), &input)
+	assert.NoError(t, err)
+	_, actual := m.ApplyRule(input)
+	expected := map[string]interface{}(
+		map[string]interface{}{
+			"outputs": map[string]interface{}{
+				"cloudwatch": []interface{}{
+					map[string]interface{}{
+						"force_flush_interval": "60s",
+						"max_datums_per_call":  1000.0,
+						"max_payload_size":     800000.0,
+						"namespace":            "CWAgent",
+						"region":               "auto",
+						"tagexclude":           []string{"metricPath"},
+						"tagpass":              map[string][]string{"metricPath": []string{"metrics"}},
+					},
+				},
+			},
+		},
+	)
+	assert.Equal(t, expected, actual, "Expected to be equal")
+}
+
+func TestMetrics_EMFOutput(t *testing.T) {
+	m := new(Metrics)
+	var input interface{}
+	agent.Global_Config.Region = "auto"
+	agent.Global_Config.MetricsOutput = map[string]interface{}{"mode": "emf", "log_group_name": "/aws/cwagent/metrics", "log_stream_name": "web"}
+	defer func() { agent.Global_Config.MetricsOutput = nil }()
+	err := json.Unmarshal([]byte(`{"metrics":{}}`), &input)
+	assert.NoError(t, err)
+	_, actual := m.ApplyRule(input)
+	expected := map[string]interface{}(
+		map[string]interface{}{
+			"outputs": map[string]interface{}{
+				"cloudwatch": []interface{}{
+					map[string]interface{}{
+						"force_flush_interval": "60s",
+						"log_group_name":       "/aws/cwagent/metrics",
+						"log_stream_name":      "web",
+						"namespace":            "CWAgent",
+						"output_mode":          "emf",
+						"region":               "auto",
+						"tagexclude":           []string{"metricPath"},
+						"tagpass":              map[string][]string{"metricPath": []string{"metrics"}},
+					},
+				},
+			},
+		},
+	)
+	assert.Equal(t, expected, actual, "Expected to be equal")
+}
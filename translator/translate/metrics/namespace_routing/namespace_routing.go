@@ -0,0 +1,77 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package namespace_routing
+
+import (
+	"fmt"
+
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+	parent "github.com/aws/amazon-cloudwatch-agent/translator/translate/metrics"
+)
+
+type namespaceRouting struct {
+}
+
+const (
+	SectionKey       = "namespace_routing"
+	dimensionKey     = "dimension"
+	namespaceKey     = "namespace"
+	namespacesKey    = "namespaces"
+	dropDimensionKey = "drop_dimension"
+)
+
+func GetCurPath() string {
+	curPath := parent.GetCurPath() + SectionKey + "/"
+	return curPath
+}
+
+// ApplyRule translates the routing rules of the namespaces, e.g.
+// "namespace_routing": [{"dimension": "team", "namespace": "Teams/{value}"}] sends the metrics with a team dimension
+// to the namespace of their team.
+func (n *namespaceRouting) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	im := input.(map[string]interface{})
+
+	returnKey = ""
+	returnVal = ""
+	rules, ok := im[SectionKey].([]interface{})
+	if !ok || len(rules) == 0 {
+		return
+	}
+
+	result := make([]interface{}, 0, len(rules))
+	for _, r := range rules {
+		rule, isMap := r.(map[string]interface{})
+		if !isMap {
+			translator.AddErrorMessages(GetCurPath(), fmt.Sprintf("Invalid namespace routing rule %v, expected {\"dimension\": <name>, \"namespace\": <namespace>}", r))
+			return
+		}
+		dimension, hasDimension := rule[dimensionKey].(string)
+		namespace, _ := rule[namespaceKey].(string)
+		namespaces, _ := rule[namespacesKey].(map[string]interface{})
+		if !hasDimension || (namespace == "" && len(namespaces) == 0) {
+			translator.AddErrorMessages(GetCurPath(), fmt.Sprintf("Invalid namespace routing rule %v, %s and either %s or %s are required", r, dimensionKey, namespaceKey, namespacesKey))
+			return
+		}
+		translated := map[string]interface{}{dimensionKey: dimension}
+		if namespace != "" {
+			translated[namespaceKey] = namespace
+		}
+		if len(namespaces) > 0 {
+			translated[namespacesKey] = namespaces
+		}
+		if drop, ok := rule[dropDimensionKey].(bool); ok && drop {
+			translated[dropDimensionKey] = true
+		}
+		result = append(result, translated)
+	}
+
+	returnKey = parent.OutputsKey
+	returnVal = map[string]interface{}{SectionKey: result}
+	return
+}
+
+func init() {
+	n := new(namespaceRouting)
+	parent.RegisterRule(SectionKey, n)
+}
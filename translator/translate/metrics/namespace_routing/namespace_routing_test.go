@@ -0,0 +1,45 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package namespace_routing
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNamespaceRouting(t *testing.T) {
+	n := new(namespaceRouting)
+	var input interface{}
+	err := json.Unmarshal([]byte(`{
+      "namespace_routing": [
+        {"dimension": "team", "namespace": "Teams/{value}", "drop_dimension": true},
+        {"dimension": "env", "namespaces": {"prod": "Prod"}}
+      ]
+    }`), &input)
+	assert.NoError(t, err)
+	actualKey, actualVal := n.ApplyRule(input)
+	expectedVal := map[string]interface{}{
+		"namespace_routing": []interface{}{
+			map[string]interface{}{"dimension": "team", "namespace": "Teams/{value}", "drop_dimension": true},
+			map[string]interface{}{"dimension": "env", "namespaces": map[string]interface{}{"prod": "Prod"}},
+		},
+	}
+	assert.Equal(t, "outputs", actualKey)
+	assert.Equal(t, expectedVal, actualVal)
+}
+
+func TestNamespaceRoutingWithoutNamespace(t *testing.T) {
+	translator.ResetMessages()
+	n := new(namespaceRouting)
+	var input interface{}
+	err := json.Unmarshal([]byte(`{"namespace_routing": [{"dimension": "team"}]}`), &input)
+	assert.NoError(t, err)
+	actualKey, _ := n.ApplyRule(input)
+	assert.Equal(t, "", actualKey)
+	assert.Equal(t, 1, len(translator.ErrorMessages))
+}
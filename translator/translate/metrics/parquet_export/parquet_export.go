@@ -0,0 +1,68 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package parquet_export
+
+import (
+	"fmt"
+
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+	parent "github.com/aws/amazon-cloudwatch-agent/translator/translate/metrics"
+)
+
+type parquetExport struct {
+}
+
+const (
+	SectionKey         = "parquet_export"
+	bucketKey          = "bucket"
+	prefixKey          = "prefix"
+	compressionKey     = "compression"
+	maxRowsPerFileKey  = "max_rows_per_file"
+	maxPendingFilesKey = "max_pending_files"
+)
+
+func GetCurPath() string {
+	curPath := parent.GetCurPath() + SectionKey + "/"
+	return curPath
+}
+
+// ApplyRule translates the export of the metrics to S3 as hourly parquet files, e.g.
+// "parquet_export": {"bucket": "my-metrics-bucket", "prefix": "cloudwatch-agent"}
+// The region, credentials and namespace of the cloudwatch output are added to the s3_parquet output by the metrics rule.
+func (p *parquetExport) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	im := input.(map[string]interface{})
+
+	returnKey = ""
+	returnVal = ""
+	export, ok := im[SectionKey].(map[string]interface{})
+	if !ok {
+		return
+	}
+
+	bucket, _ := export[bucketKey].(string)
+	if bucket == "" {
+		translator.AddErrorMessages(GetCurPath(), fmt.Sprintf("%s is required to export the metrics to S3", bucketKey))
+		return
+	}
+	result := map[string]interface{}{bucketKey: bucket}
+	for _, key := range []string{prefixKey, compressionKey} {
+		if val, ok := export[key].(string); ok && val != "" {
+			result[key] = val
+		}
+	}
+	for _, key := range []string{maxRowsPerFileKey, maxPendingFilesKey} {
+		if val, ok := export[key].(float64); ok {
+			result[key] = int(val)
+		}
+	}
+
+	returnKey = parent.ParquetOutputKey
+	returnVal = result
+	return
+}
+
+func init() {
+	p := new(parquetExport)
+	parent.RegisterRule(SectionKey, p)
+}
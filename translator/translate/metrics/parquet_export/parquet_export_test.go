@@ -0,0 +1,57 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package parquet_export
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParquetExport(t *testing.T) {
+	p := new(parquetExport)
+	var input interface{}
+	err := json.Unmarshal([]byte(`{
+      "parquet_export": {
+        "bucket": "my-metrics-bucket",
+        "prefix": "cloudwatch-agent",
+        "compression": "none",
+        "max_rows_per_file": 50000
+      }
+    }`), &input)
+	assert.NoError(t, err)
+	actualKey, actualVal := p.ApplyRule(input)
+	expectedVal := map[string]interface{}{
+		"bucket":            "my-metrics-bucket",
+		"prefix":            "cloudwatch-agent",
+		"compression":       "none",
+		"max_rows_per_file": 50000,
+	}
+	assert.Equal(t, "s3_parquet", actualKey)
+	assert.Equal(t, expectedVal, actualVal)
+}
+
+func TestNoParquetExport(t *testing.T) {
+	p := new(parquetExport)
+	var input interface{}
+	err := json.Unmarshal([]byte(`{"namespace": "CWAgent"}`), &input)
+	assert.NoError(t, err)
+	actualKey, actualVal := p.ApplyRule(input)
+	assert.Equal(t, "", actualKey)
+	assert.Equal(t, "", actualVal)
+}
+
+func TestParquetExportWithoutBucket(t *testing.T) {
+	translator.ResetMessages()
+	p := new(parquetExport)
+	var input interface{}
+	err := json.Unmarshal([]byte(`{"parquet_export": {"prefix": "cloudwatch-agent"}}`), &input)
+	assert.NoError(t, err)
+	actualKey, _ := p.ApplyRule(input)
+	assert.Equal(t, "", actualKey)
+	assert.Equal(t, 1, len(translator.ErrorMessages))
+}
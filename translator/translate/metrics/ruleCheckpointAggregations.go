@@ -0,0 +1,35 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package metrics
+
+import (
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+	"github.com/aws/amazon-cloudwatch-agent/translator/config"
+	logsutil "github.com/aws/amazon-cloudwatch-agent/translator/translate/logs/util"
+)
+
+const checkpointFileName = "cloudwatch-aggregations.json"
+
+// CheckpointAggregations persists the metrics being aggregated to the state folder on shutdown.
+type CheckpointAggregations struct {
+}
+
+func (r *CheckpointAggregations) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	_, val := translator.DefaultCase("checkpoint_aggregations", false, input)
+	if enabled, ok := val.(bool); !ok || !enabled {
+		return
+	}
+	separator := "/"
+	if translator.GetTargetPlatform() == config.OS_TYPE_WINDOWS {
+		separator = "\\"
+	}
+	returnKey = "outputs"
+	returnVal = map[string]interface{}{"checkpoint_file": logsutil.GetFileStateFolder() + separator + checkpointFileName}
+	return
+}
+
+func init() {
+	r := new(CheckpointAggregations)
+	RegisterRule("checkpoint_aggregations", r)
+}
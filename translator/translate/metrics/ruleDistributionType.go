@@ -0,0 +1,28 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package metrics
+
+import (
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+)
+
+// DistributionType is the distribution of the values, seh1 or the OpenTelemetry exponential histograms.
+type DistributionType struct {
+}
+
+func (r *DistributionType) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	res := map[string]interface{}{}
+	key, val := translator.DefaultCase("distribution_type", "", input)
+	res[key] = val
+	if val != "" {
+		returnKey = "outputs"
+		returnVal = res
+	}
+	return
+}
+
+func init() {
+	r := new(DistributionType)
+	RegisterRule("distribution_type", r)
+}
@@ -0,0 +1,26 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package metrics
+
+import (
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+)
+
+// MaxBackoff bounds the backoff in seconds between the attempts of the requests of the metrics.
+type MaxBackoff struct {
+}
+
+func (r *MaxBackoff) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	if _, ok := input.(map[string]interface{})["max_backoff"]; ok {
+		key, val := translator.DefaultTimeIntervalCase("max_backoff", "", input)
+		returnKey = "outputs"
+		returnVal = map[string]interface{}{key: val}
+	}
+	return
+}
+
+func init() {
+	r := new(MaxBackoff)
+	RegisterRule("max_backoff", r)
+}
@@ -3,14 +3,22 @@
 
 package metrics
 
-import "github.com/aws/amazon-cloudwatch-agent/translator/translate/agent"
+import (
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+	"github.com/aws/amazon-cloudwatch-agent/translator/translate/agent"
+)
 
 type MaxDatumsPerCall struct {
 }
 
+// ApplyRule sets the configured datums per call, or 1000 for the internal configurations.
 func (obj *MaxDatumsPerCall) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
-	if agent.Global_Config.Internal {
-		res := map[string]interface{}{"max_datums_per_call": 1000}
+	_, val := translator.DefaultCase("max_datums_per_call", "", input)
+	if val == "" && agent.Global_Config.Internal {
+		val = 1000
+	}
+	if val != "" {
+		res := map[string]interface{}{"max_datums_per_call": val}
 		returnKey = "outputs"
 		returnVal = res
 	}
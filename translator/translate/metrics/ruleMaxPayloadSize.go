@@ -0,0 +1,28 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package metrics
+
+import (
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+)
+
+// MaxPayloadSize is the payload of the PutMetricData requests the datums are packed in.
+type MaxPayloadSize struct {
+}
+
+func (r *MaxPayloadSize) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	res := map[string]interface{}{}
+	key, val := translator.DefaultCase("max_payload_size", "", input)
+	res[key] = val
+	if val != "" {
+		returnKey = "outputs"
+		returnVal = res
+	}
+	return
+}
+
+func init() {
+	r := new(MaxPayloadSize)
+	RegisterRule("max_payload_size", r)
+}
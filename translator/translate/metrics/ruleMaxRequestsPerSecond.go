@@ -0,0 +1,28 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package metrics
+
+import (
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+)
+
+// MaxRequestsPerSecond limits the attempts of the requests of the metrics, the rate is halved while they are throttled.
+type MaxRequestsPerSecond struct {
+}
+
+func (r *MaxRequestsPerSecond) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	res := map[string]interface{}{}
+	key, val := translator.DefaultCase("max_requests_per_second", "", input)
+	res[key] = val
+	if val != "" {
+		returnKey = "outputs"
+		returnVal = res
+	}
+	return
+}
+
+func init() {
+	r := new(MaxRequestsPerSecond)
+	RegisterRule("max_requests_per_second", r)
+}
@@ -0,0 +1,26 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package metrics
+
+import (
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+)
+
+// MaxRetries is the attempts of the PutMetricData and PutLogEvents requests of the metrics.
+type MaxRetries struct {
+}
+
+func (r *MaxRetries) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	if _, ok := input.(map[string]interface{})["max_retries"]; ok {
+		key, val := translator.DefaultIntegralCase("max_retries", "", input)
+		returnKey = "outputs"
+		returnVal = map[string]interface{}{key: val}
+	}
+	return
+}
+
+func init() {
+	r := new(MaxRetries)
+	RegisterRule("max_retries", r)
+}
@@ -0,0 +1,56 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package metrics
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+	"github.com/aws/amazon-cloudwatch-agent/translator/translate/agent"
+	"github.com/aws/amazon-cloudwatch-agent/translator/translate/util"
+)
+
+const (
+	metricsOutputModePutMetricData = "put_metric_data"
+	metricsOutputModeEMF           = "emf"
+)
+
+// MetricsOutput is the publication of the metrics of the metrics_output of the agent section, the datums are written
+// as embedded metric format documents to the log stream with the emf mode.
+type MetricsOutput struct {
+}
+
+func (r *MetricsOutput) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	output := agent.Global_Config.MetricsOutput
+	mode, _ := output[agent.MetricsOutputModeKey].(string)
+	switch mode {
+	case "", metricsOutputModePutMetricData:
+		return
+	case metricsOutputModeEMF:
+	default:
+		translator.AddErrorMessages(agent.GetCurPath()+agent.MetricsOutputKey+"/", fmt.Sprintf("Invalid mode %s, the supported modes are %s and %s", mode, metricsOutputModePutMetricData, metricsOutputModeEMF))
+		return
+	}
+	res := map[string]interface{}{"output_mode": mode}
+	for _, key := range []string{agent.MetricsOutputLogGroupNameKey, agent.MetricsOutputLogStreamNameKey} {
+		name, _ := output[key].(string)
+		if name == "" {
+			continue
+		}
+		if strings.Contains(name, "{") {
+			name = util.ResolvePlaceholder(name, util.GetMetadataInfo(util.Ec2MetadataInfoProvider))
+		}
+		res[key] = name
+	}
+	translator.AddInfoMessages(GetCurPath(), "The metrics are written to CloudWatch Logs as embedded metric format documents, billed as the ingested logs besides the extracted metrics")
+	returnKey = OutputsKey
+	returnVal = res
+	return
+}
+
+func init() {
+	r := new(MetricsOutput)
+	RegisterRule(agent.MetricsOutputKey, r)
+}
@@ -0,0 +1,28 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package metrics
+
+import (
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+)
+
+// NegativeValues is the policy of the negative values of the distributions.
+type NegativeValues struct {
+}
+
+func (r *NegativeValues) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	res := map[string]interface{}{}
+	key, val := translator.DefaultCase("negative_values", "", input)
+	res[key] = val
+	if val != "" {
+		returnKey = "outputs"
+		returnVal = res
+	}
+	return
+}
+
+func init() {
+	r := new(NegativeValues)
+	RegisterRule("negative_values", r)
+}
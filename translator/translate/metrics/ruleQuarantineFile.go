@@ -0,0 +1,28 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package metrics
+
+import (
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+)
+
+// QuarantineFile is the file the datums PutMetricData would reject are written to.
+type QuarantineFile struct {
+}
+
+func (r *QuarantineFile) ApplyRule(input interface{}) (returnKey string, returnVal interface{}) {
+	res := map[string]interface{}{}
+	key, val := translator.DefaultCase("quarantine_file", "", input)
+	res[key] = val
+	if val != "" {
+		returnKey = "outputs"
+		returnVal = res
+	}
+	return
+}
+
+func init() {
+	r := new(QuarantineFile)
+	RegisterRule("quarantine_file", r)
+}
@@ -0,0 +1,36 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package util
+
+import (
+	"github.com/aws/amazon-cloudwatch-agent/translator/translate/agent"
+)
+
+const (
+	Aggregate_percpu_Key = "aggregate_percpu"
+)
+
+// ProcessAggregatePerCpu adds the aggregate_percpu tag to the cpu input so the cpuaggregator processor collapses
+// the per core metrics into one distribution per host. The tag value is the aggregation interval, which matches
+// the collection interval of the cpu input.
+func ProcessAggregatePerCpu(input interface{}, result map[string]interface{}) {
+	m := input.(map[string]interface{})
+	if val, ok := m[Aggregate_percpu_Key].(bool); !ok || !val {
+		return
+	}
+
+	interval := agent.Global_Config.Interval
+	if val, ok := result[Collect_Interval_Mapped_Key].(string); ok {
+		interval = val
+	}
+	if interval == "" {
+		return
+	}
+
+	if result[Tags_Key] == nil {
+		result[Tags_Key] = map[string]interface{}{}
+	}
+	tagsMap := result[Tags_Key].(map[string]interface{})
+	tagsMap[Aggregate_percpu_Key] = interval
+}
@@ -13,16 +13,19 @@ import (
 )
 
 const (
-	Measurement_Key              = "measurement"
-	Collect_Interval_Key         = "metrics_collection_interval"
-	Collect_Interval_Mapped_Key  = "interval"
-	Aggregation_Interval_Key     = "metrics_aggregation_interval"
-	Append_Dimensions_Key        = "append_dimensions"
-	Append_Dimensions_Mapped_Key = "tags"
-	Windows_Object_Name_Key      = "ObjectName"
-	Windows_Measurement_Key      = "Measurement"
-	Windows_WarnOnMissing_Key    = "WarnOnMissing"
-	Windows_Disable_Replacer_Key = "DisableReplacer"
+	Measurement_Key                = "measurement"
+	Measurement_Exclude_Key        = "measurement_exclude"
+	Collect_Interval_Key           = "metrics_collection_interval"
+	Collect_Interval_Mapped_Key    = "interval"
+	Aggregation_Interval_Key       = "metrics_aggregation_interval"
+	Max_Series_Key                 = "max_series"
+	Max_Series_Interval_Mapped_Key = "max_series_interval"
+	Append_Dimensions_Key          = "append_dimensions"
+	Append_Dimensions_Mapped_Key   = "tags"
+	Windows_Object_Name_Key        = "ObjectName"
+	Windows_Measurement_Key        = "Measurement"
+	Windows_WarnOnMissing_Key      = "WarnOnMissing"
+	Windows_Disable_Replacer_Key   = "DisableReplacer"
 )
 
 // ProcessLinuxCommonConfig is used by both Linux and Darwin.
@@ -43,6 +46,11 @@ func ProcessLinuxCommonConfig(input interface{}, pluginName string, path string,
 			// No valid metric get generated, stop processing
 			return false
 		}
+		if val, ok := inputMap[Measurement_Exclude_Key]; ok {
+			if returnKey, returnVal := ApplyMeasurementExcludeRule(val, pluginName, os, path); returnKey != "" {
+				result[returnKey] = returnVal
+			}
+		}
 	} else {
 		return false
 	}
@@ -147,6 +155,7 @@ func setTimeInterval(inputMap map[string]interface{}, returnVal map[string]inter
 			returnVal[Collect_Interval_Mapped_Key] = val
 			//Check if this metric is high resolution
 			isHighRsolution = IsHighResolution(val.(string))
+			WarnHighResolutionCost(fmt.Sprintf("metrics plugin %s", pluginName), val.(string))
 		} else {
 			translator.AddErrorMessages(
 				fmt.Sprintf("metrics plugin %s", pluginName),
@@ -161,6 +170,7 @@ func ProcessMetricsCollectionInterval(input interface{}, defaultValue, pluginNam
 		if val, ok := inputMap[Collect_Interval_Key]; ok {
 			if floatVal, ok := val.(float64); ok {
 				val = fmt.Sprintf("%ds", int(floatVal))
+				WarnHighResolutionCost(fmt.Sprintf("metrics plugin %s", pluginName), val.(string))
 				return Collect_Interval_Mapped_Key, val
 			} else {
 				translator.AddErrorMessages(
@@ -198,7 +208,23 @@ func ProcessMetricsAggregationInterval(input interface{}, defaultValue, pluginNa
 	return
 }
 
-//check if desiredVal exist in inputs list
+// ProcessMaxSeriesInterval returns the interval the series of max_series are counted by, the
+// metrics_aggregation_interval of the plugin, or the defaultValue when it's not set or the metrics aren't aggregated.
+func ProcessMaxSeriesInterval(input interface{}, defaultValue string) (returnKey string, returnVal interface{}) {
+	inputMap, ok := input.(map[string]interface{})
+	if !ok {
+		return
+	}
+	if _, ok := inputMap[Max_Series_Key]; !ok {
+		return
+	}
+	if floatVal, ok := inputMap[Aggregation_Interval_Key].(float64); ok && floatVal > 0 {
+		return Max_Series_Interval_Mapped_Key, fmt.Sprintf("%ds", int(floatVal))
+	}
+	return Max_Series_Interval_Mapped_Key, defaultValue
+}
+
+// check if desiredVal exist in inputs list
 func ListContains(inputs []string, desiredVal string) bool {
 	for _, val := range inputs {
 		if val == desiredVal {
@@ -5,8 +5,10 @@ package util
 
 import (
 	"encoding/json"
+	"strings"
 	"testing"
 
+	"github.com/aws/amazon-cloudwatch-agent/translator"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -61,3 +63,48 @@ func TestProcessLinuxCommonConfigHappy(t *testing.T) {
 		panic(err)
 	}
 }
+
+func TestProcessLinuxCommonConfigHighResolutionCost(t *testing.T) {
+	for interval, warned := range map[float64]bool{1: true, 5: true, 10: false, 60: false} {
+		translator.ResetMessages()
+		input := map[string]interface{}{
+			"measurement":                 []interface{}{"usage_idle"},
+			"metrics_collection_interval": interval,
+		}
+		assert.True(t, ProcessLinuxCommonConfig(input, "cpu", "", map[string]interface{}{}))
+		if !warned {
+			assert.Empty(t, translator.InfoMessages, interval)
+			continue
+		}
+		if assert.Len(t, translator.InfoMessages, 1, interval) {
+			assert.True(t, strings.Contains(translator.InfoMessages[0], "metrics plugin cpu"))
+			assert.True(t, strings.Contains(translator.InfoMessages[0], "high resolution"))
+		}
+	}
+	translator.ResetMessages()
+}
+
+func TestProcessLinuxCommonConfigMeasurementWildcard(t *testing.T) {
+	var input interface{}
+	actualResult := map[string]interface{}{}
+	err := json.Unmarshal([]byte(`{
+					"measurement": [
+						"usage_idle",
+						"*"
+					],
+					"measurement_exclude": [
+						"cpu_time_guest",
+						"time_guest_nice",
+						"dummy_cpu_time"
+					]
+				}`), &input)
+	assert.NoError(t, err)
+	hasValidMetrics := ProcessLinuxCommonConfig(input, "cpu", "", actualResult)
+	expectedResult := map[string]interface{}{
+		"fieldpass": []string{"*"},
+		"fielddrop": []string{"time_guest", "time_guest_nice", "dummy_cpu_time"},
+	}
+	assert.True(t, hasValidMetrics, "Should return valid metrics")
+	assert.Equal(t, expectedResult, actualResult, "should be equal")
+	assert.True(t, HasMeasurementWildcard(input))
+}
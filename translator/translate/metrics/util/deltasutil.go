@@ -5,10 +5,19 @@ package util
 
 const (
 	Report_deltas_Key            = "report_deltas"
+	Report_rates_Key             = "report_rates"
 	Tags_Key                     = "tags"
 	True_value                   = "true"
 	Ignored_fields_for_delta     = "iops_in_progress"
 	Ignored_fields_for_delta_Key = "ignored_fields_for_delta"
+	// the numa memory fields are gauges, the allocation counters are reported as deltas
+	Ignored_numa_fields_for_delta = "numa_free,numa_total,numa_used,numa_used_percent"
+	// the kernel tables and socket memory are gauges, the drop and pressure counters are reported as deltas, the
+	// context switches and the interrupts are already rates
+	Ignored_kernel_fields_for_delta = "arp_entries,arp_max,arp_utilization,conntrack_count,conntrack_max,conntrack_utilization," +
+		"context_switches_per_sec,entropy_avail,interrupts_per_sec," +
+		"tcp_mem_max_pages,tcp_mem_pages,tcp_mem_pressure_pages,tcp_mem_utilization," +
+		"udp_mem_max_pages,udp_mem_pages,udp_mem_pressure_pages,udp_mem_utilization"
 )
 
 func addReportDeltasTag(inputMap map[string]interface{}, result map[string]interface{}) bool {
@@ -41,7 +50,7 @@ func ProcessReportDeltasForDiskIO(input interface{}, result map[string]interface
 		for _, field := range fieldsPass {
 			switch t := field.(type) {
 			case string:
-				if t == Ignored_fields_for_delta || t == "diskio_"+Ignored_fields_for_delta {
+				if t == Ignored_fields_for_delta || t == "diskio_"+Ignored_fields_for_delta || t == Measurement_Wildcard {
 					tagsMap := result[Tags_Key].(map[string]interface{})
 					tagsMap[Ignored_fields_for_delta_Key] = Ignored_fields_for_delta
 					return
@@ -59,7 +68,35 @@ func ProcessReportDeltasForDiskIO(input interface{}, result map[string]interface
 	}
 }
 
+// ProcessReportDeltasForNet reports the counters of the interfaces as deltas, or as rates per second in place of the
+// deltas when report_rates is true.
 func ProcessReportDeltasForNet(input interface{}, result map[string]interface{}) {
 	m := input.(map[string]interface{})
+	if reportRates, ok := m[Report_rates_Key].(bool); ok && reportRates {
+		if result[Tags_Key] == nil {
+			result[Tags_Key] = map[string]interface{}{}
+		}
+		tagsMap := result[Tags_Key].(map[string]interface{})
+		tagsMap[Report_rates_Key] = True_value
+		return
+	}
 	addReportDeltasTag(m, result)
 }
+
+func ProcessReportDeltasForNuma(input interface{}, result map[string]interface{}) {
+	m := input.(map[string]interface{})
+	if !addReportDeltasTag(m, result) {
+		return
+	}
+	tagsMap := result[Tags_Key].(map[string]interface{})
+	tagsMap[Ignored_fields_for_delta_Key] = Ignored_numa_fields_for_delta
+}
+
+func ProcessReportDeltasForKernel(input interface{}, result map[string]interface{}) {
+	m := input.(map[string]interface{})
+	if !addReportDeltasTag(m, result) {
+		return
+	}
+	tagsMap := result[Tags_Key].(map[string]interface{})
+	tagsMap[Ignored_fields_for_delta_Key] = Ignored_kernel_fields_for_delta
+}
@@ -15,14 +15,22 @@ import (
 )
 
 const field_pass_key = "fieldpass"
+const field_drop_key = "fielddrop"
 const windows_measurement_key = "Counters"
 const measurement_name = "name"
 const measurement_category = "category"
 const measurement_rename = "rename"
 const measurement_unit = "unit"
 const nvidia_smi_plugin_name = "nvidia_smi"
+const timesync_plugin_name = "timesync"
+const docker_plugin_name = "docker"
+const containerd_plugin_name = "containerd"
 const tag_exclude_key = "tagexclude"
 
+// Measurement_Wildcard in the measurement list publishes every field of the plugin, including the fields that are not
+// registered, the measurement_exclude list prunes the unwanted ones.
+const Measurement_Wildcard = "*"
+
 func ApplyMeasurementRule(inputs interface{}, pluginName string, targetOs string, path string) (returnKey string, returnVal []string) {
 	inputList := inputs.([]interface{})
 	returnKey = ""
@@ -49,6 +57,10 @@ func ApplyMeasurementRule(inputs interface{}, pluginName string, targetOs string
 			inputMetricName = input.(map[string]interface{})[measurement_name]
 		}
 
+		if inputMetricName == Measurement_Wildcard {
+			// every field passes, the other names of the list are redundant
+			return returnKey, []string{Measurement_Wildcard}
+		}
 		if formatted_metricName := getValidMetric(targetOs, pluginName, inputMetricName.(string)); formatted_metricName != "" {
 			returnVal = append(returnVal, formatted_metricName)
 		} else {
@@ -63,6 +75,27 @@ func ApplyMeasurementRule(inputs interface{}, pluginName string, targetOs string
 	return
 }
 
+// ApplyMeasurementExcludeRule returns the fielddrop list of the measurement_exclude names. The names the translator
+// doesn't know are dropped too, as they are the fields the "*" measurement surfaces.
+func ApplyMeasurementExcludeRule(inputs interface{}, pluginName string, targetOs string, path string) (returnKey string, returnVal []string) {
+	for _, input := range inputs.([]interface{}) {
+		name, ok := input.(string)
+		if !ok {
+			continue
+		}
+		formattedMetricName := getValidMetric(targetOs, pluginName, name)
+		if formattedMetricName == "" {
+			formattedMetricName = getFormattedMetricName(name, pluginName)
+			log.Printf("W! measurement_exclude name %s is not a known metric of %s, dropping the %s field", name, pluginName, formattedMetricName)
+		}
+		returnVal = append(returnVal, formattedMetricName)
+	}
+	if len(returnVal) > 0 {
+		returnKey = field_drop_key
+	}
+	return
+}
+
 func ApplyMeasurementRuleForMetricDecoration(inputs interface{}, pluginName string, targetOs string) (returnVal []interface{}) {
 	inputList := inputs.([]interface{})
 	returnVal = []interface{}{}
@@ -171,11 +204,21 @@ func GetMeasurementName(input interface{}) (measurementNames []string) {
 	return
 }
 
+// HasMeasurementWildcard returns true when the measurement list of the plugin config publishes every field.
+func HasMeasurementWildcard(input interface{}) bool {
+	for _, measurementName := range GetMeasurementName(input) {
+		if measurementName == Measurement_Wildcard {
+			return true
+		}
+	}
+	return false
+}
+
 // ApplyPluginSpecificRules returns a map contains all the rules for tagpass, tagdrop, namepass, namedrop,
 //fieldpass, fielddrop, taginclude, tagexclude specifically for certain plugin.
 func ApplyPluginSpecificRules(pluginName string) (map[string][]string, bool) {
 	switch pluginName {
-	case nvidia_smi_plugin_name:
+	case nvidia_smi_plugin_name, timesync_plugin_name, docker_plugin_name, containerd_plugin_name:
 		return map[string][]string{tag_exclude_key: GetExcludingTags(pluginName)}, true
 	default:
 		return nil, false
@@ -0,0 +1,55 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package util
+
+const (
+	Normalize_Instances_Key          = "normalize_instances"
+	Normalize_Instances_Patterns_Key = "normalize_instances_patterns"
+)
+
+// ProcessNormalizeInstances adds the normalize_instances tag to the win_perf_counters object config so the
+// instancenormalizer processor rewrites the volatile instance names into stable dimension values.
+// The tags map is copied since it may be the append_dimensions map of the json config.
+func ProcessNormalizeInstances(input interface{}, result map[string]interface{}) {
+	m := input.(map[string]interface{})
+	if val, ok := m[Normalize_Instances_Key].(bool); !ok || !val {
+		return
+	}
+
+	tagsMap := map[string]interface{}{}
+	if val, ok := result[Tags_Key].(map[string]interface{}); ok {
+		for k, v := range val {
+			tagsMap[k] = v
+		}
+	}
+	tagsMap[Normalize_Instances_Key] = "true"
+	result[Tags_Key] = tagsMap
+}
+
+// NormalizeInstancesPatterns returns the object_patterns of the instancenormalizer processor, the
+// normalize_instances_patterns of the objects of the metrics_collected section which normalize their instances.
+func NormalizeInstancesPatterns(input interface{}) map[string]interface{} {
+	objectPatterns := map[string]interface{}{}
+	metrics, ok := input.(map[string]interface{})["metrics"].(map[string]interface{})
+	if !ok {
+		return objectPatterns
+	}
+	metricsCollected, ok := metrics["metrics_collected"].(map[string]interface{})
+	if !ok {
+		return objectPatterns
+	}
+	for objectName, object := range metricsCollected {
+		m, ok := object.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if val, ok := m[Normalize_Instances_Key].(bool); !ok || !val {
+			continue
+		}
+		if patterns, ok := m[Normalize_Instances_Patterns_Key].([]interface{}); ok {
+			objectPatterns[objectName] = patterns
+		}
+	}
+	return objectPatterns
+}
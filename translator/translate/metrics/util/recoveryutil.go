@@ -0,0 +1,13 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: MIT
+
+package util
+
+// the tags of the procstat input read by the recovery processor
+const (
+	Recovery_Restart_Service_Key = "recovery_restart_service"
+	Recovery_Command_Key         = "recovery_command"
+	Recovery_Min_Interval_Key    = "recovery_min_interval"
+	Recovery_Max_Attempts_Key    = "recovery_max_attempts"
+	Recovery_Drop_Pid_Count_Key  = "recovery_drop_pid_count"
+)
@@ -3,10 +3,19 @@
 
 package util
 
-import "time"
+import (
+	"fmt"
+	"time"
+
+	"github.com/aws/amazon-cloudwatch-agent/translator"
+)
 
 const Metric_High_Resolution_Threhold = 60 * time.Second
 
+// the metrics collected more often than every 10 seconds are published in as many more datums and PutMetricData
+// requests, which are charged
+const Metric_High_Resolution_Cost_Threhold = 10 * time.Second
+
 func IsHighResolution(intervalVal string) bool {
 	if actualInterval, err := time.ParseDuration(intervalVal); err == nil {
 		if actualInterval < Metric_High_Resolution_Threhold {
@@ -15,3 +24,14 @@ func IsHighResolution(intervalVal string) bool {
 	}
 	return false
 }
+
+// WarnHighResolutionCost adds an info message about the cost of the metrics collected more often than every 10
+// seconds, they are published with a storage resolution of 1 second.
+func WarnHighResolutionCost(path, intervalVal string) {
+	if actualInterval, err := time.ParseDuration(intervalVal); err == nil && actualInterval < Metric_High_Resolution_Cost_Threhold {
+		translator.AddInfoMessages(path, fmt.Sprintf("metrics_collection_interval %s is below 10s, the metrics are published as high resolution metrics "+
+			"in %d times the datums and the PutMetricData requests of a 60s interval, the requests and the high resolution alarms are charged, "+
+			"see https://aws.amazon.com/cloudwatch/pricing/",
+			intervalVal, int(Metric_High_Resolution_Threhold/actualInterval)))
+	}
+}
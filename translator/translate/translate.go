@@ -10,6 +10,7 @@ import (
 	"github.com/aws/amazon-cloudwatch-agent/translator"
 	"github.com/aws/amazon-cloudwatch-agent/translator/config"
 	"github.com/aws/amazon-cloudwatch-agent/translator/translate/agent"
+	"github.com/aws/amazon-cloudwatch-agent/translator/translate/metrics/util"
 )
 
 type Rule translator.Rule
@@ -99,8 +100,8 @@ func (t *Translator) ApplyRule(input interface{}) (returnKey string, returnVal i
 	result["inputs"] = allInputPlugin
 	result["outputs"] = allOutputPlugin
 
-	//we need to add delta processor because (only) diskio and net input plugins report delta metric
-	if allInputPlugin["diskio"] != nil || allInputPlugin["net"] != nil {
+	//we need to add delta processor because (only) diskio, net, numa and kernel input plugins report delta metric
+	if allInputPlugin["diskio"] != nil || allInputPlugin["net"] != nil || allInputPlugin["numa"] != nil || allInputPlugin["kernel"] != nil {
 		if allProcessorPlugin == nil {
 			allProcessorPlugin = make(map[string]interface{})
 		}
@@ -109,6 +110,44 @@ func (t *Translator) ApplyRule(input interface{}) (returnKey string, returnVal i
 		allProcessorPlugin["delta"] = deltaProcessorSettings
 	}
 
+	//we need to add cpuaggregator processor if the per core cpu metrics are aggregated into distributions
+	if inputHasTag(allInputPlugin["cpu"], util.Aggregate_percpu_Key) {
+		if allProcessorPlugin == nil {
+			allProcessorPlugin = make(map[string]interface{})
+		}
+		cpuAggregatorProcessorSettings := make([]interface{}, 0)
+		cpuAggregatorProcessorSettings = append(cpuAggregatorProcessorSettings, make(map[string]interface{}))
+		allProcessorPlugin["cpuaggregator"] = cpuAggregatorProcessorSettings
+	}
+
+	//we need to add instancenormalizer processor if the instance names of any windows perf counter object are normalized
+	if inputHasTag(allInputPlugin["win_perf_counters"], util.Normalize_Instances_Key) {
+		if allProcessorPlugin == nil {
+			allProcessorPlugin = make(map[string]interface{})
+		}
+		instanceNormalizerProcessorSettings := make([]interface{}, 0)
+		instanceNormalizerSettings := map[string]interface{}{}
+		if objectPatterns := util.NormalizeInstancesPatterns(m); len(objectPatterns) > 0 {
+			instanceNormalizerSettings["object_patterns"] = objectPatterns
+		}
+		instanceNormalizerProcessorSettings = append(instanceNormalizerProcessorSettings, instanceNormalizerSettings)
+		allProcessorPlugin["instancenormalizer"] = instanceNormalizerProcessorSettings
+	}
+
+	//we need to add recovery processor if any procstat target is configured with a recovery action
+	if inputHasTag(allInputPlugin["procstat"], util.Recovery_Restart_Service_Key) || inputHasTag(allInputPlugin["procstat"], util.Recovery_Command_Key) {
+		if allProcessorPlugin == nil {
+			allProcessorPlugin = make(map[string]interface{})
+		}
+		recoveryProcessorSettings := make([]interface{}, 0)
+		recoverySettings := map[string]interface{}{}
+		if val, ok := agent.Timeout(agent.ExecTimeoutKey, nil); ok {
+			recoverySettings["timeout"] = val
+		}
+		recoveryProcessorSettings = append(recoveryProcessorSettings, recoverySettings)
+		allProcessorPlugin["recovery"] = recoveryProcessorSettings
+	}
+
 	if allProcessorPlugin != nil {
 		result["processors"] = allProcessorPlugin
 	}
@@ -119,3 +158,21 @@ func (t *Translator) ApplyRule(input interface{}) (returnKey string, returnVal i
 	returnVal = result
 	return
 }
+
+// inputHasTag checks if any instance of the translated input plugin carries the given tag.
+func inputHasTag(inputPlugin interface{}, tagKey string) bool {
+	instances, ok := inputPlugin.([]interface{})
+	if !ok {
+		return false
+	}
+	for _, instance := range instances {
+		if instanceMap, ok := instance.(map[string]interface{}); ok {
+			if tags, ok := instanceMap["tags"].(map[string]interface{}); ok {
+				if _, ok := tags[tagKey]; ok {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}